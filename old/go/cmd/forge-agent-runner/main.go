@@ -15,6 +15,7 @@ import (
 	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
 )
 
 // Version information (set by goreleaser)
@@ -32,6 +33,7 @@ func main() {
 	busyRegex := flag.String("busy-regex", "", "regex to detect busy output")
 	heartbeat := flag.Duration("heartbeat", 5*time.Second, "heartbeat interval")
 	tailLines := flag.Int("tail-lines", 50, "output lines included in heartbeat")
+	heartbeatFile := flag.String("heartbeat-file", os.Getenv(models.HeartbeatFileEnv), "file rewritten on every heartbeat (default $"+models.HeartbeatFileEnv+")")
 	dbPath := flag.String("db-path", "", "database path (defaults to config)")
 	configFile := flag.String("config", "", "config file (default is $HOME/.config/forge/config.yaml)")
 	logLevel := flag.String("log-level", "", "override logging level (debug, info, warn, error)")
@@ -88,6 +90,7 @@ func main() {
 		PromptRegex:       promptRE,
 		BusyRegex:         busyRE,
 		HeartbeatInterval: *heartbeat,
+		HeartbeatFile:     *heartbeatFile,
 		TailLines:         *tailLines,
 		EventSink:         sink,
 		ControlReader:     os.Stdin,
@@ -165,6 +168,17 @@ func buildEventSink(ctx context.Context, cfg *config.Config, workspaceID, agentI
 	if cfg.Database.BusyTimeoutMs > 0 {
 		dbConfig.BusyTimeoutMs = cfg.Database.BusyTimeoutMs
 	}
+	if cfg.Database.JournalMode != "" {
+		dbConfig.JournalMode = cfg.Database.JournalMode
+	}
+	if cfg.Database.Synchronous != "" {
+		dbConfig.Synchronous = cfg.Database.Synchronous
+	}
+	dbConfig.BusyRetries = cfg.Database.BusyRetries
+	if cfg.Database.BusyRetryBackoff > 0 {
+		dbConfig.BusyRetryBackoff = cfg.Database.BusyRetryBackoff
+	}
+	dbConfig.CacheTTL = cfg.Database.EffectiveCacheTTL()
 
 	database, err := db.Open(dbConfig)
 	if err != nil {
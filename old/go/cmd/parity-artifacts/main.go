@@ -12,14 +12,16 @@ func main() {
 	var expected string
 	var actual string
 	var out string
+	var junit string
 
 	flag.StringVar(&expected, "expected", "", "expected output directory")
 	flag.StringVar(&actual, "actual", "", "actual output directory")
 	flag.StringVar(&out, "out", "parity-artifacts", "artifact output directory")
+	flag.StringVar(&junit, "junit", "", "optional path to write a JUnit XML report (one test case per file)")
 	flag.Parse()
 
 	if expected == "" || actual == "" {
-		fmt.Fprintln(os.Stderr, "usage: parity-artifacts --expected <dir> --actual <dir> [--out <dir>]")
+		fmt.Fprintln(os.Stderr, "usage: parity-artifacts --expected <dir> --actual <dir> [--out <dir>] [--junit <file>]")
 		os.Exit(2)
 	}
 
@@ -29,6 +31,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if junit != "" {
+		suite, err := parity.TreeDriftJUnit(expected, actual, report)
+		if err == nil {
+			err = parity.WriteJUnitReport(junit, "parity-artifacts", suite)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "write junit: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	fmt.Println(parity.DriftSummary(report))
 	if report.HasDrift() {
 		os.Exit(1)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/parity"
+)
+
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+func main() {
+	var scenarios stringList
+	var goBinary string
+	var surfacePath string
+	var outPath string
+	var gapsPath string
+	var timeout time.Duration
+
+	flag.Var(&scenarios, "scenario", "scenario json file or directory of scenario files (repeatable)")
+	flag.StringVar(&goBinary, "go-bin", "", "path to Go forge binary; its --help output defines the surface")
+	flag.StringVar(&surfacePath, "surface", "", "surface manifest json to use instead of walking --go-bin help")
+	flag.StringVar(&outPath, "out", "", "optional path to write the JSON coverage report")
+	flag.StringVar(&gapsPath, "gaps", "", "optional path to write the JSON gap list")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "per-command timeout for --help")
+	flag.Parse()
+
+	if len(scenarios) == 0 || (goBinary == "") == (surfacePath == "") {
+		fmt.Fprintln(os.Stderr, "usage: parity-coverage --scenario <file|dir> (--go-bin <path> | --surface <file>) [--out <file>] [--gaps <file>] [--timeout 10s]")
+		os.Exit(2)
+	}
+
+	var manifest parity.SurfaceManifest
+	if surfacePath != "" {
+		data, err := os.ReadFile(surfacePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read surface: %v\n", err)
+			os.Exit(1)
+		}
+		if manifest, err = parity.ParseSurfaceManifestJSON(data); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		var err error
+		if manifest, err = parity.SurfaceFromHelp(context.Background(), goBinary, timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "surface: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	paths, err := parity.ScenarioFiles(scenarios)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scenarios: %v\n", err)
+		os.Exit(1)
+	}
+
+	tracker := parity.NewCoverageTracker(manifest)
+	for _, path := range paths {
+		scenario, err := parity.LoadLifecycleScenario(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load scenario %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if strings.TrimSpace(scenario.Name) == "" {
+			scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		tracker.RecordScenario(scenario)
+	}
+	report := tracker.Report()
+
+	if outPath != "" {
+		if err := parity.WriteCoverageReport(outPath, report); err != nil {
+			fmt.Fprintf(os.Stderr, "write report: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if gapsPath != "" {
+		if err := parity.WriteCoverageGaps(gapsPath, report); err != nil {
+			fmt.Fprintf(os.Stderr, "write gaps: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println(parity.CoverageSummary(report))
+	for _, args := range report.Unresolved {
+		fmt.Printf("unresolved: %s\n", args)
+	}
+}
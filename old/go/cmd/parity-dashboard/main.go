@@ -5,49 +5,72 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/tOgg1/forge/internal/paritydash"
 )
 
+// inputList collects repeated --input flags; comma-separated values are split.
+type inputList []string
+
+func (l *inputList) String() string { return strings.Join(*l, ",") }
+
+func (l *inputList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
 func main() {
-	var inputPath string
+	var inputPaths inputList
 	var outDir string
 	var writeMD bool
+	var writeHTML bool
+	var history int
 
-	flag.StringVar(&inputPath, "input", "", "input JSON file produced by CI")
+	flag.Var(&inputPaths, "input", "input JSON file produced by CI (repeat oldest to newest; the last one is current)")
 	flag.StringVar(&outDir, "out", "parity-dashboard", "output directory")
 	flag.BoolVar(&writeMD, "md", true, "write parity-dashboard.md")
+	flag.BoolVar(&writeHTML, "html", true, "write parity-dashboard.html")
+	flag.IntVar(&history, "history", paritydash.DefaultHistoryLimit, "number of most recent inputs to include in the trend")
 	flag.Parse()
 
-	if inputPath == "" {
-		fmt.Fprintln(os.Stderr, "usage: parity-dashboard --input <file> [--out <dir>] [--md=true|false]")
+	if len(inputPaths) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: parity-dashboard --input <file> [--input <file>...] [--out <dir>] [--md=true|false] [--html=true|false] [--history N]")
 		os.Exit(2)
 	}
 
-	b, err := os.ReadFile(inputPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "read input: %v\n", err)
-		os.Exit(1)
-	}
+	inputs := make([]paritydash.Input, 0, len(inputPaths))
+	for _, inputPath := range inputPaths {
+		b, err := os.ReadFile(inputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read input: %v\n", err)
+			os.Exit(1)
+		}
 
-	var in paritydash.Input
-	if err := json.Unmarshal(b, &in); err != nil {
-		fmt.Fprintf(os.Stderr, "parse input: %v\n", err)
-		os.Exit(1)
+		var in paritydash.Input
+		if err := json.Unmarshal(b, &in); err != nil {
+			fmt.Fprintf(os.Stderr, "parse input %s: %v\n", inputPath, err)
+			os.Exit(1)
+		}
+		inputs = append(inputs, in)
 	}
 
-	d, err := paritydash.Build(in, time.Now())
+	d, err := paritydash.BuildWithHistory(inputs, time.Now(), history)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "build: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err := paritydash.WriteFiles(outDir, d, writeMD); err != nil {
+	opts := paritydash.WriteOptions{Markdown: writeMD, HTML: writeHTML}
+	if err := paritydash.WriteFilesWithOptions(outDir, d, opts); err != nil {
 		fmt.Fprintf(os.Stderr, "write: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("wrote %s\n", outDir)
 }
-
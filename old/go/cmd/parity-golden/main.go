@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/parity"
+)
+
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+func main() {
+	var scenarios stringList
+	var accept stringList
+	var fixtureDir string
+	var goBinary string
+	var goldenDir string
+	var outDir string
+	var timeout time.Duration
+
+	flag.Var(&scenarios, "scenario", "scenario json file or directory of scenario files (repeatable)")
+	flag.Var(&accept, "accept", "update goldens matching scenario, scenario/step, or path glob; \"all\" accepts everything (repeatable)")
+	flag.StringVar(&fixtureDir, "fixture", "", "fixture repository directory copied for each scenario")
+	flag.StringVar(&goBinary, "go-bin", "", "path to Go forge binary")
+	flag.StringVar(&goldenDir, "golden", "", "golden (expected) output directory")
+	flag.StringVar(&outDir, "out", "parity-golden-artifacts", "drift artifact output directory")
+	flag.DurationVar(&timeout, "timeout", 30*time.Second, "per-command timeout")
+	flag.Parse()
+
+	if len(scenarios) == 0 || goBinary == "" || goldenDir == "" {
+		fmt.Fprintln(os.Stderr, "usage: parity-golden --scenario <file|dir> --go-bin <path> --golden <dir> [--accept all|<scenario>[/<step>]|<glob>] [--fixture <dir>] [--out <dir>] [--timeout 30s]")
+		os.Exit(2)
+	}
+
+	paths, err := parity.ScenarioFiles(scenarios)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scenarios: %v\n", err)
+		os.Exit(1)
+	}
+
+	actualDir, err := os.MkdirTemp("", "parity-golden-actual-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "temp dir: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(actualDir)
+
+	for _, path := range paths {
+		scenario, err := parity.LoadLifecycleScenario(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load scenario %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if strings.TrimSpace(scenario.Name) == "" {
+			scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if _, err := parity.RecordGolden(context.Background(), parity.GoldenConfig{
+			Binary:     goBinary,
+			FixtureDir: fixtureDir,
+			Scenario:   scenario,
+			Timeout:    timeout,
+		}, actualDir); err != nil {
+			fmt.Fprintf(os.Stderr, "record %s: %v\n", scenario.Name, err)
+			os.Exit(1)
+		}
+	}
+
+	if len(accept) > 0 {
+		updated, err := parity.AcceptGolden(goldenDir, actualDir, accept)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "accept: %v\n", err)
+			os.Exit(1)
+		}
+		for _, rel := range updated {
+			fmt.Printf("accepted %s\n", rel)
+		}
+	} else if _, err := os.Stat(goldenDir); err != nil {
+		fmt.Fprintf(os.Stderr, "golden dir %s: %v (run with --accept all to record it)\n", goldenDir, err)
+		os.Exit(1)
+	}
+
+	report, err := parity.WriteDiffArtifacts(goldenDir, actualDir, outDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write artifacts: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(parity.DriftSummary(report))
+	if report.HasDrift() {
+		os.Exit(1)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/tOgg1/forge/internal/parity"
@@ -16,18 +17,28 @@ func main() {
 	var goBinary string
 	var rustBinary string
 	var outPath string
+	var junitPath string
 	var timeout time.Duration
+	var benchRuns int
+	var perfThreshold float64
+	var repeat int
+	var allowFlaky bool
 
 	flag.StringVar(&scenarioPath, "scenario", "", "path to lifecycle scenario json")
 	flag.StringVar(&fixtureDir, "fixture", "", "fixture repository directory copied for each runtime")
 	flag.StringVar(&goBinary, "go-bin", "", "path to Go forge binary")
 	flag.StringVar(&rustBinary, "rust-bin", "", "path to Rust forge binary")
 	flag.StringVar(&outPath, "out", "", "optional path to write JSON report")
+	flag.StringVar(&junitPath, "junit", "", "optional path to write a JUnit XML report (one test case per step)")
 	flag.DurationVar(&timeout, "timeout", 30*time.Second, "per-command timeout")
+	flag.IntVar(&benchRuns, "bench-runs", 0, "replay the scenario N times per binary to compare wall-clock and peak RSS")
+	flag.Float64Var(&perfThreshold, "perf-threshold", parity.DefaultPerfRegressionThreshold, "relative slowdown tolerated before a step is flagged as a perf regression")
+	flag.IntVar(&repeat, "repeat", 0, "rerun the scenario N times to classify drifting steps as deterministic or flaky")
+	flag.BoolVar(&allowFlaky, "allow-flaky", false, "exit 0 when all drift is classified as flaky (requires --repeat)")
 	flag.Parse()
 
 	if scenarioPath == "" || goBinary == "" || rustBinary == "" {
-		fmt.Fprintln(os.Stderr, "usage: parity-loop-lifecycle --scenario <file> --go-bin <path> --rust-bin <path> [--fixture <dir>] [--out <file>] [--timeout 30s]")
+		fmt.Fprintln(os.Stderr, "usage: parity-loop-lifecycle --scenario <file> --go-bin <path> --rust-bin <path> [--fixture <dir>] [--out <file>] [--junit <file>] [--timeout 30s] [--bench-runs N] [--perf-threshold 0.25] [--repeat N [--allow-flaky]]")
 		os.Exit(2)
 	}
 
@@ -38,11 +49,14 @@ func main() {
 	}
 
 	report, err := parity.RunLoopLifecycleHarness(context.Background(), parity.LifecycleHarnessConfig{
-		GoBinary:   goBinary,
-		RustBinary: rustBinary,
-		FixtureDir: fixtureDir,
-		Scenario:   scenario,
-		Timeout:    timeout,
+		GoBinary:      goBinary,
+		RustBinary:    rustBinary,
+		FixtureDir:    fixtureDir,
+		Scenario:      scenario,
+		Timeout:       timeout,
+		BenchRuns:     benchRuns,
+		PerfThreshold: perfThreshold,
+		Repeat:        repeat,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "run harness: %v\n", err)
@@ -56,20 +70,60 @@ func main() {
 		}
 	}
 
-	fmt.Printf("scenario=%s steps=%d drift=%d\n", report.Scenario, len(report.Steps), report.DriftCount())
+	if junitPath != "" {
+		if err := parity.WriteJUnitReport(junitPath, "parity-loop-lifecycle", parity.LifecycleJUnit(report)); err != nil {
+			fmt.Fprintf(os.Stderr, "write junit: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if report.Repeat > 0 {
+		fmt.Printf("scenario=%s steps=%d drift=%d flaky=%d repeat=%d\n", report.Scenario, len(report.Steps), report.DriftCount(), report.FlakyCount(), report.Repeat)
+	} else {
+		fmt.Printf("scenario=%s steps=%d drift=%d\n", report.Scenario, len(report.Steps), report.DriftCount())
+	}
+	for _, variant := range report.Variants {
+		fmt.Printf("variant=%s steps=%d drift=%d\n", variant.Name, variant.Steps, variant.Drift)
+	}
 	for _, step := range report.Steps {
 		if !step.HasDrift {
 			continue
 		}
-		fmt.Printf("drift step=%s exit_match=%t stdout_equal=%t stderr_equal=%t\n",
+		detail := ""
+		if step.Variant != "" {
+			detail = " variant=" + step.Variant
+		}
+		if step.Flake != nil {
+			detail += fmt.Sprintf(" class=%s drift_runs=%d/%d", step.Flake.Classification, step.Flake.DriftRuns, step.Flake.Repeats)
+		}
+		fmt.Printf("drift step=%s exit_match=%t stdout_equal=%t stderr_equal=%t%s\n",
 			step.Name,
 			step.ExitCodeMatch,
 			step.Stdout.Equal,
 			step.Stderr.Equal,
+			detail,
 		)
 	}
 
-	if report.HasDrift() {
+	if report.BenchRuns > 0 {
+		fmt.Printf("bench runs=%d threshold=%.2f perf_regressions=%d\n", report.BenchRuns, report.PerfThreshold, report.PerfRegressionCount())
+		for _, step := range report.Steps {
+			if step.Perf == nil || !step.Perf.Regression {
+				continue
+			}
+			variant := ""
+			if step.Variant != "" {
+				variant = " variant=" + step.Variant
+			}
+			fmt.Printf("perf-regression step=%s%s %s\n", step.Name, variant, strings.Join(step.Perf.Reasons, "; "))
+		}
+	}
+
+	driftFails := report.HasDrift()
+	if allowFlaky {
+		driftFails = report.HasDeterministicDrift()
+	}
+	if driftFails || report.HasPerfRegression() {
 		os.Exit(1)
 	}
 }
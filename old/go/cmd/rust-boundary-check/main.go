@@ -0,0 +1,577 @@
+// Command rust-boundary-check enforces the Rust crate layer policy in
+// docs/rust-crate-boundaries.json: local crates may only depend on crates in
+// the same or a lower layer, same-layer crates may not form cycles, and
+// every workspace member must be assigned a layer.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Policy is the parsed boundary policy. The file is either a flat
+// {"crate": layer} map or {"layers": {...}, "allow": {"crate": ["dep"]}}.
+type Policy struct {
+	Layers map[string]int      `json:"layers"`
+	Allow  map[string][]string `json:"allow,omitempty"`
+}
+
+// Allowed reports whether the policy exempts the edge from -> to from the
+// layer rule.
+func (p Policy) Allowed(from, to string) bool {
+	for _, dep := range p.Allow[from] {
+		if dep == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Crate is a workspace member.
+type Crate struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+}
+
+// Edge is a dependency between two workspace crates.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Workspace is the local crate graph.
+type Workspace struct {
+	Crates []Crate
+	Edges  []Edge
+}
+
+// Report is the result of checking a workspace against a policy.
+type Report struct {
+	Violations []string
+	Warnings   []string
+	Cycles     [][]string
+}
+
+// OK reports whether the check passed.
+func (r Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+func main() {
+	var policyPath string
+	var root string
+	var graphOut string
+	var graphFormat string
+
+	flag.StringVar(&policyPath, "policy", "", "boundary policy JSON (default: <root>/docs/rust-crate-boundaries.json)")
+	flag.StringVar(&root, "root", "", "cargo workspace root (default: nearest parent with a [workspace] Cargo.toml)")
+	flag.StringVar(&graphOut, "graph-out", "", "write the local crate dependency graph to this file (- for stdout)")
+	flag.StringVar(&graphFormat, "graph-format", "", "graph format: dot or json (default: from --graph-out extension, else dot)")
+	flag.Parse()
+
+	if root == "" {
+		found, err := findWorkspaceRoot(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rust-boundary-check: %v\n", err)
+			os.Exit(2)
+		}
+		root = found
+	}
+	if policyPath == "" {
+		policyPath = filepath.Join(root, "docs", "rust-crate-boundaries.json")
+	}
+
+	policy, err := loadPolicy(policyPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rust-boundary-check: %v\n", err)
+		os.Exit(2)
+	}
+	ws, err := loadWorkspace(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rust-boundary-check: %v\n", err)
+		os.Exit(2)
+	}
+
+	report := check(ws, policy)
+
+	if graphOut != "" {
+		format, err := resolveGraphFormat(graphFormat, graphOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rust-boundary-check: %v\n", err)
+			os.Exit(2)
+		}
+		var buf bytes.Buffer
+		if err := writeGraph(&buf, format, ws, policy, report); err != nil {
+			fmt.Fprintf(os.Stderr, "rust-boundary-check: write graph: %v\n", err)
+			os.Exit(1)
+		}
+		if graphOut == "-" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+		} else if err := os.WriteFile(graphOut, buf.Bytes(), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "rust-boundary-check: write graph: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	if !report.OK() {
+		for _, violation := range report.Violations {
+			fmt.Fprintf(os.Stderr, "violation: %s\n", violation)
+		}
+		fmt.Fprintf(os.Stderr, "rust boundary check failed: %d violation(s)\n", len(report.Violations))
+		os.Exit(1)
+	}
+	if graphOut != "-" {
+		fmt.Printf("rust boundary check ok: %d crates, %d local edges\n", len(ws.Crates), len(ws.Edges))
+	}
+}
+
+func loadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("read policy: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Policy{}, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	var policy Policy
+	if _, structured := raw["layers"]; structured {
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return Policy{}, fmt.Errorf("parse policy %s: %w", path, err)
+		}
+	} else if err := json.Unmarshal(data, &policy.Layers); err != nil {
+		return Policy{}, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	if len(policy.Layers) == 0 {
+		return Policy{}, fmt.Errorf("policy %s defines no crate layers", path)
+	}
+	return policy, nil
+}
+
+func findWorkspaceRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+		if err == nil && bytes.Contains(data, []byte("[workspace]")) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no cargo workspace found; pass --root")
+		}
+		dir = parent
+	}
+}
+
+type cargoManifest struct {
+	Package struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Workspace struct {
+		Members      []string       `toml:"members"`
+		Dependencies map[string]any `toml:"dependencies"`
+	} `toml:"workspace"`
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+}
+
+func readManifest(path string) (cargoManifest, error) {
+	var manifest cargoManifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err := toml.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// loadWorkspace reads the workspace members and the path dependencies
+// between them. Registry dependencies are ignored.
+func loadWorkspace(root string) (Workspace, error) {
+	rootManifest, err := readManifest(filepath.Join(root, "Cargo.toml"))
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	var ws Workspace
+	manifests := make(map[string]cargoManifest)
+	byDir := make(map[string]string)
+	for _, member := range rootManifest.Workspace.Members {
+		dir := filepath.Clean(filepath.Join(root, member))
+		manifest, err := readManifest(filepath.Join(dir, "Cargo.toml"))
+		if err != nil {
+			return Workspace{}, fmt.Errorf("workspace member %s: %w", member, err)
+		}
+		name := manifest.Package.Name
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		ws.Crates = append(ws.Crates, Crate{Name: name, Dir: filepath.ToSlash(member)})
+		manifests[name] = manifest
+		byDir[dir] = name
+	}
+
+	// Workspace-inherited dependencies resolve their path from the root.
+	inherited := make(map[string]string)
+	for dep, spec := range rootManifest.Workspace.Dependencies {
+		if path := dependencyPath(spec); path != "" {
+			inherited[dep] = filepath.Clean(filepath.Join(root, path))
+		}
+	}
+
+	seen := make(map[Edge]bool)
+	for _, crate := range ws.Crates {
+		manifest := manifests[crate.Name]
+		dir := filepath.Clean(filepath.Join(root, crate.Dir))
+		for _, section := range []struct {
+			kind string
+			deps map[string]any
+		}{
+			{"normal", manifest.Dependencies},
+			{"dev", manifest.DevDependencies},
+			{"build", manifest.BuildDependencies},
+		} {
+			for dep, spec := range section.deps {
+				var target string
+				if path := dependencyPath(spec); path != "" {
+					target = byDir[filepath.Clean(filepath.Join(dir, path))]
+				} else if isWorkspaceDependency(spec) {
+					target = byDir[inherited[dependencyName(dep, spec)]]
+				}
+				if target == "" {
+					continue
+				}
+				edge := Edge{From: crate.Name, To: target, Kind: section.kind}
+				if !seen[edge] {
+					seen[edge] = true
+					ws.Edges = append(ws.Edges, edge)
+				}
+			}
+		}
+	}
+
+	sort.Slice(ws.Crates, func(i, j int) bool { return ws.Crates[i].Name < ws.Crates[j].Name })
+	sort.Slice(ws.Edges, func(i, j int) bool {
+		a, b := ws.Edges[i], ws.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+	return ws, nil
+}
+
+func dependencyPath(spec any) string {
+	table, ok := spec.(map[string]any)
+	if !ok {
+		return ""
+	}
+	path, _ := table["path"].(string)
+	return path
+}
+
+func isWorkspaceDependency(spec any) bool {
+	table, ok := spec.(map[string]any)
+	if !ok {
+		return false
+	}
+	inherited, _ := table["workspace"].(bool)
+	return inherited
+}
+
+// dependencyName honours `package = "..."` renames.
+func dependencyName(key string, spec any) string {
+	if table, ok := spec.(map[string]any); ok {
+		if name, ok := table["package"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return key
+}
+
+func check(ws Workspace, policy Policy) Report {
+	var report Report
+	members := make(map[string]bool, len(ws.Crates))
+	for _, crate := range ws.Crates {
+		members[crate.Name] = true
+		if _, ok := policy.Layers[crate.Name]; !ok {
+			report.Violations = append(report.Violations,
+				fmt.Sprintf("active workspace crate %s (%s) has no layer in the policy", crate.Name, crate.Dir))
+		}
+	}
+
+	used := make(map[[2]string]bool)
+	for _, edge := range ws.Edges {
+		fromLayer, fromOK := policy.Layers[edge.From]
+		toLayer, toOK := policy.Layers[edge.To]
+		if !fromOK || !toOK || toLayer <= fromLayer {
+			continue
+		}
+		if policy.Allowed(edge.From, edge.To) {
+			used[[2]string{edge.From, edge.To}] = true
+			continue
+		}
+		report.Violations = append(report.Violations,
+			fmt.Sprintf("%s (layer %d) depends on %s (layer %d) [%s]", edge.From, fromLayer, edge.To, toLayer, edge.Kind))
+	}
+
+	report.Cycles = sameLayerCycles(ws, policy)
+	for _, cycle := range report.Cycles {
+		report.Violations = append(report.Violations,
+			fmt.Sprintf("dependency cycle in layer %d: %s", policy.Layers[cycle[0]], strings.Join(append(cycle, cycle[0]), " -> ")))
+	}
+
+	crates := make([]string, 0, len(policy.Allow))
+	for crate := range policy.Allow {
+		crates = append(crates, crate)
+	}
+	sort.Strings(crates)
+	for _, crate := range crates {
+		for _, dep := range policy.Allow[crate] {
+			if !members[crate] || !used[[2]string{crate, dep}] {
+				report.Warnings = append(report.Warnings,
+					fmt.Sprintf("allow exception %s -> %s is not needed by any upward edge", crate, dep))
+			}
+		}
+	}
+	return report
+}
+
+// sameLayerCycles finds strongly connected components among crates that
+// share a layer. Cycles spanning layers always contain an upward edge and
+// are already reported as layer violations.
+func sameLayerCycles(ws Workspace, policy Policy) [][]string {
+	adjacency := make(map[string][]string)
+	for _, edge := range ws.Edges {
+		fromLayer, fromOK := policy.Layers[edge.From]
+		toLayer, toOK := policy.Layers[edge.To]
+		if fromOK && toOK && fromLayer == toLayer {
+			adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		}
+	}
+
+	// Tarjan's algorithm.
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowlink[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfLoop := false
+		for _, next := range adjacency[node] {
+			if next == node {
+				selfLoop = true
+			}
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				lowlink[node] = min(lowlink[node], lowlink[next])
+			} else if onStack[next] {
+				lowlink[node] = min(lowlink[node], indices[next])
+			}
+		}
+
+		if lowlink[node] != indices[node] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, crate := range ws.Crates {
+		if _, visited := indices[crate.Name]; !visited {
+			connect(crate.Name)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+func resolveGraphFormat(format, out string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(out)) {
+		case ".json":
+			format = "json"
+		default:
+			format = "dot"
+		}
+	}
+	if format != "dot" && format != "json" {
+		return "", fmt.Errorf("unknown graph format %q (want dot or json)", format)
+	}
+	return format, nil
+}
+
+type graphEdge struct {
+	Edge
+	Violation bool `json:"violation,omitempty"`
+	Allowed   bool `json:"allowed,omitempty"`
+}
+
+type graphCrate struct {
+	Crate
+	Layer *int `json:"layer"`
+}
+
+type graphDoc struct {
+	Crates []graphCrate `json:"crates"`
+	Edges  []graphEdge  `json:"edges"`
+	Cycles [][]string   `json:"cycles"`
+}
+
+func buildGraph(ws Workspace, policy Policy, report Report) graphDoc {
+	doc := graphDoc{Crates: []graphCrate{}, Edges: []graphEdge{}, Cycles: report.Cycles}
+	if doc.Cycles == nil {
+		doc.Cycles = [][]string{}
+	}
+	for _, crate := range ws.Crates {
+		entry := graphCrate{Crate: crate}
+		if layer, ok := policy.Layers[crate.Name]; ok {
+			entry.Layer = &layer
+		}
+		doc.Crates = append(doc.Crates, entry)
+	}
+	inCycle := make(map[[2]string]bool)
+	for _, cycle := range report.Cycles {
+		members := make(map[string]bool, len(cycle))
+		for _, name := range cycle {
+			members[name] = true
+		}
+		for _, edge := range ws.Edges {
+			if members[edge.From] && members[edge.To] {
+				inCycle[[2]string{edge.From, edge.To}] = true
+			}
+		}
+	}
+	for _, edge := range ws.Edges {
+		entry := graphEdge{Edge: edge}
+		fromLayer, fromOK := policy.Layers[edge.From]
+		toLayer, toOK := policy.Layers[edge.To]
+		if fromOK && toOK && toLayer > fromLayer {
+			if policy.Allowed(edge.From, edge.To) {
+				entry.Allowed = true
+			} else {
+				entry.Violation = true
+			}
+		}
+		if inCycle[[2]string{edge.From, edge.To}] {
+			entry.Violation = true
+		}
+		doc.Edges = append(doc.Edges, entry)
+	}
+	return doc
+}
+
+func writeGraph(w io.Writer, format string, ws Workspace, policy Policy, report Report) error {
+	doc := buildGraph(ws, policy, report)
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	}
+	return writeDOT(w, doc)
+}
+
+// writeDOT renders crates clustered by layer. Violations are red, allowed
+// exceptions dashed orange and dev/build edges dotted.
+func writeDOT(w io.Writer, doc graphDoc) error {
+	var b strings.Builder
+	b.WriteString("digraph rust_crates {\n")
+	b.WriteString("  rankdir=BT;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	byLayer := make(map[int][]string)
+	var unassigned []string
+	for _, crate := range doc.Crates {
+		if crate.Layer == nil {
+			unassigned = append(unassigned, crate.Name)
+			continue
+		}
+		byLayer[*crate.Layer] = append(byLayer[*crate.Layer], crate.Name)
+	}
+	layers := make([]int, 0, len(byLayer))
+	for layer := range byLayer {
+		layers = append(layers, layer)
+	}
+	sort.Ints(layers)
+	for _, layer := range layers {
+		fmt.Fprintf(&b, "  subgraph cluster_layer_%d {\n", layer)
+		fmt.Fprintf(&b, "    label=\"layer %d\";\n", layer)
+		for _, name := range byLayer[layer] {
+			fmt.Fprintf(&b, "    %q;\n", name)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, name := range unassigned {
+		fmt.Fprintf(&b, "  %q [color=red, label=\"%s\\n(no layer)\"];\n", name, name)
+	}
+
+	for _, edge := range doc.Edges {
+		var attrs []string
+		switch {
+		case edge.Violation:
+			attrs = append(attrs, "color=red")
+		case edge.Allowed:
+			attrs = append(attrs, "color=orange", "style=dashed")
+		}
+		if edge.Kind != "normal" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", edge.Kind))
+			if !edge.Allowed {
+				attrs = append(attrs, "style=dotted")
+			}
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCrate(t *testing.T, root, name, deps string) {
+	t.Helper()
+	dir := filepath.Join(root, "crates", name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifest := "[package]\nname = \"" + name + "\"\nversion = \"0.1.0\"\n\n" + deps
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+}
+
+func writeWorkspace(t *testing.T, members ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	var b strings.Builder
+	b.WriteString("[workspace]\nmembers = [\n")
+	for _, member := range members {
+		b.WriteString("    \"crates/" + member + "\",\n")
+	}
+	b.WriteString("]\n\n[workspace.dependencies]\ncore = { path = \"crates/core\" }\nserde = \"1\"\n")
+	if err := os.WriteFile(filepath.Join(root, "Cargo.toml"), []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write workspace: %v", err)
+	}
+	return root
+}
+
+func writePolicy(t *testing.T, content string) Policy {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	policy, err := loadPolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	return policy
+}
+
+func TestCheckReportsUpwardEdgesAndMissingCrates(t *testing.T) {
+	root := writeWorkspace(t, "core", "db", "cli", "extra")
+	// core's dev-dependency on cli points up two layers.
+	writeCrate(t, root, "core", "[dependencies]\nserde = { workspace = true }\n\n[dev-dependencies]\ncli = { path = \"../cli\" }\n")
+	writeCrate(t, root, "db", "[dependencies]\ncore = { workspace = true }\n")
+	writeCrate(t, root, "cli", "[dependencies]\ndb = { path = \"../db\" }\n")
+	writeCrate(t, root, "extra", "")
+
+	ws, err := loadWorkspace(root)
+	if err != nil {
+		t.Fatalf("load workspace: %v", err)
+	}
+	if len(ws.Edges) != 3 {
+		t.Fatalf("expected 3 local edges, got %+v", ws.Edges)
+	}
+
+	report := check(ws, writePolicy(t, `{"core": 0, "db": 1, "cli": 2}`))
+	if report.OK() {
+		t.Fatalf("expected violations")
+	}
+	joined := strings.Join(report.Violations, "\n")
+	if !strings.Contains(joined, "core (layer 0) depends on cli (layer 2) [dev]") {
+		t.Fatalf("missing upward edge violation: %s", joined)
+	}
+	if !strings.Contains(joined, "extra (crates/extra) has no layer") {
+		t.Fatalf("missing unassigned crate violation: %s", joined)
+	}
+}
+
+func TestCheckAllowlistExemptsEdges(t *testing.T) {
+	root := writeWorkspace(t, "core", "cli")
+	writeCrate(t, root, "core", "[dev-dependencies]\ncli = { path = \"../cli\" }\n")
+	writeCrate(t, root, "cli", "[dependencies]\ncore = { path = \"../core\" }\n")
+
+	ws, err := loadWorkspace(root)
+	if err != nil {
+		t.Fatalf("load workspace: %v", err)
+	}
+	policy := writePolicy(t, `{
+  "layers": {"core": 0, "cli": 2},
+  "allow": {"core": ["cli"], "cli": ["gone"]}
+}`)
+	report := check(ws, policy)
+	if !report.OK() {
+		t.Fatalf("expected allowlisted edge to pass, got %v", report.Violations)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "cli -> gone") {
+		t.Fatalf("expected stale exception warning, got %v", report.Warnings)
+	}
+}
+
+func TestCheckDetectsSameLayerCycles(t *testing.T) {
+	root := writeWorkspace(t, "agent", "daemon", "runner")
+	writeCrate(t, root, "agent", "[dependencies]\ndaemon = { path = \"../daemon\" }\n")
+	writeCrate(t, root, "daemon", "[dependencies]\nrunner = { path = \"../runner\" }\n")
+	writeCrate(t, root, "runner", "[dev-dependencies]\nagent = { path = \"../agent\" }\n")
+
+	ws, err := loadWorkspace(root)
+	if err != nil {
+		t.Fatalf("load workspace: %v", err)
+	}
+	policy := writePolicy(t, `{"agent": 3, "daemon": 3, "runner": 3}`)
+	report := check(ws, policy)
+	if len(report.Cycles) != 1 || strings.Join(report.Cycles[0], ",") != "agent,daemon,runner" {
+		t.Fatalf("expected one cycle, got %v", report.Cycles)
+	}
+	if report.OK() || !strings.Contains(report.Violations[0], "dependency cycle in layer 3: agent -> daemon -> runner -> agent") {
+		t.Fatalf("unexpected violations: %v", report.Violations)
+	}
+
+	var dot bytes.Buffer
+	if err := writeGraph(&dot, "dot", ws, policy, report); err != nil {
+		t.Fatalf("write dot: %v", err)
+	}
+	if !strings.Contains(dot.String(), "subgraph cluster_layer_3") ||
+		!strings.Contains(dot.String(), `"runner" -> "agent" [color=red, label="dev", style=dotted];`) {
+		t.Fatalf("unexpected dot output:\n%s", dot.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeGraph(&out, "json", ws, policy, report); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	var doc graphDoc
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("parse json graph: %v", err)
+	}
+	if len(doc.Crates) != 3 || len(doc.Edges) != 3 || len(doc.Cycles) != 1 {
+		t.Fatalf("unexpected json graph: %+v", doc)
+	}
+	for _, edge := range doc.Edges {
+		if !edge.Violation {
+			t.Fatalf("expected cycle edge %s -> %s flagged", edge.From, edge.To)
+		}
+	}
+}
+
+func TestResolveGraphFormat(t *testing.T) {
+	cases := []struct{ format, out, want string }{
+		{"", "graph.json", "json"},
+		{"", "graph.dot", "dot"},
+		{"", "-", "dot"},
+		{"JSON", "graph.dot", "json"},
+	}
+	for _, tc := range cases {
+		got, err := resolveGraphFormat(tc.format, tc.out)
+		if err != nil || got != tc.want {
+			t.Fatalf("resolveGraphFormat(%q, %q) = %q, %v; want %q", tc.format, tc.out, got, err, tc.want)
+		}
+	}
+	if _, err := resolveGraphFormat("svg", "x"); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func TestRepositoryPolicyPasses(t *testing.T) {
+	root, err := findWorkspaceRoot(".")
+	if err != nil {
+		t.Skipf("no cargo workspace: %v", err)
+	}
+	policy, err := loadPolicy(filepath.Join(root, "docs", "rust-crate-boundaries.json"))
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	ws, err := loadWorkspace(root)
+	if err != nil {
+		t.Fatalf("load workspace: %v", err)
+	}
+	if report := check(ws, policy); !report.OK() {
+		t.Fatalf("repository violates boundary policy: %v", report.Violations)
+	}
+}
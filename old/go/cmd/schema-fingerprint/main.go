@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tOgg1/forge/internal/parity"
+)
+
+func main() {
+	var outDir string
+
+	flag.StringVar(&outDir, "out-dir", "", "write schema-fingerprint.{txt,sha256} to this directory")
+	flag.Parse()
+
+	fingerprint, err := parity.ComputeSchemaFingerprint(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "compute schema fingerprint: %v\n", err)
+		os.Exit(1)
+	}
+
+	if outDir == "" {
+		fmt.Printf("schema version %d sha256 %s\n", fingerprint.Version, fingerprint.SHA256)
+		return
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "create %s: %v\n", outDir, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "schema-fingerprint.txt"), []byte(fingerprint.Dump), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "write dump: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "schema-fingerprint.sha256"), []byte(fingerprint.SHA256+"\n"), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "write digest: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("schema version %d sha256 %s\n", fingerprint.Version, fingerprint.SHA256)
+}
@@ -0,0 +1,134 @@
+package account
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// HealthStatus summarizes whether an account can take work.
+type HealthStatus string
+
+const (
+	HealthAvailable HealthStatus = "available"
+	HealthCooldown  HealthStatus = "cooldown"
+	HealthOverQuota HealthStatus = "over_quota"
+	HealthNearQuota HealthStatus = "near_quota"
+	HealthInactive  HealthStatus = "inactive"
+)
+
+// Health is the rotation-relevant state of one account.
+type Health struct {
+	Account           *models.Account          `json:"account"`
+	Status            HealthStatus             `json:"status"`
+	CooldownRemaining time.Duration            `json:"cooldown_remaining,omitempty"`
+	Quota             *models.UsageLimits      `json:"quota,omitempty"`
+	QuotaStatus       *models.UsageLimitStatus `json:"quota_status,omitempty"`
+	RateLimitCount    int64                    `json:"rate_limit_count"`
+}
+
+// QuotaStatus evaluates an account's usage against its quota: tokens and
+// cost since midnight UTC, requests over the last hour. It returns nil when
+// the account has no quota or no usage repository is configured.
+func (s *Service) QuotaStatus(ctx context.Context, id string) (*models.UsageLimitStatus, error) {
+	s.mu.RLock()
+	account, exists := s.accounts[id]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	return s.quotaStatus(ctx, account)
+}
+
+// IsOverQuota reports whether an account has exhausted its quota.
+func (s *Service) IsOverQuota(ctx context.Context, id string) (bool, error) {
+	status, err := s.QuotaStatus(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.IsOverLimit, nil
+}
+
+func (s *Service) quotaStatus(ctx context.Context, account *models.Account) (*models.UsageLimitStatus, error) {
+	limits, ok := s.quotas[account.ProfileName]
+	if !ok || s.usageRepo == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day, err := s.usageRepo.SummarizeByAccountRef(ctx, account.Provider, account.ID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	usage := &models.UsageSummary{TotalTokens: day.TotalTokens, TotalCostCents: day.TotalCostCents}
+	if limits.MaxRequestsPerHour > 0 {
+		hour, err := s.usageRepo.SummarizeByAccountRef(ctx, account.Provider, account.ID, now.Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		usage.RequestCount = hour.RequestCount
+	}
+	return models.CheckLimits(usage, &limits), nil
+}
+
+// overQuota is the rotation filter; lookup failures do not block an account.
+func (s *Service) overQuota(ctx context.Context, account *models.Account) bool {
+	status, err := s.quotaStatus(ctx, account)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("account_id", account.ID).Msg("failed to check account quota")
+		return false
+	}
+	return status != nil && status.IsOverLimit
+}
+
+// Health reports cooldown and quota state for every account, ordered by
+// provider and profile name.
+func (s *Service) Health(ctx context.Context) ([]Health, error) {
+	s.mu.RLock()
+	accounts := make([]*models.Account, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		accounts = append(accounts, cloneAccount(account))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Provider != accounts[j].Provider {
+			return accounts[i].Provider < accounts[j].Provider
+		}
+		return accounts[i].ProfileName < accounts[j].ProfileName
+	})
+
+	health := make([]Health, 0, len(accounts))
+	for _, account := range accounts {
+		entry := Health{Account: account, Status: HealthAvailable}
+		if account.UsageStats != nil {
+			entry.RateLimitCount = account.UsageStats.RateLimitCount
+		}
+		if limits, ok := s.quotas[account.ProfileName]; ok {
+			limits := limits
+			entry.Quota = &limits
+		}
+		status, err := s.quotaStatus(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		entry.QuotaStatus = status
+
+		switch {
+		case !account.IsActive:
+			entry.Status = HealthInactive
+		case account.IsOnCooldown():
+			entry.Status = HealthCooldown
+			entry.CooldownRemaining = account.CooldownRemaining()
+		case status != nil && status.IsOverLimit:
+			entry.Status = HealthOverQuota
+		case status != nil && status.IsApproachingLimit:
+			entry.Status = HealthNearQuota
+		}
+		health = append(health, entry)
+	}
+	return health, nil
+}
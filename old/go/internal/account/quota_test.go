@@ -0,0 +1,97 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestService_QuotaSkipsAccountsDuringRotation(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Accounts = []config.AccountConfig{{
+		Provider:      models.ProviderOpenAI,
+		ProfileName:   "old",
+		CredentialRef: "env:OPENAI_API_KEY",
+		Quota:         &config.AccountQuotaConfig{MaxTokensPerDay: 1000},
+	}}
+	usageRepo := db.NewUsageRepository(database)
+	accountRepo := db.NewAccountRepository(database)
+
+	// Config accounts are replaced by accounts registered by profile name,
+	// like the CLI does; the quota still applies by profile name.
+	cfgCopy := *cfg
+	cfgCopy.Accounts = nil
+	service := NewService(&cfgCopy, WithUsageRepository(usageRepo), WithQuotas(cfg.AccountQuotas()))
+
+	now := time.Now().UTC()
+	lastUsed := map[string]time.Time{
+		"current": now.Add(-30 * time.Minute),
+		"old":     now.Add(-2 * time.Hour),
+		"new":     now.Add(-time.Hour),
+	}
+	for _, name := range []string{"current", "old", "new"} {
+		stored := &models.Account{Provider: models.ProviderOpenAI, ProfileName: name, CredentialRef: "env:OPENAI_API_KEY", IsActive: true}
+		if err := accountRepo.Create(ctx, stored); err != nil {
+			t.Fatalf("create account: %v", err)
+		}
+		if name == "old" {
+			if err := usageRepo.Create(ctx, &models.UsageRecord{AccountID: stored.ID, Provider: models.ProviderOpenAI, InputTokens: 1200}); err != nil {
+				t.Fatalf("create usage: %v", err)
+			}
+		}
+		clone := *stored
+		clone.ID = name
+		clone.UsageStats = &models.UsageStats{LastUsed: timePtr(lastUsed[name])}
+		if err := service.AddAccount(ctx, &clone); err != nil {
+			t.Fatalf("AddAccount failed: %v", err)
+		}
+	}
+
+	over, err := service.IsOverQuota(ctx, "old")
+	if err != nil || !over {
+		t.Fatalf("expected old to be over quota, got %v (%v)", over, err)
+	}
+
+	got, err := service.RotateAccount(ctx, "current")
+	if err != nil {
+		t.Fatalf("RotateAccount failed: %v", err)
+	}
+	if got.ProfileName != "new" {
+		t.Fatalf("expected rotation to skip over-quota account, got %s", got.ProfileName)
+	}
+
+	if err := service.SetCooldown(ctx, "current", time.Minute); err != nil {
+		t.Fatalf("SetCooldown failed: %v", err)
+	}
+	health, err := service.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	byName := make(map[string]Health, len(health))
+	for _, entry := range health {
+		byName[entry.Account.ProfileName] = entry
+	}
+	if byName["current"].Status != HealthCooldown || byName["current"].CooldownRemaining <= 0 || byName["current"].RateLimitCount != 1 {
+		t.Fatalf("unexpected current health %+v", byName["current"])
+	}
+	if entry := byName["old"]; entry.Status != HealthOverQuota || entry.Quota == nil || entry.QuotaStatus == nil || entry.QuotaStatus.TokensUsedPercent < 100 {
+		t.Fatalf("unexpected old health %+v", entry)
+	}
+	if entry := byName["new"]; entry.Status != HealthAvailable || entry.Quota != nil {
+		t.Fatalf("unexpected new health %+v", entry)
+	}
+}
@@ -35,6 +35,8 @@ type Service struct {
 	accounts        map[string]*models.Account
 	defaultCooldown time.Duration
 	repo            *db.AccountRepository
+	usageRepo       *db.UsageRepository
+	quotas          map[string]models.UsageLimits // keyed by profile name
 	publisher       events.Publisher
 	logger          zerolog.Logger
 	vaultPath       string // Path to the native credential vault
@@ -50,6 +52,22 @@ func WithRepository(repo *db.AccountRepository) ServiceOption {
 	}
 }
 
+// WithUsageRepository configures the usage history quotas are checked
+// against. Without it, quotas are not enforced.
+func WithUsageRepository(repo *db.UsageRepository) ServiceOption {
+	return func(s *Service) {
+		s.usageRepo = repo
+	}
+}
+
+// WithQuotas sets usage quotas keyed by account profile name, replacing
+// the quotas read from config.
+func WithQuotas(quotas map[string]models.UsageLimits) ServiceOption {
+	return func(s *Service) {
+		s.quotas = quotas
+	}
+}
+
 // WithPublisher configures a publisher for event emission.
 func WithPublisher(publisher events.Publisher) ServiceOption {
 	return func(s *Service) {
@@ -70,6 +88,7 @@ func NewService(cfg *config.Config, opts ...ServiceOption) *Service {
 	s := &Service{
 		accounts:        make(map[string]*models.Account),
 		defaultCooldown: cfg.Scheduler.DefaultCooldownDuration,
+		quotas:          cfg.AccountQuotas(),
 		logger:          logging.Component("account"),
 		vaultPath:       vault.DefaultVaultPath(),
 	}
@@ -161,7 +180,7 @@ func (s *Service) GetNextAvailable(ctx context.Context, provider models.Provider
 
 	var candidates []*models.Account
 	for _, account := range s.accounts {
-		if account.Provider == provider && account.IsAvailable() {
+		if account.Provider == provider && account.IsAvailable() && !s.overQuota(ctx, account) {
 			candidates = append(candidates, account)
 		}
 	}
@@ -421,7 +440,8 @@ func (s *Service) RotateAccountForAgent(ctx context.Context, currentID, agentID,
 	for _, account := range s.accounts {
 		if account.ID != currentID &&
 			account.Provider == current.Provider &&
-			account.IsAvailable() {
+			account.IsAvailable() &&
+			!s.overQuota(ctx, account) {
 			candidates = append(candidates, account)
 		}
 	}
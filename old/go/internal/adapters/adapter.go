@@ -25,6 +25,10 @@ type SpawnOptions struct {
 
 	// ApprovalPolicy is the effective approval policy for the agent.
 	ApprovalPolicy string
+
+	// ServerPort is the port reserved for adapters that implement
+	// ServerAdapter. Zero when no port was reserved.
+	ServerPort int
 }
 
 // StateReason describes why an adapter reported a state.
@@ -56,10 +56,19 @@ func (a *claudeCodeAdapter) SpawnCommand(opts SpawnOptions) (cmd string, args []
 	if strings.EqualFold(strings.TrimSpace(opts.ApprovalPolicy), "permissive") {
 		args = append(args, "--permission-mode", "dontAsk")
 	}
+	if prompt := strings.TrimSpace(opts.InitialPrompt); prompt != "" {
+		args = append(args, prompt)
+	}
 
 	return cmd, args
 }
 
+// InjectPrompt is a no-op: Claude Code takes the initial prompt as a
+// command-line argument.
+func (a *claudeCodeAdapter) InjectPrompt(tmux TmuxClient, pane, prompt string) error {
+	return nil
+}
+
 // DetectReady reports whether the agent is ready based on screen output.
 func (a *claudeCodeAdapter) DetectReady(screen string) (bool, error) {
 	if hasClaudeStreamInit(screen) {
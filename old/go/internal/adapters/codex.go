@@ -60,10 +60,19 @@ func (a *codexAdapter) SpawnCommand(opts SpawnOptions) (cmd string, args []strin
 			args = append(args, "--ask-for-approval", "on-request")
 		}
 	}
+	if prompt := strings.TrimSpace(opts.InitialPrompt); prompt != "" {
+		args = append(args, prompt)
+	}
 
 	return cmd, args
 }
 
+// InjectPrompt is a no-op: Codex takes the initial prompt as a command-line
+// argument.
+func (a *codexAdapter) InjectPrompt(tmux TmuxClient, pane, prompt string) error {
+	return nil
+}
+
 // DetectReady reports whether the agent is ready based on screen output.
 func (a *codexAdapter) DetectReady(screen string) (bool, error) {
 	lower := strings.ToLower(screen)
@@ -0,0 +1,66 @@
+package adapters
+
+import "github.com/tOgg1/forge/internal/models"
+
+// A harness adapter is an AgentAdapter registered under the agent type it
+// drives. AgentAdapter covers the launch command (SpawnCommand), output
+// classification (DetectState), and idle detection (DetectReady); the
+// optional interfaces below cover behavior that only some harnesses need.
+// The agent service resolves adapters by name and never switches on agent
+// types, so a new harness only has to register an adapter.
+
+// PromptInjector controls how the initial prompt reaches a freshly launched
+// agent. Adapters without it get the prompt typed into the pane followed by
+// Enter once the start command was sent.
+type PromptInjector interface {
+	// InjectPrompt delivers prompt to the agent running in pane. Adapters
+	// that pass the prompt on the command line return nil without sending.
+	InjectPrompt(tmux TmuxClient, pane, prompt string) error
+}
+
+// ServerAdapter is implemented by adapters whose CLI serves an API on a
+// local port. The agent service reserves a port before launch and passes
+// it in SpawnOptions.ServerPort.
+type ServerAdapter interface {
+	RequiresServerPort() bool
+}
+
+// Resolve returns the adapter registered for the agent type, or the generic
+// fallback adapter when none is registered.
+func (r *Registry) Resolve(agentType models.AgentType) AgentAdapter {
+	if adapter := r.GetByAgentType(agentType); adapter != nil {
+		return adapter
+	}
+	if adapter := r.GetByAgentType(models.AgentTypeGeneric); adapter != nil {
+		return adapter
+	}
+	return GenericFallbackAdapter()
+}
+
+// Has reports whether an adapter is registered for the agent type.
+func (r *Registry) Has(agentType models.AgentType) bool {
+	return r.GetByAgentType(agentType) != nil
+}
+
+// Resolve returns the adapter from the default registry for the agent type,
+// falling back to the generic adapter.
+func Resolve(agentType models.AgentType) AgentAdapter {
+	return DefaultRegistry.Resolve(agentType)
+}
+
+// Has reports whether the default registry has an adapter for the agent type.
+func Has(agentType models.AgentType) bool {
+	return DefaultRegistry.Has(agentType)
+}
+
+// RequiresServerPort reports whether the adapter needs a reserved server port.
+func RequiresServerPort(adapter AgentAdapter) bool {
+	server, ok := adapter.(ServerAdapter)
+	return ok && server.RequiresServerPort()
+}
+
+var (
+	_ PromptInjector = (*claudeCodeAdapter)(nil)
+	_ PromptInjector = (*codexAdapter)(nil)
+	_ ServerAdapter  = (*openCodeAdapter)(nil)
+)
@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestRegistry_ResolveFallsBackToGeneric(t *testing.T) {
+	r := NewRegistry()
+	RegisterBuiltinAdapters(r)
+
+	if got := r.Resolve(models.AgentTypeCodex).Name(); got != string(models.AgentTypeCodex) {
+		t.Errorf("expected codex adapter, got %q", got)
+	}
+	if got := r.Resolve("aider").Name(); got != string(models.AgentTypeGeneric) {
+		t.Errorf("expected generic fallback, got %q", got)
+	}
+	if r.Has("aider") {
+		t.Error("expected aider to be unregistered")
+	}
+
+	r.MustRegister(NewGenericAdapter("aider", "aider"))
+	if !r.Has("aider") || r.Resolve("aider").Name() != "aider" {
+		t.Error("expected registered adapter to resolve by name")
+	}
+
+	if got := NewRegistry().Resolve("aider"); got == nil || got.Name() != string(models.AgentTypeGeneric) {
+		t.Errorf("expected generic fallback from empty registry, got %v", got)
+	}
+}
+
+func TestRequiresServerPort(t *testing.T) {
+	if !RequiresServerPort(NewOpenCodeAdapter()) {
+		t.Error("expected opencode to require a server port")
+	}
+	if RequiresServerPort(NewClaudeCodeAdapter()) {
+		t.Error("expected claude-code not to require a server port")
+	}
+
+	_, args := NewOpenCodeAdapter().SpawnCommand(SpawnOptions{ServerPort: 4123})
+	if len(args) < 2 || args[len(args)-2] != "--port" || args[len(args)-1] != "4123" {
+		t.Errorf("expected --port 4123, got %v", args)
+	}
+}
+
+func TestPromptInjectorPassesPromptAtLaunch(t *testing.T) {
+	for _, adapter := range []AgentAdapter{NewClaudeCodeAdapter(), NewCodexAdapter()} {
+		if _, ok := adapter.(PromptInjector); !ok {
+			t.Errorf("%s: expected PromptInjector", adapter.Name())
+			continue
+		}
+		_, args := adapter.SpawnCommand(SpawnOptions{InitialPrompt: "fix the tests"})
+		if len(args) == 0 || args[len(args)-1] != "fix the tests" {
+			t.Errorf("%s: expected prompt as last arg, got %v", adapter.Name(), args)
+		}
+	}
+	if _, ok := AgentAdapter(NewGeminiAdapter()).(PromptInjector); ok {
+		t.Error("expected gemini to use the default prompt injection")
+	}
+}
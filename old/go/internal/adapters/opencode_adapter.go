@@ -1,6 +1,10 @@
 package adapters
 
-import "github.com/tOgg1/forge/internal/models"
+import (
+	"strconv"
+
+	"github.com/tOgg1/forge/internal/models"
+)
 
 type openCodeAdapter struct {
 	*GenericAdapter
@@ -29,6 +33,21 @@ func NewOpenCodeAdapter() *openCodeAdapter {
 	return &openCodeAdapter{GenericAdapter: base}
 }
 
+// SpawnCommand returns the command and args to launch OpenCode, serving on
+// the reserved port when one was allocated.
+func (a *openCodeAdapter) SpawnCommand(opts SpawnOptions) (cmd string, args []string) {
+	cmd, args = a.GenericAdapter.SpawnCommand(opts)
+	if cmd != "" && opts.ServerPort > 0 {
+		args = append(args, "--port", strconv.Itoa(opts.ServerPort))
+	}
+	return cmd, args
+}
+
+// RequiresServerPort reports that OpenCode needs a port for its API server.
+func (a *openCodeAdapter) RequiresServerPort() bool {
+	return true
+}
+
 // SupportsUsageMetrics indicates if the adapter reports usage metrics.
 func (a *openCodeAdapter) SupportsUsageMetrics() bool {
 	return true
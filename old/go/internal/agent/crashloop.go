@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// ErrCrashLoopBackoff is returned when a restart is attempted while an agent
+// is still inside its crash-loop backoff window.
+var ErrCrashLoopBackoff = errors.New("agent is in crash-loop backoff")
+
+// CrashLoopPolicy controls crash-loop detection and restart backoff.
+type CrashLoopPolicy struct {
+	// Threshold is how many consecutive failures mark an agent crashlooping.
+	Threshold int
+
+	// BaseDelay is the restart backoff after the first failure; it doubles
+	// with each further consecutive failure.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the restart backoff.
+	MaxDelay time.Duration
+}
+
+// DefaultCrashLoopPolicy returns the default crash-loop policy.
+func DefaultCrashLoopPolicy() CrashLoopPolicy {
+	return CrashLoopPolicy{
+		Threshold: 3,
+		BaseDelay: 10 * time.Second,
+		MaxDelay:  10 * time.Minute,
+	}
+}
+
+// WithCrashLoopPolicy overrides the crash-loop policy. Zero fields keep
+// their defaults.
+func WithCrashLoopPolicy(policy CrashLoopPolicy) ServiceOption {
+	return func(s *Service) {
+		defaults := DefaultCrashLoopPolicy()
+		if policy.Threshold <= 0 {
+			policy.Threshold = defaults.Threshold
+		}
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = defaults.BaseDelay
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = defaults.MaxDelay
+		}
+		s.crashLoop = policy
+	}
+}
+
+// backoff returns the restart delay after the given number of consecutive
+// failures.
+func (p CrashLoopPolicy) backoff(failures int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// RecordAgentFailure counts a failure against the agent and extends its
+// restart backoff. When the consecutive failures reach the policy threshold
+// the agent is marked crashlooping (state error, reason prefixed with
+// "crashlooping") and an agent.crashlooping event is published so the
+// scheduler and TUI stop feeding it work.
+func (s *Service) RecordAgentFailure(ctx context.Context, id, reason string) (*models.CrashLoopInfo, error) {
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordFailure(ctx, agent, reason), nil
+}
+
+func (s *Service) recordFailure(ctx context.Context, agent *models.Agent, reason string) *models.CrashLoopInfo {
+	now := time.Now().UTC()
+	info := agent.Metadata.CrashLoop
+	if info == nil {
+		info = &models.CrashLoopInfo{}
+		agent.Metadata.CrashLoop = info
+	}
+	info.ConsecutiveFailures++
+	info.LastFailure = strings.TrimSpace(reason)
+	info.LastFailureAt = &now
+	backoffUntil := now.Add(s.crashLoop.backoff(info.ConsecutiveFailures))
+	info.BackoffUntil = &backoffUntil
+
+	newlyLooping := !info.CrashLooping && info.ConsecutiveFailures >= s.crashLoop.Threshold
+	if info.ConsecutiveFailures >= s.crashLoop.Threshold {
+		info.CrashLooping = true
+		agent.State = models.AgentStateError
+		agent.StateInfo = models.StateInfo{
+			State:      models.AgentStateError,
+			Confidence: models.StateConfidenceHigh,
+			Reason: fmt.Sprintf("%s: %d consecutive failures, restart backoff until %s",
+				models.CrashLoopReasonPrefix, info.ConsecutiveFailures, backoffUntil.Format(time.RFC3339)),
+			Evidence:   []string{info.LastFailure},
+			DetectedAt: now,
+		}
+	}
+
+	if err := s.repo.Update(ctx, agent); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to persist agent failure")
+	}
+
+	s.logger.Warn().
+		Str("agent_id", agent.ID).
+		Int("consecutive_failures", info.ConsecutiveFailures).
+		Time("backoff_until", backoffUntil).
+		Bool("crash_looping", info.CrashLooping).
+		Msg("agent failure recorded")
+
+	if newlyLooping {
+		s.publishEvent(ctx, models.EventTypeAgentCrashLooping, agent.ID, models.CrashLoopPayload{
+			ConsecutiveFailures: info.ConsecutiveFailures,
+			LastFailure:         info.LastFailure,
+			BackoffUntil:        backoffUntil,
+		})
+	}
+	return info
+}
+
+// ResetCrashLoop clears an agent's failure count and backoff, e.g. after it
+// completes work or an operator intervenes.
+func (s *Service) ResetCrashLoop(ctx context.Context, id string) error {
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if agent.Metadata.CrashLoop == nil {
+		return nil
+	}
+	agent.Metadata.CrashLoop = nil
+	if err := s.repo.Update(ctx, agent); err != nil {
+		return err
+	}
+	s.logger.Info().Str("agent_id", id).Msg("agent crash-loop state reset")
+	return nil
+}
+
+// ObserveStateChange feeds detected state transitions into crash-loop
+// tracking: entering the error state counts as a failure, and finishing
+// work (working -> idle) counts as a healthy run and also resets the
+// recovery attempt count.
+func (s *Service) ObserveStateChange(ctx context.Context, id string, from, to models.AgentState, reason string) {
+	var err error
+	switch {
+	case to == models.AgentStateError && from != models.AgentStateError:
+		_, err = s.RecordAgentFailure(ctx, id, reason)
+	case from == models.AgentStateWorking && to == models.AgentStateIdle:
+		err = s.ResetCrashLoop(ctx, id)
+		if err == nil {
+			err = s.ResetRecovery(ctx, id)
+		}
+	}
+	if err != nil && !errors.Is(err, ErrServiceAgentNotFound) {
+		s.logger.Warn().Err(err).Str("agent_id", id).Msg("failed to update crash-loop tracking")
+	}
+}
+
+// checkRestartBackoff returns ErrCrashLoopBackoff while the agent's restart
+// backoff is active.
+func checkRestartBackoff(agent *models.Agent, now time.Time) error {
+	if remaining := agent.Metadata.CrashLoop.BackoffRemaining(now); remaining > 0 {
+		return fmt.Errorf("%w: %d consecutive failures, retry in %s",
+			ErrCrashLoopBackoff, agent.Metadata.CrashLoop.ConsecutiveFailures, remaining.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestCrashLoopPolicy_BackoffDoublesAndCaps(t *testing.T) {
+	policy := CrashLoopPolicy{Threshold: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	cases := map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	}
+	for failures, want := range cases {
+		if got := policy.backoff(failures); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestCheckRestartBackoff(t *testing.T) {
+	now := time.Now().UTC()
+	until := now.Add(30 * time.Second)
+	agent := &models.Agent{ID: "agent-1"}
+
+	if err := checkRestartBackoff(agent, now); err != nil {
+		t.Fatalf("expected no backoff without crash-loop info, got %v", err)
+	}
+
+	agent.Metadata.CrashLoop = &models.CrashLoopInfo{ConsecutiveFailures: 3, CrashLooping: true, BackoffUntil: &until}
+	if err := checkRestartBackoff(agent, now); !errors.Is(err, ErrCrashLoopBackoff) {
+		t.Fatalf("expected ErrCrashLoopBackoff, got %v", err)
+	}
+	if err := checkRestartBackoff(agent, until.Add(time.Second)); err != nil {
+		t.Fatalf("expected backoff to expire, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Heartbeat sources recorded in models.HeartbeatInfo.Source.
+const (
+	HeartbeatSourceFile  = "file"
+	HeartbeatSourceFmail = "fmail"
+)
+
+// maxHeartbeatFileSize bounds how much of a heartbeat file is parsed.
+const maxHeartbeatFileSize = 4096
+
+// HeartbeatPolicy controls how harness heartbeats are collected and when an
+// agent that stopped sending them is marked stalled.
+//
+// A harness wrapper signals liveness in one of two ways: by writing (or just
+// touching) the file named by FORGE_HEARTBEAT_FILE, or by posting through
+// fmail as the agent's FMAIL_AGENT, e.g. `fmail status working`. Agents are
+// only tracked once a first heartbeat has been seen, so harnesses without a
+// wrapper are never marked stalled.
+type HeartbeatPolicy struct {
+	// Dir holds one heartbeat file per agent. Empty disables file
+	// heartbeats; fmail heartbeats are still read.
+	Dir string
+
+	// Timeout is how long an agent may go without a heartbeat before it is
+	// marked stalled. Zero disables stall detection.
+	Timeout time.Duration
+
+	// HarnessTimeouts overrides Timeout by agent type. A zero entry
+	// disables stall detection for that harness.
+	HarnessTimeouts map[models.AgentType]time.Duration
+}
+
+// DefaultHeartbeatPolicy returns the default heartbeat policy: fmail
+// heartbeats only, stalled after five minutes of silence.
+func DefaultHeartbeatPolicy() HeartbeatPolicy {
+	return HeartbeatPolicy{Timeout: 5 * time.Minute}
+}
+
+// WithHeartbeatPolicy overrides the heartbeat policy.
+func WithHeartbeatPolicy(policy HeartbeatPolicy) ServiceOption {
+	return func(s *Service) {
+		policy.Dir = strings.TrimSpace(policy.Dir)
+		s.heartbeat = policy
+	}
+}
+
+// TimeoutFor returns the stall timeout for a harness, or zero when stall
+// detection is disabled for it.
+func (p HeartbeatPolicy) TimeoutFor(agentType models.AgentType) time.Duration {
+	if timeout, ok := p.HarnessTimeouts[agentType]; ok {
+		return timeout
+	}
+	return p.Timeout
+}
+
+// HeartbeatPath returns the heartbeat file for an agent under dir.
+func HeartbeatPath(dir, agentID string) string {
+	return filepath.Join(dir, agentID+".json")
+}
+
+// readHeartbeatFile returns the heartbeat recorded in path. An empty or
+// unparsable file counts as a heartbeat at its modification time.
+func readHeartbeatFile(path string) (models.AgentHeartbeat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return models.AgentHeartbeat{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return models.AgentHeartbeat{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxHeartbeatFileSize))
+	if err != nil {
+		return models.AgentHeartbeat{}, err
+	}
+	var beat models.AgentHeartbeat
+	if json.Unmarshal(data, &beat) != nil || beat.Timestamp.IsZero() {
+		beat = models.AgentHeartbeat{Timestamp: info.ModTime()}
+	}
+	beat.Timestamp = beat.Timestamp.UTC()
+	beat.Status = strings.TrimSpace(beat.Status)
+	return beat, nil
+}
+
+// heartbeatEnv returns env with the agent's heartbeat file added, leaving
+// the original map untouched.
+func (s *Service) heartbeatEnv(agentID string, env map[string]string) map[string]string {
+	if s.heartbeat.Dir == "" {
+		return env
+	}
+	// Create the directory up front so shell wrappers can just touch the file.
+	if err := os.MkdirAll(s.heartbeat.Dir, 0o755); err != nil {
+		s.logger.Warn().Err(err).Str("dir", s.heartbeat.Dir).Msg("failed to create heartbeat directory")
+	}
+	out := make(map[string]string, len(env)+1)
+	for key, value := range env {
+		out[key] = value
+	}
+	out[models.HeartbeatFileEnv] = HeartbeatPath(s.heartbeat.Dir, agentID)
+	return out
+}
+
+// resetHeartbeat forgets an agent's heartbeat before it is restarted, so a
+// beat from the previous process does not count for the new one.
+func (s *Service) resetHeartbeat(agent *models.Agent) {
+	agent.Metadata.Heartbeat = nil
+	if s.heartbeat.Dir == "" {
+		return
+	}
+	if err := os.Remove(HeartbeatPath(s.heartbeat.Dir, agent.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Debug().Err(err).Str("agent_id", agent.ID).Msg("failed to remove heartbeat file")
+	}
+}
+
+// latestHeartbeat returns the newest heartbeat from the agent's file and its
+// fmail registry entry.
+func (s *Service) latestHeartbeat(agent *models.Agent) (models.HeartbeatInfo, bool) {
+	var latest models.HeartbeatInfo
+	found := false
+	if s.heartbeat.Dir != "" {
+		beat, err := readHeartbeatFile(HeartbeatPath(s.heartbeat.Dir, agent.ID))
+		if err == nil {
+			latest = models.HeartbeatInfo{LastBeat: beat.Timestamp, Source: HeartbeatSourceFile, Status: beat.Status}
+			found = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Debug().Err(err).Str("agent_id", agent.ID).Msg("failed to read heartbeat file")
+		}
+	}
+	if record := fmailAgentRecord(agent); record != nil && record.LastSeen.After(latest.LastBeat) {
+		latest = models.HeartbeatInfo{LastBeat: record.LastSeen.UTC(), Source: HeartbeatSourceFmail, Status: record.Status}
+		found = true
+	}
+	return latest, found
+}
+
+// fmailAgentRecord looks up the fmail registry entry of the agent's
+// FMAIL_AGENT in the project the agent works in.
+func fmailAgentRecord(agent *models.Agent) *fmail.AgentRecord {
+	env := agent.Metadata.Environment
+	name := strings.TrimSpace(env[fmail.EnvAgent])
+	if name == "" {
+		return nil
+	}
+	root := strings.TrimSpace(env[fmail.EnvRoot])
+	if root == "" {
+		discovered, err := fmail.DiscoverProjectRoot(agent.Metadata.WorkingDir)
+		if err != nil {
+			return nil
+		}
+		root = discovered
+	}
+	store, err := fmail.NewStore(root)
+	if err != nil {
+		return nil
+	}
+	record, err := store.ReadAgentRecord(name)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// CheckHeartbeats reads every agent's latest heartbeat and returns the
+// agents newly marked stalled. An agent is stalled once it has gone longer
+// than its harness timeout without a heartbeat; an agent.stalled event is
+// published then, and an agent.alive event when heartbeats resume. Stopped
+// and paused agents are not marked stalled.
+func (s *Service) CheckHeartbeats(ctx context.Context) ([]*models.Agent, error) {
+	agents, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var stalled []*models.Agent
+	for _, agent := range agents {
+		if agent.State == models.AgentStateStopped {
+			continue
+		}
+		timeout := s.heartbeat.TimeoutFor(agent.Type)
+		info := agent.Metadata.Heartbeat
+		changed := false
+
+		if beat, ok := s.latestHeartbeat(agent); ok && (info == nil || beat.LastBeat.After(info.LastBeat)) {
+			if info.IsStalled() {
+				s.logger.Info().Str("agent_id", agent.ID).Str("source", beat.Source).Msg("agent heartbeat resumed")
+				s.publishEvent(ctx, models.EventTypeAgentAlive, agent.ID, heartbeatPayload(beat, timeout))
+			}
+			info = &beat
+			agent.Metadata.Heartbeat = info
+			changed = true
+		}
+		if info == nil {
+			continue
+		}
+
+		if timeout > 0 && !info.Stalled && agent.State != models.AgentStatePaused && now.Sub(info.LastBeat) > timeout {
+			info.Stalled = true
+			info.StalledAt = &now
+			changed = true
+			stalled = append(stalled, agent)
+			s.logger.Warn().
+				Str("agent_id", agent.ID).
+				Time("last_beat", info.LastBeat).
+				Dur("timeout", timeout).
+				Msg("agent stalled")
+			s.publishEvent(ctx, models.EventTypeAgentStalled, agent.ID, heartbeatPayload(*info, timeout))
+		}
+
+		if changed {
+			if err := s.repo.Update(ctx, agent); err != nil {
+				s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to persist agent heartbeat")
+			}
+		}
+	}
+	return stalled, nil
+}
+
+func heartbeatPayload(info models.HeartbeatInfo, timeout time.Duration) models.AgentHeartbeatPayload {
+	return models.AgentHeartbeatPayload{
+		LastBeat: info.LastBeat,
+		Source:   info.Source,
+		Status:   info.Status,
+		Timeout:  timeout.String(),
+	}
+}
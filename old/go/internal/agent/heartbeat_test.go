@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestHeartbeatPolicy_TimeoutFor(t *testing.T) {
+	policy := HeartbeatPolicy{
+		Timeout:         time.Minute,
+		HarnessTimeouts: map[models.AgentType]time.Duration{models.AgentTypeCodex: 0, models.AgentTypeGemini: time.Hour},
+	}
+	cases := map[models.AgentType]time.Duration{
+		models.AgentTypeClaudeCode: time.Minute,
+		models.AgentTypeCodex:      0,
+		models.AgentTypeGemini:     time.Hour,
+	}
+	for agentType, want := range cases {
+		if got := policy.TimeoutFor(agentType); got != want {
+			t.Errorf("TimeoutFor(%s) = %s, want %s", agentType, got, want)
+		}
+	}
+}
+
+func TestCheckHeartbeats_FileMarksStalledAndAlive(t *testing.T) {
+	ctx := context.Background()
+	agentModel := &models.Agent{State: models.AgentStateWorking}
+	service, agentRepo, published := newTakeoverService(t, agentModel)
+	dir := t.TempDir()
+	WithHeartbeatPolicy(HeartbeatPolicy{Dir: dir, Timeout: time.Minute})(service)
+
+	stalled, err := service.CheckHeartbeats(ctx)
+	if err != nil {
+		t.Fatalf("CheckHeartbeats: %v", err)
+	}
+	if len(stalled) != 0 || len(*published) != 0 {
+		t.Fatalf("agent without heartbeats should not be tracked, got %d stalled, %d events", len(stalled), len(*published))
+	}
+
+	env := service.heartbeatEnv(agentModel.ID, nil)
+	path := env[models.HeartbeatFileEnv]
+	if path != HeartbeatPath(dir, agentModel.ID) {
+		t.Fatalf("heartbeat env = %v", env)
+	}
+	old := time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339)
+	if err := os.WriteFile(path, []byte(`{"ts":"`+old+`","status":"working"}`), 0o644); err != nil {
+		t.Fatalf("write heartbeat: %v", err)
+	}
+
+	stalled, err = service.CheckHeartbeats(ctx)
+	if err != nil {
+		t.Fatalf("CheckHeartbeats: %v", err)
+	}
+	if len(stalled) != 1 || stalled[0].ID != agentModel.ID {
+		t.Fatalf("expected the agent to stall, got %d", len(stalled))
+	}
+	got, err := agentRepo.Get(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if info := got.Metadata.Heartbeat; !info.IsStalled() || info.Source != HeartbeatSourceFile || info.Status != "working" {
+		t.Fatalf("expected a stalled file heartbeat, got %+v", info)
+	}
+	if len(*published) != 1 || (*published)[0].Type != models.EventTypeAgentStalled {
+		t.Fatalf("expected one agent.stalled event, got %+v", *published)
+	}
+
+	if stalled, _ := service.CheckHeartbeats(ctx); len(stalled) != 0 || len(*published) != 1 {
+		t.Fatalf("a stalled agent should only be reported once")
+	}
+
+	// A wrapper that just touches the file counts too.
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("touch heartbeat: %v", err)
+	}
+	if _, err := service.CheckHeartbeats(ctx); err != nil {
+		t.Fatalf("CheckHeartbeats: %v", err)
+	}
+	got, _ = agentRepo.Get(ctx, agentModel.ID)
+	if got.Metadata.Heartbeat.IsStalled() {
+		t.Fatalf("expected the agent to be alive again, got %+v", got.Metadata.Heartbeat)
+	}
+	if len(*published) != 2 || (*published)[1].Type != models.EventTypeAgentAlive {
+		t.Fatalf("expected an agent.alive event, got %+v", *published)
+	}
+}
+
+func TestCheckHeartbeats_FmailStatusAndHarnessOverride(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	agentModel := &models.Agent{
+		State:    models.AgentStateIdle,
+		Metadata: models.AgentMetadata{Environment: map[string]string{fmail.EnvAgent: "builder", fmail.EnvRoot: root}},
+	}
+	service, agentRepo, published := newTakeoverService(t, agentModel)
+	WithHeartbeatPolicy(HeartbeatPolicy{
+		Timeout:         time.Minute,
+		HarnessTimeouts: map[models.AgentType]time.Duration{models.AgentTypeClaudeCode: 0},
+	})(service)
+
+	past := time.Now().UTC().Add(-time.Hour)
+	store, err := fmail.NewStore(root, fmail.WithNow(func() time.Time { return past }))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	if _, err := store.SetAgentStatus("builder", "reviewing", ""); err != nil {
+		t.Fatalf("set status: %v", err)
+	}
+
+	stalled, err := service.CheckHeartbeats(ctx)
+	if err != nil {
+		t.Fatalf("CheckHeartbeats: %v", err)
+	}
+	if len(stalled) != 0 || len(*published) != 0 {
+		t.Fatalf("stall detection is disabled for the harness, got %d stalled", len(stalled))
+	}
+	got, err := agentRepo.Get(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	info := got.Metadata.Heartbeat
+	if info == nil || info.Source != HeartbeatSourceFmail || info.Status != "reviewing" || !info.LastBeat.Equal(past) {
+		t.Fatalf("expected an fmail heartbeat, got %+v", info)
+	}
+}
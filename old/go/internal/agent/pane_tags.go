@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+// tagAgentPane records the agent ID on its pane (tmux.AgentIDOption) so the
+// pane can be matched back to the agent after it is renumbered or moved.
+// Tagging is best effort: pane options need tmux 3.0 or newer.
+func (s *Service) tagAgentPane(ctx context.Context, agent *models.Agent) {
+	if agent == nil || agent.TmuxPane == "" {
+		return
+	}
+	if err := s.tmuxClient.TagPaneAgent(ctx, agent.TmuxPane, agent.ID); err != nil {
+		s.logger.Debug().Err(err).Str("agent_id", agent.ID).Str("pane", agent.TmuxPane).Msg("failed to tag agent pane")
+	}
+}
+
+// relinkAgentPane looks for a pane tagged with the agent's ID and, when the
+// agent's recorded pane target no longer matches it, points the agent at the
+// tagged pane. It reports whether the agent was relinked.
+func (s *Service) relinkAgentPane(ctx context.Context, agent *models.Agent) bool {
+	pane, err := s.tmuxClient.FindPaneByAgentID(ctx, agent.ID)
+	if err != nil {
+		if !errors.Is(err, tmux.ErrPaneNotFound) {
+			s.logger.Debug().Err(err).Str("agent_id", agent.ID).Msg("failed to look up tagged pane")
+		}
+		return false
+	}
+	if pane.ID == agent.TmuxPane {
+		return false
+	}
+
+	oldPane := agent.TmuxPane
+	agent.TmuxPane = pane.ID
+	if err := s.repo.Update(ctx, agent); err != nil {
+		agent.TmuxPane = oldPane
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to relink agent pane")
+		return false
+	}
+	if err := s.paneMap.UnregisterAgent(agent.ID); err != nil && !errors.Is(err, ErrAgentNotFound) {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to unregister stale pane mapping")
+	}
+	if err := s.paneMap.Register(agent.ID, pane.ID, pane.ID); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to register pane mapping")
+	}
+
+	s.logger.Info().
+		Str("agent_id", agent.ID).
+		Str("old_pane", oldPane).
+		Str("new_pane", pane.ID).
+		Str("session", pane.Session).
+		Msg("relinked agent to tagged pane")
+	return true
+}
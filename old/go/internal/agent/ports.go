@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+)
+
+// DefaultPortLeaseTTL is how long a port reserved during spawn is held
+// before the agent record must bind it.
+const DefaultPortLeaseTTL = 2 * time.Minute
+
+// PortAllocator hands out OpenCode server ports. Allocations live in the
+// database, so every forge process on the host shares one view of the range;
+// a reservation is a lease that expires unless the spawned agent binds it,
+// and ports held by stopped or deleted agents are reclaimed before each
+// reservation. On the local node, ports something is already listening on
+// are skipped.
+type PortAllocator struct {
+	repo           *db.PortRepository
+	leaseTTL       time.Duration
+	checkListening bool
+	inUse          func(port int) bool
+}
+
+// NewPortAllocator creates an allocator over repo. A zero leaseTTL uses
+// DefaultPortLeaseTTL.
+func NewPortAllocator(repo *db.PortRepository, leaseTTL time.Duration, checkListening bool) *PortAllocator {
+	if leaseTTL <= 0 {
+		leaseTTL = DefaultPortLeaseTTL
+	}
+	return &PortAllocator{
+		repo:           repo,
+		leaseTTL:       leaseTTL,
+		checkListening: checkListening,
+		inUse:          LocalPortInUse,
+	}
+}
+
+// WithPortAllocator configures the allocator used for OpenCode agent ports.
+func WithPortAllocator(allocator *PortAllocator) ServiceOption {
+	return func(s *Service) {
+		s.ports = allocator
+	}
+}
+
+// Repository returns the underlying port repository.
+func (a *PortAllocator) Repository() *db.PortRepository {
+	return a.repo
+}
+
+// Reserve leases a free port on nodeID. Listening sockets are only checked
+// when the node is the local machine.
+func (a *PortAllocator) Reserve(ctx context.Context, nodeID string, local bool, reason string) (int, error) {
+	if _, err := a.repo.ReclaimStale(ctx); err != nil {
+		return 0, err
+	}
+	var inUse func(port int) bool
+	if local && a.checkListening {
+		inUse = a.inUse
+	}
+	return a.repo.AllocateLease(ctx, nodeID, reason, a.leaseTTL, inUse)
+}
+
+// Bind attaches a reserved port to the agent that will serve on it.
+func (a *PortAllocator) Bind(ctx context.Context, nodeID string, port int, agentID string) error {
+	return a.repo.Bind(ctx, nodeID, port, agentID)
+}
+
+// Release frees a single port, ignoring ports that are already free.
+func (a *PortAllocator) Release(ctx context.Context, nodeID string, port int) error {
+	if err := a.repo.Release(ctx, nodeID, port); err != nil && !errors.Is(err, db.ErrPortNotAllocated) {
+		return err
+	}
+	return nil
+}
+
+// ReleaseAgent frees every port bound to an agent.
+func (a *PortAllocator) ReleaseAgent(ctx context.Context, agentID string) (int, error) {
+	return a.repo.ReleaseByAgent(ctx, agentID)
+}
+
+// Reclaim frees expired leases and ports held by stopped or deleted agents.
+func (a *PortAllocator) Reclaim(ctx context.Context) (int, error) {
+	return a.repo.ReclaimStale(ctx)
+}
+
+// LocalPortInUse reports whether a TCP port on this machine is already
+// bound, by trying to listen on it.
+func LocalPortInUse(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return true
+	}
+	_ = listener.Close()
+	return false
+}
+
+// isLocalWorkspace reports whether a workspace's node is this machine.
+func (s *Service) isLocalWorkspace(ctx context.Context, workspaceID string) bool {
+	if s.workspaceService == nil {
+		return false
+	}
+	nodeObj, err := s.workspaceService.GetWorkspaceNode(ctx, workspaceID)
+	return err == nil && nodeObj.IsLocal
+}
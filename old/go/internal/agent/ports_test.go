@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestPortAllocator_ReserveSkipsListeningPorts(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	node := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, IsLocal: true}
+	if err := db.NewNodeRepository(database).Create(ctx, node); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+	if busy == 65535 {
+		t.Skip("ephemeral port at the top of the range")
+	}
+
+	allocator := NewPortAllocator(db.NewPortRepositoryWithRange(database, busy, busy+1), 0, true)
+	port, err := allocator.Reserve(ctx, node.ID, true, "test")
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if port != busy+1 {
+		t.Fatalf("expected listening port %d to be skipped, got %d", busy, port)
+	}
+
+	// Remote nodes are not probed, so the busy port is handed out there.
+	remote := &models.Node{Name: "remote", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, SSHTarget: "user@remote"}
+	if err := db.NewNodeRepository(database).Create(ctx, remote); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	port, err = allocator.Reserve(ctx, remote.ID, false, "test")
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if port != busy {
+		t.Fatalf("expected remote reservation of %d, got %d", busy, port)
+	}
+}
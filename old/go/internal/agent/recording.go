@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+// RecordingOptions configures agent screen recording.
+type RecordingOptions struct {
+	// Dir holds one subdirectory of recordings per agent.
+	Dir string
+
+	// Interval is how often the pane is captured.
+	Interval time.Duration
+
+	// Cast controls file rotation and retention per agent.
+	Cast tmux.CastOptions
+}
+
+// Recorder streams agent panes into asciicast v2 files under
+// <dir>/<agent-id>. Each spawn or restart starts a new session whose files
+// are named after its start time, so sessions can be replayed separately
+// with `asciinema play`. Recording only lasts as long as the process that
+// started it, which makes it a daemon feature.
+type Recorder struct {
+	client *tmux.Client
+	opts   RecordingOptions
+	logger zerolog.Logger
+
+	mu     sync.Mutex
+	active map[string]*agentRecording
+}
+
+type agentRecording struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRecorder creates a recorder that captures panes through client.
+func NewRecorder(client *tmux.Client, opts RecordingOptions) *Recorder {
+	return &Recorder{
+		client: client,
+		opts:   opts,
+		logger: logging.Component("recorder"),
+		active: make(map[string]*agentRecording),
+	}
+}
+
+// WithRecorder records every agent the service spawns or restarts.
+func WithRecorder(recorder *Recorder) ServiceOption {
+	return func(s *Service) {
+		s.recorder = recorder
+	}
+}
+
+// Start begins a new recording session for agent, ending any session
+// already running for it.
+func (r *Recorder) Start(agent *models.Agent) error {
+	if agent == nil || agent.TmuxPane == "" {
+		return fmt.Errorf("agent has no pane to record")
+	}
+	dir, err := r.agentDir(agent.ID)
+	if err != nil {
+		return err
+	}
+	opts := r.opts.Cast
+	opts.Title = fmt.Sprintf("forge agent %s (%s)", agent.ID, agent.Type)
+	writer, err := tmux.NewCastWriter(dir, time.Now().UTC().Format("20060102T150405Z"), opts)
+	if err != nil {
+		return err
+	}
+
+	r.Stop(agent.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := &agentRecording{cancel: cancel, done: make(chan struct{})}
+	r.mu.Lock()
+	r.active[agent.ID] = rec
+	r.mu.Unlock()
+
+	go func() {
+		defer close(rec.done)
+		err := r.client.RecordPane(ctx, agent.TmuxPane, writer, r.opts.Interval)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			r.logger.Debug().Err(err).Str("agent_id", agent.ID).Msg("agent recording ended")
+		}
+		r.mu.Lock()
+		if r.active[agent.ID] == rec {
+			delete(r.active, agent.ID)
+		}
+		r.mu.Unlock()
+	}()
+	return nil
+}
+
+// Stop ends the agent's recording session, if any, and waits for its file
+// to be closed.
+func (r *Recorder) Stop(agentID string) {
+	r.mu.Lock()
+	rec := r.active[agentID]
+	delete(r.active, agentID)
+	r.mu.Unlock()
+	if rec != nil {
+		rec.cancel()
+		<-rec.done
+	}
+}
+
+// StopAll ends every recording session.
+func (r *Recorder) StopAll() {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.active))
+	for id := range r.active {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+	for _, id := range ids {
+		r.Stop(id)
+	}
+}
+
+// Active reports whether the agent is being recorded.
+func (r *Recorder) Active(agentID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.active[agentID]
+	return ok
+}
+
+// List returns the agent's recordings, oldest first.
+func (r *Recorder) List(agentID string) ([]tmux.CastFile, error) {
+	dir, err := r.agentDir(agentID)
+	if err != nil {
+		return nil, err
+	}
+	return tmux.ListCasts(dir)
+}
+
+// Open opens one of the agent's recordings by file name.
+func (r *Recorder) Open(agentID, name string) (*os.File, error) {
+	dir, err := r.agentDir(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPlainFileName(name) || filepath.Ext(name) != tmux.CastExt {
+		return nil, fmt.Errorf("invalid recording name %q", name)
+	}
+	return os.Open(filepath.Join(dir, name))
+}
+
+// Handler serves recordings over HTTP for mounting on the daemon:
+//
+//	GET /recordings/{agent}         JSON list of the agent's recordings
+//	GET /recordings/{agent}/{name}  download one .cast file
+func (r *Recorder) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /recordings/{agent}", func(w http.ResponseWriter, req *http.Request) {
+		files, err := r.List(req.PathValue("agent"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if files == nil {
+			files = []tmux.CastFile{}
+		}
+		// Paths are local to the daemon host.
+		for i := range files {
+			files[i].Path = ""
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(files)
+	})
+	mux.HandleFunc("GET /recordings/{agent}/{name}", func(w http.ResponseWriter, req *http.Request) {
+		name := req.PathValue("name")
+		file, err := r.Open(req.PathValue("agent"), name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				http.NotFound(w, req)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-asciicast")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		http.ServeContent(w, req, name, info.ModTime(), file)
+	})
+	return mux
+}
+
+func (r *Recorder) agentDir(agentID string) (string, error) {
+	if !isPlainFileName(agentID) {
+		return "", fmt.Errorf("invalid agent id %q", agentID)
+	}
+	return filepath.Join(r.opts.Dir, agentID), nil
+}
+
+// isPlainFileName rejects empty names and anything that could leave the
+// recording directory.
+func isPlainFileName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`) && filepath.Base(name) == name
+}
+
+// startRecording records a freshly started agent. Failures are logged and
+// the agent runs unrecorded.
+func (s *Service) startRecording(agent *models.Agent) {
+	if s.recorder == nil || agent == nil {
+		return
+	}
+	if err := s.recorder.Start(agent); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to start agent recording")
+	}
+}
+
+func (s *Service) stopRecording(agentID string) {
+	if s.recorder != nil {
+		s.recorder.Stop(agentID)
+	}
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+func TestRecorderHandlerListsAndServesRecordings(t *testing.T) {
+	dir := t.TempDir()
+	agentDir := filepath.Join(dir, "agent-1")
+	if err := os.MkdirAll(agentDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	cast := "{\"version\":2,\"width\":80,\"height\":24}\n[0,\"o\",\"hi\"]\n"
+	if err := os.WriteFile(filepath.Join(agentDir, "20260101T000000Z-001.cast"), []byte(cast), 0o644); err != nil {
+		t.Fatalf("write cast: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+
+	handler := NewRecorder(nil, RecordingOptions{Dir: dir}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recordings/agent-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var files []tmux.CastFile
+	if err := json.Unmarshal(rec.Body.Bytes(), &files); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "20260101T000000Z-001.cast" || files[0].Path != "" {
+		t.Fatalf("unexpected list %+v", files)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recordings/agent-1/20260101T000000Z-001.cast", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != cast {
+		t.Fatalf("download status = %d body = %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/x-asciicast" {
+		t.Fatalf("content type = %q", got)
+	}
+
+	for path, want := range map[string]int{
+		"/recordings/agent-1/missing.cast": http.StatusNotFound,
+		"/recordings/agent-1/notes.txt":    http.StatusBadRequest,
+		"/recordings/..%2Fetc":             http.StatusBadRequest,
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestRecorderListWithoutRecordings(t *testing.T) {
+	recorder := NewRecorder(nil, RecordingOptions{Dir: t.TempDir()})
+	files, err := recorder.List("agent-2")
+	if err != nil || len(files) != 0 {
+		t.Fatalf("expected no recordings, got %v, %v", files, err)
+	}
+	if _, err := recorder.List("../etc"); err == nil {
+		t.Fatalf("expected traversal to be rejected")
+	}
+}
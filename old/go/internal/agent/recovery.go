@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tmux"
+	"github.com/tOgg1/forge/internal/tracing"
+)
+
+// ErrRecoveryExhausted is returned when an agent's pane is gone and it has
+// already used all of its automatic recovery attempts.
+var ErrRecoveryExhausted = errors.New("agent recovery attempts exhausted")
+
+// RecoveryPolicy controls automatic recovery of agents whose tmux session
+// or pane disappeared.
+type RecoveryPolicy struct {
+	// MaxAttempts caps recoveries between healthy runs (working -> idle).
+	MaxAttempts int
+}
+
+// DefaultRecoveryPolicy returns the default recovery policy.
+func DefaultRecoveryPolicy() RecoveryPolicy {
+	return RecoveryPolicy{MaxAttempts: 3}
+}
+
+// WithRecoveryPolicy overrides the recovery policy. Zero fields keep their
+// defaults.
+func WithRecoveryPolicy(policy RecoveryPolicy) ServiceOption {
+	return func(s *Service) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = DefaultRecoveryPolicy().MaxAttempts
+		}
+		s.recovery = policy
+	}
+}
+
+// recoveryCandidate reports whether an agent should be checked for a dead
+// pane: it has a pane, is not stopped, and is not crashlooping or already
+// out of recovery attempts.
+func recoveryCandidate(agent *models.Agent) bool {
+	if agent == nil || strings.TrimSpace(agent.TmuxPane) == "" {
+		return false
+	}
+	if agent.State == models.AgentStateStopped {
+		return false
+	}
+	if agent.Metadata.CrashLoop.IsCrashLooping() {
+		return false
+	}
+	return agent.Metadata.Recovery == nil || !agent.Metadata.Recovery.Exhausted
+}
+
+// RecoverDeadAgents recovers every agent whose tmux pane has disappeared and
+// returns the agents that were recovered. Failures are recorded on the agent
+// and published as events; they do not stop the scan.
+func (s *Service) RecoverDeadAgents(ctx context.Context) ([]*models.Agent, error) {
+	agents, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	var recovered []*models.Agent
+	for _, agent := range agents {
+		if !recoveryCandidate(agent) {
+			continue
+		}
+		exists, err := s.paneExists(ctx, agent.TmuxPane)
+		if err != nil {
+			s.logger.Debug().Err(err).Str("agent_id", agent.ID).Msg("failed to check agent pane")
+			continue
+		}
+		if exists || s.relinkAgentPane(ctx, agent) {
+			continue
+		}
+		if err := s.recoverAgent(ctx, agent); err != nil {
+			if !errors.Is(err, ErrRecoveryExhausted) {
+				s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("agent recovery failed")
+			}
+			continue
+		}
+		recovered = append(recovered, agent)
+	}
+	return recovered, nil
+}
+
+// RecoverAgent recovers an agent whose tmux pane has disappeared: the
+// workspace session is recreated if needed, a new pane is opened in the
+// agent's working directory, and the harness launch command is replayed.
+// An agent whose pane still exists is returned unchanged, and one whose
+// tagged pane turns up under a new pane ID is relinked instead of respawned.
+func (s *Service) RecoverAgent(ctx context.Context, id string) (_ *models.Agent, err error) {
+	ctx, span := tracing.Start(ctx, "agent.recover", tracing.String("agent_id", id))
+	defer func() { span.RecordError(err); span.End() }()
+
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(agent.TmuxPane) != "" {
+		exists, err := s.paneExists(ctx, agent.TmuxPane)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check agent pane: %w", err)
+		}
+		if exists {
+			return agent, nil
+		}
+	}
+	if s.relinkAgentPane(ctx, agent) {
+		return agent, nil
+	}
+	if err := s.recoverAgent(ctx, agent); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+func (s *Service) recoverAgent(ctx context.Context, agent *models.Agent) error {
+	info := agent.Metadata.Recovery
+	if info == nil {
+		info = &models.RecoveryInfo{}
+		agent.Metadata.Recovery = info
+	}
+	oldPane := agent.TmuxPane
+	payload := models.AgentRecoveryPayload{
+		Attempt:     info.Attempts,
+		MaxAttempts: s.recovery.MaxAttempts,
+		OldPane:     oldPane,
+	}
+
+	if info.Attempts >= s.recovery.MaxAttempts {
+		if !info.Exhausted {
+			info.Exhausted = true
+			reason := fmt.Sprintf("tmux pane %s disappeared; %d recovery attempts exhausted", oldPane, info.Attempts)
+			s.markAgentError(ctx, agent, reason, models.StateConfidenceHigh, nil)
+			payload.Error = info.LastError
+			payload.Exhausted = true
+			s.publishEvent(ctx, models.EventTypeAgentRecoveryFailed, agent.ID, payload)
+		}
+		return fmt.Errorf("%w: %d of %d used", ErrRecoveryExhausted, info.Attempts, s.recovery.MaxAttempts)
+	}
+
+	now := time.Now().UTC()
+	info.Attempts++
+	info.LastAttemptAt = &now
+	payload.Attempt = info.Attempts
+
+	fail := func(err error) error {
+		info.LastError = err.Error()
+		payload.Error = info.LastError
+		s.markAgentError(ctx, agent, fmt.Sprintf("recovery attempt %d/%d failed: %v", info.Attempts, s.recovery.MaxAttempts, err), models.StateConfidenceLow, nil)
+		s.publishEvent(ctx, models.EventTypeAgentRecoveryFailed, agent.ID, payload)
+		return err
+	}
+
+	ws, err := s.workspaceService.GetWorkspace(ctx, agent.WorkspaceID)
+	if err != nil {
+		return fail(fmt.Errorf("failed to get workspace: %w", err))
+	}
+	workDir := agent.Metadata.WorkingDir
+	if workDir == "" {
+		workDir = ws.RepoPath
+	}
+	payload.Session = ws.TmuxSession
+	payload.WorkDir = workDir
+
+	if err := s.ensureAgentSession(ctx, ws.TmuxSession, ws.RepoPath); err != nil {
+		return fail(err)
+	}
+
+	splitTarget := fmt.Sprintf("%s:%s", ws.TmuxSession, tmux.AgentWindowName)
+	paneID, err := s.tmuxClient.SplitWindow(ctx, splitTarget, false, workDir)
+	if err != nil {
+		s.logger.Debug().Err(err).Str("target", splitTarget).Msg("failed to split agents window, falling back to session")
+		paneID, err = s.tmuxClient.SplitWindow(ctx, ws.TmuxSession, false, workDir)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("failed to create pane: %w", err))
+	}
+	payload.NewPane = paneID
+
+	startCmd := agent.Metadata.StartCommand
+	if startCmd == "" {
+		serverPort := 0
+		if agent.Metadata.OpenCode != nil {
+			serverPort = agent.Metadata.OpenCode.Port
+		}
+		startCmd = s.buildStartCommand(SpawnOptions{
+			WorkspaceID:    agent.WorkspaceID,
+			Type:           agent.Type,
+			AccountID:      agent.AccountID,
+			Environment:    s.heartbeatEnv(agent.ID, agent.Metadata.Environment),
+			WorkingDir:     workDir,
+			ApprovalPolicy: agent.Metadata.ApprovalPolicy,
+		}, serverPort)
+	}
+	payload.Command = startCmd
+	if startCmd != "" {
+		if err := s.tmuxClient.SendKeys(ctx, paneID, startCmd, true, true); err != nil {
+			_ = s.tmuxClient.KillPane(ctx, paneID)
+			return fail(fmt.Errorf("failed to send start command: %w", err))
+		}
+	}
+
+	s.stopRecording(agent.ID)
+	if err := s.paneMap.UnregisterAgent(agent.ID); err != nil && !errors.Is(err, ErrAgentNotFound) {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to unregister dead pane mapping")
+	}
+
+	info.LastError = ""
+	s.resetHeartbeat(agent)
+	agent.TmuxPane = paneID
+	agent.Metadata.StartCommand = startCmd
+	agent.Metadata.WorkingDir = workDir
+	s.markAgentState(ctx, agent, models.AgentStateStarting,
+		fmt.Sprintf("Recovered after tmux pane %s disappeared (attempt %d/%d)", oldPane, info.Attempts, s.recovery.MaxAttempts),
+		models.StateConfidenceHigh, nil)
+
+	if err := s.paneMap.Register(agent.ID, paneID, paneID); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to register pane mapping")
+	}
+	s.tagAgentPane(ctx, agent)
+	s.applyResourceLimits(ctx, agent, nil)
+	s.startRecording(agent)
+
+	s.logger.Info().
+		Str("agent_id", agent.ID).
+		Str("old_pane", oldPane).
+		Str("new_pane", paneID).
+		Int("attempt", info.Attempts).
+		Msg("agent recovered")
+
+	s.publishEvent(ctx, models.EventTypeAgentRecovered, agent.ID, payload)
+	return nil
+}
+
+// ensureAgentSession recreates a workspace tmux session and its agents
+// window if either has disappeared.
+func (s *Service) ensureAgentSession(ctx context.Context, session, workDir string) error {
+	if strings.TrimSpace(session) == "" {
+		return fmt.Errorf("workspace has no tmux session")
+	}
+	exists, err := s.tmuxClient.HasSession(ctx, session)
+	if err != nil {
+		return fmt.Errorf("failed to check tmux session: %w", err)
+	}
+	if !exists {
+		if err := s.tmuxClient.EnsureSession(ctx, session, workDir); err != nil {
+			return fmt.Errorf("failed to recreate tmux session: %w", err)
+		}
+		s.logger.Info().Str("session", session).Msg("recreated workspace tmux session")
+	}
+	if err := s.tmuxClient.EnsureWindow(ctx, session, tmux.AgentWindowName, workDir); err != nil {
+		return fmt.Errorf("failed to recreate agent window: %w", err)
+	}
+	return nil
+}
+
+// ResetRecovery clears an agent's recovery attempt count, e.g. after it
+// completes work or an operator restarts it.
+func (s *Service) ResetRecovery(ctx context.Context, id string) error {
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if agent.Metadata.Recovery == nil {
+		return nil
+	}
+	agent.Metadata.Recovery = nil
+	return s.repo.Update(ctx, agent)
+}
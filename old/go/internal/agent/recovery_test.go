@@ -0,0 +1,219 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/tmux"
+	"github.com/tOgg1/forge/internal/workspace"
+)
+
+// deadSessionExecutor simulates a tmux server whose workspace session was
+// killed: panes cannot be captured and the session only exists once it has
+// been recreated.
+type deadSessionExecutor struct {
+	sessionAlive bool
+	commands     []string
+}
+
+func (e *deadSessionExecutor) Exec(ctx context.Context, cmd string) ([]byte, []byte, error) {
+	e.commands = append(e.commands, cmd)
+	switch {
+	case strings.HasPrefix(cmd, "tmux capture-pane"):
+		return nil, []byte("can't find pane"), errors.New("exit status 1")
+	case strings.HasPrefix(cmd, "tmux has-session"):
+		if !e.sessionAlive {
+			return nil, []byte("can't find session"), errors.New("exit status 1")
+		}
+	case strings.HasPrefix(cmd, "tmux new-session"):
+		e.sessionAlive = true
+	case strings.HasPrefix(cmd, "tmux split-window"):
+		return []byte("%7\n"), nil, nil
+	}
+	return nil, nil, nil
+}
+
+func (e *deadSessionExecutor) hasCommand(prefix, contains string) bool {
+	for _, cmd := range e.commands {
+		if strings.HasPrefix(cmd, prefix) && strings.Contains(cmd, contains) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRecoverDeadAgents_RecreatesSessionAndReplaysCommand(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, IsLocal: true}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	wsRepo := db.NewWorkspaceRepository(database)
+	ws := &models.Workspace{Name: "demo", NodeID: localNode.ID, RepoPath: "/repo/demo", TmuxSession: "forge-demo", Status: models.WorkspaceStatusActive}
+	if err := wsRepo.Create(ctx, ws); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	agentRepo := db.NewAgentRepository(database)
+	agentModel := &models.Agent{
+		WorkspaceID: ws.ID,
+		Type:        models.AgentTypeClaudeCode,
+		TmuxPane:    "%3",
+		State:       models.AgentStateIdle,
+		StateInfo:   models.StateInfo{State: models.AgentStateIdle, Confidence: models.StateConfidenceHigh},
+		Metadata: models.AgentMetadata{
+			StartCommand: "claude --resume",
+			WorkingDir:   "/repo/demo/sub",
+		},
+	}
+	if err := agentRepo.Create(ctx, agentModel); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+
+	exec := &deadSessionExecutor{}
+	tmuxClient := tmux.NewClient(exec)
+	wsService := workspace.NewService(wsRepo, node.NewService(nodeRepo), agentRepo)
+	service := NewService(agentRepo, nil, wsService, nil, tmuxClient, WithRecoveryPolicy(RecoveryPolicy{MaxAttempts: 1}))
+
+	recovered, err := service.RecoverDeadAgents(ctx)
+	if err != nil {
+		t.Fatalf("RecoverDeadAgents failed: %v", err)
+	}
+	if len(recovered) != 1 {
+		t.Fatalf("expected 1 recovered agent, got %d", len(recovered))
+	}
+	if !exec.hasCommand("tmux new-session", "forge-demo") || !exec.hasCommand("tmux new-window", "agents") {
+		t.Fatalf("expected session and agents window to be recreated, got %v", exec.commands)
+	}
+	if !exec.hasCommand("tmux split-window", "/repo/demo/sub") {
+		t.Fatalf("expected pane in the agent's working directory, got %v", exec.commands)
+	}
+	if !exec.hasCommand("tmux send-keys", "claude --resume") {
+		t.Fatalf("expected start command to be replayed, got %v", exec.commands)
+	}
+
+	got, err := service.GetAgent(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.TmuxPane != "%7" || got.State != models.AgentStateStarting {
+		t.Fatalf("expected agent on new pane and starting, got pane=%q state=%q", got.TmuxPane, got.State)
+	}
+	if got.Metadata.Recovery == nil || got.Metadata.Recovery.Attempts != 1 {
+		t.Fatalf("expected one recorded recovery attempt, got %+v", got.Metadata.Recovery)
+	}
+
+	// The new pane dies too; the retry cap stops further recoveries.
+	if _, err := service.RecoverAgent(ctx, agentModel.ID); !errors.Is(err, ErrRecoveryExhausted) {
+		t.Fatalf("expected ErrRecoveryExhausted, got %v", err)
+	}
+	got, _ = service.GetAgent(ctx, agentModel.ID)
+	if got.State != models.AgentStateError || !got.Metadata.Recovery.Exhausted {
+		t.Fatalf("expected exhausted agent in error state, got state=%q recovery=%+v", got.State, got.Metadata.Recovery)
+	}
+	if recoveryCandidate(got) {
+		t.Fatalf("expected exhausted agent to be skipped by later scans")
+	}
+
+	if err := service.ResetRecovery(ctx, agentModel.ID); err != nil {
+		t.Fatalf("ResetRecovery failed: %v", err)
+	}
+	got, _ = service.GetAgent(ctx, agentModel.ID)
+	if got.Metadata.Recovery != nil {
+		t.Fatalf("expected recovery state to be cleared, got %+v", got.Metadata.Recovery)
+	}
+}
+
+// movedPaneExecutor simulates an agent pane that survives under a new pane
+// ID: the recorded pane is gone but a pane tagged with the agent ID exists.
+type movedPaneExecutor struct {
+	agentID  string
+	commands []string
+}
+
+func (e *movedPaneExecutor) Exec(ctx context.Context, cmd string) ([]byte, []byte, error) {
+	e.commands = append(e.commands, cmd)
+	switch {
+	case strings.HasPrefix(cmd, "tmux capture-pane"):
+		return nil, []byte("can't find pane"), errors.New("exit status 1")
+	case strings.HasPrefix(cmd, "tmux list-panes -a"):
+		return []byte("|forge-demo|%1|0|0|/repo/demo|1|bash\n" + e.agentID + "|forge-demo|%8|1|1|/repo/demo|0|claude\n"), nil, nil
+	}
+	return nil, nil, nil
+}
+
+func TestRecoverDeadAgents_RelinksTaggedPane(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, IsLocal: true}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	wsRepo := db.NewWorkspaceRepository(database)
+	ws := &models.Workspace{Name: "demo", NodeID: localNode.ID, RepoPath: "/repo/demo", TmuxSession: "forge-demo", Status: models.WorkspaceStatusActive}
+	if err := wsRepo.Create(ctx, ws); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	agentRepo := db.NewAgentRepository(database)
+	agentModel := &models.Agent{
+		WorkspaceID: ws.ID,
+		Type:        models.AgentTypeClaudeCode,
+		TmuxPane:    "%3",
+		State:       models.AgentStateIdle,
+		StateInfo:   models.StateInfo{State: models.AgentStateIdle, Confidence: models.StateConfidenceHigh},
+	}
+	if err := agentRepo.Create(ctx, agentModel); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+
+	exec := &movedPaneExecutor{agentID: agentModel.ID}
+	wsService := workspace.NewService(wsRepo, node.NewService(nodeRepo), agentRepo)
+	service := NewService(agentRepo, nil, wsService, nil, tmux.NewClient(exec), WithRecoveryPolicy(RecoveryPolicy{MaxAttempts: 1}))
+
+	recovered, err := service.RecoverDeadAgents(ctx)
+	if err != nil {
+		t.Fatalf("RecoverDeadAgents failed: %v", err)
+	}
+	if len(recovered) != 0 {
+		t.Fatalf("expected relink instead of recovery, got %d recovered", len(recovered))
+	}
+	for _, cmd := range exec.commands {
+		if strings.HasPrefix(cmd, "tmux split-window") {
+			t.Fatalf("expected no new pane, got %v", exec.commands)
+		}
+	}
+
+	got, err := service.GetAgent(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.TmuxPane != "%8" || got.Metadata.Recovery != nil {
+		t.Fatalf("expected agent relinked to %%8 without a recovery attempt, got pane=%q recovery=%+v", got.TmuxPane, got.Metadata.Recovery)
+	}
+	if agentID, ok := service.GetPaneMap().AgentForPaneID("%8"); !ok || agentID != agentModel.ID {
+		t.Fatalf("expected pane map to point %%8 at the agent, got %q", agentID)
+	}
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tOgg1/forge/internal/cgroup"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// ResourceLimitsFunc resolves the cgroup limits for an agent's account
+// profile. An empty account ID asks for the defaults.
+type ResourceLimitsFunc func(ctx context.Context, accountID string) models.ResourceLimits
+
+// WithCgroups places spawned agents in per-agent cgroups below manager's
+// root, using limits to look up each profile's CPU and memory caps. Agents
+// whose profile has no limits are not placed in a cgroup.
+func WithCgroups(manager *cgroup.Manager, limits ResourceLimitsFunc) ServiceOption {
+	return func(s *Service) {
+		s.cgroups = manager
+		s.resourceLimits = limits
+	}
+}
+
+// applyResourceLimits moves the agent's pane shell into its cgroup and
+// records the cgroup on the agent. Failures are logged and the agent runs
+// unconfined.
+func (s *Service) applyResourceLimits(ctx context.Context, agent *models.Agent, override *models.ResourceLimits) {
+	if s.cgroups == nil || agent == nil {
+		return
+	}
+
+	var limits models.ResourceLimits
+	if override != nil {
+		limits = *override
+	} else if s.resourceLimits != nil {
+		limits = s.resourceLimits(ctx, agent.AccountID)
+	}
+	if limits.IsZero() {
+		// A restart onto an unlimited profile drops the old group.
+		if agent.Metadata.Resources != nil {
+			s.removeCgroup(agent)
+			agent.Metadata.Resources = nil
+			s.storeResourceUsage(ctx, agent.ID, nil)
+		}
+		return
+	}
+
+	logger := s.logger.With().Str("agent_id", agent.ID).Logger()
+	if s.tmuxClient == nil {
+		logger.Warn().Msg("cannot apply resource limits without a tmux client")
+		return
+	}
+	pid, err := s.tmuxClient.GetPanePID(ctx, agent.TmuxPane)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to resolve pane pid, running without resource limits")
+		return
+	}
+	path, err := s.cgroups.Create(agent.ID, limits)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to create cgroup, running without resource limits")
+		return
+	}
+	if err := s.cgroups.AddProcess(agent.ID, pid); err != nil {
+		logger.Warn().Err(err).Msg("failed to move pane into cgroup, running without resource limits")
+		_ = s.cgroups.Remove(agent.ID)
+		return
+	}
+
+	agent.Metadata.Resources = &models.ResourceUsage{
+		CgroupPath: path,
+		Limits:     limits,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	s.storeResourceUsage(ctx, agent.ID, agent.Metadata.Resources)
+	logger.Debug().
+		Str("cgroup", path).
+		Float64("cpu_cores", limits.CPUCores).
+		Int64("memory_bytes", limits.MemoryBytes).
+		Msg("applied resource limits")
+}
+
+// RefreshResourceUsage reads the agent's cgroup counters and stores them on
+// the agent record, where the TUI and scheduler pick them up. It returns nil
+// usage for agents that are not in a cgroup.
+func (s *Service) RefreshResourceUsage(ctx context.Context, id string) (*models.ResourceUsage, error) {
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.cgroups == nil || agent.Metadata.Resources == nil {
+		return nil, nil
+	}
+
+	usage, err := s.cgroups.Usage(agent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("read cgroup usage: %w", err)
+	}
+	if err := s.repo.UpdateResourceUsage(ctx, agent.ID, usage); err != nil {
+		return nil, fmt.Errorf("store resource usage: %w", err)
+	}
+	return usage, nil
+}
+
+func (s *Service) storeResourceUsage(ctx context.Context, id string, usage *models.ResourceUsage) {
+	if err := s.repo.UpdateResourceUsage(ctx, id, usage); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", id).Msg("failed to store resource usage")
+	}
+}
+
+func (s *Service) removeCgroup(agent *models.Agent) {
+	if s.cgroups == nil || agent == nil || agent.Metadata.Resources == nil {
+		return
+	}
+	if err := s.cgroups.Remove(agent.ID); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to remove cgroup")
+	}
+}
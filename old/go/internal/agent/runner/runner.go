@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/creack/pty"
 	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
 )
 
 var (
@@ -70,6 +72,7 @@ type Runner struct {
 	BusyRegex   *regexp.Regexp
 
 	HeartbeatInterval time.Duration
+	HeartbeatFile     string
 	TailLines         int
 	TailBytes         int
 
@@ -482,10 +485,40 @@ func (r *Runner) heartbeatLoop(ctx context.Context) {
 				IdleFor:      idleFor.String(),
 				Tail:         tail,
 			})
+			if r.HeartbeatFile != "" {
+				status := "working"
+				if r.isReady() {
+					status = "idle"
+				}
+				if err := WriteHeartbeat(r.HeartbeatFile, models.AgentHeartbeat{Timestamp: now, Status: status}); err != nil {
+					logger := logging.Component("agent-runner")
+					logger.Debug().Err(err).Str("path", r.HeartbeatFile).Msg("failed to write heartbeat file")
+				}
+			}
 		}
 	}
 }
 
+// WriteHeartbeat records a heartbeat in path. The file is replaced
+// atomically so readers never see a partial record.
+func WriteHeartbeat(path string, beat models.AgentHeartbeat) error {
+	if beat.Timestamp.IsZero() {
+		beat.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(beat)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
 func (r *Runner) controlLoop(ctx context.Context) {
 	logger := logging.Component("agent-runner")
 	scanner := bufio.NewScanner(r.ControlReader)
@@ -3,6 +3,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -13,11 +14,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/tOgg1/forge/internal/account"
 	"github.com/tOgg1/forge/internal/adapters"
+	"github.com/tOgg1/forge/internal/cgroup"
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/events"
 	"github.com/tOgg1/forge/internal/logging"
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/tmux"
+	"github.com/tOgg1/forge/internal/tracing"
 	"github.com/tOgg1/forge/internal/workspace"
 )
 
@@ -31,13 +34,14 @@ var (
 	ErrTerminateFailed      = errors.New("failed to terminate agent")
 	ErrSendFailed           = errors.New("failed to send message to agent")
 	ErrAgentNotIdle         = errors.New("agent is not idle")
+	ErrNodeConstraints      = errors.New("node does not satisfy constraints")
 )
 
 // Service manages agent lifecycle operations.
 type Service struct {
 	repo             *db.AgentRepository
 	queueRepo        *db.QueueRepository
-	portRepo         *db.PortRepository
+	ports            *PortAllocator
 	workspaceService *workspace.Service
 	accountService   *account.Service
 	tmuxClient       *tmux.Client
@@ -48,6 +52,13 @@ type Service struct {
 	publisher        events.Publisher
 	logger           zerolog.Logger
 	eventWatcher     *adapters.OpenCodeEventWatcher
+	adapters         *adapters.Registry
+	cgroups          *cgroup.Manager
+	resourceLimits   ResourceLimitsFunc
+	crashLoop        CrashLoopPolicy
+	recorder         *Recorder
+	recovery         RecoveryPolicy
+	heartbeat        HeartbeatPolicy
 }
 
 // ServiceOption configures an AgentService.
@@ -81,10 +92,11 @@ func WithArchiveAfter(duration time.Duration) ServiceOption {
 	}
 }
 
-// WithPortRepository configures a port repository for OpenCode port allocation.
+// WithPortRepository configures a port repository for OpenCode port
+// allocation, using a PortAllocator with default leases.
 func WithPortRepository(repo *db.PortRepository) ServiceOption {
 	return func(s *Service) {
-		s.portRepo = repo
+		s.ports = NewPortAllocator(repo, DefaultPortLeaseTTL, true)
 	}
 }
 
@@ -95,6 +107,16 @@ func WithEventWatcher(watcher *adapters.OpenCodeEventWatcher) ServiceOption {
 	}
 }
 
+// WithAdapterRegistry sets the registry harness adapters are resolved from
+// (default: adapters.DefaultRegistry).
+func WithAdapterRegistry(registry *adapters.Registry) ServiceOption {
+	return func(s *Service) {
+		if registry != nil {
+			s.adapters = registry
+		}
+	}
+}
+
 // NewService creates a new AgentService.
 func NewService(
 	repo *db.AgentRepository,
@@ -113,6 +135,10 @@ func NewService(
 		paneMap:          NewPaneMap(),
 		logger:           logging.Component("agent"),
 		archiveAfter:     defaultArchiveAfter,
+		crashLoop:        DefaultCrashLoopPolicy(),
+		recovery:         DefaultRecoveryPolicy(),
+		heartbeat:        DefaultHeartbeatPolicy(),
+		adapters:         adapters.DefaultRegistry,
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -151,10 +177,25 @@ type SpawnOptions struct {
 	// ReadyPollInterval controls how often to poll for readiness.
 	// If zero, defaults to 250 milliseconds.
 	ReadyPollInterval time.Duration
+
+	// ResourceLimits overrides the profile's cgroup limits when set.
+	// Ignored unless the service has a cgroup manager.
+	ResourceLimits *models.ResourceLimits
+
+	// NodeConstraints are label requirements the workspace's node must
+	// satisfy. They are kept on the agent so the scheduler can re-check
+	// them before each dispatch.
+	NodeConstraints []models.NodeConstraint
 }
 
 // SpawnAgent creates a new agent in a workspace.
-func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Agent, error) {
+func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (_ *models.Agent, err error) {
+	ctx, span := tracing.Start(ctx, "agent.spawn",
+		tracing.String("workspace_id", opts.WorkspaceID),
+		tracing.String("agent_type", string(opts.Type)),
+	)
+	defer func() { span.RecordError(err); span.End() }()
+
 	s.logger.Debug().
 		Str("workspace_id", opts.WorkspaceID).
 		Str("type", string(opts.Type)).
@@ -169,6 +210,21 @@ func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Ag
 		return nil, fmt.Errorf("failed to get workspace: %w", err)
 	}
 
+	if len(opts.NodeConstraints) > 0 {
+		nodeObj, err := s.workspaceService.GetWorkspaceNode(ctx, ws.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workspace node: %w", err)
+		}
+		if !nodeObj.SatisfiesConstraints(opts.NodeConstraints) {
+			return nil, fmt.Errorf("%w: node %s (labels %q) does not match %s",
+				ErrNodeConstraints, nodeObj.Name, models.FormatNodeLabels(nodeObj.Labels), models.FormatNodeConstraints(opts.NodeConstraints))
+		}
+	}
+
+	if err := s.workspaceService.CheckAgentQuota(ctx, ws); err != nil {
+		return nil, err
+	}
+
 	// Determine working directory
 	workDir := opts.WorkingDir
 	if workDir == "" {
@@ -223,15 +279,18 @@ func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Ag
 			DetectedAt: time.Now().UTC(),
 		},
 		Metadata: models.AgentMetadata{
-			Environment:    opts.Environment,
-			ApprovalPolicy: opts.ApprovalPolicy,
+			Environment:     opts.Environment,
+			WorkingDir:      workDir,
+			ApprovalPolicy:  opts.ApprovalPolicy,
+			NodeConstraints: opts.NodeConstraints,
 		},
 	}
 
-	// Allocate port for OpenCode agents
+	// Allocate a port for adapters that serve an API (OpenCode)
+	adapter := s.adapterFor(opts.Type)
 	var allocatedPort int
-	if opts.Type == models.AgentTypeOpenCode && s.portRepo != nil {
-		port, err := s.portRepo.Allocate(ctx, ws.NodeID, "", "opencode-agent-spawn")
+	if adapters.RequiresServerPort(adapter) && s.ports != nil {
+		port, err := s.ports.Reserve(ctx, ws.NodeID, s.isLocalWorkspace(ctx, ws.ID), adapter.Name()+"-agent-spawn")
 		if err != nil {
 			_ = s.tmuxClient.KillPane(ctx, paneTarget)
 			return nil, fmt.Errorf("%w: failed to allocate port: %v", ErrSpawnFailed, err)
@@ -244,15 +303,16 @@ func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Ag
 		s.logger.Debug().
 			Str("workspace_id", opts.WorkspaceID).
 			Int("port", port).
-			Msg("allocated port for OpenCode agent")
+			Str("adapter", adapter.Name()).
+			Msg("allocated server port for agent")
 	}
 
 	// Persist agent to database
 	if err := s.repo.Create(ctx, agent); err != nil {
 		// Clean up pane and port on failure
 		_ = s.tmuxClient.KillPane(ctx, paneTarget)
-		if allocatedPort > 0 && s.portRepo != nil {
-			_ = s.portRepo.Release(ctx, ws.NodeID, allocatedPort)
+		if allocatedPort > 0 && s.ports != nil {
+			_ = s.ports.Release(ctx, ws.NodeID, allocatedPort)
 		}
 		if errors.Is(err, db.ErrAgentAlreadyExists) {
 			return nil, ErrAgentAlreadyExists
@@ -260,13 +320,26 @@ func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Ag
 		return nil, fmt.Errorf("failed to create agent: %w", err)
 	}
 
+	// Bind the reserved port so it outlives its spawn lease
+	if allocatedPort > 0 && s.ports != nil {
+		if err := s.ports.Bind(ctx, ws.NodeID, allocatedPort, agent.ID); err != nil {
+			s.logger.Warn().Err(err).Str("agent_id", agent.ID).Int("port", allocatedPort).Msg("failed to bind port allocation")
+		}
+	}
+
 	// Register pane mapping
 	if err := s.paneMap.Register(agent.ID, paneID, paneTarget); err != nil {
 		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to register pane mapping")
 	}
+	s.tagAgentPane(ctx, agent)
+
+	// Confine the pane shell before the agent CLI starts so it inherits the cgroup.
+	s.applyResourceLimits(ctx, agent, opts.ResourceLimits)
+	s.startRecording(agent)
 
 	// Start the agent CLI in the pane
-	startCmd := s.buildStartCommand(opts)
+	opts.Environment = s.heartbeatEnv(agent.ID, opts.Environment)
+	startCmd := s.buildStartCommand(opts, allocatedPort)
 	if startCmd != "" {
 		if err := s.tmuxClient.SendKeys(ctx, paneTarget, startCmd, true, true); err != nil {
 			spawnErr := fmt.Errorf("failed to send start command: %w", err)
@@ -280,9 +353,7 @@ func (s *Service) SpawnAgent(ctx context.Context, opts SpawnOptions) (*models.Ag
 
 	// Send initial prompt if provided
 	if opts.InitialPrompt != "" {
-		// Wait a bit for the agent to start
-		time.Sleep(500 * time.Millisecond)
-		if err := s.tmuxClient.SendKeys(ctx, paneTarget, opts.InitialPrompt, true, true); err != nil {
+		if err := s.injectInitialPrompt(ctx, adapter, paneTarget, opts.InitialPrompt); err != nil {
 			s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to send initial prompt")
 		}
 	}
@@ -319,10 +390,7 @@ func (s *Service) waitForReady(ctx context.Context, agent *models.Agent, opts Sp
 		return fmt.Errorf("agent has no tmux pane")
 	}
 
-	adapter := adapters.GetByAgentType(agent.Type)
-	if adapter == nil {
-		adapter = adapters.GenericFallbackAdapter()
-	}
+	adapter := s.adapterFor(agent.Type)
 
 	timeout := opts.ReadyTimeout
 	if timeout <= 0 {
@@ -425,6 +493,8 @@ func (s *Service) cleanupSpawnFailure(ctx context.Context, agent *models.Agent)
 			s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to kill pane after spawn failure")
 		}
 	}
+	s.stopRecording(agent.ID)
+	s.removeCgroup(agent)
 
 	if s.queueRepo != nil {
 		if _, err := s.queueRepo.Clear(ctx, agent.ID); err != nil {
@@ -433,8 +503,8 @@ func (s *Service) cleanupSpawnFailure(ctx context.Context, agent *models.Agent)
 	}
 
 	// Release allocated port for OpenCode agents
-	if s.portRepo != nil && agent.Metadata.OpenCode != nil && agent.Metadata.OpenCode.Port > 0 {
-		if _, err := s.portRepo.ReleaseByAgent(ctx, agent.ID); err != nil {
+	if s.ports != nil && agent.Metadata.OpenCode != nil && agent.Metadata.OpenCode.Port > 0 {
+		if _, err := s.ports.ReleaseAgent(ctx, agent.ID); err != nil {
 			s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to release port after spawn failure")
 		}
 	}
@@ -690,7 +760,10 @@ func (s *Service) GetAgentState(ctx context.Context, id string) (*AgentStateResu
 }
 
 // InterruptAgent sends an interrupt signal to an agent.
-func (s *Service) InterruptAgent(ctx context.Context, id string) error {
+func (s *Service) InterruptAgent(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "agent.interrupt", tracing.String("agent_id", id))
+	defer func() { span.RecordError(err); span.End() }()
+
 	s.logger.Debug().Str("agent_id", id).Msg("interrupting agent")
 
 	agent, err := s.GetAgent(ctx, id)
@@ -727,7 +800,10 @@ func (s *Service) InterruptAgent(ctx context.Context, id string) error {
 }
 
 // RestartAgent restarts an agent by terminating and respawning it.
-func (s *Service) RestartAgent(ctx context.Context, id string) (*models.Agent, error) {
+func (s *Service) RestartAgent(ctx context.Context, id string) (_ *models.Agent, err error) {
+	ctx, span := tracing.Start(ctx, "agent.restart", tracing.String("agent_id", id))
+	defer func() { span.RecordError(err); span.End() }()
+
 	s.logger.Debug().Str("agent_id", id).Msg("restarting agent")
 
 	// Get current agent
@@ -735,6 +811,9 @@ func (s *Service) RestartAgent(ctx context.Context, id string) (*models.Agent, e
 	if err != nil {
 		return nil, err
 	}
+	if err := checkRestartBackoff(agent, time.Now().UTC()); err != nil {
+		return nil, err
+	}
 
 	// Remember spawn options
 	opts := SpawnOptions{
@@ -756,6 +835,14 @@ func (s *Service) RestartAgent(ctx context.Context, id string) (*models.Agent, e
 		return nil, fmt.Errorf("failed to respawn agent: %w", err)
 	}
 
+	// Failures keep counting across restarts until the agent does real work.
+	if agent.Metadata.CrashLoop != nil {
+		newAgent.Metadata.CrashLoop = agent.Metadata.CrashLoop
+		if err := s.repo.Update(ctx, newAgent); err != nil {
+			s.logger.Warn().Err(err).Str("agent_id", newAgent.ID).Msg("failed to carry crash-loop state to restarted agent")
+		}
+	}
+
 	s.logger.Info().
 		Str("old_agent_id", id).
 		Str("new_agent_id", newAgent.ID).
@@ -768,7 +855,13 @@ func (s *Service) RestartAgent(ctx context.Context, id string) (*models.Agent, e
 }
 
 // RestartAgentWithAccount restarts an agent using a new account without clearing the queue.
-func (s *Service) RestartAgentWithAccount(ctx context.Context, id, accountID string) (*models.Agent, error) {
+func (s *Service) RestartAgentWithAccount(ctx context.Context, id, accountID string) (_ *models.Agent, err error) {
+	ctx, span := tracing.Start(ctx, "agent.restart",
+		tracing.String("agent_id", id),
+		tracing.String("account_id", accountID),
+	)
+	defer func() { span.RecordError(err); span.End() }()
+
 	s.logger.Debug().
 		Str("agent_id", id).
 		Str("account_id", accountID).
@@ -845,6 +938,9 @@ func (s *Service) RestartAgentWithAccount(ctx context.Context, id, accountID str
 		DetectedAt: now,
 	}
 	agent.Metadata.Environment = env
+	agent.Metadata.WorkingDir = workDir
+	agent.Metadata.Recovery = nil
+	s.resetHeartbeat(agent)
 	agent.LastActivity = &now
 
 	if err := s.repo.Update(ctx, agent); err != nil {
@@ -855,17 +951,25 @@ func (s *Service) RestartAgentWithAccount(ctx context.Context, id, accountID str
 	if err := s.paneMap.Register(agent.ID, paneID, paneTarget); err != nil {
 		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to register pane mapping")
 	}
+	s.tagAgentPane(ctx, agent)
+
+	s.applyResourceLimits(ctx, agent, nil)
+	s.startRecording(agent)
 
 	opts := SpawnOptions{
 		WorkspaceID:    agent.WorkspaceID,
 		Type:           agent.Type,
 		AccountID:      accountID,
-		Environment:    env,
+		Environment:    s.heartbeatEnv(agent.ID, env),
 		WorkingDir:     workDir,
 		ApprovalPolicy: agent.Metadata.ApprovalPolicy,
 	}
 
-	startCmd := s.buildStartCommand(opts)
+	serverPort := 0
+	if agent.Metadata.OpenCode != nil {
+		serverPort = agent.Metadata.OpenCode.Port
+	}
+	startCmd := s.buildStartCommand(opts, serverPort)
 	if startCmd != "" {
 		if err := s.tmuxClient.SendKeys(ctx, paneTarget, startCmd, true, true); err != nil {
 			spawnErr := fmt.Errorf("failed to send start command: %w", err)
@@ -898,7 +1002,10 @@ func (s *Service) RestartAgentWithAccount(ctx context.Context, id, accountID str
 }
 
 // TerminateAgent stops and removes an agent.
-func (s *Service) TerminateAgent(ctx context.Context, id string) error {
+func (s *Service) TerminateAgent(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "agent.terminate", tracing.String("agent_id", id))
+	defer func() { span.RecordError(err); span.End() }()
+
 	s.logger.Debug().Str("agent_id", id).Msg("terminating agent")
 
 	// Stop SSE event watcher first
@@ -937,7 +1044,9 @@ func (s *Service) TerminateAgent(ctx context.Context, id string) error {
 		}
 	}
 
+	s.stopRecording(id)
 	s.archiveAgentLogs(ctx, agent, transcript, transcriptAt, transcriptErr)
+	s.removeCgroup(agent)
 
 	// Clear the agent's queue
 	if s.queueRepo != nil {
@@ -955,8 +1064,8 @@ func (s *Service) TerminateAgent(ctx context.Context, id string) error {
 	}
 
 	// Release allocated port for OpenCode agents
-	if s.portRepo != nil && agent.Metadata.OpenCode != nil && agent.Metadata.OpenCode.Port > 0 {
-		if released, err := s.portRepo.ReleaseByAgent(ctx, id); err != nil {
+	if s.ports != nil && agent.Metadata.OpenCode != nil && agent.Metadata.OpenCode.Port > 0 {
+		if released, err := s.ports.ReleaseAgent(ctx, id); err != nil {
 			s.logger.Warn().Err(err).Str("agent_id", id).Msg("failed to release port on termination")
 		} else if released > 0 {
 			s.logger.Debug().Str("agent_id", id).Int("released", released).Msg("released port allocation")
@@ -1044,6 +1153,7 @@ func (s *Service) ResumeAgent(ctx context.Context, id string) error {
 		DetectedAt: now,
 	}
 	agent.PausedUntil = nil
+	agent.Metadata.Takeover = nil
 	agent.LastActivity = &now
 
 	if err := s.repo.Update(ctx, agent); err != nil {
@@ -1056,22 +1166,26 @@ func (s *Service) ResumeAgent(ctx context.Context, id string) error {
 	return nil
 }
 
-// buildStartCommand builds the command to start an agent CLI.
-func (s *Service) buildStartCommand(opts SpawnOptions) string {
-	adapter := adapters.GetByAgentType(opts.Type)
-	if adapter == nil {
-		adapter = adapters.GenericFallbackAdapter()
-	}
-	if adapter == nil {
-		return ""
+// adapterFor resolves the harness adapter for an agent type.
+func (s *Service) adapterFor(agentType models.AgentType) adapters.AgentAdapter {
+	registry := s.adapters
+	if registry == nil {
+		registry = adapters.DefaultRegistry
 	}
+	return registry.Resolve(agentType)
+}
 
+// buildStartCommand builds the command to start an agent CLI. serverPort is
+// the port reserved for adapters that serve an API, or zero.
+func (s *Service) buildStartCommand(opts SpawnOptions, serverPort int) string {
+	adapter := s.adapterFor(opts.Type)
 	cmd, args := adapter.SpawnCommand(adapters.SpawnOptions{
 		AgentType:      opts.Type,
 		AccountID:      opts.AccountID,
 		InitialPrompt:  opts.InitialPrompt,
 		Environment:    opts.Environment,
 		ApprovalPolicy: opts.ApprovalPolicy,
+		ServerPort:     serverPort,
 	})
 	if cmd == "" {
 		return ""
@@ -1081,6 +1195,27 @@ func (s *Service) buildStartCommand(opts SpawnOptions) string {
 	return envPrefix + joinCommand(cmd, args)
 }
 
+// injectInitialPrompt delivers the initial prompt through the adapter's
+// PromptInjector, or types it into the pane once the CLI had a moment to
+// start.
+func (s *Service) injectInitialPrompt(ctx context.Context, adapter adapters.AgentAdapter, pane, prompt string) error {
+	if injector, ok := adapter.(adapters.PromptInjector); ok {
+		return injector.InjectPrompt(paneKeySender{ctx: ctx, client: s.tmuxClient}, pane, prompt)
+	}
+	time.Sleep(500 * time.Millisecond)
+	return s.tmuxClient.SendKeys(ctx, pane, prompt, true, true)
+}
+
+// paneKeySender adapts the tmux client to the adapters.TmuxClient interface.
+type paneKeySender struct {
+	ctx    context.Context
+	client *tmux.Client
+}
+
+func (p paneKeySender) SendKeys(target, keys string, literal bool) error {
+	return p.client.SendKeys(p.ctx, target, keys, literal, false)
+}
+
 func formatEnvPrefix(env map[string]string) string {
 	if len(env) == 0 {
 		return ""
@@ -1153,7 +1288,13 @@ const (
 // SendMessage sends a message to an agent.
 // By default, it verifies the agent is in an idle state before sending.
 // The message is sent via the adapter for proper formatting.
-func (s *Service) SendMessage(ctx context.Context, id, message string, opts *SendMessageOptions) error {
+func (s *Service) SendMessage(ctx context.Context, id, message string, opts *SendMessageOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "agent.send_message",
+		tracing.String("agent_id", id),
+		tracing.Int("message_bytes", len(message)),
+	)
+	defer func() { span.RecordError(err); span.End() }()
+
 	if opts == nil {
 		opts = &SendMessageOptions{}
 	}
@@ -1263,7 +1404,7 @@ func (s *Service) SendMessage(ctx context.Context, id, message string, opts *Sen
 			Reason:     fmt.Sprintf("Send failed after %d attempts: %v", maxAttempts, lastErr),
 			DetectedAt: time.Now().UTC(),
 		}
-		_ = s.repo.Update(ctx, agent)
+		s.recordFailure(ctx, agent, agent.StateInfo.Reason)
 		return fmt.Errorf("%w: %v", ErrSendFailed, lastErr)
 	}
 
@@ -1346,6 +1487,14 @@ func (s *Service) publishEvent(ctx context.Context, eventType models.EventType,
 		EntityType: models.EntityTypeAgent,
 		EntityID:   agentID,
 	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("event_type", string(eventType)).Msg("failed to encode event payload")
+		} else {
+			event.Payload = data
+		}
+	}
 
 	s.publisher.Publish(ctx, event)
 }
@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultTakeoverLease is how long a takeover pauses dispatch before it must
+// be renewed.
+const DefaultTakeoverLease = 2 * time.Minute
+
+var (
+	// ErrControlHeld is returned when another operator already has control
+	// of the agent.
+	ErrControlHeld = errors.New("agent is controlled by another operator")
+	// ErrAgentStopped is returned when taking control of a stopped agent.
+	ErrAgentStopped = errors.New("agent is stopped")
+)
+
+// TakeControl pauses dispatch to an agent while operator types in its pane.
+// The pause lasts lease and is renewed by calling TakeControl again; it ends
+// with ReleaseControl. If the caller goes away without releasing, the
+// scheduler auto-resumes the agent once the lease lapses, as with any pause.
+// A pause already in effect is remembered and restored on release.
+func (s *Service) TakeControl(ctx context.Context, id, operator string, lease time.Duration) (*models.Agent, error) {
+	if lease <= 0 {
+		lease = DefaultTakeoverLease
+	}
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if agent.State == models.AgentStateStopped {
+		return nil, ErrAgentStopped
+	}
+
+	now := time.Now().UTC()
+	until := now.Add(lease)
+	prior := agent.Metadata.Takeover
+	if agent.State != models.AgentStatePaused {
+		prior = nil
+	}
+	renewing := prior != nil && prior.Operator == operator
+	if prior != nil && !renewing && agent.PausedUntil != nil && now.Before(*agent.PausedUntil) {
+		return nil, fmt.Errorf("%w (%s)", ErrControlHeld, prior.Operator)
+	}
+	takeover := prior
+	if !renewing {
+		takeover = &models.TakeoverInfo{Operator: operator, Since: now}
+		switch {
+		case prior != nil:
+			takeover.PrevPausedUntil = prior.PrevPausedUntil
+		case agent.State == models.AgentStatePaused && agent.PausedUntil != nil:
+			prev := *agent.PausedUntil
+			takeover.PrevPausedUntil = &prev
+		}
+	}
+	if takeover.PrevPausedUntil != nil && takeover.PrevPausedUntil.After(until) {
+		until = *takeover.PrevPausedUntil
+	}
+
+	agent.State = models.AgentStatePaused
+	agent.StateInfo = models.StateInfo{
+		State:      models.AgentStatePaused,
+		Confidence: models.StateConfidenceHigh,
+		Reason:     fmt.Sprintf("Controlled by %s", operator),
+		DetectedAt: now,
+	}
+	agent.PausedUntil = &until
+	agent.Metadata.Takeover = takeover
+	if err := s.repo.Update(ctx, agent); err != nil {
+		return nil, err
+	}
+
+	if !renewing {
+		s.publishEvent(ctx, models.EventTypeAgentControlTaken, id, models.AgentControlPayload{Operator: operator, Until: &until})
+	}
+	return agent, nil
+}
+
+// ReleaseControl ends a takeover. The agent resumes unless it was already
+// paused when control was taken and that pause has not lapsed.
+func (s *Service) ReleaseControl(ctx context.Context, id string) error {
+	agent, err := s.GetAgent(ctx, id)
+	if err != nil {
+		return err
+	}
+	takeover := agent.Metadata.Takeover
+	if takeover == nil {
+		return nil
+	}
+	if agent.State != models.AgentStatePaused {
+		agent.Metadata.Takeover = nil
+		return s.repo.Update(ctx, agent)
+	}
+
+	now := time.Now().UTC()
+	payload := models.AgentControlPayload{Operator: takeover.Operator}
+	if prev := takeover.PrevPausedUntil; prev != nil && now.Before(*prev) {
+		agent.Metadata.Takeover = nil
+		agent.PausedUntil = prev
+		agent.StateInfo = models.StateInfo{
+			State:      models.AgentStatePaused,
+			Confidence: models.StateConfidenceHigh,
+			Reason:     fmt.Sprintf("Paused until %s", prev.Format(time.RFC3339)),
+			DetectedAt: now,
+		}
+		if err := s.repo.Update(ctx, agent); err != nil {
+			return err
+		}
+		payload.Until = prev
+		s.publishEvent(ctx, models.EventTypeAgentControlReleased, id, payload)
+		return nil
+	}
+
+	if err := s.ResumeAgent(ctx, id); err != nil {
+		return err
+	}
+	s.publishEvent(ctx, models.EventTypeAgentControlReleased, id, payload)
+	return nil
+}
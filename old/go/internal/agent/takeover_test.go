@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func newTakeoverService(t *testing.T, agentModel *models.Agent) (*Service, *db.AgentRepository, *[]*models.Event) {
+	t.Helper()
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, IsLocal: true}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	ws := &models.Workspace{Name: "demo", NodeID: localNode.ID, RepoPath: "/repo/demo", TmuxSession: "forge-demo", Status: models.WorkspaceStatusActive}
+	if err := db.NewWorkspaceRepository(database).Create(ctx, ws); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	agentRepo := db.NewAgentRepository(database)
+	agentModel.WorkspaceID = ws.ID
+	agentModel.Type = models.AgentTypeClaudeCode
+	agentModel.TmuxPane = "%3"
+	agentModel.StateInfo = models.StateInfo{State: agentModel.State, Confidence: models.StateConfidenceHigh}
+	if err := agentRepo.Create(ctx, agentModel); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+
+	publisher := events.NewInMemoryPublisher()
+	var published []*models.Event
+	if err := publisher.Subscribe("test", events.Filter{}, func(event *models.Event) {
+		published = append(published, event)
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	return NewService(agentRepo, nil, nil, nil, nil, WithPublisher(publisher)), agentRepo, &published
+}
+
+func TestTakeControlPausesAndReleaseResumes(t *testing.T) {
+	ctx := context.Background()
+	agentModel := &models.Agent{State: models.AgentStateIdle}
+	service, agentRepo, published := newTakeoverService(t, agentModel)
+
+	got, err := service.TakeControl(ctx, agentModel.ID, "ana@box", time.Minute)
+	if err != nil {
+		t.Fatalf("TakeControl: %v", err)
+	}
+	if got.State != models.AgentStatePaused || got.PausedUntil == nil || got.Metadata.Takeover == nil || got.Metadata.Takeover.Operator != "ana@box" {
+		t.Fatalf("expected paused takeover, got %+v", got)
+	}
+	firstUntil := *got.PausedUntil
+
+	if _, err := service.TakeControl(ctx, agentModel.ID, "bo@box", time.Minute); !errors.Is(err, ErrControlHeld) {
+		t.Fatalf("expected ErrControlHeld for a second operator, got %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	got, err = service.TakeControl(ctx, agentModel.ID, "ana@box", time.Minute)
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if !got.PausedUntil.After(firstUntil) {
+		t.Fatalf("expected renewal to extend the lease")
+	}
+
+	if err := service.ReleaseControl(ctx, agentModel.ID); err != nil {
+		t.Fatalf("ReleaseControl: %v", err)
+	}
+	stored, err := agentRepo.Get(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if stored.State != models.AgentStateIdle || stored.PausedUntil != nil || stored.Metadata.Takeover != nil {
+		t.Fatalf("expected resumed agent, got %+v", stored)
+	}
+
+	var types []models.EventType
+	for _, event := range *published {
+		types = append(types, event.Type)
+	}
+	want := []models.EventType{models.EventTypeAgentControlTaken, models.EventTypeAgentResumed, models.EventTypeAgentControlReleased}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, types)
+		}
+	}
+}
+
+func TestReleaseControlRestoresEarlierPause(t *testing.T) {
+	ctx := context.Background()
+	pausedUntil := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	agentModel := &models.Agent{State: models.AgentStatePaused, PausedUntil: &pausedUntil}
+	service, agentRepo, _ := newTakeoverService(t, agentModel)
+
+	got, err := service.TakeControl(ctx, agentModel.ID, "ana@box", time.Minute)
+	if err != nil {
+		t.Fatalf("TakeControl: %v", err)
+	}
+	if !got.PausedUntil.Equal(pausedUntil) {
+		t.Fatalf("expected the longer existing pause to be kept, got %s", got.PausedUntil)
+	}
+	if err := service.ReleaseControl(ctx, agentModel.ID); err != nil {
+		t.Fatalf("ReleaseControl: %v", err)
+	}
+	stored, err := agentRepo.Get(ctx, agentModel.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if stored.State != models.AgentStatePaused || stored.PausedUntil == nil || !stored.PausedUntil.Equal(pausedUntil) || stored.Metadata.Takeover != nil {
+		t.Fatalf("expected original pause restored, got %+v", stored)
+	}
+}
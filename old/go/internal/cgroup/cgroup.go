@@ -0,0 +1,275 @@
+// Package cgroup places agent processes in cgroup v2 groups with CPU and
+// memory limits and reads back their usage.
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultRoot is the parent cgroup for agent groups. It must be delegated to
+// the user running forge (for example via systemd Delegate=yes).
+const DefaultRoot = "/sys/fs/cgroup/forge"
+
+// cpuPeriodUsec is the cpu.max period used when converting cores to quota.
+const cpuPeriodUsec = 100000
+
+var (
+	// ErrUnsupported is returned when cgroup v2 is not available.
+	ErrUnsupported = errors.New("cgroup v2 is not available")
+	// ErrInvalidName is returned for group names that are not a single path element.
+	ErrInvalidName = errors.New("invalid cgroup name")
+)
+
+// Manager creates and inspects groups below a root cgroup directory.
+type Manager struct {
+	root string
+}
+
+// NewManager returns a manager rooted at root, or DefaultRoot when empty.
+func NewManager(root string) *Manager {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		root = DefaultRoot
+	}
+	return &Manager{root: filepath.Clean(root)}
+}
+
+// Root returns the parent cgroup directory.
+func (m *Manager) Root() string {
+	return m.root
+}
+
+// Path returns the directory for a named group.
+func (m *Manager) Path(name string) string {
+	return filepath.Join(m.root, name)
+}
+
+// Available reports whether the platform supports cgroup v2 and the root's
+// parent is a cgroup v2 directory.
+func (m *Manager) Available() bool {
+	if !supported() {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(m.root), "cgroup.controllers"))
+	return err == nil
+}
+
+// Create makes the named group and applies limits. Zero limits are written
+// as "max" so an existing group is reset. It returns the group path.
+func (m *Manager) Create(name string, limits models.ResourceLimits) (string, error) {
+	if !supported() {
+		return "", ErrUnsupported
+	}
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(m.root, 0o755); err != nil {
+		return "", fmt.Errorf("create cgroup root: %w", err)
+	}
+	// Children only get the cpu and memory interface files once the parent
+	// delegates those controllers.
+	if err := writeFile(filepath.Join(m.root, "cgroup.subtree_control"), "+cpu +memory"); err != nil {
+		return "", fmt.Errorf("enable cgroup controllers: %w", err)
+	}
+
+	path := m.Path(name)
+	if err := os.Mkdir(path, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("create cgroup: %w", err)
+	}
+	if err := writeFile(filepath.Join(path, "cpu.max"), FormatCPUMax(limits.CPUCores)); err != nil {
+		return "", fmt.Errorf("set cpu.max: %w", err)
+	}
+	if err := writeFile(filepath.Join(path, "memory.max"), formatMemoryMax(limits.MemoryBytes)); err != nil {
+		return "", fmt.Errorf("set memory.max: %w", err)
+	}
+	return path, nil
+}
+
+// AddProcess moves pid into the named group. Processes it starts later
+// inherit the group.
+func (m *Manager) AddProcess(name string, pid int) error {
+	if !supported() {
+		return ErrUnsupported
+	}
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if pid <= 0 {
+		return fmt.Errorf("invalid pid %d", pid)
+	}
+	if err := writeFile(filepath.Join(m.Path(name), "cgroup.procs"), strconv.Itoa(pid)); err != nil {
+		return fmt.Errorf("add pid %d to cgroup: %w", pid, err)
+	}
+	return nil
+}
+
+// Usage reads the named group's limits and current consumption. Interface
+// files the kernel does not provide are reported as zero.
+func (m *Manager) Usage(name string) (*models.ResourceUsage, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	path := m.Path(name)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	usage := &models.ResourceUsage{CgroupPath: path, UpdatedAt: time.Now().UTC()}
+	if value, ok, err := readTrimmed(filepath.Join(path, "cpu.max")); err != nil {
+		return nil, err
+	} else if ok {
+		usage.Limits.CPUCores = parseCPUMax(value)
+	}
+	if value, ok, err := readTrimmed(filepath.Join(path, "memory.max")); err != nil {
+		return nil, err
+	} else if ok {
+		usage.Limits.MemoryBytes = parseMemoryValue(value)
+	}
+	if value, ok, err := readTrimmed(filepath.Join(path, "memory.current")); err != nil {
+		return nil, err
+	} else if ok {
+		usage.MemoryBytes = parseMemoryValue(value)
+	}
+	if value, ok, err := readTrimmed(filepath.Join(path, "memory.peak")); err != nil {
+		return nil, err
+	} else if ok {
+		usage.MemoryPeakBytes = parseMemoryValue(value)
+	}
+
+	cpuStat, err := readKeyed(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	usage.CPUUsageUsec = cpuStat["usage_usec"]
+	usage.CPUThrottledUsec = cpuStat["throttled_usec"]
+
+	events, err := readKeyed(filepath.Join(path, "memory.events"))
+	if err != nil {
+		return nil, err
+	}
+	usage.OOMKills = events["oom_kill"]
+	return usage, nil
+}
+
+// Remove deletes the named group. The kernel refuses while processes remain
+// in it; a missing group is not an error.
+func (m *Manager) Remove(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	path := m.Path(name)
+	// cgroupfs directories are removed with rmdir even though they list
+	// interface files; plain directories (tests, stale mounts) need RemoveAll.
+	if err := os.Remove(path); err == nil || errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("remove cgroup: %w", err)
+	}
+	return nil
+}
+
+// FormatCPUMax converts a core count into a cpu.max value.
+func FormatCPUMax(cores float64) string {
+	if cores <= 0 {
+		return fmt.Sprintf("max %d", cpuPeriodUsec)
+	}
+	quota := int64(cores * cpuPeriodUsec)
+	if quota < 1000 {
+		// The kernel rejects quotas below 1ms.
+		quota = 1000
+	}
+	return fmt.Sprintf("%d %d", quota, cpuPeriodUsec)
+}
+
+func formatMemoryMax(bytes int64) string {
+	if bytes <= 0 {
+		return "max"
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+func parseCPUMax(value string) float64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || fields[0] == "max" {
+		return 0
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	period := float64(cpuPeriodUsec)
+	if len(fields) > 1 {
+		if parsed, err := strconv.ParseFloat(fields[1], 64); err == nil && parsed > 0 {
+			period = parsed
+		}
+	}
+	return quota / period
+}
+
+func parseMemoryValue(value string) int64 {
+	if value == "max" {
+		return 0
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+func writeFile(path, value string) error {
+	return os.WriteFile(path, []byte(value), 0o644)
+}
+
+func readTrimmed(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+// readKeyed parses "key value" lines such as cpu.stat and memory.events.
+func readKeyed(path string) (map[string]int64, error) {
+	values := make(map[string]int64)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if parsed, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[fields[0]] = parsed
+		}
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,7 @@
+//go:build linux
+
+package cgroup
+
+func supported() bool {
+	return true
+}
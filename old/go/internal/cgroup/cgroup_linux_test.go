@@ -0,0 +1,123 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestCreateWritesLimits(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "forge")
+	mgr := NewManager(root)
+
+	path, err := mgr.Create("agent-1", models.ResourceLimits{CPUCores: 1.5, MemoryBytes: 512 << 20})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if path != filepath.Join(root, "agent-1") {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if got := readFile(t, filepath.Join(root, "cgroup.subtree_control")); got != "+cpu +memory" {
+		t.Fatalf("expected controllers delegated, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(path, "cpu.max")); got != "150000 100000" {
+		t.Fatalf("unexpected cpu.max %q", got)
+	}
+	if got := readFile(t, filepath.Join(path, "memory.max")); got != "536870912" {
+		t.Fatalf("unexpected memory.max %q", got)
+	}
+
+	// Recreating without limits resets them.
+	if _, err := mgr.Create("agent-1", models.ResourceLimits{}); err != nil {
+		t.Fatalf("recreate: %v", err)
+	}
+	if got := readFile(t, filepath.Join(path, "memory.max")); got != "max" {
+		t.Fatalf("expected memory.max reset, got %q", got)
+	}
+
+	if err := mgr.AddProcess("agent-1", 4242); err != nil {
+		t.Fatalf("add process: %v", err)
+	}
+	if got := readFile(t, filepath.Join(path, "cgroup.procs")); got != "4242" {
+		t.Fatalf("unexpected cgroup.procs %q", got)
+	}
+}
+
+func TestUsageReadsInterfaceFiles(t *testing.T) {
+	root := t.TempDir()
+	mgr := NewManager(root)
+	if _, err := mgr.Create("agent-2", models.ResourceLimits{CPUCores: 2, MemoryBytes: 1000}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	path := mgr.Path("agent-2")
+	files := map[string]string{
+		"memory.current": "900\n",
+		"cpu.stat":       "usage_usec 12345\nuser_usec 10000\nthrottled_usec 77\n",
+		"memory.events":  "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	usage, err := mgr.Usage("agent-2")
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	if usage.Limits.CPUCores != 2 || usage.Limits.MemoryBytes != 1000 {
+		t.Fatalf("unexpected limits %+v", usage.Limits)
+	}
+	if usage.MemoryBytes != 900 || usage.MemoryPeakBytes != 0 {
+		t.Fatalf("unexpected memory usage %+v", usage)
+	}
+	if usage.CPUUsageUsec != 12345 || usage.CPUThrottledUsec != 77 || usage.OOMKills != 1 {
+		t.Fatalf("unexpected counters %+v", usage)
+	}
+	if !usage.IsRunaway(0.95) {
+		t.Fatalf("expected OOM kill to mark the agent as runaway")
+	}
+
+	if err := mgr.Remove("agent-2"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected group removed, stat err=%v", err)
+	}
+	if err := mgr.Remove("agent-2"); err != nil {
+		t.Fatalf("removing a missing group should succeed: %v", err)
+	}
+}
+
+func TestRejectsInvalidNames(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	for _, name := range []string{"", "..", "a/b"} {
+		if _, err := mgr.Create(name, models.ResourceLimits{}); !errors.Is(err, ErrInvalidName) {
+			t.Fatalf("name %q: expected ErrInvalidName, got %v", name, err)
+		}
+	}
+}
+
+func TestFormatCPUMax(t *testing.T) {
+	cases := map[float64]string{0: "max 100000", 0.5: "50000 100000", 0.001: "1000 100000"}
+	for cores, want := range cases {
+		if got := FormatCPUMax(cores); got != want {
+			t.Fatalf("FormatCPUMax(%v) = %q, want %q", cores, got, want)
+		}
+	}
+}
@@ -53,6 +53,7 @@ func init() {
 	accountsCmd.AddCommand(accountsAddCmd)
 	accountsCmd.AddCommand(accountsCooldownCmd)
 	accountsCmd.AddCommand(accountsRotateCmd)
+	accountsCmd.AddCommand(accountsHealthCmd)
 	accountsCmd.AddCommand(accountsImportCaamCmd)
 
 	accountsCooldownCmd.AddCommand(accountsCooldownListCmd)
@@ -730,7 +731,7 @@ var accountsRotateCmd = &cobra.Command{
 		queueRepo := db.NewQueueRepository(database)
 
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		agentInfo, err := findAgent(ctx, agentRepo, args[0])
 		if err != nil {
@@ -787,6 +788,64 @@ var accountsRotateCmd = &cobra.Command{
 	},
 }
 
+var accountsHealthCmd = &cobra.Command{
+	Use:     "health",
+	Aliases: []string{"status"},
+	Short:   "Show account rotation health",
+	Long: `Show whether each account can take work: cooldown remaining, usage
+against its configured quota (tokens and cost per day, requests per hour)
+and how often it has been rate limited.
+
+Accounts on cooldown or over quota are skipped when agents are rotated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		accountService, err := buildAccountService(ctx, db.NewAccountRepository(database), accountIDModeDatabase, database)
+		if err != nil {
+			return err
+		}
+		health, err := accountService.Health(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check account health: %w", err)
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, health)
+		}
+
+		if len(health) == 0 {
+			fmt.Fprintln(os.Stdout, "No accounts found.")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "PROVIDER\tPROFILE\tSTATUS\tCOOLDOWN\tTOKENS/DAY\tCOST/DAY\tREQ/HOUR\tRATE LIMITS")
+		for _, entry := range health {
+			tokens, cost, requests := formatAccountQuota(entry)
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+				entry.Account.Provider,
+				entry.Account.ProfileName,
+				entry.Status,
+				formatAccountCooldown(entry.Account),
+				tokens,
+				cost,
+				requests,
+				entry.RateLimitCount,
+			)
+		}
+		return writer.Flush()
+	},
+}
+
 var accountsCooldownListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List account cooldowns",
@@ -977,7 +1036,12 @@ func buildAccountService(ctx context.Context, repo *db.AccountRepository, mode a
 	cfgCopy := *cfg
 	cfgCopy.Accounts = nil
 
-	svc := account.NewService(&cfgCopy, account.WithRepository(repo), account.WithPublisher(newEventPublisher(database)))
+	svc := account.NewService(&cfgCopy,
+		account.WithRepository(repo),
+		account.WithPublisher(newEventPublisher(database)),
+		account.WithUsageRepository(db.NewUsageRepository(database)),
+		account.WithQuotas(cfg.AccountQuotas()),
+	)
 
 	accounts, err := repo.List(ctx, nil)
 	if err != nil {
@@ -1113,6 +1177,26 @@ func formatAccountCooldown(account *models.Account) string {
 	return remaining.Round(time.Second).String()
 }
 
+// formatAccountQuota renders usage as a percentage of each configured
+// limit, or "-" when the limit is not set.
+func formatAccountQuota(entry account.Health) (tokens, cost, requests string) {
+	tokens, cost, requests = "-", "-", "-"
+	if entry.Quota == nil || entry.QuotaStatus == nil {
+		return tokens, cost, requests
+	}
+	quota, status := entry.Quota, entry.QuotaStatus
+	if quota.MaxTokensPerDay > 0 {
+		tokens = fmt.Sprintf("%.0f%% of %d", status.TokensUsedPercent, quota.MaxTokensPerDay)
+	}
+	if quota.MaxCostPerDayCents > 0 {
+		cost = fmt.Sprintf("%.0f%% of $%.2f", status.CostUsedPercent, float64(quota.MaxCostPerDayCents)/100)
+	}
+	if quota.MaxRequestsPerHour > 0 {
+		requests = fmt.Sprintf("%.0f%% of %d", status.RequestsUsedPercent, quota.MaxRequestsPerHour)
+	}
+	return tokens, cost, requests
+}
+
 func parseCooldownUntil(value string) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
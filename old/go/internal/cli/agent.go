@@ -11,10 +11,12 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/adapters"
 	"github.com/tOgg1/forge/internal/agent"
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/models"
@@ -26,12 +28,13 @@ import (
 
 var (
 	// agent spawn flags
-	agentSpawnWorkspace string
-	agentSpawnType      string
-	agentSpawnCount     int
-	agentSpawnProfile   string
-	agentSpawnPrompt    string
-	agentSpawnNoWait    bool
+	agentSpawnWorkspace   string
+	agentSpawnType        string
+	agentSpawnCount       int
+	agentSpawnProfile     string
+	agentSpawnPrompt      string
+	agentSpawnNoWait      bool
+	agentSpawnConstraints []string
 
 	// agent list flags
 	agentListWorkspace string
@@ -79,6 +82,7 @@ func init() {
 	agentSpawnCmd.Flags().StringVarP(&agentSpawnProfile, "profile", "p", "", "account profile to use")
 	agentSpawnCmd.Flags().StringVar(&agentSpawnPrompt, "prompt", "", "initial prompt to send after spawn")
 	agentSpawnCmd.Flags().BoolVar(&agentSpawnNoWait, "no-wait", false, "don't wait for agent to be ready")
+	agentSpawnCmd.Flags().StringArrayVar(&agentSpawnConstraints, "constraint", nil, "node label constraint the workspace's node must satisfy (repeatable)")
 
 	// List flags
 	agentListCmd.Flags().StringVarP(&agentListWorkspace, "workspace", "w", "", "filter by workspace (uses context if not set)")
@@ -135,10 +139,18 @@ If --workspace is not specified, the workspace is resolved from:
   forge agent spawn -w my-project -t claude-code -n 3 -p work-account
 
   # Spawn with an initial prompt
-  forge agent spawn -w my-project --prompt "Fix all linting errors"`,
+  forge agent spawn -w my-project --prompt "Fix all linting errors"
+
+  # Require a GPU node; dispatch pauses if the node loses the label
+  forge agent spawn -w my-project --constraint gpu=true`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
+		constraints, err := models.ParseNodeConstraints(agentSpawnConstraints)
+		if err != nil {
+			return err
+		}
+
 		database, err := openDatabase()
 		if err != nil {
 			return err
@@ -151,10 +163,10 @@ If --workspace is not specified, the workspace is resolved from:
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		// Resolve workspace from flag, directory, or stored context
 		resolved, err := RequireWorkspaceContext(ctx, wsRepo, agentSpawnWorkspace)
@@ -173,23 +185,22 @@ If --workspace is not specified, the workspace is resolved from:
 
 		// Parse agent type
 		agentType := models.AgentType(agentSpawnType)
-		switch agentType {
-		case models.AgentTypeOpenCode, models.AgentTypeClaudeCode,
-			models.AgentTypeCodex, models.AgentTypeGemini, models.AgentTypeGeneric:
-			// Valid
-		default:
-			return fmt.Errorf("invalid agent type: %s", agentSpawnType)
+		if !adapters.Has(agentType) {
+			names := adapters.Names()
+			sort.Strings(names)
+			return fmt.Errorf("invalid agent type: %s (available: %s)", agentSpawnType, strings.Join(names, ", "))
 		}
 
 		// Spawn agents
 		var agents []*models.Agent
 		for i := 0; i < agentSpawnCount; i++ {
 			opts := agent.SpawnOptions{
-				WorkspaceID:    ws.ID,
-				Type:           agentType,
-				AccountID:      agentSpawnProfile,
-				InitialPrompt:  agentSpawnPrompt,
-				ApprovalPolicy: approvalPolicy,
+				WorkspaceID:     ws.ID,
+				Type:            agentType,
+				AccountID:       agentSpawnProfile,
+				InitialPrompt:   agentSpawnPrompt,
+				ApprovalPolicy:  approvalPolicy,
+				NodeConstraints: constraints,
 			}
 			// If --no-wait is set, use a very short timeout to skip waiting
 			if agentSpawnNoWait {
@@ -286,10 +297,10 @@ Use --workspace="" to list all agents across workspaces.`,
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		// Build options
 		opts := agent.ListAgentsOptions{
@@ -373,10 +384,10 @@ var agentStatusCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -464,10 +475,10 @@ var agentTerminateCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -523,10 +534,10 @@ var agentInterruptCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -577,10 +588,10 @@ var agentPauseCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -630,10 +641,10 @@ var agentResumeCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -699,7 +710,7 @@ Provide the message inline, or use --file, --stdin, or --editor to send multi-li
 
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		queueService := queue.NewService(queueRepo)
+		queueService := queue.NewService(queueRepo, queueServiceOptions()...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -744,10 +755,10 @@ var agentRestartCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		resolved, err := findAgent(ctx, agentRepo, agentID)
 		if err != nil {
@@ -814,7 +825,7 @@ Special markers in the file:
 
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		queueService := queue.NewService(queueRepo)
+		queueService := queue.NewService(queueRepo, queueServiceOptions()...)
 
 		// Verify agent exists
 		a, err := findAgent(ctx, agentRepo, agentID)
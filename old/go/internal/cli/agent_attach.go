@@ -0,0 +1,428 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/agent"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/tmux"
+	"github.com/tOgg1/forge/internal/workspace"
+)
+
+var (
+	agentAttachMirror      bool
+	agentAttachTakeControl bool
+	agentAttachInterval    time.Duration
+	agentAttachLease       time.Duration
+)
+
+func init() {
+	agentCmd.AddCommand(agentAttachCmd)
+
+	agentAttachCmd.Flags().BoolVar(&agentAttachMirror, "mirror", false, "open a read-only mirror of the pane instead of attaching")
+	agentAttachCmd.Flags().BoolVar(&agentAttachTakeControl, "take-control", false, "pause dispatch to the agent while attached")
+	agentAttachCmd.Flags().DurationVar(&agentAttachInterval, "interval", 500*time.Millisecond, "mirror refresh interval")
+	agentAttachCmd.Flags().DurationVar(&agentAttachLease, "lease", agent.DefaultTakeoverLease, "how long a takeover outlives this command if it exits without releasing")
+}
+
+var agentAttachCmd = &cobra.Command{
+	Use:   "attach <agent-id>",
+	Short: "Attach to an agent's pane or mirror it read-only",
+	Long: `Attach your terminal to an agent's tmux pane, or watch it read-only.
+
+By default the terminal attaches to the workspace session with the agent's
+pane selected (inside tmux the client switches to it instead). With
+--take-control the scheduler stops dispatching to the agent until you
+detach, so queued messages are not typed over your input.
+
+--mirror opens a read-only view that refreshes the pane. Press c to take
+control: dispatch pauses and your keys are sent to the pane until Ctrl+]
+releases control. q quits the mirror.
+
+Control is a renewed pause: if this command dies without releasing, the
+scheduler resumes the agent once --lease has passed. A pause that was
+already in effect is restored on release.`,
+	Example: `  forge agent attach abc123
+  forge agent attach abc123 --take-control
+  forge agent attach abc123 --mirror`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		nodeRepo := db.NewNodeRepository(database)
+		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
+		wsRepo := db.NewWorkspaceRepository(database)
+		agentRepo := db.NewAgentRepository(database)
+		queueRepo := db.NewQueueRepository(database)
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
+
+		tmuxClient := tmux.NewLocalClient()
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
+
+		resolved, err := findAgent(ctx, agentRepo, args[0])
+		if err != nil {
+			return err
+		}
+		if resolved.TmuxPane == "" {
+			return fmt.Errorf("agent '%s' has no tmux pane", resolved.ID)
+		}
+		ws, err := wsRepo.Get(ctx, resolved.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to get workspace: %w", err)
+		}
+		if ws.TmuxSession == "" {
+			return fmt.Errorf("workspace has no tmux session")
+		}
+
+		insideTmux := getEnvTrim("TMUX") != ""
+		attachCmd := agentAttachCommand(ws.TmuxSession, resolved.TmuxPane, insideTmux)
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]string{
+				"agent_id": resolved.ID,
+				"pane_id":  resolved.TmuxPane,
+				"session":  ws.TmuxSession,
+				"command":  attachCmd,
+			})
+		}
+		if !hasTTY() {
+			return errors.New("forge agent attach needs a terminal")
+		}
+
+		operator := currentAuditActor()
+		control := agentControl{service: agentService, agentID: resolved.ID, operator: operator, lease: agentAttachLease}
+
+		if agentAttachMirror {
+			return runAgentMirror(ctx, tmuxClient, resolved, control, agentAttachInterval)
+		}
+
+		if agentAttachTakeControl {
+			if insideTmux {
+				return errors.New("--take-control cannot tell when you leave a pane switched to inside tmux; use --mirror, or run it outside tmux")
+			}
+			if err := control.take(ctx); err != nil {
+				return err
+			}
+			stop := control.renewEvery(ctx, agentAttachLease/3)
+			defer func() {
+				stop()
+				if err := control.release(context.Background()); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to release control of agent '%s': %v\n", resolved.ID, err)
+				}
+			}()
+			fmt.Fprintf(os.Stderr, "Dispatch to agent '%s' is paused until you detach\n", resolved.ID)
+		}
+
+		tmuxCmd := exec.CommandContext(ctx, "sh", "-c", attachCmd)
+		tmuxCmd.Stdin = os.Stdin
+		tmuxCmd.Stdout = os.Stdout
+		tmuxCmd.Stderr = os.Stderr
+		return tmuxCmd.Run()
+	},
+}
+
+// agentAttachCommand builds the shell command that shows pane to the user.
+// Inside tmux the current client switches to it; nesting an attach would
+// fail.
+func agentAttachCommand(session, pane string, insideTmux bool) string {
+	if insideTmux {
+		return fmt.Sprintf("tmux switch-client -t %s && tmux select-window -t %s && tmux select-pane -t %s",
+			session, pane, pane)
+	}
+	return fmt.Sprintf("tmux select-window -t %s && tmux select-pane -t %s && tmux attach-session -t %s",
+		pane, pane, session)
+}
+
+// agentControl takes, renews, and releases an operator's control of an
+// agent.
+type agentControl struct {
+	service  *agent.Service
+	agentID  string
+	operator string
+	lease    time.Duration
+}
+
+func (c agentControl) take(ctx context.Context) error {
+	if _, err := c.service.TakeControl(ctx, c.agentID, c.operator, c.lease); err != nil {
+		return fmt.Errorf("failed to take control: %w", err)
+	}
+	return nil
+}
+
+func (c agentControl) release(ctx context.Context) error {
+	return c.service.ReleaseControl(ctx, c.agentID)
+}
+
+// renewEvery renews control in the background until the returned stop
+// function is called.
+func (c agentControl) renewEvery(ctx context.Context, interval time.Duration) func() {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.take(ctx); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func runAgentMirror(ctx context.Context, client *tmux.Client, agentEntry *models.Agent, control agentControl, interval time.Duration) error {
+	pane := agentEntry.TmuxPane
+	model := newMirrorModel(fmt.Sprintf("%s (%s) %s", shortID(agentEntry.ID), agentEntry.Type, pane), interval, control.lease/3)
+	model.capture = func() (string, error) {
+		return client.CapturePane(ctx, pane, false)
+	}
+	model.sendKeys = func(keys string, literal bool) error {
+		return client.SendKeys(ctx, pane, keys, literal, false)
+	}
+	model.takeControl = func() error { return control.take(ctx) }
+	model.releaseControl = func() error { return control.release(ctx) }
+
+	final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if m, ok := final.(mirrorModel); ok && m.controlled {
+		if releaseErr := control.release(context.Background()); releaseErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to release control of agent '%s': %v\n", agentEntry.ID, releaseErr)
+		}
+	}
+	return err
+}
+
+type mirrorCaptureMsg struct {
+	content string
+	err     error
+}
+
+type mirrorTickMsg struct{}
+
+type mirrorRenewMsg struct{}
+
+type mirrorControlMsg struct {
+	controlled bool
+	err        error
+}
+
+type mirrorSendMsg struct {
+	err error
+}
+
+// mirrorModel is the read-only pane view of 'forge agent attach --mirror'.
+// Taking control forwards keys to the pane; Ctrl+] gives control back.
+type mirrorModel struct {
+	title         string
+	interval      time.Duration
+	renewInterval time.Duration
+
+	capture        func() (string, error)
+	sendKeys       func(keys string, literal bool) error
+	takeControl    func() error
+	releaseControl func() error
+
+	content    string
+	err        error
+	controlled bool
+	width      int
+	height     int
+}
+
+func newMirrorModel(title string, interval, renewInterval time.Duration) mirrorModel {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	if renewInterval <= 0 {
+		renewInterval = time.Minute
+	}
+	return mirrorModel{title: title, interval: interval, renewInterval: renewInterval}
+}
+
+func (m mirrorModel) Init() tea.Cmd {
+	return m.captureCmd()
+}
+
+func (m mirrorModel) captureCmd() tea.Cmd {
+	capture := m.capture
+	return func() tea.Msg {
+		if capture == nil {
+			return mirrorCaptureMsg{}
+		}
+		content, err := capture()
+		return mirrorCaptureMsg{content: content, err: err}
+	}
+}
+
+func (m mirrorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case mirrorCaptureMsg:
+		m.content, m.err = msg.content, msg.err
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return mirrorTickMsg{} })
+	case mirrorTickMsg:
+		return m, m.captureCmd()
+	case mirrorRenewMsg:
+		if !m.controlled {
+			return m, nil
+		}
+		return m, m.controlCmd(true)
+	case mirrorControlMsg:
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		wasControlled := m.controlled
+		m.controlled = msg.controlled
+		if m.controlled {
+			if !wasControlled {
+				m.err = nil
+			}
+			return m, tea.Tick(m.renewInterval, func(time.Time) tea.Msg { return mirrorRenewMsg{} })
+		}
+	case mirrorSendMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, m.captureCmd()
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m mirrorModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.controlled {
+		if msg.String() == "ctrl+]" {
+			return m, m.controlCmd(false)
+		}
+		keys, literal, ok := tmuxKeyFor(msg)
+		if !ok || m.sendKeys == nil {
+			return m, nil
+		}
+		send := m.sendKeys
+		return m, func() tea.Msg { return mirrorSendMsg{err: send(keys, literal)} }
+	}
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "c":
+		return m, m.controlCmd(true)
+	}
+	return m, nil
+}
+
+func (m mirrorModel) controlCmd(take bool) tea.Cmd {
+	fn := m.releaseControl
+	if take {
+		fn = m.takeControl
+	}
+	return func() tea.Msg {
+		if fn == nil {
+			return mirrorControlMsg{controlled: take}
+		}
+		if err := fn(); err != nil {
+			return mirrorControlMsg{controlled: !take, err: err}
+		}
+		return mirrorControlMsg{controlled: take}
+	}
+}
+
+func (m mirrorModel) View() string {
+	mode := "read-only  c take control  q quit"
+	if m.controlled {
+		mode = "CONTROL: keys go to the pane, dispatch paused  Ctrl+] release"
+	}
+	header := fmt.Sprintf("agent %s  [%s]", m.title, mode)
+	footer := ""
+	if m.err != nil {
+		footer = "error: " + m.err.Error()
+	}
+
+	bodyHeight := m.height - 2
+	if bodyHeight < 1 {
+		bodyHeight = 20
+	}
+	lines := strings.Split(strings.TrimRight(m.content, "\n"), "\n")
+	if len(lines) > bodyHeight {
+		lines = lines[len(lines)-bodyHeight:]
+	}
+	if m.width > 0 {
+		header = truncateString(header, m.width)
+		footer = truncateString(footer, m.width)
+	}
+	return header + "\n" + strings.Join(lines, "\n") + "\n" + footer
+}
+
+// tmuxSpecialKeys maps bubbletea key names to tmux send-keys names.
+var tmuxSpecialKeys = map[string]string{
+	"enter":     "Enter",
+	"tab":       "Tab",
+	"shift+tab": "BTab",
+	"backspace": "BSpace",
+	"delete":    "DC",
+	"insert":    "IC",
+	"esc":       "Escape",
+	"up":        "Up",
+	"down":      "Down",
+	"left":      "Left",
+	"right":     "Right",
+	"home":      "Home",
+	"end":       "End",
+	"pgup":      "PPage",
+	"pgdown":    "NPage",
+	" ":         "Space",
+}
+
+// tmuxKeyFor translates a key press into send-keys arguments. Typed text is
+// sent literally; everything else uses tmux key names.
+func tmuxKeyFor(msg tea.KeyMsg) (keys string, literal bool, ok bool) {
+	name := msg.String()
+	if msg.Type == tea.KeyRunes && !msg.Alt {
+		return string(msg.Runes), true, len(msg.Runes) > 0
+	}
+	prefix := ""
+	if rest, found := strings.CutPrefix(name, "alt+"); found {
+		prefix, name = "M-", rest
+	}
+	if special, found := tmuxSpecialKeys[name]; found {
+		return prefix + special, false, true
+	}
+	if rest, found := strings.CutPrefix(name, "ctrl+"); found && len(rest) == 1 {
+		return prefix + "C-" + rest, false, true
+	}
+	if len(name) >= 2 && name[0] == 'f' && strings.Trim(name[1:], "0123456789") == "" {
+		return prefix + "F" + name[1:], false, true
+	}
+	if prefix != "" && len([]rune(name)) == 1 {
+		return prefix + name, false, true
+	}
+	return "", false, false
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTmuxKeyFor(t *testing.T) {
+	cases := []struct {
+		msg     tea.KeyMsg
+		keys    string
+		literal bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ls -la")}, "ls -la", true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, "Enter", false},
+		{tea.KeyMsg{Type: tea.KeyCtrlC}, "C-c", false},
+		{tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, "Space", false},
+		{tea.KeyMsg{Type: tea.KeyUp, Alt: true}, "M-Up", false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}, Alt: true}, "M-x", false},
+		{tea.KeyMsg{Type: tea.KeyF5}, "F5", false},
+	}
+	for _, tc := range cases {
+		keys, literal, ok := tmuxKeyFor(tc.msg)
+		if !ok || keys != tc.keys || literal != tc.literal {
+			t.Fatalf("%q: got keys=%q literal=%v ok=%v", tc.msg.String(), keys, literal, ok)
+		}
+	}
+}
+
+func TestMirrorModelTakeAndReleaseControl(t *testing.T) {
+	var sent []string
+	var taken, released int
+	m := newMirrorModel("abc (claude) %3", 0, 0)
+	m.sendKeys = func(keys string, literal bool) error {
+		sent = append(sent, keys)
+		return nil
+	}
+	m.takeControl = func() error {
+		taken++
+		return nil
+	}
+	m.releaseControl = func() error {
+		released++
+		return nil
+	}
+
+	step := func(model mirrorModel, msg tea.Msg) (mirrorModel, tea.Cmd) {
+		next, cmd := model.Update(msg)
+		return next.(mirrorModel), cmd
+	}
+
+	// Read-only: typing goes nowhere.
+	m, cmd := step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd != nil || len(sent) != 0 {
+		t.Fatalf("expected read-only mirror to ignore keys")
+	}
+
+	m, cmd = step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m, _ = step(m, cmd())
+	if !m.controlled || taken != 1 {
+		t.Fatalf("expected control taken, controlled=%v taken=%d", m.controlled, taken)
+	}
+
+	m, cmd = step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	cmd()
+	if len(sent) != 1 || sent[0] != "q" {
+		t.Fatalf("expected q forwarded to the pane, got %v", sent)
+	}
+
+	m, cmd = step(m, tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	m, _ = step(m, cmd())
+	if m.controlled || released != 1 {
+		t.Fatalf("expected control released, controlled=%v released=%d", m.controlled, released)
+	}
+
+	m.takeControl = func() error { return errors.New("agent is controlled by another operator") }
+	m, cmd = step(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m, _ = step(m, cmd())
+	if m.controlled || m.err == nil {
+		t.Fatalf("expected failed takeover to stay read-only with an error")
+	}
+}
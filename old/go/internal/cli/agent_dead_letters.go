@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/queue"
+)
+
+func init() {
+	agentCmd.AddCommand(agentDeadLettersCmd)
+	agentDeadLettersCmd.AddCommand(agentDeadLettersRequeueCmd)
+}
+
+var agentDeadLettersCmd = &cobra.Command{
+	Use:     "dead-letters [agent-id]",
+	Aliases: []string{"dlq"},
+	Short:   "Show queue items that exhausted their retries",
+	Long: `Show queue items that were dead-lettered after failing more times than
+their retry policy allows (see scheduler.retry_policies). Dead-lettered
+items are marked failed and are not dispatched again until requeued with
+'forge agent dead-letters requeue'.`,
+	Example: `  forge agent dead-letters
+  forge agent dead-letters triage-1 --json
+  forge agent dead-letters requeue <item-id>`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		agentID := ""
+		if len(args) == 1 {
+			resolved, err := findAgent(ctx, db.NewAgentRepository(database), args[0])
+			if err != nil {
+				return err
+			}
+			agentID = resolved.ID
+		}
+
+		items, err := queue.NewService(db.NewQueueRepository(database), queueServiceOptions()...).ListDeadLetters(ctx, agentID)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, items)
+		}
+		if len(items) == 0 {
+			fmt.Println("No dead-lettered queue items")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			policy := "-"
+			if item.RetryPolicy != nil {
+				policy = string(item.RetryPolicy.Kind)
+			}
+			deadAt := "-"
+			if item.DeadLetteredAt != nil {
+				deadAt = formatRelativeTime(*item.DeadLetteredAt)
+			}
+			rows = append(rows, []string{
+				shortID(item.ID),
+				shortID(item.AgentID),
+				string(item.Type),
+				strconv.Itoa(item.Attempts),
+				policy,
+				deadAt,
+				truncate(item.Error, 60),
+			})
+		}
+		return writeTable(os.Stdout, []string{"ID", "AGENT", "TYPE", "ATTEMPTS", "POLICY", "DEAD-LETTERED", "ERROR"}, rows)
+	},
+}
+
+var agentDeadLettersRequeueCmd = &cobra.Command{
+	Use:   "requeue <item-id>",
+	Short: "Move a dead-lettered item back to the end of its agent's queue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		item, err := queue.NewService(db.NewQueueRepository(database), queueServiceOptions()...).Requeue(ctx, args[0])
+		if err != nil {
+			if errors.Is(err, queue.ErrQueueItemNotFound) {
+				return fmt.Errorf("no dead-lettered queue item %q", args[0])
+			}
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, item)
+		}
+		if IsQuiet() {
+			return nil
+		}
+		fmt.Printf("Requeued %s item %s for agent %s at position %d\n", item.Type, shortID(item.ID), shortID(item.AgentID), item.Position)
+		return nil
+	},
+}
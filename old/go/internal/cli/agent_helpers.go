@@ -2,10 +2,18 @@
 package cli
 
 import (
+	"context"
 	"path/filepath"
+	"time"
 
+	"github.com/tOgg1/forge/internal/account"
 	"github.com/tOgg1/forge/internal/agent"
+	"github.com/tOgg1/forge/internal/cgroup"
+	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/queue"
+	"github.com/tOgg1/forge/internal/workspace"
 )
 
 func agentServiceOptions(database *db.DB) []agent.ServiceOption {
@@ -21,7 +29,95 @@ func agentServiceOptions(database *db.DB) []agent.ServiceOption {
 	if cfg := GetConfig(); cfg != nil {
 		archiveDir := filepath.Join(cfg.Global.DataDir, "archives", "agents")
 		opts = append(opts, agent.WithArchiveDir(archiveDir))
+		opts = append(opts, agent.WithRecoveryPolicy(agent.RecoveryPolicy{MaxAttempts: cfg.Scheduler.MaxRecoveryAttempts}))
+		opts = append(opts, agent.WithHeartbeatPolicy(agentHeartbeatPolicy(cfg)))
+		if cfg.AgentDefaults.Cgroups.Enabled {
+			manager := cgroup.NewManager(cfg.AgentDefaults.Cgroups.Root)
+			opts = append(opts, agent.WithCgroups(manager, agentResourceLimits(cfg, database)))
+		}
+		if database != nil {
+			opts = append(opts, agent.WithPortAllocator(newPortAllocator(cfg, database)))
+		}
 	}
 
 	return opts
 }
+
+// agentHeartbeatPolicy maps agent_defaults.heartbeat onto the agent
+// service's heartbeat policy.
+func agentHeartbeatPolicy(cfg *config.Config) agent.HeartbeatPolicy {
+	settings := cfg.AgentDefaults.Heartbeat
+	policy := agent.HeartbeatPolicy{Timeout: settings.Timeout}
+	if settings.Enabled {
+		policy.Dir = cfg.HeartbeatPath()
+	}
+	if len(settings.HarnessTimeouts) > 0 {
+		policy.HarnessTimeouts = make(map[models.AgentType]time.Duration, len(settings.HarnessTimeouts))
+		for harness, timeout := range settings.HarnessTimeouts {
+			policy.HarnessTimeouts[models.AgentType(harness)] = timeout
+		}
+	}
+	return policy
+}
+
+// workspaceServiceOptions returns the standard options for a workspace
+// service, including the configured workspace quotas and git status interval.
+func workspaceServiceOptions(database *db.DB) []workspace.ServiceOption {
+	opts := []workspace.ServiceOption{workspace.WithPublisher(newEventPublisher(database))}
+	if database != nil {
+		opts = append(opts, workspace.WithLoopRepository(db.NewLoopRepository(database)))
+	}
+	if cfg := GetConfig(); cfg != nil {
+		opts = append(opts,
+			workspace.WithQuotas(cfg.QuotaForWorkspace),
+			workspace.WithGitStatusInterval(cfg.WorkspaceDefaults.GitStatusInterval),
+		)
+	}
+	return opts
+}
+
+// queueServiceOptions returns the standard options for a queue service,
+// including the configured per-type retry policies.
+func queueServiceOptions() []queue.ServiceOption {
+	if cfg := GetConfig(); cfg != nil {
+		return []queue.ServiceOption{queue.WithRetryPolicies(cfg.Scheduler.QueueRetryPolicies())}
+	}
+	return nil
+}
+
+// newAgentAccountService builds the account pool used to inject credentials
+// into agents and rotate them off rate-limited or over-quota accounts.
+// Agents reference accounts by profile name. It returns nil when accounts
+// cannot be loaded so agent commands keep working without injection.
+func newAgentAccountService(ctx context.Context, database *db.DB) *account.Service {
+	if database == nil {
+		return nil
+	}
+	svc, err := buildAccountService(ctx, db.NewAccountRepository(database), accountIDModeProfile, database)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to load accounts, continuing without credential injection")
+		return nil
+	}
+	return svc
+}
+
+// newPortAllocator builds the OpenCode port allocator from agent_defaults.ports.
+func newPortAllocator(cfg *config.Config, database *db.DB) *agent.PortAllocator {
+	ports := cfg.AgentDefaults.Ports
+	repo := db.NewPortRepositoryWithRange(database, ports.RangeStart, ports.RangeEnd)
+	return agent.NewPortAllocator(repo, ports.LeaseTTL, ports.CheckListening)
+}
+
+// agentResourceLimits resolves limits by the account's profile name. Agents
+// may also reference an account by profile name directly.
+func agentResourceLimits(cfg *config.Config, database *db.DB) agent.ResourceLimitsFunc {
+	return func(ctx context.Context, accountID string) models.ResourceLimits {
+		profileName := accountID
+		if accountID != "" && database != nil {
+			if acct, err := db.NewAccountRepository(database).Get(ctx, accountID); err == nil {
+				profileName = acct.ProfileName
+			}
+		}
+		return cfg.AgentResourceLimits(profileName)
+	}
+}
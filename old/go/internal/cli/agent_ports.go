@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/agent"
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var agentPortsNode string
+
+func init() {
+	agentCmd.AddCommand(agentPortsCmd)
+	agentPortsCmd.AddCommand(agentPortsReclaimCmd)
+
+	agentPortsCmd.Flags().StringVar(&agentPortsNode, "node", "", "filter by node name or ID")
+}
+
+// Port assignment statuses reported by 'forge agent ports'.
+const (
+	portStatusBound   = "bound"
+	portStatusLeased  = "leased"
+	portStatusExpired = "expired"
+	portStatusStale   = "stale"
+)
+
+// portAssignment is one row of 'forge agent ports'.
+type portAssignment struct {
+	Node           string     `json:"node"`
+	NodeID         string     `json:"node_id"`
+	Port           int        `json:"port"`
+	AgentID        string     `json:"agent_id,omitempty"`
+	AgentState     string     `json:"agent_state,omitempty"`
+	Status         string     `json:"status"`
+	Reason         string     `json:"reason,omitempty"`
+	AllocatedAt    time.Time  `json:"allocated_at"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	// Listening is only set for allocations on the local node.
+	Listening *bool  `json:"listening,omitempty"`
+	Conflict  string `json:"conflict,omitempty"`
+}
+
+var agentPortsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Show OpenCode port assignments",
+	Long: `Show OpenCode server ports allocated to agents.
+
+Status is bound (held by a live agent), leased (reserved during spawn and not
+yet bound), expired (lease ran out), or stale (agent stopped or deleted).
+Expired and stale allocations are reclaimed on the next spawn or by
+'forge agent ports reclaim'. On the local node each port is probed; a port
+that is listening without a live agent, or that lies outside
+agent_defaults.ports, is reported as a conflict.`,
+	Example: `  forge agent ports
+  forge agent ports --node local --json
+  forge agent ports reclaim`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		cfg := GetConfig()
+		if cfg == nil {
+			cfg = config.DefaultConfig()
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		assignments, err := listPortAssignments(ctx, cfg, database, agentPortsNode)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, assignments)
+		}
+		if len(assignments) == 0 {
+			fmt.Printf("No ports allocated (range %d-%d)\n", cfg.AgentDefaults.Ports.RangeStart, cfg.AgentDefaults.Ports.RangeEnd)
+			return nil
+		}
+
+		rows := make([][]string, 0, len(assignments))
+		for _, a := range assignments {
+			agentID := "-"
+			if a.AgentID != "" {
+				agentID = shortID(a.AgentID)
+			}
+			listening := "-"
+			if a.Listening != nil {
+				listening = "no"
+				if *a.Listening {
+					listening = "yes"
+				}
+			}
+			conflict := a.Conflict
+			if conflict == "" {
+				conflict = "-"
+			}
+			rows = append(rows, []string{
+				a.Node,
+				strconv.Itoa(a.Port),
+				agentID,
+				a.Status,
+				listening,
+				formatRelativeTime(a.AllocatedAt),
+				conflict,
+			})
+		}
+		return writeTable(os.Stdout, []string{"NODE", "PORT", "AGENT", "STATUS", "LISTENING", "ALLOCATED", "CONFLICT"}, rows)
+	},
+}
+
+var agentPortsReclaimCmd = &cobra.Command{
+	Use:   "reclaim",
+	Short: "Free expired leases and ports held by dead agents",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		cfg := GetConfig()
+		if cfg == nil {
+			cfg = config.DefaultConfig()
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		reclaimed, err := newPortAllocator(cfg, database).Reclaim(ctx)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{"reclaimed": reclaimed})
+		}
+		if IsQuiet() {
+			return nil
+		}
+		fmt.Printf("Reclaimed %d port(s)\n", reclaimed)
+		return nil
+	},
+}
+
+// listPortAssignments resolves every allocation's node and agent, probing
+// ports on the local node for listeners.
+func listPortAssignments(ctx context.Context, cfg *config.Config, database *db.DB, nodeFilter string) ([]portAssignment, error) {
+	allocations, err := db.NewPortRepository(database).ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	agentRepo := db.NewAgentRepository(database)
+	nodes := make(map[string]*models.Node)
+	now := time.Now().UTC()
+	ports := cfg.AgentDefaults.Ports
+
+	assignments := make([]portAssignment, 0, len(allocations))
+	for _, alloc := range allocations {
+		nodeObj, ok := nodes[alloc.NodeID]
+		if !ok {
+			nodeObj, err = nodeRepo.Get(ctx, alloc.NodeID)
+			if err != nil && !errors.Is(err, db.ErrNodeNotFound) {
+				return nil, err
+			}
+			nodes[alloc.NodeID] = nodeObj
+		}
+		nodeName := alloc.NodeID
+		if nodeObj != nil {
+			nodeName = nodeObj.Name
+		}
+		if nodeFilter != "" && nodeFilter != nodeName && nodeFilter != alloc.NodeID {
+			continue
+		}
+
+		entry := portAssignment{
+			Node:           nodeName,
+			NodeID:         alloc.NodeID,
+			Port:           alloc.Port,
+			Reason:         alloc.Reason,
+			AllocatedAt:    alloc.AllocatedAt,
+			LeaseExpiresAt: alloc.LeaseExpiresAt,
+		}
+
+		switch {
+		case alloc.AgentID != nil:
+			entry.AgentID = *alloc.AgentID
+			entry.Status = portStatusBound
+			agentObj, err := agentRepo.Get(ctx, entry.AgentID)
+			switch {
+			case errors.Is(err, db.ErrAgentNotFound):
+				entry.Status = portStatusStale
+			case err != nil:
+				return nil, err
+			default:
+				entry.AgentState = string(agentObj.State)
+				if agentObj.State == models.AgentStateStopped {
+					entry.Status = portStatusStale
+				}
+			}
+		case alloc.LeaseExpiresAt != nil && !alloc.LeaseExpiresAt.After(now):
+			entry.Status = portStatusExpired
+		case alloc.LeaseExpiresAt != nil:
+			entry.Status = portStatusLeased
+		default:
+			entry.Status = portStatusBound
+		}
+
+		if nodeObj != nil && nodeObj.IsLocal {
+			listening := agent.LocalPortInUse(alloc.Port)
+			entry.Listening = &listening
+			if listening && (entry.Status == portStatusStale || entry.Status == portStatusExpired) {
+				entry.Conflict = "listener without a live agent"
+			}
+		}
+		if alloc.Port < ports.RangeStart || alloc.Port > ports.RangeEnd {
+			entry.Conflict = fmt.Sprintf("outside range %d-%d", ports.RangeStart, ports.RangeEnd)
+		}
+
+		assignments = append(assignments, entry)
+	}
+	return assignments, nil
+}
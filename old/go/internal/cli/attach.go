@@ -60,7 +60,7 @@ If no target is specified:
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		var target string
 		if len(args) > 0 {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/db"
@@ -15,14 +16,24 @@ import (
 
 func init() {
 	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditActionsCmd)
 
 	auditCmd.Flags().StringVar(&auditEventTypes, "type", "", "filter by event type (comma-separated)")
 	auditCmd.Flags().StringVar(&auditActionTypes, "action", "", "alias for --type")
-	auditCmd.Flags().StringVar(&auditEntityType, "entity-type", "", "filter by entity type (node, workspace, agent, queue, account, system)")
+	auditCmd.Flags().StringVar(&auditEntityType, "entity-type", "", "filter by entity type (node, workspace, agent, queue, account, system, loop)")
 	auditCmd.Flags().StringVar(&auditEntityID, "entity-id", "", "filter by entity ID")
 	auditCmd.Flags().StringVar(&auditUntil, "until", "", "filter events before a time (same format as --since)")
 	auditCmd.Flags().StringVar(&auditCursor, "cursor", "", "start after this event ID")
 	auditCmd.Flags().IntVar(&auditLimit, "limit", 100, "max number of events to return")
+
+	auditActionsCmd.Flags().StringVar(&auditActor, "actor", "", "filter by actor")
+	auditActionsCmd.Flags().StringVar(&auditAction, "action", "", "filter by action prefix (e.g. \"loop\" or \"loop stop\")")
+	auditActionsCmd.Flags().StringVar(&auditSource, "source", "", "filter by source (cli, api)")
+	auditActionsCmd.Flags().StringVar(&auditResult, "result", "", "filter by result (ok, error)")
+	auditActionsCmd.Flags().StringVar(&auditRequestID, "request-id", "", "filter by request ID")
+	auditActionsCmd.Flags().StringVar(&auditUntil, "until", "", "filter actions before a time (same format as --since)")
+	auditActionsCmd.Flags().StringVar(&auditCursor, "cursor", "", "start after this entry ID")
+	auditActionsCmd.Flags().IntVar(&auditLimit, "limit", 100, "max number of entries to return")
 }
 
 var (
@@ -33,6 +44,12 @@ var (
 	auditUntil       string
 	auditCursor      string
 	auditLimit       int
+
+	auditActor     string
+	auditAction    string
+	auditSource    string
+	auditResult    string
+	auditRequestID string
 )
 
 var auditCmd = &cobra.Command{
@@ -143,3 +160,108 @@ Examples:
 		return nil
 	},
 }
+
+var auditActionsCmd = &cobra.Command{
+	Use:   "actions",
+	Short: "View mutating actions recorded in the audit log",
+	Long: `View who ran which mutating command, against what, and how it ended.
+
+Every state-changing CLI command is recorded with its actor (FORGE_ACTOR,
+or user@host), a hash of its arguments, its result, and its duration.
+Set FORGE_REQUEST_ID to group several commands under one request.
+
+Examples:
+  forge audit actions --since 1h
+  forge audit actions --action "loop stop" --result error
+  forge audit actions --actor ci-bot --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		since, err := GetSinceTime()
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		until, err := ParseSince(auditUntil)
+		if err != nil {
+			return fmt.Errorf("invalid --until value: %w", err)
+		}
+		if since != nil && until != nil && since.After(*until) {
+			return fmt.Errorf("--since must be before --until")
+		}
+
+		query := db.AuditQuery{
+			Actor:     strings.TrimSpace(auditActor),
+			Action:    strings.TrimSpace(auditAction),
+			RequestID: strings.TrimSpace(auditRequestID),
+			Since:     since,
+			Until:     until,
+			Cursor:    auditCursor,
+			Limit:     auditLimit,
+		}
+		if raw := strings.TrimSpace(auditSource); raw != "" {
+			source := models.AuditSource(raw)
+			if source != models.AuditSourceCLI && source != models.AuditSourceAPI {
+				return fmt.Errorf("invalid --source %q (expected cli or api)", raw)
+			}
+			query.Source = &source
+		}
+		if raw := strings.TrimSpace(auditResult); raw != "" {
+			result := models.AuditResult(raw)
+			if result != models.AuditResultOK && result != models.AuditResultError {
+				return fmt.Errorf("invalid --result %q (expected ok or error)", raw)
+			}
+			query.Result = &result
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		page, err := db.NewAuditRepository(database).Query(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, page.Entries)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "TIME\tACTOR\tSOURCE\tACTION\tTARGET\tRESULT\tDURATION")
+		for _, entry := range page.Entries {
+			target := entry.Target
+			if target == "" {
+				target = "-"
+			}
+			result := string(entry.Result)
+			if entry.Error != "" {
+				result += ": " + truncateString(strings.SplitN(entry.Error, "\n", 2)[0], 60)
+			}
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				entry.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+				entry.Actor,
+				entry.Source,
+				entry.Action,
+				target,
+				result,
+				entry.Duration.Round(time.Millisecond),
+			)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if page.NextCursor != "" {
+			fmt.Fprintf(os.Stdout, "\nNext cursor: %s\n", page.NextCursor)
+		}
+		if len(page.Entries) == 0 {
+			fmt.Fprintln(os.Stdout, "No actions matched the current filters.")
+		}
+		return nil
+	},
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	// auditActorEnv overrides the actor recorded in the audit log.
+	auditActorEnv = "FORGE_ACTOR"
+	// auditRequestIDEnv lets a caller correlate several commands under one
+	// request ID.
+	auditRequestIDEnv = "FORGE_REQUEST_ID"
+)
+
+// auditReadOnlyCommands never change state. A command is audited unless it
+// or one of its parents is listed here.
+var auditReadOnlyCommands = map[string]struct{}{
+	"artifacts":    {},
+	"attach":       {},
+	"audit":        {},
+	"beads-status": {},
+	"check":        {},
+	"completion":   {},
+	"context":      {},
+	"costs":        {},
+	"current":      {},
+	"doctor":       {},
+	"events":       {},
+	"explain":      {},
+	"export":       {},
+	"get":          {},
+	"help":         {},
+	"inbox":        {},
+	"ledger":       {},
+	"list":         {},
+	"logs":         {},
+	"ls":           {},
+	"path":         {},
+	"paths":        {},
+	"ps":           {},
+	"read":         {},
+	"resolve":      {},
+	"rollups":      {},
+	"show":         {},
+	"sinks":        {},
+	"status":       {},
+	"tui":          {},
+	"validate":     {},
+	"version":      {},
+	"wait":         {},
+	"__complete":   {},
+}
+
+func isAuditedCommand(cmd *cobra.Command) bool {
+	if cmd == nil || !cmd.Runnable() || !cmd.HasParent() {
+		return false
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if _, ok := auditReadOnlyCommands[c.Name()]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// recordCLIAudit writes an audit entry for a mutating command once it has
+// finished. Failures to record are logged and never change the command's
+// outcome.
+func recordCLIAudit(cmd *cobra.Command, start time.Time, runErr error) {
+	if robotHelp || appConfig == nil || !isAuditedCommand(cmd) {
+		return
+	}
+
+	args := cmd.Flags().Args()
+	entry := &models.AuditEntry{
+		Timestamp:   start,
+		RequestID:   strings.TrimSpace(os.Getenv(auditRequestIDEnv)),
+		Actor:       currentAuditActor(),
+		Source:      models.AuditSourceCLI,
+		Action:      strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		PayloadHash: auditPayloadHash(args, cmd.Flags()),
+		Result:      models.AuditResultOK,
+		Duration:    time.Since(start),
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = uuid.New().String()
+	}
+	if len(args) > 0 {
+		entry.Target = args[0]
+	}
+	var exitErr *ExitError
+	if runErr != nil && !(errors.As(runErr, &exitErr) && exitErr.Code == 0) {
+		entry.Result = models.AuditResultError
+		entry.Error = runErr.Error()
+	}
+
+	database, err := openDatabaseNoMigrate()
+	if err != nil {
+		logger.Debug().Err(err).Msg("audit log unavailable")
+		return
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := db.NewAuditRepository(database).Create(ctx, entry); err != nil {
+		logger.Debug().Err(err).Str("action", entry.Action).Msg("failed to record audit entry")
+	}
+}
+
+// currentAuditActor identifies who ran the command: FORGE_ACTOR when set, else
+// user@host.
+func currentAuditActor() string {
+	if actor := strings.TrimSpace(os.Getenv(auditActorEnv)); actor != "" {
+		return actor
+	}
+	name := strings.TrimSpace(os.Getenv("USER"))
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		name = current.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return name + "@" + host
+	}
+	return name
+}
+
+// auditPayloadHash hashes the positional args and explicitly set flags, so
+// identical invocations hash the same without storing their values.
+func auditPayloadHash(args []string, flags *pflag.FlagSet) string {
+	var changed []string
+	flags.Visit(func(flag *pflag.Flag) {
+		changed = append(changed, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	sort.Strings(changed)
+
+	hash := sha256.New()
+	for _, part := range append(append([]string{}, args...), changed...) {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
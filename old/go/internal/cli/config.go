@@ -5,20 +5,31 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/config"
 )
 
 var (
-	configInitForce bool
+	configInitForce      bool
+	configResolveEnv     string
+	configValidateEnv    string
+	configValidateStrict bool
 )
 
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configResolveCmd)
+	configCmd.AddCommand(configValidateCmd)
 
 	configInitCmd.Flags().BoolVarP(&configInitForce, "force", "f", false, "overwrite existing config file")
+	configResolveCmd.Flags().StringVar(&configResolveEnv, "env", "", "environment overlay to resolve (default: $FORGE_ENV)")
+	configValidateCmd.Flags().StringVar(&configValidateEnv, "env", "", "environment overlay to validate (default: $FORGE_ENV)")
+	configValidateCmd.Flags().BoolVar(&configValidateStrict, "strict", false, "treat unknown keys as errors (default: $FORGE_STRICT_CONFIG)")
 }
 
 var configCmd = &cobra.Command{
@@ -60,6 +71,270 @@ var configPathCmd = &cobra.Command{
 	},
 }
 
+var configResolveCmd = &cobra.Command{
+	Use:   "resolve [key-prefix...]",
+	Short: "Show the effective config and where each value comes from",
+	Long: `Load the config the way every command does and print each key with its
+effective value and source: default, file, overlay, env, or flag.
+
+Files are merged base first, then the environment overlay (config.<env>.yaml
+next to the base file, selected by --env or FORGE_ENV). ${VAR} and
+${VAR:-default} in string values are expanded from the environment. Keys
+holding tokens, passwords, or secrets are redacted.`,
+	Example: `  forge config resolve
+  forge config resolve --env prod database logging.level
+  forge config resolve --json`,
+	RunE: runConfigResolve,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config for errors",
+	Long: `Load the config and report every problem found instead of stopping at the
+first: files that do not parse, values of the wrong type, values out of
+range, keys that match no setting, and directories Forge cannot write to or
+that other users can write to.
+
+Unknown keys are reported as warnings unless --strict or FORGE_STRICT_CONFIG
+is set, in which case they fail validation just as they make every other
+command refuse to start. The command exits non-zero when any error is found.`,
+	Example: `  forge config validate
+  forge config validate --strict --env prod
+  forge --config ./config.yaml config validate --json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+// configValidateResult is the output of 'forge config validate'.
+type configValidateResult struct {
+	Valid       bool             `json:"valid"`
+	Strict      bool             `json:"strict"`
+	Environment string           `json:"environment,omitempty"`
+	Files       []string         `json:"files"`
+	Errors      []config.Problem `json:"errors"`
+	Warnings    []config.Problem `json:"warnings"`
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	loader := config.NewLoader()
+	if cfgFile != "" {
+		loader.SetConfigFile(cfgFile)
+	}
+	if configValidateEnv != "" {
+		loader.SetEnvironment(configValidateEnv)
+	}
+	if cmd.Flags().Changed("strict") {
+		loader.SetStrict(configValidateStrict)
+	}
+
+	result := configValidateResult{
+		Strict:      loader.Strict(),
+		Environment: loader.Environment(),
+		Errors:      []config.Problem{},
+		Warnings:    []config.Problem{},
+	}
+	problems := loader.Check()
+	unknown := make(map[config.Problem]bool)
+	if !result.Strict {
+		for _, problem := range loader.UnknownKeys() {
+			unknown[problem] = true
+		}
+	}
+	for _, problem := range problems {
+		if unknown[problem] {
+			result.Warnings = append(result.Warnings, problem)
+		} else {
+			result.Errors = append(result.Errors, problem)
+		}
+	}
+	result.Warnings = append(result.Warnings, loader.Deprecated()...)
+	result.Files = loader.Files()
+	if result.Files == nil {
+		result.Files = []string{}
+	}
+	result.Valid = len(result.Errors) == 0
+
+	if IsJSONOutput() || IsJSONLOutput() {
+		if err := WriteOutput(os.Stdout, result); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range result.Errors {
+			fmt.Printf("error: %s\n", problem)
+		}
+		for _, problem := range result.Warnings {
+			fmt.Printf("warning: %s\n", problem)
+		}
+		if result.Valid {
+			if len(result.Files) == 0 {
+				fmt.Println("Config valid (defaults only)")
+			} else {
+				fmt.Printf("Config valid: %s\n", strings.Join(result.Files, ", "))
+			}
+		}
+	}
+
+	if !result.Valid {
+		return &ExitError{Code: 1, Err: fmt.Errorf("config has %d error(s)", len(result.Errors)), Printed: true}
+	}
+	return nil
+}
+
+// invokesConfigValidate reports whether this process runs 'forge config
+// validate', which must start even when the config does not load.
+func invokesConfigValidate() bool {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	return err == nil && cmd == configValidateCmd
+}
+
+// configResolvedValue is one row of 'forge config resolve'.
+type configResolvedValue struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+type configResolveResult struct {
+	Environment string                `json:"environment,omitempty"`
+	Files       []string              `json:"files"`
+	Values      []configResolvedValue `json:"values"`
+}
+
+func runConfigResolve(cmd *cobra.Command, args []string) error {
+	loader := config.NewLoader()
+	if cfgFile != "" {
+		loader.SetConfigFile(cfgFile)
+	}
+	if configResolveEnv != "" {
+		loader.SetEnvironment(configResolveEnv)
+	}
+	if _, err := loader.Load(); err != nil {
+		return err
+	}
+
+	flagOverrides := map[string]string{}
+	flags := rootCmd.PersistentFlags()
+	if flags.Changed("log-level") {
+		flagOverrides["logging.level"] = logLevel
+	} else if verbose {
+		flagOverrides["logging.level"] = "debug"
+	}
+	if flags.Changed("log-format") {
+		flagOverrides["logging.format"] = logFormat
+	}
+
+	keys := loader.Viper().AllKeys()
+	sort.Strings(keys)
+	result := configResolveResult{
+		Environment: loader.Environment(),
+		Files:       loader.Files(),
+		Values:      make([]configResolvedValue, 0, len(keys)),
+	}
+	if result.Files == nil {
+		result.Files = []string{}
+	}
+	for _, key := range keys {
+		if !configKeyMatches(key, args) {
+			continue
+		}
+		entry := configResolvedValue{Key: key, Value: loader.Get(key), Source: loader.Source(key)}
+		if value, ok := flagOverrides[key]; ok {
+			entry.Value = value
+			entry.Source = "flag"
+		}
+		if isSecretConfigKey(key) && !isEmptyConfigValue(entry.Value) {
+			entry.Value = "<redacted>"
+		}
+		result.Values = append(result.Values, entry)
+	}
+
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, result)
+	}
+
+	environment := result.Environment
+	if environment == "" {
+		environment = "(none)"
+	}
+	fmt.Printf("Environment: %s\n", environment)
+	if len(result.Files) == 0 {
+		fmt.Println("Files: (none, defaults only)")
+	} else {
+		fmt.Println("Files (lowest precedence first):")
+		for _, file := range result.Files {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+	fmt.Println()
+
+	rows := make([][]string, 0, len(result.Values))
+	for _, entry := range result.Values {
+		rows = append(rows, []string{entry.Key, formatConfigValue(entry.Value), entry.Source})
+	}
+	return writeTable(os.Stdout, []string{"KEY", "VALUE", "SOURCE"}, rows)
+}
+
+// configKeyMatches reports whether key equals or sits below any prefix.
+func configKeyMatches(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		prefix = strings.ToLower(strings.TrimSuffix(prefix, "."))
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretConfigKey matches keys such as control_plane.token or
+// daemon_auth.tokens, but not token_file or token_reload_interval.
+func isSecretConfigKey(key string) bool {
+	name := strings.TrimSuffix(key[strings.LastIndex(key, ".")+1:], "s")
+	for _, marker := range []string{"token", "password", "secret", "api_key"} {
+		if name == marker || strings.HasSuffix(name, "_"+marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyConfigValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []string:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case []any, []string, map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
 type configInitResult struct {
 	Path    string `json:"path"`
 	Created bool   `json:"created"`
@@ -161,6 +436,38 @@ database:
   # Default: 5000
   # busy_timeout_ms: 5000
 
+  # SQLite journal mode (wal lets readers run alongside loop runners)
+  # Default: wal
+  # journal_mode: wal
+
+  # SQLite synchronous level
+  # Default: normal
+  # synchronous: normal
+
+  # Attempts for statements that still hit "database is locked"
+  # Default: 3
+  # busy_retries: 3
+
+  # Delay before the first busy retry (doubles per attempt)
+  # Default: 50ms
+  # busy_retry_backoff: 50ms
+
+  # Cache profile/pool/node lookups in memory (disable to debug stale reads)
+  # Default: true
+  # cache_enabled: true
+
+  # Maximum age of a cached lookup
+  # Default: 5s
+  # cache_ttl: 5s
+
+  # Open read-only and skip auto-migration (for readers beside the writer)
+  # Default: false
+  # read_only: false
+
+  # Tenant whose workspaces, agents, loops, and events commands use
+  # Default: default
+  # namespace: default
+
 # =============================================================================
 # Logging Settings
 # =============================================================================
@@ -194,6 +501,25 @@ loop_defaults:
   # Default base prompt message content
   # prompt_msg: ""
 
+  # End-of-day summary per loop (iterations, outcomes, commits, errors).
+  # Disable for a single loop with: forge up --no-daily-summary
+  # daily_summary:
+  #   enabled: false
+  #   time: "23:55"          # local HH:MM when the day closes
+  #   timezone: ""           # IANA zone, e.g. Europe/Oslo (default: system)
+  #   topic: loop-daily      # fmail topic; empty disables posting
+  #   write_report: true     # Markdown under {data_dir}/reports/daily
+
+  # Files copied into {data_dir}/artifacts/loops/<loop>/<run-id> after each
+  # iteration. Override per loop with: forge up --artifact <glob>
+  # artifacts:
+  #   globs: ["dist/**", "reports/*.xml"]
+
+  # Snapshot the repo before each iteration so the TUI can show what an
+  # iteration changed and forge loop rollback can undo it: off, commit, stash.
+  # Override per loop with: forge up --checkpoint <mode>
+  # checkpoint: off
+
 # =============================================================================
 # Scheduler Settings
 # =============================================================================
@@ -202,13 +528,20 @@ scheduler:
   # Default: 1s
   # dispatch_interval: 1s
 
-  # Maximum dispatch retry count
-  # Default: 3
-  # max_retries: 3
-
-  # Base backoff duration for retries
-  # Default: 5s
-  # retry_backoff: 5s
+  # Adaptive interval bounds: shortened under queue pressure, backed off
+  # exponentially while idle (the minimum is clamped to dispatch_interval)
+  # Default: 250ms / 10s
+  # min_dispatch_interval: 250ms
+  # max_dispatch_interval: 10s
+
+  # Retry policy per queue item type: immediate, exponential (backoff
+  # doubles per retry), or none. Items that exhaust their retries are
+  # dead-lettered; see 'forge agent dead-letters'. Replaces the deprecated
+  # max_retries and retry_backoff, which still fill in unset values.
+  # retry_policies:
+  #   message: { policy: exponential, max_retries: 3, backoff: 5s }
+  #   conditional: { policy: exponential, max_retries: 3, backoff: 5s }
+  #   pause: { policy: none }
 
   # Default cooldown after rate limiting
   # Default: 5m
@@ -218,6 +551,19 @@ scheduler:
   # Default: true
   # auto_rotate_on_rate_limit: true
 
+  # Recreate the tmux pane of agents whose session or pane disappeared
+  # Default: true
+  # auto_recover_agents: true
+
+  # Automatic recoveries per agent before it is left in the error state
+  # Default: 3
+  # max_recovery_attempts: 3
+
+  # Dispatch queue items with a deadline ahead of the policy order (and warn)
+  # once the deadline is this close; 0 disables
+  # Default: 5m
+  # deadline_warning: 5m
+
 # =============================================================================
 # TUI Settings
 # =============================================================================
@@ -238,6 +584,16 @@ tui:
   # Default: false
   # compact_mode: false
 
+  # Loop list columns, in order: status, id, name, runs, queue, last_run,
+  # harness, profile, pool, tags, cpu, dir
+  # Default: [status, id, runs, harness, cpu, dir]
+  # loop_columns: [status, name, runs, queue, last_run, pool, tags]
+
+  # Initial loop list order: created, status, runs, last_run, queue
+  # (O cycles it in the TUI)
+  # Default: created
+  # loop_sort: created
+
 # =============================================================================
 # Node Defaults (for remote SSH nodes)
 # =============================================================================
@@ -257,6 +613,15 @@ node_defaults:
   # Default: 60s
   # health_check_interval: 60s
 
+  # forged listener authentication (TLS, mutual TLS, bearer tokens).
+  # Not enforced yet: forged does not install these on its listeners.
+  # daemon_auth:
+  #   tls_cert_file: /etc/forge/forged.crt
+  #   tls_key_file: /etc/forge/forged.key
+  #   tls_client_ca_file: /etc/forge/clients-ca.crt
+  #   token_file: /etc/forge/forged.tokens
+  #   token_reload_interval: 10s
+
 # =============================================================================
 # Workspace Defaults
 # =============================================================================
@@ -273,6 +638,27 @@ workspace_defaults:
   # Default: false
   # auto_import_existing: false
 
+  # Directory for workspace snapshots (forge ws snapshot)
+  # Default: <data_dir>/snapshots
+  # snapshot_dir: ~/.local/share/forge/snapshots
+
+  # Snapshots kept per workspace; older ones are pruned (0 keeps all)
+  # Default: 10
+  # snapshot_retention: 10
+
+  # How long git status (branch, dirty files, ahead/behind) is cached before
+  # the repo is inspected again (forge ws status, loop TUI overview)
+  # Default: 30s
+  # git_status_interval: 30s
+
+  # Per-workspace resource quotas (0 = unlimited). Creating an agent or loop
+  # over quota fails and records a workspace.quota_exceeded event.
+  # Override per workspace with workspace_overrides[].quota.
+  # quota:
+  #   max_agents: 0
+  #   max_loops: 0
+  #   max_disk_mb: 0
+
 # =============================================================================
 # Agent Defaults
 # =============================================================================
@@ -297,6 +683,25 @@ agent_defaults:
   # Default: strict
   # approval_policy: strict
 
+  # Record agent panes to asciicast files (forged only)
+  # recording:
+  #   enabled: false
+  #   dir: ""              # Default: {data_dir}/recordings
+  #   interval: 500ms
+  #   max_size_mb: 50
+  #   max_duration: 1h
+  #   max_files: 20        # Per agent
+
+  # Harness heartbeats: wrappers write $FORGE_HEARTBEAT_FILE (or post via
+  # fmail as $FMAIL_AGENT); agents silent for longer than the timeout are
+  # marked stalled
+  # heartbeat:
+  #   enabled: true
+  #   dir: ""              # Default: {data_dir}/heartbeats
+  #   timeout: 5m          # 0 disables stall detection
+  #   harness_timeouts:    # Per agent type
+  #     codex: 15m
+
 # =============================================================================
 # Event Retention
 # =============================================================================
@@ -325,6 +730,27 @@ event_retention:
   # Default: 1000
   # batch_size: 1000
 
+# =============================================================================
+# Tracing (OpenTelemetry)
+# =============================================================================
+tracing:
+  # Export spans for scheduler ticks, dispatches, agent calls, tmux commands,
+  # and DB queries to an OTLP/HTTP collector
+  # Default: false
+  # enabled: false
+
+  # Collector base URL (/v1/traces is appended)
+  # Default: http://localhost:4318
+  # endpoint: http://localhost:4318
+
+  # Fraction of traces to record (0.0-1.0)
+  # Default: 1.0
+  # sample_ratio: 1.0
+
+  # Extra headers sent to the collector
+  # headers:
+  #   authorization: "Bearer <token>"
+
 # =============================================================================
 # Profiles (harness + auth combinations)
 # =============================================================================
@@ -15,6 +15,8 @@ import (
 	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/reconcile"
+	"github.com/tOgg1/forge/internal/tmux"
 )
 
 // DoctorCheckStatus indicates the result of a diagnostic check.
@@ -52,8 +54,11 @@ type DoctorSummary struct {
 	Skipped  int `json:"skipped"`
 }
 
+var doctorFix bool
+
 func init() {
 	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "repair drifted loop and agent state")
 }
 
 var doctorCmd = &cobra.Command{
@@ -65,8 +70,17 @@ Checks include:
 - Dependencies: tmux, opencode, ssh, git
 - Configuration: config file, database, migrations
 - Nodes: connectivity and health
-- Accounts: vault access and profiles`,
+- Accounts: vault access and profiles
+- Reconcile: loops and agents recorded as running whose runner process or
+  tmux pane is gone, and live runners or tagged panes the database does not
+  consider running
+
+Reconcile findings are reported without changes unless --fix is set. --fix
+stops loops whose runner died, relinks agents to their tagged pane, and
+marks agents whose pane is gone as errored; orphaned runners and panes are
+only reported.`,
 	Example: `  forge doctor
+  forge doctor --fix
   forge doctor --json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
@@ -83,9 +97,10 @@ Checks include:
 		dbChecks, database := checkDatabaseHealth()
 		checks = append(checks, dbChecks...)
 
-		// Node checks (only if DB is available)
+		// Node and reconcile checks (only if DB is available)
 		if database != nil {
 			checks = append(checks, checkNodes(ctx, database)...)
+			checks = append(checks, checkReconcile(ctx, database, doctorFix)...)
 			database.Close()
 		}
 
@@ -331,7 +346,16 @@ func checkDatabaseHealth() ([]DoctorCheck, *db.DB) {
 				pending++
 			}
 		}
-		if pending > 0 {
+		current, _ := database.SchemaVersion(ctx)
+		latest, _ := db.LatestSchemaVersion()
+		if current > latest {
+			checks = append(checks, DoctorCheck{
+				Category: "database",
+				Name:     "migrations",
+				Status:   DoctorWarn,
+				Details:  fmt.Sprintf("schema version %d is newer than this binary (%d); upgrade forge", current, latest),
+			})
+		} else if pending > 0 {
 			checks = append(checks, DoctorCheck{
 				Category: "database",
 				Name:     "migrations",
@@ -437,6 +461,76 @@ func checkNodes(ctx context.Context, database *db.DB) []DoctorCheck {
 	return checks
 }
 
+func checkReconcile(ctx context.Context, database *db.DB, fix bool) []DoctorCheck {
+	opts := []reconcile.Option{
+		reconcile.WithTmuxClient(tmux.NewLocalClient()),
+		reconcile.WithRunnerLiveness(daemonRunnerLiveness(ctx)),
+	}
+	// Only a repairing pass changes state worth an event.
+	if fix {
+		opts = append(opts, reconcile.WithPublisher(newEventPublisher(database)))
+	}
+
+	report, err := reconcile.New(database, opts...).Run(ctx, "doctor", fix)
+	if err != nil {
+		return []DoctorCheck{{
+			Category: "reconcile",
+			Name:     "state",
+			Status:   DoctorFail,
+			Error:    err.Error(),
+		}}
+	}
+	return reconcileChecks(report)
+}
+
+func reconcileChecks(report *reconcile.Report) []DoctorCheck {
+	checks := make([]DoctorCheck, 0, len(report.Findings)+2)
+	if report.TmuxError != "" {
+		checks = append(checks, DoctorCheck{
+			Category: "reconcile",
+			Name:     "tmux",
+			Status:   DoctorSkip,
+			Details:  "agent checks skipped",
+			Error:    report.TmuxError,
+		})
+	}
+
+	for _, finding := range report.Findings {
+		name := finding.Name
+		if name == "" {
+			name = finding.EntityID
+		}
+		check := DoctorCheck{
+			Category: "reconcile",
+			Name:     fmt.Sprintf("%s:%s", finding.Kind, name),
+			Status:   DoctorWarn,
+			Details:  finding.Details,
+		}
+		if finding.Fixed {
+			check.Status = DoctorPass
+			check.Details += " (fixed)"
+		}
+		checks = append(checks, check)
+	}
+
+	if len(report.Findings) == 0 {
+		checks = append(checks, DoctorCheck{
+			Category: "reconcile",
+			Name:     "state",
+			Status:   DoctorPass,
+			Details:  fmt.Sprintf("%d loop(s), %d agent(s), %d pane(s) in sync", report.LoopsChecked, report.AgentsChecked, report.PanesChecked),
+		})
+	} else if !report.Fix {
+		checks = append(checks, DoctorCheck{
+			Category: "reconcile",
+			Name:     "state",
+			Status:   DoctorWarn,
+			Details:  fmt.Sprintf("%d finding(s) (run 'forge doctor --fix')", len(report.Findings)),
+		})
+	}
+	return checks
+}
+
 func buildSummary(checks []DoctorCheck) DoctorSummary {
 	summary := DoctorSummary{Total: len(checks)}
 	for _, c := range checks {
@@ -460,7 +554,7 @@ func printDoctorReport(report *DoctorReport) {
 	fmt.Println()
 
 	// Group by category
-	categories := []string{"dependencies", "config", "database", "nodes"}
+	categories := []string{"dependencies", "config", "database", "nodes", "reconcile"}
 	categoryChecks := make(map[string][]DoctorCheck)
 	for _, c := range report.Checks {
 		categoryChecks[c.Category] = append(categoryChecks[c.Category], c)
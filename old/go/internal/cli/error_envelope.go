@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/workspace"
 )
 
 // ErrorEnvelope is the JSON/JSONL error response shape.
@@ -117,6 +120,19 @@ func classifyError(err error) (code, message, hint string, details map[string]an
 		return code, message, hint, details, 2
 	}
 
+	var quota *workspace.QuotaError
+	if errors.As(err, &quota) {
+		details = map[string]any{
+			"workspace_id": quota.WorkspaceID,
+			"resource":     string(quota.Resource),
+			"limit":        quota.Limit,
+			"current":      quota.Current,
+			"requested":    quota.Requested,
+		}
+		hint = "Free capacity in the workspace or raise its quota under workspace_defaults.quota or workspace_overrides."
+		return "ERR_QUOTA_EXCEEDED", message, hint, details, 2
+	}
+
 	lower := strings.ToLower(message)
 
 	switch {
@@ -141,6 +157,10 @@ func classifyError(err error) (code, message, hint string, details map[string]an
 		code = "ERR_INVALID_FLAG"
 	case strings.Contains(lower, "invalid") || strings.Contains(lower, "required") || strings.Contains(lower, "usage") || strings.Contains(lower, "must"):
 		code = "ERR_INVALID"
+	case db.IsReadOnlyError(err):
+		code = "ERR_READ_ONLY"
+		hint = "The database is open read-only (database.read_only or --read-only); run writes from the process that owns the writer."
+		exitCode = 2
 	case strings.Contains(lower, "permission denied") || strings.Contains(lower, "timeout") || strings.Contains(lower, "connection"):
 		code = "ERR_OPERATION_FAILED"
 		exitCode = 2
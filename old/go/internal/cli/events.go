@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.AddCommand(eventsPruneCmd)
+	eventsCmd.AddCommand(eventsRollupsCmd)
+	eventsCmd.AddCommand(eventsSearchCmd)
+
+	eventsPruneCmd.Flags().BoolVar(&eventsPruneDryRun, "dry-run", false, "report what would be deleted without deleting")
+
+	eventsRollupsCmd.Flags().StringVar(&eventsRollupsType, "type", "", "filter by event type")
+	eventsRollupsCmd.Flags().IntVar(&eventsRollupsLimit, "limit", 100, "max number of rows to return")
+
+	eventsSearchCmd.Flags().StringVar(&eventsSearchType, "type", "", "only search events of this type")
+	eventsSearchCmd.Flags().StringVar(&eventsSearchEntity, "entity", "", "only search events for this entity ID")
+	eventsSearchCmd.Flags().StringVar(&eventsSearchLoop, "loop", "", "only search output of this loop's runs")
+	eventsSearchCmd.Flags().BoolVar(&eventsSearchEvents, "events", false, "only search event payloads")
+	eventsSearchCmd.Flags().BoolVar(&eventsSearchRuns, "runs", false, "only search run output")
+	eventsSearchCmd.Flags().IntVar(&eventsSearchLimit, "limit", 20, "max matches of each kind")
+}
+
+var (
+	eventsPruneDryRun  bool
+	eventsRollupsType  string
+	eventsRollupsLimit int
+
+	eventsSearchType   string
+	eventsSearchEntity string
+	eventsSearchLoop   string
+	eventsSearchEvents bool
+	eventsSearchRuns   bool
+	eventsSearchLimit  int
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Manage the event log",
+	Long:  "Search the event log, compact it, and inspect summaries of compacted events.",
+}
+
+var eventsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Apply event retention now",
+	Long: `Apply the event_retention policy once: delete events older than their
+retention window (max_age, or the most specific type_max_age entry), then
+trim to max_count. Deleted events are archived and rolled up per day when
+archive_before_delete and rollup are set.
+
+Runs even when background retention is disabled.`,
+	Example: `  forge events prune --dry-run
+  forge events prune`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		cfg := GetConfig()
+		if cfg == nil {
+			cfg = config.DefaultConfig()
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		service := events.NewRetentionService(cfg, db.NewEventRepository(database))
+		report, err := service.Prune(ctx, eventsPruneDryRun)
+		if err != nil {
+			return fmt.Errorf("failed to prune events: %w", err)
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, report)
+		}
+
+		verb := "Deleted"
+		if report.DryRun {
+			verb = "Would delete"
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "RULE\tMAX AGE\tCUTOFF\tEVENTS")
+		for _, rule := range report.Rules {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d\n", rule.Rule, rule.MaxAge, rule.Cutoff.Format("2006-01-02 15:04:05"), rule.Events)
+		}
+		if cfg.EventRetention.MaxCount > 0 {
+			fmt.Fprintf(writer, "max_count=%d\t-\t-\t%d\n", cfg.EventRetention.MaxCount, report.ByCount)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("\n%s %d event(s)", verb, report.Deleted())
+		if report.Archived > 0 {
+			fmt.Printf(", archived %d", report.Archived)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var eventsRollupsCmd = &cobra.Command{
+	Use:   "rollups",
+	Short: "Show per-day counts of compacted events",
+	Example: `  forge events rollups --since 7d
+  forge events rollups --type agent.state_changed`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		since, err := GetSinceTime()
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		rollups, err := db.NewEventRepository(database).ListRollups(ctx, strings.TrimSpace(eventsRollupsType), since, eventsRollupsLimit)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, rollups)
+		}
+		if len(rollups) == 0 {
+			fmt.Println("No compacted events.")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "DAY\tTYPE\tENTITY\tCOUNT")
+		for _, rollup := range rollups {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d\n", rollup.Day, rollup.Type, rollup.EntityType, rollup.Count)
+		}
+		return writer.Flush()
+	},
+}
+
+// eventsSearchResult is the JSON shape of forge events search.
+type eventsSearchResult struct {
+	Events []db.EventSearchResult   `json:"events"`
+	Runs   []db.LoopRunSearchResult `json:"runs"`
+}
+
+var eventsSearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Full-text search over events and run output",
+	Long: `Search event payloads and loop run output through the full-text index.
+Every word must match; the last word also matches as a prefix. Punctuation
+is matched literally, so "agent.state_changed" finds that event type.
+
+Run output is indexed from each run's output tail, not the full log file.`,
+	Example: `  forge events search quota exceeded
+  forge events search panic --runs --loop my-loop
+  forge events search unreachable --type node.offline --since 24h`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		text := strings.Join(args, " ")
+		if eventsSearchEvents && eventsSearchRuns {
+			return fmt.Errorf("--events and --runs are mutually exclusive")
+		}
+		searchEvents := !eventsSearchRuns && strings.TrimSpace(eventsSearchLoop) == ""
+		searchRuns := !eventsSearchEvents && strings.TrimSpace(eventsSearchType) == "" && strings.TrimSpace(eventsSearchEntity) == ""
+
+		since, err := GetSinceTime()
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		result := eventsSearchResult{Events: []db.EventSearchResult{}, Runs: []db.LoopRunSearchResult{}}
+		if searchEvents {
+			query := db.EventSearchQuery{Text: text, Since: since, Limit: eventsSearchLimit}
+			if eventType := strings.TrimSpace(eventsSearchType); eventType != "" {
+				t := models.EventType(eventType)
+				query.Type = &t
+			}
+			if entity := strings.TrimSpace(eventsSearchEntity); entity != "" {
+				query.EntityID = &entity
+			}
+			if result.Events, err = db.NewEventRepository(database).Search(ctx, query); err != nil {
+				return err
+			}
+		}
+		if searchRuns {
+			loopID := ""
+			if ref := strings.TrimSpace(eventsSearchLoop); ref != "" {
+				loopEntry, err := resolveLoopByRef(ctx, db.NewLoopRepository(database), ref)
+				if err != nil {
+					return err
+				}
+				loopID = loopEntry.ID
+			}
+			query := db.LoopRunSearchQuery{Text: text, LoopID: loopID, Since: since, Limit: eventsSearchLimit}
+			if result.Runs, err = db.NewLoopRunRepository(database).SearchOutput(ctx, query); err != nil {
+				return err
+			}
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, result)
+		}
+		if len(result.Events) == 0 && len(result.Runs) == 0 {
+			fmt.Println("No matches.")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		if len(result.Events) > 0 {
+			fmt.Fprintln(writer, "TIME\tTYPE\tENTITY\tMATCH")
+			for _, match := range result.Events {
+				event := match.Event
+				fmt.Fprintf(writer, "%s\t%s\t%s/%s\t%s\n", event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.Type, event.EntityType, shortID(event.EntityID), searchSnippetLine(match.Snippet))
+			}
+		}
+		if len(result.Runs) > 0 {
+			if len(result.Events) > 0 {
+				fmt.Fprintln(writer)
+			}
+			fmt.Fprintln(writer, "STARTED\tRUN\tLOOP\tMATCH")
+			for _, match := range result.Runs {
+				run := match.Run
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", run.StartedAt.Local().Format("2006-01-02 15:04:05"), shortID(run.ID), shortID(run.LoopID), searchSnippetLine(match.Snippet))
+			}
+		}
+		return writer.Flush()
+	},
+}
+
+// searchSnippetLine flattens a multi-line snippet onto one table row.
+func searchSnippetLine(snippet string) string {
+	return strings.Join(strings.Fields(snippet), " ")
+}
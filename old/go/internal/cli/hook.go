@@ -32,7 +32,7 @@ func init() {
 	hookOnEventCmd.Flags().StringVar(&hookURL, "url", "", "webhook URL to POST matching events")
 	hookOnEventCmd.Flags().StringSliceVar(&hookHeaders, "header", nil, "webhook header (key=value)")
 	hookOnEventCmd.Flags().StringVar(&hookTypes, "type", "", "filter by event type (comma-separated)")
-	hookOnEventCmd.Flags().StringVar(&hookEntity, "entity-type", "", "filter by entity type (node, workspace, agent, queue, account, system)")
+	hookOnEventCmd.Flags().StringVar(&hookEntity, "entity-type", "", "filter by entity type (node, workspace, agent, queue, account, system, loop)")
 	hookOnEventCmd.Flags().StringVar(&hookEntityID, "entity-id", "", "filter by entity ID")
 	hookOnEventCmd.Flags().StringVar(&hookTimeout, "timeout", hooks.DefaultTimeout.String(), "hook execution timeout (0 to disable)")
 	hookOnEventCmd.Flags().BoolVar(&hookDisabled, "disabled", false, "register hook as disabled")
@@ -122,7 +122,7 @@ func parseEntityTypes(raw string) ([]models.EntityType, error) {
 	entity := models.EntityType(trimmed)
 	switch entity {
 	case models.EntityTypeNode, models.EntityTypeWorkspace, models.EntityTypeAgent,
-		models.EntityTypeQueue, models.EntityTypeAccount, models.EntityTypeSystem:
+		models.EntityTypeQueue, models.EntityTypeAccount, models.EntityTypeSystem, models.EntityTypeLoop:
 		return []models.EntityType{entity}, nil
 	default:
 		return nil, fmt.Errorf("invalid entity type: %s", trimmed)
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var loopArtifactsPath bool
+
+func init() {
+	loopInternalCmd.AddCommand(loopArtifactsCmd)
+
+	loopArtifactsCmd.Flags().BoolVar(&loopArtifactsPath, "path", false, "print only the run's artifact directory")
+}
+
+type loopArtifactsRunRow struct {
+	RunID       string    `json:"run_id"`
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"started_at"`
+	Files       int       `json:"files"`
+	Size        int64     `json:"size"`
+	ArtifactDir string    `json:"artifact_dir"`
+}
+
+var loopArtifactsCmd = &cobra.Command{
+	Use:   "artifacts <loop> [run-id|latest]",
+	Short: "List artifacts captured from loop runs",
+	Long: `List artifacts captured after loop iterations.
+
+Loops capture files matching the globs given with ` + "`forge up --artifact`" + ` (or
+loop_defaults.artifacts.globs) into a per-run directory under the data dir.
+Without a run, list runs that captured artifacts; with a run ID (or prefix)
+or "latest", list that run's files.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ctx := context.Background()
+		loopEntry, err := resolveLoopByRef(ctx, db.NewLoopRepository(database), args[0])
+		if err != nil {
+			return err
+		}
+		runs, err := db.NewLoopRunRepository(database).ListByLoop(ctx, loopEntry.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			if loopArtifactsPath {
+				return fmt.Errorf("--path requires a run")
+			}
+			return writeLoopArtifactRuns(loopEntry, runs)
+		}
+
+		run, err := resolveArtifactRun(runs, args[1])
+		if err != nil {
+			return err
+		}
+		if loopArtifactsPath {
+			if run.ArtifactDir == "" {
+				return fmt.Errorf("run %s captured no artifacts", shortID(run.ID))
+			}
+			fmt.Fprintln(os.Stdout, run.ArtifactDir)
+			return nil
+		}
+		return writeLoopArtifactFiles(run)
+	},
+}
+
+func writeLoopArtifactRuns(loopEntry *models.Loop, runs []*models.LoopRun) error {
+	rows := make([]loopArtifactsRunRow, 0, len(runs))
+	for _, run := range runs {
+		if len(run.Artifacts) == 0 {
+			continue
+		}
+		rows = append(rows, loopArtifactsRunRow{
+			RunID:       run.ID,
+			Status:      string(run.Status),
+			StartedAt:   run.StartedAt,
+			Files:       len(run.Artifacts),
+			Size:        artifactsSize(run.Artifacts),
+			ArtifactDir: run.ArtifactDir,
+		})
+	}
+
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, rows)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(os.Stdout, "No artifacts captured for loop '%s'\n", loopEntry.Name)
+		return nil
+	}
+	table := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		table = append(table, []string{
+			shortID(row.RunID),
+			row.Status,
+			row.StartedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(row.Files),
+			formatByteSize(row.Size),
+			row.ArtifactDir,
+		})
+	}
+	return writeTable(os.Stdout, []string{"RUN", "STATUS", "STARTED", "FILES", "SIZE", "DIR"}, table)
+}
+
+func writeLoopArtifactFiles(run *models.LoopRun) error {
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, map[string]any{
+			"run_id":       run.ID,
+			"artifact_dir": run.ArtifactDir,
+			"artifacts":    run.Artifacts,
+		})
+	}
+	if len(run.Artifacts) == 0 {
+		fmt.Fprintf(os.Stdout, "Run %s captured no artifacts\n", shortID(run.ID))
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "Artifacts for run %s in %s\n", shortID(run.ID), run.ArtifactDir)
+	table := make([][]string, 0, len(run.Artifacts))
+	for _, artifact := range run.Artifacts {
+		table = append(table, []string{filepath.FromSlash(artifact.Path), formatByteSize(artifact.Size)})
+	}
+	return writeTable(os.Stdout, []string{"PATH", "SIZE"}, table)
+}
+
+// resolveArtifactRun picks a run by ID or unique ID prefix. "latest" is the
+// most recent run that captured artifacts.
+func resolveArtifactRun(runs []*models.LoopRun, ref string) (*models.LoopRun, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "latest" {
+		for _, run := range runs {
+			if len(run.Artifacts) > 0 {
+				return run, nil
+			}
+		}
+		return nil, fmt.Errorf("no runs with artifacts")
+	}
+
+	var match *models.LoopRun
+	for _, run := range runs {
+		if run.ID == ref {
+			return run, nil
+		}
+		if ref != "" && strings.HasPrefix(run.ID, ref) {
+			if match != nil {
+				return nil, fmt.Errorf("run prefix %q is ambiguous", ref)
+			}
+			match = run
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("run not found: %s", ref)
+	}
+	return match, nil
+}
+
+func artifactsSize(artifacts []models.LoopRunArtifact) int64 {
+	var total int64
+	for _, artifact := range artifacts {
+		total += artifact.Size
+	}
+	return total
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	loopRollbackDiff  bool
+	loopRollbackForce bool
+)
+
+func init() {
+	loopInternalCmd.AddCommand(loopRollbackCmd)
+
+	loopRollbackCmd.Flags().BoolVar(&loopRollbackDiff, "diff", false, "show changes since the checkpoint instead of rolling back")
+	loopRollbackCmd.Flags().BoolVar(&loopRollbackForce, "force", false, "roll back even if the loop is not stopped")
+}
+
+var loopRollbackCmd = &cobra.Command{
+	Use:   "rollback <loop> [run-id|latest]",
+	Short: "Restore the repo to a loop run's checkpoint",
+	Long: `Restore a loop's repo to the git checkpoint taken before a run.
+
+Loops started with ` + "`forge up --checkpoint commit|stash`" + ` (or
+loop_defaults.checkpoint) snapshot the repo before every iteration. Rollback
+makes the working tree and index match the snapshot of the given run (ID,
+unique ID prefix, or "latest"); HEAD is not moved. Uncommitted work is saved
+as a stash entry first.
+
+With --diff, print the changes made since the checkpoint instead: up to the
+next run's checkpoint, or up to the working tree for the latest run.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ctx := context.Background()
+		loopEntry, err := resolveLoopByRef(ctx, db.NewLoopRepository(database), args[0])
+		if err != nil {
+			return err
+		}
+		runs, err := db.NewLoopRunRepository(database).ListByLoop(ctx, loopEntry.ID)
+		if err != nil {
+			return err
+		}
+		ref := "latest"
+		if len(args) == 2 {
+			ref = args[1]
+		}
+		run, checkpoint, err := resolveCheckpointRun(runs, ref)
+		if err != nil {
+			return err
+		}
+
+		if loopRollbackDiff {
+			diff, err := loop.CheckpointDiff(ctx, loopEntry.RepoPath, checkpoint, loop.NextRunCheckpoint(runs, run.ID))
+			if err != nil {
+				return err
+			}
+			if IsJSONOutput() || IsJSONLOutput() {
+				return WriteOutput(os.Stdout, map[string]any{
+					"run_id":     run.ID,
+					"checkpoint": checkpoint,
+					"diff":       diff,
+				})
+			}
+			fmt.Fprint(os.Stdout, diff)
+			return nil
+		}
+
+		if loopEntry.State != models.LoopStateStopped && loopEntry.State != models.LoopStateError && !loopRollbackForce {
+			return fmt.Errorf("loop '%s' is %s; stop it first or pass --force", loopEntry.Name, loopEntry.State)
+		}
+		impact := fmt.Sprintf("This will reset the working tree of %s to the checkpoint %s taken before run %s. Uncommitted changes are stashed first.",
+			loopEntry.RepoPath, shortID(checkpoint.SHA), shortID(run.ID))
+		if !ConfirmDestructiveAction("loop checkpoint", loopEntry.Name, impact) {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+
+		backup, err := loop.RestoreCheckpoint(ctx, loopEntry.RepoPath, checkpoint.SHA)
+		if err != nil {
+			return err
+		}
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{
+				"loop_id":    loopEntry.ID,
+				"run_id":     run.ID,
+				"checkpoint": checkpoint,
+				"backup":     backup,
+			})
+		}
+		fmt.Fprintf(os.Stdout, "Rolled back '%s' to checkpoint %s (run %s)\n", loopEntry.Name, shortID(checkpoint.SHA), shortID(run.ID))
+		if backup != "" {
+			fmt.Fprintf(os.Stdout, "Previous changes saved as %s\n", backup)
+		}
+		return nil
+	},
+}
+
+// resolveCheckpointRun picks a run by ID or unique ID prefix. "latest" is the
+// most recent run with a checkpoint.
+func resolveCheckpointRun(runs []*models.LoopRun, ref string) (*models.LoopRun, models.LoopCheckpoint, error) {
+	if strings.TrimSpace(ref) == "latest" {
+		for _, run := range runs {
+			if checkpoint, ok := loop.RunCheckpoint(run); ok {
+				return run, checkpoint, nil
+			}
+		}
+		return nil, models.LoopCheckpoint{}, fmt.Errorf("no runs with checkpoints")
+	}
+	run, err := resolveArtifactRun(runs, ref)
+	if err != nil {
+		return nil, models.LoopCheckpoint{}, err
+	}
+	checkpoint, ok := loop.RunCheckpoint(run)
+	if !ok {
+		if checkpoint.Error != "" {
+			return nil, models.LoopCheckpoint{}, fmt.Errorf("run %s checkpoint failed: %s", shortID(run.ID), checkpoint.Error)
+		}
+		return nil, models.LoopCheckpoint{}, fmt.Errorf("run %s has no checkpoint", shortID(run.ID))
+	}
+	return run, checkpoint, nil
+}
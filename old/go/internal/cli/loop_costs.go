@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	loopCostsBy    string
+	loopCostsSince string
+)
+
+func init() {
+	loopInternalCmd.AddCommand(loopCostsCmd)
+
+	loopCostsCmd.Flags().StringVar(&loopCostsBy, "by", "loop", "group by loop or profile")
+	loopCostsCmd.Flags().StringVar(&loopCostsSince, "since", "", "only runs started since duration or timestamp (e.g., 24h)")
+}
+
+type loopCostsReport struct {
+	By    string                     `json:"by"`
+	Rows  []loopCostsRow             `json:"rows"`
+	Total models.LoopRunUsageSummary `json:"total"`
+}
+
+type loopCostsRow struct {
+	Name string `json:"name"`
+	models.LoopRunUsageSummary
+}
+
+var loopCostsCmd = &cobra.Command{
+	Use:   "costs",
+	Short: "Report token usage and cost per loop or profile",
+	Long: `Report token usage and cost recorded on loop runs, grouped per loop or per profile.
+
+Usage is parsed from harness output when the harness reports it (Claude JSON
+output, OpenAI-style usage objects, Codex "tokens used"); runs without a report
+count toward RUNS but not toward the token and cost totals.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by := strings.ToLower(strings.TrimSpace(loopCostsBy))
+		if by != "loop" && by != "profile" {
+			return fmt.Errorf("--by must be loop or profile")
+		}
+		since, err := parseSince(loopCostsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ctx := context.Background()
+		report, err := buildLoopCostsReport(ctx, database, by, since)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, report)
+		}
+		if len(report.Rows) == 0 {
+			fmt.Fprintln(os.Stdout, "No loop runs found")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(report.Rows)+1)
+		for _, row := range report.Rows {
+			rows = append(rows, loopCostsTableRow(row.Name, row.LoopRunUsageSummary))
+		}
+		rows = append(rows, loopCostsTableRow("TOTAL", report.Total))
+		return writeTable(os.Stdout, []string{strings.ToUpper(by), "RUNS", "WITH USAGE", "INPUT", "OUTPUT", "TOTAL", "COST"}, rows)
+	},
+}
+
+func buildLoopCostsReport(ctx context.Context, database *db.DB, by string, since time.Time) (loopCostsReport, error) {
+	runRepo := db.NewLoopRunRepository(database)
+
+	var (
+		summaries []models.LoopRunUsageSummary
+		names     = map[string]string{}
+		err       error
+	)
+	if by == "profile" {
+		summaries, err = runRepo.UsageByProfile(ctx, since)
+		if err != nil {
+			return loopCostsReport{}, err
+		}
+		profiles, err := db.NewProfileRepository(database).List(ctx)
+		if err != nil {
+			return loopCostsReport{}, err
+		}
+		for _, profile := range profiles {
+			names[profile.ID] = profile.Name
+		}
+	} else {
+		summaries, err = runRepo.UsageByLoop(ctx, since)
+		if err != nil {
+			return loopCostsReport{}, err
+		}
+		loops, err := db.NewLoopRepository(database).List(ctx)
+		if err != nil {
+			return loopCostsReport{}, err
+		}
+		for _, loopEntry := range loops {
+			names[loopEntry.ID] = loopEntry.Name
+		}
+	}
+
+	report := loopCostsReport{By: by, Rows: make([]loopCostsRow, 0, len(summaries))}
+	for _, summary := range summaries {
+		name := names[summary.Key]
+		switch {
+		case name != "":
+		case summary.Key == "":
+			name = "(none)"
+		default:
+			name = summary.Key
+		}
+		report.Rows = append(report.Rows, loopCostsRow{Name: name, LoopRunUsageSummary: summary})
+
+		report.Total.Runs += summary.Runs
+		report.Total.RunsWithUsage += summary.RunsWithUsage
+		report.Total.InputTokens += summary.InputTokens
+		report.Total.OutputTokens += summary.OutputTokens
+		report.Total.TotalTokens += summary.TotalTokens
+		report.Total.CostUSD += summary.CostUSD
+	}
+	return report, nil
+}
+
+func loopCostsTableRow(name string, summary models.LoopRunUsageSummary) []string {
+	return []string{
+		name,
+		strconv.Itoa(summary.Runs),
+		strconv.Itoa(summary.RunsWithUsage),
+		strconv.FormatInt(summary.InputTokens, 10),
+		strconv.FormatInt(summary.OutputTokens, 10),
+		strconv.FormatInt(summary.TotalTokens, 10),
+		fmt.Sprintf("$%.4f", summary.CostUSD),
+	}
+}
@@ -11,10 +11,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/workspace"
 )
 
+// checkLoopQuota rejects creating count loops in repoPath when a workspace
+// on that repo would exceed its loop or disk quota.
+func checkLoopQuota(ctx context.Context, database *db.DB, repoPath string, count int) error {
+	nodeService := node.NewService(db.NewNodeRepository(database), node.WithPublisher(newEventPublisher(database)))
+	wsService := workspace.NewService(db.NewWorkspaceRepository(database), nodeService, db.NewAgentRepository(database), workspaceServiceOptions(database)...)
+	return wsService.CheckLoopQuota(ctx, repoPath, count)
+}
+
 type loopSelector struct {
 	All     bool
 	LoopRef string
@@ -114,6 +126,96 @@ func parseDuration(value string, fallback time.Duration) (time.Duration, error)
 	return parsed, nil
 }
 
+// buildLoopHooks combines --pre-run-hook/--post-run-hook/--hook-timeout with
+// loop_defaults.hooks; flags win over config.
+func buildLoopHooks(defaults config.LoopHooksConfig, preRun, postRun, timeout string) (models.LoopHooksConfig, error) {
+	hooks := models.LoopHooksConfig{
+		PreRun:  strings.TrimSpace(defaults.PreRun),
+		PostRun: strings.TrimSpace(defaults.PostRun),
+	}
+	if value := strings.TrimSpace(preRun); value != "" {
+		hooks.PreRun = value
+	}
+	if value := strings.TrimSpace(postRun); value != "" {
+		hooks.PostRun = value
+	}
+	parsed, err := parseDuration(timeout, defaults.Timeout)
+	if err != nil {
+		return models.LoopHooksConfig{}, err
+	}
+	if parsed < 0 {
+		return models.LoopHooksConfig{}, fmt.Errorf("hook timeout must be >= 0")
+	}
+	if !hooks.IsZero() {
+		hooks.TimeoutSeconds = int(parsed.Round(time.Second).Seconds())
+	}
+	return hooks, nil
+}
+
+// buildLoopCheckpoint uses --checkpoint when given, otherwise
+// loop_defaults.checkpoint.
+func buildLoopCheckpoint(defaultMode, flagMode string) (models.LoopCheckpointMode, error) {
+	value := defaultMode
+	if strings.TrimSpace(flagMode) != "" {
+		value = flagMode
+	}
+	return models.ParseLoopCheckpointMode(value)
+}
+
+// buildLoopArtifacts uses --artifact globs when given, otherwise
+// loop_defaults.artifacts.
+func buildLoopArtifacts(defaults config.LoopArtifactsConfig, flagGlobs []string) models.LoopArtifactsConfig {
+	source := defaults.Globs
+	if len(flagGlobs) > 0 {
+		source = flagGlobs
+	}
+	artifacts := models.LoopArtifactsConfig{}
+	for _, glob := range source {
+		if value := strings.TrimSpace(glob); value != "" {
+			artifacts.Globs = append(artifacts.Globs, value)
+		}
+	}
+	return artifacts
+}
+
+// buildPromptPlaylist resolves --prompt-playlist entries the same way as
+// --prompt, keeping their order.
+func buildPromptPlaylist(repoPath string, refs []string, strategy string) (models.LoopPromptPlaylist, error) {
+	if !models.ValidPromptStrategy(strategy) {
+		return models.LoopPromptPlaylist{}, fmt.Errorf("--prompt-strategy must be sequential or random")
+	}
+	playlist := models.LoopPromptPlaylist{}
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		resolved, _, err := resolvePromptPath(repoPath, ref)
+		if err != nil {
+			return models.LoopPromptPlaylist{}, err
+		}
+		playlist.Prompts = append(playlist.Prompts, resolved)
+	}
+	if !playlist.IsZero() {
+		playlist.Strategy = playlist.NormalizedStrategy()
+	}
+	return playlist, nil
+}
+
+// parseNotBefore resolves --at/--delay into an earliest-dispatch time.
+func parseNotBefore(at, delay string, now time.Time) (*time.Time, error) {
+	at = strings.TrimSpace(at)
+	delay = strings.TrimSpace(delay)
+	switch {
+	case at != "" && delay != "":
+		return nil, fmt.Errorf("use either --at or --delay, not both")
+	case delay != "":
+		return loop.ParseNotBefore("+"+delay, now)
+	default:
+		return loop.ParseNotBefore(at, now)
+	}
+}
+
 func durationSecondsCeil(value time.Duration) int {
 	if value <= 0 {
 		return 0
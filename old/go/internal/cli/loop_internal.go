@@ -18,8 +18,8 @@ func init() {
 }
 
 var loopInternalCmd = &cobra.Command{
-	Use:    "loop",
-	Hidden: true,
+	Use:   "loop",
+	Short: "Loop utilities (templates, costs, artifacts, ledger)",
 }
 
 var loopRunCmd = &cobra.Command{
@@ -33,11 +33,16 @@ var loopRunCmd = &cobra.Command{
 		}
 		defer database.Close()
 
+		// The spawning command already resolved the loop in its namespace
+		// (the daemon does not pass one on), so look it up in any namespace
+		// and then work in the loop's own.
+		database.SetNamespace("")
 		loopRepo := db.NewLoopRepository(database)
 		loopEntry, err := resolveLoopByRef(context.Background(), loopRepo, args[0])
 		if err != nil {
 			return err
 		}
+		database.SetNamespace(loopEntry.Namespace)
 
 		runner := loop.NewRunner(database, GetConfig())
 
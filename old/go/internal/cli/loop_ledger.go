@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	loopLedgerIteration string
+	loopLedgerTypes     []string
+	loopLedgerSince     string
+	loopLedgerUntil     string
+	loopLedgerLimit     int
+	loopLedgerOutput    bool
+	loopLedgerSummary   bool
+)
+
+func init() {
+	loopInternalCmd.AddCommand(loopLedgerCmd)
+
+	loopLedgerCmd.Flags().StringVar(&loopLedgerIteration, "iteration", "", "iteration number or range (e.g., 3, 3-7, 10-)")
+	loopLedgerCmd.Flags().StringSliceVar(&loopLedgerTypes, "type", nil, "run status to keep: success, error, killed, running (repeatable)")
+	loopLedgerCmd.Flags().StringVar(&loopLedgerSince, "since", "", "only entries since duration or timestamp (e.g., 24h)")
+	loopLedgerCmd.Flags().StringVar(&loopLedgerUntil, "until", "", "only entries before duration ago or timestamp")
+	loopLedgerCmd.Flags().IntVar(&loopLedgerLimit, "limit", 0, "show only the newest N matching entries")
+	loopLedgerCmd.Flags().BoolVar(&loopLedgerOutput, "output", false, "include each entry's output tail")
+	loopLedgerCmd.Flags().BoolVar(&loopLedgerSummary, "summary", false, "print counts per status instead of entries")
+}
+
+var loopLedgerCmd = &cobra.Command{
+	Use:   "ledger <loop>",
+	Short: "Query a loop's ledger",
+	Long: `Query the ledger the loop runner appends to after every iteration
+(.forge/ledgers/<loop>.md in the repo).
+
+Entries are numbered by iteration in file order and can be filtered by
+iteration range, run status (--type), and time range.`,
+	Example: `  forge loop ledger build
+  forge loop ledger build --iteration 10-20 --type error --output
+  forge loop ledger build --since 24h --summary
+  forge loop ledger build --limit 5 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := buildLoopLedgerQuery()
+		if err != nil {
+			return err
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		loopEntry, err := resolveLoopByRef(context.Background(), db.NewLoopRepository(database), args[0])
+		if err != nil {
+			return err
+		}
+		if loopEntry.LedgerPath == "" {
+			return fmt.Errorf("loop '%s' has no ledger", loopEntry.Name)
+		}
+		ledger, err := loop.ReadLedger(loopEntry.LedgerPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("loop '%s' has not written its ledger yet (%s)", loopEntry.Name, loopEntry.LedgerPath)
+			}
+			return err
+		}
+
+		entries := ledger.Query(query)
+		if loopLedgerSummary {
+			return writeLoopLedgerSummary(loopEntry, loop.SummarizeLedger(entries))
+		}
+		return writeLoopLedgerEntries(loopEntry, entries)
+	},
+}
+
+func buildLoopLedgerQuery() (loop.LedgerQuery, error) {
+	var query loop.LedgerQuery
+	from, to, err := parseIterationRange(loopLedgerIteration)
+	if err != nil {
+		return query, fmt.Errorf("invalid --iteration: %w", err)
+	}
+	query.FromIteration, query.ToIteration = from, to
+
+	for _, value := range loopLedgerTypes {
+		status := models.LoopRunStatus(strings.ToLower(strings.TrimSpace(value)))
+		switch status {
+		case models.LoopRunStatusSuccess, models.LoopRunStatusError, models.LoopRunStatusKilled, models.LoopRunStatusRunning:
+			query.Statuses = append(query.Statuses, status)
+		default:
+			return query, fmt.Errorf("invalid --type %q (success, error, killed, running)", value)
+		}
+	}
+
+	if query.Since, err = parseSince(loopLedgerSince); err != nil {
+		return query, fmt.Errorf("invalid --since: %w", err)
+	}
+	if query.Until, err = parseSince(loopLedgerUntil); err != nil {
+		return query, fmt.Errorf("invalid --until: %w", err)
+	}
+	if loopLedgerLimit < 0 {
+		return query, fmt.Errorf("--limit must be zero or greater")
+	}
+	query.Limit = loopLedgerLimit
+	return query, nil
+}
+
+// parseIterationRange parses "N", "A-B", "A-", or "-B". Zero bounds are open.
+func parseIterationRange(value string) (int, int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, 0, nil
+	}
+	fromText, toText, isRange := strings.Cut(value, "-")
+	if !isRange {
+		toText = fromText
+	}
+	bound := func(text string) (int, error) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%q is not an iteration number", text)
+		}
+		return n, nil
+	}
+	from, err := bound(fromText)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := bound(toText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from > 0 && to > 0 && from > to {
+		return 0, 0, fmt.Errorf("range %d-%d is reversed", from, to)
+	}
+	return from, to, nil
+}
+
+func writeLoopLedgerEntries(loopEntry *models.Loop, entries []loop.LedgerEntry) error {
+	if IsJSONOutput() || IsJSONLOutput() {
+		if !loopLedgerOutput {
+			for i := range entries {
+				entries[i].Output = ""
+			}
+		}
+		return WriteOutput(os.Stdout, entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stdout, "No ledger entries match for loop '%s'\n", loopEntry.Name)
+		return nil
+	}
+
+	if !loopLedgerOutput {
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, loopLedgerRow(entry))
+		}
+		return writeTable(os.Stdout, []string{"ITER", "TIME", "STATUS", "EXIT", "PROFILE", "PROMPT", "RUN"}, rows)
+	}
+
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Fprintln(os.Stdout)
+		}
+		row := loopLedgerRow(entry)
+		fmt.Fprintf(os.Stdout, "#%s %s status=%s exit=%s profile=%s prompt=%s run=%s\n", row[0], row[1], row[2], row[3], row[4], row[5], row[6])
+		if strings.TrimSpace(entry.Output) != "" {
+			fmt.Fprintln(os.Stdout, entry.Output)
+		}
+	}
+	return nil
+}
+
+func loopLedgerRow(entry loop.LedgerEntry) []string {
+	exitCode := "-"
+	if entry.ExitCode != nil {
+		exitCode = strconv.Itoa(*entry.ExitCode)
+	}
+	prompt := ledgerField(entry.PromptSource)
+	if entry.PromptOverride {
+		prompt += " (override)"
+	}
+	return []string{
+		strconv.Itoa(entry.Iteration),
+		entry.Timestamp.UTC().Format(time.RFC3339),
+		ledgerField(string(entry.Status)),
+		exitCode,
+		ledgerField(entry.Profile),
+		prompt,
+		ledgerField(shortID(entry.RunID)),
+	}
+}
+
+func writeLoopLedgerSummary(loopEntry *models.Loop, summary loop.LedgerSummary) error {
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, summary)
+	}
+	if summary.Entries == 0 {
+		fmt.Fprintf(os.Stdout, "No ledger entries match for loop '%s'\n", loopEntry.Name)
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "Loop: %s\n", loopEntry.Name)
+	fmt.Fprintf(os.Stdout, "Entries: %d (%s .. %s)\n", summary.Entries, summary.First.UTC().Format(time.RFC3339), summary.Last.Timestamp.UTC().Format(time.RFC3339))
+	for _, status := range []models.LoopRunStatus{models.LoopRunStatusSuccess, models.LoopRunStatusError, models.LoopRunStatusKilled, models.LoopRunStatusRunning} {
+		if count := summary.ByStatus[status]; count > 0 {
+			fmt.Fprintf(os.Stdout, "  %-8s %d\n", status, count)
+		}
+	}
+	fmt.Fprintf(os.Stdout, "Last: iteration %d %s\n", summary.Last.Iteration, ledgerField(string(summary.Last.Status)))
+	return nil
+}
+
+func ledgerField(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
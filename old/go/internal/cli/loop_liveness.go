@@ -9,6 +9,7 @@ import (
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/procutil"
+	"github.com/tOgg1/forge/internal/reconcile"
 )
 
 type loopRunnerLiveness struct {
@@ -79,26 +80,7 @@ func shouldMarkLoopStale(loopEntry *models.Loop, info loopRunnerLiveness, daemon
 }
 
 func markLoopStale(ctx context.Context, loopRepo *db.LoopRepository, loopEntry *models.Loop, info loopRunnerLiveness) error {
-	if loopEntry == nil {
-		return nil
-	}
-
-	loopEntry.State = models.LoopStateStopped
-	loopEntry.LastError = loopStaleRunnerReason
-	if loopEntry.Metadata == nil {
-		loopEntry.Metadata = make(map[string]any)
-	}
-	loopEntry.Metadata[loopMetadataRunnerLivenessKey] = map[string]any{
-		"pid_alive":           boolPtrValue(info.PIDAlive),
-		"daemon_runner_alive": boolPtrValue(info.DaemonAlive),
-		"reconciled_at":       time.Now().UTC().Format(time.RFC3339),
-		"reason":              loopStaleRunnerReason,
-	}
-
-	if err := loopRepo.Update(ctx, loopEntry); err != nil {
-		return err
-	}
-	return nil
+	return reconcile.MarkLoopStale(ctx, loopRepo, loopEntry, boolPtrValue(info.PIDAlive), boolPtrValue(info.DaemonAlive))
 }
 
 func listDaemonRunners(parent context.Context) (map[string]*forgedv1.LoopRunner, bool) {
@@ -126,6 +108,18 @@ func listDaemonRunners(parent context.Context) (map[string]*forgedv1.LoopRunner,
 	return result, true
 }
 
+// daemonRunnerLiveness asks the daemon for its runners once and answers
+// reconcile liveness checks from that snapshot.
+func daemonRunnerLiveness(ctx context.Context) reconcile.RunnerLiveness {
+	runners, reachable := listDaemonRunnersFunc(ctx)
+	return func(_ context.Context, loopEntry *models.Loop) (bool, bool) {
+		if !reachable {
+			return false, false
+		}
+		return daemonRunnerAlive(runners[loopEntry.ID], loopRunnerInstanceID(loopEntry)), true
+	}
+}
+
 func daemonRunnerAlive(runner *forgedv1.LoopRunner, instanceID string) bool {
 	if runner == nil {
 		return false
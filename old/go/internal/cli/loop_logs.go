@@ -120,10 +120,13 @@ func readLog(path string, lines int, since string) (string, error) {
 	highlighter := newLogHighlighter()
 	for scanner.Scan() {
 		line := scanner.Text()
-		if !parsedSince.IsZero() {
-			if ts, ok := parseLogTimestamp(line); ok && ts.Before(parsedSince) {
-				continue
-			}
+		ts, hasTS := parseLogTimestamp(line)
+		if rec, ok := loop.ParseLogRecord(line); ok {
+			line = loop.FormatLogRecord(rec)
+			ts, hasTS = rec.Time, true
+		}
+		if !parsedSince.IsZero() && hasTS && ts.Before(parsedSince) {
+			continue
 		}
 
 		buffer = append(buffer, highlighter.HighlightLine(line))
@@ -166,6 +169,9 @@ func followFile(path string, lines int) error {
 			continue
 		}
 		offset += int64(len(line))
+		if rec, ok := loop.ParseLogRecord(line); ok {
+			line = loop.FormatLogRecord(rec) + "\n"
+		}
 		fmt.Print(highlighter.HighlightLine(line))
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/db"
@@ -25,6 +26,8 @@ var (
 	msgState      string
 	msgTag        string
 	msgAll        bool
+	msgAt         string
+	msgDelay      string
 )
 
 func init() {
@@ -40,6 +43,8 @@ func init() {
 	loopMsgCmd.Flags().StringVar(&msgState, "state", "", "filter by state")
 	loopMsgCmd.Flags().StringVar(&msgTag, "tag", "", "filter by tag")
 	loopMsgCmd.Flags().BoolVar(&msgAll, "all", false, "target all loops")
+	loopMsgCmd.Flags().StringVar(&msgAt, "at", "", "hold until time (HH:MM local or RFC3339)")
+	loopMsgCmd.Flags().StringVar(&msgDelay, "delay", "", "hold for duration before dispatch (e.g. 30m)")
 }
 
 var loopMsgCmd = &cobra.Command{
@@ -73,6 +78,11 @@ var loopMsgCmd = &cobra.Command{
 			return fmt.Errorf("specify a loop or selector")
 		}
 
+		notBefore, err := parseNotBefore(msgAt, msgDelay, time.Now())
+		if err != nil {
+			return err
+		}
+
 		vars := parseKeyValuePairs(msgVars)
 		repoPath, err := resolveRepoPath("")
 		if err != nil {
@@ -157,19 +167,33 @@ var loopMsgCmd = &cobra.Command{
 				items = append(items, &models.LoopQueueItem{Type: models.LoopQueueItemSteerMessage, Payload: payload})
 			}
 
+			if notBefore != nil {
+				for _, item := range items {
+					item.NotBefore = notBefore
+				}
+			}
+
 			if err := queueRepo.Enqueue(context.Background(), loopEntry.ID, items...); err != nil {
 				return err
 			}
 		}
 
 		if IsJSONOutput() || IsJSONLOutput() {
-			return WriteOutput(os.Stdout, map[string]any{"loops": len(loops), "queued": true})
+			out := map[string]any{"loops": len(loops), "queued": true}
+			if notBefore != nil {
+				out["not_before"] = notBefore.Format(time.RFC3339)
+			}
+			return WriteOutput(os.Stdout, out)
 		}
 
 		if IsQuiet() {
 			return nil
 		}
 
+		if notBefore != nil {
+			fmt.Fprintf(os.Stdout, "Queued message for %d loop(s), due %s\n", len(loops), notBefore.Local().Format("2006-01-02 15:04 MST"))
+			return nil
+		}
 		fmt.Fprintf(os.Stdout, "Queued message for %d loop(s)\n", len(loops))
 		return nil
 	},
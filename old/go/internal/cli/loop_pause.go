@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	loopPauseAll     bool
+	loopPauseRepo    string
+	loopPausePool    string
+	loopPauseProfile string
+	loopPauseState   string
+	loopPauseTag     string
+	loopPauseFor     time.Duration
+	loopPauseReason  string
+)
+
+func init() {
+	rootCmd.AddCommand(loopPauseCmd)
+
+	loopPauseCmd.Flags().BoolVar(&loopPauseAll, "all", false, "pause all loops")
+	loopPauseCmd.Flags().StringVar(&loopPauseRepo, "repo", "", "filter by repo path")
+	loopPauseCmd.Flags().StringVar(&loopPausePool, "pool", "", "filter by pool")
+	loopPauseCmd.Flags().StringVar(&loopPauseProfile, "profile", "", "filter by profile")
+	loopPauseCmd.Flags().StringVar(&loopPauseState, "state", "", "filter by state")
+	loopPauseCmd.Flags().StringVar(&loopPauseTag, "tag", "", "filter by tag")
+	loopPauseCmd.Flags().DurationVar(&loopPauseFor, "for", 0, "resume automatically after this long (default: until 'forge resume')")
+	loopPauseCmd.Flags().StringVar(&loopPauseReason, "reason", "", "reason recorded on the pause")
+}
+
+var loopPauseCmd = &cobra.Command{
+	Use:   "pause [loop]",
+	Short: "Pause loops after the current iteration",
+	Long: `Pause loops between iterations without stopping the runner process.
+
+The runner finishes its current iteration, then holds until the pause
+expires (--for) or 'forge resume' is run. In-process state and the ledger
+carry on unchanged after resuming.`,
+	Example: `  forge pause my-loop
+  forge pause my-loop --for 30m --reason "deploy freeze"
+  forge resume my-loop`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sel := loopSelector{Repo: loopPauseRepo, Pool: loopPausePool, Profile: loopPauseProfile, State: loopPauseState, Tag: loopPauseTag}
+		if len(args) > 0 {
+			sel.LoopRef = args[0]
+		}
+		if sel.LoopRef == "" && !loopPauseAll && sel.Repo == "" && sel.Pool == "" && sel.Profile == "" && sel.State == "" && sel.Tag == "" {
+			return fmt.Errorf("specify a loop or selector")
+		}
+		if loopPauseFor < 0 {
+			return fmt.Errorf("--for must be positive")
+		}
+		if loopPauseFor > 0 && loopPauseFor < time.Second {
+			return fmt.Errorf("--for must be at least 1s")
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ctx := context.Background()
+		loopRepo := db.NewLoopRepository(database)
+		queueRepo := db.NewLoopQueueRepository(database)
+		loops, err := selectLoops(ctx, loopRepo, db.NewPoolRepository(database), db.NewProfileRepository(database), sel)
+		if err != nil {
+			return err
+		}
+		if len(loops) == 0 {
+			return fmt.Errorf("no loops matched")
+		}
+
+		payload, err := json.Marshal(models.LoopPausePayload{
+			DurationSeconds: int(loopPauseFor / time.Second),
+			Reason:          loopPauseReason,
+		})
+		if err != nil {
+			return err
+		}
+		paused := 0
+		for _, loopEntry := range loops {
+			switch loopEntry.State {
+			case models.LoopStateStopped, models.LoopStateError:
+				continue
+			}
+			if loop.IsPaused(loopEntry) {
+				continue
+			}
+			if err := queueRepo.Enqueue(ctx, loopEntry.ID, &models.LoopQueueItem{Type: models.LoopQueueItemPause, Payload: payload}); err != nil {
+				return err
+			}
+			paused++
+		}
+		if sel.LoopRef != "" && paused == 0 {
+			if loop.IsPaused(loops[0]) {
+				return fmt.Errorf("loop %q is already paused", loops[0].Name)
+			}
+			return fmt.Errorf("loop %q is %s; only running loops can be paused", loops[0].Name, loops[0].State)
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{"loops": paused, "action": string(models.LoopQueueItemPause)})
+		}
+		if IsQuiet() {
+			return nil
+		}
+		fmt.Fprintf(os.Stdout, "Pausing %d loop(s)\n", paused)
+		return nil
+	},
+}
+
+// resumePausedLoop queues a resume item for a loop whose runner is holding
+// on a pause.
+func resumePausedLoop(ctx context.Context, database *db.DB, loopEntry *models.Loop) error {
+	payload, err := json.Marshal(models.ResumePayload{Reason: "operator"})
+	if err != nil {
+		return err
+	}
+	return db.NewLoopQueueRepository(database).Enqueue(ctx, loopEntry.ID, &models.LoopQueueItem{Type: models.LoopQueueItemResume, Payload: payload})
+}
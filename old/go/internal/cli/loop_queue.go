@@ -77,16 +77,21 @@ var loopQueueListCmd = &cobra.Command{
 
 		rows := make([][]string, 0, len(filtered))
 		for _, item := range filtered {
+			notBefore := "-"
+			if item.NotBefore != nil {
+				notBefore = item.NotBefore.UTC().Format(time.RFC3339)
+			}
 			rows = append(rows, []string{
 				item.ID,
 				string(item.Type),
 				string(item.Status),
 				fmt.Sprintf("%d", item.Position),
 				item.CreatedAt.UTC().Format(time.RFC3339),
+				notBefore,
 			})
 		}
 
-		return writeTable(os.Stdout, []string{"ID", "TYPE", "STATUS", "POSITION", "CREATED"}, rows)
+		return writeTable(os.Stdout, []string{"ID", "TYPE", "STATUS", "POSITION", "CREATED", "NOT BEFORE"}, rows)
 	},
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/models"
 )
 
@@ -20,7 +21,9 @@ func init() {
 var loopResumeCmd = &cobra.Command{
 	Use:   "resume <loop>",
 	Short: "Resume a stopped loop",
-	Args:  cobra.ExactArgs(1),
+	Long: `Resume a stopped or errored loop by starting a new runner, or release a
+loop paused with 'forge pause' so its existing runner continues.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDatabase()
 		if err != nil {
@@ -34,6 +37,13 @@ var loopResumeCmd = &cobra.Command{
 			return err
 		}
 
+		if loop.IsPaused(loopEntry) {
+			if err := resumePausedLoop(context.Background(), database, loopEntry); err != nil {
+				return err
+			}
+			return writeLoopResumed(loopEntry)
+		}
+
 		switch loopEntry.State {
 		case models.LoopStateStopped, models.LoopStateError:
 		default:
@@ -53,19 +63,23 @@ var loopResumeCmd = &cobra.Command{
 			return err
 		}
 
-		if IsJSONOutput() || IsJSONLOutput() {
-			return WriteOutput(os.Stdout, map[string]any{
-				"resumed": true,
-				"loop_id": loopEntry.ID,
-				"name":    loopEntry.Name,
-			})
-		}
+		return writeLoopResumed(loopEntry)
+	},
+}
 
-		if IsQuiet() {
-			return nil
-		}
+func writeLoopResumed(loopEntry *models.Loop) error {
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, map[string]any{
+			"resumed": true,
+			"loop_id": loopEntry.ID,
+			"name":    loopEntry.Name,
+		})
+	}
 
-		fmt.Fprintf(os.Stdout, "Loop %q resumed (%s)\n", loopEntry.Name, loopShortID(loopEntry))
+	if IsQuiet() {
 		return nil
-	},
+	}
+
+	fmt.Fprintf(os.Stdout, "Loop %q resumed (%s)\n", loopEntry.Name, loopShortID(loopEntry))
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/models"
 )
 
@@ -18,6 +19,8 @@ var (
 	loopRmState   string
 	loopRmTag     string
 	loopRmForce   bool
+	loopRmCascade bool
+	loopRmDryRun  bool
 )
 
 func init() {
@@ -30,6 +33,8 @@ func init() {
 	loopRmCmd.Flags().StringVar(&loopRmState, "state", "", "filter by state")
 	loopRmCmd.Flags().StringVar(&loopRmTag, "tag", "", "filter by tag")
 	loopRmCmd.Flags().BoolVar(&loopRmForce, "force", false, "remove even if loops are running")
+	loopRmCmd.Flags().BoolVar(&loopRmCascade, "cascade", false, "also delete logs, ledgers, run prompts, and run artifacts")
+	loopRmCmd.Flags().BoolVar(&loopRmDryRun, "dry-run", false, "list what would be removed without removing anything")
 }
 
 var loopRmCmd = &cobra.Command{
@@ -38,7 +43,12 @@ var loopRmCmd = &cobra.Command{
 	Short:   "Remove loop records",
 	Long: `Remove loop records from Forge.
 
-This only removes the loop record. Logs and ledgers are left on disk.`,
+Run history and queue items are removed with the loop record. Logs and
+ledgers are left on disk unless --cascade is set, which also deletes the
+loop's log, ledger, run prompt files, and run artifacts.
+
+Use --dry-run to list everything that would be removed, with the total
+bytes reclaimed, before deleting.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sel := loopSelector{Repo: loopRmRepo, Pool: loopRmPool, Profile: loopRmProfile, State: loopRmState, Tag: loopRmTag}
@@ -50,7 +60,7 @@ This only removes the loop record. Logs and ledgers are left on disk.`,
 		}
 
 		usesSelector := loopRmAll || sel.Repo != "" || sel.Pool != "" || sel.Profile != "" || sel.State != "" || sel.Tag != ""
-		if usesSelector && !loopRmForce {
+		if usesSelector && !loopRmForce && !loopRmDryRun {
 			return fmt.Errorf("selector-based removal requires --force")
 		}
 
@@ -73,6 +83,24 @@ This only removes the loop record. Logs and ledgers are left on disk.`,
 			return fmt.Errorf("no loops matched")
 		}
 
+		dataDir := ""
+		if cfg := GetConfig(); cfg != nil {
+			dataDir = cfg.Global.DataDir
+		}
+		plans := make([]*loop.DeletePlan, 0, len(loops))
+		var reclaimed int64
+		for _, loopEntry := range loops {
+			plan, err := loop.PlanDelete(ctx, database, dataDir, loopEntry, loopRmCascade)
+			if err != nil {
+				return err
+			}
+			plans = append(plans, plan)
+			reclaimed += plan.Bytes
+		}
+		if loopRmDryRun {
+			return writeLoopDeletePlans(plans, reclaimed)
+		}
+
 		activeCount := 0
 		for _, loopEntry := range loops {
 			if loopEntry.State != models.LoopStateStopped {
@@ -84,6 +112,9 @@ This only removes the loop record. Logs and ledgers are left on disk.`,
 		}
 
 		impact := fmt.Sprintf("This will remove %d loop record(s). Logs and ledgers will remain on disk.", len(loops))
+		if loopRmCascade {
+			impact = fmt.Sprintf("This will remove %d loop record(s) and delete their logs, ledgers, and run files (%s).", len(loops), formatByteSize(reclaimed))
+		}
 		if activeCount > 0 {
 			impact += " Some loops are not stopped; their processes will keep running."
 		}
@@ -98,14 +129,17 @@ This only removes the loop record. Logs and ledgers are left on disk.`,
 			return nil
 		}
 
-		for _, loopEntry := range loops {
-			if err := loopRepo.Delete(ctx, loopEntry.ID); err != nil {
+		for _, plan := range plans {
+			if err := loop.ExecuteDelete(ctx, database, plan); err != nil {
 				return err
 			}
 		}
 
 		if IsJSONOutput() || IsJSONLOutput() {
 			result := map[string]any{"removed": len(loops)}
+			if loopRmCascade {
+				result["bytes_reclaimed"] = reclaimed
+			}
 			if len(loops) == 1 {
 				result["loop_id"] = loops[0].ID
 				result["name"] = loops[0].Name
@@ -126,12 +160,45 @@ This only removes the loop record. Logs and ledgers are left on disk.`,
 			return nil
 		}
 
+		suffix := ""
+		if loopRmCascade {
+			suffix = fmt.Sprintf(" (%s reclaimed)", formatByteSize(reclaimed))
+		}
 		if len(loops) == 1 {
-			fmt.Printf("Loop '%s' removed\n", loops[0].Name)
+			fmt.Printf("Loop '%s' removed%s\n", loops[0].Name, suffix)
 			return nil
 		}
 
-		fmt.Printf("Removed %d loop(s)\n", len(loops))
+		fmt.Printf("Removed %d loop(s)%s\n", len(loops), suffix)
 		return nil
 	},
 }
+
+func writeLoopDeletePlans(plans []*loop.DeletePlan, reclaimed int64) error {
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, map[string]any{
+			"dry_run":         true,
+			"loops":           plans,
+			"bytes_reclaimed": reclaimed,
+		})
+	}
+
+	for _, plan := range plans {
+		fmt.Printf("Would remove loop '%s' (%d runs, %d queue items)\n", plan.Name, plan.Runs, plan.QueueItems)
+		rows := make([][]string, 0, len(plan.Files))
+		for _, file := range plan.Files {
+			rows = append(rows, []string{"  " + file.Kind, file.Path, formatByteSize(file.Bytes)})
+		}
+		if len(rows) > 0 {
+			if err := writeTable(os.Stdout, nil, rows); err != nil {
+				return err
+			}
+		}
+	}
+	if len(plans) > 0 && plans[0].Cascade {
+		fmt.Printf("Total reclaimed: %s\n", formatByteSize(reclaimed))
+	} else {
+		fmt.Println("Logs and ledgers would remain on disk (use --cascade to delete them).")
+	}
+	return nil
+}
@@ -6,13 +6,13 @@ import (
 
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/reconcile"
 )
 
 const (
 	loopMetadataRunnerOwnerKey      = "runner_owner"
 	loopMetadataRunnerInstanceIDKey = "runner_instance_id"
-	loopMetadataRunnerLivenessKey   = "runner_liveness"
-	loopStaleRunnerReason           = "stale_runner"
+	loopStaleRunnerReason           = reconcile.StaleRunnerReason
 )
 
 func setLoopRunnerMetadata(ctx context.Context, loopRepo *db.LoopRepository, loopID string, owner loopSpawnOwner, instanceID string) error {
@@ -30,6 +30,17 @@ var (
 	loopScaleKill          bool
 	loopScaleSpawnOwner    string
 
+	loopScalePreRunHook  string
+	loopScalePostRunHook string
+	loopScaleHookTimeout string
+
+	loopScaleArtifacts []string
+
+	loopScaleCheckpoint string
+
+	loopScalePromptPlaylist []string
+	loopScalePromptStrategy string
+
 	loopScaleQuantStopCmd        string
 	loopScaleQuantStopEvery      int
 	loopScaleQuantStopWhen       string
@@ -65,6 +76,14 @@ func init() {
 	loopScaleCmd.Flags().BoolVar(&loopScaleKill, "kill", false, "kill extra loops instead of stopping")
 	loopScaleCmd.Flags().StringVar(&loopScaleSpawnOwner, "spawn-owner", string(loopSpawnOwnerAuto), "loop runner owner (local|daemon|auto)")
 
+	loopScaleCmd.Flags().StringVar(&loopScalePreRunHook, "pre-run-hook", "", "command run before each iteration (bash -lc; relative script paths resolve against the repo)")
+	loopScaleCmd.Flags().StringVar(&loopScalePostRunHook, "post-run-hook", "", "command run after each iteration (bash -lc; EXIT_CODE holds the run's exit code)")
+	loopScaleCmd.Flags().StringVar(&loopScaleHookTimeout, "hook-timeout", "", "timeout per hook command (e.g. 30s; 0s/empty = no limit)")
+	loopScaleCmd.Flags().StringArrayVar(&loopScaleArtifacts, "artifact", nil, "artifact glob copied after each iteration (repeatable; ** spans directories)")
+	loopScaleCmd.Flags().StringVar(&loopScaleCheckpoint, "checkpoint", "", "git checkpoint before each iteration (off|commit|stash; default loop_defaults.checkpoint)")
+	loopScaleCmd.Flags().StringArrayVar(&loopScalePromptPlaylist, "prompt-playlist", nil, "prompt path or name rotated per iteration (repeatable, in order; replaces --prompt)")
+	loopScaleCmd.Flags().StringVar(&loopScalePromptStrategy, "prompt-strategy", models.PromptStrategySequential, "prompt playlist rotation (sequential|random)")
+
 	loopScaleCmd.Flags().StringVar(&loopScaleQuantStopCmd, "quantitative-stop-cmd", "", "quantitative stop: command to execute (bash -lc)")
 	loopScaleCmd.Flags().IntVar(&loopScaleQuantStopEvery, "quantitative-stop-every", 1, "quantitative stop: evaluate every N iterations (> 0)")
 	loopScaleCmd.Flags().StringVar(&loopScaleQuantStopWhen, "quantitative-stop-when", "before", "quantitative stop: when to evaluate (before|after|both)")
@@ -150,6 +169,26 @@ For new loops, you can configure smart stop:
 
 		tags := parseTags(loopScaleTags)
 
+		hooksCfg, err := buildLoopHooks(cfg.LoopDefaults.Hooks, loopScalePreRunHook, loopScalePostRunHook, loopScaleHookTimeout)
+		if err != nil {
+			return err
+		}
+		artifactsCfg := buildLoopArtifacts(cfg.LoopDefaults.Artifacts, loopScaleArtifacts)
+		checkpointMode, err := buildLoopCheckpoint(cfg.LoopDefaults.Checkpoint, loopScaleCheckpoint)
+		if err != nil {
+			return err
+		}
+		playlist, err := buildPromptPlaylist(repoPath, loopScalePromptPlaylist, loopScalePromptStrategy)
+		if err != nil {
+			return err
+		}
+		if !playlist.IsZero() {
+			if strings.TrimSpace(loopScalePrompt) != "" || strings.TrimSpace(loopScalePromptMsg) != "" {
+				return fmt.Errorf("--prompt-playlist cannot be combined with --prompt or --prompt-msg")
+			}
+			basePromptPath, basePromptMsg = "", ""
+		}
+
 		stopCfg := models.LoopStopConfig{}
 		if strings.TrimSpace(loopScaleQuantStopCmd) != "" {
 			if loopScaleQuantStopEvery <= 0 {
@@ -285,6 +324,9 @@ For new loops, you can configure smart stop:
 
 		if len(loops) < loopScaleCount {
 			toCreate := loopScaleCount - len(loops)
+			if err := checkLoopQuota(context.Background(), database, repoPath, toCreate); err != nil {
+				return err
+			}
 			spawnOwner, err := resolveSpawnOwner(cmd, loopScaleSpawnOwner)
 			if err != nil {
 				return err
@@ -319,6 +361,30 @@ For new loops, you can configure smart stop:
 				if stopCfg.Quant != nil || stopCfg.Qual != nil {
 					loopEntry.Metadata = map[string]any{"stop_config": stopCfg}
 				}
+				if !hooksCfg.IsZero() {
+					if loopEntry.Metadata == nil {
+						loopEntry.Metadata = make(map[string]any)
+					}
+					loopEntry.Metadata["hooks"] = hooksCfg
+				}
+				if !artifactsCfg.IsZero() {
+					if loopEntry.Metadata == nil {
+						loopEntry.Metadata = make(map[string]any)
+					}
+					loopEntry.Metadata["artifacts"] = artifactsCfg
+				}
+				if checkpointMode != models.LoopCheckpointOff {
+					if loopEntry.Metadata == nil {
+						loopEntry.Metadata = make(map[string]any)
+					}
+					loopEntry.Metadata["checkpoint"] = string(checkpointMode)
+				}
+				if !playlist.IsZero() {
+					if loopEntry.Metadata == nil {
+						loopEntry.Metadata = make(map[string]any)
+					}
+					loopEntry.Metadata["prompt_playlist"] = playlist
+				}
 				if err := loopRepo.Create(context.Background(), loopEntry); err != nil {
 					return err
 				}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+var (
+	loopSwitchQueue      string
+	loopSwitchForce      bool
+	loopSwitchNoRestart  bool
+	loopSwitchReason     string
+	loopSwitchSpawnOwner string
+)
+
+func init() {
+	rootCmd.AddCommand(loopSwitchProfileCmd)
+	loopSwitchProfileCmd.Flags().StringVar(&loopSwitchQueue, "queue", string(loop.SwitchQueueMigrate), "pending queue handling (migrate|drain)")
+	loopSwitchProfileCmd.Flags().BoolVar(&loopSwitchForce, "force", false, "switch even if the profile is in cooldown or its harness is missing")
+	loopSwitchProfileCmd.Flags().BoolVar(&loopSwitchNoRestart, "no-restart", false, "leave the loop stopped after switching")
+	loopSwitchProfileCmd.Flags().StringVar(&loopSwitchReason, "reason", "", "reason recorded in the audit log")
+	loopSwitchProfileCmd.Flags().StringVar(&loopSwitchSpawnOwner, "spawn-owner", string(loopSpawnOwnerAuto), "loop runner owner (local|daemon|auto)")
+}
+
+var loopSwitchProfileCmd = &cobra.Command{
+	Use:   "switch-profile <loop> <profile>",
+	Short: "Move a loop to another profile",
+	Long: `Move a loop to another profile (and possibly another harness).
+
+The running loop is stopped, pinned to the new profile and restarted.
+Pending messages and overrides carry over with --queue migrate (default);
+--queue drain skips all pending items. The switch is recorded in the
+audit log as loop.profile_switched.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		mode, err := loop.ParseSwitchQueueMode(loopSwitchQueue)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		loopRepo := db.NewLoopRepository(database)
+		loopEntry, err := resolveLoopByRef(ctx, loopRepo, args[0])
+		if err != nil {
+			return err
+		}
+		profile, err := resolveProfileByRef(ctx, db.NewProfileRepository(database), args[1])
+		if err != nil {
+			return err
+		}
+
+		plan, err := loop.PlanProfileSwitch(ctx, database, loopEntry, profile, mode, loopSwitchForce)
+		if err != nil {
+			return err
+		}
+		plan.Reason = loopSwitchReason
+		if loopSwitchNoRestart {
+			plan.Restart = false
+		}
+
+		var spawnOwner loopSpawnOwner
+		if plan.Restart {
+			spawnOwner, err = resolveSpawnOwner(cmd, loopSwitchSpawnOwner)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The runner caches the loop record and would write the old
+		// profile back, so it has to go before the switch is applied.
+		_ = killLoopProcess(loopEntry)
+		if err := loop.ApplyProfileSwitch(ctx, database, plan); err != nil {
+			return err
+		}
+
+		if plan.Restart {
+			startResult, err := startLoopRunnerFunc(loopEntry.ID, cfgFile, spawnOwner)
+			if err != nil {
+				return fmt.Errorf("profile switched but restart failed: %w", err)
+			}
+			if err := setLoopRunnerMetadata(ctx, loopRepo, loopEntry.ID, startResult.Owner, startResult.InstanceID); err != nil {
+				return err
+			}
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{
+				"loop_id":    loopEntry.ID,
+				"name":       loopEntry.Name,
+				"profile_id": profile.ID,
+				"profile":    profile.Name,
+				"harness":    profile.Harness,
+				"queue_mode": string(plan.QueueMode),
+				"migrated":   plan.Migrated(),
+				"dropped":    plan.Dropped(),
+				"restarted":  plan.Restart,
+				"warnings":   plan.Warnings,
+			})
+		}
+
+		for _, warning := range plan.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if IsQuiet() {
+			return nil
+		}
+
+		fmt.Fprintf(os.Stdout, "Loop %q switched to profile %s (%s)\n", loopEntry.Name, profile.Name, profile.Harness)
+		fmt.Fprintf(os.Stdout, "Queue: %d migrated, %d dropped\n", plan.Migrated(), plan.Dropped())
+		if plan.Restart {
+			fmt.Fprintln(os.Stdout, "Runner restarted")
+		}
+		return nil
+	},
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+var (
+	loopTemplateDescription   string
+	loopTemplateLoopName      string
+	loopTemplateNamePrefix    string
+	loopTemplateCount         int
+	loopTemplatePool          string
+	loopTemplateProfile       string
+	loopTemplatePrompt        string
+	loopTemplatePromptMsg     string
+	loopTemplateInterval      string
+	loopTemplateMaxRuntime    string
+	loopTemplateMaxIterations int
+	loopTemplateTags          string
+)
+
+func init() {
+	loopInternalCmd.AddCommand(loopTemplateCmd)
+
+	loopTemplateCmd.AddCommand(loopTemplateListCmd)
+	loopTemplateCmd.AddCommand(loopTemplateShowCmd)
+	loopTemplateCmd.AddCommand(loopTemplateSaveCmd)
+	loopTemplateCmd.AddCommand(loopTemplateRmCmd)
+
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateDescription, "description", "", "template description")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateLoopName, "name", "", "loop name (single loop)")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateNamePrefix, "name-prefix", "", "loop name prefix")
+	loopTemplateSaveCmd.Flags().IntVarP(&loopTemplateCount, "count", "n", 1, "number of loops to start")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplatePool, "pool", "", "pool name or ID")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateProfile, "profile", "", "profile name or ID")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplatePrompt, "prompt", "", "base prompt path or prompt name")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplatePromptMsg, "prompt-msg", "", "base prompt content for each iteration")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateInterval, "interval", "", "sleep interval (e.g., 30s, 2m)")
+	loopTemplateSaveCmd.Flags().StringVarP(&loopTemplateMaxRuntime, "max-runtime", "r", "", "max runtime before stopping (e.g., 30m, 2h)")
+	loopTemplateSaveCmd.Flags().IntVarP(&loopTemplateMaxIterations, "max-iterations", "i", 0, "max iterations before stopping (0 = no limit)")
+	loopTemplateSaveCmd.Flags().StringVar(&loopTemplateTags, "tags", "", "comma-separated tags")
+}
+
+var loopTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage loop wizard templates",
+	Long:  "Save, list, and remove named loop templates. The loop TUI wizard offers them on step 1.",
+}
+
+var loopTemplateListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List loop templates",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, err := loop.ListTemplates(loopTemplateDir())
+		if err != nil {
+			return err
+		}
+		if templates == nil {
+			templates = []loop.Template{}
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, templates)
+		}
+		if len(templates) == 0 {
+			fmt.Fprintln(os.Stdout, "No loop templates found")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(templates))
+		for _, tpl := range templates {
+			target := tpl.Pool
+			if target == "" {
+				target = tpl.Profile
+			}
+			rows = append(rows, []string{tpl.Name, target, tpl.Count, tpl.Description})
+		}
+		return writeTable(os.Stdout, []string{"NAME", "POOL/PROFILE", "COUNT", "DESCRIPTION"}, rows)
+	},
+}
+
+var loopTemplateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a loop template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tpl, err := loop.LoadTemplate(loopTemplateDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, tpl)
+		}
+
+		fields := [][2]string{
+			{"name", tpl.Name},
+			{"description", tpl.Description},
+			{"loop-name", tpl.LoopName},
+			{"name-prefix", tpl.NamePrefix},
+			{"count", tpl.Count},
+			{"pool", tpl.Pool},
+			{"profile", tpl.Profile},
+			{"prompt", tpl.Prompt},
+			{"prompt-msg", tpl.PromptMsg},
+			{"interval", tpl.Interval},
+			{"max-runtime", tpl.MaxRuntime},
+			{"max-iterations", tpl.MaxIterations},
+			{"tags", tpl.Tags},
+		}
+		for _, field := range fields {
+			if field[1] == "" {
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "%s: %s\n", field[0], field[1])
+		}
+		return nil
+	},
+}
+
+var loopTemplateSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a loop template",
+	Long:  "Save a loop template from flags (the same flags as `forge up`), replacing any template with the same name.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if loopTemplatePool != "" && loopTemplateProfile != "" {
+			return fmt.Errorf("use either --pool or --profile, not both")
+		}
+		if loopTemplateLoopName != "" && loopTemplateCount > 1 {
+			return fmt.Errorf("--name requires --count 1")
+		}
+		if loopTemplateCount < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+		if loopTemplateMaxIterations < 0 {
+			return fmt.Errorf("--max-iterations must be >= 0")
+		}
+		if _, err := parseDuration(loopTemplateInterval, 0); err != nil {
+			return fmt.Errorf("--interval: %w", err)
+		}
+		if _, err := parseDuration(loopTemplateMaxRuntime, 0); err != nil {
+			return fmt.Errorf("--max-runtime: %w", err)
+		}
+
+		tpl := loop.Template{
+			Name:        args[0],
+			Description: loopTemplateDescription,
+			LoopName:    loopTemplateLoopName,
+			NamePrefix:  loopTemplateNamePrefix,
+			Count:       strconv.Itoa(loopTemplateCount),
+			Pool:        loopTemplatePool,
+			Profile:     loopTemplateProfile,
+			Prompt:      loopTemplatePrompt,
+			PromptMsg:   loopTemplatePromptMsg,
+			Interval:    loopTemplateInterval,
+			MaxRuntime:  loopTemplateMaxRuntime,
+			Tags:        loopTemplateTags,
+		}
+		if loopTemplateMaxIterations > 0 {
+			tpl.MaxIterations = strconv.Itoa(loopTemplateMaxIterations)
+		}
+		if err := loop.SaveTemplate(loopTemplateDir(), tpl); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, tpl)
+		}
+		if IsQuiet() {
+			return nil
+		}
+		fmt.Fprintf(os.Stdout, "Loop template %q saved\n", tpl.Name)
+		return nil
+	},
+}
+
+var loopTemplateRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"delete"},
+	Short:   "Remove a loop template",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loop.DeleteTemplate(loopTemplateDir(), args[0]); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{"template": args[0], "deleted": true})
+		}
+		if IsQuiet() {
+			return nil
+		}
+		fmt.Fprintf(os.Stdout, "Loop template %q removed\n", args[0])
+		return nil
+	},
+}
+
+func loopTemplateDir() string {
+	return loop.TemplateDir(getConfigDir())
+}
@@ -28,6 +28,18 @@ var (
 	loopUpMaxIterations int
 	loopUpTags          string
 	loopUpSpawnOwner    string
+	loopUpNoDailySum    bool
+
+	loopUpPreRunHook  string
+	loopUpPostRunHook string
+	loopUpHookTimeout string
+
+	loopUpArtifacts []string
+
+	loopUpCheckpoint string
+
+	loopUpPromptPlaylist []string
+	loopUpPromptStrategy string
 
 	loopUpQuantStopCmd        string
 	loopUpQuantStopEvery      int
@@ -63,6 +75,15 @@ func init() {
 	loopUpCmd.Flags().IntVarP(&loopUpMaxIterations, "max-iterations", "i", 0, "max iterations before stopping (0 = no limit)")
 	loopUpCmd.Flags().StringVar(&loopUpTags, "tags", "", "comma-separated tags")
 	loopUpCmd.Flags().StringVar(&loopUpSpawnOwner, "spawn-owner", string(loopSpawnOwnerAuto), "loop runner owner (local|daemon|auto)")
+	loopUpCmd.Flags().BoolVar(&loopUpNoDailySum, "no-daily-summary", false, "opt this loop out of daily summaries")
+
+	loopUpCmd.Flags().StringVar(&loopUpPreRunHook, "pre-run-hook", "", "command run before each iteration (bash -lc; relative script paths resolve against the repo)")
+	loopUpCmd.Flags().StringVar(&loopUpPostRunHook, "post-run-hook", "", "command run after each iteration (bash -lc; EXIT_CODE holds the run's exit code)")
+	loopUpCmd.Flags().StringVar(&loopUpHookTimeout, "hook-timeout", "", "timeout per hook command (e.g. 30s; 0s/empty = no limit)")
+	loopUpCmd.Flags().StringArrayVar(&loopUpArtifacts, "artifact", nil, "artifact glob copied after each iteration (repeatable; ** spans directories)")
+	loopUpCmd.Flags().StringVar(&loopUpCheckpoint, "checkpoint", "", "git checkpoint before each iteration (off|commit|stash; default loop_defaults.checkpoint)")
+	loopUpCmd.Flags().StringArrayVar(&loopUpPromptPlaylist, "prompt-playlist", nil, "prompt path or name rotated per iteration (repeatable, in order; replaces --prompt)")
+	loopUpCmd.Flags().StringVar(&loopUpPromptStrategy, "prompt-strategy", models.PromptStrategySequential, "prompt playlist rotation (sequential|random)")
 
 	loopUpCmd.Flags().StringVar(&loopUpQuantStopCmd, "quantitative-stop-cmd", "", "quantitative stop: command to execute (bash -lc)")
 	loopUpCmd.Flags().IntVar(&loopUpQuantStopEvery, "quantitative-stop-every", 1, "quantitative stop: evaluate every N iterations (> 0)")
@@ -155,6 +176,26 @@ Smart stop (optional):
 
 		tags := parseTags(loopUpTags)
 
+		hooksCfg, err := buildLoopHooks(cfg.LoopDefaults.Hooks, loopUpPreRunHook, loopUpPostRunHook, loopUpHookTimeout)
+		if err != nil {
+			return err
+		}
+		artifactsCfg := buildLoopArtifacts(cfg.LoopDefaults.Artifacts, loopUpArtifacts)
+		checkpointMode, err := buildLoopCheckpoint(cfg.LoopDefaults.Checkpoint, loopUpCheckpoint)
+		if err != nil {
+			return err
+		}
+		playlist, err := buildPromptPlaylist(repoPath, loopUpPromptPlaylist, loopUpPromptStrategy)
+		if err != nil {
+			return err
+		}
+		if !playlist.IsZero() {
+			if strings.TrimSpace(loopUpPrompt) != "" || strings.TrimSpace(loopUpPromptMsg) != "" {
+				return fmt.Errorf("--prompt-playlist cannot be combined with --prompt or --prompt-msg")
+			}
+			basePromptPath, basePromptMsg = "", ""
+		}
+
 		stopCfg := models.LoopStopConfig{}
 		if strings.TrimSpace(loopUpQuantStopCmd) != "" {
 			if loopUpQuantStopEvery <= 0 {
@@ -274,6 +315,9 @@ Smart stop (optional):
 		for _, item := range existing {
 			existingNames[item.Name] = struct{}{}
 		}
+		if err := checkLoopQuota(context.Background(), database, repoPath, loopUpCount); err != nil {
+			return err
+		}
 
 		created := make([]*models.Loop, 0, loopUpCount)
 		spawnOwner, err := resolveSpawnOwner(cmd, loopUpSpawnOwner)
@@ -310,6 +354,36 @@ Smart stop (optional):
 			if stopCfg.Quant != nil || stopCfg.Qual != nil {
 				loopEntry.Metadata = map[string]any{"stop_config": stopCfg}
 			}
+			if !hooksCfg.IsZero() {
+				if loopEntry.Metadata == nil {
+					loopEntry.Metadata = make(map[string]any)
+				}
+				loopEntry.Metadata["hooks"] = hooksCfg
+			}
+			if !artifactsCfg.IsZero() {
+				if loopEntry.Metadata == nil {
+					loopEntry.Metadata = make(map[string]any)
+				}
+				loopEntry.Metadata["artifacts"] = artifactsCfg
+			}
+			if checkpointMode != models.LoopCheckpointOff {
+				if loopEntry.Metadata == nil {
+					loopEntry.Metadata = make(map[string]any)
+				}
+				loopEntry.Metadata["checkpoint"] = string(checkpointMode)
+			}
+			if !playlist.IsZero() {
+				if loopEntry.Metadata == nil {
+					loopEntry.Metadata = make(map[string]any)
+				}
+				loopEntry.Metadata["prompt_playlist"] = playlist
+			}
+			if loopUpNoDailySum {
+				if loopEntry.Metadata == nil {
+					loopEntry.Metadata = make(map[string]any)
+				}
+				loopEntry.Metadata[loop.MetadataDailySummaryDisabled] = true
+			}
 			if err := loopRepo.Create(context.Background(), loopEntry); err != nil {
 				return err
 			}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	watchesLoopRef string
+	watchesTimeout time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(watchesCmd)
+	watchesCmd.PersistentFlags().StringVar(&watchesLoopRef, "loop", "", "loop ref (defaults to FORGE_LOOP_ID)")
+	watchesEvalCmd.Flags().DurationVar(&watchesTimeout, "timeout", loop.DefaultWatchTimeout, "timeout per expression")
+
+	watchesCmd.AddCommand(watchesAddCmd)
+	watchesCmd.AddCommand(watchesListCmd)
+	watchesCmd.AddCommand(watchesRmCmd)
+	watchesCmd.AddCommand(watchesEvalCmd)
+}
+
+var watchesCmd = &cobra.Command{
+	Use:   "watches",
+	Short: "Per-loop watch expressions shown in the loop TUI",
+	Long: `Manage per-loop watch expressions.
+
+A watch is a named shell expression the loop TUI evaluates on every refresh
+and shows in the overview pane, so domain-specific health signals sit next to
+the logs. Expressions run via bash -lc in the repo root with REPO, LOOP_ID,
+and LOOP_NAME set; the first line of stdout is displayed.`,
+	Example: `  forge watches add dirty 'git -C $REPO status --porcelain | wc -l' --loop my-loop
+  forge watches ls --loop my-loop
+  forge watches eval --loop my-loop`,
+}
+
+var watchesAddCmd = &cobra.Command{
+	Use:   "add <name> <expr>",
+	Short: "Add or replace a watch expression",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(args[0])
+		expr := strings.TrimSpace(args[1])
+		if name == "" || expr == "" {
+			return fmt.Errorf("watch name and expression are required")
+		}
+
+		return updateLoopWatches(func(loopEntry *models.Loop, watches []models.LoopWatch) ([]models.LoopWatch, error) {
+			for i := range watches {
+				if watches[i].Name == name {
+					watches[i].Expr = expr
+					return watches, nil
+				}
+			}
+			return append(watches, models.LoopWatch{Name: name, Expr: expr}), nil
+		}, name)
+	},
+}
+
+var watchesRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a watch expression",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(args[0])
+		return updateLoopWatches(func(loopEntry *models.Loop, watches []models.LoopWatch) ([]models.LoopWatch, error) {
+			for i := range watches {
+				if watches[i].Name == name {
+					return append(watches[:i], watches[i+1:]...), nil
+				}
+			}
+			return nil, fmt.Errorf("watch %q not found on loop %s", name, loopEntry.Name)
+		}, name)
+	},
+}
+
+var watchesListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List watch expressions",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loopEntry, err := resolveWatchesLoop()
+		if err != nil {
+			return err
+		}
+		watches := models.LoopWatches(loopEntry)
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			if watches == nil {
+				watches = []models.LoopWatch{}
+			}
+			return WriteOutput(os.Stdout, watches)
+		}
+		if len(watches) == 0 {
+			fmt.Fprintln(os.Stdout, "(empty)")
+			return nil
+		}
+		for _, watch := range watches {
+			fmt.Fprintf(os.Stdout, "%s=%s\n", watch.Name, watch.Expr)
+		}
+		return nil
+	},
+}
+
+var watchesEvalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Evaluate watch expressions now",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loopEntry, err := resolveWatchesLoop()
+		if err != nil {
+			return err
+		}
+		results := loop.EvalWatches(context.Background(), loopEntry, watchesTimeout)
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			if results == nil {
+				results = []loop.WatchResult{}
+			}
+			return WriteOutput(os.Stdout, results)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(os.Stdout, "(empty)")
+			return nil
+		}
+		for _, result := range results {
+			value := result.Value
+			switch {
+			case result.Error != "":
+				value = "error: " + result.Error
+			case result.ExitCode != 0:
+				value = fmt.Sprintf("exit %d: %s", result.ExitCode, value)
+			}
+			fmt.Fprintf(os.Stdout, "%s: %s\n", result.Name, value)
+		}
+		return nil
+	},
+}
+
+func resolveWatchesLoop() (*models.Loop, error) {
+	loopRef, err := requireLoopRef(watchesLoopRef)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	return resolveLoopByRef(context.Background(), db.NewLoopRepository(database), loopRef)
+}
+
+func updateLoopWatches(update func(*models.Loop, []models.LoopWatch) ([]models.LoopWatch, error), name string) error {
+	loopRef, err := requireLoopRef(watchesLoopRef)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	loopRepo := db.NewLoopRepository(database)
+	loopEntry, err := resolveLoopByRef(ctx, loopRepo, loopRef)
+	if err != nil {
+		return err
+	}
+
+	watches, err := update(loopEntry, models.LoopWatches(loopEntry))
+	if err != nil {
+		return err
+	}
+	models.SetLoopWatches(loopEntry, watches)
+	if err := loopRepo.Update(ctx, loopEntry); err != nil {
+		return err
+	}
+
+	if IsJSONOutput() || IsJSONLOutput() {
+		return WriteOutput(os.Stdout, map[string]any{"loop": loopEntry.Name, "watch": name, "ok": true})
+	}
+	if IsQuiet() {
+		return nil
+	}
+	fmt.Fprintln(os.Stdout, "ok")
+	return nil
+}
@@ -4,12 +4,14 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
 )
 
 var (
@@ -194,9 +196,28 @@ func openDatabaseWithMigration(autoMigrate bool) (*db.DB, error) {
 	}
 
 	cfg := db.Config{
-		Path:          appConfig.DatabasePath(),
-		MaxOpenConns:  10,
-		BusyTimeoutMs: 5000,
+		Path:             appConfig.DatabasePath(),
+		MaxOpenConns:     10,
+		BusyTimeoutMs:    5000,
+		JournalMode:      appConfig.Database.JournalMode,
+		Synchronous:      appConfig.Database.Synchronous,
+		BusyRetries:      appConfig.Database.BusyRetries,
+		BusyRetryBackoff: appConfig.Database.BusyRetryBackoff,
+		CacheTTL:         appConfig.Database.EffectiveCacheTTL(),
+		ReadOnly:         appConfig.Database.ReadOnly || readOnlyDatabase,
+	}
+	if !allNamespaces {
+		namespace, err := models.NormalizeNamespace(appConfig.Database.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Namespace = namespace
+	}
+	if appConfig.Database.MaxConnections > 0 {
+		cfg.MaxOpenConns = appConfig.Database.MaxConnections
+	}
+	if appConfig.Database.BusyTimeoutMs > 0 {
+		cfg.BusyTimeoutMs = appConfig.Database.BusyTimeoutMs
 	}
 
 	database, err := db.Open(cfg)
@@ -204,7 +225,9 @@ func openDatabaseWithMigration(autoMigrate bool) (*db.DB, error) {
 		return nil, err
 	}
 
-	if autoMigrate {
+	if cfg.ReadOnly {
+		warnIfSchemaBehind(database)
+	} else if autoMigrate {
 		if err := autoMigrateDatabase(database); err != nil {
 			_ = database.Close()
 			return nil, err
@@ -214,6 +237,21 @@ func openDatabaseWithMigration(autoMigrate bool) (*db.DB, error) {
 	return database, nil
 }
 
+// warnIfSchemaBehind logs when a read-only database has migrations pending;
+// they are left to the writer.
+func warnIfSchemaBehind(database *db.DB) {
+	version, err := database.SchemaVersion(context.Background())
+	if err != nil {
+		return
+	}
+	if latest, err := db.LatestSchemaVersion(); err == nil && version < latest {
+		logger.Warn().
+			Int("version", version).
+			Int("latest", latest).
+			Msg("read-only database has pending migrations; newer tables may be missing until the writer migrates")
+	}
+}
+
 func autoMigrateDatabase(database *db.DB) error {
 	if database == nil {
 		return fmt.Errorf("database is required")
@@ -232,6 +270,11 @@ func autoMigrateDatabase(database *db.DB) error {
 	}
 
 	applied, err := database.MigrateUp(ctx)
+	if errors.Is(err, db.ErrSchemaAhead) {
+		// Newer migrations are additive; keep working but tell the operator.
+		logger.Warn().Err(err).Msg("database was migrated by a newer forge")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("auto-migrate failed: %w", err)
 	}
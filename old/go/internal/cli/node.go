@@ -26,6 +26,7 @@ var (
 	nodeAddLocal   bool
 	nodeAddKeyPath string
 	nodeAddNoTest  bool
+	nodeAddLabels  []string
 
 	// Node remove flags
 	nodeRemoveForce bool
@@ -53,6 +54,7 @@ func init() {
 	nodeAddCmd.Flags().BoolVar(&nodeAddLocal, "local", false, "mark as local node (no SSH)")
 	nodeAddCmd.Flags().StringVar(&nodeAddKeyPath, "key", "", "path to SSH private key")
 	nodeAddCmd.Flags().BoolVar(&nodeAddNoTest, "no-test", false, "skip connection test")
+	nodeAddCmd.Flags().StringArrayVar(&nodeAddLabels, "label", nil, "node label key=value for placement constraints (repeatable)")
 	if err := nodeAddCmd.MarkFlagRequired("name"); err != nil {
 		panic(err)
 	}
@@ -114,9 +116,10 @@ var nodeListCmd = &cobra.Command{
 					formatYesNo(n.IsLocal),
 					sshTarget,
 					fmt.Sprintf("%d", n.AgentCount),
+					formatNodeLabelsCell(n.Labels),
 				})
 			}
-			return writeTable(os.Stdout, []string{"NAME", "ID", "STATUS", "LOCAL", "SSH", "AGENTS"}, rows)
+			return writeTable(os.Stdout, []string{"NAME", "ID", "STATUS", "LOCAL", "SSH", "AGENTS", "LABELS"}, rows)
 		}
 
 		return WriteOutput(os.Stdout, nodes)
@@ -142,7 +145,10 @@ By default, the connection is tested before adding. Use --no-test to skip.`,
   forge node add --name staging --ssh deploy@staging.example.com:2222 --key ~/.ssh/staging_key
 
   # Add the local machine
-  forge node add --name localhost --local`,
+  forge node add --name localhost --local
+
+  # Add a labeled node for constraint-based placement
+  forge node add --name gpu-box --ssh ubuntu@10.0.0.5 --label gpu=true --label region=eu`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
@@ -153,6 +159,10 @@ By default, the connection is tested before adding. Use --no-test to skip.`,
 		if nodeAddLocal && nodeAddSSH != "" {
 			return errors.New("--ssh and --local are mutually exclusive")
 		}
+		labels, err := parseNodeLabelFlags(nodeAddLabels)
+		if err != nil {
+			return err
+		}
 
 		database, err := openDatabase()
 		if err != nil {
@@ -169,6 +179,7 @@ By default, the connection is tested before adding. Use --no-test to skip.`,
 			SSHTarget:  nodeAddSSH,
 			SSHKeyPath: nodeAddKeyPath,
 			Status:     models.NodeStatusUnknown,
+			Labels:     labels,
 		}
 
 		testConnection := !nodeAddNoTest && !nodeAddLocal
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+)
+
+func init() {
+	nodeCmd.AddCommand(nodeLabelCmd)
+}
+
+var nodeLabelCmd = &cobra.Command{
+	Use:   "label <name-or-id> [key=value|key-]...",
+	Short: "Show or change node labels",
+	Long: `Show or change the labels used for constraint-based placement.
+
+key=value adds or replaces a label; key- removes it. With no label
+arguments the node's current labels are printed.
+
+Workspaces and agents select or check nodes with --constraint, which
+accepts key=value, key!=value, or key (label present).`,
+	Example: `  forge node label gpu-box gpu=true region=eu
+  forge node label gpu-box region-
+  forge ws create --path ~/repo --constraint gpu=true`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		set, remove, err := parseNodeLabelArgs(args[1:])
+		if err != nil {
+			return err
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		repo := db.NewNodeRepository(database)
+		service := node.NewService(repo, node.WithPublisher(newEventPublisher(database)))
+
+		n, err := findNode(ctx, service, args[0])
+		if err != nil {
+			return err
+		}
+		if len(set) > 0 || len(remove) > 0 {
+			n, err = service.SetLabels(ctx, n.ID, set, remove)
+			if err != nil {
+				return fmt.Errorf("failed to update labels: %w", err)
+			}
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			labels := n.Labels
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			return WriteOutput(os.Stdout, map[string]any{
+				"node_id": n.ID,
+				"name":    n.Name,
+				"labels":  labels,
+			})
+		}
+
+		if len(n.Labels) == 0 {
+			fmt.Printf("Node '%s' has no labels\n", n.Name)
+			return nil
+		}
+		for _, pair := range strings.Split(models.FormatNodeLabels(n.Labels), ",") {
+			fmt.Println(pair)
+		}
+		return nil
+	},
+}
+
+// parseNodeLabelArgs splits label arguments into assignments (key=value)
+// and removals (key-).
+func parseNodeLabelArgs(args []string) (map[string]string, []string, error) {
+	set := make(map[string]string)
+	var remove []string
+	for _, arg := range args {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			if err := models.ValidateNodeLabelKey(key); err != nil {
+				return nil, nil, err
+			}
+			remove = append(remove, key)
+			continue
+		}
+		key, value, err := models.ParseNodeLabel(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		set[key] = value
+	}
+	return set, remove, nil
+}
+
+// parseNodeLabelFlags parses repeated --label key=value flags.
+func parseNodeLabelFlags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, err := models.ParseNodeLabel(value)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = val
+	}
+	return labels, nil
+}
+
+func formatNodeLabelsCell(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	return models.FormatNodeLabels(labels)
+}
@@ -0,0 +1,163 @@
+// Package cli provides the Forge command-line interface.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/notify"
+)
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifyWatchCmd)
+	notifyCmd.AddCommand(notifyTestCmd)
+	notifyCmd.AddCommand(notifySinksCmd)
+}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Deliver event notifications to Slack, webhooks and email",
+	Long: `Deliver event notifications to external sinks.
+
+Sinks (webhook, slack, email) and rules are configured under
+'notifications' in config.yaml. Rules fire on named triggers
+(agent_error, disk_critical, loop_finished, loop_failed) or raw event types,
+and render message titles and bodies from Go templates.`,
+}
+
+var notifyWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow the event log and send notifications",
+	Long: `Follow the event log and send notifications for new events until
+interrupted. Use --since to replay recent events.`,
+	Example: `  forge notify watch
+  forge notify watch --since 10m`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		cfg := notificationsConfig()
+		if !cfg.Enabled {
+			return fmt.Errorf("notifications are disabled (notifications.enabled)")
+		}
+		if len(cfg.Rules) == 0 {
+			return fmt.Errorf("no notification rules configured (notifications.rules)")
+		}
+		notifier, err := notify.New(cfg)
+		if err != nil {
+			return err
+		}
+
+		since := time.Now().UTC()
+		if sinceFlag, err := GetSinceTime(); err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		} else if sinceFlag != nil {
+			since = *sinceFlag
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if !IsJSONOutput() && !IsJSONLOutput() {
+			fmt.Fprintf(os.Stderr, "Watching events (%d rule(s), %d sink(s)); Ctrl+C to stop\n", len(cfg.Rules), len(cfg.Sinks))
+		}
+		return notifier.Watch(ctx, db.NewEventRepository(database), cfg.PollInterval, since)
+	},
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test <sink>",
+	Short: "Send a test notification to a sink",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifier, err := notify.New(notificationsConfig())
+		if err != nil {
+			return err
+		}
+		if err := notifier.Test(context.Background(), args[0]); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{"sink": args[0], "sent": true})
+		}
+		fmt.Printf("Test notification sent to '%s'\n", args[0])
+		return nil
+	},
+}
+
+var notifySinksCmd = &cobra.Command{
+	Use:     "sinks",
+	Aliases: []string{"ls"},
+	Short:   "List configured notification sinks",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := notificationsConfig()
+
+		rules := make(map[string]int, len(cfg.Sinks))
+		for _, rule := range cfg.Rules {
+			for _, sink := range rule.Sinks {
+				rules[sink]++
+			}
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			sinks := make([]map[string]any, 0, len(cfg.Sinks))
+			for _, sink := range cfg.Sinks {
+				sinks = append(sinks, map[string]any{
+					"name":   sink.Name,
+					"type":   sink.Type,
+					"target": notifySinkTarget(sink),
+					"rules":  rules[sink.Name],
+				})
+			}
+			return WriteOutput(os.Stdout, sinks)
+		}
+		if len(cfg.Sinks) == 0 {
+			fmt.Fprintln(os.Stdout, "No notification sinks configured")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(cfg.Sinks))
+		for _, sink := range cfg.Sinks {
+			rows = append(rows, []string{sink.Name, sink.Type, notifySinkTarget(sink), strconv.Itoa(rules[sink.Name])})
+		}
+		return writeTable(os.Stdout, []string{"NAME", "TYPE", "TARGET", "RULES"}, rows)
+	},
+}
+
+func notificationsConfig() config.NotificationsConfig {
+	cfg := GetConfig()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return cfg.Notifications
+}
+
+// notifySinkTarget describes where a sink delivers without printing
+// webhook URLs, which usually embed a secret.
+func notifySinkTarget(sink config.NotificationSinkConfig) string {
+	switch sink.Type {
+	case config.NotificationSinkEmail:
+		return fmt.Sprintf("%s (%d recipient(s))", sink.SMTPHost, len(sink.To))
+	case config.NotificationSinkSlack:
+		if sink.Channel != "" {
+			return sink.Channel
+		}
+		return "webhook default channel"
+	default:
+		return "webhook"
+	}
+}
@@ -90,7 +90,7 @@ func maybeAutoImportWorkspaces(ctx context.Context) {
 	nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 	wsRepo := db.NewWorkspaceRepository(database)
 	agentRepo := db.NewAgentRepository(database)
-	wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+	wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 	report, err := wsService.RecoverOrphanedSessions(ctx, "", appConfig.WorkspaceDefaults.TmuxPrefix)
 	if err != nil {
@@ -183,6 +183,8 @@ func shouldRunPreflight(cmd *cobra.Command) bool {
 		return false
 	case strings.HasPrefix(path, "forge vault"):
 		return false
+	case strings.HasPrefix(path, "forge config validate"):
+		return false
 	}
 
 	return true
@@ -24,6 +24,8 @@ var (
 	profileAddExtraArgs      []string
 	profileAddEnv            []string
 	profileAddMaxConcurrency int
+	profileAddMaxRunsPerHour int
+	profileAddMinRunGap      time.Duration
 
 	profileEditName           string
 	profileEditAuthKind       string
@@ -34,6 +36,8 @@ var (
 	profileEditExtraArgs      []string
 	profileEditEnv            []string
 	profileEditMaxConcurrency int
+	profileEditMaxRunsPerHour int
+	profileEditMinRunGap      time.Duration
 
 	profileCooldownUntil string
 )
@@ -61,6 +65,8 @@ func init() {
 	profileAddCmd.Flags().StringSliceVar(&profileAddExtraArgs, "extra-arg", nil, "extra argument (repeatable)")
 	profileAddCmd.Flags().StringSliceVar(&profileAddEnv, "env", nil, "environment variable (KEY=VALUE)")
 	profileAddCmd.Flags().IntVar(&profileAddMaxConcurrency, "max-concurrency", 0, "max concurrent runs for this profile")
+	profileAddCmd.Flags().IntVar(&profileAddMaxRunsPerHour, "max-runs-per-hour", 0, "max runs started per rolling hour (0 = unlimited)")
+	profileAddCmd.Flags().DurationVar(&profileAddMinRunGap, "min-run-gap", 0, "minimum time between run starts (e.g. 30s, 2m)")
 
 	profileEditCmd.Flags().StringVar(&profileEditName, "name", "", "new profile name")
 	profileEditCmd.Flags().StringVar(&profileEditAuthKind, "auth-kind", "", "auth kind (claude, codex, etc)")
@@ -71,6 +77,8 @@ func init() {
 	profileEditCmd.Flags().StringSliceVar(&profileEditExtraArgs, "extra-arg", nil, "extra argument (repeatable)")
 	profileEditCmd.Flags().StringSliceVar(&profileEditEnv, "env", nil, "environment variable (KEY=VALUE)")
 	profileEditCmd.Flags().IntVar(&profileEditMaxConcurrency, "max-concurrency", 0, "max concurrent runs for this profile")
+	profileEditCmd.Flags().IntVar(&profileEditMaxRunsPerHour, "max-runs-per-hour", 0, "max runs started per rolling hour (0 = unlimited)")
+	profileEditCmd.Flags().DurationVar(&profileEditMinRunGap, "min-run-gap", 0, "minimum time between run starts (e.g. 30s, 2m)")
 
 	profileCooldownSetCmd.Flags().StringVar(&profileCooldownUntil, "until", "", "time or duration (e.g. 1h, 2025-01-01T00:00:00Z)")
 }
@@ -119,11 +127,12 @@ var profileListCmd = &cobra.Command{
 				profile.AuthKind,
 				profile.AuthHome,
 				fmt.Sprintf("%d", profile.MaxConcurrency),
+				formatProfileRateLimit(profile),
 				cooldown,
 			})
 		}
 
-		return writeTable(os.Stdout, []string{"NAME", "HARNESS", "AUTH_KIND", "AUTH_HOME", "MAX_CONCURRENCY", "COOLDOWN"}, rows)
+		return writeTable(os.Stdout, []string{"NAME", "HARNESS", "AUTH_KIND", "AUTH_HOME", "MAX_CONCURRENCY", "RATE_LIMIT", "COOLDOWN"}, rows)
 	},
 }
 
@@ -160,16 +169,18 @@ var profileAddCmd = &cobra.Command{
 		}
 
 		profile := &models.Profile{
-			Name:            profileAddName,
-			Harness:         harnessValue,
-			AuthKind:        profileAddAuthKind,
-			AuthHome:        profileAddAuthHome,
-			PromptMode:      promptMode,
-			CommandTemplate: commandTemplate,
-			Model:           profileAddModel,
-			ExtraArgs:       profileAddExtraArgs,
-			Env:             parseEnvPairs(profileAddEnv),
-			MaxConcurrency:  maxConcurrency,
+			Name:             profileAddName,
+			Harness:          harnessValue,
+			AuthKind:         profileAddAuthKind,
+			AuthHome:         profileAddAuthHome,
+			PromptMode:       promptMode,
+			CommandTemplate:  commandTemplate,
+			Model:            profileAddModel,
+			ExtraArgs:        profileAddExtraArgs,
+			Env:              parseEnvPairs(profileAddEnv),
+			MaxConcurrency:   maxConcurrency,
+			MaxRunsPerHour:   profileAddMaxRunsPerHour,
+			MinRunGapSeconds: int(profileAddMinRunGap / time.Second),
 		}
 
 		database, err := openDatabase()
@@ -241,6 +252,12 @@ var profileEditCmd = &cobra.Command{
 		if cmd.Flags().Changed("max-concurrency") {
 			profile.MaxConcurrency = profileEditMaxConcurrency
 		}
+		if cmd.Flags().Changed("max-runs-per-hour") {
+			profile.MaxRunsPerHour = profileEditMaxRunsPerHour
+		}
+		if cmd.Flags().Changed("min-run-gap") {
+			profile.MinRunGapSeconds = int(profileEditMinRunGap / time.Second)
+		}
 
 		if err := repo.Update(context.Background(), profile); err != nil {
 			return err
@@ -489,3 +506,15 @@ func parseTimeOrDuration(value string) (time.Time, error) {
 	}
 	return parsed.UTC(), nil
 }
+
+// formatProfileRateLimit renders a profile's dispatch limits, e.g. "20/h, gap 2m0s".
+func formatProfileRateLimit(profile *models.Profile) string {
+	parts := make([]string, 0, 2)
+	if profile.MaxRunsPerHour > 0 {
+		parts = append(parts, fmt.Sprintf("%d/h", profile.MaxRunsPerHour))
+	}
+	if gap := profile.MinRunGap(); gap > 0 {
+		parts = append(parts, "gap "+gap.String())
+	}
+	return strings.Join(parts, ", ")
+}
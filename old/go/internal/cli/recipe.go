@@ -189,9 +189,9 @@ optional initial templates/sequences to run on each agent.`,
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		// Resolve workspace
 		var ws *models.Workspace
@@ -2,13 +2,16 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 var (
@@ -28,11 +31,16 @@ var (
 	logFormat      string
 	chdirPath      string
 	robotHelp      bool
+	namespaceFlag  string
+	allNamespaces  bool
 
 	// Global config loader and config
 	configLoader *config.Loader
 	appConfig    *config.Config
 	logger       zerolog.Logger
+
+	// tracingShutdown flushes spans; set by initTracing.
+	tracingShutdown func(context.Context) error
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -60,7 +68,11 @@ func Execute(version, commit, date string) error {
 	rootCmd.Version = formatVersion(version, commit, date)
 	rootCmd.SilenceErrors = true
 	rootCmd.SilenceUsage = true
-	if err := rootCmd.Execute(); err != nil {
+	defer shutdownTracing()
+	start := time.Now().UTC()
+	cmd, err := rootCmd.ExecuteC()
+	recordCLIAudit(cmd, start, err)
+	if err != nil {
 		return handleCLIError(err)
 	}
 	return nil
@@ -92,6 +104,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "override logging format (json, console)")
 	rootCmd.PersistentFlags().StringVarP(&chdirPath, "chdir", "C", "", "change working directory for this command")
 	rootCmd.PersistentFlags().BoolVar(&robotHelp, "robot-help", false, "show agent-oriented help and exit")
+	rootCmd.PersistentFlags().StringVar(&namespaceFlag, "namespace", "", "tenant namespace to use (default from config, else \"default\")")
+	rootCmd.PersistentFlags().BoolVar(&allNamespaces, "all-namespaces", false, "see workspaces, agents, loops, and events in every namespace")
 }
 
 // initConfig loads configuration using Viper with proper precedence:
@@ -119,6 +133,12 @@ func initConfig() {
 	var err error
 	appConfig, err = configLoader.Load()
 	if err != nil {
+		if invokesConfigValidate() {
+			// 'forge config validate' loads the config itself and reports
+			// every problem instead of just the first.
+			appConfig = config.DefaultConfig()
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
@@ -127,12 +147,17 @@ func initConfig() {
 
 	// Initialize logging based on config
 	initLogging()
+	initTracing()
 
 	// Ensure directories exist
 	if err := appConfig.EnsureDirectories(); err != nil {
 		logger.Warn().Err(err).Msg("failed to create directories")
 	}
 
+	for _, problem := range configLoader.Deprecated() {
+		logger.Warn().Str("key", problem.Key).Msg(problem.Message)
+	}
+
 	// Log config file used (if any)
 	if cfgUsed := configLoader.ConfigFileUsed(); cfgUsed != "" {
 		logger.Debug().Str("config_file", cfgUsed).Msg("loaded config file")
@@ -151,6 +176,10 @@ func applyCLIOverrides() {
 	if flags.Changed("log-format") {
 		appConfig.Logging.Format = logFormat
 	}
+
+	if flags.Changed("namespace") {
+		appConfig.Database.Namespace = namespaceFlag
+	}
 }
 
 // initLogging sets up the logger based on configuration
@@ -170,6 +199,24 @@ func initLogging() {
 	logger = logging.Component("cli")
 }
 
+// initTracing installs the span exporter when tracing is enabled.
+func initTracing() {
+	tracingShutdown = tracing.Setup(appConfig.Tracing)
+}
+
+// shutdownTracing flushes buffered spans before the process exits.
+func shutdownTracing() {
+	if tracingShutdown == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracingShutdown(ctx); err != nil {
+		logger.Debug().Err(err).Msg("failed to flush traces")
+	}
+	tracingShutdown = nil
+}
+
 // GetConfig returns the loaded configuration.
 // Returns nil if called before initConfig.
 func GetConfig() *config.Config {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tOgg1/forge/internal/agent"
@@ -25,6 +26,7 @@ var (
 	sendAfter     string
 	sendFront     bool
 	sendWhenIdle  bool
+	sendDeadline  string
 	sendAll       bool
 	sendImmediate bool
 	sendSkipIdle  bool
@@ -43,10 +45,10 @@ var newAgentMessageSender = func(database *db.DB) agentMessageSender {
 	wsRepo := db.NewWorkspaceRepository(database)
 	agentRepo := db.NewAgentRepository(database)
 	queueRepo := db.NewQueueRepository(database)
-	wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+	wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 	tmuxClient := tmux.NewLocalClient()
-	return agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+	return agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(context.Background(), database), tmuxClient, agentServiceOptions(database)...)
 }
 
 func init() {
@@ -56,6 +58,7 @@ func init() {
 	sendCmd.Flags().StringVar(&sendAfter, "after", "", "insert after a specific queue item (queue-only)")
 	sendCmd.Flags().BoolVar(&sendFront, "front", false, "insert at front of queue")
 	sendCmd.Flags().BoolVar(&sendWhenIdle, "when-idle", false, "only dispatch when agent is idle (conditional)")
+	sendCmd.Flags().StringVar(&sendDeadline, "deadline", "", "dispatch deadline as duration from now or RFC3339 time (e.g., 30m)")
 	sendCmd.Flags().BoolVar(&sendAll, "all", false, "send to all agents in workspace")
 	sendCmd.Flags().BoolVar(&sendImmediate, "immediate", false, "send immediately (deprecated; bypasses queue)")
 	sendCmd.Flags().BoolVar(&sendSkipIdle, "skip-idle-check", false, "send even if agent is not idle (immediate only)")
@@ -101,6 +104,9 @@ without specifying an agent ID.`,
   # Queue conditional message (only dispatch when idle)
   forge send --when-idle abc123 "Continue when ready"
 
+  # Queue with a deadline (prioritized as it approaches, flagged if missed)
+  forge send --deadline 30m abc123 "Ship the hotfix"
+
   # Queue from file
   forge send abc123 --file prompt.txt
 
@@ -123,15 +129,18 @@ without specifying an agent ID.`,
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 		_ = wsService // for future use with --all
 
-		queueService := queue.NewService(queueRepo)
+		queueService := queue.NewService(queueRepo, queueServiceOptions()...)
 
 		if sendImmediate {
 			if sendWhenIdle {
 				return errors.New("--when-idle cannot be used with --immediate")
 			}
+			if sendDeadline != "" {
+				return errors.New("--deadline cannot be used with --immediate")
+			}
 			if sendFront {
 				return errors.New("--front cannot be used with --immediate")
 			}
@@ -287,6 +296,7 @@ type queueOptions struct {
 	Front    bool
 	WhenIdle bool
 	AfterID  string
+	Deadline *time.Time
 }
 
 func resolveQueueOptions(cmd *cobra.Command) (queueOptions, error) {
@@ -305,6 +315,14 @@ func resolveQueueOptions(cmd *cobra.Command) (queueOptions, error) {
 		AfterID:  sendAfter,
 	}
 
+	if strings.TrimSpace(sendDeadline) != "" {
+		deadline, err := parseTimeOrDuration(strings.TrimSpace(sendDeadline))
+		if err != nil {
+			return queueOptions{}, fmt.Errorf("invalid --deadline: %w", err)
+		}
+		opts.Deadline = &deadline
+	}
+
 	if !opts.Front && opts.AfterID == "" && priority == "high" {
 		opts.Front = true
 	}
@@ -330,6 +348,7 @@ func enqueueMessage(ctx context.Context, queueService *queue.Service, queueRepo
 	if err != nil {
 		return sendResult{AgentID: agent.ID, Error: err.Error()}
 	}
+	item.Deadline = opts.Deadline
 
 	switch {
 	case opts.AfterID != "":
@@ -398,11 +417,15 @@ func buildQueueItem(agentID, message string, whenIdle bool) (*models.QueueItem,
 
 func writeQueueResults(message string, results []sendResult, opts queueOptions) error {
 	if IsJSONOutput() || IsJSONLOutput() {
-		return WriteOutput(os.Stdout, map[string]any{
+		payload := map[string]any{
 			"queued":  true,
 			"results": results,
 			"message": truncateMessage(message, 100),
-		})
+		}
+		if opts.Deadline != nil {
+			payload["deadline"] = opts.Deadline.UTC()
+		}
+		return WriteOutput(os.Stdout, payload)
 	}
 
 	for _, r := range results {
@@ -421,6 +444,9 @@ func writeQueueResults(message string, results []sendResult, opts queueOptions)
 		if r.ItemType == string(models.QueueItemTypeConditional) {
 			typeStr = " (when idle)"
 		}
+		if opts.Deadline != nil {
+			typeStr += fmt.Sprintf(" (deadline %s)", opts.Deadline.Local().Format("2006-01-02 15:04"))
+		}
 		fmt.Printf("✓ Queued for agent %s at position %s%s\n", shortID(r.AgentID), positionStr, typeStr)
 	}
 
@@ -247,7 +247,7 @@ var sequenceRunCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		queueService := queue.NewService(queueRepo)
+		queueService := queue.NewService(queueRepo, queueServiceOptions()...)
 
 		projectDir := resolveTemplateProjectDir(ctx, wsRepo)
 		sequencesList, err := sequences.LoadSequencesFromSearchPaths(projectDir)
@@ -247,7 +247,7 @@ var templateRunCmd = &cobra.Command{
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		queueService := queue.NewService(queueRepo)
+		queueService := queue.NewService(queueRepo, queueServiceOptions()...)
 
 		projectDir := resolveTemplateProjectDir(ctx, wsRepo)
 		templatesList, err := templates.LoadTemplatesFromSearchPaths(projectDir)
@@ -8,12 +8,18 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/looptui"
 	"golang.org/x/term"
 )
 
+// readOnlyDatabase opens the database read-only regardless of
+// database.read_only (set by tui --read-only).
+var readOnlyDatabase bool
+
 func init() {
 	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().BoolVar(&readOnlyDatabase, "read-only", false, "open the database read-only (actions that write will fail)")
 }
 
 var uiCmd = &cobra.Command{
@@ -50,8 +56,13 @@ func runTUI() error {
 		loopConfig.DefaultInterval = cfg.LoopDefaults.Interval
 		loopConfig.DefaultPrompt = cfg.LoopDefaults.Prompt
 		loopConfig.DefaultPromptMsg = cfg.LoopDefaults.PromptMsg
+		loopConfig.Keybindings = cfg.Keybindings
+		loopConfig.ListColumns = cfg.TUI.LoopColumns
+		loopConfig.ListSort = cfg.TUI.LoopSort
+		loopConfig.GitStatusInterval = cfg.WorkspaceDefaults.GitStatusInterval
 	}
 	loopConfig.ConfigFile = cfgFile
+	loopConfig.TemplateDir = loop.TemplateDir(getConfigDir())
 
 	return looptui.Run(database, loopConfig)
 }
@@ -24,11 +24,13 @@ import (
 
 var (
 	// ws create flags
-	wsCreatePath    string
-	wsCreateNode    string
-	wsCreateName    string
-	wsCreateSession string
-	wsCreateNoTmux  bool
+	wsCreatePath        string
+	wsCreateNode        string
+	wsCreateName        string
+	wsCreateSession     string
+	wsCreateNoTmux      bool
+	wsCreateConstraints []string
+	wsCreateTemplate    string
 
 	// ws import flags
 	wsImportSession  string
@@ -66,6 +68,8 @@ func init() {
 	wsCreateCmd.Flags().StringVar(&wsCreateName, "name", "", "workspace name (default: derived from path)")
 	wsCreateCmd.Flags().StringVar(&wsCreateSession, "session", "", "tmux session name (default: auto-generated)")
 	wsCreateCmd.Flags().BoolVar(&wsCreateNoTmux, "no-tmux", false, "don't create tmux session")
+	wsCreateCmd.Flags().StringArrayVar(&wsCreateConstraints, "constraint", nil, "node label constraint key=value, key!=value, or key (repeatable)")
+	wsCreateCmd.Flags().StringVar(&wsCreateTemplate, "template", "", "workspace template to clone and bootstrap from (see 'forge ws template')")
 	if err := wsCreateCmd.MarkFlagRequired("path"); err != nil {
 		panic(err)
 	}
@@ -108,7 +112,11 @@ var wsCreateCmd = &cobra.Command{
 	Short: "Create a new workspace",
 	Long: `Create a new workspace for a repository.
 
-By default, a tmux session is created in the repository directory.`,
+By default, a tmux session is created in the repository directory.
+
+With --template, the template's repository is cloned into --path when it does
+not exist yet, and its bootstrap commands run in a 'bootstrap' tmux window
+before the command returns.`,
 	Example: `  # Create workspace for current directory
   forge ws create --path .
 
@@ -116,10 +124,21 @@ By default, a tmux session is created in the repository directory.`,
   forge ws create --path /home/user/myproject --name my-project
 
   # Create on a specific node
-  forge ws create --path /data/repos/api --node prod-server`,
+  forge ws create --path /data/repos/api --node prod-server
+
+  # Create on the least loaded node labeled gpu=true
+  forge ws create --path /data/repos/train --constraint gpu=true
+
+  # Clone and bootstrap from a template
+  forge ws create --template api-service --path ~/src/api`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
+		constraints, err := models.ParseNodeConstraints(wsCreateConstraints)
+		if err != nil {
+			return err
+		}
+
 		database, err := openDatabase()
 		if err != nil {
 			return err
@@ -132,7 +151,22 @@ By default, a tmux session is created in the repository directory.`,
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		eventRepo := db.NewEventRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithEventRepository(eventRepo), workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo,
+			workspace.WithEventRepository(eventRepo),
+			workspace.WithPublisher(newEventPublisher(database)),
+			workspace.WithTemplateRepository(db.NewWorkspaceTemplateRepository(database)),
+		)
+
+		var tmpl *models.WorkspaceTemplate
+		if wsCreateTemplate != "" {
+			tmpl, err = findWorkspaceTemplate(ctx, database, wsCreateTemplate)
+			if err != nil {
+				return err
+			}
+			if wsCreateNoTmux && len(tmpl.Bootstrap) > 0 {
+				return fmt.Errorf("template %q has bootstrap commands, which need a tmux session (drop --no-tmux)", tmpl.Name)
+			}
+		}
 
 		// Resolve node ID if name provided
 		nodeID := ""
@@ -146,10 +180,12 @@ By default, a tmux session is created in the repository directory.`,
 
 		input := workspace.CreateWorkspaceInput{
 			NodeID:            nodeID,
+			NodeConstraints:   constraints,
 			RepoPath:          wsCreatePath,
 			Name:              wsCreateName,
 			TmuxSession:       wsCreateSession,
 			CreateTmuxSession: !wsCreateNoTmux,
+			Template:          tmpl,
 		}
 
 		step := startProgress("Creating workspace")
@@ -166,7 +202,25 @@ By default, a tmux session is created in the repository directory.`,
 		}
 		step.Done()
 
+		var bootstrap *models.WorkspaceBootstrap
+		var bootstrapErr error
+		if tmpl != nil && len(tmpl.Bootstrap) > 0 {
+			step = startProgress("Bootstrapping workspace")
+			bootstrap, bootstrapErr = wsService.RunBootstrap(ctx, ws, tmpl, 0)
+			if bootstrapErr != nil {
+				step.Fail(bootstrapErr)
+			} else {
+				step.Done()
+			}
+		}
+
 		if IsJSONOutput() || IsJSONLOutput() {
+			if tmpl != nil {
+				if err := WriteOutput(os.Stdout, map[string]any{"workspace": ws, "bootstrap": bootstrap}); err != nil {
+					return err
+				}
+				return bootstrapErr
+			}
 			return WriteOutput(os.Stdout, ws)
 		}
 
@@ -179,11 +233,19 @@ By default, a tmux session is created in the repository directory.`,
 			fmt.Printf("  Branch:  %s\n", ws.GitInfo.Branch)
 		}
 
+		if bootstrap != nil {
+			fmt.Println()
+			printBootstrapRun(bootstrap)
+			if bootstrapErr != nil && bootstrap.Output != "" {
+				fmt.Printf("\n%s\n", bootstrapOutputTail(bootstrap.Output, 20))
+			}
+		}
+
 		if !wsCreateNoTmux {
 			fmt.Printf("\nAttach with: tmux attach -t %s\n", ws.TmuxSession)
 		}
 
-		return nil
+		return bootstrapErr
 	},
 }
 
@@ -288,7 +350,7 @@ var wsListCmd = &cobra.Command{
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		// Build options
 		opts := workspace.ListWorkspacesOptions{
@@ -357,7 +419,7 @@ var wsListCmd = &cobra.Command{
 var wsStatusCmd = &cobra.Command{
 	Use:   "status <id-or-name>",
 	Short: "Show workspace status",
-	Long:  "Display detailed status for a workspace including git info and agent states.",
+	Long:  "Display detailed status for a workspace including git state (branch, changed files, ahead/behind upstream) and agent states.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
@@ -373,7 +435,7 @@ var wsStatusCmd = &cobra.Command{
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		// Find workspace
 		ws, err := findWorkspace(ctx, wsRepo, idOrName)
@@ -403,15 +465,8 @@ var wsStatusCmd = &cobra.Command{
 		fmt.Printf("Tmux Active:   %v\n", status.TmuxActive)
 		fmt.Println()
 
-		if status.GitInfo != nil {
-			fmt.Printf("Git:\n")
-			fmt.Printf("  Branch:   %s\n", status.GitInfo.Branch)
-			if status.GitInfo.LastCommit != "" {
-				fmt.Printf("  Commit:   %s\n", truncate(status.GitInfo.LastCommit, 12))
-			}
-			if status.GitInfo.RemoteURL != "" {
-				fmt.Printf("  Remote:   %s\n", status.GitInfo.RemoteURL)
-			}
+		if status.GitInfo != nil && status.GitInfo.IsRepo {
+			printWorkspaceGitStatus(status.GitInfo)
 			fmt.Println()
 		}
 
@@ -435,6 +490,38 @@ var wsStatusCmd = &cobra.Command{
 	},
 }
 
+// wsStatusDirtyFiles caps how many changed files `ws status` lists.
+const wsStatusDirtyFiles = 10
+
+func printWorkspaceGitStatus(info *models.GitInfo) {
+	fmt.Printf("Git:\n")
+	fmt.Printf("  Branch:   %s\n", info.Branch)
+	if info.Upstream != "" {
+		fmt.Printf("  Upstream: %s (ahead %d, behind %d)\n", info.Upstream, info.Ahead, info.Behind)
+	}
+	if info.LastCommit != "" {
+		fmt.Printf("  Commit:   %s\n", truncate(info.LastCommit, 12))
+	}
+	if info.RemoteURL != "" {
+		fmt.Printf("  Remote:   %s\n", info.RemoteURL)
+	}
+	if !info.IsDirty {
+		fmt.Printf("  Changes:  clean\n")
+	} else {
+		fmt.Printf("  Changes:  %d file(s)\n", info.DirtyCount)
+		for i, change := range info.DirtyFiles {
+			if i == wsStatusDirtyFiles {
+				fmt.Printf("    ... and %d more\n", info.DirtyCount-wsStatusDirtyFiles)
+				break
+			}
+			fmt.Printf("    %s %s\n", change.Status, change.Path)
+		}
+	}
+	if info.CheckedAt != nil {
+		fmt.Printf("  Checked:  %s\n", formatRelativeTime(*info.CheckedAt))
+	}
+}
+
 type beadsStatusReport struct {
 	Workspace      *models.Workspace   `json:"workspace"`
 	IssuesPath     string              `json:"issues_path"`
@@ -551,7 +638,7 @@ var wsAttachCmd = &cobra.Command{
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		// Find workspace
 		ws, err := findWorkspace(ctx, wsRepo, idOrName)
@@ -607,7 +694,7 @@ Use --destroy to also kill the tmux session.`,
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		// Find workspace
 		ws, err := findWorkspace(ctx, wsRepo, idOrName)
@@ -686,10 +773,10 @@ and removing the Forge record.`,
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
 		queueRepo := db.NewQueueRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		tmuxClient := tmux.NewLocalClient()
-		agentService := agent.NewService(agentRepo, queueRepo, wsService, nil, tmuxClient, agentServiceOptions(database)...)
+		agentService := agent.NewService(agentRepo, queueRepo, wsService, newAgentAccountService(ctx, database), tmuxClient, agentServiceOptions(database)...)
 
 		ws, err := findWorkspace(ctx, wsRepo, idOrName)
 		if err != nil {
@@ -756,7 +843,7 @@ var wsRefreshCmd = &cobra.Command{
 		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
 		wsRepo := db.NewWorkspaceRepository(database)
 		agentRepo := db.NewAgentRepository(database)
-		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspace.WithPublisher(newEventPublisher(database)))
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
 
 		var workspaces []*models.Workspace
 
@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/workspace"
+)
+
+var (
+	wsSnapshotLabel   string
+	wsRestoreNoSafety bool
+	wsSnapshotRmForce bool
+)
+
+func init() {
+	wsCmd.AddCommand(wsSnapshotCmd)
+	wsSnapshotCmd.AddCommand(wsSnapshotCreateCmd)
+	wsSnapshotCmd.AddCommand(wsSnapshotListCmd)
+	wsSnapshotCmd.AddCommand(wsSnapshotRestoreCmd)
+	wsSnapshotCmd.AddCommand(wsSnapshotRemoveCmd)
+
+	wsSnapshotCreateCmd.Flags().StringVar(&wsSnapshotLabel, "label", "", "note stored with the snapshot")
+	wsSnapshotRestoreCmd.Flags().BoolVar(&wsRestoreNoSafety, "no-safety-snapshot", false, "don't snapshot the current state before restoring")
+	wsSnapshotRemoveCmd.Flags().BoolVarP(&wsSnapshotRmForce, "force", "f", false, "skip confirmation")
+}
+
+var wsSnapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Aliases: []string{"snap"},
+	Short:   "Snapshot and restore workspace files",
+	Long: `Snapshot a workspace directory and roll it back later.
+
+A snapshot is a compressed tarball of the whole workspace directory (including
+.git/) plus the workspace and agent records at that moment. Snapshots are kept
+under workspace_defaults.snapshot_dir (default <data_dir>/snapshots); only the
+newest workspace_defaults.snapshot_retention per workspace are kept.
+
+Restoring replaces the workspace contents with the snapshot. The current state
+is snapshotted first, so a restore can itself be undone. Only workspaces on
+the local node are supported.`,
+	Example: `  # Snapshot before letting an agent loose
+  forge ws snapshot create api --label "before refactor"
+
+  # Roll back to the newest snapshot
+  forge ws snapshot restore api latest`,
+}
+
+var wsSnapshotCreateCmd = &cobra.Command{
+	Use:   "create <workspace>",
+	Short: "Snapshot a workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		wsRepo := db.NewWorkspaceRepository(database)
+		wsService := newSnapshotWorkspaceService(database)
+
+		ws, err := findWorkspace(ctx, wsRepo, args[0])
+		if err != nil {
+			return err
+		}
+
+		step := startProgress("Snapshotting workspace")
+		snap, err := wsService.Snapshot(ctx, ws.ID, workspace.SnapshotOptions{Label: wsSnapshotLabel})
+		if err != nil {
+			step.Fail(err)
+			return fmt.Errorf("failed to snapshot workspace: %w", err)
+		}
+		step.Done()
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, snap)
+		}
+
+		fmt.Printf("Snapshot %s of '%s' created (%d files, %s)\n", snap.ID, ws.Name, snap.Files, formatByteSize(snap.Size))
+		return nil
+	},
+}
+
+var wsSnapshotListCmd = &cobra.Command{
+	Use:     "list <workspace>",
+	Aliases: []string{"ls"},
+	Short:   "List workspace snapshots",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		wsRepo := db.NewWorkspaceRepository(database)
+		wsService := newSnapshotWorkspaceService(database)
+
+		ws, err := findWorkspace(ctx, wsRepo, args[0])
+		if err != nil {
+			return err
+		}
+
+		snapshots, err := wsService.ListSnapshots(ctx, ws.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots: %w", err)
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, snapshots)
+		}
+		if len(snapshots) == 0 {
+			fmt.Fprintf(os.Stdout, "No snapshots for workspace '%s'\n", ws.Name)
+			return nil
+		}
+
+		rows := make([][]string, 0, len(snapshots))
+		for _, snap := range snapshots {
+			label := snap.Label
+			if label == "" {
+				label = "-"
+			}
+			rows = append(rows, []string{
+				snap.ID,
+				formatRelativeTime(snap.CreatedAt),
+				strconv.Itoa(snap.Files),
+				formatByteSize(snap.Size),
+				label,
+			})
+		}
+		return writeTable(os.Stdout, []string{"ID", "CREATED", "FILES", "SIZE", "LABEL"}, rows)
+	},
+}
+
+var wsSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <workspace> <snapshot-id|latest>",
+	Short: "Restore a workspace from a snapshot",
+	Long: `Replace the workspace directory contents with a snapshot.
+
+Files created since the snapshot are removed. Stop agents working in the
+workspace first; the current state is snapshotted before restoring unless
+--no-safety-snapshot is given.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		wsRepo := db.NewWorkspaceRepository(database)
+		wsService := newSnapshotWorkspaceService(database)
+
+		ws, err := findWorkspace(ctx, wsRepo, args[0])
+		if err != nil {
+			return err
+		}
+		snapshotID, err := resolveSnapshotID(ctx, wsService, ws.ID, args[1])
+		if err != nil {
+			return err
+		}
+
+		impact := fmt.Sprintf("This will replace all files in %s with snapshot %s.", ws.RepoPath, snapshotID)
+		if wsRestoreNoSafety {
+			impact += " The current state will NOT be saved."
+		}
+		if ws.AgentCount > 0 {
+			impact += fmt.Sprintf(" %d agent(s) are still attached to this workspace.", ws.AgentCount)
+		}
+		if !ConfirmDestructiveAction("workspace", ws.Name, impact) {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+
+		step := startProgress("Restoring workspace")
+		result, err := wsService.Restore(ctx, ws.ID, snapshotID, workspace.RestoreOptions{SkipSafetySnapshot: wsRestoreNoSafety})
+		if err != nil {
+			step.Fail(err)
+			return fmt.Errorf("failed to restore workspace: %w", err)
+		}
+		step.Done()
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, result)
+		}
+
+		fmt.Printf("Workspace '%s' restored from snapshot %s (%d files)\n", ws.Name, snapshotID, result.Files)
+		if result.SafetySnapshot != nil {
+			fmt.Printf("Previous state saved as snapshot %s\n", result.SafetySnapshot.ID)
+		}
+		return nil
+	},
+}
+
+var wsSnapshotRemoveCmd = &cobra.Command{
+	Use:     "rm <workspace> <snapshot-id>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Delete a workspace snapshot",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		wsRepo := db.NewWorkspaceRepository(database)
+		wsService := newSnapshotWorkspaceService(database)
+
+		ws, err := findWorkspace(ctx, wsRepo, args[0])
+		if err != nil {
+			return err
+		}
+		snapshotID, err := resolveSnapshotID(ctx, wsService, ws.ID, args[1])
+		if err != nil {
+			return err
+		}
+
+		if !wsSnapshotRmForce && !ConfirmDestructiveAction("snapshot", snapshotID, "The snapshot archive will be deleted.") {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+		if err := wsService.DeleteSnapshot(ctx, ws.ID, snapshotID); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{
+				"deleted":      true,
+				"workspace_id": ws.ID,
+				"snapshot_id":  snapshotID,
+			})
+		}
+		fmt.Printf("Snapshot %s deleted\n", snapshotID)
+		return nil
+	},
+}
+
+// newSnapshotWorkspaceService builds a workspace service with the configured
+// snapshot directory and retention.
+func newSnapshotWorkspaceService(database *db.DB) *workspace.Service {
+	nodeRepo := db.NewNodeRepository(database)
+	nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
+	wsRepo := db.NewWorkspaceRepository(database)
+	agentRepo := db.NewAgentRepository(database)
+
+	opts := workspaceServiceOptions(database)
+	if cfg := GetConfig(); cfg != nil {
+		opts = append(opts,
+			workspace.WithSnapshotDir(cfg.SnapshotPath()),
+			workspace.WithSnapshotRetention(cfg.WorkspaceDefaults.SnapshotRetention),
+		)
+	}
+	return workspace.NewService(wsRepo, nodeService, agentRepo, opts...)
+}
+
+// resolveSnapshotID maps "latest" to the newest snapshot of a workspace.
+func resolveSnapshotID(ctx context.Context, wsService *workspace.Service, workspaceID, ref string) (string, error) {
+	if ref != "latest" {
+		return ref, nil
+	}
+	snapshots, err := wsService.ListSnapshots(ctx, workspaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return "", errors.New("workspace has no snapshots")
+	}
+	return snapshots[0].ID, nil
+}
+
+func formatByteSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
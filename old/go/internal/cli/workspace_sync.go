@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/workspace"
+)
+
+var (
+	wsSyncPull     bool
+	wsSyncPush     bool
+	wsSyncLocal    string
+	wsSyncExcludes []string
+	wsSyncDryRun   bool
+	wsSyncDelete   bool
+	wsSyncForce    bool
+)
+
+func init() {
+	wsCmd.AddCommand(wsSyncCmd)
+
+	wsSyncCmd.Flags().BoolVar(&wsSyncPull, "pull", false, "copy the node workspace to the local directory")
+	wsSyncCmd.Flags().BoolVar(&wsSyncPush, "push", false, "copy the local directory to the node workspace")
+	wsSyncCmd.Flags().StringVar(&wsSyncLocal, "local", ".", "local directory to sync with")
+	wsSyncCmd.Flags().StringArrayVar(&wsSyncExcludes, "exclude", nil, "additional exclude pattern (repeatable)")
+	wsSyncCmd.Flags().BoolVar(&wsSyncDryRun, "dry-run", false, "show what would change without transferring")
+	wsSyncCmd.Flags().BoolVar(&wsSyncDelete, "delete", false, "delete destination files missing from the source")
+	wsSyncCmd.Flags().BoolVarP(&wsSyncForce, "force", "f", false, "overwrite destination files that are newer than the source")
+}
+
+var wsSyncCmd = &cobra.Command{
+	Use:   "sync <id-or-name>",
+	Short: "Sync workspace files with the local machine",
+	Long: `Sync files between a workspace on its node and a local directory.
+
+Transfers use rsync over the node's SSH settings. .git/ and .forge/ are always
+excluded; add patterns with --exclude or a .forgesyncignore file in the local
+directory. Files that are newer at the destination are reported as conflicts
+and block the transfer unless --force is given.`,
+	Example: `  # Preview what an agent changed on a remote node
+  forge ws sync api --pull --local ./api --dry-run
+
+  # Pull the changes
+  forge ws sync api --pull --local ./api
+
+  # Push local edits back to the node
+  forge ws sync api --push --local ./api`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		if wsSyncPull == wsSyncPush {
+			return errors.New("specify exactly one of --pull or --push")
+		}
+		direction := workspace.SyncPull
+		if wsSyncPush {
+			direction = workspace.SyncPush
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		nodeRepo := db.NewNodeRepository(database)
+		nodeService := node.NewService(nodeRepo, node.WithPublisher(newEventPublisher(database)))
+		wsRepo := db.NewWorkspaceRepository(database)
+		agentRepo := db.NewAgentRepository(database)
+		wsService := workspace.NewService(wsRepo, nodeService, agentRepo, workspaceServiceOptions(database)...)
+
+		ws, err := findWorkspace(ctx, wsRepo, args[0])
+		if err != nil {
+			return err
+		}
+
+		step := startProgress("Syncing workspace")
+		result, err := wsService.SyncWorkspace(ctx, ws.ID, workspace.SyncOptions{
+			Direction: direction,
+			LocalPath: wsSyncLocal,
+			Excludes:  wsSyncExcludes,
+			DryRun:    wsSyncDryRun,
+			Delete:    wsSyncDelete,
+			Force:     wsSyncForce,
+		})
+		if err != nil {
+			step.Fail(err)
+			if result != nil && errors.Is(err, workspace.ErrSyncConflict) && !IsJSONOutput() && !IsJSONLOutput() {
+				fmt.Fprintln(os.Stderr, "Conflicting files (newer at destination):")
+				for _, path := range result.Conflicts {
+					fmt.Fprintf(os.Stderr, "  %s\n", path)
+				}
+			}
+			return fmt.Errorf("failed to sync workspace: %w", err)
+		}
+		step.Done()
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, result)
+		}
+
+		verb := "Synced"
+		if result.DryRun {
+			verb = "Would sync"
+		}
+		fmt.Printf("%s %s → %s (%d change(s))\n", verb, result.Source, result.Destination, len(result.Changes))
+		if len(result.Changes) > 0 {
+			rows := make([][]string, 0, len(result.Changes))
+			for _, change := range result.Changes {
+				path := change.Path
+				if change.IsDir {
+					path += "/"
+				}
+				rows = append(rows, []string{string(change.Kind), path})
+			}
+			if err := writeTable(os.Stdout, []string{"CHANGE", "PATH"}, rows); err != nil {
+				return err
+			}
+		}
+		if len(result.Conflicts) > 0 {
+			fmt.Printf("\nConflicts (newer at destination):\n")
+			for _, path := range result.Conflicts {
+				fmt.Printf("  %s\n", path)
+			}
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+var (
+	wsTemplateDescription string
+	wsTemplateGitURL      string
+	wsTemplateBranch      string
+	wsTemplateBootstrap   []string
+	wsTemplateEnv         []string
+	wsTemplateReplace     bool
+	wsTemplateRmForce     bool
+)
+
+func init() {
+	wsCmd.AddCommand(wsTemplateCmd)
+	wsCmd.AddCommand(wsBootstrapCmd)
+	wsTemplateCmd.AddCommand(wsTemplateAddCmd)
+	wsTemplateCmd.AddCommand(wsTemplateListCmd)
+	wsTemplateCmd.AddCommand(wsTemplateShowCmd)
+	wsTemplateCmd.AddCommand(wsTemplateRemoveCmd)
+
+	wsTemplateAddCmd.Flags().StringVar(&wsTemplateDescription, "description", "", "template description")
+	wsTemplateAddCmd.Flags().StringVar(&wsTemplateGitURL, "git-url", "", "repository to clone when the workspace path does not exist")
+	wsTemplateAddCmd.Flags().StringVar(&wsTemplateBranch, "branch", "", "branch to check out when cloning")
+	wsTemplateAddCmd.Flags().StringArrayVar(&wsTemplateBootstrap, "bootstrap", nil, "bootstrap command, run in order (repeatable)")
+	wsTemplateAddCmd.Flags().StringArrayVar(&wsTemplateEnv, "env", nil, "environment variable KEY=VALUE for bootstrap (repeatable)")
+	wsTemplateAddCmd.Flags().BoolVar(&wsTemplateReplace, "replace", false, "replace an existing template with the same name")
+	wsTemplateRemoveCmd.Flags().BoolVarP(&wsTemplateRmForce, "force", "f", false, "skip confirmation")
+}
+
+var wsTemplateCmd = &cobra.Command{
+	Use:     "template",
+	Aliases: []string{"templates", "tmpl"},
+	Short:   "Manage workspace templates",
+	Long: `Manage workspace templates.
+
+A template names a git repository, branch, bootstrap commands and environment.
+'forge ws create --template NAME' clones the repository when --path does not
+exist yet, then runs the bootstrap commands in a 'bootstrap' window of the
+workspace's tmux session and records their output ('forge ws bootstrap').`,
+	Example: `  forge ws template add api-service \
+    --git-url git@github.com:acme/api.git --branch main \
+    --bootstrap "make deps" --bootstrap "make db-migrate" \
+    --env GOFLAGS=-mod=mod
+
+  forge ws create --template api-service --path ~/src/api`,
+}
+
+var wsTemplateAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a workspace template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		env, err := parseTemplateEnv(wsTemplateEnv)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		repo := db.NewWorkspaceTemplateRepository(database)
+		tmpl := &models.WorkspaceTemplate{
+			Name:        args[0],
+			Description: wsTemplateDescription,
+			GitURL:      wsTemplateGitURL,
+			Branch:      wsTemplateBranch,
+			Bootstrap:   wsTemplateBootstrap,
+			Env:         env,
+		}
+
+		err = repo.Create(ctx, tmpl)
+		if errors.Is(err, db.ErrWorkspaceTemplateAlreadyExists) && wsTemplateReplace {
+			existing, getErr := repo.GetByName(ctx, tmpl.Name)
+			if getErr != nil {
+				return getErr
+			}
+			tmpl.ID = existing.ID
+			tmpl.CreatedAt = existing.CreatedAt
+			err = repo.Update(ctx, tmpl)
+		}
+		if err != nil {
+			if errors.Is(err, db.ErrWorkspaceTemplateAlreadyExists) {
+				return fmt.Errorf("workspace template %q already exists (use --replace to overwrite)", tmpl.Name)
+			}
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, tmpl)
+		}
+		fmt.Printf("Workspace template '%s' saved (%d bootstrap command(s))\n", tmpl.Name, len(tmpl.Bootstrap))
+		return nil
+	},
+}
+
+var wsTemplateListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List workspace templates",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		templates, err := db.NewWorkspaceTemplateRepository(database).List(ctx)
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, templates)
+		}
+		if len(templates) == 0 {
+			fmt.Fprintln(os.Stdout, "No workspace templates found")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(templates))
+		for _, tmpl := range templates {
+			rows = append(rows, []string{
+				tmpl.Name,
+				templateField(tmpl.GitURL),
+				templateField(tmpl.Branch),
+				strconv.Itoa(len(tmpl.Bootstrap)),
+				templateField(tmpl.Description),
+			})
+		}
+		return writeTable(os.Stdout, []string{"NAME", "GIT URL", "BRANCH", "BOOTSTRAP", "DESCRIPTION"}, rows)
+	},
+}
+
+var wsTemplateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a workspace template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		tmpl, err := findWorkspaceTemplate(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, tmpl)
+		}
+
+		fmt.Printf("Template:    %s\n", tmpl.Name)
+		if tmpl.Description != "" {
+			fmt.Printf("Description: %s\n", tmpl.Description)
+		}
+		fmt.Printf("Git URL:     %s\n", templateField(tmpl.GitURL))
+		fmt.Printf("Branch:      %s\n", templateField(tmpl.Branch))
+		if len(tmpl.Env) > 0 {
+			fmt.Println("Env:")
+			keys := make([]string, 0, len(tmpl.Env))
+			for key := range tmpl.Env {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("  %s=%s\n", key, tmpl.Env[key])
+			}
+		}
+		if len(tmpl.Bootstrap) > 0 {
+			fmt.Println("Bootstrap:")
+			for i, command := range tmpl.Bootstrap {
+				fmt.Printf("  %d. %s\n", i+1, command)
+			}
+		}
+		return nil
+	},
+}
+
+var wsTemplateRemoveCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Delete a workspace template",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		name := args[0]
+		if !wsTemplateRmForce && !ConfirmDestructiveAction("workspace template", name, "Workspaces created from it are kept.") {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+		if err := db.NewWorkspaceTemplateRepository(database).DeleteByName(ctx, name); err != nil {
+			if errors.Is(err, db.ErrWorkspaceTemplateNotFound) {
+				return fmt.Errorf("workspace template not found: %s", name)
+			}
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, map[string]any{"deleted": true, "name": name})
+		}
+		fmt.Printf("Workspace template '%s' deleted\n", name)
+		return nil
+	},
+}
+
+var wsBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap <workspace>",
+	Short: "Show the last bootstrap run of a workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		database, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		ws, err := findWorkspace(ctx, db.NewWorkspaceRepository(database), args[0])
+		if err != nil {
+			return err
+		}
+		run, err := db.NewWorkspaceTemplateRepository(database).GetBootstrap(ctx, ws.ID)
+		if err != nil {
+			if errors.Is(err, db.ErrWorkspaceBootstrapNotFound) {
+				return fmt.Errorf("workspace '%s' has no bootstrap run", ws.Name)
+			}
+			return err
+		}
+
+		if IsJSONOutput() || IsJSONLOutput() {
+			return WriteOutput(os.Stdout, run)
+		}
+		printBootstrapRun(run)
+		if run.Output != "" {
+			fmt.Printf("\n%s\n", run.Output)
+		}
+		return nil
+	},
+}
+
+func findWorkspaceTemplate(ctx context.Context, database *db.DB, name string) (*models.WorkspaceTemplate, error) {
+	tmpl, err := db.NewWorkspaceTemplateRepository(database).GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, db.ErrWorkspaceTemplateNotFound) {
+			return nil, fmt.Errorf("workspace template not found: %s", name)
+		}
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// parseTemplateEnv parses KEY=VALUE pairs, rejecting malformed entries
+// rather than dropping them.
+func parseTemplateEnv(pairs []string) (map[string]string, error) {
+	for _, pair := range pairs {
+		if key, _, ok := strings.Cut(pair, "="); !ok || key == "" {
+			return nil, fmt.Errorf("invalid --env %q (expected KEY=VALUE)", pair)
+		}
+	}
+	return parseEnvPairs(pairs), nil
+}
+
+func printBootstrapRun(run *models.WorkspaceBootstrap) {
+	fmt.Printf("Bootstrap: %s", run.Status)
+	if run.ExitCode != nil {
+		fmt.Printf(" (exit %d)", *run.ExitCode)
+	}
+	fmt.Println()
+	fmt.Printf("  Started:  %s\n", formatRelativeTime(run.StartedAt))
+	if run.FinishedAt != nil {
+		fmt.Printf("  Duration: %s\n", run.FinishedAt.Sub(run.StartedAt).Round(time.Second))
+	}
+}
+
+func templateField(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
+
+// bootstrapOutputTail returns the last n lines of bootstrap output.
+func bootstrapOutputTail(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
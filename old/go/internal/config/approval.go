@@ -48,7 +48,8 @@ func (c *Config) ApprovalPolicyForWorkspace(ws *models.Workspace) ResolvedApprov
 			mode = ApprovalPolicyCustom
 		}
 		if mode == "" {
-			return base
+			// Quota-only overrides leave the approval policy alone.
+			continue
 		}
 		return ResolvedApprovalPolicy{
 			Mode:  mode,
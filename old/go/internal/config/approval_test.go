@@ -132,3 +132,36 @@ func TestApprovalPolicyRulesWithoutMode(t *testing.T) {
 		t.Fatalf("unexpected validation error: %v", err)
 	}
 }
+
+func TestQuotaForWorkspaceOverride(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WorkspaceDefaults.Quota = WorkspaceQuotaConfig{MaxAgents: 4, MaxLoops: 8}
+	ws := &models.Workspace{ID: "ws-1", Name: "alpha", RepoPath: "/tmp/alpha"}
+
+	cfg.WorkspaceOverrides = []WorkspaceOverrideConfig{
+		{
+			Name:  "alpha",
+			Quota: &WorkspaceQuotaConfig{MaxAgents: 1, MaxDiskMB: 512},
+		},
+		{
+			RepoPath:       "/tmp/*",
+			ApprovalPolicy: ApprovalPolicyPermissive,
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected quota-only override to validate, got %v", err)
+	}
+
+	quota := cfg.QuotaForWorkspace(ws)
+	if quota.MaxAgents != 1 || quota.MaxLoops != 8 || quota.MaxDiskMB != 512 {
+		t.Fatalf("unexpected merged quota: %+v", quota)
+	}
+	if policy := cfg.ApprovalPolicyForWorkspace(ws); policy.Mode != ApprovalPolicyPermissive {
+		t.Fatalf("expected quota-only override to be skipped for approvals, got %q", policy.Mode)
+	}
+
+	other := &models.Workspace{ID: "ws-2", Name: "beta", RepoPath: "/srv/beta"}
+	if quota := cfg.QuotaForWorkspace(other); quota.MaxAgents != 4 || quota.MaxDiskMB != 0 {
+		t.Fatalf("expected defaults for unmatched workspace, got %+v", quota)
+	}
+}
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StrictEnvVar turns on strict mode when the loader was not told otherwise.
+const StrictEnvVar = "FORGE_STRICT_CONFIG"
+
+// Problem is one finding from Loader.Check. Key is empty for problems that
+// are not tied to a single key, such as a file that does not parse.
+type Problem struct {
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Key == "" {
+		return p.Message
+	}
+	return p.Key + ": " + p.Message
+}
+
+// SetStrict makes Load fail when a config file sets keys Forge does not
+// know, instead of silently ignoring them.
+func (l *Loader) SetStrict(strict bool) {
+	l.strict = &strict
+}
+
+// Strict reports whether unknown keys are fatal: SetStrict when called,
+// otherwise FORGE_STRICT_CONFIG.
+func (l *Loader) Strict() bool {
+	if l.strict != nil {
+		return *l.strict
+	}
+	strict, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv(StrictEnvVar)))
+	return strict
+}
+
+// UnknownKeys returns the keys set by the loaded config files that match no
+// config field, with a suggestion when a known key is a likely typo.
+func (l *Loader) UnknownKeys() []Problem {
+	return append([]Problem(nil), l.unknown...)
+}
+
+// Check loads the config the way Load does but collects every problem
+// instead of stopping at the first: files that do not parse or decode,
+// unknown keys, failed validation, and data directories that are missing
+// write access or are writable by other users.
+func (l *Loader) Check() []Problem {
+	cfg, err := l.load()
+	if err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+	problems := l.UnknownKeys()
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	return append(problems, CheckDirectories(cfg)...)
+}
+
+func strictError(unknown []Problem) error {
+	parts := make([]string, len(unknown))
+	for i, problem := range unknown {
+		parts[i] = problem.String()
+	}
+	return fmt.Errorf("strict mode: %s", strings.Join(parts, "; "))
+}
+
+// collectUnknownKeys walks a parsed config file against the Config struct.
+func (l *Loader) collectUnknownKeys(settings map[string]any, path string) {
+	var found []Problem
+	walkUnknownKeys(reflect.TypeOf(Config{}), settings, "", &found)
+	for _, problem := range found {
+		problem.Message += " (in " + path + ")"
+		l.unknown = append(l.unknown, problem)
+	}
+}
+
+func walkUnknownKeys(t reflect.Type, value any, prefix string, out *[]Problem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := configFields(t)
+		entries, ok := asStringMap(value)
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				*out = append(*out, Problem{Key: joinKey(prefix, key), Message: unknownKeyMessage(key, fields)})
+				continue
+			}
+			walkUnknownKeys(field, entries[key], joinKey(prefix, key), out)
+		}
+	case reflect.Map:
+		entries, ok := asStringMap(value)
+		if !ok {
+			return
+		}
+		for key, item := range entries {
+			walkUnknownKeys(t.Elem(), item, joinKey(prefix, key), out)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			walkUnknownKeys(t.Elem(), item, fmt.Sprintf("%s[%d]", prefix, i), out)
+		}
+	}
+}
+
+// configFields maps a struct's mapstructure keys to field types.
+func configFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+func asStringMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = item
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func unknownKeyMessage(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := editDistance(strings.ToLower(key), name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown key (did you mean %q?)", best)
+	}
+	return "unknown key"
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// CheckDirectories reports configured directories Forge could not use: a
+// path that exists but is not a directory, a directory (or, when it does not
+// exist yet, the parent it would be created in) without write access, and a
+// data directory other users can write to.
+func CheckDirectories(cfg *Config) []Problem {
+	type dirCheck struct {
+		key     string
+		path    string
+		private bool
+	}
+	checks := []dirCheck{
+		{key: "global.data_dir", path: cfg.Global.DataDir, private: true},
+		{key: "global.config_dir", path: cfg.Global.ConfigDir},
+		{key: "database.path", path: filepath.Dir(cfg.DatabasePath()), private: true},
+		{key: "event_retention.archive_dir", path: cfg.EventRetention.ArchiveDir},
+		{key: "workspace_defaults.snapshot_dir", path: cfg.WorkspaceDefaults.SnapshotDir},
+		{key: "agent_defaults.recording.dir", path: cfg.AgentDefaults.Recording.Dir},
+		{key: "agent_defaults.heartbeat.dir", path: cfg.AgentDefaults.Heartbeat.Dir},
+	}
+	if cfg.Logging.File != "" {
+		checks = append(checks, dirCheck{key: "logging.file", path: filepath.Dir(cfg.Logging.File)})
+	}
+	if strings.HasPrefix(cfg.Database.Path, ":") || strings.HasPrefix(cfg.Database.Path, "file:") {
+		checks = append(checks[:2], checks[3:]...)
+	}
+
+	var problems []Problem
+	for _, check := range checks {
+		if strings.TrimSpace(check.path) == "" {
+			continue
+		}
+		if msg := checkDirectory(check.path, check.private); msg != "" {
+			problems = append(problems, Problem{Key: check.key, Message: msg})
+		}
+	}
+	return problems
+}
+
+func checkDirectory(path string, private bool) string {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		if !info.IsDir() {
+			return fmt.Sprintf("%s is not a directory", path)
+		}
+		if private && info.Mode().Perm()&0o002 != 0 {
+			return fmt.Sprintf("%s is writable by every user (mode %04o); run chmod o-w on it", path, info.Mode().Perm())
+		}
+		if err := probeWritable(path); err != nil {
+			return fmt.Sprintf("%s is not writable: %v", path, err)
+		}
+		return ""
+	case errors.Is(err, os.ErrNotExist):
+		parent := filepath.Dir(path)
+		for parent != filepath.Dir(parent) {
+			if _, err := os.Stat(parent); err == nil {
+				break
+			}
+			parent = filepath.Dir(parent)
+		}
+		if err := probeWritable(parent); err != nil {
+			return fmt.Sprintf("%s does not exist and cannot be created under %s: %v", path, parent, err)
+		}
+		return ""
+	default:
+		return err.Error()
+	}
+}
+
+// probeWritable creates and removes a temporary file in dir.
+func probeWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".forge-write-check-*")
+	if err != nil {
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			return pathErr.Err
+		}
+		return err
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
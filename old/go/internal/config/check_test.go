@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCheckConfig(t *testing.T, content string) string {
+	t.Helper()
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpHome, ".config"))
+	t.Setenv(StrictEnvVar, "")
+	path := filepath.Join(tmpHome, "config.yaml")
+	content = strings.ReplaceAll(content, "$DATA", filepath.Join(tmpHome, "data"))
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestUnknownKeys(t *testing.T) {
+	path := writeCheckConfig(t, `
+global:
+  data_dir: $DATA
+loging:
+  level: debug
+database:
+  max_conections: 5
+profiles:
+  - name: one
+    harness: claude
+    command_template: "claude"
+    comand_template: "x"
+scheduler:
+  retry_policies:
+    message:
+      max_retries: 2
+`)
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	if _, err := loader.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var got []string
+	for _, problem := range loader.UnknownKeys() {
+		got = append(got, problem.Key)
+	}
+	want := []string{"database.max_conections", "loging", "profiles[0].comand_template"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("UnknownKeys() = %v, want %v", got, want)
+	}
+	if msg := loader.UnknownKeys()[1].Message; !strings.Contains(msg, `did you mean "logging"`) || !strings.Contains(msg, path) {
+		t.Fatalf("unexpected message %q", msg)
+	}
+}
+
+func TestStrictModeRejectsUnknownKeys(t *testing.T) {
+	path := writeCheckConfig(t, `
+global:
+  data_dir: $DATA
+loging:
+  level: debug
+`)
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	loader.SetStrict(true)
+	_, err := loader.Load()
+	if err == nil || !strings.Contains(err.Error(), "loging: unknown key") {
+		t.Fatalf("expected strict mode error, got %v", err)
+	}
+
+	t.Setenv(StrictEnvVar, "1")
+	loader = NewLoader()
+	loader.SetConfigFile(path)
+	if _, err := loader.Load(); err == nil {
+		t.Fatal("expected FORGE_STRICT_CONFIG to enable strict mode")
+	}
+
+	loader = NewLoader()
+	loader.SetConfigFile(path)
+	loader.SetStrict(false)
+	if _, err := loader.Load(); err != nil {
+		t.Fatalf("SetStrict(false) should override the env var: %v", err)
+	}
+}
+
+func TestCheckCollectsProblems(t *testing.T) {
+	path := writeCheckConfig(t, `
+global:
+  data_dir: $DATA
+loging:
+  level: debug
+logging:
+  level: loud
+`)
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	problems := loader.Check()
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	if problems[0].Key != "loging" {
+		t.Fatalf("expected unknown key first, got %v", problems[0])
+	}
+	if !strings.Contains(problems[1].Message, "logging.level") {
+		t.Fatalf("expected validation problem, got %v", problems[1])
+	}
+}
+
+func TestCheckReportsTypeErrors(t *testing.T) {
+	path := writeCheckConfig(t, `
+database:
+  max_connections: lots
+`)
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	problems := loader.Check()
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, "max_connections") {
+		t.Fatalf("expected a decode problem, got %v", problems)
+	}
+}
+
+func TestCheckDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	notDir := filepath.Join(tmpDir, "file")
+	if err := os.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	open := filepath.Join(tmpDir, "open")
+	if err := os.Mkdir(open, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Chmod(open, 0o777); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Global.DataDir = open
+	cfg.Global.ConfigDir = filepath.Join(tmpDir, "missing", "config")
+	cfg.Database.Path = filepath.Join(tmpDir, "forge.db")
+	cfg.EventRetention.ArchiveDir = notDir
+	cfg.WorkspaceDefaults.SnapshotDir = ""
+	cfg.AgentDefaults.Recording.Dir = ""
+
+	problems := CheckDirectories(cfg)
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	if problems[0].Key != "global.data_dir" || !strings.Contains(problems[0].Message, "writable by every user") {
+		t.Fatalf("unexpected data_dir problem: %v", problems[0])
+	}
+	if problems[1].Key != "event_retention.archive_dir" || !strings.Contains(problems[1].Message, "not a directory") {
+		t.Fatalf("unexpected archive_dir problem: %v", problems[1])
+	}
+}
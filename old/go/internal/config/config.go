@@ -3,11 +3,13 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/tOgg1/forge/internal/adapters"
 	"github.com/tOgg1/forge/internal/models"
 )
 
@@ -55,11 +57,20 @@ type Config struct {
 	// TUI settings
 	TUI TUIConfig `yaml:"tui" mapstructure:"tui"`
 
+	// Keybindings remaps loop TUI actions (action name -> keys).
+	Keybindings map[string][]string `yaml:"keybindings" mapstructure:"keybindings"`
+
 	// Mail settings
 	Mail MailConfig `yaml:"mail" mapstructure:"mail"`
 
 	// EventRetention settings
 	EventRetention EventRetentionConfig `yaml:"event_retention" mapstructure:"event_retention"`
+
+	// Tracing settings
+	Tracing TracingConfig `yaml:"tracing" mapstructure:"tracing"`
+
+	// Notifications routes matching events to external sinks.
+	Notifications NotificationsConfig `yaml:"notifications" mapstructure:"notifications"`
 }
 
 // GlobalConfig contains global Forge settings.
@@ -84,6 +95,43 @@ type DatabaseConfig struct {
 
 	// BusyTimeout is how long to wait for a locked database (milliseconds).
 	BusyTimeoutMs int `yaml:"busy_timeout_ms" mapstructure:"busy_timeout_ms"`
+
+	// JournalMode is the SQLite journal mode (wal, delete, truncate, persist, memory).
+	JournalMode string `yaml:"journal_mode" mapstructure:"journal_mode"`
+
+	// Synchronous is the SQLite synchronous level (off, normal, full, extra).
+	Synchronous string `yaml:"synchronous" mapstructure:"synchronous"`
+
+	// BusyRetries is how many attempts a statement or transaction gets when
+	// it still hits "database is locked" after the busy timeout (1 = no retry).
+	BusyRetries int `yaml:"busy_retries" mapstructure:"busy_retries"`
+
+	// BusyRetryBackoff is the delay before the first busy retry; it doubles
+	// on each further attempt.
+	BusyRetryBackoff time.Duration `yaml:"busy_retry_backoff" mapstructure:"busy_retry_backoff"`
+
+	// CacheEnabled caches profile, pool, and node lookups in memory.
+	// Disable it when debugging stale reads.
+	CacheEnabled bool `yaml:"cache_enabled" mapstructure:"cache_enabled"`
+
+	// CacheTTL bounds how long a cached lookup may be served.
+	CacheTTL time.Duration `yaml:"cache_ttl" mapstructure:"cache_ttl"`
+
+	// ReadOnly opens the database read-only and skips auto-migration, for
+	// CLI and TUI processes running beside the process that owns writes.
+	ReadOnly bool `yaml:"read_only" mapstructure:"read_only"`
+
+	// Namespace is the tenant whose workspaces, agents, loops, and events
+	// commands see and create.
+	Namespace string `yaml:"namespace" mapstructure:"namespace"`
+}
+
+// EffectiveCacheTTL returns the repository cache TTL, or 0 when disabled.
+func (c DatabaseConfig) EffectiveCacheTTL() time.Duration {
+	if !c.CacheEnabled {
+		return 0
+	}
+	return c.CacheTTL
 }
 
 // LoggingConfig contains logging settings.
@@ -114,6 +162,68 @@ type AccountConfig struct {
 
 	// IsActive indicates if this account is enabled for use.
 	IsActive bool `yaml:"is_active" mapstructure:"is_active"`
+
+	// ResourceLimits overrides agent_defaults.resource_limits for agents
+	// spawned with this account.
+	ResourceLimits *ResourceLimitsConfig `yaml:"resource_limits" mapstructure:"resource_limits"`
+
+	// Quota caps this account's usage. Accounts over quota are skipped by
+	// rotation until their window rolls over.
+	Quota *AccountQuotaConfig `yaml:"quota" mapstructure:"quota"`
+}
+
+// AccountQuotaConfig defines usage quotas for an account. Zero values are
+// unlimited.
+type AccountQuotaConfig struct {
+	// MaxTokensPerDay caps tokens used since midnight UTC.
+	MaxTokensPerDay int64 `yaml:"max_tokens_per_day" mapstructure:"max_tokens_per_day"`
+
+	// MaxCostPerDayCents caps estimated cost since midnight UTC.
+	MaxCostPerDayCents int64 `yaml:"max_cost_per_day_cents" mapstructure:"max_cost_per_day_cents"`
+
+	// MaxRequestsPerHour caps requests in the last hour.
+	MaxRequestsPerHour int64 `yaml:"max_requests_per_hour" mapstructure:"max_requests_per_hour"`
+
+	// WarningThresholdPercent is when the account is reported as
+	// approaching its quota. Default: 80.
+	WarningThresholdPercent int `yaml:"warning_threshold_percent" mapstructure:"warning_threshold_percent"`
+}
+
+// Limits converts the config into model usage limits.
+func (q AccountQuotaConfig) Limits() models.UsageLimits {
+	return models.UsageLimits{
+		MaxTokensPerDay:         q.MaxTokensPerDay,
+		MaxCostPerDayCents:      q.MaxCostPerDayCents,
+		MaxRequestsPerHour:      q.MaxRequestsPerHour,
+		WarningThresholdPercent: q.WarningThresholdPercent,
+	}
+}
+
+func (q AccountQuotaConfig) validate(field string) error {
+	if q.MaxTokensPerDay < 0 {
+		return fmt.Errorf("%s.max_tokens_per_day must be >= 0", field)
+	}
+	if q.MaxCostPerDayCents < 0 {
+		return fmt.Errorf("%s.max_cost_per_day_cents must be >= 0", field)
+	}
+	if q.MaxRequestsPerHour < 0 {
+		return fmt.Errorf("%s.max_requests_per_hour must be >= 0", field)
+	}
+	if q.WarningThresholdPercent < 0 || q.WarningThresholdPercent > 100 {
+		return fmt.Errorf("%s.warning_threshold_percent must be between 0 and 100", field)
+	}
+	return nil
+}
+
+// AccountQuotas returns the configured quotas keyed by account profile name.
+func (c *Config) AccountQuotas() map[string]models.UsageLimits {
+	quotas := make(map[string]models.UsageLimits)
+	for _, account := range c.Accounts {
+		if account.Quota != nil {
+			quotas[account.ProfileName] = account.Quota.Limits()
+		}
+	}
+	return quotas
 }
 
 // ProfileConfig defines a harness+auth profile.
@@ -152,6 +262,54 @@ type NodeConfig struct {
 
 	// HealthCheckInterval is how often to check node health.
 	HealthCheckInterval time.Duration `yaml:"health_check_interval" mapstructure:"health_check_interval"`
+
+	// ControlPlane configures forged self-registration with a coordinator.
+	ControlPlane ControlPlaneConfig `yaml:"control_plane" mapstructure:"control_plane"`
+
+	// DaemonAuth configures TLS and token authentication on forged listeners.
+	DaemonAuth DaemonAuthConfig `yaml:"daemon_auth" mapstructure:"daemon_auth"`
+}
+
+// DaemonAuthConfig secures the forged HTTP and gRPC listeners. With nothing
+// set the listeners accept unauthenticated plaintext connections.
+type DaemonAuthConfig struct {
+	// TLSCertFile and TLSKeyFile enable TLS on the listeners.
+	TLSCertFile string `yaml:"tls_cert_file" mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file" mapstructure:"tls_key_file"`
+
+	// TLSClientCAFile enables mutual TLS: clients must present a certificate
+	// signed by one of these CAs.
+	TLSClientCAFile string `yaml:"tls_client_ca_file" mapstructure:"tls_client_ca_file"`
+
+	// Tokens lists accepted static bearer tokens.
+	Tokens []string `yaml:"tokens" mapstructure:"tokens"`
+
+	// TokenFile holds accepted bearer tokens, one per line. It is re-read
+	// when it changes so tokens can be rotated without a restart.
+	TokenFile string `yaml:"token_file" mapstructure:"token_file"`
+
+	// TokenReloadInterval is how often TokenFile is checked for changes.
+	TokenReloadInterval time.Duration `yaml:"token_reload_interval" mapstructure:"token_reload_interval"`
+}
+
+// ControlPlaneConfig tells forged where to register itself so swarmd or
+// another coordinator can discover the node. Registration is off when URL is
+// empty.
+type ControlPlaneConfig struct {
+	// URL is the control-plane base URL.
+	URL string `yaml:"url" mapstructure:"url"`
+
+	// Token is sent as a bearer token on registration requests.
+	Token string `yaml:"token" mapstructure:"token"`
+
+	// NodeID identifies this node to the control plane (default: hostname).
+	NodeID string `yaml:"node_id" mapstructure:"node_id"`
+
+	// Address is the host:port the control plane should use to reach forged.
+	Address string `yaml:"address" mapstructure:"address"`
+
+	// HeartbeatInterval is how often forged reports liveness.
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval" mapstructure:"heartbeat_interval"`
 }
 
 // WorkspaceConfig contains default settings for workspaces.
@@ -164,6 +322,74 @@ type WorkspaceConfig struct {
 
 	// AutoImportExisting automatically imports existing tmux sessions.
 	AutoImportExisting bool `yaml:"auto_import_existing" mapstructure:"auto_import_existing"`
+
+	// SnapshotDir is where workspace snapshots are stored (defaults to DataDir/snapshots).
+	SnapshotDir string `yaml:"snapshot_dir" mapstructure:"snapshot_dir"`
+
+	// SnapshotRetention is how many snapshots to keep per workspace (0 keeps all).
+	SnapshotRetention int `yaml:"snapshot_retention" mapstructure:"snapshot_retention"`
+
+	// GitStatusInterval is how long a collected git status (branch, dirty
+	// files, ahead/behind) is reused before the repo is inspected again.
+	GitStatusInterval time.Duration `yaml:"git_status_interval" mapstructure:"git_status_interval"`
+
+	// Quota caps agents, loops, and disk usage per workspace.
+	Quota WorkspaceQuotaConfig `yaml:"quota" mapstructure:"quota"`
+}
+
+// WorkspaceQuotaConfig defines resource quotas for a workspace. Zero values
+// are unlimited.
+type WorkspaceQuotaConfig struct {
+	// MaxAgents caps the number of agents in a workspace.
+	MaxAgents int `yaml:"max_agents" mapstructure:"max_agents"`
+
+	// MaxLoops caps the number of loops on a workspace's repo.
+	MaxLoops int `yaml:"max_loops" mapstructure:"max_loops"`
+
+	// MaxDiskMB caps the size of a workspace's repo directory in MiB.
+	MaxDiskMB int64 `yaml:"max_disk_mb" mapstructure:"max_disk_mb"`
+}
+
+// Limits converts the config into model quota limits.
+func (q WorkspaceQuotaConfig) Limits() models.WorkspaceQuota {
+	return models.WorkspaceQuota{
+		MaxAgents: q.MaxAgents,
+		MaxLoops:  q.MaxLoops,
+		MaxDiskMB: q.MaxDiskMB,
+	}
+}
+
+func (q WorkspaceQuotaConfig) validate(field string) error {
+	if q.MaxAgents < 0 || q.MaxLoops < 0 || q.MaxDiskMB < 0 {
+		return fmt.Errorf("%s: max_agents, max_loops, and max_disk_mb must be zero or greater", field)
+	}
+	return nil
+}
+
+// QuotaForWorkspace resolves the effective quota for a workspace: the
+// workspace_defaults quota, with the non-zero fields of the first matching
+// workspace override's quota applied on top.
+func (c *Config) QuotaForWorkspace(ws *models.Workspace) models.WorkspaceQuota {
+	quota := c.WorkspaceDefaults.Quota.Limits()
+	if ws == nil {
+		return quota
+	}
+	for _, override := range c.WorkspaceOverrides {
+		if override.Quota == nil || !override.matchesWorkspace(ws) {
+			continue
+		}
+		if override.Quota.MaxAgents > 0 {
+			quota.MaxAgents = override.Quota.MaxAgents
+		}
+		if override.Quota.MaxLoops > 0 {
+			quota.MaxLoops = override.Quota.MaxLoops
+		}
+		if override.Quota.MaxDiskMB > 0 {
+			quota.MaxDiskMB = override.Quota.MaxDiskMB
+		}
+		break
+	}
+	return quota
 }
 
 // WorkspaceOverrideConfig provides per-workspace configuration overrides.
@@ -182,6 +408,9 @@ type WorkspaceOverrideConfig struct {
 
 	// ApprovalRules apply when approval_policy is custom.
 	ApprovalRules []ApprovalRule `yaml:"approval_rules" mapstructure:"approval_rules"`
+
+	// Quota overrides the non-zero workspace_defaults.quota limits.
+	Quota *WorkspaceQuotaConfig `yaml:"quota" mapstructure:"quota"`
 }
 
 // ApprovalRule defines a rule for approval decisions.
@@ -212,6 +441,121 @@ type AgentConfig struct {
 
 	// ApprovalRules apply when approval_policy is custom.
 	ApprovalRules []ApprovalRule `yaml:"approval_rules" mapstructure:"approval_rules"`
+
+	// Cgroups controls placing agents in cgroup v2 groups (Linux only).
+	Cgroups CgroupConfig `yaml:"cgroups" mapstructure:"cgroups"`
+
+	// ResourceLimits are the default CPU and memory caps for agents when
+	// cgroups are enabled.
+	ResourceLimits ResourceLimitsConfig `yaml:"resource_limits" mapstructure:"resource_limits"`
+
+	// Ports configures OpenCode server port allocation.
+	Ports PortsConfig `yaml:"ports" mapstructure:"ports"`
+
+	// Recording configures asciicast recording of agent panes by forged.
+	Recording RecordingConfig `yaml:"recording" mapstructure:"recording"`
+
+	// Heartbeat configures harness heartbeats and stall detection.
+	Heartbeat HeartbeatConfig `yaml:"heartbeat" mapstructure:"heartbeat"`
+}
+
+// HeartbeatConfig controls the heartbeats harness wrappers write and when an
+// agent that stops sending them is marked stalled.
+type HeartbeatConfig struct {
+	// Enabled passes FORGE_HEARTBEAT_FILE to spawned agents and reads the
+	// files back (default: true). fmail heartbeats are read either way.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Dir is where heartbeat files are written (defaults to
+	// DataDir/heartbeats).
+	Dir string `yaml:"dir" mapstructure:"dir"`
+
+	// Timeout is how long an agent may go without a heartbeat before it is
+	// marked stalled; 0 disables stall detection (default: 5m).
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+
+	// HarnessTimeouts overrides Timeout by agent type; 0 disables stall
+	// detection for that harness.
+	HarnessTimeouts map[string]time.Duration `yaml:"harness_timeouts" mapstructure:"harness_timeouts"`
+}
+
+// RecordingConfig controls asciicast recordings of agent sessions.
+type RecordingConfig struct {
+	// Enabled turns on recording (default: false).
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Dir is where recordings are stored (defaults to DataDir/recordings).
+	Dir string `yaml:"dir" mapstructure:"dir"`
+
+	// Interval is how often the pane is captured (default: 500ms).
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// MaxSizeMB starts a new file once a recording reaches this size;
+	// 0 disables size rotation (default: 50).
+	MaxSizeMB int `yaml:"max_size_mb" mapstructure:"max_size_mb"`
+
+	// MaxDuration starts a new file once a recording spans this long;
+	// 0 disables time rotation (default: 1h).
+	MaxDuration time.Duration `yaml:"max_duration" mapstructure:"max_duration"`
+
+	// MaxFiles is how many recordings are kept per agent; 0 keeps all
+	// (default: 20).
+	MaxFiles int `yaml:"max_files" mapstructure:"max_files"`
+}
+
+// PortsConfig controls the port range and leases used for OpenCode servers.
+type PortsConfig struct {
+	// RangeStart is the first port handed out (default: 17000).
+	RangeStart int `yaml:"range_start" mapstructure:"range_start"`
+
+	// RangeEnd is the last port handed out, inclusive (default: 17999).
+	RangeEnd int `yaml:"range_end" mapstructure:"range_end"`
+
+	// LeaseTTL is how long a port reserved during spawn is held before the
+	// agent record binds it; unbound reservations are reclaimed afterwards.
+	LeaseTTL time.Duration `yaml:"lease_ttl" mapstructure:"lease_ttl"`
+
+	// CheckListening skips ports something on the local node is already
+	// listening on.
+	CheckListening bool `yaml:"check_listening" mapstructure:"check_listening"`
+}
+
+// CgroupConfig controls cgroup placement for agent processes.
+type CgroupConfig struct {
+	// Enabled turns on cgroup placement.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Root is the delegated parent cgroup (default: /sys/fs/cgroup/forge).
+	Root string `yaml:"root" mapstructure:"root"`
+
+	// RunawayThreshold is the fraction of the memory limit at which an
+	// agent is reported as runaway (default: 0.9).
+	RunawayThreshold float64 `yaml:"runaway_threshold" mapstructure:"runaway_threshold"`
+}
+
+// ResourceLimitsConfig caps CPU and memory for an agent. Zero means no limit.
+type ResourceLimitsConfig struct {
+	// CPUCores is the CPU bandwidth limit in cores (e.g. 1.5).
+	CPUCores float64 `yaml:"cpu_cores" mapstructure:"cpu_cores"`
+
+	// MemoryMB is the hard memory limit in megabytes.
+	MemoryMB int64 `yaml:"memory_mb" mapstructure:"memory_mb"`
+}
+
+// Limits converts the config into model limits.
+func (r ResourceLimitsConfig) Limits() models.ResourceLimits {
+	return models.ResourceLimits{CPUCores: r.CPUCores, MemoryBytes: r.MemoryMB << 20}
+}
+
+// AgentResourceLimits returns the limits for agents using the account
+// profile with the given name, falling back to agent_defaults.
+func (c *Config) AgentResourceLimits(profileName string) models.ResourceLimits {
+	for _, account := range c.Accounts {
+		if account.ProfileName == profileName && account.ResourceLimits != nil {
+			return account.ResourceLimits.Limits()
+		}
+	}
+	return c.AgentDefaults.ResourceLimits.Limits()
 }
 
 // LoopDefaultsConfig contains defaults for loop creation.
@@ -224,17 +568,128 @@ type LoopDefaultsConfig struct {
 
 	// PromptMsg is the default base prompt message (optional).
 	PromptMsg string `yaml:"prompt_msg" mapstructure:"prompt_msg"`
+
+	// DailySummary configures the end-of-day report each loop produces.
+	DailySummary DailySummaryConfig `yaml:"daily_summary" mapstructure:"daily_summary"`
+
+	// Hooks are the default pre/post-run hook commands for new loops.
+	Hooks LoopHooksConfig `yaml:"hooks" mapstructure:"hooks"`
+
+	// Artifacts are the default artifact globs captured after each
+	// iteration of new loops.
+	Artifacts LoopArtifactsConfig `yaml:"artifacts" mapstructure:"artifacts"`
+
+	// Log configures the per-loop log file format and rotation.
+	Log LoopLogConfig `yaml:"log" mapstructure:"log"`
+
+	// Checkpoint snapshots the repo before each iteration of new loops:
+	// "off", "commit" (a ref under refs/forge/checkpoints/), or "stash".
+	Checkpoint string `yaml:"checkpoint" mapstructure:"checkpoint"`
+}
+
+// LoopLogConfig configures loop log files.
+type LoopLogConfig struct {
+	// Format is "text" (raw harness output) or "jsonl" (one structured
+	// record per line with stream, iteration, and harness event type).
+	Format string `yaml:"format" mapstructure:"format"`
+
+	// MaxSizeMB rotates the log once it exceeds this size (0 = no rotation).
+	MaxSizeMB int `yaml:"max_size_mb" mapstructure:"max_size_mb"`
+
+	// MaxFiles is how many rotated log files to keep.
+	MaxFiles int `yaml:"max_files" mapstructure:"max_files"`
 }
 
+// LoopHooksConfig configures commands run around each loop iteration.
+type LoopHooksConfig struct {
+	// PreRun runs before each iteration (bash -lc, repo root as workdir).
+	PreRun string `yaml:"pre_run" mapstructure:"pre_run"`
+
+	// PostRun runs after each iteration completes.
+	PostRun string `yaml:"post_run" mapstructure:"post_run"`
+
+	// Timeout caps each hook's runtime (0 = no limit).
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// LoopArtifactsConfig configures files copied out of the repo after each
+// loop iteration.
+type LoopArtifactsConfig struct {
+	// Globs are repo-relative patterns; "**" matches across directories.
+	Globs []string `yaml:"globs" mapstructure:"globs"`
+}
+
+// DailySummaryConfig controls per-loop daily summaries. Loops can opt out
+// individually with `forge up --no-daily-summary`.
+type DailySummaryConfig struct {
+	// Enabled turns on daily summaries.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Time is the local time of day (HH:MM) at which the day closes.
+	Time string `yaml:"time" mapstructure:"time"`
+
+	// Timezone is an IANA zone name. Empty uses the system local zone.
+	Timezone string `yaml:"timezone" mapstructure:"timezone"`
+
+	// Topic is the fmail topic summaries are posted to. Empty disables posting.
+	Topic string `yaml:"topic" mapstructure:"topic"`
+
+	// WriteReport writes a Markdown report under {data_dir}/reports/daily.
+	WriteReport bool `yaml:"write_report" mapstructure:"write_report"`
+}
+
+// Location returns the configured timezone, falling back to time.Local.
+func (c DailySummaryConfig) Location() (*time.Location, error) {
+	if strings.TrimSpace(c.Timezone) == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(strings.TrimSpace(c.Timezone))
+}
+
+// ClockTime parses Time into hour and minute.
+func (c DailySummaryConfig) ClockTime() (int, int, error) {
+	value := strings.TrimSpace(c.Time)
+	if value == "" {
+		value = DefaultDailySummaryTime
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (expected HH:MM)", c.Time)
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// DefaultDailySummaryTime is the default close-of-day time for summaries.
+const DefaultDailySummaryTime = "23:55"
+
 // SchedulerConfig contains scheduler settings.
 type SchedulerConfig struct {
 	// DispatchInterval is how often the scheduler runs.
 	DispatchInterval time.Duration `yaml:"dispatch_interval" mapstructure:"dispatch_interval"`
 
+	// MinDispatchInterval is the shortest adaptive interval used while
+	// queues have pending work or deadlines are near. Values above
+	// DispatchInterval are clamped to it.
+	MinDispatchInterval time.Duration `yaml:"min_dispatch_interval" mapstructure:"min_dispatch_interval"`
+
+	// MaxDispatchInterval caps the exponential backoff applied while idle.
+	MaxDispatchInterval time.Duration `yaml:"max_dispatch_interval" mapstructure:"max_dispatch_interval"`
+
+	// RetryPolicies sets how failed dispatches are retried, keyed by queue
+	// item type (message, pause, conditional). Types not listed use the
+	// built-in policy.
+	RetryPolicies map[string]RetryPolicyConfig `yaml:"retry_policies" mapstructure:"retry_policies"`
+
 	// MaxRetries is the maximum dispatch retry count.
+	//
+	// Deprecated: use RetryPolicies. When set, it applies to every retrying
+	// item type whose policy does not set max_retries.
 	MaxRetries int `yaml:"max_retries" mapstructure:"max_retries"`
 
 	// RetryBackoff is the base backoff duration for retries.
+	//
+	// Deprecated: use RetryPolicies. When set, it applies to every retrying
+	// item type whose policy does not set backoff.
 	RetryBackoff time.Duration `yaml:"retry_backoff" mapstructure:"retry_backoff"`
 
 	// DefaultCooldownDuration is the default cooldown after rate limiting.
@@ -242,6 +697,80 @@ type SchedulerConfig struct {
 
 	// AutoRotateOnRateLimit automatically rotates accounts on rate limit.
 	AutoRotateOnRateLimit bool `yaml:"auto_rotate_on_rate_limit" mapstructure:"auto_rotate_on_rate_limit"`
+
+	// AutoRecoverAgents recreates the tmux pane of agents whose session or
+	// pane disappeared and replays their launch command.
+	AutoRecoverAgents bool `yaml:"auto_recover_agents" mapstructure:"auto_recover_agents"`
+
+	// MaxRecoveryAttempts caps automatic recoveries of an agent between
+	// healthy runs.
+	MaxRecoveryAttempts int `yaml:"max_recovery_attempts" mapstructure:"max_recovery_attempts"`
+
+	// DispatchPolicy orders dispatches across workspaces: "fair_share"
+	// (weighted), "round_robin", "fifo" (agent list order), "priority"
+	// (by workspace priority), or "deadline_first" (earliest queue-head deadline).
+	DispatchPolicy string `yaml:"dispatch_policy" mapstructure:"dispatch_policy"`
+
+	// WorkspaceWeights sets fair-share weights by workspace ID (default 1).
+	WorkspaceWeights map[string]float64 `yaml:"workspace_weights" mapstructure:"workspace_weights"`
+
+	// WorkspacePriorities sets priorities by workspace ID for the
+	// "priority" policy; higher goes first (default 0).
+	WorkspacePriorities map[string]int `yaml:"workspace_priorities" mapstructure:"workspace_priorities"`
+
+	// DeadlineWarning is how long before a queue item's deadline it is
+	// dispatched ahead of the policy order and a warning event is emitted.
+	// Zero disables deadline prioritization and warnings.
+	DeadlineWarning time.Duration `yaml:"deadline_warning" mapstructure:"deadline_warning"`
+}
+
+// RetryPolicyConfig is the retry policy for one queue item type.
+type RetryPolicyConfig struct {
+	// Policy is "immediate", "exponential", or "none".
+	Policy string `yaml:"policy" mapstructure:"policy"`
+
+	// MaxRetries is how many times a failed item is redispatched before it
+	// is dead-lettered.
+	MaxRetries int `yaml:"max_retries" mapstructure:"max_retries"`
+
+	// Backoff is the first exponential retry delay; it doubles on each
+	// further retry.
+	Backoff time.Duration `yaml:"backoff" mapstructure:"backoff"`
+}
+
+// QueueRetryPolicies returns the retry policy for every queue item type:
+// the configured ones, with the built-in policy for unlisted types and the
+// default backoff for exponential policies that omit one. The deprecated
+// MaxRetries and RetryBackoff, when non-zero, override the built-in policy
+// of unlisted types that retry.
+func (c SchedulerConfig) QueueRetryPolicies() map[models.QueueItemType]models.RetryPolicy {
+	defaultBackoff := models.DefaultRetryPolicies()[models.QueueItemTypeMessage].Backoff
+	if c.RetryBackoff > 0 {
+		defaultBackoff = c.RetryBackoff
+	}
+	policies := models.DefaultRetryPolicies()
+	for itemType, policy := range policies {
+		if policy.Kind == models.RetryPolicyNone {
+			continue
+		}
+		if c.MaxRetries > 0 {
+			policy.MaxRetries = c.MaxRetries
+		}
+		policy.Backoff = defaultBackoff
+		policies[itemType] = policy
+	}
+	for itemType, raw := range c.RetryPolicies {
+		kind, err := models.ParseRetryPolicyKind(raw.Policy)
+		if err != nil {
+			continue
+		}
+		policy := models.RetryPolicy{Kind: kind, MaxRetries: raw.MaxRetries, Backoff: raw.Backoff}
+		if kind == models.RetryPolicyExponential && policy.Backoff <= 0 {
+			policy.Backoff = defaultBackoff
+		}
+		policies[models.QueueItemType(itemType)] = policy
+	}
+	return policies
 }
 
 // TUIConfig contains TUI settings.
@@ -257,6 +786,15 @@ type TUIConfig struct {
 
 	// CompactMode uses a more compact layout.
 	CompactMode bool `yaml:"compact_mode" mapstructure:"compact_mode"`
+
+	// LoopColumns picks the loop list columns in display order (status, id,
+	// name, runs, queue, last_run, harness, profile, pool, tags, cpu, dir).
+	// Empty uses status, id, runs, harness, cpu, dir.
+	LoopColumns []string `yaml:"loop_columns" mapstructure:"loop_columns"`
+
+	// LoopSort is the initial loop list order (created, status, runs,
+	// last_run, queue).
+	LoopSort string `yaml:"loop_sort" mapstructure:"loop_sort"`
 }
 
 // MailConfig contains mail subsystem settings.
@@ -304,6 +842,182 @@ type EventRetentionConfig struct {
 
 	// BatchSize is the number of events to process per cleanup batch.
 	BatchSize int `yaml:"batch_size" mapstructure:"batch_size"`
+
+	// TypeMaxAge overrides MaxAge per event type. Keys are exact types
+	// ("agent.state_changed") or prefixes ending in ".*" ("message.*"); the
+	// most specific key wins. Zero keeps events of that type indefinitely.
+	TypeMaxAge map[string]time.Duration `yaml:"type_max_age" mapstructure:"type_max_age"`
+
+	// Rollup records per-day counts of deleted events so totals survive compaction.
+	Rollup bool `yaml:"rollup" mapstructure:"rollup"`
+}
+
+// MaxAgeFor returns the retention window for an event type: an exact
+// TypeMaxAge entry, else the longest matching prefix entry, else MaxAge.
+func (c EventRetentionConfig) MaxAgeFor(eventType string) time.Duration {
+	if age, ok := c.TypeMaxAge[eventType]; ok {
+		return age
+	}
+	best, bestLen := c.MaxAge, -1
+	for key, age := range c.TypeMaxAge {
+		prefix, ok := strings.CutSuffix(key, "*")
+		if ok && strings.HasPrefix(eventType, prefix) && len(prefix) > bestLen {
+			best, bestLen = age, len(prefix)
+		}
+	}
+	return best
+}
+
+// TracingConfig contains OpenTelemetry trace export settings.
+type TracingConfig struct {
+	// Enabled turns on span collection and export.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Endpoint is the OTLP/HTTP collector base URL (e.g., http://localhost:4318).
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string `yaml:"service_name" mapstructure:"service_name"`
+
+	// SampleRatio is the fraction of new traces to record (0.0-1.0).
+	SampleRatio float64 `yaml:"sample_ratio" mapstructure:"sample_ratio"`
+
+	// Headers are extra HTTP headers sent to the collector (e.g., auth tokens).
+	Headers map[string]string `yaml:"headers" mapstructure:"headers"`
+
+	// ExportTimeout bounds each export request.
+	ExportTimeout time.Duration `yaml:"export_timeout" mapstructure:"export_timeout"`
+}
+
+// Notification sink types.
+const (
+	NotificationSinkWebhook = "webhook"
+	NotificationSinkSlack   = "slack"
+	NotificationSinkEmail   = "email"
+)
+
+// NotificationsConfig contains notifier settings.
+type NotificationsConfig struct {
+	// Enabled toggles notification delivery.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// PollInterval is how often 'forge notify watch' reads new events.
+	PollInterval time.Duration `yaml:"poll_interval" mapstructure:"poll_interval"`
+
+	// Sinks are the named delivery targets.
+	Sinks []NotificationSinkConfig `yaml:"sinks" mapstructure:"sinks"`
+
+	// Rules map event triggers to sinks.
+	Rules []NotificationRuleConfig `yaml:"rules" mapstructure:"rules"`
+}
+
+// NotificationSinkConfig describes one delivery target.
+type NotificationSinkConfig struct {
+	// Name identifies the sink in rules.
+	Name string `yaml:"name" mapstructure:"name"`
+
+	// Type is webhook, slack or email.
+	Type string `yaml:"type" mapstructure:"type"`
+
+	// URL is the webhook or Slack incoming-webhook URL. Credential
+	// references (env:VAR, file:/path, vault:...) are resolved at send time.
+	URL string `yaml:"url" mapstructure:"url"`
+
+	// Headers are extra HTTP headers for webhook sinks.
+	Headers map[string]string `yaml:"headers" mapstructure:"headers"`
+
+	// Channel overrides the Slack webhook's default channel.
+	Channel string `yaml:"channel" mapstructure:"channel"`
+
+	// SMTPHost and SMTPPort address the mail server for email sinks.
+	SMTPHost string `yaml:"smtp_host" mapstructure:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port" mapstructure:"smtp_port"`
+
+	// Username and PasswordRef authenticate to the mail server (PLAIN auth).
+	Username    string `yaml:"username" mapstructure:"username"`
+	PasswordRef string `yaml:"password_ref" mapstructure:"password_ref"`
+
+	// From and To are the envelope sender and recipients.
+	From string   `yaml:"from" mapstructure:"from"`
+	To   []string `yaml:"to" mapstructure:"to"`
+
+	// Timeout bounds each delivery. Zero uses the notifier default.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// NotificationRuleConfig routes events to sinks.
+type NotificationRuleConfig struct {
+	// Name identifies the rule in logs and templates.
+	Name string `yaml:"name" mapstructure:"name"`
+
+	// On lists triggers: agent_error, disk_critical, loop_finished,
+	// loop_failed, or raw event types such as "account.rotated".
+	On []string `yaml:"on" mapstructure:"on"`
+
+	// EntityID restricts the rule to one agent, loop, node or workspace.
+	EntityID string `yaml:"entity_id" mapstructure:"entity_id"`
+
+	// Sinks names the sinks that receive matching events.
+	Sinks []string `yaml:"sinks" mapstructure:"sinks"`
+
+	// Title and Body are Go text/templates rendered per event. Empty
+	// values use a per-trigger default.
+	Title string `yaml:"title" mapstructure:"title"`
+	Body  string `yaml:"body" mapstructure:"body"`
+}
+
+func (n NotificationsConfig) validate() error {
+	sinks := make(map[string]bool, len(n.Sinks))
+	for i, sink := range n.Sinks {
+		field := fmt.Sprintf("notifications.sinks[%d]", i)
+		name := strings.TrimSpace(sink.Name)
+		if name == "" {
+			return fmt.Errorf("%s.name is required", field)
+		}
+		if sinks[name] {
+			return fmt.Errorf("%s.name %q is duplicated", field, name)
+		}
+		sinks[name] = true
+		switch sink.Type {
+		case NotificationSinkWebhook, NotificationSinkSlack:
+			if strings.TrimSpace(sink.URL) == "" {
+				return fmt.Errorf("%s.url is required for %s sinks", field, sink.Type)
+			}
+		case NotificationSinkEmail:
+			if strings.TrimSpace(sink.SMTPHost) == "" {
+				return fmt.Errorf("%s.smtp_host is required for email sinks", field)
+			}
+			if strings.TrimSpace(sink.From) == "" || len(sink.To) == 0 {
+				return fmt.Errorf("%s.from and %s.to are required for email sinks", field, field)
+			}
+			if sink.SMTPPort < 0 || sink.SMTPPort > 65535 {
+				return fmt.Errorf("%s.smtp_port must be between 0 and 65535", field)
+			}
+		default:
+			return fmt.Errorf("%s.type must be webhook, slack, or email", field)
+		}
+		if sink.Timeout < 0 {
+			return fmt.Errorf("%s.timeout must be zero or positive", field)
+		}
+	}
+	for i, rule := range n.Rules {
+		field := fmt.Sprintf("notifications.rules[%d]", i)
+		if len(rule.On) == 0 {
+			return fmt.Errorf("%s.on is required", field)
+		}
+		if len(rule.Sinks) == 0 {
+			return fmt.Errorf("%s.sinks is required", field)
+		}
+		for _, name := range rule.Sinks {
+			if !sinks[strings.TrimSpace(name)] {
+				return fmt.Errorf("%s.sinks references unknown sink %q", field, name)
+			}
+		}
+	}
+	if n.Enabled && n.PollInterval < time.Second {
+		return fmt.Errorf("notifications.poll_interval must be at least 1s")
+	}
+	return nil
 }
 
 // DefaultConfig returns the default configuration.
@@ -317,9 +1031,16 @@ func DefaultConfig() *Config {
 			AutoRegisterLocalNode: true,
 		},
 		Database: DatabaseConfig{
-			Path:           "", // Will be set to DataDir/forge.db
-			MaxConnections: 10,
-			BusyTimeoutMs:  5000,
+			Path:             "", // Will be set to DataDir/forge.db
+			MaxConnections:   10,
+			BusyTimeoutMs:    5000,
+			JournalMode:      "wal",
+			Synchronous:      "normal",
+			BusyRetries:      3,
+			BusyRetryBackoff: 50 * time.Millisecond,
+			CacheEnabled:     true,
+			CacheTTL:         5 * time.Second,
+			Namespace:        models.DefaultNamespace,
 		},
 		Logging: LoggingConfig{
 			Level:        "info",
@@ -334,11 +1055,20 @@ func DefaultConfig() *Config {
 			SSHBackend:          models.SSHBackendAuto,
 			SSHTimeout:          30 * time.Second,
 			HealthCheckInterval: 60 * time.Second,
+			ControlPlane: ControlPlaneConfig{
+				HeartbeatInterval: 30 * time.Second,
+			},
+			DaemonAuth: DaemonAuthConfig{
+				TokenReloadInterval: 10 * time.Second,
+			},
 		},
 		WorkspaceDefaults: WorkspaceConfig{
 			TmuxPrefix:         "forge",
 			DefaultAgentType:   models.AgentTypeOpenCode,
 			AutoImportExisting: false,
+			SnapshotDir:        "", // Will be set to DataDir/snapshots
+			SnapshotRetention:  10,
+			GitStatusInterval:  30 * time.Second,
 		},
 		AgentDefaults: AgentConfig{
 			DefaultType:          models.AgentTypeOpenCode,
@@ -346,22 +1076,63 @@ func DefaultConfig() *Config {
 			IdleTimeout:          10 * time.Second,
 			TranscriptBufferSize: 10000,
 			ApprovalPolicy:       "strict",
+			Cgroups: CgroupConfig{
+				RunawayThreshold: 0.9,
+			},
+			Ports: PortsConfig{
+				RangeStart:     17000,
+				RangeEnd:       17999,
+				LeaseTTL:       2 * time.Minute,
+				CheckListening: true,
+			},
+			Recording: RecordingConfig{
+				Interval:    500 * time.Millisecond,
+				MaxSizeMB:   50,
+				MaxDuration: time.Hour,
+				MaxFiles:    20,
+			},
+			Heartbeat: HeartbeatConfig{
+				Enabled: true,
+				Timeout: 5 * time.Minute,
+			},
 		},
 		Scheduler: SchedulerConfig{
-			DispatchInterval:        1 * time.Second,
+			DispatchInterval:    1 * time.Second,
+			MinDispatchInterval: 250 * time.Millisecond,
+			MaxDispatchInterval: 10 * time.Second,
+			RetryPolicies: map[string]RetryPolicyConfig{
+				string(models.QueueItemTypeMessage):     {Policy: "exponential", MaxRetries: 3, Backoff: 5 * time.Second},
+				string(models.QueueItemTypeConditional): {Policy: "exponential", MaxRetries: 3, Backoff: 5 * time.Second},
+				string(models.QueueItemTypePause):       {Policy: "none"},
+			},
 			MaxRetries:              3,
 			RetryBackoff:            5 * time.Second,
 			DefaultCooldownDuration: 5 * time.Minute,
 			AutoRotateOnRateLimit:   true,
+			AutoRecoverAgents:       true,
+			MaxRecoveryAttempts:     3,
+			DispatchPolicy:          "fair_share",
+			DeadlineWarning:         5 * time.Minute,
 		},
 		LoopDefaults: LoopDefaultsConfig{
 			Interval: 30 * time.Second,
+			DailySummary: DailySummaryConfig{
+				Enabled:     false,
+				Time:        DefaultDailySummaryTime,
+				Topic:       "loop-daily",
+				WriteReport: true,
+			},
+			Log: LoopLogConfig{
+				Format:   "text",
+				MaxFiles: 5,
+			},
 		},
 		TUI: TUIConfig{
 			RefreshInterval: 500 * time.Millisecond,
 			Theme:           "default",
 			ShowTimestamps:  true,
 			CompactMode:     false,
+			LoopSort:        "created",
 		},
 		Mail: MailConfig{
 			Relay: MailRelayConfig{
@@ -379,6 +1150,18 @@ func DefaultConfig() *Config {
 			ArchiveBeforeDelete: false,
 			ArchiveDir:          "", // Will be set to DataDir/archives
 			BatchSize:           1000,
+			Rollup:              true,
+		},
+		Tracing: TracingConfig{
+			Enabled:       false,
+			Endpoint:      "http://localhost:4318",
+			ServiceName:   "forge",
+			SampleRatio:   1.0,
+			ExportTimeout: 10 * time.Second,
+		},
+		Notifications: NotificationsConfig{
+			Enabled:      true,
+			PollInterval: 5 * time.Second,
 		},
 	}
 }
@@ -398,6 +1181,28 @@ func (c *Config) Validate() error {
 	if c.Database.BusyTimeoutMs < 0 {
 		return fmt.Errorf("database.busy_timeout_ms must be zero or greater")
 	}
+	switch strings.ToLower(strings.TrimSpace(c.Database.JournalMode)) {
+	case "", "wal", "delete", "truncate", "persist", "memory":
+	default:
+		return fmt.Errorf("database.journal_mode must be one of: wal, delete, truncate, persist, memory")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Database.Synchronous)) {
+	case "", "off", "normal", "full", "extra":
+	default:
+		return fmt.Errorf("database.synchronous must be one of: off, normal, full, extra")
+	}
+	if c.Database.BusyRetries < 0 {
+		return fmt.Errorf("database.busy_retries must be zero or greater")
+	}
+	if c.Database.BusyRetryBackoff < 0 {
+		return fmt.Errorf("database.busy_retry_backoff must be zero or greater")
+	}
+	if c.Database.CacheTTL < 0 {
+		return fmt.Errorf("database.cache_ttl must be zero or greater")
+	}
+	if _, err := models.NormalizeNamespace(c.Database.Namespace); err != nil {
+		return fmt.Errorf("database.namespace: %w", err)
+	}
 
 	switch strings.ToLower(strings.TrimSpace(c.Logging.Level)) {
 	case "debug", "info", "warn", "error":
@@ -421,6 +1226,24 @@ func (c *Config) Validate() error {
 	if c.NodeDefaults.HealthCheckInterval <= 0 {
 		return fmt.Errorf("node_defaults.health_check_interval must be greater than 0")
 	}
+	if c.NodeDefaults.ControlPlane.HeartbeatInterval < 0 {
+		return fmt.Errorf("node_defaults.control_plane.heartbeat_interval must be >= 0")
+	}
+	if raw := strings.TrimSpace(c.NodeDefaults.ControlPlane.URL); raw != "" {
+		if parsed, err := url.Parse(raw); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("node_defaults.control_plane.url must be an absolute URL")
+		}
+	}
+	auth := c.NodeDefaults.DaemonAuth
+	if (strings.TrimSpace(auth.TLSCertFile) == "") != (strings.TrimSpace(auth.TLSKeyFile) == "") {
+		return fmt.Errorf("node_defaults.daemon_auth.tls_cert_file and tls_key_file must be set together")
+	}
+	if strings.TrimSpace(auth.TLSClientCAFile) != "" && strings.TrimSpace(auth.TLSCertFile) == "" {
+		return fmt.Errorf("node_defaults.daemon_auth.tls_client_ca_file requires tls_cert_file and tls_key_file")
+	}
+	if auth.TokenReloadInterval < 0 {
+		return fmt.Errorf("node_defaults.daemon_auth.token_reload_interval must be >= 0")
+	}
 
 	if strings.TrimSpace(c.WorkspaceDefaults.TmuxPrefix) == "" {
 		return fmt.Errorf("workspace_defaults.tmux_prefix is required")
@@ -428,6 +1251,15 @@ func (c *Config) Validate() error {
 	if !isValidAgentType(c.WorkspaceDefaults.DefaultAgentType) {
 		return fmt.Errorf("workspace_defaults.default_agent_type must be one of opencode, claude-code, codex, gemini, generic")
 	}
+	if c.WorkspaceDefaults.SnapshotRetention < 0 {
+		return fmt.Errorf("workspace_defaults.snapshot_retention must be zero or greater")
+	}
+	if c.WorkspaceDefaults.GitStatusInterval < time.Second {
+		return fmt.Errorf("workspace_defaults.git_status_interval must be at least 1s")
+	}
+	if err := c.WorkspaceDefaults.Quota.validate("workspace_defaults.quota"); err != nil {
+		return err
+	}
 
 	if c.AgentDefaults.StatePollingInterval < 100*time.Millisecond {
 		return fmt.Errorf("agent_defaults.state_polling_interval must be at least 100ms")
@@ -444,6 +1276,35 @@ func (c *Config) Validate() error {
 	if err := validateApprovalPolicy("agent_defaults", c.AgentDefaults.ApprovalPolicy, c.AgentDefaults.ApprovalRules); err != nil {
 		return err
 	}
+	if threshold := c.AgentDefaults.Cgroups.RunawayThreshold; threshold < 0 || threshold > 1 {
+		return fmt.Errorf("agent_defaults.cgroups.runaway_threshold must be between 0 and 1")
+	}
+	if err := c.AgentDefaults.ResourceLimits.validate("agent_defaults.resource_limits"); err != nil {
+		return err
+	}
+	if ports := c.AgentDefaults.Ports; ports.RangeStart < 1 || ports.RangeEnd > 65535 || ports.RangeStart > ports.RangeEnd {
+		return fmt.Errorf("agent_defaults.ports: range_start and range_end must satisfy 1 <= range_start <= range_end <= 65535")
+	}
+	if c.AgentDefaults.Ports.LeaseTTL < time.Second {
+		return fmt.Errorf("agent_defaults.ports.lease_ttl must be at least 1s")
+	}
+	if rec := c.AgentDefaults.Recording; rec.Enabled && rec.Interval < 100*time.Millisecond {
+		return fmt.Errorf("agent_defaults.recording.interval must be at least 100ms")
+	}
+	if rec := c.AgentDefaults.Recording; rec.MaxSizeMB < 0 || rec.MaxDuration < 0 || rec.MaxFiles < 0 {
+		return fmt.Errorf("agent_defaults.recording: max_size_mb, max_duration, and max_files must be zero or greater")
+	}
+	if c.AgentDefaults.Heartbeat.Timeout < 0 {
+		return fmt.Errorf("agent_defaults.heartbeat.timeout must be zero or greater")
+	}
+	for harness, timeout := range c.AgentDefaults.Heartbeat.HarnessTimeouts {
+		if !isValidAgentType(models.AgentType(harness)) {
+			return fmt.Errorf("agent_defaults.heartbeat.harness_timeouts: unknown harness %q", harness)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("agent_defaults.heartbeat.harness_timeouts.%s must be zero or greater", harness)
+		}
+	}
 
 	if c.Mail.Relay.DialTimeout < 0 {
 		return fmt.Errorf("mail.relay.dial_timeout must be zero or greater")
@@ -457,26 +1318,71 @@ func (c *Config) Validate() error {
 		if strings.TrimSpace(override.WorkspaceID) == "" && strings.TrimSpace(override.Name) == "" && strings.TrimSpace(override.RepoPath) == "" {
 			return fmt.Errorf("%s must include workspace_id, name, or repo_path", path)
 		}
-		if strings.TrimSpace(override.ApprovalPolicy) == "" && len(override.ApprovalRules) == 0 {
-			return fmt.Errorf("%s must set approval_policy or approval_rules", path)
+		if strings.TrimSpace(override.ApprovalPolicy) == "" && len(override.ApprovalRules) == 0 && override.Quota == nil {
+			return fmt.Errorf("%s must set approval_policy, approval_rules, or quota", path)
 		}
 		if err := validateApprovalPolicy(path, override.ApprovalPolicy, override.ApprovalRules); err != nil {
 			return err
 		}
+		if override.Quota != nil {
+			if err := override.Quota.validate(path + ".quota"); err != nil {
+				return err
+			}
+		}
 	}
 
 	if c.Scheduler.DispatchInterval < 100*time.Millisecond {
 		return fmt.Errorf("scheduler.dispatch_interval must be at least 100ms")
 	}
+	// A minimum above dispatch_interval (e.g. the 250ms default with a faster
+	// dispatch_interval) is clamped by the scheduler, not rejected.
+	if c.Scheduler.MinDispatchInterval < 0 {
+		return fmt.Errorf("scheduler.min_dispatch_interval must be zero or greater")
+	}
+	if c.Scheduler.MaxDispatchInterval != 0 && c.Scheduler.MaxDispatchInterval < c.Scheduler.DispatchInterval {
+		return fmt.Errorf("scheduler.max_dispatch_interval must be at least scheduler.dispatch_interval")
+	}
 	if c.Scheduler.MaxRetries < 0 {
 		return fmt.Errorf("scheduler.max_retries must be zero or greater")
 	}
 	if c.Scheduler.RetryBackoff <= 0 {
 		return fmt.Errorf("scheduler.retry_backoff must be greater than 0")
 	}
+	for itemType, policy := range c.Scheduler.RetryPolicies {
+		switch models.QueueItemType(itemType) {
+		case models.QueueItemTypeMessage, models.QueueItemTypePause, models.QueueItemTypeConditional:
+		default:
+			return fmt.Errorf("scheduler.retry_policies: unknown queue item type %q (expected message, pause, or conditional)", itemType)
+		}
+		if _, err := models.ParseRetryPolicyKind(policy.Policy); err != nil {
+			return fmt.Errorf("scheduler.retry_policies.%s.policy: %w", itemType, err)
+		}
+		if policy.MaxRetries < 0 {
+			return fmt.Errorf("scheduler.retry_policies.%s.max_retries must be zero or greater", itemType)
+		}
+		if policy.Backoff < 0 {
+			return fmt.Errorf("scheduler.retry_policies.%s.backoff must be zero or greater", itemType)
+		}
+	}
 	if c.Scheduler.DefaultCooldownDuration <= 0 {
 		return fmt.Errorf("scheduler.default_cooldown_duration must be greater than 0")
 	}
+	if c.Scheduler.MaxRecoveryAttempts < 1 {
+		return fmt.Errorf("scheduler.max_recovery_attempts must be at least 1")
+	}
+	if c.Scheduler.DeadlineWarning < 0 {
+		return fmt.Errorf("scheduler.deadline_warning must be zero or greater")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Scheduler.DispatchPolicy)) {
+	case "", "fair_share", "round_robin", "fifo", "priority", "deadline_first":
+	default:
+		return fmt.Errorf("scheduler.dispatch_policy must be one of: fair_share, round_robin, fifo, priority, deadline_first")
+	}
+	for workspace, weight := range c.Scheduler.WorkspaceWeights {
+		if weight <= 0 {
+			return fmt.Errorf("scheduler.workspace_weights.%s must be greater than 0", workspace)
+		}
+	}
 
 	if c.TUI.RefreshInterval <= 0 {
 		return fmt.Errorf("tui.refresh_interval must be greater than 0")
@@ -495,8 +1401,16 @@ func (c *Config) Validate() error {
 		if c.EventRetention.MaxCount < 0 {
 			return fmt.Errorf("event_retention.max_count must be zero or positive")
 		}
-		if c.EventRetention.MaxAge == 0 && c.EventRetention.MaxCount == 0 {
-			return fmt.Errorf("event_retention: at least one of max_age or max_count must be set when enabled")
+		if c.EventRetention.MaxAge == 0 && c.EventRetention.MaxCount == 0 && len(c.EventRetention.TypeMaxAge) == 0 {
+			return fmt.Errorf("event_retention: at least one of max_age, max_count, or type_max_age must be set when enabled")
+		}
+		for key, age := range c.EventRetention.TypeMaxAge {
+			if age < 0 {
+				return fmt.Errorf("event_retention.type_max_age[%s] must be zero or positive", key)
+			}
+			if key == "" || key == "*" || (strings.Contains(key, "*") && !strings.HasSuffix(key, ".*")) || strings.Count(key, "*") > 1 {
+				return fmt.Errorf("event_retention.type_max_age: invalid key %q (use an event type or a prefix ending in .*)", key)
+			}
 		}
 		if c.EventRetention.CleanupInterval < 1*time.Minute {
 			return fmt.Errorf("event_retention.cleanup_interval must be at least 1 minute")
@@ -506,6 +1420,22 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Tracing.Enabled {
+		if strings.TrimSpace(c.Tracing.Endpoint) == "" {
+			return fmt.Errorf("tracing.endpoint is required when tracing is enabled")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+		}
+		if c.Tracing.ExportTimeout < 0 {
+			return fmt.Errorf("tracing.export_timeout must be zero or positive")
+		}
+	}
+
+	if err := c.Notifications.validate(); err != nil {
+		return err
+	}
+
 	for i, account := range c.Accounts {
 		if account.Provider == "" {
 			return fmt.Errorf("accounts[%d].provider is required", i)
@@ -516,6 +1446,16 @@ func (c *Config) Validate() error {
 		if account.CredentialRef == "" {
 			return fmt.Errorf("accounts[%d].credential_ref is required", i)
 		}
+		if account.ResourceLimits != nil {
+			if err := account.ResourceLimits.validate(fmt.Sprintf("accounts[%d].resource_limits", i)); err != nil {
+				return err
+			}
+		}
+		if account.Quota != nil {
+			if err := account.Quota.validate(fmt.Sprintf("accounts[%d].quota", i)); err != nil {
+				return err
+			}
+		}
 		switch account.Provider {
 		case models.ProviderAnthropic, models.ProviderOpenAI, models.ProviderGoogle, models.ProviderCustom:
 			// ok
@@ -577,21 +1517,36 @@ func (c *Config) Validate() error {
 	if c.LoopDefaults.Interval < 0 {
 		return fmt.Errorf("loop_defaults.interval must be zero or positive")
 	}
+	if _, _, err := c.LoopDefaults.DailySummary.ClockTime(); err != nil {
+		return fmt.Errorf("loop_defaults.daily_summary.time: %w", err)
+	}
+	if _, err := c.LoopDefaults.DailySummary.Location(); err != nil {
+		return fmt.Errorf("loop_defaults.daily_summary.timezone: %w", err)
+	}
+	if c.LoopDefaults.Hooks.Timeout < 0 {
+		return fmt.Errorf("loop_defaults.hooks.timeout must be zero or positive")
+	}
+	switch c.LoopDefaults.Log.Format {
+	case "", "text", "jsonl":
+	default:
+		return fmt.Errorf("loop_defaults.log.format must be text or jsonl")
+	}
+	if c.LoopDefaults.Log.MaxSizeMB < 0 {
+		return fmt.Errorf("loop_defaults.log.max_size_mb must be zero or positive")
+	}
+	if c.LoopDefaults.Log.MaxFiles < 0 {
+		return fmt.Errorf("loop_defaults.log.max_files must be zero or positive")
+	}
+	if _, err := models.ParseLoopCheckpointMode(c.LoopDefaults.Checkpoint); err != nil {
+		return fmt.Errorf("loop_defaults.checkpoint: %w", err)
+	}
 
 	return nil
 }
 
+// isValidAgentType accepts any agent type with a registered harness adapter.
 func isValidAgentType(agentType models.AgentType) bool {
-	switch agentType {
-	case models.AgentTypeOpenCode,
-		models.AgentTypeClaudeCode,
-		models.AgentTypeCodex,
-		models.AgentTypeGemini,
-		models.AgentTypeGeneric:
-		return true
-	default:
-		return false
-	}
+	return adapters.Has(agentType)
 }
 
 func isValidHarness(harness models.Harness) bool {
@@ -646,3 +1601,37 @@ func (c *Config) ArchivePath() string {
 	}
 	return filepath.Join(c.Global.DataDir, "archives")
 }
+
+// SnapshotPath returns the workspace snapshot directory path.
+func (c *Config) SnapshotPath() string {
+	if c.WorkspaceDefaults.SnapshotDir != "" {
+		return c.WorkspaceDefaults.SnapshotDir
+	}
+	return filepath.Join(c.Global.DataDir, "snapshots")
+}
+
+// HeartbeatPath returns the agent heartbeat directory path.
+func (c *Config) HeartbeatPath() string {
+	if c.AgentDefaults.Heartbeat.Dir != "" {
+		return c.AgentDefaults.Heartbeat.Dir
+	}
+	return filepath.Join(c.Global.DataDir, "heartbeats")
+}
+
+// RecordingPath returns the agent recording directory path.
+func (c *Config) RecordingPath() string {
+	if c.AgentDefaults.Recording.Dir != "" {
+		return c.AgentDefaults.Recording.Dir
+	}
+	return filepath.Join(c.Global.DataDir, "recordings")
+}
+
+func (r ResourceLimitsConfig) validate(field string) error {
+	if r.CPUCores < 0 {
+		return fmt.Errorf("%s.cpu_cores must be >= 0", field)
+	}
+	if r.MemoryMB < 0 {
+		return fmt.Errorf("%s.memory_mb must be >= 0", field)
+	}
+	return nil
+}
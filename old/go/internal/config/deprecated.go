@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Deprecated returns the deprecated keys set by the loaded config files or
+// environment, with what replaces them.
+func (l *Loader) Deprecated() []Problem {
+	return append([]Problem(nil), l.deprecated...)
+}
+
+// applyLegacyRetryKeys carries scheduler.max_retries and
+// scheduler.retry_backoff over to every retrying item type whose
+// retry_policies entry does not set the value itself, so configs written
+// before per-type policies keep their retry behaviour.
+func (l *Loader) applyLegacyRetryKeys(cfg *Config) {
+	maxRetriesSet := l.Source("scheduler.max_retries") != SourceDefault
+	backoffSet := l.Source("scheduler.retry_backoff") != SourceDefault
+	if maxRetriesSet {
+		l.deprecated = append(l.deprecated, Problem{Key: "scheduler.max_retries", Message: "deprecated; use scheduler.retry_policies.<type>.max_retries"})
+	}
+	if backoffSet {
+		l.deprecated = append(l.deprecated, Problem{Key: "scheduler.retry_backoff", Message: "deprecated; use scheduler.retry_policies.<type>.backoff"})
+	}
+	if !maxRetriesSet && !backoffSet {
+		return
+	}
+
+	for itemType, policy := range cfg.Scheduler.RetryPolicies {
+		kind, err := models.ParseRetryPolicyKind(policy.Policy)
+		if err != nil || kind == models.RetryPolicyNone {
+			continue
+		}
+		prefix := "scheduler.retry_policies." + itemType
+		if maxRetriesSet && l.Source(prefix+".max_retries") == SourceDefault {
+			policy.MaxRetries = cfg.Scheduler.MaxRetries
+		}
+		if backoffSet && l.Source(prefix+".backoff") == SourceDefault {
+			policy.Backoff = cfg.Scheduler.RetryBackoff
+		}
+		cfg.Scheduler.RetryPolicies[itemType] = policy
+	}
+}
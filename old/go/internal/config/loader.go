@@ -11,14 +11,21 @@ import (
 
 // Loader handles configuration loading with Viper.
 type Loader struct {
-	v          *viper.Viper
-	configFile string
+	v           *viper.Viper
+	configFile  string
+	environment string
+	files       []string
+	keySources  map[string]string
+	unknown     []Problem
+	deprecated  []Problem
+	strict      *bool
 }
 
 // NewLoader creates a new configuration loader.
 func NewLoader() *Loader {
 	return &Loader{
-		v: viper.New(),
+		v:          viper.New(),
+		keySources: make(map[string]string),
 	}
 }
 
@@ -28,8 +35,32 @@ func (l *Loader) SetConfigFile(path string) {
 }
 
 // Load loads configuration with proper precedence:
-// defaults < config file < env vars < CLI flags
+// defaults < config file < environment overlay < env vars < CLI flags
+//
+// In strict mode, keys in a config file that match no setting are an error.
 func (l *Loader) Load() (*Config, error) {
+	cfg, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Strict() && len(l.unknown) > 0 {
+		return nil, strictError(l.unknown)
+	}
+
+	// Validate
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// load reads, merges, and decodes the configuration without validating it.
+func (l *Loader) load() (*Config, error) {
+	l.unknown = nil
+	l.deprecated = nil
+
 	// Start with defaults
 	cfg := DefaultConfig()
 
@@ -39,7 +70,7 @@ func (l *Loader) Load() (*Config, error) {
 	// Load config file
 	if err := l.loadConfigFile(); err != nil {
 		// Config file is optional, only error if explicitly specified
-		if l.configFile != "" {
+		if l.configFile != "" || l.Environment() != "" {
 			return nil, fmt.Errorf("failed to load config file: %w", err)
 		}
 	}
@@ -52,14 +83,11 @@ func (l *Loader) Load() (*Config, error) {
 	// Apply env var overrides (Viper's Unmarshal doesn't properly merge env vars for nested structs)
 	l.applyEnvOverrides(cfg)
 
+	l.applyLegacyRetryKeys(cfg)
+
 	// Expand ~ in paths
 	expandPaths(cfg)
 
-	// Validate
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
-	}
-
 	return cfg, nil
 }
 
@@ -87,6 +115,9 @@ func expandPaths(cfg *Config) {
 	cfg.Logging.File = expandTilde(cfg.Logging.File)
 	cfg.NodeDefaults.SSHKeyPath = expandTilde(cfg.NodeDefaults.SSHKeyPath)
 	cfg.EventRetention.ArchiveDir = expandTilde(cfg.EventRetention.ArchiveDir)
+	cfg.WorkspaceDefaults.SnapshotDir = expandTilde(cfg.WorkspaceDefaults.SnapshotDir)
+	cfg.AgentDefaults.Recording.Dir = expandTilde(cfg.AgentDefaults.Recording.Dir)
+	cfg.AgentDefaults.Heartbeat.Dir = expandTilde(cfg.AgentDefaults.Heartbeat.Dir)
 	cfg.LoopDefaults.Prompt = expandTilde(cfg.LoopDefaults.Prompt)
 	for i := range cfg.Profiles {
 		cfg.Profiles[i].AuthHome = expandTilde(cfg.Profiles[i].AuthHome)
@@ -143,6 +174,14 @@ func (l *Loader) setDefaults(cfg *Config) {
 	v.SetDefault("database.path", cfg.Database.Path)
 	v.SetDefault("database.max_connections", cfg.Database.MaxConnections)
 	v.SetDefault("database.busy_timeout_ms", cfg.Database.BusyTimeoutMs)
+	v.SetDefault("database.journal_mode", cfg.Database.JournalMode)
+	v.SetDefault("database.synchronous", cfg.Database.Synchronous)
+	v.SetDefault("database.busy_retries", cfg.Database.BusyRetries)
+	v.SetDefault("database.busy_retry_backoff", cfg.Database.BusyRetryBackoff)
+	v.SetDefault("database.cache_enabled", cfg.Database.CacheEnabled)
+	v.SetDefault("database.cache_ttl", cfg.Database.CacheTTL)
+	v.SetDefault("database.read_only", cfg.Database.ReadOnly)
+	v.SetDefault("database.namespace", cfg.Database.Namespace)
 
 	// Logging
 	v.SetDefault("logging.level", cfg.Logging.Level)
@@ -155,11 +194,28 @@ func (l *Loader) setDefaults(cfg *Config) {
 	v.SetDefault("node_defaults.ssh_timeout", cfg.NodeDefaults.SSHTimeout)
 	v.SetDefault("node_defaults.ssh_key_path", cfg.NodeDefaults.SSHKeyPath)
 	v.SetDefault("node_defaults.health_check_interval", cfg.NodeDefaults.HealthCheckInterval)
+	v.SetDefault("node_defaults.control_plane.url", cfg.NodeDefaults.ControlPlane.URL)
+	v.SetDefault("node_defaults.control_plane.token", cfg.NodeDefaults.ControlPlane.Token)
+	v.SetDefault("node_defaults.control_plane.node_id", cfg.NodeDefaults.ControlPlane.NodeID)
+	v.SetDefault("node_defaults.control_plane.address", cfg.NodeDefaults.ControlPlane.Address)
+	v.SetDefault("node_defaults.control_plane.heartbeat_interval", cfg.NodeDefaults.ControlPlane.HeartbeatInterval)
+	v.SetDefault("node_defaults.daemon_auth.tls_cert_file", cfg.NodeDefaults.DaemonAuth.TLSCertFile)
+	v.SetDefault("node_defaults.daemon_auth.tls_key_file", cfg.NodeDefaults.DaemonAuth.TLSKeyFile)
+	v.SetDefault("node_defaults.daemon_auth.tls_client_ca_file", cfg.NodeDefaults.DaemonAuth.TLSClientCAFile)
+	v.SetDefault("node_defaults.daemon_auth.tokens", cfg.NodeDefaults.DaemonAuth.Tokens)
+	v.SetDefault("node_defaults.daemon_auth.token_file", cfg.NodeDefaults.DaemonAuth.TokenFile)
+	v.SetDefault("node_defaults.daemon_auth.token_reload_interval", cfg.NodeDefaults.DaemonAuth.TokenReloadInterval)
 
 	// Workspace defaults
 	v.SetDefault("workspace_defaults.tmux_prefix", cfg.WorkspaceDefaults.TmuxPrefix)
 	v.SetDefault("workspace_defaults.default_agent_type", string(cfg.WorkspaceDefaults.DefaultAgentType))
 	v.SetDefault("workspace_defaults.auto_import_existing", cfg.WorkspaceDefaults.AutoImportExisting)
+	v.SetDefault("workspace_defaults.snapshot_dir", cfg.WorkspaceDefaults.SnapshotDir)
+	v.SetDefault("workspace_defaults.snapshot_retention", cfg.WorkspaceDefaults.SnapshotRetention)
+	v.SetDefault("workspace_defaults.git_status_interval", cfg.WorkspaceDefaults.GitStatusInterval)
+	v.SetDefault("workspace_defaults.quota.max_agents", cfg.WorkspaceDefaults.Quota.MaxAgents)
+	v.SetDefault("workspace_defaults.quota.max_loops", cfg.WorkspaceDefaults.Quota.MaxLoops)
+	v.SetDefault("workspace_defaults.quota.max_disk_mb", cfg.WorkspaceDefaults.Quota.MaxDiskMB)
 
 	// Agent defaults
 	v.SetDefault("agent_defaults.default_type", string(cfg.AgentDefaults.DefaultType))
@@ -167,18 +223,57 @@ func (l *Loader) setDefaults(cfg *Config) {
 	v.SetDefault("agent_defaults.idle_timeout", cfg.AgentDefaults.IdleTimeout)
 	v.SetDefault("agent_defaults.transcript_buffer_size", cfg.AgentDefaults.TranscriptBufferSize)
 	v.SetDefault("agent_defaults.approval_policy", cfg.AgentDefaults.ApprovalPolicy)
+	v.SetDefault("agent_defaults.cgroups.runaway_threshold", cfg.AgentDefaults.Cgroups.RunawayThreshold)
+	v.SetDefault("agent_defaults.ports.range_start", cfg.AgentDefaults.Ports.RangeStart)
+	v.SetDefault("agent_defaults.ports.range_end", cfg.AgentDefaults.Ports.RangeEnd)
+	v.SetDefault("agent_defaults.ports.lease_ttl", cfg.AgentDefaults.Ports.LeaseTTL)
+	v.SetDefault("agent_defaults.ports.check_listening", cfg.AgentDefaults.Ports.CheckListening)
+	v.SetDefault("agent_defaults.recording.enabled", cfg.AgentDefaults.Recording.Enabled)
+	v.SetDefault("agent_defaults.recording.dir", cfg.AgentDefaults.Recording.Dir)
+	v.SetDefault("agent_defaults.recording.interval", cfg.AgentDefaults.Recording.Interval)
+	v.SetDefault("agent_defaults.recording.max_size_mb", cfg.AgentDefaults.Recording.MaxSizeMB)
+	v.SetDefault("agent_defaults.recording.max_duration", cfg.AgentDefaults.Recording.MaxDuration)
+	v.SetDefault("agent_defaults.recording.max_files", cfg.AgentDefaults.Recording.MaxFiles)
+	v.SetDefault("agent_defaults.heartbeat.enabled", cfg.AgentDefaults.Heartbeat.Enabled)
+	v.SetDefault("agent_defaults.heartbeat.dir", cfg.AgentDefaults.Heartbeat.Dir)
+	v.SetDefault("agent_defaults.heartbeat.timeout", cfg.AgentDefaults.Heartbeat.Timeout)
 
 	// Scheduler
 	v.SetDefault("scheduler.dispatch_interval", cfg.Scheduler.DispatchInterval)
+	v.SetDefault("scheduler.min_dispatch_interval", cfg.Scheduler.MinDispatchInterval)
+	v.SetDefault("scheduler.max_dispatch_interval", cfg.Scheduler.MaxDispatchInterval)
 	v.SetDefault("scheduler.max_retries", cfg.Scheduler.MaxRetries)
 	v.SetDefault("scheduler.retry_backoff", cfg.Scheduler.RetryBackoff)
+	for itemType, policy := range cfg.Scheduler.RetryPolicies {
+		prefix := "scheduler.retry_policies." + itemType
+		v.SetDefault(prefix+".policy", policy.Policy)
+		v.SetDefault(prefix+".max_retries", policy.MaxRetries)
+		v.SetDefault(prefix+".backoff", policy.Backoff)
+	}
 	v.SetDefault("scheduler.default_cooldown_duration", cfg.Scheduler.DefaultCooldownDuration)
 	v.SetDefault("scheduler.auto_rotate_on_rate_limit", cfg.Scheduler.AutoRotateOnRateLimit)
+	v.SetDefault("scheduler.auto_recover_agents", cfg.Scheduler.AutoRecoverAgents)
+	v.SetDefault("scheduler.max_recovery_attempts", cfg.Scheduler.MaxRecoveryAttempts)
+	v.SetDefault("scheduler.dispatch_policy", cfg.Scheduler.DispatchPolicy)
+	v.SetDefault("scheduler.deadline_warning", cfg.Scheduler.DeadlineWarning)
 
 	// Loop defaults
 	v.SetDefault("loop_defaults.interval", cfg.LoopDefaults.Interval)
 	v.SetDefault("loop_defaults.prompt", cfg.LoopDefaults.Prompt)
 	v.SetDefault("loop_defaults.prompt_msg", cfg.LoopDefaults.PromptMsg)
+	v.SetDefault("loop_defaults.daily_summary.enabled", cfg.LoopDefaults.DailySummary.Enabled)
+	v.SetDefault("loop_defaults.daily_summary.time", cfg.LoopDefaults.DailySummary.Time)
+	v.SetDefault("loop_defaults.daily_summary.timezone", cfg.LoopDefaults.DailySummary.Timezone)
+	v.SetDefault("loop_defaults.daily_summary.topic", cfg.LoopDefaults.DailySummary.Topic)
+	v.SetDefault("loop_defaults.daily_summary.write_report", cfg.LoopDefaults.DailySummary.WriteReport)
+	v.SetDefault("loop_defaults.hooks.pre_run", cfg.LoopDefaults.Hooks.PreRun)
+	v.SetDefault("loop_defaults.hooks.post_run", cfg.LoopDefaults.Hooks.PostRun)
+	v.SetDefault("loop_defaults.hooks.timeout", cfg.LoopDefaults.Hooks.Timeout)
+	v.SetDefault("loop_defaults.artifacts.globs", cfg.LoopDefaults.Artifacts.Globs)
+	v.SetDefault("loop_defaults.log.format", cfg.LoopDefaults.Log.Format)
+	v.SetDefault("loop_defaults.log.max_size_mb", cfg.LoopDefaults.Log.MaxSizeMB)
+	v.SetDefault("loop_defaults.log.max_files", cfg.LoopDefaults.Log.MaxFiles)
+	v.SetDefault("loop_defaults.checkpoint", cfg.LoopDefaults.Checkpoint)
 
 	// Pools/default pool
 	v.SetDefault("default_pool", cfg.DefaultPool)
@@ -188,12 +283,25 @@ func (l *Loader) setDefaults(cfg *Config) {
 	v.SetDefault("tui.theme", cfg.TUI.Theme)
 	v.SetDefault("tui.show_timestamps", cfg.TUI.ShowTimestamps)
 	v.SetDefault("tui.compact_mode", cfg.TUI.CompactMode)
+	v.SetDefault("tui.loop_columns", cfg.TUI.LoopColumns)
+	v.SetDefault("tui.loop_sort", cfg.TUI.LoopSort)
 
 	// Mail relay
 	v.SetDefault("mail.relay.enabled", cfg.Mail.Relay.Enabled)
 	v.SetDefault("mail.relay.peers", cfg.Mail.Relay.Peers)
 	v.SetDefault("mail.relay.dial_timeout", cfg.Mail.Relay.DialTimeout)
 	v.SetDefault("mail.relay.reconnect_interval", cfg.Mail.Relay.ReconnectInterval)
+
+	// Tracing
+	v.SetDefault("tracing.enabled", cfg.Tracing.Enabled)
+	v.SetDefault("tracing.endpoint", cfg.Tracing.Endpoint)
+	v.SetDefault("tracing.service_name", cfg.Tracing.ServiceName)
+	v.SetDefault("tracing.sample_ratio", cfg.Tracing.SampleRatio)
+	v.SetDefault("tracing.export_timeout", cfg.Tracing.ExportTimeout)
+
+	// Notifications
+	v.SetDefault("notifications.enabled", cfg.Notifications.Enabled)
+	v.SetDefault("notifications.poll_interval", cfg.Notifications.PollInterval)
 }
 
 // loadConfigFile attempts to load the configuration file.
@@ -204,13 +312,17 @@ func (l *Loader) loadConfigFile() error {
 
 	if err := l.v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			if environment := l.Environment(); environment != "" {
+				return fmt.Errorf("environment %q overlay needs a base config file", environment)
+			}
 			// Config file not found, use defaults
 			return nil
 		}
 		return err
 	}
 
-	return nil
+	// Re-merge the file with interpolated values and the overlay on top.
+	return l.mergeFiles(l.v.ConfigFileUsed())
 }
 
 // ConfigFileUsed returns the config file that was loaded.
@@ -274,6 +386,14 @@ func bindEnvVars(v *viper.Viper) {
 		"database.path",
 		"database.max_connections",
 		"database.busy_timeout_ms",
+		"database.journal_mode",
+		"database.synchronous",
+		"database.busy_retries",
+		"database.busy_retry_backoff",
+		"database.cache_enabled",
+		"database.cache_ttl",
+		"database.read_only",
+		"database.namespace",
 		// Logging
 		"logging.level",
 		"logging.format",
@@ -284,26 +404,66 @@ func bindEnvVars(v *viper.Viper) {
 		"node_defaults.ssh_timeout",
 		"node_defaults.ssh_key_path",
 		"node_defaults.health_check_interval",
+		"node_defaults.control_plane.url",
+		"node_defaults.control_plane.token",
+		"node_defaults.control_plane.node_id",
+		"node_defaults.control_plane.address",
+		"node_defaults.control_plane.heartbeat_interval",
+		"node_defaults.daemon_auth.tls_cert_file",
+		"node_defaults.daemon_auth.tls_key_file",
+		"node_defaults.daemon_auth.tls_client_ca_file",
+		"node_defaults.daemon_auth.tokens",
+		"node_defaults.daemon_auth.token_file",
+		"node_defaults.daemon_auth.token_reload_interval",
 		// Workspace defaults
 		"workspace_defaults.tmux_prefix",
 		"workspace_defaults.default_agent_type",
 		"workspace_defaults.auto_import_existing",
+		"workspace_defaults.snapshot_dir",
+		"workspace_defaults.snapshot_retention",
+		"workspace_defaults.git_status_interval",
+		"workspace_defaults.quota.max_agents",
+		"workspace_defaults.quota.max_loops",
+		"workspace_defaults.quota.max_disk_mb",
 		// Agent defaults
 		"agent_defaults.default_type",
 		"agent_defaults.state_polling_interval",
 		"agent_defaults.idle_timeout",
 		"agent_defaults.transcript_buffer_size",
 		"agent_defaults.approval_policy",
+		"agent_defaults.ports.range_start",
+		"agent_defaults.ports.range_end",
+		"agent_defaults.heartbeat.enabled",
+		"agent_defaults.heartbeat.timeout",
 		// Scheduler
 		"scheduler.dispatch_interval",
+		"scheduler.min_dispatch_interval",
+		"scheduler.max_dispatch_interval",
 		"scheduler.max_retries",
 		"scheduler.retry_backoff",
 		"scheduler.default_cooldown_duration",
 		"scheduler.auto_rotate_on_rate_limit",
+		"scheduler.auto_recover_agents",
+		"scheduler.max_recovery_attempts",
+		"scheduler.dispatch_policy",
+		"scheduler.deadline_warning",
 		// Loop defaults
 		"loop_defaults.interval",
 		"loop_defaults.prompt",
 		"loop_defaults.prompt_msg",
+		"loop_defaults.daily_summary.enabled",
+		"loop_defaults.daily_summary.time",
+		"loop_defaults.daily_summary.timezone",
+		"loop_defaults.daily_summary.topic",
+		"loop_defaults.daily_summary.write_report",
+		"loop_defaults.hooks.pre_run",
+		"loop_defaults.hooks.post_run",
+		"loop_defaults.hooks.timeout",
+		"loop_defaults.artifacts.globs",
+		"loop_defaults.log.format",
+		"loop_defaults.log.max_size_mb",
+		"loop_defaults.log.max_files",
+		"loop_defaults.checkpoint",
 		// Pools
 		"default_pool",
 		// TUI
@@ -311,6 +471,8 @@ func bindEnvVars(v *viper.Viper) {
 		"tui.theme",
 		"tui.show_timestamps",
 		"tui.compact_mode",
+		"tui.loop_columns",
+		"tui.loop_sort",
 		// Event retention
 		"event_retention.enabled",
 		"event_retention.max_age",
@@ -319,6 +481,16 @@ func bindEnvVars(v *viper.Viper) {
 		"event_retention.archive_before_delete",
 		"event_retention.archive_dir",
 		"event_retention.batch_size",
+		"event_retention.rollup",
+		// Tracing
+		"tracing.enabled",
+		"tracing.endpoint",
+		"tracing.service_name",
+		"tracing.sample_ratio",
+		"tracing.export_timeout",
+		// Notifications
+		"notifications.enabled",
+		"notifications.poll_interval",
 	}
 
 	// Keys that support SWARM_* legacy fallback for migration
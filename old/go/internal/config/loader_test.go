@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
 )
 
 func TestLoadDefault(t *testing.T) {
@@ -134,6 +137,166 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestFastDispatchIntervalKeepsDefaultMinimum(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("scheduler:\n  dispatch_interval: 200ms\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	// The 250ms default min_dispatch_interval exceeds dispatch_interval; it
+	// is clamped by the scheduler rather than rejected.
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Scheduler.DispatchInterval != 200*time.Millisecond {
+		t.Errorf("Expected scheduler.dispatch_interval = 200ms, got %v", cfg.Scheduler.DispatchInterval)
+	}
+
+	cfg.Scheduler.MinDispatchInterval = -time.Millisecond
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative min_dispatch_interval")
+	}
+}
+
+func TestEventRetentionTypeMaxAge(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EventRetention.TypeMaxAge = map[string]time.Duration{
+		"message.*":         time.Hour,
+		"message.queue.*":   2 * time.Hour,
+		"message.completed": 0,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("valid type_max_age failed validation: %v", err)
+	}
+
+	retention := cfg.EventRetention
+	cases := map[string]time.Duration{
+		"message.dispatched":    time.Hour,
+		"message.queue.drained": 2 * time.Hour,
+		"message.completed":     0,
+		"agent.spawned":         retention.MaxAge,
+	}
+	for eventType, want := range cases {
+		if got := retention.MaxAgeFor(eventType); got != want {
+			t.Errorf("MaxAgeFor(%q) = %v, want %v", eventType, got, want)
+		}
+	}
+
+	for _, key := range []string{"*", "message*", "a.*.b"} {
+		cfg.EventRetention.TypeMaxAge = map[string]time.Duration{key: time.Hour}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for key %q", key)
+		}
+	}
+}
+
+func TestAgentResourceLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AgentDefaults.ResourceLimits = ResourceLimitsConfig{CPUCores: 2, MemoryMB: 1024}
+	cfg.Accounts = []AccountConfig{{
+		Provider:       models.ProviderAnthropic,
+		ProfileName:    "small",
+		CredentialRef:  "env:KEY",
+		ResourceLimits: &ResourceLimitsConfig{MemoryMB: 256},
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Valid resource limits failed validation: %v", err)
+	}
+
+	if got := cfg.AgentResourceLimits("small"); got.MemoryBytes != 256<<20 || got.CPUCores != 0 {
+		t.Fatalf("expected profile override, got %+v", got)
+	}
+	if got := cfg.AgentResourceLimits("other"); got.MemoryBytes != 1024<<20 || got.CPUCores != 2 {
+		t.Fatalf("expected defaults, got %+v", got)
+	}
+
+	cfg.Accounts[0].ResourceLimits.MemoryMB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("Expected validation error for negative memory_mb")
+	}
+}
+
+func TestAccountQuotas(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Accounts = []AccountConfig{
+		{
+			Provider:      models.ProviderAnthropic,
+			ProfileName:   "capped",
+			CredentialRef: "env:KEY",
+			Quota:         &AccountQuotaConfig{MaxTokensPerDay: 1000, MaxRequestsPerHour: 50},
+		},
+		{Provider: models.ProviderAnthropic, ProfileName: "open", CredentialRef: "env:OTHER"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Valid quota failed validation: %v", err)
+	}
+
+	quotas := cfg.AccountQuotas()
+	if len(quotas) != 1 || quotas["capped"].MaxTokensPerDay != 1000 || quotas["capped"].MaxRequestsPerHour != 50 {
+		t.Fatalf("unexpected quotas %+v", quotas)
+	}
+
+	cfg.Accounts[0].Quota.WarningThresholdPercent = 120
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("Expected validation error for warning_threshold_percent > 100")
+	}
+}
+
+func TestNotificationsValidation(t *testing.T) {
+	valid := func() *Config {
+		cfg := DefaultConfig()
+		cfg.Notifications.Sinks = []NotificationSinkConfig{
+			{Name: "slack", Type: NotificationSinkSlack, URL: "env:SLACK_WEBHOOK_URL"},
+			{Name: "mail", Type: NotificationSinkEmail, SMTPHost: "smtp.example.com", From: "forge@example.com", To: []string{"ops@example.com"}},
+		}
+		cfg.Notifications.Rules = []NotificationRuleConfig{
+			{Name: "failures", On: []string{"agent_error", "loop_failed"}, Sinks: []string{"slack", "mail"}},
+		}
+		return cfg
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Valid notifications failed validation: %v", err)
+	}
+
+	for name, mutate := range map[string]func(*Config){
+		"unknown type":       func(c *Config) { c.Notifications.Sinks[0].Type = "pager" },
+		"missing url":        func(c *Config) { c.Notifications.Sinks[0].URL = "" },
+		"duplicate name":     func(c *Config) { c.Notifications.Sinks[1].Name = "slack" },
+		"email without to":   func(c *Config) { c.Notifications.Sinks[1].To = nil },
+		"rule without on":    func(c *Config) { c.Notifications.Rules[0].On = nil },
+		"rule unknown sink":  func(c *Config) { c.Notifications.Rules[0].Sinks = []string{"teams"} },
+		"poll interval zero": func(c *Config) { c.Notifications.PollInterval = 0 },
+	} {
+		cfg := valid()
+		mutate(cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestAgentPortsValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Default ports failed validation: %v", err)
+	}
+
+	for _, ports := range []PortsConfig{
+		{RangeStart: 0, RangeEnd: 100, LeaseTTL: time.Minute},
+		{RangeStart: 18000, RangeEnd: 17000, LeaseTTL: time.Minute},
+		{RangeStart: 17000, RangeEnd: 70000, LeaseTTL: time.Minute},
+		{RangeStart: 17000, RangeEnd: 17999, LeaseTTL: 0},
+	} {
+		cfg := DefaultConfig()
+		cfg.AgentDefaults.Ports = ports
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for %+v", ports)
+		}
+	}
+}
+
 func TestLoopConfigValidation(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Profiles = []ProfileConfig{{
@@ -199,6 +362,114 @@ func TestExplicitConfigFileNotFound(t *testing.T) {
 	}
 }
 
+func TestEnvironmentOverlay(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "config.yaml")
+	baseContent := `
+logging:
+  level: debug
+  file: ${FORGE_TEST_LOG_DIR:-/var/log}/forge.log
+database:
+  max_connections: 20
+  journal_mode: WAL
+`
+	overlayContent := `
+database:
+  max_connections: 40
+tui:
+  theme: ${FORGE_TEST_THEME}
+`
+	if err := os.WriteFile(base, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("Failed to write base config: %v", err)
+	}
+	if err := os.WriteFile(OverlayPath(base, "prod"), []byte(overlayContent), 0644); err != nil {
+		t.Fatalf("Failed to write overlay: %v", err)
+	}
+	t.Setenv("FORGE_ENV", "prod")
+	t.Setenv("FORGE_TEST_THEME", "high-contrast")
+	t.Setenv("FORGE_LOGGING_LEVEL", "warn")
+
+	loader := NewLoader()
+	loader.SetConfigFile(base)
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Database.MaxConnections != 40 {
+		t.Errorf("Expected overlay max_connections = 40, got %d", cfg.Database.MaxConnections)
+	}
+	if cfg.Database.JournalMode != "WAL" {
+		t.Errorf("Expected base journal_mode to survive the overlay, got %q", cfg.Database.JournalMode)
+	}
+	if cfg.TUI.Theme != "high-contrast" {
+		t.Errorf("Expected interpolated theme, got %q", cfg.TUI.Theme)
+	}
+	if cfg.Logging.File != "/var/log/forge.log" {
+		t.Errorf("Expected default interpolation, got %q", cfg.Logging.File)
+	}
+	if cfg.Logging.Level != "warn" {
+		t.Errorf("Expected env var to beat both files, got %q", cfg.Logging.Level)
+	}
+
+	if files := loader.Files(); len(files) != 2 || files[0] != base || files[1] != filepath.Join(tmpDir, "config.prod.yaml") {
+		t.Errorf("Unexpected files %v", files)
+	}
+	for key, want := range map[string]string{
+		"database.max_connections":                     SourceOverlay,
+		"database.journal_mode":                        SourceFile,
+		"logging.level":                                SourceEnv,
+		"scheduler.max_retries":                        SourceDefault,
+		"scheduler.retry_policies.message.max_retries": SourceDefault,
+	} {
+		if got := loader.Source(key); got != want {
+			t.Errorf("Source(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEnvironmentOverlayMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(base, []byte("logging:\n  level: debug\n"), 0644); err != nil {
+		t.Fatalf("Failed to write base config: %v", err)
+	}
+
+	loader := NewLoader()
+	loader.SetConfigFile(base)
+	loader.SetEnvironment("staging")
+	if _, err := loader.Load(); err == nil {
+		t.Error("Load() should error when the selected overlay is missing")
+	}
+
+	loader = NewLoader()
+	loader.SetConfigFile(base)
+	loader.SetEnvironment("../prod")
+	if _, err := loader.Load(); err == nil {
+		t.Error("Load() should reject invalid environment names")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("FORGE_TEST_HOST", "db.internal")
+	t.Setenv("FORGE_TEST_EMPTY", "")
+
+	tests := map[string]string{
+		"plain":                          "plain",
+		"${FORGE_TEST_HOST}:5432":        "db.internal:5432",
+		"${FORGE_TEST_UNSET}":            "",
+		"${FORGE_TEST_UNSET:-fallback}":  "fallback",
+		"${FORGE_TEST_EMPTY:-fallback}":  "fallback",
+		"$${FORGE_TEST_HOST} stays":      "${FORGE_TEST_HOST} stays",
+		"cost $5 and ${FORGE_TEST_HOST}": "cost $5 and db.internal",
+	}
+	for input, want := range tests {
+		if got := Interpolate(input); got != want {
+			t.Errorf("Interpolate(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
 func TestDatabasePath(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -348,3 +619,64 @@ database:
 		t.Errorf("Database.Path = %q, want %q", cfg.Database.Path, expectedDBPath)
 	}
 }
+
+func TestSchedulerRetryPoliciesMergeWithDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	content := "scheduler:\n  retry_policies:\n    message:\n      policy: immediate\n      max_retries: 1\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	message := cfg.Scheduler.RetryPolicies["message"]
+	if message.Policy != "immediate" || message.MaxRetries != 1 {
+		t.Errorf("Expected message override, got %+v", message)
+	}
+	if pause := cfg.Scheduler.RetryPolicies["pause"]; pause.Policy != "none" {
+		t.Errorf("Expected default pause policy to survive, got %+v", pause)
+	}
+
+	cfg.Scheduler.RetryPolicies["message"] = RetryPolicyConfig{Policy: "sometimes"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown retry policy")
+	}
+}
+
+func TestSchedulerLegacyRetryKeysFillPolicies(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	content := "scheduler:\n  max_retries: 7\n  retry_backoff: 2s\n  retry_policies:\n    message:\n      policy: exponential\n      max_retries: 1\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if message := cfg.Scheduler.RetryPolicies["message"]; message.MaxRetries != 1 || message.Backoff != 2*time.Second {
+		t.Errorf("Expected explicit max_retries kept and legacy backoff applied, got %+v", message)
+	}
+	if conditional := cfg.Scheduler.RetryPolicies["conditional"]; conditional.MaxRetries != 7 || conditional.Backoff != 2*time.Second {
+		t.Errorf("Expected legacy keys applied to conditional, got %+v", conditional)
+	}
+	if pause := cfg.Scheduler.RetryPolicies["pause"]; pause.MaxRetries != 0 {
+		t.Errorf("Expected pause to keep never retrying, got %+v", pause)
+	}
+	if deprecated := loader.Deprecated(); len(deprecated) != 2 || deprecated[0].Key != "scheduler.max_retries" {
+		t.Errorf("Expected deprecation warnings for both legacy keys, got %v", deprecated)
+	}
+	if unknown := loader.UnknownKeys(); len(unknown) != 0 {
+		t.Errorf("Legacy keys must not be reported as unknown, got %v", unknown)
+	}
+}
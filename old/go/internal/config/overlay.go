@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvironmentEnvVar selects the environment overlay when the loader has no
+// explicit environment.
+const EnvironmentEnvVar = "FORGE_ENV"
+
+// Key sources reported by Loader.Source, lowest precedence first.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceOverlay = "overlay"
+	SourceEnv     = "env"
+)
+
+var (
+	environmentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+	interpolationPattern   = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+)
+
+// SetEnvironment selects the environment overlay, overriding FORGE_ENV.
+func (l *Loader) SetEnvironment(name string) {
+	l.environment = strings.TrimSpace(name)
+}
+
+// Environment returns the environment whose overlay is (or will be) merged,
+// or "" when none is selected.
+func (l *Loader) Environment() string {
+	if l.environment != "" {
+		return l.environment
+	}
+	return strings.TrimSpace(os.Getenv(EnvironmentEnvVar))
+}
+
+// Files returns the config files merged by Load, base file first.
+func (l *Loader) Files() []string {
+	return append([]string(nil), l.files...)
+}
+
+// Source reports which layer set key: env, overlay, file, or default.
+func (l *Loader) Source(key string) string {
+	key = strings.ToLower(key)
+	if _, ok := os.LookupEnv("FORGE_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))); ok {
+		return SourceEnv
+	}
+	if source, ok := l.keySources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// OverlayPath returns the environment overlay that sits next to base:
+// config.yaml with environment prod becomes config.prod.yaml.
+func OverlayPath(base, environment string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + environment + ext
+}
+
+// mergeFiles merges the base config file and, when an environment is
+// selected, its overlay, interpolating environment variables in both.
+func (l *Loader) mergeFiles(base string) error {
+	if err := l.mergeFile(base, SourceFile); err != nil {
+		return err
+	}
+
+	environment := l.Environment()
+	if environment == "" {
+		return nil
+	}
+	if !environmentNamePattern.MatchString(environment) {
+		return fmt.Errorf("invalid environment name %q", environment)
+	}
+	overlay := OverlayPath(base, environment)
+	if _, err := os.Stat(overlay); err != nil {
+		return fmt.Errorf("environment %q overlay: %w", environment, err)
+	}
+	return l.mergeFile(overlay, SourceOverlay)
+}
+
+func (l *Loader) mergeFile(path, source string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fv := viper.New()
+	fv.SetConfigType(configTypeForPath(path))
+	if err := fv.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	settings := interpolateValue(fv.AllSettings()).(map[string]any)
+	l.collectUnknownKeys(settings, path)
+	if err := l.v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, key := range fv.AllKeys() {
+		l.keySources[key] = source
+	}
+	l.files = append(l.files, path)
+	return nil
+}
+
+func configTypeForPath(path string) string {
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext != "" {
+		return ext
+	}
+	return "yaml"
+}
+
+// interpolateValue expands ${VAR} and ${VAR:-default} in every string of a
+// parsed config tree. Unset variables expand to the default or "", and $${
+// produces a literal ${.
+func interpolateValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return Interpolate(v)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = interpolateValue(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// Interpolate expands ${VAR} and ${VAR:-default} references in s.
+func Interpolate(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return interpolationPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		parts := interpolationPattern.FindStringSubmatch(match)
+		if value, ok := os.LookupEnv(parts[1]); ok && value != "" {
+			return value
+		}
+		return parts[2]
+	})
+}
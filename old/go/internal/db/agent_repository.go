@@ -43,6 +43,12 @@ func (r *AgentRepository) Create(ctx context.Context, agent *models.Agent) error
 		agent.ID = uuid.New().String()
 	}
 
+	namespace, err := r.createNamespace(ctx, agent)
+	if err != nil {
+		return err
+	}
+	agent.Namespace = namespace
+
 	now := time.Now().UTC()
 	agent.CreatedAt = now
 	agent.UpdatedAt = now
@@ -68,14 +74,15 @@ func (r *AgentRepository) Create(ctx context.Context, agent *models.Agent) error
 
 	_, err = r.db.ExecContext(ctx, `
 		INSERT INTO agents (
-			id, workspace_id, type, tmux_pane, account_id,
+			id, workspace_id, namespace, type, tmux_pane, account_id,
 			state, state_confidence, state_reason, state_detected_at,
 			paused_until, last_activity_at, metadata_json,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		agent.ID,
 		agent.WorkspaceID,
+		agent.Namespace,
 		string(agent.Type),
 		agent.TmuxPane,
 		accountID,
@@ -101,28 +108,30 @@ func (r *AgentRepository) Create(ctx context.Context, agent *models.Agent) error
 
 // Get retrieves an agent by ID.
 func (r *AgentRepository) Get(ctx context.Context, id string) (*models.Agent, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
-			id, workspace_id, type, tmux_pane, account_id,
+			id, workspace_id, namespace, type, tmux_pane, account_id,
 			state, state_confidence, state_reason, state_detected_at,
 			paused_until, last_activity_at, metadata_json,
 			created_at, updated_at
-		FROM agents WHERE id = ?
-	`, id)
+		FROM agents WHERE id = ?`+filter, append([]any{id}, filterArgs...)...)
 
 	return r.scanAgent(row)
 }
 
 // List retrieves all agents.
 func (r *AgentRepository) List(ctx context.Context) ([]*models.Agent, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
-			id, workspace_id, type, tmux_pane, account_id,
+			id, workspace_id, namespace, type, tmux_pane, account_id,
 			state, state_confidence, state_reason, state_detected_at,
 			paused_until, last_activity_at, metadata_json,
 			created_at, updated_at
-		FROM agents ORDER BY created_at
-	`)
+		FROM agents`+filter+`
+		ORDER BY created_at
+	`, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query agents: %w", err)
 	}
@@ -133,15 +142,16 @@ func (r *AgentRepository) List(ctx context.Context) ([]*models.Agent, error) {
 
 // ListByWorkspace retrieves agents for a specific workspace.
 func (r *AgentRepository) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.Agent, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
-			id, workspace_id, type, tmux_pane, account_id,
+			id, workspace_id, namespace, type, tmux_pane, account_id,
 			state, state_confidence, state_reason, state_detected_at,
 			paused_until, last_activity_at, metadata_json,
 			created_at, updated_at
-		FROM agents WHERE workspace_id = ?
+		FROM agents WHERE workspace_id = ?`+filter+`
 		ORDER BY created_at
-	`, workspaceID)
+	`, append([]any{workspaceID}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query agents by workspace: %w", err)
 	}
@@ -152,15 +162,16 @@ func (r *AgentRepository) ListByWorkspace(ctx context.Context, workspaceID strin
 
 // ListByState retrieves agents with a specific state.
 func (r *AgentRepository) ListByState(ctx context.Context, state models.AgentState) ([]*models.Agent, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
-			id, workspace_id, type, tmux_pane, account_id,
+			id, workspace_id, namespace, type, tmux_pane, account_id,
 			state, state_confidence, state_reason, state_detected_at,
 			paused_until, last_activity_at, metadata_json,
 			created_at, updated_at
-		FROM agents WHERE state = ?
+		FROM agents WHERE state = ?`+filter+`
 		ORDER BY created_at
-	`, string(state))
+	`, append([]any{string(state)}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query agents by state: %w", err)
 	}
@@ -171,9 +182,10 @@ func (r *AgentRepository) ListByState(ctx context.Context, state models.AgentSta
 
 // ListWithQueueLength retrieves all agents with queue length counts.
 func (r *AgentRepository) ListWithQueueLength(ctx context.Context) ([]*models.Agent, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "a.namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
-			a.id, a.workspace_id, a.type, a.tmux_pane, a.account_id,
+			a.id, a.workspace_id, a.namespace, a.type, a.tmux_pane, a.account_id,
 			a.state, a.state_confidence, a.state_reason, a.state_detected_at,
 			a.paused_until, a.last_activity_at, a.metadata_json,
 			a.created_at, a.updated_at,
@@ -181,10 +193,10 @@ func (r *AgentRepository) ListWithQueueLength(ctx context.Context) ([]*models.Ag
 		FROM agents a
 		LEFT JOIN queue_items q
 			ON q.agent_id = a.id
-			AND q.status = 'pending'
+			AND q.status = 'pending'`+filter+`
 		GROUP BY a.id
 		ORDER BY a.created_at
-	`)
+	`, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query agents with queue length: %w", err)
 	}
@@ -223,7 +235,23 @@ func (r *AgentRepository) UpdateWithEvent(ctx context.Context, agent *models.Age
 	})
 }
 
+// UpdateWithOutbox updates an agent and enqueues an event for publishing
+// atomically.
+func (r *AgentRepository) UpdateWithOutbox(ctx context.Context, agent *models.Agent, event *models.Event, outbox *OutboxRepository) error {
+	if event == nil || outbox == nil {
+		return r.Update(ctx, agent)
+	}
+
+	return r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		if err := r.updateWithExecutor(ctx, tx, agent); err != nil {
+			return err
+		}
+		return outbox.EnqueueWithTx(ctx, tx, event)
+	})
+}
+
 func (r *AgentRepository) updateWithExecutor(ctx context.Context, execer agentExecer, agent *models.Agent) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	if err := agent.Validate(); err != nil {
 		return fmt.Errorf("invalid agent: %w", err)
 	}
@@ -262,8 +290,7 @@ func (r *AgentRepository) updateWithExecutor(ctx context.Context, execer agentEx
 			last_activity_at = ?,
 			metadata_json = ?,
 			updated_at = ?
-		WHERE id = ?
-	`,
+		WHERE id = ?`+filter, append([]any{
 		agent.WorkspaceID,
 		string(agent.Type),
 		agent.TmuxPane,
@@ -277,7 +304,7 @@ func (r *AgentRepository) updateWithExecutor(ctx context.Context, execer agentEx
 		string(metadataJSON),
 		agent.UpdatedAt.Format(time.RFC3339),
 		agent.ID,
-	)
+	}, filterArgs...)...)
 	if err != nil {
 		if isUniqueConstraintError(err) {
 			return ErrAgentAlreadyExists
@@ -297,9 +324,59 @@ func (r *AgentRepository) updateWithExecutor(ctx context.Context, execer agentEx
 	return nil
 }
 
+// UpdateResourceUsage stores a cgroup usage snapshot in the agent's metadata.
+// Only the resources key is replaced, so it does not race with state updates
+// that rewrite the rest of the record.
+func (r *AgentRepository) UpdateResourceUsage(ctx context.Context, id string, usage *models.ResourceUsage) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
+	value := "null"
+	if usage != nil {
+		data, err := json.Marshal(usage)
+		if err != nil {
+			return fmt.Errorf("failed to marshal resource usage: %w", err)
+		}
+		value = string(data)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE agents SET
+			metadata_json = json_set(COALESCE(NULLIF(metadata_json, ''), '{}'), '$.resources', json(?)),
+			updated_at = ?
+		WHERE id = ?`+filter, append([]any{value, time.Now().UTC().Format(time.RFC3339), id}, filterArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update resource usage: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAgentNotFound
+	}
+	return nil
+}
+
+// ListRunaway returns agents whose last resource snapshot shows an OOM kill
+// or memory use at or above threshold of the limit.
+func (r *AgentRepository) ListRunaway(ctx context.Context, threshold float64) ([]*models.Agent, error) {
+	agents, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runaway := make([]*models.Agent, 0)
+	for _, agent := range agents {
+		if agent.Metadata.Resources.IsRunaway(threshold) {
+			runaway = append(runaway, agent)
+		}
+	}
+	return runaway, nil
+}
+
 // Delete removes an agent by ID.
 func (r *AgentRepository) Delete(ctx context.Context, id string) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM agents WHERE id = ?", id)
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM agents WHERE id = ?"+filter, append([]any{id}, filterArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete agent: %w", err)
 	}
@@ -316,6 +393,30 @@ func (r *AgentRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// createNamespace returns the namespace for a new agent: its own, else its
+// workspace's. A workspace outside the query scope is not found.
+func (r *AgentRepository) createNamespace(ctx context.Context, agent *models.Agent) (string, error) {
+	if agent.Namespace == "" {
+		filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
+		var namespace string
+		err := r.db.QueryRowContext(ctx, "SELECT namespace FROM workspaces WHERE id = ?"+filter,
+			append([]any{agent.WorkspaceID}, filterArgs...)...).Scan(&namespace)
+		switch {
+		case err == nil:
+			return namespace, nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return "", fmt.Errorf("failed to look up workspace namespace: %w", err)
+		case filter != "":
+			return "", ErrWorkspaceNotFound
+		}
+	}
+	namespace, err := r.db.createNamespace(ctx, agent.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("invalid agent: %w", err)
+	}
+	return namespace, nil
+}
+
 func (r *AgentRepository) scanAgent(row *sql.Row) (*models.Agent, error) {
 	var agent models.Agent
 	var agentType, state, confidence string
@@ -327,6 +428,7 @@ func (r *AgentRepository) scanAgent(row *sql.Row) (*models.Agent, error) {
 	err := row.Scan(
 		&agent.ID,
 		&agent.WorkspaceID,
+		&agent.Namespace,
 		&agentType,
 		&agent.TmuxPane,
 		&accountID,
@@ -365,6 +467,7 @@ func (r *AgentRepository) scanAgents(rows *sql.Rows) ([]*models.Agent, error) {
 		err := rows.Scan(
 			&agent.ID,
 			&agent.WorkspaceID,
+			&agent.Namespace,
 			&agentType,
 			&agent.TmuxPane,
 			&accountID,
@@ -408,6 +511,7 @@ func (r *AgentRepository) scanAgentsWithQueueLength(rows *sql.Rows) ([]*models.A
 		err := rows.Scan(
 			&agent.ID,
 			&agent.WorkspaceID,
+			&agent.Namespace,
 			&agentType,
 			&agent.TmuxPane,
 			&accountID,
@@ -312,3 +312,59 @@ func TestAgentRepository_UpdateWithEventAtomic(t *testing.T) {
 		t.Fatalf("expected 1 event, got %d", len(events))
 	}
 }
+
+func TestAgentRepository_UpdateResourceUsage(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ws := createTestWorkspace(t, db)
+	repo := NewAgentRepository(db)
+	ctx := context.Background()
+
+	agent := &models.Agent{
+		WorkspaceID: ws.ID,
+		Type:        models.AgentTypeOpenCode,
+		TmuxPane:    "forge-test:0.1",
+		State:       models.AgentStateWorking,
+		Metadata:    models.AgentMetadata{Model: "gpt-5"},
+	}
+	if err := repo.Create(ctx, agent); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	usage := &models.ResourceUsage{
+		CgroupPath:  "/sys/fs/cgroup/forge/" + agent.ID,
+		Limits:      models.ResourceLimits{CPUCores: 1, MemoryBytes: 1000},
+		MemoryBytes: 960,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if err := repo.UpdateResourceUsage(ctx, agent.ID, usage); err != nil {
+		t.Fatalf("UpdateResourceUsage failed: %v", err)
+	}
+
+	retrieved, err := repo.Get(ctx, agent.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if retrieved.Metadata.Model != "gpt-5" {
+		t.Fatalf("expected other metadata preserved, got %+v", retrieved.Metadata)
+	}
+	if retrieved.Metadata.Resources == nil || retrieved.Metadata.Resources.MemoryBytes != 960 {
+		t.Fatalf("expected stored usage, got %+v", retrieved.Metadata.Resources)
+	}
+
+	runaway, err := repo.ListRunaway(ctx, 0.9)
+	if err != nil {
+		t.Fatalf("ListRunaway failed: %v", err)
+	}
+	if len(runaway) != 1 || runaway[0].ID != agent.ID {
+		t.Fatalf("expected agent to be runaway, got %d agents", len(runaway))
+	}
+	if runaway, _ := repo.ListRunaway(ctx, 0.99); len(runaway) != 0 {
+		t.Fatalf("expected no runaway agents above 99%%, got %d", len(runaway))
+	}
+
+	if err := repo.UpdateResourceUsage(ctx, "missing", usage); err != ErrAgentNotFound {
+		t.Fatalf("expected ErrAgentNotFound, got %v", err)
+	}
+}
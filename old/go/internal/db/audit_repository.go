@@ -0,0 +1,194 @@
+// Package db provides SQLite database access for Forge.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// AuditQuery defines filters for querying the audit log.
+type AuditQuery struct {
+	Actor     string              // Filter by actor
+	Action    string              // Filter by action prefix (e.g. "loop" matches "loop stop")
+	Source    *models.AuditSource // Filter by source
+	Result    *models.AuditResult // Filter by result
+	RequestID string              // Filter by request ID
+	Since     *time.Time          // Entries at or after this time (inclusive)
+	Until     *time.Time          // Entries before this time (exclusive)
+	Cursor    string              // Pagination cursor (entry ID)
+	Limit     int                 // Max results to return
+}
+
+// AuditPage represents a page of audit log results.
+type AuditPage struct {
+	Entries    []*models.AuditEntry
+	NextCursor string
+}
+
+// AuditRepository persists the audit log of mutating requests.
+type AuditRepository struct {
+	db *DB
+}
+
+// NewAuditRepository creates a new AuditRepository.
+func NewAuditRepository(db *DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create records an audit entry, filling in its ID, timestamp, and request
+// ID when they are empty.
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry is required")
+	}
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("invalid audit entry: %w", err)
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = entry.ID
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	} else {
+		entry.Timestamp = entry.Timestamp.UTC()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO audit_log (
+			id, timestamp, request_id, actor, source, action, target,
+			payload_hash, result, error, duration_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		entry.ID,
+		entry.Timestamp.Format(time.RFC3339),
+		entry.RequestID,
+		entry.Actor,
+		string(entry.Source),
+		entry.Action,
+		nullableString(entry.Target),
+		nullableString(entry.PayloadHash),
+		string(entry.Result),
+		nullableString(entry.Error),
+		entry.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves audit entries matching the filters, oldest first, with
+// cursor-based pagination.
+func (r *AuditRepository) Query(ctx context.Context, q AuditQuery) (*AuditPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id, timestamp, request_id, actor, source, action, target,
+		payload_hash, result, error, duration_ms FROM audit_log WHERE 1=1`
+	args := []any{}
+
+	if q.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, q.Actor)
+	}
+	if q.Action != "" {
+		prefix := q.Action + " "
+		query += ` AND (action = ? OR substr(action, 1, ?) = ?)`
+		args = append(args, q.Action, utf8.RuneCountInString(prefix), prefix)
+	}
+	if q.Source != nil {
+		query += ` AND source = ?`
+		args = append(args, string(*q.Source))
+	}
+	if q.Result != nil {
+		query += ` AND result = ?`
+		args = append(args, string(*q.Result))
+	}
+	if q.RequestID != "" {
+		query += ` AND request_id = ?`
+		args = append(args, q.RequestID)
+	}
+	if q.Since != nil {
+		query += ` AND timestamp >= ?`
+		args = append(args, q.Since.UTC().Format(time.RFC3339))
+	}
+	if q.Until != nil {
+		query += ` AND timestamp < ?`
+		args = append(args, q.Until.UTC().Format(time.RFC3339))
+	}
+	if q.Cursor != "" {
+		query += ` AND (timestamp, id) > (SELECT timestamp, id FROM audit_log WHERE id = ?)`
+		args = append(args, q.Cursor)
+	}
+
+	query += ` ORDER BY timestamp, id LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		var (
+			entry       models.AuditEntry
+			timestamp   string
+			source      string
+			result      string
+			target      sql.NullString
+			payloadHash sql.NullString
+			errText     sql.NullString
+			durationMs  int64
+		)
+		if err := rows.Scan(
+			&entry.ID,
+			&timestamp,
+			&entry.RequestID,
+			&entry.Actor,
+			&source,
+			&entry.Action,
+			&target,
+			&payloadHash,
+			&result,
+			&errText,
+			&durationMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+			entry.Timestamp = t
+		}
+		entry.Source = models.AuditSource(source)
+		entry.Result = models.AuditResult(result)
+		entry.Target = target.String
+		entry.PayloadHash = payloadHash.String
+		entry.Error = errText.String
+		entry.Duration = time.Duration(durationMs) * time.Millisecond
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	page := &AuditPage{}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = entries[limit-1].ID
+	} else {
+		page.Entries = entries
+	}
+	return page, nil
+}
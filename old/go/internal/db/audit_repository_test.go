@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestAuditRepository_CreateAndQuery(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewAuditRepository(db)
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	entries := []*models.AuditEntry{
+		{Timestamp: base, Actor: "alice", Source: models.AuditSourceCLI, Action: "loop stop", Target: "alpha", Result: models.AuditResultOK, Duration: 1500 * time.Millisecond},
+		{Timestamp: base.Add(time.Minute), Actor: "bob", Source: models.AuditSourceAPI, Action: "SpawnAgent", Result: models.AuditResultError, Error: "boom"},
+		{Timestamp: base.Add(2 * time.Minute), Actor: "alice", Source: models.AuditSourceCLI, Action: "loopx run", Result: models.AuditResultOK},
+		{Timestamp: base.Add(3 * time.Minute), Actor: "alice", Source: models.AuditSourceCLI, Action: "loop", Result: models.AuditResultOK},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if entries[0].ID == "" || entries[0].RequestID != entries[0].ID {
+		t.Fatalf("expected generated ID reused as request ID, got %+v", entries[0])
+	}
+
+	page, err := repo.Query(ctx, AuditQuery{Action: "loop"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Entries) != 2 || page.Entries[0].Action != "loop stop" || page.Entries[1].Action != "loop" {
+		t.Fatalf("expected action prefix to match whole words, got %+v", page.Entries)
+	}
+	if got := page.Entries[0]; got.Target != "alpha" || got.Duration != 1500*time.Millisecond || !got.Timestamp.Equal(base) {
+		t.Fatalf("unexpected round trip %+v", got)
+	}
+
+	failed := models.AuditResultError
+	page, err = repo.Query(ctx, AuditQuery{Result: &failed})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Actor != "bob" || page.Entries[0].Error != "boom" {
+		t.Fatalf("expected failed entry, got %+v", page.Entries)
+	}
+
+	page, err = repo.Query(ctx, AuditQuery{Actor: "alice", Limit: 2})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Entries) != 2 || page.NextCursor == "" {
+		t.Fatalf("expected first page with cursor, got %+v", page)
+	}
+	page, err = repo.Query(ctx, AuditQuery{Actor: "alice", Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Action != "loop" {
+		t.Fatalf("expected last entry on second page, got %+v", page.Entries)
+	}
+
+	if err := repo.Create(ctx, &models.AuditEntry{Actor: "alice", Source: "web", Action: "x", Result: models.AuditResultOK}); err == nil {
+		t.Fatal("expected invalid source to be rejected")
+	}
+}
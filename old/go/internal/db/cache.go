@@ -0,0 +1,266 @@
+package db
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultCacheTTL bounds how stale a cached profile, pool, or node can be
+// when another process writes to the database.
+const DefaultCacheTTL = 5 * time.Second
+
+// Cache namespaces. A write to a repository invalidates its whole namespace.
+const (
+	cacheProfiles = "profiles"
+	cachePools    = "pools"
+	cacheNodes    = "nodes"
+)
+
+// CacheStats reports repository cache effectiveness.
+type CacheStats struct {
+	Enabled       bool          `json:"enabled"`
+	TTL           time.Duration `json:"ttl"`
+	Hits          int64         `json:"hits"`
+	Misses        int64         `json:"misses"`
+	Invalidations int64         `json:"invalidations"`
+	Entries       int           `json:"entries"`
+}
+
+// HitRate returns hits / (hits + misses), or 0 before any lookup.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// repoCache is a TTL cache for read-mostly repositories. Values are cloned on
+// the way in and out so callers can mutate what they receive.
+type repoCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]map[string]cacheEntry
+	// generations bump on invalidation so a load that raced a write is not
+	// stored after the write invalidated the namespace.
+	generations map[string]uint64
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+func newRepoCache(ttl time.Duration) *repoCache {
+	return &repoCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]map[string]cacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+func (c *repoCache) get(namespace, key string) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[namespace][key]
+	if ok && c.now().After(entry.expires) {
+		delete(c.entries[namespace], key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return entry.value, ok
+}
+
+func (c *repoCache) generation(namespace string) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[namespace]
+}
+
+func (c *repoCache) put(namespace, key string, value any, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[namespace] != generation {
+		return
+	}
+	bucket := c.entries[namespace]
+	if bucket == nil {
+		bucket = make(map[string]cacheEntry)
+		c.entries[namespace] = bucket
+	}
+	bucket[key] = cacheEntry{value: value, expires: c.now().Add(c.ttl)}
+}
+
+func (c *repoCache) invalidate(namespace string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, namespace)
+	c.generations[namespace]++
+	c.mu.Unlock()
+	c.invalidations.Add(1)
+}
+
+func (c *repoCache) stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	entries := 0
+	for _, bucket := range c.entries {
+		entries += len(bucket)
+	}
+	c.mu.Unlock()
+	return CacheStats{
+		Enabled:       true,
+		TTL:           c.ttl,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+// SetCacheTTL enables the repository cache with the given TTL, or disables
+// it when ttl <= 0. Existing entries are dropped.
+func (db *DB) SetCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		db.cache.Store(nil)
+		return
+	}
+	db.cache.Store(newRepoCache(ttl))
+}
+
+// CacheStats returns repository cache counters.
+func (db *DB) CacheStats() CacheStats {
+	return db.repoCache().stats()
+}
+
+// InvalidateCache drops all cached repository lookups.
+func (db *DB) InvalidateCache() {
+	c := db.repoCache()
+	for _, namespace := range []string{cacheProfiles, cachePools, cacheNodes} {
+		c.invalidate(namespace)
+	}
+}
+
+func (db *DB) invalidateCache(namespace string) {
+	db.repoCache().invalidate(namespace)
+}
+
+func (db *DB) repoCache() *repoCache {
+	return db.cache.Load()
+}
+
+// cachedOne returns a cached single-row lookup or loads and caches it.
+// Errors, including not-found, are never cached.
+func cachedOne[T any](c *repoCache, namespace, key string, clone func(*T) *T, load func() (*T, error)) (*T, error) {
+	if value, ok := c.get(namespace, key); ok {
+		return clone(value.(*T)), nil
+	}
+	generation := c.generation(namespace)
+	item, err := load()
+	if err != nil || c == nil {
+		return item, err
+	}
+	c.put(namespace, key, clone(item), generation)
+	return item, nil
+}
+
+// cachedList is cachedOne for list queries.
+func cachedList[T any](c *repoCache, namespace, key string, clone func(*T) *T, load func() ([]*T, error)) ([]*T, error) {
+	if value, ok := c.get(namespace, key); ok {
+		return cloneList(value.([]*T), clone), nil
+	}
+	generation := c.generation(namespace)
+	items, err := load()
+	if err != nil || c == nil {
+		return items, err
+	}
+	c.put(namespace, key, cloneList(items, clone), generation)
+	return items, nil
+}
+
+func cloneList[T any](items []*T, clone func(*T) *T) []*T {
+	if items == nil {
+		return nil
+	}
+	out := make([]*T, len(items))
+	for i, item := range items {
+		out[i] = clone(item)
+	}
+	return out
+}
+
+func cloneTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := *t
+	return &value
+}
+
+func cloneProfile(p *models.Profile) *models.Profile {
+	out := *p
+	if p.ExtraArgs != nil {
+		out.ExtraArgs = append([]string(nil), p.ExtraArgs...)
+	}
+	if p.Env != nil {
+		out.Env = make(map[string]string, len(p.Env))
+		for k, v := range p.Env {
+			out.Env[k] = v
+		}
+	}
+	out.CooldownUntil = cloneTimePtr(p.CooldownUntil)
+	return &out
+}
+
+func clonePool(p *models.Pool) *models.Pool {
+	out := *p
+	if p.Metadata != nil {
+		out.Metadata = make(map[string]any, len(p.Metadata))
+		for k, v := range p.Metadata {
+			out.Metadata[k] = v
+		}
+	}
+	return &out
+}
+
+func cloneNode(n *models.Node) *models.Node {
+	out := *n
+	out.LastSeen = cloneTimePtr(n.LastSeen)
+	if n.Metadata.AvailableAdapters != nil {
+		out.Metadata.AvailableAdapters = append([]string(nil), n.Metadata.AvailableAdapters...)
+	}
+	if n.Labels != nil {
+		out.Labels = make(map[string]string, len(n.Labels))
+		for key, value := range n.Labels {
+			out.Labels[key] = value
+		}
+	}
+	return &out
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestRepositoryCacheServesHitsAndClones(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetCacheTTL(time.Minute)
+
+	repo := NewProfileRepository(db)
+	ctx := context.Background()
+	profile := &models.Profile{
+		Name:            "pi-default",
+		Harness:         models.HarnessPi,
+		CommandTemplate: "pi -p \"{prompt}\"",
+		MaxConcurrency:  1,
+		PromptMode:      models.PromptModePath,
+		Env:             map[string]string{"A": "1"},
+	}
+	if err := repo.Create(ctx, profile); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	first, err := repo.Get(ctx, profile.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	first.Name = "mutated"
+	first.Env["A"] = "mutated"
+
+	second, err := NewProfileRepository(db).Get(ctx, profile.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if second.Name != "pi-default" || second.Env["A"] != "1" {
+		t.Fatalf("cached profile was mutated through a returned copy: %+v", second)
+	}
+
+	stats := db.CacheStats()
+	if !stats.Enabled || stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.HitRate() != 0.5 {
+		t.Fatalf("hit rate = %v", stats.HitRate())
+	}
+}
+
+func TestRepositoryCacheInvalidatesOnWrite(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetCacheTTL(time.Minute)
+
+	repo := NewPoolRepository(db)
+	ctx := context.Background()
+	pool := &models.Pool{Name: "default", Strategy: models.PoolStrategyRoundRobin}
+	if err := repo.Create(ctx, pool); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	pools, err := repo.List(ctx)
+	if err != nil || len(pools) != 1 {
+		t.Fatalf("List = %d, %v", len(pools), err)
+	}
+	if _, err := repo.GetDefault(ctx); !errors.Is(err, ErrPoolNotFound) {
+		t.Fatalf("expected no default pool yet, got %v", err)
+	}
+
+	if err := repo.Create(ctx, &models.Pool{Name: "second", Strategy: models.PoolStrategyRoundRobin}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.SetDefault(ctx, pool.ID); err != nil {
+		t.Fatalf("SetDefault failed: %v", err)
+	}
+
+	pools, err = repo.List(ctx)
+	if err != nil || len(pools) != 2 {
+		t.Fatalf("expected list to reflect new pool, got %d, %v", len(pools), err)
+	}
+	def, err := repo.GetDefault(ctx)
+	if err != nil || def.ID != pool.ID {
+		t.Fatalf("expected default pool after invalidation, got %v, %v", def, err)
+	}
+	if db.CacheStats().Invalidations < 2 {
+		t.Fatalf("expected invalidations to be counted: %+v", db.CacheStats())
+	}
+}
+
+func TestRepositoryCacheExpiresAfterTTL(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetCacheTTL(time.Second)
+	now := time.Now()
+	db.repoCache().now = func() time.Time { return now }
+
+	repo := NewNodeRepository(db)
+	ctx := context.Background()
+	node := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, IsLocal: true, Status: models.NodeStatusOnline}
+	if err := repo.Create(ctx, node); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, node.ID); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	// Simulate another process writing behind the cache's back.
+	if _, err := db.DB.ExecContext(ctx, `UPDATE nodes SET name = 'renamed' WHERE id = ?`, node.ID); err != nil {
+		t.Fatalf("raw update failed: %v", err)
+	}
+	cached, _ := repo.Get(ctx, node.ID)
+	if cached.Name != "local" {
+		t.Fatalf("expected cached name before expiry, got %q", cached.Name)
+	}
+
+	now = now.Add(2 * time.Second)
+	fresh, _ := repo.Get(ctx, node.ID)
+	if fresh.Name != "renamed" {
+		t.Fatalf("expected fresh name after expiry, got %q", fresh.Name)
+	}
+}
+
+func TestRepositoryCacheDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProfileRepository(db)
+	if _, err := repo.List(context.Background()); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if stats := db.CacheStats(); stats.Enabled || stats.Misses != 0 {
+		t.Fatalf("expected cache to be disabled by default: %+v", stats)
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
 
@@ -18,11 +23,26 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// ErrReadOnly is returned by schema changes on a database opened read-only.
+var ErrReadOnly = errors.New("database is open read-only")
+
 // DB wraps the SQLite database connection.
 type DB struct {
 	*sql.DB
 	mu     sync.RWMutex
 	logger zerolog.Logger
+	cache  atomic.Pointer[repoCache]
+
+	// busyRetries and busyBackoff bound the retry of statements that fail
+	// with SQLITE_BUSY (busyRetries <= 1 disables retry).
+	busyRetries int
+	busyBackoff time.Duration
+
+	readOnly bool
+
+	// namespace scopes workspace, agent, loop, and event queries; empty
+	// sees every namespace (see namespace.go).
+	namespace atomic.Value
 }
 
 // Config contains database configuration.
@@ -35,13 +55,45 @@ type Config struct {
 
 	// BusyTimeoutMs is the busy timeout in milliseconds.
 	BusyTimeoutMs int
+
+	// JournalMode is the SQLite journal mode (default "WAL").
+	JournalMode string
+
+	// Synchronous is the SQLite synchronous level (default "NORMAL").
+	Synchronous string
+
+	// BusyRetries is the number of attempts made for a statement or
+	// transaction that fails with SQLITE_BUSY after busy_timeout has elapsed
+	// (0 or 1 = no retry).
+	BusyRetries int
+
+	// BusyRetryBackoff is the delay before the first retry; it doubles on
+	// each further attempt.
+	BusyRetryBackoff time.Duration
+
+	// CacheTTL enables the profile/pool/node lookup cache (0 = disabled).
+	CacheTTL time.Duration
+
+	// ReadOnly opens the database file read-only and rejects writes and
+	// migrations, so a reader never contends with (or changes the schema
+	// under) the process that owns the writer. The file must already exist.
+	ReadOnly bool
+
+	// Namespace scopes workspace, agent, loop, and event queries to one
+	// tenant. Empty leaves queries unscoped.
+	Namespace string
 }
 
 // DefaultConfig returns the default database configuration.
 func DefaultConfig() Config {
 	return Config{
-		MaxOpenConns:  10,
-		BusyTimeoutMs: 5000,
+		MaxOpenConns:     10,
+		BusyTimeoutMs:    5000,
+		JournalMode:      "WAL",
+		Synchronous:      "NORMAL",
+		BusyRetries:      defaultRetryAttempts,
+		BusyRetryBackoff: defaultRetryBackoff,
+		CacheTTL:         DefaultCacheTTL,
 	}
 }
 
@@ -54,11 +106,7 @@ func Open(cfg Config) (*DB, error) {
 	// Ensure directory exists
 	_ = filepath.Dir(cfg.Path)
 
-	// Build connection string with pragmas
-	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_pragma=synchronous(NORMAL)",
-		cfg.Path, cfg.BusyTimeoutMs)
-
-	db, err := sql.Open("sqlite", dsn)
+	db, err := sql.Open("sqlite", buildDSN(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -74,10 +122,54 @@ func Open(cfg Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{
-		DB:     db,
-		logger: logging.Component("db"),
-	}, nil
+	database := &DB{
+		DB:          db,
+		logger:      logging.Component("db"),
+		busyRetries: cfg.BusyRetries,
+		busyBackoff: cfg.BusyRetryBackoff,
+		readOnly:    cfg.ReadOnly,
+	}
+	database.SetNamespace(cfg.Namespace)
+	if database.busyBackoff <= 0 {
+		database.busyBackoff = defaultRetryBackoff
+	}
+	database.SetCacheTTL(cfg.CacheTTL)
+	return database, nil
+}
+
+// buildDSN builds the connection string, applying the configured pragmas to
+// every pooled connection.
+func buildDSN(cfg Config) string {
+	journalMode := strings.ToUpper(strings.TrimSpace(cfg.JournalMode))
+	if journalMode == "" {
+		journalMode = "WAL"
+	}
+	synchronous := strings.ToUpper(strings.TrimSpace(cfg.Synchronous))
+	if synchronous == "" {
+		synchronous = "NORMAL"
+	}
+	busyTimeout := cfg.BusyTimeoutMs
+	if busyTimeout < 0 {
+		busyTimeout = 0
+	}
+	if cfg.ReadOnly {
+		// The journal mode is the writer's to set. query_only also blocks
+		// writes to temp tables, which mode=ro alone allows.
+		// A relative path would be read as the URI authority, so anchor it.
+		path := cfg.Path
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		path = filepath.ToSlash(path)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path // Windows volume paths
+		}
+		uri := url.URL{Scheme: "file", Path: path}
+		return fmt.Sprintf("%s?mode=ro&_pragma=busy_timeout(%d)&_pragma=foreign_keys(ON)&_pragma=query_only(1)",
+			uri.String(), busyTimeout)
+	}
+	return fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(%s)&_pragma=foreign_keys(ON)&_pragma=synchronous(%s)",
+		cfg.Path, busyTimeout, journalMode, synchronous)
 }
 
 // OpenInMemory opens an in-memory SQLite database (for testing).
@@ -97,6 +189,25 @@ func OpenInMemory() (*DB, error) {
 	}, nil
 }
 
+// ReadOnly reports whether the database was opened read-only.
+func (db *DB) ReadOnly() bool {
+	return db.readOnly
+}
+
+// IsReadOnlyError reports whether err was caused by a write to a database
+// opened read-only.
+func IsReadOnlyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrReadOnly) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "readonly database") ||
+		strings.Contains(message, "sqlite_readonly")
+}
+
 // Migrate runs all pending database migrations.
 func (db *DB) Migrate(ctx context.Context) error {
 	_, err := db.MigrateUp(ctx)
@@ -105,11 +216,27 @@ func (db *DB) Migrate(ctx context.Context) error {
 
 // Close closes the database connection.
 func (db *DB) Close() error {
+	if stats := db.CacheStats(); stats.Enabled && stats.Hits+stats.Misses > 0 {
+		db.logger.Debug().
+			Int64("hits", stats.Hits).
+			Int64("misses", stats.Misses).
+			Int64("invalidations", stats.Invalidations).
+			Float64("hit_rate", stats.HitRate()).
+			Msg("repository cache stats")
+	}
 	return db.DB.Close()
 }
 
-// Transaction executes a function within a database transaction.
+// Transaction executes a function within a database transaction. The whole
+// transaction is retried when it fails with SQLITE_BUSY and busy retries are
+// configured, so fn must not have side effects outside tx.
 func (db *DB) Transaction(ctx context.Context, fn func(*sql.Tx) error) error {
+	return db.retryBusy(ctx, func() error {
+		return db.transaction(ctx, fn)
+	})
+}
+
+func (db *DB) transaction(ctx context.Context, fn func(*sql.Tx) error) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 // Event repository errors.
@@ -65,6 +66,12 @@ func (r *EventRepository) Create(ctx context.Context, event *models.Event) error
 	return r.createWithExecutor(ctx, r.db, event)
 }
 
+// Ensure appends event unless an event with the same ID is already logged,
+// so replaying an event (e.g. from the outbox after a crash) is harmless.
+func (r *EventRepository) Ensure(ctx context.Context, event *models.Event) error {
+	return r.insertWithExecutor(ctx, r.db, event, "INSERT OR IGNORE")
+}
+
 // CreateWithTx appends a new event using an existing transaction.
 func (r *EventRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, event *models.Event) error {
 	if tx == nil {
@@ -74,23 +81,12 @@ func (r *EventRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, event *m
 }
 
 func (r *EventRepository) createWithExecutor(ctx context.Context, execer eventExecer, event *models.Event) error {
-	if event.Type == "" {
-		return fmt.Errorf("event type is required")
-	}
-	if event.EntityType == "" {
-		return fmt.Errorf("event entity type is required")
-	}
-	if event.EntityID == "" {
-		return fmt.Errorf("event entity id is required")
-	}
+	return r.insertWithExecutor(ctx, execer, event, "INSERT")
+}
 
-	if event.ID == "" {
-		event.ID = uuid.New().String()
-	}
-	if event.Timestamp.IsZero() {
-		event.Timestamp = time.Now().UTC()
-	} else {
-		event.Timestamp = event.Timestamp.UTC()
+func (r *EventRepository) insertWithExecutor(ctx context.Context, execer eventExecer, event *models.Event, insert string) error {
+	if err := prepareEvent(ctx, r.db, event); err != nil {
+		return err
 	}
 
 	var payloadJSON *string
@@ -109,16 +105,16 @@ func (r *EventRepository) createWithExecutor(ctx context.Context, execer eventEx
 		metadataJSON = &s
 	}
 
-	_, err := execer.ExecContext(ctx, `
-		INSERT INTO events (
-			id, timestamp, type, entity_type, entity_id, payload_json, metadata_json
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	_, err := execer.ExecContext(ctx, insert+` INTO events (
+			id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		event.ID,
 		event.Timestamp.Format(time.RFC3339),
 		string(event.Type),
 		string(event.EntityType),
 		event.EntityID,
+		event.Namespace,
 		payloadJSON,
 		metadataJSON,
 	)
@@ -129,12 +125,51 @@ func (r *EventRepository) createWithExecutor(ctx context.Context, execer eventEx
 	return nil
 }
 
+// prepareEvent validates event and fills in its ID, UTC timestamp,
+// namespace, and the trace ID from ctx.
+func prepareEvent(ctx context.Context, db *DB, event *models.Event) error {
+	if event.Type == "" {
+		return fmt.Errorf("event type is required")
+	}
+	if event.EntityType == "" {
+		return fmt.Errorf("event entity type is required")
+	}
+	if event.EntityID == "" {
+		return fmt.Errorf("event entity id is required")
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	namespace, err := db.createNamespace(ctx, event.Namespace)
+	if err != nil {
+		return fmt.Errorf("invalid event: %w", err)
+	}
+	event.Namespace = namespace
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	} else {
+		event.Timestamp = event.Timestamp.UTC()
+	}
+
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+		if event.Metadata == nil {
+			event.Metadata = make(map[string]string, 1)
+		}
+		if _, ok := event.Metadata[TraceIDMetadataKey]; !ok {
+			event.Metadata[TraceIDMetadataKey] = traceID
+		}
+	}
+
+	return nil
+}
+
 // Get retrieves an event by ID.
 func (r *EventRepository) Get(ctx context.Context, id string) (*models.Event, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, timestamp, type, entity_type, entity_id, payload_json, metadata_json
-		FROM events WHERE id = ?
-	`, id)
+		SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
+		FROM events WHERE id = ?`+filter, append([]any{id}, filterArgs...)...)
 
 	return r.scanEvent(row)
 }
@@ -147,8 +182,9 @@ func (r *EventRepository) Query(ctx context.Context, q EventQuery) (*EventPage,
 	}
 
 	// Build query dynamically
-	query := `SELECT id, timestamp, type, entity_type, entity_id, payload_json, metadata_json FROM events WHERE 1=1`
-	args := []any{}
+	query := `SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json FROM events WHERE 1=1`
+	filter, args := r.db.namespaceFilter(ctx, "AND", "namespace")
+	query += filter
 
 	if q.Type != nil {
 		query += ` AND type = ?`
@@ -210,19 +246,102 @@ func (r *EventRepository) Query(ctx context.Context, q EventQuery) (*EventPage,
 	return page, nil
 }
 
+// EventSearchQuery defines a full-text search over events. Text is matched
+// against the event type, entity, and payload; see SearchMatchQuery.
+type EventSearchQuery struct {
+	Text       string
+	Type       *models.EventType
+	EntityType *models.EntityType
+	EntityID   *string
+	Since      *time.Time // Events at or after this time (inclusive)
+	Limit      int        // Max results to return
+}
+
+// EventSearchResult is an event matching a search, with the matching
+// excerpt of its payload.
+type EventSearchResult struct {
+	Event   *models.Event `json:"event"`
+	Snippet string        `json:"snippet"`
+}
+
+// Search returns events matching q.Text, best matches first.
+func (r *EventRepository) Search(ctx context.Context, q EventSearchQuery) ([]EventSearchResult, error) {
+	match, err := SearchMatchQuery(q.Text)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT e.id, e.timestamp, e.type, e.entity_type, e.entity_id, e.namespace, e.payload_json, e.metadata_json,
+			snippet(events_fts, -1, ?, ?, ?, ?)
+		FROM events_fts
+		JOIN events e ON e.rowid = events_fts.rowid
+		WHERE events_fts MATCH ?`
+	args := []any{SnippetOpen, SnippetClose, SnippetEllipsis, snippetTokens, match}
+
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "e.namespace")
+	query += filter
+	args = append(args, filterArgs...)
+
+	if q.Type != nil {
+		query += ` AND e.type = ?`
+		args = append(args, string(*q.Type))
+	}
+	if q.EntityType != nil {
+		query += ` AND e.entity_type = ?`
+		args = append(args, string(*q.EntityType))
+	}
+	if q.EntityID != nil {
+		query += ` AND e.entity_id = ?`
+		args = append(args, *q.EntityID)
+	}
+	if q.Since != nil {
+		query += ` AND e.timestamp >= ?`
+		args = append(args, q.Since.UTC().Format(time.RFC3339))
+	}
+	query += ` ORDER BY events_fts.rank, e.timestamp DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]EventSearchResult, 0)
+	for rows.Next() {
+		var result EventSearchResult
+		event, err := r.scanEventFromRows(withExtraColumns(rows, &result.Snippet))
+		if err != nil {
+			return nil, err
+		}
+		result.Event = event
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event search results: %w", err)
+	}
+	return results, nil
+}
+
 // ListByEntity retrieves events for an entity, ordered by timestamp.
 func (r *EventRepository) ListByEntity(ctx context.Context, entityType models.EntityType, entityID string, limit int) ([]*models.Event, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	if limit <= 0 {
 		limit = 100
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, timestamp, type, entity_type, entity_id, payload_json, metadata_json
+		SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
 		FROM events
-		WHERE entity_type = ? AND entity_id = ?
+		WHERE entity_type = ? AND entity_id = ?`+filter+`
 		ORDER BY timestamp
 		LIMIT ?
-	`, string(entityType), entityID, limit)
+	`, append(append([]any{string(entityType), entityID}, filterArgs...), limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -255,6 +374,7 @@ func (r *EventRepository) scanEvent(row *sql.Row) (*models.Event, error) {
 		&eventType,
 		&entityType,
 		&event.EntityID,
+		&event.Namespace,
 		&payloadJSON,
 		&metadataJSON,
 	)
@@ -284,7 +404,7 @@ func (r *EventRepository) scanEvent(row *sql.Row) (*models.Event, error) {
 	return &event, nil
 }
 
-func (r *EventRepository) scanEventFromRows(rows *sql.Rows) (*models.Event, error) {
+func (r *EventRepository) scanEventFromRows(rows interface{ Scan(...any) error }) (*models.Event, error) {
 	var event models.Event
 	var timestamp, eventType, entityType string
 	var payloadJSON sql.NullString
@@ -296,6 +416,7 @@ func (r *EventRepository) scanEventFromRows(rows *sql.Rows) (*models.Event, erro
 		&eventType,
 		&entityType,
 		&event.EntityID,
+		&event.Namespace,
 		&payloadJSON,
 		&metadataJSON,
 	); err != nil {
@@ -323,8 +444,9 @@ func (r *EventRepository) scanEventFromRows(rows *sql.Rows) (*models.Event, erro
 
 // Count returns the total number of events.
 func (r *EventRepository) Count(ctx context.Context) (int64, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "namespace")
 	var count int64
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events`).Scan(&count)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events`+filter, filterArgs...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count events: %w", err)
 	}
@@ -422,7 +544,7 @@ func (r *EventRepository) ListOlderThan(ctx context.Context, before time.Time, l
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, timestamp, type, entity_type, entity_id, payload_json, metadata_json
+		SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
 		FROM events
 		WHERE timestamp < ?
 		ORDER BY timestamp
@@ -456,7 +578,7 @@ func (r *EventRepository) ListOldest(ctx context.Context, limit int) ([]*models.
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, timestamp, type, entity_type, entity_id, payload_json, metadata_json
+		SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
 		FROM events
 		ORDER BY timestamp
 		LIMIT ?
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -187,3 +189,102 @@ func TestEventRepositoryValidation(t *testing.T) {
 		t.Fatalf("expected ErrInvalidEvent, got %v", err)
 	}
 }
+
+func TestEventRepositoryEnsureIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEventRepository(db)
+	event := &models.Event{
+		Type:       models.EventTypeAgentSpawned,
+		EntityType: models.EntityTypeAgent,
+		EntityID:   "agent-1",
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.Ensure(ctx, event); err != nil {
+			t.Fatalf("ensure #%d: %v", i+1, err)
+		}
+	}
+	if err := repo.Create(ctx, event); err == nil {
+		t.Fatalf("expected duplicate Create to fail")
+	}
+
+	events, err := repo.ListByEntity(ctx, models.EntityTypeAgent, "agent-1", 10)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+}
+
+func TestEventRepositorySearch(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repo := NewEventRepository(database)
+	base := time.Now().UTC().Truncate(time.Second)
+	for i, payload := range []string{
+		`{"reason":"disk quota exceeded on build host"}`,
+		`{"reason":"network unreachable"}`,
+		`{"message":"quota restored"}`,
+	} {
+		event := &models.Event{
+			Type:       models.EventTypeNodeOffline,
+			EntityType: models.EntityTypeNode,
+			EntityID:   fmt.Sprintf("node-%d", i),
+			Timestamp:  base.Add(time.Duration(i) * time.Second),
+			Payload:    json.RawMessage(payload),
+		}
+		if err := repo.Append(ctx, event); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	results, err := repo.Search(ctx, EventSearchQuery{Text: "quota"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !strings.Contains(results[0].Snippet, SnippetOpen+"quota"+SnippetClose) {
+		t.Fatalf("expected highlighted snippet, got %q", results[0].Snippet)
+	}
+
+	entityID := "node-0"
+	results, err = repo.Search(ctx, EventSearchQuery{Text: "quo", EntityID: &entityID})
+	if err != nil {
+		t.Fatalf("Search prefix: %v", err)
+	}
+	if len(results) != 1 || results[0].Event.EntityID != "node-0" {
+		t.Fatalf("expected prefix match on node-0, got %+v", results)
+	}
+
+	if _, err := repo.Search(ctx, EventSearchQuery{Text: `node.offline "unbalanced`}); err != nil {
+		t.Fatalf("expected punctuation to be matched literally, got %v", err)
+	}
+	if _, err := repo.Search(ctx, EventSearchQuery{Text: "  "}); !errors.Is(err, ErrEmptySearch) {
+		t.Fatalf("expected ErrEmptySearch, got %v", err)
+	}
+
+	if _, err := repo.DeleteOlderThan(ctx, base.Add(time.Second), 0); err != nil {
+		t.Fatalf("DeleteOlderThan: %v", err)
+	}
+	results, err = repo.Search(ctx, EventSearchQuery{Text: "quota"})
+	if err != nil {
+		t.Fatalf("Search after delete: %v", err)
+	}
+	if len(results) != 1 || results[0].Event.EntityID != "node-2" {
+		t.Fatalf("expected deleted event to leave the index, got %+v", results)
+	}
+}
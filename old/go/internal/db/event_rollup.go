@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// EventSelector matches events for retention compaction. Types and
+// Prefixes are ORed together; an empty include list matches every type.
+// Events whose type is in ExcludeTypes or starts with an ExcludePrefixes
+// entry are never matched.
+type EventSelector struct {
+	Before          time.Time
+	Types           []string
+	Prefixes        []string
+	ExcludeTypes    []string
+	ExcludePrefixes []string
+}
+
+// EventRollup summarizes events removed by compaction for one day, event
+// type, and entity type.
+type EventRollup struct {
+	Day        string            `json:"day"`
+	Type       models.EventType  `json:"type"`
+	EntityType models.EntityType `json:"entity_type"`
+	Count      int64             `json:"count"`
+	FirstAt    time.Time         `json:"first_at"`
+	LastAt     time.Time         `json:"last_at"`
+}
+
+func (s EventSelector) where() (string, []any) {
+	clauses := []string{"timestamp < ?"}
+	args := []any{s.Before.UTC().Format(time.RFC3339)}
+
+	var include []string
+	for _, t := range s.Types {
+		include = append(include, "type = ?")
+		args = append(args, t)
+	}
+	for _, p := range s.Prefixes {
+		include = append(include, "substr(type, 1, ?) = ?")
+		args = append(args, len(p), p)
+	}
+	if len(include) > 0 {
+		clauses = append(clauses, "("+strings.Join(include, " OR ")+")")
+	}
+	for _, t := range s.ExcludeTypes {
+		clauses = append(clauses, "type != ?")
+		args = append(args, t)
+	}
+	for _, p := range s.ExcludePrefixes {
+		clauses = append(clauses, "substr(type, 1, ?) != ?")
+		args = append(args, len(p), p)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// CountForCompaction returns the number of events matching sel.
+func (r *EventRepository) CountForCompaction(ctx context.Context, sel EventSelector) (int64, error) {
+	where, args := sel.where()
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count events for compaction: %w", err)
+	}
+	return count, nil
+}
+
+// ListForCompaction returns up to limit of the oldest events matching sel.
+func (r *EventRepository) ListForCompaction(ctx context.Context, sel EventSelector, limit int) ([]*models.Event, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	where, args := sel.where()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, timestamp, type, entity_type, entity_id, namespace, payload_json, metadata_json
+		FROM events
+		WHERE `+where+`
+		ORDER BY timestamp
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events for compaction: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.Event
+	for rows.Next() {
+		event, err := r.scanEventFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events for compaction: %w", err)
+	}
+	return events, nil
+}
+
+// Compact deletes the given events in one transaction. When rollup is set,
+// their counts are first folded into event_rollups.
+func (r *EventRepository) Compact(ctx context.Context, ids []string, rollup bool) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	var deleted int64
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		if rollup {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO event_rollups (day, type, entity_type, count, first_at, last_at)
+				SELECT substr(timestamp, 1, 10), type, entity_type, COUNT(*), MIN(timestamp), MAX(timestamp)
+				FROM events
+				WHERE id IN (`+placeholders+`)
+				GROUP BY substr(timestamp, 1, 10), type, entity_type
+				ON CONFLICT(day, type, entity_type) DO UPDATE SET
+					count = count + excluded.count,
+					first_at = MIN(first_at, excluded.first_at),
+					last_at = MAX(last_at, excluded.last_at)
+			`, args...)
+			if err != nil {
+				return fmt.Errorf("failed to roll up events: %w", err)
+			}
+		}
+		result, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id IN (`+placeholders+`)`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete compacted events: %w", err)
+		}
+		deleted, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get deleted count: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// ListRollups returns rollup summaries, newest day first. An empty
+// eventType returns every type.
+func (r *EventRepository) ListRollups(ctx context.Context, eventType string, since *time.Time, limit int) ([]EventRollup, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `SELECT day, type, entity_type, count, first_at, last_at FROM event_rollups WHERE 1=1`
+	args := []any{}
+	if eventType != "" {
+		query += ` AND type = ?`
+		args = append(args, eventType)
+	}
+	if since != nil {
+		query += ` AND day >= ?`
+		args = append(args, since.UTC().Format("2006-01-02"))
+	}
+	query += ` ORDER BY day DESC, type, entity_type LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event rollups: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []EventRollup
+	for rows.Next() {
+		var (
+			rollup          EventRollup
+			firstAt, lastAt string
+		)
+		if err := rows.Scan(&rollup.Day, &rollup.Type, &rollup.EntityType, &rollup.Count, &firstAt, &lastAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event rollup: %w", err)
+		}
+		if rollup.FirstAt, err = time.Parse(time.RFC3339, firstAt); err != nil {
+			return nil, fmt.Errorf("failed to parse rollup first_at: %w", err)
+		}
+		if rollup.LastAt, err = time.Parse(time.RFC3339, lastAt); err != nil {
+			return nil, fmt.Errorf("failed to parse rollup last_at: %w", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rollups: %w", err)
+	}
+	return rollups, nil
+}
@@ -52,8 +52,8 @@ func (r *LoopQueueRepository) Enqueue(ctx context.Context, loopID string, items
 		_, err := r.db.ExecContext(ctx, `
 			INSERT INTO loop_queue_items (
 				id, loop_id, type, position, status, attempts, payload_json,
-				error_message, created_at, dispatched_at, completed_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				error_message, created_at, dispatched_at, completed_at, not_before
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`,
 			item.ID,
 			item.LoopID,
@@ -66,6 +66,7 @@ func (r *LoopQueueRepository) Enqueue(ctx context.Context, loopID string, items
 			item.CreatedAt.Format(time.RFC3339),
 			stringTimePtr(item.DispatchedAt),
 			stringTimePtr(item.CompletedAt),
+			stringTimePtr(item.NotBefore),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert loop queue item: %w", err)
@@ -75,16 +76,19 @@ func (r *LoopQueueRepository) Enqueue(ctx context.Context, loopID string, items
 	return nil
 }
 
-// Peek returns the next pending item without removing it.
+// Peek returns the next pending item that is due without removing it.
+// Items scheduled for the future are skipped until their time arrives.
 func (r *LoopQueueRepository) Peek(ctx context.Context, loopID string) (*models.LoopQueueItem, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, loop_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at, not_before
 		FROM loop_queue_items
 		WHERE loop_id = ? AND status = ?
+			AND (not_before IS NULL OR not_before <= ?)
 		ORDER BY position ASC
 		LIMIT 1
-	`, loopID, string(models.LoopQueueStatusPending))
+	`, loopID, string(models.LoopQueueStatusPending), now)
 
 	item, err := r.scanLoopQueueItem(row)
 	if err != nil {
@@ -123,7 +127,7 @@ func (r *LoopQueueRepository) Dequeue(ctx context.Context, loopID string) (*mode
 func (r *LoopQueueRepository) List(ctx context.Context, loopID string) ([]*models.LoopQueueItem, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, loop_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at, not_before
 		FROM loop_queue_items
 		WHERE loop_id = ?
 		ORDER BY position ASC
@@ -235,6 +239,7 @@ func (r *LoopQueueRepository) scanLoopQueueItem(scanner interface{ Scan(...any)
 		createdAt    string
 		dispatchedAt sql.NullString
 		completedAt  sql.NullString
+		notBefore    sql.NullString
 	)
 
 	if err := scanner.Scan(
@@ -249,6 +254,7 @@ func (r *LoopQueueRepository) scanLoopQueueItem(scanner interface{ Scan(...any)
 		&createdAt,
 		&dispatchedAt,
 		&completedAt,
+		&notBefore,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrQueueItemNotFound
@@ -280,6 +286,11 @@ func (r *LoopQueueRepository) scanLoopQueueItem(scanner interface{ Scan(...any)
 			item.CompletedAt = &t
 		}
 	}
+	if notBefore.Valid && notBefore.String != "" {
+		if t, err := time.Parse(time.RFC3339, notBefore.String); err == nil {
+			item.NotBefore = &t
+		}
+	}
 
 	return item, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tOgg1/forge/internal/models"
@@ -79,3 +80,47 @@ func TestLoopQueueRepository_EnqueuePeekDequeue(t *testing.T) {
 		t.Fatalf("expected 2 items, got %d", len(items))
 	}
 }
+
+func TestLoopQueueRepository_PeekSkipsScheduledItems(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	loop := createTestLoop(t, db)
+	repo := NewLoopQueueRepository(db)
+	ctx := context.Background()
+
+	later := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	scheduled := newLoopMessageItem(t, "later")
+	scheduled.NotBefore = &later
+	due := newLoopMessageItem(t, "now")
+
+	if err := repo.Enqueue(ctx, loop.ID, scheduled, due); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	peeked, err := repo.Peek(ctx, loop.ID)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if peeked.ID != due.ID {
+		t.Fatalf("expected due item %s, got %s", due.ID, peeked.ID)
+	}
+
+	items, err := repo.List(ctx, loop.ID)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if items[0].NotBefore == nil || !items[0].NotBefore.Equal(later) {
+		t.Fatalf("expected not_before %s, got %v", later, items[0].NotBefore)
+	}
+	if items[1].NotBefore != nil {
+		t.Fatalf("expected no not_before, got %v", items[1].NotBefore)
+	}
+
+	if _, err := repo.Dequeue(ctx, loop.ID); err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if _, err := repo.Peek(ctx, loop.ID); err != ErrQueueEmpty {
+		t.Fatalf("expected ErrQueueEmpty while scheduled item is not due, got %v", err)
+	}
+}
@@ -47,6 +47,11 @@ func (r *LoopRepository) Create(ctx context.Context, loop *models.Loop) error {
 	if loop.State == "" {
 		loop.State = models.DefaultLoopState()
 	}
+	namespace, err := r.db.createNamespace(ctx, loop.Namespace)
+	if err != nil {
+		return fmt.Errorf("invalid loop: %w", err)
+	}
+	loop.Namespace = namespace
 
 	now := time.Now().UTC()
 	loop.CreatedAt = now
@@ -74,18 +79,19 @@ func (r *LoopRepository) Create(ctx context.Context, loop *models.Loop) error {
 
 	lastRunAt := stringTimePtr(loop.LastRunAt)
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err = r.db.ExecContext(ctx, `
 		INSERT INTO loops (
-			id, short_id, name, repo_path, base_prompt_path, base_prompt_msg,
+			id, short_id, name, namespace, repo_path, base_prompt_path, base_prompt_msg,
 			interval_seconds, max_iterations, max_runtime_seconds, pool_id, profile_id, state,
 			last_run_at, last_exit_code, last_error,
 			log_path, ledger_path, tags_json, metadata_json,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		loop.ID,
 		loop.ShortID,
 		loop.Name,
+		loop.Namespace,
 		loop.RepoPath,
 		nullableString(loop.BasePromptPath),
 		nullableString(loop.BasePromptMsg),
@@ -117,61 +123,62 @@ func (r *LoopRepository) Create(ctx context.Context, loop *models.Loop) error {
 
 // Get retrieves a loop by ID.
 func (r *LoopRepository) Get(ctx context.Context, id string) (*models.Loop, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
-			id, short_id, name, repo_path, base_prompt_path, base_prompt_msg,
+			id, short_id, name, namespace, repo_path, base_prompt_path, base_prompt_msg,
 			interval_seconds, max_iterations, max_runtime_seconds, pool_id, profile_id, state,
 			last_run_at, last_exit_code, last_error,
 			log_path, ledger_path, tags_json, metadata_json,
 			created_at, updated_at
-		FROM loops WHERE id = ?
-	`, id)
+		FROM loops WHERE id = ?`+filter, append([]any{id}, filterArgs...)...)
 
 	return r.scanLoop(row)
 }
 
 // GetByName retrieves a loop by name.
 func (r *LoopRepository) GetByName(ctx context.Context, name string) (*models.Loop, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
-			id, short_id, name, repo_path, base_prompt_path, base_prompt_msg,
+			id, short_id, name, namespace, repo_path, base_prompt_path, base_prompt_msg,
 			interval_seconds, max_iterations, max_runtime_seconds, pool_id, profile_id, state,
 			last_run_at, last_exit_code, last_error,
 			log_path, ledger_path, tags_json, metadata_json,
 			created_at, updated_at
-		FROM loops WHERE name = ?
-	`, name)
+		FROM loops WHERE name = ?`+filter, append([]any{name}, filterArgs...)...)
 
 	return r.scanLoop(row)
 }
 
 // GetByShortID retrieves a loop by short ID.
 func (r *LoopRepository) GetByShortID(ctx context.Context, shortID string) (*models.Loop, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
-			id, short_id, name, repo_path, base_prompt_path, base_prompt_msg,
+			id, short_id, name, namespace, repo_path, base_prompt_path, base_prompt_msg,
 			interval_seconds, max_iterations, max_runtime_seconds, pool_id, profile_id, state,
 			last_run_at, last_exit_code, last_error,
 			log_path, ledger_path, tags_json, metadata_json,
 			created_at, updated_at
-		FROM loops WHERE short_id = ?
-	`, shortID)
+		FROM loops WHERE short_id = ?`+filter, append([]any{shortID}, filterArgs...)...)
 
 	return r.scanLoop(row)
 }
 
 // List retrieves all loops.
 func (r *LoopRepository) List(ctx context.Context) ([]*models.Loop, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
-			id, short_id, name, repo_path, base_prompt_path, base_prompt_msg,
+			id, short_id, name, namespace, repo_path, base_prompt_path, base_prompt_msg,
 			interval_seconds, max_iterations, max_runtime_seconds, pool_id, profile_id, state,
 			last_run_at, last_exit_code, last_error,
 			log_path, ledger_path, tags_json, metadata_json,
 			created_at, updated_at
-		FROM loops
+		FROM loops`+filter+`
 		ORDER BY created_at
-	`)
+	`, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query loops: %w", err)
 	}
@@ -191,6 +198,7 @@ func (r *LoopRepository) List(ctx context.Context) ([]*models.Loop, error) {
 
 // Update updates a loop.
 func (r *LoopRepository) Update(ctx context.Context, loop *models.Loop) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	if err := r.ensureLoopShortID(ctx, loop); err != nil {
 		return err
 	}
@@ -229,8 +237,7 @@ func (r *LoopRepository) Update(ctx context.Context, loop *models.Loop) error {
 			last_run_at = ?, last_exit_code = ?, last_error = ?,
 			log_path = ?, ledger_path = ?, tags_json = ?, metadata_json = ?,
 			updated_at = ?
-		WHERE id = ?
-	`,
+		WHERE id = ?`+filter, append([]any{
 		loop.ShortID,
 		loop.Name,
 		loop.RepoPath,
@@ -251,7 +258,7 @@ func (r *LoopRepository) Update(ctx context.Context, loop *models.Loop) error {
 		metadataJSON,
 		loop.UpdatedAt.Format(time.RFC3339),
 		loop.ID,
-	)
+	}, filterArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update loop: %w", err)
 	}
@@ -265,7 +272,8 @@ func (r *LoopRepository) Update(ctx context.Context, loop *models.Loop) error {
 
 // Delete removes a loop.
 func (r *LoopRepository) Delete(ctx context.Context, id string) error {
-	result, err := r.db.ExecContext(ctx, `DELETE FROM loops WHERE id = ?`, id)
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
+	result, err := r.db.ExecContext(ctx, `DELETE FROM loops WHERE id = ?`+filter, append([]any{id}, filterArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete loop: %w", err)
 	}
@@ -281,6 +289,7 @@ func (r *LoopRepository) scanLoop(scanner interface{ Scan(...any) error }) (*mod
 		id              string
 		shortID         sql.NullString
 		name            string
+		namespace       string
 		repoPath        string
 		basePromptPath  sql.NullString
 		basePromptMsg   sql.NullString
@@ -305,6 +314,7 @@ func (r *LoopRepository) scanLoop(scanner interface{ Scan(...any) error }) (*mod
 		&id,
 		&shortID,
 		&name,
+		&namespace,
 		&repoPath,
 		&basePromptPath,
 		&basePromptMsg,
@@ -334,6 +344,7 @@ func (r *LoopRepository) scanLoop(scanner interface{ Scan(...any) error }) (*mod
 		ID:                id,
 		ShortID:           shortID.String,
 		Name:              name,
+		Namespace:         namespace,
 		RepoPath:          repoPath,
 		BasePromptPath:    basePromptPath.String,
 		BasePromptMsg:     basePromptMsg.String,
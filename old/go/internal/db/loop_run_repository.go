@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 // LoopRun repository errors.
@@ -40,6 +41,15 @@ func (r *LoopRunRepository) Create(ctx context.Context, run *models.LoopRun) err
 		run.StartedAt = time.Now().UTC()
 	}
 
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+		if run.Metadata == nil {
+			run.Metadata = make(map[string]any, 1)
+		}
+		if _, ok := run.Metadata[TraceIDMetadataKey]; !ok {
+			run.Metadata[TraceIDMetadataKey] = traceID
+		}
+	}
+
 	var metadataJSON *string
 	if run.Metadata != nil {
 		data, err := json.Marshal(run.Metadata)
@@ -50,17 +60,24 @@ func (r *LoopRunRepository) Create(ctx context.Context, run *models.LoopRun) err
 		metadataJSON = &value
 	}
 
+	artifactsJSON, err := marshalRunArtifacts(run.Artifacts)
+	if err != nil {
+		return err
+	}
+
 	promptOverride := 0
 	if run.PromptOverride {
 		promptOverride = 1
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err = r.db.ExecContext(ctx, `
 		INSERT INTO loop_runs (
 			id, loop_id, profile_id, status,
 			prompt_source, prompt_path, prompt_override,
-			started_at, finished_at, exit_code, output_tail, metadata_json
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			started_at, finished_at, exit_code, output_tail, metadata_json,
+			input_tokens, output_tokens, total_tokens, cost_usd,
+			artifact_dir, artifacts_json
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		run.ID,
 		run.LoopID,
@@ -74,6 +91,12 @@ func (r *LoopRunRepository) Create(ctx context.Context, run *models.LoopRun) err
 		run.ExitCode,
 		nullableString(run.OutputTail),
 		metadataJSON,
+		run.InputTokens,
+		run.OutputTokens,
+		run.TotalTokens,
+		run.CostUSD,
+		nullableString(run.ArtifactDir),
+		artifactsJSON,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert loop run: %w", err)
@@ -87,7 +110,9 @@ func (r *LoopRunRepository) Get(ctx context.Context, id string) (*models.LoopRun
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, loop_id, profile_id, status,
 			prompt_source, prompt_path, prompt_override,
-			started_at, finished_at, exit_code, output_tail, metadata_json
+			started_at, finished_at, exit_code, output_tail, metadata_json,
+			input_tokens, output_tokens, total_tokens, cost_usd,
+			artifact_dir, artifacts_json
 		FROM loop_runs WHERE id = ?
 	`, id)
 
@@ -99,7 +124,9 @@ func (r *LoopRunRepository) ListByLoop(ctx context.Context, loopID string) ([]*m
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, loop_id, profile_id, status,
 			prompt_source, prompt_path, prompt_override,
-			started_at, finished_at, exit_code, output_tail, metadata_json
+			started_at, finished_at, exit_code, output_tail, metadata_json,
+			input_tokens, output_tokens, total_tokens, cost_usd,
+			artifact_dir, artifacts_json
 		FROM loop_runs
 		WHERE loop_id = ?
 		ORDER BY started_at DESC
@@ -121,6 +148,81 @@ func (r *LoopRunRepository) ListByLoop(ctx context.Context, loopID string) ([]*m
 	return runs, nil
 }
 
+// LoopRunSearchQuery defines a full-text search over run output.
+type LoopRunSearchQuery struct {
+	Text   string
+	LoopID string     // Only runs of this loop; empty searches every loop
+	Since  *time.Time // Runs started at or after this time (inclusive)
+	Limit  int        // Max results to return
+}
+
+// LoopRunSearchResult is a run whose output matches a search, with the
+// matching excerpt of the output.
+type LoopRunSearchResult struct {
+	Run     *models.LoopRun `json:"run"`
+	Snippet string          `json:"snippet"`
+}
+
+// SearchOutput returns runs whose output tail matches q.Text, best matches
+// first.
+func (r *LoopRunRepository) SearchOutput(ctx context.Context, q LoopRunSearchQuery) ([]LoopRunSearchResult, error) {
+	match, err := SearchMatchQuery(q.Text)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT r.id, r.loop_id, r.profile_id, r.status,
+			r.prompt_source, r.prompt_path, r.prompt_override,
+			r.started_at, r.finished_at, r.exit_code, r.output_tail, r.metadata_json,
+			r.input_tokens, r.output_tokens, r.total_tokens, r.cost_usd,
+			r.artifact_dir, r.artifacts_json,
+			snippet(loop_runs_fts, 0, ?, ?, ?, ?)
+		FROM loop_runs_fts
+		JOIN loop_runs r ON r.rowid = loop_runs_fts.rowid
+		JOIN loops l ON l.id = r.loop_id
+		WHERE loop_runs_fts MATCH ?`
+	args := []any{SnippetOpen, SnippetClose, SnippetEllipsis, snippetTokens, match}
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "l.namespace")
+	query += filter
+	args = append(args, filterArgs...)
+	if q.LoopID != "" {
+		query += ` AND r.loop_id = ?`
+		args = append(args, q.LoopID)
+	}
+	if q.Since != nil {
+		query += ` AND r.started_at >= ?`
+		args = append(args, q.Since.UTC().Format(time.RFC3339))
+	}
+	query += ` ORDER BY loop_runs_fts.rank, r.started_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search loop runs: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]LoopRunSearchResult, 0)
+	for rows.Next() {
+		var result LoopRunSearchResult
+		run, err := r.scanLoopRun(withExtraColumns(rows, &result.Snippet))
+		if err != nil {
+			return nil, err
+		}
+		result.Run = run
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loop run search results: %w", err)
+	}
+	return results, nil
+}
+
 // CountRunningByProfile returns the number of running loop runs for a profile.
 func (r *LoopRunRepository) CountRunningByProfile(ctx context.Context, profileID string) (int, error) {
 	row := r.db.QueryRowContext(ctx, `
@@ -135,6 +237,55 @@ func (r *LoopRunRepository) CountRunningByProfile(ctx context.Context, profileID
 	return count, nil
 }
 
+// ProfileStartStats returns how many runs started on a profile since the
+// given time and when the most recent run on the profile started.
+func (r *LoopRunRepository) ProfileStartStats(ctx context.Context, profileID string, since time.Time) (int, *time.Time, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(CASE WHEN started_at >= ? THEN 1 ELSE 0 END), 0),
+			MAX(started_at)
+		FROM loop_runs
+		WHERE profile_id = ?
+	`, since.UTC().Format(time.RFC3339), profileID)
+
+	var (
+		count     int
+		lastStart sql.NullString
+	)
+	if err := row.Scan(&count, &lastStart); err != nil {
+		return 0, nil, fmt.Errorf("failed to query profile run starts: %w", err)
+	}
+	if !lastStart.Valid || lastStart.String == "" {
+		return count, nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, lastStart.String)
+	if err != nil {
+		return count, nil, nil
+	}
+	return count, &t, nil
+}
+
+// OldestStartSince returns the earliest run start on a profile at or after since.
+func (r *LoopRunRepository) OldestStartSince(ctx context.Context, profileID string, since time.Time) (*time.Time, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT MIN(started_at) FROM loop_runs
+		WHERE profile_id = ? AND started_at >= ?
+	`, profileID, since.UTC().Format(time.RFC3339))
+
+	var oldest sql.NullString
+	if err := row.Scan(&oldest); err != nil {
+		return nil, fmt.Errorf("failed to query profile run starts: %w", err)
+	}
+	if !oldest.Valid || oldest.String == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, oldest.String)
+	if err != nil {
+		return nil, nil
+	}
+	return &t, nil
+}
+
 // CountByLoop returns the number of runs for a loop.
 func (r *LoopRunRepository) CountByLoop(ctx context.Context, loopID string) (int, error) {
 	row := r.db.QueryRowContext(ctx, `
@@ -149,19 +300,101 @@ func (r *LoopRunRepository) CountByLoop(ctx context.Context, loopID string) (int
 	return count, nil
 }
 
+// UsageByLoop aggregates token usage and cost per loop for runs started at
+// or after since. A zero since covers all runs.
+func (r *LoopRunRepository) UsageByLoop(ctx context.Context, since time.Time) ([]models.LoopRunUsageSummary, error) {
+	return r.usageBy(ctx, "loop_id", since)
+}
+
+// UsageByProfile aggregates token usage and cost per profile for runs
+// started at or after since. Runs without a profile are grouped under "".
+func (r *LoopRunRepository) UsageByProfile(ctx context.Context, since time.Time) ([]models.LoopRunUsageSummary, error) {
+	return r.usageBy(ctx, "profile_id", since)
+}
+
+func (r *LoopRunRepository) usageBy(ctx context.Context, column string, since time.Time) ([]models.LoopRunUsageSummary, error) {
+	sinceValue := ""
+	if !since.IsZero() {
+		sinceValue = since.UTC().Format(time.RFC3339)
+	}
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "l.namespace")
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(r.`+column+`, ''),
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN r.input_tokens > 0 OR r.output_tokens > 0 OR r.total_tokens > 0 OR r.cost_usd > 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(r.input_tokens), 0),
+			COALESCE(SUM(r.output_tokens), 0),
+			COALESCE(SUM(r.total_tokens), 0),
+			COALESCE(SUM(r.cost_usd), 0)
+		FROM loop_runs r
+		JOIN loops l ON l.id = r.loop_id
+		WHERE r.started_at >= ?`+filter+`
+		GROUP BY 1
+		ORDER BY 7 DESC, 6 DESC, 1
+	`, append([]any{sinceValue}, filterArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loop run usage: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]models.LoopRunUsageSummary, 0)
+	for rows.Next() {
+		var summary models.LoopRunUsageSummary
+		if err := rows.Scan(
+			&summary.Key,
+			&summary.Runs,
+			&summary.RunsWithUsage,
+			&summary.InputTokens,
+			&summary.OutputTokens,
+			&summary.TotalTokens,
+			&summary.CostUSD,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan loop run usage: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
 // Finish updates a loop run with completion details.
 func (r *LoopRunRepository) Finish(ctx context.Context, run *models.LoopRun) error {
 	finishedAt := time.Now().UTC()
 	run.FinishedAt = &finishedAt
+
+	var metadataJSON *string
+	if run.Metadata != nil {
+		data, err := json.Marshal(run.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal run metadata: %w", err)
+		}
+		value := string(data)
+		metadataJSON = &value
+	}
+
+	artifactsJSON, err := marshalRunArtifacts(run.Artifacts)
+	if err != nil {
+		return err
+	}
+
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE loop_runs
-		SET status = ?, finished_at = ?, exit_code = ?, output_tail = ?
+		SET status = ?, finished_at = ?, exit_code = ?, output_tail = ?,
+			metadata_json = COALESCE(?, metadata_json),
+			input_tokens = ?, output_tokens = ?, total_tokens = ?, cost_usd = ?,
+			artifact_dir = ?, artifacts_json = ?
 		WHERE id = ?
 	`,
 		string(run.Status),
 		stringTimePtr(run.FinishedAt),
 		run.ExitCode,
 		nullableString(run.OutputTail),
+		metadataJSON,
+		run.InputTokens,
+		run.OutputTokens,
+		run.TotalTokens,
+		run.CostUSD,
+		nullableString(run.ArtifactDir),
+		artifactsJSON,
 		run.ID,
 	)
 	if err != nil {
@@ -189,6 +422,12 @@ func (r *LoopRunRepository) scanLoopRun(scanner interface{ Scan(...any) error })
 		exitCode       sql.NullInt64
 		outputTail     sql.NullString
 		metadataJSON   sql.NullString
+		inputTokens    int64
+		outputTokens   int64
+		totalTokens    int64
+		costUSD        float64
+		artifactDir    sql.NullString
+		artifactsJSON  sql.NullString
 	)
 
 	if err := scanner.Scan(
@@ -204,6 +443,12 @@ func (r *LoopRunRepository) scanLoopRun(scanner interface{ Scan(...any) error })
 		&exitCode,
 		&outputTail,
 		&metadataJSON,
+		&inputTokens,
+		&outputTokens,
+		&totalTokens,
+		&costUSD,
+		&artifactDir,
+		&artifactsJSON,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrLoopRunNotFound
@@ -220,6 +465,11 @@ func (r *LoopRunRepository) scanLoopRun(scanner interface{ Scan(...any) error })
 		PromptPath:     promptPath.String,
 		PromptOverride: promptOverride == 1,
 		OutputTail:     outputTail.String,
+		InputTokens:    inputTokens,
+		OutputTokens:   outputTokens,
+		TotalTokens:    totalTokens,
+		CostUSD:        costUSD,
+		ArtifactDir:    artifactDir.String,
 	}
 
 	if t, err := time.Parse(time.RFC3339, startedAt); err == nil {
@@ -237,6 +487,21 @@ func (r *LoopRunRepository) scanLoopRun(scanner interface{ Scan(...any) error })
 	if metadataJSON.Valid && metadataJSON.String != "" {
 		_ = json.Unmarshal([]byte(metadataJSON.String), &run.Metadata)
 	}
+	if artifactsJSON.Valid && artifactsJSON.String != "" {
+		_ = json.Unmarshal([]byte(artifactsJSON.String), &run.Artifacts)
+	}
 
 	return run, nil
 }
+
+func marshalRunArtifacts(artifacts []models.LoopRunArtifact) (*string, error) {
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal run artifacts: %w", err)
+	}
+	value := string(data)
+	return &value, nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/tOgg1/forge/internal/models"
 )
@@ -58,6 +59,46 @@ func TestLoopRunRepository_CreateFinish(t *testing.T) {
 	}
 }
 
+func TestLoopRunRepository_Artifacts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	loop := createTestLoop(t, db)
+	ctx := context.Background()
+	repo := NewLoopRunRepository(db)
+
+	run := &models.LoopRun{LoopID: loop.ID}
+	if err := repo.Create(ctx, run); err != nil {
+		t.Fatalf("Create run failed: %v", err)
+	}
+	stored, err := repo.Get(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stored.ArtifactDir != "" || stored.Artifacts != nil {
+		t.Fatalf("expected no artifacts, got %q %v", stored.ArtifactDir, stored.Artifacts)
+	}
+
+	run.Status = models.LoopRunStatusSuccess
+	run.ArtifactDir = "/data/artifacts/loops/demo/" + run.ID
+	run.Artifacts = []models.LoopRunArtifact{{Path: "dist/app", Size: 42}, {Path: "report.xml", Size: 7}}
+	if err := repo.Finish(ctx, run); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	runs, err := repo.ListByLoop(ctx, loop.ID)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("ListByLoop failed: %v (%d runs)", err, len(runs))
+	}
+	stored = runs[0]
+	if stored.ArtifactDir != run.ArtifactDir {
+		t.Fatalf("artifact dir = %q, want %q", stored.ArtifactDir, run.ArtifactDir)
+	}
+	if len(stored.Artifacts) != 2 || stored.Artifacts[0] != run.Artifacts[0] || stored.Artifacts[1] != run.Artifacts[1] {
+		t.Fatalf("unexpected artifacts %v", stored.Artifacts)
+	}
+}
+
 func TestLoopRunRepository_CountByLoop(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -112,3 +153,110 @@ func TestLoopRunRepository_CountByLoop(t *testing.T) {
 		t.Fatalf("expected 1 run, got %d", countB)
 	}
 }
+
+func TestLoopRunRepository_UsageAggregates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	loop := createTestLoop(t, db)
+	ctx := context.Background()
+	repo := NewLoopRunRepository(db)
+
+	runs := []*models.LoopRun{
+		{LoopID: loop.ID, InputTokens: 1000, OutputTokens: 200, TotalTokens: 1200, CostUSD: 0.25},
+		{LoopID: loop.ID, InputTokens: 500, OutputTokens: 100, TotalTokens: 600, CostUSD: 0.5},
+		{LoopID: loop.ID},
+	}
+	for _, run := range runs {
+		if err := repo.Create(ctx, run); err != nil {
+			t.Fatalf("Create run failed: %v", err)
+		}
+		run.Status = models.LoopRunStatusSuccess
+		if err := repo.Finish(ctx, run); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+	}
+
+	stored, err := repo.Get(ctx, runs[0].ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stored.InputTokens != 1000 || stored.OutputTokens != 200 || stored.TotalTokens != 1200 || stored.CostUSD != 0.25 {
+		t.Fatalf("unexpected stored usage: %+v", stored)
+	}
+
+	byLoop, err := repo.UsageByLoop(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("UsageByLoop failed: %v", err)
+	}
+	if len(byLoop) != 1 {
+		t.Fatalf("expected one loop summary, got %+v", byLoop)
+	}
+	got := byLoop[0]
+	if got.Key != loop.ID || got.Runs != 3 || got.RunsWithUsage != 2 || got.TotalTokens != 1800 || got.CostUSD != 0.75 {
+		t.Fatalf("unexpected loop summary: %+v", got)
+	}
+
+	byProfile, err := repo.UsageByProfile(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("UsageByProfile failed: %v", err)
+	}
+	if len(byProfile) != 0 {
+		t.Fatalf("expected no runs after since, got %+v", byProfile)
+	}
+}
+
+func TestLoopRunRepository_SearchOutput(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	loop := createTestLoop(t, db)
+	ctx := context.Background()
+	repo := NewLoopRunRepository(db)
+
+	outputs := []string{"panic: nil map write in scheduler", "all tests passed"}
+	runs := make([]*models.LoopRun, len(outputs))
+	for i, output := range outputs {
+		run := &models.LoopRun{LoopID: loop.ID, Status: models.LoopRunStatusRunning}
+		if err := repo.Create(ctx, run); err != nil {
+			t.Fatalf("Create run failed: %v", err)
+		}
+		run.Status = models.LoopRunStatusSuccess
+		run.OutputTail = output
+		if err := repo.Finish(ctx, run); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+		runs[i] = run
+	}
+
+	results, err := repo.SearchOutput(ctx, LoopRunSearchQuery{Text: "nil map"})
+	if err != nil {
+		t.Fatalf("SearchOutput failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Run.ID != runs[0].ID {
+		t.Fatalf("expected the panicking run, got %+v", results)
+	}
+	if results[0].Snippet == "" {
+		t.Fatalf("expected snippet")
+	}
+
+	runs[0].OutputTail = "retried cleanly"
+	if err := repo.Finish(ctx, runs[0]); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+	results, err = repo.SearchOutput(ctx, LoopRunSearchQuery{Text: "panic"})
+	if err != nil {
+		t.Fatalf("SearchOutput failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected updated output to replace the index entry, got %d results", len(results))
+	}
+
+	results, err = repo.SearchOutput(ctx, LoopRunSearchQuery{Text: "passed", LoopID: "other-loop"})
+	if err != nil {
+		t.Fatalf("SearchOutput failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected loop filter to exclude runs, got %d", len(results))
+	}
+}
@@ -4,6 +4,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io/fs"
 	"regexp"
@@ -28,6 +29,10 @@ type MigrationStatus struct {
 	AppliedAt   string
 }
 
+// ErrSchemaAhead is returned when the database was migrated by a newer binary
+// than this one.
+var ErrSchemaAhead = errors.New("database schema is newer than this binary")
+
 // migrationFilePattern matches migration filenames like "001_initial_schema.up.sql"
 var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
 
@@ -46,6 +51,7 @@ func loadMigrations() ([]Migration, error) {
 		downFile    string
 	}
 	filesByVersion := make(map[int]*migrationFiles)
+	var problems []string
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -67,11 +73,15 @@ func loadMigrations() ([]Migration, error) {
 				description: description,
 			}
 		}
+		files := filesByVersion[version]
+		if files.description != description {
+			problems = append(problems, fmt.Sprintf("version %d has conflicting names %q and %q", version, files.description, description))
+		}
 
 		if direction == "up" {
-			filesByVersion[version].upFile = entry.Name()
+			files.upFile = entry.Name()
 		} else {
-			filesByVersion[version].downFile = entry.Name()
+			files.downFile = entry.Name()
 		}
 	}
 
@@ -106,11 +116,60 @@ func loadMigrations() ([]Migration, error) {
 		return migrations[i].Version < migrations[j].Version
 	})
 
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid migrations: %s", strings.Join(problems, "; "))
+	}
+	if err := validateMigrations(migrations); err != nil {
+		return nil, err
+	}
+
 	return migrations, nil
 }
 
+// validateMigrations checks that versions are positive and ascending and that
+// every version can be applied and rolled back. Retired version numbers may
+// be skipped.
+func validateMigrations(migrations []Migration) error {
+	var problems []string
+	for i, m := range migrations {
+		if m.Version <= 0 {
+			problems = append(problems, fmt.Sprintf("version %d must be positive", m.Version))
+		}
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			problems = append(problems, fmt.Sprintf("version %d is out of order", m.Version))
+		}
+		if strings.TrimSpace(m.UpSQL) == "" {
+			problems = append(problems, fmt.Sprintf("version %d has no up SQL", m.Version))
+		}
+		if strings.TrimSpace(m.DownSQL) == "" {
+			problems = append(problems, fmt.Sprintf("version %d has no down SQL", m.Version))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid migrations: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Migrations returns the embedded migrations in version order.
+func Migrations() ([]Migration, error) {
+	return loadMigrations()
+}
+
+// LatestSchemaVersion returns the highest migration version this binary knows.
+func LatestSchemaVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	return latestVersion(migrations), nil
+}
+
 // MigrateUp applies all pending migrations.
 func (db *DB) MigrateUp(ctx context.Context) (int, error) {
+	if db.readOnly {
+		return 0, ErrReadOnly
+	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -127,17 +186,23 @@ func (db *DB) MigrateUp(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	if latest := latestVersion(migrations); currentVersion > latest {
+		return 0, fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaAhead, currentVersion, latest)
+	}
 
+	appliedVersions, err := db.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Apply every missing version, not just those above the current maximum,
+	// so a database with a hole in its history is repaired in order.
 	applied := 0
 	for _, m := range migrations {
-		if m.Version <= currentVersion {
+		if appliedVersions[m.Version] {
 			continue
 		}
 
-		if m.UpSQL == "" {
-			return applied, fmt.Errorf("migration %d has no up SQL", m.Version)
-		}
-
 		if err := db.applyMigrationTx(ctx, m.Version, m.Description, m.UpSQL); err != nil {
 			return applied, fmt.Errorf("migration %d failed: %w", m.Version, err)
 		}
@@ -154,6 +219,9 @@ func (db *DB) MigrateUp(ctx context.Context) (int, error) {
 
 // MigrateDown rolls back the last n migrations.
 func (db *DB) MigrateDown(ctx context.Context, steps int) (int, error) {
+	if db.readOnly {
+		return 0, ErrReadOnly
+	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -175,10 +243,15 @@ func (db *DB) MigrateDown(ctx context.Context, steps int) (int, error) {
 		return 0, nil // Nothing to roll back
 	}
 
+	appliedVersions, err := db.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	// Find migrations to roll back (in reverse order)
 	var toRollback []Migration
 	for i := len(migrations) - 1; i >= 0 && len(toRollback) < steps; i-- {
-		if migrations[i].Version <= currentVersion {
+		if appliedVersions[migrations[i].Version] {
 			toRollback = append(toRollback, migrations[i])
 		}
 	}
@@ -205,6 +278,9 @@ func (db *DB) MigrateDown(ctx context.Context, steps int) (int, error) {
 
 // MigrateTo migrates to a specific version.
 func (db *DB) MigrateTo(ctx context.Context, targetVersion int) error {
+	if db.readOnly {
+		return ErrReadOnly
+	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -222,6 +298,9 @@ func (db *DB) MigrateTo(ctx context.Context, targetVersion int) error {
 		return err
 	}
 
+	if latest := latestVersion(migrations); targetVersion < 0 || targetVersion > latest {
+		return fmt.Errorf("target version %d out of range (0-%d)", targetVersion, latest)
+	}
 	if targetVersion == currentVersion {
 		return nil
 	}
@@ -323,6 +402,32 @@ func (db *DB) ensureSchemaVersionTable(ctx context.Context) error {
 	return err
 }
 
+// appliedVersions returns the set of versions recorded in schema_version.
+func (db *DB) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_version: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_version row: %w", err)
+		}
+		versions[version] = true
+	}
+	return versions, rows.Err()
+}
+
+func latestVersion(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
 // getCurrentVersion returns the current schema version.
 func (db *DB) getCurrentVersion(ctx context.Context) (int, error) {
 	var version int
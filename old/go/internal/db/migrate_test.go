@@ -2,6 +2,9 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -252,3 +255,155 @@ func TestMigrateTablesCreated(t *testing.T) {
 		}
 	}
 }
+
+func schemaDump(t *testing.T, database *DB) string {
+	t.Helper()
+
+	rows, err := database.QueryContext(context.Background(), `
+		SELECT type, name, COALESCE(sql, '') FROM sqlite_master
+		WHERE name NOT LIKE 'sqlite_%' AND name != 'schema_version'
+		ORDER BY type, name
+	`)
+	if err != nil {
+		t.Fatalf("query sqlite_master: %v", err)
+	}
+	defer rows.Close()
+
+	var b strings.Builder
+	for rows.Next() {
+		var objType, name, sqlText string
+		if err := rows.Scan(&objType, &name, &sqlText); err != nil {
+			t.Fatalf("scan sqlite_master: %v", err)
+		}
+		fmt.Fprintf(&b, "%s|%s|%s\n", objType, name, strings.Join(strings.Fields(sqlText), " "))
+	}
+	return b.String()
+}
+
+func TestMigrateDownUpRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	defer database.Close()
+
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp failed: %v", err)
+	}
+	want := schemaDump(t, database)
+
+	if err := database.MigrateTo(ctx, 0); err != nil {
+		t.Fatalf("MigrateTo(0) failed: %v", err)
+	}
+	if dump := schemaDump(t, database); dump != "" {
+		t.Fatalf("expected empty schema after full rollback, got:\n%s", dump)
+	}
+
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("second MigrateUp failed: %v", err)
+	}
+	if got := schemaDump(t, database); got != want {
+		t.Fatalf("schema differs after down/up round trip")
+	}
+}
+
+func TestMigrateUpFillsMissingVersions(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	defer database.Close()
+
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations failed: %v", err)
+	}
+	latest := migrations[len(migrations)-1]
+
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp failed: %v", err)
+	}
+	// Simulate a database whose history skipped the second-to-last migration.
+	skipped := migrations[len(migrations)-2]
+	if err := database.MigrateTo(ctx, skipped.Version-1); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+	if err := database.applyMigrationTx(ctx, latest.Version, latest.Description, latest.UpSQL); err != nil {
+		t.Fatalf("apply latest failed: %v", err)
+	}
+
+	applied, err := database.MigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("MigrateUp failed: %v", err)
+	}
+	if applied != 1 {
+		t.Fatalf("expected the skipped migration to be applied, got %d", applied)
+	}
+
+	status, err := database.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	for _, s := range status {
+		if !s.Applied {
+			t.Fatalf("migration %d still pending", s.Version)
+		}
+	}
+}
+
+func TestMigrateUpRejectsNewerSchema(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	defer database.Close()
+
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp failed: %v", err)
+	}
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("LatestSchemaVersion failed: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, "INSERT INTO schema_version (version, description) VALUES (?, 'future')", latest+1); err != nil {
+		t.Fatalf("insert future version: %v", err)
+	}
+
+	if _, err := database.MigrateUp(ctx); !errors.Is(err, ErrSchemaAhead) {
+		t.Fatalf("expected ErrSchemaAhead, got %v", err)
+	}
+	if err := database.MigrateTo(ctx, latest+1); err == nil {
+		t.Fatal("expected MigrateTo beyond latest to fail")
+	}
+}
+
+func TestValidateMigrations(t *testing.T) {
+	valid := []Migration{
+		{Version: 1, UpSQL: "CREATE TABLE a (id INTEGER);", DownSQL: "DROP TABLE a;"},
+		{Version: 3, UpSQL: "CREATE TABLE b (id INTEGER);", DownSQL: "DROP TABLE b;"},
+	}
+	if err := validateMigrations(valid); err != nil {
+		t.Fatalf("expected retired version gap to be allowed, got %v", err)
+	}
+
+	missingDown := []Migration{
+		{Version: 1, UpSQL: "CREATE TABLE a (id INTEGER);"},
+	}
+	if err := validateMigrations(missingDown); err == nil || !strings.Contains(err.Error(), "no down SQL") {
+		t.Fatalf("expected missing down SQL error, got %v", err)
+	}
+
+	outOfOrder := []Migration{
+		{Version: 2, UpSQL: "x", DownSQL: "x"},
+		{Version: 2, UpSQL: "y", DownSQL: "y"},
+	}
+	if err := validateMigrations(outOfOrder); err == nil {
+		t.Fatal("expected duplicate version error")
+	}
+}
@@ -0,0 +1,9 @@
+-- Migration: 016_profile_rate_limits (DOWN)
+-- Description: Remove per-profile dispatch rate limits
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_loop_runs_profile_started;
+
+-- Dropping columns in place keeps pool_members/loops foreign keys intact.
+ALTER TABLE profiles DROP COLUMN min_run_gap_seconds;
+ALTER TABLE profiles DROP COLUMN max_runs_per_hour;
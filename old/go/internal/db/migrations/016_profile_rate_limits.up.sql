@@ -0,0 +1,8 @@
+-- Migration: 016_profile_rate_limits
+-- Description: Add per-profile dispatch rate limits
+-- Created: 2026-10-16
+
+ALTER TABLE profiles ADD COLUMN max_runs_per_hour INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE profiles ADD COLUMN min_run_gap_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_loop_runs_profile_started ON loop_runs(profile_id, started_at);
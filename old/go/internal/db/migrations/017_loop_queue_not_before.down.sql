@@ -0,0 +1,5 @@
+-- Migration: 017_loop_queue_not_before (DOWN)
+-- Description: Remove earliest-dispatch time from loop queue items
+-- Created: 2026-10-16
+
+ALTER TABLE loop_queue_items DROP COLUMN not_before;
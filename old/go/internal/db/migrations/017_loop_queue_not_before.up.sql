@@ -0,0 +1,5 @@
+-- Migration: 017_loop_queue_not_before
+-- Description: Add earliest-dispatch time to loop queue items
+-- Created: 2026-10-16
+
+ALTER TABLE loop_queue_items ADD COLUMN not_before TEXT;
@@ -0,0 +1,8 @@
+-- Migration: 019_loop_run_usage (DOWN)
+-- Description: Remove token usage and cost accounting from loop runs
+-- Created: 2026-10-16
+
+ALTER TABLE loop_runs DROP COLUMN cost_usd;
+ALTER TABLE loop_runs DROP COLUMN total_tokens;
+ALTER TABLE loop_runs DROP COLUMN output_tokens;
+ALTER TABLE loop_runs DROP COLUMN input_tokens;
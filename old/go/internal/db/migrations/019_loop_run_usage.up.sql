@@ -0,0 +1,8 @@
+-- Migration: 019_loop_run_usage
+-- Description: Add token usage and cost accounting to loop runs
+-- Created: 2026-10-16
+
+ALTER TABLE loop_runs ADD COLUMN input_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE loop_runs ADD COLUMN output_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE loop_runs ADD COLUMN total_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE loop_runs ADD COLUMN cost_usd REAL NOT NULL DEFAULT 0;
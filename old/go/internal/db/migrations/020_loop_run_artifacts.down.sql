@@ -0,0 +1,6 @@
+-- Migration: 020_loop_run_artifacts (DOWN)
+-- Description: Remove captured artifact files from loop runs
+-- Created: 2026-10-16
+
+ALTER TABLE loop_runs DROP COLUMN artifacts_json;
+ALTER TABLE loop_runs DROP COLUMN artifact_dir;
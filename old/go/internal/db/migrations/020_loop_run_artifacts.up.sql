@@ -0,0 +1,6 @@
+-- Migration: 020_loop_run_artifacts
+-- Description: Record captured artifact files on loop runs
+-- Created: 2026-10-16
+
+ALTER TABLE loop_runs ADD COLUMN artifact_dir TEXT;
+ALTER TABLE loop_runs ADD COLUMN artifacts_json TEXT;
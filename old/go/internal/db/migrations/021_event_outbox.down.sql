@@ -0,0 +1,6 @@
+-- Migration: 021_event_outbox (DOWN)
+-- Description: Remove the event outbox
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,15 @@
+-- Migration: 021_event_outbox
+-- Description: Transactional outbox for events published after a state change commits
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id TEXT NOT NULL UNIQUE,
+    event_json TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    published_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(published_at, id);
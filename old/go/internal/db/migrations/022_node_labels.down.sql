@@ -0,0 +1,5 @@
+-- Migration: 022_node_labels (DOWN)
+-- Description: Remove node labels
+-- Created: 2026-10-16
+
+ALTER TABLE nodes DROP COLUMN labels_json;
@@ -0,0 +1,5 @@
+-- Migration: 022_node_labels
+-- Description: Operator-assigned node labels for constraint-based placement
+-- Created: 2026-10-16
+
+ALTER TABLE nodes ADD COLUMN labels_json TEXT;
@@ -0,0 +1,6 @@
+-- Migration: 023_event_rollups (DOWN)
+-- Description: Remove event rollup summaries
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_event_rollups_type;
+DROP TABLE IF EXISTS event_rollups;
@@ -0,0 +1,15 @@
+-- Migration: 023_event_rollups
+-- Description: Per-day summaries of events removed by retention compaction
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS event_rollups (
+    day TEXT NOT NULL,
+    type TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    first_at TEXT NOT NULL,
+    last_at TEXT NOT NULL,
+    PRIMARY KEY (day, type, entity_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_rollups_type ON event_rollups(type, day);
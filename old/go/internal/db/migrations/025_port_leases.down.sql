@@ -0,0 +1,6 @@
+-- Migration: 025_port_leases (DOWN)
+-- Description: Remove port allocation lease expiry
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_port_allocations_lease;
+ALTER TABLE port_allocations DROP COLUMN lease_expires_at;
@@ -0,0 +1,11 @@
+-- Migration: 025_port_leases
+-- Description: Lease expiry for port reservations not yet bound to an agent
+-- Created: 2026-10-16
+
+-- NULL means the allocation does not expire (it is bound to an agent or was
+-- allocated explicitly). Reservations made during spawn expire if the agent
+-- record is never created.
+ALTER TABLE port_allocations ADD COLUMN lease_expires_at TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_port_allocations_lease
+    ON port_allocations(lease_expires_at);
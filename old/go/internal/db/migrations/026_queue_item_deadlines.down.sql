@@ -0,0 +1,7 @@
+-- Migration: 026_queue_item_deadlines (DOWN)
+-- Description: Remove dispatch deadlines from queue_items
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_queue_items_deadline;
+ALTER TABLE queue_items DROP COLUMN deadline_missed;
+ALTER TABLE queue_items DROP COLUMN deadline;
@@ -0,0 +1,8 @@
+-- Migration: 026_queue_item_deadlines (UP)
+-- Description: Add dispatch deadlines to queue_items
+-- Created: 2026-10-17
+
+ALTER TABLE queue_items ADD COLUMN deadline TEXT;
+ALTER TABLE queue_items ADD COLUMN deadline_missed INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_queue_items_deadline ON queue_items(deadline) WHERE deadline IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Migration: 027_audit_log (DOWN)
+-- Description: Remove the audit log
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_audit_log_request;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_timestamp;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,21 @@
+-- Migration: 027_audit_log
+-- Description: Request-scoped audit log of mutating CLI and API actions
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    timestamp TEXT NOT NULL,
+    request_id TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('cli', 'api')),
+    action TEXT NOT NULL,
+    target TEXT,
+    payload_hash TEXT,
+    result TEXT NOT NULL CHECK (result IN ('ok', 'error')),
+    error TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor, timestamp);
+CREATE INDEX IF NOT EXISTS idx_audit_log_request ON audit_log(request_id);
@@ -0,0 +1,6 @@
+-- Migration: 028_workspace_templates (DOWN)
+-- Description: Remove workspace templates and bootstrap runs
+-- Created: 2026-10-17
+
+DROP TABLE IF EXISTS workspace_bootstraps;
+DROP TABLE IF EXISTS workspace_templates;
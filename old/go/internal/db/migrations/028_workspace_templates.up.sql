@@ -0,0 +1,25 @@
+-- Migration: 028_workspace_templates
+-- Description: Workspace templates and recorded bootstrap runs
+-- Created: 2026-10-17
+
+CREATE TABLE IF NOT EXISTS workspace_templates (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    git_url TEXT,
+    branch TEXT,
+    bootstrap_json TEXT,
+    env_json TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE IF NOT EXISTS workspace_bootstraps (
+    workspace_id TEXT PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    template_id TEXT REFERENCES workspace_templates(id) ON DELETE SET NULL,
+    status TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    exit_code INTEGER,
+    output TEXT,
+    started_at TEXT NOT NULL,
+    finished_at TEXT
+);
@@ -0,0 +1,11 @@
+-- Migration: 029_search_index (DOWN)
+-- Description: Remove the full-text search index
+-- Created: 2026-10-17
+
+DROP TRIGGER IF EXISTS loop_runs_fts_delete;
+DROP TRIGGER IF EXISTS loop_runs_fts_update;
+DROP TRIGGER IF EXISTS loop_runs_fts_insert;
+DROP TRIGGER IF EXISTS events_fts_delete;
+DROP TRIGGER IF EXISTS events_fts_insert;
+DROP TABLE IF EXISTS loop_runs_fts;
+DROP TABLE IF EXISTS events_fts;
@@ -0,0 +1,58 @@
+-- Migration: 029_search_index
+-- Description: Full-text search over event payloads and run output
+-- Created: 2026-10-17
+
+-- Index rows share the rowid of the row they index, so triggers can keep
+-- them in step without scanning the index.
+CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(
+    type,
+    entity_type,
+    entity_id,
+    payload,
+    tokenize = 'unicode61'
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS loop_runs_fts USING fts5(
+    output,
+    tokenize = 'unicode61'
+);
+
+INSERT INTO events_fts(rowid, type, entity_type, entity_id, payload)
+SELECT rowid, type, entity_type, entity_id, COALESCE(payload_json, '') FROM events;
+
+INSERT INTO loop_runs_fts(rowid, output)
+SELECT rowid, output_tail FROM loop_runs WHERE output_tail IS NOT NULL AND output_tail != '';
+
+CREATE TRIGGER IF NOT EXISTS events_fts_insert
+AFTER INSERT ON events
+BEGIN
+    INSERT INTO events_fts(rowid, type, entity_type, entity_id, payload)
+    VALUES (NEW.rowid, NEW.type, NEW.entity_type, NEW.entity_id, COALESCE(NEW.payload_json, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS events_fts_delete
+AFTER DELETE ON events
+BEGIN
+    DELETE FROM events_fts WHERE rowid = OLD.rowid;
+END;
+
+CREATE TRIGGER IF NOT EXISTS loop_runs_fts_insert
+AFTER INSERT ON loop_runs
+WHEN NEW.output_tail IS NOT NULL AND NEW.output_tail != ''
+BEGIN
+    INSERT INTO loop_runs_fts(rowid, output) VALUES (NEW.rowid, NEW.output_tail);
+END;
+
+CREATE TRIGGER IF NOT EXISTS loop_runs_fts_update
+AFTER UPDATE OF output_tail ON loop_runs
+BEGIN
+    DELETE FROM loop_runs_fts WHERE rowid = OLD.rowid;
+    INSERT INTO loop_runs_fts(rowid, output)
+    SELECT NEW.rowid, NEW.output_tail WHERE NEW.output_tail IS NOT NULL AND NEW.output_tail != '';
+END;
+
+CREATE TRIGGER IF NOT EXISTS loop_runs_fts_delete
+AFTER DELETE ON loop_runs
+BEGIN
+    DELETE FROM loop_runs_fts WHERE rowid = OLD.rowid;
+END;
@@ -0,0 +1,13 @@
+-- Migration: 030_namespaces (DOWN)
+-- Description: Remove tenant namespaces
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_events_namespace_timestamp;
+DROP INDEX IF EXISTS idx_loops_namespace;
+DROP INDEX IF EXISTS idx_agents_namespace;
+DROP INDEX IF EXISTS idx_workspaces_namespace;
+
+ALTER TABLE events DROP COLUMN namespace;
+ALTER TABLE loops DROP COLUMN namespace;
+ALTER TABLE agents DROP COLUMN namespace;
+ALTER TABLE workspaces DROP COLUMN namespace;
@@ -0,0 +1,14 @@
+-- Migration: 030_namespaces
+-- Description: Tenant namespaces for workspaces, agents, loops, and events
+-- Created: 2026-10-17
+
+-- Existing rows belong to the default namespace.
+ALTER TABLE workspaces ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE agents ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE loops ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE events ADD COLUMN namespace TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_workspaces_namespace ON workspaces(namespace, name);
+CREATE INDEX IF NOT EXISTS idx_agents_namespace ON agents(namespace);
+CREATE INDEX IF NOT EXISTS idx_loops_namespace ON loops(namespace);
+CREATE INDEX IF NOT EXISTS idx_events_namespace_timestamp ON events(namespace, timestamp);
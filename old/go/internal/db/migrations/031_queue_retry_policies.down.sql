@@ -0,0 +1,7 @@
+-- Migration: 031_queue_retry_policies (DOWN)
+-- Description: Remove retry policies and dead-letter tracking from queue_items
+-- Created: 2026-10-17
+
+DROP INDEX IF EXISTS idx_queue_items_dead_lettered;
+ALTER TABLE queue_items DROP COLUMN dead_lettered_at;
+ALTER TABLE queue_items DROP COLUMN retry_policy_json;
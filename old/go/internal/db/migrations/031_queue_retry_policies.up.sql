@@ -0,0 +1,8 @@
+-- Migration: 031_queue_retry_policies (UP)
+-- Description: Store retry policies on queue_items and track dead-lettered items
+-- Created: 2026-10-17
+
+ALTER TABLE queue_items ADD COLUMN retry_policy_json TEXT;
+ALTER TABLE queue_items ADD COLUMN dead_lettered_at TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_queue_items_dead_lettered ON queue_items(dead_lettered_at) WHERE dead_lettered_at IS NOT NULL;
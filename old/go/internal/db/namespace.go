@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+type namespaceKey struct{}
+
+// WithNamespace returns a context that scopes repository queries to
+// namespace, overriding the handle's namespace. An empty namespace lifts the
+// scope so queries see every tenant (admin access).
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// SetNamespace scopes queries made without a context namespace.
+func (db *DB) SetNamespace(namespace string) {
+	db.namespace.Store(namespace)
+}
+
+// Namespace returns the handle's namespace ("" when unscoped).
+func (db *DB) Namespace() string {
+	namespace, _ := db.namespace.Load().(string)
+	return namespace
+}
+
+// scopeNamespace returns the namespace that applies to a query: the context
+// override if set, else the handle's namespace. Empty means unscoped.
+func (db *DB) scopeNamespace(ctx context.Context) string {
+	if ctx != nil {
+		if namespace, ok := ctx.Value(namespaceKey{}).(string); ok {
+			return namespace
+		}
+	}
+	return db.Namespace()
+}
+
+// createNamespace picks the namespace for a new row: the model's own value,
+// else the query scope, else the default namespace.
+func (db *DB) createNamespace(ctx context.Context, namespace string) (string, error) {
+	if namespace == "" {
+		namespace = db.scopeNamespace(ctx)
+	}
+	return models.NormalizeNamespace(namespace)
+}
+
+// namespaceFilter returns a " <keyword> <column> = ?" clause and its
+// argument when ctx is scoped, or nothing when it is not. keyword is "WHERE"
+// or "AND".
+func (db *DB) namespaceFilter(ctx context.Context, keyword, column string) (string, []any) {
+	namespace := db.scopeNamespace(ctx)
+	if namespace == "" {
+		return "", nil
+	}
+	return " " + keyword + " " + column + " = ?", []any{namespace}
+}
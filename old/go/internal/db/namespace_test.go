@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestNamespaceIsolatesRepositories(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ws := createTestWorkspace(t, db)
+	if ws.Namespace != models.DefaultNamespace {
+		t.Fatalf("expected unscoped workspace in %q, got %q", models.DefaultNamespace, ws.Namespace)
+	}
+
+	acme := WithNamespace(context.Background(), "acme")
+	wsRepo := NewWorkspaceRepository(db)
+	acmeWS := &models.Workspace{NodeID: ws.NodeID, RepoPath: "/tmp/acme", TmuxSession: "acme"}
+	if err := wsRepo.Create(acme, acmeWS); err != nil {
+		t.Fatalf("create acme workspace: %v", err)
+	}
+
+	agentRepo := NewAgentRepository(db)
+	agent := &models.Agent{WorkspaceID: acmeWS.ID, Type: models.AgentTypeOpenCode, TmuxPane: "acme:0.1", State: models.AgentStateIdle}
+	if err := agentRepo.Create(context.Background(), agent); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+	if agent.Namespace != "acme" {
+		t.Fatalf("expected agent to inherit workspace namespace, got %q", agent.Namespace)
+	}
+	crossTenant := &models.Agent{WorkspaceID: ws.ID, Type: models.AgentTypeOpenCode, TmuxPane: "forge-test:0.2"}
+	if err := agentRepo.Create(acme, crossTenant); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("expected agent in another tenant's workspace to be rejected, got %v", err)
+	}
+
+	loopRepo := NewLoopRepository(db)
+	loop := &models.Loop{Name: "acme-loop", RepoPath: "/tmp/acme", IntervalSeconds: 10}
+	if err := loopRepo.Create(acme, loop); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runRepo := NewLoopRunRepository(db)
+	run := &models.LoopRun{LoopID: loop.ID, Status: models.LoopRunStatusRunning}
+	if err := runRepo.Create(acme, run); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	run.Status = models.LoopRunStatusSuccess
+	run.OutputTail = "acme deploy key rotated"
+	run.TotalTokens, run.CostUSD = 1200, 0.5
+	if err := runRepo.Finish(acme, run); err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+
+	eventRepo := NewEventRepository(db)
+	if err := eventRepo.Create(acme, &models.Event{Type: models.EventTypeAgentSpawned, EntityType: models.EntityTypeAgent, EntityID: agent.ID}); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+
+	other := WithNamespace(context.Background(), "other")
+	if workspaces, err := wsRepo.List(other); err != nil || len(workspaces) != 0 {
+		t.Fatalf("expected no workspaces in other namespace, got %d (%v)", len(workspaces), err)
+	}
+	if _, err := wsRepo.Get(other, acmeWS.ID); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("expected workspace to be hidden from other namespace, got %v", err)
+	}
+	if _, err := agentRepo.Get(other, agent.ID); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("expected agent to be hidden from other namespace, got %v", err)
+	}
+	if _, err := loopRepo.GetByName(other, "acme-loop"); !errors.Is(err, ErrLoopNotFound) {
+		t.Fatalf("expected loop to be hidden from other namespace, got %v", err)
+	}
+	if err := loopRepo.Delete(other, loop.ID); !errors.Is(err, ErrLoopNotFound) {
+		t.Fatalf("expected delete from other namespace to miss, got %v", err)
+	}
+	if count, err := eventRepo.Count(other); err != nil || count != 0 {
+		t.Fatalf("expected no events in other namespace, got %d (%v)", count, err)
+	}
+	if results, err := runRepo.SearchOutput(other, LoopRunSearchQuery{Text: "deploy key"}); err != nil || len(results) != 0 {
+		t.Fatalf("expected run output to be hidden from other namespace, got %d (%v)", len(results), err)
+	}
+	if usage, err := runRepo.UsageByLoop(other, time.Time{}); err != nil || len(usage) != 0 {
+		t.Fatalf("expected no loop usage in other namespace, got %+v (%v)", usage, err)
+	}
+	if usage, err := runRepo.UsageByProfile(other, time.Time{}); err != nil || len(usage) != 0 {
+		t.Fatalf("expected no profile usage in other namespace, got %+v (%v)", usage, err)
+	}
+	if results, err := runRepo.SearchOutput(acme, LoopRunSearchQuery{Text: "deploy key"}); err != nil || len(results) != 1 {
+		t.Fatalf("expected the acme run in search, got %d (%v)", len(results), err)
+	}
+	if usage, err := runRepo.UsageByLoop(acme, time.Time{}); err != nil || len(usage) != 1 || usage[0].CostUSD != 0.5 {
+		t.Fatalf("expected the acme loop usage, got %+v (%v)", usage, err)
+	}
+
+	workspaces, err := wsRepo.List(acme)
+	if err != nil || len(workspaces) != 1 || workspaces[0].ID != acmeWS.ID {
+		t.Fatalf("expected only the acme workspace, got %+v (%v)", workspaces, err)
+	}
+	loops, err := loopRepo.List(acme)
+	if err != nil || len(loops) != 1 || loops[0].Namespace != "acme" {
+		t.Fatalf("expected the acme loop, got %+v (%v)", loops, err)
+	}
+
+	// The handle namespace applies when the context sets none; an empty
+	// context namespace lifts the scope.
+	db.SetNamespace("acme")
+	if agents, err := agentRepo.List(context.Background()); err != nil || len(agents) != 1 {
+		t.Fatalf("expected handle namespace to scope agents, got %d (%v)", len(agents), err)
+	}
+	all := WithNamespace(context.Background(), "")
+	if workspaces, err := wsRepo.List(all); err != nil || len(workspaces) != 2 {
+		t.Fatalf("expected unscoped list to see both workspaces, got %d (%v)", len(workspaces), err)
+	}
+
+	if err := loopRepo.Create(context.Background(), &models.Loop{Name: "bad", RepoPath: "/tmp", Namespace: "Not Valid!"}); err == nil {
+		t.Fatal("expected invalid namespace to be rejected")
+	}
+}
@@ -31,6 +31,8 @@ func NewNodeRepository(db *DB) *NodeRepository {
 
 // Create adds a new node to the database.
 func (r *NodeRepository) Create(ctx context.Context, node *models.Node) error {
+	defer r.db.invalidateCache(cacheNodes)
+
 	if err := node.Validate(); err != nil {
 		return fmt.Errorf("invalid node: %w", err)
 	}
@@ -47,6 +49,10 @@ func (r *NodeRepository) Create(ctx context.Context, node *models.Node) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
+	labelsJSON, err := marshalNodeLabels(node.Labels)
+	if err != nil {
+		return err
+	}
 
 	var lastSeen *string
 	if node.LastSeen != nil {
@@ -59,8 +65,8 @@ func (r *NodeRepository) Create(ctx context.Context, node *models.Node) error {
 			id, name, ssh_target, ssh_backend, ssh_key_path,
 			ssh_agent_forwarding, ssh_proxy_jump, ssh_control_master,
 			ssh_control_path, ssh_control_persist, ssh_timeout_seconds,
-			status, is_local, last_seen_at, metadata_json, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			status, is_local, last_seen_at, metadata_json, labels_json, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		node.ID,
 		node.Name,
@@ -77,6 +83,7 @@ func (r *NodeRepository) Create(ctx context.Context, node *models.Node) error {
 		boolToInt(node.IsLocal),
 		lastSeen,
 		string(metadataJSON),
+		labelsJSON,
 		node.CreatedAt.Format(time.RFC3339),
 		node.UpdatedAt.Format(time.RFC3339),
 	)
@@ -93,12 +100,18 @@ func (r *NodeRepository) Create(ctx context.Context, node *models.Node) error {
 
 // Get retrieves a node by ID.
 func (r *NodeRepository) Get(ctx context.Context, id string) (*models.Node, error) {
+	return cachedOne(r.db.repoCache(), cacheNodes, "id:"+id, cloneNode, func() (*models.Node, error) {
+		return r.get(ctx, id)
+	})
+}
+
+func (r *NodeRepository) get(ctx context.Context, id string) (*models.Node, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
 			id, name, ssh_target, ssh_backend, ssh_key_path,
 			ssh_agent_forwarding, ssh_proxy_jump, ssh_control_master, ssh_control_path,
 			ssh_control_persist, ssh_timeout_seconds, status,
-			is_local, last_seen_at, metadata_json, created_at, updated_at
+			is_local, last_seen_at, metadata_json, labels_json, created_at, updated_at
 		FROM nodes WHERE id = ?
 	`, id)
 
@@ -107,12 +120,18 @@ func (r *NodeRepository) Get(ctx context.Context, id string) (*models.Node, erro
 
 // GetByName retrieves a node by name.
 func (r *NodeRepository) GetByName(ctx context.Context, name string) (*models.Node, error) {
+	return cachedOne(r.db.repoCache(), cacheNodes, "name:"+name, cloneNode, func() (*models.Node, error) {
+		return r.getByName(ctx, name)
+	})
+}
+
+func (r *NodeRepository) getByName(ctx context.Context, name string) (*models.Node, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
 			id, name, ssh_target, ssh_backend, ssh_key_path,
 			ssh_agent_forwarding, ssh_proxy_jump, ssh_control_master, ssh_control_path,
 			ssh_control_persist, ssh_timeout_seconds, status,
-			is_local, last_seen_at, metadata_json, created_at, updated_at
+			is_local, last_seen_at, metadata_json, labels_json, created_at, updated_at
 		FROM nodes WHERE name = ?
 	`, name)
 
@@ -121,6 +140,12 @@ func (r *NodeRepository) GetByName(ctx context.Context, name string) (*models.No
 
 // List retrieves all nodes, optionally filtered by status.
 func (r *NodeRepository) List(ctx context.Context, status *models.NodeStatus) ([]*models.Node, error) {
+	return cachedList(r.db.repoCache(), cacheNodes, nodeListCacheKey(status), cloneNode, func() ([]*models.Node, error) {
+		return r.list(ctx, status)
+	})
+}
+
+func (r *NodeRepository) list(ctx context.Context, status *models.NodeStatus) ([]*models.Node, error) {
 	var rows *sql.Rows
 	var err error
 
@@ -130,7 +155,7 @@ func (r *NodeRepository) List(ctx context.Context, status *models.NodeStatus) ([
 				id, name, ssh_target, ssh_backend, ssh_key_path,
 				ssh_agent_forwarding, ssh_proxy_jump, ssh_control_master, ssh_control_path,
 				ssh_control_persist, ssh_timeout_seconds, status,
-				is_local, last_seen_at, metadata_json, created_at, updated_at
+				is_local, last_seen_at, metadata_json, labels_json, created_at, updated_at
 			FROM nodes WHERE status = ?
 			ORDER BY name
 		`, string(*status))
@@ -140,7 +165,7 @@ func (r *NodeRepository) List(ctx context.Context, status *models.NodeStatus) ([
 				id, name, ssh_target, ssh_backend, ssh_key_path,
 				ssh_agent_forwarding, ssh_proxy_jump, ssh_control_master, ssh_control_path,
 				ssh_control_persist, ssh_timeout_seconds, status,
-				is_local, last_seen_at, metadata_json, created_at, updated_at
+				is_local, last_seen_at, metadata_json, labels_json, created_at, updated_at
 			FROM nodes ORDER BY name
 		`)
 	}
@@ -168,6 +193,8 @@ func (r *NodeRepository) List(ctx context.Context, status *models.NodeStatus) ([
 
 // Update updates an existing node.
 func (r *NodeRepository) Update(ctx context.Context, node *models.Node) error {
+	defer r.db.invalidateCache(cacheNodes)
+
 	if err := node.Validate(); err != nil {
 		return fmt.Errorf("invalid node: %w", err)
 	}
@@ -178,6 +205,10 @@ func (r *NodeRepository) Update(ctx context.Context, node *models.Node) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
+	labelsJSON, err := marshalNodeLabels(node.Labels)
+	if err != nil {
+		return err
+	}
 
 	var lastSeen *string
 	if node.LastSeen != nil {
@@ -201,6 +232,7 @@ func (r *NodeRepository) Update(ctx context.Context, node *models.Node) error {
 			is_local = ?,
 			last_seen_at = ?,
 			metadata_json = ?,
+			labels_json = ?,
 			updated_at = ?
 		WHERE id = ?
 	`,
@@ -218,6 +250,7 @@ func (r *NodeRepository) Update(ctx context.Context, node *models.Node) error {
 		boolToInt(node.IsLocal),
 		lastSeen,
 		string(metadataJSON),
+		labelsJSON,
 		node.UpdatedAt.Format(time.RFC3339),
 		node.ID,
 	)
@@ -243,6 +276,8 @@ func (r *NodeRepository) Update(ctx context.Context, node *models.Node) error {
 
 // Delete removes a node by ID.
 func (r *NodeRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.invalidateCache(cacheNodes)
+
 	result, err := r.db.ExecContext(ctx, "DELETE FROM nodes WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete node: %w", err)
@@ -262,6 +297,8 @@ func (r *NodeRepository) Delete(ctx context.Context, id string) error {
 
 // UpdateStatus updates only the status and last_seen fields.
 func (r *NodeRepository) UpdateStatus(ctx context.Context, id string, status models.NodeStatus) error {
+	defer r.db.invalidateCache(cacheNodes)
+
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	result, err := r.db.ExecContext(ctx, `
@@ -309,7 +346,7 @@ func (r *NodeRepository) scanNode(row *sql.Row) (*models.Node, error) {
 	var agentForwarding int
 	var proxyJump, controlMaster, controlPath, controlPersist sql.NullString
 	var timeoutSeconds sql.NullInt64
-	var lastSeen, metadataJSON sql.NullString
+	var lastSeen, metadataJSON, labelsJSON sql.NullString
 	var createdAt, updatedAt string
 
 	err := row.Scan(
@@ -328,6 +365,7 @@ func (r *NodeRepository) scanNode(row *sql.Row) (*models.Node, error) {
 		&isLocal,
 		&lastSeen,
 		&metadataJSON,
+		&labelsJSON,
 		&createdAt,
 		&updatedAt,
 	)
@@ -372,6 +410,12 @@ func (r *NodeRepository) scanNode(row *sql.Row) (*models.Node, error) {
 		}
 	}
 
+	if labelsJSON.Valid && labelsJSON.String != "" {
+		if err := json.Unmarshal([]byte(labelsJSON.String), &node.Labels); err != nil {
+			r.db.logger.Warn().Err(err).Str("node_id", node.ID).Msg("failed to parse node labels")
+		}
+	}
+
 	if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
 		node.CreatedAt = t
 	}
@@ -390,7 +434,7 @@ func (r *NodeRepository) scanNodeFromRows(rows *sql.Rows) (*models.Node, error)
 	var agentForwarding int
 	var proxyJump, controlMaster, controlPath, controlPersist sql.NullString
 	var timeoutSeconds sql.NullInt64
-	var lastSeen, metadataJSON sql.NullString
+	var lastSeen, metadataJSON, labelsJSON sql.NullString
 	var createdAt, updatedAt string
 
 	err := rows.Scan(
@@ -409,6 +453,7 @@ func (r *NodeRepository) scanNodeFromRows(rows *sql.Rows) (*models.Node, error)
 		&isLocal,
 		&lastSeen,
 		&metadataJSON,
+		&labelsJSON,
 		&createdAt,
 		&updatedAt,
 	)
@@ -450,6 +495,12 @@ func (r *NodeRepository) scanNodeFromRows(rows *sql.Rows) (*models.Node, error)
 		}
 	}
 
+	if labelsJSON.Valid && labelsJSON.String != "" {
+		if err := json.Unmarshal([]byte(labelsJSON.String), &node.Labels); err != nil {
+			r.db.logger.Warn().Err(err).Str("node_id", node.ID).Msg("failed to parse node labels")
+		}
+	}
+
 	if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
 		node.CreatedAt = t
 	}
@@ -462,6 +513,27 @@ func (r *NodeRepository) scanNodeFromRows(rows *sql.Rows) (*models.Node, error)
 
 // Helper functions
 
+// marshalNodeLabels encodes labels for the labels_json column, storing
+// NULL when the node has none.
+func marshalNodeLabels(labels map[string]string) (*string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+func nodeListCacheKey(status *models.NodeStatus) string {
+	if status == nil {
+		return "list"
+	}
+	return "list:" + string(*status)
+}
+
 func boolToInt(b bool) int {
 	if b {
 		return 1
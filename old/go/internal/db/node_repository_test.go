@@ -227,6 +227,52 @@ func TestNodeRepository_Update(t *testing.T) {
 	}
 }
 
+func TestNodeRepository_Labels(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewNodeRepository(db)
+	ctx := context.Background()
+
+	node := &models.Node{
+		Name:       "gpu-node",
+		SSHTarget:  "user@gpu.example.com",
+		SSHBackend: models.SSHBackendAuto,
+		Status:     models.NodeStatusOnline,
+		Labels:     map[string]string{"gpu": "true", "region": "eu"},
+	}
+	if err := repo.Create(ctx, node); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	retrieved, err := repo.Get(ctx, node.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := models.FormatNodeLabels(retrieved.Labels); got != "gpu=true,region=eu" {
+		t.Fatalf("expected labels gpu=true,region=eu, got %q", got)
+	}
+
+	// Mutating the returned copy must not leak into the cache.
+	retrieved.Labels["region"] = "us"
+	delete(retrieved.Labels, "gpu")
+	if err := repo.Update(ctx, retrieved); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	nodes, err := repo.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(nodes) != 1 || models.FormatNodeLabels(nodes[0].Labels) != "region=us" {
+		t.Fatalf("expected updated labels region=us, got %+v", nodes)
+	}
+
+	node.Labels = map[string]string{"bad key": "x"}
+	if err := repo.Update(ctx, node); err == nil {
+		t.Fatal("expected invalid label key to be rejected")
+	}
+}
+
 func TestNodeRepository_Delete(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
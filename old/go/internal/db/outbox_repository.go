@@ -0,0 +1,173 @@
+// Package db provides SQLite database access for Forge.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// OutboxEntry is an event waiting in the outbox to be published.
+type OutboxEntry struct {
+	// ID orders entries; events are published in enqueue order.
+	ID        int64
+	Event     *models.Event
+	CreatedAt time.Time
+	Attempts  int
+	LastError string
+}
+
+// OutboxRepository stores events in the same transaction as the state
+// change they describe. A dispatcher (events.OutboxDispatcher) later
+// publishes them, so an event is never lost when the process dies between
+// committing a write and publishing it.
+type OutboxRepository struct {
+	db *DB
+}
+
+// NewOutboxRepository creates a new OutboxRepository.
+func NewOutboxRepository(db *DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue adds an event to the outbox on its own.
+func (r *OutboxRepository) Enqueue(ctx context.Context, event *models.Event) error {
+	return r.enqueueWithExecutor(ctx, r.db, event)
+}
+
+// EnqueueWithTx adds an event to the outbox within an existing transaction.
+func (r *OutboxRepository) EnqueueWithTx(ctx context.Context, tx *sql.Tx, event *models.Event) error {
+	if tx == nil {
+		return fmt.Errorf("transaction is required")
+	}
+	return r.enqueueWithExecutor(ctx, tx, event)
+}
+
+func (r *OutboxRepository) enqueueWithExecutor(ctx context.Context, execer eventExecer, event *models.Event) error {
+	if event == nil {
+		return ErrInvalidEvent
+	}
+	if err := prepareEvent(ctx, r.db, event); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+
+	_, err = execer.ExecContext(ctx, `
+		INSERT INTO event_outbox (event_id, event_json, created_at)
+		VALUES (?, ?, ?)
+	`,
+		event.ID,
+		string(data),
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+	return nil
+}
+
+// Pending returns up to limit unpublished entries, oldest first.
+func (r *OutboxRepository) Pending(ctx context.Context, limit int) ([]*OutboxEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_json, created_at, attempts, last_error
+		FROM event_outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*OutboxEntry
+	for rows.Next() {
+		var (
+			entry     OutboxEntry
+			eventJSON string
+			createdAt string
+			lastError sql.NullString
+		)
+		if err := rows.Scan(&entry.ID, &eventJSON, &createdAt, &entry.Attempts, &lastError); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		entry.LastError = lastError.String
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			entry.CreatedAt = t
+		}
+		entry.Event = &models.Event{}
+		if err := json.Unmarshal([]byte(eventJSON), entry.Event); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox entry %d: %w", entry.ID, err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox: %w", err)
+	}
+	return entries, nil
+}
+
+// PendingCount returns the number of unpublished entries.
+func (r *OutboxRepository) PendingCount(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM event_outbox WHERE published_at IS NULL
+	`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count outbox entries: %w", err)
+	}
+	return count, nil
+}
+
+// MarkPublished records that an entry has been delivered.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET published_at = ?, attempts = attempts + 1, last_error = NULL
+		WHERE id = ?
+	`, time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox entry published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt; the entry stays pending.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, cause error) error {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET attempts = attempts + 1, last_error = ?
+		WHERE id = ?
+	`, message, id)
+	if err != nil {
+		return fmt.Errorf("failed to record outbox failure: %w", err)
+	}
+	return nil
+}
+
+// PurgePublished deletes entries published before the cutoff.
+func (r *OutboxRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_outbox
+		WHERE published_at IS NOT NULL AND published_at < ?
+	`, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestOutboxRepository_EnqueueWithTxRollsBack(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	outbox := NewOutboxRepository(db)
+	boom := errors.New("boom")
+
+	err := db.Transaction(ctx, func(tx *sql.Tx) error {
+		if err := outbox.EnqueueWithTx(ctx, tx, &models.Event{
+			Type:       models.EventTypeAgentStateChanged,
+			EntityType: models.EntityTypeAgent,
+			EntityID:   "agent-1",
+		}); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if count, err := outbox.PendingCount(ctx); err != nil || count != 0 {
+		t.Fatalf("expected empty outbox after rollback, got %d (%v)", count, err)
+	}
+}
+
+func TestOutboxRepository_PendingAndMark(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	outbox := NewOutboxRepository(db)
+
+	for _, id := range []string{"agent-1", "agent-2"} {
+		if err := outbox.Enqueue(ctx, &models.Event{
+			Type:       models.EventTypeAgentStateChanged,
+			EntityType: models.EntityTypeAgent,
+			EntityID:   id,
+			Payload:    []byte(`{"new_state":"working"}`),
+		}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if err := outbox.Enqueue(ctx, &models.Event{EntityType: models.EntityTypeAgent, EntityID: "x"}); err == nil {
+		t.Fatalf("expected invalid event to be rejected")
+	}
+
+	entries, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Event.EntityID != "agent-1" || entries[1].Event.EntityID != "agent-2" {
+		t.Fatalf("unexpected pending entries %+v", entries)
+	}
+	if entries[0].Event.ID == "" || string(entries[0].Event.Payload) != `{"new_state":"working"}` {
+		t.Fatalf("event not round-tripped: %+v", entries[0].Event)
+	}
+
+	if err := outbox.MarkFailed(ctx, entries[0].ID, errors.New("db down")); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := outbox.MarkPublished(ctx, entries[1].ID); err != nil {
+		t.Fatalf("mark published: %v", err)
+	}
+
+	entries, err = outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Attempts != 1 || entries[0].LastError != "db down" {
+		t.Fatalf("expected failed entry to stay pending, got %+v", entries)
+	}
+
+	purged, err := outbox.PurgePublished(ctx, time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("purge = %d, %v; want 1", purged, err)
+	}
+}
+
+func TestAgentRepository_UpdateWithOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewAgentRepository(db)
+	outbox := NewOutboxRepository(db)
+	ws := createTestWorkspace(t, db)
+
+	agent := &models.Agent{
+		WorkspaceID: ws.ID,
+		Type:        models.AgentTypeOpenCode,
+		TmuxPane:    "forge:0.1",
+		State:       models.AgentStateIdle,
+		StateInfo:   models.StateInfo{State: models.AgentStateIdle, Confidence: models.StateConfidenceHigh},
+	}
+	if err := repo.Create(ctx, agent); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+
+	agent.State = models.AgentStateWorking
+	agent.StateInfo = models.StateInfo{State: models.AgentStateWorking, Confidence: models.StateConfidenceMedium}
+	if err := repo.UpdateWithOutbox(ctx, agent, &models.Event{EntityType: models.EntityTypeAgent, EntityID: agent.ID}, outbox); err == nil {
+		t.Fatalf("expected error from invalid event")
+	}
+	stored, err := repo.Get(ctx, agent.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if stored.State != models.AgentStateIdle {
+		t.Fatalf("expected state to remain idle, got %s", stored.State)
+	}
+
+	event := &models.Event{Type: models.EventTypeAgentStateChanged, EntityType: models.EntityTypeAgent, EntityID: agent.ID}
+	if err := repo.UpdateWithOutbox(ctx, agent, event, outbox); err != nil {
+		t.Fatalf("update with outbox: %v", err)
+	}
+	if count, err := outbox.PendingCount(ctx); err != nil || count != 1 {
+		t.Fatalf("expected 1 pending event, got %d (%v)", count, err)
+	}
+}
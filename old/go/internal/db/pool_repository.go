@@ -31,6 +31,8 @@ func NewPoolRepository(db *DB) *PoolRepository {
 
 // Create adds a new pool to the database.
 func (r *PoolRepository) Create(ctx context.Context, pool *models.Pool) error {
+	defer r.db.invalidateCache(cachePools)
+
 	if err := pool.Validate(); err != nil {
 		return fmt.Errorf("invalid pool: %w", err)
 	}
@@ -82,6 +84,12 @@ func (r *PoolRepository) Create(ctx context.Context, pool *models.Pool) error {
 
 // Get retrieves a pool by ID.
 func (r *PoolRepository) Get(ctx context.Context, id string) (*models.Pool, error) {
+	return cachedOne(r.db.repoCache(), cachePools, "id:"+id, clonePool, func() (*models.Pool, error) {
+		return r.get(ctx, id)
+	})
+}
+
+func (r *PoolRepository) get(ctx context.Context, id string) (*models.Pool, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, name, strategy, is_default, metadata_json, created_at, updated_at
 		FROM pools WHERE id = ?
@@ -91,6 +99,12 @@ func (r *PoolRepository) Get(ctx context.Context, id string) (*models.Pool, erro
 
 // GetByName retrieves a pool by name.
 func (r *PoolRepository) GetByName(ctx context.Context, name string) (*models.Pool, error) {
+	return cachedOne(r.db.repoCache(), cachePools, "name:"+name, clonePool, func() (*models.Pool, error) {
+		return r.getByName(ctx, name)
+	})
+}
+
+func (r *PoolRepository) getByName(ctx context.Context, name string) (*models.Pool, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, name, strategy, is_default, metadata_json, created_at, updated_at
 		FROM pools WHERE name = ?
@@ -100,6 +114,12 @@ func (r *PoolRepository) GetByName(ctx context.Context, name string) (*models.Po
 
 // GetDefault retrieves the default pool.
 func (r *PoolRepository) GetDefault(ctx context.Context) (*models.Pool, error) {
+	return cachedOne(r.db.repoCache(), cachePools, "default", clonePool, func() (*models.Pool, error) {
+		return r.getDefault(ctx)
+	})
+}
+
+func (r *PoolRepository) getDefault(ctx context.Context) (*models.Pool, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, name, strategy, is_default, metadata_json, created_at, updated_at
 		FROM pools WHERE is_default = 1
@@ -111,6 +131,12 @@ func (r *PoolRepository) GetDefault(ctx context.Context) (*models.Pool, error) {
 
 // List retrieves all pools.
 func (r *PoolRepository) List(ctx context.Context) ([]*models.Pool, error) {
+	return cachedList(r.db.repoCache(), cachePools, "list", clonePool, func() ([]*models.Pool, error) {
+		return r.list(ctx)
+	})
+}
+
+func (r *PoolRepository) list(ctx context.Context) ([]*models.Pool, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, name, strategy, is_default, metadata_json, created_at, updated_at
 		FROM pools
@@ -135,6 +161,8 @@ func (r *PoolRepository) List(ctx context.Context) ([]*models.Pool, error) {
 
 // Update updates a pool.
 func (r *PoolRepository) Update(ctx context.Context, pool *models.Pool) error {
+	defer r.db.invalidateCache(cachePools)
+
 	if err := pool.Validate(); err != nil {
 		return fmt.Errorf("invalid pool: %w", err)
 	}
@@ -182,6 +210,8 @@ func (r *PoolRepository) Update(ctx context.Context, pool *models.Pool) error {
 
 // Delete removes a pool.
 func (r *PoolRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.invalidateCache(cachePools)
+
 	result, err := r.db.ExecContext(ctx, `DELETE FROM pools WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete pool: %w", err)
@@ -195,6 +225,8 @@ func (r *PoolRepository) Delete(ctx context.Context, id string) error {
 
 // SetDefault marks a pool as default and clears other defaults.
 func (r *PoolRepository) SetDefault(ctx context.Context, id string) error {
+	defer r.db.invalidateCache(cachePools)
+
 	_, err := r.db.ExecContext(ctx, `UPDATE pools SET is_default = 0`)
 	if err != nil {
 		return fmt.Errorf("failed to clear defaults: %w", err)
@@ -33,6 +33,8 @@ type PortAllocation struct {
 	Reason      string
 	AllocatedAt time.Time
 	ReleasedAt  *time.Time
+	// LeaseExpiresAt is set on reservations not yet bound to an agent.
+	LeaseExpiresAt *time.Time
 }
 
 // PortRepository handles port allocation persistence.
@@ -60,32 +62,61 @@ func NewPortRepositoryWithRange(db *DB, start, end int) *PortRepository {
 	}
 }
 
+// Range returns the inclusive port range this repository allocates from.
+func (r *PortRepository) Range() (int, int) {
+	return r.rangeStart, r.rangeEnd
+}
+
 // Allocate finds an available port for the given node and allocates it.
 // Returns the allocated port number.
 func (r *PortRepository) Allocate(ctx context.Context, nodeID, agentID, reason string) (int, error) {
+	return r.allocate(ctx, nodeID, agentID, reason, 0, nil)
+}
+
+// AllocateLease reserves the first free port on a node that inUse does not
+// report as taken. The reservation expires after ttl unless Bind attaches it
+// to an agent first; expired reservations on the node are dropped in the
+// same transaction, so concurrent allocators never hand out the same port.
+func (r *PortRepository) AllocateLease(ctx context.Context, nodeID, reason string, ttl time.Duration, inUse func(port int) bool) (int, error) {
+	return r.allocate(ctx, nodeID, "", reason, ttl, inUse)
+}
+
+func (r *PortRepository) allocate(ctx context.Context, nodeID, agentID, reason string, ttl time.Duration, inUse func(port int) bool) (int, error) {
 	var port int
 	var err error
 
 	// Use a transaction to ensure atomicity
 	err = r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM port_allocations
+			WHERE node_id = ? AND lease_expires_at IS NOT NULL AND lease_expires_at <= ?
+		`, nodeID, now.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("failed to drop expired port leases: %w", err)
+		}
+
 		// Find the first available port in range
 		// A port is available if it has no allocation record or its allocation is released
-		port, err = r.findAvailablePort(ctx, tx, nodeID)
+		port, err = r.findAvailablePort(ctx, tx, nodeID, inUse)
 		if err != nil {
 			return err
 		}
 
 		// Create the allocation
-		now := time.Now().UTC().Format(time.RFC3339)
 		var agentIDPtr *string
 		if agentID != "" {
 			agentIDPtr = &agentID
 		}
+		var leaseExpiresAt *string
+		if ttl > 0 {
+			expires := now.Add(ttl).Format(time.RFC3339)
+			leaseExpiresAt = &expires
+		}
 
 		_, err = tx.ExecContext(ctx, `
-			INSERT INTO port_allocations (port, node_id, agent_id, reason, allocated_at)
-			VALUES (?, ?, ?, ?, ?)
-		`, port, nodeID, agentIDPtr, reason, now)
+			INSERT INTO port_allocations (port, node_id, agent_id, reason, allocated_at, lease_expires_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, port, nodeID, agentIDPtr, reason, now.Format(time.RFC3339), leaseExpiresAt)
 		if err != nil {
 			return fmt.Errorf("failed to insert port allocation: %w", err)
 		}
@@ -100,6 +131,30 @@ func (r *PortRepository) Allocate(ctx context.Context, nodeID, agentID, reason s
 	return port, nil
 }
 
+// Bind attaches a port allocation to an agent and clears its lease, so it is
+// kept until the agent releases it or is reclaimed as dead.
+func (r *PortRepository) Bind(ctx context.Context, nodeID string, port int, agentID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE port_allocations
+		SET agent_id = ?, lease_expires_at = NULL
+		WHERE node_id = ? AND port = ?
+	`, agentID, nodeID, port)
+	if err != nil {
+		return fmt.Errorf("failed to bind port allocation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPortNotAllocated
+	}
+
+	return nil
+}
+
 // AllocateSpecific allocates a specific port for the given node.
 // Returns an error if the port is already in use.
 func (r *PortRepository) AllocateSpecific(ctx context.Context, nodeID string, port int, agentID, reason string) error {
@@ -185,7 +240,7 @@ func (r *PortRepository) ReleaseByAgent(ctx context.Context, agentID string) (in
 // GetByAgent retrieves the active port allocation for an agent.
 func (r *PortRepository) GetByAgent(ctx context.Context, agentID string) (*PortAllocation, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, port, node_id, agent_id, reason, allocated_at
+		SELECT id, port, node_id, agent_id, reason, allocated_at, lease_expires_at
 		FROM port_allocations
 		WHERE agent_id = ?
 		ORDER BY allocated_at DESC
@@ -198,7 +253,7 @@ func (r *PortRepository) GetByAgent(ctx context.Context, agentID string) (*PortA
 // GetByNodeAndPort retrieves an active port allocation by node and port.
 func (r *PortRepository) GetByNodeAndPort(ctx context.Context, nodeID string, port int) (*PortAllocation, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, port, node_id, agent_id, reason, allocated_at
+		SELECT id, port, node_id, agent_id, reason, allocated_at, lease_expires_at
 		FROM port_allocations
 		WHERE node_id = ? AND port = ?
 	`, nodeID, port)
@@ -209,7 +264,7 @@ func (r *PortRepository) GetByNodeAndPort(ctx context.Context, nodeID string, po
 // ListActiveByNode retrieves all active port allocations for a node.
 func (r *PortRepository) ListActiveByNode(ctx context.Context, nodeID string) ([]*PortAllocation, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, port, node_id, agent_id, reason, allocated_at
+		SELECT id, port, node_id, agent_id, reason, allocated_at, lease_expires_at
 		FROM port_allocations
 		WHERE node_id = ?
 		ORDER BY port
@@ -222,6 +277,21 @@ func (r *PortRepository) ListActiveByNode(ctx context.Context, nodeID string) ([
 	return r.scanAllocations(rows)
 }
 
+// ListActive retrieves all active port allocations across nodes.
+func (r *PortRepository) ListActive(ctx context.Context) ([]*PortAllocation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, port, node_id, agent_id, reason, allocated_at, lease_expires_at
+		FROM port_allocations
+		ORDER BY node_id, port
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query port allocations: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanAllocations(rows)
+}
+
 // CountActiveByNode returns the number of active port allocations for a node.
 func (r *PortRepository) CountActiveByNode(ctx context.Context, nodeID string) (int, error) {
 	var count int
@@ -269,8 +339,30 @@ func (r *PortRepository) CleanupExpired(ctx context.Context) (int, error) {
 	return int(rowsAffected), nil
 }
 
-// findAvailablePort finds the first available port in the configured range.
-func (r *PortRepository) findAvailablePort(ctx context.Context, tx *sql.Tx, nodeID string) (int, error) {
+// ReclaimStale releases allocations whose lease expired without being bound
+// to an agent, whose agent no longer exists, or whose agent is stopped.
+func (r *PortRepository) ReclaimStale(ctx context.Context) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM port_allocations
+		WHERE (lease_expires_at IS NOT NULL AND lease_expires_at <= ?)
+		OR (agent_id IS NOT NULL AND agent_id NOT IN (SELECT id FROM agents))
+		OR agent_id IN (SELECT id FROM agents WHERE state = 'stopped')
+	`, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reclaim stale port allocations: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// findAvailablePort finds the first available port in the configured range,
+// skipping ports inUse reports as taken outside of Forge.
+func (r *PortRepository) findAvailablePort(ctx context.Context, tx *sql.Tx, nodeID string, inUse func(port int) bool) (int, error) {
 	// Get all allocated ports for this node
 	rows, err := tx.QueryContext(ctx, `
 		SELECT port FROM port_allocations
@@ -297,9 +389,13 @@ func (r *PortRepository) findAvailablePort(ctx context.Context, tx *sql.Tx, node
 
 	// Find the first available port in range
 	for port := r.rangeStart; port <= r.rangeEnd; port++ {
-		if !allocated[port] {
-			return port, nil
+		if allocated[port] {
+			continue
+		}
+		if inUse != nil && inUse(port) {
+			continue
 		}
+		return port, nil
 	}
 
 	return 0, ErrNoAvailablePorts
@@ -310,6 +406,7 @@ func (r *PortRepository) scanAllocation(row *sql.Row) (*PortAllocation, error) {
 	var agentID sql.NullString
 	var reason sql.NullString
 	var allocatedAt string
+	var leaseExpiresAt sql.NullString
 
 	err := row.Scan(
 		&alloc.ID,
@@ -318,6 +415,7 @@ func (r *PortRepository) scanAllocation(row *sql.Row) (*PortAllocation, error) {
 		&agentID,
 		&reason,
 		&allocatedAt,
+		&leaseExpiresAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -335,6 +433,11 @@ func (r *PortRepository) scanAllocation(row *sql.Row) (*PortAllocation, error) {
 	if t, err := time.Parse(time.RFC3339, allocatedAt); err == nil {
 		alloc.AllocatedAt = t
 	}
+	if leaseExpiresAt.Valid {
+		if t, err := time.Parse(time.RFC3339, leaseExpiresAt.String); err == nil {
+			alloc.LeaseExpiresAt = &t
+		}
+	}
 
 	return &alloc, nil
 }
@@ -347,6 +450,7 @@ func (r *PortRepository) scanAllocations(rows *sql.Rows) ([]*PortAllocation, err
 		var agentID sql.NullString
 		var reason sql.NullString
 		var allocatedAt string
+		var leaseExpiresAt sql.NullString
 
 		err := rows.Scan(
 			&alloc.ID,
@@ -355,6 +459,7 @@ func (r *PortRepository) scanAllocations(rows *sql.Rows) ([]*PortAllocation, err
 			&agentID,
 			&reason,
 			&allocatedAt,
+			&leaseExpiresAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan port allocation: %w", err)
@@ -369,6 +474,11 @@ func (r *PortRepository) scanAllocations(rows *sql.Rows) ([]*PortAllocation, err
 		if t, err := time.Parse(time.RFC3339, allocatedAt); err == nil {
 			alloc.AllocatedAt = t
 		}
+		if leaseExpiresAt.Valid {
+			if t, err := time.Parse(time.RFC3339, leaseExpiresAt.String); err == nil {
+				alloc.LeaseExpiresAt = &t
+			}
+		}
 
 		allocations = append(allocations, &alloc)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/tOgg1/forge/internal/models"
 )
@@ -466,3 +467,113 @@ func TestPortRepository_NodeIsolation(t *testing.T) {
 		t.Errorf("expected 1 allocation per node, got node1=%d node2=%d", count1, count2)
 	}
 }
+
+func TestPortRepository_AllocateLeaseSkipsPortsInUse(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	node := createTestNode(t, db)
+	repo := NewPortRepositoryWithRange(db, 20000, 20005)
+	ctx := context.Background()
+
+	busy := map[int]bool{20000: true, 20001: true}
+	port, err := repo.AllocateLease(ctx, node.ID, "spawn", time.Minute, func(port int) bool { return busy[port] })
+	if err != nil {
+		t.Fatalf("AllocateLease failed: %v", err)
+	}
+	if port != 20002 {
+		t.Fatalf("expected first port not in use (20002), got %d", port)
+	}
+
+	alloc, err := repo.GetByNodeAndPort(ctx, node.ID, port)
+	if err != nil {
+		t.Fatalf("GetByNodeAndPort failed: %v", err)
+	}
+	if alloc.LeaseExpiresAt == nil || alloc.AgentID != nil {
+		t.Fatalf("expected unbound lease, got %+v", alloc)
+	}
+}
+
+func TestPortRepository_BindClearsLease(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	node := createTestNode(t, db)
+	ws := createTestWorkspace(t, db)
+	agent := createTestAgentForPort(t, db, ws)
+	repo := NewPortRepository(db)
+	ctx := context.Background()
+
+	port, err := repo.AllocateLease(ctx, node.ID, "spawn", time.Minute, nil)
+	if err != nil {
+		t.Fatalf("AllocateLease failed: %v", err)
+	}
+	if err := repo.Bind(ctx, node.ID, port, agent.ID); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	alloc, err := repo.GetByAgent(ctx, agent.ID)
+	if err != nil {
+		t.Fatalf("GetByAgent failed: %v", err)
+	}
+	if alloc.Port != port || alloc.LeaseExpiresAt != nil {
+		t.Fatalf("expected bound allocation without lease, got %+v", alloc)
+	}
+
+	if err := repo.Bind(ctx, node.ID, port+1, agent.ID); err != ErrPortNotAllocated {
+		t.Fatalf("expected ErrPortNotAllocated, got %v", err)
+	}
+}
+
+func TestPortRepository_ReclaimStale(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	node := createTestNode(t, db)
+	ws := createTestWorkspace(t, db)
+	live := createTestAgentForPort(t, db, ws)
+	repo := NewPortRepositoryWithRange(db, 20000, 20010)
+	agentRepo := NewAgentRepository(db)
+	ctx := context.Background()
+
+	livePort, err := repo.Allocate(ctx, node.ID, live.ID, "live")
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+
+	stopped := &models.Agent{WorkspaceID: ws.ID, Type: models.AgentTypeOpenCode, TmuxPane: "forge-test:0.2", State: models.AgentStateStopped}
+	if err := agentRepo.Create(ctx, stopped); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+	if _, err := repo.Allocate(ctx, node.ID, stopped.ID, "stopped"); err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+
+	expiredPort, err := repo.AllocateLease(ctx, node.ID, "expired", time.Minute, nil)
+	if err != nil {
+		t.Fatalf("AllocateLease failed: %v", err)
+	}
+	if _, err := repo.AllocateLease(ctx, node.ID, "pending", time.Minute, nil); err != nil {
+		t.Fatalf("AllocateLease failed: %v", err)
+	}
+	past := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	if _, err := db.ExecContext(ctx, `UPDATE port_allocations SET lease_expires_at = ? WHERE port = ?`, past, expiredPort); err != nil {
+		t.Fatalf("expire lease: %v", err)
+	}
+
+	reclaimed, err := repo.ReclaimStale(ctx)
+	if err != nil {
+		t.Fatalf("ReclaimStale failed: %v", err)
+	}
+	if reclaimed != 2 {
+		t.Fatalf("expected 2 reclaimed (stopped agent + expired lease), got %d", reclaimed)
+	}
+
+	allocs, err := repo.ListActive(ctx)
+	if err != nil {
+		t.Fatalf("ListActive failed: %v", err)
+	}
+	if len(allocs) != 2 || allocs[0].Port != livePort || allocs[1].Reason != "pending" {
+		t.Fatalf("expected live allocation and pending lease to remain, got %+v", allocs)
+	}
+}
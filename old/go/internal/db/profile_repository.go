@@ -31,6 +31,8 @@ func NewProfileRepository(db *DB) *ProfileRepository {
 
 // Create adds a new profile to the database.
 func (r *ProfileRepository) Create(ctx context.Context, profile *models.Profile) error {
+	defer r.db.invalidateCache(cacheProfiles)
+
 	if err := profile.Validate(); err != nil {
 		return fmt.Errorf("invalid profile: %w", err)
 	}
@@ -74,8 +76,9 @@ func (r *ProfileRepository) Create(ctx context.Context, profile *models.Profile)
 			id, name, harness, auth_kind, auth_home,
 			prompt_mode, command_template, model,
 			extra_args_json, env_json, max_concurrency,
+			max_runs_per_hour, min_run_gap_seconds,
 			cooldown_until, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		profile.ID,
 		profile.Name,
@@ -88,6 +91,8 @@ func (r *ProfileRepository) Create(ctx context.Context, profile *models.Profile)
 		extraArgsJSON,
 		envJSON,
 		profile.MaxConcurrency,
+		profile.MaxRunsPerHour,
+		profile.MinRunGapSeconds,
 		cooldownUntil,
 		profile.CreatedAt.Format(time.RFC3339),
 		profile.UpdatedAt.Format(time.RFC3339),
@@ -104,11 +109,18 @@ func (r *ProfileRepository) Create(ctx context.Context, profile *models.Profile)
 
 // Get retrieves a profile by ID.
 func (r *ProfileRepository) Get(ctx context.Context, id string) (*models.Profile, error) {
+	return cachedOne(r.db.repoCache(), cacheProfiles, "id:"+id, cloneProfile, func() (*models.Profile, error) {
+		return r.get(ctx, id)
+	})
+}
+
+func (r *ProfileRepository) get(ctx context.Context, id string) (*models.Profile, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
 			id, name, harness, auth_kind, auth_home,
 			prompt_mode, command_template, model,
 			extra_args_json, env_json, max_concurrency,
+			max_runs_per_hour, min_run_gap_seconds,
 			cooldown_until, created_at, updated_at
 		FROM profiles WHERE id = ?
 	`, id)
@@ -118,11 +130,18 @@ func (r *ProfileRepository) Get(ctx context.Context, id string) (*models.Profile
 
 // GetByName retrieves a profile by name.
 func (r *ProfileRepository) GetByName(ctx context.Context, name string) (*models.Profile, error) {
+	return cachedOne(r.db.repoCache(), cacheProfiles, "name:"+name, cloneProfile, func() (*models.Profile, error) {
+		return r.getByName(ctx, name)
+	})
+}
+
+func (r *ProfileRepository) getByName(ctx context.Context, name string) (*models.Profile, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT
 			id, name, harness, auth_kind, auth_home,
 			prompt_mode, command_template, model,
 			extra_args_json, env_json, max_concurrency,
+			max_runs_per_hour, min_run_gap_seconds,
 			cooldown_until, created_at, updated_at
 		FROM profiles WHERE name = ?
 	`, name)
@@ -132,11 +151,18 @@ func (r *ProfileRepository) GetByName(ctx context.Context, name string) (*models
 
 // List retrieves all profiles.
 func (r *ProfileRepository) List(ctx context.Context) ([]*models.Profile, error) {
+	return cachedList(r.db.repoCache(), cacheProfiles, "list", cloneProfile, func() ([]*models.Profile, error) {
+		return r.list(ctx)
+	})
+}
+
+func (r *ProfileRepository) list(ctx context.Context) ([]*models.Profile, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
 			id, name, harness, auth_kind, auth_home,
 			prompt_mode, command_template, model,
 			extra_args_json, env_json, max_concurrency,
+			max_runs_per_hour, min_run_gap_seconds,
 			cooldown_until, created_at, updated_at
 		FROM profiles
 		ORDER BY name
@@ -160,6 +186,8 @@ func (r *ProfileRepository) List(ctx context.Context) ([]*models.Profile, error)
 
 // Update updates a profile.
 func (r *ProfileRepository) Update(ctx context.Context, profile *models.Profile) error {
+	defer r.db.invalidateCache(cacheProfiles)
+
 	if err := profile.Validate(); err != nil {
 		return fmt.Errorf("invalid profile: %w", err)
 	}
@@ -193,6 +221,7 @@ func (r *ProfileRepository) Update(ctx context.Context, profile *models.Profile)
 		SET name = ?, harness = ?, auth_kind = ?, auth_home = ?,
 			prompt_mode = ?, command_template = ?, model = ?,
 			extra_args_json = ?, env_json = ?, max_concurrency = ?,
+			max_runs_per_hour = ?, min_run_gap_seconds = ?,
 			cooldown_until = ?, updated_at = ?
 		WHERE id = ?
 	`,
@@ -206,6 +235,8 @@ func (r *ProfileRepository) Update(ctx context.Context, profile *models.Profile)
 		extraArgsJSON,
 		envJSON,
 		profile.MaxConcurrency,
+		profile.MaxRunsPerHour,
+		profile.MinRunGapSeconds,
 		cooldownUntil,
 		profile.UpdatedAt.Format(time.RFC3339),
 		profile.ID,
@@ -224,6 +255,8 @@ func (r *ProfileRepository) Update(ctx context.Context, profile *models.Profile)
 
 // Delete removes a profile.
 func (r *ProfileRepository) Delete(ctx context.Context, id string) error {
+	defer r.db.invalidateCache(cacheProfiles)
+
 	result, err := r.db.ExecContext(ctx, `DELETE FROM profiles WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete profile: %w", err)
@@ -237,6 +270,8 @@ func (r *ProfileRepository) Delete(ctx context.Context, id string) error {
 
 // SetCooldown sets cooldown_until for a profile.
 func (r *ProfileRepository) SetCooldown(ctx context.Context, id string, until *time.Time) error {
+	defer r.db.invalidateCache(cacheProfiles)
+
 	updatedAt := time.Now().UTC()
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE profiles
@@ -268,6 +303,8 @@ func (r *ProfileRepository) scanProfile(scanner interface {
 		extraArgsJSON   sql.NullString
 		envJSON         sql.NullString
 		maxConcurrency  int
+		maxRunsPerHour  int
+		minRunGap       int
 		cooldownUntil   sql.NullString
 		createdAt       string
 		updatedAt       string
@@ -285,6 +322,8 @@ func (r *ProfileRepository) scanProfile(scanner interface {
 		&extraArgsJSON,
 		&envJSON,
 		&maxConcurrency,
+		&maxRunsPerHour,
+		&minRunGap,
 		&cooldownUntil,
 		&createdAt,
 		&updatedAt,
@@ -296,15 +335,17 @@ func (r *ProfileRepository) scanProfile(scanner interface {
 	}
 
 	profile := &models.Profile{
-		ID:              id,
-		Name:            name,
-		Harness:         models.Harness(harness),
-		AuthKind:        authKind.String,
-		AuthHome:        authHome.String,
-		PromptMode:      models.PromptMode(promptMode),
-		CommandTemplate: commandTemplate,
-		Model:           model.String,
-		MaxConcurrency:  maxConcurrency,
+		ID:               id,
+		Name:             name,
+		Harness:          models.Harness(harness),
+		AuthKind:         authKind.String,
+		AuthHome:         authHome.String,
+		PromptMode:       models.PromptMode(promptMode),
+		CommandTemplate:  commandTemplate,
+		Model:            model.String,
+		MaxConcurrency:   maxConcurrency,
+		MaxRunsPerHour:   maxRunsPerHour,
+		MinRunGapSeconds: minRunGap,
 	}
 
 	if extraArgsJSON.Valid && extraArgsJSON.String != "" {
@@ -59,11 +59,16 @@ func (r *QueueRepository) Enqueue(ctx context.Context, agentID string, items ...
 		if item.Status == "" {
 			item.Status = models.QueueItemStatusPending
 		}
-		_, err := r.db.ExecContext(ctx, `
+		retryPolicyJSON, err := marshalRetryPolicy(item.RetryPolicy)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.ExecContext(ctx, `
 			INSERT INTO queue_items (
 				id, agent_id, type, position, status, attempts, payload_json,
-				error_message, created_at, dispatched_at, completed_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				error_message, created_at, dispatched_at, completed_at,
+				deadline, deadline_missed, retry_policy_json, dead_lettered_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`,
 			item.ID,
 			item.AgentID,
@@ -76,6 +81,10 @@ func (r *QueueRepository) Enqueue(ctx context.Context, agentID string, items ...
 			item.CreatedAt.Format(time.RFC3339),
 			stringTimePtr(item.DispatchedAt),
 			stringTimePtr(item.CompletedAt),
+			stringTimePtr(item.Deadline),
+			boolToInt(item.DeadlineMissed),
+			retryPolicyJSON,
+			stringTimePtr(item.DeadLetteredAt),
 		)
 
 		if err != nil {
@@ -93,13 +102,14 @@ func (r *QueueRepository) Dequeue(ctx context.Context, agentID string) (*models.
 		return nil, err
 	}
 
-	// Update the item status to dispatched
+	// Update the item status to dispatched, recording a missed deadline.
 	now := time.Now().UTC()
+	missed := item.DeadlineMissed || (item.Deadline != nil && now.After(*item.Deadline))
 	_, err = r.db.ExecContext(ctx, `
 		UPDATE queue_items 
-		SET status = ?, dispatched_at = ?
+		SET status = ?, dispatched_at = ?, deadline_missed = ?
 		WHERE id = ?
-	`, string(models.QueueItemStatusDispatched), now.Format(time.RFC3339), item.ID)
+	`, string(models.QueueItemStatusDispatched), now.Format(time.RFC3339), boolToInt(missed), item.ID)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to update queue item status: %w", err)
@@ -107,6 +117,7 @@ func (r *QueueRepository) Dequeue(ctx context.Context, agentID string) (*models.
 
 	item.Status = models.QueueItemStatusDispatched
 	item.DispatchedAt = &now
+	item.DeadlineMissed = missed
 
 	return item, nil
 }
@@ -116,7 +127,8 @@ func (r *QueueRepository) Peek(ctx context.Context, agentID string) (*models.Que
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
 			id, agent_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
 		FROM queue_items
 		WHERE agent_id = ? AND status = ?
 		ORDER BY position ASC
@@ -139,7 +151,8 @@ func (r *QueueRepository) List(ctx context.Context, agentID string) ([]*models.Q
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
 			id, agent_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
 		FROM queue_items
 		WHERE agent_id = ?
 		ORDER BY position ASC
@@ -158,7 +171,8 @@ func (r *QueueRepository) ListPending(ctx context.Context, agentID string) ([]*m
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
 			id, agent_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
 		FROM queue_items
 		WHERE agent_id = ? AND status = ?
 		ORDER BY position ASC
@@ -293,12 +307,17 @@ func (r *QueueRepository) InsertAt(ctx context.Context, agentID string, position
 	if item.Status == "" {
 		item.Status = models.QueueItemStatusPending
 	}
+	retryPolicyJSON, err := marshalRetryPolicy(item.RetryPolicy)
+	if err != nil {
+		return err
+	}
 
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO queue_items (
 			id, agent_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		item.ID,
 		item.AgentID,
@@ -311,6 +330,10 @@ func (r *QueueRepository) InsertAt(ctx context.Context, agentID string, position
 		item.CreatedAt.Format(time.RFC3339),
 		stringTimePtr(item.DispatchedAt),
 		stringTimePtr(item.CompletedAt),
+		stringTimePtr(item.Deadline),
+		boolToInt(item.DeadlineMissed),
+		retryPolicyJSON,
+		stringTimePtr(item.DeadLetteredAt),
 	)
 
 	if err != nil {
@@ -344,7 +367,8 @@ func (r *QueueRepository) Get(ctx context.Context, id string) (*models.QueueItem
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
 			id, agent_id, type, position, status, attempts, payload_json,
-			error_message, created_at, dispatched_at, completed_at
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
 		FROM queue_items WHERE id = ?
 	`, id)
 
@@ -410,6 +434,94 @@ func (r *QueueRepository) UpdateAttempts(ctx context.Context, id string, attempt
 	return nil
 }
 
+// DeadLetter marks an item as failed for good after its retries ran out,
+// moving it to the dead-letter queue.
+func (r *QueueRepository) DeadLetter(ctx context.Context, id string, errorMsg string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE queue_items
+		SET status = ?, error_message = ?, completed_at = ?, dead_lettered_at = ?
+		WHERE id = ?
+	`, string(models.QueueItemStatusFailed), errorMsg, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter queue item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrQueueItemNotFound
+	}
+
+	return nil
+}
+
+// ListDeadLetters returns dead-lettered items, most recent first. An empty
+// agentID lists every agent's items.
+func (r *QueueRepository) ListDeadLetters(ctx context.Context, agentID string) ([]*models.QueueItem, error) {
+	query := `
+		SELECT 
+			id, agent_id, type, position, status, attempts, payload_json,
+			error_message, created_at, dispatched_at, completed_at,
+			deadline, deadline_missed, retry_policy_json, dead_lettered_at
+		FROM queue_items
+		WHERE dead_lettered_at IS NOT NULL`
+	args := []any{}
+	if agentID != "" {
+		query += " AND agent_id = ?"
+		args = append(args, agentID)
+	}
+	query += " ORDER BY dead_lettered_at DESC, position ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead-lettered queue items: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanQueueItems(rows)
+}
+
+// Requeue moves a dead-lettered item back to the end of its agent's queue
+// with a fresh attempt count. It returns ErrQueueItemNotFound when the item
+// does not exist or is not dead-lettered.
+func (r *QueueRepository) Requeue(ctx context.Context, id string) (*models.QueueItem, error) {
+	item, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.DeadLetteredAt == nil {
+		return nil, ErrQueueItemNotFound
+	}
+
+	maxPos, err := r.getMaxPosition(ctx, item.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE queue_items
+		SET status = ?, attempts = 0, position = ?, error_message = NULL,
+			dispatched_at = NULL, completed_at = NULL, dead_lettered_at = NULL
+		WHERE id = ?
+	`, string(models.QueueItemStatusPending), maxPos+1, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue queue item: %w", err)
+	}
+
+	item.Status = models.QueueItemStatusPending
+	item.Attempts = 0
+	item.Position = maxPos + 1
+	item.Error = ""
+	item.DispatchedAt = nil
+	item.CompletedAt = nil
+	item.DeadLetteredAt = nil
+	return item, nil
+}
+
 // Count returns the number of pending items in an agent's queue.
 func (r *QueueRepository) Count(ctx context.Context, agentID string) (int, error) {
 	var count int
@@ -451,7 +563,9 @@ func (r *QueueRepository) scanQueueItem(row *sql.Row) (*models.QueueItem, error)
 	var payloadJSON string
 	var errorMsg sql.NullString
 	var createdAt string
-	var dispatchedAt, completedAt sql.NullString
+	var dispatchedAt, completedAt, deadline sql.NullString
+	var deadlineMissed int
+	var retryPolicyJSON, deadLetteredAt sql.NullString
 
 	err := row.Scan(
 		&item.ID,
@@ -465,6 +579,10 @@ func (r *QueueRepository) scanQueueItem(row *sql.Row) (*models.QueueItem, error)
 		&createdAt,
 		&dispatchedAt,
 		&completedAt,
+		&deadline,
+		&deadlineMissed,
+		&retryPolicyJSON,
+		&deadLetteredAt,
 	)
 
 	if err != nil {
@@ -496,6 +614,15 @@ func (r *QueueRepository) scanQueueItem(row *sql.Row) (*models.QueueItem, error)
 			item.CompletedAt = &t
 		}
 	}
+	if deadline.Valid {
+		if t, err := time.Parse(time.RFC3339, deadline.String); err == nil {
+			item.Deadline = &t
+		}
+	}
+	item.DeadlineMissed = deadlineMissed != 0
+	if err := applyRetryColumns(&item, retryPolicyJSON, deadLetteredAt); err != nil {
+		return nil, err
+	}
 
 	return &item, nil
 }
@@ -511,7 +638,9 @@ func (r *QueueRepository) scanQueueItems(rows *sql.Rows) ([]*models.QueueItem, e
 		var payloadJSON string
 		var errorMsg sql.NullString
 		var createdAt string
-		var dispatchedAt, completedAt sql.NullString
+		var dispatchedAt, completedAt, deadline sql.NullString
+		var deadlineMissed int
+		var retryPolicyJSON, deadLetteredAt sql.NullString
 
 		err := rows.Scan(
 			&item.ID,
@@ -525,6 +654,10 @@ func (r *QueueRepository) scanQueueItems(rows *sql.Rows) ([]*models.QueueItem, e
 			&createdAt,
 			&dispatchedAt,
 			&completedAt,
+			&deadline,
+			&deadlineMissed,
+			&retryPolicyJSON,
+			&deadLetteredAt,
 		)
 
 		if err != nil {
@@ -553,6 +686,15 @@ func (r *QueueRepository) scanQueueItems(rows *sql.Rows) ([]*models.QueueItem, e
 				item.CompletedAt = &t
 			}
 		}
+		if deadline.Valid {
+			if t, err := time.Parse(time.RFC3339, deadline.String); err == nil {
+				item.Deadline = &t
+			}
+		}
+		item.DeadlineMissed = deadlineMissed != 0
+		if err := applyRetryColumns(&item, retryPolicyJSON, deadLetteredAt); err != nil {
+			return nil, err
+		}
 
 		items = append(items, &item)
 	}
@@ -563,3 +705,32 @@ func (r *QueueRepository) scanQueueItems(rows *sql.Rows) ([]*models.QueueItem, e
 
 	return items, nil
 }
+
+func marshalRetryPolicy(policy *models.RetryPolicy) (*string, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal retry policy: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// applyRetryColumns decodes the retry policy and dead-letter columns.
+func applyRetryColumns(item *models.QueueItem, retryPolicyJSON, deadLetteredAt sql.NullString) error {
+	if retryPolicyJSON.Valid && retryPolicyJSON.String != "" {
+		var policy models.RetryPolicy
+		if err := json.Unmarshal([]byte(retryPolicyJSON.String), &policy); err != nil {
+			return fmt.Errorf("failed to parse retry policy for queue item %s: %w", item.ID, err)
+		}
+		item.RetryPolicy = &policy
+	}
+	if deadLetteredAt.Valid {
+		if t, err := time.Parse(time.RFC3339, deadLetteredAt.String); err == nil {
+			item.DeadLetteredAt = &t
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tOgg1/forge/internal/models"
@@ -295,3 +296,49 @@ func TestQueueRepository_UpdateAttempts(t *testing.T) {
 		t.Fatalf("expected attempts 2, got %d", updated.Attempts)
 	}
 }
+
+func TestQueueRepository_DeadlineMissedOnLateDequeue(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ws := createTestWorkspace(t, db)
+	agent := createTestAgent(t, db, ws)
+	repo := NewQueueRepository(db)
+	ctx := context.Background()
+
+	past := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	future := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	late := newMessageItem(t, "late")
+	late.Deadline = &past
+	onTime := newMessageItem(t, "on time")
+	onTime.Deadline = &future
+
+	if err := repo.Enqueue(ctx, agent.ID, late, onTime); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	peeked, err := repo.Peek(ctx, agent.ID)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if peeked.Deadline == nil || !peeked.Deadline.Equal(past) || peeked.DeadlineMissed {
+		t.Fatalf("expected pending deadline %v, got %v missed=%v", past, peeked.Deadline, peeked.DeadlineMissed)
+	}
+
+	for _, want := range []bool{true, false} {
+		item, err := repo.Dequeue(ctx, agent.ID)
+		if err != nil {
+			t.Fatalf("Dequeue failed: %v", err)
+		}
+		if item.DeadlineMissed != want {
+			t.Fatalf("item %s: expected missed=%v", item.ID, want)
+		}
+		stored, err := repo.Get(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if stored.DeadlineMissed != want {
+			t.Fatalf("stored item %s: expected missed=%v", item.ID, want)
+		}
+	}
+}
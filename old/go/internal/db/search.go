@@ -0,0 +1,52 @@
+package db
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrEmptySearch is returned when a search has no terms.
+var ErrEmptySearch = errors.New("search text is required")
+
+// Markers placed around matched terms in search snippets.
+const (
+	SnippetOpen     = "«"
+	SnippetClose    = "»"
+	SnippetEllipsis = "…"
+)
+
+// snippetTokens is the number of tokens in a search snippet.
+const snippetTokens = 16
+
+// SearchMatchQuery turns free text into an FTS5 match expression. Each
+// whitespace-separated term is quoted, so punctuation in terms such as
+// "agent.state_changed" is matched rather than parsed as query syntax; all
+// terms must match, and the last one also matches as a prefix so results
+// can update while the text is being typed.
+func SearchMatchQuery(text string) (string, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return "", ErrEmptySearch
+	}
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	quoted[len(quoted)-1] += "*"
+	return strings.Join(quoted, " "), nil
+}
+
+// extraColumns scans trailing columns a shared scan helper doesn't know
+// about, such as a search snippet.
+type extraColumns struct {
+	scanner interface{ Scan(...any) error }
+	dest    []any
+}
+
+func withExtraColumns(scanner interface{ Scan(...any) error }, dest ...any) extraColumns {
+	return extraColumns{scanner: scanner, dest: dest}
+}
+
+func (s extraColumns) Scan(dest ...any) error {
+	return s.scanner.Scan(append(dest, s.dest...)...)
+}
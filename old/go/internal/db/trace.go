@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/tracing"
+)
+
+// TraceIDMetadataKey is the metadata key under which events and loop runs
+// record the trace that produced them.
+const TraceIDMetadataKey = "trace_id"
+
+// maxTracedStatement bounds the SQL text recorded on query spans.
+const maxTracedStatement = 200
+
+// ExecContext executes a statement, recording a span when the caller is traced.
+// Statements that fail with SQLITE_BUSY are retried per the busy retry policy.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	var result sql.Result
+	err := db.retryBusy(ctx, func() error {
+		var execErr error
+		result, execErr = db.DB.ExecContext(ctx, query, args...)
+		return execErr
+	})
+	span.RecordError(err)
+	span.End()
+	return result, err
+}
+
+// QueryContext runs a query, recording a span when the caller is traced. The
+// span covers execution only, not row iteration, and so does busy retry.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	var rows *sql.Rows
+	err := db.retryBusy(ctx, func() error {
+		var queryErr error
+		rows, queryErr = db.DB.QueryContext(ctx, query, args...)
+		return queryErr
+	})
+	span.RecordError(err)
+	span.End()
+	return rows, err
+}
+
+// QueryRowContext runs a single-row query, recording a span when the caller is
+// traced. A query that fails with SQLITE_BUSY before returning a row is
+// retried per the busy retry policy.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	var row *sql.Row
+	_ = db.retryBusy(ctx, func() error {
+		row = db.DB.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	if row == nil {
+		// The context was already done; let database/sql report it on Scan.
+		row = db.DB.QueryRowContext(ctx, query, args...)
+	}
+	span.End()
+	return row
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	return tracing.StartChild(ctx, "db.query",
+		tracing.String("db.system", "sqlite"),
+		tracing.String("db.statement", traceStatement(query)),
+	)
+}
+
+// traceStatement collapses whitespace and truncates long SQL for span attributes.
+func traceStatement(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxTracedStatement {
+		query = query[:maxTracedStatement] + "..."
+	}
+	return query
+}
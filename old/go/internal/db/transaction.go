@@ -24,10 +24,18 @@ func (db *DB) TransactionWithRetry(ctx context.Context, maxAttempts int, baseBac
 	}
 
 	return withRetry(ctx, maxAttempts, baseBackoff, func() error {
-		return db.Transaction(ctx, fn)
+		return db.transaction(ctx, fn)
 	})
 }
 
+// retryBusy runs fn with the connection's configured busy retry policy.
+func (db *DB) retryBusy(ctx context.Context, fn func() error) error {
+	if db.busyRetries <= 1 {
+		return fn()
+	}
+	return withRetry(ctx, db.busyRetries, db.busyBackoff, fn)
+}
+
 func withRetry(ctx context.Context, maxAttempts int, baseBackoff time.Duration, fn func() error) error {
 	attempt := 0
 	backoff := baseBackoff
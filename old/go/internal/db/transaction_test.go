@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -84,3 +86,119 @@ func TestTransactionWithRetry(t *testing.T) {
 		t.Fatalf("expected 2 attempts, got %d", attempts)
 	}
 }
+
+func TestBuildDSNAppliesPragmas(t *testing.T) {
+	dsn := buildDSN(Config{Path: "/tmp/forge.db", BusyTimeoutMs: 250, JournalMode: "wal", Synchronous: "full"})
+	for _, want := range []string{"busy_timeout(250)", "journal_mode(WAL)", "synchronous(FULL)", "foreign_keys(ON)"} {
+		if !strings.Contains(dsn, want) {
+			t.Fatalf("expected dsn %q to contain %q", dsn, want)
+		}
+	}
+
+	dsn = buildDSN(Config{Path: "/tmp/forge.db"})
+	if !strings.Contains(dsn, "journal_mode(WAL)") || !strings.Contains(dsn, "synchronous(NORMAL)") {
+		t.Fatalf("expected WAL/NORMAL defaults, got %q", dsn)
+	}
+}
+
+func TestOpenRetriesBusyStatements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forge.db")
+	cfg := DefaultConfig()
+	cfg.Path = path
+	cfg.BusyTimeoutMs = 1
+	cfg.BusyRetries = 5
+	cfg.BusyRetryBackoff = 20 * time.Millisecond
+
+	writer, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open writer: %v", err)
+	}
+	defer writer.Close()
+	reader, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open reader: %v", err)
+	}
+	defer reader.Close()
+
+	ctx := context.Background()
+	if _, err := writer.ExecContext(ctx, "CREATE TABLE t (v INTEGER)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	// Hold the write lock briefly from another connection.
+	lock, err := writer.DB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := lock.ExecContext(ctx, "INSERT INTO t (v) VALUES (1)"); err != nil {
+		t.Fatalf("insert under lock: %v", err)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = lock.Commit()
+	}()
+
+	if _, err := reader.ExecContext(ctx, "INSERT INTO t (v) VALUES (2)"); err != nil {
+		t.Fatalf("expected busy insert to succeed after retry, got %v", err)
+	}
+}
+
+func TestOpenReadOnlyRejectsWritesAndMigrations(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "forge db.sqlite")
+	dbPath := cfg.Path
+
+	writer, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open writer: %v", err)
+	}
+	defer writer.Close()
+	if _, err := writer.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	cfg.ReadOnly = true
+	reader, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open reader: %v", err)
+	}
+	defer reader.Close()
+	if !reader.ReadOnly() {
+		t.Fatalf("expected reader to report read-only")
+	}
+
+	if _, err := writer.ExecContext(ctx, "INSERT INTO nodes (id, name) VALUES ('n1', 'local')"); err != nil {
+		t.Fatalf("writer insert: %v", err)
+	}
+	var count int
+	if err := reader.QueryRowContext(ctx, "SELECT COUNT(*) FROM nodes").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("expected reader to see the writer's row, got %d (%v)", count, err)
+	}
+
+	_, err = reader.ExecContext(ctx, "INSERT INTO nodes (id, name) VALUES ('n2', 'other')")
+	if !IsReadOnlyError(err) {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+	if _, err := reader.MigrateUp(ctx); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly from MigrateUp, got %v", err)
+	}
+
+	cfg.Path = filepath.Join(t.TempDir(), "missing.db")
+	if missing, err := Open(cfg); err == nil {
+		missing.Close()
+		t.Fatalf("expected read-only open of a missing file to fail")
+	}
+
+	// database.path is not made absolute by the config loader.
+	t.Chdir(filepath.Dir(dbPath))
+	cfg.Path = filepath.Base(dbPath)
+	relative, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open reader with relative path: %v", err)
+	}
+	defer relative.Close()
+	if err := relative.QueryRowContext(ctx, "SELECT COUNT(*) FROM nodes").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("expected relative-path reader to see the writer's row, got %d (%v)", count, err)
+	}
+}
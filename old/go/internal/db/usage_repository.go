@@ -227,6 +227,41 @@ func (r *UsageRepository) SummarizeByAccount(ctx context.Context, accountID stri
 	return &summary, nil
 }
 
+// SummarizeByAccountRef returns aggregated usage since a time for an
+// account referenced either by its ID or by its provider profile name, so
+// callers that key accounts by profile name see the same totals.
+func (r *UsageRepository) SummarizeByAccountRef(ctx context.Context, provider models.Provider, ref string, since time.Time) (*models.UsageSummary, error) {
+	var summary models.UsageSummary
+	err := r.db.QueryRowContext(ctx, `SELECT
+		COALESCE(SUM(input_tokens), 0),
+		COALESCE(SUM(output_tokens), 0),
+		COALESCE(SUM(total_tokens), 0),
+		COALESCE(SUM(cost_cents), 0),
+		COALESCE(SUM(request_count), 0),
+		COUNT(*)
+		FROM usage_records
+		WHERE (account_id = ? OR account_id IN (SELECT id FROM accounts WHERE provider = ? AND profile_name = ?))
+		AND recorded_at >= ?`,
+		ref, string(provider), ref, since.UTC().Format(time.RFC3339),
+	).Scan(
+		&summary.InputTokens,
+		&summary.OutputTokens,
+		&summary.TotalTokens,
+		&summary.TotalCostCents,
+		&summary.RequestCount,
+		&summary.RecordCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize usage: %w", err)
+	}
+
+	summary.AccountID = ref
+	summary.Provider = provider
+	summary.Period = "custom"
+	summary.PeriodStart = since
+	return &summary, nil
+}
+
 // SummarizeByProvider returns aggregated usage for a provider.
 func (r *UsageRepository) SummarizeByProvider(ctx context.Context, provider models.Provider, since, until *time.Time) (*models.UsageSummary, error) {
 	query := `SELECT 
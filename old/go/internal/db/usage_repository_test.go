@@ -204,6 +204,59 @@ func TestUsageRepositorySummarizeByAccount(t *testing.T) {
 	}
 }
 
+func TestUsageRepositorySummarizeByAccountRef(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+
+	if _, err := database.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	accountRepo := NewAccountRepository(database)
+	account := &models.Account{Provider: models.ProviderAnthropic, ProfileName: "work"}
+	if err := accountRepo.Create(ctx, account); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+
+	repo := NewUsageRepository(database)
+	now := time.Now().UTC()
+	for _, recordedAt := range []time.Time{now.Add(-2 * time.Hour), now.Add(-10 * time.Minute)} {
+		record := &models.UsageRecord{
+			AccountID:    account.ID,
+			Provider:     models.ProviderAnthropic,
+			InputTokens:  100,
+			RequestCount: 1,
+			RecordedAt:   recordedAt,
+		}
+		if err := repo.Create(ctx, record); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	for _, ref := range []string{account.ID, "work"} {
+		summary, err := repo.SummarizeByAccountRef(ctx, models.ProviderAnthropic, ref, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("SummarizeByAccountRef(%s): %v", ref, err)
+		}
+		if summary.TotalTokens != 100 || summary.RequestCount != 1 {
+			t.Errorf("ref %s: expected last hour only, got %+v", ref, summary)
+		}
+	}
+
+	summary, err := repo.SummarizeByAccountRef(ctx, models.ProviderOpenAI, "work", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("SummarizeByAccountRef: %v", err)
+	}
+	if summary.RecordCount != 0 {
+		t.Errorf("expected other provider to match nothing, got %+v", summary)
+	}
+}
+
 func TestUsageRepositorySummarizeAll(t *testing.T) {
 	ctx := context.Background()
 
@@ -50,6 +50,11 @@ func (r *WorkspaceRepository) Create(ctx context.Context, workspace *models.Work
 	if workspace.Name == "" {
 		workspace.Name = workspace.TmuxSession
 	}
+	namespace, err := r.db.createNamespace(ctx, workspace.Namespace)
+	if err != nil {
+		return fmt.Errorf("invalid workspace: %w", err)
+	}
+	workspace.Namespace = namespace
 
 	var gitInfoJSON *string
 	if workspace.GitInfo != nil {
@@ -61,14 +66,15 @@ func (r *WorkspaceRepository) Create(ctx context.Context, workspace *models.Work
 		gitInfoJSON = &s
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err = r.db.ExecContext(ctx, `
 		INSERT INTO workspaces (
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		workspace.ID,
 		workspace.Name,
+		workspace.Namespace,
 		workspace.NodeID,
 		workspace.RepoPath,
 		workspace.TmuxSession,
@@ -90,60 +96,66 @@ func (r *WorkspaceRepository) Create(ctx context.Context, workspace *models.Work
 
 // Get retrieves a workspace by ID.
 func (r *WorkspaceRepository) Get(ctx context.Context, id string) (*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
 		FROM workspaces WHERE id = ?
-	`, id)
+	`+filter, append([]any{id}, filterArgs...)...)
 
 	return r.scanWorkspace(row)
 }
 
 // GetByNodeAndPath retrieves a workspace by node ID and repo path.
 func (r *WorkspaceRepository) GetByNodeAndPath(ctx context.Context, nodeID, repoPath string) (*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
 		FROM workspaces WHERE node_id = ? AND repo_path = ?
-	`, nodeID, repoPath)
+	`+filter, append([]any{nodeID, repoPath}, filterArgs...)...)
 
 	return r.scanWorkspace(row)
 }
 
 // GetByTmuxSession retrieves a workspace by node ID and tmux session name.
 func (r *WorkspaceRepository) GetByTmuxSession(ctx context.Context, nodeID, sessionName string) (*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
 		FROM workspaces WHERE node_id = ? AND tmux_session = ?
-	`, nodeID, sessionName)
+	`+filter, append([]any{nodeID, sessionName}, filterArgs...)...)
 
 	return r.scanWorkspace(row)
 }
 
 // GetByName retrieves a workspace by name.
 func (r *WorkspaceRepository) GetByName(ctx context.Context, name string) (*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	row := r.db.QueryRowContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
 		FROM workspaces WHERE name = ?
-	`, name)
+	`+filter, append([]any{name}, filterArgs...)...)
 
 	return r.scanWorkspace(row)
 }
 
 // List retrieves all workspaces.
 func (r *WorkspaceRepository) List(ctx context.Context) ([]*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
-		FROM workspaces ORDER BY name
-	`)
+		FROM workspaces`+filter+`
+		ORDER BY name
+	`, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workspaces: %w", err)
 	}
@@ -154,12 +166,14 @@ func (r *WorkspaceRepository) List(ctx context.Context) ([]*models.Workspace, er
 
 // ListByNode retrieves all workspaces for a specific node.
 func (r *WorkspaceRepository) ListByNode(ctx context.Context, nodeID string) ([]*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
-		FROM workspaces WHERE node_id = ? ORDER BY name
-	`, nodeID)
+		FROM workspaces WHERE node_id = ?`+filter+`
+		ORDER BY name
+	`, append([]any{nodeID}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workspaces by node: %w", err)
 	}
@@ -170,12 +184,14 @@ func (r *WorkspaceRepository) ListByNode(ctx context.Context, nodeID string) ([]
 
 // ListByStatus retrieves workspaces with a specific status.
 func (r *WorkspaceRepository) ListByStatus(ctx context.Context, status models.WorkspaceStatus) ([]*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
-			id, name, node_id, repo_path, tmux_session, status,
+			id, name, namespace, node_id, repo_path, tmux_session, status,
 			git_info_json, created_at, updated_at
-		FROM workspaces WHERE status = ? ORDER BY name
-	`, string(status))
+		FROM workspaces WHERE status = ?`+filter+`
+		ORDER BY name
+	`, append([]any{string(status)}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workspaces by status: %w", err)
 	}
@@ -186,9 +202,10 @@ func (r *WorkspaceRepository) ListByStatus(ctx context.Context, status models.Wo
 
 // ListWithAgentCounts retrieves all workspaces with their agent counts.
 func (r *WorkspaceRepository) ListWithAgentCounts(ctx context.Context) ([]*models.Workspace, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "w.namespace")
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
-			w.id, w.name, w.node_id, w.repo_path, w.tmux_session, w.status,
+			w.id, w.name, w.namespace, w.node_id, w.repo_path, w.tmux_session, w.status,
 			w.git_info_json, w.created_at, w.updated_at,
 			COUNT(a.id) as agent_count,
 			COALESCE(SUM(CASE WHEN a.state IN ('working', 'starting') THEN 1 ELSE 0 END), 0) as working,
@@ -196,10 +213,10 @@ func (r *WorkspaceRepository) ListWithAgentCounts(ctx context.Context) ([]*model
 			COALESCE(SUM(CASE WHEN a.state IN ('awaiting_approval', 'rate_limited', 'paused') THEN 1 ELSE 0 END), 0) as blocked,
 			COALESCE(SUM(CASE WHEN a.state = 'error' THEN 1 ELSE 0 END), 0) as error
 		FROM workspaces w
-		LEFT JOIN agents a ON w.id = a.workspace_id
+		LEFT JOIN agents a ON w.id = a.workspace_id`+filter+`
 		GROUP BY w.id
 		ORDER BY w.name
-	`)
+	`, filterArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workspaces with agent counts: %w", err)
 	}
@@ -210,6 +227,7 @@ func (r *WorkspaceRepository) ListWithAgentCounts(ctx context.Context) ([]*model
 
 // Update updates an existing workspace.
 func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Workspace) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	if err := workspace.Validate(); err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
@@ -235,8 +253,7 @@ func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Work
 			status = ?,
 			git_info_json = ?,
 			updated_at = ?
-		WHERE id = ?
-	`,
+		WHERE id = ?`+filter, append([]any{
 		workspace.Name,
 		workspace.NodeID,
 		workspace.RepoPath,
@@ -245,7 +262,7 @@ func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Work
 		gitInfoJSON,
 		workspace.UpdatedAt.Format(time.RFC3339),
 		workspace.ID,
-	)
+	}, filterArgs...)...)
 
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -268,12 +285,12 @@ func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Work
 
 // UpdateStatus updates just the status of a workspace.
 func (r *WorkspaceRepository) UpdateStatus(ctx context.Context, id string, status models.WorkspaceStatus) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE workspaces SET status = ?, updated_at = ?
-		WHERE id = ?
-	`, string(status), now, id)
+		WHERE id = ?`+filter, append([]any{string(status), now, id}, filterArgs...)...)
 
 	if err != nil {
 		return fmt.Errorf("failed to update workspace status: %w", err)
@@ -293,6 +310,7 @@ func (r *WorkspaceRepository) UpdateStatus(ctx context.Context, id string, statu
 
 // UpdateGitInfo updates just the git info of a workspace.
 func (r *WorkspaceRepository) UpdateGitInfo(ctx context.Context, id string, gitInfo *models.GitInfo) error {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	var gitInfoJSON *string
@@ -307,8 +325,7 @@ func (r *WorkspaceRepository) UpdateGitInfo(ctx context.Context, id string, gitI
 
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE workspaces SET git_info_json = ?, updated_at = ?
-		WHERE id = ?
-	`, gitInfoJSON, now, id)
+		WHERE id = ?`+filter, append([]any{gitInfoJSON, now, id}, filterArgs...)...)
 
 	if err != nil {
 		return fmt.Errorf("failed to update workspace git info: %w", err)
@@ -328,7 +345,8 @@ func (r *WorkspaceRepository) UpdateGitInfo(ctx context.Context, id string, gitI
 
 // Delete removes a workspace by ID.
 func (r *WorkspaceRepository) Delete(ctx context.Context, id string) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM workspaces WHERE id = ?", id)
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM workspaces WHERE id = ?"+filter, append([]any{id}, filterArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete workspace: %w", err)
 	}
@@ -347,10 +365,10 @@ func (r *WorkspaceRepository) Delete(ctx context.Context, id string) error {
 
 // GetAgentCount returns the number of agents in a workspace.
 func (r *WorkspaceRepository) GetAgentCount(ctx context.Context, workspaceID string) (int, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "AND", "namespace")
 	var count int
 	err := r.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM agents WHERE workspace_id = ?
-	`, workspaceID).Scan(&count)
+		SELECT COUNT(*) FROM agents WHERE workspace_id = ?`+filter, append([]any{workspaceID}, filterArgs...)...).Scan(&count)
 
 	if err != nil {
 		return 0, fmt.Errorf("failed to count agents: %w", err)
@@ -361,8 +379,9 @@ func (r *WorkspaceRepository) GetAgentCount(ctx context.Context, workspaceID str
 
 // Count returns the total number of workspaces.
 func (r *WorkspaceRepository) Count(ctx context.Context) (int, error) {
+	filter, filterArgs := r.db.namespaceFilter(ctx, "WHERE", "namespace")
 	var count int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM workspaces`).Scan(&count)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM workspaces`+filter, filterArgs...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count workspaces: %w", err)
 	}
@@ -379,6 +398,7 @@ func (r *WorkspaceRepository) scanWorkspace(row *sql.Row) (*models.Workspace, er
 	err := row.Scan(
 		&workspace.ID,
 		&workspace.Name,
+		&workspace.Namespace,
 		&workspace.NodeID,
 		&workspace.RepoPath,
 		&workspace.TmuxSession,
@@ -429,6 +449,7 @@ func (r *WorkspaceRepository) scanWorkspaces(rows *sql.Rows) ([]*models.Workspac
 		err := rows.Scan(
 			&workspace.ID,
 			&workspace.Name,
+			&workspace.Namespace,
 			&workspace.NodeID,
 			&workspace.RepoPath,
 			&workspace.TmuxSession,
@@ -483,6 +504,7 @@ func (r *WorkspaceRepository) scanWorkspacesWithCounts(rows *sql.Rows) ([]*model
 		err := rows.Scan(
 			&workspace.ID,
 			&workspace.Name,
+			&workspace.Namespace,
 			&workspace.NodeID,
 			&workspace.RepoPath,
 			&workspace.TmuxSession,
@@ -0,0 +1,298 @@
+// Package db provides SQLite database access for Forge.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Workspace template repository errors.
+var (
+	ErrWorkspaceTemplateNotFound      = errors.New("workspace template not found")
+	ErrWorkspaceTemplateAlreadyExists = errors.New("workspace template already exists")
+	ErrWorkspaceBootstrapNotFound     = errors.New("workspace bootstrap not found")
+)
+
+// WorkspaceTemplateRepository handles workspace templates and the bootstrap
+// runs provisioned from them.
+type WorkspaceTemplateRepository struct {
+	db *DB
+}
+
+// NewWorkspaceTemplateRepository creates a new WorkspaceTemplateRepository.
+func NewWorkspaceTemplateRepository(db *DB) *WorkspaceTemplateRepository {
+	return &WorkspaceTemplateRepository{db: db}
+}
+
+const workspaceTemplateColumns = `id, name, description, git_url, branch, bootstrap_json, env_json, created_at, updated_at`
+
+// Create adds a new template.
+func (r *WorkspaceTemplateRepository) Create(ctx context.Context, tmpl *models.WorkspaceTemplate) error {
+	if err := tmpl.Validate(); err != nil {
+		return fmt.Errorf("invalid workspace template: %w", err)
+	}
+	if tmpl.ID == "" {
+		tmpl.ID = uuid.New().String()
+	}
+	now := time.Now().UTC()
+	tmpl.CreatedAt = now
+	tmpl.UpdatedAt = now
+
+	bootstrapJSON, envJSON, err := marshalTemplateLists(tmpl)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO workspace_templates (`+workspaceTemplateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		tmpl.ID,
+		tmpl.Name,
+		nullableString(tmpl.Description),
+		nullableString(tmpl.GitURL),
+		nullableString(tmpl.Branch),
+		bootstrapJSON,
+		envJSON,
+		tmpl.CreatedAt.Format(time.RFC3339),
+		tmpl.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrWorkspaceTemplateAlreadyExists
+		}
+		return fmt.Errorf("failed to insert workspace template: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a template's fields, matched by ID.
+func (r *WorkspaceTemplateRepository) Update(ctx context.Context, tmpl *models.WorkspaceTemplate) error {
+	if err := tmpl.Validate(); err != nil {
+		return fmt.Errorf("invalid workspace template: %w", err)
+	}
+	tmpl.UpdatedAt = time.Now().UTC()
+
+	bootstrapJSON, envJSON, err := marshalTemplateLists(tmpl)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_templates
+		SET name = ?, description = ?, git_url = ?, branch = ?, bootstrap_json = ?, env_json = ?, updated_at = ?
+		WHERE id = ?
+	`,
+		tmpl.Name,
+		nullableString(tmpl.Description),
+		nullableString(tmpl.GitURL),
+		nullableString(tmpl.Branch),
+		bootstrapJSON,
+		envJSON,
+		tmpl.UpdatedAt.Format(time.RFC3339),
+		tmpl.ID,
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrWorkspaceTemplateAlreadyExists
+		}
+		return fmt.Errorf("failed to update workspace template: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return ErrWorkspaceTemplateNotFound
+	}
+	return nil
+}
+
+// GetByName retrieves a template by name.
+func (r *WorkspaceTemplateRepository) GetByName(ctx context.Context, name string) (*models.WorkspaceTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workspaceTemplateColumns+` FROM workspace_templates WHERE name = ?`, name)
+	return scanWorkspaceTemplate(row)
+}
+
+// List retrieves all templates ordered by name.
+func (r *WorkspaceTemplateRepository) List(ctx context.Context) ([]*models.WorkspaceTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+workspaceTemplateColumns+` FROM workspace_templates ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspace templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.WorkspaceTemplate
+	for rows.Next() {
+		tmpl, err := scanWorkspaceTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspace templates: %w", err)
+	}
+	return templates, nil
+}
+
+// DeleteByName removes a template by name.
+func (r *WorkspaceTemplateRepository) DeleteByName(ctx context.Context, name string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM workspace_templates WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace template: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return ErrWorkspaceTemplateNotFound
+	}
+	return nil
+}
+
+// SaveBootstrap records the latest bootstrap run of a workspace, replacing
+// any earlier one.
+func (r *WorkspaceTemplateRepository) SaveBootstrap(ctx context.Context, run *models.WorkspaceBootstrap) error {
+	if run.WorkspaceID == "" {
+		return fmt.Errorf("workspace id is required")
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	var finishedAt *string
+	if run.FinishedAt != nil {
+		value := run.FinishedAt.UTC().Format(time.RFC3339)
+		finishedAt = &value
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO workspace_bootstraps (workspace_id, template_id, status, exit_code, output, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(workspace_id) DO UPDATE SET
+			template_id = excluded.template_id,
+			status = excluded.status,
+			exit_code = excluded.exit_code,
+			output = excluded.output,
+			started_at = excluded.started_at,
+			finished_at = excluded.finished_at
+	`,
+		run.WorkspaceID,
+		nullableString(run.TemplateID),
+		string(run.Status),
+		run.ExitCode,
+		nullableString(run.Output),
+		run.StartedAt.UTC().Format(time.RFC3339),
+		finishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save workspace bootstrap: %w", err)
+	}
+	return nil
+}
+
+// GetBootstrap retrieves the latest bootstrap run of a workspace.
+func (r *WorkspaceTemplateRepository) GetBootstrap(ctx context.Context, workspaceID string) (*models.WorkspaceBootstrap, error) {
+	var (
+		run        models.WorkspaceBootstrap
+		templateID sql.NullString
+		status     string
+		exitCode   sql.NullInt64
+		output     sql.NullString
+		startedAt  string
+		finishedAt sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT workspace_id, template_id, status, exit_code, output, started_at, finished_at
+		FROM workspace_bootstraps WHERE workspace_id = ?
+	`, workspaceID).Scan(&run.WorkspaceID, &templateID, &status, &exitCode, &output, &startedAt, &finishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkspaceBootstrapNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace bootstrap: %w", err)
+	}
+
+	run.TemplateID = templateID.String
+	run.Status = models.BootstrapStatus(status)
+	run.Output = output.String
+	if exitCode.Valid {
+		code := int(exitCode.Int64)
+		run.ExitCode = &code
+	}
+	if t, err := time.Parse(time.RFC3339, startedAt); err == nil {
+		run.StartedAt = t
+	}
+	if finishedAt.Valid {
+		if t, err := time.Parse(time.RFC3339, finishedAt.String); err == nil {
+			run.FinishedAt = &t
+		}
+	}
+	return &run, nil
+}
+
+func marshalTemplateLists(tmpl *models.WorkspaceTemplate) (*string, *string, error) {
+	var bootstrapJSON, envJSON *string
+	if len(tmpl.Bootstrap) > 0 {
+		data, err := json.Marshal(tmpl.Bootstrap)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal bootstrap commands: %w", err)
+		}
+		value := string(data)
+		bootstrapJSON = &value
+	}
+	if len(tmpl.Env) > 0 {
+		data, err := json.Marshal(tmpl.Env)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal template env: %w", err)
+		}
+		value := string(data)
+		envJSON = &value
+	}
+	return bootstrapJSON, envJSON, nil
+}
+
+func scanWorkspaceTemplate(scanner interface{ Scan(...any) error }) (*models.WorkspaceTemplate, error) {
+	var (
+		tmpl          models.WorkspaceTemplate
+		description   sql.NullString
+		gitURL        sql.NullString
+		branch        sql.NullString
+		bootstrapJSON sql.NullString
+		envJSON       sql.NullString
+		createdAt     string
+		updatedAt     string
+	)
+	err := scanner.Scan(&tmpl.ID, &tmpl.Name, &description, &gitURL, &branch, &bootstrapJSON, &envJSON, &createdAt, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkspaceTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to scan workspace template: %w", err)
+	}
+
+	tmpl.Description = description.String
+	tmpl.GitURL = gitURL.String
+	tmpl.Branch = branch.String
+	if bootstrapJSON.Valid && bootstrapJSON.String != "" {
+		if err := json.Unmarshal([]byte(bootstrapJSON.String), &tmpl.Bootstrap); err != nil {
+			return nil, fmt.Errorf("failed to parse bootstrap commands: %w", err)
+		}
+	}
+	if envJSON.Valid && envJSON.String != "" {
+		if err := json.Unmarshal([]byte(envJSON.String), &tmpl.Env); err != nil {
+			return nil, fmt.Errorf("failed to parse template env: %w", err)
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+		tmpl.CreatedAt = t
+	}
+	if t, err := time.Parse(time.RFC3339, updatedAt); err == nil {
+		tmpl.UpdatedAt = t
+	}
+	return &tmpl, nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestWorkspaceTemplateRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewWorkspaceTemplateRepository(db)
+
+	tmpl := &models.WorkspaceTemplate{
+		Name:      "api-service",
+		GitURL:    "git@example.com:acme/api.git",
+		Branch:    "main",
+		Bootstrap: []string{"make deps", "make db-migrate"},
+		Env:       map[string]string{"GOFLAGS": "-mod=mod"},
+	}
+	if err := repo.Create(ctx, tmpl); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.Create(ctx, &models.WorkspaceTemplate{Name: "api-service"}); !errors.Is(err, ErrWorkspaceTemplateAlreadyExists) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	if err := repo.Create(ctx, &models.WorkspaceTemplate{Name: "bad", Env: map[string]string{"1X": "y"}}); err == nil {
+		t.Fatal("expected invalid env name to be rejected")
+	}
+
+	got, err := repo.GetByName(ctx, "api-service")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.GitURL != tmpl.GitURL || got.Branch != "main" || len(got.Bootstrap) != 2 || got.Env["GOFLAGS"] != "-mod=mod" {
+		t.Fatalf("unexpected round trip %+v", got)
+	}
+
+	got.Bootstrap = []string{"make deps"}
+	got.Env = nil
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	list, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 || len(list[0].Bootstrap) != 1 || list[0].Env != nil {
+		t.Fatalf("unexpected list after update %+v", list)
+	}
+
+	if err := repo.DeleteByName(ctx, "api-service"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.GetByName(ctx, "api-service"); !errors.Is(err, ErrWorkspaceTemplateNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+}
+
+func TestWorkspaceTemplateRepository_Bootstrap(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewWorkspaceTemplateRepository(db)
+	ws := createTestWorkspace(t, db)
+
+	if _, err := repo.GetBootstrap(ctx, ws.ID); !errors.Is(err, ErrWorkspaceBootstrapNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	started := time.Now().UTC().Truncate(time.Second)
+	if err := repo.SaveBootstrap(ctx, &models.WorkspaceBootstrap{
+		WorkspaceID: ws.ID,
+		Status:      models.BootstrapStatusRunning,
+		StartedAt:   started,
+	}); err != nil {
+		t.Fatalf("save running: %v", err)
+	}
+
+	code := 2
+	finished := started.Add(time.Minute)
+	if err := repo.SaveBootstrap(ctx, &models.WorkspaceBootstrap{
+		WorkspaceID: ws.ID,
+		Status:      models.BootstrapStatusFailed,
+		ExitCode:    &code,
+		Output:      "make: *** [deps] Error 2",
+		StartedAt:   started,
+		FinishedAt:  &finished,
+	}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	run, err := repo.GetBootstrap(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("get bootstrap: %v", err)
+	}
+	if run.Status != models.BootstrapStatusFailed || run.ExitCode == nil || *run.ExitCode != 2 ||
+		run.Output == "" || run.FinishedAt == nil || !run.FinishedAt.Equal(finished) {
+		t.Fatalf("unexpected bootstrap %+v", run)
+	}
+}
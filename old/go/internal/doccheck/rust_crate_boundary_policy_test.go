@@ -20,8 +20,18 @@ func TestRustCrateBoundaryPolicyCoversActiveWorkspaceCrates(t *testing.T) {
 		t.Fatalf("no rust workspace crates discovered in Cargo.toml")
 	}
 
+	// The policy is a flat crate->layer map, or {"layers": ..., "allow": ...}
+	// once exceptions are listed.
+	var structured struct {
+		Layers map[string]int `json:"layers"`
+	}
 	var policy map[string]int
-	if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+	if strings.Contains(policyJSON, `"layers"`) {
+		if err := json.Unmarshal([]byte(policyJSON), &structured); err != nil {
+			t.Fatalf("parse docs/rust-crate-boundaries.json: %v", err)
+		}
+		policy = structured.Layers
+	} else if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
 		t.Fatalf("parse docs/rust-crate-boundaries.json: %v", err)
 	}
 
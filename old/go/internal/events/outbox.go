@@ -0,0 +1,163 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	defaultOutboxPollInterval = 250 * time.Millisecond
+	defaultOutboxBatchSize    = 100
+	defaultOutboxRetention    = time.Hour
+)
+
+// EnsureRepository persists an event unless it is already stored.
+// db.EventRepository implements it.
+type EnsureRepository interface {
+	Ensure(ctx context.Context, event *models.Event) error
+}
+
+// OutboxDispatcher drains db.OutboxRepository: each pending event is written
+// to the event log and then handed to the publisher's subscribers. Delivery
+// is at-least-once; an event replayed after a crash is not logged twice but
+// may reach subscribers twice.
+type OutboxDispatcher struct {
+	outbox    *db.OutboxRepository
+	repo      EnsureRepository
+	publisher Publisher
+	logger    zerolog.Logger
+
+	pollInterval time.Duration
+	batchSize    int
+	retention    time.Duration
+
+	wake chan struct{}
+	mu   sync.Mutex
+}
+
+// OutboxOption configures an OutboxDispatcher.
+type OutboxOption func(*OutboxDispatcher)
+
+// WithOutboxPollInterval sets how often the outbox is checked when nobody
+// calls Notify.
+func WithOutboxPollInterval(interval time.Duration) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		if interval > 0 {
+			d.pollInterval = interval
+		}
+	}
+}
+
+// WithOutboxBatchSize sets how many entries are read per pass.
+func WithOutboxBatchSize(size int) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		if size > 0 {
+			d.batchSize = size
+		}
+	}
+}
+
+// WithOutboxRetention sets how long published entries are kept before
+// they are purged (0 = purge right away).
+func WithOutboxRetention(retention time.Duration) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		if retention >= 0 {
+			d.retention = retention
+		}
+	}
+}
+
+// NewOutboxDispatcher creates a dispatcher. repo and publisher may each be
+// nil; the publisher should not persist events itself (no WithRepository),
+// since repo already does.
+func NewOutboxDispatcher(outbox *db.OutboxRepository, repo EnsureRepository, publisher Publisher, opts ...OutboxOption) *OutboxDispatcher {
+	d := &OutboxDispatcher{
+		outbox:       outbox,
+		repo:         repo,
+		publisher:    publisher,
+		logger:       logging.Component("outbox"),
+		pollInterval: defaultOutboxPollInterval,
+		batchSize:    defaultOutboxBatchSize,
+		retention:    defaultOutboxRetention,
+		wake:         make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Notify wakes the dispatcher early, e.g. right after a transaction that
+// enqueued events commits. It never blocks.
+func (d *OutboxDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches pending events until ctx is done. Entries left over from a
+// previous process are delivered on the first pass.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Warn().Err(err).Msg("outbox dispatch failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DispatchPending delivers pending events in enqueue order and returns how
+// many were published. It stops at the first event that cannot be logged
+// so ordering is kept; that entry is retried on the next pass.
+func (d *OutboxDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	published := 0
+	for {
+		entries, err := d.outbox.Pending(ctx, d.batchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, entry := range entries {
+			if d.repo != nil {
+				if err := d.repo.Ensure(ctx, entry.Event); err != nil {
+					_ = d.outbox.MarkFailed(ctx, entry.ID, err)
+					return published, err
+				}
+			}
+			if d.publisher != nil {
+				d.publisher.Publish(ctx, entry.Event)
+			}
+			if err := d.outbox.MarkPublished(ctx, entry.ID); err != nil {
+				return published, err
+			}
+			published++
+		}
+		if len(entries) < d.batchSize {
+			break
+		}
+	}
+
+	if published > 0 {
+		if _, err := d.outbox.PurgePublished(ctx, time.Now().Add(-d.retention)); err != nil {
+			return published, err
+		}
+	}
+	return published, nil
+}
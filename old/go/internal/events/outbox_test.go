@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+type failingEnsure struct {
+	err error
+}
+
+func (f failingEnsure) Ensure(context.Context, *models.Event) error { return f.err }
+
+func enqueueTestEvent(t *testing.T, outbox *db.OutboxRepository, entityID string) *models.Event {
+	t.Helper()
+	event := &models.Event{
+		Type:       models.EventTypeAgentStateChanged,
+		EntityType: models.EntityTypeAgent,
+		EntityID:   entityID,
+	}
+	if err := outbox.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return event
+}
+
+func TestOutboxDispatcher_LogsAndPublishesInOrder(t *testing.T) {
+	database, repo := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+	outbox := db.NewOutboxRepository(database)
+	first := enqueueTestEvent(t, outbox, "agent-1")
+	enqueueTestEvent(t, outbox, "agent-2")
+
+	publisher := NewInMemoryPublisher()
+	var got []string
+	if err := publisher.Subscribe("test", Filter{}, func(event *models.Event) {
+		got = append(got, event.EntityID)
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	// Simulate a crash after the first event was logged but before the
+	// outbox entry was marked published.
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("pre-log event: %v", err)
+	}
+
+	dispatcher := NewOutboxDispatcher(outbox, repo, publisher, WithOutboxBatchSize(1), WithOutboxRetention(0))
+	published, err := dispatcher.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if published != 2 || len(got) != 2 || got[0] != "agent-1" || got[1] != "agent-2" {
+		t.Fatalf("published=%d delivered=%v", published, got)
+	}
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 logged events, got %d", count)
+	}
+	if pending, _ := outbox.PendingCount(ctx); pending != 0 {
+		t.Fatalf("expected empty outbox, got %d pending", pending)
+	}
+}
+
+func TestOutboxDispatcher_KeepsEntryWhenLoggingFails(t *testing.T) {
+	database, _ := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+	outbox := db.NewOutboxRepository(database)
+	enqueueTestEvent(t, outbox, "agent-1")
+
+	publisher := NewInMemoryPublisher()
+	delivered := 0
+	_ = publisher.Subscribe("test", Filter{}, func(*models.Event) { delivered++ })
+
+	dispatcher := NewOutboxDispatcher(outbox, failingEnsure{err: errors.New("disk full")}, publisher)
+	if _, err := dispatcher.DispatchPending(ctx); err == nil {
+		t.Fatalf("expected dispatch error")
+	}
+	if delivered != 0 {
+		t.Fatalf("event should not reach subscribers before it is logged")
+	}
+
+	entries, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Attempts != 1 || entries[0].LastError != "disk full" {
+		t.Fatalf("expected entry kept for retry, got %+v", entries)
+	}
+}
+
+func TestOutboxDispatcher_RunWakesOnNotify(t *testing.T) {
+	database, repo := setupTestDB(t)
+	defer database.Close()
+
+	outbox := db.NewOutboxRepository(database)
+	publisher := NewInMemoryPublisher()
+	delivered := make(chan string, 1)
+	_ = publisher.Subscribe("test", Filter{}, func(event *models.Event) { delivered <- event.EntityID })
+
+	dispatcher := NewOutboxDispatcher(outbox, repo, publisher, WithOutboxPollInterval(time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	enqueueTestEvent(t, outbox, "agent-9")
+	dispatcher.Notify()
+
+	select {
+	case id := <-delivered:
+		if id != "agent-9" {
+			t.Fatalf("unexpected event %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("event not dispatched after Notify")
+	}
+}
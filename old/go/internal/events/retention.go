@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -105,54 +107,178 @@ func (s *RetentionService) Stop() {
 	s.logger.Info().Msg("retention service stopped")
 }
 
+// PruneReport describes the outcome of a compaction pass.
+type PruneReport struct {
+	DryRun   bool              `json:"dry_run"`
+	Rules    []PruneRuleResult `json:"rules"`
+	ByCount  int64             `json:"by_count"`
+	Archived int64             `json:"archived"`
+}
+
+// PruneRuleResult is the number of events an age rule removed (or would
+// remove, for a dry run).
+type PruneRuleResult struct {
+	Rule   string        `json:"rule"`
+	MaxAge time.Duration `json:"max_age"`
+	Cutoff time.Time     `json:"cutoff"`
+	Events int64         `json:"events"`
+}
+
+// Deleted returns the total number of events removed across all rules.
+func (r *PruneReport) Deleted() int64 {
+	total := r.ByCount
+	for _, rule := range r.Rules {
+		total += rule.Events
+	}
+	return total
+}
+
+// retentionRule is one age-based compaction pass over a set of event types.
+type retentionRule struct {
+	name   string
+	maxAge time.Duration
+	sel    db.EventSelector
+}
+
+// defaultRetentionRule names the rule covering types without a type_max_age entry.
+const defaultRetentionRule = "default"
+
+// ageRules expands MaxAge and TypeMaxAge into non-overlapping selectors:
+// each event type is handled by its most specific rule only. Rules with a
+// zero window keep their events and are omitted.
+func (s *RetentionService) ageRules() []retentionRule {
+	var exact, prefixes []string
+	for key := range s.cfg.TypeMaxAge {
+		if prefix, ok := strings.CutSuffix(key, "*"); ok {
+			prefixes = append(prefixes, prefix)
+		} else {
+			exact = append(exact, key)
+		}
+	}
+	sort.Strings(exact)
+	sort.Strings(prefixes)
+
+	var rules []retentionRule
+	if s.cfg.MaxAge > 0 {
+		rules = append(rules, retentionRule{
+			name:   defaultRetentionRule,
+			maxAge: s.cfg.MaxAge,
+			sel:    db.EventSelector{ExcludeTypes: exact, ExcludePrefixes: prefixes},
+		})
+	}
+	for _, prefix := range prefixes {
+		age := s.cfg.TypeMaxAge[prefix+"*"]
+		if age <= 0 {
+			continue
+		}
+		var longer []string
+		for _, other := range prefixes {
+			if len(other) > len(prefix) && strings.HasPrefix(other, prefix) {
+				longer = append(longer, other)
+			}
+		}
+		rules = append(rules, retentionRule{
+			name:   prefix + "*",
+			maxAge: age,
+			sel:    db.EventSelector{Prefixes: []string{prefix}, ExcludeTypes: exact, ExcludePrefixes: longer},
+		})
+	}
+	for _, eventType := range exact {
+		age := s.cfg.TypeMaxAge[eventType]
+		if age <= 0 {
+			continue
+		}
+		rules = append(rules, retentionRule{
+			name:   eventType,
+			maxAge: age,
+			sel:    db.EventSelector{Types: []string{eventType}},
+		})
+	}
+	return rules
+}
+
 // RunCleanup runs a single cleanup cycle.
 func (s *RetentionService) RunCleanup(ctx context.Context) error {
-	s.logger.Debug().Msg("running cleanup cycle")
-	startTime := time.Now()
+	_, err := s.Prune(ctx, false)
+	return err
+}
 
-	var deletedByAge, deletedByCount int64
-	var archivedByAge, archivedByCount int64
-	var err error
+// Prune applies the retention policy once, regardless of Enabled. With
+// dryRun set nothing is deleted and the report holds matching counts.
+func (s *RetentionService) Prune(ctx context.Context, dryRun bool) (*PruneReport, error) {
+	s.logger.Debug().Bool("dry_run", dryRun).Msg("running cleanup cycle")
+	startTime := time.Now()
+	report := &PruneReport{DryRun: dryRun}
 
 	// Clean by age first
-	if s.cfg.MaxAge > 0 {
-		cutoff := time.Now().Add(-s.cfg.MaxAge)
-		deletedByAge, archivedByAge, err = s.cleanupByAge(ctx, cutoff)
-		if err != nil {
-			return fmt.Errorf("cleanup by age failed: %w", err)
+	for _, rule := range s.ageRules() {
+		sel := rule.sel
+		sel.Before = startTime.Add(-rule.maxAge)
+		result := PruneRuleResult{Rule: rule.name, MaxAge: rule.maxAge, Cutoff: sel.Before.UTC()}
+		if dryRun {
+			count, err := s.repo.CountForCompaction(ctx, sel)
+			if err != nil {
+				return nil, fmt.Errorf("cleanup by age failed: %w", err)
+			}
+			result.Events = count
+		} else {
+			deleted, archived, err := s.cleanupBySelector(ctx, sel)
+			if err != nil {
+				return nil, fmt.Errorf("cleanup by age (%s) failed: %w", rule.name, err)
+			}
+			result.Events = deleted
+			report.Archived += archived
 		}
+		report.Rules = append(report.Rules, result)
 	}
 
 	// Then clean by count
 	if s.cfg.MaxCount > 0 {
-		deletedByCount, archivedByCount, err = s.cleanupByCount(ctx, s.cfg.MaxCount)
-		if err != nil {
-			return fmt.Errorf("cleanup by count failed: %w", err)
+		if dryRun {
+			total, err := s.repo.Count(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("cleanup by count failed: %w", err)
+			}
+			var pending int64
+			for _, rule := range report.Rules {
+				pending += rule.Events
+			}
+			if excess := total - pending - int64(s.cfg.MaxCount); excess > 0 {
+				report.ByCount = excess
+			}
+		} else {
+			deleted, archived, err := s.cleanupByCount(ctx, s.cfg.MaxCount)
+			if err != nil {
+				return nil, fmt.Errorf("cleanup by count failed: %w", err)
+			}
+			report.ByCount = deleted
+			report.Archived += archived
 		}
 	}
 
-	totalDeleted := deletedByAge + deletedByCount
-	totalArchived := archivedByAge + archivedByCount
+	if dryRun {
+		return report, nil
+	}
 
+	totalDeleted := report.Deleted()
 	s.mu.Lock()
 	s.lastCleanup = startTime
 	s.totalDeleted += totalDeleted
-	s.totalArchived += totalArchived
+	s.totalArchived += report.Archived
 	s.mu.Unlock()
 
-	if totalDeleted > 0 || totalArchived > 0 {
+	if totalDeleted > 0 || report.Archived > 0 {
 		s.logger.Info().
-			Int64("deleted_by_age", deletedByAge).
-			Int64("deleted_by_count", deletedByCount).
-			Int64("archived_by_age", archivedByAge).
-			Int64("archived_by_count", archivedByCount).
+			Int64("deleted_by_age", totalDeleted-report.ByCount).
+			Int64("deleted_by_count", report.ByCount).
+			Int64("archived", report.Archived).
 			Dur("duration", time.Since(startTime)).
 			Msg("cleanup completed")
 	} else {
 		s.logger.Debug().Msg("cleanup completed, no events to remove")
 	}
 
-	return nil
+	return report, nil
 }
 
 // Stats returns current retention statistics.
@@ -200,7 +326,7 @@ func (s *RetentionService) cleanupLoop(ctx context.Context) {
 	}
 }
 
-func (s *RetentionService) cleanupByAge(ctx context.Context, cutoff time.Time) (deleted, archived int64, err error) {
+func (s *RetentionService) cleanupBySelector(ctx context.Context, sel db.EventSelector) (deleted, archived int64, err error) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -208,51 +334,25 @@ func (s *RetentionService) cleanupByAge(ctx context.Context, cutoff time.Time) (
 		default:
 		}
 
+		events, err := s.repo.ListForCompaction(ctx, sel, s.cfg.BatchSize)
+		if err != nil {
+			return deleted, archived, err
+		}
+		if len(events) == 0 {
+			return deleted, archived, nil
+		}
+		count, err := s.compact(ctx, events)
+		if err != nil {
+			return deleted, archived, err
+		}
+		deleted += count
 		if s.cfg.ArchiveBeforeDelete {
-			// Fetch events to archive
-			events, err := s.repo.ListOlderThan(ctx, cutoff, s.cfg.BatchSize)
-			if err != nil {
-				return deleted, archived, err
-			}
-			if len(events) == 0 {
-				break
-			}
-
-			// Archive events
-			if err := s.archiveEvents(events); err != nil {
-				return deleted, archived, fmt.Errorf("archive failed: %w", err)
-			}
 			archived += int64(len(events))
-
-			// Delete archived events
-			ids := make([]string, len(events))
-			for i, e := range events {
-				ids[i] = e.ID
-			}
-			count, err := s.repo.DeleteByIDs(ctx, ids)
-			if err != nil {
-				return deleted, archived, err
-			}
-			deleted += count
-		} else {
-			// Direct delete
-			count, err := s.repo.DeleteOlderThan(ctx, cutoff, s.cfg.BatchSize)
-			if err != nil {
-				return deleted, archived, err
-			}
-			if count == 0 {
-				break
-			}
-			deleted += count
 		}
-
-		// Check if we've deleted less than batch size, meaning we're done
-		if deleted > 0 && deleted%int64(s.cfg.BatchSize) != 0 {
-			break
+		if len(events) < s.cfg.BatchSize {
+			return deleted, archived, nil
 		}
 	}
-
-	return deleted, archived, nil
 }
 
 func (s *RetentionService) cleanupByCount(ctx context.Context, maxCount int) (deleted, archived int64, err error) {
@@ -263,10 +363,6 @@ func (s *RetentionService) cleanupByCount(ctx context.Context, maxCount int) (de
 	}
 
 	excess := total - int64(maxCount)
-	if excess <= 0 {
-		return 0, 0, nil
-	}
-
 	for excess > 0 {
 		select {
 		case <-ctx.Done():
@@ -279,49 +375,42 @@ func (s *RetentionService) cleanupByCount(ctx context.Context, maxCount int) (de
 			batchSize = int(excess)
 		}
 
+		events, err := s.repo.ListOldest(ctx, batchSize)
+		if err != nil {
+			return deleted, archived, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		count, err := s.compact(ctx, events)
+		if err != nil {
+			return deleted, archived, err
+		}
 		if s.cfg.ArchiveBeforeDelete {
-			// Fetch oldest events to archive
-			events, err := s.repo.ListOldest(ctx, batchSize)
-			if err != nil {
-				return deleted, archived, err
-			}
-			if len(events) == 0 {
-				break
-			}
-
-			// Archive events
-			if err := s.archiveEvents(events); err != nil {
-				return deleted, archived, fmt.Errorf("archive failed: %w", err)
-			}
 			archived += int64(len(events))
-
-			// Delete archived events
-			ids := make([]string, len(events))
-			for i, e := range events {
-				ids[i] = e.ID
-			}
-			count, err := s.repo.DeleteByIDs(ctx, ids)
-			if err != nil {
-				return deleted, archived, err
-			}
-			deleted += count
-			excess -= count
-		} else {
-			count, err := s.repo.DeleteExcess(ctx, maxCount, batchSize)
-			if err != nil {
-				return deleted, archived, err
-			}
-			if count == 0 {
-				break
-			}
-			deleted += count
-			excess -= count
 		}
+		deleted += count
+		excess -= count
 	}
 
 	return deleted, archived, nil
 }
 
+// compact archives events when configured, then deletes them, folding
+// their counts into event rollups when enabled.
+func (s *RetentionService) compact(ctx context.Context, events []*models.Event) (int64, error) {
+	if s.cfg.ArchiveBeforeDelete {
+		if err := s.archiveEvents(events); err != nil {
+			return 0, fmt.Errorf("archive failed: %w", err)
+		}
+	}
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return s.repo.Compact(ctx, ids, s.cfg.Rollup)
+}
+
 func (s *RetentionService) archiveEvents(events []*models.Event) error {
 	if len(events) == 0 {
 		return nil
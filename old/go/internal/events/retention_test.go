@@ -294,6 +294,81 @@ func TestRetentionService_DisabledByDefault(t *testing.T) {
 	svc.Stop()
 }
 
+func TestRetentionService_TypeMaxAgeAndRollup(t *testing.T) {
+	database, repo := setupTestDB(t)
+	defer database.Close()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	create := func(eventType models.EventType, age time.Duration) {
+		t.Helper()
+		event := &models.Event{
+			Type:       eventType,
+			EntityType: models.EntityTypeAgent,
+			EntityID:   "agent-1",
+			Timestamp:  now.Add(-age),
+		}
+		if err := repo.Create(ctx, event); err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+	}
+	// 3h old: past message.*, within the default window.
+	create(models.EventTypeMessageDispatched, 3*time.Hour)
+	create(models.EventTypeMessageQueued, 3*time.Hour)
+	// message.completed is kept forever despite matching message.*.
+	create(models.EventTypeMessageCompleted, 72*time.Hour)
+	// Default window applies to other types.
+	create(models.EventTypeAgentSpawned, 72*time.Hour)
+	create(models.EventTypeAgentSpawned, time.Hour)
+
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.EventRetention.MaxAge = 48 * time.Hour
+	cfg.EventRetention.TypeMaxAge = map[string]time.Duration{
+		"message.*":         2 * time.Hour,
+		"message.completed": 0,
+	}
+	svc := NewRetentionService(cfg, repo)
+
+	report, err := svc.Prune(ctx, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if report.Deleted() != 3 {
+		t.Fatalf("expected dry run to match 3 events, got %+v", report)
+	}
+	if count, _ := repo.Count(ctx); count != 5 {
+		t.Fatalf("dry run deleted events: %d remain", count)
+	}
+
+	report, err = svc.Prune(ctx, false)
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	byRule := map[string]int64{}
+	for _, rule := range report.Rules {
+		byRule[rule.Rule] = rule.Events
+	}
+	if byRule["default"] != 1 || byRule["message.*"] != 2 {
+		t.Fatalf("unexpected per-rule counts: %+v", byRule)
+	}
+	if count, _ := repo.Count(ctx); count != 2 {
+		t.Fatalf("expected 2 events to remain, got %d", count)
+	}
+
+	rollups, err := repo.ListRollups(ctx, "", nil, 0)
+	if err != nil {
+		t.Fatalf("list rollups: %v", err)
+	}
+	var total int64
+	for _, rollup := range rollups {
+		total += rollup.Count
+	}
+	if len(rollups) != 3 || total != 3 {
+		t.Fatalf("expected 3 rollup rows totalling 3, got %+v", rollups)
+	}
+}
+
 func TestEventRepository_Count(t *testing.T) {
 	database, repo := setupTestDB(t)
 	defer database.Close()
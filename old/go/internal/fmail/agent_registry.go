@@ -11,13 +11,24 @@ import (
 	"time"
 )
 
+// maxAgentStatusHistory bounds the status changes kept per agent record.
+const maxAgentStatusHistory = 10
+
 // AgentRecord tracks agent presence in the project.
 type AgentRecord struct {
-	Name      string    `json:"name"`
-	Host      string    `json:"host,omitempty"`
-	Status    string    `json:"status,omitempty"`
-	FirstSeen time.Time `json:"first_seen"`
-	LastSeen  time.Time `json:"last_seen"`
+	Name          string              `json:"name"`
+	Host          string              `json:"host,omitempty"`
+	Status        string              `json:"status,omitempty"`
+	StatusHistory []AgentStatusChange `json:"status_history,omitempty"`
+	FirstSeen     time.Time           `json:"first_seen"`
+	LastSeen      time.Time           `json:"last_seen"`
+}
+
+// AgentStatusChange records a status transition, oldest first in
+// AgentRecord.StatusHistory. An empty Status means the status was cleared.
+type AgentStatusChange struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
 }
 
 // UpdateAgentRecord creates or updates the agent registry entry.
@@ -158,6 +169,12 @@ func (s *Store) SetAgentStatus(name, status, host string) (*AgentRecord, error)
 	record.LastSeen = now
 
 	status = strings.TrimSpace(status)
+	if status != record.Status {
+		record.StatusHistory = append(record.StatusHistory, AgentStatusChange{Status: status, At: now})
+		if len(record.StatusHistory) > maxAgentStatusHistory {
+			record.StatusHistory = append([]AgentStatusChange(nil), record.StatusHistory[len(record.StatusHistory)-maxAgentStatusHistory:]...)
+		}
+	}
 	record.Status = status
 
 	host = strings.TrimSpace(host)
@@ -1,6 +1,7 @@
 package fmail
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -24,3 +25,38 @@ func TestRegisterAgentRecordUnique(t *testing.T) {
 	_, err = store.RegisterAgentRecord("alice", "other-host")
 	require.ErrorIs(t, err, ErrAgentExists)
 }
+
+func TestSetAgentStatusRecordsHistory(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC)
+
+	store, err := NewStore(root, WithNow(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	_, err = store.SetAgentStatus("alice", "building", "")
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, err = store.SetAgentStatus("alice", "building", "")
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	record, err := store.SetAgentStatus("alice", "", "")
+	require.NoError(t, err)
+
+	require.Equal(t, []AgentStatusChange{
+		{Status: "building", At: time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC)},
+		{Status: "", At: time.Date(2026, 1, 10, 18, 2, 0, 0, time.UTC)},
+	}, record.StatusHistory)
+
+	for i := 0; i < maxAgentStatusHistory+5; i++ {
+		now = now.Add(time.Minute)
+		record, err = store.SetAgentStatus("alice", fmt.Sprintf("step %d", i), "")
+		require.NoError(t, err)
+	}
+	require.Len(t, record.StatusHistory, maxAgentStatusHistory)
+	require.Equal(t, fmt.Sprintf("step %d", maxAgentStatusHistory+4), record.StatusHistory[maxAgentStatusHistory-1].Status)
+
+	records, err := store.ListAgentRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, record.StatusHistory, records[0].StatusHistory)
+}
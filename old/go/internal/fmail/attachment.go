@@ -0,0 +1,242 @@
+package fmail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxAttachmentSize bounds a single attachment unless overridden with
+	// WithMaxAttachmentSize.
+	MaxAttachmentSize = 10 << 20 // 10MB
+
+	attachmentDirPerm  = 0o700
+	attachmentFilePerm = 0o600
+)
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment exceeds size limit")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+)
+
+// Attachment references a file stored content-addressed under
+// <root>/attachments. Identical content is stored once.
+type Attachment struct {
+	Name      string `json:"name"`
+	SHA256    string `json:"sha256"`
+	Size      int64  `json:"size"`
+	MediaType string `json:"media_type,omitempty"`
+}
+
+// Validate checks that the attachment can be resolved safely.
+func (a Attachment) Validate() error {
+	name := strings.TrimSpace(a.Name)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: bad name %q", ErrInvalidAttachment, a.Name)
+	}
+	if !validDigest(a.SHA256) {
+		return fmt.Errorf("%w: bad sha256 %q", ErrInvalidAttachment, a.SHA256)
+	}
+	if a.Size < 0 {
+		return fmt.Errorf("%w: negative size", ErrInvalidAttachment)
+	}
+	return nil
+}
+
+func validDigest(value string) bool {
+	if len(value) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil && strings.ToLower(value) == value
+}
+
+// WithMaxAttachmentSize overrides the per-attachment size limit.
+func WithMaxAttachmentSize(limit int64) StoreOption {
+	return func(store *Store) {
+		if limit > 0 {
+			store.maxAttachmentSize = limit
+		}
+	}
+}
+
+func (s *Store) AttachmentsDir() string {
+	return filepath.Join(s.Root, "attachments")
+}
+
+// AttachmentPath returns the blob path for a digest, fanned out by its first
+// two hex characters.
+func (s *Store) AttachmentPath(digest string) string {
+	return filepath.Join(s.AttachmentsDir(), digest[:2], digest)
+}
+
+// SaveAttachment stores content read from r and returns its reference.
+// Content larger than the store's limit is rejected without being kept.
+func (s *Store) SaveAttachment(name string, r io.Reader) (*Attachment, error) {
+	name = filepath.Base(strings.TrimSpace(name))
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	if err := ensureDirPerm(s.AttachmentsDir(), attachmentDirPerm); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(s.AttachmentsDir(), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	limit := s.attachmentLimit()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, limit+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if size > limit {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrAttachmentTooLarge, name, limit)
+	}
+
+	attachment := &Attachment{
+		Name:      name,
+		SHA256:    hex.EncodeToString(hash.Sum(nil)),
+		Size:      size,
+		MediaType: mime.TypeByExtension(filepath.Ext(name)),
+	}
+	if err := attachment.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := s.AttachmentPath(attachment.SHA256)
+	if _, err := os.Stat(path); err == nil {
+		return attachment, nil
+	}
+	if err := ensureDirPerm(filepath.Dir(path), attachmentDirPerm); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmpPath, attachmentFilePerm); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// SaveAttachmentFile stores the file at path under its base name.
+func (s *Store) SaveAttachmentFile(path string) (*Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s is a directory", ErrInvalidAttachment, path)
+	}
+	if info.Size() > s.attachmentLimit() {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrAttachmentTooLarge, filepath.Base(path), s.attachmentLimit())
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.SaveAttachment(filepath.Base(path), f)
+}
+
+// OpenAttachment opens the stored content for an attachment.
+func (s *Store) OpenAttachment(attachment Attachment) (io.ReadCloser, error) {
+	if err := attachment.Validate(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.AttachmentPath(attachment.SHA256))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachment.Name)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// ExportAttachment copies an attachment into dir under its original name,
+// adding a numeric suffix instead of overwriting an existing file. The
+// content is verified against its digest; a mismatch removes the copy.
+func (s *Store) ExportAttachment(attachment Attachment, dir string) (string, error) {
+	src, err := s.OpenAttachment(attachment)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	dst, path, err := createUnique(dir, attachment.Name)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(dst, hash), src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && hex.EncodeToString(hash.Sum(nil)) != attachment.SHA256 {
+		err = fmt.Errorf("%w: %s content does not match its digest", ErrInvalidAttachment, attachment.Name)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+func createUnique(dir, name string) (*os.File, string, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 0; i < 1000; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s-%d%s", stem, i, ext)
+		}
+		path := filepath.Join(dir, candidate)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			return f, path, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("no free file name for %s in %s", name, dir)
+}
+
+func (s *Store) attachmentLimit() int64 {
+	if s.maxAttachmentSize > 0 {
+		return s.maxAttachmentSize
+	}
+	return MaxAttachmentSize
+}
+
+// FormatAttachmentSize renders a byte count for attachment listings.
+func FormatAttachmentSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
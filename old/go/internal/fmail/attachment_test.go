@@ -0,0 +1,77 @@
+package fmail
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreAttachmentRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	first, err := store.SaveAttachment("notes.txt", strings.NewReader("hello attachment"))
+	require.NoError(t, err)
+	require.Equal(t, "notes.txt", first.Name)
+	require.Equal(t, int64(16), first.Size)
+	require.Len(t, first.SHA256, 64)
+	require.True(t, strings.HasPrefix(first.MediaType, "text/plain"))
+
+	// Same content under another name is stored once.
+	second, err := store.SaveAttachment("copy.txt", strings.NewReader("hello attachment"))
+	require.NoError(t, err)
+	require.Equal(t, first.SHA256, second.SHA256)
+	blobs, err := os.ReadDir(filepath.Dir(store.AttachmentPath(first.SHA256)))
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+
+	reader, err := store.OpenAttachment(*first)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, reader.Close())
+	require.NoError(t, err)
+	require.Equal(t, "hello attachment", string(data))
+
+	outDir := t.TempDir()
+	path, err := store.ExportAttachment(*first, outDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(outDir, "notes.txt"), path)
+	path, err = store.ExportAttachment(*first, outDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(outDir, "notes-1.txt"), path)
+}
+
+func TestStoreAttachmentSizeLimit(t *testing.T) {
+	store, err := NewStore(t.TempDir(), WithMaxAttachmentSize(8))
+	require.NoError(t, err)
+
+	_, err = store.SaveAttachment("big.bin", bytes.NewReader(make([]byte, 9)))
+	require.ErrorIs(t, err, ErrAttachmentTooLarge)
+	entries, err := os.ReadDir(store.AttachmentsDir())
+	require.NoError(t, err)
+	require.Empty(t, entries, "rejected upload must not leave files behind")
+
+	_, err = store.SaveAttachment("fits.bin", bytes.NewReader(make([]byte, 8)))
+	require.NoError(t, err)
+}
+
+func TestStoreAttachmentMissingAndInvalid(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	missing := Attachment{Name: "gone.txt", SHA256: strings.Repeat("a", 64)}
+	_, err = store.OpenAttachment(missing)
+	require.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	_, err = store.OpenAttachment(Attachment{Name: "../escape", SHA256: strings.Repeat("a", 64)})
+	require.ErrorIs(t, err, ErrInvalidAttachment)
+
+	msg := &Message{From: "alice", To: "task", Body: "see file", Attachments: []Attachment{{Name: "x", SHA256: "nothex"}}}
+	_, err = store.SaveMessage(msg)
+	require.ErrorIs(t, err, ErrInvalidAttachment)
+}
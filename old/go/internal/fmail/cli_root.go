@@ -35,6 +35,14 @@ func newRootCmd(version string) *cobra.Command {
 		newTopicsCmd(),
 		newGCCmd(),
 		newInitCmd(),
+		newTemplateCmd(),
+		newGroupCmd(),
+		newEncryptCmd(),
+		newDigestCmd(),
+		newWebhookCmd(),
+		newReactCmd(),
+		newQuotaCmd(),
+		newSearchCmd(),
 	)
 
 	return cmd
@@ -18,6 +18,9 @@ func newSendCmd() *cobra.Command {
 	cmd.Flags().StringP("reply-to", "r", "", "Reference a previous message ID")
 	cmd.Flags().StringP("priority", "p", "normal", "Set priority: low, normal, high")
 	cmd.Flags().StringSliceP("tag", "t", nil, "Add tags (repeatable or comma-separated)")
+	cmd.Flags().StringArrayP("attach", "a", nil, "Attach a file (repeatable)")
+	cmd.Flags().String("template", "", "Use a saved template as the message body")
+	cmd.Flags().StringArray("var", nil, "Template placeholder value as name=value (repeatable)")
 	cmd.Flags().Bool("json", false, "Output message as JSON")
 	return cmd
 }
@@ -63,6 +66,8 @@ func newWatchCmd() *cobra.Command {
 	cmd.Flags().Duration("timeout", 0, "Maximum wait time before exiting")
 	cmd.Flags().IntP("count", "c", 0, "Exit after receiving N messages")
 	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("exec", "", "Run a shell command per message (message JSON on stdin, FMAIL_MSG_* env)")
+	cmd.Flags().String("color", watchColorAuto, "Colorize output: auto, always, never")
 	return cmd
 }
 
@@ -6,17 +6,27 @@ const (
 	EnvAgent   = "FMAIL_AGENT"
 	EnvRoot    = "FMAIL_ROOT"
 	EnvProject = "FMAIL_PROJECT"
+	EnvKeyDir  = "FMAIL_KEY_DIR"
 
 	MaxMessageSize = 1 << 20 // 1MB
 )
 
 var (
-	ErrInvalidTopic    = errors.New("invalid topic name")
-	ErrInvalidAgent    = errors.New("invalid agent name")
-	ErrInvalidTarget   = errors.New("invalid target")
-	ErrInvalidTag      = errors.New("invalid tag")
-	ErrMessageTooLarge = errors.New("message exceeds 1MB limit")
-	ErrEmptyMessage    = errors.New("message is nil")
-	ErrIDCollision     = errors.New("message id collision")
-	ErrAgentExists     = errors.New("agent already exists")
+	ErrInvalidTopic     = errors.New("invalid topic name")
+	ErrInvalidAgent     = errors.New("invalid agent name")
+	ErrInvalidTarget    = errors.New("invalid target")
+	ErrInvalidTag       = errors.New("invalid tag")
+	ErrMessageTooLarge  = errors.New("message exceeds 1MB limit")
+	ErrEmptyMessage     = errors.New("message is nil")
+	ErrIDCollision      = errors.New("message id collision")
+	ErrAgentExists      = errors.New("agent already exists")
+	ErrInvalidTemplate  = errors.New("invalid template name")
+	ErrTemplateExists   = errors.New("template already exists")
+	ErrInvalidGroup     = errors.New("invalid group name")
+	ErrGroupNotFound    = errors.New("group not found")
+	ErrInvalidWebhook   = errors.New("invalid webhook name")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrInvalidReaction  = errors.New("invalid reaction")
+	ErrReactionNotFound = errors.New("reaction not found")
+	ErrQuotaExceeded    = errors.New("quota exceeded")
 )
@@ -0,0 +1,312 @@
+package fmail
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	digestFormatText     = "text"
+	digestFormatMarkdown = "markdown"
+
+	digestSnippetLimit = 80
+)
+
+// Digest summarizes topic activity over a time window.
+type Digest struct {
+	Since     time.Time        `json:"since"`
+	Until     time.Time        `json:"until"`
+	Messages  int              `json:"messages"`
+	Agents    []string         `json:"agents,omitempty"`
+	Topics    []DigestTopic    `json:"topics,omitempty"`
+	NewAgents []DigestNewAgent `json:"new_agents,omitempty"`
+	Questions int              `json:"open_questions"`
+	High      int              `json:"high_priority"`
+}
+
+// DigestTopic is the activity of one topic in a digest.
+type DigestTopic struct {
+	Name          string          `json:"name"`
+	Messages      int             `json:"messages"`
+	Agents        []string        `json:"agents"`
+	LastActivity  time.Time       `json:"last_activity"`
+	OpenQuestions []DigestMessage `json:"open_questions,omitempty"`
+	HighPriority  []DigestMessage `json:"high_priority,omitempty"`
+}
+
+// DigestMessage is a one-line reference to a message.
+type DigestMessage struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	Time    time.Time `json:"time"`
+	Snippet string    `json:"snippet"`
+}
+
+// DigestNewAgent is an agent first seen inside the digest window.
+type DigestNewAgent struct {
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+func newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize recent topic activity",
+		Long: `Summarize topic activity over a time window: message counts and
+participants per topic, open questions (messages with a question that
+nobody replied to), high-priority messages, and newly registered agents.
+
+Markdown output is meant for pasting into standup notes.`,
+		Args: argsMax(0),
+		RunE: runDigest,
+	}
+	cmd.Flags().String("since", "24h", "Time window (duration like 8h or timestamp)")
+	cmd.Flags().String("format", digestFormatText, "Output format: text, markdown")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	runtime, err := EnsureRuntime(cmd)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseSince(sinceFlag, now)
+	if err != nil {
+		return usageError(cmd, "invalid --since value: %v", err)
+	}
+	if since == nil {
+		return usageError(cmd, "--since is required")
+	}
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "md" {
+		format = digestFormatMarkdown
+	}
+	if format != digestFormatText && format != digestFormatMarkdown {
+		return usageError(cmd, "invalid --format value %q (use text or markdown)", format)
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	digest, err := store.BuildDigest(*since, now)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "digest: %v", err)
+	}
+
+	if jsonOutput {
+		payload, err := json.MarshalIndent(digest, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode digest: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+	if format == digestFormatMarkdown {
+		writeDigestMarkdown(cmd.OutOrStdout(), digest)
+	} else {
+		writeDigestText(cmd.OutOrStdout(), digest)
+	}
+	return nil
+}
+
+// BuildDigest summarizes topic messages sent in [since, until] and agents
+// first seen in that window. A question is open when its message body asks
+// something (a line ending in "?" or a "question" tag) and no topic message
+// replies to it.
+func (s *Store) BuildDigest(since, until time.Time) (*Digest, error) {
+	if s == nil {
+		return nil, fmt.Errorf("store is nil")
+	}
+	digest := &Digest{Since: since.UTC(), Until: until.UTC()}
+
+	topics, err := s.ListTopics()
+	if err != nil {
+		return nil, err
+	}
+	agents := make(map[string]struct{})
+	for _, summary := range topics {
+		if summary.LastActivity.Before(digest.Since) {
+			continue
+		}
+		messages, err := s.ListTopicMessages(summary.Name)
+		if err != nil {
+			return nil, err
+		}
+		topic, ok := digestTopic(summary.Name, messages, digest.Since, digest.Until)
+		if !ok {
+			continue
+		}
+		for _, name := range topic.Agents {
+			agents[name] = struct{}{}
+		}
+		digest.Messages += topic.Messages
+		digest.Questions += len(topic.OpenQuestions)
+		digest.High += len(topic.HighPriority)
+		digest.Topics = append(digest.Topics, topic)
+	}
+	sort.SliceStable(digest.Topics, func(i, j int) bool {
+		if digest.Topics[i].Messages != digest.Topics[j].Messages {
+			return digest.Topics[i].Messages > digest.Topics[j].Messages
+		}
+		return digest.Topics[i].Name < digest.Topics[j].Name
+	})
+	for name := range agents {
+		digest.Agents = append(digest.Agents, name)
+	}
+	sort.Strings(digest.Agents)
+
+	records, err := s.ListAgentRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.FirstSeen.IsZero() || record.FirstSeen.Before(digest.Since) || record.FirstSeen.After(digest.Until) {
+			continue
+		}
+		digest.NewAgents = append(digest.NewAgents, DigestNewAgent{Name: record.Name, FirstSeen: record.FirstSeen.UTC()})
+	}
+	sort.Slice(digest.NewAgents, func(i, j int) bool {
+		return digest.NewAgents[i].FirstSeen.Before(digest.NewAgents[j].FirstSeen)
+	})
+	return digest, nil
+}
+
+func digestTopic(name string, messages []Message, since, until time.Time) (DigestTopic, bool) {
+	topic := DigestTopic{Name: name}
+	replied := make(map[string]struct{})
+	for _, message := range messages {
+		if message.ReplyTo != "" {
+			replied[message.ReplyTo] = struct{}{}
+		}
+	}
+
+	senders := make(map[string]struct{})
+	for _, message := range messages {
+		if message.Time.Before(since) || message.Time.After(until) {
+			continue
+		}
+		topic.Messages++
+		senders[message.From] = struct{}{}
+		if message.Time.After(topic.LastActivity) {
+			topic.LastActivity = message.Time.UTC()
+		}
+		body, _ := formatMessageBody(message.Body)
+		ref := DigestMessage{ID: message.ID, From: message.From, Time: message.Time.UTC(), Snippet: digestSnippet(body)}
+		if message.Priority == PriorityHigh {
+			topic.HighPriority = append(topic.HighPriority, ref)
+		}
+		if _, ok := replied[message.ID]; !ok && isDigestQuestion(&message, body) {
+			topic.OpenQuestions = append(topic.OpenQuestions, ref)
+		}
+	}
+	if topic.Messages == 0 {
+		return topic, false
+	}
+	for sender := range senders {
+		topic.Agents = append(topic.Agents, sender)
+	}
+	sort.Strings(topic.Agents)
+	return topic, true
+}
+
+func isDigestQuestion(message *Message, body string) bool {
+	for _, tag := range message.Tags {
+		if strings.EqualFold(tag, "question") {
+			return true
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), "?") {
+			return true
+		}
+	}
+	return false
+}
+
+func digestSnippet(body string) string {
+	snippet := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(snippet); len(runes) > digestSnippetLimit {
+		snippet = string(runes[:digestSnippetLimit-3]) + "..."
+	}
+	return snippet
+}
+
+func writeDigestText(out io.Writer, digest *Digest) {
+	fmt.Fprintf(out, "Digest since %s (%s)\n", digest.Since.Format("2006-01-02 15:04 MST"), formatDigestWindow(digest))
+	fmt.Fprintf(out, "%d messages in %d topics from %d agents; %d open questions, %d high priority\n",
+		digest.Messages, len(digest.Topics), len(digest.Agents), digest.Questions, digest.High)
+	for _, topic := range digest.Topics {
+		fmt.Fprintf(out, "\n%s: %d messages (%s)\n", topic.Name, topic.Messages, strings.Join(topic.Agents, ", "))
+		writeDigestTextRefs(out, "high priority", topic.HighPriority)
+		writeDigestTextRefs(out, "open questions", topic.OpenQuestions)
+	}
+	if len(digest.NewAgents) > 0 {
+		names := make([]string, 0, len(digest.NewAgents))
+		for _, agent := range digest.NewAgents {
+			names = append(names, agent.Name)
+		}
+		fmt.Fprintf(out, "\nNew agents: %s\n", strings.Join(names, ", "))
+	}
+}
+
+func writeDigestTextRefs(out io.Writer, label string, refs []DigestMessage) {
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "  %s:\n", label)
+	for _, ref := range refs {
+		fmt.Fprintf(out, "    %s %s: %s\n", ref.ID, ref.From, ref.Snippet)
+	}
+}
+
+func writeDigestMarkdown(out io.Writer, digest *Digest) {
+	fmt.Fprintf(out, "## fmail digest (%s)\n\n", formatDigestWindow(digest))
+	fmt.Fprintf(out, "_Since %s: %d messages in %d topics from %d agents; %d open questions, %d high priority._\n",
+		digest.Since.Format("2006-01-02 15:04 MST"), digest.Messages, len(digest.Topics), len(digest.Agents), digest.Questions, digest.High)
+	for _, topic := range digest.Topics {
+		fmt.Fprintf(out, "\n### %s\n\n", topic.Name)
+		fmt.Fprintf(out, "- %d messages from %s\n", topic.Messages, strings.Join(topic.Agents, ", "))
+		writeDigestMarkdownRefs(out, "High priority", topic.HighPriority)
+		writeDigestMarkdownRefs(out, "Open questions", topic.OpenQuestions)
+	}
+	if len(digest.NewAgents) > 0 {
+		fmt.Fprint(out, "\n### New agents\n\n")
+		for _, agent := range digest.NewAgents {
+			fmt.Fprintf(out, "- %s (first seen %s)\n", agent.Name, agent.FirstSeen.Format("2006-01-02 15:04 MST"))
+		}
+	}
+}
+
+func writeDigestMarkdownRefs(out io.Writer, label string, refs []DigestMessage) {
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "- %s:\n", label)
+	for _, ref := range refs {
+		fmt.Fprintf(out, "  - **%s**: %s (`%s`)\n", ref.From, ref.Snippet, ref.ID)
+	}
+}
+
+func formatDigestWindow(digest *Digest) string {
+	window := digest.Until.Sub(digest.Since).Round(time.Minute)
+	if window >= 24*time.Hour && window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("last %dd", int(window/(24*time.Hour)))
+	}
+	if window >= time.Hour && window%time.Hour == 0 {
+		return fmt.Sprintf("last %dh", int(window/time.Hour))
+	}
+	return "last " + strings.TrimSuffix(window.String(), "0s")
+}
@@ -0,0 +1,83 @@
+package fmail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Now().UTC()
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	save := func(message *Message) string {
+		t.Helper()
+		id, err := store.SaveMessage(message)
+		require.NoError(t, err)
+		return id
+	}
+	save(&Message{From: "alice", To: "task", Time: now.Add(-48 * time.Hour), Body: "old news?"})
+	question := save(&Message{From: "bob", To: "task", Time: now.Add(-2 * time.Hour), Body: "who owns\nthe auth refactor?"})
+	answered := save(&Message{From: "bob", To: "task", Time: now.Add(-2 * time.Hour), Body: "is CI green?"})
+	save(&Message{From: "alice", To: "task", Time: now.Add(-time.Hour), Body: "yes", ReplyTo: answered})
+	blocker := save(&Message{From: "alice", To: "task", Time: now.Add(-time.Hour), Body: "deploy blocked", Priority: PriorityHigh})
+	save(&Message{From: "carol", To: "status", Time: now.Add(-30 * time.Minute), Body: "starting", Tags: []string{"question"}})
+	save(&Message{From: "alice", To: "@bob", Time: now.Add(-30 * time.Minute), Body: "private?"})
+	_, err = store.UpdateAgentRecord("carol", "")
+	require.NoError(t, err)
+
+	digest, err := store.BuildDigest(now.Add(-8*time.Hour), time.Now().UTC())
+	require.NoError(t, err)
+
+	require.Equal(t, 5, digest.Messages)
+	require.Equal(t, []string{"alice", "bob", "carol"}, digest.Agents)
+	require.Len(t, digest.Topics, 2)
+	task := digest.Topics[0]
+	require.Equal(t, "task", task.Name)
+	require.Equal(t, 4, task.Messages)
+	require.Len(t, task.OpenQuestions, 1)
+	require.Equal(t, question, task.OpenQuestions[0].ID)
+	require.Equal(t, "who owns the auth refactor?", task.OpenQuestions[0].Snippet)
+	require.Len(t, task.HighPriority, 1)
+	require.Equal(t, blocker, task.HighPriority[0].ID)
+	require.Len(t, digest.Topics[1].OpenQuestions, 1)
+	require.Equal(t, 2, digest.Questions)
+	require.Len(t, digest.NewAgents, 1)
+	require.Equal(t, "carol", digest.NewAgents[0].Name)
+}
+
+func TestDigestCommandMarkdown(t *testing.T) {
+	t.Setenv(EnvProject, "proj-test")
+	root := t.TempDir()
+	runtime := &Runtime{Root: root, Agent: "alice"}
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "alice", To: "build", Time: time.Now().UTC(), Body: "release tonight?", Priority: PriorityHigh})
+	require.NoError(t, err)
+
+	cmd := newDigestCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(context.WithValue(context.Background(), runtimeKey{}, runtime))
+	require.NoError(t, cmd.Flags().Set("since", "8h"))
+	require.NoError(t, cmd.Flags().Set("format", "markdown"))
+	require.NoError(t, runDigest(cmd, nil))
+
+	require.Contains(t, out.String(), "## fmail digest (last 8h)")
+	require.Contains(t, out.String(), "### build")
+	require.Contains(t, out.String(), "- High priority:\n  - **alice**: release tonight?")
+	require.Contains(t, out.String(), "- Open questions:\n")
+
+	cmd = newDigestCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(context.WithValue(context.Background(), runtimeKey{}, runtime))
+	require.NoError(t, cmd.Flags().Set("format", "html"))
+	require.Error(t, runDigest(cmd, nil))
+}
@@ -0,0 +1,172 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newEncryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Manage encryption of direct messages at rest",
+		Long: `Manage encryption of direct message bodies on disk.
+
+When the project has a keyfile (~/.config/forge/fmail/keys/<project-id>.key,
+or $FMAIL_KEY_DIR), every DM body is written AES-256-GCM encrypted and read
+back transparently. Anyone without the keyfile sees only ciphertext. Topic
+and group thread messages are never encrypted.
+
+Run "fmail encrypt init" once, then "fmail encrypt migrate" to encrypt DMs
+written before the key existed.`,
+	}
+
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create the project key",
+		Args:  argsMax(0),
+		RunE:  runEncryptInit,
+	}
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show the keyfile and whether DMs are encrypted",
+		Args:  argsMax(0),
+		RunE:  runEncryptStatus,
+	}
+	status.Flags().Bool("json", false, "Output as JSON")
+
+	migrate := &cobra.Command{
+		Use:   "migrate",
+		Short: "Encrypt existing DMs (or decrypt with --decrypt)",
+		Args:  argsMax(0),
+		RunE:  runEncryptMigrate,
+	}
+	migrate.Flags().Bool("decrypt", false, "Write encrypted DMs back as plaintext")
+	migrate.Flags().Bool("dry-run", false, "List files that would change")
+	migrate.Flags().Bool("json", false, "Output as JSON")
+
+	cmd.AddCommand(initCmd, status, migrate)
+	return cmd
+}
+
+func encryptStore() (*Store, error) {
+	root, err := DiscoverProjectRoot("")
+	if err != nil {
+		return nil, Exitf(ExitCodeFailure, "resolve project root: %v", err)
+	}
+	store, err := NewStore(root)
+	if err != nil {
+		return nil, Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	return store, nil
+}
+
+func runEncryptInit(cmd *cobra.Command, args []string) error {
+	store, err := encryptStore()
+	if err != nil {
+		return err
+	}
+	path, err := store.GenerateKey()
+	if err != nil {
+		if errors.Is(err, ErrKeyExists) {
+			return Exitf(ExitCodeFailure, "key already exists: %s", path)
+		}
+		return Exitf(ExitCodeFailure, "generate key: %v", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+	fmt.Fprintln(cmd.OutOrStdout(), "New DMs are encrypted; run 'fmail encrypt migrate' for existing ones. Back up the key: losing it loses the messages.")
+	return nil
+}
+
+func runEncryptStatus(cmd *cobra.Command, args []string) error {
+	store, err := encryptStore()
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	path, err := store.KeyPath()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "resolve key: %v", err)
+	}
+	if _, err := store.encryptionKey(); err != nil {
+		return Exitf(ExitCodeFailure, "load key: %v", err)
+	}
+	enabled := store.EncryptionEnabled()
+	pending := 0
+	if enabled {
+		result, err := store.MigrateDMs(false, true)
+		if err != nil {
+			return Exitf(ExitCodeFailure, "scan dms: %v", err)
+		}
+		pending = result.Rewritten
+	}
+
+	if jsonOutput {
+		payload, err := json.MarshalIndent(map[string]any{
+			"enabled":   enabled,
+			"key_path":  path,
+			"plaintext": pending,
+		}, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode status: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+	if !enabled {
+		fmt.Fprintf(cmd.OutOrStdout(), "DM encryption: off (no key at %s)\n", path)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "DM encryption: on (%s)\n", path)
+	if pending > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d plaintext DMs; run 'fmail encrypt migrate'\n", pending)
+	}
+	return nil
+}
+
+func runEncryptMigrate(cmd *cobra.Command, args []string) error {
+	store, err := encryptStore()
+	if err != nil {
+		return err
+	}
+	decrypt, _ := cmd.Flags().GetBool("decrypt")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	result, err := store.MigrateDMs(decrypt, dryRun)
+	if err != nil {
+		if errors.Is(err, ErrNoEncryptionKey) {
+			return Exitf(ExitCodeFailure, "no key for this project; run 'fmail encrypt init' first")
+		}
+		return Exitf(ExitCodeFailure, "migrate: %v", err)
+	}
+
+	if jsonOutput {
+		payload, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode result: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+	if dryRun {
+		for _, path := range result.Paths {
+			if rel, err := filepath.Rel(store.Root, path); err == nil {
+				path = rel
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+		}
+		return nil
+	}
+	verb := "Encrypted"
+	if decrypt {
+		verb = "Decrypted"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %d of %d DMs\n", verb, result.Rewritten, result.Scanned)
+	return nil
+}
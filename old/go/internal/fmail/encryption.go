@@ -0,0 +1,357 @@
+package fmail
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EncryptionAlgorithm is the only cipher used for DM bodies at rest.
+const EncryptionAlgorithm = "aes-256-gcm"
+
+const (
+	keyFileExt  = ".key"
+	keyDirPerm  = 0o700
+	keyFilePerm = 0o600
+	keySize     = 32
+)
+
+var (
+	ErrNoEncryptionKey  = errors.New("no encryption key for project")
+	ErrKeyExists        = errors.New("encryption key already exists")
+	ErrDecryptionFailed = errors.New("message decryption failed")
+)
+
+// Encryption describes how a message body was sealed. When it is set the
+// stored body is the base64 ciphertext of the JSON-encoded original body.
+type Encryption struct {
+	Alg   string `json:"alg"`
+	Nonce string `json:"nonce"`
+}
+
+// Encrypted reports whether the message body is still sealed, i.e. it was
+// read without the project key.
+func (m *Message) Encrypted() bool {
+	return m != nil && m.Encryption != nil
+}
+
+// WithEncryptionKey sets the DM key directly instead of loading the project
+// keyfile. A nil key disables encryption.
+func WithEncryptionKey(key []byte) StoreOption {
+	return func(store *Store) {
+		store.dmKey = key
+		store.dmKeyOnce.Do(func() {})
+	}
+}
+
+// WithKeyDir overrides the directory holding project keyfiles.
+func WithKeyDir(dir string) StoreOption {
+	return func(store *Store) {
+		if strings.TrimSpace(dir) != "" {
+			store.keyDir = dir
+		}
+	}
+}
+
+// DefaultKeyDir returns $FMAIL_KEY_DIR or ~/.config/forge/fmail/keys.
+func DefaultKeyDir() string {
+	if dir := strings.TrimSpace(os.Getenv(EnvKeyDir)); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "forge", "fmail", "keys")
+}
+
+// KeyPath returns the keyfile for the store's project.
+func (s *Store) KeyPath() (string, error) {
+	if strings.TrimSpace(s.keyDir) == "" {
+		return "", fmt.Errorf("key directory unavailable")
+	}
+	projectID, err := s.projectID()
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(projectID, `/\`) || projectID == "." || projectID == ".." {
+		return "", fmt.Errorf("invalid project id %q", projectID)
+	}
+	return filepath.Join(s.keyDir, projectID+keyFileExt), nil
+}
+
+// EncryptionEnabled reports whether DMs written by this store are encrypted.
+func (s *Store) EncryptionEnabled() bool {
+	key, err := s.encryptionKey()
+	return err == nil && key != nil
+}
+
+// GenerateKey writes a new random key for the store's project and enables
+// encryption for subsequent DMs. An existing key is never replaced.
+func (s *Store) GenerateKey() (string, error) {
+	path, err := s.KeyPath()
+	if err != nil {
+		return "", err
+	}
+	if err := ensureDirPerm(filepath.Dir(path), keyDirPerm); err != nil {
+		return "", err
+	}
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := writeFileExclusivePerm(path, []byte(encoded), keyFilePerm); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return path, ErrKeyExists
+		}
+		return "", err
+	}
+	s.dmKeyOnce.Do(func() {})
+	s.dmKey, s.dmKeyErr = key, nil
+	return path, nil
+}
+
+func (s *Store) projectID() (string, error) {
+	project, err := readProjectIfExists(s.ProjectFile())
+	if err != nil {
+		return "", err
+	}
+	if project != nil && strings.TrimSpace(project.ID) != "" {
+		return strings.TrimSpace(project.ID), nil
+	}
+	return DeriveProjectID(filepath.Dir(s.Root))
+}
+
+// encryptionKey loads the project key once. A missing keyfile means
+// encryption is off and is not an error.
+func (s *Store) encryptionKey() ([]byte, error) {
+	s.dmKeyOnce.Do(func() {
+		path, err := s.KeyPath()
+		if err != nil {
+			s.dmKeyErr = err
+			return
+		}
+		s.dmKey, s.dmKeyErr = readKeyFile(path)
+	})
+	return s.dmKey, s.dmKeyErr
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("invalid key file %s: expected %d base64-encoded bytes", path, keySize)
+	}
+	return key, nil
+}
+
+// marshalDM encodes a DM for disk, sealing its body when a key is present.
+// Size limits apply to the plaintext so encryption never rejects a message
+// that would otherwise fit.
+func (s *Store) marshalDM(message *Message) ([]byte, error) {
+	data, err := marshalMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	key, err := s.encryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || message.Encrypted() {
+		return data, nil
+	}
+	sealed, err := sealMessage(message, key)
+	if err != nil {
+		return nil, err
+	}
+	return marshalMessage(sealed)
+}
+
+// openMessage decrypts msg in place when the key is available. Without a
+// key the message is returned still sealed.
+func (s *Store) openMessage(msg *Message) error {
+	if !msg.Encrypted() {
+		return nil
+	}
+	key, err := s.encryptionKey()
+	if err != nil || key == nil {
+		return nil
+	}
+	opened, err := openMessage(msg, key)
+	if err != nil {
+		return err
+	}
+	*msg = *opened
+	return nil
+}
+
+func sealMessage(message *Message, key []byte) (*Message, error) {
+	plaintext, err := json.Marshal(message.Body)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := *message
+	sealed.Body = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, messageAAD(message)))
+	sealed.Encryption = &Encryption{Alg: EncryptionAlgorithm, Nonce: base64.StdEncoding.EncodeToString(nonce)}
+	return &sealed, nil
+}
+
+func openMessage(message *Message, key []byte) (*Message, error) {
+	if message.Encryption.Alg != EncryptionAlgorithm {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrDecryptionFailed, message.Encryption.Alg)
+	}
+	body, ok := message.Body.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: body is not ciphertext", ErrDecryptionFailed)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(message.Encryption.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: bad nonce", ErrDecryptionFailed)
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, messageAAD(message))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecryptionFailed, message.ID)
+	}
+	opened := *message
+	opened.Encryption = nil
+	if err := json.Unmarshal(plaintext, &opened.Body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	return &opened, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// messageAAD binds the ciphertext to its envelope so a body cannot be moved
+// to another message or recipient.
+func messageAAD(message *Message) []byte {
+	return []byte(message.ID + "\x00" + message.From + "\x00" + message.To)
+}
+
+// DMMigration reports what MigrateDMs changed.
+type DMMigration struct {
+	Scanned   int      `json:"scanned"`
+	Rewritten int      `json:"rewritten"`
+	Skipped   int      `json:"skipped"`
+	Paths     []string `json:"paths,omitempty"`
+}
+
+// MigrateDMs encrypts every plaintext DM on disk with the project key, or
+// with decrypt set, writes every encrypted DM back as plaintext. Files are
+// replaced atomically; with dryRun nothing is written.
+func (s *Store) MigrateDMs(decrypt, dryRun bool) (DMMigration, error) {
+	var result DMMigration
+	key, err := s.encryptionKey()
+	if err != nil {
+		return result, err
+	}
+	if key == nil {
+		return result, ErrNoEncryptionKey
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.Root, "dm", "*", "*.json"))
+	if err != nil {
+		return result, err
+	}
+	for _, path := range paths {
+		result.Scanned++
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return result, err
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+		if msg.Encrypted() != decrypt {
+			result.Skipped++
+			continue
+		}
+
+		var next *Message
+		if decrypt {
+			next, err = openMessage(&msg, key)
+		} else {
+			next, err = sealMessage(&msg, key)
+		}
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", path, err)
+		}
+		result.Rewritten++
+		result.Paths = append(result.Paths, path)
+		if dryRun {
+			continue
+		}
+		encoded, err := marshalMessage(next)
+		if err != nil {
+			return result, err
+		}
+		if err := replaceFilePerm(path, encoded, dmFilePerm); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func replaceFilePerm(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".migrate-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil && !errors.Is(err, os.ErrPermission) {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
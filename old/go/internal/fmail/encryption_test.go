@@ -0,0 +1,135 @@
+package fmail
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newEncryptedStore(t *testing.T, root, keyDir string) *Store {
+	t.Helper()
+	store, err := NewStore(root, WithKeyDir(keyDir))
+	require.NoError(t, err)
+	_, err = store.EnsureProject("proj-test")
+	require.NoError(t, err)
+	return store
+}
+
+func TestStoreEncryptsDMsAtRest(t *testing.T) {
+	root := t.TempDir()
+	keyDir := t.TempDir()
+	store := newEncryptedStore(t, root, keyDir)
+	require.False(t, store.EncryptionEnabled())
+
+	path, err := store.GenerateKey()
+	require.NoError(t, err)
+	require.Equal(t, "proj-test.key", strings.TrimPrefix(path, keyDir+string(os.PathSeparator)))
+	require.True(t, store.EncryptionEnabled())
+	_, err = store.GenerateKey()
+	require.ErrorIs(t, err, ErrKeyExists)
+
+	body := map[string]any{"task": "secret plans"}
+	id, err := store.SaveMessage(&Message{From: "alice", To: "@bob", Body: body})
+	require.NoError(t, err)
+	topicID, err := store.SaveMessage(&Message{From: "alice", To: "task", Body: "public"})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(store.DMMessagePath("bob", id))
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "secret plans")
+	require.Contains(t, string(raw), EncryptionAlgorithm)
+	raw, err = os.ReadFile(store.TopicMessagePath("task", topicID))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), "public")
+
+	// A fresh store loads the keyfile and decrypts transparently.
+	reader := newEncryptedStore(t, root, keyDir)
+	list, err := reader.ListDMMessages("bob")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.False(t, list[0].Encrypted())
+	require.Equal(t, body, list[0].Body)
+
+	// Without the key the message stays sealed.
+	outsider := newEncryptedStore(t, root, t.TempDir())
+	sealed, err := outsider.ReadMessage(store.DMMessagePath("bob", id))
+	require.NoError(t, err)
+	require.True(t, sealed.Encrypted())
+	require.NotEqual(t, body, sealed.Body)
+}
+
+func TestStoreRejectsTamperedEnvelope(t *testing.T) {
+	root := t.TempDir()
+	key := make([]byte, keySize)
+	store, err := NewStore(root, WithEncryptionKey(key))
+	require.NoError(t, err)
+
+	id, err := store.SaveMessage(&Message{From: "alice", To: "@bob", Body: "hi"})
+	require.NoError(t, err)
+	path := store.DMMessagePath("bob", id)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(raw, &msg))
+	msg.From = "mallory"
+	data, err := marshalMessage(&msg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, dmFilePerm))
+
+	_, err = store.ReadMessage(path)
+	require.ErrorIs(t, err, ErrDecryptionFailed)
+}
+
+func TestMigrateDMs(t *testing.T) {
+	root := t.TempDir()
+	keyDir := t.TempDir()
+	store := newEncryptedStore(t, root, keyDir)
+
+	_, err := store.MigrateDMs(false, false)
+	require.ErrorIs(t, err, ErrNoEncryptionKey)
+
+	first, err := store.SaveMessage(&Message{From: "alice", To: "@bob", Body: "one"})
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "bob", To: "@alice", Body: "two"})
+	require.NoError(t, err)
+
+	store = newEncryptedStore(t, root, keyDir)
+	_, err = store.GenerateKey()
+	require.NoError(t, err)
+
+	result, err := store.MigrateDMs(false, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Rewritten)
+	raw, err := os.ReadFile(store.DMMessagePath("bob", first))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"one"`)
+
+	result, err = store.MigrateDMs(false, false)
+	require.NoError(t, err)
+	require.Equal(t, DMMigration{Scanned: 2, Rewritten: 2, Paths: result.Paths}, result)
+	raw, err = os.ReadFile(store.DMMessagePath("bob", first))
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), `"one"`)
+
+	list, err := store.ListDMMessages("bob")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "one", list[0].Body)
+
+	result, err = store.MigrateDMs(false, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, result.Rewritten)
+	require.Equal(t, 2, result.Skipped)
+
+	result, err = store.MigrateDMs(true, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Rewritten)
+	raw, err = os.ReadFile(store.DMMessagePath("bob", first))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"one"`)
+	require.NotContains(t, string(raw), "encryption")
+}
@@ -33,6 +33,7 @@ func runGC(cmd *cobra.Command, args []string) error {
 		return Exitf(ExitCodeFailure, "gc scan: %v", err)
 	}
 
+	removed := 0
 	for _, file := range files {
 		fileTime := file.modTime.UTC()
 		if ts, ok := parseMessageTime(filepath.Base(file.path)); ok {
@@ -57,6 +58,16 @@ func runGC(cmd *cobra.Command, args []string) error {
 			}
 			return Exitf(ExitCodeFailure, "remove %s: %v", file.path, err)
 		}
+		removed++
+	}
+
+	// Drop removed messages from an existing search index.
+	if removed > 0 {
+		if _, err := os.Stat(store.SearchIndexPath()); err == nil {
+			if _, err := store.RebuildSearchIndex(); err != nil {
+				return Exitf(ExitCodeFailure, "gc: reindex: %v", err)
+			}
+		}
 	}
 	return nil
 }
@@ -99,6 +110,21 @@ func listGCFiles(store *Store) ([]messageFile, error) {
 		files = append(files, list...)
 	}
 
+	groupNames, err := listSubDirs(store.GroupsDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groupNames {
+		if _, err := NormalizeGroupName(group); err != nil {
+			continue
+		}
+		list, err := listFilesInDir(store.GroupThreadDir(group))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, list...)
+	}
+
 	return files, nil
 }
 
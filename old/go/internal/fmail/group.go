@@ -0,0 +1,321 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GroupTargetPrefix marks a group target, e.g. "#frontend-team".
+const GroupTargetPrefix = "#"
+
+const (
+	groupDirPerm  = 0o755
+	groupFilePerm = 0o644
+)
+
+// Group is a named set of agents stored under .fmail/groups. Sending to
+// "#name" writes the message to the group thread and fans out a DM copy,
+// tagged with the group name, to every member except the sender.
+type Group struct {
+	Name      string    `json:"name"`
+	Members   []string  `json:"members"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// NormalizeGroupName lowercases and validates a group name, accepting an
+// optional leading "#". Names follow the topic naming rules.
+func NormalizeGroupName(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), GroupTargetPrefix))
+	if normalized == "" || !namePattern.MatchString(normalized) {
+		return "", ErrInvalidGroup
+	}
+	return normalized, nil
+}
+
+// IsGroupTarget reports whether target addresses a group.
+func IsGroupTarget(target string) bool {
+	return strings.HasPrefix(strings.TrimSpace(target), GroupTargetPrefix)
+}
+
+// HasMember reports whether agent belongs to the group.
+func (g *Group) HasMember(agent string) bool {
+	for _, member := range g.Members {
+		if strings.EqualFold(member, agent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) GroupsDir() string {
+	return filepath.Join(s.Root, "groups")
+}
+
+// GroupThreadDir holds the canonical copy of every message sent to a group.
+func (s *Store) GroupThreadDir(name string) string {
+	return filepath.Join(s.GroupsDir(), name)
+}
+
+// ThreadDir returns the directory holding messages for a topic or "#group"
+// target.
+func (s *Store) ThreadDir(target string) string {
+	if IsGroupTarget(target) {
+		return s.GroupThreadDir(strings.TrimPrefix(target, GroupTargetPrefix))
+	}
+	return s.TopicDir(target)
+}
+
+func (s *Store) groupPath(name string) (string, string, error) {
+	normalized, err := NormalizeGroupName(name)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(s.GroupsDir(), normalized+".json"), normalized, nil
+}
+
+// SaveGroup creates a group or replaces its member list. Members are
+// normalized, deduplicated, and sorted.
+func (s *Store) SaveGroup(name string, members []string, createdBy string) (*Group, error) {
+	path, normalized, err := s.groupPath(name)
+	if err != nil {
+		return nil, err
+	}
+	cleaned, err := normalizeGroupMembers(members)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	if err := ensureDirPerm(s.GroupsDir(), groupDirPerm); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	group, exists, err := readGroup(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		group = &Group{Name: normalized, Created: now, CreatedBy: strings.TrimSpace(createdBy)}
+	}
+	group.Name = normalized
+	group.Members = cleaned
+	group.Updated = now
+
+	data, err := json.MarshalIndent(group, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, groupFilePerm); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateGroupMembers adds and removes members of an existing group.
+func (s *Store) UpdateGroupMembers(name string, add, remove []string) (*Group, error) {
+	group, err := s.ReadGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	drop := make(map[string]bool, len(remove))
+	for _, raw := range remove {
+		member, err := NormalizeAgentName(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+		if err != nil {
+			return nil, err
+		}
+		drop[member] = true
+	}
+	members := make([]string, 0, len(group.Members)+len(add))
+	for _, member := range append(group.Members, add...) {
+		if !drop[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(member), "@"))] {
+			members = append(members, member)
+		}
+	}
+	return s.SaveGroup(group.Name, members, group.CreatedBy)
+}
+
+// ReadGroup loads a group by name. Missing groups return ErrGroupNotFound.
+func (s *Store) ReadGroup(name string) (*Group, error) {
+	path, normalized, err := s.groupPath(name)
+	if err != nil {
+		return nil, err
+	}
+	group, exists, err := readGroup(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, normalized)
+	}
+	return group, nil
+}
+
+// DeleteGroup removes a group definition. Its thread and the members' DM
+// copies are kept.
+func (s *Store) DeleteGroup(name string) error {
+	path, normalized, err := s.groupPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrGroupNotFound, normalized)
+		}
+		return err
+	}
+	return nil
+}
+
+// ListGroups returns all groups sorted by name.
+func (s *Store) ListGroups() ([]Group, error) {
+	entries, err := os.ReadDir(s.GroupsDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	groups := make([]Group, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		group, exists, err := readGroup(filepath.Join(s.GroupsDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// ListGroupMessages returns the group thread, oldest first.
+func (s *Store) ListGroupMessages(name string) ([]Message, error) {
+	normalized, err := NormalizeGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.listMessages(s.GroupThreadDir(normalized))
+}
+
+// saveGroupMessage writes message (addressed to "#name") to the group
+// thread and a copy with the same ID to each member's DM directory, so
+// every view can correlate the copies and replies back to the thread. With
+// exact set, an existing thread message with the same ID is left alone and
+// reported as not persisted.
+func (s *Store) saveGroupMessage(message *Message, exact bool) (bool, error) {
+	group, err := s.ReadGroup(message.To)
+	if err != nil {
+		return false, err
+	}
+	message.To = GroupTargetPrefix + group.Name
+	message.Group = group.Name
+
+	if err := message.Validate(); err != nil {
+		return false, err
+	}
+	if err := s.EnsureRoot(); err != nil {
+		return false, err
+	}
+	dir := s.GroupThreadDir(group.Name)
+	if err := ensureDirPerm(dir, groupDirPerm); err != nil {
+		return false, err
+	}
+
+	persisted := false
+	for attempt := 0; attempt < maxIDRetries; attempt++ {
+		data, err := marshalMessage(message)
+		if err != nil {
+			return false, err
+		}
+		if len(data) > MaxMessageSize {
+			return false, ErrMessageTooLarge
+		}
+		err = writeFileExclusivePerm(filepath.Join(dir, message.ID+".json"), data, groupFilePerm)
+		if err == nil {
+			persisted = true
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return false, err
+		}
+		if exact {
+			return false, nil
+		}
+		message.ID = s.idGenerator(s.now())
+	}
+	if !persisted {
+		return false, ErrIDCollision
+	}
+	s.indexMessage(message)
+
+	for _, member := range group.Members {
+		if member == message.From {
+			continue
+		}
+		copied := *message
+		copied.To = "@" + member
+		memberDir, err := s.ensureDMDir(member)
+		if err != nil {
+			return true, err
+		}
+		data, err := s.marshalDM(&copied)
+		if err != nil {
+			return true, err
+		}
+		if err := writeFileExclusivePerm(filepath.Join(memberDir, copied.ID+".json"), data, dmFilePerm); err != nil && !errors.Is(err, os.ErrExist) {
+			return true, fmt.Errorf("deliver to @%s: %w", member, err)
+		}
+	}
+	return true, nil
+}
+
+func normalizeGroupMembers(members []string) ([]string, error) {
+	seen := make(map[string]bool, len(members))
+	out := make([]string, 0, len(members))
+	for _, raw := range members {
+		member, err := NormalizeAgentName(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid member %q: %w", raw, err)
+		}
+		if seen[member] {
+			continue
+		}
+		seen[member] = true
+		out = append(out, member)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("group needs at least one member")
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func readGroup(path string) (*Group, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var group Group
+	if err := json.Unmarshal(data, &group); err != nil {
+		return nil, false, err
+	}
+	return &group, true, nil
+}
@@ -0,0 +1,209 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group",
+		Aliases: []string{"groups"},
+		Short:   "Manage group conversations",
+		Long: `Manage named agent groups stored in .fmail/groups.
+
+"fmail send #name <message>" writes the message to the group thread and a
+copy to every member's inbox (except the sender). Replies sent to #name with
+--reply-to stay in the same thread. Read the thread with "fmail log #name".`,
+	}
+
+	list := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List groups",
+		Args:    argsMax(0),
+		RunE:    runGroupList,
+	}
+	list.Flags().Bool("json", false, "Output as JSON")
+
+	show := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show group members",
+		Args:  argsRange(1, 1),
+		RunE:  runGroupShow,
+	}
+	show.Flags().Bool("json", false, "Output as JSON")
+
+	create := &cobra.Command{
+		Use:   "create <name> <agent>...",
+		Short: "Create or replace a group",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return usageError(cmd, "expected a group name and at least one member")
+			}
+			return nil
+		},
+		RunE: runGroupCreate,
+	}
+
+	add := &cobra.Command{
+		Use:   "add <name> <agent>...",
+		Short: "Add members to a group",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return usageError(cmd, "expected a group name and at least one member")
+			}
+			return nil
+		},
+		RunE: runGroupAdd,
+	}
+
+	kick := &cobra.Command{
+		Use:   "kick <name> <agent>...",
+		Short: "Remove members from a group",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return usageError(cmd, "expected a group name and at least one member")
+			}
+			return nil
+		},
+		RunE: runGroupKick,
+	}
+
+	remove := &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"remove"},
+		Short:   "Delete a group (its thread is kept)",
+		Args:    argsRange(1, 1),
+		RunE:    runGroupRemove,
+	}
+
+	cmd.AddCommand(list, show, create, add, kick, remove)
+	return cmd
+}
+
+func groupError(name string, err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidGroup):
+		return Exitf(ExitCodeFailure, "invalid group name %q", name)
+	case errors.Is(err, ErrGroupNotFound):
+		return Exitf(ExitCodeFailure, "group %q not found", strings.TrimPrefix(name, GroupTargetPrefix))
+	default:
+		return Exitf(ExitCodeFailure, "group: %v", err)
+	}
+}
+
+func runGroupList(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	groups, err := store.ListGroups()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "list groups: %v", err)
+	}
+
+	if jsonOutput {
+		if groups == nil {
+			groups = []Group{}
+		}
+		payload, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode groups: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "GROUP\tMEMBERS\tUPDATED")
+	for _, group := range groups {
+		fmt.Fprintf(writer, "%s%s\t%s\t%s\n", GroupTargetPrefix, group.Name, strings.Join(group.Members, ","), group.Updated.Format("2006-01-02 15:04"))
+	}
+	if err := writer.Flush(); err != nil {
+		return Exitf(ExitCodeFailure, "write output: %v", err)
+	}
+	return nil
+}
+
+func runGroupShow(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	group, err := store.ReadGroup(args[0])
+	if err != nil {
+		return groupError(args[0], err)
+	}
+	if jsonOutput {
+		payload, err := json.MarshalIndent(group, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode group: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+	for _, member := range group.Members {
+		fmt.Fprintln(cmd.OutOrStdout(), member)
+	}
+	return nil
+}
+
+func runGroupCreate(cmd *cobra.Command, args []string) error {
+	runtime, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	group, err := store.SaveGroup(args[0], args[1:], runtime.Agent)
+	if err != nil {
+		return groupError(args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved group %s%s (%d members)\n", GroupTargetPrefix, group.Name, len(group.Members))
+	return nil
+}
+
+func runGroupAdd(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	group, err := store.UpdateGroupMembers(args[0], args[1:], nil)
+	if err != nil {
+		return groupError(args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s%s: %s\n", GroupTargetPrefix, group.Name, strings.Join(group.Members, ","))
+	return nil
+}
+
+func runGroupKick(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	group, err := store.UpdateGroupMembers(args[0], nil, args[1:])
+	if err != nil {
+		return groupError(args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s%s: %s\n", GroupTargetPrefix, group.Name, strings.Join(group.Members, ","))
+	return nil
+}
+
+func runGroupRemove(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	if err := store.DeleteGroup(args[0]); err != nil {
+		return groupError(args[0], err)
+	}
+	return nil
+}
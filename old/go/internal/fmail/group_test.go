@@ -0,0 +1,94 @@
+package fmail
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupStoreRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	group, err := store.SaveGroup("#Frontend-Team", []string{"@bob", "alice", "bob"}, "lead")
+	require.NoError(t, err)
+	require.Equal(t, "frontend-team", group.Name)
+	require.Equal(t, []string{"alice", "bob"}, group.Members)
+	require.Equal(t, "lead", group.CreatedBy)
+
+	group, err = store.UpdateGroupMembers("frontend-team", []string{"carol"}, []string{"@alice"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", "carol"}, group.Members)
+	require.Equal(t, "lead", group.CreatedBy)
+
+	_, err = store.SaveGroup("../escape", []string{"bob"}, "")
+	require.ErrorIs(t, err, ErrInvalidGroup)
+	_, err = store.SaveGroup("empty", nil, "")
+	require.Error(t, err)
+
+	groups, err := store.ListGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	require.NoError(t, store.DeleteGroup("frontend-team"))
+	_, err = store.ReadGroup("frontend-team")
+	require.ErrorIs(t, err, ErrGroupNotFound)
+}
+
+func TestSaveGroupMessageFansOut(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SaveGroup("team", []string{"alice", "bob", "carol"}, "")
+	require.NoError(t, err)
+
+	_, err = store.SaveMessage(&Message{From: "alice", To: "#nope", Body: "x"})
+	require.ErrorIs(t, err, ErrGroupNotFound)
+
+	msg := &Message{From: "alice", To: "#Team", Body: "ship it", Time: time.Now().UTC()}
+	id, err := store.SaveMessage(msg)
+	require.NoError(t, err)
+	require.Equal(t, "#team", msg.To)
+	require.Equal(t, "team", msg.Group)
+
+	thread, err := store.ListGroupMessages("team")
+	require.NoError(t, err)
+	require.Len(t, thread, 1)
+	require.Equal(t, id, thread[0].ID)
+
+	for _, member := range []string{"bob", "carol"} {
+		inbox, err := store.ListDMMessages(member)
+		require.NoError(t, err)
+		require.Len(t, inbox, 1)
+		require.Equal(t, id, inbox[0].ID)
+		require.Equal(t, "@"+member, inbox[0].To)
+		require.Equal(t, "team", inbox[0].Group)
+	}
+	inbox, err := store.ListDMMessages("alice")
+	require.NoError(t, err)
+	require.Empty(t, inbox, "sender gets no copy")
+
+	reply := &Message{From: "bob", To: "#team", Body: "on it", ReplyTo: id, Time: time.Now().UTC()}
+	_, err = store.SaveMessage(reply)
+	require.NoError(t, err)
+	thread, err = store.ListGroupMessages("team")
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	require.Equal(t, id, thread[1].ReplyTo)
+
+	// The all-messages view reads the thread and skips the members' copies.
+	files, err := listMessageFiles(store, watchTarget{mode: watchAllMessages})
+	require.NoError(t, err)
+	sorts, err := loadMessageSorts(store, files)
+	require.NoError(t, err)
+	sorts = filterMessageSorts(sorts, logFilter{}, watchTarget{mode: watchAllMessages})
+	require.Len(t, sorts, 2)
+	for _, entry := range sorts {
+		require.Equal(t, filepath.Join(store.GroupsDir(), "team"), filepath.Dir(entry.path))
+	}
+
+	target, err := parseWatchTarget("#team")
+	require.NoError(t, err)
+	require.Equal(t, watchTarget{mode: watchGroup, name: "team"}, target)
+}
@@ -83,14 +83,14 @@ func runLogForTarget(cmd *cobra.Command, runtime *Runtime, target watchTarget) e
 	if err != nil {
 		return Exitf(ExitCodeFailure, "log: %v", err)
 	}
-	messages = filterMessageSorts(messages, filter)
+	messages = filterMessageSorts(messages, filter, target)
 	sortMessageSorts(messages)
 	if limit > 0 && len(messages) > limit {
 		messages = messages[len(messages)-limit:]
 	}
 
 	for _, entry := range messages {
-		if err := writeWatchMessage(cmd.OutOrStdout(), entry.message, jsonOutput); err != nil {
+		if err := writeWatchMessage(cmd.OutOrStdout(), entry.message, watchOptions{jsonOutput: jsonOutput}); err != nil {
 			return Exitf(ExitCodeFailure, "output: %v", err)
 		}
 	}
@@ -118,7 +118,7 @@ func followLog(cmd *cobra.Command, store *Store, target watchTarget, seen map[st
 				return Exitf(ExitCodeFailure, "follow: %v", err)
 			}
 			for _, message := range filterMessages(messages, filter) {
-				if err := writeWatchMessage(cmd.OutOrStdout(), message, jsonOutput); err != nil {
+				if err := writeWatchMessage(cmd.OutOrStdout(), message, watchOptions{jsonOutput: jsonOutput}); err != nil {
 					return Exitf(ExitCodeFailure, "output: %v", err)
 				}
 			}
@@ -140,13 +140,13 @@ func filterMessages(messages []*Message, filter logFilter) []*Message {
 	return filtered
 }
 
-func filterMessageSorts(messages []messageSort, filter logFilter) []messageSort {
+func filterMessageSorts(messages []messageSort, filter logFilter, target watchTarget) []messageSort {
 	filtered := make([]messageSort, 0, len(messages))
 	for _, message := range messages {
 		if message.message == nil {
 			continue
 		}
-		if !filter.match(message.message) {
+		if !filter.match(message.message) || !target.includes(message.message) {
 			continue
 		}
 		filtered = append(filtered, message)
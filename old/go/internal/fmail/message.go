@@ -23,6 +23,13 @@ type Message struct {
 	Priority string    `json:"priority,omitempty"`
 	Host     string    `json:"host,omitempty"`
 	Tags     []string  `json:"tags,omitempty"`
+	// Group names the group a message was sent to. It is set on the group
+	// thread copy and on each member's DM copy.
+	Group string `json:"group,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Encryption is set on DMs whose body is stored encrypted.
+	Encryption *Encryption `json:"encryption,omitempty"`
 }
 
 var idCounter uint32
@@ -58,6 +65,11 @@ func (m *Message) Validate() error {
 	if m.Body == nil {
 		return fmt.Errorf("missing body")
 	}
+	if m.Group != "" {
+		if _, err := NormalizeGroupName(m.Group); err != nil {
+			return fmt.Errorf("invalid group: %w", err)
+		}
+	}
 	if m.Priority != "" {
 		if err := ValidatePriority(m.Priority); err != nil {
 			return err
@@ -68,6 +80,11 @@ func (m *Message) Validate() error {
 			return err
 		}
 	}
+	for _, attachment := range m.Attachments {
+		if err := attachment.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
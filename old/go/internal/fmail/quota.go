@@ -0,0 +1,353 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const quotaFilePerm = 0o644
+
+// QuotaLimits caps how much of the store one agent's messages may take up.
+// Zero fields are unlimited.
+type QuotaLimits struct {
+	MaxMessages int   `json:"max_messages,omitempty"`
+	MaxBytes    int64 `json:"max_bytes,omitempty"`
+	// MaxSharePercent caps the agent's share of all stored message bytes. It
+	// only applies once other agents have messages in the store too.
+	MaxSharePercent float64 `json:"max_share_percent,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (l QuotaLimits) IsZero() bool {
+	return l.MaxMessages <= 0 && l.MaxBytes <= 0 && l.MaxSharePercent <= 0
+}
+
+// QuotaConfig is stored in .fmail/quotas.json. An agent's own entry replaces
+// the default entirely.
+type QuotaConfig struct {
+	Default QuotaLimits            `json:"default"`
+	Agents  map[string]QuotaLimits `json:"agents,omitempty"`
+	Updated time.Time              `json:"updated,omitempty"`
+}
+
+// LimitsFor returns the limits that apply to agent.
+func (c *QuotaConfig) LimitsFor(agent string) QuotaLimits {
+	if c == nil {
+		return QuotaLimits{}
+	}
+	if limits, ok := c.Agents[strings.ToLower(strings.TrimSpace(agent))]; ok {
+		return limits
+	}
+	return c.Default
+}
+
+// Enabled reports whether any limit is configured.
+func (c *QuotaConfig) Enabled() bool {
+	if c == nil {
+		return false
+	}
+	if !c.Default.IsZero() {
+		return true
+	}
+	for _, limits := range c.Agents {
+		if !limits.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// MailboxUsage is one agent's footprint in the store. Sent counts every
+// file the agent authored, including the DM copies a group send fans out;
+// Inbox counts the agent's DM directory.
+type MailboxUsage struct {
+	Agent      string      `json:"agent"`
+	Sent       int         `json:"sent"`
+	SentBytes  int64       `json:"sent_bytes"`
+	Inbox      int         `json:"inbox"`
+	InboxBytes int64       `json:"inbox_bytes"`
+	Share      float64     `json:"share_percent"`
+	Limits     QuotaLimits `json:"limits"`
+	Over       bool        `json:"over_quota,omitempty"`
+}
+
+// StoreUsage summarizes message storage per agent.
+type StoreUsage struct {
+	Messages      int            `json:"messages"`
+	Bytes         int64          `json:"bytes"`
+	Agents        []MailboxUsage `json:"agents"`
+	QuotasEnabled bool           `json:"quotas_enabled"`
+}
+
+// QuotaError reports a send rejected because it would take the sender over
+// its quota.
+type QuotaError struct {
+	Agent  string
+	Usage  MailboxUsage
+	Limits QuotaLimits
+	// Reason names the exceeded limit, e.g. "120 messages (limit 100)".
+	Reason string
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: %s is at %s", ErrQuotaExceeded, e.Agent, e.Reason)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+func (s *Store) QuotaFile() string {
+	return filepath.Join(s.Root, "quotas.json")
+}
+
+// ReadQuotas loads the quota config. A missing file is an empty config.
+func (s *Store) ReadQuotas() (*QuotaConfig, error) {
+	data, err := os.ReadFile(s.QuotaFile())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &QuotaConfig{}, nil
+		}
+		return nil, err
+	}
+	var config QuotaConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", s.QuotaFile(), err)
+	}
+	return &config, nil
+}
+
+// SetQuota replaces the limits for agent, or the default limits when agent
+// is empty. Zero limits remove an agent's entry so the default applies again.
+func (s *Store) SetQuota(agent string, limits QuotaLimits) (*QuotaConfig, error) {
+	config, err := s.ReadQuotas()
+	if err != nil {
+		return nil, err
+	}
+	agent = strings.TrimSpace(agent)
+	if agent == "" {
+		config.Default = limits
+	} else {
+		normalized, err := NormalizeAgentName(strings.TrimPrefix(agent, "@"))
+		if err != nil {
+			return nil, err
+		}
+		if config.Agents == nil {
+			config.Agents = make(map[string]QuotaLimits)
+		}
+		if limits.IsZero() {
+			delete(config.Agents, normalized)
+		} else {
+			config.Agents[normalized] = limits
+		}
+	}
+	config.Updated = s.now()
+
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(s.QuotaFile(), data, quotaFilePerm); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Usage scans the store and returns per-agent message counts and sizes,
+// largest senders first, with the configured limits applied.
+func (s *Store) Usage() (*StoreUsage, error) {
+	config, err := s.ReadQuotas()
+	if err != nil {
+		return nil, err
+	}
+	files, err := listGCFiles(s)
+	if err != nil {
+		return nil, err
+	}
+
+	dmRoot := filepath.Join(s.Root, "dm") + string(filepath.Separator)
+	byAgent := make(map[string]*MailboxUsage)
+	entry := func(agent string) *MailboxUsage {
+		usage, ok := byAgent[agent]
+		if !ok {
+			usage = &MailboxUsage{Agent: agent}
+			byAgent[agent] = usage
+		}
+		return usage
+	}
+
+	total := &StoreUsage{QuotasEnabled: config.Enabled()}
+	for _, file := range files {
+		size, from, err := readMessageFootprint(file.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		total.Messages++
+		total.Bytes += size
+		if from != "" {
+			sender := entry(from)
+			sender.Sent++
+			sender.SentBytes += size
+		}
+		if strings.HasPrefix(file.path, dmRoot) {
+			owner := filepath.Base(filepath.Dir(file.path))
+			inbox := entry(owner)
+			inbox.Inbox++
+			inbox.InboxBytes += size
+		}
+	}
+
+	for _, usage := range byAgent {
+		if total.Bytes > 0 {
+			usage.Share = float64(usage.SentBytes) * 100 / float64(total.Bytes)
+		}
+		usage.Limits = config.LimitsFor(usage.Agent)
+		_, usage.Over = quotaExceeded(*usage, usage.Limits, total, 0, 0)
+		total.Agents = append(total.Agents, *usage)
+	}
+	sort.Slice(total.Agents, func(i, j int) bool {
+		if total.Agents[i].SentBytes != total.Agents[j].SentBytes {
+			return total.Agents[i].SentBytes > total.Agents[j].SentBytes
+		}
+		return total.Agents[i].Agent < total.Agents[j].Agent
+	})
+	return total, nil
+}
+
+// CheckQuota returns a *QuotaError when storing messages more files of
+// bytes in total from agent would exceed its limits. Without any configured
+// quota it does not scan the store.
+func (s *Store) CheckQuota(agent string, messages int, bytes int64) error {
+	config, err := s.ReadQuotas()
+	if err != nil {
+		return err
+	}
+	normalized, err := NormalizeAgentName(agent)
+	if err != nil {
+		return err
+	}
+	limits := config.LimitsFor(normalized)
+	if limits.IsZero() {
+		return nil
+	}
+	usage, err := s.Usage()
+	if err != nil {
+		return err
+	}
+	current := MailboxUsage{Agent: normalized}
+	for _, candidate := range usage.Agents {
+		if candidate.Agent == normalized {
+			current = candidate
+			break
+		}
+	}
+	if reason, over := quotaExceeded(current, limits, usage, messages, bytes); over {
+		return &QuotaError{Agent: normalized, Usage: current, Limits: limits, Reason: reason}
+	}
+	return nil
+}
+
+// CheckSendQuota reports a *QuotaError when storing message would take its
+// sender over quota. A group message is charged for the thread copy plus one
+// DM copy per other member.
+func (s *Store) CheckSendQuota(message *Message) error {
+	data, err := marshalMessage(message)
+	if err != nil {
+		return err
+	}
+	copies := 1
+	if IsGroupTarget(message.To) {
+		if group, err := s.ReadGroup(message.To); err == nil {
+			for _, member := range group.Members {
+				if !strings.EqualFold(member, message.From) {
+					copies++
+				}
+			}
+		}
+	}
+	return s.CheckQuota(message.From, copies, int64(len(data)*copies))
+}
+
+// quotaExceeded checks usage plus a pending write of messages files and
+// bytes against limits. With nothing pending it reports whether the agent
+// is already over.
+func quotaExceeded(usage MailboxUsage, limits QuotaLimits, total *StoreUsage, messages int, bytes int64) (string, bool) {
+	sent := usage.Sent + messages
+	sentBytes := usage.SentBytes + bytes
+	if limits.MaxMessages > 0 && sent > limits.MaxMessages {
+		return fmt.Sprintf("%d messages (limit %d)", sent, limits.MaxMessages), true
+	}
+	if limits.MaxBytes > 0 && sentBytes > limits.MaxBytes {
+		return fmt.Sprintf("%s (limit %s)", FormatAttachmentSize(sentBytes), FormatAttachmentSize(limits.MaxBytes)), true
+	}
+	if limits.MaxSharePercent > 0 && total != nil {
+		storeBytes := total.Bytes + bytes
+		others := total.Bytes - usage.SentBytes
+		if others > 0 && storeBytes > 0 {
+			share := float64(sentBytes) * 100 / float64(storeBytes)
+			if share > limits.MaxSharePercent {
+				return fmt.Sprintf("%.1f%% of the store (limit %s%%)", share, strconv.FormatFloat(limits.MaxSharePercent, 'f', -1, 64)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// readMessageFootprint returns a stored message's size on disk and author.
+// Encrypted DMs keep their sender in the clear, so no key is needed.
+func readMessageFootprint(path string) (int64, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, "", err
+	}
+	var header struct {
+		From string `json:"from"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return int64(len(data)), "", nil
+	}
+	return int64(len(data)), strings.ToLower(strings.TrimSpace(header.From)), nil
+}
+
+// ParseQuotaSize parses a byte size such as 500KB, 10MB, 1GB, or a plain
+// number of bytes.
+func ParseQuotaSize(value string) (int64, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(value))
+	if trimmed == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		scale  int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(trimmed, unit.suffix) {
+			trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, unit.suffix))
+			multiplier = unit.scale
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
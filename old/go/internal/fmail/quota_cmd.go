@@ -0,0 +1,231 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newQuotaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "quota [@agent]",
+		Aliases: []string{"quotas"},
+		Short:   "Show per-agent mailbox usage and quotas",
+		Long: `Show how many messages each agent has sent and received and how much of the
+store they take up.
+
+Quotas are stored in .fmail/quotas.json. "fmail quota set" caps the messages
+an agent may have in the store (--max-messages), their total size
+(--max-size), or their share of all stored message bytes (--max-share).
+Sends that would go over are rejected until old messages are removed with
+"fmail gc" or the quota is raised. Without an agent, set changes the default
+that applies to every agent without its own entry.`,
+		Args: argsMax(1),
+		RunE: runQuotaShow,
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+
+	set := &cobra.Command{
+		Use:   "set [@agent]",
+		Short: "Set an agent's quota, or the default quota",
+		Args:  argsMax(1),
+		RunE:  runQuotaSet,
+	}
+	set.Flags().Int("max-messages", 0, "Maximum messages the agent may have in the store (0 = unlimited)")
+	set.Flags().String("max-size", "", "Maximum total size of the agent's messages, e.g. 50MB (0 = unlimited)")
+	set.Flags().Float64("max-share", 0, "Maximum percent of all stored message bytes (0 = unlimited)")
+
+	remove := &cobra.Command{
+		Use:     "rm [@agent]",
+		Aliases: []string{"remove"},
+		Short:   "Remove an agent's quota, or the default quota",
+		Args:    argsMax(1),
+		RunE:    runQuotaRemove,
+	}
+
+	cmd.AddCommand(set, remove)
+	return cmd
+}
+
+func runQuotaShow(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	usage, err := store.Usage()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "quota: %v", err)
+	}
+	if len(args) == 1 {
+		agent, err := NormalizeAgentName(strings.TrimPrefix(strings.TrimSpace(args[0]), "@"))
+		if err != nil {
+			return Exitf(ExitCodeFailure, "invalid agent %q", args[0])
+		}
+		usage.Agents = filterMailboxUsage(usage.Agents, agent)
+		if len(usage.Agents) == 0 {
+			config, err := store.ReadQuotas()
+			if err != nil {
+				return Exitf(ExitCodeFailure, "quota: %v", err)
+			}
+			usage.Agents = []MailboxUsage{{Agent: agent, Limits: config.LimitsFor(agent)}}
+		}
+	}
+	if usage.Agents == nil {
+		usage.Agents = []MailboxUsage{}
+	}
+
+	if jsonOutput {
+		payload, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode quota: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Store: %d messages, %s\n\n", usage.Messages, FormatAttachmentSize(usage.Bytes))
+	writer := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "AGENT\tSENT\tSENT SIZE\tSHARE\tINBOX\tINBOX SIZE\tQUOTA")
+	for _, agent := range usage.Agents {
+		quota := formatQuotaLimits(agent.Limits)
+		if agent.Over {
+			quota += " (over)"
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%.1f%%\t%d\t%s\t%s\n",
+			agent.Agent, agent.Sent, FormatAttachmentSize(agent.SentBytes), agent.Share,
+			agent.Inbox, FormatAttachmentSize(agent.InboxBytes), quota)
+	}
+	if err := writer.Flush(); err != nil {
+		return Exitf(ExitCodeFailure, "write output: %v", err)
+	}
+	return nil
+}
+
+func runQuotaSet(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("max-messages") && !cmd.Flags().Changed("max-size") && !cmd.Flags().Changed("max-share") {
+		return usageError(cmd, "set at least one of --max-messages, --max-size, --max-share")
+	}
+
+	limits := QuotaLimits{}
+	limits.MaxMessages, _ = cmd.Flags().GetInt("max-messages")
+	if limits.MaxMessages < 0 {
+		return usageError(cmd, "max-messages must be >= 0")
+	}
+	if raw, _ := cmd.Flags().GetString("max-size"); strings.TrimSpace(raw) != "" {
+		limits.MaxBytes, err = ParseQuotaSize(raw)
+		if err != nil {
+			return usageError(cmd, "%v", err)
+		}
+	}
+	limits.MaxSharePercent, _ = cmd.Flags().GetFloat64("max-share")
+	if limits.MaxSharePercent < 0 || limits.MaxSharePercent > 100 {
+		return usageError(cmd, "max-share must be between 0 and 100")
+	}
+
+	agent := quotaAgentArg(args)
+	if _, err := store.SetQuota(agent, limits); err != nil {
+		return quotaError(agent, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Quota for %s: %s\n", quotaSubject(agent), formatQuotaLimits(limits))
+	return nil
+}
+
+func runQuotaRemove(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	agent := quotaAgentArg(args)
+	if _, err := store.SetQuota(agent, QuotaLimits{}); err != nil {
+		return quotaError(agent, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed quota for %s\n", quotaSubject(agent))
+	return nil
+}
+
+// enforceSendQuota rejects a send that would take the sender over its
+// quota. Sends written to the store are checked by SaveMessage; this covers
+// sends forged writes and lets callers reject before doing other work.
+func enforceSendQuota(runtime *Runtime, message *Message) error {
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	if err := store.CheckSendQuota(message); err != nil {
+		if exitErr := quotaExitError(err); exitErr != nil {
+			return exitErr
+		}
+		return Exitf(ExitCodeFailure, "check quota: %v", err)
+	}
+	return nil
+}
+
+// quotaExitError turns a *QuotaError into the CLI error that says how to
+// free space, or returns nil for any other error.
+func quotaExitError(err error) *ExitError {
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		return nil
+	}
+	return Exitf(ExitCodeFailure, "%v; free space with \"fmail gc\" or raise the limit with \"fmail quota set @%s\" (see \"fmail quota @%s\")",
+		quotaErr, quotaErr.Agent, quotaErr.Agent)
+}
+
+func filterMailboxUsage(agents []MailboxUsage, agent string) []MailboxUsage {
+	for _, usage := range agents {
+		if usage.Agent == agent {
+			return []MailboxUsage{usage}
+		}
+	}
+	return nil
+}
+
+func quotaAgentArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(args[0]), "@")
+}
+
+func quotaSubject(agent string) string {
+	if agent == "" {
+		return "all agents (default)"
+	}
+	return "@" + strings.ToLower(agent)
+}
+
+func quotaError(agent string, err error) error {
+	if errors.Is(err, ErrInvalidAgent) {
+		return Exitf(ExitCodeFailure, "invalid agent %q", agent)
+	}
+	return Exitf(ExitCodeFailure, "quota: %v", err)
+}
+
+func formatQuotaLimits(limits QuotaLimits) string {
+	if limits.IsZero() {
+		return "unlimited"
+	}
+	parts := make([]string, 0, 3)
+	if limits.MaxMessages > 0 {
+		parts = append(parts, fmt.Sprintf("%d msgs", limits.MaxMessages))
+	}
+	if limits.MaxBytes > 0 {
+		parts = append(parts, FormatAttachmentSize(limits.MaxBytes))
+	}
+	if limits.MaxSharePercent > 0 {
+		parts = append(parts, strconv.FormatFloat(limits.MaxSharePercent, 'f', -1, 64)+"%")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,136 @@
+package fmail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreUsageCountsSentAndInbox(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SaveGroup("team", []string{"alice", "bob", "carol"}, "")
+	require.NoError(t, err)
+
+	for _, msg := range []*Message{
+		{From: "alice", To: "build", Body: "green"},
+		{From: "alice", To: "#team", Body: "ship it"},
+		{From: "bob", To: "@alice", Body: "thanks"},
+	} {
+		_, err := store.SaveMessage(msg)
+		require.NoError(t, err)
+	}
+
+	usage, err := store.Usage()
+	require.NoError(t, err)
+	require.Equal(t, 5, usage.Messages, "topic + group thread + 2 fan-out copies + DM")
+	require.False(t, usage.QuotasEnabled)
+
+	byAgent := make(map[string]MailboxUsage)
+	for _, agent := range usage.Agents {
+		byAgent[agent.Agent] = agent
+	}
+	require.Equal(t, "alice", usage.Agents[0].Agent, "largest sender first")
+	require.Equal(t, 4, byAgent["alice"].Sent)
+	require.Equal(t, 1, byAgent["alice"].Inbox)
+	require.Equal(t, 1, byAgent["bob"].Sent)
+	require.Equal(t, 1, byAgent["bob"].Inbox)
+	require.Equal(t, 0, byAgent["carol"].Sent)
+	require.Equal(t, 1, byAgent["carol"].Inbox)
+	require.InDelta(t, 100, byAgent["alice"].Share+byAgent["bob"].Share, 0.01)
+}
+
+func TestCheckQuotaRejectsOverLimit(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := store.SaveMessage(&Message{From: "alice", To: "build", Body: "step"})
+		require.NoError(t, err)
+	}
+	_, err = store.SaveMessage(&Message{From: "bob", To: "build", Body: "ack"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.CheckQuota("alice", 1, 100), "no quota configured")
+
+	_, err = store.SetQuota("", QuotaLimits{MaxMessages: 3})
+	require.NoError(t, err)
+	err = store.CheckQuota("alice", 1, 100)
+	var quotaErr *QuotaError
+	require.ErrorAs(t, err, &quotaErr)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	require.Equal(t, "alice", quotaErr.Agent)
+	require.Contains(t, err.Error(), "4 messages (limit 3)")
+	require.NoError(t, store.CheckQuota("bob", 1, 100))
+
+	// An agent entry replaces the default.
+	_, err = store.SetQuota("@Alice", QuotaLimits{MaxSharePercent: 90})
+	require.NoError(t, err)
+	require.NoError(t, store.CheckQuota("alice", 1, 10))
+	err = store.CheckQuota("alice", 1, 1<<20)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	require.Contains(t, err.Error(), "of the store")
+
+	usage, err := store.Usage()
+	require.NoError(t, err)
+	require.True(t, usage.QuotasEnabled)
+	for _, agent := range usage.Agents {
+		require.False(t, agent.Over, "%s is at its limit, not over", agent.Agent)
+	}
+
+	_, err = store.SetQuota("alice", QuotaLimits{})
+	require.NoError(t, err)
+	config, err := store.ReadQuotas()
+	require.NoError(t, err)
+	require.NotContains(t, config.Agents, "alice")
+	require.Equal(t, 3, config.LimitsFor("alice").MaxMessages)
+}
+
+func TestEnforceSendQuotaChargesGroupFanOut(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SaveGroup("team", []string{"alice", "bob", "carol"}, "")
+	require.NoError(t, err)
+	_, err = store.SetQuota("alice", QuotaLimits{MaxMessages: 2})
+	require.NoError(t, err)
+
+	runtime := &Runtime{Root: root, Agent: "alice"}
+	require.NoError(t, enforceSendQuota(runtime, &Message{From: "alice", To: "build", Body: "x"}))
+
+	err = enforceSendQuota(runtime, &Message{From: "alice", To: "#team", Body: "x"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "3 messages (limit 2)")
+	require.Contains(t, err.Error(), "fmail quota set @alice")
+}
+
+func TestSaveMessageRejectsSenderOverQuota(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SetQuota("alice", QuotaLimits{MaxMessages: 1})
+	require.NoError(t, err)
+
+	_, err = store.SaveMessage(&Message{From: "alice", To: "build", Body: "one"})
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "alice", To: "@bob", Body: "two"})
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	_, err = store.SaveMessage(&Message{From: "bob", To: "build", Body: "ack"})
+	require.NoError(t, err, "other senders are not limited")
+}
+
+func TestParseQuotaSize(t *testing.T) {
+	for input, want := range map[string]int64{
+		"1024":  1024,
+		"10KB":  10 << 10,
+		"1.5mb": 3 << 19,
+		"2GB":   2 << 30,
+		"0":     0,
+	} {
+		got, err := ParseQuotaSize(input)
+		require.NoError(t, err, input)
+		require.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "lots", "-1MB"} {
+		_, err := ParseQuotaSize(input)
+		require.Error(t, err, input)
+	}
+}
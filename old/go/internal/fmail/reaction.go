@@ -0,0 +1,292 @@
+package fmail
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxReactionBytes = 32
+
+// ReactionShortcodes maps the accepted shortcodes to their emoji. A
+// shortcode may also be written with surrounding colons (":eyes:").
+var ReactionShortcodes = map[string]string{
+	"+1":       "👍",
+	"thumbsup": "👍",
+	"-1":       "👎",
+	"eyes":     "👀",
+	"check":    "✅",
+	"x":        "❌",
+	"rocket":   "🚀",
+	"tada":     "🎉",
+	"heart":    "❤️",
+	"laugh":    "😄",
+	"thinking": "🤔",
+	"fire":     "🔥",
+	"warning":  "⚠️",
+}
+
+// Reaction is one agent's emoji reaction to a message. Each reaction is a
+// tiny record at reactions/<message-id>/<agent>-<emoji-hex>.json, so adding
+// and removing never rewrites the message or races other reactors.
+type Reaction struct {
+	MessageID string    `json:"message_id"`
+	Emoji     string    `json:"emoji"`
+	From      string    `json:"from"`
+	Time      time.Time `json:"time"`
+}
+
+// ReactionCount aggregates the reactions of one emoji on a message.
+type ReactionCount struct {
+	Emoji string   `json:"emoji"`
+	Count int      `json:"count"`
+	From  []string `json:"from"`
+}
+
+// NormalizeReaction resolves a shortcode or validates a literal emoji.
+// Letters and digits are rejected so reactions stay emoji, not text.
+func NormalizeReaction(input string) (string, error) {
+	value := strings.TrimSpace(input)
+	code := strings.ToLower(strings.Trim(value, ":"))
+	if emoji, ok := ReactionShortcodes[code]; ok {
+		return emoji, nil
+	}
+	if value == "" || len(value) > maxReactionBytes {
+		return "", fmt.Errorf("%w: %q", ErrInvalidReaction, input)
+	}
+	for _, r := range value {
+		if unicode.IsSpace(r) || unicode.IsLetter(r) || unicode.IsDigit(r) || r < 0x80 {
+			return "", fmt.Errorf("%w: %q (use an emoji or one of %s)", ErrInvalidReaction, input, strings.Join(reactionShortcodeNames(), ", "))
+		}
+	}
+	return value, nil
+}
+
+func reactionShortcodeNames() []string {
+	names := make([]string, 0, len(ReactionShortcodes))
+	for name := range ReactionShortcodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Store) ReactionsDir() string {
+	return filepath.Join(s.Root, "reactions")
+}
+
+func (s *Store) reactionPath(messageID, emoji, from string) (string, error) {
+	dir, err := s.messageReactionsDir(messageID)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateAgentName(from); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, from+"-"+hex.EncodeToString([]byte(emoji))+".json"), nil
+}
+
+func (s *Store) messageReactionsDir(messageID string) (string, error) {
+	id := strings.TrimSpace(messageID)
+	if id == "" || !namePattern.MatchString(id) {
+		return "", fmt.Errorf("invalid message id %q", messageID)
+	}
+	return filepath.Join(s.ReactionsDir(), id), nil
+}
+
+// AddReaction records from's reaction to a message. Adding a reaction that
+// already exists is a no-op and returns the existing record.
+func (s *Store) AddReaction(messageID, emoji, from string) (*Reaction, error) {
+	emoji, err := NormalizeReaction(emoji)
+	if err != nil {
+		return nil, err
+	}
+	from, err = NormalizeAgentName(from)
+	if err != nil {
+		return nil, err
+	}
+	path, err := s.reactionPath(messageID, emoji, from)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := readReaction(path); err == nil {
+		return existing, nil
+	}
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	if err := ensureDirPerm(filepath.Dir(path), topicDirPerm); err != nil {
+		return nil, err
+	}
+
+	reaction := &Reaction{
+		MessageID: strings.TrimSpace(messageID),
+		Emoji:     emoji,
+		From:      from,
+		Time:      s.now().UTC(),
+	}
+	data, err := json.MarshalIndent(reaction, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileExclusive(path, data); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return readReaction(path)
+		}
+		return nil, err
+	}
+	return reaction, nil
+}
+
+// RemoveReaction deletes from's reaction to a message.
+func (s *Store) RemoveReaction(messageID, emoji, from string) error {
+	emoji, err := NormalizeReaction(emoji)
+	if err != nil {
+		return err
+	}
+	from, err = NormalizeAgentName(from)
+	if err != nil {
+		return err
+	}
+	path, err := s.reactionPath(messageID, emoji, from)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrReactionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ToggleReaction adds from's reaction, or removes it when already present.
+// It reports whether the reaction was added.
+func (s *Store) ToggleReaction(messageID, emoji, from string) (bool, error) {
+	err := s.RemoveReaction(messageID, emoji, from)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrReactionNotFound) {
+		return false, err
+	}
+	if _, err := s.AddReaction(messageID, emoji, from); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListReactions returns the reactions to a message, oldest first.
+func (s *Store) ListReactions(messageID string) ([]Reaction, error) {
+	dir, err := s.messageReactionsDir(messageID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	reactions := make([]Reaction, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		reaction, err := readReaction(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		reactions = append(reactions, *reaction)
+	}
+	sortReactions(reactions)
+	return reactions, nil
+}
+
+// ListAllReactions returns the reactions of every message, keyed by message
+// ID.
+func (s *Store) ListAllReactions() (map[string][]Reaction, error) {
+	entries, err := os.ReadDir(s.ReactionsDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string][]Reaction{}, nil
+		}
+		return nil, err
+	}
+	out := make(map[string][]Reaction, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		reactions, err := s.ListReactions(entry.Name())
+		if err != nil {
+			continue
+		}
+		if len(reactions) > 0 {
+			out[entry.Name()] = reactions
+		}
+	}
+	return out, nil
+}
+
+// AggregateReactions groups reactions by emoji, ordered by each emoji's
+// first use.
+func AggregateReactions(reactions []Reaction) []ReactionCount {
+	sorted := append([]Reaction(nil), reactions...)
+	sortReactions(sorted)
+	index := make(map[string]int)
+	counts := make([]ReactionCount, 0)
+	for _, reaction := range sorted {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(counts)
+			index[reaction.Emoji] = i
+			counts = append(counts, ReactionCount{Emoji: reaction.Emoji})
+		}
+		counts[i].Count++
+		counts[i].From = append(counts[i].From, reaction.From)
+	}
+	return counts
+}
+
+// FormatReactionCounts renders counts compactly, e.g. "👍2 👀1".
+func FormatReactionCounts(counts []ReactionCount) string {
+	parts := make([]string, 0, len(counts))
+	for _, count := range counts {
+		parts = append(parts, fmt.Sprintf("%s%d", count.Emoji, count.Count))
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortReactions(reactions []Reaction) {
+	sort.SliceStable(reactions, func(i, j int) bool {
+		if !reactions[i].Time.Equal(reactions[j].Time) {
+			return reactions[i].Time.Before(reactions[j].Time)
+		}
+		if reactions[i].Emoji != reactions[j].Emoji {
+			return reactions[i].Emoji < reactions[j].Emoji
+		}
+		return reactions[i].From < reactions[j].From
+	})
+}
+
+func readReaction(path string) (*Reaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var reaction Reaction
+	if err := json.Unmarshal(data, &reaction); err != nil {
+		return nil, err
+	}
+	return &reaction, nil
+}
@@ -0,0 +1,129 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newReactCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "react <message-id> [emoji]",
+		Short: "React to a message with an emoji",
+		Long: `Add, remove, or list emoji reactions on a message.
+
+Reactions are stored as small records in .fmail/reactions/<message-id>/,
+one per agent and emoji, and shown as counts next to messages in the TUI.
+The emoji may be a literal emoji or a shortcode: ` + strings.Join(reactionShortcodeNames(), ", ") + `.
+Without an emoji the message's reactions are listed.`,
+		Example: `  fmail react 20260101-120000-0001 +1
+  fmail react 20260101-120000-0001 🚀
+  fmail react 20260101-120000-0001 +1 --remove
+  fmail react 20260101-120000-0001 --json`,
+		Args: argsRange(1, 2),
+		RunE: runReact,
+	}
+	cmd.Flags().Bool("remove", false, "Remove your reaction instead of adding it")
+	cmd.Flags().Bool("toggle", false, "Add the reaction, or remove it if already present")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func runReact(cmd *cobra.Command, args []string) error {
+	runtime, err := EnsureRuntime(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	remove, _ := cmd.Flags().GetBool("remove")
+	toggle, _ := cmd.Flags().GetBool("toggle")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	messageID := strings.TrimSpace(args[0])
+
+	if len(args) == 1 {
+		if remove || toggle {
+			return usageError(cmd, "an emoji is required with --remove or --toggle")
+		}
+		return listReactions(cmd, store, messageID, jsonOutput)
+	}
+	if remove && toggle {
+		return usageError(cmd, "use either --remove or --toggle")
+	}
+
+	emoji, err := NormalizeReaction(args[1])
+	if err != nil {
+		return usageError(cmd, "%v", err)
+	}
+	action := "added"
+	switch {
+	case remove:
+		err = store.RemoveReaction(messageID, emoji, runtime.Agent)
+		action = "removed"
+	case toggle:
+		var added bool
+		added, err = store.ToggleReaction(messageID, emoji, runtime.Agent)
+		if !added {
+			action = "removed"
+		}
+	default:
+		_, err = store.AddReaction(messageID, emoji, runtime.Agent)
+	}
+	switch {
+	case errors.Is(err, ErrReactionNotFound):
+		return Exitf(ExitCodeFailure, "no %s reaction from %s on %s", emoji, runtime.Agent, messageID)
+	case err != nil:
+		return Exitf(ExitCodeFailure, "react: %v", err)
+	}
+
+	if jsonOutput {
+		return writeReactionJSON(cmd, map[string]any{
+			"message_id": messageID,
+			"emoji":      emoji,
+			"from":       runtime.Agent,
+			"action":     action,
+		})
+	}
+	verb := "Added"
+	if action == "removed" {
+		verb = "Removed"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s on %s\n", verb, emoji, messageID)
+	return nil
+}
+
+func listReactions(cmd *cobra.Command, store *Store, messageID string, jsonOutput bool) error {
+	reactions, err := store.ListReactions(messageID)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "list reactions: %v", err)
+	}
+	counts := AggregateReactions(reactions)
+	if jsonOutput {
+		return writeReactionJSON(cmd, counts)
+	}
+	if len(counts) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No reactions")
+		return nil
+	}
+	for _, count := range counts {
+		from := append([]string(nil), count.From...)
+		sort.Strings(from)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %d  %s\n", count.Emoji, count.Count, strings.Join(from, ", "))
+	}
+	return nil
+}
+
+func writeReactionJSON(cmd *cobra.Command, value any) error {
+	payload, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return Exitf(ExitCodeFailure, "encode reactions: %v", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+	return nil
+}
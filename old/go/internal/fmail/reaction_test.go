@@ -0,0 +1,90 @@
+package fmail
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeReaction(t *testing.T) {
+	emoji, err := NormalizeReaction(":eyes:")
+	require.NoError(t, err)
+	require.Equal(t, "👀", emoji)
+
+	emoji, err = NormalizeReaction("+1")
+	require.NoError(t, err)
+	require.Equal(t, "👍", emoji)
+
+	emoji, err = NormalizeReaction("🚀")
+	require.NoError(t, err)
+	require.Equal(t, "🚀", emoji)
+
+	_, err = NormalizeReaction("lol")
+	require.ErrorIs(t, err, ErrInvalidReaction)
+	_, err = NormalizeReaction("")
+	require.ErrorIs(t, err, ErrInvalidReaction)
+}
+
+func TestReactionStoreRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store, err := NewStore(t.TempDir(), WithNow(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}))
+	require.NoError(t, err)
+	id := "20260101-120000-0001"
+
+	_, err = store.AddReaction(id, "+1", "alice")
+	require.NoError(t, err)
+	_, err = store.AddReaction(id, "👍", "alice")
+	require.NoError(t, err)
+	_, err = store.AddReaction(id, "eyes", "bob")
+	require.NoError(t, err)
+	_, err = store.AddReaction(id, "+1", "bob")
+	require.NoError(t, err)
+
+	reactions, err := store.ListReactions(id)
+	require.NoError(t, err)
+	require.Len(t, reactions, 3)
+
+	counts := AggregateReactions(reactions)
+	require.Equal(t, []ReactionCount{
+		{Emoji: "👍", Count: 2, From: []string{"alice", "bob"}},
+		{Emoji: "👀", Count: 1, From: []string{"bob"}},
+	}, counts)
+	require.Equal(t, "👍2 👀1", FormatReactionCounts(counts))
+
+	added, err := store.ToggleReaction(id, "+1", "alice")
+	require.NoError(t, err)
+	require.False(t, added)
+	require.ErrorIs(t, store.RemoveReaction(id, "+1", "alice"), ErrReactionNotFound)
+
+	all, err := store.ListAllReactions()
+	require.NoError(t, err)
+	require.Len(t, all[id], 2)
+
+	_, err = store.AddReaction("../escape", "+1", "alice")
+	require.Error(t, err)
+}
+
+func TestReactCommand(t *testing.T) {
+	t.Setenv(EnvAgent, "alice")
+	root := t.TempDir()
+	t.Setenv(EnvRoot, root)
+
+	run := func(args ...string) string {
+		cmd := newRootCmd("test")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+
+	require.Equal(t, "Added 🚀 on 20260101-120000-0001\n", run("react", "20260101-120000-0001", "rocket"))
+	require.Equal(t, "🚀 1  alice\n", run("react", "20260101-120000-0001"))
+	require.Equal(t, "Removed 🚀 on 20260101-120000-0001\n", run("react", "20260101-120000-0001", "rocket", "--toggle"))
+	require.Equal(t, "No reactions\n", run("react", "20260101-120000-0001"))
+}
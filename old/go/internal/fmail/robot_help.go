@@ -42,10 +42,11 @@ func robotHelp(version string) robotHelpPayload {
 		Commands: map[string]robotHelpCommand{
 			"send": {
 				Usage: "fmail send <topic|@agent> <message>",
-				Flags: []string{"-f FILE", "--reply-to ID", "--priority low|normal|high"},
+				Flags: []string{"-f FILE", "--reply-to ID", "--priority low|normal|high", "-a FILE", "--template NAME", "--var KEY=VALUE"},
 				Examples: []string{
 					"fmail send task 'implement auth'",
 					"fmail send @reviewer 'check PR #42'",
+					"fmail send @worker --template handoff --var task=auth",
 				},
 			},
 			"log": {
@@ -66,12 +67,14 @@ func robotHelp(version string) robotHelpPayload {
 				Description: "View all public messages across topics and direct messages",
 			},
 			"watch": {
-				Usage: "fmail watch [topic|@agent] [--timeout T] [--count N]",
-				Flags: []string{"--timeout DURATION", "--count N", "--json"},
+				Usage: "fmail watch [topic|@agent] [--timeout T] [--count N] [--exec CMD]",
+				Flags: []string{"--timeout DURATION", "--count N", "--json", "--exec CMD", "--color auto|always|never"},
 				Examples: []string{
 					"fmail watch task",
 					"fmail watch @$FMAIL_AGENT --count 1 --timeout 2m",
+					"fmail watch @$FMAIL_AGENT --exec 'notify-send \"$FMAIL_MSG_FROM\" \"$FMAIL_MSG_BODY\"'",
 				},
+				Description: "Tail new messages; --exec runs a shell command per message with the message JSON on stdin and FMAIL_MSG_ID/FROM/TO/BODY/TIME set",
 			},
 			"who": {
 				Usage:       "fmail who [--json]",
@@ -99,6 +102,84 @@ func robotHelp(version string) robotHelpPayload {
 			"gc": {
 				Usage: "fmail gc [--days N] [--dry-run]",
 			},
+			"template": {
+				Usage: "fmail template ls|show|add|rm|render <name>",
+				Flags: []string{"--json", "-f FILE", "--force", "--var KEY=VALUE"},
+				Examples: []string{
+					"fmail template add handoff 'Hi {{to}}, {{agent}} handing off {{task}}'",
+					"fmail template render handoff @worker --var task=auth",
+				},
+				Description: "Named message bodies in .fmail/templates; {{agent}} and {{to}} are filled automatically",
+			},
+			"group": {
+				Usage: "fmail group ls|show|create|add|kick|rm <name> [agent...]",
+				Flags: []string{"--json"},
+				Examples: []string{
+					"fmail group create frontend-team alice bob",
+					"fmail send '#frontend-team' 'API shape changed'",
+					"fmail log '#frontend-team'",
+				},
+				Description: "Named agent groups in .fmail/groups; sending to #name copies the message to every member's inbox",
+			},
+			"encrypt": {
+				Usage: "fmail encrypt init|status|migrate",
+				Flags: []string{"--decrypt", "--dry-run", "--json"},
+				Examples: []string{
+					"fmail encrypt init",
+					"fmail encrypt migrate --dry-run",
+				},
+				Description: "Encrypt DM bodies at rest with a per-project key in ~/.config/forge/fmail/keys; reads decrypt transparently",
+			},
+			"digest": {
+				Usage: "fmail digest [--since DURATION|TIMESTAMP] [--format text|markdown]",
+				Flags: []string{"--since", "--format", "--json"},
+				Examples: []string{
+					"fmail digest --since 8h",
+					"fmail digest --since 8h --format markdown",
+				},
+				Description: "Per-topic activity report: message counts, participants, open questions, high-priority items, new agents",
+			},
+			"webhook": {
+				Usage: "fmail webhook ls|add|rm|test|run|receive|serve",
+				Flags: []string{"--topic", "--tag", "--secret", "--format auto|github|ci|raw", "--github-event", "--from", "--addr"},
+				Examples: []string{
+					"fmail webhook add ci-alerts https://hooks.example.com/fmail --topic build --tag blocker",
+					"fmail webhook run",
+					"gh api repos/o/r/actions/runs/1 | fmail webhook receive build --format ci",
+					"fmail webhook serve build --addr 127.0.0.1:8089 --secret $SECRET",
+				},
+				Description: "Outbound webhooks in .fmail/webhooks POST matching messages; receive/serve turn GitHub events, CI results, or raw payloads into messages",
+			},
+			"react": {
+				Usage: "fmail react <message-id> [emoji]",
+				Flags: []string{"--remove", "--toggle", "--json"},
+				Examples: []string{
+					"fmail react 20260101-120000-0001 +1",
+					"fmail react 20260101-120000-0001 eyes --remove",
+					"fmail react 20260101-120000-0001 --json",
+				},
+				Description: "Emoji reactions stored in .fmail/reactions/<message-id>; shortcodes like +1, eyes, check, rocket; no emoji lists counts",
+			},
+			"quota": {
+				Usage: "fmail quota [@agent] | fmail quota set|rm [@agent]",
+				Flags: []string{"--json", "--max-messages N", "--max-size SIZE", "--max-share PERCENT"},
+				Examples: []string{
+					"fmail quota",
+					"fmail quota set --max-size 50MB",
+					"fmail quota set @worker --max-messages 500 --max-share 40",
+				},
+				Description: "Per-agent message counts and sizes; quotas in .fmail/quotas.json reject sends that would go over",
+			},
+			"search": {
+				Usage: "fmail search <words...>",
+				Flags: []string{"--in TARGET", "--from AGENT", "-n, --limit N", "--reindex", "--json"},
+				Examples: []string{
+					"fmail search deploy failed",
+					"fmail search auth --in @reviewer --json",
+					"fmail search --reindex",
+				},
+				Description: "Whole-word body search through the index in .fmail/index/search.jsonl, built on first use and appended on every send",
+			},
 		},
 		Patterns: robotHelpPatterns{
 			RequestResponse: []string{
@@ -119,11 +200,11 @@ func robotHelp(version string) robotHelpPayload {
 		MessageFormat: map[string]string{
 			"id":   "YYYYMMDD-HHMMSS-NNNN",
 			"from": "sender agent name",
-			"to":   "topic or @agent",
+			"to":   "topic, @agent, or #group",
 			"time": "ISO 8601 timestamp",
 			"body": "string or JSON object",
 		},
-		Storage: ".fmail/topics/<topic>/<id>.json and .fmail/dm/<agent>/<id>.json",
+		Storage: ".fmail/topics/<topic>/<id>.json, .fmail/dm/<agent>/<id>.json, and .fmail/groups/<group>/<id>.json; search index in .fmail/index/search.jsonl",
 	}
 }
 
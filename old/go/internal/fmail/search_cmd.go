@@ -0,0 +1,118 @@
+package fmail
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <words...>",
+		Short: "Search message bodies using the on-disk index",
+		Long: `Search message bodies for messages containing every given word.
+
+Searches use the index in .fmail/index/search.jsonl, which is built on first
+use and updated on every send, so only matching message files are read.
+Words match whole lowercase alphanumeric tokens. Direct messages are only
+indexed when DM encryption is off. Use --reindex after copying message files
+into .fmail by hand.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runSearch,
+	}
+	cmd.Flags().String("in", "", "Limit to a topic, #group, or @agent")
+	cmd.Flags().String("from", "", "Filter by sender")
+	cmd.Flags().IntP("limit", "n", 50, "Max messages to show (most recent)")
+	cmd.Flags().Bool("reindex", false, "Rebuild the search index before searching")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return usageError(cmd, "limit must be >= 0")
+	}
+	fromFlag, _ := cmd.Flags().GetString("from")
+	from, err := normalizeFromFilter(fromFlag)
+	if err != nil {
+		return usageError(cmd, "invalid --from value: %v", err)
+	}
+	inFlag, _ := cmd.Flags().GetString("in")
+	scope, err := normalizeSearchScope(inFlag)
+	if err != nil {
+		return usageError(cmd, "invalid --in value: %v", err)
+	}
+	reindex, _ := cmd.Flags().GetBool("reindex")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	text := strings.Join(args, " ")
+	if len(SearchTokens(text)) == 0 && !reindex {
+		return usageError(cmd, "search needs at least one word")
+	}
+
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	if reindex {
+		count, err := store.RebuildSearchIndex()
+		if err != nil {
+			return Exitf(ExitCodeFailure, "reindex: %v", err)
+		}
+		if len(SearchTokens(text)) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Indexed %d messages\n", count)
+			return nil
+		}
+	}
+
+	index, err := store.OpenSearchIndex()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "search: %v", err)
+	}
+	messages, err := store.searchMessages(index, text, scope, logFilter{from: from})
+	if err != nil {
+		return Exitf(ExitCodeFailure, "search: %v", err)
+	}
+	if limit > 0 && len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	for _, message := range messages {
+		if err := writeWatchMessage(cmd.OutOrStdout(), message, watchOptions{jsonOutput: jsonOutput}); err != nil {
+			return Exitf(ExitCodeFailure, "output: %v", err)
+		}
+	}
+	return nil
+}
+
+// searchMessages reads the messages the index matched, oldest first. Hits
+// whose files were removed since they were indexed are skipped.
+func (s *Store) searchMessages(index *SearchIndex, text, scope string, filter logFilter) ([]*Message, error) {
+	hits := index.Lookup(text, scope)
+	messages := make([]*Message, 0, len(hits))
+	for _, hit := range hits {
+		message, err := s.ReadMessage(s.SearchHitPath(hit))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if filter.match(message) {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// normalizeSearchScope validates an --in value and returns the index target
+// it names.
+func normalizeSearchScope(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	target, _, err := NormalizeTarget(raw)
+	return target, err
+}
@@ -0,0 +1,425 @@
+package fmail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The search index is an append-only JSON lines file under
+// .fmail/index/search.jsonl. The first line is a header written when the
+// index is (re)built; every other line lists the tokens of one message.
+// Message writes append a line when the index exists, so searches only
+// read the index and the matching message files. Encrypted DMs are never
+// indexed, and DM copies of group messages are indexed once under the
+// group thread.
+
+const (
+	searchIndexVersion  = 1
+	searchIndexDirPerm  = 0o700
+	searchIndexFilePerm = 0o600
+)
+
+type searchIndexHeader struct {
+	Version int       `json:"version"`
+	BuiltAt time.Time `json:"built_at"`
+}
+
+type searchIndexEntry struct {
+	ID     string   `json:"id"`
+	Target string   `json:"target"`
+	Tokens []string `json:"tokens"`
+}
+
+// SearchHit is a message found through the search index. Target is a topic,
+// "#group", or "@agent" (a DM directory).
+type SearchHit struct {
+	ID     string `json:"id"`
+	Target string `json:"target"`
+}
+
+// SearchIndex is an in-memory copy of the on-disk search index. Refresh
+// picks up lines appended since the last read.
+type SearchIndex struct {
+	path     string
+	builtAt  time.Time
+	offset   int64
+	hits     []SearchHit
+	byHit    map[SearchHit]int
+	postings map[string][]int // token -> hit ordinals, ascending
+}
+
+// SearchIndexPath returns the index file location.
+func (s *Store) SearchIndexPath() string {
+	return filepath.Join(s.Root, "index", "search.jsonl")
+}
+
+// SearchHitPath returns the message file a hit refers to.
+func (s *Store) SearchHitPath(hit SearchHit) string {
+	if strings.HasPrefix(hit.Target, "@") {
+		return s.DMMessagePath(strings.TrimPrefix(hit.Target, "@"), hit.ID)
+	}
+	return filepath.Join(s.ThreadDir(hit.Target), hit.ID+".json")
+}
+
+// SearchTokens splits text into the lowercase alphanumeric tokens used by
+// the index, without duplicates.
+func SearchTokens(text string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	})
+	seen := make(map[string]struct{}, len(parts))
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if _, ok := seen[part]; ok {
+			continue
+		}
+		seen[part] = struct{}{}
+		out = append(out, part)
+	}
+	return out
+}
+
+// indexMessage appends a saved message to the search index. It is best
+// effort: a missing index is left for the next search to build, and write
+// errors never fail the send.
+func (s *Store) indexMessage(message *Message) {
+	if message == nil || (strings.HasPrefix(message.To, "@") && s.EncryptionEnabled()) {
+		return
+	}
+	line, err := searchIndexLine(message, message.To)
+	if err != nil || line == nil {
+		return
+	}
+	file, err := os.OpenFile(s.SearchIndexPath(), os.O_WRONLY|os.O_APPEND, searchIndexFilePerm)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	_, _ = file.Write(line)
+}
+
+func searchIndexLine(message *Message, target string) ([]byte, error) {
+	body, err := formatMessageBody(message.Body)
+	if err != nil {
+		return nil, err
+	}
+	tokens := SearchTokens(body)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(searchIndexEntry{ID: message.ID, Target: target, Tokens: tokens})
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// RebuildSearchIndex rescans every topic, group thread, and (unencrypted)
+// DM directory and atomically replaces the index. It returns the number of
+// messages indexed.
+func (s *Store) RebuildSearchIndex() (int, error) {
+	if err := s.EnsureRoot(); err != nil {
+		return 0, err
+	}
+	path := s.SearchIndexPath()
+	if err := ensureDirPerm(filepath.Dir(path), searchIndexDirPerm); err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	header, err := json.Marshal(searchIndexHeader{Version: searchIndexVersion, BuiltAt: s.now()})
+	if err != nil {
+		return 0, err
+	}
+	buf.Write(header)
+	buf.WriteByte('\n')
+
+	count := 0
+	add := func(dir, target string, skipGroupCopies bool) error {
+		messages, err := s.listMessages(dir)
+		if err != nil {
+			return err
+		}
+		for i := range messages {
+			if skipGroupCopies && messages[i].Group != "" {
+				continue
+			}
+			line, err := searchIndexLine(&messages[i], target)
+			if err != nil {
+				return err
+			}
+			if line != nil {
+				buf.Write(line)
+				count++
+			}
+		}
+		return nil
+	}
+
+	topics, err := listSubDirs(filepath.Join(s.Root, "topics"))
+	if err != nil {
+		return 0, err
+	}
+	for _, topic := range topics {
+		if ValidateTopic(topic) != nil {
+			continue
+		}
+		if err := add(s.TopicDir(topic), topic, false); err != nil {
+			return 0, err
+		}
+	}
+	groups, err := listSubDirs(s.GroupsDir())
+	if err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		if _, err := NormalizeGroupName(group); err != nil {
+			continue
+		}
+		if err := add(s.GroupThreadDir(group), GroupTargetPrefix+group, false); err != nil {
+			return 0, err
+		}
+	}
+	if !s.EncryptionEnabled() {
+		agents, err := listSubDirs(filepath.Join(s.Root, "dm"))
+		if err != nil {
+			return 0, err
+		}
+		for _, agent := range agents {
+			if ValidateAgentName(agent) != nil {
+				continue
+			}
+			if err := add(s.DMDir(agent), "@"+agent, true); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".search-*.jsonl")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Chmod(searchIndexFilePerm); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// OpenSearchIndex loads the search index, building it first when it does
+// not exist yet or was written by another index version.
+func (s *Store) OpenSearchIndex() (*SearchIndex, error) {
+	idx := &SearchIndex{path: s.SearchIndexPath()}
+	err := idx.load()
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errSearchIndexStale) {
+		if _, err := s.RebuildSearchIndex(); err != nil {
+			return nil, fmt.Errorf("build search index: %w", err)
+		}
+		idx = &SearchIndex{path: s.SearchIndexPath()}
+		err = idx.load()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// errSearchIndexStale marks an index with an unreadable header or another
+// format version; it is rebuilt rather than read.
+var errSearchIndexStale = errors.New("search index is stale")
+
+// Refresh reads lines appended since the last load. A rebuilt index is
+// reloaded from the start.
+func (idx *SearchIndex) Refresh() error {
+	info, err := os.Stat(idx.path)
+	if err != nil {
+		return err
+	}
+	if info.Size() == idx.offset {
+		return nil
+	}
+	if info.Size() < idx.offset {
+		return idx.load()
+	}
+	file, err := os.Open(idx.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	header, err := readSearchIndexHeader(file)
+	if err != nil {
+		return err
+	}
+	if !header.BuiltAt.Equal(idx.builtAt) {
+		return idx.load()
+	}
+	return idx.readFrom(file, idx.offset)
+}
+
+// BuiltAt reports when the index was last rebuilt.
+func (idx *SearchIndex) BuiltAt() time.Time {
+	return idx.builtAt
+}
+
+// Len returns the number of indexed messages.
+func (idx *SearchIndex) Len() int {
+	return len(idx.hits)
+}
+
+// Lookup returns the messages containing every token of text, sorted by ID
+// then target. scope limits hits to one target; "" searches everywhere.
+func (idx *SearchIndex) Lookup(text, scope string) []SearchHit {
+	terms := SearchTokens(text)
+	if len(terms) == 0 {
+		return nil
+	}
+	lists := make([][]int, 0, len(terms))
+	for _, term := range terms {
+		refs := idx.postings[term]
+		if len(refs) == 0 {
+			return nil
+		}
+		lists = append(lists, refs)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	matched := lists[0]
+	for _, refs := range lists[1:] {
+		matched = intersectSorted(matched, refs)
+		if len(matched) == 0 {
+			return nil
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(matched))
+	for _, ord := range matched {
+		hit := idx.hits[ord]
+		if scope != "" && hit.Target != scope {
+			continue
+		}
+		hits = append(hits, hit)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].ID != hits[j].ID {
+			return hits[i].ID < hits[j].ID
+		}
+		return hits[i].Target < hits[j].Target
+	})
+	return hits
+}
+
+func (idx *SearchIndex) load() error {
+	file, err := os.Open(idx.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	header, err := readSearchIndexHeader(file)
+	if err != nil {
+		return err
+	}
+	if header.Version != searchIndexVersion {
+		return errSearchIndexStale
+	}
+	idx.builtAt = header.BuiltAt
+	idx.offset = 0
+	idx.hits = nil
+	idx.byHit = make(map[SearchHit]int)
+	idx.postings = make(map[string][]int)
+	return idx.readFrom(file, 0)
+}
+
+// readFrom indexes complete lines after offset, skipping the header. A
+// trailing partial line (an append in progress) is left for the next read.
+func (idx *SearchIndex) readFrom(file *os.File, offset int64) error {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(file, 64*1024)
+	pos := offset
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start := pos
+		pos += int64(len(line))
+		if start == 0 {
+			continue // header
+		}
+		var entry searchIndexEntry
+		if json.Unmarshal(line, &entry) != nil || entry.ID == "" || entry.Target == "" {
+			continue
+		}
+		idx.add(entry)
+	}
+	idx.offset = pos
+	return nil
+}
+
+func (idx *SearchIndex) add(entry searchIndexEntry) {
+	hit := SearchHit{ID: entry.ID, Target: entry.Target}
+	if _, ok := idx.byHit[hit]; ok {
+		return
+	}
+	ord := len(idx.hits)
+	idx.hits = append(idx.hits, hit)
+	idx.byHit[hit] = ord
+	for _, token := range entry.Tokens {
+		idx.postings[token] = append(idx.postings[token], ord)
+	}
+}
+
+func readSearchIndexHeader(file *os.File) (searchIndexHeader, error) {
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return searchIndexHeader{}, err
+	}
+	var header searchIndexHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return searchIndexHeader{}, errSearchIndexStale
+	}
+	return header, nil
+}
+
+// intersectSorted returns the values present in both ascending lists; a
+// should be the shorter one.
+func intersectSorted(a, b []int) []int {
+	out := make([]int, 0, len(a))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
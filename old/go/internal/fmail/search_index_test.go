@@ -0,0 +1,91 @@
+package fmail
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIndexBuildsAndFollowsSends(t *testing.T) {
+	store, err := NewStore(t.TempDir(), WithKeyDir(t.TempDir()))
+	require.NoError(t, err)
+	_, err = store.SaveGroup("team", []string{"alice", "bob"}, "")
+	require.NoError(t, err)
+
+	for _, msg := range []*Message{
+		{From: "alice", To: "build", Body: "Deploy failed on staging"},
+		{From: "bob", To: "build", Body: "deploy fixed"},
+		{From: "alice", To: "#team", Body: "staging deploy is back"},
+	} {
+		_, err := store.SaveMessage(msg)
+		require.NoError(t, err)
+	}
+
+	// No index yet: sends must not create a partial one.
+	_, err = os.Stat(store.SearchIndexPath())
+	require.True(t, os.IsNotExist(err))
+
+	idx, err := store.OpenSearchIndex()
+	require.NoError(t, err)
+	require.Equal(t, 3, idx.Len(), "group copies in DM directories are not indexed twice")
+
+	hits := idx.Lookup("STAGING deploy", "")
+	require.Len(t, hits, 2)
+	require.Equal(t, "build", hits[0].Target)
+	require.Equal(t, "#team", hits[1].Target)
+	require.Empty(t, idx.Lookup("deploy", "@alice"))
+	require.Len(t, idx.Lookup("deploy", "build"), 2)
+	require.Empty(t, idx.Lookup("dep", ""), "tokens match whole words")
+
+	dm := &Message{From: "bob", To: "@alice", Body: "staging creds rotated"}
+	_, err = store.SaveMessage(dm)
+	require.NoError(t, err)
+	require.NoError(t, idx.Refresh())
+	hits = idx.Lookup("staging", "")
+	require.Len(t, hits, 3)
+	require.Equal(t, SearchHit{ID: dm.ID, Target: "@alice"}, hits[2])
+
+	loaded, err := store.ReadMessage(store.SearchHitPath(hits[2]))
+	require.NoError(t, err)
+	require.Equal(t, "staging creds rotated", loaded.Body)
+
+	// Removed files are skipped, and a rebuild drops them from the index.
+	require.NoError(t, os.Remove(store.SearchHitPath(hits[0])))
+	messages, err := store.searchMessages(idx, "staging", "", logFilter{})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	count, err := store.RebuildSearchIndex()
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.NoError(t, idx.Refresh())
+	require.Len(t, idx.Lookup("staging", ""), 2)
+}
+
+func TestSearchIndexIgnoresPartialLinesAndStaleHeaders(t *testing.T) {
+	store, err := NewStore(t.TempDir(), WithKeyDir(t.TempDir()))
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "alice", To: "build", Body: "green"})
+	require.NoError(t, err)
+
+	idx, err := store.OpenSearchIndex()
+	require.NoError(t, err)
+
+	file, err := os.OpenFile(store.SearchIndexPath(), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":"20260101-000000-0001","target":"build","tok`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.NoError(t, idx.Refresh())
+	require.Equal(t, 1, idx.Len())
+
+	require.NoError(t, os.WriteFile(store.SearchIndexPath(), []byte("{\"version\":99}\n"), 0o600))
+	idx, err = store.OpenSearchIndex()
+	require.NoError(t, err)
+	require.Len(t, idx.Lookup("green", ""), 1)
+	data, err := os.ReadFile(store.SearchIndexPath())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), `{"version":1,`))
+}
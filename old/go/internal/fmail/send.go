@@ -33,6 +33,7 @@ func runSend(cmd *cobra.Command, args []string) error {
 	replyTo, _ := cmd.Flags().GetString("reply-to")
 	priority, _ := cmd.Flags().GetString("priority")
 	tags, _ := cmd.Flags().GetStringSlice("tag")
+	attachPaths, _ := cmd.Flags().GetStringArray("attach")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 
 	normalizedTarget, _, err := NormalizeTarget(target)
@@ -40,7 +41,18 @@ func runSend(cmd *cobra.Command, args []string) error {
 		return Exitf(ExitCodeFailure, "invalid target %q: %v", target, err)
 	}
 
-	body, err := resolveSendBody(cmd, bodyArg, filePath)
+	templateName, _ := cmd.Flags().GetString("template")
+	if strings.TrimSpace(templateName) != "" {
+		if strings.TrimSpace(bodyArg) != "" || strings.TrimSpace(filePath) != "" {
+			return usageError(cmd, "provide either --template or a message body, not both")
+		}
+		bodyArg, err = resolveTemplateBody(cmd, runtime, templateName, normalizedTarget)
+		if err != nil {
+			return err
+		}
+	}
+
+	body, err := resolveSendBody(cmd, bodyArg, filePath, len(attachPaths) > 0)
 	if err != nil {
 		return err
 	}
@@ -72,6 +84,38 @@ func runSend(cmd *cobra.Command, args []string) error {
 		message.Priority = priority
 	}
 
+	if len(attachPaths) > 0 {
+		// Check before the attachments are copied into the store.
+		if err := enforceSendQuota(runtime, message); err != nil {
+			return err
+		}
+		attachments, err := saveSendAttachments(runtime, attachPaths)
+		if err != nil {
+			return err
+		}
+		message.Attachments = attachments
+		if body == nil {
+			message.Body = attachmentBody(attachments)
+		}
+		// forged's send request has no attachment field, so messages that
+		// carry attachments are written to the store directly.
+		result, err := sendStandalone(runtime, message)
+		if err != nil {
+			return err
+		}
+		return writeSendResult(cmd, result, jsonOutput)
+	}
+
+	if IsGroupTarget(normalizedTarget) {
+		// forged has no notion of groups; the store fans group messages out
+		// to the members itself.
+		result, err := sendStandalone(runtime, message)
+		if err != nil {
+			return err
+		}
+		return writeSendResult(cmd, result, jsonOutput)
+	}
+
 	result, err := sendViaForged(runtime, message)
 	if err == nil {
 		return writeSendResult(cmd, result, jsonOutput)
@@ -99,7 +143,9 @@ func runSend(cmd *cobra.Command, args []string) error {
 	return Exitf(ExitCodeFailure, "forged: %v", err)
 }
 
-func resolveSendBody(cmd *cobra.Command, bodyArg, filePath string) (any, error) {
+// resolveSendBody returns nil without error when the body is empty and
+// allowEmpty is set, i.e. the message carries attachments.
+func resolveSendBody(cmd *cobra.Command, bodyArg, filePath string, allowEmpty bool) (any, error) {
 	bodyArgTrim := strings.TrimSpace(bodyArg)
 	filePath = strings.TrimSpace(filePath)
 
@@ -126,11 +172,56 @@ func resolveSendBody(cmd *cobra.Command, bodyArg, filePath string) (any, error)
 	}
 
 	if strings.TrimSpace(raw) == "" {
+		if allowEmpty {
+			return nil, nil
+		}
 		return nil, usageError(cmd, "message body is required")
 	}
 	return parseMessageBody(raw)
 }
 
+// resolveTemplateBody renders --template for target. Unfilled placeholders
+// are an error so half-finished templates are never sent.
+func resolveTemplateBody(cmd *cobra.Command, runtime *Runtime, name, target string) (string, error) {
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return "", Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	rendered, missing, err := renderNamedTemplate(store, name, runtime.Agent, target, pairs)
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", usageError(cmd, "template %s needs --var for: %s", strings.TrimSpace(name), strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
+
+func saveSendAttachments(runtime *Runtime, paths []string) ([]Attachment, error) {
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return nil, Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	attachments := make([]Attachment, 0, len(paths))
+	for _, path := range paths {
+		attachment, err := store.SaveAttachmentFile(strings.TrimSpace(path))
+		if err != nil {
+			return nil, Exitf(ExitCodeFailure, "attach %s: %v", path, err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, nil
+}
+
+func attachmentBody(attachments []Attachment) string {
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.Name)
+	}
+	return "attached: " + strings.Join(names, ", ")
+}
+
 func readStdinIfPiped() (string, error) {
 	info, err := os.Stdin.Stat()
 	if err != nil {
@@ -168,6 +259,10 @@ func sendViaForged(runtime *Runtime, message *Message) (sendResult, error) {
 	if message == nil {
 		return sendResult{}, Exitf(ExitCodeFailure, "message is required")
 	}
+	// forged writes the message itself, so the store never sees it.
+	if err := enforceSendQuota(runtime, message); err != nil {
+		return sendResult{}, err
+	}
 
 	projectID, err := resolveProjectID(runtime.Root)
 	if err != nil {
@@ -262,9 +357,15 @@ func sendStandalone(runtime *Runtime, message *Message) (sendResult, error) {
 	}
 
 	if _, err := store.SaveMessage(message); err != nil {
+		if exitErr := quotaExitError(err); exitErr != nil {
+			return sendResult{}, exitErr
+		}
 		if errors.Is(err, ErrMessageTooLarge) {
 			return sendResult{}, Exitf(ExitCodeFailure, "message exceeds 1MB limit")
 		}
+		if errors.Is(err, ErrGroupNotFound) {
+			return sendResult{}, Exitf(ExitCodeFailure, "unknown group %s (create it with: fmail group create)", message.To)
+		}
 		return sendResult{}, Exitf(ExitCodeFailure, "save message: %v", err)
 	}
 
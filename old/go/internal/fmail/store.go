@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,9 +23,14 @@ const (
 )
 
 type Store struct {
-	Root        string
-	now         func() time.Time
-	idGenerator func(time.Time) string
+	Root              string
+	now               func() time.Time
+	idGenerator       func(time.Time) string
+	maxAttachmentSize int64
+	keyDir            string
+	dmKeyOnce         sync.Once
+	dmKey             []byte
+	dmKeyErr          error
 }
 
 type StoreOption func(*Store)
@@ -58,6 +64,7 @@ func NewStore(projectRoot string, opts ...StoreOption) (*Store, error) {
 		Root:        filepath.Join(abs, ".fmail"),
 		now:         func() time.Time { return time.Now().UTC() },
 		idGenerator: GenerateMessageID,
+		keyDir:      DefaultKeyDir(),
 	}
 	for _, opt := range opts {
 		opt(store)
@@ -109,6 +116,21 @@ func (s *Store) SaveMessage(message *Message) (string, error) {
 		return "", err
 	}
 	message.To = normalizedTarget
+	if err := s.CheckSendQuota(message); err != nil {
+		return "", err
+	}
+	if IsGroupTarget(normalizedTarget) {
+		if message.Time.IsZero() {
+			message.Time = s.now()
+		}
+		if message.ID == "" {
+			message.ID = s.idGenerator(message.Time)
+		}
+		if _, err := s.saveGroupMessage(message, false); err != nil {
+			return "", err
+		}
+		return message.ID, nil
+	}
 
 	if message.Time.IsZero() {
 		message.Time = s.now()
@@ -145,17 +167,15 @@ func (s *Store) SaveMessage(message *Message) (string, error) {
 	}
 
 	for attempt := 0; attempt < maxIDRetries; attempt++ {
-		data, err := marshalMessage(message)
+		data, err := s.marshalStored(message, isDM)
 		if err != nil {
 			return "", err
 		}
-		if len(data) > MaxMessageSize {
-			return "", ErrMessageTooLarge
-		}
 
 		path := filepath.Join(dir, message.ID+".json")
 		err = writeFileExclusivePerm(path, data, filePerm)
 		if err == nil {
+			s.indexMessage(message)
 			return message.ID, nil
 		}
 		if errors.Is(err, os.ErrExist) {
@@ -191,6 +211,9 @@ func (s *Store) SaveMessageExact(message *Message) (bool, error) {
 	if message.Time.IsZero() {
 		return false, fmt.Errorf("missing time")
 	}
+	if IsGroupTarget(normalizedTarget) {
+		return s.saveGroupMessage(message, true)
+	}
 
 	if err := message.Validate(); err != nil {
 		return false, err
@@ -217,13 +240,10 @@ func (s *Store) SaveMessageExact(message *Message) (bool, error) {
 		filePerm = topicFilePerm
 	}
 
-	data, err := marshalMessage(message)
+	data, err := s.marshalStored(message, isDM)
 	if err != nil {
 		return false, err
 	}
-	if len(data) > MaxMessageSize {
-		return false, ErrMessageTooLarge
-	}
 
 	path := filepath.Join(dir, message.ID+".json")
 	if err := writeFileExclusivePerm(path, data, filePerm); err != nil {
@@ -232,6 +252,7 @@ func (s *Store) SaveMessageExact(message *Message) (bool, error) {
 		}
 		return false, err
 	}
+	s.indexMessage(message)
 	return true, nil
 }
 
@@ -244,9 +265,28 @@ func (s *Store) ReadMessage(path string) (*Message, error) {
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, err
 	}
+	if err := s.openMessage(&msg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
 	return &msg, nil
 }
 
+// marshalStored encodes a message for disk; DMs go through marshalDM so
+// they are encrypted when the project has a key.
+func (s *Store) marshalStored(message *Message, isDM bool) ([]byte, error) {
+	if isDM {
+		return s.marshalDM(message)
+	}
+	data, err := marshalMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	return data, nil
+}
+
 func (s *Store) ListTopicMessages(topic string) ([]Message, error) {
 	normalized, err := NormalizeTopic(topic)
 	if err != nil {
@@ -0,0 +1,177 @@
+package fmail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	templateDirPerm  = 0o755
+	templateFilePerm = 0o644
+	templateFileExt  = ".md"
+)
+
+// Template is a named message body stored under .fmail/templates. Bodies may
+// contain {{name}} placeholders filled in by RenderTemplate.
+type Template struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_-]+)\s*\}\}`)
+
+// NormalizeTemplateName lowercases and validates a template name. Names
+// follow the topic naming rules.
+func NormalizeTemplateName(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" || !namePattern.MatchString(normalized) {
+		return "", ErrInvalidTemplate
+	}
+	return normalized, nil
+}
+
+func (s *Store) TemplatesDir() string {
+	return filepath.Join(s.Root, "templates")
+}
+
+func (s *Store) templatePath(name string) (string, string, error) {
+	normalized, err := NormalizeTemplateName(name)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(s.TemplatesDir(), normalized+templateFileExt), normalized, nil
+}
+
+// SaveTemplate writes a template. An existing template is only replaced when
+// overwrite is set.
+func (s *Store) SaveTemplate(name, body string, overwrite bool) (*Template, error) {
+	path, normalized, err := s.templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("template body is required")
+	}
+	if len(body) > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	if err := ensureDirPerm(s.TemplatesDir(), templateDirPerm); err != nil {
+		return nil, err
+	}
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return nil, ErrTemplateExists
+		}
+	}
+	if err := os.WriteFile(path, []byte(body), templateFilePerm); err != nil {
+		return nil, err
+	}
+	return &Template{Name: normalized, Body: body}, nil
+}
+
+// ReadTemplate loads a template by name. Missing templates return an error
+// satisfying os.IsNotExist.
+func (s *Store) ReadTemplate(name string) (*Template, error) {
+	path, normalized, err := s.templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Name: normalized, Body: string(data)}, nil
+}
+
+// DeleteTemplate removes a template by name.
+func (s *Store) DeleteTemplate(name string) error {
+	path, _, err := s.templatePath(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// ListTemplates returns all templates sorted by name.
+func (s *Store) ListTemplates() ([]Template, error) {
+	entries, err := os.ReadDir(s.TemplatesDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	templates := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != templateFileExt {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), templateFileExt)
+		if _, err := NormalizeTemplateName(name); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.TemplatesDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, Template{Name: name, Body: string(data)})
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// TemplateVars returns the built-in placeholder values: {{agent}} is the
+// sending agent and {{to}} the target (without "@" for DMs). Callers add
+// task and any other values on top.
+func TemplateVars(agent, target string) map[string]string {
+	vars := map[string]string{}
+	if agent = strings.TrimSpace(agent); agent != "" {
+		vars["agent"] = agent
+	}
+	if target = strings.TrimPrefix(strings.TrimSpace(target), "@"); target != "" {
+		vars["to"] = target
+	}
+	return vars
+}
+
+// RenderTemplate substitutes {{name}} placeholders from vars. Placeholders
+// without a value are left in place and returned, so callers can prompt for
+// them or refuse to send.
+func RenderTemplate(body string, vars map[string]string) (string, []string) {
+	var missing []string
+	seen := map[string]bool{}
+	rendered := templatePlaceholder.ReplaceAllStringFunc(body, func(match string) string {
+		key := strings.ToLower(templatePlaceholder.FindStringSubmatch(match)[1])
+		if value, ok := vars[key]; ok {
+			return value
+		}
+		if !seen[key] {
+			seen[key] = true
+			missing = append(missing, key)
+		}
+		return match
+	})
+	return rendered, missing
+}
+
+// ParseTemplateVars parses repeated key=value flags.
+func ParseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" || !templatePlaceholder.MatchString("{{"+key+"}}") {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,257 @@
+package fmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "template",
+		Aliases: []string{"templates"},
+		Short:   "Manage message templates",
+		Long: `Manage named message templates stored in .fmail/templates.
+
+Template bodies may contain {{agent}} (your agent name), {{to}} (the target),
+{{task}}, or any other {{name}} placeholder; values come from --var name=value.
+Use a template with "fmail send <target> --template NAME" or pick one in the
+TUI compose view with ctrl+t.`,
+	}
+
+	list := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List templates",
+		Args:    argsMax(0),
+		RunE:    runTemplateList,
+	}
+	list.Flags().Bool("json", false, "Output as JSON")
+
+	show := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print a template body",
+		Args:  argsRange(1, 1),
+		RunE:  runTemplateShow,
+	}
+
+	add := &cobra.Command{
+		Use:   "add <name> [body]",
+		Short: "Create or replace a template",
+		Args:  argsRange(1, 2),
+		RunE:  runTemplateAdd,
+	}
+	add.Flags().StringP("file", "f", "", "Read template body from file")
+	add.Flags().Bool("force", false, "Replace an existing template")
+
+	remove := &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"remove"},
+		Short:   "Delete a template",
+		Args:    argsRange(1, 1),
+		RunE:    runTemplateRemove,
+	}
+
+	render := &cobra.Command{
+		Use:   "render <name> [topic|@agent]",
+		Short: "Print a template with placeholders filled in",
+		Args:  argsRange(1, 2),
+		RunE:  runTemplateRender,
+	}
+	render.Flags().StringArray("var", nil, "Placeholder value as name=value (repeatable)")
+
+	cmd.AddCommand(list, show, add, remove, render)
+	return cmd
+}
+
+func templateStore(cmd *cobra.Command) (*Runtime, *Store, error) {
+	runtime, err := EnsureRuntime(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := NewStore(runtime.Root)
+	if err != nil {
+		return nil, nil, Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+	return runtime, store, nil
+}
+
+func readTemplate(store *Store, name string) (*Template, error) {
+	tmpl, err := store.ReadTemplate(name)
+	switch {
+	case err == nil:
+		return tmpl, nil
+	case errors.Is(err, ErrInvalidTemplate):
+		return nil, Exitf(ExitCodeFailure, "invalid template name %q", name)
+	case os.IsNotExist(err):
+		return nil, Exitf(ExitCodeFailure, "template %q not found", name)
+	default:
+		return nil, Exitf(ExitCodeFailure, "read template: %v", err)
+	}
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	templates, err := store.ListTemplates()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "list templates: %v", err)
+	}
+
+	if jsonOutput {
+		if templates == nil {
+			templates = []Template{}
+		}
+		payload, err := json.MarshalIndent(templates, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode templates: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tPLACEHOLDERS\tPREVIEW")
+	for _, tmpl := range templates {
+		_, placeholders := RenderTemplate(tmpl.Body, nil)
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", tmpl.Name, strings.Join(placeholders, ","), templatePreview(tmpl.Body, 50))
+	}
+	if err := writer.Flush(); err != nil {
+		return Exitf(ExitCodeFailure, "write output: %v", err)
+	}
+	return nil
+}
+
+func runTemplateShow(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	tmpl, err := readTemplate(store, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(tmpl.Body, "\n"))
+	return nil
+}
+
+func runTemplateAdd(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	filePath, _ := cmd.Flags().GetString("file")
+	force, _ := cmd.Flags().GetBool("force")
+
+	bodyArg := ""
+	if len(args) > 1 {
+		bodyArg = args[1]
+	}
+	filePath = strings.TrimSpace(filePath)
+	if filePath != "" && strings.TrimSpace(bodyArg) != "" {
+		return usageError(cmd, "provide either a body argument or --file, not both")
+	}
+	body := bodyArg
+	switch {
+	case filePath != "":
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return Exitf(ExitCodeFailure, "read file: %v", err)
+		}
+		body = string(data)
+	case strings.TrimSpace(body) == "":
+		data, err := readStdinIfPiped()
+		if err != nil {
+			return Exitf(ExitCodeFailure, "read stdin: %v", err)
+		}
+		body = data
+	}
+	if strings.TrimSpace(body) == "" {
+		return usageError(cmd, "template body is required")
+	}
+
+	tmpl, err := store.SaveTemplate(args[0], body, force)
+	switch {
+	case errors.Is(err, ErrTemplateExists):
+		return Exitf(ExitCodeFailure, "template %q already exists (use --force to replace)", args[0])
+	case errors.Is(err, ErrInvalidTemplate):
+		return Exitf(ExitCodeFailure, "invalid template name %q", args[0])
+	case err != nil:
+		return Exitf(ExitCodeFailure, "save template: %v", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved template %s\n", tmpl.Name)
+	return nil
+}
+
+func runTemplateRemove(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := readTemplate(store, args[0]); err != nil {
+		return err
+	}
+	if err := store.DeleteTemplate(args[0]); err != nil {
+		return Exitf(ExitCodeFailure, "delete template: %v", err)
+	}
+	return nil
+}
+
+func runTemplateRender(cmd *cobra.Command, args []string) error {
+	runtime, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	pairs, _ := cmd.Flags().GetStringArray("var")
+
+	target := ""
+	if len(args) > 1 {
+		target = args[1]
+	}
+	rendered, missing, err := renderNamedTemplate(store, args[0], runtime.Agent, target, pairs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unfilled placeholders: %s\n", strings.Join(missing, ", "))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(rendered, "\n"))
+	return nil
+}
+
+// renderNamedTemplate loads a template and fills it from the built-in vars
+// and --var pairs; explicit pairs win.
+func renderNamedTemplate(store *Store, name, agent, target string, pairs []string) (string, []string, error) {
+	tmpl, err := readTemplate(store, name)
+	if err != nil {
+		return "", nil, err
+	}
+	extra, err := ParseTemplateVars(pairs)
+	if err != nil {
+		return "", nil, Exitf(ExitCodeFailure, "%v", err)
+	}
+	vars := TemplateVars(agent, target)
+	for key, value := range extra {
+		vars[key] = value
+	}
+	rendered, missing := RenderTemplate(tmpl.Body, vars)
+	return rendered, missing, nil
+}
+
+func templatePreview(body string, max int) string {
+	preview := strings.Join(strings.Fields(body), " ")
+	runes := []rune(preview)
+	if len(runes) > max {
+		return string(runes[:max-3]) + "..."
+	}
+	return preview
+}
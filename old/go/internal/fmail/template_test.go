@@ -0,0 +1,97 @@
+package fmail
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateStoreRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	saved, err := store.SaveTemplate("Handoff", "Hi {{to}}, {{agent}} here: {{task}}", false)
+	require.NoError(t, err)
+	require.Equal(t, "handoff", saved.Name)
+
+	_, err = store.SaveTemplate("handoff", "other", false)
+	require.ErrorIs(t, err, ErrTemplateExists)
+	_, err = store.SaveTemplate("handoff", "replaced {{task}}", true)
+	require.NoError(t, err)
+	_, err = store.SaveTemplate("../escape", "x", false)
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+
+	_, err = store.SaveTemplate("ack", "ack", false)
+	require.NoError(t, err)
+
+	templates, err := store.ListTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	require.Equal(t, "ack", templates[0].Name)
+	require.Equal(t, "handoff", templates[1].Name)
+
+	tmpl, err := store.ReadTemplate("handoff")
+	require.NoError(t, err)
+	require.Equal(t, "replaced {{task}}", tmpl.Body)
+
+	require.NoError(t, store.DeleteTemplate("handoff"))
+	_, err = store.ReadTemplate("handoff")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRenderTemplate(t *testing.T) {
+	vars := TemplateVars("alice", "@bob")
+	vars["task"] = "auth"
+
+	rendered, missing := RenderTemplate("Hi {{to}}, {{ agent }} on {{task}} ({{eta}}, {{eta}})", vars)
+	require.Equal(t, "Hi bob, alice on auth ({{eta}}, {{eta}})", rendered)
+	require.Equal(t, []string{"eta"}, missing)
+
+	parsed, err := ParseTemplateVars([]string{"Task=a=b", "eta="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"task": "a=b", "eta": ""}, parsed)
+
+	_, err = ParseTemplateVars([]string{"novalue"})
+	require.Error(t, err)
+	_, err = ParseTemplateVars([]string{"bad key=x"})
+	require.Error(t, err)
+}
+
+func TestSendWithTemplate(t *testing.T) {
+	t.Setenv(EnvProject, "proj-test")
+	root := t.TempDir()
+	runtime := &Runtime{Root: root, Agent: "alice"}
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SaveTemplate("handoff", "{{agent}} -> {{to}}: {{task}}\n", false)
+	require.NoError(t, err)
+
+	newCmd := func() *cobra.Command {
+		cmd := newSendCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetContext(context.WithValue(context.Background(), runtimeKey{}, runtime))
+		require.NoError(t, cmd.Flags().Set("template", "handoff"))
+		return cmd
+	}
+
+	cmd := newCmd()
+	err = runSend(cmd, []string{"task"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "task")
+
+	cmd = newCmd()
+	require.Error(t, runSend(cmd, []string{"task", "inline body"}))
+
+	cmd = newCmd()
+	require.NoError(t, cmd.Flags().Set("var", "task=auth"))
+	require.NoError(t, runSend(cmd, []string{"task"}))
+
+	messages := runLogJSON(t, runtime, []string{"task"}, nil)
+	require.Len(t, messages, 1)
+	require.Equal(t, "alice -> task: auth\n", messages[0].Body)
+}
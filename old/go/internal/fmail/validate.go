@@ -50,11 +50,19 @@ func ValidateAgentName(name string) error {
 }
 
 // NormalizeTarget returns the normalized target and whether it is a DM.
+// Group targets ("#name") are normalized but are not DMs.
 func NormalizeTarget(target string) (string, bool, error) {
 	raw := strings.TrimSpace(target)
 	if raw == "" {
 		return "", false, ErrInvalidTarget
 	}
+	if IsGroupTarget(raw) {
+		group, err := NormalizeGroupName(raw)
+		if err != nil {
+			return "", false, err
+		}
+		return GroupTargetPrefix + group, false, nil
+	}
 	if strings.HasPrefix(raw, "@") {
 		agent, err := NormalizeAgentName(strings.TrimPrefix(raw, "@"))
 		if err != nil {
@@ -69,12 +77,19 @@ func NormalizeTarget(target string) (string, bool, error) {
 	return topic, false, nil
 }
 
-// ValidateTarget checks whether a target is a topic or direct message.
+// ValidateTarget checks whether a target is a topic, group, or direct message.
 func ValidateTarget(target string) error {
 	raw := strings.TrimSpace(target)
 	if raw == "" {
 		return ErrInvalidTarget
 	}
+	if IsGroupTarget(raw) {
+		group := strings.TrimPrefix(raw, GroupTargetPrefix)
+		if group == "" || group != strings.ToLower(group) || !namePattern.MatchString(group) {
+			return fmt.Errorf("%w: %s", ErrInvalidTarget, raw)
+		}
+		return nil
+	}
 	if strings.HasPrefix(raw, "@") {
 		return ValidateAgentName(strings.TrimPrefix(raw, "@"))
 	}
@@ -26,6 +26,7 @@ const (
 	watchAllMessages
 	watchTopic
 	watchDM
+	watchGroup
 )
 
 type watchTarget struct {
@@ -33,10 +34,23 @@ type watchTarget struct {
 	name string
 }
 
+// includes reports whether message belongs in the target's output. The
+// all-messages view reads group threads directly, so the members' DM copies
+// of group messages are skipped there.
+func (t watchTarget) includes(message *Message) bool {
+	if t.mode != watchAllMessages || message == nil {
+		return true
+	}
+	return message.Group == "" || !strings.HasPrefix(message.To, "@")
+}
+
 type watchOptions struct {
 	count      int
 	jsonOutput bool
 	deadline   time.Time
+	color      bool
+	exec       string
+	errOut     io.Writer
 }
 
 type watchFallback struct {
@@ -83,6 +97,12 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		return usageError(cmd, "timeout must be >= 0")
 	}
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	execCmd, _ := cmd.Flags().GetString("exec")
+	colorMode, _ := cmd.Flags().GetString("color")
+	color, err := resolveWatchColor(colorMode, cmd.OutOrStdout())
+	if err != nil {
+		return usageError(cmd, "%v", err)
+	}
 
 	store, err := NewStore(runtime.Root)
 	if err != nil {
@@ -101,6 +121,9 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		count:      count,
 		jsonOutput: jsonOutput,
 		deadline:   deadline,
+		color:      color && !jsonOutput,
+		exec:       execCmd,
+		errOut:     cmd.ErrOrStderr(),
 	}
 
 	fallback, err := watchConnected(ctx, runtime, target, opts, start, cmd.OutOrStdout())
@@ -117,6 +140,11 @@ func watchConnected(ctx context.Context, runtime *Runtime, target watchTarget, o
 	if runtime == nil {
 		return nil, Exitf(ExitCodeFailure, "runtime unavailable")
 	}
+	if target.mode == watchGroup {
+		// forged does not know about groups; group threads are only in the
+		// store.
+		return &watchFallback{scanStart: start}, nil
+	}
 	projectID, err := resolveProjectID(runtime.Root)
 	if err != nil {
 		return nil, Exitf(ExitCodeFailure, "resolve project id: %v", err)
@@ -280,7 +308,7 @@ func watchConnected(ctx context.Context, runtime *Runtime, target watchTarget, o
 					lastSeenID = env.Msg.ID
 				}
 				if allowDM || !strings.HasPrefix(env.Msg.To, "@") {
-					if err := writeWatchMessage(out, env.Msg, opts.jsonOutput); err != nil {
+					if err := deliverWatchMessage(ctx, out, env.Msg, opts); err != nil {
 						close(stopWatch)
 						conn.Close()
 						return nil, Exitf(ExitCodeFailure, "output: %v", err)
@@ -329,7 +357,7 @@ func watchStandalone(ctx context.Context, store *Store, target watchTarget, opts
 				return Exitf(ExitCodeFailure, "watch: %v", err)
 			}
 			for _, message := range messages {
-				if err := writeWatchMessage(out, message, opts.jsonOutput); err != nil {
+				if err := deliverWatchMessage(ctx, out, message, opts); err != nil {
 					return Exitf(ExitCodeFailure, "output: %v", err)
 				}
 				if remaining > 0 {
@@ -355,6 +383,13 @@ func parseWatchTarget(arg string) (watchTarget, error) {
 		}
 		return watchTarget{mode: watchDM, name: agent}, nil
 	}
+	if IsGroupTarget(trimmed) {
+		group, err := NormalizeGroupName(trimmed)
+		if err != nil {
+			return watchTarget{}, err
+		}
+		return watchTarget{mode: watchGroup, name: group}, nil
+	}
 	topic, err := NormalizeTopic(trimmed)
 	if err != nil {
 		return watchTarget{}, err
@@ -384,7 +419,7 @@ func scanNewMessages(store *Store, target watchTarget, seen map[string]struct{},
 			}
 			return nil, err
 		}
-		if !since.allows(message) {
+		if !since.allows(message) || !target.includes(message) {
 			seen[file.path] = struct{}{}
 			continue
 		}
@@ -435,6 +470,8 @@ func watchTopicRequest(target watchTarget) string {
 		return "*"
 	case watchDM:
 		return "@" + target.name
+	case watchGroup:
+		return GroupTargetPrefix + target.name
 	case watchTopic:
 		return target.name
 	default:
@@ -493,6 +530,8 @@ func listMessageFiles(store *Store, target watchTarget) ([]messageFile, error) {
 		return listFilesInDir(store.TopicDir(target.name))
 	case watchDM:
 		return listFilesInDir(store.DMDir(target.name))
+	case watchGroup:
+		return listFilesInDir(store.GroupThreadDir(target.name))
 	default:
 		return nil, fmt.Errorf("unknown watch target")
 	}
@@ -511,9 +550,14 @@ func listAllMessageFiles(store *Store) ([]messageFile, error) {
 	if err != nil {
 		return nil, err
 	}
-	files := make([]messageFile, 0, len(topicFiles)+len(dmFiles))
+	groupFiles, err := listAllSubdirFiles(store.GroupsDir())
+	if err != nil {
+		return nil, err
+	}
+	files := make([]messageFile, 0, len(topicFiles)+len(dmFiles)+len(groupFiles))
 	files = append(files, topicFiles...)
 	files = append(files, dmFiles...)
+	files = append(files, groupFiles...)
 	return files, nil
 }
 
@@ -586,21 +630,15 @@ func listFilesInDir(dir string) ([]messageFile, error) {
 	return files, nil
 }
 
-func writeWatchMessage(out io.Writer, message *Message, jsonOutput bool) error {
-	if jsonOutput {
-		data, err := json.Marshal(message)
-		if err != nil {
-			return err
-		}
-		_, err = fmt.Fprintln(out, string(data))
-		return err
+func formatAttachmentSuffix(attachments []Attachment) string {
+	if len(attachments) == 0 {
+		return ""
 	}
-	body, err := formatMessageBody(message.Body)
-	if err != nil {
-		return err
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.Name)
 	}
-	_, err = fmt.Fprintf(out, "%s %s -> %s: %s\n", message.ID, message.From, message.To, body)
-	return err
+	return fmt.Sprintf(" [%d attachment(s): %s]", len(attachments), strings.Join(names, ", "))
 }
 
 func formatMessageBody(body any) (string, error) {
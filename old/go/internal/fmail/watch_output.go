@@ -0,0 +1,157 @@
+package fmail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Watch color modes accepted by --color.
+const (
+	watchColorAuto   = "auto"
+	watchColorAlways = "always"
+	watchColorNever  = "never"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiBold    = "\x1b[1m"
+	ansiCyan    = "\x1b[36m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// watchAgentColors are cycled per sender so one agent keeps one color.
+var watchAgentColors = []string{
+	"\x1b[32m", // green
+	"\x1b[33m", // yellow
+	"\x1b[34m", // blue
+	"\x1b[91m", // bright red
+	"\x1b[92m", // bright green
+	"\x1b[94m", // bright blue
+	"\x1b[95m", // bright magenta
+	"\x1b[96m", // bright cyan
+}
+
+// resolveWatchColor decides whether watch output is colorized. Auto colors
+// only when out is a terminal and NO_COLOR is unset.
+func resolveWatchColor(mode string, out io.Writer) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", watchColorAuto:
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		file, ok := out.(*os.File)
+		return ok && term.IsTerminal(int(file.Fd())), nil
+	case watchColorAlways:
+		return true, nil
+	case watchColorNever:
+		return false, nil
+	default:
+		return false, fmt.Errorf("color must be auto, always, or never")
+	}
+}
+
+// deliverWatchMessage prints one watched message and, when --exec is set,
+// runs the command for it. A failing command is reported on opts.errOut and
+// does not stop the watch.
+func deliverWatchMessage(ctx context.Context, out io.Writer, message *Message, opts watchOptions) error {
+	if err := writeWatchMessage(out, message, opts); err != nil {
+		return err
+	}
+	if strings.TrimSpace(opts.exec) == "" {
+		return nil
+	}
+	errOut := opts.errOut
+	if errOut == nil {
+		errOut = io.Discard
+	}
+	if err := runWatchExec(ctx, opts.exec, message, out, errOut); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(errOut, "fmail watch: exec for %s failed: %v\n", message.ID, err)
+	}
+	return nil
+}
+
+// runWatchExec runs command through sh with the message as JSON on stdin and
+// its fields in FMAIL_MSG_* environment variables.
+func runWatchExec(ctx context.Context, command string, message *Message, out, errOut io.Writer) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	body, err := formatMessageBody(message.Body)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+	cmd.Env = append(os.Environ(),
+		"FMAIL_MSG_ID="+message.ID,
+		"FMAIL_MSG_FROM="+message.From,
+		"FMAIL_MSG_TO="+message.To,
+		"FMAIL_MSG_BODY="+body,
+		"FMAIL_MSG_TIME="+message.Time.UTC().Format(time.RFC3339Nano),
+	)
+	return cmd.Run()
+}
+
+func writeWatchMessage(out io.Writer, message *Message, opts watchOptions) error {
+	if opts.jsonOutput {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+	body, err := formatMessageBody(message.Body)
+	if err != nil {
+		return err
+	}
+	attachments := formatAttachmentSuffix(message.Attachments)
+	target := displayTarget(message)
+	if !opts.color {
+		_, err = fmt.Fprintf(out, "%s %s -> %s: %s%s\n", message.ID, message.From, target, body, attachments)
+		return err
+	}
+
+	to := ansiCyan + target + ansiReset
+	if strings.HasPrefix(target, "@") {
+		to = ansiMagenta + target + ansiReset
+	}
+	if attachments != "" {
+		attachments = ansiDim + attachments + ansiReset
+	}
+	_, err = fmt.Fprintf(out, "%s%s%s %s%s%s%s -> %s: %s%s\n",
+		ansiDim, message.ID, ansiReset,
+		ansiBold, watchAgentColor(message.From), message.From, ansiReset,
+		to, body, attachments)
+	return err
+}
+
+func watchAgentColor(agent string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(agent))
+	return watchAgentColors[hash.Sum32()%uint32(len(watchAgentColors))]
+}
+
+// displayTarget shows a member's DM copy of a group message under the group
+// it was sent to.
+func displayTarget(message *Message) string {
+	if message.Group != "" {
+		return GroupTargetPrefix + message.Group
+	}
+	return message.To
+}
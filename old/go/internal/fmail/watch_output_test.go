@@ -0,0 +1,79 @@
+package fmail
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchExecRunsPerMessage(t *testing.T) {
+	t.Setenv(EnvProject, "proj-test")
+	root := t.TempDir()
+	runtime := &Runtime{Root: root, Agent: "alice"}
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	hookOut := filepath.Join(t.TempDir(), "hook.log")
+	var out, errOut bytes.Buffer
+	opts := watchOptions{
+		count:    2,
+		deadline: time.Now().Add(2 * time.Second),
+		exec:     `printf '%s|%s|%s\n' "$FMAIL_MSG_FROM" "$FMAIL_MSG_TO" "$FMAIL_MSG_BODY" >> "` + hookOut + `"; grep -q '"body":"boom"' && exit 3; true`,
+		errOut:   &errOut,
+	}
+	target := watchTarget{mode: watchTopic, name: "task"}
+	scanStart := time.Now().UTC().Add(-time.Second)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watchStandalone(ctx, store, target, opts, scanStart, messageSince{}, &out)
+	}()
+
+	for _, body := range []string{"ping", "boom"} {
+		_, err = sendStandalone(runtime, &Message{From: runtime.Agent, To: "task", Body: body})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, <-errCh)
+	data, err := os.ReadFile(hookOut)
+	require.NoError(t, err)
+	require.Equal(t, "alice|task|ping\nalice|task|boom\n", string(data))
+	require.Contains(t, errOut.String(), "exec for")
+	require.Contains(t, errOut.String(), "exit status 3")
+	require.Equal(t, 2, strings.Count(out.String(), "alice -> task"))
+}
+
+func TestWriteWatchMessageColor(t *testing.T) {
+	message := &Message{ID: "20260101-000000-0001", From: "alice", To: "@bob", Body: "hi"}
+
+	var plain bytes.Buffer
+	require.NoError(t, writeWatchMessage(&plain, message, watchOptions{}))
+	require.Equal(t, "20260101-000000-0001 alice -> @bob: hi\n", plain.String())
+
+	var colored bytes.Buffer
+	require.NoError(t, writeWatchMessage(&colored, message, watchOptions{color: true}))
+	require.Contains(t, colored.String(), ansiMagenta+"@bob"+ansiReset)
+	require.Contains(t, colored.String(), watchAgentColor("alice")+"alice"+ansiReset)
+	require.Equal(t, watchAgentColor("alice"), watchAgentColor("alice"))
+}
+
+func TestResolveWatchColor(t *testing.T) {
+	var buf bytes.Buffer
+	for mode, want := range map[string]bool{"auto": false, "always": true, "never": false} {
+		got, err := resolveWatchColor(mode, &buf)
+		require.NoError(t, err)
+		require.Equal(t, want, got, mode)
+	}
+	_, err := resolveWatchColor("rainbow", &buf)
+	require.Error(t, err)
+}
@@ -0,0 +1,316 @@
+package fmail
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	webhookDirPerm  = 0o700
+	webhookFilePerm = 0o600
+
+	// WebhookSignatureHeader carries "sha256=<hex HMAC of the body>" when the
+	// webhook has a secret, matching GitHub's scheme so receivers can reuse
+	// their verification code.
+	WebhookSignatureHeader = "X-Fmail-Signature-256"
+	// WebhookEventHeader names the event type of an outbound delivery.
+	WebhookEventHeader = "X-Fmail-Event"
+
+	webhookEventMessage   = "message"
+	webhookDeliverTimeout = 10 * time.Second
+)
+
+// Webhook is an outbound integration stored under .fmail/webhooks. Every new
+// message whose target matches Topics and which carries one of Tags is
+// POSTed to URL as a WebhookEvent.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Topics are path.Match patterns over the message target. Empty matches
+	// every topic and group. DMs only match patterns starting with "@".
+	Topics []string `json:"topics,omitempty"`
+	// Tags, when set, require the message to carry at least one of them.
+	Tags      []string  `json:"tags,omitempty"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// WebhookEvent is the JSON body POSTed to a webhook.
+type WebhookEvent struct {
+	Event   string    `json:"event"`
+	Webhook string    `json:"webhook"`
+	Project string    `json:"project,omitempty"`
+	Message *Message  `json:"message"`
+	Sent    time.Time `json:"sent"`
+}
+
+// NormalizeWebhookName lowercases and validates a webhook name. Names follow
+// the topic naming rules.
+func NormalizeWebhookName(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" || !namePattern.MatchString(normalized) {
+		return "", ErrInvalidWebhook
+	}
+	return normalized, nil
+}
+
+// Matches reports whether message should be delivered to the webhook. The
+// members' DM copies of group messages never match; the group thread copy
+// does.
+func (w *Webhook) Matches(message *Message) bool {
+	if w == nil || message == nil {
+		return false
+	}
+	isDM := strings.HasPrefix(message.To, "@")
+	if isDM && message.Group != "" {
+		return false
+	}
+	if len(w.Topics) == 0 {
+		if isDM {
+			return false
+		}
+	} else if !webhookTopicMatch(w.Topics, message.To, isDM) {
+		return false
+	}
+	if len(w.Tags) == 0 {
+		return true
+	}
+	for _, want := range w.Tags {
+		for _, tag := range message.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func webhookTopicMatch(patterns []string, target string, isDM bool) bool {
+	for _, pattern := range patterns {
+		if isDM != strings.HasPrefix(pattern, "@") {
+			continue
+		}
+		if ok, err := path.Match(pattern, target); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWebhookTopics validates topic patterns. Patterns may use
+// path.Match wildcards and the "@" and "#" target prefixes.
+func normalizeWebhookTopics(topics []string) ([]string, error) {
+	out := make([]string, 0, len(topics))
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		pattern := strings.ToLower(strings.TrimSpace(topic))
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid topic pattern %q: %w", topic, err)
+		}
+		seen[pattern] = true
+		out = append(out, pattern)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (s *Store) WebhooksDir() string {
+	return filepath.Join(s.Root, "webhooks")
+}
+
+func (s *Store) webhookPath(name string) (string, string, error) {
+	normalized, err := NormalizeWebhookName(name)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(s.WebhooksDir(), normalized+".json"), normalized, nil
+}
+
+// SaveWebhook creates or replaces a webhook. The file is written 0600 since
+// it may hold a signing secret.
+func (s *Store) SaveWebhook(hook Webhook) (*Webhook, error) {
+	path, normalized, err := s.webhookPath(hook.Name)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := url.Parse(strings.TrimSpace(hook.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("webhook url must be an http(s) URL: %q", hook.URL)
+	}
+	topics, err := normalizeWebhookTopics(hook.Topics)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := NormalizeTags(hook.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EnsureRoot(); err != nil {
+		return nil, err
+	}
+	if err := ensureDirPerm(s.WebhooksDir(), webhookDirPerm); err != nil {
+		return nil, err
+	}
+
+	saved := &Webhook{
+		Name:      normalized,
+		URL:       parsed.String(),
+		Topics:    topics,
+		Tags:      tags,
+		Secret:    hook.Secret,
+		CreatedBy: strings.TrimSpace(hook.CreatedBy),
+		Created:   s.now(),
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, webhookFilePerm); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ReadWebhook loads a webhook by name. Missing webhooks return
+// ErrWebhookNotFound.
+func (s *Store) ReadWebhook(name string) (*Webhook, error) {
+	path, normalized, err := s.webhookPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrWebhookNotFound, normalized)
+		}
+		return nil, err
+	}
+	var hook Webhook
+	if err := json.Unmarshal(data, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// DeleteWebhook removes a webhook.
+func (s *Store) DeleteWebhook(name string) error {
+	path, normalized, err := s.webhookPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrWebhookNotFound, normalized)
+		}
+		return err
+	}
+	return nil
+}
+
+// ListWebhooks returns all webhooks sorted by name.
+func (s *Store) ListWebhooks() ([]Webhook, error) {
+	entries, err := os.ReadDir(s.WebhooksDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	hooks := make([]Webhook, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		hook, err := s.ReadWebhook(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, *hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
+
+// WebhookClient delivers webhook events over HTTP.
+type WebhookClient struct {
+	HTTP    *http.Client
+	Project string
+	now     func() time.Time
+}
+
+// NewWebhookClient returns a client with a bounded request timeout.
+func NewWebhookClient(project string) *WebhookClient {
+	return &WebhookClient{
+		HTTP:    &http.Client{Timeout: webhookDeliverTimeout},
+		Project: project,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Deliver POSTs message to hook. Non-2xx responses are errors.
+func (c *WebhookClient) Deliver(ctx context.Context, hook Webhook, message *Message) error {
+	event := WebhookEvent{
+		Event:   webhookEventMessage,
+		Webhook: hook.Name,
+		Project: c.Project,
+		Message: message,
+		Sent:    c.now(),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fmail-webhook")
+	req.Header.Set(WebhookEventHeader, webhookEventMessage)
+	if hook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(hook.Secret, body))
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded %s", hook.URL, resp.Status)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the "sha256=<hex>" HMAC signature of body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a "sha256=<hex>" signature in constant time.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, body)
+	return hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature)))
+}
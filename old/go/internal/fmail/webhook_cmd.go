@@ -0,0 +1,570 @@
+package fmail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	webhookDefaultFrom = "webhook"
+	webhookDefaultAddr = "127.0.0.1:8089"
+	webhookPollPeriod  = 500 * time.Millisecond
+	webhookMaxAttempts = 3
+)
+
+func newWebhookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "webhook",
+		Aliases: []string{"webhooks"},
+		Short:   "Bridge messages to and from external services",
+		Long: `Outbound webhooks are stored in .fmail/webhooks. "fmail webhook run" follows
+the store and POSTs every new message whose target matches the webhook's
+--topic patterns and carries one of its --tag values. With --secret the body
+is signed in the X-Fmail-Signature-256 header (sha256=<hex HMAC>).
+
+Inbound payloads become messages: "fmail webhook receive" reads one from
+stdin, "fmail webhook serve" accepts them over HTTP. GitHub events (push,
+pull_request, issues, workflow_run, check_run) and CI status JSON are
+summarized into one line plus a link; other payloads are forwarded as-is.`,
+	}
+
+	list := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List webhooks",
+		Args:    argsMax(0),
+		RunE:    runWebhookList,
+	}
+	list.Flags().Bool("json", false, "Output as JSON")
+
+	add := &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: "Create or replace an outbound webhook",
+		Args:  argsRange(2, 2),
+		RunE:  runWebhookAdd,
+	}
+	add.Flags().StringArray("topic", nil, "Target pattern to forward, e.g. build, task-*, #team, @alice (repeatable; default all topics and groups)")
+	add.Flags().StringSlice("tag", nil, "Only forward messages carrying one of these tags")
+	add.Flags().String("secret", "", "Sign deliveries with this HMAC secret")
+
+	remove := &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"remove"},
+		Short:   "Delete a webhook",
+		Args:    argsRange(1, 1),
+		RunE:    runWebhookRemove,
+	}
+
+	test := &cobra.Command{
+		Use:   "test <name>",
+		Short: "Send a sample message to a webhook",
+		Args:  argsRange(1, 1),
+		RunE:  runWebhookTest,
+	}
+
+	run := &cobra.Command{
+		Use:   "run",
+		Short: "Deliver new messages to matching webhooks",
+		Args:  argsMax(0),
+		RunE:  runWebhookRun,
+	}
+	run.Flags().Duration("timeout", 0, "Stop after this long (0 = until interrupted)")
+
+	receive := &cobra.Command{
+		Use:   "receive <target>",
+		Short: "Convert a payload from stdin into a message",
+		Args:  argsRange(1, 1),
+		RunE:  runWebhookReceive,
+	}
+	addInboundFlags(receive)
+	receive.Flags().StringP("file", "f", "", "Read the payload from a file")
+	receive.Flags().String("github-event", "", "GitHub event name (the X-GitHub-Event header)")
+	receive.Flags().Bool("json", false, "Output the sent message as JSON")
+
+	serve := &cobra.Command{
+		Use:   "serve <target>",
+		Short: "Accept payloads over HTTP and send them as messages",
+		Args:  argsRange(1, 1),
+		RunE:  runWebhookServe,
+	}
+	addInboundFlags(serve)
+	serve.Flags().String("addr", webhookDefaultAddr, "Listen address")
+	serve.Flags().String("secret", "", "Require requests signed with this secret (X-Hub-Signature-256 or X-Fmail-Signature-256)")
+
+	cmd.AddCommand(list, add, remove, test, run, receive, serve)
+	return cmd
+}
+
+func addInboundFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", InboundFormatAuto, "Payload format: auto, github, ci, or raw")
+	cmd.Flags().String("from", webhookDefaultFrom, "Agent name the messages are sent as")
+	cmd.Flags().StringSlice("tag", nil, "Extra tags for every message")
+	cmd.Flags().String("priority", "", "Priority override: low, normal, high")
+}
+
+func webhookError(name string, err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidWebhook):
+		return Exitf(ExitCodeFailure, "invalid webhook name %q", name)
+	case errors.Is(err, ErrWebhookNotFound):
+		return Exitf(ExitCodeFailure, "webhook %q not found", name)
+	default:
+		return Exitf(ExitCodeFailure, "webhook: %v", err)
+	}
+}
+
+func runWebhookList(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	hooks, err := store.ListWebhooks()
+	if err != nil {
+		return Exitf(ExitCodeFailure, "list webhooks: %v", err)
+	}
+
+	if jsonOutput {
+		if hooks == nil {
+			hooks = []Webhook{}
+		}
+		for i := range hooks {
+			hooks[i].Secret = ""
+		}
+		payload, err := json.MarshalIndent(hooks, "", "  ")
+		if err != nil {
+			return Exitf(ExitCodeFailure, "encode webhooks: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "WEBHOOK\tURL\tTOPICS\tTAGS\tSIGNED")
+	for _, hook := range hooks {
+		topics := strings.Join(hook.Topics, ",")
+		if topics == "" {
+			topics = "*"
+		}
+		tags := strings.Join(hook.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		signed := "no"
+		if hook.Secret != "" {
+			signed = "yes"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", hook.Name, hook.URL, topics, tags, signed)
+	}
+	if err := writer.Flush(); err != nil {
+		return Exitf(ExitCodeFailure, "write output: %v", err)
+	}
+	return nil
+}
+
+func runWebhookAdd(cmd *cobra.Command, args []string) error {
+	runtime, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	topics, _ := cmd.Flags().GetStringArray("topic")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	secret, _ := cmd.Flags().GetString("secret")
+
+	hook, err := store.SaveWebhook(Webhook{
+		Name:      args[0],
+		URL:       args[1],
+		Topics:    topics,
+		Tags:      tags,
+		Secret:    secret,
+		CreatedBy: runtime.Agent,
+	})
+	if err != nil {
+		return webhookError(args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "webhook %s -> %s\n", hook.Name, hook.URL)
+	return nil
+}
+
+func runWebhookRemove(cmd *cobra.Command, args []string) error {
+	_, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	if err := store.DeleteWebhook(args[0]); err != nil {
+		return webhookError(args[0], err)
+	}
+	return nil
+}
+
+func runWebhookTest(cmd *cobra.Command, args []string) error {
+	runtime, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	hook, err := store.ReadWebhook(args[0])
+	if err != nil {
+		return webhookError(args[0], err)
+	}
+	projectID, err := resolveProjectID(runtime.Root)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "resolve project id: %v", err)
+	}
+
+	target := "webhook-test"
+	if len(hook.Topics) > 0 && !strings.ContainsAny(hook.Topics[0], "*?[") {
+		target = hook.Topics[0]
+	}
+	now := time.Now().UTC()
+	sample := &Message{
+		ID:   GenerateMessageID(now),
+		From: runtime.Agent,
+		To:   target,
+		Time: now,
+		Body: fmt.Sprintf("test delivery for webhook %s", hook.Name),
+		Tags: hook.Tags,
+	}
+	if err := NewWebhookClient(projectID).Deliver(cmd.Context(), *hook, sample); err != nil {
+		return Exitf(ExitCodeFailure, "webhook %s: %v", hook.Name, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "delivered test message to %s\n", hook.URL)
+	return nil
+}
+
+func runWebhookRun(cmd *cobra.Command, args []string) error {
+	runtime, store, err := templateStore(cmd)
+	if err != nil {
+		return err
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout < 0 {
+		return usageError(cmd, "timeout must be >= 0")
+	}
+	projectID, err := resolveProjectID(runtime.Root)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "resolve project id: %v", err)
+	}
+	if err := store.EnsureRoot(); err != nil {
+		return Exitf(ExitCodeFailure, "init store: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	dispatcher := &webhookDispatcher{
+		store:  store,
+		client: NewWebhookClient(projectID),
+		out:    cmd.OutOrStdout(),
+		errOut: cmd.ErrOrStderr(),
+	}
+	return dispatcher.run(ctx, time.Now().UTC(), webhookPollPeriod)
+}
+
+// webhookDispatcher delivers messages written to the store after it starts.
+// Webhooks are re-read on every poll so add/rm take effect without a
+// restart. Failed deliveries are retried a few times, then reported and
+// dropped; they never block later messages.
+type webhookDispatcher struct {
+	store  *Store
+	client *WebhookClient
+	out    io.Writer
+	errOut io.Writer
+	// retryDelay is the base delay between attempts; zero means one second.
+	retryDelay time.Duration
+}
+
+func (d *webhookDispatcher) run(ctx context.Context, start time.Time, interval time.Duration) error {
+	seen := make(map[string]struct{})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := d.poll(ctx, seen, start); err != nil {
+				return Exitf(ExitCodeFailure, "webhook: %v", err)
+			}
+		}
+	}
+}
+
+func (d *webhookDispatcher) poll(ctx context.Context, seen map[string]struct{}, start time.Time) error {
+	hooks, err := d.store.ListWebhooks()
+	if err != nil {
+		return err
+	}
+	messages, err := scanNewMessages(d.store, watchTarget{mode: watchAllMessages}, seen, start, messageSince{})
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		for _, hook := range hooks {
+			if !hook.Matches(message) {
+				continue
+			}
+			if err := d.deliver(ctx, hook, message); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				fmt.Fprintf(d.errOut, "webhook %s: message %s: %v\n", hook.Name, message.ID, err)
+				continue
+			}
+			fmt.Fprintf(d.out, "%s -> %s\n", message.ID, hook.Name)
+		}
+	}
+	return nil
+}
+
+func (d *webhookDispatcher) deliver(ctx context.Context, hook Webhook, message *Message) error {
+	delay := d.retryDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	var err error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		if err = d.client.Deliver(ctx, hook, message); err == nil {
+			return nil
+		}
+		if attempt == webhookMaxAttempts {
+			break
+		}
+		timer := time.NewTimer(time.Duration(attempt) * delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// inboundOptions holds the flags shared by receive and serve.
+type inboundOptions struct {
+	target   string
+	format   string
+	from     string
+	tags     []string
+	priority string
+}
+
+func parseInboundOptions(cmd *cobra.Command, target string) (inboundOptions, error) {
+	normalizedTarget, _, err := NormalizeTarget(target)
+	if err != nil {
+		return inboundOptions{}, Exitf(ExitCodeFailure, "invalid target %q: %v", target, err)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case InboundFormatAuto, InboundFormatGitHub, InboundFormatCI, InboundFormatRaw:
+	default:
+		return inboundOptions{}, usageError(cmd, "unknown format %q (use auto, github, ci, or raw)", format)
+	}
+	from, _ := cmd.Flags().GetString("from")
+	from, err = NormalizeAgentName(from)
+	if err != nil {
+		return inboundOptions{}, Exitf(ExitCodeFailure, "invalid --from: %v", err)
+	}
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	normalizedTags, err := NormalizeTags(tags)
+	if err != nil {
+		return inboundOptions{}, Exitf(ExitCodeFailure, "invalid tags: %v", err)
+	}
+	priority, _ := cmd.Flags().GetString("priority")
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	if priority != "" {
+		if err := ValidatePriority(priority); err != nil {
+			return inboundOptions{}, Exitf(ExitCodeFailure, "invalid priority: %s", priority)
+		}
+	}
+	return inboundOptions{
+		target:   normalizedTarget,
+		format:   format,
+		from:     from,
+		tags:     normalizedTags,
+		priority: priority,
+	}, nil
+}
+
+// buildInboundMessage converts a payload into the message to send.
+func (o inboundOptions) buildInboundMessage(githubEvent string, data []byte) (*Message, error) {
+	converted, err := ConvertInbound(o.format, githubEvent, data)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := NormalizeTags(compactTags(append(converted.Tags, o.tags...)))
+	if err != nil {
+		return nil, err
+	}
+	message := &Message{
+		From:     o.from,
+		To:       o.target,
+		Body:     converted.Body,
+		Tags:     tags,
+		Priority: converted.Priority,
+	}
+	if o.priority != "" {
+		message.Priority = o.priority
+	}
+	return message, nil
+}
+
+// sendInbound sends message as opts.from, through forged when it is running.
+func sendInbound(runtime *Runtime, from string, message *Message) (sendResult, error) {
+	sender := &Runtime{Root: runtime.Root, Agent: from}
+	result, err := sendViaForged(sender, message)
+	if err == nil {
+		return result, nil
+	}
+	if errors.Is(err, errForgedUnavailable) || errors.Is(err, errForgedDisconnected) {
+		return sendStandalone(sender, message)
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return sendResult{}, exitErr
+	}
+	return sendResult{}, Exitf(ExitCodeFailure, "forged: %v", err)
+}
+
+func runWebhookReceive(cmd *cobra.Command, args []string) error {
+	runtime, err := EnsureRuntime(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := parseInboundOptions(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	githubEvent, _ := cmd.Flags().GetString("github-event")
+	filePath, _ := cmd.Flags().GetString("file")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var data string
+	if strings.TrimSpace(filePath) != "" {
+		raw, err := os.ReadFile(filePath)
+		if err != nil {
+			return Exitf(ExitCodeFailure, "read file: %v", err)
+		}
+		data = string(raw)
+	} else {
+		data, err = readStdinIfPiped()
+		if err != nil {
+			return Exitf(ExitCodeFailure, "read stdin: %v", err)
+		}
+	}
+	if strings.TrimSpace(data) == "" {
+		return usageError(cmd, "payload is required on stdin or via --file")
+	}
+
+	message, err := opts.buildInboundMessage(githubEvent, []byte(data))
+	if err != nil {
+		return Exitf(ExitCodeFailure, "convert payload: %v", err)
+	}
+	result, err := sendInbound(runtime, opts.from, message)
+	if err != nil {
+		return err
+	}
+	return writeSendResult(cmd, result, jsonOutput)
+}
+
+func runWebhookServe(cmd *cobra.Command, args []string) error {
+	runtime, err := EnsureRuntime(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := parseInboundOptions(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	addr, _ := cmd.Flags().GetString("addr")
+	secret, _ := cmd.Flags().GetString("secret")
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return Exitf(ExitCodeFailure, "listen %s: %v", addr, err)
+	}
+	server := &http.Server{
+		Handler:           newInboundHandler(runtime, opts, secret, cmd.ErrOrStderr()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "listening on http://%s -> %s\n", listener.Addr(), opts.target)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return Exitf(ExitCodeFailure, "serve: %v", err)
+	}
+	return nil
+}
+
+// newInboundHandler converts each POSTed payload into a message. With a
+// secret, requests must carry a valid GitHub or fmail HMAC signature.
+func newInboundHandler(runtime *Runtime, opts inboundOptions, secret string, errOut io.Writer) http.Handler {
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, MaxMessageSize+1))
+		if err != nil {
+			http.Error(w, "read body", http.StatusBadRequest)
+			return
+		}
+		if len(data) > MaxMessageSize {
+			http.Error(w, ErrMessageTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if secret != "" {
+			signature := firstNonEmpty(r.Header.Get("X-Hub-Signature-256"), r.Header.Get(WebhookSignatureHeader))
+			if !VerifyWebhookSignature(secret, data, signature) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		message, err := opts.buildInboundMessage(r.Header.Get("X-GitHub-Event"), data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		result, err := sendInbound(runtime, opts.from, message)
+		mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(errOut, "webhook serve: %v\n", err)
+			http.Error(w, "send failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": result.ID, "to": message.To})
+	})
+}
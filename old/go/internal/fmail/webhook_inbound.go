@@ -0,0 +1,303 @@
+package fmail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Inbound payload formats accepted by "fmail webhook receive" and
+// "fmail webhook serve".
+const (
+	InboundFormatAuto   = "auto"
+	InboundFormatGitHub = "github"
+	InboundFormatCI     = "ci"
+	InboundFormatRaw    = "raw"
+)
+
+// InboundMessage holds the message fields converted from an external
+// payload.
+type InboundMessage struct {
+	Body     any
+	Tags     []string
+	Priority string
+}
+
+// ConvertInbound turns an external payload into message fields. GitHub
+// events (githubEvent is the X-GitHub-Event header) and CI status payloads
+// become one-line summaries with a link; anything else is forwarded as-is,
+// JSON staying structured. Failed runs are sent with high priority.
+func ConvertInbound(format, githubEvent string, data []byte) (InboundMessage, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = InboundFormatAuto
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return InboundMessage{}, fmt.Errorf("empty payload")
+	}
+	if format == InboundFormatAuto {
+		switch {
+		case strings.TrimSpace(githubEvent) != "":
+			format = InboundFormatGitHub
+		case looksLikeCIStatus(data):
+			format = InboundFormatCI
+		default:
+			format = InboundFormatRaw
+		}
+	}
+
+	switch format {
+	case InboundFormatGitHub:
+		return convertGitHubEvent(githubEvent, data)
+	case InboundFormatCI:
+		return convertCIStatus(data)
+	case InboundFormatRaw:
+		body, err := parseMessageBody(string(data))
+		if err != nil {
+			return InboundMessage{}, err
+		}
+		return InboundMessage{Body: body}, nil
+	default:
+		return InboundMessage{}, fmt.Errorf("unknown format %q (use auto, github, ci, or raw)", format)
+	}
+}
+
+type githubEventPayload struct {
+	Action     string `json:"action"`
+	Ref        string `json:"ref"`
+	Compare    string `json:"compare"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+	Pusher struct {
+		Name string `json:"name"`
+	} `json:"pusher"`
+	Commits    []json.RawMessage `json:"commits"`
+	HeadCommit *struct {
+		Message string `json:"message"`
+	} `json:"head_commit"`
+	PullRequest *githubItem `json:"pull_request"`
+	Issue       *githubItem `json:"issue"`
+	WorkflowRun *githubRun  `json:"workflow_run"`
+	CheckRun    *githubRun  `json:"check_run"`
+}
+
+type githubItem struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+}
+
+type githubRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	HeadBranch string `json:"head_branch"`
+}
+
+func convertGitHubEvent(event string, data []byte) (InboundMessage, error) {
+	var payload githubEventPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return InboundMessage{}, fmt.Errorf("decode github payload: %w", err)
+	}
+	event = strings.ToLower(strings.TrimSpace(event))
+	if event == "" {
+		event = "event"
+	}
+	repo := payload.Repository.FullName
+	if repo == "" {
+		repo = "github"
+	}
+	tags := []string{"github", inboundTag(event)}
+
+	var lines []string
+	priority := ""
+	switch {
+	case event == "ping":
+		lines = append(lines, fmt.Sprintf("%s: webhook ping", repo))
+	case event == "push":
+		branch := strings.TrimPrefix(payload.Ref, "refs/heads/")
+		pusher := firstNonEmpty(payload.Pusher.Name, payload.Sender.Login)
+		lines = append(lines, fmt.Sprintf("%s: %s pushed %d commit(s) to %s", repo, pusher, len(payload.Commits), branch))
+		if payload.HeadCommit != nil {
+			if subject := firstLine(payload.HeadCommit.Message); subject != "" {
+				lines = append(lines, subject)
+			}
+		}
+		lines = appendNonEmpty(lines, payload.Compare)
+	case payload.PullRequest != nil:
+		action := payload.Action
+		if action == "closed" && payload.PullRequest.Merged {
+			action = "merged"
+		}
+		lines = append(lines, fmt.Sprintf("%s#%d %s by %s: %s", repo, payload.PullRequest.Number, action, payload.Sender.Login, payload.PullRequest.Title))
+		lines = appendNonEmpty(lines, payload.PullRequest.HTMLURL)
+		tags = append(tags, inboundTag(action))
+	case payload.Issue != nil:
+		lines = append(lines, fmt.Sprintf("%s#%d issue %s by %s: %s", repo, payload.Issue.Number, payload.Action, payload.Sender.Login, payload.Issue.Title))
+		lines = appendNonEmpty(lines, payload.Issue.HTMLURL)
+		tags = append(tags, inboundTag(payload.Action))
+	case payload.WorkflowRun != nil || payload.CheckRun != nil:
+		run, kind := payload.WorkflowRun, "workflow"
+		if run == nil {
+			run, kind = payload.CheckRun, "check"
+		}
+		state := firstNonEmpty(run.Conclusion, run.Status)
+		summary := fmt.Sprintf("%s: %s %s %s", repo, kind, run.Name, state)
+		if run.HeadBranch != "" {
+			summary += " on " + run.HeadBranch
+		}
+		lines = append(lines, summary)
+		lines = appendNonEmpty(lines, run.HTMLURL)
+		tags = append(tags, inboundTag(state))
+		if isFailureStatus(state) {
+			priority = PriorityHigh
+		}
+	default:
+		summary := fmt.Sprintf("%s: github %s", repo, event)
+		if payload.Action != "" {
+			summary += " " + payload.Action
+		}
+		if payload.Sender.Login != "" {
+			summary += " by " + payload.Sender.Login
+		}
+		lines = append(lines, summary)
+	}
+
+	return InboundMessage{Body: strings.Join(lines, "\n"), Tags: compactTags(tags), Priority: priority}, nil
+}
+
+// ciStatusPayload accepts the field names common CI systems use for a build
+// result.
+type ciStatusPayload struct {
+	Name       string `json:"name"`
+	Job        string `json:"job"`
+	Pipeline   string `json:"pipeline"`
+	Workflow   string `json:"workflow"`
+	Status     string `json:"status"`
+	State      string `json:"state"`
+	Conclusion string `json:"conclusion"`
+	Result     string `json:"result"`
+	URL        string `json:"url"`
+	BuildURL   string `json:"build_url"`
+	WebURL     string `json:"web_url"`
+	Branch     string `json:"branch"`
+	Ref        string `json:"ref"`
+	Commit     string `json:"commit"`
+	SHA        string `json:"sha"`
+	Message    string `json:"message"`
+}
+
+func (p ciStatusPayload) status() string {
+	return strings.ToLower(firstNonEmpty(p.Conclusion, p.Result, p.Status, p.State))
+}
+
+func looksLikeCIStatus(data []byte) bool {
+	var payload ciStatusPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return false
+	}
+	return payload.status() != ""
+}
+
+func convertCIStatus(data []byte) (InboundMessage, error) {
+	var payload ciStatusPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return InboundMessage{}, fmt.Errorf("decode ci payload: %w", err)
+	}
+	status := payload.status()
+	if status == "" {
+		return InboundMessage{}, fmt.Errorf("ci payload has no status")
+	}
+	name := firstNonEmpty(payload.Name, payload.Job, payload.Pipeline, payload.Workflow, "build")
+
+	summary := fmt.Sprintf("ci: %s %s", name, status)
+	if branch := strings.TrimPrefix(firstNonEmpty(payload.Branch, payload.Ref), "refs/heads/"); branch != "" {
+		summary += " on " + branch
+	}
+	if commit := firstNonEmpty(payload.Commit, payload.SHA); commit != "" {
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		summary += " (" + commit + ")"
+	}
+	lines := []string{summary}
+	lines = appendNonEmpty(lines, firstLine(payload.Message))
+	lines = appendNonEmpty(lines, firstNonEmpty(payload.URL, payload.BuildURL, payload.WebURL))
+
+	message := InboundMessage{
+		Body: strings.Join(lines, "\n"),
+		Tags: compactTags([]string{"ci", inboundTag(status)}),
+	}
+	if isFailureStatus(status) {
+		message.Priority = PriorityHigh
+	}
+	return message, nil
+}
+
+func isFailureStatus(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "failure", "failed", "error", "errored", "timed_out", "broken":
+		return true
+	default:
+		return false
+	}
+}
+
+// inboundTag maps an external name onto the tag alphabet, e.g.
+// "pull_request" -> "pull-request".
+func inboundTag(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(value)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	tag := strings.Trim(b.String(), "-")
+	if len(tag) > MaxTagLength {
+		tag = tag[:MaxTagLength]
+	}
+	return tag
+}
+
+func compactTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(value), "\n")
+	return strings.TrimSpace(line)
+}
+
+func appendNonEmpty(lines []string, value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return lines
+	}
+	return append(lines, value)
+}
@@ -0,0 +1,214 @@
+package fmail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookMatches(t *testing.T) {
+	all := Webhook{Name: "all"}
+	require.True(t, all.Matches(&Message{To: "build"}))
+	require.True(t, all.Matches(&Message{To: "#team"}))
+	require.False(t, all.Matches(&Message{To: "@bob"}))
+
+	filtered := Webhook{Name: "ci", Topics: []string{"build", "task-*", "@alice"}, Tags: []string{"blocker"}}
+	require.True(t, filtered.Matches(&Message{To: "task-42", Tags: []string{"blocker"}}))
+	require.False(t, filtered.Matches(&Message{To: "task-42"}))
+	require.False(t, filtered.Matches(&Message{To: "status", Tags: []string{"blocker"}}))
+	require.True(t, filtered.Matches(&Message{To: "@alice", Tags: []string{"blocker"}}))
+	require.False(t, filtered.Matches(&Message{To: "@alice", Group: "team", Tags: []string{"blocker"}}))
+}
+
+func TestWebhookStoreRoundTrip(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.SaveWebhook(Webhook{Name: "ci", URL: "ftp://example.com"})
+	require.Error(t, err)
+	_, err = store.SaveWebhook(Webhook{Name: "Bad Name", URL: "https://example.com"})
+	require.ErrorIs(t, err, ErrInvalidWebhook)
+
+	saved, err := store.SaveWebhook(Webhook{Name: "CI", URL: "https://example.com/hook", Topics: []string{"Build", "build"}, Tags: []string{"Blocker"}})
+	require.NoError(t, err)
+	require.Equal(t, "ci", saved.Name)
+	require.Equal(t, []string{"build"}, saved.Topics)
+	require.Equal(t, []string{"blocker"}, saved.Tags)
+
+	hooks, err := store.ListWebhooks()
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+
+	require.NoError(t, store.DeleteWebhook("ci"))
+	require.ErrorIs(t, store.DeleteWebhook("ci"), ErrWebhookNotFound)
+}
+
+func TestWebhookDispatcherDeliversMatchingMessages(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []WebhookEvent
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.True(t, VerifyWebhookSignature("s3cret", body, r.Header.Get(WebhookSignatureHeader)))
+		var event WebhookEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SaveWebhook(Webhook{Name: "ci", URL: server.URL, Topics: []string{"build"}, Secret: "s3cret"})
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "alice", To: "build", Time: time.Now().UTC(), Body: "green"})
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&Message{From: "alice", To: "status", Time: time.Now().UTC(), Body: "ignored"})
+	require.NoError(t, err)
+
+	var out, errOut bytes.Buffer
+	dispatcher := &webhookDispatcher{store: store, client: NewWebhookClient("proj"), out: &out, errOut: &errOut}
+	seen := make(map[string]struct{})
+	require.NoError(t, dispatcher.poll(context.Background(), seen, time.Time{}))
+	require.NoError(t, dispatcher.poll(context.Background(), seen, time.Time{}))
+
+	require.Empty(t, errOut.String())
+	require.Len(t, received, 1)
+	require.Equal(t, "message", received[0].Event)
+	require.Equal(t, "proj", received[0].Project)
+	require.Equal(t, "build", received[0].Message.To)
+	require.Equal(t, "green", received[0].Message.Body)
+}
+
+func TestWebhookDispatcherReportsFailures(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SaveWebhook(Webhook{Name: "flaky", URL: server.URL})
+	require.NoError(t, err)
+	id, err := store.SaveMessage(&Message{From: "alice", To: "build", Time: time.Now().UTC(), Body: "red"})
+	require.NoError(t, err)
+
+	var out, errOut bytes.Buffer
+	dispatcher := &webhookDispatcher{store: store, client: NewWebhookClient(""), out: &out, errOut: &errOut, retryDelay: time.Millisecond}
+	require.NoError(t, dispatcher.poll(context.Background(), make(map[string]struct{}), time.Time{}))
+	require.Equal(t, webhookMaxAttempts, attempts)
+	require.Contains(t, errOut.String(), "webhook flaky: message "+id)
+	require.Contains(t, errOut.String(), "502")
+}
+
+func TestConvertInbound(t *testing.T) {
+	run := `{"action":"completed","repository":{"full_name":"o/r"},"workflow_run":{"name":"CI","status":"completed","conclusion":"failure","head_branch":"main","html_url":"https://github.com/o/r/actions/runs/1"}}`
+	message, err := ConvertInbound(InboundFormatAuto, "workflow_run", []byte(run))
+	require.NoError(t, err)
+	require.Equal(t, "o/r: workflow CI failure on main\nhttps://github.com/o/r/actions/runs/1", message.Body)
+	require.Equal(t, []string{"github", "workflow-run", "failure"}, message.Tags)
+	require.Equal(t, PriorityHigh, message.Priority)
+
+	pr := `{"action":"closed","repository":{"full_name":"o/r"},"sender":{"login":"bob"},"pull_request":{"number":7,"title":"Fix auth","html_url":"https://github.com/o/r/pull/7","merged":true}}`
+	message, err = ConvertInbound(InboundFormatAuto, "pull_request", []byte(pr))
+	require.NoError(t, err)
+	require.Equal(t, "o/r#7 merged by bob: Fix auth\nhttps://github.com/o/r/pull/7", message.Body)
+	require.Equal(t, []string{"github", "pull-request", "merged"}, message.Tags)
+
+	ci := `{"job":"unit","status":"passed","branch":"main","commit":"0123456789abcdef","url":"https://ci.example.com/1"}`
+	message, err = ConvertInbound(InboundFormatAuto, "", []byte(ci))
+	require.NoError(t, err)
+	require.Equal(t, "ci: unit passed on main (01234567)\nhttps://ci.example.com/1", message.Body)
+	require.Equal(t, []string{"ci", "passed"}, message.Tags)
+	require.Empty(t, message.Priority)
+
+	message, err = ConvertInbound(InboundFormatAuto, "", []byte(`{"deploy":"done"}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"deploy": "done"}, message.Body)
+
+	message, err = ConvertInbound(InboundFormatRaw, "", []byte("nightly backup ok\n"))
+	require.NoError(t, err)
+	require.Equal(t, "nightly backup ok\n", message.Body)
+
+	_, err = ConvertInbound("xml", "", []byte("x"))
+	require.Error(t, err)
+}
+
+func TestInboundHandlerSendsMessage(t *testing.T) {
+	t.Setenv(EnvProject, "proj-test")
+	root := t.TempDir()
+	runtime := &Runtime{Root: root, Agent: "alice"}
+	opts := inboundOptions{target: "build", format: InboundFormatAuto, from: "github", tags: []string{"ext"}}
+	handler := newInboundHandler(runtime, opts, "s3cret", io.Discard)
+
+	payload := `{"ref":"refs/heads/main","repository":{"full_name":"o/r"},"pusher":{"name":"bob"},"commits":[{},{}],"head_commit":{"message":"Fix login\n\nDetails"}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "push")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", SignWebhookPayload("s3cret", []byte(payload)))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	messages, err := store.ListTopicMessages("build")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, "github", messages[0].From)
+	require.Equal(t, "o/r: bob pushed 2 commit(s) to main\nFix login", messages[0].Body)
+	require.Equal(t, []string{"github", "push", "ext"}, messages[0].Tags)
+}
+
+func TestWebhookReceiveRejectsSenderOverQuota(t *testing.T) {
+	t.Setenv(EnvAgent, "alice")
+	t.Setenv(EnvProject, "proj-test")
+	root := t.TempDir()
+	t.Setenv(EnvRoot, root)
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SetQuota("ci", QuotaLimits{MaxMessages: 1})
+	require.NoError(t, err)
+
+	payload := filepath.Join(t.TempDir(), "payload.txt")
+	require.NoError(t, os.WriteFile(payload, []byte("build finished"), 0o644))
+	receive := func() error {
+		cmd := newRootCmd("test")
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"webhook", "receive", "build", "--from", "ci", "--format", "raw", "--file", payload})
+		return cmd.Execute()
+	}
+
+	require.NoError(t, receive())
+	err = receive()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "2 messages (limit 1)")
+
+	messages, err := store.ListTopicMessages("build")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+}
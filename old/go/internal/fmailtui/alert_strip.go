@@ -0,0 +1,81 @@
+package fmailtui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	tuistate "github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+const alertStripDuration = 8 * time.Second
+
+// alertStripState tracks the most recent rule match shown above the footer.
+type alertStripState struct {
+	item  tuistate.Notification
+	until time.Time
+}
+
+func (a alertStripState) visible(now time.Time) bool {
+	if strings.TrimSpace(a.item.MessageID) == "" {
+		return false
+	}
+	return a.until.IsZero() || now.Before(a.until)
+}
+
+// raiseAlert shows the newest notification in the alert strip.
+func (m *Model) raiseAlert(now time.Time) {
+	if m.notifications == nil {
+		return
+	}
+	items := m.notifications.Notifications()
+	if len(items) == 0 {
+		return
+	}
+	m.alert = alertStripState{item: items[0], until: now.Add(alertStripDuration)}
+}
+
+func (m *Model) dismissAlert() {
+	m.alert = alertStripState{}
+}
+
+func (m *Model) renderAlertStrip(width int, theme Theme, now time.Time) string {
+	if !m.alert.visible(now) || m.activeViewID() == ViewNotify {
+		return ""
+	}
+	item := m.alert.item
+	palette := themePalette(theme)
+
+	label := strings.TrimSpace(item.RuleLabel)
+	if label == "" {
+		label = "ALERT"
+	}
+	parts := []string{"! " + label}
+	route := strings.TrimSpace(item.From)
+	if target := strings.TrimSpace(item.Target); target != "" {
+		route += " -> " + target
+	}
+	if route = strings.TrimSpace(route); route != "" {
+		parts = append(parts, route)
+	}
+	if preview := strings.TrimSpace(item.Preview); preview != "" {
+		parts = append(parts, preview)
+	}
+	if m.notifications != nil {
+		if extra := m.notifications.UnreadCount() - 1; extra > 0 {
+			parts = append(parts, fmt.Sprintf("(+%d unread)", extra))
+		}
+	}
+	parts = append(parts, "ctrl+n: open  ctrl+x: dismiss")
+
+	line := truncateVis(strings.Join(parts, "  "), maxInt(0, width-2))
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(palette.Base.Background)).
+		Background(lipgloss.Color(palette.Priority.High)).
+		Bold(true).
+		Padding(0, 1).
+		Width(maxInt(0, width)).
+		Render(line)
+}
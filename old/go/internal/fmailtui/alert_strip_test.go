@@ -0,0 +1,51 @@
+package fmailtui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+func TestAlertStripShowsRuleMatchAcrossViews(t *testing.T) {
+	model := newTestModel(t, Config{})
+	model = applyUpdate(t, model, tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	model = applyUpdate(t, model, statusIncomingMsg{msg: fmail.Message{
+		ID:       "20260209-120000-0001",
+		From:     "architect",
+		To:       "task",
+		Priority: fmail.PriorityHigh,
+		Body:     "blocker: migrations are failing",
+		Time:     time.Now().UTC(),
+	}})
+	require.Contains(t, model.View(), "! HIGH")
+	require.Contains(t, model.View(), "architect -> task")
+
+	model = applyUpdateWithCmd(t, model, runeKey('2'))
+	require.Equal(t, ViewTopics, model.activeViewID())
+	require.Contains(t, model.View(), "blocker: migrations are failing")
+
+	model = applyUpdate(t, model, tea.KeyMsg{Type: tea.KeyCtrlX})
+	require.NotContains(t, model.View(), "! HIGH")
+}
+
+func TestAlertStripExpiresAndSkipsNonMatches(t *testing.T) {
+	model := newTestModel(t, Config{})
+	model = applyUpdate(t, model, statusIncomingMsg{msg: fmail.Message{
+		ID:       "20260209-120000-0002",
+		From:     "coder",
+		To:       "task",
+		Priority: fmail.PriorityNormal,
+		Body:     "routine update",
+		Time:     time.Now().UTC(),
+	}})
+	require.False(t, model.alert.visible(time.Now().UTC()))
+
+	model.alert = alertStripState{until: time.Now().UTC().Add(-time.Second)}
+	model.alert.item.MessageID = "expired"
+	require.Equal(t, "", model.renderAlertStrip(120, model.theme, time.Now().UTC()))
+}
@@ -47,6 +47,7 @@ const (
 	ViewReplay    ViewID = "replay"
 	ViewBookmarks ViewID = "bookmarks"
 	ViewNotify    ViewID = "notifications"
+	ViewInbox     ViewID = "inbox"
 )
 
 var viewSwitchKeys = map[string]ViewID{
@@ -63,6 +64,7 @@ var viewSwitchKeys = map[string]ViewID{
 	"N": ViewNotify,
 	"D": ViewDashboard,
 	"S": ViewSearch,
+	"i": ViewInbox,
 }
 
 var defaultEnterRoute = map[ViewID]ViewID{
@@ -83,16 +85,22 @@ var dashboardAssignableViews = []ViewID{
 	ViewReplay,
 	ViewBookmarks,
 	ViewNotify,
+	ViewInbox,
 }
 
 type Config struct {
-	ProjectID    string
-	Root         string
-	ForgedAddr   string
-	Agent        string
-	Operator     bool
-	Theme        string
+	ProjectID  string
+	Root       string
+	ForgedAddr string
+	Agent      string
+	Operator   bool
+	Theme      string
+	// ThemeFile points at a YAML/JSON file of custom palettes; it is
+	// reloaded when it changes on disk.
+	ThemeFile    string
 	PollInterval time.Duration
+	// RefreshIntervals overrides the background refresh cadence per view.
+	RefreshIntervals map[ViewID]time.Duration
 }
 
 type ForgedClient interface {
@@ -130,6 +138,7 @@ type Model struct {
 	reconnectAttempts    int
 	lastReconnectAttempt time.Time
 	theme                Theme
+	themeFile            themeFileState
 	pollInterval         time.Duration
 
 	width        int
@@ -138,14 +147,21 @@ type Model struct {
 	toast        string
 	toastUntil   time.Time
 	flashUntil   time.Time
+	alert        alertStripState
 	spinnerFrame int
 	status       statusState
 	statusCh     <-chan fmail.Message
 	statusCancel func()
 
-	compose composeState
-	quick   quickSendState
-	layout  *layout.Manager
+	compose  composeState
+	quick    quickSendState
+	switcher switcherState
+	macro    macroState
+	layout   *layout.Manager
+
+	// sentHistory holds compose history per target when there is no
+	// persisted TUI state.
+	sentHistory map[string][]string
 
 	layoutWindowCmd bool
 	preZenMode      layout.Mode
@@ -206,6 +222,10 @@ func applyTimelineCmd(filterRaw string, windowEnd time.Time, zoom time.Duration)
 }
 
 func NewModel(cfg Config) (*Model, error) {
+	themeFile, err := loadThemeFile(cfg.ThemeFile)
+	if err != nil {
+		return nil, err
+	}
 	normalized, err := cfg.normalize()
 	if err != nil {
 		return nil, err
@@ -262,6 +282,7 @@ func NewModel(cfg Config) (*Model, error) {
 		forgedAddr:   normalized.ForgedAddr,
 		forgedErr:    err,
 		theme:        Theme(normalized.Theme),
+		themeFile:    themeFile,
 		pollInterval: normalized.PollInterval,
 		quick: quickSendState{
 			historyIndex: -1,
@@ -274,7 +295,7 @@ func NewModel(cfg Config) (*Model, error) {
 	// Non-fatal: state can be created later; fall back to in-memory defaults.
 	_ = m.tuiState.Load()
 	if prefTheme := strings.TrimSpace(m.tuiState.Theme()); prefTheme != "" {
-		if _, ok := styles.Themes[prefTheme]; ok {
+		if _, ok := styles.LookupTheme(prefTheme); ok {
 			m.theme = Theme(prefTheme)
 		}
 	}
@@ -285,6 +306,7 @@ func NewModel(cfg Config) (*Model, error) {
 	m.status.notificationsUnread = m.notifications.UnreadCount()
 	m.restoreLayoutPreferences()
 	m.initViews()
+	m.applyRefreshIntervals(normalized.RefreshIntervals)
 	return m, nil
 }
 
@@ -325,6 +347,9 @@ func (m *Model) Init() tea.Cmd {
 		cmds = append(cmds, view.Init())
 	}
 	cmds = append(cmds, m.statusInitCmd())
+	if m.themeFile.path != "" {
+		cmds = append(cmds, themeFileTickCmd())
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -400,6 +425,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.notifications != nil {
 			if actions, ok := m.notifications.ProcessMessage(typed.msg); ok {
 				m.status.notificationsUnread = m.notifications.UnreadCount()
+				m.raiseAlert(time.Now().UTC())
 				if actions.Bell {
 					cmds = append(cmds, bellCmd())
 				}
@@ -419,6 +445,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusProbeMsg:
 		m.status.applyProbe(typed, time.Now().UTC())
 		return m, nil
+	case themeFileTickMsg:
+		m.reloadThemeFileIfChanged()
+		return m, themeFileTickCmd()
 	case flashHeaderMsg:
 		m.flashUntil = typed.until
 		return m, nil
@@ -427,7 +456,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.flashUntil = time.Time{}
 		}
 		return m, nil
+	case macroStepMsg:
+		return m, m.stepMacro(typed)
 	case tea.KeyMsg:
+		if cmd, handled := m.handleMacroKey(typed); handled {
+			return m, cmd
+		}
+		m.recordMacroKey(typed)
 		if cmd, handled := m.handleGlobalKey(typed); handled {
 			return m, cmd
 		}
@@ -447,6 +482,9 @@ func (m *Model) View() string {
 			if m.compose.active {
 				body = m.renderComposeOverlay(m.width, m.height, m.theme)
 			}
+			if m.switcher.active {
+				body = m.renderSwitcherOverlay(m.width, m.height, m.theme)
+			}
 			if m.showHelp {
 				body = m.renderHelpOverlay(m.width, m.height, m.theme)
 			}
@@ -463,6 +501,9 @@ func (m *Model) View() string {
 		if m.compose.active {
 			body = m.renderComposeOverlay(m.width, contentHeight, m.theme)
 		}
+		if m.switcher.active {
+			body = m.renderSwitcherOverlay(m.width, contentHeight, m.theme)
+		}
 		if m.showHelp {
 			body = m.renderHelpOverlay(m.width, contentHeight, m.theme)
 		}
@@ -472,6 +513,9 @@ func (m *Model) View() string {
 			lines = append(lines, m.renderQuickSendBar(m.width, m.theme))
 		}
 		if !m.showHelp {
+			if strip := m.renderAlertStrip(m.width, m.theme, time.Now().UTC()); strip != "" {
+				lines = append(lines, strip)
+			}
 			if toast := m.renderToast(m.width, m.theme); strings.TrimSpace(toast) != "" {
 				lines = append(lines, toast)
 			}
@@ -483,6 +527,16 @@ func (m *Model) View() string {
 }
 
 func (m *Model) handleGlobalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.switcher.active {
+		return m.handleSwitcherKey(msg), true
+	}
+	if msg.String() == "ctrl+k" && !m.compose.active && !m.quick.active {
+		m.showHelp = false
+		m.layoutWindowCmd = false
+		m.openSwitcher()
+		return nil, true
+	}
+
 	if m.activeViewID() == ViewTimeline {
 		if timeline, ok := m.views[ViewTimeline].(*timelineView); ok {
 			if timeline.wantsKey(msg.String()) {
@@ -592,17 +646,16 @@ func (m *Model) handleGlobalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 			return nil, true
 		}
 	case "R":
-		if m.maybeOpenComposeReply(true) {
+		// Thread view keeps R as DM reply; everywhere else it reloads now.
+		if m.activeViewID() == ViewThread && m.maybeOpenComposeReply(true) {
 			return nil, true
 		}
+		return m.forceRefreshActiveView(), true
 	case ":":
 		m.openQuickSendBar()
 		return nil, true
 	case "ctrl+r":
-		if view := m.activeView(); view != nil {
-			return view.Init(), true
-		}
-		return nil, true
+		return m.forceRefreshActiveView(), true
 	case "ctrl+t":
 		m.theme = nextTheme(m.theme)
 		if m.tuiState != nil {
@@ -616,7 +669,13 @@ func (m *Model) handleGlobalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 	case "ctrl+b":
 		return pushViewCmd(ViewBookmarks), true
 	case "ctrl+n":
+		m.dismissAlert()
 		return pushViewCmd(ViewNotify), true
+	case "ctrl+x":
+		if m.alert.visible(time.Now().UTC()) {
+			m.dismissAlert()
+			return nil, true
+		}
 	case "1":
 		return pushViewCmd(ViewDashboard), true
 	case "2":
@@ -1028,13 +1087,16 @@ func (m *Model) cycleDashboardSlot(slot int) {
 	m.toastUntil = time.Now().UTC().Add(2 * time.Second)
 }
 
+// nextTheme cycles through the built-in themes followed by any custom
+// themes loaded from the theme file.
 func nextTheme(current Theme) Theme {
-	switch current {
-	case ThemeDefault:
-		return ThemeHighContrast
-	default:
-		return ThemeDefault
+	names := styles.ThemeNames()
+	for idx, name := range names {
+		if Theme(name) == current {
+			return Theme(names[(idx+1)%len(names)])
+		}
 	}
+	return ThemeDefault
 }
 
 func (m *Model) activeView() viewModel {
@@ -1072,7 +1134,7 @@ func (m *Model) popView() {
 func (m *Model) initViews() {
 	m.views[ViewDashboard] = newDashboardView(m.root, m.projectID, m.provider, m.notifications)
 	m.views[ViewTopics] = newTopicsView(m.root, m.provider, m.tuiState)
-	m.views[ViewThread] = newThreadView(m.root, m.provider, m.tuiState)
+	m.views[ViewThread] = newThreadView(m.root, m.selfAgent, m.provider, m.tuiState)
 	m.views[ViewAgents] = newAgentsView(m.root, m.provider)
 	m.views[ViewOperator] = newOperatorView(m.root, m.projectID, m.selfAgent, m.store, m.provider, m.tuiState)
 	m.views[ViewSearch] = newSearchView(m.root, m.selfAgent, m.provider, m.tuiState)
@@ -1084,6 +1146,7 @@ func (m *Model) initViews() {
 	m.views[ViewReplay] = newReplayView(m.root, m.selfAgent, m.provider, m.tuiState)
 	m.views[ViewBookmarks] = newBookmarksView(m.root, m.store, m.provider, m.tuiState)
 	m.views[ViewNotify] = newNotificationsView(m.selfAgent, m.provider, m.notifications)
+	m.views[ViewInbox] = newInboxView(m.selfAgent, m.provider, m.tuiState)
 }
 
 func (c Config) normalize() (Config, error) {
@@ -1097,9 +1160,7 @@ func (c Config) normalize() (Config, error) {
 	if strings.TrimSpace(c.Theme) == "" {
 		c.Theme = string(ThemeDefault)
 	}
-	switch Theme(c.Theme) {
-	case ThemeDefault, ThemeHighContrast:
-	default:
+	if _, ok := styles.LookupTheme(c.Theme); !ok {
 		return Config{}, fmt.Errorf("invalid theme %q", c.Theme)
 	}
 	return c, nil
@@ -0,0 +1,110 @@
+package fmailtui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+type timelineAttachmentMsg struct {
+	status string
+	err    error
+}
+
+// openAttachmentPath hands a file to the desktop opener; tests replace it.
+var openAttachmentPath = func(path string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+	cmd := exec.Command(opener, path)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// attachmentBadge is the row indicator for messages with attachments.
+func attachmentBadge(attachments []fmail.Attachment) string {
+	switch len(attachments) {
+	case 0:
+		return ""
+	case 1:
+		return "📎"
+	default:
+		return fmt.Sprintf("📎%d", len(attachments))
+	}
+}
+
+func attachmentLines(attachments []fmail.Attachment) []string {
+	if len(attachments) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Attachments (%d):", len(attachments))}
+	for _, attachment := range attachments {
+		line := fmt.Sprintf("  📎 %s  %s", attachment.Name, fmail.FormatAttachmentSize(attachment.Size))
+		if attachment.MediaType != "" {
+			line += "  " + attachment.MediaType
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// attachmentExportDir is where the save action writes attachments, next to
+// replay exports.
+func attachmentExportDir(root string) string {
+	return filepath.Join(root, ".fmail", "exports", "attachments")
+}
+
+// exportAttachmentsCmd saves the selected message's attachments. With open
+// set they go to a temp directory and are handed to the system opener.
+func (v *timelineView) exportAttachmentsCmd(open bool) tea.Cmd {
+	msg, ok := v.selectedMessage()
+	if !ok || len(msg.Attachments) == 0 {
+		v.attachmentStatus = "no attachments"
+		return nil
+	}
+	root := v.root
+	attachments := append([]fmail.Attachment(nil), msg.Attachments...)
+	return func() tea.Msg {
+		if strings.TrimSpace(root) == "" {
+			return timelineAttachmentMsg{err: fmt.Errorf("root not set")}
+		}
+		store, err := fmail.NewStore(root)
+		if err != nil {
+			return timelineAttachmentMsg{err: err}
+		}
+		dir := attachmentExportDir(root)
+		if open {
+			dir = filepath.Join(os.TempDir(), "fmail-attachments")
+		}
+		paths := make([]string, 0, len(attachments))
+		for _, attachment := range attachments {
+			path, err := store.ExportAttachment(attachment, dir)
+			if err != nil {
+				return timelineAttachmentMsg{err: err}
+			}
+			if open {
+				if err := openAttachmentPath(path); err != nil {
+					return timelineAttachmentMsg{err: fmt.Errorf("open %s: %w", attachment.Name, err)}
+				}
+			}
+			paths = append(paths, path)
+		}
+		if open {
+			return timelineAttachmentMsg{status: fmt.Sprintf("opened %d attachment(s)", len(paths))}
+		}
+		if len(paths) == 1 {
+			return timelineAttachmentMsg{status: "saved: " + paths[0]}
+		}
+		return timelineAttachmentMsg{status: fmt.Sprintf("saved %d attachments to %s", len(paths), dir)}
+	}
+}
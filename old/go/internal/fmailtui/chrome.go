@@ -13,7 +13,7 @@ import (
 )
 
 func (m *Model) renderHeader() string {
-	palette, ok := styles.Themes[string(m.theme)]
+	palette, ok := styles.LookupTheme(string(m.theme))
 	if !ok {
 		palette = styles.DefaultTheme
 	}
@@ -1,6 +1,8 @@
 package fmailtui
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -10,6 +12,7 @@ func Execute(version string) error {
 
 func newRootCmd(version string) *cobra.Command {
 	cfg := Config{}
+	var refreshSpecs []string
 	cmd := &cobra.Command{
 		Use:           "fmail-tui",
 		Short:         "fmail terminal UI",
@@ -18,6 +21,11 @@ func newRootCmd(version string) *cobra.Command {
 		SilenceErrors: true,
 		Version:       version,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			intervals, err := parseRefreshIntervals(refreshSpecs)
+			if err != nil {
+				return err
+			}
+			cfg.RefreshIntervals = intervals
 			return Run(cfg)
 		},
 	}
@@ -26,7 +34,9 @@ func newRootCmd(version string) *cobra.Command {
 	cmd.Flags().StringVar(&cfg.ForgedAddr, "forged-addr", "", "forged endpoint (socket path or host:port)")
 	cmd.Flags().StringVar(&cfg.Agent, "agent", "", "sender identity for compose/quick-send (defaults to FMAIL_AGENT)")
 	cmd.Flags().BoolVarP(&cfg.Operator, "operator", "o", false, "start in operator console view")
-	cmd.Flags().StringVar(&cfg.Theme, "theme", string(ThemeDefault), "theme: default|high-contrast")
+	cmd.Flags().StringVar(&cfg.Theme, "theme", string(ThemeDefault), "theme: default|high-contrast or a name from --theme-file")
+	cmd.Flags().StringVar(&cfg.ThemeFile, "theme-file", os.Getenv("FMAIL_TUI_THEME_FILE"), "YAML/JSON file of custom palettes, reloaded on change (env FMAIL_TUI_THEME_FILE)")
 	cmd.Flags().DurationVar(&cfg.PollInterval, "poll-interval", defaultPollInterval, "poll interval for background refresh")
+	cmd.Flags().StringArrayVar(&refreshSpecs, "refresh-interval", nil, "per-view refresh interval, e.g. thread=5s (topics, thread, timeline, operator; repeatable)")
 	return cmd
 }
@@ -35,7 +35,7 @@ type composeState struct {
 	tags        string
 	replyTo     string
 	parentLine  string
-	body        string
+	body        composeEditor
 	sending     bool
 	err         string
 	savePrompt  bool
@@ -46,6 +46,18 @@ type composeState struct {
 	toCompletionIndex   int
 	tagCompletionPrefix string
 	tagCompletionIndex  int
+
+	// history holds bodies previously sent to historyTarget, oldest first.
+	// historyIndex is -1 while editing a fresh message; historyStash keeps
+	// that message while browsing.
+	history       []string
+	historyTarget string
+	historyIndex  int
+	historyStash  string
+
+	// templates is non-nil while the ctrl+t picker is open.
+	templates     []fmail.Template
+	templateIndex int
 }
 
 type quickSendState struct {
@@ -125,7 +137,7 @@ func (m *Model) handleComposeOverlayKey(msg tea.KeyMsg) tea.Cmd {
 			m.compose.priority = m.compose.draftCached.Priority
 			m.compose.tags = m.compose.draftCached.Tags
 			m.compose.replyTo = m.compose.draftCached.ReplyTo
-			m.compose.body = m.compose.draftCached.Body
+			m.compose.body.SetValue(m.compose.draftCached.Body)
 			m.compose.restoreAsk = false
 			return nil
 		case "n", "esc", "backspace":
@@ -161,7 +173,15 @@ func (m *Model) handleComposeOverlayKey(msg tea.KeyMsg) tea.Cmd {
 		return nil
 	}
 
+	if m.compose.templates != nil {
+		m.handleComposeTemplateKey(msg)
+		return nil
+	}
+
 	switch msg.String() {
+	case "ctrl+t":
+		m.openComposeTemplatePicker()
+		return nil
 	case "tab":
 		if m.compose.focus == composeFieldTo {
 			before := m.compose.to
@@ -183,7 +203,7 @@ func (m *Model) handleComposeOverlayKey(msg tea.KeyMsg) tea.Cmd {
 		m.compose.focus = composeField((int(m.compose.focus) + 3) % 4)
 		return nil
 	case "esc":
-		if strings.TrimSpace(m.compose.body) != "" {
+		if !m.compose.body.Empty() {
 			m.compose.savePrompt = true
 			return nil
 		}
@@ -197,36 +217,53 @@ func (m *Model) handleComposeOverlayKey(msg tea.KeyMsg) tea.Cmd {
 			m.compose.focus = composeField((int(m.compose.focus) + 1) % 4)
 			return nil
 		case composeFieldBody:
-			m.compose.body += "\n"
+			m.compose.body.InsertNewline()
 			return nil
 		}
+	case "alt+enter":
+		if m.compose.focus == composeFieldBody {
+			m.compose.body.InsertNewline()
+		}
+		return nil
 	case "up":
-		if m.compose.focus == composeFieldPriority {
+		switch m.compose.focus {
+		case composeFieldPriority:
 			m.cyclePriority(-1)
+		case composeFieldBody:
+			if m.compose.body.OnFirstLine() && m.composeHistoryStep(-1) {
+				return nil
+			}
+			m.compose.body.MoveUp()
 		}
 		return nil
 	case "down":
-		if m.compose.focus == composeFieldPriority {
+		switch m.compose.focus {
+		case composeFieldPriority:
 			m.cyclePriority(1)
+		case composeFieldBody:
+			if m.compose.body.OnLastLine() && m.composeHistoryStep(1) {
+				return nil
+			}
+			m.compose.body.MoveDown()
 		}
 		return nil
-	case "backspace", "delete":
-		m.composeDeleteRune()
-		return nil
-	case "ctrl+h":
-		m.composeDeleteRune()
-		return nil
 	case "ctrl+j":
 		return m.composeSendCmd(sendSourceCompose)
 	}
 
-	if msg.String() == "alt+enter" {
-		m.compose.body += "\n"
+	if m.compose.focus == composeFieldBody {
+		m.compose.body.HandleKey(msg)
+		return nil
+	}
+
+	switch msg.String() {
+	case "backspace", "delete", "ctrl+h":
+		m.composeDeleteRune()
 		return nil
 	}
 
 	switch msg.Type {
-	case tea.KeyRunes:
+	case tea.KeyRunes, tea.KeySpace:
 		if len(msg.Runes) == 0 {
 			return nil
 		}
@@ -238,8 +275,6 @@ func (m *Model) handleComposeOverlayKey(msg tea.KeyMsg) tea.Cmd {
 			m.compose.priority += strings.ToLower(r)
 		case composeFieldTags:
 			m.compose.tags += strings.ToLower(r)
-		case composeFieldBody:
-			m.compose.body += r
 		}
 		m.resetComposeCompletionState()
 		return nil
@@ -326,6 +361,44 @@ func (m *Model) maybeOpenComposeReply(dmDirect bool) bool {
 	return true
 }
 
+func (m *Model) openComposeTemplatePicker() {
+	if m.store == nil {
+		return
+	}
+	templates, err := m.store.ListTemplates()
+	if err != nil {
+		m.compose.err = err.Error()
+		return
+	}
+	if len(templates) == 0 {
+		m.setToast("No templates (fmail template add)")
+		return
+	}
+	m.compose.templates = templates
+	m.compose.templateIndex = 0
+}
+
+func (m *Model) handleComposeTemplateKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "up", "k":
+		if m.compose.templateIndex > 0 {
+			m.compose.templateIndex--
+		}
+	case "down", "j":
+		if m.compose.templateIndex < len(m.compose.templates)-1 {
+			m.compose.templateIndex++
+		}
+	case "enter":
+		tmpl := m.compose.templates[m.compose.templateIndex]
+		rendered, _ := fmail.RenderTemplate(tmpl.Body, fmail.TemplateVars(m.selfAgent, m.compose.to))
+		m.compose.focus = composeFieldBody
+		m.compose.body.Insert(strings.TrimRight(rendered, "\n"))
+		m.compose.templates = nil
+	case "esc", "ctrl+t":
+		m.compose.templates = nil
+	}
+}
+
 func (m *Model) openComposeOverlay(target string, seed composeReplySeed) {
 	m.quick.active = false
 	m.quick.err = ""
@@ -337,13 +410,13 @@ func (m *Model) openComposeOverlay(target string, seed composeReplySeed) {
 		tags:               "",
 		replyTo:            strings.TrimSpace(seed.ReplyTo),
 		parentLine:         strings.TrimSpace(seed.ParentLine),
-		body:               "",
 		sending:            false,
 		err:                "",
 		savePrompt:         false,
 		restoreAsk:         false,
 		toCompletionIndex:  -1,
 		tagCompletionIndex: -1,
+		historyIndex:       -1,
 	}
 
 	if strings.TrimSpace(m.compose.to) == "" {
@@ -489,14 +562,14 @@ func (m *Model) composeSendRequest(source composeSendSource) (data.SendRequest,
 	if strings.TrimSpace(m.compose.to) == "" {
 		return data.SendRequest{}, fmt.Errorf("missing target")
 	}
-	if strings.TrimSpace(m.compose.body) == "" {
+	if m.compose.body.Empty() {
 		return data.SendRequest{}, fmt.Errorf("message body is empty")
 	}
 
 	return data.SendRequest{
 		From:     m.selfAgent,
 		To:       strings.TrimSpace(m.compose.to),
-		Body:     strings.TrimSpace(m.compose.body),
+		Body:     strings.TrimSpace(m.compose.body.Value()),
 		ReplyTo:  strings.TrimSpace(m.compose.replyTo),
 		Priority: normalizePriorityInput(m.compose.priority),
 		Tags:     parseTagCSV(m.compose.tags),
@@ -511,6 +584,7 @@ func (m *Model) handleComposeSendResult(msg composeSendResultMsg) tea.Cmd {
 			return nil
 		}
 		m.persistDraft(false)
+		m.recordComposeHistory(msg.req)
 		m.closeComposeOverlay()
 		m.setToast("Sent ✓")
 	} else {
@@ -547,6 +621,81 @@ func (m *Model) recordQuickHistory(req data.SendRequest) {
 	}
 }
 
+// composeHistoryFor returns bodies previously sent to target, oldest first.
+// History is persisted with the TUI state when available.
+func (m *Model) composeHistoryFor(target string) []string {
+	target = strings.TrimSpace(target)
+	if m.tuiState != nil {
+		return m.tuiState.SentHistory(target)
+	}
+	return append([]string(nil), m.sentHistory[target]...)
+}
+
+func (m *Model) recordComposeHistory(req data.SendRequest) {
+	target := strings.TrimSpace(req.To)
+	body := strings.TrimSpace(req.Body)
+	if target == "" || body == "" {
+		return
+	}
+	if m.tuiState != nil {
+		m.tuiState.AppendSentHistory(target, body)
+		m.tuiState.SaveSoon()
+		return
+	}
+	history := m.sentHistory[target]
+	if len(history) > 0 && history[len(history)-1] == body {
+		return
+	}
+	history = append(history, body)
+	if len(history) > quickHistoryLimit {
+		history = history[len(history)-quickHistoryLimit:]
+	}
+	if m.sentHistory == nil {
+		m.sentHistory = make(map[string][]string)
+	}
+	m.sentHistory[target] = history
+}
+
+// composeHistoryStep browses the current target's sent history: delta -1
+// goes to older messages, +1 back toward the message being written. It
+// reports whether the body was replaced.
+func (m *Model) composeHistoryStep(delta int) bool {
+	c := &m.compose
+	target := strings.TrimSpace(c.to)
+	if c.historyTarget != target || c.history == nil {
+		c.history = m.composeHistoryFor(target)
+		c.historyTarget = target
+		c.historyIndex = -1
+	}
+	if len(c.history) == 0 {
+		return false
+	}
+
+	if c.historyIndex < 0 {
+		if delta > 0 {
+			return false
+		}
+		c.historyStash = c.body.Value()
+		c.historyIndex = len(c.history) - 1
+		c.body.SetValue(c.history[c.historyIndex])
+		return true
+	}
+
+	next := c.historyIndex + delta
+	switch {
+	case next < 0:
+		return true
+	case next >= len(c.history):
+		c.historyIndex = -1
+		c.body.SetValue(c.historyStash)
+		c.historyStash = ""
+	default:
+		c.historyIndex = next
+		c.body.SetValue(c.history[next])
+	}
+	return true
+}
+
 func (m *Model) quickHistoryStep(delta int) {
 	if len(m.quick.history) == 0 {
 		return
@@ -584,10 +733,6 @@ func (m *Model) renderComposeOverlay(width, height int, theme Theme) string {
 	to := c.to
 	priority := normalizePriorityInput(c.priority)
 	tags := c.tags
-	body := c.body
-	if body == "" {
-		body = ""
-	}
 
 	cursor := "_"
 	if c.sending {
@@ -603,8 +748,9 @@ func (m *Model) renderComposeOverlay(width, height int, theme Theme) string {
 	if c.focus == composeFieldTags && !c.sending {
 		tags += cursor
 	}
+	bodyCursor := ""
 	if c.focus == composeFieldBody && !c.sending {
-		body += cursor
+		bodyCursor = cursor
 	}
 
 	head := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(palette.Chrome.Breadcrumb)).Render("Compose")
@@ -612,7 +758,13 @@ func (m *Model) renderComposeOverlay(width, height int, theme Theme) string {
 		head += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render("reply "+shortID(c.replyTo))
 	}
 
-	status := "[Ctrl+Enter: Send] [Esc: Close] [Tab: Next]"
+	status := "[Ctrl+Enter: Send] [Enter: Newline] [↑: History] [Ctrl+T: Template] [Esc: Close] [Tab: Next]"
+	if c.templates != nil {
+		status = "Template  [↑/↓: Select] [Enter: Insert] [Esc: Cancel]"
+	}
+	if c.historyIndex >= 0 {
+		status = fmt.Sprintf("History %d/%d  [↑/↓: Browse] [Ctrl+Enter: Send]", c.historyIndex+1, len(c.history))
+	}
 	if c.sending {
 		status = "Sending... " + spinnerFrame(m.spinnerFrame)
 	}
@@ -643,20 +795,21 @@ func (m *Model) renderComposeOverlay(width, height int, theme Theme) string {
 		lines = append(lines, replyLine)
 	}
 	lines = append(lines, "", "Body:")
+	if c.templates != nil {
+		lines[len(lines)-1] = "Templates:"
+		for i, tmpl := range c.templates {
+			marker := "  "
+			if i == c.templateIndex {
+				marker = "> "
+			}
+			lines = append(lines, marker+tmpl.Name+"  "+lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render(truncate(strings.Join(strings.Fields(tmpl.Body), " "), 50)))
+		}
+		lines = append(lines, "", "Body:")
+	}
 
-	bodyLines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
 	maxBody := maxInt(3, panelHeight-len(lines)-4)
-	if len(bodyLines) > maxBody {
-		bodyLines = bodyLines[len(bodyLines)-maxBody:]
-	}
-	for _, line := range bodyLines {
-		if strings.TrimSpace(line) == "" {
-			lines = append(lines, "  ")
-			continue
-		}
-		for _, wrapped := range wrapLines(line, maxInt(8, panelWidth-6)) {
-			lines = append(lines, "  "+wrapped)
-		}
+	for _, line := range c.body.View(maxInt(8, panelWidth-7), maxBody, bodyCursor) {
+		lines = append(lines, "  "+line)
 	}
 
 	lines = append(lines, "", status)
@@ -984,11 +1137,7 @@ func (m *Model) composeDeleteRune() {
 		runes := []rune(m.compose.tags)
 		m.compose.tags = string(runes[:len(runes)-1])
 	case composeFieldBody:
-		if len(m.compose.body) == 0 {
-			return
-		}
-		runes := []rune(m.compose.body)
-		m.compose.body = string(runes[:len(runes)-1])
+		m.compose.body.DeleteBackward()
 	}
 	m.resetComposeCompletionState()
 }
@@ -1024,7 +1173,7 @@ func (m *Model) persistDraft(save bool) {
 		Priority:  normalizePriorityInput(m.compose.priority),
 		Tags:      strings.TrimSpace(m.compose.tags),
 		ReplyTo:   strings.TrimSpace(m.compose.replyTo),
-		Body:      strings.TrimSpace(m.compose.body),
+		Body:      strings.TrimSpace(m.compose.body.Value()),
 		UpdatedAt: time.Now().UTC(),
 	})
 	m.tuiState.SaveSoon()
@@ -0,0 +1,352 @@
+package fmailtui
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// composeEditor is the multi-line text area behind the compose body. Text is
+// kept as lines of runes with a row/col cursor; col may equal the line
+// length (cursor after the last rune).
+type composeEditor struct {
+	lines [][]rune
+	row   int
+	col   int
+
+	// goalCol keeps the column across up/down moves through short lines.
+	goalCol int
+}
+
+func (e *composeEditor) ensure() {
+	if len(e.lines) == 0 {
+		e.lines = [][]rune{nil}
+		e.row, e.col, e.goalCol = 0, 0, 0
+	}
+}
+
+// Value returns the editor text with lines joined by "\n".
+func (e *composeEditor) Value() string {
+	if len(e.lines) == 0 {
+		return ""
+	}
+	parts := make([]string, len(e.lines))
+	for i, line := range e.lines {
+		parts[i] = string(line)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// SetValue replaces the text and moves the cursor to the end.
+func (e *composeEditor) SetValue(text string) {
+	e.lines = nil
+	e.ensure()
+	e.Insert(text)
+}
+
+// Empty reports whether the editor holds only whitespace.
+func (e *composeEditor) Empty() bool {
+	return strings.TrimSpace(e.Value()) == ""
+}
+
+// OnFirstLine and OnLastLine let callers route up/down to history at the
+// edges of the text.
+func (e *composeEditor) OnFirstLine() bool { return e.row == 0 }
+
+func (e *composeEditor) OnLastLine() bool { return e.row >= len(e.lines)-1 }
+
+// Insert types text at the cursor. Pasted CRLF and CR line endings become
+// newlines; other control characters except tab are dropped.
+func (e *composeEditor) Insert(text string) {
+	e.ensure()
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			e.InsertNewline()
+		case r != '\t' && unicode.IsControl(r):
+		default:
+			line := e.lines[e.row]
+			next := make([]rune, 0, len(line)+1)
+			next = append(next, line[:e.col]...)
+			next = append(next, r)
+			next = append(next, line[e.col:]...)
+			e.lines[e.row] = next
+			e.col++
+		}
+	}
+	e.goalCol = e.col
+}
+
+// InsertNewline splits the current line at the cursor.
+func (e *composeEditor) InsertNewline() {
+	e.ensure()
+	line := e.lines[e.row]
+	head := append([]rune(nil), line[:e.col]...)
+	tail := append([]rune(nil), line[e.col:]...)
+	e.lines[e.row] = head
+	e.lines = append(e.lines, nil)
+	copy(e.lines[e.row+2:], e.lines[e.row+1:])
+	e.lines[e.row+1] = tail
+	e.row++
+	e.col = 0
+	e.goalCol = 0
+}
+
+// DeleteBackward removes the rune before the cursor, joining lines at a
+// line start.
+func (e *composeEditor) DeleteBackward() {
+	e.ensure()
+	switch {
+	case e.col > 0:
+		line := e.lines[e.row]
+		e.lines[e.row] = append(line[:e.col-1:e.col-1], line[e.col:]...)
+		e.col--
+	case e.row > 0:
+		prev := e.lines[e.row-1]
+		e.col = len(prev)
+		e.lines[e.row-1] = append(prev[:len(prev):len(prev)], e.lines[e.row]...)
+		e.lines = append(e.lines[:e.row], e.lines[e.row+1:]...)
+		e.row--
+	}
+	e.goalCol = e.col
+}
+
+// DeleteForward removes the rune under the cursor, joining the next line at
+// a line end.
+func (e *composeEditor) DeleteForward() {
+	e.ensure()
+	line := e.lines[e.row]
+	switch {
+	case e.col < len(line):
+		e.lines[e.row] = append(line[:e.col:e.col], line[e.col+1:]...)
+	case e.row < len(e.lines)-1:
+		e.lines[e.row] = append(line[:len(line):len(line)], e.lines[e.row+1]...)
+		e.lines = append(e.lines[:e.row+1], e.lines[e.row+2:]...)
+	}
+	e.goalCol = e.col
+}
+
+// DeleteWordBackward removes the word before the cursor (ctrl+w).
+func (e *composeEditor) DeleteWordBackward() {
+	e.ensure()
+	if e.col == 0 {
+		e.DeleteBackward()
+		return
+	}
+	start := wordStart(e.lines[e.row], e.col)
+	line := e.lines[e.row]
+	e.lines[e.row] = append(line[:start:start], line[e.col:]...)
+	e.col = start
+	e.goalCol = e.col
+}
+
+// DeleteToLineStart removes text before the cursor on the current line.
+func (e *composeEditor) DeleteToLineStart() {
+	e.ensure()
+	e.lines[e.row] = append([]rune(nil), e.lines[e.row][e.col:]...)
+	e.col = 0
+	e.goalCol = 0
+}
+
+// DeleteToLineEnd removes text after the cursor on the current line.
+func (e *composeEditor) DeleteToLineEnd() {
+	e.ensure()
+	e.lines[e.row] = e.lines[e.row][:e.col:e.col]
+	e.goalCol = e.col
+}
+
+// MoveLeft and MoveRight step one rune, wrapping across lines.
+func (e *composeEditor) MoveLeft() {
+	e.ensure()
+	switch {
+	case e.col > 0:
+		e.col--
+	case e.row > 0:
+		e.row--
+		e.col = len(e.lines[e.row])
+	}
+	e.goalCol = e.col
+}
+
+func (e *composeEditor) MoveRight() {
+	e.ensure()
+	switch {
+	case e.col < len(e.lines[e.row]):
+		e.col++
+	case e.row < len(e.lines)-1:
+		e.row++
+		e.col = 0
+	}
+	e.goalCol = e.col
+}
+
+// MoveUp and MoveDown change line, keeping the goal column where possible.
+func (e *composeEditor) MoveUp() {
+	e.ensure()
+	if e.row == 0 {
+		e.col, e.goalCol = 0, 0
+		return
+	}
+	e.row--
+	e.col = minInt(e.goalCol, len(e.lines[e.row]))
+}
+
+func (e *composeEditor) MoveDown() {
+	e.ensure()
+	if e.row >= len(e.lines)-1 {
+		e.col = len(e.lines[e.row])
+		e.goalCol = e.col
+		return
+	}
+	e.row++
+	e.col = minInt(e.goalCol, len(e.lines[e.row]))
+}
+
+// MoveWordLeft and MoveWordRight jump to the previous or next word
+// boundary on the current line.
+func (e *composeEditor) MoveWordLeft() {
+	e.ensure()
+	if e.col == 0 {
+		e.MoveLeft()
+		return
+	}
+	e.col = wordStart(e.lines[e.row], e.col)
+	e.goalCol = e.col
+}
+
+func (e *composeEditor) MoveWordRight() {
+	e.ensure()
+	line := e.lines[e.row]
+	if e.col >= len(line) {
+		e.MoveRight()
+		return
+	}
+	i := e.col
+	for i < len(line) && unicode.IsSpace(line[i]) {
+		i++
+	}
+	for i < len(line) && !unicode.IsSpace(line[i]) {
+		i++
+	}
+	e.col = i
+	e.goalCol = e.col
+}
+
+// MoveLineStart and MoveLineEnd jump within the current line.
+func (e *composeEditor) MoveLineStart() {
+	e.ensure()
+	e.col, e.goalCol = 0, 0
+}
+
+func (e *composeEditor) MoveLineEnd() {
+	e.ensure()
+	e.col = len(e.lines[e.row])
+	e.goalCol = e.col
+}
+
+// HandleKey applies an editing key and reports whether it was consumed.
+// Enter is left to the caller so it can decide between newline and send.
+func (e *composeEditor) HandleKey(msg tea.KeyMsg) bool {
+	// Bracketed paste arrives as a single KeyRunes message, newlines
+	// included.
+	if msg.Type == tea.KeyRunes && (!msg.Alt || msg.Paste) {
+		e.Insert(string(msg.Runes))
+		return true
+	}
+	if msg.Type == tea.KeySpace {
+		e.Insert(" ")
+		return true
+	}
+
+	switch msg.String() {
+	case "left", "ctrl+b":
+		e.MoveLeft()
+	case "right", "ctrl+f":
+		e.MoveRight()
+	case "up":
+		e.MoveUp()
+	case "down":
+		e.MoveDown()
+	case "alt+left", "ctrl+left", "alt+b":
+		e.MoveWordLeft()
+	case "alt+right", "ctrl+right", "alt+f":
+		e.MoveWordRight()
+	case "home", "ctrl+a":
+		e.MoveLineStart()
+	case "end", "ctrl+e":
+		e.MoveLineEnd()
+	case "backspace", "ctrl+h":
+		e.DeleteBackward()
+	case "delete", "ctrl+d":
+		e.DeleteForward()
+	case "ctrl+w", "alt+backspace":
+		e.DeleteWordBackward()
+	case "ctrl+u":
+		e.DeleteToLineStart()
+	case "ctrl+k":
+		e.DeleteToLineEnd()
+	default:
+		return false
+	}
+	return true
+}
+
+// View renders the text with cursor inserted at the cursor position, wrapped
+// to width and scrolled so the cursor stays within height lines.
+func (e *composeEditor) View(width, height int, cursor string) []string {
+	e.ensure()
+	width = maxInt(1, width)
+	out := make([]string, 0, len(e.lines))
+	cursorLine := 0
+	for row, line := range e.lines {
+		col := -1
+		if row == e.row && cursor != "" {
+			col = e.col
+		}
+		segments := wrapRunes(line, width)
+		for i, seg := range segments {
+			start := i * width
+			rendered := string(seg)
+			last := i == len(segments)-1
+			if col >= start && (col < start+len(seg) || (last && col == start+len(seg))) {
+				at := col - start
+				rendered = string(seg[:at]) + cursor + string(seg[at:])
+				cursorLine = len(out)
+			}
+			out = append(out, rendered)
+		}
+	}
+	if height > 0 && len(out) > height {
+		start := minInt(maxInt(0, cursorLine-height+1), len(out)-height)
+		out = out[start : start+height]
+	}
+	return out
+}
+
+// wrapRunes hard-wraps line into width-sized segments; an empty line yields
+// one empty segment.
+func wrapRunes(line []rune, width int) [][]rune {
+	if len(line) == 0 {
+		return [][]rune{nil}
+	}
+	out := make([][]rune, 0, len(line)/width+1)
+	for len(line) > width {
+		out = append(out, line[:width])
+		line = line[width:]
+	}
+	return append(out, line)
+}
+
+func wordStart(line []rune, col int) int {
+	i := col
+	for i > 0 && unicode.IsSpace(line[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(line[i-1]) {
+		i--
+	}
+	return i
+}
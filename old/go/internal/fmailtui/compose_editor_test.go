@@ -0,0 +1,123 @@
+package fmailtui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/data"
+)
+
+func TestComposeEditorEditsAtCursor(t *testing.T) {
+	var e composeEditor
+	e.SetValue("hello world")
+	e.MoveWordLeft()
+	e.Insert("big ")
+	require.Equal(t, "hello big world", e.Value())
+
+	e.MoveLineEnd()
+	e.InsertNewline()
+	e.Insert("second")
+	e.MoveUp()
+	require.Equal(t, 0, e.row)
+	require.Equal(t, 6, e.col)
+
+	e.MoveLineEnd()
+	e.DeleteForward()
+	require.Equal(t, "hello big worldsecond", e.Value())
+
+	e.DeleteWordBackward()
+	require.Equal(t, "hello big second", e.Value())
+
+	e.MoveLineStart()
+	e.DeleteBackward()
+	require.Equal(t, "hello big second", e.Value())
+	e.MoveRight()
+	e.DeleteToLineStart()
+	require.Equal(t, "ello big second", e.Value())
+	e.DeleteToLineEnd()
+	require.Equal(t, "", e.Value())
+	require.True(t, e.Empty())
+}
+
+func TestComposeEditorPasteAndKeys(t *testing.T) {
+	var e composeEditor
+	require.True(t, e.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("line one\r\nline two\rthree"), Paste: true}))
+	require.Equal(t, "line one\nline two\nthree", e.Value())
+	require.Equal(t, 2, e.row)
+
+	require.True(t, e.HandleKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}))
+	require.True(t, e.HandleKey(tea.KeyMsg{Type: tea.KeyUp}))
+	require.True(t, e.HandleKey(tea.KeyMsg{Type: tea.KeyBackspace}))
+	require.Equal(t, "line one\nline wo\nthree ", e.Value())
+	require.False(t, e.HandleKey(tea.KeyMsg{Type: tea.KeyEnter}))
+}
+
+func TestComposeEditorViewWrapsAndScrollsToCursor(t *testing.T) {
+	var e composeEditor
+	e.SetValue("abcdefgh\nxy")
+	require.Equal(t, []string{"abcd", "efgh", "xy_"}, e.View(4, 0, "_"))
+	require.Equal(t, []string{"efgh", "xy_"}, e.View(4, 2, "_"))
+
+	e.MoveUp()
+	e.MoveLineStart()
+	require.Equal(t, []string{"_abcd", "efgh"}, e.View(4, 2, "_"))
+}
+
+func TestComposeHistoryPerTarget(t *testing.T) {
+	m := &Model{selfAgent: "viewer"}
+	m.recordComposeHistory(data.SendRequest{To: "task", Body: "first"})
+	m.recordComposeHistory(data.SendRequest{To: "task", Body: "second\nline"})
+	m.recordComposeHistory(data.SendRequest{To: "@bob", Body: "dm"})
+
+	m.openComposeOverlay("task", composeReplySeed{})
+	m.compose.body.SetValue("draft")
+
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	m.handleComposeOverlayKey(up)
+	require.Equal(t, "second\nline", m.compose.body.Value())
+
+	// The cursor lands on the last line, so up first moves within the text.
+	m.handleComposeOverlayKey(up)
+	require.Equal(t, "second\nline", m.compose.body.Value())
+	m.handleComposeOverlayKey(up)
+	require.Equal(t, "first", m.compose.body.Value())
+	m.handleComposeOverlayKey(up)
+	require.Equal(t, "first", m.compose.body.Value())
+
+	m.handleComposeOverlayKey(down)
+	require.Equal(t, "second\nline", m.compose.body.Value())
+	m.handleComposeOverlayKey(down)
+	m.handleComposeOverlayKey(down)
+	require.Equal(t, "draft", m.compose.body.Value())
+	require.Equal(t, -1, m.compose.historyIndex)
+}
+
+func TestComposeTemplatePickerInsertsRenderedBody(t *testing.T) {
+	store, err := fmail.NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SaveTemplate("ack", "ack", false)
+	require.NoError(t, err)
+	_, err = store.SaveTemplate("handoff", "{{agent}} -> {{to}}: {{task}}\n", false)
+	require.NoError(t, err)
+
+	m := &Model{selfAgent: "viewer", store: store}
+	m.openComposeOverlay("@bob", composeReplySeed{})
+	m.compose.body.SetValue("note: ")
+
+	m.handleComposeOverlayKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	require.Len(t, m.compose.templates, 2)
+	m.handleComposeOverlayKey(tea.KeyMsg{Type: tea.KeyDown})
+	require.Equal(t, 1, m.compose.templateIndex)
+	m.handleComposeOverlayKey(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Nil(t, m.compose.templates)
+	require.Equal(t, "note: viewer -> bob: {{task}}", m.compose.body.Value())
+
+	m.handleComposeOverlayKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m.handleComposeOverlayKey(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Nil(t, m.compose.templates)
+	require.True(t, m.compose.active)
+}
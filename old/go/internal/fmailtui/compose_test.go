@@ -37,7 +37,7 @@ func TestDraftPersistenceRestorePrompt(t *testing.T) {
 	m.compose.to = "task"
 	m.compose.priority = "normal"
 	m.compose.tags = "auth, urgent"
-	m.compose.body = "draft message"
+	m.compose.body.SetValue("draft message")
 	m.persistDraft(true)
 	require.NoError(t, m.tuiState.SaveNow())
 
@@ -0,0 +1,186 @@
+package fmailtui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+// conversationExport is the visible slice of a conversation written by the
+// thread and timeline export keys.
+type conversationExport struct {
+	view   string // "thread" | "timeline"
+	topic  string
+	filter string
+	since  time.Time
+	until  time.Time
+	msgs   []fmail.Message
+}
+
+// writeConversationExport renders exp as Markdown into the store's exports
+// directory and returns the file path.
+func writeConversationExport(root string, exp conversationExport, now time.Time) (string, error) {
+	if strings.TrimSpace(root) == "" || len(exp.msgs) == 0 {
+		return "", fmt.Errorf("nothing to export")
+	}
+	store, err := fmail.NewStore(root)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(store.Root, "exports")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	subject := exp.topic
+	if strings.TrimSpace(subject) == "" {
+		subject = exp.view
+	}
+	name := fmt.Sprintf("%s-%s-%s.md", exp.view, sanitizeFilenamePart(subject), now.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(renderConversationMarkdown(exp, now)), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// exportVisibleCmd writes the messages in the current window that pass the
+// active filter. When they all share a target, it is recorded as the topic.
+func (v *timelineView) exportVisibleCmd() tea.Cmd {
+	v.rebuildVisible()
+	since, until := v.windowBounds()
+	exp := conversationExport{
+		view:   "timeline",
+		filter: strings.TrimSpace(v.filterRaw),
+		since:  since,
+		until:  until,
+		msgs:   make([]fmail.Message, 0, len(v.visible)),
+	}
+	targets := make(map[string]struct{})
+	for _, item := range v.visible {
+		exp.msgs = append(exp.msgs, item.msg)
+		targets[strings.TrimSpace(item.msg.To)] = struct{}{}
+	}
+	if len(targets) == 1 {
+		for target := range targets {
+			exp.topic = target
+		}
+	}
+	root := strings.TrimSpace(v.root)
+	return func() tea.Msg {
+		path, err := writeConversationExport(root, exp, time.Now().UTC())
+		if err != nil {
+			return timelineExportResultMsg{err: err}
+		}
+		return timelineExportResultMsg{path: path}
+	}
+}
+
+// renderConversationMarkdown writes YAML front matter describing the export
+// followed by one section per message, oldest first.
+func renderConversationMarkdown(exp conversationExport, now time.Time) string {
+	msgs := make([]fmail.Message, 0, len(exp.msgs))
+	for _, msg := range exp.msgs {
+		if strings.TrimSpace(msg.ID) != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	sortMessages(msgs)
+
+	participants := make(map[string]struct{})
+	for _, msg := range msgs {
+		if from := strings.TrimSpace(msg.From); from != "" {
+			participants[from] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(participants))
+	for name := range participants {
+		names = append(names, strconv.Quote(name))
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "view: %s\n", exp.view)
+	if topic := strings.TrimSpace(exp.topic); topic != "" {
+		fmt.Fprintf(&b, "topic: %s\n", strconv.Quote(topic))
+	}
+	if filter := strings.TrimSpace(exp.filter); filter != "" {
+		fmt.Fprintf(&b, "filter: %s\n", strconv.Quote(filter))
+	}
+	since, until := exp.since, exp.until
+	if len(msgs) > 0 {
+		if since.IsZero() {
+			since = msgs[0].Time
+		}
+		if until.IsZero() {
+			until = msgs[len(msgs)-1].Time
+		}
+	}
+	if !since.IsZero() {
+		fmt.Fprintf(&b, "since: %s\n", since.UTC().Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		fmt.Fprintf(&b, "until: %s\n", until.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "messages: %d\n", len(msgs))
+	fmt.Fprintf(&b, "participants: [%s]\n", strings.Join(names, ", "))
+	fmt.Fprintf(&b, "exported_at: %s\n", now.UTC().Format(time.RFC3339))
+	b.WriteString("---\n\n")
+
+	title := strings.TrimSpace(exp.topic)
+	if title == "" {
+		title = "fmail " + exp.view
+	}
+	b.WriteString("# ")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	for _, msg := range msgs {
+		b.WriteString("## ")
+		b.WriteString(msg.ID)
+		b.WriteString("\n")
+		b.WriteString("**From:** ")
+		b.WriteString(strings.TrimSpace(msg.From))
+		b.WriteString(" → ")
+		b.WriteString(strings.TrimSpace(msg.To))
+		if !msg.Time.IsZero() {
+			b.WriteString(" | **Time:** ")
+			b.WriteString(msg.Time.UTC().Format(time.RFC3339))
+		}
+		b.WriteString("\n")
+		if replyTo := strings.TrimSpace(msg.ReplyTo); replyTo != "" {
+			b.WriteString("**Reply to:** ")
+			b.WriteString(replyTo)
+			b.WriteString("\n")
+		}
+		if msg.Priority != "" && msg.Priority != fmail.PriorityNormal {
+			b.WriteString("**Priority:** ")
+			b.WriteString(msg.Priority)
+			b.WriteString("\n")
+		}
+		if len(msg.Tags) > 0 {
+			b.WriteString("**Tags:** ")
+			b.WriteString(strings.Join(msg.Tags, ", "))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		body := strings.TrimRight(messageBodyString(msg.Body), "\n")
+		if body == "" {
+			body = "(empty)"
+		}
+		for _, line := range strings.Split(body, "\n") {
+			b.WriteString("> ")
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n---\n\n")
+	}
+	return b.String()
+}
@@ -720,7 +720,7 @@ func clampInt(v, lo, hi int) int {
 }
 
 func themePalette(theme Theme) styles.Theme {
-	if palette, ok := styles.Themes[string(theme)]; ok {
+	if palette, ok := styles.LookupTheme(string(theme)); ok {
 		return palette
 	}
 	return styles.DefaultTheme
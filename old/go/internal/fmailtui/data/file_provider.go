@@ -38,6 +38,7 @@ type FileProvider struct {
 	searchMu    sync.Mutex
 	searchIndex *textSearchIndex
 	searchDirty map[string]struct{}
+	diskIndex   *fmail.SearchIndex // persisted index, see searchWithDiskIndex
 
 	messageReadLookups atomic.Int64
 	messageDiskReads   atomic.Int64
@@ -131,13 +132,13 @@ func (p *FileProvider) Topics() ([]TopicInfo, error) {
 }
 
 func (p *FileProvider) Messages(topic string, opts MessageFilter) ([]fmail.Message, error) {
-	normalized, err := fmail.NormalizeTopic(topic)
+	normalized, err := normalizeThreadName(topic)
 	if err != nil {
 		return nil, err
 	}
 
 	if shouldUseWindowedRead(opts) {
-		messages, err := p.readMessagesFromDir(p.store.TopicDir(normalized), opts.Since, opts.Until, opts.Limit)
+		messages, err := p.readMessagesFromDir(p.store.ThreadDir(normalized), opts.Since, opts.Until, opts.Limit)
 		if err != nil {
 			return nil, err
 		}
@@ -339,12 +340,6 @@ func (p *FileProvider) searchLinear(query SearchQuery) ([]SearchResult, error) {
 }
 
 func (p *FileProvider) searchWithIndex(query SearchQuery) ([]SearchResult, error) {
-	now := time.Now().UTC()
-	idx, err := p.ensureTextIndex(now)
-	if err != nil {
-		return nil, err
-	}
-
 	scope := strings.TrimSpace(query.In)
 	if scope != "" {
 		if !strings.HasPrefix(scope, "@") {
@@ -363,6 +358,17 @@ func (p *FileProvider) searchWithIndex(query SearchQuery) ([]SearchResult, error
 		return p.searchLinear(query)
 	}
 
+	// Prefer the persisted index; it avoids reading every message file to
+	// build the in-memory one.
+	if results, ok := p.searchWithDiskIndex(query, scope); ok {
+		return results, nil
+	}
+
+	idx, err := p.ensureTextIndex(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
 	// Build candidate set by intersecting postings for all query terms.
 	candidates := make(map[searchRef]struct{})
 	first := true
@@ -476,7 +482,7 @@ func (p *FileProvider) messagesForTopic(topic string) ([]fmail.Message, error) {
 	if messages, ok := p.cachedTopicMessages(topic); ok {
 		return messages, nil
 	}
-	messages, err := p.readMessagesFromDir(p.store.TopicDir(topic), time.Time{}, time.Time{}, 0)
+	messages, err := p.readMessagesFromDir(p.store.ThreadDir(topic), time.Time{}, time.Time{}, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -492,6 +498,7 @@ func (p *FileProvider) messagesForDMDirectory(agent string) ([]fmail.Message, er
 	if err != nil {
 		return nil, err
 	}
+	messages = withoutGroupCopies(messages)
 	p.storeDMMessages(agent, messages)
 	return cloneMessages(messages), nil
 }
@@ -529,7 +536,7 @@ func (p *FileProvider) dmMessagesByDir(agent string, opts MessageFilter) ([]fmai
 		if err != nil {
 			return nil, err
 		}
-		return messages, nil
+		return withoutGroupCopies(messages), nil
 	}
 	return p.messagesForDMDirectory(agent)
 }
@@ -537,10 +544,7 @@ func (p *FileProvider) dmMessagesByDir(agent string, opts MessageFilter) ([]fmai
 func (p *FileProvider) listTopicNames() ([]string, error) {
 	root := filepath.Join(p.store.Root, "topics")
 	entries, err := os.ReadDir(root)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, nil
-		}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return nil, err
 	}
 	names := make([]string, 0, len(entries))
@@ -557,10 +561,42 @@ func (p *FileProvider) listTopicNames() ([]string, error) {
 		}
 		names = append(names, topic)
 	}
+	groups, err := p.listGroupThreadNames()
+	if err != nil {
+		return nil, err
+	}
+	names = append(names, groups...)
+	if len(names) == 0 {
+		return nil, nil
+	}
 	sort.Strings(names)
 	return names, nil
 }
 
+// listGroupThreadNames returns "#name" for every group thread directory.
+// Group threads are listed and read like topics.
+func (p *FileProvider) listGroupThreadNames() ([]string, error) {
+	entries, err := os.ReadDir(p.store.GroupsDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		group, err := fmail.NormalizeGroupName(entry.Name())
+		if err != nil || group != entry.Name() {
+			continue
+		}
+		names = append(names, fmail.GroupTargetPrefix+group)
+	}
+	return names, nil
+}
+
 func (p *FileProvider) listDMDirectoryNames() ([]string, error) {
 	root := filepath.Join(p.store.Root, "dm")
 	entries, err := os.ReadDir(root)
@@ -757,6 +793,34 @@ func sliceMessagesByIDRange(messages []fmail.Message, since time.Time, until tim
 	return cloneMessages(messages[start:end])
 }
 
+// normalizeThreadName normalizes a topic or "#group" thread name.
+func normalizeThreadName(name string) (string, error) {
+	if fmail.IsGroupTarget(name) {
+		group, err := fmail.NormalizeGroupName(name)
+		if err != nil {
+			return "", err
+		}
+		return fmail.GroupTargetPrefix + group, nil
+	}
+	return fmail.NormalizeTopic(name)
+}
+
+// isGroupCopy reports whether message is a member's DM copy of a group
+// message. Views show the group thread instead of the copies.
+func isGroupCopy(message fmail.Message) bool {
+	return message.Group != "" && strings.HasPrefix(message.To, "@")
+}
+
+func withoutGroupCopies(messages []fmail.Message) []fmail.Message {
+	out := messages[:0]
+	for _, message := range messages {
+		if !isGroupCopy(message) {
+			out = append(out, message)
+		}
+	}
+	return out
+}
+
 func isDMBetween(message fmail.Message, left string, right string) bool {
 	target := strings.TrimPrefix(message.To, "@")
 	if target == message.To {
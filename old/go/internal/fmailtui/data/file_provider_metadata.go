@@ -15,6 +15,7 @@ type messageMetadataEntry struct {
 	id       string
 	from     string
 	to       string
+	group    string
 	activity time.Time
 	modTime  time.Time
 	message  fmail.Message
@@ -52,7 +53,7 @@ func (p *FileProvider) buildTopicsFromMetadata() ([]TopicInfo, error) {
 }
 
 func (p *FileProvider) topicInfoFromMetadata(topic string) (TopicInfo, error) {
-	dir := p.store.TopicDir(topic)
+	dir := p.store.ThreadDir(topic)
 	dirModTime, dirExists := dirModTimeUTC(dir)
 	now := time.Now().UTC()
 	ttl := p.metadataTTL
@@ -199,6 +200,10 @@ func (p *FileProvider) buildDMConversationsFromMetadata(viewer string) ([]DMConv
 			return nil, err
 		}
 		for _, meta := range dmMeta.files {
+			if meta.group != "" {
+				// Group copies are shown in the group thread.
+				continue
+			}
 			msg := fmail.Message{
 				ID:   meta.id,
 				From: meta.from,
@@ -309,6 +314,7 @@ func (p *FileProvider) refreshDMDirectoryMetadata(dir string, dirModTime time.Ti
 			id:       strings.TrimSpace(message.ID),
 			from:     strings.TrimSpace(message.From),
 			to:       strings.TrimSpace(message.To),
+			group:    message.Group,
 			activity: latestActivity(message),
 			modTime:  modTime,
 		}
@@ -385,7 +391,7 @@ func (p *FileProvider) invalidateCachesForMessage(message fmail.Message) {
 	}
 
 	topic := target
-	if normalized, err := fmail.NormalizeTopic(topic); err == nil {
+	if normalized, err := normalizeThreadName(topic); err == nil {
 		topic = normalized
 	}
 	p.topicsCache = timedEntry[[]TopicInfo]{}
@@ -467,6 +473,16 @@ func (p *FileProvider) metadataTargetFromPath(path string) (string, string) {
 			return "", ""
 		}
 		return "dm", agent
+	case "groups":
+		// groups/<name>.json is the definition; only thread files count.
+		if len(parts) < 3 {
+			return "", ""
+		}
+		group, err := fmail.NormalizeGroupName(parts[1])
+		if err != nil {
+			return "", ""
+		}
+		return "topic", fmail.GroupTargetPrefix + group
 	default:
 		return "", ""
 	}
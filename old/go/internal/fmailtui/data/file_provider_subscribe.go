@@ -269,11 +269,11 @@ func (p *FileProvider) subscriptionDirs(filter SubscriptionFilter) ([]string, er
 			}
 			return []string{p.store.DMDir(normalized)}, nil
 		}
-		normalized, err := fmail.NormalizeTopic(topic)
+		normalized, err := normalizeThreadName(topic)
 		if err != nil {
 			return nil, err
 		}
-		return []string{p.store.TopicDir(normalized)}, nil
+		return []string{p.store.ThreadDir(normalized)}, nil
 	}
 
 	dirs := make([]string, 0)
@@ -282,7 +282,7 @@ func (p *FileProvider) subscriptionDirs(filter SubscriptionFilter) ([]string, er
 		return nil, err
 	}
 	for _, topicName := range topics {
-		dirs = append(dirs, p.store.TopicDir(topicName))
+		dirs = append(dirs, p.store.ThreadDir(topicName))
 	}
 
 	includeDM := filter.IncludeDM || topic == "*" || (topic == "" && strings.TrimSpace(filter.Agent) == "")
@@ -309,7 +309,7 @@ func messageMatchesSubscription(message fmail.Message, filter SubscriptionFilter
 		} else if !strings.EqualFold(message.To, topic) {
 			return false
 		}
-	} else if !filter.IncludeDM && strings.HasPrefix(message.To, "@") {
+	} else if isGroupCopy(message) || (!filter.IncludeDM && strings.HasPrefix(message.To, "@")) {
 		return false
 	}
 
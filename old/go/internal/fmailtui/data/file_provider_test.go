@@ -254,17 +254,60 @@ func TestFileProviderSearchIndexTTLCheckAvoidsFullRebuild(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	_, err = provider.Search(SearchQuery{Text: "needle"})
+	// has:reply needs whole targets, so it runs on the in-memory index.
+	_, err = provider.Search(SearchQuery{Text: "needle", HasReply: true})
 	require.NoError(t, err)
 	lookups1, _ := provider.messageReadStats()
 
 	time.Sleep(25 * time.Millisecond)
-	_, err = provider.Search(SearchQuery{Text: "needle"})
+	_, err = provider.Search(SearchQuery{Text: "needle", HasReply: true})
 	require.NoError(t, err)
 	lookups2, _ := provider.messageReadStats()
 	require.Equal(t, lookups1, lookups2)
 }
 
+func TestFileProviderSearchUsesPersistedIndex(t *testing.T) {
+	root := t.TempDir()
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureRoot())
+
+	base := time.Now().UTC().Add(-time.Hour)
+	for i := 0; i < 50; i++ {
+		_, err = store.SaveMessage(&fmail.Message{
+			From: "ops-bot",
+			To:   "ops",
+			Body: fmt.Sprintf("ops warm %d", i),
+			Time: base.Add(time.Duration(i) * time.Second),
+		})
+		require.NoError(t, err)
+	}
+	_, err = store.SaveMessage(&fmail.Message{From: "alice", To: "ops", Body: "needle here", Time: base.Add(time.Minute)})
+	require.NoError(t, err)
+
+	provider, err := NewFileProvider(FileProviderConfig{Root: root})
+	require.NoError(t, err)
+
+	results, err := provider.Search(SearchQuery{Text: "needle"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "ops", results[0].Topic)
+	require.NotNil(t, results[0].Prev)
+	require.Nil(t, results[0].Next)
+	lookups, _ := provider.messageReadStats()
+	require.Equal(t, int64(2), lookups, "only the hit and its neighbour are read")
+	_, err = os.Stat(store.SearchIndexPath())
+	require.NoError(t, err)
+
+	// Sends from other processes append to the index.
+	_, err = store.SaveMessage(&fmail.Message{From: "bob", To: "@alice", Body: "another needle", Time: time.Now().UTC()})
+	require.NoError(t, err)
+	results, err = provider.Search(SearchQuery{Text: "needle"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "@alice", results[1].Topic)
+}
+
 func TestFileProviderDMsIgnoreUnrelatedCorruptDirectory(t *testing.T) {
 	root := t.TempDir()
 	store, err := fmail.NewStore(root)
@@ -428,3 +471,37 @@ func findDMConversation(conversations []DMConversation, agent string) *DMConvers
 	}
 	return nil
 }
+
+func TestFileProviderShowsGroupThreadsInsteadOfCopies(t *testing.T) {
+	root := t.TempDir()
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SaveGroup("frontend", []string{"alice", "bob"}, "")
+	require.NoError(t, err)
+
+	provider, err := NewFileProvider(FileProviderConfig{Root: root, CacheTTL: time.Millisecond, MetadataTTL: time.Millisecond})
+	require.NoError(t, err)
+
+	sent, err := provider.Send(SendRequest{From: "alice", To: "#frontend", Body: "api changed"})
+	require.NoError(t, err)
+	_, err = provider.Send(SendRequest{From: "bob", To: "#frontend", Body: "ack", ReplyTo: sent.ID})
+	require.NoError(t, err)
+
+	topics, err := provider.Topics()
+	require.NoError(t, err)
+	require.Len(t, topics, 1)
+	require.Equal(t, "#frontend", topics[0].Name)
+	require.Equal(t, 2, topics[0].MessageCount)
+
+	thread, err := provider.Messages("#frontend", MessageFilter{})
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	require.Equal(t, sent.ID, thread[1].ReplyTo)
+
+	conversations, err := provider.DMConversations("bob")
+	require.NoError(t, err)
+	require.Empty(t, conversations)
+	dms, err := provider.DMs("alice", MessageFilter{To: "@bob"})
+	require.NoError(t, err)
+	require.Empty(t, dms)
+}
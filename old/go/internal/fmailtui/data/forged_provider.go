@@ -167,6 +167,10 @@ func (p *ForgedProvider) Send(req SendRequest) (fmail.Message, error) {
 	if err != nil {
 		return fmail.Message{}, err
 	}
+	if fmail.IsGroupTarget(msg.To) {
+		// forged does not fan out to groups; the store does.
+		return p.fallback.Send(req)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), p.dialTimeout)
 	defer cancel()
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+// searchWithDiskIndex answers a text query from the persisted fmail search
+// index, reading only the matching message files and their neighbours for
+// context. It reports false when the query needs the in-memory index:
+// has:reply needs every message of a target, and encrypted DMs are not in
+// the persisted index.
+func (p *FileProvider) searchWithDiskIndex(query SearchQuery, scope string) ([]SearchResult, bool) {
+	if query.HasReply {
+		return nil, false
+	}
+	if (scope == "" || strings.HasPrefix(scope, "@")) && p.store.EncryptionEnabled() {
+		return nil, false
+	}
+
+	p.searchMu.Lock()
+	if p.diskIndex == nil {
+		idx, err := p.store.OpenSearchIndex()
+		if err != nil {
+			p.searchMu.Unlock()
+			return nil, false
+		}
+		p.diskIndex = idx
+	} else if err := p.diskIndex.Refresh(); err != nil {
+		p.diskIndex = nil
+		p.searchMu.Unlock()
+		return nil, false
+	}
+	hits := p.diskIndex.Lookup(query.Text, scope)
+	p.searchMu.Unlock()
+
+	listings := make(map[string]*dirListing)
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		listing, ok := listings[hit.Target]
+		if !ok {
+			var err error
+			listing, err = p.listDir(filepath.Dir(p.store.SearchHitPath(hit)))
+			if err != nil {
+				return nil, false
+			}
+			listings[hit.Target] = listing
+		}
+		pos, found := listing.find(hit.ID + ".json")
+		if !found {
+			continue
+		}
+		msg, ok := listing.read(p, pos)
+		if !ok {
+			continue
+		}
+		matched, offset, length := searchMatches(&msg, query)
+		if !matched {
+			continue
+		}
+		result := SearchResult{
+			Message:     msg,
+			Topic:       hit.Target,
+			MatchOffset: offset,
+			MatchLength: length,
+		}
+		if prev, ok := listing.read(p, pos-1); ok {
+			result.Prev = &prev
+		}
+		if next, ok := listing.read(p, pos+1); ok {
+			result.Next = &next
+		}
+		results = append(results, result)
+	}
+	return results, true
+}
+
+// dirListing is one message directory's sorted file names.
+type dirListing struct {
+	dir     string
+	names   []string
+	entries map[string]os.DirEntry
+}
+
+func (p *FileProvider) listDir(dir string) (*dirListing, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	names, byName := sortedJSONNames(entries)
+	return &dirListing{dir: dir, names: names, entries: byName}, nil
+}
+
+func (l *dirListing) find(name string) (int, bool) {
+	i := sort.SearchStrings(l.names, name)
+	return i, i < len(l.names) && l.names[i] == name
+}
+
+// read loads the message at position i; group copies in DM directories are
+// skipped, matching the DM views.
+func (l *dirListing) read(p *FileProvider, i int) (fmail.Message, bool) {
+	if i < 0 || i >= len(l.names) {
+		return fmail.Message{}, false
+	}
+	name := l.names[i]
+	msg, ok, err := p.readMessageFile(filepath.Join(l.dir, name), l.entries[name])
+	if err != nil || !ok || isGroupCopy(msg) {
+		return fmail.Message{}, false
+	}
+	return msg, true
+}
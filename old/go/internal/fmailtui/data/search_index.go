@@ -132,7 +132,7 @@ func (p *FileProvider) refreshIndexTargetLocked(idx *textSearchIndex, target str
 		modTime, ok = dirModTimeUTC(p.store.DMDir(agent))
 	} else {
 		topic := target
-		if normalized, normalizeErr := fmail.NormalizeTopic(topic); normalizeErr != nil {
+		if normalized, normalizeErr := normalizeThreadName(topic); normalizeErr != nil {
 			return nil
 		} else {
 			topic = normalized
@@ -141,7 +141,7 @@ func (p *FileProvider) refreshIndexTargetLocked(idx *textSearchIndex, target str
 		if err != nil {
 			return err
 		}
-		modTime, ok = dirModTimeUTC(p.store.TopicDir(topic))
+		modTime, ok = dirModTimeUTC(p.store.ThreadDir(topic))
 	}
 	if !ok {
 		return nil
@@ -266,7 +266,7 @@ func (p *FileProvider) detectIndexDriftLocked(idx *textSearchIndex) (map[string]
 		return nil, err
 	}
 	for _, topic := range topics {
-		if mod, ok := dirModTimeUTC(p.store.TopicDir(topic)); ok {
+		if mod, ok := dirModTimeUTC(p.store.ThreadDir(topic)); ok {
 			currentTargets[topic] = mod
 		}
 	}
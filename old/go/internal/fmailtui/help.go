@@ -64,10 +64,12 @@ func helpForView(id ViewID) []helpSection {
 			{key: ":", desc: "quick send"},
 			{key: "n", desc: "new message"},
 			{key: "Ctrl+B", desc: "open bookmarks"},
+			{key: "Ctrl+K", desc: "jump to topic/DM/agent"},
 			{key: "Ctrl+T", desc: "cycle theme"},
-			{key: "Ctrl+R", desc: "refresh view"},
+			{key: "R / Ctrl+R", desc: "refresh view now"},
 			{key: "Ctrl+Z", desc: "toggle zen layout"},
 			{key: "Ctrl+N", desc: "open notifications"},
+			{key: "Ctrl+X", desc: "dismiss alert strip"},
 			{key: "Tab", desc: "cycle pane focus"},
 			{key: "Ctrl+W h/j/k/l", desc: "move pane focus"},
 			{key: "Ctrl+W +/-", desc: "adjust split ratio"},
@@ -75,6 +77,8 @@ func helpForView(id ViewID) []helpSection {
 			{key: "| / Ctrl+\\", desc: "split resize / collapse"},
 			{key: "Ctrl+G", desc: "cycle dashboard grid"},
 			{key: "Ctrl+1..4", desc: "cycle dashboard slot"},
+			{key: "Q<reg> ... Q", desc: "record macro into register a-z/0-9"},
+			{key: "@<reg> / @@", desc: "replay macro / repeat last"},
 			{key: "?", desc: "toggle help"},
 		},
 	}
@@ -89,6 +93,8 @@ func helpForView(id ViewID) []helpSection {
 				{key: "d", desc: "toggle topics/DMs"},
 				{key: "s", desc: "cycle sort"},
 				{key: "*", desc: "star topic"},
+				{key: "M", desc: "mute/unmute (hide from unread counts)"},
+				{key: "z", desc: "snooze 1h/4h/24h/off"},
 				{key: "/", desc: "filter"},
 			}},
 		}
@@ -101,9 +107,27 @@ func helpForView(id ViewID) []helpSection {
 				{key: "g/G", desc: "top/bottom"},
 				{key: "Enter", desc: "expand/collapse"},
 				{key: "f", desc: "toggle flat/threaded"},
+				{key: "b / P", desc: "bookmark / pin in topic"},
+				{key: "z", desc: "collapse/expand pinned"},
+				{key: "e / E", desc: "react (1-9 picks emoji) / clear my reactions"},
+				{key: "X", desc: "export visible thread to markdown"},
 				{key: "r / R", desc: "reply / DM reply"},
 			}},
 		}
+	case ViewOperator:
+		return []helpSection{
+			global,
+			{title: "Operator", items: []helpItem{
+				{key: "Tab / Shift+Tab", desc: "next/prev conversation (split: switch pane)"},
+				{key: "1-9", desc: "jump to quick target"},
+				{key: "Ctrl+O", desc: "open/close second conversation pane"},
+				{key: "Ctrl+B", desc: "toggle conversation sidebar"},
+				{key: "Ctrl+E", desc: "toggle agent presence sidebar"},
+				{key: "Ctrl+F", desc: "presence: online agents only"},
+				{key: "Ctrl+P", desc: "command palette"},
+				{key: "Ctrl+M", desc: "toggle multi-line compose"},
+			}},
+		}
 	case ViewAgents:
 		return []helpSection{
 			global,
@@ -128,6 +152,9 @@ func helpForView(id ViewID) []helpSection {
 				{key: "Enter", desc: "toggle detail popup"},
 				{key: "o", desc: "open selected in thread view"},
 				{key: "b", desc: "toggle bookmark"},
+				{key: "e / E", desc: "react (1-9 picks emoji) / clear my reactions"},
+				{key: "X", desc: "export visible messages to markdown"},
+				{key: "s / O", desc: "detail: save / open attachments"},
 			}},
 		}
 	case ViewBookmarks:
@@ -150,6 +177,7 @@ func helpForView(id ViewID) []helpSection {
 				{key: "r", desc: "refresh"},
 				{key: "[ / ]", desc: "adjust time range"},
 				{key: "h/l or ←/→", desc: "pan window"},
+				{key: "t / T", desc: "cycle topic scope"},
 			}},
 		}
 	case ViewHeatmap:
@@ -204,6 +232,19 @@ func helpForView(id ViewID) []helpSection {
 				{key: "Space", desc: "toggle selected rule"},
 			}},
 		}
+	case ViewInbox:
+		return []helpSection{
+			global,
+			{title: "Inbox", items: []helpItem{
+				{key: "j/k", desc: "move selection"},
+				{key: "g/G", desc: "top/bottom"},
+				{key: "Enter", desc: "mark read and jump to thread"},
+				{key: "x / X", desc: "mark read / mark all read"},
+				{key: "b", desc: "toggle bookmark"},
+				{key: "u", desc: "toggle unread only"},
+				{key: "M / z", desc: "mute / snooze thread"},
+			}},
+		}
 	default:
 		return []helpSection{global}
 	}
@@ -0,0 +1,479 @@
+package fmailtui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/data"
+	"github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+// inboxMaxItems caps the inbox to the newest DMs and mentions.
+const inboxMaxItems = 500
+
+// inboxItem is one incoming message: a DM addressed to self, or a topic
+// message mentioning @self. Target is the thread it belongs to ("@peer" or
+// the topic name) and keys its read marker.
+type inboxItem struct {
+	target string
+	msg    fmail.Message
+}
+
+type inboxLoadedMsg struct {
+	now   time.Time
+	items []inboxItem
+	err   error
+}
+
+// inboxView merges DMs and mentions across topics into one triage list,
+// unread and high-priority first.
+type inboxView struct {
+	self     string
+	provider data.MessageProvider
+	state    *state.Manager
+
+	items    []inboxItem
+	visible  []inboxItem
+	selected int
+
+	unreadOnly bool
+	loading    bool
+	lastErr    error
+	now        time.Time
+
+	statusLine string
+	statusErr  bool
+}
+
+func newInboxView(self string, provider data.MessageProvider, st *state.Manager) *inboxView {
+	return &inboxView{
+		self:     strings.TrimSpace(self),
+		provider: provider,
+		state:    st,
+	}
+}
+
+func (v *inboxView) Init() tea.Cmd {
+	v.loading = true
+	return v.loadCmd()
+}
+
+func (v *inboxView) Update(msg tea.Msg) tea.Cmd {
+	switch typed := msg.(type) {
+	case inboxLoadedMsg:
+		v.loading = false
+		v.lastErr = typed.err
+		v.now = typed.now
+		if typed.err == nil {
+			v.items = typed.items
+			v.rebuild()
+		}
+		return nil
+	case tea.KeyMsg:
+		return v.handleKey(typed)
+	}
+	return nil
+}
+
+func (v *inboxView) MinSize() (int, int) {
+	return 60, 12
+}
+
+func (v *inboxView) ComposeTarget() string {
+	item, ok := v.selectedItem()
+	if !ok {
+		return ""
+	}
+	return item.target
+}
+
+func (v *inboxView) View(width, height int, theme Theme) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	palette := themePalette(theme)
+	base := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Foreground)).Background(lipgloss.Color(palette.Base.Background))
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Priority.High)).Bold(true)
+	highStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Priority.High))
+	selStyle := lipgloss.NewStyle().Background(lipgloss.Color(palette.Base.Border))
+
+	title := fmt.Sprintf("Inbox (%d unread / %d)", v.unreadCount(), len(v.items))
+	if v.unreadOnly {
+		title += "  [unread only]"
+	}
+	if v.loading {
+		title += "  loading..."
+	}
+	lines := []string{
+		accent.Render(truncateVis(title, width)),
+		muted.Render(truncateVis("Enter open  x mark read  X mark all read  b bookmark  u unread only  M mute  z snooze  Esc back", width)),
+	}
+
+	if v.lastErr != nil {
+		lines = append(lines, errStyle.Render(truncateVis("load failed: "+v.lastErr.Error(), width)))
+	}
+
+	bodyH := maxInt(1, height-len(lines)-1)
+	if len(v.visible) == 0 && !v.loading {
+		empty := "No DMs or mentions for @" + v.self + "."
+		if v.unreadOnly {
+			empty = "Inbox zero. Press u to show read messages."
+		}
+		lines = append(lines, muted.Render(truncateVis(empty, width)))
+	}
+
+	start := 0
+	if v.selected >= bodyH {
+		start = v.selected - bodyH + 1
+	}
+	end := minInt(len(v.visible), start+bodyH)
+	now := v.now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	for i := start; i < end; i++ {
+		item := v.visible[i]
+		unread := " "
+		if label := muteLabel(v.state, item.target, time.Now().UTC()); label != "" {
+			unread = label[:1]
+		} else if v.isUnread(item) {
+			unread = "●"
+		}
+		prio := " "
+		if item.msg.Priority == fmail.PriorityHigh {
+			prio = "!"
+		}
+		mark := " "
+		if v.state != nil && v.state.IsBookmarked(item.msg.ID) {
+			mark = "*"
+		}
+		row := fmt.Sprintf("%s%s%s %-14s %-12s %-8s %s",
+			unread, prio, mark,
+			truncateVis(item.target, 14),
+			truncateVis(item.msg.From, 12),
+			relativeTime(item.msg.Time, now),
+			firstNonEmptyLine(messageBodyString(item.msg.Body)),
+		)
+		row = truncateVis(row, width)
+		switch {
+		case i == v.selected:
+			row = selStyle.Render(row)
+		case item.msg.Priority == fmail.PriorityHigh && v.countsUnread(item):
+			row = highStyle.Render(row)
+		case !v.countsUnread(item):
+			row = muted.Render(row)
+		}
+		lines = append(lines, row)
+	}
+
+	if strings.TrimSpace(v.statusLine) != "" {
+		if v.statusErr {
+			lines = append(lines, errStyle.Render(truncateVis(v.statusLine, width)))
+		} else {
+			lines = append(lines, muted.Render(truncateVis(v.statusLine, width)))
+		}
+	}
+	return base.Render(joinClampedLines(lines, height))
+}
+
+func (v *inboxView) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "backspace":
+		return popViewCmd()
+	case "j", "down":
+		v.selected = clampInt(v.selected+1, 0, maxInt(0, len(v.visible)-1))
+		return nil
+	case "k", "up":
+		v.selected = clampInt(v.selected-1, 0, maxInt(0, len(v.visible)-1))
+		return nil
+	case "g", "home":
+		v.selected = 0
+		return nil
+	case "G", "end":
+		v.selected = maxInt(0, len(v.visible)-1)
+		return nil
+	case "enter":
+		item, ok := v.selectedItem()
+		if !ok {
+			return nil
+		}
+		v.markRead(item)
+		return tea.Batch(openThreadCmd(item.target, item.msg.ID), pushViewCmd(ViewThread))
+	case "x":
+		item, ok := v.selectedItem()
+		if !ok {
+			return nil
+		}
+		if !v.isUnread(item) {
+			v.statusLine = "already read"
+			v.statusErr = false
+			return nil
+		}
+		v.markRead(item)
+		v.statusLine = fmt.Sprintf("marked %s read through %s", item.target, shortID(item.msg.ID))
+		v.statusErr = false
+		v.rebuild()
+		return nil
+	case "X":
+		count := v.markAllRead()
+		v.statusLine = fmt.Sprintf("marked %d message(s) read", count)
+		v.statusErr = false
+		v.rebuild()
+		return nil
+	case "b":
+		item, ok := v.selectedItem()
+		if !ok || v.state == nil {
+			return nil
+		}
+		if v.state.ToggleBookmark(item.msg.ID, item.target) {
+			v.statusLine = "bookmarked"
+		} else {
+			v.statusLine = "bookmark removed"
+		}
+		v.statusErr = false
+		v.state.SaveSoon()
+		return nil
+	case "u":
+		v.unreadOnly = !v.unreadOnly
+		v.rebuild()
+		return nil
+	case "M", "z":
+		item, ok := v.selectedItem()
+		if !ok || v.state == nil {
+			return nil
+		}
+		if msg.String() == "M" {
+			v.statusLine = toggleMute(v.state, item.target, time.Now().UTC())
+		} else {
+			v.statusLine = cycleSnooze(v.state, item.target, time.Now().UTC())
+		}
+		v.statusErr = false
+		v.rebuild()
+		return nil
+	}
+	return nil
+}
+
+func (v *inboxView) selectedItem() (inboxItem, bool) {
+	if v.selected < 0 || v.selected >= len(v.visible) {
+		return inboxItem{}, false
+	}
+	return v.visible[v.selected], true
+}
+
+func (v *inboxView) readMarker(target string) string {
+	if v.state == nil {
+		return ""
+	}
+	if marker := v.state.ReadMarker(target); marker != "" {
+		return marker
+	}
+	if strings.HasPrefix(target, "@") {
+		return v.state.ReadMarker(strings.TrimPrefix(target, "@"))
+	}
+	return ""
+}
+
+// isUnread treats a thread that was never opened as unread.
+func (v *inboxView) isUnread(item inboxItem) bool {
+	marker := v.readMarker(item.target)
+	return marker == "" || item.msg.ID > marker
+}
+
+// countsUnread is isUnread for threads that are not muted or snoozed.
+func (v *inboxView) countsUnread(item inboxItem) bool {
+	return v.isUnread(item) && !targetMuted(v.state, item.target, time.Now().UTC())
+}
+
+func (v *inboxView) unreadCount() int {
+	count := 0
+	for _, item := range v.items {
+		if v.countsUnread(item) {
+			count++
+		}
+	}
+	return count
+}
+
+// markRead advances the item's thread read marker to it, which also marks
+// older messages in that thread read.
+func (v *inboxView) markRead(item inboxItem) {
+	if v.state == nil {
+		return
+	}
+	v.state.SetReadMarker(item.target, item.msg.ID)
+	v.state.SaveSoon()
+}
+
+func (v *inboxView) markAllRead() int {
+	if v.state == nil {
+		return 0
+	}
+	newest := make(map[string]string)
+	count := 0
+	for _, item := range v.items {
+		if !v.isUnread(item) {
+			continue
+		}
+		count++
+		if item.msg.ID > newest[item.target] {
+			newest[item.target] = item.msg.ID
+		}
+	}
+	for target, id := range newest {
+		v.state.SetReadMarker(target, id)
+	}
+	if count > 0 {
+		v.state.SaveSoon()
+	}
+	return count
+}
+
+// rebuild re-sorts the visible list, keeping the selection on the same
+// message when it is still shown.
+func (v *inboxView) rebuild() {
+	selectedID := ""
+	if item, ok := v.selectedItem(); ok {
+		selectedID = item.msg.ID
+	}
+
+	visible := make([]inboxItem, 0, len(v.items))
+	unread := make(map[string]bool, len(v.items))
+	for _, item := range v.items {
+		isUnread := v.countsUnread(item)
+		if v.unreadOnly && !isUnread {
+			continue
+		}
+		unread[item.msg.ID] = isUnread
+		visible = append(visible, item)
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		left, right := visible[i], visible[j]
+		if unread[left.msg.ID] != unread[right.msg.ID] {
+			return unread[left.msg.ID]
+		}
+		leftHigh := left.msg.Priority == fmail.PriorityHigh
+		rightHigh := right.msg.Priority == fmail.PriorityHigh
+		if leftHigh != rightHigh {
+			return leftHigh
+		}
+		return left.msg.ID > right.msg.ID
+	})
+	v.visible = visible
+
+	v.selected = clampInt(v.selected, 0, maxInt(0, len(visible)-1))
+	for i, item := range visible {
+		if item.msg.ID == selectedID {
+			v.selected = i
+			break
+		}
+	}
+}
+
+func (v *inboxView) loadCmd() tea.Cmd {
+	provider := v.provider
+	self := v.self
+	return func() tea.Msg {
+		now := time.Now().UTC()
+		items, err := loadInboxItems(provider, self)
+		return inboxLoadedMsg{now: now, items: items, err: err}
+	}
+}
+
+// loadInboxItems collects DMs received by self and topic messages that
+// mention @self, newest first, capped at inboxMaxItems.
+func loadInboxItems(provider data.MessageProvider, self string) ([]inboxItem, error) {
+	if provider == nil || self == "" {
+		return nil, nil
+	}
+	items := make([]inboxItem, 0, 128)
+	seen := make(map[string]struct{}, 128)
+	add := func(item inboxItem) {
+		if strings.EqualFold(strings.TrimSpace(item.msg.From), self) {
+			return
+		}
+		key := item.target + "\x00" + item.msg.ID
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		items = append(items, item)
+	}
+
+	convs, err := provider.DMConversations(self)
+	if err != nil {
+		return nil, err
+	}
+	for _, conv := range convs {
+		peer := strings.TrimSpace(conv.Agent)
+		if peer == "" {
+			continue
+		}
+		msgs, err := provider.DMs(peer, data.MessageFilter{To: "@" + self})
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			add(inboxItem{target: "@" + peer, msg: msg})
+		}
+	}
+
+	topics, err := provider.Topics()
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		name := strings.TrimSpace(topic.Name)
+		if name == "" {
+			continue
+		}
+		msgs, err := provider.Messages(name, data.MessageFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if mentionsAgent(messageBodyString(msg.Body), self) {
+				add(inboxItem{target: name, msg: msg})
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].msg.ID > items[j].msg.ID })
+	if len(items) > inboxMaxItems {
+		items = items[:inboxMaxItems]
+	}
+	return items, nil
+}
+
+// mentionsAgent reports whether text contains @agent as a whole word.
+func mentionsAgent(text, agent string) bool {
+	if agent == "" {
+		return false
+	}
+	lower := strings.ToLower(text)
+	needle := "@" + strings.ToLower(agent)
+	for offset := 0; ; {
+		idx := strings.Index(lower[offset:], needle)
+		if idx < 0 {
+			return false
+		}
+		end := offset + idx + len(needle)
+		if end >= len(lower) || !isAgentNameRune(rune(lower[end])) {
+			return true
+		}
+		offset = end
+	}
+}
+
+func isAgentNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
@@ -0,0 +1,74 @@
+package fmailtui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/data"
+	tuistate "github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+func TestMentionsAgentMatchesWholeName(t *testing.T) {
+	require.True(t, mentionsAgent("ping @Alice please", "alice"))
+	require.True(t, mentionsAgent("cc @alice.", "alice"))
+	require.False(t, mentionsAgent("ping @alice-2", "alice"))
+	require.True(t, mentionsAgent("@alice-2 and @alice", "alice"))
+	require.False(t, mentionsAgent("alice without at", "alice"))
+}
+
+func TestInboxViewSortsUnreadAndHighPriorityFirst(t *testing.T) {
+	now := time.Date(2026, 2, 9, 11, 0, 0, 0, time.UTC)
+	provider := &stubTopicsProvider{
+		topics: []data.TopicInfo{{Name: "task"}},
+		dms:    []data.DMConversation{{Agent: "bob"}},
+		byTopic: map[string][]fmail.Message{
+			"task": {
+				{ID: "20260209-100000-0001", From: "carol", To: "task", Time: now, Body: "@me old mention"},
+				{ID: "20260209-100100-0001", From: "carol", To: "task", Time: now, Body: "no mention here"},
+				{ID: "20260209-100200-0001", From: "dave", To: "task", Time: now, Body: "urgent @me", Priority: fmail.PriorityHigh},
+			},
+		},
+		byDM: map[string][]fmail.Message{
+			"bob": {
+				{ID: "20260209-100300-0001", From: "bob", To: "@me", Time: now, Body: "dm one"},
+				{ID: "20260209-100400-0001", From: "me", To: "@bob", Time: now, Body: "my reply"},
+			},
+		},
+	}
+	st := tuistate.New(filepath.Join(t.TempDir(), "tui-state.json"))
+	st.SetReadMarker("task", "20260209-100000-0001")
+
+	v := newInboxView("me", provider, st)
+	v.Update(v.loadCmd()())
+
+	require.Len(t, v.items, 3)
+	require.Equal(t, 2, v.unreadCount())
+	require.Equal(t, "20260209-100200-0001", v.visible[0].msg.ID) // unread + high
+	require.Equal(t, "20260209-100300-0001", v.visible[1].msg.ID) // unread DM
+	require.Equal(t, "@bob", v.visible[1].target)
+	require.Equal(t, "20260209-100000-0001", v.visible[2].msg.ID) // read
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	require.Len(t, v.visible, 2)
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	require.True(t, st.IsBookmarked("20260209-100200-0001"))
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	require.Equal(t, "20260209-100200-0001", st.ReadMarker("task"))
+	require.Len(t, v.visible, 1)
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	require.Equal(t, "20260209-100300-0001", st.ReadMarker("@bob"))
+	require.Equal(t, 0, v.unreadCount())
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	require.Len(t, v.visible, 3)
+	cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+}
@@ -0,0 +1,232 @@
+package fmailtui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// macroStepDelay spaces replayed keys so loads triggered by one key (opening
+// a thread, switching views) land before the next key is applied.
+const macroStepDelay = 30 * time.Millisecond
+
+type macroPrompt int
+
+const (
+	macroPromptNone macroPrompt = iota
+	macroPromptRecord
+	macroPromptPlay
+)
+
+// macroState tracks keyboard macros. "Q<reg>" starts recording into a
+// register, "Q" stops; "@<reg>" replays it and "@@" repeats the last replay.
+// Recordings are persisted in tuistate as tea.KeyMsg strings.
+type macroState struct {
+	prompt    macroPrompt
+	recording string
+	keys      []string
+
+	playing    string
+	playID     int
+	replaying  bool // a replayed key is being applied
+	lastPlayed string
+
+	// registers holds recordings when there is no persisted TUI state.
+	registers map[string][]string
+}
+
+// macroStepMsg applies keys[idx] of a replay. Steps from an interrupted or
+// superseded replay carry a stale id and are dropped.
+type macroStepMsg struct {
+	id   int
+	keys []string
+	idx  int
+}
+
+func validMacroRegister(key string) bool {
+	if len(key) != 1 {
+		return false
+	}
+	c := key[0]
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// macroKeysEnabled reports whether Q and @ act as macro keys. Anywhere text
+// is being typed they are passed through.
+func (m *Model) macroKeysEnabled() bool {
+	if m.compose.active || m.quick.active || m.layoutWindowCmd {
+		return false
+	}
+	switch m.activeViewID() {
+	case ViewSearch, ViewOperator:
+		return false
+	case ViewTimeline:
+		if timeline, ok := m.views[ViewTimeline].(*timelineView); ok && timeline.wantsKey("@") {
+			return false
+		}
+	}
+	return true
+}
+
+// handleMacroKey handles the macro control keys and register prompts, and
+// cancels a replay when a real key arrives mid-replay.
+func (m *Model) handleMacroKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	if m.macro.playing != "" && !m.macro.replaying {
+		m.setToast(fmt.Sprintf("macro @%s interrupted", m.macro.playing))
+		m.macro.playing = ""
+		m.macro.playID++
+	}
+
+	switch m.macro.prompt {
+	case macroPromptRecord:
+		m.macro.prompt = macroPromptNone
+		if !validMacroRegister(key) {
+			m.setToast("macro cancelled")
+			return nil, true
+		}
+		m.macro.recording = key
+		m.macro.keys = nil
+		m.setToast(fmt.Sprintf("recording @%s (Q to stop)", key))
+		return nil, true
+	case macroPromptPlay:
+		m.macro.prompt = macroPromptNone
+		if key == "@" {
+			key = m.macro.lastPlayed
+		}
+		if !validMacroRegister(key) {
+			m.setToast("macro cancelled")
+			return nil, true
+		}
+		return m.playMacro(key), true
+	}
+
+	if m.macro.replaying || !m.macroKeysEnabled() {
+		return nil, false
+	}
+	switch key {
+	case "Q":
+		if m.macro.recording != "" {
+			m.stopMacroRecording()
+			return nil, true
+		}
+		m.macro.prompt = macroPromptRecord
+		return nil, true
+	case "@":
+		m.macro.prompt = macroPromptPlay
+		return nil, true
+	}
+	return nil, false
+}
+
+func (m *Model) recordMacroKey(msg tea.KeyMsg) {
+	if m.macro.recording == "" {
+		return
+	}
+	m.macro.keys = append(m.macro.keys, msg.String())
+}
+
+func (m *Model) stopMacroRecording() {
+	register, keys := m.macro.recording, m.macro.keys
+	m.macro.recording = ""
+	m.macro.keys = nil
+	m.storeMacro(register, keys)
+	if len(keys) == 0 {
+		m.setToast(fmt.Sprintf("macro @%s cleared", register))
+		return
+	}
+	m.setToast(fmt.Sprintf("recorded @%s (%d keys)", register, len(keys)))
+}
+
+func (m *Model) playMacro(register string) tea.Cmd {
+	keys := m.loadMacro(register)
+	if len(keys) == 0 {
+		m.setToast(fmt.Sprintf("macro @%s is empty", register))
+		return nil
+	}
+	m.macro.lastPlayed = register
+	m.macro.playing = register
+	m.macro.playID++
+	step := macroStepMsg{id: m.macro.playID, keys: keys}
+	return func() tea.Msg { return step }
+}
+
+// stepMacro applies one replayed key through Update and schedules the next.
+func (m *Model) stepMacro(step macroStepMsg) tea.Cmd {
+	if m.macro.playing == "" || step.id != m.macro.playID {
+		return nil
+	}
+	if step.idx >= len(step.keys) {
+		m.macro.playing = ""
+		return nil
+	}
+	var cmd tea.Cmd
+	if msg, ok := parseMacroKey(step.keys[step.idx]); ok {
+		m.macro.replaying = true
+		_, cmd = m.Update(msg)
+		m.macro.replaying = false
+	}
+	next := macroStepMsg{id: step.id, keys: step.keys, idx: step.idx + 1}
+	return tea.Batch(cmd, tea.Tick(macroStepDelay, func(time.Time) tea.Msg { return next }))
+}
+
+func (m *Model) loadMacro(register string) []string {
+	if m.tuiState != nil {
+		return m.tuiState.Macro(register)
+	}
+	return append([]string(nil), m.macro.registers[register]...)
+}
+
+func (m *Model) storeMacro(register string, keys []string) {
+	if m.tuiState != nil {
+		m.tuiState.SetMacro(register, keys)
+		m.tuiState.SaveSoon()
+		return
+	}
+	if m.macro.registers == nil {
+		m.macro.registers = make(map[string][]string)
+	}
+	if len(keys) == 0 {
+		delete(m.macro.registers, register)
+		return
+	}
+	m.macro.registers[register] = append([]string(nil), keys...)
+}
+
+// macroKeyTypes maps tea key names ("enter", "ctrl+b", "shift+tab") back to
+// their key types.
+var macroKeyTypes = func() map[string]tea.KeyType {
+	out := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-256); k < 256; k++ {
+		name := k.String()
+		if name == "" || k == tea.KeyRunes {
+			continue
+		}
+		if _, ok := out[name]; !ok {
+			out[name] = k
+		}
+	}
+	return out
+}()
+
+// parseMacroKey rebuilds the key message whose String() is key.
+func parseMacroKey(key string) (tea.KeyMsg, bool) {
+	if key == "" {
+		return tea.KeyMsg{}, false
+	}
+	alt := false
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok && rest != "" {
+		alt = true
+		key = rest
+	}
+	if keyType, ok := macroKeyTypes[key]; ok {
+		msg := tea.KeyMsg{Type: keyType, Alt: alt}
+		if keyType == tea.KeySpace {
+			msg.Runes = []rune{' '}
+		}
+		return msg, true
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt}, true
+}
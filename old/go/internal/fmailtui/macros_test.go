@@ -0,0 +1,71 @@
+package fmailtui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroRecordAndReplay(t *testing.T) {
+	model := newTestModel(t, Config{})
+
+	model = applyUpdate(t, model, runeKey('Q'))
+	model = applyUpdate(t, model, runeKey('a'))
+	require.Equal(t, "a", model.macro.recording)
+	require.Equal(t, ViewDashboard, model.activeViewID())
+
+	model = applyUpdate(t, model, runeKey('t'))
+	model = applyUpdate(t, model, runeKey('l'))
+	require.Equal(t, ViewLiveTail, model.activeViewID())
+	model = applyUpdate(t, model, runeKey('Q'))
+	require.Empty(t, model.macro.recording)
+	require.Equal(t, []string{"t", "l"}, model.tuiState.Macro("a"))
+
+	model = applyUpdate(t, model, runeKey('D'))
+	require.Equal(t, ViewDashboard, model.activeViewID())
+
+	model = applyUpdate(t, model, runeKey('@'))
+	next, cmd := model.Update(runeKey('a'))
+	model = next.(*Model)
+	require.NotNil(t, cmd)
+	step, ok := cmd().(macroStepMsg)
+	require.True(t, ok)
+	require.Equal(t, "a", model.macro.playing)
+
+	for step.idx <= len(step.keys) {
+		model = applyUpdate(t, model, step)
+		step.idx++
+	}
+	require.Equal(t, ViewLiveTail, model.activeViewID())
+	require.Empty(t, model.macro.playing)
+	require.Equal(t, "a", model.macro.lastPlayed)
+}
+
+func TestMacroReplayInterruptedByKeypress(t *testing.T) {
+	model := newTestModel(t, Config{})
+	model.tuiState.SetMacro("b", []string{"t", "l"})
+
+	model = applyUpdate(t, model, runeKey('@'))
+	next, cmd := model.Update(runeKey('b'))
+	model = next.(*Model)
+	step := cmd().(macroStepMsg)
+	model = applyUpdate(t, model, step)
+	require.Equal(t, ViewTopics, model.activeViewID())
+
+	model = applyUpdate(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	require.Empty(t, model.macro.playing)
+	step.idx = 1
+	model = applyUpdate(t, model, step)
+	require.Equal(t, ViewTopics, model.activeViewID())
+}
+
+func TestParseMacroKeyRoundTrip(t *testing.T) {
+	for _, key := range []string{"j", "G", "enter", "esc", "ctrl+b", "shift+tab", "alt+x", " ", "pgdown", "ctrl+\\"} {
+		msg, ok := parseMacroKey(key)
+		require.True(t, ok, key)
+		require.Equal(t, key, msg.String())
+	}
+	_, ok := parseMacroKey("")
+	require.False(t, ok)
+}
@@ -0,0 +1,92 @@
+package fmailtui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	tuistate "github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+// snoozePresets are the durations the snooze key steps through; stepping
+// past the last one clears the snooze.
+var snoozePresets = []time.Duration{time.Hour, 4 * time.Hour, 24 * time.Hour}
+
+// muteTarget is the mute key for a message: "@peer" for DMs involving self,
+// the topic name otherwise.
+func muteTarget(self string, msg fmail.Message) string {
+	to := strings.TrimSpace(msg.To)
+	if !strings.HasPrefix(to, "@") {
+		return to
+	}
+	if peer := dmPeerForSelf(self, msg); peer != "" {
+		return "@" + peer
+	}
+	return to
+}
+
+func targetMuted(st *tuistate.Manager, target string, now time.Time) bool {
+	if st == nil {
+		return false
+	}
+	return st.IsMuted(target, now)
+}
+
+// toggleMute mutes target indefinitely, or clears an existing mute or
+// snooze, and returns a status line.
+func toggleMute(st *tuistate.Manager, target string, now time.Time) string {
+	if st == nil || strings.TrimSpace(target) == "" {
+		return ""
+	}
+	defer st.SaveSoon()
+	if _, ok := st.Mute(target, now); ok {
+		st.Unmute(target)
+		return "unmuted " + target
+	}
+	st.SetMute(target, time.Time{})
+	return "muted " + target
+}
+
+// cycleSnooze steps target through snoozePresets and then back to unmuted.
+// An indefinite mute becomes the shortest snooze.
+func cycleSnooze(st *tuistate.Manager, target string, now time.Time) string {
+	if st == nil || strings.TrimSpace(target) == "" {
+		return ""
+	}
+	defer st.SaveSoon()
+	next := snoozePresets[0]
+	if mute, ok := st.Mute(target, now); ok && mute.Snoozed() {
+		current := mute.Until.Sub(mute.MutedAt)
+		next = 0
+		for _, preset := range snoozePresets {
+			if preset > current+time.Minute {
+				next = preset
+				break
+			}
+		}
+	}
+	if next == 0 {
+		st.Unmute(target)
+		return "unsnoozed " + target
+	}
+	until := now.Add(next)
+	st.SetMute(target, until)
+	return fmt.Sprintf("snoozed %s until %s", target, until.Local().Format("Mon 15:04"))
+}
+
+// muteLabel describes the mute on target for listings: "muted",
+// "zzz 15:04", or "" when it is not muted.
+func muteLabel(st *tuistate.Manager, target string, now time.Time) string {
+	if st == nil {
+		return ""
+	}
+	mute, ok := st.Mute(target, now)
+	if !ok {
+		return ""
+	}
+	if mute.Snoozed() {
+		return "zzz " + mute.Until.Local().Format("15:04")
+	}
+	return "muted"
+}
@@ -0,0 +1,90 @@
+package fmailtui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/data"
+	tuistate "github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+func TestMuteTargetUsesPeerForDMs(t *testing.T) {
+	require.Equal(t, "task", muteTarget("me", fmail.Message{From: "bob", To: "task"}))
+	require.Equal(t, "@bob", muteTarget("me", fmail.Message{From: "bob", To: "@me"}))
+	require.Equal(t, "@bob", muteTarget("me", fmail.Message{From: "me", To: "@bob"}))
+}
+
+func TestCycleSnoozeStepsThroughPresets(t *testing.T) {
+	st := tuistate.New(filepath.Join(t.TempDir(), "tui-state.json"))
+	now := time.Now().UTC()
+
+	require.Equal(t, "muted task", toggleMute(st, "task", now))
+	require.Equal(t, "muted", muteLabel(st, "task", now))
+
+	for _, preset := range snoozePresets {
+		status := cycleSnooze(st, "task", now)
+		require.True(t, strings.HasPrefix(status, "snoozed task until"), status)
+		mute, ok := st.Mute("task", now)
+		require.True(t, ok)
+		require.WithinDuration(t, now.Add(preset), mute.Until, time.Second)
+		require.True(t, strings.HasPrefix(muteLabel(st, "task", now), "zzz "))
+	}
+	require.Equal(t, "unsnoozed task", cycleSnooze(st, "task", now))
+	require.False(t, st.IsMuted("task", now))
+
+	cycleSnooze(st, "task", now)
+	require.Equal(t, "unmuted task", toggleMute(st, "task", now))
+	require.Empty(t, muteLabel(st, "task", now))
+}
+
+func TestMutedTargetsSkipUnreadCountsAndNotifications(t *testing.T) {
+	now := time.Date(2026, 2, 9, 11, 0, 0, 0, time.UTC)
+	provider := &stubTopicsProvider{
+		topics: []data.TopicInfo{{Name: "task"}, {Name: "noise"}},
+		dms:    []data.DMConversation{{Agent: "bob"}},
+		byTopic: map[string][]fmail.Message{
+			"task":  {{ID: "20260209-100000-0001", From: "carol", To: "task", Time: now, Body: "@me look"}},
+			"noise": {{ID: "20260209-100100-0001", From: "dave", To: "noise", Time: now, Body: "@me spam"}},
+		},
+		byDM: map[string][]fmail.Message{
+			"bob": {{ID: "20260209-100200-0001", From: "bob", To: "@me", Time: now, Body: "dm"}},
+		},
+	}
+	st := tuistate.New(filepath.Join(t.TempDir(), "tui-state.json"))
+	st.SetMute("noise", time.Time{})
+
+	inbox := newInboxView("me", provider, st)
+	inbox.Update(inbox.loadCmd()())
+	require.Len(t, inbox.items, 3)
+	require.Equal(t, 2, inbox.unreadCount())
+	require.Equal(t, "noise", inbox.visible[2].target, "muted threads sort with read ones")
+
+	inbox.selected = 0
+	require.Equal(t, "@bob", inbox.visible[0].target)
+	inbox.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	require.True(t, st.IsMuted("@bob", time.Now().UTC()))
+	require.Equal(t, 1, inbox.unreadCount())
+
+	center := newNotificationCenter("me", st)
+	_, ok := center.ProcessMessage(fmail.Message{ID: "20260209-100300-0001", From: "bob", To: "@me", Priority: fmail.PriorityHigh})
+	require.False(t, ok, "snoozed DM should not notify")
+	_, ok = center.ProcessMessage(fmail.Message{ID: "20260209-100400-0001", From: "carol", To: "@me", Priority: fmail.PriorityHigh})
+	require.True(t, ok)
+
+	topics := newTopicsView(t.TempDir(), provider, st)
+	topics.topics = provider.topics
+	topics.rebuildItems()
+	for _, item := range topics.items {
+		if item.target == "noise" {
+			require.Equal(t, "muted", item.muted)
+		} else {
+			require.Empty(t, item.muted)
+		}
+	}
+}
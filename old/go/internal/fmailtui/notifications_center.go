@@ -146,6 +146,9 @@ func (c *notificationCenter) ProcessMessage(msg fmail.Message) (notificationActi
 	if _, dup := c.seen[id]; dup {
 		return notificationActions{}, false
 	}
+	if targetMuted(c.state, muteTarget(c.self, msg), time.Now().UTC()) {
+		return notificationActions{}, false
+	}
 
 	matches := make([]compiledNotificationRule, 0, 2)
 	for _, rule := range c.index {
@@ -1,6 +1,7 @@
 package fmailtui
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -181,54 +182,40 @@ func (v *operatorView) handleSlashCommand(input string) (tea.Cmd, bool) {
 			v.statusErr = fmt.Errorf("usage: /group create <name> <agents...> | /group <name> <msg>")
 			return nil, true
 		}
+		store, err := v.groupStore()
+		if err != nil {
+			v.statusErr = err
+			return nil, true
+		}
 		if strings.EqualFold(args[0], "create") {
 			if len(args) < 3 {
 				v.statusErr = fmt.Errorf("usage: /group create <name> <agents...>")
 				return nil, true
 			}
-			name := strings.TrimSpace(args[1])
-			if name == "" {
-				v.statusErr = fmt.Errorf("usage: /group create <name> <agents...>")
+			group, err := store.SaveGroup(args[1], args[2:], v.self)
+			if err != nil {
+				v.statusErr = err
 				return nil, true
 			}
-			members := make([]string, 0, len(args)-2)
-			for _, raw := range args[2:] {
-				members = append(members, normalizeAgentTarget(raw))
-			}
-			v.groups[name] = dedupeAndSort(members)
-			if v.tuiState != nil {
-				v.tuiState.SetGroup(name, v.groups[name])
-				v.tuiState.SaveSoon()
-			}
 			v.statusErr = nil
-			v.statusLine = fmt.Sprintf("group %s saved (%d members)", name, len(v.groups[name]))
+			v.statusLine = fmt.Sprintf("group #%s saved (%d members)", group.Name, len(group.Members))
 			return nil, true
 		}
 		if len(args) < 2 {
 			v.statusErr = fmt.Errorf("usage: /group <name> <msg>")
 			return nil, true
 		}
-		name := strings.TrimSpace(args[0])
-		members := v.groups[name]
-		if len(members) == 0 {
-			if v.tuiState != nil {
-				members = v.tuiState.Groups()[name]
-			}
-			if len(members) == 0 {
-				v.statusErr = fmt.Errorf("unknown group %q", name)
-				return nil, true
-			}
+		group, err := v.resolveGroup(store, args[0])
+		if err != nil {
+			v.statusErr = err
+			return nil, true
 		}
 		body := strings.TrimSpace(strings.Join(args[1:], " "))
 		if body == "" {
 			v.statusErr = fmt.Errorf("usage: /group <name> <msg>")
 			return nil, true
 		}
-		reqs := make([]data.SendRequest, 0, len(members))
-		for _, member := range members {
-			reqs = append(reqs, v.newRequest(member, body, "", v.composePriority, v.composeTags))
-		}
-		return v.sendRequests(reqs), true
+		return v.sendRequests([]data.SendRequest{v.newRequest(fmail.GroupTargetPrefix+group.Name, body, "", v.composePriority, v.composeTags)}), true
 	case "mystatus":
 		status := strings.TrimSpace(strings.TrimPrefix(input, "/mystatus"))
 		if status == "" {
@@ -328,23 +315,24 @@ func normalizeAgentTarget(value string) string {
 	return "@" + value
 }
 
-func dedupeAndSort(items []string) []string {
-	if len(items) == 0 {
-		return nil
+func (v *operatorView) groupStore() (*fmail.Store, error) {
+	if v.store != nil {
+		return v.store, nil
 	}
-	seen := map[string]struct{}{}
-	out := make([]string, 0, len(items))
-	for _, item := range items {
-		item = strings.TrimSpace(item)
-		if item == "" {
-			continue
-		}
-		if _, ok := seen[item]; ok {
-			continue
+	return fmail.NewStore(v.root)
+}
+
+// resolveGroup looks a group up in the store. Groups saved in TUI state by
+// older versions are promoted to the store on first use.
+func (v *operatorView) resolveGroup(store *fmail.Store, name string) (*fmail.Group, error) {
+	group, err := store.ReadGroup(name)
+	if !errors.Is(err, fmail.ErrGroupNotFound) {
+		return group, err
+	}
+	if v.tuiState != nil {
+		if members := v.tuiState.Groups()[strings.TrimPrefix(strings.TrimSpace(name), fmail.GroupTargetPrefix)]; len(members) > 0 {
+			return store.SaveGroup(name, members, v.self)
 		}
-		seen[item] = struct{}{}
-		out = append(out, item)
 	}
-	sort.Strings(out)
-	return out
+	return nil, fmt.Errorf("unknown group %q", name)
 }
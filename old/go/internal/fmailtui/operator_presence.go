@@ -0,0 +1,138 @@
+package fmailtui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+// operatorPresenceHistory is how many past status changes the presence
+// sidebar shows per agent.
+const operatorPresenceHistory = 3
+
+func (v *operatorView) togglePresenceSidebar() {
+	v.presenceExpanded = !v.presenceExpanded
+}
+
+func (v *operatorView) togglePresenceOnlineOnly() {
+	v.presenceOnlineOnly = !v.presenceOnlineOnly
+	if v.presenceOnlineOnly {
+		v.statusLine = "presence: online agents only"
+	} else {
+		v.statusLine = "presence: all agents"
+	}
+}
+
+// presenceRecords returns the agents to show in the ticker or sidebar:
+// online agents first, then by name, honoring the online-only filter.
+func (v *operatorView) presenceRecords(now time.Time) []fmail.AgentRecord {
+	records := make([]fmail.AgentRecord, 0, len(v.agents))
+	for _, rec := range v.agents {
+		if v.presenceOnlineOnly && now.Sub(rec.LastSeen) > operatorActiveWindow {
+			continue
+		}
+		records = append(records, rec)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		iActive := now.Sub(records[i].LastSeen) <= operatorActiveWindow
+		jActive := now.Sub(records[j].LastSeen) <= operatorActiveWindow
+		if iActive != jActive {
+			return iActive
+		}
+		return records[i].Name < records[j].Name
+	})
+	return records
+}
+
+// renderMainArea renders the conversation area, with the presence sidebar
+// on the right when it is expanded.
+func (v *operatorView) renderMainArea(width, height int, palette styles.Theme) string {
+	if !v.presenceExpanded {
+		return v.renderConversationArea(width, height, palette)
+	}
+	presenceW := minInt(36, maxInt(24, width/4))
+	mainW := maxInt(16, width-presenceW-1)
+	main := v.renderConversationArea(mainW, height, palette)
+	presence := v.renderPresenceSidebar(presenceW, height, palette)
+	return lipgloss.JoinHorizontal(lipgloss.Top, main, " ", presence)
+}
+
+func (v *operatorView) renderPresenceSidebar(width, height int, palette styles.Theme) string {
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	innerW := maxInt(0, width-2)
+
+	title := "Agents"
+	if v.presenceOnlineOnly {
+		title += " (online)"
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(palette.Chrome.Breadcrumb)).Render(title)
+	if v.unreadTotal > 0 {
+		header += muted.Render(fmt.Sprintf("  [N:%d]", v.unreadTotal))
+	}
+	lines := []string{header}
+
+	now := time.Now().UTC()
+	records := v.presenceRecords(now)
+	if len(records) == 0 {
+		empty := "No agents"
+		if v.presenceOnlineOnly && len(v.agents) > 0 {
+			empty = "No agents online"
+		}
+		lines = append(lines, muted.Render(empty))
+	}
+	for _, rec := range records {
+		dot := "○"
+		if now.Sub(rec.LastSeen) <= operatorActiveWindow {
+			dot = "●"
+		}
+		name := dot + " " + rec.Name
+		if host := strings.TrimSpace(rec.Host); host != "" {
+			name += muted.Render(" " + host)
+		}
+		lines = append(lines, truncateVis(name, innerW))
+
+		status := strings.TrimSpace(rec.Status)
+		if status == "" {
+			status = "seen " + relativeTime(rec.LastSeen, now)
+			lines = append(lines, muted.Render(truncateVis("  "+status, innerW)))
+		} else {
+			lines = append(lines, truncateVis("  "+status, innerW))
+		}
+
+		for _, change := range presenceHistoryTail(rec.StatusHistory) {
+			label := strings.TrimSpace(change.Status)
+			if label == "" {
+				label = "(cleared)"
+			}
+			lines = append(lines, muted.Render(truncateVis("  "+change.At.Local().Format("15:04")+" "+label, innerW)))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(palette.Borders.Divider)).
+		Padding(0, 1).
+		Width(width).
+		Height(height).
+		Render(clampLines(strings.Join(lines, "\n"), maxInt(0, height-2)))
+}
+
+// presenceHistoryTail returns the most recent status changes before the
+// current one, newest first.
+func presenceHistoryTail(history []fmail.AgentStatusChange) []fmail.AgentStatusChange {
+	if len(history) <= 1 {
+		return nil
+	}
+	past := history[:len(history)-1]
+	out := make([]fmail.AgentStatusChange, 0, operatorPresenceHistory)
+	for i := len(past) - 1; i >= 0 && len(out) < operatorPresenceHistory; i-- {
+		out = append(out, past[i])
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package fmailtui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+// operatorSplitPane holds the unfocused conversation while the operator view
+// is split. The focused conversation always lives in the view's own fields,
+// so scrolling, compose and slash commands act on it unchanged; switching
+// focus swaps the two.
+type operatorSplitPane struct {
+	target     string
+	messages   []fmail.Message
+	replyIndex map[string]fmail.Message
+	scroll     int
+	follow     bool
+	compose    string
+}
+
+type operatorSplitLoadedMsg struct {
+	target     string
+	messages   []fmail.Message
+	replyIndex map[string]fmail.Message
+	err        error
+}
+
+// toggleSplit opens a second conversation pane to the right of the current
+// one, or closes it and keeps the focused conversation.
+func (v *operatorView) toggleSplit() tea.Cmd {
+	if v.split != nil {
+		v.split = nil
+		v.splitFocusRight = false
+		v.statusErr = nil
+		v.statusLine = "split closed"
+		return nil
+	}
+	target := v.nextSplitTarget()
+	if target == "" {
+		v.statusErr = fmt.Errorf("no other conversation to open")
+		return nil
+	}
+	v.split = &operatorSplitPane{follow: true, replyIndex: map[string]fmail.Message{}}
+	v.swapSplit()
+	v.splitFocusRight = true
+	v.target = target
+	v.syncSelectedTarget()
+	v.statusErr = nil
+	v.statusLine = "split: Tab switches pane"
+	return v.loadCmd()
+}
+
+// toggleSplitFocus moves focus (and compose) to the other pane.
+func (v *operatorView) toggleSplitFocus() {
+	if v.split == nil {
+		return
+	}
+	v.swapSplit()
+	v.splitFocusRight = !v.splitFocusRight
+	v.syncSelectedTarget()
+}
+
+// focusSplitTarget retargets the focused pane. Picking the other pane's
+// conversation moves focus there instead of showing it twice.
+func (v *operatorView) focusSplitTarget(target string) tea.Cmd {
+	if v.split != nil && v.split.target == target {
+		v.toggleSplitFocus()
+		return nil
+	}
+	v.target = target
+	v.scroll = 0
+	v.follow = true
+	return v.loadCmd()
+}
+
+func (v *operatorView) swapSplit() {
+	pane := v.split
+	pane.target, v.target = v.target, pane.target
+	pane.messages, v.messages = v.messages, pane.messages
+	pane.replyIndex, v.replyIndex = v.replyIndex, pane.replyIndex
+	pane.scroll, v.scroll = v.scroll, pane.scroll
+	pane.follow, v.follow = v.follow, pane.follow
+	pane.compose, v.compose = v.compose, pane.compose
+	if v.replyIndex == nil {
+		v.replyIndex = map[string]fmail.Message{}
+	}
+}
+
+func (v *operatorView) nextSplitTarget() string {
+	current := strings.TrimSpace(v.target)
+	for _, candidate := range v.quickTargets {
+		if candidate != current {
+			return candidate
+		}
+	}
+	for _, conv := range v.convs {
+		if conv.Target != current {
+			return conv.Target
+		}
+	}
+	return ""
+}
+
+func (v *operatorView) syncSelectedTarget() {
+	for idx := range v.convs {
+		if v.convs[idx].Target == v.target {
+			v.selected = idx
+			return
+		}
+	}
+}
+
+func (v *operatorView) splitLoadCmd() tea.Cmd {
+	if v.split == nil || strings.TrimSpace(v.split.target) == "" {
+		return nil
+	}
+	provider := v.provider
+	self := v.self
+	target := v.split.target
+	return func() tea.Msg {
+		if provider == nil {
+			return operatorSplitLoadedMsg{target: target, err: fmt.Errorf("missing provider")}
+		}
+		messages, replyIndex, err := loadOperatorMessages(provider, target, self)
+		return operatorSplitLoadedMsg{target: target, messages: messages, replyIndex: replyIndex, err: err}
+	}
+}
+
+func (v *operatorView) applySplitLoaded(msg operatorSplitLoadedMsg) {
+	if msg.err != nil {
+		v.statusErr = msg.err
+		return
+	}
+	if v.split == nil || v.split.target != strings.TrimSpace(msg.target) {
+		return
+	}
+	v.split.messages = msg.messages
+	v.split.replyIndex = msg.replyIndex
+	if v.split.follow || len(v.split.messages) == 0 {
+		v.split.scroll = 0
+	}
+	if v.tuiState != nil && len(msg.messages) > 0 {
+		if last := strings.TrimSpace(msg.messages[len(msg.messages)-1].ID); last != "" {
+			v.tuiState.SetReadMarker(v.split.target, last)
+			v.tuiState.SaveSoon()
+		}
+	}
+}
+
+// appendSplitIncoming adds a live message to the unfocused pane when it shows
+// the message's conversation.
+func (v *operatorView) appendSplitIncoming(target string, msg fmail.Message) bool {
+	if v.split == nil || v.split.target != target {
+		return false
+	}
+	v.split.messages = append(v.split.messages, msg)
+	if id := strings.TrimSpace(msg.ID); id != "" {
+		if v.split.replyIndex == nil {
+			v.split.replyIndex = map[string]fmail.Message{}
+		}
+		v.split.replyIndex[id] = msg
+		v.markRead(target, id)
+	}
+	if v.split.follow {
+		v.split.scroll = 0
+	}
+	return true
+}
+
+func (v *operatorView) renderSplitPanes(width, height int, palette styles.Theme) string {
+	leftW := maxInt(16, (width-1)/2)
+	rightW := maxInt(16, width-leftW-1)
+	focusedW, otherW := leftW, rightW
+	if v.splitFocusRight {
+		focusedW, otherW = rightW, leftW
+	}
+
+	focused := v.renderConversationPanel(focusedW, height, palette, true)
+	// Render the other pane through the same code path by swapping it in.
+	v.swapSplit()
+	other := v.renderConversationPanel(otherW, height, palette, false)
+	v.swapSplit()
+
+	if v.splitFocusRight {
+		return lipgloss.JoinHorizontal(lipgloss.Top, other, " ", focused)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, focused, " ", other)
+}
@@ -82,6 +82,10 @@ type operatorView struct {
 	agents           []fmail.AgentRecord
 	unreadTotal      int
 	lastLoaded       time.Time
+	refreshInterval  time.Duration
+
+	presenceExpanded   bool
+	presenceOnlineOnly bool
 
 	compose          string
 	composePriority  string
@@ -89,10 +93,12 @@ type operatorView struct {
 	composeMultiline bool
 	showPalette      bool
 
-	groups         map[string][]string
 	pendingApprove string
 	waitingSince   map[string]time.Time
 
+	split           *operatorSplitPane
+	splitFocusRight bool
+
 	statusLine string
 	statusErr  error
 
@@ -100,8 +106,20 @@ type operatorView struct {
 	cancel func()
 }
 
-func operatorTickCmd() tea.Cmd {
-	return tea.Tick(operatorRefreshInterval, func(time.Time) tea.Msg { return operatorTickMsg{} })
+func (v *operatorView) tickCmd() tea.Cmd {
+	interval := v.refreshInterval
+	if interval <= 0 {
+		interval = operatorRefreshInterval
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return operatorTickMsg{} })
+}
+
+func (v *operatorView) SetRefreshInterval(interval time.Duration) {
+	v.refreshInterval = interval
+}
+
+func (v *operatorView) ForceRefresh() tea.Cmd {
+	return v.loadCmd()
 }
 
 func operatorPresenceTickCmd() tea.Cmd {
@@ -120,23 +138,16 @@ func newOperatorView(root, projectID, self string, store *fmail.Store, provider
 		tuiState:        st,
 		follow:          true,
 		composePriority: fmail.PriorityNormal,
-		groups:          map[string][]string{},
 		waitingSince:    map[string]time.Time{},
 		replyIndex:      map[string]fmail.Message{},
 	}
-	if st != nil {
-		v.groups = st.Groups()
-		if v.groups == nil {
-			v.groups = map[string][]string{}
-		}
-	}
 	return v
 }
 
 func (v *operatorView) Init() tea.Cmd {
 	v.startSubscription()
 	v.touchPresence("")
-	return tea.Batch(v.loadCmd(), operatorTickCmd(), operatorPresenceTickCmd(), v.waitForMessageCmd())
+	return tea.Batch(v.loadCmd(), v.tickCmd(), operatorPresenceTickCmd(), v.waitForMessageCmd())
 }
 
 func (v *operatorView) Close() {
@@ -150,13 +161,16 @@ func (v *operatorView) Close() {
 func (v *operatorView) Update(msg tea.Msg) tea.Cmd {
 	switch typed := msg.(type) {
 	case operatorTickMsg:
-		return tea.Batch(v.loadCmd(), operatorTickCmd())
+		return tea.Batch(v.loadCmd(), v.splitLoadCmd(), v.tickCmd())
 	case operatorPresenceTickMsg:
 		v.touchPresence("")
 		return operatorPresenceTickCmd()
 	case operatorLoadedMsg:
 		v.applyLoaded(typed)
 		return nil
+	case operatorSplitLoadedMsg:
+		v.applySplitLoaded(typed)
+		return nil
 	case operatorIncomingMsg:
 		v.handleIncoming(typed.msg)
 		return v.waitForMessageCmd()
@@ -173,7 +187,7 @@ func (v *operatorView) Update(msg tea.Msg) tea.Cmd {
 				v.waitingSince[sent.ID] = sent.Time
 			}
 		}
-		return v.loadCmd()
+		return tea.Batch(v.loadCmd(), v.splitLoadCmd())
 	case tea.WindowSizeMsg:
 		v.width = typed.Width
 		v.height = typed.Height
@@ -188,7 +202,7 @@ func (v *operatorView) View(width, height int, theme Theme) string {
 	v.width = width
 	v.height = height
 
-	palette, ok := styles.Themes[string(theme)]
+	palette, ok := styles.LookupTheme(string(theme))
 	if !ok {
 		palette = styles.DefaultTheme
 	}
@@ -198,13 +212,20 @@ func (v *operatorView) View(width, height int, theme Theme) string {
 
 	composePanel := v.renderComposePanel(width, palette)
 	quick := v.renderQuickActions(width, palette)
-	ticker := v.renderStatusTicker(width, palette)
-
-	reserved := lipgloss.Height(composePanel) + lipgloss.Height(quick) + lipgloss.Height(ticker)
+	reserved := lipgloss.Height(composePanel) + lipgloss.Height(quick)
+	ticker := ""
+	if !v.presenceExpanded {
+		ticker = v.renderStatusTicker(width, palette)
+		reserved += lipgloss.Height(ticker)
+	}
 	conversationHeight := maxInt(4, height-reserved)
-	conversation := v.renderConversationArea(width, conversationHeight, palette)
+	conversation := v.renderMainArea(width, conversationHeight, palette)
 
-	parts := []string{conversation, quick, ticker, composePanel}
+	parts := []string{conversation, quick}
+	if ticker != "" {
+		parts = append(parts, ticker)
+	}
+	parts = append(parts, composePanel)
 	if v.showPalette {
 		parts = append(parts, v.renderCommandPalette(width, palette))
 	}
@@ -260,10 +281,26 @@ func (v *operatorView) handleKey(msg tea.KeyMsg) tea.Cmd {
 	case "ctrl+b":
 		v.sidebarCollapsed = !v.sidebarCollapsed
 		return nil
+	case "ctrl+e":
+		v.togglePresenceSidebar()
+		return nil
+	case "ctrl+f":
+		v.togglePresenceOnlineOnly()
+		return nil
+	case "ctrl+o":
+		return v.toggleSplit()
 	case "tab":
+		if v.split != nil {
+			v.toggleSplitFocus()
+			return nil
+		}
 		v.selectConversation(1)
 		return v.loadCmd()
 	case "shift+tab":
+		if v.split != nil {
+			v.toggleSplitFocus()
+			return nil
+		}
 		v.selectConversation(-1)
 		return v.loadCmd()
 	case "up":
@@ -352,10 +389,7 @@ func (v *operatorView) handleKey(msg tea.KeyMsg) tea.Cmd {
 		if r >= '1' && r <= '9' && strings.TrimSpace(v.compose) == "" {
 			idx := int(r - '1')
 			if idx >= 0 && idx < len(v.quickTargets) {
-				v.target = v.quickTargets[idx]
-				v.scroll = 0
-				v.follow = true
-				return v.loadCmd()
+				return v.focusSplitTarget(v.quickTargets[idx])
 			}
 		}
 	}
@@ -452,6 +486,12 @@ func (v *operatorView) applyLoaded(msg operatorLoadedMsg) {
 	v.quickTargets = msg.quick
 	v.unreadTotal = msg.unread
 	v.agents = msg.agents
+	if v.split != nil && msg.target != v.target && msg.target == v.split.target {
+		// Focus moved to the other pane while this load was in flight.
+		v.applySplitLoaded(operatorSplitLoadedMsg{target: msg.target, messages: msg.messages, replyIndex: msg.replyIndex})
+		v.syncSelectedTarget()
+		return
+	}
 	v.selected = clampInt(msg.selected, 0, maxInt(0, len(v.convs)-1))
 	v.target = strings.TrimSpace(msg.target)
 	v.messages = msg.messages
@@ -479,7 +519,7 @@ func (v *operatorView) handleIncoming(msg fmail.Message) {
 			continue
 		}
 		v.convs[i].LastActivity = msg.Time
-		if target != strings.TrimSpace(v.target) {
+		if target != strings.TrimSpace(v.target) && (v.split == nil || target != v.split.target) {
 			v.convs[i].Unread++
 			v.unreadTotal++
 		}
@@ -497,7 +537,7 @@ func (v *operatorView) handleIncoming(msg fmail.Message) {
 		if v.follow {
 			v.scroll = 0
 		}
-	} else if strings.HasPrefix(target, "@") && strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(msg.To), "@"), v.self) {
+	} else if !v.appendSplitIncoming(target, msg) && strings.HasPrefix(target, "@") && strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(msg.To), "@"), v.self) {
 		v.statusLine = "\aDM from @" + strings.TrimSpace(msg.From)
 	}
 
@@ -560,17 +600,18 @@ func (v *operatorView) renderConversationArea(width, height int, palette styles.
 		return ""
 	}
 	if v.sidebarCollapsed {
-		return v.renderConversationPanel(width, height, palette)
+		return v.renderConversationPanes(width, height, palette)
 	}
 	sidebarW := minInt(34, maxInt(24, width/4))
 	mainW := maxInt(16, width-sidebarW-1)
 	sidebar := v.renderConversationList(sidebarW, height, palette)
-	main := v.renderConversationPanel(mainW, height, palette)
+	main := v.renderConversationPanes(mainW, height, palette)
 	return lipgloss.JoinHorizontal(lipgloss.Top, sidebar, " ", main)
 }
 
 func (v *operatorView) renderConversationList(width, height int, palette styles.Theme) string {
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(palette.Chrome.Breadcrumb)).Render("Conversations")
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(palette.Chrome.Breadcrumb)).Render("Conversations") +
+		lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render(truncateVis("  "+refreshStampLabel(v.lastLoaded), maxInt(0, width-15)))
 	lines := []string{title}
 	maxRows := maxInt(1, height-1)
 	if len(v.convs) == 0 {
@@ -609,7 +650,14 @@ func (v *operatorView) renderConversationList(width, height int, palette styles.
 		Render(clampLines(strings.Join(lines, "\n"), maxInt(0, height-2)))
 }
 
-func (v *operatorView) renderConversationPanel(width, height int, palette styles.Theme) string {
+func (v *operatorView) renderConversationPanes(width, height int, palette styles.Theme) string {
+	if v.split != nil {
+		return v.renderSplitPanes(width, height, palette)
+	}
+	return v.renderConversationPanel(width, height, palette, true)
+}
+
+func (v *operatorView) renderConversationPanel(width, height int, palette styles.Theme, active bool) string {
 	head := "Operator Console"
 	if target := strings.TrimSpace(v.target); target != "" {
 		head = "Conversation with " + target
@@ -618,9 +666,13 @@ func (v *operatorView) renderConversationPanel(width, height int, palette styles
 	bodyHeight := maxInt(1, height-2)
 	bodyLines := v.renderConversationLines(width-4, bodyHeight, palette)
 	content := append([]string{headLine}, bodyLines...)
+	border := palette.Borders.ActivePane
+	if !active {
+		border = palette.Borders.Divider
+	}
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(palette.Borders.ActivePane)).
+		BorderForeground(lipgloss.Color(border)).
 		Padding(0, 1).
 		Width(width).
 		Height(height).
@@ -733,19 +785,11 @@ func (v *operatorView) renderQuickActions(width int, palette styles.Theme) strin
 }
 
 func (v *operatorView) renderStatusTicker(width int, palette styles.Theme) string {
-	if len(v.agents) == 0 {
+	now := time.Now().UTC()
+	records := v.presenceRecords(now)
+	if len(records) == 0 {
 		return lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render("agents: none")
 	}
-	now := time.Now().UTC()
-	records := append([]fmail.AgentRecord(nil), v.agents...)
-	sort.SliceStable(records, func(i, j int) bool {
-		iActive := now.Sub(records[i].LastSeen) <= operatorActiveWindow
-		jActive := now.Sub(records[j].LastSeen) <= operatorActiveWindow
-		if iActive != jActive {
-			return iActive
-		}
-		return records[i].Name < records[j].Name
-	})
 	parts := make([]string, 0, minInt(len(records), 6))
 	for i, rec := range records {
 		if i >= 6 {
@@ -797,6 +841,11 @@ func (v *operatorView) renderComposePanel(width int, palette styles.Theme) strin
 		"> " + strings.Join(bodyLines, "\n  "),
 		"Enter: send (single-line) | Ctrl+Enter: send | Ctrl+M: toggle multiline | Ctrl+P: commands",
 	}
+	if v.split != nil {
+		content[2] += " | Tab: switch pane | Ctrl+O: close split"
+	} else {
+		content[2] += " | Ctrl+O: split"
+	}
 	return lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
 		BorderForeground(lipgloss.Color(palette.Borders.Divider)).
@@ -1,7 +1,6 @@
 package fmailtui
 
 import (
-	"sort"
 	"testing"
 	"time"
 
@@ -118,22 +117,30 @@ func TestOperatorGroupCreateAndSendPersists(t *testing.T) {
 	provider := &operatorTestProvider{}
 	v := newOperatorView(root, "prj", "viewer", nil, provider, st)
 
-	v.compose = "/group create frontend coder-1 coder-2"
+	v.compose = "/group create frontend coder-1 @coder-2"
 	runOperatorCmd(v, v.submitCompose())
-	require.Contains(t, v.groups, "frontend")
-	require.Equal(t, []string{"@coder-1", "@coder-2"}, v.groups["frontend"])
+	require.NoError(t, v.statusErr)
+
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	group, err := store.ReadGroup("frontend")
+	require.NoError(t, err)
+	require.Equal(t, []string{"coder-1", "coder-2"}, group.Members)
 
 	v.compose = "/group frontend deploy now"
 	runOperatorCmd(v, v.submitCompose())
+	require.Len(t, provider.sent, 1)
+	require.Equal(t, "#frontend", provider.sent[0].To)
+
+	// Groups kept in TUI state by older versions are promoted on use.
+	st.SetGroup("legacy", []string{"@coder-3"})
+	v.compose = "/group legacy hello"
+	runOperatorCmd(v, v.submitCompose())
 	require.Len(t, provider.sent, 2)
-	actualTargets := []string{provider.sent[0].To, provider.sent[1].To}
-	sort.Strings(actualTargets)
-	require.Equal(t, []string{"@coder-1", "@coder-2"}, actualTargets)
-
-	require.NoError(t, st.SaveNow())
-	reloaded := tuistate.New(statePath)
-	require.NoError(t, reloaded.Load())
-	require.Equal(t, []string{"@coder-1", "@coder-2"}, reloaded.Groups()["frontend"])
+	require.Equal(t, "#legacy", provider.sent[1].To)
+	group, err = store.ReadGroup("legacy")
+	require.NoError(t, err)
+	require.Equal(t, []string{"coder-3"}, group.Members)
 }
 
 func TestOperatorLoadConversationsUnread(t *testing.T) {
@@ -177,3 +184,98 @@ func runOperatorCmd(v *operatorView, cmd tea.Cmd) {
 	}
 	_ = v.Update(msg)
 }
+
+func TestOperatorSplitPaneKeepsIndependentScrollAndCompose(t *testing.T) {
+	now := time.Now().UTC()
+	provider := &operatorTestProvider{
+		topics: []data.TopicInfo{{Name: "build", LastActivity: now.Add(-2 * time.Minute)}},
+		dms:    []data.DMConversation{{Agent: "architect", LastActivity: now.Add(-time.Minute)}},
+		topicMessages: map[string][]fmail.Message{
+			"build": {{ID: "20260209-100000-0001", From: "coder", To: "build", Body: "green", Time: now.Add(-2 * time.Minute)}},
+		},
+		dmMessages: map[string][]fmail.Message{
+			"architect": {{ID: "20260209-100000-0002", From: "architect", To: "@viewer", Body: "ping", Time: now.Add(-time.Minute)}},
+		},
+	}
+	v := newOperatorView(t.TempDir(), "prj", "viewer", nil, provider, nil)
+	runOperatorCmd(v, v.loadCmd())
+	require.Equal(t, "@architect", v.target)
+
+	v.scroll = 6
+	v.follow = false
+	v.compose = "draft for architect"
+
+	runOperatorCmd(v, v.Update(tea.KeyMsg{Type: tea.KeyCtrlO}))
+	require.NotNil(t, v.split)
+	require.True(t, v.splitFocusRight)
+	require.Equal(t, "build", v.target)
+	require.Equal(t, "@architect", v.split.target)
+	require.Len(t, v.messages, 1)
+	require.Equal(t, "green", v.messages[0].Body)
+	require.Empty(t, v.compose)
+	require.Equal(t, 6, v.split.scroll)
+
+	runOperatorCmd(v, v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hello build")}))
+	runOperatorCmd(v, v.Update(tea.KeyMsg{Type: tea.KeyTab}))
+	require.Equal(t, "@architect", v.target)
+	require.False(t, v.splitFocusRight)
+	require.Equal(t, "draft for architect", v.compose)
+	require.Equal(t, 6, v.scroll)
+	require.Equal(t, "hello build", v.split.compose)
+
+	view := v.View(120, 30, ThemeDefault)
+	require.Contains(t, view, "Conversation with @architect")
+	require.Contains(t, view, "Conversation with build")
+
+	runOperatorCmd(v, v.Update(tea.KeyMsg{Type: tea.KeyTab}))
+	runOperatorCmd(v, v.submitCompose())
+	require.Len(t, provider.sent, 1)
+	require.Equal(t, "build", provider.sent[0].To)
+
+	v.handleIncoming(fmail.Message{ID: "20260209-100000-0003", From: "architect", To: "@viewer", Body: "pong", Time: now})
+	require.Len(t, v.split.messages, 2)
+	require.Zero(t, v.unreadTotal)
+
+	runOperatorCmd(v, v.Update(tea.KeyMsg{Type: tea.KeyCtrlO}))
+	require.Nil(t, v.split)
+	require.Equal(t, "build", v.target)
+}
+
+func TestOperatorPresenceSidebarShowsHistoryAndFiltersOnline(t *testing.T) {
+	now := time.Now().UTC()
+	provider := &operatorTestProvider{
+		agents: []fmail.AgentRecord{
+			{
+				Name:     "architect",
+				Host:     "build-1",
+				Status:   "reviewing",
+				LastSeen: now,
+				StatusHistory: []fmail.AgentStatusChange{
+					{Status: "planning", At: now.Add(-3 * time.Minute)},
+					{Status: "reviewing", At: now.Add(-time.Minute)},
+				},
+			},
+			{Name: "coder", Status: "asleep", LastSeen: now.Add(-time.Hour)},
+		},
+	}
+	st := tuistate.New(t.TempDir() + "/.fmail/tui-state.json")
+	require.NoError(t, st.Load())
+	v := newOperatorView(t.TempDir(), "prj", "viewer", nil, provider, st)
+	runOperatorCmd(v, v.loadCmd())
+
+	collapsed := v.View(140, 30, ThemeDefault)
+	require.NotContains(t, collapsed, "planning")
+
+	v.handleKey(tea.KeyMsg{Type: tea.KeyCtrlE})
+	require.True(t, v.presenceExpanded)
+	expanded := v.View(140, 30, ThemeDefault)
+	require.Contains(t, expanded, "build-1")
+	require.Contains(t, expanded, "planning")
+	require.Contains(t, expanded, "coder")
+
+	v.handleKey(tea.KeyMsg{Type: tea.KeyCtrlF})
+	require.True(t, v.presenceOnlineOnly)
+	online := v.View(140, 30, ThemeDefault)
+	require.Contains(t, online, "architect")
+	require.NotContains(t, online, "coder")
+}
@@ -0,0 +1,258 @@
+package fmailtui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+// quickReactions are the emoji offered by the reaction picker; digits 1-9
+// select them in order.
+var quickReactions = []string{"👍", "👀", "✅", "🚀", "🎉", "❤️", "😄", "🤔", "🔥"}
+
+// loadReactionCounts returns the aggregated reactions of the given messages,
+// keyed by message ID. Messages without reactions are omitted.
+func loadReactionCounts(root string, msgs []fmail.Message) map[string][]fmail.ReactionCount {
+	if strings.TrimSpace(root) == "" || len(msgs) == 0 {
+		return nil
+	}
+	store, err := fmail.NewStore(root)
+	if err != nil {
+		return nil
+	}
+	all, err := store.ListAllReactions()
+	if err != nil || len(all) == 0 {
+		return nil
+	}
+	out := make(map[string][]fmail.ReactionCount)
+	for _, msg := range msgs {
+		id := strings.TrimSpace(msg.ID)
+		if reactions, ok := all[id]; ok {
+			out[id] = fmail.AggregateReactions(reactions)
+		}
+	}
+	return out
+}
+
+// quickReactionForKey maps a picker key ("1".."9") to its emoji.
+func quickReactionForKey(key string) (string, bool) {
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return "", false
+	}
+	idx := int(key[0] - '1')
+	if idx >= len(quickReactions) {
+		return "", false
+	}
+	return quickReactions[idx], true
+}
+
+// toggleMessageReaction toggles self's emoji reaction on a message and
+// returns a status line plus the message's updated counts.
+func toggleMessageReaction(root, id, emoji, self string) (string, []fmail.ReactionCount, error) {
+	store, err := reactionStore(root, id)
+	if err != nil {
+		return "", nil, err
+	}
+	added, err := store.ToggleReaction(id, emoji, self)
+	if err != nil {
+		return "", nil, err
+	}
+	counts, err := messageReactionCounts(store, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if added {
+		return "reacted " + emoji, counts, nil
+	}
+	return "removed " + emoji, counts, nil
+}
+
+// clearMessageReactions removes every reaction self left on a message.
+func clearMessageReactions(root, id, self string) (string, []fmail.ReactionCount, error) {
+	store, err := reactionStore(root, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if normalized, err := fmail.NormalizeAgentName(self); err == nil {
+		self = normalized
+	}
+	reactions, err := store.ListReactions(id)
+	if err != nil {
+		return "", nil, err
+	}
+	removed := 0
+	for _, reaction := range reactions {
+		if reaction.From != self {
+			continue
+		}
+		if err := store.RemoveReaction(id, reaction.Emoji, self); err != nil && !errors.Is(err, fmail.ErrReactionNotFound) {
+			return "", nil, err
+		}
+		removed++
+	}
+	counts, err := messageReactionCounts(store, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if removed == 0 {
+		return "no reactions to clear", counts, nil
+	}
+	return fmt.Sprintf("cleared %d reaction(s)", removed), counts, nil
+}
+
+func reactionStore(root, id string) (*fmail.Store, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, fmt.Errorf("root not set")
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("no message selected")
+	}
+	return fmail.NewStore(root)
+}
+
+func messageReactionCounts(store *fmail.Store, id string) ([]fmail.ReactionCount, error) {
+	reactions, err := store.ListReactions(id)
+	if err != nil {
+		return nil, err
+	}
+	return fmail.AggregateReactions(reactions), nil
+}
+
+// setReactionCounts stores counts for id, dropping the entry when empty.
+func setReactionCounts(reactions map[string][]fmail.ReactionCount, id string, counts []fmail.ReactionCount) map[string][]fmail.ReactionCount {
+	if reactions == nil {
+		reactions = make(map[string][]fmail.ReactionCount)
+	}
+	if len(counts) == 0 {
+		delete(reactions, id)
+		return reactions
+	}
+	reactions[id] = counts
+	return reactions
+}
+
+func reactionCountsEqual(a, b map[string][]fmail.ReactionCount) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, left := range a {
+		right, ok := b[id]
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		for i := range left {
+			if left[i].Emoji != right[i].Emoji || left[i].Count != right[i].Count || strings.Join(left[i].From, ",") != strings.Join(right[i].From, ",") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// renderReactionPills renders counts as "👍2 👀1", accenting the emoji self
+// has reacted with.
+func renderReactionPills(counts []fmail.ReactionCount, self string, palette styles.Theme) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	parts := make([]string, 0, len(counts))
+	for _, count := range counts {
+		style := muted
+		for _, from := range count.From {
+			if strings.EqualFold(from, self) {
+				style = accent
+				break
+			}
+		}
+		parts = append(parts, style.Render(fmt.Sprintf("%s%d", count.Emoji, count.Count)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderReactionPicker renders the one-line quick reaction prompt.
+func renderReactionPicker(width int, palette styles.Theme) string {
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	parts := make([]string, 0, len(quickReactions))
+	for i, emoji := range quickReactions {
+		parts = append(parts, fmt.Sprintf("%d %s", i+1, emoji))
+	}
+	return truncateVis(accent.Render("React")+muted.Render(" (Esc cancel)  ")+strings.Join(parts, "  "), width)
+}
+
+// handleReactKey handles keys while the reaction picker is open: 1-9 toggle
+// a quick reaction on the targeted message, anything else closes the picker.
+func (v *threadView) handleReactKey(msg tea.KeyMsg) {
+	id := v.reactID
+	v.reactID = ""
+	emoji, ok := quickReactionForKey(msg.String())
+	if !ok {
+		return
+	}
+	status, counts, err := toggleMessageReaction(v.root, id, emoji, v.self)
+	v.applyReactionResult(id, status, counts, err)
+}
+
+// clearReactions removes all of self's reactions on the selected message.
+func (v *threadView) clearReactions() {
+	id := v.selectedID()
+	status, counts, err := clearMessageReactions(v.root, id, v.self)
+	v.applyReactionResult(id, status, counts, err)
+}
+
+func (v *threadView) applyReactionResult(id, status string, counts []fmail.ReactionCount, err error) {
+	if err != nil {
+		v.statusLine = "react failed: " + err.Error()
+		v.statusErr = true
+		return
+	}
+	v.reactions = setReactionCounts(v.reactions, id, counts)
+	v.rowCardCache = nil
+	v.statusLine = status
+	v.statusErr = false
+}
+
+func (v *timelineView) openReactionPicker() {
+	if msg, ok := v.selectedMessage(); ok {
+		v.reactID = strings.TrimSpace(msg.ID)
+	}
+}
+
+// handleReactKey mirrors threadView.handleReactKey for the timeline.
+func (v *timelineView) handleReactKey(msg tea.KeyMsg) {
+	id := v.reactID
+	v.reactID = ""
+	emoji, ok := quickReactionForKey(msg.String())
+	if !ok {
+		return
+	}
+	status, counts, err := toggleMessageReaction(v.root, id, emoji, v.self)
+	v.applyReactionResult(id, status, counts, err)
+}
+
+func (v *timelineView) clearSelectedReactions() {
+	msg, ok := v.selectedMessage()
+	if !ok {
+		return
+	}
+	id := strings.TrimSpace(msg.ID)
+	status, counts, err := clearMessageReactions(v.root, id, v.self)
+	v.applyReactionResult(id, status, counts, err)
+}
+
+func (v *timelineView) applyReactionResult(id, status string, counts []fmail.ReactionCount, err error) {
+	if err != nil {
+		v.statusLine = "react failed: " + err.Error()
+		return
+	}
+	v.reactions = setReactionCounts(v.reactions, id, counts)
+	v.statusLine = status
+}
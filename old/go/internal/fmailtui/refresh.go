@@ -0,0 +1,125 @@
+package fmailtui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minRefreshInterval guards against configurations that would hammer the
+// provider (network filesystems in particular).
+const minRefreshInterval = 250 * time.Millisecond
+
+// refreshableViews lists views whose background refresh cadence is configurable.
+var refreshableViews = []ViewID{ViewTopics, ViewThread, ViewTimeline, ViewOperator}
+
+// refreshableView is implemented by views with a configurable refresh cadence
+// and a manual reload.
+type refreshableView interface {
+	SetRefreshInterval(interval time.Duration)
+	ForceRefresh() tea.Cmd
+}
+
+// parseRefreshIntervals parses view=duration pairs (e.g. "thread=500ms").
+func parseRefreshIntervals(specs []string) (map[ViewID]time.Duration, error) {
+	out := make(map[ViewID]time.Duration, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid refresh interval %q (expected view=duration)", spec)
+		}
+		id, err := refreshableViewID(name)
+		if err != nil {
+			return nil, err
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid refresh interval for %s: %q", id, strings.TrimSpace(raw))
+		}
+		out[id] = interval
+	}
+	return out, nil
+}
+
+func refreshableViewID(name string) (ViewID, error) {
+	id := ViewID(strings.ToLower(strings.TrimSpace(name)))
+	for _, candidate := range refreshableViews {
+		if candidate == id {
+			return id, nil
+		}
+	}
+	names := make([]string, 0, len(refreshableViews))
+	for _, candidate := range refreshableViews {
+		names = append(names, string(candidate))
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown refresh view %q (expected one of %s)", strings.TrimSpace(name), strings.Join(names, ", "))
+}
+
+func clampRefreshInterval(interval time.Duration) time.Duration {
+	if interval < minRefreshInterval {
+		return minRefreshInterval
+	}
+	return interval
+}
+
+// resolveRefreshIntervals layers the poll interval, persisted preferences and
+// explicit per-view overrides, in that order.
+func resolveRefreshIntervals(base time.Duration, prefs map[string]string, overrides map[ViewID]time.Duration) map[ViewID]time.Duration {
+	if base <= 0 {
+		base = defaultPollInterval
+	}
+	out := make(map[ViewID]time.Duration, len(refreshableViews))
+	for _, id := range refreshableViews {
+		interval := base
+		if raw := strings.TrimSpace(prefs[string(id)]); raw != "" {
+			if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+				interval = parsed
+			}
+		}
+		if override, ok := overrides[id]; ok && override > 0 {
+			interval = override
+		}
+		out[id] = clampRefreshInterval(interval)
+	}
+	return out
+}
+
+func (m *Model) applyRefreshIntervals(overrides map[ViewID]time.Duration) {
+	var prefs map[string]string
+	if m.tuiState != nil {
+		prefs = m.tuiState.Preferences().RefreshIntervals
+	}
+	for id, interval := range resolveRefreshIntervals(m.pollInterval, prefs, overrides) {
+		if view, ok := m.views[id].(refreshableView); ok {
+			view.SetRefreshInterval(interval)
+		}
+	}
+}
+
+// forceRefreshActiveView reloads the active view immediately.
+func (m *Model) forceRefreshActiveView() tea.Cmd {
+	view := m.activeView()
+	if view == nil {
+		return nil
+	}
+	if refreshable, ok := view.(refreshableView); ok {
+		return refreshable.ForceRefresh()
+	}
+	return view.Init()
+}
+
+// refreshStampLabel renders a view header's last-refreshed marker.
+func refreshStampLabel(at time.Time) string {
+	if at.IsZero() {
+		return "refreshing…"
+	}
+	return "refreshed " + at.Local().Format("15:04:05")
+}
@@ -0,0 +1,80 @@
+package fmailtui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/fmailtui/state"
+)
+
+func TestParseRefreshIntervals(t *testing.T) {
+	got, err := parseRefreshIntervals([]string{"thread=5s", " Topics = 500ms ", ""})
+	require.NoError(t, err)
+	require.Equal(t, map[ViewID]time.Duration{
+		ViewThread: 5 * time.Second,
+		ViewTopics: 500 * time.Millisecond,
+	}, got)
+
+	_, err = parseRefreshIntervals([]string{"graph=1s"})
+	require.ErrorContains(t, err, "unknown refresh view")
+	_, err = parseRefreshIntervals([]string{"thread"})
+	require.ErrorContains(t, err, "expected view=duration")
+	_, err = parseRefreshIntervals([]string{"thread=-1s"})
+	require.Error(t, err)
+}
+
+func TestResolveRefreshIntervalsLayering(t *testing.T) {
+	got := resolveRefreshIntervals(3*time.Second,
+		map[string]string{"thread": "10s", "timeline": "bogus", "operator": "1ms"},
+		map[ViewID]time.Duration{ViewTopics: time.Minute},
+	)
+	require.Equal(t, time.Minute, got[ViewTopics])
+	require.Equal(t, 10*time.Second, got[ViewThread])
+	require.Equal(t, 3*time.Second, got[ViewTimeline])
+	require.Equal(t, minRefreshInterval, got[ViewOperator])
+}
+
+func TestModelAppliesRefreshIntervals(t *testing.T) {
+	root := t.TempDir()
+	st := state.New(root + "/.fmail/tui-state.json")
+	st.UpdatePreferences(func(p *state.Preferences) {
+		p.RefreshIntervals = map[string]string{"thread": "7s"}
+	})
+	require.NoError(t, st.SaveNow())
+
+	model := newTestModel(t, Config{
+		Root:             root,
+		RefreshIntervals: map[ViewID]time.Duration{ViewOperator: 4 * time.Second},
+	})
+	require.Equal(t, 7*time.Second, model.views[ViewThread].(*threadView).refreshInterval)
+	require.Equal(t, 4*time.Second, model.views[ViewOperator].(*operatorView).refreshInterval)
+	require.Equal(t, defaultPollInterval, model.views[ViewTopics].(*topicsView).refreshInterval)
+	require.Equal(t, 5*defaultPollInterval, model.views[ViewTopics].(*topicsView).metadataRefreshInterval())
+}
+
+func TestManualRefreshKeyReloadsTopics(t *testing.T) {
+	root := t.TempDir()
+	model := newTestModel(t, Config{Root: root})
+	model = applyUpdate(t, model, tea.WindowSizeMsg{Width: 160, Height: 40})
+	model = applyUpdateWithCmd(t, model, runeKey('2'))
+	require.Equal(t, ViewTopics, model.activeViewID())
+
+	topics := model.views[ViewTopics].(*topicsView)
+	firstLoad := topics.lastLoad
+	require.False(t, firstLoad.IsZero())
+
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	_, err = store.SaveMessage(&fmail.Message{From: "coder", To: "fresh", Body: "hello"})
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	model = applyUpdateWithCmd(t, model, runeKey('R'))
+	require.True(t, topics.lastLoad.After(firstLoad))
+	require.Contains(t, model.View(), "fresh")
+	require.Contains(t, model.View(), "refreshed ")
+}
@@ -22,20 +22,28 @@ const (
 	maxBookmarks     = 500
 	bookmarkMaxAge   = 30 * 24 * time.Hour
 	maxNotifications = 50
+	maxPinsPerTopic  = 20
+
+	maxSentHistoryPerTarget = 50
+	maxMacroKeys            = 200
 )
 
 type TUIState struct {
 	Version       int                     `json:"version"`
 	ReadMarkers   map[string]string       `json:"read_markers,omitempty"`   // topic/dm -> last-read message ID
 	Bookmarks     []Bookmark              `json:"bookmarks,omitempty"`      // saved message references
+	Pins          []Pin                   `json:"pins,omitempty"`           // per-topic pinned messages
 	Annotations   map[string]string       `json:"annotations,omitempty"`    // message ID -> annotation text
 	Drafts        map[string]ComposeDraft `json:"drafts,omitempty"`         // target -> draft payload
-	Groups        map[string][]string     `json:"groups,omitempty"`         // ad-hoc compose groups
+	SentHistory   map[string][]string     `json:"sent_history,omitempty"`   // target -> sent bodies, oldest first
+	Groups        map[string][]string     `json:"groups,omitempty"`         // legacy compose groups; promoted to .fmail/groups on use
 	StarredTopics []string                `json:"starred_topics,omitempty"` // pinned topic names
 	SavedSearches []SavedSearch           `json:"saved_searches,omitempty"` // named search presets
+	Macros        map[string][]string     `json:"macros,omitempty"`         // register -> recorded key strings
 	Preferences   Preferences             `json:"preferences,omitempty"`    // UI preferences
 	NotifyRules   []NotificationRule      `json:"notify_rules,omitempty"`   // notification configuration
 	Notifications []Notification          `json:"notifications,omitempty"`  // persisted recent notifications
+	Mutes         map[string]Mute         `json:"mutes,omitempty"`          // topic/dm -> mute or snooze
 	LastView      string                  `json:"last_view,omitempty"`      // last active view (for session restore)
 	LastTopic     string                  `json:"last_topic,omitempty"`     // last viewed topic
 }
@@ -57,6 +65,33 @@ type Bookmark struct {
 	CreatedAt time.Time `json:"created_at,omitempty"` // set on creation
 }
 
+// Pin marks a message as important within its topic. Unlike bookmarks, which
+// are a personal cross-topic reading list that ages out after 30 days, pins
+// belong to one topic, show at the top of its thread view, and never expire.
+type Pin struct {
+	MessageID string    `json:"message_id"`
+	Topic     string    `json:"topic"`               // topic name or "@agent" for DMs
+	PinnedAt  time.Time `json:"pinned_at,omitempty"` // set on creation
+}
+
+// Mute hides a topic or DM ("@agent") from unread counts and notifications.
+// A zero Until mutes until unmuted; otherwise the target is snoozed until
+// then and the entry lapses on its own.
+type Mute struct {
+	Until   time.Time `json:"until,omitempty"`
+	MutedAt time.Time `json:"muted_at,omitempty"`
+}
+
+// Active reports whether the mute is in effect at now.
+func (m Mute) Active(now time.Time) bool {
+	return m.Until.IsZero() || now.Before(m.Until)
+}
+
+// Snoozed reports whether the mute expires.
+func (m Mute) Snoozed() bool {
+	return !m.Until.IsZero()
+}
+
 type SavedSearch struct {
 	Name  string           `json:"name"`
 	Query data.SearchQuery `json:"query"`
@@ -79,6 +114,9 @@ type Preferences struct {
 	ReplayMode           string   `json:"replay_mode,omitempty"` // "feed" or "timeline"
 	// HighlightPatterns are live-tail keyword highlight regexes.
 	HighlightPatterns []string `json:"highlight_patterns,omitempty"`
+	// RefreshIntervals maps view IDs (topics, thread, timeline, operator) to
+	// Go duration strings, e.g. {"thread": "5s"}.
+	RefreshIntervals map[string]string `json:"refresh_intervals,omitempty"`
 }
 
 type NotificationRule struct {
@@ -271,6 +309,51 @@ func (m *Manager) SetReadMarkers(markers map[string]string) {
 	m.markDirtyLocked()
 }
 
+// Mute returns the mute in effect for target at now.
+func (m *Manager) Mute(target string, now time.Time) (Mute, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mute, ok := m.state.Mutes[strings.TrimSpace(target)]
+	if !ok || !mute.Active(now) {
+		return Mute{}, false
+	}
+	return mute, true
+}
+
+// IsMuted reports whether target is muted or snoozed at now.
+func (m *Manager) IsMuted(target string, now time.Time) bool {
+	_, ok := m.Mute(target, now)
+	return ok
+}
+
+// SetMute mutes target until until, or indefinitely when until is zero.
+func (m *Manager) SetMute(target string, until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return
+	}
+	if m.state.Mutes == nil {
+		m.state.Mutes = make(map[string]Mute)
+	}
+	m.state.Mutes[target] = Mute{Until: until.UTC(), MutedAt: time.Now().UTC()}
+	m.markDirtyLocked()
+}
+
+// Unmute clears a mute or snooze. It reports whether one was set.
+func (m *Manager) Unmute(target string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target = strings.TrimSpace(target)
+	if _, ok := m.state.Mutes[target]; !ok {
+		return false
+	}
+	delete(m.state.Mutes, target)
+	m.markDirtyLocked()
+	return true
+}
+
 func (m *Manager) Bookmarks() []Bookmark {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -413,6 +496,67 @@ func (m *Manager) DeleteBookmark(messageID string) bool {
 	return true
 }
 
+// Pins returns the pins for a topic, oldest first.
+func (m *Manager) Pins(topic string) []Pin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	topic = strings.TrimSpace(topic)
+	var out []Pin
+	for _, pin := range m.state.Pins {
+		if pin.Topic == topic {
+			out = append(out, pin)
+		}
+	}
+	return out
+}
+
+func (m *Manager) IsPinned(messageID, topic string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messageID = strings.TrimSpace(messageID)
+	topic = strings.TrimSpace(topic)
+	for _, pin := range m.state.Pins {
+		if pin.MessageID == messageID && pin.Topic == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// TogglePin pins or unpins a message within a topic.
+// Returns true when the pin was added, false when removed/no-op. Adding past
+// the per-topic limit drops that topic's oldest pin.
+func (m *Manager) TogglePin(messageID, topic string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messageID = strings.TrimSpace(messageID)
+	topic = strings.TrimSpace(topic)
+	if messageID == "" || topic == "" {
+		return false
+	}
+
+	filtered := make([]Pin, 0, len(m.state.Pins))
+	removed := false
+	for _, pin := range m.state.Pins {
+		if pin.MessageID == messageID && pin.Topic == topic {
+			removed = true
+			continue
+		}
+		filtered = append(filtered, pin)
+	}
+	if removed {
+		m.state.Pins = filtered
+		m.markDirtyLocked()
+		return false
+	}
+
+	filtered = append(filtered, Pin{MessageID: messageID, Topic: topic, PinnedAt: time.Now().UTC()})
+	m.state.Pins = capPinsPerTopic(filtered)
+	m.markDirtyLocked()
+	return true
+}
+
 func (m *Manager) Annotation(messageID string) string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -496,6 +640,42 @@ func (m *Manager) DeleteDraft(target string) {
 	m.markDirtyLocked()
 }
 
+// SentHistory returns bodies previously sent to target, oldest first.
+func (m *Manager) SentHistory(target string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+	return append([]string(nil), m.state.SentHistory[target]...)
+}
+
+// AppendSentHistory records body as sent to target. Repeating the previous
+// entry is a no-op; only the newest maxSentHistoryPerTarget are kept.
+func (m *Manager) AppendSentHistory(target, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target = strings.TrimSpace(target)
+	body = strings.TrimSpace(body)
+	if target == "" || body == "" {
+		return
+	}
+	history := m.state.SentHistory[target]
+	if len(history) > 0 && history[len(history)-1] == body {
+		return
+	}
+	history = append(history, body)
+	if len(history) > maxSentHistoryPerTarget {
+		history = append([]string(nil), history[len(history)-maxSentHistoryPerTarget:]...)
+	}
+	if m.state.SentHistory == nil {
+		m.state.SentHistory = make(map[string][]string)
+	}
+	m.state.SentHistory[target] = history
+	m.markDirtyLocked()
+}
+
 func (m *Manager) Groups() map[string][]string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -581,6 +761,48 @@ func (m *Manager) DeleteSavedSearch(name string) {
 	m.markDirtyLocked()
 }
 
+// Macro returns the keys recorded in register, in order.
+func (m *Manager) Macro(register string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.state.Macros[strings.TrimSpace(register)]...)
+}
+
+// Macros returns every recorded macro keyed by register.
+func (m *Manager) Macros() map[string][]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return cloneGroups(m.state.Macros)
+}
+
+// SetMacro stores keys in register, replacing any previous recording. Only
+// the first maxMacroKeys keys are kept; an empty recording deletes the
+// register.
+func (m *Manager) SetMacro(register string, keys []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	register = strings.TrimSpace(register)
+	if register == "" {
+		return
+	}
+	if len(keys) == 0 {
+		if _, ok := m.state.Macros[register]; !ok {
+			return
+		}
+		delete(m.state.Macros, register)
+		m.markDirtyLocked()
+		return
+	}
+	if len(keys) > maxMacroKeys {
+		keys = keys[:maxMacroKeys]
+	}
+	if m.state.Macros == nil {
+		m.state.Macros = make(map[string][]string)
+	}
+	m.state.Macros[register] = append([]string(nil), keys...)
+	m.markDirtyLocked()
+}
+
 func (m *Manager) SetStarredTopics(topics []string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -860,6 +1082,29 @@ func normalizeState(state TUIState, now time.Time) TUIState {
 		state.Bookmarks = pruned
 	}
 
+	// Pins: drop invalid/duplicate entries, keep oldest first, cap per topic.
+	if len(state.Pins) > 0 {
+		seen := make(map[string]struct{}, len(state.Pins))
+		pins := make([]Pin, 0, len(state.Pins))
+		for _, pin := range state.Pins {
+			pin.MessageID = strings.TrimSpace(pin.MessageID)
+			pin.Topic = strings.TrimSpace(pin.Topic)
+			if pin.MessageID == "" || pin.Topic == "" {
+				continue
+			}
+			key := pin.Topic + "\x00" + pin.MessageID
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			pins = append(pins, pin)
+		}
+		sort.SliceStable(pins, func(i, j int) bool {
+			return pins[i].PinnedAt.Before(pins[j].PinnedAt)
+		})
+		state.Pins = capPinsPerTopic(pins)
+	}
+
 	// StarredTopics: de-dupe + sort.
 	if len(state.StarredTopics) > 0 {
 		seen := make(map[string]struct{}, len(state.StarredTopics))
@@ -921,6 +1166,17 @@ func normalizeState(state TUIState, now time.Time) TUIState {
 		}
 		state.Notifications = normalized
 	}
+	if len(state.Mutes) > 0 {
+		mutes := make(map[string]Mute, len(state.Mutes))
+		for target, mute := range state.Mutes {
+			target = strings.TrimSpace(target)
+			if target == "" || !mute.Active(now) {
+				continue
+			}
+			mutes[target] = mute
+		}
+		state.Mutes = mutes
+	}
 	state.Preferences = normalizePreferences(state.Preferences)
 
 	return state
@@ -950,9 +1206,21 @@ func cloneState(state TUIState) TUIState {
 	if state.Groups != nil {
 		out.Groups = cloneGroups(state.Groups)
 	}
+	if state.Macros != nil {
+		out.Macros = cloneGroups(state.Macros)
+	}
+	if state.SentHistory != nil {
+		out.SentHistory = make(map[string][]string, len(state.SentHistory))
+		for k, v := range state.SentHistory {
+			out.SentHistory[k] = append([]string(nil), v...)
+		}
+	}
 	if len(state.Bookmarks) > 0 {
 		out.Bookmarks = append([]Bookmark(nil), state.Bookmarks...)
 	}
+	if len(state.Pins) > 0 {
+		out.Pins = append([]Pin(nil), state.Pins...)
+	}
 	if len(state.StarredTopics) > 0 {
 		out.StarredTopics = append([]string(nil), state.StarredTopics...)
 	}
@@ -965,6 +1233,30 @@ func cloneState(state TUIState) TUIState {
 	if len(state.Notifications) > 0 {
 		out.Notifications = append([]Notification(nil), state.Notifications...)
 	}
+	if state.Mutes != nil {
+		out.Mutes = make(map[string]Mute, len(state.Mutes))
+		for k, v := range state.Mutes {
+			out.Mutes[k] = v
+		}
+	}
+	return out
+}
+
+// capPinsPerTopic keeps the newest maxPinsPerTopic pins of each topic. pins
+// must be ordered oldest first.
+func capPinsPerTopic(pins []Pin) []Pin {
+	counts := make(map[string]int, 8)
+	for _, pin := range pins {
+		counts[pin.Topic]++
+	}
+	out := make([]Pin, 0, len(pins))
+	for _, pin := range pins {
+		if counts[pin.Topic] > maxPinsPerTopic {
+			counts[pin.Topic]--
+			continue
+		}
+		out = append(out, pin)
+	}
 	return out
 }
 
@@ -1020,6 +1312,12 @@ func clonePreferences(p Preferences) Preferences {
 	if len(p.HighlightPatterns) > 0 {
 		out.HighlightPatterns = append([]string(nil), p.HighlightPatterns...)
 	}
+	if len(p.RefreshIntervals) > 0 {
+		out.RefreshIntervals = make(map[string]string, len(p.RefreshIntervals))
+		for k, v := range p.RefreshIntervals {
+			out.RefreshIntervals[k] = v
+		}
+	}
 	return out
 }
 
@@ -1070,6 +1368,21 @@ func normalizePreferences(p Preferences) Preferences {
 	if len(p.HighlightPatterns) > 0 {
 		p.HighlightPatterns = normalizeStringList(p.HighlightPatterns)
 	}
+	if len(p.RefreshIntervals) > 0 {
+		out := make(map[string]string, len(p.RefreshIntervals))
+		for view, interval := range p.RefreshIntervals {
+			view = strings.TrimSpace(strings.ToLower(view))
+			interval = strings.TrimSpace(interval)
+			if view == "" || interval == "" {
+				continue
+			}
+			out[view] = interval
+		}
+		p.RefreshIntervals = out
+		if len(out) == 0 {
+			p.RefreshIntervals = nil
+		}
+	}
 	return p
 }
 
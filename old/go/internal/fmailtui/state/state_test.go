@@ -1,6 +1,7 @@
 package state
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -99,6 +100,29 @@ func TestManager_DraftRoundTrip(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestManager_SentHistoryRoundTripAndCap(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".fmail", "tui-state.json")
+	m := New(path)
+	require.NoError(t, m.Load())
+
+	for i := 0; i < maxSentHistoryPerTarget+5; i++ {
+		m.AppendSentHistory("task", fmt.Sprintf("msg %d", i))
+	}
+	m.AppendSentHistory("task", fmt.Sprintf("msg %d", maxSentHistoryPerTarget+4))
+	m.AppendSentHistory("@bob", "line one\nline two")
+	require.NoError(t, m.SaveNow())
+
+	loaded := New(path)
+	require.NoError(t, loaded.Load())
+	history := loaded.SentHistory("task")
+	require.Len(t, history, maxSentHistoryPerTarget)
+	require.Equal(t, "msg 5", history[0])
+	require.Equal(t, fmt.Sprintf("msg %d", maxSentHistoryPerTarget+4), history[len(history)-1])
+	require.Equal(t, []string{"line one\nline two"}, loaded.SentHistory("@bob"))
+	require.Empty(t, loaded.SentHistory("other"))
+}
+
 func TestManager_GroupRoundTripAndNormalization(t *testing.T) {
 	root := t.TempDir()
 	path := filepath.Join(root, ".fmail", "tui-state.json")
@@ -117,6 +141,30 @@ func TestManager_GroupRoundTripAndNormalization(t *testing.T) {
 	require.NotContains(t, groups, "empty")
 }
 
+func TestManager_MacroRoundTripAndCap(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".fmail", "tui-state.json")
+	m := New(path)
+	require.NoError(t, m.Load())
+
+	m.SetMacro("a", []string{"2", "j", "enter", "ctrl+b"})
+	long := make([]string, maxMacroKeys+10)
+	for i := range long {
+		long[i] = "j"
+	}
+	m.SetMacro("b", long)
+	m.SetMacro("c", []string{"k"})
+	m.SetMacro("c", nil)
+	require.NoError(t, m.SaveNow())
+
+	loaded := New(path)
+	require.NoError(t, loaded.Load())
+	require.Equal(t, []string{"2", "j", "enter", "ctrl+b"}, loaded.Macro("a"))
+	require.Len(t, loaded.Macro("b"), maxMacroKeys)
+	require.Empty(t, loaded.Macro("c"))
+	require.Len(t, loaded.Macros(), 2)
+}
+
 func TestManager_ToggleBookmarkRoundTrip(t *testing.T) {
 	root := t.TempDir()
 	path := filepath.Join(root, ".fmail", "tui-state.json")
@@ -137,6 +185,42 @@ func TestManager_ToggleBookmarkRoundTrip(t *testing.T) {
 	require.False(t, loaded.IsBookmarked("20260209-101010-0001"))
 }
 
+func TestManager_PinsAreSeparateFromBookmarks(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".fmail", "tui-state.json")
+	m := New(path)
+	require.NoError(t, m.Load())
+
+	require.True(t, m.TogglePin("20260209-101010-0001", "task"))
+	require.True(t, m.TogglePin("20260209-101010-0002", "task"))
+	require.True(t, m.TogglePin("20260209-101010-0003", "build"))
+	require.True(t, m.IsPinned("20260209-101010-0001", "task"))
+	require.False(t, m.IsPinned("20260209-101010-0001", "build"))
+	require.False(t, m.IsBookmarked("20260209-101010-0001"))
+	require.Empty(t, m.Bookmarks())
+	require.NoError(t, m.SaveNow())
+
+	loaded := New(path)
+	require.NoError(t, loaded.Load())
+	pins := loaded.Pins("task")
+	require.Len(t, pins, 2)
+	require.Equal(t, "20260209-101010-0001", pins[0].MessageID)
+
+	require.False(t, loaded.TogglePin("20260209-101010-0001", "task"))
+	require.Len(t, loaded.Pins("task"), 1)
+	require.Len(t, loaded.Pins("build"), 1)
+}
+
+func TestManager_PinsCappedPerTopic(t *testing.T) {
+	m := New("")
+	for i := 0; i < maxPinsPerTopic+3; i++ {
+		m.TogglePin(fmt.Sprintf("20260209-101010-%04d", i), "task")
+	}
+	pins := m.Pins("task")
+	require.Len(t, pins, maxPinsPerTopic)
+	require.Equal(t, "20260209-101010-0003", pins[0].MessageID)
+}
+
 func TestManager_UpsertBookmarkUpdatesNote(t *testing.T) {
 	root := t.TempDir()
 	path := filepath.Join(root, ".fmail", "tui-state.json")
@@ -287,3 +371,31 @@ func TestManager_NotificationRulesAndNotificationsRoundTrip(t *testing.T) {
 	require.Equal(t, "high", notes[0].Priority)
 	require.True(t, notes[0].Unread)
 }
+
+func TestManager_MuteAndSnoozeRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".fmail", "tui-state.json")
+	m := New(path)
+	require.NoError(t, m.Load())
+
+	now := time.Now().UTC()
+	m.SetMute("task", time.Time{})
+	m.SetMute("@coder", now.Add(time.Hour))
+	m.SetMute("old", now.Add(-time.Minute))
+	require.True(t, m.IsMuted("task", now.Add(24*time.Hour)))
+	require.True(t, m.IsMuted("@coder", now))
+	require.False(t, m.IsMuted("@coder", now.Add(2*time.Hour)))
+	require.False(t, m.IsMuted("old", now))
+	require.NoError(t, m.SaveNow())
+
+	loaded := New(path)
+	require.NoError(t, loaded.Load())
+	mute, ok := loaded.Mute("@coder", now)
+	require.True(t, ok)
+	require.True(t, mute.Snoozed())
+	require.NotContains(t, loaded.Snapshot().Mutes, "old", "expired snoozes are pruned")
+
+	require.True(t, loaded.Unmute("task"))
+	require.False(t, loaded.Unmute("task"))
+	require.False(t, loaded.IsMuted("task", now))
+}
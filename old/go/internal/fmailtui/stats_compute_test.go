@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tOgg1/forge/internal/fmail"
@@ -80,3 +81,62 @@ func TestChooseBucketInterval_PrefersNiceSteps(t *testing.T) {
 	require.Equal(t, 24*time.Hour, chooseBucketInterval(start, end, 48))
 }
 
+func TestTopicStatsAccumulator_Incremental(t *testing.T) {
+	t0 := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+	acc := newTopicStatsAccumulator()
+	acc.add(fmail.Message{ID: "1", From: "architect", To: "task", Time: t0, Body: "who owns the build?"})
+	acc.add(fmail.Message{ID: "2", From: "coder", To: "task", Time: t0.Add(time.Minute), Body: "anyone seen the flaky test?"})
+	acc.add(fmail.Message{ID: "3", From: "tester", To: "@coder", Time: t0.Add(time.Minute), Body: "dm?"})
+
+	snap := acc.snapshot("task", t0, t0.Add(time.Hour))
+	require.Equal(t, 2, snap.Messages)
+	require.Equal(t, 2, snap.UnansweredQuestions)
+	require.Equal(t, 0, snap.ReplySamples)
+	require.Equal(t, []string{"task"}, acc.topicNames())
+
+	// A reply answers the question and feeds the latency distribution.
+	acc.add(fmail.Message{ID: "4", From: "coder", To: "task", Time: t0.Add(20 * time.Second), Body: "me", ReplyTo: "1"})
+	// A reply that arrives before its parent is settled once the parent shows up.
+	acc.add(fmail.Message{ID: "6", From: "architect", To: "task", Time: t0.Add(10 * time.Minute), Body: "yes", ReplyTo: "5"})
+	acc.add(fmail.Message{ID: "5", From: "reviewer", To: "task", Time: t0.Add(4 * time.Minute), Body: "ready to merge?"})
+
+	snap = acc.snapshot("task", t0, t0.Add(time.Hour))
+	require.Equal(t, 5, snap.Messages)
+	require.Equal(t, 1, snap.UnansweredQuestions)
+	require.Equal(t, 2, snap.ReplySamples)
+	require.Equal(t, 1, snap.Latency[0].Count)
+	require.Equal(t, 1, snap.Latency[2].Count)
+	require.Equal(t, "architect", snap.TopSenders[0].Label)
+
+	sum := 0
+	for _, n := range snap.VolumeCounts {
+		sum += n
+	}
+	require.Equal(t, 5, sum)
+}
+
+func TestRenderHistogram_MergesBucketsToWidth(t *testing.T) {
+	lines := renderHistogram([]int{1, 0, 4, 0, 2, 0}, 3, 2)
+	require.Equal(t, []string{" #", "###", "---"}, lines)
+}
+
+func TestStatsView_CyclesTopicScope(t *testing.T) {
+	t0 := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+	v := newStatsView("", "", nil)
+	v.windowIdx = len(v.windows) - 1 // all-time
+	v.applyLoaded(statsLoadedMsg{now: t0.Add(time.Hour), allTime: true, msgs: []fmail.Message{
+		{ID: "1", From: "architect", To: "task", Time: t0, Body: "who owns the build?"},
+		{ID: "2", From: "coder", To: "build", Time: t0.Add(time.Minute), Body: "green"},
+		{ID: "3", From: "coder", To: "task", Time: t0.Add(2 * time.Minute), Body: "next?"},
+	}})
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.Equal(t, "task", v.topic)
+	require.Equal(t, 2, v.topicSnap.UnansweredQuestions)
+	out := v.View(120, 30, ThemeDefault)
+	require.Contains(t, out, "topic: task")
+	require.Contains(t, out, "VOLUME OVER TIME")
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	require.Equal(t, "", v.topic)
+}
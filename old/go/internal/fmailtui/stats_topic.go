@@ -0,0 +1,221 @@
+package fmailtui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+// topicStatsSnapshot is the per-topic slice of the stats view.
+type topicStatsSnapshot struct {
+	Topic    string
+	Messages int
+
+	TopSenders []statsBar
+
+	VolumeCounts   []int
+	VolumeStart    time.Time
+	VolumeInterval time.Duration
+
+	ReplySamples int
+	MedianReply  time.Duration
+	Latency      []statsBucket
+
+	UnansweredQuestions int
+}
+
+// topicStats holds the running totals for one topic.
+type topicStats struct {
+	messages  int
+	senders   map[string]int
+	times     []time.Time
+	sentAt    map[string]time.Time   // message ID -> send time
+	pending   map[string][]time.Time // parent ID -> replies seen before it
+	latencies []time.Duration
+	questions map[string]struct{} // question IDs without a reply yet
+	replied   map[string]struct{} // message IDs that have at least one reply
+}
+
+// topicStatsAccumulator folds messages into per-topic stats one at a time,
+// so live messages from the provider update the view without a rescan.
+// Direct messages are ignored.
+type topicStatsAccumulator struct {
+	topics map[string]*topicStats
+}
+
+func newTopicStatsAccumulator() *topicStatsAccumulator {
+	return &topicStatsAccumulator{topics: make(map[string]*topicStats, 16)}
+}
+
+func (a *topicStatsAccumulator) reset() {
+	a.topics = make(map[string]*topicStats, 16)
+}
+
+func (a *topicStatsAccumulator) add(msg fmail.Message) {
+	topic := strings.TrimSpace(msg.To)
+	if topic == "" || strings.HasPrefix(topic, "@") {
+		return
+	}
+	ts := a.topics[topic]
+	if ts == nil {
+		ts = &topicStats{
+			senders:   make(map[string]int, 8),
+			sentAt:    make(map[string]time.Time, 64),
+			pending:   make(map[string][]time.Time),
+			questions: make(map[string]struct{}),
+			replied:   make(map[string]struct{}),
+		}
+		a.topics[topic] = ts
+	}
+
+	ts.messages++
+	if from := strings.TrimSpace(msg.From); from != "" {
+		ts.senders[from]++
+	}
+	if !msg.Time.IsZero() {
+		ts.times = append(ts.times, msg.Time)
+	}
+
+	id := strings.TrimSpace(msg.ID)
+	if id != "" {
+		ts.sentAt[id] = msg.Time
+		// Replies can arrive before their parent when the provider delivers
+		// out of order; settle them now.
+		for _, replyAt := range ts.pending[id] {
+			ts.addLatency(msg.Time, replyAt)
+		}
+		delete(ts.pending, id)
+		if _, ok := ts.replied[id]; !ok && isQuestionMessage(msg) {
+			ts.questions[id] = struct{}{}
+		}
+	}
+
+	if parentID := strings.TrimSpace(msg.ReplyTo); parentID != "" {
+		ts.replied[parentID] = struct{}{}
+		delete(ts.questions, parentID)
+		if parentAt, ok := ts.sentAt[parentID]; ok {
+			ts.addLatency(parentAt, msg.Time)
+		} else {
+			ts.pending[parentID] = append(ts.pending[parentID], msg.Time)
+		}
+	}
+}
+
+func (ts *topicStats) addLatency(parentAt, replyAt time.Time) {
+	if parentAt.IsZero() || replyAt.IsZero() {
+		return
+	}
+	if delta := replyAt.Sub(parentAt); delta >= 0 {
+		ts.latencies = append(ts.latencies, delta)
+	}
+}
+
+// topicNames returns the tracked topics, busiest first.
+func (a *topicStatsAccumulator) topicNames() []string {
+	names := make([]string, 0, len(a.topics))
+	for name := range a.topics {
+		names = append(names, name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ci, cj := a.topics[names[i]].messages, a.topics[names[j]].messages
+		if ci != cj {
+			return ci > cj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func (a *topicStatsAccumulator) snapshot(topic string, windowStart, windowEnd time.Time) topicStatsSnapshot {
+	out := topicStatsSnapshot{Topic: topic}
+	ts := a.topics[topic]
+	if ts == nil {
+		return out
+	}
+	out.Messages = ts.messages
+	out.TopSenders = topN(ts.senders, 5)
+	out.UnansweredQuestions = len(ts.questions)
+
+	out.ReplySamples = len(ts.latencies)
+	out.Latency = latencyBuckets(ts.latencies)
+	if len(ts.latencies) > 0 {
+		sorted := append([]time.Duration(nil), ts.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 1 {
+			out.MedianReply = sorted[mid]
+		} else {
+			out.MedianReply = (sorted[mid-1] + sorted[mid]) / 2
+		}
+	}
+
+	out.VolumeInterval = chooseBucketInterval(windowStart, windowEnd, 48)
+	out.VolumeStart = bucketStartTime(windowStart, out.VolumeInterval)
+	out.VolumeCounts = bucketTimes(ts.times, out.VolumeStart, windowEnd, out.VolumeInterval)
+	return out
+}
+
+func bucketTimes(times []time.Time, start, end time.Time, interval time.Duration) []int {
+	if interval <= 0 || start.IsZero() || end.IsZero() || !end.After(start) {
+		return nil
+	}
+	counts := make([]int, int(end.Sub(start)/interval)+1)
+	for _, ts := range times {
+		if ts.Before(start) || !ts.Before(end) {
+			continue
+		}
+		if idx := int(ts.Sub(start) / interval); idx >= 0 && idx < len(counts) {
+			counts[idx]++
+		}
+	}
+	return counts
+}
+
+// isQuestionMessage reports whether any line of the body ends in a question
+// mark.
+func isQuestionMessage(msg fmail.Message) bool {
+	for _, line := range strings.Split(messageBodyString(msg.Body), "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), "?") {
+			return true
+		}
+	}
+	return false
+}
+
+// renderHistogram draws counts as an ASCII column chart of the given size,
+// merging adjacent buckets when there are more buckets than columns.
+func renderHistogram(counts []int, width, height int) []string {
+	if len(counts) == 0 || width <= 0 || height <= 0 {
+		return nil
+	}
+	cols := counts
+	if len(counts) > width {
+		cols = make([]int, width)
+		for i, c := range counts {
+			cols[i*width/len(counts)] += c
+		}
+	}
+	maxC := 0
+	for _, c := range cols {
+		if c > maxC {
+			maxC = c
+		}
+	}
+	lines := make([]string, 0, height+1)
+	for row := height; row >= 1; row-- {
+		var b strings.Builder
+		for _, c := range cols {
+			// Round up so any non-zero bucket shows at least one cell.
+			if maxC > 0 && (c*height+maxC-1)/maxC >= row {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	lines = append(lines, strings.Repeat("-", len(cols)))
+	return lines
+}
@@ -57,6 +57,11 @@ type statsView struct {
 	subCancel func()
 
 	snap statsSnapshot
+
+	// topic scopes the view to one topic; "" shows every conversation.
+	topic      string
+	topicStats *topicStatsAccumulator
+	topicSnap  topicStatsSnapshot
 }
 
 func newStatsView(root, self string, provider data.MessageProvider) *statsView {
@@ -72,8 +77,9 @@ func newStatsView(root, self string, provider data.MessageProvider) *statsView {
 			30 * 24 * time.Hour,
 			0, // all-time
 		},
-		windowIdx: 2, // 24h
-		seen:      make(map[string]struct{}, 1024),
+		windowIdx:  2, // 24h
+		seen:       make(map[string]struct{}, 1024),
+		topicStats: newTopicStatsAccumulator(),
 	}
 }
 
@@ -166,10 +172,38 @@ func (v *statsView) handleKey(msg tea.KeyMsg) tea.Cmd {
 			v.loading = true
 			return v.loadCmd()
 		}
+	case "t":
+		v.cycleTopic(1)
+	case "T":
+		v.cycleTopic(-1)
 	}
 	return nil
 }
 
+// cycleTopic steps the topic scope through "all topics" and each topic,
+// busiest first.
+func (v *statsView) cycleTopic(delta int) {
+	scopes := append([]string{""}, v.topicStats.topicNames()...)
+	idx := 0
+	for i, name := range scopes {
+		if name == v.topic {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + delta + len(scopes)) % len(scopes)
+	v.topic = scopes[idx]
+	v.refreshTopicSnap()
+}
+
+func (v *statsView) refreshTopicSnap() {
+	if v.topic == "" {
+		v.topicSnap = topicStatsSnapshot{}
+		return
+	}
+	v.topicSnap = v.topicStats.snapshot(v.topic, v.loadedStart, v.loadedEnd)
+}
+
 func (v *statsView) followingTail(now time.Time) bool {
 	if v.windows[v.windowIdx] == 0 {
 		return false
@@ -309,8 +343,13 @@ func (v *statsView) applyLoaded(msg statsLoadedMsg) {
 
 	v.all = append(v.all[:0], msg.msgs...)
 	v.seen = make(map[string]struct{}, len(v.all))
+	v.topicStats.reset()
 	for i := range v.all {
 		v.seen[statsDedupKey(v.all[i])] = struct{}{}
+		v.topicStats.add(v.all[i])
+	}
+	if _, ok := v.topicStats.topics[v.topic]; !ok {
+		v.topic = ""
 	}
 
 	start := msg.start
@@ -330,6 +369,7 @@ func (v *statsView) applyLoaded(msg statsLoadedMsg) {
 	v.loadedStart = start
 	v.loadedEnd = end
 	v.snap = computeStats(v.all, v.loadedStart, v.loadedEnd)
+	v.refreshTopicSnap()
 	if v.followingTail(msg.now) && !msg.allTime {
 		v.windowEnd = msg.now
 	}
@@ -351,6 +391,7 @@ func (v *statsView) applyIncoming(msg fmail.Message) {
 
 	v.all = append(v.all, msg)
 	sortMessages(v.all)
+	v.topicStats.add(msg)
 
 	// Extend tail window.
 	if v.windows[v.windowIdx] > 0 {
@@ -372,6 +413,7 @@ func (v *statsView) applyIncoming(msg fmail.Message) {
 	}
 
 	v.snap = computeStats(v.all, v.loadedStart, v.loadedEnd)
+	v.refreshTopicSnap()
 }
 
 func statsDedupKey(msg fmail.Message) string {
@@ -411,7 +453,11 @@ func (v *statsView) View(width, height int, theme Theme) string {
 	mapper := styles.NewAgentColorMapperWithPalette(palette.AgentPalette)
 
 	rangeLabel := v.rangeLabel()
-	head := titleStyle.Render(truncateVis("STATS  "+rangeLabel, innerW))
+	scope := "all topics"
+	if v.topic != "" {
+		scope = "topic: " + v.topic
+	}
+	head := titleStyle.Render(truncateVis("STATS  "+rangeLabel+"  "+scope, innerW))
 
 	if v.lastErr != nil {
 		body := muted.Render("error: " + v.lastErr.Error())
@@ -434,12 +480,16 @@ func (v *statsView) View(width, height int, theme Theme) string {
 	}
 	divider := styles.DividerStyle(palette).Render("│")
 
-	left := v.renderLeft(leftW, innerH, palette, mapper)
+	renderLeft, renderRight := v.renderLeft, v.renderRight
+	if v.topic != "" {
+		renderLeft, renderRight = v.renderTopicLeft, v.renderTopicRight
+	}
+	left := renderLeft(leftW, innerH, palette, mapper)
 	if rightW == 0 {
 		content := lipgloss.JoinVertical(lipgloss.Left, head, "", left)
 		return panel.Width(width).Height(height).Render(content)
 	}
-	right := v.renderRight(rightW, innerH, palette, mapper)
+	right := renderRight(rightW, innerH, palette, mapper)
 
 	cols := lipgloss.JoinHorizontal(lipgloss.Top,
 		lipgloss.NewStyle().Width(leftW).Height(innerH).Render(left),
@@ -447,7 +497,7 @@ func (v *statsView) View(width, height int, theme Theme) string {
 		lipgloss.NewStyle().Width(rightW).Height(innerH).Render(right),
 	)
 
-	footer := muted.Render(truncateVis("[/]: range  \u2190/\u2192: pan  r: refresh  Esc: back  (p: stats)", innerW))
+	footer := muted.Render(truncateVis("[/]: range  \u2190/\u2192: pan  t/T: topic  r: refresh  Esc: back  (p: stats)", innerW))
 	content := lipgloss.JoinVertical(lipgloss.Left, head, "", cols, "", footer)
 	return panel.Width(width).Height(height).Render(content)
 }
@@ -660,6 +710,109 @@ func (v *statsView) renderRight(width, height int, palette styles.Theme, mapper
 	return strings.Join(lines, "\n")
 }
 
+func (v *statsView) renderTopicLeft(width, height int, palette styles.Theme, mapper *styles.AgentColorMapper) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	bold := lipgloss.NewStyle().Bold(true)
+
+	s := v.topicSnap
+	lines := make([]string, 0, height)
+
+	lines = append(lines, bold.Render("TOPIC "+s.Topic))
+	lines = append(lines, fmt.Sprintf("Messages:        %d", s.Messages))
+	lines = append(lines, fmt.Sprintf("Unanswered (?):  %d", s.UnansweredQuestions))
+	if s.ReplySamples > 0 {
+		lines = append(lines, fmt.Sprintf("Median reply:    %s", formatDurationCompact(s.MedianReply)))
+	} else {
+		lines = append(lines, "Median reply:    -")
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, bold.Render("TOP SENDERS"))
+	if len(s.TopSenders) == 0 {
+		lines = append(lines, muted.Render("No data"))
+	} else {
+		maxC := s.TopSenders[0].Count
+		barW := maxInt(0, width-18)
+		if barW > 24 {
+			barW = 24
+		}
+		for i, a := range s.TopSenders {
+			label := mapper.Foreground(a.Label).Render(mapper.Plain(a.Label))
+			bar := renderBar(a.Count, maxC, barW, "#")
+			lines = append(lines, truncateVis(fmt.Sprintf("%2d. %-10s %4d %s", i+1, label, a.Count, bar), width))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, bold.Render("REPLY LATENCY"))
+	if s.ReplySamples == 0 {
+		lines = append(lines, muted.Render("No replies"))
+	} else {
+		maxC := 0
+		for _, b := range s.Latency {
+			if b.Count > maxC {
+				maxC = b.Count
+			}
+		}
+		barW := maxInt(0, width-18)
+		if barW > 24 {
+			barW = 24
+		}
+		for _, b := range s.Latency {
+			bar := latencyStyle(palette, b.Label).Render(renderBar(b.Count, maxC, barW, "#"))
+			lines = append(lines, truncateVis(fmt.Sprintf("%-7s %s %4.0f%%", b.Label, bar, b.Pct), width))
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i := range lines {
+		lines[i] = truncateVis(lines[i], width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (v *statsView) renderTopicRight(width, height int, palette styles.Theme, _ *styles.AgentColorMapper) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	bold := lipgloss.NewStyle().Bold(true)
+
+	s := v.topicSnap
+	lines := make([]string, 0, height)
+
+	lines = append(lines, bold.Render("VOLUME OVER TIME"))
+	histogram := renderHistogram(s.VolumeCounts, width, minInt(8, maxInt(1, height-4)))
+	if len(histogram) == 0 {
+		lines = append(lines, muted.Render("No data"))
+	} else {
+		lines = append(lines, histogram...)
+		if s.VolumeInterval > 0 {
+			label := fmt.Sprintf("%s per column", formatDurationCompact(s.VolumeInterval))
+			if len(s.VolumeCounts) > width {
+				label = "merged buckets"
+			}
+			if v.windows[v.windowIdx] > 0 {
+				label = v.loadedStart.UTC().Format("15:04") + " ... " + v.loadedEnd.UTC().Format("15:04") + "  (" + label + ")"
+			}
+			lines = append(lines, muted.Render(truncateVis(label, width)))
+		}
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i := range lines {
+		lines[i] = truncateVis(lines[i], width)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func latencyStyle(palette styles.Theme, label string) lipgloss.Style {
 	switch label {
 	case "<30s":
@@ -271,6 +271,9 @@ func (m *Model) statusMetricsCmd() tea.Cmd {
 		topics, err := provider.Topics()
 		if err == nil {
 			for _, topic := range topics {
+				if mute, ok := snap.Mutes[topic.Name]; ok && mute.Active(now) {
+					continue
+				}
 				marker := readMarkerForTarget(snap.ReadMarkers, topic.Name)
 				n, err := unreadCountForTopic(provider, topic.Name, marker, topic.MessageCount)
 				if err != nil {
@@ -284,6 +287,9 @@ func (m *Model) statusMetricsCmd() tea.Cmd {
 			convs, err := provider.DMConversations(self)
 			if err == nil {
 				for _, conv := range convs {
+					if mute, ok := snap.Mutes["@"+conv.Agent]; ok && mute.Active(now) {
+						continue
+					}
 					marker := readMarkerForTarget(snap.ReadMarkers, "@"+conv.Agent)
 					n, err := unreadCountForDM(provider, self, conv.Agent, marker)
 					if err != nil {
@@ -374,13 +380,15 @@ func viewLabel(id ViewID) string {
 		return "Bookmarks"
 	case ViewNotify:
 		return "Notifications"
+	case ViewInbox:
+		return "Inbox"
 	default:
 		return string(id)
 	}
 }
 
 func (m *Model) renderStatusBar() string {
-	palette, ok := styles.Themes[string(m.theme)]
+	palette, ok := styles.LookupTheme(string(m.theme))
 	if !ok {
 		palette = styles.DefaultTheme
 	}
@@ -437,6 +445,9 @@ func (m *Model) renderStatusBar() string {
 
 	segments := make([]string, 0, 6)
 	segments = append(segments, conn)
+	if m.macro.recording != "" {
+		segments = append(segments, lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Priority.High)).Bold(true).Render("REC @"+m.macro.recording))
+	}
 	if m.width >= 52 {
 		segments = append(segments, throughput)
 	}
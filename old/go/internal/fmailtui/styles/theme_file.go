@@ -0,0 +1,202 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeFile is the on-disk format for user-defined palettes. YAML and JSON
+// are both accepted. Each theme starts from a built-in palette (Extends,
+// default "default") and overrides only the colors it sets.
+//
+//	themes:
+//	  - name: solarized
+//	    extends: default
+//	    agent_palette: ["33", "37", "61", "125"]
+//	    base: {background: "234", foreground: "254", accent: "37"}
+type ThemeFile struct {
+	Themes []ThemeSpec `yaml:"themes" json:"themes"`
+}
+
+// ThemeSpec describes one custom palette. Empty fields inherit from Extends.
+type ThemeSpec struct {
+	Name         string            `yaml:"name" json:"name"`
+	Extends      string            `yaml:"extends" json:"extends"`
+	BorderStyle  string            `yaml:"border_style" json:"border_style"`
+	AgentPalette []string          `yaml:"agent_palette" json:"agent_palette"`
+	Base         map[string]string `yaml:"base" json:"base"`
+	Message      map[string]string `yaml:"message" json:"message"`
+	Priority     map[string]string `yaml:"priority" json:"priority"`
+	Status       map[string]string `yaml:"status" json:"status"`
+	Chrome       map[string]string `yaml:"chrome" json:"chrome"`
+	Borders      map[string]string `yaml:"borders" json:"borders"`
+}
+
+var (
+	customMu     sync.RWMutex
+	customThemes = map[string]Theme{}
+)
+
+// LookupTheme returns a built-in or custom palette by name. Built-in names
+// cannot be shadowed by a theme file.
+func LookupTheme(name string) (Theme, bool) {
+	if theme, ok := Themes[name]; ok {
+		return theme, true
+	}
+	customMu.RLock()
+	defer customMu.RUnlock()
+	theme, ok := customThemes[name]
+	return theme, ok
+}
+
+// ThemeNames lists built-in themes (default first) followed by custom
+// themes in name order.
+func ThemeNames() []string {
+	names := []string{DefaultTheme.Name, HighContrastTheme.Name}
+	customMu.RLock()
+	custom := make([]string, 0, len(customThemes))
+	for name := range customThemes {
+		custom = append(custom, name)
+	}
+	customMu.RUnlock()
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// SetCustomThemes replaces the registered custom palettes.
+func SetCustomThemes(themes []Theme) {
+	next := make(map[string]Theme, len(themes))
+	for _, theme := range themes {
+		next[theme.Name] = theme
+	}
+	customMu.Lock()
+	customThemes = next
+	customMu.Unlock()
+}
+
+// LoadThemeFile reads and resolves the palettes in a theme file.
+func LoadThemeFile(path string) ([]Theme, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseThemeFile(raw)
+}
+
+// ParseThemeFile resolves palettes from YAML or JSON theme file contents.
+func ParseThemeFile(raw []byte) ([]Theme, error) {
+	var file ThemeFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse theme file: %w", err)
+	}
+	themes := make([]Theme, 0, len(file.Themes))
+	seen := make(map[string]bool, len(file.Themes))
+	for i, spec := range file.Themes {
+		theme, err := spec.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("themes[%d]: %w", i, err)
+		}
+		if seen[theme.Name] {
+			return nil, fmt.Errorf("themes[%d]: duplicate theme %q", i, theme.Name)
+		}
+		seen[theme.Name] = true
+		themes = append(themes, theme)
+	}
+	return themes, nil
+}
+
+func (s ThemeSpec) resolve() (Theme, error) {
+	name := strings.TrimSpace(s.Name)
+	if name == "" {
+		return Theme{}, fmt.Errorf("name is required")
+	}
+	if _, ok := Themes[name]; ok {
+		return Theme{}, fmt.Errorf("theme %q is built in", name)
+	}
+	extends := strings.TrimSpace(s.Extends)
+	if extends == "" {
+		extends = DefaultTheme.Name
+	}
+	base, ok := Themes[extends]
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %q extends unknown theme %q", name, extends)
+	}
+
+	theme := base
+	theme.Name = name
+	theme.AgentPalette = append([]string(nil), base.AgentPalette...)
+	if border := strings.TrimSpace(s.BorderStyle); border != "" {
+		switch border {
+		case "rounded", "sharp", "double", "hidden":
+			theme.BorderStyle = border
+		default:
+			return Theme{}, fmt.Errorf("theme %q: invalid border_style %q", name, border)
+		}
+	}
+	if len(s.AgentPalette) > 0 {
+		theme.AgentPalette = make([]string, 0, len(s.AgentPalette))
+		for _, code := range s.AgentPalette {
+			if code = strings.TrimSpace(code); code != "" {
+				theme.AgentPalette = append(theme.AgentPalette, code)
+			}
+		}
+	}
+
+	sections := []struct {
+		name   string
+		values map[string]string
+		fields map[string]*string
+	}{
+		{"base", s.Base, map[string]*string{
+			"background": &theme.Base.Background,
+			"foreground": &theme.Base.Foreground,
+			"muted":      &theme.Base.Muted,
+			"accent":     &theme.Base.Accent,
+			"border":     &theme.Base.Border,
+		}},
+		{"message", s.Message, map[string]*string{
+			"own":    &theme.Message.Own,
+			"other":  &theme.Message.Other,
+			"system": &theme.Message.System,
+		}},
+		{"priority", s.Priority, map[string]*string{
+			"high":   &theme.Priority.High,
+			"normal": &theme.Priority.Normal,
+			"low":    &theme.Priority.Low,
+		}},
+		{"status", s.Status, map[string]*string{
+			"online": &theme.Status.Online,
+			"recent": &theme.Status.Recent,
+			"stale":  &theme.Status.Stale,
+		}},
+		{"chrome", s.Chrome, map[string]*string{
+			"header":        &theme.Chrome.Header,
+			"footer":        &theme.Chrome.Footer,
+			"breadcrumb":    &theme.Chrome.Breadcrumb,
+			"selected_item": &theme.Chrome.SelectedItem,
+			"scrollbar":     &theme.Chrome.Scrollbar,
+		}},
+		{"borders", s.Borders, map[string]*string{
+			"active_pane":   &theme.Borders.ActivePane,
+			"inactive_pane": &theme.Borders.InactivePane,
+			"divider":       &theme.Borders.Divider,
+		}},
+	}
+	for _, section := range sections {
+		for key, value := range section.values {
+			field, ok := section.fields[key]
+			if !ok {
+				return Theme{}, fmt.Errorf("theme %q: unknown color %s.%s", name, section.name, key)
+			}
+			if value = strings.TrimSpace(value); value != "" {
+				*field = value
+			}
+		}
+	}
+	return theme, nil
+}
@@ -0,0 +1,302 @@
+package fmailtui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const switcherMaxRows = 12
+
+// Quick-switcher entry kinds.
+const (
+	switcherKindTopic = "topic"
+	switcherKindDM    = "dm"
+	switcherKindAgent = "agent"
+)
+
+// switcherState is the Ctrl+K fuzzy finder over topics, DMs, and agents.
+type switcherState struct {
+	active   bool
+	query    string
+	items    []switcherItem
+	matches  []switcherItem
+	selected int
+	err      string
+}
+
+type switcherItem struct {
+	target       string // topic name or "@agent"
+	kind         string
+	unread       int
+	lastActivity time.Time
+	score        int
+}
+
+func (m *Model) openSwitcher() {
+	m.switcher = switcherState{active: true}
+	items, err := m.switcherItems()
+	if err != nil {
+		m.switcher.err = err.Error()
+	}
+	m.switcher.items = items
+	m.switcher.filter()
+}
+
+func (m *Model) closeSwitcher() {
+	m.switcher = switcherState{}
+}
+
+// switcherItems collects one entry per topic and per agent. Agents with a
+// DM conversation carry its activity; the rest are listed as agents and
+// open an empty DM thread.
+func (m *Model) switcherItems() ([]switcherItem, error) {
+	if m.provider == nil {
+		return nil, nil
+	}
+	var unreadTop, unreadDM map[string]int
+	if topics, ok := m.views[ViewTopics].(*topicsView); ok {
+		unreadTop, unreadDM = topics.unreadByTop, topics.unreadByDM
+	}
+
+	topics, err := m.provider.Topics()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]switcherItem, 0, len(topics))
+	for _, topic := range topics {
+		items = append(items, switcherItem{
+			target:       topic.Name,
+			kind:         switcherKindTopic,
+			unread:       unreadTop[topic.Name],
+			lastActivity: topic.LastActivity,
+		})
+	}
+
+	seen := make(map[string]bool)
+	if self := strings.TrimSpace(m.selfAgent); self != "" {
+		dms, err := m.provider.DMConversations(self)
+		if err != nil {
+			return nil, err
+		}
+		for _, conv := range dms {
+			seen[strings.ToLower(conv.Agent)] = true
+			items = append(items, switcherItem{
+				target:       "@" + conv.Agent,
+				kind:         switcherKindDM,
+				unread:       maxInt(conv.UnreadCount, unreadDM[conv.Agent]),
+				lastActivity: conv.LastActivity,
+			})
+		}
+		seen[strings.ToLower(self)] = true
+	}
+
+	agents, err := m.provider.Agents()
+	if err != nil {
+		return nil, err
+	}
+	for _, agent := range agents {
+		name := strings.TrimSpace(agent.Name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		items = append(items, switcherItem{
+			target:       "@" + name,
+			kind:         switcherKindAgent,
+			lastActivity: agent.LastSeen,
+		})
+	}
+	return items, nil
+}
+
+// filter ranks the items against the query: best fuzzy match first, then
+// targets with unread messages, then most recent activity. With an empty
+// query only the last two apply.
+func (s *switcherState) filter() {
+	query := strings.ToLower(strings.TrimSpace(s.query))
+	s.matches = s.matches[:0]
+	for _, item := range s.items {
+		score := 0
+		if query != "" {
+			score = fuzzyScore(strings.ToLower(item.target), query)
+			if score < 0 {
+				continue
+			}
+		}
+		item.score = score
+		s.matches = append(s.matches, item)
+	}
+	sort.SliceStable(s.matches, func(i, j int) bool {
+		a, b := s.matches[i], s.matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if (a.unread > 0) != (b.unread > 0) {
+			return a.unread > 0
+		}
+		if !a.lastActivity.Equal(b.lastActivity) {
+			return a.lastActivity.After(b.lastActivity)
+		}
+		if a.unread != b.unread {
+			return a.unread > b.unread
+		}
+		if len(a.target) != len(b.target) {
+			return len(a.target) < len(b.target)
+		}
+		return a.target < b.target
+	})
+	if s.selected >= len(s.matches) {
+		s.selected = maxInt(0, len(s.matches)-1)
+	}
+}
+
+// fuzzyScore returns how well query matches target as a subsequence, or -1
+// when it does not. Consecutive runs, matches at word starts, and a match at
+// the very start score higher, so "bld" prefers "build" over "bug-lead".
+func fuzzyScore(target, query string) int {
+	candidate := []rune(target)
+	score := 0
+	pos := 0
+	prev := -2
+	for _, qr := range query {
+		found := -1
+		for i := pos; i < len(candidate); i++ {
+			if candidate[i] == qr {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return -1
+		}
+		score++
+		switch {
+		case found == prev+1:
+			score += 5
+		case found == 0 || isSwitcherWordBreak(candidate[found-1]):
+			score += 3
+		}
+		if found == 0 || (found == 1 && candidate[0] == '@') {
+			score += 10
+		}
+		prev = found
+		pos = found + 1
+	}
+	return score
+}
+
+func isSwitcherWordBreak(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func (m *Model) handleSwitcherKey(msg tea.KeyMsg) tea.Cmd {
+	s := &m.switcher
+	switch msg.String() {
+	case "esc", "ctrl+k", "ctrl+c":
+		m.closeSwitcher()
+		return nil
+	case "enter":
+		if s.selected < 0 || s.selected >= len(s.matches) {
+			return nil
+		}
+		target := s.matches[s.selected].target
+		m.closeSwitcher()
+		return tea.Batch(openThreadCmd(target, ""), pushViewCmd(ViewThread))
+	case "up", "ctrl+p":
+		if s.selected > 0 {
+			s.selected--
+		}
+		return nil
+	case "down", "ctrl+n", "tab":
+		if s.selected < len(s.matches)-1 {
+			s.selected++
+		}
+		return nil
+	case "backspace", "ctrl+h":
+		if runes := []rune(s.query); len(runes) > 0 {
+			s.query = string(runes[:len(runes)-1])
+			s.selected = 0
+			s.filter()
+		}
+		return nil
+	case "ctrl+u":
+		s.query = ""
+		s.selected = 0
+		s.filter()
+		return nil
+	}
+	// Targets never contain spaces, so only runes extend the query.
+	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 {
+		s.query += string(msg.Runes)
+		s.selected = 0
+		s.filter()
+	}
+	return nil
+}
+
+func (m *Model) renderSwitcherOverlay(width, height int, theme Theme) string {
+	palette := themePalette(theme)
+	panelWidth := minInt(maxInt(40, width-8), 72)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+	s := m.switcher
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(palette.Chrome.Breadcrumb)).Render("Jump to"),
+		"",
+		"> " + s.query + "_",
+		"",
+	}
+	switch {
+	case s.err != "":
+		lines = append(lines, "error: "+s.err)
+	case len(s.matches) == 0:
+		lines = append(lines, muted.Render("no matches"))
+	}
+
+	rows := minInt(switcherMaxRows, maxInt(3, height-12))
+	start := 0
+	if s.selected >= rows {
+		start = s.selected - rows + 1
+	}
+	now := time.Now().UTC()
+	nameWidth := maxInt(10, panelWidth-30)
+	for i := start; i < len(s.matches) && i < start+rows; i++ {
+		item := s.matches[i]
+		marker := "  "
+		if i == s.selected {
+			marker = "> "
+		}
+		unread := ""
+		if item.unread > 0 {
+			unread = fmt.Sprintf("%d new", item.unread)
+		}
+		active := ""
+		if !item.lastActivity.IsZero() {
+			active = relativeTime(item.lastActivity, now)
+		}
+		line := fmt.Sprintf("%s%-*s %-6s %7s %8s", marker, nameWidth, truncate(item.target, nameWidth), item.kind, unread, active)
+		style := lipgloss.NewStyle()
+		if i == s.selected {
+			style = style.Bold(true).Foreground(lipgloss.Color(palette.Borders.ActivePane))
+		} else if item.unread > 0 {
+			style = style.Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	lines = append(lines, "", muted.Render(fmt.Sprintf("%d/%d  [↑/↓: Select] [Enter: Open] [Esc: Close]", len(s.matches), len(s.items))))
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(palette.Borders.ActivePane)).
+		Background(lipgloss.Color(palette.Base.Background)).
+		Foreground(lipgloss.Color(palette.Base.Foreground)).
+		Padding(1, 2).
+		Width(panelWidth)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, panel.Render(strings.Join(lines, "\n")))
+}
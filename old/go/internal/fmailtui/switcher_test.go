@@ -0,0 +1,76 @@
+package fmailtui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmail"
+)
+
+func TestFuzzyScorePrefersWordStartsAndRuns(t *testing.T) {
+	require.Equal(t, -1, fuzzyScore("build", "xyz"))
+	require.Greater(t, fuzzyScore("build", "bld"), fuzzyScore("bug-lead", "bld"))
+	require.Greater(t, fuzzyScore("@reviewer", "rev"), fuzzyScore("pre-review", "rev"))
+}
+
+func TestSwitcherFilterRanksByMatchThenUnreadThenActivity(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := switcherState{items: []switcherItem{
+		{target: "ops", kind: switcherKindTopic, lastActivity: now.Add(-time.Hour)},
+		{target: "build", kind: switcherKindTopic, lastActivity: now.Add(-2 * time.Hour)},
+		{target: "@builder", kind: switcherKindDM, unread: 2, lastActivity: now.Add(-3 * time.Hour)},
+		{target: "@idle", kind: switcherKindAgent},
+	}}
+
+	s.filter()
+	require.Equal(t, []string{"@builder", "ops", "build", "@idle"}, switcherTargets(s.matches))
+
+	s.query = "bu"
+	s.filter()
+	require.Equal(t, []string{"@builder", "build"}, switcherTargets(s.matches))
+}
+
+func TestSwitcherJumpsToThread(t *testing.T) {
+	model := newTestModel(t, Config{Agent: "me"})
+	_, err := model.store.SaveMessage(&fmail.Message{From: "coder", To: "build-status", Body: "green"})
+	require.NoError(t, err)
+	_, err = model.store.SaveMessage(&fmail.Message{From: "coder", To: "ops", Body: "paged"})
+	require.NoError(t, err)
+	_, err = model.store.SaveMessage(&fmail.Message{From: "reviewer", To: "@me", Body: "ping"})
+	require.NoError(t, err)
+
+	model = applyUpdate(t, model, tea.KeyMsg{Type: tea.KeyCtrlK})
+	require.True(t, model.switcher.active)
+	targets := switcherTargets(model.switcher.matches)
+	require.Contains(t, targets, "build-status")
+	require.Contains(t, targets, "@reviewer")
+
+	for _, r := range "bst" {
+		model = applyUpdate(t, model, runeKey(r))
+	}
+	require.Equal(t, "build-status", model.switcher.matches[0].target)
+	require.Contains(t, model.View(), "Jump to")
+
+	model = applyUpdateWithCmd(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, model.switcher.active)
+	require.Equal(t, ViewThread, model.activeViewID())
+	thread, ok := model.views[ViewThread].(*threadView)
+	require.True(t, ok)
+	require.Equal(t, "build-status", thread.topic)
+
+	model = applyUpdate(t, model, tea.KeyMsg{Type: tea.KeyCtrlK})
+	model = applyUpdate(t, model, tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, model.switcher.active)
+	require.Equal(t, ViewThread, model.activeViewID())
+}
+
+func switcherTargets(items []switcherItem) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.target)
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package fmailtui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+const themeFilePollInterval = 2 * time.Second
+
+type themeFileTickMsg struct{}
+
+func themeFileTickCmd() tea.Cmd {
+	return tea.Tick(themeFilePollInterval, func(time.Time) tea.Msg { return themeFileTickMsg{} })
+}
+
+// themeFileState tracks the custom theme file so edits can be picked up
+// without restarting the TUI.
+type themeFileState struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// loadThemeFile registers the palettes from path. An empty path clears any
+// previously registered custom palettes.
+func loadThemeFile(path string) (themeFileState, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		styles.SetCustomThemes(nil)
+		return themeFileState{}, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return themeFileState{}, err
+	}
+	state := themeFileState{path: abs}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return themeFileState{}, fmt.Errorf("load theme file: %w", err)
+	}
+	state.modTime, state.size = info.ModTime(), info.Size()
+	themes, err := styles.LoadThemeFile(abs)
+	if err != nil {
+		return themeFileState{}, fmt.Errorf("load theme file %s: %w", abs, err)
+	}
+	styles.SetCustomThemes(themes)
+	return state, nil
+}
+
+// changed reports whether the file on disk differs from the last load.
+func (s themeFileState) changed() (os.FileInfo, bool) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, false
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return info, false
+	}
+	return info, true
+}
+
+// reloadThemeFileIfChanged re-reads the theme file after an edit. A file
+// that fails to parse keeps the previous palettes and reports the error as
+// a toast; the active theme falls back to default if it was removed.
+func (m *Model) reloadThemeFileIfChanged() {
+	if m.themeFile.path == "" {
+		return
+	}
+	info, changed := m.themeFile.changed()
+	if !changed {
+		return
+	}
+	m.themeFile.modTime, m.themeFile.size = info.ModTime(), info.Size()
+
+	themes, err := styles.LoadThemeFile(m.themeFile.path)
+	if err != nil {
+		m.toast = fmt.Sprintf("theme file: %v", err)
+		m.toastUntil = time.Now().UTC().Add(4 * time.Second)
+		return
+	}
+	styles.SetCustomThemes(themes)
+	if _, ok := styles.LookupTheme(string(m.theme)); !ok {
+		m.theme = ThemeDefault
+	}
+	m.toast = fmt.Sprintf("theme file reloaded (%d custom)", len(themes))
+	m.toastUntil = time.Now().UTC().Add(2 * time.Second)
+}
@@ -0,0 +1,93 @@
+package fmailtui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+func TestParseThemeFileOverlaysBuiltIn(t *testing.T) {
+	themes, err := styles.ParseThemeFile([]byte(`
+themes:
+  - name: dusk
+    extends: high-contrast
+    agent_palette: ["33", "37"]
+    base:
+      accent: "208"
+    chrome:
+      selected_item: "99"
+`))
+	require.NoError(t, err)
+	require.Len(t, themes, 1)
+
+	dusk := themes[0]
+	require.Equal(t, "dusk", dusk.Name)
+	require.Equal(t, []string{"33", "37"}, dusk.AgentPalette)
+	require.Equal(t, "208", dusk.Base.Accent)
+	require.Equal(t, "99", dusk.Chrome.SelectedItem)
+	require.Equal(t, styles.HighContrastTheme.Base.Foreground, dusk.Base.Foreground)
+	require.Equal(t, "double", dusk.BorderStyle)
+}
+
+func TestParseThemeFileRejectsBadSpecs(t *testing.T) {
+	for name, raw := range map[string]string{
+		"builtin":   `{"themes": [{"name": "default"}]}`,
+		"extends":   `{"themes": [{"name": "x", "extends": "nope"}]}`,
+		"color":     `{"themes": [{"name": "x", "base": {"accnt": "1"}}]}`,
+		"noname":    `{"themes": [{"base": {"accent": "1"}}]}`,
+		"dup":       `{"themes": [{"name": "x"}, {"name": "x"}]}`,
+		"border":    `{"themes": [{"name": "x", "border_style": "wavy"}]}`,
+		"malformed": `themes: [`,
+	} {
+		_, err := styles.ParseThemeFile([]byte(raw))
+		require.Error(t, err, name)
+	}
+}
+
+func TestThemeFileSelectCycleAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "themes.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"themes": [{"name": "dusk", "base": {"accent": "208"}}]}`), 0o644))
+	t.Cleanup(func() { styles.SetCustomThemes(nil) })
+
+	model, err := NewModel(Config{Root: t.TempDir(), Theme: "dusk", ThemeFile: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, model.Close()) })
+	require.Equal(t, Theme("dusk"), model.theme)
+	require.Equal(t, "208", themePalette(model.theme).Base.Accent)
+
+	require.Equal(t, ThemeDefault, nextTheme(Theme("dusk")))
+	require.Equal(t, Theme("dusk"), nextTheme(ThemeHighContrast))
+
+	// Edit the file: the palette changes in place.
+	require.NoError(t, os.WriteFile(path, []byte(`{"themes": [{"name": "dusk", "base": {"accent": "45"}}]}`), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	model.Update(themeFileTickMsg{})
+	require.Equal(t, "45", themePalette(model.theme).Base.Accent)
+
+	// A broken edit keeps the last good palette.
+	require.NoError(t, os.WriteFile(path, []byte(`{"themes": [`), 0o644))
+	later = later.Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	model.Update(themeFileTickMsg{})
+	require.Equal(t, "45", themePalette(model.theme).Base.Accent)
+	require.Contains(t, model.toast, "theme file:")
+
+	// Removing the active theme falls back to default.
+	require.NoError(t, os.WriteFile(path, []byte(`{"themes": []}`), 0o644))
+	later = later.Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	model.Update(themeFileTickMsg{})
+	require.Equal(t, ThemeDefault, model.theme)
+}
+
+func TestNewModelRejectsMissingThemeFile(t *testing.T) {
+	_, err := NewModel(Config{Root: t.TempDir(), ThemeFile: filepath.Join(t.TempDir(), "missing.yaml")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "load theme file")
+}
@@ -30,12 +30,13 @@ const (
 type threadTickMsg struct{}
 
 type threadLoadedMsg struct {
-	now    time.Time
-	topics []data.TopicInfo
-	topic  string
-	msgs   []fmail.Message
-	total  int
-	err    error
+	now       time.Time
+	topics    []data.TopicInfo
+	topic     string
+	msgs      []fmail.Message
+	total     int
+	reactions map[string][]fmail.ReactionCount
+	err       error
 }
 
 type threadExportResultMsg struct {
@@ -58,6 +59,7 @@ type threadRow struct {
 
 type threadView struct {
 	root     string
+	self     string
 	provider data.MessageProvider
 	state    *tuistate.Manager
 
@@ -81,6 +83,11 @@ type threadView struct {
 	readMarkers    map[string]string
 	bookmarkedIDs  map[string]bool
 	annotations    map[string]string
+	reactions      map[string][]fmail.ReactionCount
+
+	pins          []string // current topic's pinned message IDs, oldest first
+	pinnedIDs     map[string]bool
+	pinsCollapsed bool
 
 	selected int
 	top      int
@@ -101,17 +108,26 @@ type threadView struct {
 	editKind     string // "bookmark-note" | "annotation"
 	editTargetID string
 	editInput    string
+	reactID      string // message the reaction picker targets; "" when closed
 	statusLine   string
 	statusErr    bool
 
 	initialized bool
+
+	lastRefresh     time.Time
+	refreshInterval time.Duration
 }
 
 var _ composeContextView = (*threadView)(nil)
 
-func newThreadView(root string, provider data.MessageProvider, st *tuistate.Manager) *threadView {
+func newThreadView(root, self string, provider data.MessageProvider, st *tuistate.Manager) *threadView {
+	self = strings.TrimSpace(self)
+	if self == "" {
+		self = defaultSelfAgent
+	}
 	return &threadView{
 		root:           root,
+		self:           self,
 		provider:       provider,
 		state:          st,
 		mode:           threadModeThreaded,
@@ -121,6 +137,7 @@ func newThreadView(root string, provider data.MessageProvider, st *tuistate.Mana
 		readMarkers:    make(map[string]string),
 		bookmarkedIDs:  make(map[string]bool),
 		annotations:    make(map[string]string),
+		pinnedIDs:      make(map[string]bool),
 		rowIndexByID:   make(map[string]int),
 	}
 }
@@ -164,7 +181,7 @@ func (v *threadView) ComposeReplySeed(dmDirect bool) (composeReplySeed, bool) {
 
 func (v *threadView) Init() tea.Cmd {
 	v.loadState()
-	return tea.Batch(v.loadCmd(), threadTickCmd())
+	return tea.Batch(v.loadCmd(), v.tickCmd())
 }
 
 func (v *threadView) SetTarget(target string) tea.Cmd {
@@ -190,7 +207,7 @@ func (v *threadView) SetTarget(target string) tea.Cmd {
 func (v *threadView) Update(msg tea.Msg) tea.Cmd {
 	switch typed := msg.(type) {
 	case threadTickMsg:
-		return tea.Batch(v.loadCmd(), threadTickCmd())
+		return tea.Batch(v.loadCmd(), v.tickCmd())
 	case threadLoadedMsg:
 		v.applyLoaded(typed)
 		return nil
@@ -220,11 +237,15 @@ func (v *threadView) View(width, height int, theme Theme) string {
 	palette := themePalette(theme)
 	header := v.renderHeader(width, palette)
 	meta := v.renderMeta(width, palette)
+	pinned := v.renderPinned(width, palette)
 
-	reserved := 0
+	reserved := len(pinned)
 	if v.editActive {
 		reserved += 4
 	}
+	if v.reactID != "" {
+		reserved++
+	}
 	if strings.TrimSpace(v.statusLine) != "" {
 		reserved++
 	}
@@ -237,10 +258,14 @@ func (v *threadView) View(width, height int, theme Theme) string {
 	v.viewportRows = maxInt(1, bodyHeight/4)
 
 	body := v.renderRows(width, bodyHeight, palette)
-	lines := []string{header, meta, body}
+	lines := append([]string{header, meta}, pinned...)
+	lines = append(lines, body)
 	if v.editActive {
 		lines = append(lines, v.renderEditPrompt(width, palette))
 	}
+	if v.reactID != "" {
+		lines = append(lines, renderReactionPicker(width, palette))
+	}
 	if strings.TrimSpace(v.statusLine) != "" {
 		style := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
 		if v.statusErr {
@@ -266,6 +291,10 @@ func (v *threadView) handleKey(msg tea.KeyMsg) tea.Cmd {
 	if v.editActive {
 		return v.handleEditKey(msg)
 	}
+	if v.reactID != "" {
+		v.handleReactKey(msg)
+		return nil
+	}
 
 	switch msg.String() {
 	case "esc", "backspace":
@@ -316,9 +345,25 @@ func (v *threadView) handleKey(msg tea.KeyMsg) tea.Cmd {
 	case "B":
 		v.openBookmarkNoteEditor()
 		return nil
+	case "P":
+		v.bookmarkConfirmID = ""
+		v.togglePin()
+		return nil
+	case "z":
+		v.bookmarkConfirmID = ""
+		v.pinsCollapsed = !v.pinsCollapsed
+		return nil
 	case "a":
 		v.openAnnotationEditor()
 		return nil
+	case "e":
+		v.bookmarkConfirmID = ""
+		v.reactID = v.selectedID()
+		return nil
+	case "E":
+		v.bookmarkConfirmID = ""
+		v.clearReactions()
+		return nil
 	case "X":
 		return v.exportThreadCmd()
 	case "[":
@@ -454,12 +499,25 @@ func (v *threadView) ensureVisible() {
 	v.top = clampInt(v.top, 0, maxTop)
 }
 
-func threadTickCmd() tea.Cmd {
-	return tea.Tick(threadRefreshInterval, func(time.Time) tea.Msg {
+func (v *threadView) tickCmd() tea.Cmd {
+	interval := v.refreshInterval
+	if interval <= 0 {
+		interval = threadRefreshInterval
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
 		return threadTickMsg{}
 	})
 }
 
+func (v *threadView) SetRefreshInterval(interval time.Duration) {
+	v.refreshInterval = interval
+}
+
+func (v *threadView) ForceRefresh() tea.Cmd {
+	v.loadState()
+	return v.loadCmd()
+}
+
 func (v *threadView) rebuildRows(anchorID string, preferBottom bool) {
 	msgs := v.allMsgs
 	rows := make([]threadRow, 0, len(msgs))
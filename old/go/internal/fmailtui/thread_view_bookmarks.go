@@ -2,8 +2,6 @@ package fmailtui
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -183,71 +181,27 @@ func (v *threadView) renderEditPrompt(width int, palette styles.Theme) string {
 	return strings.Join(lines[:4], "\n")
 }
 
+// exportThreadCmd writes the rows currently shown (respecting collapsed
+// replies) to a Markdown file.
 func (v *threadView) exportThreadCmd() tea.Cmd {
-	topic := strings.TrimSpace(v.topic)
-	msgs := append([]fmail.Message(nil), v.allMsgs...)
+	exp := conversationExport{view: "thread", topic: strings.TrimSpace(v.topic)}
+	exp.msgs = make([]fmail.Message, 0, len(v.rows))
+	for _, row := range v.rows {
+		exp.msgs = append(exp.msgs, row.msg)
+	}
 	root := strings.TrimSpace(v.root)
 	return func() tea.Msg {
-		if topic == "" || len(msgs) == 0 || root == "" {
+		if exp.topic == "" {
 			return threadExportResultMsg{err: fmt.Errorf("nothing to export")}
 		}
-		store, err := fmail.NewStore(root)
+		path, err := writeConversationExport(root, exp, time.Now().UTC())
 		if err != nil {
 			return threadExportResultMsg{err: err}
 		}
-		dir := filepath.Join(store.Root, "exports")
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return threadExportResultMsg{err: err}
-		}
-		now := time.Now().UTC()
-		name := fmt.Sprintf("thread-%s-%s.md", sanitizeFilenamePart(topic), now.Format("20060102-150405"))
-		path := filepath.Join(dir, name)
-		if err := os.WriteFile(path, []byte(renderThreadMarkdown(topic, now, msgs)), 0o644); err != nil {
-			return threadExportResultMsg{err: err}
-		}
 		return threadExportResultMsg{path: path}
 	}
 }
 
-func renderThreadMarkdown(topic string, now time.Time, msgs []fmail.Message) string {
-	var b strings.Builder
-	b.WriteString("# Thread - Exported ")
-	b.WriteString(now.Format(time.RFC3339))
-	b.WriteString("\n\n")
-	b.WriteString("Topic: ")
-	b.WriteString(topic)
-	b.WriteString("\n\n")
-	sortMessages(msgs)
-	for _, msg := range msgs {
-		if strings.TrimSpace(msg.ID) == "" {
-			continue
-		}
-		b.WriteString("## ")
-		b.WriteString(msg.ID)
-		b.WriteString("\n")
-		b.WriteString("**From:** ")
-		b.WriteString(strings.TrimSpace(msg.From))
-		b.WriteString(" \u2192 ")
-		b.WriteString(strings.TrimSpace(msg.To))
-		if !msg.Time.IsZero() {
-			b.WriteString(" | **Time:** ")
-			b.WriteString(msg.Time.UTC().Format(time.RFC3339))
-		}
-		b.WriteString("\n\n")
-		body := strings.TrimRight(messageBodyString(msg.Body), "\n")
-		if body == "" {
-			body = "(empty)"
-		}
-		for _, line := range strings.Split(body, "\n") {
-			b.WriteString("> ")
-			b.WriteString(line)
-			b.WriteString("\n")
-		}
-		b.WriteString("\n---\n\n")
-	}
-	return b.String()
-}
-
 func sanitizeFilenamePart(s string) string {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -17,6 +17,7 @@ func (v *threadView) applyLoaded(msg threadLoadedMsg) {
 	if msg.err != nil {
 		return
 	}
+	v.lastRefresh = msg.now
 
 	prevTopic := v.topic
 	prevAnchor := v.selectedID()
@@ -32,6 +33,10 @@ func (v *threadView) applyLoaded(msg threadLoadedMsg) {
 	}
 
 	v.allMsgs = append([]fmail.Message(nil), msg.msgs...)
+	if !reactionCountsEqual(v.reactions, msg.reactions) {
+		v.reactions = msg.reactions
+		v.rowCardCache = nil
+	}
 	if !v.initialized || v.topic != prevTopic {
 		v.limit = maxInt(threadPageSize, v.limit)
 		v.total = msg.total
@@ -62,6 +67,7 @@ func (v *threadView) applyLoaded(msg threadLoadedMsg) {
 		}
 	}
 
+	v.refreshPins()
 	preferBottom := wasAtBottom && prevTopic == v.topic
 	v.rebuildRows(prevAnchor, preferBottom)
 	if preferBottom {
@@ -82,6 +88,7 @@ func (v *threadView) loadCmd() tea.Cmd {
 	if limit <= 0 {
 		limit = threadPageSize
 	}
+	root := v.root
 	return func() tea.Msg {
 		now := time.Now().UTC()
 		topics, err := v.provider.Topics()
@@ -125,6 +132,6 @@ func (v *threadView) loadCmd() tea.Cmd {
 			}
 		}
 
-		return threadLoadedMsg{now: now, topics: sortedTopics, topic: topic, msgs: msgs, total: total}
+		return threadLoadedMsg{now: now, topics: sortedTopics, topic: topic, msgs: msgs, total: total, reactions: loadReactionCounts(root, msgs)}
 	}
 }
@@ -0,0 +1,81 @@
+package fmailtui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/fmailtui/styles"
+)
+
+// threadMaxPinnedRows caps the expanded pinned section so it never crowds
+// out the thread itself.
+const threadMaxPinnedRows = 5
+
+// refreshPins reloads the current topic's pins from tuistate.
+func (v *threadView) refreshPins() {
+	v.pins = v.pins[:0]
+	if v.pinnedIDs == nil {
+		v.pinnedIDs = make(map[string]bool)
+	}
+	for k := range v.pinnedIDs {
+		delete(v.pinnedIDs, k)
+	}
+	if v.state == nil {
+		return
+	}
+	for _, pin := range v.state.Pins(v.topic) {
+		v.pins = append(v.pins, pin.MessageID)
+		v.pinnedIDs[pin.MessageID] = true
+	}
+}
+
+func (v *threadView) togglePin() {
+	if v == nil || v.state == nil {
+		return
+	}
+	id := v.selectedID()
+	topic := strings.TrimSpace(v.topic)
+	if id == "" || topic == "" {
+		return
+	}
+	if v.state.TogglePin(id, topic) {
+		v.statusLine = "pinned"
+	} else {
+		v.statusLine = "unpinned"
+	}
+	v.statusErr = false
+	v.state.SaveSoon()
+	v.refreshPins()
+	v.rowCardCache = nil
+}
+
+func (v *threadView) renderPinned(width int, palette styles.Theme) []string {
+	if len(v.pins) == 0 || width <= 0 {
+		return nil
+	}
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted))
+
+	if v.pinsCollapsed {
+		return []string{truncateVis(accent.Render(fmt.Sprintf("▸ Pinned (%d)", len(v.pins)))+muted.Render("  z expand"), width)}
+	}
+
+	out := []string{truncateVis(accent.Render(fmt.Sprintf("▾ Pinned (%d)", len(v.pins)))+muted.Render("  z collapse  P unpin selected"), width)}
+	// Newest pins first; the oldest overflow into a "+N more" line.
+	shown := 0
+	for i := len(v.pins) - 1; i >= 0 && shown < threadMaxPinnedRows; i-- {
+		id := v.pins[i]
+		line := "  " + shortID(id) + " (not loaded)"
+		if msg, ok := v.msgByID[id]; ok {
+			line = fmt.Sprintf("  %s · %s  %s", msg.From, relativeTime(msg.Time, v.now), firstNonEmptyLine(messageBodyString(msg.Body)))
+		}
+		out = append(out, truncateVis(line, width))
+		shown++
+	}
+	if hidden := len(v.pins) - shown; hidden > 0 {
+		out = append(out, muted.Render(truncateVis(fmt.Sprintf("  +%d more", hidden), width)))
+	}
+	return out
+}
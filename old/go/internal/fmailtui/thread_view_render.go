@@ -27,7 +27,7 @@ func (v *threadView) renderHeader(width int, palette styles.Theme) string {
 	if total > 0 && loaded > 0 && loaded < total {
 		countLabel = fmt.Sprintf("%d/%d messages", loaded, total)
 	}
-	right := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render(fmt.Sprintf("%s  %d participants", countLabel, participants))
+	right := lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Muted)).Render(fmt.Sprintf("%s  %d participants  %s", countLabel, participants, refreshStampLabel(v.lastRefresh)))
 
 	gap := maxInt(1, width-lipgloss.Width(left)-lipgloss.Width(right))
 	return truncateVis(left+strings.Repeat(" ", gap)+right, width)
@@ -146,6 +146,12 @@ func (v *threadView) renderRowCard(row threadRow, width int, selected bool, unre
 	if v.bookmarkedIDs != nil && v.bookmarkedIDs[id] {
 		headerParts = append(headerParts, lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true).Render("★"))
 	}
+	if v.pinnedIDs != nil && v.pinnedIDs[id] {
+		headerParts = append(headerParts, lipgloss.NewStyle().Foreground(lipgloss.Color(palette.Base.Accent)).Bold(true).Render("[pinned]"))
+	}
+	if badge := attachmentBadge(row.msg.Attachments); badge != "" {
+		headerParts = append(headerParts, muted.Render(badge))
+	}
 	header := strings.Join(headerParts, " ")
 	content := []string{header}
 
@@ -185,6 +191,9 @@ func (v *threadView) renderRowCard(row threadRow, width int, selected bool, unre
 	if tags := msgStyles.RenderTagPills(row.msg.Tags); tags != "" {
 		footerParts = append(footerParts, tags)
 	}
+	if pills := renderReactionPills(v.reactions[id], v.self, palette); pills != "" {
+		footerParts = append(footerParts, pills)
+	}
 	if len(footerParts) > 0 {
 		content = append(content, bodyPrefix+strings.Join(footerParts, "  "))
 	}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/tOgg1/forge/internal/fmail"
 	"github.com/tOgg1/forge/internal/fmailtui/data"
+	tuistate "github.com/tOgg1/forge/internal/fmailtui/state"
 )
 
 type stubThreadProvider struct {
@@ -64,7 +65,7 @@ func (s *stubThreadProvider) Subscribe(data.SubscriptionFilter) (<-chan fmail.Me
 
 func TestThreadViewDepthClampAddsOverflowIndicator(t *testing.T) {
 	msgs := makeThreadChain(9)
-	v := newThreadView("", &stubThreadProvider{}, nil)
+	v := newThreadView("", "", &stubThreadProvider{}, nil)
 	v.mode = threadModeThreaded
 	v.allMsgs = msgs
 	v.rebuildRows("", false)
@@ -88,7 +89,7 @@ func TestThreadViewEnterExpandsLongMessage(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.lastWidth = 120
 	v.lastHeight = 30
 	v.applyLoaded(mustLoad(v))
@@ -112,7 +113,7 @@ func TestThreadViewToggleFlatKeepsSelectedMessage(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.lastWidth = 120
 	v.lastHeight = 30
 	v.applyLoaded(mustLoad(v))
@@ -140,7 +141,7 @@ func TestThreadViewPaginationLoadsOlderAtTop(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.lastWidth = 120
 	v.lastHeight = 30
 	v.applyLoaded(mustLoad(v))
@@ -168,7 +169,7 @@ func TestThreadViewPendingNewCountWhenScrolledUp(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.lastWidth = 80
 	v.lastHeight = 12
 	v.applyLoaded(mustLoad(v))
@@ -193,7 +194,7 @@ func TestThreadViewLoadDMTargetUsesDMProvider(t *testing.T) {
 		},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	cmd := v.SetTarget("@bob")
 	require.NotNil(t, cmd)
 
@@ -217,7 +218,7 @@ func TestThreadViewComposeReplySeed(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.lastWidth = 120
 	v.lastHeight = 30
 	v.applyLoaded(mustLoad(v))
@@ -245,7 +246,7 @@ func TestThreadViewRenderMetaShowsSelectionAndUnreadCounts(t *testing.T) {
 		byTopic: map[string][]fmail.Message{"task": msgs},
 	}
 
-	v := newThreadView("", provider, nil)
+	v := newThreadView("", "", provider, nil)
 	v.readMarkers["task"] = msgs[0].ID
 	v.applyLoaded(mustLoad(v))
 
@@ -255,6 +256,41 @@ func TestThreadViewRenderMetaShowsSelectionAndUnreadCounts(t *testing.T) {
 	require.Contains(t, meta, "read:")
 }
 
+func TestThreadViewPinShowsCollapsiblePinnedSection(t *testing.T) {
+	now := time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC)
+	msgs := []fmail.Message{
+		{ID: "20260209-080000-0001", From: "architect", To: "task", Time: now, Body: "deploy freeze until friday"},
+		{ID: "20260209-080001-0001", From: "coder", To: "task", Time: now.Add(time.Second), Body: "ack"},
+	}
+	provider := &stubThreadProvider{
+		topics:  []data.TopicInfo{{Name: "task", LastActivity: msgs[1].Time}},
+		byTopic: map[string][]fmail.Message{"task": msgs},
+	}
+	st := tuistate.New("")
+
+	v := newThreadView("", "", provider, st)
+	v.applyLoaded(mustLoad(v))
+	v.selected = v.indexForID(msgs[0].ID)
+
+	v.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	require.True(t, st.IsPinned(msgs[0].ID, "task"))
+	require.False(t, st.IsBookmarked(msgs[0].ID))
+
+	view := v.View(120, 30, ThemeDefault)
+	require.Contains(t, view, "Pinned (1)")
+	require.Contains(t, view, "deploy freeze until friday")
+
+	v.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	require.True(t, v.pinsCollapsed)
+	view = v.View(120, 30, ThemeDefault)
+	require.Contains(t, view, "Pinned (1)")
+	require.NotContains(t, view, "z collapse")
+
+	v.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	require.False(t, st.IsPinned(msgs[0].ID, "task"))
+	require.NotContains(t, v.View(120, 30, ThemeDefault), "Pinned (")
+}
+
 func mustLoad(v *threadView) threadLoadedMsg {
 	msg, ok := v.loadCmd()().(threadLoadedMsg)
 	if !ok {
@@ -278,3 +314,39 @@ func makeThreadChain(n int) []fmail.Message {
 	}
 	return msgs
 }
+
+func TestThreadViewReactions(t *testing.T) {
+	root := t.TempDir()
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	msgs := []fmail.Message{
+		{ID: "20260209-080000-0001", From: "a", To: "task", Time: time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC), Body: "ship it"},
+	}
+	_, err = store.AddReaction(msgs[0].ID, "rocket", "bob")
+	require.NoError(t, err)
+	provider := &stubThreadProvider{
+		topics:  []data.TopicInfo{{Name: "task", LastActivity: msgs[0].Time}},
+		byTopic: map[string][]fmail.Message{"task": msgs},
+	}
+
+	v := newThreadView(root, "viewer", provider, nil)
+	v.applyLoaded(mustLoad(v))
+	require.Contains(t, v.View(120, 30, ThemeDefault), "🚀1")
+
+	require.Nil(t, v.handleKey(runeKey('e')))
+	require.Equal(t, msgs[0].ID, v.reactID)
+	require.Contains(t, v.View(120, 30, ThemeDefault), "1 👍")
+	require.Nil(t, v.handleKey(runeKey('1')))
+	require.Empty(t, v.reactID)
+	require.Equal(t, "reacted 👍", v.statusLine)
+	require.Contains(t, v.View(120, 30, ThemeDefault), "👍1")
+
+	reactions, err := store.ListReactions(msgs[0].ID)
+	require.NoError(t, err)
+	require.Len(t, reactions, 2)
+
+	require.Nil(t, v.handleKey(runeKey('E')))
+	require.Equal(t, "cleared 1 reaction(s)", v.statusLine)
+	require.NotContains(t, v.View(120, 30, ThemeDefault), "👍1")
+	require.Contains(t, v.View(120, 30, ThemeDefault), "🚀1")
+}
@@ -56,6 +56,7 @@ type timelineLoadedMsg struct {
 	messages          []fmail.Message
 	topicParticipants map[string][]string
 	hasOlder          bool
+	reactions         map[string][]fmail.ReactionCount
 	mode              timelineLoadMode
 	err               error
 }
@@ -71,6 +72,11 @@ type timelineIncomingMsg struct {
 	msg fmail.Message
 }
 
+type timelineExportResultMsg struct {
+	path string
+	err  error
+}
+
 type timelineFilter struct {
 	From        string
 	To          string
@@ -104,6 +110,7 @@ type timelineView struct {
 	visible           []timelineItem
 	repliedByParentID map[string]struct{}
 	bookmarkedIDs     map[string]struct{}
+	reactions         map[string][]fmail.ReactionCount
 	topicParticipants map[string][]string
 
 	mode      timelineMode
@@ -129,12 +136,19 @@ type timelineView struct {
 	noteTargetID    string
 	noteTargetTopic string
 
-	detailOpen bool
-	laneOffset int
+	reactID string // message the reaction picker targets; "" when closed
+
+	detailOpen       bool
+	attachmentStatus string
+	statusLine       string
+	laneOffset       int
 
 	subCh     <-chan fmail.Message
 	subCancel func()
 	loading   bool
+
+	lastRefresh     time.Time
+	refreshInterval time.Duration
 }
 
 var _ composeContextView = (*timelineView)(nil)
@@ -158,14 +172,29 @@ func newTimelineView(root, self string, provider data.MessageProvider, st *tuist
 	}
 }
 
-func timelineTickCmd() tea.Cmd {
-	return tea.Tick(timelineRefreshInterval, func(time.Time) tea.Msg { return timelineTickMsg{} })
+func (v *timelineView) tickCmd() tea.Cmd {
+	interval := v.refreshInterval
+	if interval <= 0 {
+		interval = timelineRefreshInterval
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return timelineTickMsg{} })
+}
+
+func (v *timelineView) SetRefreshInterval(interval time.Duration) {
+	v.refreshInterval = interval
+}
+
+// ForceRefresh reloads the newest page; live messages keep arriving through
+// the subscription in between.
+func (v *timelineView) ForceRefresh() tea.Cmd {
+	v.loading = true
+	return v.loadWindowCmd(data.MessageFilter{Limit: timelineInitialPageSize}, timelineLoadReplace)
 }
 
 func (v *timelineView) Init() tea.Cmd {
 	v.startSubscription()
 	v.loading = true
-	return tea.Batch(v.loadWindowCmd(data.MessageFilter{Limit: timelineInitialPageSize}, timelineLoadReplace), timelineTickCmd(), v.waitForMessageCmd())
+	return tea.Batch(v.loadWindowCmd(data.MessageFilter{Limit: timelineInitialPageSize}, timelineLoadReplace), v.tickCmd(), v.waitForMessageCmd())
 }
 
 func (v *timelineView) Close() {
@@ -204,13 +233,27 @@ func (v *timelineView) Update(msg tea.Msg) tea.Cmd {
 	switch typed := msg.(type) {
 	case timelineTickMsg:
 		v.now = time.Now().UTC()
-		return timelineTickCmd()
+		return v.tickCmd()
 	case timelineLoadedMsg:
 		v.applyLoaded(typed)
 		return nil
 	case timelineIncomingMsg:
 		v.applyIncoming(typed.msg)
 		return v.waitForMessageCmd()
+	case timelineExportResultMsg:
+		if typed.err != nil {
+			v.statusLine = "export failed: " + typed.err.Error()
+		} else {
+			v.statusLine = "exported: " + typed.path
+		}
+		return nil
+	case timelineAttachmentMsg:
+		if typed.err != nil {
+			v.attachmentStatus = "attachment error: " + typed.err.Error()
+		} else {
+			v.attachmentStatus = typed.status
+		}
+		return nil
 	case tea.KeyMsg:
 		return v.handleKey(typed)
 	}
@@ -248,7 +291,7 @@ func (v *timelineView) View(width, height int, theme Theme) string {
 	} else if v.hasOlder {
 		olderLabel = "more"
 	}
-	head := fmt.Sprintf("%s  %s - %s  zoom:%s  filter:%s  older:%s  %d/%d",
+	head := fmt.Sprintf("%s  %s - %s  zoom:%s  filter:%s  older:%s  %d/%d  %s",
 		modeLabel,
 		windowStart.Format("15:04"),
 		windowEnd.Format("15:04"),
@@ -257,6 +300,7 @@ func (v *timelineView) View(width, height int, theme Theme) string {
 		olderLabel,
 		len(v.visible),
 		len(v.all),
+		refreshStampLabel(v.lastRefresh),
 	)
 
 	lines := make([]string, 0, 4)
@@ -270,6 +314,12 @@ func (v *timelineView) View(width, height int, theme Theme) string {
 	if v.noteActive {
 		lines = append(lines, muted.Render("B bookmark note: ")+v.noteInput)
 	}
+	if v.reactID != "" {
+		lines = append(lines, renderReactionPicker(width, palette))
+	}
+	if status := strings.TrimSpace(v.statusLine); status != "" {
+		lines = append(lines, muted.Render(truncateVis(status, maxInt(0, width))))
+	}
 
 	contentHeight := height - len(lines)
 	if contentHeight < 0 {
@@ -298,7 +348,7 @@ func (v *timelineView) View(width, height int, theme Theme) string {
 }
 
 func (v *timelineView) wantsKey(key string) bool {
-	if v.noteActive {
+	if v.noteActive || v.reactID != "" {
 		switch key {
 		case "ctrl+c":
 			return false
@@ -308,10 +358,12 @@ func (v *timelineView) wantsKey(key string) bool {
 	}
 
 	switch key {
-	case "n", "t", "o", "a", "h", "l", "left", "right", "+", "=", "-", "_", "1", "2", "3", "[", "]", "b", "B":
+	case "n", "t", "o", "a", "h", "l", "left", "right", "+", "=", "-", "_", "1", "2", "3", "[", "]", "b", "B", "e", "E":
 		return true
 	case "esc":
 		return v.filterActive || v.jumpActive || v.detailOpen || v.noteActive
+	case "s", "O":
+		return v.detailOpen
 	default:
 		return false
 	}
@@ -327,6 +379,10 @@ func (v *timelineView) handleKey(msg tea.KeyMsg) tea.Cmd {
 	if v.noteActive {
 		return v.handleNoteKey(msg)
 	}
+	if v.reactID != "" {
+		v.handleReactKey(msg)
+		return nil
+	}
 	if v.detailOpen {
 		switch msg.String() {
 		case "enter", "d":
@@ -334,18 +390,30 @@ func (v *timelineView) handleKey(msg tea.KeyMsg) tea.Cmd {
 			return nil
 		case "o":
 			return v.openSelectedInThreadCmd()
+		case "s":
+			return v.exportAttachmentsCmd(false)
+		case "O":
+			return v.exportAttachmentsCmd(true)
 		case "b":
 			v.toggleSelectedBookmark()
 			return nil
 		case "B":
 			v.openSelectedBookmarkNote()
 			return nil
+		case "e":
+			v.detailOpen = false
+			v.openReactionPicker()
+			return nil
+		case "E":
+			v.clearSelectedReactions()
+			return nil
 		case "esc":
 			v.detailOpen = false
 			return nil
 		}
 	}
 
+	v.statusLine = ""
 	switch msg.String() {
 	case "esc", "backspace":
 		return popViewCmd()
@@ -424,16 +492,25 @@ func (v *timelineView) handleKey(msg tea.KeyMsg) tea.Cmd {
 	case "enter":
 		if len(v.visible) > 0 {
 			v.detailOpen = true
+			v.attachmentStatus = ""
 		}
 		return nil
 	case "o":
 		return v.openSelectedInThreadCmd()
+	case "X":
+		return v.exportVisibleCmd()
 	case "b":
 		v.toggleSelectedBookmark()
 		return nil
 	case "B":
 		v.openSelectedBookmarkNote()
 		return nil
+	case "e":
+		v.openReactionPicker()
+		return nil
+	case "E":
+		v.clearSelectedReactions()
+		return nil
 	case "1":
 		v.applyQuickFilter("to", v.selectedTarget())
 		return nil
@@ -725,6 +802,7 @@ func (v *timelineView) waitForMessageCmd() tea.Cmd {
 func (v *timelineView) loadWindowCmd(base data.MessageFilter, mode timelineLoadMode) tea.Cmd {
 	provider := v.provider
 	self := v.self
+	root := v.root
 	return func() tea.Msg {
 		now := time.Now().UTC()
 		if provider == nil {
@@ -806,6 +884,7 @@ func (v *timelineView) loadWindowCmd(base data.MessageFilter, mode timelineLoadM
 			messages:          merged,
 			topicParticipants: flattenParticipants(participants),
 			hasOlder:          hasOlder,
+			reactions:         loadReactionCounts(root, merged),
 			mode:              mode,
 		}
 	}
@@ -818,6 +897,7 @@ func (v *timelineView) applyLoaded(msg timelineLoadedMsg) {
 	if msg.err != nil {
 		return
 	}
+	v.lastRefresh = msg.now
 
 	keepSelection := v.selectedID
 	prevLatest := v.latestTime(v.now)
@@ -826,9 +906,13 @@ func (v *timelineView) applyLoaded(msg timelineLoadedMsg) {
 	if msg.mode == timelineLoadReplace {
 		v.all = append(v.all[:0], msg.messages...)
 		v.topicParticipants = msg.topicParticipants
+		v.reactions = msg.reactions
 	} else {
 		v.all = mergeTimelineMessages(v.all, msg.messages)
 		v.topicParticipants = mergeTopicParticipants(v.topicParticipants, msg.topicParticipants)
+		for id, counts := range msg.reactions {
+			v.reactions = setReactionCounts(v.reactions, id, counts)
+		}
 	}
 	v.hasOlder = msg.hasOlder
 
@@ -1029,6 +1113,12 @@ func (v *timelineView) renderChronological(width, height int, palette styles.The
 		if _, ok := v.bookmarkedIDs[strings.TrimSpace(item.msg.ID)]; ok {
 			head += " ★"
 		}
+		if badge := attachmentBadge(item.msg.Attachments); badge != "" {
+			head += " " + badge
+		}
+		if pills := renderReactionPills(v.reactions[strings.TrimSpace(item.msg.ID)], v.self, palette); pills != "" {
+			head += "  " + pills
+		}
 		head = truncateVis(head, maxInt(0, width-3))
 
 		body := firstNonEmptyLine(messageBodyString(item.msg.Body))
@@ -1224,7 +1314,20 @@ func (v *timelineView) renderDetailOverlay(base string, width, height int, palet
 	if _, ok := v.bookmarkedIDs[strings.TrimSpace(msg.ID)]; ok {
 		lines = append(lines, "Bookmark: yes")
 	}
-	lines = append(lines, "", truncateVis(body, maxInt(20, width-14)), "", "Enter close  o open thread  b bookmark  r reply")
+	if counts := v.reactions[strings.TrimSpace(msg.ID)]; len(counts) > 0 {
+		lines = append(lines, "Reactions: "+renderReactionPills(counts, v.self, palette))
+	}
+	lines = append(lines, "", truncateVis(body, maxInt(20, width-14)))
+	hints := "Enter close  o open thread  b bookmark  e react  r reply"
+	if attachments := attachmentLines(msg.Attachments); len(attachments) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, attachments...)
+		hints += "  s save attachments  O open attachments"
+	}
+	if status := strings.TrimSpace(v.attachmentStatus); status != "" {
+		lines = append(lines, "", truncateVis(status, maxInt(20, width-14)))
+	}
+	lines = append(lines, "", hints)
 	panel := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color(palette.Base.Border)).
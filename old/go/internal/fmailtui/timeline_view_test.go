@@ -2,6 +2,9 @@ package fmailtui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -216,6 +219,89 @@ func TestTimelineDetailOpenAndOpenThreadCommand(t *testing.T) {
 	require.Len(t, batch, 2)
 }
 
+func TestTimelineDetailSavesAndOpensAttachments(t *testing.T) {
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+	root := t.TempDir()
+	store, err := fmail.NewStore(root)
+	require.NoError(t, err)
+	attachment, err := store.SaveAttachment("report.md", strings.NewReader("# report"))
+	require.NoError(t, err)
+
+	v := newTimelineView(root, "viewer", nil, nil)
+	v.now = now
+	v.all = []fmail.Message{
+		{ID: "20260209-095500-0001", From: "alice", To: "task", Time: now.Add(-5 * time.Minute), Body: "see attached", Attachments: []fmail.Attachment{*attachment}},
+	}
+	v.windowEnd = now
+	v.rebuildReplyIndex()
+	v.rebuildVisible()
+
+	require.Nil(t, v.handleKey(tea.KeyMsg{Type: tea.KeyEnter}))
+	require.True(t, v.wantsKey("s"))
+	overlay := v.renderDetailOverlay("", 120, 40, themePalette(ThemeDefault))
+	require.Contains(t, overlay, "report.md")
+	require.Contains(t, overlay, "s save attachments")
+
+	cmd := v.handleKey(runeKey('s'))
+	require.NotNil(t, cmd)
+	require.Nil(t, v.Update(cmd()))
+	saved := filepath.Join(attachmentExportDir(root), "report.md")
+	require.Equal(t, "saved: "+saved, v.attachmentStatus)
+	content, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	require.Equal(t, "# report", string(content))
+
+	t.Setenv("TMPDIR", t.TempDir())
+	var opened []string
+	prev := openAttachmentPath
+	openAttachmentPath = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+	t.Cleanup(func() { openAttachmentPath = prev })
+	cmd = v.handleKey(runeKey('O'))
+	require.NotNil(t, cmd)
+	require.Nil(t, v.Update(cmd()))
+	require.Len(t, opened, 1)
+	require.Equal(t, "opened 1 attachment(s)", v.attachmentStatus)
+}
+
+func TestTimelineExportWritesFilteredMessagesWithFrontMatter(t *testing.T) {
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+	root := t.TempDir()
+	v := newTimelineView(root, "viewer", nil, nil)
+	v.now = now
+	v.all = []fmail.Message{
+		{ID: "20260209-095000-0001", From: "alice", To: "task", Time: now.Add(-10 * time.Minute), Body: "ship it?", Tags: []string{"decision"}},
+		{ID: "20260209-095500-0001", From: "bob", To: "task", Time: now.Add(-5 * time.Minute), Body: "not yet"},
+		{ID: "20260209-095800-0001", From: "alice", To: "task", Time: now.Add(-2 * time.Minute), Body: "ok, tomorrow", ReplyTo: "20260209-095500-0001"},
+	}
+	v.windowEnd = now
+	v.zoomIdx = 3 // 1h window
+	v.filterRaw = "from:alice"
+	v.filter = parseTimelineFilter(v.filterRaw)
+	v.rebuildReplyIndex()
+	v.rebuildVisible()
+
+	cmd := v.handleKey(runeKey('X'))
+	require.NotNil(t, cmd)
+	require.Nil(t, v.Update(cmd()))
+	require.True(t, strings.HasPrefix(v.statusLine, "exported: "), v.statusLine)
+
+	path := strings.TrimPrefix(v.statusLine, "exported: ")
+	require.Equal(t, filepath.Join(root, ".fmail", "exports"), filepath.Dir(path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	out := string(content)
+	require.True(t, strings.HasPrefix(out, "---\nview: timeline\ntopic: \"task\"\nfilter: \"from:alice\"\n"), out)
+	require.Contains(t, out, "messages: 2\n")
+	require.Contains(t, out, "participants: [\"alice\"]\n")
+	require.Contains(t, out, "> ship it?")
+	require.Contains(t, out, "**Tags:** decision")
+	require.Contains(t, out, "**Reply to:** 20260209-095500-0001")
+	require.NotContains(t, out, "not yet")
+}
+
 func TestTimelineBookmarkToggleUsesStateManager(t *testing.T) {
 	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
 	st := tuistate.New(t.TempDir() + "/tui-state.json")
@@ -254,3 +340,28 @@ func filterTimelineMessages(input []fmail.Message, opts data.MessageFilter) []fm
 	}
 	return out
 }
+
+func TestTimelineReactionPickerTogglesReaction(t *testing.T) {
+	now := time.Date(2026, 2, 9, 10, 0, 0, 0, time.UTC)
+	root := t.TempDir()
+	v := newTimelineView(root, "viewer", nil, nil)
+	v.now = now
+	v.all = []fmail.Message{
+		{ID: "20260209-095500-0001", From: "alice", To: "task", Time: now.Add(-5 * time.Minute), Body: "done"},
+	}
+	v.windowEnd = now
+	v.rebuildReplyIndex()
+	v.rebuildVisible()
+
+	require.True(t, v.wantsKey("e"))
+	require.Nil(t, v.handleKey(runeKey('e')))
+	require.True(t, v.wantsKey("4"))
+	require.Nil(t, v.handleKey(runeKey('4')))
+	require.Equal(t, "reacted 🚀", v.statusLine)
+	require.Contains(t, v.View(120, 20, ThemeDefault), "🚀1")
+
+	require.Nil(t, v.handleKey(runeKey('e')))
+	require.Nil(t, v.handleKey(runeKey('4')))
+	require.Equal(t, "removed 🚀", v.statusLine)
+	require.NotContains(t, v.View(120, 20, ThemeDefault), "🚀1")
+}
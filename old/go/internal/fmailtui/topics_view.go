@@ -85,6 +85,7 @@ type topicsItem struct {
 	lastActivity time.Time
 	participants []string
 	unread       int
+	muted        string // muteLabel; muted targets are left out of unread totals
 }
 
 type topicsView struct {
@@ -128,7 +129,10 @@ type topicsView struct {
 	subCh     <-chan fmail.Message
 	subCancel func()
 
-	lastLoad time.Time
+	lastLoad        time.Time
+	refreshInterval time.Duration
+
+	statusLine string
 }
 
 var _ composeContextView = (*topicsView)(nil)
@@ -166,7 +170,7 @@ func (v *topicsView) ComposeReplySeed(_ bool) (composeReplySeed, bool) {
 func (v *topicsView) Init() tea.Cmd {
 	v.loadState()
 	v.startSubscription()
-	return tea.Batch(v.loadCmd(), topicsTickCmd(), v.waitForMessageCmd())
+	return tea.Batch(v.loadCmd(), v.tickCmd(), v.waitForMessageCmd())
 }
 
 func (v *topicsView) Close() {
@@ -183,7 +187,7 @@ func (v *topicsView) Update(msg tea.Msg) tea.Cmd {
 		// Refresh read markers/starred topics in case another view updated the state file.
 		prevMarkers := cloneStringMap(v.readMarkers)
 		v.loadState()
-		cmds := []tea.Cmd{topicsTickCmd()}
+		cmds := []tea.Cmd{v.tickCmd()}
 		if !stringMapEqual(prevMarkers, v.readMarkers) {
 			cmds = append(cmds, v.recomputeUnreadTargetsCmd(changedMarkerKeys(prevMarkers, v.readMarkers)))
 		}
@@ -362,6 +366,7 @@ func (v *topicsView) handleKey(msg tea.KeyMsg) tea.Cmd {
 		}
 	}
 
+	v.statusLine = ""
 	switch msg.String() {
 	case "/":
 		v.filterActive = true
@@ -399,6 +404,18 @@ func (v *topicsView) handleKey(msg tea.KeyMsg) tea.Cmd {
 			v.rebuildItems()
 		}
 		return nil
+	case "M":
+		if target := v.selectedTarget(); target != "" {
+			v.statusLine = toggleMute(v.state, target, time.Now().UTC())
+			v.rebuildItems()
+		}
+		return nil
+	case "z":
+		if target := v.selectedTarget(); target != "" {
+			v.statusLine = cycleSnooze(v.state, target, time.Now().UTC())
+			v.rebuildItems()
+		}
+		return nil
 	case "enter":
 		target := v.selectedTarget()
 		if target == "" {
@@ -438,11 +455,14 @@ func (v *topicsView) renderListPanel(width, height int, palette styles.Theme) st
 	if v.filterActive {
 		filterSuffix = "_"
 	}
-	titleLine := titleStyle.Render(title) + muted.Render(truncateVis(fmt.Sprintf("  (%d)  sort:%s", len(v.items), sortLabel), maxInt(0, innerW-lipgloss.Width(title))))
+	titleLine := titleStyle.Render(title) + muted.Render(truncateVis(fmt.Sprintf("  (%d)  sort:%s  %s", len(v.items), sortLabel, refreshStampLabel(v.lastLoad)), maxInt(0, innerW-lipgloss.Width(title))))
 
-	hints := "j/k move  Enter open  / filter  d toggle  s sort  n compose  Esc back"
+	hints := "j/k move  Enter open  / filter  d toggle  s sort  M mute  z snooze  n compose  R refresh  Esc back"
 	if v.mode == topicsModeTopics {
-		hints = "j/k move  Enter open  / filter  d toggle  s sort  * star  n compose  Esc back"
+		hints = "j/k move  Enter open  / filter  d toggle  s sort  * star  M mute  z snooze  n compose  R refresh  Esc back"
+	}
+	if strings.TrimSpace(v.statusLine) != "" {
+		hints = v.statusLine
 	}
 	keyLine := muted.Render(truncateVis(hints, innerW))
 
@@ -498,7 +518,9 @@ func (v *topicsView) renderRows(width, maxRows int, palette styles.Theme) string
 			cursor = "▸"
 		}
 		unreadStyle := lipgloss.NewStyle()
-		if item.unread > 0 {
+		if item.muted != "" {
+			unreadStyle = unreadStyle.Foreground(lipgloss.Color(palette.Base.Muted))
+		} else if item.unread > 0 {
 			unreadStyle = unreadStyle.Bold(true)
 		}
 
@@ -515,6 +537,9 @@ func (v *topicsView) renderRows(width, maxRows int, palette styles.Theme) string
 		} else {
 			line = fmt.Sprintf("%s  %-20s %s %4d  %-11s %4d", cursor, truncate(item.target, 20), heat, item.messageCount, lastActive, item.unread)
 		}
+		if item.muted != "" {
+			line += "  " + item.muted
+		}
 
 		line = truncateVis(line, width)
 		rowStyle := unreadStyle
@@ -649,12 +674,30 @@ func (v *topicsView) loadCmd() tea.Cmd {
 	}
 }
 
-func topicsTickCmd() tea.Cmd {
-	return tea.Tick(topicsRefreshInterval, func(ts time.Time) tea.Msg {
+func (v *topicsView) tickCmd() tea.Cmd {
+	return tea.Tick(v.tickInterval(), func(ts time.Time) tea.Msg {
 		return topicsTickMsg{now: ts.UTC()}
 	})
 }
 
+func (v *topicsView) tickInterval() time.Duration {
+	if v.refreshInterval > 0 {
+		return v.refreshInterval
+	}
+	return topicsRefreshInterval
+}
+
+// SetRefreshInterval sets the state poll cadence; topic metadata reloads at
+// the same multiple of it as the defaults.
+func (v *topicsView) SetRefreshInterval(interval time.Duration) {
+	v.refreshInterval = interval
+}
+
+func (v *topicsView) ForceRefresh() tea.Cmd {
+	v.loadState()
+	return v.loadCmd()
+}
+
 func (v *topicsView) ensurePreviewCmd() tea.Cmd {
 	target := v.selectedTarget()
 	v.previewTarget = target
@@ -810,6 +853,7 @@ func (v *topicsView) applyIncoming(msg fmail.Message) {
 
 func (v *topicsView) rebuildItems() {
 	filter := strings.ToLower(strings.TrimSpace(v.filter))
+	now := time.Now().UTC()
 	items := make([]topicsItem, 0, len(v.topics))
 
 	if v.mode == topicsModeTopics {
@@ -821,6 +865,7 @@ func (v *topicsView) rebuildItems() {
 				lastActivity: topic.LastActivity,
 				participants: append([]string(nil), topic.Participants...),
 				unread:       v.unreadByTop[topic.Name],
+				muted:        muteLabel(v.state, topic.Name, now),
 			}
 			if filter != "" && !topicMatchesFilter(item, filter) {
 				continue
@@ -838,6 +883,7 @@ func (v *topicsView) rebuildItems() {
 				lastActivity: conv.LastActivity,
 				participants: []string{conv.Agent},
 				unread:       v.unreadByDM[conv.Agent],
+				muted:        muteLabel(v.state, target, now),
 			}
 			if filter != "" && !topicMatchesFilter(item, filter) {
 				continue
@@ -999,7 +1045,14 @@ func (v *topicsView) shouldRefresh(now time.Time) bool {
 	if v.lastLoad.IsZero() {
 		return true
 	}
-	return now.Sub(v.lastLoad) >= topicsMetadataRefresh
+	return now.Sub(v.lastLoad) >= v.metadataRefreshInterval()
+}
+
+func (v *topicsView) metadataRefreshInterval() time.Duration {
+	if v.refreshInterval <= 0 {
+		return topicsMetadataRefresh
+	}
+	return v.refreshInterval * (topicsMetadataRefresh / topicsRefreshInterval)
 }
 
 func (v *topicsView) recomputeUnreadCmd() tea.Cmd {
@@ -0,0 +1,188 @@
+package loop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	loopArtifactsKey = "artifacts"
+
+	// Caps keep a broad glob from copying a whole build tree every
+	// iteration.
+	maxArtifactFiles = 500
+	maxArtifactBytes = 256 << 20
+)
+
+var errArtifactLimit = errors.New("artifact limit reached")
+
+func loadArtifactsConfig(loopEntry *models.Loop) (models.LoopArtifactsConfig, bool) {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return models.LoopArtifactsConfig{}, false
+	}
+	raw, ok := loopEntry.Metadata[loopArtifactsKey]
+	if !ok || raw == nil {
+		return models.LoopArtifactsConfig{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return models.LoopArtifactsConfig{}, false
+	}
+	var cfg models.LoopArtifactsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return models.LoopArtifactsConfig{}, false
+	}
+	return cfg, !cfg.IsZero()
+}
+
+// captureArtifacts copies repo files matching globs into destDir, keeping
+// their repo-relative paths. Files are visited in lexical order; once the
+// file or byte cap is hit the remaining matches are skipped and the files
+// copied so far are returned with errArtifactLimit.
+func captureArtifacts(repoPath, destDir string, globs []string) ([]models.LoopRunArtifact, error) {
+	patterns := make([]string, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.Trim(filepath.ToSlash(strings.TrimSpace(glob)), "/")
+		glob = strings.TrimPrefix(glob, "./")
+		if glob != "" {
+			patterns = append(patterns, glob)
+		}
+	}
+	if repoPath == "" || len(patterns) == 0 {
+		return nil, nil
+	}
+
+	var (
+		artifacts []models.LoopRunArtifact
+		total     int64
+	)
+	err := filepath.WalkDir(repoPath, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(repoPath, current)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !matchesArtifactGlobs(patterns, rel) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if len(artifacts) >= maxArtifactFiles || total+info.Size() > maxArtifactBytes {
+			return errArtifactLimit
+		}
+		if err := copyArtifact(current, filepath.Join(destDir, filepath.FromSlash(rel))); err != nil {
+			return err
+		}
+		total += info.Size()
+		artifacts = append(artifacts, models.LoopRunArtifact{Path: rel, Size: info.Size()})
+		return nil
+	})
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, err
+}
+
+// matchesArtifactGlobs reports whether rel, or one of its parent
+// directories, matches any pattern.
+func matchesArtifactGlobs(patterns []string, rel string) bool {
+	segments := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "/")
+		for n := len(segments); n > 0; n-- {
+			if matchGlobSegments(parts, segments[:n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches path segments against pattern segments, where
+// "**" matches zero or more segments and the rest use path.Match.
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], segments[1:])
+}
+
+func copyArtifact(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// captureRunArtifacts copies the loop's artifact globs for run and records
+// the result on it. Failures are logged; the run's own status is left alone.
+func (r *Runner) captureRunArtifacts(loopEntry *models.Loop, run *models.LoopRun, logWriter *loopLogger) {
+	cfg, ok := loadArtifactsConfig(loopEntry)
+	if !ok || r.Config == nil {
+		return
+	}
+
+	destDir := ArtifactDir(r.Config.Global.DataDir, loopEntry.Name, loopEntry.ID, run.ID)
+	artifacts, err := captureArtifacts(loopEntry.RepoPath, destDir, cfg.Globs)
+	switch {
+	case errors.Is(err, errArtifactLimit):
+		logWriter.WriteLine(fmt.Sprintf("artifact limit reached; captured %d files", len(artifacts)))
+	case err != nil:
+		logWriter.WriteLine(fmt.Sprintf("artifact capture failed: %v", err))
+	}
+	if len(artifacts) == 0 {
+		return
+	}
+	run.ArtifactDir = destDir
+	run.Artifacts = artifacts
+}
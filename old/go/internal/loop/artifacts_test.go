@@ -0,0 +1,149 @@
+package loop
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func writeRepoFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestMatchesArtifactGlobs(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"dist/**", "dist/app", true},
+		{"dist/**", "dist/sub/app.js", true},
+		{"dist", "dist/sub/app.js", true},
+		{"**/*.xml", "report.xml", true},
+		{"**/*.xml", "a/b/report.xml", true},
+		{"reports/*.xml", "reports/sub/x.xml", false},
+		{"*.log", "logs/run.log", false},
+		{"build/**/out.txt", "build/out.txt", true},
+		{"build/**/out.txt", "build/a/b/out.txt", true},
+		{"[", "x", false},
+	}
+	for _, tc := range cases {
+		if got := matchesArtifactGlobs([]string{tc.pattern}, tc.path); got != tc.want {
+			t.Errorf("matchesArtifactGlobs(%q, %q) = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestCaptureArtifactsCopiesMatches(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoFile(t, repoDir, "dist/app.js", "app")
+	writeRepoFile(t, repoDir, "dist/css/site.css", "css!")
+	writeRepoFile(t, repoDir, "reports/junit.xml", "<xml/>")
+	writeRepoFile(t, repoDir, "src/main.go", "package main")
+	writeRepoFile(t, repoDir, ".git/dist/HEAD", "ref")
+
+	destDir := filepath.Join(t.TempDir(), "run-1")
+	artifacts, err := captureArtifacts(repoDir, destDir, []string{"./dist/**", " reports/*.xml ", ""})
+	if err != nil {
+		t.Fatalf("captureArtifacts: %v", err)
+	}
+
+	var paths []string
+	for _, artifact := range artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	if strings.Join(paths, ",") != "dist/app.js,dist/css/site.css,reports/junit.xml" {
+		t.Fatalf("unexpected artifacts %v", paths)
+	}
+	if artifacts[1].Size != 4 {
+		t.Fatalf("expected size 4 for site.css, got %d", artifacts[1].Size)
+	}
+	data, err := os.ReadFile(filepath.Join(destDir, "dist", "css", "site.css"))
+	if err != nil || string(data) != "css!" {
+		t.Fatalf("copied artifact = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "src")); !os.IsNotExist(err) {
+		t.Fatalf("expected unmatched files to be skipped, stat err=%v", err)
+	}
+}
+
+func TestRunnerRecordsRunArtifacts(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	repoDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.Global.ConfigDir = t.TempDir()
+
+	profileRepo := db.NewProfileRepository(database)
+	loopRepo := db.NewLoopRepository(database)
+	runRepo := db.NewLoopRunRepository(database)
+
+	profile := &models.Profile{
+		Name:            "artifact-profile",
+		Harness:         models.HarnessPi,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "pi -p \"$FORGE_PROMPT_CONTENT\"",
+		MaxConcurrency:  1,
+	}
+	if err := profileRepo.Create(context.Background(), profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+
+	loopEntry := &models.Loop{
+		Name:            "Build Loop",
+		RepoPath:        repoDir,
+		BasePromptMsg:   "base",
+		IntervalSeconds: 1,
+		ProfileID:       profile.ID,
+		State:           models.LoopStateStopped,
+		Metadata: map[string]any{"artifacts": models.LoopArtifactsConfig{
+			Globs: []string{"out/*.txt"},
+		}},
+	}
+	if err := loopRepo.Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runner := NewRunner(database, cfg)
+	runner.Exec = func(ctx context.Context, p models.Profile, promptPath, promptContent, workDir string, output io.Writer) (int, string, error) {
+		writeRepoFile(t, workDir, "out/result.txt", "built")
+		return 0, "done", nil
+	}
+
+	if err := runner.RunOnce(context.Background(), loopEntry.ID); err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+
+	runs, err := runRepo.ListByLoop(context.Background(), loopEntry.ID)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("list runs: %v (%d runs)", err, len(runs))
+	}
+	run := runs[0]
+	wantDir := filepath.Join(cfg.Global.DataDir, "artifacts", "loops", "build-loop", run.ID)
+	if run.ArtifactDir != wantDir {
+		t.Fatalf("artifact dir = %q, want %q", run.ArtifactDir, wantDir)
+	}
+	if len(run.Artifacts) != 1 || run.Artifacts[0].Path != "out/result.txt" || run.Artifacts[0].Size != 5 {
+		t.Fatalf("unexpected artifacts %v", run.Artifacts)
+	}
+	if _, err := os.Stat(filepath.Join(wantDir, "out", "result.txt")); err != nil {
+		t.Fatalf("expected copied artifact: %v", err)
+	}
+}
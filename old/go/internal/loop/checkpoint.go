@@ -0,0 +1,283 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	loopCheckpointKey = "checkpoint"
+
+	// CheckpointRefPrefix is where commit-mode checkpoints are kept, one ref
+	// per run under the loop's ID.
+	CheckpointRefPrefix = "refs/forge/checkpoints/"
+
+	// checkpointKeep is how many commit-mode checkpoint refs are kept per
+	// loop; older ones are deleted as new ones are taken.
+	checkpointKeep = 50
+
+	checkpointAuthor = "forge"
+	checkpointEmail  = "forge@localhost"
+)
+
+// CheckpointModeForLoop returns the loop's configured checkpoint mode.
+func CheckpointModeForLoop(loopEntry *models.Loop) models.LoopCheckpointMode {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return models.LoopCheckpointOff
+	}
+	value, _ := loopEntry.Metadata[loopCheckpointKey].(string)
+	mode, err := models.ParseLoopCheckpointMode(value)
+	if err != nil {
+		return models.LoopCheckpointOff
+	}
+	return mode
+}
+
+// RunCheckpoint returns the checkpoint recorded on run, if any.
+func RunCheckpoint(run *models.LoopRun) (models.LoopCheckpoint, bool) {
+	if run == nil || run.Metadata == nil {
+		return models.LoopCheckpoint{}, false
+	}
+	raw, ok := run.Metadata[loopCheckpointKey]
+	if !ok || raw == nil {
+		return models.LoopCheckpoint{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return models.LoopCheckpoint{}, false
+	}
+	var checkpoint models.LoopCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return models.LoopCheckpoint{}, false
+	}
+	return checkpoint, checkpoint.SHA != ""
+}
+
+// NextRunCheckpoint returns the checkpoint of the first run started after
+// runID that has one, or nil. runs must be ordered newest first, as
+// LoopRunRepository.ListByLoop returns them.
+func NextRunCheckpoint(runs []*models.LoopRun, runID string) *models.LoopCheckpoint {
+	for i, run := range runs {
+		if run.ID != runID {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if checkpoint, ok := RunCheckpoint(runs[j]); ok {
+				return &checkpoint
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// checkpointRun snapshots the repo before an iteration and records the
+// result on run. Failures are logged and recorded; they never abort the
+// iteration.
+func (r *Runner) checkpointRun(ctx context.Context, loopEntry *models.Loop, run *models.LoopRun, logWriter *loopLogger) {
+	mode := CheckpointModeForLoop(loopEntry)
+	if mode == models.LoopCheckpointOff {
+		return
+	}
+	checkpoint, err := CreateCheckpoint(ctx, loopEntry.RepoPath, mode, loopEntry.ID, run.ID)
+	if err != nil {
+		checkpoint.Error = err.Error()
+		logWriter.WriteLine(fmt.Sprintf("checkpoint failed: %v", err))
+	}
+	if run.Metadata == nil {
+		run.Metadata = make(map[string]any)
+	}
+	run.Metadata[loopCheckpointKey] = checkpoint
+}
+
+// CreateCheckpoint snapshots repoPath. Commit mode writes the working tree,
+// untracked files included, to a commit whose parent is HEAD and points
+// refs/forge/checkpoints/<loopID>/<runID> at it. Stash mode stores
+// uncommitted changes to tracked files as a stash entry; with nothing to
+// stash the checkpoint is HEAD. Neither mode changes HEAD, the index, or the
+// working tree.
+func CreateCheckpoint(ctx context.Context, repoPath string, mode models.LoopCheckpointMode, loopID, runID string) (models.LoopCheckpoint, error) {
+	checkpoint := models.LoopCheckpoint{Mode: mode}
+	head, _ := runCheckpointGit(ctx, repoPath, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+	checkpoint.Head = head
+	message := fmt.Sprintf("forge checkpoint: loop %s run %s", loopID, runID)
+
+	switch mode {
+	case models.LoopCheckpointCommit:
+		tree, err := snapshotTree(ctx, repoPath, head)
+		if err != nil {
+			return checkpoint, err
+		}
+		args := []string{"commit-tree", tree, "-m", message}
+		if head != "" {
+			args = append(args, "-p", head)
+		}
+		sha, err := runCheckpointGit(ctx, repoPath, checkpointIdentity(), args...)
+		if err != nil {
+			return checkpoint, err
+		}
+		ref := CheckpointRefPrefix + loopID + "/" + runID
+		if _, err := runCheckpointGit(ctx, repoPath, nil, "update-ref", ref, sha); err != nil {
+			return checkpoint, err
+		}
+		checkpoint.SHA = sha
+		checkpoint.Ref = ref
+		pruneCheckpointRefs(ctx, repoPath, loopID)
+		return checkpoint, nil
+
+	case models.LoopCheckpointStash:
+		if head == "" {
+			return checkpoint, errors.New("stash checkpoints need at least one commit")
+		}
+		sha, err := runCheckpointGit(ctx, repoPath, checkpointIdentity(), "stash", "create", message)
+		if err != nil {
+			return checkpoint, err
+		}
+		if sha == "" {
+			checkpoint.SHA = head
+			return checkpoint, nil
+		}
+		if _, err := runCheckpointGit(ctx, repoPath, checkpointIdentity(), "stash", "store", "-m", message, sha); err != nil {
+			return checkpoint, err
+		}
+		checkpoint.SHA = sha
+		checkpoint.Ref = message
+		return checkpoint, nil
+
+	default:
+		return checkpoint, fmt.Errorf("invalid checkpoint mode %q", mode)
+	}
+}
+
+// CheckpointDiff returns the unified diff from checkpoint to next, the
+// checkpoint of the following run. With no next it diffs against the
+// working tree, including untracked files for commit-mode checkpoints.
+func CheckpointDiff(ctx context.Context, repoPath string, checkpoint models.LoopCheckpoint, next *models.LoopCheckpoint) (string, error) {
+	if checkpoint.SHA == "" {
+		return "", errors.New("run has no checkpoint")
+	}
+	args := []string{"diff", "--no-color", checkpoint.SHA}
+	switch {
+	case next != nil && next.SHA != "":
+		args = append(args, next.SHA)
+	case checkpoint.Mode == models.LoopCheckpointCommit:
+		head, _ := runCheckpointGit(ctx, repoPath, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+		tree, err := snapshotTree(ctx, repoPath, head)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, tree)
+	}
+	return runCheckpointGitRaw(ctx, repoPath, nil, args...)
+}
+
+// RestoreCheckpoint makes the working tree and index match a checkpoint,
+// leaving HEAD where it is so the rollback shows up as uncommitted changes.
+// Uncommitted work, untracked files included, is first saved as a stash
+// entry, whose name is returned.
+func RestoreCheckpoint(ctx context.Context, repoPath, sha string) (string, error) {
+	if strings.TrimSpace(sha) == "" {
+		return "", errors.New("checkpoint SHA is required")
+	}
+	if _, err := runCheckpointGit(ctx, repoPath, nil, "cat-file", "-e", sha+"^{tree}"); err != nil {
+		return "", fmt.Errorf("checkpoint %s not found: %w", shortSHA(sha), err)
+	}
+	status, err := runCheckpointGit(ctx, repoPath, nil, "status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	backup := ""
+	if status != "" {
+		message := "forge: before rollback to " + shortSHA(sha)
+		if _, err := runCheckpointGit(ctx, repoPath, checkpointIdentity(), "stash", "push", "--include-untracked", "-m", message); err != nil {
+			return "", fmt.Errorf("save uncommitted changes: %w", err)
+		}
+		backup = "stash@{0}"
+	}
+	if _, err := runCheckpointGit(ctx, repoPath, nil, "read-tree", "-u", "--reset", sha); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// snapshotTree writes the working tree, untracked but not ignored files
+// included, to a tree object using a throwaway index.
+func snapshotTree(ctx context.Context, repoPath, head string) (string, error) {
+	dir, err := os.MkdirTemp("", "forge-checkpoint-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(dir, "index")}
+
+	if head != "" {
+		if _, err := runCheckpointGit(ctx, repoPath, env, "read-tree", head); err != nil {
+			return "", err
+		}
+	}
+	if _, err := runCheckpointGit(ctx, repoPath, env, "add", "--all"); err != nil {
+		return "", err
+	}
+	return runCheckpointGit(ctx, repoPath, env, "write-tree")
+}
+
+func pruneCheckpointRefs(ctx context.Context, repoPath, loopID string) {
+	out, err := runCheckpointGit(ctx, repoPath, nil, "for-each-ref", "--sort=-committerdate", "--format=%(refname)", CheckpointRefPrefix+loopID+"/")
+	if err != nil || out == "" {
+		return
+	}
+	refs := strings.Split(out, "\n")
+	for i := checkpointKeep; i < len(refs); i++ {
+		_, _ = runCheckpointGit(ctx, repoPath, nil, "update-ref", "-d", refs[i])
+	}
+}
+
+// checkpointIdentity lets checkpoint commits and stash entries be written in
+// repos without a configured user.
+func checkpointIdentity() []string {
+	return []string{
+		"GIT_AUTHOR_NAME=" + checkpointAuthor,
+		"GIT_AUTHOR_EMAIL=" + checkpointEmail,
+		"GIT_COMMITTER_NAME=" + checkpointAuthor,
+		"GIT_COMMITTER_EMAIL=" + checkpointEmail,
+	}
+}
+
+func runCheckpointGit(ctx context.Context, repoPath string, env []string, args ...string) (string, error) {
+	out, err := runCheckpointGitRaw(ctx, repoPath, env, args...)
+	return strings.TrimSpace(out), err
+}
+
+func runCheckpointGitRaw(ctx context.Context, repoPath string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return stdout.String(), fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
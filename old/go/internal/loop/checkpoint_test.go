@@ -0,0 +1,149 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func initCheckpointRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	gitT(t, repo, "init", "-q")
+	writeRepoFile(t, repo, "main.go", "package main\n")
+	gitT(t, repo, "add", "main.go")
+	gitT(t, repo, "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", "init")
+	return repo
+}
+
+func gitT(t *testing.T, repo string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCommitCheckpointDiffAndRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := initCheckpointRepo(t)
+	head := gitT(t, repo, "rev-parse", "HEAD")
+	writeRepoFile(t, repo, "main.go", "package main\n\nfunc main() {}\n")
+	writeRepoFile(t, repo, "notes.txt", "untracked\n")
+	statusBefore := gitT(t, repo, "status", "--porcelain")
+
+	checkpoint, err := CreateCheckpoint(ctx, repo, models.LoopCheckpointCommit, "loop-1", "run-1")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if checkpoint.Head != head || checkpoint.Ref != CheckpointRefPrefix+"loop-1/run-1" {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+	if got := gitT(t, repo, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("checkpoint moved HEAD to %s", got)
+	}
+	if got := gitT(t, repo, "status", "--porcelain"); got != statusBefore {
+		t.Fatalf("checkpoint changed status:\n%s\nwant:\n%s", got, statusBefore)
+	}
+	if got := gitT(t, repo, "rev-parse", checkpoint.Ref); got != checkpoint.SHA {
+		t.Fatalf("ref points at %s, want %s", got, checkpoint.SHA)
+	}
+	if files := gitT(t, repo, "ls-tree", "--name-only", checkpoint.SHA); !strings.Contains(files, "notes.txt") {
+		t.Fatalf("expected untracked file in checkpoint, got %q", files)
+	}
+
+	// The iteration edits a file and adds another.
+	writeRepoFile(t, repo, "main.go", "package main\n\nfunc main() { println(1) }\n")
+	writeRepoFile(t, repo, "added.go", "package main\n")
+	diff, err := CheckpointDiff(ctx, repo, checkpoint, nil)
+	if err != nil {
+		t.Fatalf("CheckpointDiff: %v", err)
+	}
+	if !strings.Contains(diff, "+func main() { println(1) }") || !strings.Contains(diff, "added.go") {
+		t.Fatalf("diff missing iteration changes:\n%s", diff)
+	}
+	if strings.Contains(diff, "notes.txt") {
+		t.Fatalf("diff includes changes from before the checkpoint:\n%s", diff)
+	}
+
+	backup, err := RestoreCheckpoint(ctx, repo, checkpoint.SHA)
+	if err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	if backup == "" {
+		t.Fatalf("expected uncommitted work to be stashed")
+	}
+	data, err := os.ReadFile(filepath.Join(repo, "main.go"))
+	if err != nil || string(data) != "package main\n\nfunc main() {}\n" {
+		t.Fatalf("main.go not restored: %q %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "added.go")); !os.IsNotExist(err) {
+		t.Fatalf("expected added.go to be gone after rollback, got %v", err)
+	}
+	if got := gitT(t, repo, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("rollback moved HEAD to %s", got)
+	}
+}
+
+func TestStashCheckpointLeavesWorkingTree(t *testing.T) {
+	ctx := context.Background()
+	repo := initCheckpointRepo(t)
+	head := gitT(t, repo, "rev-parse", "HEAD")
+
+	clean, err := CreateCheckpoint(ctx, repo, models.LoopCheckpointStash, "loop-1", "run-1")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint clean: %v", err)
+	}
+	if clean.SHA != head || clean.Ref != "" {
+		t.Fatalf("expected clean checkpoint at HEAD, got %+v", clean)
+	}
+
+	writeRepoFile(t, repo, "main.go", "package main // edited\n")
+	dirty, err := CreateCheckpoint(ctx, repo, models.LoopCheckpointStash, "loop-1", "run-2")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint dirty: %v", err)
+	}
+	if dirty.SHA == head || !strings.Contains(gitT(t, repo, "stash", "list"), "run-2") {
+		t.Fatalf("expected a stash entry for run-2, got %+v", dirty)
+	}
+	if got := gitT(t, repo, "status", "--porcelain"); got != "M main.go" {
+		t.Fatalf("stash checkpoint changed the working tree: %q", got)
+	}
+
+	diff, err := CheckpointDiff(ctx, repo, clean, &dirty)
+	if err != nil {
+		t.Fatalf("CheckpointDiff: %v", err)
+	}
+	if !strings.Contains(diff, "+package main // edited") {
+		t.Fatalf("expected diff between checkpoints, got:\n%s", diff)
+	}
+}
+
+func TestRunCheckpointRoundTripsMetadata(t *testing.T) {
+	run := &models.LoopRun{Metadata: map[string]any{
+		"checkpoint": map[string]any{"mode": "commit", "sha": "abc123", "ref": "refs/forge/checkpoints/l/r"},
+	}}
+	checkpoint, ok := RunCheckpoint(run)
+	if !ok || checkpoint.Mode != models.LoopCheckpointCommit || checkpoint.SHA != "abc123" {
+		t.Fatalf("unexpected checkpoint %+v ok=%v", checkpoint, ok)
+	}
+	if _, ok := RunCheckpoint(&models.LoopRun{}); ok {
+		t.Fatalf("expected no checkpoint on a run without metadata")
+	}
+
+	loopEntry := &models.Loop{Metadata: map[string]any{"checkpoint": "STASH"}}
+	if mode := CheckpointModeForLoop(loopEntry); mode != models.LoopCheckpointStash {
+		t.Fatalf("expected stash mode, got %q", mode)
+	}
+}
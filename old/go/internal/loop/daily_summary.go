@@ -0,0 +1,256 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	// MetadataDailySummaryDisabled opts a loop out of daily summaries.
+	MetadataDailySummaryDisabled = "daily_summary_disabled"
+
+	loopDailySummaryLastKey = "daily_summary_last"
+	dailySummaryMaxErrors   = 3
+	dailySummarySender      = "forge"
+)
+
+// dailySummary aggregates one loop's runs over a closed day.
+type dailySummary struct {
+	LoopID     string
+	LoopName   string
+	Date       string
+	Start      time.Time
+	End        time.Time
+	Iterations int
+	Succeeded  int
+	Failed     int
+	Killed     int
+	Commits    int
+	CostUSD    float64
+	HasCost    bool
+	Errors     []string
+}
+
+// dailySummaryWindow returns the most recent closed day at or before now.
+func dailySummaryWindow(now time.Time, cfg config.DailySummaryConfig) (start, end time.Time, err error) {
+	loc, err := cfg.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	hour, minute, err := cfg.ClockTime()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	local := now.In(loc)
+	end = time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if local.Before(end) {
+		end = end.AddDate(0, 0, -1)
+	}
+	return end.AddDate(0, 0, -1), end, nil
+}
+
+func dailySummaryDisabled(loop *models.Loop) bool {
+	if loop == nil || loop.Metadata == nil {
+		return false
+	}
+	switch v := loop.Metadata[MetadataDailySummaryDisabled].(type) {
+	case bool:
+		return v
+	case string:
+		disabled, _ := strconv.ParseBool(v)
+		return disabled
+	}
+	return false
+}
+
+func lastDailySummary(loop *models.Loop) string {
+	if loop.Metadata == nil {
+		return ""
+	}
+	value, _ := loop.Metadata[loopDailySummaryLastKey].(string)
+	return value
+}
+
+// maybeWriteDailySummary emits the summary for the last closed day once per
+// day. The loop's metadata records the day so callers must persist the loop.
+func (r *Runner) maybeWriteDailySummary(ctx context.Context, loop *models.Loop, runRepo *db.LoopRunRepository, logWriter *loopLogger, now time.Time) {
+	cfg := r.Config.LoopDefaults.DailySummary
+	if !cfg.Enabled || dailySummaryDisabled(loop) {
+		return
+	}
+
+	start, end, err := dailySummaryWindow(now, cfg)
+	if err != nil {
+		logWriter.WriteLine(fmt.Sprintf("daily summary disabled: %v", err))
+		return
+	}
+	date := start.Format("2006-01-02")
+	if lastDailySummary(loop) >= date {
+		return
+	}
+
+	runs, err := runRepo.ListByLoop(ctx, loop.ID)
+	if err != nil {
+		logWriter.WriteLine(fmt.Sprintf("daily summary failed: %v", err))
+		return
+	}
+	summary := buildDailySummary(loop, runs, start, end)
+	if summary.Iterations > 0 {
+		summary.Commits = countCommits(ctx, loop.RepoPath, start, end)
+		if err := r.publishDailySummary(loop, summary); err != nil {
+			logWriter.WriteLine(fmt.Sprintf("daily summary failed: %v", err))
+			return
+		}
+		logWriter.WriteLine(fmt.Sprintf("daily summary written for %s", date))
+	}
+
+	if loop.Metadata == nil {
+		loop.Metadata = make(map[string]any)
+	}
+	loop.Metadata[loopDailySummaryLastKey] = date
+}
+
+func buildDailySummary(loop *models.Loop, runs []*models.LoopRun, start, end time.Time) dailySummary {
+	summary := dailySummary{
+		LoopID:   loop.ID,
+		LoopName: loop.Name,
+		Date:     start.Format("2006-01-02"),
+		Start:    start,
+		End:      end,
+	}
+	seenErrors := make(map[string]struct{})
+	// ListByLoop returns newest first, so the first errors seen are the latest.
+	for _, run := range runs {
+		if run.StartedAt.Before(start) || !run.StartedAt.Before(end) {
+			continue
+		}
+		summary.Iterations++
+		switch run.Status {
+		case models.LoopRunStatusSuccess:
+			summary.Succeeded++
+		case models.LoopRunStatusError:
+			summary.Failed++
+			if line := lastNonEmptyLine(run.OutputTail); line != "" && len(summary.Errors) < dailySummaryMaxErrors {
+				if _, seen := seenErrors[line]; !seen {
+					seenErrors[line] = struct{}{}
+					summary.Errors = append(summary.Errors, line)
+				}
+			}
+		case models.LoopRunStatusKilled:
+			summary.Killed++
+		}
+		if cost, ok := run.Metadata["cost_usd"].(float64); ok {
+			summary.CostUSD += cost
+			summary.HasCost = true
+		}
+	}
+	return summary
+}
+
+func (r *Runner) publishDailySummary(loop *models.Loop, summary dailySummary) error {
+	cfg := r.Config.LoopDefaults.DailySummary
+	body := renderDailySummary(summary)
+
+	if cfg.WriteReport {
+		path := DailySummaryPath(r.Config.Global.DataDir, loop.Name, loop.ID, summary.Date)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			return err
+		}
+	}
+
+	topic := strings.TrimSpace(cfg.Topic)
+	if topic == "" || strings.TrimSpace(loop.RepoPath) == "" {
+		return nil
+	}
+	store, err := fmail.NewStore(loop.RepoPath)
+	if err != nil {
+		return err
+	}
+	_, err = store.SaveMessage(&fmail.Message{
+		From: dailySummarySender,
+		To:   topic,
+		Body: body,
+		Tags: []string{"daily-summary", loopSlug(loop.Name)},
+	})
+	return err
+}
+
+// DailySummaryPath returns the Markdown report path for a loop's day.
+func DailySummaryPath(dataDir, name, id, date string) string {
+	slug := loopSlug(name)
+	if slug == "" {
+		slug = id
+	}
+	return filepath.Join(dataDir, "reports", "daily", slug, date+".md")
+}
+
+func renderDailySummary(s dailySummary) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("# Daily summary: %s (%s)\n\n", s.LoopName, s.Date))
+	b.WriteString(fmt.Sprintf("- window: %s to %s\n", s.Start.Format("2006-01-02 15:04 MST"), s.End.Format("2006-01-02 15:04 MST")))
+	b.WriteString(fmt.Sprintf("- iterations: %d\n", s.Iterations))
+	b.WriteString(fmt.Sprintf("- succeeded: %d\n", s.Succeeded))
+	b.WriteString(fmt.Sprintf("- failed: %d\n", s.Failed))
+	if s.Killed > 0 {
+		b.WriteString(fmt.Sprintf("- killed: %d\n", s.Killed))
+	}
+	b.WriteString(fmt.Sprintf("- commits: %d\n", s.Commits))
+	if s.HasCost {
+		b.WriteString(fmt.Sprintf("- cost: $%.2f\n", s.CostUSD))
+	}
+	if len(s.Errors) > 0 {
+		b.WriteString("\n## Notable errors\n\n")
+		for _, line := range s.Errors {
+			b.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+	}
+	return b.String()
+}
+
+// countCommits counts commits on HEAD authored within the window. Errors (no
+// git, not a repository) count as zero.
+func countCommits(ctx context.Context, repoPath string, start, end time.Time) int {
+	if strings.TrimSpace(repoPath) == "" {
+		return 0
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-list", "--count",
+		"--since="+start.UTC().Format(time.RFC3339),
+		"--until="+end.UTC().Format(time.RFC3339),
+		"HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return 0
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+func lastNonEmptyLine(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			if len(line) > 200 {
+				line = line[:200] + "..."
+			}
+			return line
+		}
+	}
+	return ""
+}
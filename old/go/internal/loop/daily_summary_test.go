@@ -0,0 +1,147 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/fmail"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func TestDailySummaryWindowUsesTimezone(t *testing.T) {
+	cfg := config.DailySummaryConfig{Time: "18:00", Timezone: "America/New_York"}
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// 20:00 local is after today's close.
+	start, end, err := dailySummaryWindow(time.Date(2026, 3, 10, 20, 0, 0, 0, loc), cfg)
+	if err != nil {
+		t.Fatalf("window: %v", err)
+	}
+	if want := time.Date(2026, 3, 10, 18, 0, 0, 0, loc); !end.Equal(want) {
+		t.Fatalf("end = %s, want %s", end, want)
+	}
+	if want := time.Date(2026, 3, 9, 18, 0, 0, 0, loc); !start.Equal(want) {
+		t.Fatalf("start = %s, want %s", start, want)
+	}
+
+	// 09:00 local has not reached today's close; the previous day is reported.
+	_, end, err = dailySummaryWindow(time.Date(2026, 3, 10, 9, 0, 0, 0, loc), cfg)
+	if err != nil {
+		t.Fatalf("window: %v", err)
+	}
+	if want := time.Date(2026, 3, 9, 18, 0, 0, 0, loc); !end.Equal(want) {
+		t.Fatalf("end = %s, want %s", end, want)
+	}
+}
+
+func TestMaybeWriteDailySummaryPostsOncePerDay(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	repoDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.LoopDefaults.DailySummary = config.DailySummaryConfig{
+		Enabled:     true,
+		Time:        "18:00",
+		Timezone:    "UTC",
+		Topic:       "loop-daily",
+		WriteReport: true,
+	}
+
+	profile := &models.Profile{Name: "pi", Harness: models.HarnessPi, CommandTemplate: "pi", MaxConcurrency: 1}
+	if err := db.NewProfileRepository(database).Create(ctx, profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+	loopEntry := &models.Loop{Name: "Daily Loop", RepoPath: repoDir, ProfileID: profile.ID, State: models.LoopStateStopped}
+	if err := db.NewLoopRepository(database).Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runRepo := db.NewLoopRunRepository(database)
+	now := time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC)
+	runs := []struct {
+		startedAt time.Time
+		status    models.LoopRunStatus
+		tail      string
+	}{
+		{now.Add(-30 * time.Hour), models.LoopRunStatusError, "outside window"},
+		{now.Add(-20 * time.Hour), models.LoopRunStatusSuccess, "ok"},
+		{now.Add(-10 * time.Hour), models.LoopRunStatusError, "step\nerror: build failed\n"},
+		{now.Add(-5 * time.Hour), models.LoopRunStatusError, "error: build failed"},
+		{now.Add(-30 * time.Minute), models.LoopRunStatusSuccess, "after close"},
+	}
+	for _, spec := range runs {
+		run := &models.LoopRun{LoopID: loopEntry.ID, ProfileID: profile.ID, Status: spec.status, StartedAt: spec.startedAt, OutputTail: spec.tail}
+		if err := runRepo.Create(ctx, run); err != nil {
+			t.Fatalf("create run: %v", err)
+		}
+	}
+
+	logWriter, err := newLoopLogger(filepath.Join(t.TempDir(), "loop.log"))
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+	defer logWriter.Close()
+
+	runner := NewRunner(database, cfg)
+	runner.maybeWriteDailySummary(ctx, loopEntry, runRepo, logWriter, now)
+	runner.maybeWriteDailySummary(ctx, loopEntry, runRepo, logWriter, now.Add(time.Hour))
+
+	if got := lastDailySummary(loopEntry); got != "2026-03-09" {
+		t.Fatalf("last summary = %q", got)
+	}
+
+	report, err := os.ReadFile(DailySummaryPath(cfg.Global.DataDir, loopEntry.Name, loopEntry.ID, "2026-03-09"))
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{"# Daily summary: Daily Loop (2026-03-09)", "- iterations: 3", "- succeeded: 1", "- failed: 2", "- error: build failed"} {
+		if !strings.Contains(string(report), want) {
+			t.Fatalf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Count(string(report), "build failed") != 1 {
+		t.Fatalf("expected notable errors to be deduplicated:\n%s", report)
+	}
+
+	store, err := fmail.NewStore(repoDir)
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	messages, err := store.ListTopicMessages("loop-daily")
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected one summary message, got %d", len(messages))
+	}
+	if messages[0].From != "forge" || !strings.Contains(messages[0].Body.(string), "- iterations: 3") {
+		t.Fatalf("unexpected message: %+v", messages[0])
+	}
+}
+
+func TestMaybeWriteDailySummaryRespectsOptOut(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.LoopDefaults.DailySummary.Enabled = true
+
+	loopEntry := &models.Loop{ID: "loop-1", Name: "quiet", Metadata: map[string]any{MetadataDailySummaryDisabled: true}}
+	runner := NewRunner(nil, cfg)
+	runner.maybeWriteDailySummary(context.Background(), loopEntry, nil, nil, time.Now())
+
+	if got := lastDailySummary(loopEntry); got != "" {
+		t.Fatalf("expected opted-out loop to be skipped, got %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DeleteFile is an on-disk file or directory removed by a cascading delete.
+type DeleteFile struct {
+	Kind  string `json:"kind"` // log, ledger, prompts, artifacts
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+// DeletePlan lists what deleting a loop removes. Run history and queue items
+// always go with the loop record (ON DELETE CASCADE); Files is only
+// populated for a cascading delete.
+type DeletePlan struct {
+	LoopID     string       `json:"loop_id"`
+	Name       string       `json:"name"`
+	Runs       int          `json:"runs"`
+	QueueItems int          `json:"queue_items"`
+	Cascade    bool         `json:"cascade"`
+	Files      []DeleteFile `json:"files,omitempty"`
+	Bytes      int64        `json:"bytes"`
+}
+
+// PlanDelete builds the delete plan for a loop without changing anything.
+// With cascade, the loop's log, ledger, run prompt files, and run artifacts
+// that exist on disk are included with their sizes.
+func PlanDelete(ctx context.Context, database *db.DB, dataDir string, loopEntry *models.Loop, cascade bool) (*DeletePlan, error) {
+	runs, err := db.NewLoopRunRepository(database).CountByLoop(ctx, loopEntry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("count runs: %w", err)
+	}
+	queue, err := db.NewLoopQueueRepository(database).List(ctx, loopEntry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list queue: %w", err)
+	}
+
+	plan := &DeletePlan{
+		LoopID:     loopEntry.ID,
+		Name:       loopEntry.Name,
+		Runs:       runs,
+		QueueItems: len(queue),
+		Cascade:    cascade,
+	}
+	if !cascade {
+		return plan, nil
+	}
+
+	logPath := loopEntry.LogPath
+	if logPath == "" && dataDir != "" {
+		logPath = LogPath(dataDir, loopEntry.Name, loopEntry.ID)
+	}
+	candidates := []DeleteFile{
+		{Kind: "log", Path: logPath},
+		{Kind: "ledger", Path: loopEntry.LedgerPath},
+	}
+	if dataDir != "" {
+		candidates = append(candidates,
+			DeleteFile{Kind: "prompts", Path: filepath.Join(dataDir, "prompts", loopEntry.ID)},
+			DeleteFile{Kind: "artifacts", Path: ArtifactDir(dataDir, loopEntry.Name, loopEntry.ID, "")},
+		)
+	}
+	for _, candidate := range candidates {
+		if candidate.Path == "" {
+			continue
+		}
+		size, err := pathSize(candidate.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", candidate.Path, err)
+		}
+		candidate.Bytes = size
+		plan.Files = append(plan.Files, candidate)
+		plan.Bytes += size
+	}
+	return plan, nil
+}
+
+// ExecuteDelete removes the loop record and then the plan's files. File
+// removal continues past failures; every failure is returned.
+func ExecuteDelete(ctx context.Context, database *db.DB, plan *DeletePlan) error {
+	if err := db.NewLoopRepository(database).Delete(ctx, plan.LoopID); err != nil {
+		return err
+	}
+	var errs []error
+	for _, file := range plan.Files {
+		if err := os.RemoveAll(file.Path); err != nil {
+			errs = append(errs, fmt.Errorf("remove %s %s: %w", file.Kind, file.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func pathSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+	var total int64
+	err = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
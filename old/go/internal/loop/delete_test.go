@@ -0,0 +1,80 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func TestPlanAndExecuteCascadingDelete(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	repoDir := t.TempDir()
+	loopRepo := db.NewLoopRepository(database)
+
+	loopEntry := &models.Loop{
+		Name:            "Loop A",
+		RepoPath:        repoDir,
+		IntervalSeconds: 10,
+		LedgerPath:      LedgerPath(repoDir, "Loop A", ""),
+	}
+	if err := loopRepo.Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	run := &models.LoopRun{LoopID: loopEntry.ID, Status: models.LoopRunStatusSuccess}
+	if err := db.NewLoopRunRepository(database).Create(ctx, run); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	payload, _ := json.Marshal(models.MessageAppendPayload{Text: "hello"})
+	if err := db.NewLoopQueueRepository(database).Enqueue(ctx, loopEntry.ID, &models.LoopQueueItem{Type: models.LoopQueueItemMessageAppend, Payload: payload}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	writeRepoFile(t, dataDir, "logs/loops/loop-a.log", "0123456789")
+	writeRepoFile(t, repoDir, ".forge/ledgers/loop-a.md", "# ledger")
+	writeRepoFile(t, dataDir, "prompts/"+loopEntry.ID+"/run-1.md", "prompt")
+	writeRepoFile(t, dataDir, "artifacts/loops/loop-a/run-1/out.txt", "out")
+
+	plan, err := PlanDelete(ctx, database, dataDir, loopEntry, false)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if plan.Runs != 1 || plan.QueueItems != 1 || len(plan.Files) != 0 || plan.Bytes != 0 {
+		t.Fatalf("unexpected record-only plan: %+v", plan)
+	}
+
+	plan, err = PlanDelete(ctx, database, dataDir, loopEntry, true)
+	if err != nil {
+		t.Fatalf("plan cascade: %v", err)
+	}
+	if len(plan.Files) != 4 {
+		t.Fatalf("expected log, ledger, prompts, artifacts; got %+v", plan.Files)
+	}
+	if want := int64(10 + 8 + 6 + 3); plan.Bytes != want {
+		t.Fatalf("expected %d bytes, got %d", want, plan.Bytes)
+	}
+
+	if err := ExecuteDelete(ctx, database, plan); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if _, err := loopRepo.Get(ctx, loopEntry.ID); err == nil {
+		t.Fatalf("expected loop record to be deleted")
+	}
+	for _, file := range plan.Files {
+		if _, err := os.Stat(file.Path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, stat err=%v", file.Path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "artifacts", "loops")); err != nil {
+		t.Fatalf("expected shared artifacts root to remain: %v", err)
+	}
+}
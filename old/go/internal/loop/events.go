@@ -0,0 +1,73 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// saveLoop persists the loop and records a loop.state_changed event when
+// its state moved to a different class since the last save.
+func (r *Runner) saveLoop(ctx context.Context, loopRepo *db.LoopRepository, loop *models.Loop) error {
+	if err := loopRepo.Update(ctx, loop); err != nil {
+		return err
+	}
+
+	r.statesMu.Lock()
+	if r.loopStates == nil {
+		r.loopStates = make(map[string]models.LoopState)
+	}
+	previous, known := r.loopStates[loop.ID]
+	r.loopStates[loop.ID] = loop.State
+	r.statesMu.Unlock()
+
+	if known && loopStateClass(previous) != loopStateClass(loop.State) {
+		r.recordLoopEvent(ctx, models.EventTypeLoopStateChanged, loop, models.LoopEventPayload{
+			PreviousState: previous,
+			Error:         loop.LastError,
+		})
+	}
+	return nil
+}
+
+// rememberLoopState seeds the state saveLoop compares against.
+func (r *Runner) rememberLoopState(loop *models.Loop) {
+	r.statesMu.Lock()
+	defer r.statesMu.Unlock()
+	if r.loopStates == nil {
+		r.loopStates = make(map[string]models.LoopState)
+	}
+	r.loopStates[loop.ID] = loop.State
+}
+
+// loopStateClass folds sleeping into running so the routine flip between
+// iterations does not produce events.
+func loopStateClass(state models.LoopState) models.LoopState {
+	if state == models.LoopStateSleeping {
+		return models.LoopStateRunning
+	}
+	return state
+}
+
+// recordLoopEvent appends a loop event to the event log. Failures are
+// logged and otherwise ignored so they never stop the loop.
+func (r *Runner) recordLoopEvent(ctx context.Context, eventType models.EventType, loop *models.Loop, payload models.LoopEventPayload) {
+	payload.LoopID = loop.ID
+	payload.LoopName = loop.Name
+	payload.State = loop.State
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	event := &models.Event{
+		Type:       eventType,
+		EntityType: models.EntityTypeLoop,
+		EntityID:   loop.ID,
+		Payload:    data,
+	}
+	if err := db.NewEventRepository(r.DB).Create(ctx, event); err != nil {
+		r.Logger.Warn().Err(err).Str("loop_id", loop.ID).Str("event", string(eventType)).Msg("failed to record loop event")
+	}
+}
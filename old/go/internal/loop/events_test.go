@@ -0,0 +1,80 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func TestRunnerRecordsLoopEvents(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.Global.ConfigDir = t.TempDir()
+
+	profile := &models.Profile{
+		Name:            "pi-default",
+		Harness:         models.HarnessPi,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "pi -p \"$FORGE_PROMPT_CONTENT\"",
+		MaxConcurrency:  1,
+	}
+	if err := db.NewProfileRepository(database).Create(context.Background(), profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+	loopEntry := &models.Loop{
+		Name:            "loop-events",
+		RepoPath:        t.TempDir(),
+		BasePromptMsg:   "base",
+		IntervalSeconds: 1,
+		ProfileID:       profile.ID,
+		State:           models.LoopStateStopped,
+	}
+	if err := db.NewLoopRepository(database).Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runner := NewRunner(database, cfg)
+	runner.Exec = func(ctx context.Context, profile models.Profile, promptPath, promptContent, workDir string, output io.Writer) (int, string, error) {
+		return 2, "boom", errors.New("exit status 2")
+	}
+	if err := runner.RunOnce(context.Background(), loopEntry.ID); err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+
+	events, err := db.NewEventRepository(database).ListByEntity(context.Background(), models.EntityTypeLoop, loopEntry.ID, 10)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	var types []models.EventType
+	var failed models.LoopEventPayload
+	for _, event := range events {
+		types = append(types, event.Type)
+		if event.Type == models.EventTypeLoopRunFailed {
+			if err := json.Unmarshal(event.Payload, &failed); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+		}
+	}
+	want := []models.EventType{models.EventTypeLoopStateChanged, models.EventTypeLoopRunFailed, models.EventTypeLoopStateChanged}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, types)
+		}
+	}
+	if failed.LoopName != "loop-events" || failed.ExitCode == nil || *failed.ExitCode != 2 || failed.RunID == "" {
+		t.Fatalf("unexpected run_failed payload %+v", failed)
+	}
+}
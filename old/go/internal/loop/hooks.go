@@ -0,0 +1,137 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	loopHooksKey = "hooks"
+
+	hookPreRun  = "pre_run"
+	hookPostRun = "post_run"
+
+	hookStderrTailLines = 20
+)
+
+type runHookFunc func(ctx context.Context, workDir, cmd string, env map[string]string, timeout time.Duration) commandResult
+
+func defaultRunHook(ctx context.Context, workDir, cmd string, env map[string]string, timeout time.Duration) commandResult {
+	environ := os.Environ()
+	for key, value := range env {
+		environ = append(environ, key+"="+value)
+	}
+	return runShellCommand(ctx, workDir, cmd, environ, timeout)
+}
+
+func loadHooksConfig(loopEntry *models.Loop) (models.LoopHooksConfig, bool) {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return models.LoopHooksConfig{}, false
+	}
+	raw, ok := loopEntry.Metadata[loopHooksKey]
+	if !ok || raw == nil {
+		return models.LoopHooksConfig{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return models.LoopHooksConfig{}, false
+	}
+	var cfg models.LoopHooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return models.LoopHooksConfig{}, false
+	}
+	return cfg, !cfg.IsZero()
+}
+
+// resolveHookCommand makes a leading relative script path absolute against
+// the repo root so hooks like "scripts/pre.sh --fast" work without "./".
+func resolveHookCommand(repoPath, cmd string) string {
+	cmd = strings.TrimSpace(cmd)
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 || repoPath == "" {
+		return cmd
+	}
+	first := fields[0]
+	if filepath.IsAbs(first) || !strings.Contains(first, "/") {
+		return cmd
+	}
+	candidate := filepath.Join(repoPath, first)
+	if info, err := os.Stat(candidate); err != nil || info.IsDir() {
+		return cmd
+	}
+	return candidate + strings.TrimPrefix(cmd, first)
+}
+
+func hookEnv(loopEntry *models.Loop, run *models.LoopRun, profile *models.Profile) map[string]string {
+	env := map[string]string{
+		"LOOP_ID":   loopEntry.ID,
+		"LOOP_NAME": loopEntry.Name,
+		"RUN_ID":    run.ID,
+	}
+	if profile != nil {
+		env["PROFILE_NAME"] = profile.Name
+	}
+	if run.Status != "" && run.Status != models.LoopRunStatusRunning {
+		env["RUN_STATUS"] = string(run.Status)
+	}
+	if run.ExitCode != nil {
+		env["EXIT_CODE"] = strconv.Itoa(*run.ExitCode)
+	}
+	return env
+}
+
+// runHook executes the named hook for run. Failures are logged and recorded
+// under the run's "hooks" metadata; they never abort the iteration.
+func (r *Runner) runHook(ctx context.Context, name, cmd string, timeout time.Duration, loopEntry *models.Loop, run *models.LoopRun, profile *models.Profile, logWriter *loopLogger) {
+	if strings.TrimSpace(cmd) == "" {
+		return
+	}
+	runHook := r.RunHook
+	if runHook == nil {
+		runHook = defaultRunHook
+	}
+
+	res := runHook(ctx, loopEntry.RepoPath, resolveHookCommand(loopEntry.RepoPath, cmd), hookEnv(loopEntry, run, profile), timeout)
+	if res.err == nil && res.exitCode == 0 {
+		return
+	}
+
+	result := models.LoopHookResult{
+		Cmd:      cmd,
+		ExitCode: res.exitCode,
+		Stderr:   tailText(res.stderr, hookStderrTailLines),
+	}
+	if res.err != nil {
+		result.Error = res.err.Error()
+	}
+	if run.Metadata == nil {
+		run.Metadata = make(map[string]any)
+	}
+	hooks, _ := run.Metadata[loopHooksKey].(map[string]any)
+	if hooks == nil {
+		hooks = make(map[string]any)
+		run.Metadata[loopHooksKey] = hooks
+	}
+	hooks[name] = result
+	logWriter.WriteLine(fmt.Sprintf("%s hook failed (exit_code=%d)", strings.ReplaceAll(name, "_", "-"), res.exitCode))
+}
+
+func tailText(text string, maxLines int) string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" || maxLines <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,145 @@
+package loop
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func TestRunnerRunsHooksAndRecordsFailures(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	repoDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.Global.ConfigDir = t.TempDir()
+
+	profileRepo := db.NewProfileRepository(database)
+	loopRepo := db.NewLoopRepository(database)
+	runRepo := db.NewLoopRunRepository(database)
+
+	profile := &models.Profile{
+		Name:            "hook-profile",
+		Harness:         models.HarnessPi,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "pi -p \"$FORGE_PROMPT_CONTENT\"",
+		MaxConcurrency:  1,
+	}
+	if err := profileRepo.Create(context.Background(), profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+
+	loopEntry := &models.Loop{
+		Name:            "loop-hooks",
+		RepoPath:        repoDir,
+		BasePromptMsg:   "base",
+		IntervalSeconds: 1,
+		ProfileID:       profile.ID,
+		State:           models.LoopStateStopped,
+		Metadata: map[string]any{"hooks": models.LoopHooksConfig{
+			PreRun:         "./pre.sh",
+			PostRun:        "./post.sh",
+			TimeoutSeconds: 5,
+		}},
+	}
+	if err := loopRepo.Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	var calls []string
+	var postEnv map[string]string
+	runner := NewRunner(database, cfg)
+	runner.RunHook = func(ctx context.Context, workDir, cmd string, env map[string]string, timeout time.Duration) commandResult {
+		calls = append(calls, cmd)
+		if workDir != repoDir || timeout != 5*time.Second {
+			t.Fatalf("unexpected hook workdir/timeout: %q %s", workDir, timeout)
+		}
+		if cmd == "./post.sh" {
+			postEnv = env
+			return commandResult{exitCode: 3, stderr: "lint failed\n"}
+		}
+		if _, ok := env["EXIT_CODE"]; ok {
+			t.Fatalf("pre-run hook should not see EXIT_CODE")
+		}
+		return commandResult{exitCode: 0}
+	}
+	runner.Exec = func(ctx context.Context, p models.Profile, promptPath, promptContent, workDir string, output io.Writer) (int, string, error) {
+		calls = append(calls, "exec")
+		return 7, "done", nil
+	}
+
+	if err := runner.RunOnce(context.Background(), loopEntry.ID); err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+
+	if strings.Join(calls, ",") != "./pre.sh,exec,./post.sh" {
+		t.Fatalf("unexpected call order: %v", calls)
+	}
+
+	runs, err := runRepo.ListByLoop(context.Background(), loopEntry.ID)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	run := runs[0]
+	if postEnv["LOOP_ID"] != loopEntry.ID || postEnv["RUN_ID"] != run.ID || postEnv["EXIT_CODE"] != "7" {
+		t.Fatalf("unexpected post-run hook env: %v", postEnv)
+	}
+
+	hooks, ok := run.Metadata["hooks"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected hook failures in run metadata, got %v", run.Metadata)
+	}
+	if _, ok := hooks["pre_run"]; ok {
+		t.Fatalf("successful pre-run hook should not be recorded: %v", hooks)
+	}
+	post, ok := hooks["post_run"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected post_run failure, got %v", hooks)
+	}
+	if post["exit_code"] != float64(3) || post["stderr"] != "lint failed" || post["cmd"] != "./post.sh" {
+		t.Fatalf("unexpected post_run record: %v", post)
+	}
+	if run.Metadata["kind"] != "main" {
+		t.Fatalf("expected run kind preserved, got %v", run.Metadata["kind"])
+	}
+}
+
+func TestDefaultRunHookResolvesRepoScripts(t *testing.T) {
+	repoDir := t.TempDir()
+	script := filepath.Join(repoDir, "scripts", "hook.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$1 $LOOP_ID $EXIT_CODE\"\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+
+	cmd := resolveHookCommand(repoDir, "scripts/hook.sh arg")
+	if cmd != script+" arg" {
+		t.Fatalf("unexpected resolved command %q", cmd)
+	}
+	if got := resolveHookCommand(repoDir, "make lint"); got != "make lint" {
+		t.Fatalf("plain commands should be left alone, got %q", got)
+	}
+
+	res := defaultRunHook(context.Background(), repoDir, cmd, map[string]string{"LOOP_ID": "loop-1", "EXIT_CODE": "0"}, 5*time.Second)
+	if res.err != nil || res.exitCode != 0 {
+		t.Fatalf("hook failed: exit=%d err=%v stderr=%q", res.exitCode, res.err, res.stderr)
+	}
+	if strings.TrimSpace(res.stdout) != "arg loop-1 0" {
+		t.Fatalf("unexpected hook output %q", res.stdout)
+	}
+}
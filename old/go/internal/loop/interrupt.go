@@ -29,10 +29,11 @@ func watchInterrupts(ctx context.Context, database *db.DB, loopID string, starte
 				return nil, err
 			}
 			for _, item := range items {
-				if item.Status != models.LoopQueueStatusPending {
+				if item.Status != models.LoopQueueStatusPending || !item.IsDue(time.Now().UTC()) {
 					continue
 				}
-				if !item.CreatedAt.After(startedAt) {
+				// Scheduled items interrupt the run they become due in.
+				if !item.CreatedAt.After(startedAt) && (item.NotBefore == nil || !item.NotBefore.After(startedAt)) {
 					continue
 				}
 				switch item.Type {
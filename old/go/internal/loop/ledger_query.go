@@ -0,0 +1,255 @@
+package loop
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Ledger is a parsed loop ledger file.
+type Ledger struct {
+	LoopID    string        `json:"loop_id,omitempty" yaml:"loop_id"`
+	LoopName  string        `json:"loop_name,omitempty" yaml:"loop_name"`
+	RepoPath  string        `json:"repo_path,omitempty" yaml:"repo_path"`
+	CreatedAt time.Time     `json:"created_at,omitempty" yaml:"created_at"`
+	Entries   []LedgerEntry `json:"entries"`
+}
+
+// LedgerEntry is one iteration recorded in a ledger. Iteration is the
+// 1-based position of the entry in the file.
+type LedgerEntry struct {
+	Iteration      int                  `json:"iteration"`
+	Timestamp      time.Time            `json:"timestamp"`
+	RunID          string               `json:"run_id,omitempty"`
+	Status         models.LoopRunStatus `json:"status,omitempty"`
+	Profile        string               `json:"profile,omitempty"`
+	Harness        string               `json:"harness,omitempty"`
+	AuthKind       string               `json:"auth_kind,omitempty"`
+	PromptSource   string               `json:"prompt_source,omitempty"`
+	PromptPath     string               `json:"prompt_path,omitempty"`
+	PromptOverride bool                 `json:"prompt_override"`
+	StartedAt      *time.Time           `json:"started_at,omitempty"`
+	FinishedAt     *time.Time           `json:"finished_at,omitempty"`
+	ExitCode       *int                 `json:"exit_code,omitempty"`
+	Output         string               `json:"output,omitempty"`
+	GitSummary     string               `json:"git_summary,omitempty"`
+	// Fields holds every "- key: value" line, including ones this version
+	// does not know about.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// LedgerQuery filters ledger entries. Zero values match everything.
+type LedgerQuery struct {
+	// FromIteration and ToIteration bound the iteration number, inclusive.
+	FromIteration int
+	ToIteration   int
+	// Statuses keeps entries with any of these run statuses.
+	Statuses []models.LoopRunStatus
+	// Since and Until bound the entry timestamp; Until is exclusive.
+	Since time.Time
+	Until time.Time
+	// Limit keeps only the newest matching entries.
+	Limit int
+}
+
+// LedgerSummary aggregates a set of ledger entries.
+type LedgerSummary struct {
+	Entries  int                          `json:"entries"`
+	ByStatus map[models.LoopRunStatus]int `json:"by_status"`
+	First    time.Time                    `json:"first,omitempty"`
+	Last     *LedgerEntry                 `json:"last,omitempty"`
+}
+
+// ReadLedger parses the ledger at path.
+func ReadLedger(path string) (*Ledger, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseLedger(f)
+}
+
+// ParseLedger parses a ledger written by the loop runner: an optional YAML
+// front matter block followed by one "## <RFC3339>" section per iteration.
+// Headings inside fenced output never start a new entry.
+func ParseLedger(r io.Reader) (*Ledger, error) {
+	ledger := &Ledger{Entries: []LedgerEntry{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var (
+		entry       *LedgerEntry
+		frontMatter []string
+		inFront     bool
+		inFence     bool
+		inGit       bool
+		block       []string
+		lineNo      int
+	)
+	flush := func() {
+		if entry != nil {
+			ledger.Entries = append(ledger.Entries, *entry)
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		if lineNo == 1 && line == "---" {
+			inFront = true
+			continue
+		}
+		if inFront {
+			if line == "---" {
+				inFront = false
+				// Loop names are written unquoted, so the header is best effort.
+				_ = yaml.Unmarshal([]byte(strings.Join(frontMatter, "\n")), ledger)
+				continue
+			}
+			frontMatter = append(frontMatter, line)
+			continue
+		}
+
+		if strings.HasPrefix(line, "```") {
+			if inFence {
+				inFence = false
+				if entry != nil {
+					text := strings.Join(block, "\n")
+					if inGit {
+						entry.GitSummary = text
+					} else {
+						entry.Output = text
+					}
+				}
+			} else {
+				inFence = true
+				block = block[:0]
+			}
+			continue
+		}
+		if inFence {
+			block = append(block, line)
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "## "); ok {
+			if ts, err := time.Parse(time.RFC3339, strings.TrimSpace(rest)); err == nil {
+				flush()
+				entry = &LedgerEntry{Iteration: len(ledger.Entries) + 1, Timestamp: ts, Fields: map[string]string{}}
+				inGit = false
+				continue
+			}
+		}
+		if entry == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "### Git Summary") {
+			inGit = true
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "- "); ok {
+			if key, value, ok := strings.Cut(rest, ":"); ok {
+				entry.setField(strings.TrimSpace(key), strings.TrimSpace(value))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return ledger, nil
+}
+
+func (e *LedgerEntry) setField(key, value string) {
+	e.Fields[key] = value
+	switch key {
+	case "run_id":
+		e.RunID = value
+	case "status":
+		e.Status = models.LoopRunStatus(value)
+	case "profile":
+		e.Profile = value
+	case "harness":
+		e.Harness = value
+	case "auth_kind":
+		e.AuthKind = value
+	case "prompt_source":
+		e.PromptSource = value
+	case "prompt_path":
+		e.PromptPath = value
+	case "prompt_override":
+		e.PromptOverride = value == "true"
+	case "started_at":
+		if ts, err := time.Parse(time.RFC3339, value); err == nil {
+			e.StartedAt = &ts
+		}
+	case "finished_at":
+		if ts, err := time.Parse(time.RFC3339, value); err == nil {
+			e.FinishedAt = &ts
+		}
+	case "exit_code":
+		if code, err := strconv.Atoi(value); err == nil {
+			e.ExitCode = &code
+		}
+	}
+}
+
+// Query returns the entries matching q in file order.
+func (l *Ledger) Query(q LedgerQuery) []LedgerEntry {
+	matches := make([]LedgerEntry, 0, len(l.Entries))
+	for _, entry := range l.Entries {
+		if q.matches(entry) {
+			matches = append(matches, entry)
+		}
+	}
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[len(matches)-q.Limit:]
+	}
+	return matches
+}
+
+func (q LedgerQuery) matches(entry LedgerEntry) bool {
+	if q.FromIteration > 0 && entry.Iteration < q.FromIteration {
+		return false
+	}
+	if q.ToIteration > 0 && entry.Iteration > q.ToIteration {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.Timestamp.Before(q.Until) {
+		return false
+	}
+	if len(q.Statuses) == 0 {
+		return true
+	}
+	for _, status := range q.Statuses {
+		if entry.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SummarizeLedger counts entries per status and records the latest one.
+func SummarizeLedger(entries []LedgerEntry) LedgerSummary {
+	summary := LedgerSummary{Entries: len(entries), ByStatus: map[models.LoopRunStatus]int{}}
+	for _, entry := range entries {
+		summary.ByStatus[entry.Status]++
+	}
+	if len(entries) > 0 {
+		summary.First = entries[0].Timestamp
+		last := entries[len(entries)-1]
+		summary.Last = &last
+	}
+	return summary
+}
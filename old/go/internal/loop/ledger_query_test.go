@@ -0,0 +1,140 @@
+package loop
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const sampleLedger = "---\n" +
+	"loop_id: loop-1\n" +
+	"loop_name: build\n" +
+	"repo_path: /repo\n" +
+	"created_at: 2026-01-01T00:00:00Z\n" +
+	"---\n\n" +
+	"# Loop Ledger: build\n\n" +
+	"## 2026-01-01T10:00:00Z\n\n" +
+	"- run_id: run-1\n" +
+	"- status: success\n" +
+	"- profile: claude\n" +
+	"- prompt_source: base\n" +
+	"- prompt_override: false\n" +
+	"- started_at: 2026-01-01T09:59:00Z\n" +
+	"- exit_code: 0\n\n" +
+	"```\n" +
+	"## 2026-01-01T10:30:00Z\n" +
+	"- status: killed\n" +
+	"```\n\n" +
+	"## 2026-01-01T11:00:00Z\n\n" +
+	"- run_id: run-2\n" +
+	"- status: error\n" +
+	"- exit_code: 2\n" +
+	"- custom: kept\n\n" +
+	"### Git Summary\n\n" +
+	"```\n" +
+	"status --porcelain:\n" +
+	"  (clean)\n" +
+	"```\n\n" +
+	"## 2026-01-01T12:00:00Z\n\n" +
+	"- run_id: run-3\n" +
+	"- status: success\n\n"
+
+func TestParseLedger(t *testing.T) {
+	ledger, err := ParseLedger(strings.NewReader(sampleLedger))
+	if err != nil {
+		t.Fatalf("ParseLedger: %v", err)
+	}
+	if ledger.LoopID != "loop-1" || ledger.LoopName != "build" || ledger.RepoPath != "/repo" {
+		t.Fatalf("unexpected header %+v", ledger)
+	}
+	if len(ledger.Entries) != 3 {
+		t.Fatalf("expected 3 entries (fenced headings ignored), got %d", len(ledger.Entries))
+	}
+
+	first := ledger.Entries[0]
+	if first.Iteration != 1 || first.RunID != "run-1" || first.Status != models.LoopRunStatusSuccess || first.Profile != "claude" {
+		t.Fatalf("unexpected first entry %+v", first)
+	}
+	if first.ExitCode == nil || *first.ExitCode != 0 || first.StartedAt == nil {
+		t.Fatalf("expected exit code and start time, got %+v", first)
+	}
+	if !strings.Contains(first.Output, "- status: killed") {
+		t.Fatalf("expected fenced output, got %q", first.Output)
+	}
+
+	second := ledger.Entries[1]
+	if second.Iteration != 2 || second.Fields["custom"] != "kept" || second.Output != "" {
+		t.Fatalf("unexpected second entry %+v", second)
+	}
+	if second.GitSummary != "status --porcelain:\n  (clean)" {
+		t.Fatalf("unexpected git summary %q", second.GitSummary)
+	}
+}
+
+func TestLedgerQuery(t *testing.T) {
+	ledger, err := ParseLedger(strings.NewReader(sampleLedger))
+	if err != nil {
+		t.Fatalf("ParseLedger: %v", err)
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query LedgerQuery
+		want  []string
+	}{
+		{"all", LedgerQuery{}, []string{"run-1", "run-2", "run-3"}},
+		{"iterations", LedgerQuery{FromIteration: 2, ToIteration: 2}, []string{"run-2"}},
+		{"status", LedgerQuery{Statuses: []models.LoopRunStatus{models.LoopRunStatusSuccess}}, []string{"run-1", "run-3"}},
+		{"time range", LedgerQuery{Since: base.Add(10 * time.Hour), Until: base.Add(12 * time.Hour)}, []string{"run-1", "run-2"}},
+		{"limit keeps newest", LedgerQuery{Limit: 2}, []string{"run-2", "run-3"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, entry := range ledger.Query(tt.query) {
+			got = append(got, entry.RunID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	summary := SummarizeLedger(ledger.Entries)
+	if summary.Entries != 3 || summary.ByStatus[models.LoopRunStatusSuccess] != 2 || summary.ByStatus[models.LoopRunStatusError] != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if summary.Last == nil || summary.Last.RunID != "run-3" {
+		t.Fatalf("expected last entry run-3, got %+v", summary.Last)
+	}
+}
+
+func TestReadLedgerRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	loopEntry := &models.Loop{ID: "loop-1", Name: "demo: build", RepoPath: dir, LedgerPath: filepath.Join(dir, "ledger.md")}
+	if err := ensureLedgerFile(loopEntry); err != nil {
+		t.Fatalf("ensureLedgerFile: %v", err)
+	}
+	exitCode := 1
+	run := &models.LoopRun{ID: "run-9", Status: models.LoopRunStatusError, PromptSource: "base", StartedAt: time.Now(), ExitCode: &exitCode}
+	if err := appendLedgerEntry(loopEntry, run, &models.Profile{Name: "codex"}, "line one\nline two", 10); err != nil {
+		t.Fatalf("appendLedgerEntry: %v", err)
+	}
+
+	ledger, err := ReadLedger(loopEntry.LedgerPath)
+	if err != nil {
+		t.Fatalf("ReadLedger: %v", err)
+	}
+	if len(ledger.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(ledger.Entries))
+	}
+	entry := ledger.Entries[0]
+	if entry.RunID != "run-9" || entry.Status != models.LoopRunStatusError || entry.Profile != "codex" || entry.Output != "line one\nline two" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if entry.ExitCode == nil || *entry.ExitCode != 1 {
+		t.Fatalf("expected exit code 1, got %v", entry.ExitCode)
+	}
+}
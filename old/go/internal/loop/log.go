@@ -2,51 +2,338 @@ package loop
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+)
+
+// Loop log formats.
+const (
+	LogFormatText  = "text"
+	LogFormatJSONL = "jsonl"
 )
 
+// Log record streams.
+const (
+	LogStreamLoop   = "loop"
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+// LogRecord is one line of a JSONL loop log.
+type LogRecord struct {
+	Time      time.Time `json:"ts"`
+	Stream    string    `json:"stream"`
+	Iteration int       `json:"iteration,omitempty"`
+	RunID     string    `json:"run_id,omitempty"`
+	Harness   string    `json:"harness,omitempty"`
+	// Event is the harness event type for JSON harness output lines.
+	Event string `json:"event,omitempty"`
+	Text  string `json:"text"`
+}
+
+// ParseLogRecord decodes a JSONL loop log line. ok is false for text-format
+// lines, including raw harness JSON.
+func ParseLogRecord(line string) (LogRecord, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, `{"ts":`) {
+		return LogRecord{}, false
+	}
+	var rec LogRecord
+	if err := json.Unmarshal([]byte(trimmed), &rec); err != nil || rec.Stream == "" {
+		return LogRecord{}, false
+	}
+	return rec, true
+}
+
+// FormatLogRecord renders a record the way the text format writes it: runner
+// messages get a timestamp prefix, harness output is shown as-is.
+func FormatLogRecord(rec LogRecord) string {
+	if rec.Stream == LogStreamLoop {
+		return "[" + rec.Time.UTC().Format(time.RFC3339) + "] " + rec.Text
+	}
+	return rec.Text
+}
+
+// DisplayLogLine returns the text form of a loop log line in either format.
+func DisplayLogLine(line string) string {
+	if rec, ok := ParseLogRecord(line); ok {
+		return FormatLogRecord(rec)
+	}
+	return line
+}
+
+// LogOptions controls the loop log writer.
+type LogOptions struct {
+	// Format is LogFormatText (default) or LogFormatJSONL.
+	Format string
+	// MaxBytes rotates the log once it would grow past this size (0 = never).
+	MaxBytes int64
+	// MaxFiles is how many rotated files (<log>.1 ... <log>.N) to keep.
+	MaxFiles int
+}
+
+func logOptionsFromConfig(cfg config.LoopLogConfig) LogOptions {
+	return LogOptions{
+		Format:   cfg.Format,
+		MaxBytes: int64(cfg.MaxSizeMB) * 1024 * 1024,
+		MaxFiles: cfg.MaxFiles,
+	}
+}
+
 type loopLogger struct {
+	path string
+	opts LogOptions
 	file *os.File
 	mu   sync.Mutex
 	w    *bufio.Writer
+	size int64
+
+	iteration int
+	runID     string
+	harness   string
+	partial   map[string]string
 }
 
 func newLoopLogger(path string) (*loopLogger, error) {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
+	return newLoopLoggerWithOptions(path, LogOptions{})
+}
+
+func newLoopLoggerWithOptions(path string, opts LogOptions) (*loopLogger, error) {
+	if opts.Format == "" {
+		opts.Format = LogFormatText
+	}
+	l := &loopLogger{path: path, opts: opts, partial: make(map[string]string)}
+	if err := l.open(); err != nil {
 		return nil, err
 	}
-	return &loopLogger{file: file, w: bufio.NewWriter(file)}, nil
+	return l, nil
 }
 
+func (l *loopLogger) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	l.w = bufio.NewWriter(file)
+	l.size = 0
+	if info, err := file.Stat(); err == nil {
+		l.size = info.Size()
+	}
+	return nil
+}
+
+func (l *loopLogger) structured() bool {
+	return l.opts.Format == LogFormatJSONL
+}
+
+// Write records harness stdout.
 func (l *loopLogger) Write(p []byte) (int, error) {
+	return l.writeStream(LogStreamStdout, p)
+}
+
+// Stderr returns a writer that records harness stderr.
+func (l *loopLogger) Stderr() io.Writer {
+	return streamWriterFunc(func(p []byte) (int, error) {
+		return l.writeStream(LogStreamStderr, p)
+	})
+}
+
+func (l *loopLogger) writeStream(stream string, p []byte) (int, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	n, err := l.w.Write(p)
-	if err != nil {
-		return n, err
+	if !l.structured() {
+		if err := l.writeRaw(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
 	}
-	return n, l.w.Flush()
+
+	text := l.partial[stream] + string(p)
+	lines := strings.Split(text, "\n")
+	l.partial[stream] = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		if err := l.writeRecord(stream, strings.TrimSuffix(line, "\r")); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
 }
 
 func (l *loopLogger) WriteLine(message string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.structured() {
+		_ = l.writeRecord(LogStreamLoop, message)
+		return
+	}
 	stamp := time.Now().UTC().Format(time.RFC3339)
-	_, _ = l.w.WriteString("[" + stamp + "] " + message + "\n")
-	_ = l.w.Flush()
+	_ = l.writeRaw([]byte("[" + stamp + "] " + message + "\n"))
+}
+
+// BeginRun tags following records with the iteration, run and harness.
+func (l *loopLogger) BeginRun(iteration int, runID, harness string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.iteration = iteration
+	l.runID = runID
+	l.harness = harness
+}
+
+// EndRun flushes partial output lines and clears the run tags.
+func (l *loopLogger) EndRun() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushPartial()
+	l.iteration = 0
+	l.runID = ""
+	l.harness = ""
 }
 
 func (l *loopLogger) Close() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	l.flushPartial()
 	_ = l.w.Flush()
 	_ = l.file.Close()
 }
 
+func (l *loopLogger) flushPartial() {
+	for _, stream := range []string{LogStreamStdout, LogStreamStderr} {
+		if rest := l.partial[stream]; rest != "" {
+			_ = l.writeRecord(stream, rest)
+		}
+		delete(l.partial, stream)
+	}
+}
+
+func (l *loopLogger) writeRecord(stream, text string) error {
+	rec := LogRecord{
+		Time:      time.Now().UTC(),
+		Stream:    stream,
+		Iteration: l.iteration,
+		RunID:     l.runID,
+		Harness:   l.harness,
+		Text:      text,
+	}
+	if stream != LogStreamLoop {
+		rec.Event = harnessEventType(text)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return l.writeRaw(append(data, '\n'))
+}
+
+// writeRaw appends p, rotating first when it would exceed MaxBytes.
+func (l *loopLogger) writeRaw(p []byte) error {
+	if l.opts.MaxBytes > 0 && l.size > 0 && l.size+int64(len(p)) > l.opts.MaxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.w.Write(p)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return l.w.Flush()
+}
+
+// rotate shifts <log>.i to <log>.i+1, drops files past MaxFiles, and starts
+// a fresh log.
+func (l *loopLogger) rotate() error {
+	_ = l.w.Flush()
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if l.opts.MaxFiles <= 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+	_ = os.Remove(rotatedLogPath(l.path, l.opts.MaxFiles))
+	for i := l.opts.MaxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedLogPath(l.path, i), rotatedLogPath(l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, rotatedLogPath(l.path, 1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func rotatedLogPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// harnessEventType extracts the event type from a JSON harness output line
+// (claude/codex/opencode stream formats), or "" for plain text.
+func harnessEventType(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return ""
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &payload); err != nil {
+		return ""
+	}
+	event := ""
+	for _, key := range []string{"type", "event"} {
+		if value, ok := payload[key].(string); ok && strings.TrimSpace(value) != "" {
+			event = strings.ToLower(strings.TrimSpace(value))
+			break
+		}
+	}
+	// Tool calls are nested one level down in most stream formats.
+	if item, ok := payload["item"].(map[string]any); ok {
+		if itemType, ok := item["type"].(string); ok && itemType != "" {
+			event += ":" + strings.ToLower(itemType)
+		}
+	}
+	if message, ok := payload["message"].(map[string]any); ok {
+		if content, ok := message["content"].([]any); ok {
+			for _, part := range content {
+				if entry, ok := part.(map[string]any); ok {
+					if partType, _ := entry["type"].(string); strings.HasPrefix(partType, "tool_") {
+						event += ":" + partType
+						break
+					}
+				}
+			}
+		}
+	}
+	return event
+}
+
+type streamWriterFunc func(p []byte) (int, error)
+
+func (f streamWriterFunc) Write(p []byte) (int, error) { return f(p) }
+
+// splitOutput is passed to Exec when the log records streams separately;
+// executors that support it send harness stderr to Stderr.
+type splitOutput struct {
+	io.Writer
+	stderr io.Writer
+}
+
+func stderrWriter(output io.Writer) io.Writer {
+	if split, ok := output.(*splitOutput); ok {
+		return split.stderr
+	}
+	return output
+}
+
 type tailWriter struct {
 	mu       sync.Mutex
 	maxLines int
@@ -62,6 +349,8 @@ func newTailWriter(maxLines int) *tailWriter {
 }
 
 func (t *tailWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	text := t.buffer + string(p)
 	parts := strings.Split(text, "\n")
 	if len(parts) == 0 {
@@ -71,8 +360,6 @@ func (t *tailWriter) Write(p []byte) (int, error) {
 	t.buffer = parts[len(parts)-1]
 	lines := parts[:len(parts)-1]
 
-	t.mu.Lock()
-	defer t.mu.Unlock()
 	for _, line := range lines {
 		if len(t.lines) >= t.maxLines {
 			t.lines = t.lines[1:]
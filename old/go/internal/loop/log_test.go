@@ -0,0 +1,97 @@
+package loop
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoopLoggerJSONLRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "loop.log")
+	logger, err := newLoopLoggerWithOptions(path, LogOptions{Format: LogFormatJSONL})
+	if err != nil {
+		t.Fatalf("new logger: %v", err)
+	}
+	logger.WriteLine("run r1 start")
+	logger.BeginRun(3, "r1", "claude")
+	_, _ = logger.Write([]byte(`{"type":"assistant","message":{"content":[{"type":"tool_use"}]}}` + "\nhalf"))
+	_, _ = logger.Stderr().Write([]byte("boom\n"))
+	_, _ = logger.Write([]byte(" line\n"))
+	logger.EndRun()
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 records, got %d: %q", len(lines), lines)
+	}
+	records := make([]LogRecord, 0, len(lines))
+	for _, line := range lines {
+		rec, ok := ParseLogRecord(line)
+		if !ok {
+			t.Fatalf("expected JSONL record, got %q", line)
+		}
+		records = append(records, rec)
+	}
+
+	if records[0].Stream != LogStreamLoop || records[0].Iteration != 0 {
+		t.Fatalf("unexpected loop record: %+v", records[0])
+	}
+	if !strings.HasSuffix(FormatLogRecord(records[0]), "] run r1 start") {
+		t.Fatalf("unexpected formatted loop record %q", FormatLogRecord(records[0]))
+	}
+	if records[1].Event != "assistant:tool_use" || records[1].Iteration != 3 || records[1].RunID != "r1" || records[1].Harness != "claude" {
+		t.Fatalf("unexpected harness record: %+v", records[1])
+	}
+	if records[2].Stream != LogStreamStderr || records[2].Text != "boom" {
+		t.Fatalf("unexpected stderr record: %+v", records[2])
+	}
+	if records[3].Stream != LogStreamStdout || records[3].Text != "half line" {
+		t.Fatalf("expected joined partial line, got %+v", records[3])
+	}
+}
+
+func TestLoopLoggerRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "loop.log")
+	logger, err := newLoopLoggerWithOptions(path, LogOptions{MaxBytes: 10, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("new logger: %v", err)
+	}
+	for _, chunk := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := logger.Write([]byte(chunk)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	logger.Close()
+
+	want := map[string]string{
+		path:                    "dddddddd\n",
+		rotatedLogPath(path, 1): "cccccccc\n",
+		rotatedLogPath(path, 2): "bbbbbbbb\n",
+	}
+	for file, content := range want {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if string(data) != content {
+			t.Fatalf("%s = %q, want %q", file, data, content)
+		}
+	}
+	if _, err := os.Stat(rotatedLogPath(path, 3)); !os.IsNotExist(err) {
+		t.Fatalf("expected only %d rotated files to be kept", 2)
+	}
+}
+
+func TestParseLogRecordIgnoresHarnessJSON(t *testing.T) {
+	if _, ok := ParseLogRecord(`{"type":"result","ts":"x"}`); ok {
+		t.Fatalf("expected raw harness JSON not to parse as a log record")
+	}
+	if got := DisplayLogLine("plain text"); got != "plain text" {
+		t.Fatalf("expected text line unchanged, got %q", got)
+	}
+}
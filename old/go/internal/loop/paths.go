@@ -48,3 +48,12 @@ func loopSlug(name string) string {
 	slug := strings.Trim(builder.String(), "-")
 	return slug
 }
+
+// ArtifactDir returns the directory holding a run's captured artifacts.
+func ArtifactDir(dataDir, name, id, runID string) string {
+	slug := loopSlug(name)
+	if slug == "" {
+		slug = id
+	}
+	return filepath.Join(dataDir, "artifacts", "loops", slug, runID)
+}
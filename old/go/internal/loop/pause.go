@@ -0,0 +1,103 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Loop metadata keys set while a pause item holds the runner.
+const (
+	metaPaused      = "paused"
+	metaPauseUntil  = "pause_until"
+	metaPauseReason = "pause_reason"
+)
+
+// IsPaused reports whether the loop's runner is currently holding on a
+// pause item. The runner process stays alive while paused.
+func IsPaused(loop *models.Loop) bool {
+	if loop == nil || loop.Metadata == nil {
+		return false
+	}
+	paused, _ := loop.Metadata[metaPaused].(bool)
+	return paused
+}
+
+// pauseLoop holds the runner between iterations until the pause expires, a
+// resume item is queued behind it, a stop or kill is queued behind it, or
+// ctx ends. The process, its in-memory state, and the ledger stay intact.
+func (r *Runner) pauseLoop(ctx context.Context, loopRepo *db.LoopRepository, queueRepo *db.LoopQueueRepository, loop *models.Loop, plan *queuePlan, logWriter *loopLogger) {
+	var deadline time.Time
+	if plan.PauseDuration > 0 {
+		deadline = time.Now().Add(plan.PauseDuration)
+		logWriter.WriteLine(fmt.Sprintf("pause for %s", plan.PauseDuration))
+	} else {
+		logWriter.WriteLine("paused until resumed")
+	}
+
+	if loop.Metadata == nil {
+		loop.Metadata = make(map[string]any)
+	}
+	loop.Metadata[metaPaused] = true
+	if !deadline.IsZero() {
+		loop.Metadata[metaPauseUntil] = deadline.UTC().Format(time.RFC3339)
+	}
+	if plan.PauseReason != "" {
+		loop.Metadata[metaPauseReason] = plan.PauseReason
+	}
+	loop.State = models.LoopStateSleeping
+	_ = r.saveLoop(ctx, loopRepo, loop)
+	paused := models.LoopEventPayload{Reason: plan.PauseReason}
+	if !deadline.IsZero() {
+		until := deadline.UTC()
+		paused.Until = &until
+	}
+	r.recordLoopEvent(ctx, models.EventTypeLoopPaused, loop, paused)
+
+	poll := r.InterruptPollInterval
+	if poll <= 0 {
+		poll = defaultInterruptInterval
+	}
+	resumed := "pause elapsed"
+	for {
+		wait := poll
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				break
+			}
+			if remaining < wait {
+				wait = remaining
+			}
+		}
+		r.sleep(ctx, wait)
+		if ctx.Err() != nil {
+			return
+		}
+
+		resumeIDs, stopping, err := pendingResume(ctx, queueRepo, loop.ID, plan.PausePosition)
+		if err != nil {
+			continue
+		}
+		if len(resumeIDs) > 0 {
+			_ = markQueueCompleted(ctx, queueRepo, resumeIDs)
+			resumed = "resumed"
+			break
+		}
+		if stopping {
+			resumed = "pause ended by stop request"
+			break
+		}
+	}
+
+	_ = markQueueCompleted(ctx, queueRepo, plan.PauseItemIDs)
+	delete(loop.Metadata, metaPaused)
+	delete(loop.Metadata, metaPauseUntil)
+	delete(loop.Metadata, metaPauseReason)
+	loop.State = models.LoopStateRunning
+	_ = r.saveLoop(ctx, loopRepo, loop)
+	logWriter.WriteLine(resumed)
+}
@@ -16,6 +16,12 @@ type promptSpec struct {
 	Source   string
 	Override bool
 	FromFile bool
+
+	// PlaylistIndex is the 1-based playlist position of the prompt, or 0
+	// when it did not come from a playlist; PlaylistSize is the length of
+	// that playlist.
+	PlaylistIndex int
+	PlaylistSize  int
 }
 
 func resolveBasePrompt(loop *models.Loop) (promptSpec, error) {
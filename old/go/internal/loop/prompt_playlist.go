@@ -0,0 +1,136 @@
+package loop
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const (
+	loopPromptPlaylistKey = "prompt_playlist"
+
+	// promptPlaylistCursorKey holds the next sequential position. It is
+	// kept across runner restarts so a restarted loop resumes the rotation.
+	promptPlaylistCursorKey = "prompt_playlist_cursor"
+
+	// promptPlaylistIndexKey holds the index used by the latest iteration.
+	promptPlaylistIndexKey = "prompt_playlist_index"
+)
+
+// LoadPromptPlaylist returns the loop's prompt playlist, if it has one.
+func LoadPromptPlaylist(loopEntry *models.Loop) (models.LoopPromptPlaylist, bool) {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return models.LoopPromptPlaylist{}, false
+	}
+	raw, ok := loopEntry.Metadata[loopPromptPlaylistKey]
+	if !ok || raw == nil {
+		return models.LoopPromptPlaylist{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return models.LoopPromptPlaylist{}, false
+	}
+	var playlist models.LoopPromptPlaylist
+	if err := json.Unmarshal(data, &playlist); err != nil {
+		return models.LoopPromptPlaylist{}, false
+	}
+	return playlist, !playlist.IsZero()
+}
+
+// ActivePlaylistIndex returns the playlist index used by the loop's latest
+// iteration, or false before the first one.
+func ActivePlaylistIndex(loopEntry *models.Loop) (int, bool) {
+	if loopEntry == nil {
+		return 0, false
+	}
+	return metadataInt(loopEntry.Metadata, promptPlaylistIndexKey)
+}
+
+// nextPlaylistIndex picks the prompt for the next iteration and records the
+// choice in the loop metadata (saved with the loop). Sequential playlists
+// advance a cursor and wrap around; random ones never repeat the previous
+// prompt when there is more than one.
+func nextPlaylistIndex(loopEntry *models.Loop, playlist models.LoopPromptPlaylist, intn func(int) int) int {
+	count := len(playlist.Prompts)
+	if loopEntry.Metadata == nil {
+		loopEntry.Metadata = make(map[string]any)
+	}
+
+	var index int
+	if playlist.NormalizedStrategy() == models.PromptStrategyRandom {
+		last, hasLast := metadataInt(loopEntry.Metadata, promptPlaylistIndexKey)
+		if count > 1 && hasLast && last >= 0 && last < count {
+			index = intn(count - 1)
+			if index >= last {
+				index++
+			}
+		} else {
+			index = intn(count)
+		}
+	} else {
+		cursor, _ := metadataInt(loopEntry.Metadata, promptPlaylistCursorKey)
+		if cursor < 0 {
+			cursor = 0
+		}
+		index = cursor % count
+		loopEntry.Metadata[promptPlaylistCursorKey] = index + 1
+	}
+	loopEntry.Metadata[promptPlaylistIndexKey] = index
+	return index
+}
+
+// resolveIterationPrompt resolves the prompt for one iteration: the next
+// playlist entry when the loop has a playlist, its base prompt otherwise.
+// Overridden iterations neither consume a playlist entry nor need a base
+// prompt.
+func resolveIterationPrompt(loopEntry *models.Loop, overridden bool) (promptSpec, error) {
+	playlist, ok := LoadPromptPlaylist(loopEntry)
+	if !ok {
+		return resolveBasePrompt(loopEntry)
+	}
+	if overridden {
+		return promptSpec{}, nil
+	}
+	return resolvePlaylistPrompt(loopEntry, playlist)
+}
+
+// resolvePlaylistPrompt reads the playlist prompt chosen for this iteration.
+func resolvePlaylistPrompt(loopEntry *models.Loop, playlist models.LoopPromptPlaylist) (promptSpec, error) {
+	index := nextPlaylistIndex(loopEntry, playlist, rand.Intn)
+	path := resolveRepoPath(loopEntry.RepoPath, playlist.Prompts[index])
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return promptSpec{}, fmt.Errorf("playlist prompt %d/%d: %w", index+1, len(playlist.Prompts), err)
+	}
+	return promptSpec{
+		Path:          path,
+		Content:       string(content),
+		Source:        "playlist",
+		FromFile:      true,
+		PlaylistIndex: index + 1,
+		PlaylistSize:  len(playlist.Prompts),
+	}, nil
+}
+
+func metadataInt(metadata map[string]any, key string) (int, bool) {
+	if metadata == nil {
+		return 0, false
+	}
+	switch v := metadata[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		parsed, err := strconv.Atoi(v)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,69 @@
+package loop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestResolveIterationPromptRotatesPlaylist(t *testing.T) {
+	repo := t.TempDir()
+	for _, name := range []string{"plan.md", "build.md", "review.md"} {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	loopEntry := &models.Loop{
+		RepoPath: repo,
+		Metadata: map[string]any{
+			loopPromptPlaylistKey: models.LoopPromptPlaylist{Prompts: []string{"plan.md", "build.md", "review.md"}},
+		},
+	}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		prompt, err := resolveIterationPrompt(loopEntry, false)
+		if err != nil {
+			t.Fatalf("resolve iteration %d: %v", i, err)
+		}
+		if prompt.Source != "playlist" || prompt.PlaylistSize != 3 {
+			t.Fatalf("expected playlist prompt, got %+v", prompt)
+		}
+		got = append(got, prompt.Content)
+	}
+	want := []string{"plan.md", "build.md", "review.md", "plan.md"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected sequential rotation %v, got %v", want, got)
+		}
+	}
+	if index, ok := ActivePlaylistIndex(loopEntry); !ok || index != 0 {
+		t.Fatalf("expected active index 0, got %d %v", index, ok)
+	}
+
+	// Overridden iterations do not consume an entry.
+	if prompt, err := resolveIterationPrompt(loopEntry, true); err != nil || prompt.Path != "" {
+		t.Fatalf("expected empty prompt for override, got %+v %v", prompt, err)
+	}
+	if prompt, _ := resolveIterationPrompt(loopEntry, false); prompt.Content != "build.md" {
+		t.Fatalf("expected rotation to continue after override, got %q", prompt.Content)
+	}
+}
+
+func TestNextPlaylistIndexRandomAvoidsRepeats(t *testing.T) {
+	playlist := models.LoopPromptPlaylist{Prompts: []string{"a", "b", "c"}, Strategy: "Random"}
+	loopEntry := &models.Loop{}
+	zero := func(int) int { return 0 }
+
+	if index := nextPlaylistIndex(loopEntry, playlist, zero); index != 0 {
+		t.Fatalf("expected first pick 0, got %d", index)
+	}
+	if index := nextPlaylistIndex(loopEntry, playlist, zero); index != 1 {
+		t.Fatalf("expected repeat of 0 to be skipped, got %d", index)
+	}
+	if index := nextPlaylistIndex(loopEntry, models.LoopPromptPlaylist{Prompts: []string{"only"}, Strategy: "random"}, zero); index != 0 {
+		t.Fatalf("expected single-entry playlist to repeat, got %d", index)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/tOgg1/forge/internal/db"
@@ -21,7 +22,10 @@ type queuePlan struct {
 	OverridePrompt *models.NextPromptOverridePayload
 	StopRequested  bool
 	KillRequested  bool
-	PauseDuration  time.Duration
+	Paused         bool
+	PauseDuration  time.Duration // zero with Paused set means until resumed
+	PauseReason    string
+	PausePosition  int
 	PauseBeforeRun bool
 	ConsumeItemIDs []string
 	PauseItemIDs   []string
@@ -40,8 +44,9 @@ func buildQueuePlan(ctx context.Context, repo *db.LoopQueueRepository, loopID st
 		plan.Messages = append(plan.Messages, steerMessages...)
 	}
 
+	now := time.Now().UTC()
 	for _, item := range items {
-		if item.Status != models.LoopQueueStatusPending {
+		if item.Status != models.LoopQueueStatusPending || !item.IsDue(now) {
 			continue
 		}
 
@@ -67,7 +72,10 @@ func buildQueuePlan(ctx context.Context, repo *db.LoopQueueRepository, loopID st
 			if err != nil {
 				return nil, err
 			}
+			plan.Paused = true
 			plan.PauseDuration = time.Duration(payload.DurationSeconds) * time.Second
+			plan.PauseReason = payload.Reason
+			plan.PausePosition = item.Position
 			plan.PauseItemIDs = append(plan.PauseItemIDs, item.ID)
 			plan.PauseBeforeRun = plan.OverridePrompt == nil && len(plan.Messages) == 0
 			return plan, nil
@@ -79,6 +87,9 @@ func buildQueuePlan(ctx context.Context, repo *db.LoopQueueRepository, loopID st
 			plan.KillRequested = true
 			plan.KillItemIDs = append(plan.KillItemIDs, item.ID)
 			return plan, nil
+		case models.LoopQueueItemResume:
+			// Nothing is paused at this point; the resume is a no-op.
+			plan.ConsumeItemIDs = append(plan.ConsumeItemIDs, item.ID)
 		case models.LoopQueueItemSteerMessage:
 			payload, err := decodePayload[models.SteerPayload](item.Payload)
 			if err != nil {
@@ -94,6 +105,39 @@ func buildQueuePlan(ctx context.Context, repo *db.LoopQueueRepository, loopID st
 	return plan, nil
 }
 
+// ParseNotBefore parses an earliest-dispatch time for a queue item. It
+// accepts RFC3339, a local HH:MM clock time (the next occurrence of it), or a
+// "+" prefixed duration relative to now. An empty value means no delay.
+func ParseNotBefore(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(value, "+") {
+		d, err := time.ParseDuration(value[1:])
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid delay %q", value[1:])
+		}
+		when := now.Add(d).UTC()
+		return &when, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		when := t.UTC()
+		return &when, nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q (use HH:MM, RFC3339, or +duration)", value)
+	}
+	local := now.Local()
+	when := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	if !when.After(local) {
+		when = when.AddDate(0, 0, 1)
+	}
+	when = when.UTC()
+	return &when, nil
+}
+
 func decodePayload[T any](payload []byte) (T, error) {
 	var data T
 	if err := json.Unmarshal(payload, &data); err != nil {
@@ -111,13 +155,70 @@ func markQueueCompleted(ctx context.Context, repo *db.LoopQueueRepository, ids [
 	return nil
 }
 
+// nextScheduledItem returns when the earliest pending item scheduled after
+// now becomes due.
+func nextScheduledItem(ctx context.Context, repo *db.LoopQueueRepository, loopID string, now time.Time) (time.Time, bool) {
+	items, err := repo.List(ctx, loopID)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var next time.Time
+	for _, item := range items {
+		if item.Status != models.LoopQueueStatusPending || item.IsDue(now) {
+			continue
+		}
+		if next.IsZero() || item.NotBefore.Before(next) {
+			next = *item.NotBefore
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// loopSleepInterval shortens the between-iteration sleep so a scheduled
+// queue item is picked up when it becomes due rather than an interval later.
+func loopSleepInterval(ctx context.Context, repo *db.LoopQueueRepository, loopID string, interval time.Duration) time.Duration {
+	now := time.Now().UTC()
+	next, ok := nextScheduledItem(ctx, repo, loopID, now)
+	if !ok {
+		return interval
+	}
+	if wait := next.Sub(now); wait < interval {
+		return wait
+	}
+	return interval
+}
+
+// pendingResume returns the due resume items queued after the pause at
+// position, and whether a stop or kill is waiting behind the pause.
+func pendingResume(ctx context.Context, repo *db.LoopQueueRepository, loopID string, position int) ([]string, bool, error) {
+	items, err := repo.List(ctx, loopID)
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now().UTC()
+	var resumeIDs []string
+	for _, item := range items {
+		if item.Status != models.LoopQueueStatusPending || !item.IsDue(now) || item.Position <= position {
+			continue
+		}
+		switch item.Type {
+		case models.LoopQueueItemResume:
+			resumeIDs = append(resumeIDs, item.ID)
+		case models.LoopQueueItemStopGraceful, models.LoopQueueItemKillNow:
+			return resumeIDs, true, nil
+		}
+	}
+	return resumeIDs, false, nil
+}
+
 func hasPendingStop(ctx context.Context, repo *db.LoopQueueRepository, loopID string) (bool, error) {
 	items, err := repo.List(ctx, loopID)
 	if err != nil {
 		return false, err
 	}
+	now := time.Now().UTC()
 	for _, item := range items {
-		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemStopGraceful {
+		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemStopGraceful && item.IsDue(now) {
 			return true, nil
 		}
 	}
@@ -129,8 +230,9 @@ func consumePendingStop(ctx context.Context, repo *db.LoopQueueRepository, loopI
 	if err != nil {
 		return err
 	}
+	now := time.Now().UTC()
 	for _, item := range items {
-		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemStopGraceful {
+		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemStopGraceful && item.IsDue(now) {
 			if err := repo.UpdateStatus(ctx, item.ID, models.LoopQueueStatusCompleted, ""); err != nil {
 				return err
 			}
@@ -144,8 +246,9 @@ func hasPendingKill(ctx context.Context, repo *db.LoopQueueRepository, loopID st
 	if err != nil {
 		return false, err
 	}
+	now := time.Now().UTC()
 	for _, item := range items {
-		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemKillNow {
+		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemKillNow && item.IsDue(now) {
 			return true, nil
 		}
 	}
@@ -157,8 +260,9 @@ func consumePendingKill(ctx context.Context, repo *db.LoopQueueRepository, loopI
 	if err != nil {
 		return err
 	}
+	now := time.Now().UTC()
 	for _, item := range items {
-		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemKillNow {
+		if item.Status == models.LoopQueueStatusPending && item.Type == models.LoopQueueItemKillNow && item.IsDue(now) {
 			if err := repo.UpdateStatus(ctx, item.ID, models.LoopQueueStatusCompleted, ""); err != nil {
 				return err
 			}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/models"
@@ -101,3 +102,85 @@ func TestBuildQueuePlanPauseBeforeRun(t *testing.T) {
 		t.Fatalf("expected no consumed items, got %d", len(plan.ConsumeItemIDs))
 	}
 }
+
+func TestBuildQueuePlanSkipsScheduledItems(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	loopRepo := db.NewLoopRepository(database)
+	queueRepo := db.NewLoopQueueRepository(database)
+
+	loop := &models.Loop{
+		Name:            "loop-c",
+		RepoPath:        "/tmp/repo",
+		IntervalSeconds: 600,
+	}
+	if err := loopRepo.Create(ctx, loop); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	later := time.Now().UTC().Add(time.Minute)
+	scheduledPayload, _ := json.Marshal(models.MessageAppendPayload{Text: "at 02:00"})
+	stopPayload, _ := json.Marshal(models.StopPayload{})
+	duePayload, _ := json.Marshal(models.MessageAppendPayload{Text: "now"})
+	items := []*models.LoopQueueItem{
+		{Type: models.LoopQueueItemMessageAppend, Payload: scheduledPayload, NotBefore: &later},
+		{Type: models.LoopQueueItemStopGraceful, Payload: stopPayload, NotBefore: &later},
+		{Type: models.LoopQueueItemMessageAppend, Payload: duePayload},
+	}
+	if err := queueRepo.Enqueue(ctx, loop.ID, items...); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	plan, err := buildQueuePlan(ctx, queueRepo, loop.ID, nil)
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	if plan.StopRequested {
+		t.Fatalf("expected scheduled stop to be skipped")
+	}
+	if len(plan.Messages) != 1 || plan.Messages[0].Text != "now" {
+		t.Fatalf("expected only the due message, got %+v", plan.Messages)
+	}
+	if stop, _ := hasPendingStop(ctx, queueRepo, loop.ID); stop {
+		t.Fatalf("expected scheduled stop not to be pending yet")
+	}
+
+	wait := loopSleepInterval(ctx, queueRepo, loop.ID, 10*time.Minute)
+	if wait <= 0 || wait > time.Minute {
+		t.Fatalf("expected sleep shortened to next scheduled item, got %s", wait)
+	}
+}
+
+func TestParseNotBefore(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	if got, err := ParseNotBefore("", now); err != nil || got != nil {
+		t.Fatalf("expected nil for empty value, got %v, %v", got, err)
+	}
+
+	got, err := ParseNotBefore("+30m", now)
+	if err != nil || !got.Equal(now.Add(30*time.Minute)) {
+		t.Fatalf("unexpected delay result %v, %v", got, err)
+	}
+
+	got, err = ParseNotBefore("2026-10-17T02:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected RFC3339 result %v, %v", got, err)
+	}
+
+	got, err = ParseNotBefore("13:30", now)
+	if err != nil || !got.Equal(time.Date(2026, 10, 16, 13, 30, 0, 0, time.Local)) {
+		t.Fatalf("expected later today, got %v, %v", got, err)
+	}
+
+	got, err = ParseNotBefore("02:00", now)
+	if err != nil || !got.Equal(time.Date(2026, 10, 17, 2, 0, 0, 0, time.Local)) {
+		t.Fatalf("expected tomorrow, got %v, %v", got, err)
+	}
+
+	if _, err := ParseNotBefore("tomorrow", now); err == nil {
+		t.Fatalf("expected error for invalid value")
+	}
+}
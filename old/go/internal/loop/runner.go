@@ -10,14 +10,17 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/db"
 	"github.com/tOgg1/forge/internal/harness"
 	"github.com/tOgg1/forge/internal/logging"
 	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 const (
@@ -38,6 +41,10 @@ type Runner struct {
 	InterruptPollInterval time.Duration
 	Exec                  ExecuteFunc
 	RunCommand            runCommandFunc
+	RunHook               runHookFunc
+
+	statesMu   sync.Mutex
+	loopStates map[string]models.LoopState
 }
 
 // NewRunner creates a Runner with default dependencies.
@@ -51,6 +58,7 @@ func NewRunner(database *db.DB, cfg *config.Config) *Runner {
 		InterruptPollInterval: defaultInterruptInterval,
 		Exec:                  defaultExecute,
 		RunCommand:            defaultRunCommand,
+		RunHook:               defaultRunHook,
 	}
 }
 
@@ -97,7 +105,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 		return err
 	}
 
-	logWriter, err := newLoopLogger(loop.LogPath)
+	logWriter, err := newLoopLoggerWithOptions(loop.LogPath, logOptionsFromConfig(r.Config.LoopDefaults.Log))
 	if err != nil {
 		return err
 	}
@@ -114,11 +122,12 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 	if maxRuntime > 0 && startedAt.IsZero() {
 		startedAt = time.Now().UTC()
 		setLoopStartedAt(loop, startedAt)
-		_ = loopRepo.Update(ctx, loop)
+		_ = r.saveLoop(ctx, loopRepo, loop)
 	}
 
+	r.rememberLoopState(loop)
 	loop.State = models.LoopStateRunning
-	if err := loopRepo.Update(ctx, loop); err != nil {
+	if err := r.saveLoop(ctx, loopRepo, loop); err != nil {
 		return err
 	}
 
@@ -130,7 +139,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 		if ctx.Err() != nil {
 			logWriter.WriteLine("loop context cancelled")
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return ctx.Err()
 		}
 
@@ -138,7 +147,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine(reason)
 			loop.State = models.LoopStateStopped
 			loop.LastError = reason
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
@@ -147,7 +156,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 		if err != nil {
 			loop.State = models.LoopStateError
 			loop.LastError = err.Error()
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			logWriter.WriteLine(fmt.Sprintf("queue planning error: %v", err))
 			return err
 		}
@@ -156,7 +165,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine("graceful stop requested")
 			_ = markQueueCompleted(ctx, queueRepo, plan.StopItemIDs)
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
@@ -164,18 +173,12 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine("kill requested")
 			_ = markQueueCompleted(ctx, queueRepo, plan.KillItemIDs)
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
-		if plan.PauseDuration > 0 && plan.PauseBeforeRun {
-			logWriter.WriteLine(fmt.Sprintf("pause for %s", plan.PauseDuration))
-			loop.State = models.LoopStateSleeping
-			_ = loopRepo.Update(ctx, loop)
-			r.sleep(ctx, plan.PauseDuration)
-			if ctx.Err() == nil {
-				_ = markQueueCompleted(ctx, queueRepo, plan.PauseItemIDs)
-			}
+		if plan.Paused && plan.PauseBeforeRun {
+			r.pauseLoop(ctx, loopRepo, queueRepo, loop, plan, logWriter)
 			continue
 		}
 
@@ -194,7 +197,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 				if decision == stopDecisionStop {
 					loop.State = models.LoopStateStopped
 					loop.LastError = fmt.Sprintf("quant stop: %s", matchReason)
-					_ = loopRepo.Update(ctx, loop)
+					_ = r.saveLoop(ctx, loopRepo, loop)
 					return nil
 				}
 			} else if strings.TrimSpace(matchReason) != "" {
@@ -212,7 +215,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 		if err != nil {
 			loop.State = models.LoopStateError
 			loop.LastError = err.Error()
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			logWriter.WriteLine(fmt.Sprintf("profile selection error: %v", err))
 			return err
 		}
@@ -223,7 +226,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			loop.Metadata["wait_until"] = waitUntil.UTC().Format(time.RFC3339)
 			loop.State = models.LoopStateWaiting
 			loop.LastError = fmt.Sprintf("waiting for profile availability until %s", waitUntil.UTC().Format(time.RFC3339))
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			logWriter.WriteLine(loop.LastError)
 			r.sleepUntil(ctx, *waitUntil)
 			continue
@@ -234,11 +237,11 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 
 		effectiveProfile := profileWithLoopEnv(profile, loop)
 
-		prompt, err := resolveBasePrompt(loop)
+		prompt, err := resolveIterationPrompt(loop, plan.OverridePrompt != nil || runKind == "qual_stop")
 		if err != nil {
 			loop.State = models.LoopStateError
 			loop.LastError = err.Error()
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			logWriter.WriteLine(fmt.Sprintf("prompt resolution error: %v", err))
 			return err
 		}
@@ -248,7 +251,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			if err != nil {
 				loop.State = models.LoopStateError
 				loop.LastError = err.Error()
-				_ = loopRepo.Update(ctx, loop)
+				_ = r.saveLoop(ctx, loopRepo, loop)
 				logWriter.WriteLine(fmt.Sprintf("override prompt error: %v", err))
 				return err
 			}
@@ -259,7 +262,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			if err != nil {
 				loop.State = models.LoopStateError
 				loop.LastError = err.Error()
-				_ = loopRepo.Update(ctx, loop)
+				_ = r.saveLoop(ctx, loopRepo, loop)
 				logWriter.WriteLine(fmt.Sprintf("qual stop prompt error: %v", err))
 				return err
 			}
@@ -286,32 +289,76 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			PromptOverride: prompt.Override,
 			Metadata:       map[string]any{"kind": runKind},
 		}
-		if err := runRepo.Create(ctx, run); err != nil {
+		if prompt.PlaylistIndex > 0 {
+			run.Metadata["prompt_playlist_index"] = prompt.PlaylistIndex
+			run.Metadata["prompt_playlist_size"] = prompt.PlaylistSize
+		}
+		if CheckpointModeForLoop(loop) != models.LoopCheckpointOff {
+			// The checkpoint ref is named after the run, so pick its ID first.
+			run.ID = uuid.New().String()
+			r.checkpointRun(ctx, loop, run, logWriter)
+		}
+		runCtx, runSpan := tracing.Start(ctx, "loop.run",
+			tracing.String("loop_id", loop.ID),
+			tracing.String("profile_id", profile.ID),
+			tracing.String("run_kind", runKind),
+		)
+		if err := runRepo.Create(runCtx, run); err != nil {
+			runSpan.RecordError(err)
+			runSpan.End()
 			return err
 		}
+		runSpan.SetAttributes(tracing.String("run_id", run.ID))
 
 		effectivePromptPath, effectivePromptContent, err := r.preparePrompt(loop, run, profile, prompt, hasMessages)
 		if err != nil {
 			run.Status = models.LoopRunStatusError
-			_ = runRepo.Finish(ctx, run)
+			_ = runRepo.Finish(runCtx, run)
+			runSpan.RecordError(err)
+			runSpan.End()
 			loop.State = models.LoopStateError
 			loop.LastError = err.Error()
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			logWriter.WriteLine(fmt.Sprintf("prompt preparation error: %v", err))
 			return err
 		}
 
+		logWriter.BeginRun(iterationCount+1, run.ID, string(profile.Harness))
+
+		hooksCfg, hasHooks := loadHooksConfig(loop)
+		hookTimeout := time.Duration(hooksCfg.TimeoutSeconds) * time.Second
+		if hasHooks {
+			r.runHook(runCtx, hookPreRun, hooksCfg.PreRun, hookTimeout, loop, run, profile, logWriter)
+		}
+
 		loop.State = models.LoopStateRunning
-		_ = loopRepo.Update(ctx, loop)
+		_ = r.saveLoop(ctx, loopRepo, loop)
 
 		logWriter.WriteLine(fmt.Sprintf("run %s start (profile=%s)", run.ID, profile.Name))
+		if prompt.PlaylistIndex > 0 {
+			logWriter.WriteLine(fmt.Sprintf("prompt %d/%d: %s", prompt.PlaylistIndex, prompt.PlaylistSize, prompt.Path))
+		}
 
-		runResult, interruptResult := r.runWithInterrupt(ctx, loop, run, effectiveProfile, effectivePromptPath, effectivePromptContent, logWriter)
+		runResult, interruptResult := r.runWithInterrupt(runCtx, loop, run, effectiveProfile, effectivePromptPath, effectivePromptContent, logWriter)
 
 		run.Status = runResult.status
 		run.ExitCode = &runResult.exitCode
 		run.OutputTail = runResult.outputTail
-		_ = runRepo.Finish(ctx, run)
+		runResult.usage.Apply(run)
+		if hasHooks {
+			r.runHook(runCtx, hookPostRun, hooksCfg.PostRun, hookTimeout, loop, run, profile, logWriter)
+		}
+		r.captureRunArtifacts(loop, run, logWriter)
+		logWriter.EndRun()
+		_ = runRepo.Finish(runCtx, run)
+		runSpan.SetAttributes(
+			tracing.String("status", string(run.Status)),
+			tracing.Int("exit_code", runResult.exitCode),
+		)
+		if runResult.errText != "" {
+			runSpan.RecordError(errors.New(runResult.errText))
+		}
+		runSpan.End()
 
 		if run.FinishedAt != nil {
 			loop.LastRunAt = run.FinishedAt
@@ -334,8 +381,16 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			stopState.QualLastMainCount = stopState.MainIterationCount
 		}
 		saveStopState(loop, stopState)
+		r.maybeWriteDailySummary(ctx, loop, runRepo, logWriter, time.Now())
 
-		_ = loopRepo.Update(ctx, loop)
+		_ = r.saveLoop(ctx, loopRepo, loop)
+		if run.Status == models.LoopRunStatusError {
+			r.recordLoopEvent(ctx, models.EventTypeLoopRunFailed, loop, models.LoopEventPayload{
+				RunID:    run.ID,
+				ExitCode: run.ExitCode,
+				Error:    runResult.errText,
+			})
+		}
 
 		_ = markQueueCompleted(ctx, queueRepo, plan.ConsumeItemIDs)
 
@@ -347,7 +402,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 		if interruptResult != nil && interruptResult.killOnly {
 			logWriter.WriteLine("run interrupted: kill")
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 		if interruptResult != nil && interruptResult.steerMessage != "" {
@@ -364,7 +419,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine("kill queued")
 			_ = consumePendingKill(ctx, queueRepo, loop.ID)
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
@@ -372,7 +427,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine("graceful stop queued")
 			_ = consumePendingStop(ctx, queueRepo, loop.ID)
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
@@ -384,14 +439,14 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 				if onInvalid == stopDecisionStop {
 					loop.State = models.LoopStateStopped
 					loop.LastError = "qual stop: invalid output (expected 0 or 1)"
-					_ = loopRepo.Update(ctx, loop)
+					_ = r.saveLoop(ctx, loopRepo, loop)
 					return nil
 				}
 			} else if signal == 0 {
 				logWriter.WriteLine("qual stop signaled stop (0)")
 				loop.State = models.LoopStateStopped
 				loop.LastError = "qual stop: signaled stop"
-				_ = loopRepo.Update(ctx, loop)
+				_ = r.saveLoop(ctx, loopRepo, loop)
 				return nil
 			} else {
 				logWriter.WriteLine("qual stop signaled continue (1)")
@@ -410,7 +465,7 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 				if decision == stopDecisionStop {
 					loop.State = models.LoopStateStopped
 					loop.LastError = fmt.Sprintf("quant stop: %s", matchReason)
-					_ = loopRepo.Update(ctx, loop)
+					_ = r.saveLoop(ctx, loopRepo, loop)
 					return nil
 				}
 			} else if strings.TrimSpace(matchReason) != "" {
@@ -430,30 +485,24 @@ func (r *Runner) runLoop(ctx context.Context, loopID string, singleRun bool) err
 			logWriter.WriteLine(reason)
 			loop.State = models.LoopStateStopped
 			loop.LastError = reason
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
-		if plan.PauseDuration > 0 && !plan.PauseBeforeRun {
-			logWriter.WriteLine(fmt.Sprintf("pause for %s", plan.PauseDuration))
-			loop.State = models.LoopStateSleeping
-			_ = loopRepo.Update(ctx, loop)
-			r.sleep(ctx, plan.PauseDuration)
-			if ctx.Err() == nil {
-				_ = markQueueCompleted(ctx, queueRepo, plan.PauseItemIDs)
-			}
+		if plan.Paused && !plan.PauseBeforeRun {
+			r.pauseLoop(ctx, loopRepo, queueRepo, loop, plan, logWriter)
 			skipSleep = true
 		}
 
 		if singleRun {
 			loop.State = models.LoopStateStopped
-			_ = loopRepo.Update(ctx, loop)
+			_ = r.saveLoop(ctx, loopRepo, loop)
 			return nil
 		}
 
 		if !skipSleep {
 			interval := time.Duration(loop.IntervalSeconds) * time.Second
-			r.sleep(ctx, interval)
+			r.sleep(ctx, loopSleepInterval(ctx, queueRepo, loop.ID, interval))
 		}
 	}
 }
@@ -491,13 +540,23 @@ func (r *Runner) runWithInterrupt(ctx context.Context, loop *models.Loop, run *m
 
 	go func() {
 		outputWriter := newTailWriter(r.OutputTailLines)
-		writer := io.MultiWriter(logWriter, outputWriter)
+		usageWriter := newUsageScanner()
+		var writer io.Writer = io.MultiWriter(logWriter, outputWriter, usageWriter)
+		if logWriter.structured() {
+			writer = &splitOutput{Writer: writer, stderr: io.MultiWriter(logWriter.Stderr(), outputWriter, usageWriter)}
+		}
 		exitCode, outputTail, err := r.Exec(runCtx, *profile, promptPath, promptContent, loop.RepoPath, writer)
+		tail := outputTailOrFallback(outputTail, outputWriter.String())
+		usage := usageWriter.Usage()
+		if usage.Empty() {
+			usage = ParseRunUsage(tail)
+		}
 		resultCh <- runResult{
 			status:     statusFromResult(err),
 			exitCode:   exitCode,
-			outputTail: outputTailOrFallback(outputTail, outputWriter.String()),
+			outputTail: tail,
 			errText:    errText(err),
+			usage:      usage,
 		}
 	}()
 
@@ -670,7 +729,7 @@ func defaultExecute(ctx context.Context, profile models.Profile, promptPath, pro
 	}
 	execPlan.Cmd.Dir = workDir
 	execPlan.Cmd.Stdout = output
-	execPlan.Cmd.Stderr = output
+	execPlan.Cmd.Stderr = stderrWriter(output)
 
 	err = execPlan.Cmd.Run()
 	return exitCodeFromError(err), "", err
@@ -712,6 +771,7 @@ type runResult struct {
 	exitCode   int
 	outputTail string
 	errText    string
+	usage      RunUsage
 }
 
 func profileWithLoopEnv(profile *models.Profile, loopEntry *models.Loop) *models.Profile {
@@ -368,6 +368,112 @@ func TestRunnerStopQueueStopsLoop(t *testing.T) {
 	}
 }
 
+func TestRunnerPauseHoldsUntilResume(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.Global.ConfigDir = t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loopRepo := db.NewLoopRepository(database)
+	queueRepo := db.NewLoopQueueRepository(database)
+	runRepo := db.NewLoopRunRepository(database)
+
+	profile := &models.Profile{
+		Name:            "pi-pause",
+		Harness:         models.HarnessPi,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "pi -p \"$FORGE_PROMPT_CONTENT\"",
+		MaxConcurrency:  1,
+	}
+	if err := db.NewProfileRepository(database).Create(ctx, profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+
+	loopEntry := &models.Loop{
+		Name:            "loop-pause",
+		RepoPath:        t.TempDir(),
+		BasePromptMsg:   "base",
+		IntervalSeconds: 60,
+		ProfileID:       profile.ID,
+		State:           models.LoopStateStopped,
+	}
+	if err := loopRepo.Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	if err := queueRepo.Enqueue(ctx, loopEntry.ID,
+		&models.LoopQueueItem{Type: models.LoopQueueItemPause, Payload: mustJSON(models.LoopPausePayload{Reason: "review"})},
+		&models.LoopQueueItem{Type: models.LoopQueueItemMessageAppend, Payload: mustJSON(models.MessageAppendPayload{Text: "after pause"})},
+	); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	runner := NewRunner(database, cfg)
+	runner.InterruptPollInterval = 10 * time.Millisecond
+	prompts := make(chan string, 4)
+	runner.Exec = func(ctx context.Context, profile models.Profile, promptPath, promptContent, workDir string, output io.Writer) (int, string, error) {
+		prompts <- promptContent
+		return 0, "", nil
+	}
+	done := make(chan error, 1)
+	go func() { done <- runner.RunLoop(ctx, loopEntry.ID) }()
+
+	waitFor := func(desc string, cond func(*models.Loop) bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			current, err := loopRepo.Get(ctx, loopEntry.ID)
+			if err == nil && cond(current) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %s", desc)
+	}
+	waitFor("pause", IsPaused)
+
+	// Held well past several poll intervals without running.
+	time.Sleep(100 * time.Millisecond)
+	if runs, _ := runRepo.ListByLoop(ctx, loopEntry.ID); len(runs) != 0 {
+		t.Fatalf("expected no runs while paused, got %d", len(runs))
+	}
+	paused, _ := loopRepo.Get(ctx, loopEntry.ID)
+	if paused.State != models.LoopStateSleeping || paused.Metadata["pause_reason"] != "review" {
+		t.Fatalf("unexpected paused loop: state=%s metadata=%v", paused.State, paused.Metadata)
+	}
+
+	if err := queueRepo.Enqueue(ctx, loopEntry.ID,
+		&models.LoopQueueItem{Type: models.LoopQueueItemResume, Payload: mustJSON(models.ResumePayload{})},
+	); err != nil {
+		t.Fatalf("enqueue resume: %v", err)
+	}
+	select {
+	case prompt := <-prompts:
+		if !strings.Contains(prompt, "after pause") {
+			t.Fatalf("expected queued message in first run after resume, got %q", prompt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for run after resume")
+	}
+	waitFor("unpaused", func(l *models.Loop) bool { return !IsPaused(l) })
+
+	cancel()
+	<-done
+
+	items, err := queueRepo.List(context.Background(), loopEntry.ID)
+	if err != nil {
+		t.Fatalf("list queue: %v", err)
+	}
+	for _, item := range items {
+		if item.Status != models.LoopQueueStatusCompleted {
+			t.Fatalf("expected %s item completed, got %s", item.Type, item.Status)
+		}
+	}
+}
+
 func TestRunnerInjectsPersistentMemoryIntoPrompt(t *testing.T) {
 	database, cleanup := testutil.NewTestDB(t)
 	defer cleanup()
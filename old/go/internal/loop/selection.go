@@ -24,11 +24,15 @@ func (r *Runner) selectProfile(ctx context.Context, loop *models.Loop, profileRe
 		if err != nil {
 			return nil, nil, err
 		}
-		available, _, err := profileAvailable(ctx, runRepo, profile, now)
+		available, next, err := profileAvailable(ctx, runRepo, profile, now)
 		if err != nil {
 			return nil, nil, err
 		}
 		if !available {
+			if next != nil && !profileInCooldown(profile, now) {
+				// Rate limited: wait for the window instead of failing the loop.
+				return nil, next, nil
+			}
 			return nil, nil, fmt.Errorf("pinned profile %s unavailable", profile.Name)
 		}
 		return profile, nil, nil
@@ -83,7 +87,7 @@ func (r *Runner) selectProfile(ctx context.Context, loop *models.Loop, profileRe
 }
 
 func profileAvailable(ctx context.Context, runRepo *db.LoopRunRepository, profile *models.Profile, now time.Time) (bool, *time.Time, error) {
-	if profile.CooldownUntil != nil && profile.CooldownUntil.After(now) {
+	if profileInCooldown(profile, now) {
 		next := *profile.CooldownUntil
 		return false, &next, nil
 	}
@@ -98,6 +102,50 @@ func profileAvailable(ctx context.Context, runRepo *db.LoopRunRepository, profil
 		}
 	}
 
+	if profile.HasRateLimit() {
+		return profileWithinRateLimit(ctx, runRepo, profile, now)
+	}
+
+	return true, nil, nil
+}
+
+func profileInCooldown(profile *models.Profile, now time.Time) bool {
+	return profile.CooldownUntil != nil && profile.CooldownUntil.After(now)
+}
+
+// profileWithinRateLimit enforces max runs per hour and the minimum gap
+// between run starts. When limited, it returns when the profile frees up.
+func profileWithinRateLimit(ctx context.Context, runRepo *db.LoopRunRepository, profile *models.Profile, now time.Time) (bool, *time.Time, error) {
+	windowStart := now.Add(-time.Hour)
+	count, lastStart, err := runRepo.ProfileStartStats(ctx, profile.ID, windowStart)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var next *time.Time
+	if gap := profile.MinRunGap(); gap > 0 && lastStart != nil {
+		if ready := lastStart.Add(gap); ready.After(now) {
+			next = &ready
+		}
+	}
+
+	if profile.MaxRunsPerHour > 0 && count >= profile.MaxRunsPerHour {
+		oldest, err := runRepo.OldestStartSince(ctx, profile.ID, windowStart)
+		if err != nil {
+			return false, nil, err
+		}
+		ready := now.Add(defaultWaitInterval)
+		if oldest != nil {
+			ready = oldest.Add(time.Hour)
+		}
+		if next == nil || ready.After(*next) {
+			next = &ready
+		}
+	}
+
+	if next != nil {
+		return false, next, nil
+	}
 	return true, nil, nil
 }
 
@@ -185,3 +185,110 @@ func TestSelectProfilePoolWaitsForCooldown(t *testing.T) {
 		t.Fatalf("expected waitUntil near %s, got %s", early, waitUntil)
 	}
 }
+
+func TestSelectProfileRateLimited(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	profileRepo := db.NewProfileRepository(database)
+	loopRepo := db.NewLoopRepository(database)
+	runRepo := db.NewLoopRunRepository(database)
+	poolRepo := db.NewPoolRepository(database)
+
+	profile := &models.Profile{
+		Name:             "metered",
+		Harness:          models.HarnessPi,
+		CommandTemplate:  "pi -p \"{prompt}\"",
+		MaxConcurrency:   5,
+		MaxRunsPerHour:   2,
+		MinRunGapSeconds: 60,
+	}
+	if err := profileRepo.Create(ctx, profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+
+	loop := &models.Loop{Name: "loop-rl", RepoPath: "/tmp/repo", IntervalSeconds: 1, ProfileID: profile.ID}
+	if err := loopRepo.Create(ctx, loop); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runner := NewRunner(database, config.DefaultConfig())
+	selected, waitUntil, err := runner.selectProfile(ctx, loop, profileRepo, poolRepo, runRepo)
+	if err != nil {
+		t.Fatalf("select profile: %v", err)
+	}
+	if selected == nil || waitUntil != nil {
+		t.Fatalf("expected profile with no history to be selected")
+	}
+
+	now := time.Now().UTC()
+	oldest := now.Add(-50 * time.Minute)
+	for _, started := range []time.Time{oldest, now.Add(-10 * time.Minute)} {
+		run := &models.LoopRun{LoopID: loop.ID, ProfileID: profile.ID, Status: models.LoopRunStatusSuccess, StartedAt: started}
+		if err := runRepo.Create(ctx, run); err != nil {
+			t.Fatalf("create run: %v", err)
+		}
+	}
+
+	selected, waitUntil, err = runner.selectProfile(ctx, loop, profileRepo, poolRepo, runRepo)
+	if err != nil {
+		t.Fatalf("expected rate-limited pinned profile to wait, got error: %v", err)
+	}
+	if selected != nil || waitUntil == nil {
+		t.Fatalf("expected wait, got selected=%v waitUntil=%v", selected, waitUntil)
+	}
+	expected := oldest.Add(time.Hour)
+	if waitUntil.Before(expected.Add(-2*time.Second)) || waitUntil.After(expected.Add(2*time.Second)) {
+		t.Fatalf("expected waitUntil near %s, got %s", expected, waitUntil)
+	}
+}
+
+func TestProfileAvailableMinRunGap(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	profileRepo := db.NewProfileRepository(database)
+	loopRepo := db.NewLoopRepository(database)
+	runRepo := db.NewLoopRunRepository(database)
+
+	profile := &models.Profile{
+		Name:             "gapped",
+		Harness:          models.HarnessPi,
+		CommandTemplate:  "pi -p \"{prompt}\"",
+		MinRunGapSeconds: 300,
+	}
+	if err := profileRepo.Create(ctx, profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+	loop := &models.Loop{Name: "loop-gap", RepoPath: "/tmp/repo", IntervalSeconds: 1, ProfileID: profile.ID}
+	if err := loopRepo.Create(ctx, loop); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	now := time.Now().UTC()
+	lastStart := now.Add(-time.Minute)
+	if err := runRepo.Create(ctx, &models.LoopRun{LoopID: loop.ID, ProfileID: profile.ID, Status: models.LoopRunStatusSuccess, StartedAt: lastStart}); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+
+	available, next, err := profileAvailable(ctx, runRepo, profile, now)
+	if err != nil {
+		t.Fatalf("profileAvailable: %v", err)
+	}
+	if available || next == nil {
+		t.Fatalf("expected profile to be gated by min run gap")
+	}
+	if expected := lastStart.Add(5 * time.Minute); next.Sub(expected).Abs() > 2*time.Second {
+		t.Fatalf("expected next near %s, got %s", expected, next)
+	}
+
+	available, _, err = profileAvailable(ctx, runRepo, profile, now.Add(5*time.Minute))
+	if err != nil {
+		t.Fatalf("profileAvailable: %v", err)
+	}
+	if !available {
+		t.Fatalf("expected profile available once the gap elapsed")
+	}
+}
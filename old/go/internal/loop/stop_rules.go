@@ -36,6 +36,12 @@ type commandResult struct {
 type runCommandFunc func(ctx context.Context, workDir, cmd string, timeout time.Duration) commandResult
 
 func defaultRunCommand(ctx context.Context, workDir, cmd string, timeout time.Duration) commandResult {
+	return runShellCommand(ctx, workDir, cmd, nil, timeout)
+}
+
+// runShellCommand runs cmd via `bash -lc`. A nil env inherits the process
+// environment.
+func runShellCommand(ctx context.Context, workDir, cmd string, env []string, timeout time.Duration) commandResult {
 	if strings.TrimSpace(cmd) == "" {
 		return commandResult{exitCode: -1, err: errors.New("empty command")}
 	}
@@ -49,6 +55,7 @@ func defaultRunCommand(ctx context.Context, workDir, cmd string, timeout time.Du
 
 	c := exec.CommandContext(runCtx, "bash", "-lc", cmd)
 	c.Dir = workDir
+	c.Env = env
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
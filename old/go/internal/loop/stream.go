@@ -0,0 +1,406 @@
+package loop
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultStreamPoll is how often a followed log is checked for new lines.
+const DefaultStreamPoll = 250 * time.Millisecond
+
+// Stream output formats.
+const (
+	// StreamFormatText renders lines the way `forge logs` shows them.
+	StreamFormatText = "text"
+	// StreamFormatRaw sends log lines unchanged, so JSONL logs stay JSONL.
+	StreamFormatRaw = "raw"
+)
+
+// LogStreamer serves loop logs and run output over HTTP, so remote UIs can
+// follow runs without access to the node's filesystem.
+type LogStreamer struct {
+	loops   *db.LoopRepository
+	runs    *db.LoopRunRepository
+	dataDir string
+	poll    time.Duration
+}
+
+// NewLogStreamer returns a streamer that resolves loops and runs in database
+// and falls back to the default log location under dataDir.
+func NewLogStreamer(database *db.DB, dataDir string) *LogStreamer {
+	return &LogStreamer{
+		loops:   db.NewLoopRepository(database),
+		runs:    db.NewLoopRunRepository(database),
+		dataDir: dataDir,
+		poll:    DefaultStreamPoll,
+	}
+}
+
+// Handler serves the streams for mounting on the daemon. Responses are
+// chunked text, one log line per line, flushed as lines are written:
+//
+//	GET /loops/{loop}/log                    last lines of the loop log
+//	GET /loops/{loop}/runs/{run}/output      output of one run
+//
+// {loop} is a loop ID, short ID, or name. Query parameters:
+//
+//	follow=1        keep the response open and send new lines; a run's
+//	                output ends when the run finishes
+//	lines=N         loop log only: lines of history to send first
+//	                (default 50, 0 for the whole log)
+//	format=raw      send lines as stored instead of rendered text
+func (s *LogStreamer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /loops/{loop}/log", func(w http.ResponseWriter, req *http.Request) {
+		opts, err := parseStreamQuery(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		loopEntry, err := s.resolveLoop(req.Context(), req.PathValue("loop"))
+		if err != nil {
+			writeStreamError(w, err)
+			return
+		}
+		file, err := os.Open(s.logPath(loopEntry))
+		if err != nil {
+			writeStreamError(w, err)
+			return
+		}
+		defer file.Close()
+
+		out := newStreamWriter(w, opts.format)
+		out.start()
+		offset, err := sendLogTail(out, file, opts.lines)
+		if err != nil || !opts.follow {
+			return
+		}
+		_ = s.follow(req.Context(), out, file, offset, nil, nil)
+	})
+	mux.HandleFunc("GET /loops/{loop}/runs/{run}/output", func(w http.ResponseWriter, req *http.Request) {
+		opts, err := parseStreamQuery(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		loopEntry, err := s.resolveLoop(req.Context(), req.PathValue("loop"))
+		if err != nil {
+			writeStreamError(w, err)
+			return
+		}
+		run, err := s.runs.Get(req.Context(), req.PathValue("run"))
+		if err == nil && run.LoopID != loopEntry.ID {
+			err = db.ErrLoopRunNotFound
+		}
+		if err != nil {
+			writeStreamError(w, err)
+			return
+		}
+		out := newStreamWriter(w, opts.format)
+		out.start()
+		s.streamRun(req.Context(), out, loopEntry, run, opts.follow)
+	})
+	return mux
+}
+
+type streamQuery struct {
+	follow bool
+	lines  int
+	format string
+}
+
+func parseStreamQuery(req *http.Request) (streamQuery, error) {
+	query := req.URL.Query()
+	opts := streamQuery{lines: 50, format: StreamFormatText}
+	if raw := query.Get("follow"); raw != "" {
+		follow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid follow %q", raw)
+		}
+		opts.follow = follow
+	}
+	if raw := query.Get("lines"); raw != "" {
+		lines, err := strconv.Atoi(raw)
+		if err != nil || lines < 0 {
+			return opts, fmt.Errorf("invalid lines %q", raw)
+		}
+		opts.lines = lines
+	}
+	switch format := query.Get("format"); format {
+	case "", StreamFormatText:
+	case StreamFormatRaw:
+		opts.format = format
+	default:
+		return opts, fmt.Errorf("invalid format %q (want %s or %s)", format, StreamFormatText, StreamFormatRaw)
+	}
+	return opts, nil
+}
+
+func writeStreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, db.ErrLoopNotFound), errors.Is(err, db.ErrLoopRunNotFound), errors.Is(err, os.ErrNotExist):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *LogStreamer) resolveLoop(ctx context.Context, ref string) (*models.Loop, error) {
+	loopEntry, err := s.loops.Get(ctx, ref)
+	if errors.Is(err, db.ErrLoopNotFound) {
+		loopEntry, err = s.loops.GetByShortID(ctx, ref)
+	}
+	if errors.Is(err, db.ErrLoopNotFound) {
+		loopEntry, err = s.loops.GetByName(ctx, ref)
+	}
+	return loopEntry, err
+}
+
+func (s *LogStreamer) logPath(loopEntry *models.Loop) string {
+	if loopEntry.LogPath != "" {
+		return loopEntry.LogPath
+	}
+	return LogPath(s.dataDir, loopEntry.Name, loopEntry.ID)
+}
+
+// streamRun sends the lines of the loop log that belong to run. JSONL logs
+// tag each record with its run ID; in text logs a run's output is everything
+// from its "run <id> start" line up to the next run's start line. When the
+// log no longer has the run (rotated away), the stored output tail is sent.
+func (s *LogStreamer) streamRun(ctx context.Context, out *streamWriter, loopEntry *models.Loop, run *models.LoopRun, follow bool) {
+	file, err := os.Open(s.logPath(loopEntry))
+	if err != nil {
+		out.sendTail(run.OutputTail)
+		return
+	}
+	defer file.Close()
+
+	filter := newRunFilter(run.ID)
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+		if filter.keep(line) {
+			if out.send(line) != nil {
+				return
+			}
+		}
+	}
+	if !filter.seen {
+		out.sendTail(run.OutputTail)
+		return
+	}
+	if !follow || filter.done {
+		return
+	}
+
+	finished := func() bool {
+		current, err := s.runs.Get(ctx, run.ID)
+		return err != nil || current.Status != models.LoopRunStatusRunning
+	}
+	_ = s.follow(ctx, out, file, offset, filter, finished)
+}
+
+// follow sends lines appended to file after offset until ctx is done, the
+// run filter sees the next run start, or finished reports true at the end of
+// the file. A log that shrank was rotated and is reopened from the start.
+func (s *LogStreamer) follow(ctx context.Context, out *streamWriter, file *os.File, offset int64, filter *runFilter, finished func() bool) error {
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+	path := file.Name()
+	var reopened *os.File
+	defer func() {
+		if reopened != nil {
+			reopened.Close()
+		}
+	}()
+	for {
+		if info, err := os.Stat(path); err == nil && info.Size() < offset {
+			next, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			if reopened != nil {
+				reopened.Close()
+			}
+			reopened = next
+			file, offset = next, 0
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		reader := bufio.NewReader(file)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break // a partial line is read again once complete
+			}
+			offset += int64(len(line))
+			if filter != nil && !filter.keep(line) {
+				if filter.done {
+					return nil
+				}
+				continue
+			}
+			if err := out.send(line); err != nil {
+				return err
+			}
+		}
+		if finished != nil && finished() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendLogTail sends the last n complete lines of file (all when n is 0) and
+// returns the offset just past them.
+func sendLogTail(out *streamWriter, file *os.File, n int) (int64, error) {
+	reader := bufio.NewReader(file)
+	var lines []string
+	var offset int64
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+		lines = append(lines, line)
+		if n > 0 && len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	for _, line := range lines {
+		if err := out.send(line); err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+// runFilter picks one run's lines out of a loop log.
+type runFilter struct {
+	runID  string
+	marker string
+	active bool
+	seen   bool
+	done   bool
+}
+
+func newRunFilter(runID string) *runFilter {
+	return &runFilter{runID: runID, marker: "run " + runID + " start"}
+}
+
+func (f *runFilter) keep(line string) bool {
+	if f.done {
+		return false
+	}
+	if rec, ok := ParseLogRecord(line); ok {
+		if rec.RunID == f.runID {
+			f.active, f.seen = true, true
+			return true
+		}
+		if f.seen && rec.RunID != "" {
+			f.done = true
+		}
+		return false
+	}
+	text := DisplayLogLine(strings.TrimRight(line, "\r\n"))
+	if _, hasStamp := parseLogStamp(text); hasStamp && strings.Contains(text, "] run ") && strings.Contains(text, " start (") {
+		if strings.Contains(text, "] "+f.marker+" (") {
+			f.active, f.seen = true, true
+			return true
+		}
+		if f.active {
+			f.active, f.done = false, true
+		}
+		return false
+	}
+	return f.active
+}
+
+func parseLogStamp(line string) (time.Time, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return time.Time{}, false
+	}
+	end := strings.Index(line, "]")
+	if end == -1 {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, line[1:end])
+	return ts, err == nil
+}
+
+// streamWriter writes lines to a chunked response and flushes after each.
+type streamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	format  string
+	started bool
+}
+
+func newStreamWriter(w http.ResponseWriter, format string) *streamWriter {
+	flusher, _ := w.(http.Flusher)
+	return &streamWriter{w: w, flusher: flusher, format: format}
+}
+
+func (s *streamWriter) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	header := s.w.Header()
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Cache-Control", "no-cache")
+	// Browsers buffer text/plain to sniff it unless told not to.
+	header.Set("X-Content-Type-Options", "nosniff")
+	s.w.WriteHeader(http.StatusOK)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *streamWriter) send(line string) error {
+	s.start()
+	line = strings.TrimRight(line, "\r\n")
+	if s.format == StreamFormatText {
+		line = DisplayLogLine(line)
+	}
+	if _, err := io.WriteString(s.w, line+"\n"); err != nil {
+		return err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+func (s *streamWriter) sendTail(tail string) {
+	if tail == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(tail, "\n"), "\n") {
+		if s.send(line) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package loop
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func newStreamFixture(t *testing.T, log string) (*LogStreamer, *models.Loop, *db.LoopRunRepository) {
+	t.Helper()
+	database, cleanup := testutil.NewTestDB(t)
+	t.Cleanup(cleanup)
+
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	loopEntry := &models.Loop{Name: "stream-loop", RepoPath: t.TempDir(), IntervalSeconds: 10}
+	if err := db.NewLoopRepository(database).Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	path := LogPath(dataDir, loopEntry.Name, loopEntry.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	streamer := NewLogStreamer(database, dataDir)
+	streamer.poll = 10 * time.Millisecond
+	return streamer, loopEntry, db.NewLoopRunRepository(database)
+}
+
+func createStreamRun(t *testing.T, runs *db.LoopRunRepository, loopID string, status models.LoopRunStatus) *models.LoopRun {
+	t.Helper()
+	run := &models.LoopRun{LoopID: loopID, Status: status, OutputTail: "stored tail"}
+	if err := runs.Create(context.Background(), run); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	return run
+}
+
+func getStream(t *testing.T, handler http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestLogStreamerServesLogTail(t *testing.T) {
+	streamer, loopEntry, _ := newStreamFixture(t, "[2026-01-01T00:00:00Z] loop started\none\ntwo\nthree\n")
+	handler := streamer.Handler()
+
+	code, body := getStream(t, handler, "/loops/"+loopEntry.Name+"/log?lines=2")
+	if code != http.StatusOK || body != "two\nthree\n" {
+		t.Fatalf("tail status = %d body = %q", code, body)
+	}
+	code, body = getStream(t, handler, "/loops/"+loopEntry.ID+"/log?lines=0")
+	if code != http.StatusOK || !strings.HasPrefix(body, "[2026-01-01T00:00:00Z] loop started\n") {
+		t.Fatalf("full log status = %d body = %q", code, body)
+	}
+
+	for path, want := range map[string]int{
+		"/loops/missing/log":                           http.StatusNotFound,
+		"/loops/" + loopEntry.ID + "/log?lines=-1":     http.StatusBadRequest,
+		"/loops/" + loopEntry.ID + "/log?format=x":     http.StatusBadRequest,
+		"/loops/" + loopEntry.ID + "/runs/nope/output": http.StatusNotFound,
+	} {
+		if code, _ := getStream(t, handler, path); code != want {
+			t.Fatalf("%s status = %d, want %d", path, code, want)
+		}
+	}
+}
+
+func TestLogStreamerServesRunOutput(t *testing.T) {
+	streamer, loopEntry, runs := newStreamFixture(t, "")
+	first := createStreamRun(t, runs, loopEntry.ID, models.LoopRunStatusSuccess)
+	second := createStreamRun(t, runs, loopEntry.ID, models.LoopRunStatusSuccess)
+	missing := createStreamRun(t, runs, loopEntry.ID, models.LoopRunStatusSuccess)
+
+	log := "[2026-01-01T00:00:00Z] loop started\n" +
+		"[2026-01-01T00:00:01Z] run " + first.ID + " start (profile=p)\n" +
+		"first output\n" +
+		"[2026-01-01T00:00:09Z] run " + second.ID + " start (profile=p)\n" +
+		"second output\n"
+	if err := os.WriteFile(streamer.logPath(loopEntry), []byte(log), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	handler := streamer.Handler()
+
+	_, body := getStream(t, handler, "/loops/"+loopEntry.ID+"/runs/"+first.ID+"/output")
+	want := "[2026-01-01T00:00:01Z] run " + first.ID + " start (profile=p)\nfirst output\n"
+	if body != want {
+		t.Fatalf("first run output = %q, want %q", body, want)
+	}
+	_, body = getStream(t, handler, "/loops/"+loopEntry.ID+"/runs/"+missing.ID+"/output")
+	if body != "stored tail\n" {
+		t.Fatalf("rotated run output = %q", body)
+	}
+}
+
+func TestLogStreamerFiltersJSONLRuns(t *testing.T) {
+	streamer, loopEntry, runs := newStreamFixture(t, "")
+	run := createStreamRun(t, runs, loopEntry.ID, models.LoopRunStatusSuccess)
+
+	log := `{"ts":"2026-01-01T00:00:00Z","stream":"loop","text":"loop started"}` + "\n" +
+		`{"ts":"2026-01-01T00:00:01Z","stream":"stdout","run_id":"` + run.ID + `","text":"hello"}` + "\n" +
+		`{"ts":"2026-01-01T00:00:02Z","stream":"stdout","run_id":"other","text":"nope"}` + "\n"
+	if err := os.WriteFile(streamer.logPath(loopEntry), []byte(log), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	handler := streamer.Handler()
+
+	if _, body := getStream(t, handler, "/loops/"+loopEntry.ID+"/runs/"+run.ID+"/output"); body != "hello\n" {
+		t.Fatalf("text output = %q", body)
+	}
+	_, body := getStream(t, handler, "/loops/"+loopEntry.ID+"/runs/"+run.ID+"/output?format=raw")
+	if !strings.HasPrefix(body, `{"ts":"2026-01-01T00:00:01Z"`) || strings.Count(body, "\n") != 1 {
+		t.Fatalf("raw output = %q", body)
+	}
+}
+
+func TestLogStreamerFollowsRunUntilFinished(t *testing.T) {
+	streamer, loopEntry, runs := newStreamFixture(t, "")
+	run := createStreamRun(t, runs, loopEntry.ID, models.LoopRunStatusRunning)
+	path := streamer.logPath(loopEntry)
+	if err := os.WriteFile(path, []byte("[2026-01-01T00:00:01Z] run "+run.ID+" start (profile=p)\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	server := httptest.NewServer(streamer.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/loops/" + loopEntry.ID + "/runs/" + run.ID + "/output?follow=1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if line, err := reader.ReadString('\n'); err != nil || !strings.Contains(line, "start") {
+		t.Fatalf("first line = %q, %v", line, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	if _, err := file.WriteString("live output\n"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	file.Close()
+	if line, err := reader.ReadString('\n'); err != nil || line != "live output\n" {
+		t.Fatalf("followed line = %q, %v", line, err)
+	}
+
+	exitCode := 0
+	run.Status = models.LoopRunStatusSuccess
+	run.ExitCode = &exitCode
+	if err := runs.Finish(context.Background(), run); err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := reader.ReadString('\n')
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the stream to end after the run finished")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the run finished")
+	}
+}
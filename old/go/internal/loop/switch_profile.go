@@ -0,0 +1,220 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// SwitchQueueMode controls what happens to pending queue items when a loop
+// changes profile.
+type SwitchQueueMode string
+
+const (
+	// SwitchQueueMigrate keeps pending work for the new profile. Pending
+	// stop/kill requests are dropped because the switch restarts the runner.
+	SwitchQueueMigrate SwitchQueueMode = "migrate"
+	// SwitchQueueDrain drops all pending queue items.
+	SwitchQueueDrain SwitchQueueMode = "drain"
+)
+
+// ParseSwitchQueueMode parses a --queue value. Empty means migrate.
+func ParseSwitchQueueMode(value string) (SwitchQueueMode, error) {
+	switch SwitchQueueMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", SwitchQueueMigrate:
+		return SwitchQueueMigrate, nil
+	case SwitchQueueDrain:
+		return SwitchQueueDrain, nil
+	default:
+		return "", fmt.Errorf("invalid queue mode %q (use migrate or drain)", value)
+	}
+}
+
+// ProfileSwitchPlan is a validated profile switch, ready to apply once the
+// loop's runner has been stopped.
+type ProfileSwitchPlan struct {
+	Loop       *models.Loop
+	From       *models.Profile
+	To         *models.Profile
+	QueueMode  SwitchQueueMode
+	Restart    bool
+	Reason     string
+	Warnings   []string
+	migrateIDs []string
+	dropIDs    []string
+}
+
+// Migrated returns how many pending items move to the new profile.
+func (p *ProfileSwitchPlan) Migrated() int { return len(p.migrateIDs) }
+
+// Dropped returns how many pending items are skipped by the switch.
+func (p *ProfileSwitchPlan) Dropped() int { return len(p.dropIDs) }
+
+// PlanProfileSwitch validates moving loop to profile. Hard incompatibilities
+// are errors; soft ones become warnings, and force turns errors that the
+// operator can knowingly override into warnings as well.
+func PlanProfileSwitch(ctx context.Context, database *db.DB, loop *models.Loop, profile *models.Profile, mode SwitchQueueMode, force bool) (*ProfileSwitchPlan, error) {
+	if loop == nil || profile == nil {
+		return nil, errors.New("loop and profile are required")
+	}
+	if loop.ProfileID == profile.ID {
+		return nil, fmt.Errorf("loop %q already uses profile %s", loop.Name, profile.Name)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("profile %s is invalid: %w", profile.Name, err)
+	}
+
+	plan := &ProfileSwitchPlan{
+		Loop:      loop,
+		To:        profile,
+		QueueMode: mode,
+		Restart:   loopRunnerActive(loop),
+	}
+
+	if loop.ProfileID != "" {
+		from, err := db.NewProfileRepository(database).Get(ctx, loop.ProfileID)
+		if err != nil && !errors.Is(err, db.ErrProfileNotFound) {
+			return nil, err
+		}
+		plan.From = from
+	}
+
+	var problems []string
+	now := time.Now().UTC()
+	if profileInCooldown(profile, now) {
+		problems = append(problems, fmt.Sprintf("profile %s is in cooldown until %s", profile.Name, profile.CooldownUntil.UTC().Format(time.RFC3339)))
+	}
+	if command := profileCommand(profile); command != "" {
+		if _, err := exec.LookPath(command); err != nil {
+			problems = append(problems, fmt.Sprintf("harness command %q not found in PATH", command))
+		}
+	}
+	if profile.MaxConcurrency > 0 {
+		count, err := db.NewLoopRunRepository(database).CountRunningByProfile(ctx, profile.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= profile.MaxConcurrency {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("profile %s is at max concurrency (%d); the loop will wait for a slot", profile.Name, profile.MaxConcurrency))
+		}
+	}
+	if len(problems) > 0 {
+		if !force {
+			return nil, fmt.Errorf("profile %s is not usable: %s (use --force to switch anyway)", profile.Name, strings.Join(problems, "; "))
+		}
+		plan.Warnings = append(plan.Warnings, problems...)
+	}
+	if plan.From != nil && plan.From.Harness != profile.Harness {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("harness changes from %s to %s; in-flight harness session state is not carried over", plan.From.Harness, profile.Harness))
+	}
+
+	items, err := db.NewLoopQueueRepository(database).List(ctx, loop.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.Status != models.LoopQueueStatusPending {
+			continue
+		}
+		switch {
+		case mode == SwitchQueueDrain:
+			plan.dropIDs = append(plan.dropIDs, item.ID)
+		case item.Type == models.LoopQueueItemStopGraceful || item.Type == models.LoopQueueItemKillNow:
+			plan.dropIDs = append(plan.dropIDs, item.ID)
+		default:
+			plan.migrateIDs = append(plan.migrateIDs, item.ID)
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyProfileSwitch pins the loop to the new profile, settles the queue and
+// records a loop.profile_switched event. The runner must already be stopped,
+// since a live runner would write its cached loop record back.
+func ApplyProfileSwitch(ctx context.Context, database *db.DB, plan *ProfileSwitchPlan) error {
+	loopRepo := db.NewLoopRepository(database)
+	queueRepo := db.NewLoopQueueRepository(database)
+
+	loop, err := loopRepo.Get(ctx, plan.Loop.ID)
+	if err != nil {
+		return err
+	}
+	loop.ProfileID = plan.To.ID
+	if plan.Restart {
+		loop.State = models.LoopStateStopped
+	}
+	if loop.Metadata != nil {
+		delete(loop.Metadata, "wait_until")
+	}
+	if err := loopRepo.Update(ctx, loop); err != nil {
+		return err
+	}
+	plan.Loop = loop
+
+	for _, id := range plan.dropIDs {
+		if err := queueRepo.UpdateStatus(ctx, id, models.LoopQueueStatusSkipped, "dropped by profile switch"); err != nil && !errors.Is(err, db.ErrQueueItemNotFound) {
+			return err
+		}
+	}
+
+	payload := models.LoopProfileSwitchedPayload{
+		LoopID:         loop.ID,
+		NewProfileID:   plan.To.ID,
+		NewProfileName: plan.To.Name,
+		NewHarness:     plan.To.Harness,
+		QueueMode:      string(plan.QueueMode),
+		Migrated:       plan.Migrated(),
+		Dropped:        plan.Dropped(),
+		Restarted:      plan.Restart,
+		Reason:         plan.Reason,
+	}
+	if plan.From != nil {
+		payload.OldProfileID = plan.From.ID
+		payload.OldProfileName = plan.From.Name
+		payload.OldHarness = plan.From.Harness
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal switch payload: %w", err)
+	}
+	event := &models.Event{
+		Type:       models.EventTypeLoopProfileSwitched,
+		EntityType: models.EntityTypeLoop,
+		EntityID:   loop.ID,
+		Payload:    data,
+	}
+	if err := db.NewEventRepository(database).Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record switch event: %w", err)
+	}
+	return nil
+}
+
+func loopRunnerActive(loop *models.Loop) bool {
+	switch loop.State {
+	case models.LoopStateRunning, models.LoopStateSleeping, models.LoopStateWaiting:
+		return true
+	}
+	return false
+}
+
+// profileCommand returns the executable a profile's command template runs,
+// or "" when it cannot be determined without a shell.
+func profileCommand(profile *models.Profile) string {
+	fields := strings.Fields(profile.CommandTemplate)
+	if len(fields) == 0 {
+		return ""
+	}
+	command := fields[0]
+	if strings.ContainsAny(command, "=${}`'\"") {
+		return ""
+	}
+	return command
+}
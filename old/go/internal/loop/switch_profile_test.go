@@ -0,0 +1,163 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func setupSwitchProfile(t *testing.T) (*db.DB, *models.Loop, *models.Profile, func()) {
+	t.Helper()
+	database, cleanup := testutil.NewTestDB(t)
+	ctx := context.Background()
+
+	profileRepo := db.NewProfileRepository(database)
+	from := &models.Profile{
+		Name:            "pi-default",
+		Harness:         models.HarnessPi,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "sh -c true",
+	}
+	to := &models.Profile{
+		Name:            "claude-default",
+		Harness:         models.HarnessClaude,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "sh -c true",
+	}
+	for _, profile := range []*models.Profile{from, to} {
+		if err := profileRepo.Create(ctx, profile); err != nil {
+			t.Fatalf("create profile: %v", err)
+		}
+	}
+
+	loopEntry := &models.Loop{
+		Name:            "loop-a",
+		RepoPath:        t.TempDir(),
+		IntervalSeconds: 1,
+		ProfileID:       from.ID,
+		State:           models.LoopStateRunning,
+	}
+	if err := db.NewLoopRepository(database).Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	if err := db.NewLoopQueueRepository(database).Enqueue(ctx, loopEntry.ID,
+		&models.LoopQueueItem{Type: models.LoopQueueItemMessageAppend, Payload: mustJSON(models.MessageAppendPayload{Text: "hello"})},
+		&models.LoopQueueItem{Type: models.LoopQueueItemStopGraceful, Payload: mustJSON(models.StopPayload{Reason: "operator"})},
+	); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return database, loopEntry, to, cleanup
+}
+
+func pendingQueueTypes(t *testing.T, database *db.DB, loopID string) []models.LoopQueueItemType {
+	t.Helper()
+	items, err := db.NewLoopQueueRepository(database).List(context.Background(), loopID)
+	if err != nil {
+		t.Fatalf("list queue: %v", err)
+	}
+	var types []models.LoopQueueItemType
+	for _, item := range items {
+		if item.Status == models.LoopQueueStatusPending {
+			types = append(types, item.Type)
+		}
+	}
+	return types
+}
+
+func TestProfileSwitchMigratesQueueAndRecordsEvent(t *testing.T) {
+	database, loopEntry, to, cleanup := setupSwitchProfile(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	plan, err := PlanProfileSwitch(ctx, database, loopEntry, to, SwitchQueueMigrate, false)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if !plan.Restart {
+		t.Fatalf("expected restart for running loop")
+	}
+	if plan.Migrated() != 1 || plan.Dropped() != 1 {
+		t.Fatalf("expected 1 migrated / 1 dropped, got %d / %d", plan.Migrated(), plan.Dropped())
+	}
+	plan.Reason = "rate limited"
+	if err := ApplyProfileSwitch(ctx, database, plan); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	updated, err := db.NewLoopRepository(database).Get(ctx, loopEntry.ID)
+	if err != nil {
+		t.Fatalf("get loop: %v", err)
+	}
+	if updated.ProfileID != to.ID {
+		t.Fatalf("expected profile %s, got %s", to.ID, updated.ProfileID)
+	}
+	if updated.State != models.LoopStateStopped {
+		t.Fatalf("expected stopped loop awaiting restart, got %s", updated.State)
+	}
+	types := pendingQueueTypes(t, database, loopEntry.ID)
+	if len(types) != 1 || types[0] != models.LoopQueueItemMessageAppend {
+		t.Fatalf("expected only the message to remain pending, got %v", types)
+	}
+
+	events, err := db.NewEventRepository(database).ListByEntity(ctx, models.EntityTypeLoop, loopEntry.ID, 10)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != models.EventTypeLoopProfileSwitched {
+		t.Fatalf("expected one loop.profile_switched event, got %+v", events)
+	}
+	var payload models.LoopProfileSwitchedPayload
+	if err := json.Unmarshal(events[0].Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.OldProfileName != "pi-default" || payload.NewProfileName != "claude-default" || payload.Reason != "rate limited" || payload.Migrated != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestProfileSwitchDrainSkipsAllPending(t *testing.T) {
+	database, loopEntry, to, cleanup := setupSwitchProfile(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	plan, err := PlanProfileSwitch(ctx, database, loopEntry, to, SwitchQueueDrain, false)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if err := ApplyProfileSwitch(ctx, database, plan); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if types := pendingQueueTypes(t, database, loopEntry.ID); len(types) != 0 {
+		t.Fatalf("expected drained queue, got %v", types)
+	}
+}
+
+func TestPlanProfileSwitchRejectsCooldownUnlessForced(t *testing.T) {
+	database, loopEntry, to, cleanup := setupSwitchProfile(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	until := time.Now().Add(time.Hour)
+	to.CooldownUntil = &until
+	if _, err := PlanProfileSwitch(ctx, database, loopEntry, to, SwitchQueueMigrate, false); err == nil || !strings.Contains(err.Error(), "cooldown") {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+	plan, err := PlanProfileSwitch(ctx, database, loopEntry, to, SwitchQueueMigrate, true)
+	if err != nil {
+		t.Fatalf("forced plan: %v", err)
+	}
+	if len(plan.Warnings) == 0 {
+		t.Fatalf("expected cooldown warning on forced plan")
+	}
+
+	if _, err := PlanProfileSwitch(ctx, database, loopEntry, &models.Profile{ID: loopEntry.ProfileID, Name: "pi-default"}, SwitchQueueMigrate, true); err == nil {
+		t.Fatalf("expected error switching to the current profile")
+	}
+}
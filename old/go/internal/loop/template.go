@@ -0,0 +1,149 @@
+package loop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a named set of loop creation values, as entered in the loop
+// wizard or passed to `forge up`. Values are kept as entered so a template
+// can prefill the wizard verbatim; they are validated when loops are created.
+type Template struct {
+	Name          string `yaml:"name" json:"name"`
+	Description   string `yaml:"description,omitempty" json:"description,omitempty"`
+	LoopName      string `yaml:"loop_name,omitempty" json:"loop_name,omitempty"`
+	NamePrefix    string `yaml:"name_prefix,omitempty" json:"name_prefix,omitempty"`
+	Count         string `yaml:"count,omitempty" json:"count,omitempty"`
+	Pool          string `yaml:"pool,omitempty" json:"pool,omitempty"`
+	Profile       string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Prompt        string `yaml:"prompt,omitempty" json:"prompt,omitempty"`
+	PromptMsg     string `yaml:"prompt_msg,omitempty" json:"prompt_msg,omitempty"`
+	Interval      string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	MaxRuntime    string `yaml:"max_runtime,omitempty" json:"max_runtime,omitempty"`
+	MaxIterations string `yaml:"max_iterations,omitempty" json:"max_iterations,omitempty"`
+	Tags          string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// ErrTemplateNotFound is returned when a named loop template does not exist.
+var ErrTemplateNotFound = errors.New("loop template not found")
+
+// TemplateDir returns the directory loop templates are stored in.
+func TemplateDir(configDir string) string {
+	return filepath.Join(configDir, "loop-templates")
+}
+
+// ValidateTemplateName checks that name is usable as a template file name.
+func ValidateTemplateName(name string) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return "", errors.New("template name is required")
+	}
+	if strings.ContainsAny(trimmed, `/\`) || strings.Contains(trimmed, "..") {
+		return "", fmt.Errorf("invalid template name %q", trimmed)
+	}
+	return trimmed, nil
+}
+
+// ListTemplates returns the templates in dir sorted by name. A missing
+// directory yields no templates.
+func ListTemplates(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	templates := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		tpl, err := readTemplate(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadTemplate reads a single template by name.
+func LoadTemplate(dir, name string) (Template, error) {
+	name, err := ValidateTemplateName(name)
+	if err != nil {
+		return Template{}, err
+	}
+	tpl, err := readTemplate(templatePath(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Template{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return Template{}, err
+	}
+	return tpl, nil
+}
+
+// SaveTemplate writes tpl to dir, replacing any template with the same name.
+func SaveTemplate(dir string, tpl Template) error {
+	name, err := ValidateTemplateName(tpl.Name)
+	if err != nil {
+		return err
+	}
+	tpl.Name = name
+
+	data, err := yaml.Marshal(tpl)
+	if err != nil {
+		return fmt.Errorf("encode loop template: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp := templatePath(dir, name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, templatePath(dir, name))
+}
+
+// DeleteTemplate removes a template by name.
+func DeleteTemplate(dir, name string) error {
+	name, err := ValidateTemplateName(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(templatePath(dir, name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return err
+	}
+	return nil
+}
+
+func templatePath(dir, name string) string {
+	return filepath.Join(dir, name+".yaml")
+}
+
+func readTemplate(path string) (Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, err
+	}
+	var tpl Template
+	if err := yaml.Unmarshal(data, &tpl); err != nil {
+		return Template{}, fmt.Errorf("parse loop template %s: %w", path, err)
+	}
+	if strings.TrimSpace(tpl.Name) == "" {
+		tpl.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return tpl, nil
+}
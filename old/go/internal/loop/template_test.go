@@ -0,0 +1,49 @@
+package loop
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateSaveListLoadDelete(t *testing.T) {
+	dir := TemplateDir(t.TempDir())
+
+	if templates, err := ListTemplates(dir); err != nil || len(templates) != 0 {
+		t.Fatalf("expected no templates in missing dir, got %v, %v", templates, err)
+	}
+
+	for _, tpl := range []Template{
+		{Name: "review", Pool: "default", Count: "2", Interval: "30s"},
+		{Name: "docs", Profile: "claude", Prompt: "docs"},
+	} {
+		if err := SaveTemplate(dir, tpl); err != nil {
+			t.Fatalf("save %s: %v", tpl.Name, err)
+		}
+	}
+
+	templates, err := ListTemplates(dir)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "docs" || templates[1].Name != "review" {
+		t.Fatalf("expected templates sorted by name, got %+v", templates)
+	}
+
+	tpl, err := LoadTemplate(dir, "review")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tpl.Pool != "default" || tpl.Count != "2" || tpl.Interval != "30s" {
+		t.Fatalf("unexpected template: %+v", tpl)
+	}
+
+	if err := DeleteTemplate(dir, "review"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := LoadTemplate(dir, "review"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound, got %v", err)
+	}
+	if err := SaveTemplate(dir, Template{Name: "../escape"}); err == nil {
+		t.Fatalf("expected invalid name to be rejected")
+	}
+}
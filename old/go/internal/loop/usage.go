@@ -0,0 +1,198 @@
+package loop
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// maxUsageLineBytes bounds how much of a single output line is buffered
+// while looking for usage reports.
+const maxUsageLineBytes = 1 << 20
+
+// RunUsage is the token usage and cost a harness reported for one run.
+type RunUsage struct {
+	InputTokens  int64
+	OutputTokens int64
+	TotalTokens  int64
+	CostUSD      float64
+}
+
+// Empty reports whether nothing was reported.
+func (u RunUsage) Empty() bool {
+	return u.InputTokens == 0 && u.OutputTokens == 0 && u.TotalTokens == 0 && u.CostUSD == 0
+}
+
+// Apply copies the usage onto a run.
+func (u RunUsage) Apply(run *models.LoopRun) {
+	if run == nil {
+		return
+	}
+	run.InputTokens = u.InputTokens
+	run.OutputTokens = u.OutputTokens
+	run.TotalTokens = u.TotalTokens
+	run.CostUSD = u.CostUSD
+}
+
+var codexTokensUsedPattern = regexp.MustCompile(`(?i)^tokens used:?\s*([\d,]+)$`)
+
+// usageScanner watches harness output for usage reports. It understands
+// JSON lines carrying a "usage" object (Claude stream-json/json output,
+// OpenAI-style prompt/completion tokens) with an optional total_cost_usd,
+// and Codex's trailing "tokens used" line. A final Claude "result" event
+// wins over the per-message usage that precedes it; otherwise the last
+// report seen is kept.
+type usageScanner struct {
+	mu        sync.Mutex
+	buf       []byte
+	usage     RunUsage
+	hasResult bool
+	prevCodex bool
+}
+
+func newUsageScanner() *usageScanner {
+	return &usageScanner{}
+}
+
+func (s *usageScanner) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := p
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			if len(s.buf)+len(data) <= maxUsageLineBytes {
+				s.buf = append(s.buf, data...)
+			}
+			break
+		}
+		if len(s.buf)+idx <= maxUsageLineBytes {
+			s.buf = append(s.buf, data[:idx]...)
+			s.scanLine(string(s.buf))
+		}
+		s.buf = s.buf[:0]
+		data = data[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Usage returns the usage seen so far, including an unterminated last line.
+func (s *usageScanner) Usage() RunUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.buf) > 0 {
+		s.scanLine(string(s.buf))
+		s.buf = s.buf[:0]
+	}
+	return s.usage
+}
+
+func (s *usageScanner) scanLine(line string) {
+	line = strings.TrimSpace(line)
+	prevCodex := s.prevCodex
+	s.prevCodex = false
+	if line == "" {
+		return
+	}
+
+	if strings.HasPrefix(line, "{") {
+		s.scanJSON(line)
+		return
+	}
+	if match := codexTokensUsedPattern.FindStringSubmatch(line); match != nil && match[1] != "" {
+		s.setCodexTotal(match[1])
+		return
+	}
+	if strings.EqualFold(line, "tokens used") {
+		s.prevCodex = true
+		return
+	}
+	if prevCodex {
+		s.setCodexTotal(line)
+	}
+}
+
+func (s *usageScanner) setCodexTotal(raw string) {
+	total, err := strconv.ParseInt(strings.ReplaceAll(raw, ",", ""), 10, 64)
+	if err != nil || total <= 0 || s.hasResult {
+		return
+	}
+	s.usage = RunUsage{TotalTokens: total}
+}
+
+type usageEvent struct {
+	Type         string          `json:"type"`
+	Usage        *usageCounts    `json:"usage"`
+	Message      *usageEventBody `json:"message"`
+	TotalCostUSD *float64        `json:"total_cost_usd"`
+	CostUSD      *float64        `json:"cost_usd"`
+}
+
+type usageEventBody struct {
+	Usage *usageCounts `json:"usage"`
+}
+
+type usageCounts struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	PromptTokens             int64 `json:"prompt_tokens"`
+	CompletionTokens         int64 `json:"completion_tokens"`
+	TotalTokens              int64 `json:"total_tokens"`
+}
+
+func (s *usageScanner) scanJSON(line string) {
+	var event usageEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return
+	}
+	counts := event.Usage
+	if counts == nil && event.Message != nil {
+		counts = event.Message.Usage
+	}
+	cost := event.TotalCostUSD
+	if cost == nil {
+		cost = event.CostUSD
+	}
+	if counts == nil && cost == nil {
+		return
+	}
+
+	isResult := event.Type == "result"
+	if s.hasResult && !isResult {
+		return
+	}
+
+	usage := RunUsage{}
+	if counts != nil {
+		usage.InputTokens = counts.InputTokens + counts.CacheCreationInputTokens + counts.CacheReadInputTokens + counts.PromptTokens
+		usage.OutputTokens = counts.OutputTokens + counts.CompletionTokens
+		usage.TotalTokens = counts.TotalTokens
+		if usage.TotalTokens == 0 {
+			usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+		}
+	} else if !isResult {
+		usage = s.usage
+	}
+	if cost != nil {
+		usage.CostUSD = *cost
+	}
+	if usage.Empty() {
+		return
+	}
+	s.usage = usage
+	s.hasResult = s.hasResult || isResult
+}
+
+// ParseRunUsage extracts usage from complete harness output.
+func ParseRunUsage(output string) RunUsage {
+	scanner := newUsageScanner()
+	_, _ = scanner.Write([]byte(output))
+	return scanner.Usage()
+}
@@ -0,0 +1,123 @@
+package loop
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+)
+
+func TestParseRunUsage(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   RunUsage
+	}{
+		{
+			name: "claude stream-json prefers result event",
+			output: `{"type":"assistant","message":{"usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"result","total_cost_usd":0.0123,"usage":{"input_tokens":100,"cache_read_input_tokens":50,"output_tokens":40}}
+{"type":"assistant","message":{"usage":{"input_tokens":1,"output_tokens":1}}}`,
+			want: RunUsage{InputTokens: 150, OutputTokens: 40, TotalTokens: 190, CostUSD: 0.0123},
+		},
+		{
+			name:   "openai style counts",
+			output: "working\n{\"usage\":{\"prompt_tokens\":30,\"completion_tokens\":12,\"total_tokens\":42}}\n",
+			want:   RunUsage{InputTokens: 30, OutputTokens: 12, TotalTokens: 42},
+		},
+		{
+			name:   "codex inline total",
+			output: "done\ntokens used: 12,345\n",
+			want:   RunUsage{TotalTokens: 12345},
+		},
+		{
+			name:   "codex split total",
+			output: "done\ntokens used\n2,048",
+			want:   RunUsage{TotalTokens: 2048},
+		},
+		{
+			name:   "no usage",
+			output: "plain output\n{\"note\":\"not usage\"}\n",
+			want:   RunUsage{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ParseRunUsage(tc.output); got != tc.want {
+				t.Fatalf("ParseRunUsage = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUsageScannerHandlesSplitWrites(t *testing.T) {
+	scanner := newUsageScanner()
+	line := `{"type":"result","total_cost_usd":1.5,"usage":{"input_tokens":7,"output_tokens":3}}` + "\n"
+	for i := 0; i < len(line); i += 9 {
+		end := i + 9
+		if end > len(line) {
+			end = len(line)
+		}
+		_, _ = scanner.Write([]byte(line[i:end]))
+	}
+	want := RunUsage{InputTokens: 7, OutputTokens: 3, TotalTokens: 10, CostUSD: 1.5}
+	if got := scanner.Usage(); got != want {
+		t.Fatalf("Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunnerRecordsRunUsage(t *testing.T) {
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	cfg := config.DefaultConfig()
+	cfg.Global.DataDir = t.TempDir()
+	cfg.Global.ConfigDir = t.TempDir()
+
+	profile := &models.Profile{
+		Name:            "claude-usage",
+		Harness:         models.HarnessClaude,
+		PromptMode:      models.PromptModeEnv,
+		CommandTemplate: "claude -p \"$FORGE_PROMPT_CONTENT\"",
+		MaxConcurrency:  1,
+	}
+	if err := db.NewProfileRepository(database).Create(context.Background(), profile); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+	loopEntry := &models.Loop{
+		Name:            "usage-loop",
+		RepoPath:        t.TempDir(),
+		BasePromptMsg:   "count tokens",
+		IntervalSeconds: 1,
+		ProfileID:       profile.ID,
+		State:           models.LoopStateStopped,
+	}
+	if err := db.NewLoopRepository(database).Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	runner := NewRunner(database, cfg)
+	runner.Exec = func(ctx context.Context, p models.Profile, promptPath, promptContent, workDir string, output io.Writer) (int, string, error) {
+		_, _ = io.WriteString(output, `{"type":"result","total_cost_usd":0.42,"usage":{"input_tokens":900,"output_tokens":100}}`+"\n")
+		return 0, "", nil
+	}
+	if err := runner.RunOnce(context.Background(), loopEntry.ID); err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+
+	runs, err := db.NewLoopRunRepository(database).ListByLoop(context.Background(), loopEntry.ID)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	run := runs[0]
+	if run.InputTokens != 900 || run.OutputTokens != 100 || run.TotalTokens != 1000 || run.CostUSD != 0.42 {
+		t.Fatalf("unexpected run usage: %+v", run)
+	}
+}
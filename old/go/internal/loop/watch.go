@@ -0,0 +1,99 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultWatchTimeout caps each watch expression so a slow command cannot
+// stall the caller's refresh.
+const DefaultWatchTimeout = 2 * time.Second
+
+// WatchResult is one evaluation of a loop watch expression.
+type WatchResult struct {
+	Name     string `json:"name"`
+	Expr     string `json:"expr"`
+	Value    string `json:"value"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Failed reports whether the expression exited non-zero or timed out.
+func (r WatchResult) Failed() bool {
+	return r.ExitCode != 0 || r.Error != ""
+}
+
+// EvalWatches runs every watch expression of the loop concurrently in its
+// repo root and returns the results in definition order. Value is the first
+// non-empty stdout line, or the last stderr line when the command failed.
+func EvalWatches(ctx context.Context, loopEntry *models.Loop, timeout time.Duration) []WatchResult {
+	watches := models.LoopWatches(loopEntry)
+	if len(watches) == 0 {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultWatchTimeout
+	}
+
+	environ := append(os.Environ(),
+		"REPO="+loopEntry.RepoPath,
+		"LOOP_ID="+loopEntry.ID,
+		"LOOP_NAME="+loopEntry.Name,
+	)
+
+	results := make([]WatchResult, len(watches))
+	var wg sync.WaitGroup
+	for i, watch := range watches {
+		wg.Add(1)
+		go func(i int, watch models.LoopWatch) {
+			defer wg.Done()
+			started := time.Now()
+			res := runShellCommand(ctx, loopEntry.RepoPath, watch.Expr, environ, timeout)
+			result := WatchResult{
+				Name:     watch.Name,
+				Expr:     watch.Expr,
+				Value:    firstOutputLine(res.stdout),
+				ExitCode: res.exitCode,
+			}
+			if res.exitCode != 0 {
+				if result.Value == "" {
+					result.Value = lastOutputLine(res.stderr)
+				}
+				switch {
+				case res.exitCode == -1 && time.Since(started) >= timeout:
+					result.Error = fmt.Sprintf("timed out after %s", timeout)
+				case res.exitCode == -1 && res.err != nil:
+					result.Error = res.err.Error()
+				}
+			}
+			results[i] = result
+		}(i, watch)
+	}
+	wg.Wait()
+	return results
+}
+
+func firstOutputLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func lastOutputLine(output string) string {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestEvalWatches(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loopEntry := &models.Loop{ID: "loop-1", Name: "alpha", RepoPath: repo}
+	models.SetLoopWatches(loopEntry, []models.LoopWatch{
+		{Name: "files", Expr: "ls $REPO | wc -l"},
+		{Name: "name", Expr: "echo; echo $LOOP_NAME"},
+		{Name: "broken", Expr: "echo nope >&2; exit 3"},
+	})
+
+	results := EvalWatches(context.Background(), loopEntry, 30*time.Second)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Value != "1" || results[0].Failed() {
+		t.Fatalf("unexpected files result: %+v", results[0])
+	}
+	if results[1].Value != "alpha" {
+		t.Fatalf("unexpected name result: %+v", results[1])
+	}
+	if results[2].ExitCode != 3 || results[2].Value != "nope" || !results[2].Failed() {
+		t.Fatalf("unexpected broken result: %+v", results[2])
+	}
+
+	models.SetLoopWatches(loopEntry, []models.LoopWatch{{Name: "slow", Expr: "sleep 5"}})
+	results = EvalWatches(context.Background(), loopEntry, 100*time.Millisecond)
+	if len(results) != 1 || results[0].Error == "" || !results[0].Failed() {
+		t.Fatalf("expected slow watch to time out: %+v", results)
+	}
+}
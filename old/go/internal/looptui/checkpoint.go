@@ -0,0 +1,117 @@
+package looptui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// checkpointDiffState is the diff of repo changes made since a run's git
+// checkpoint. It is cleared when the run selection moves.
+type checkpointDiffState struct {
+	RunID   string
+	SHA     string
+	Loading bool
+	Lines   []string
+	Err     string
+}
+
+type checkpointDiffMsg struct {
+	RunID string
+	Diff  string
+	Err   error
+}
+
+// toggleCheckpointDiff shows the selected run's checkpoint diff, or hides it
+// when it is already shown.
+func (m *model) toggleCheckpointDiff() tea.Cmd {
+	run, ok := m.selectedRunView()
+	if !ok || run.Run == nil {
+		m.setStatus(statusInfo, "No run selected")
+		return nil
+	}
+	if m.checkpoint.RunID == run.Run.ID {
+		m.checkpoint = checkpointDiffState{}
+		m.logScroll = 0
+		m.setStatus(statusInfo, "Checkpoint diff off")
+		return nil
+	}
+	checkpoint, ok := loop.RunCheckpoint(run.Run)
+	if !ok {
+		m.setStatus(statusInfo, fmt.Sprintf("Run %s has no checkpoint (start loops with --checkpoint)", shortRunID(run.Run.ID)))
+		return nil
+	}
+	view, ok := m.selectedView()
+	if !ok || view.Loop == nil {
+		return nil
+	}
+
+	m.checkpoint = checkpointDiffState{RunID: run.Run.ID, SHA: checkpoint.SHA, Loading: true}
+	m.compareRunID = ""
+	m.logLayer = logLayerDiff
+	m.logScroll = 0
+
+	repoPath := view.Loop.RepoPath
+	runID := run.Run.ID
+	next := loop.NextRunCheckpoint(m.historyRuns(), runID)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		diff, err := loop.CheckpointDiff(ctx, repoPath, checkpoint, next)
+		return checkpointDiffMsg{RunID: runID, Diff: diff, Err: err}
+	}
+}
+
+func (m *model) applyCheckpointDiff(msg checkpointDiffMsg) {
+	if msg.RunID != m.checkpoint.RunID {
+		return
+	}
+	m.checkpoint.Loading = false
+	if msg.Err != nil {
+		m.checkpoint.Err = msg.Err.Error()
+		m.setStatus(statusErr, "Checkpoint diff: "+msg.Err.Error())
+		return
+	}
+	m.checkpoint.Lines = splitDiffLines(msg.Diff)
+}
+
+func (m model) checkpointDiffDisplay(run runView) logDisplay {
+	display := logDisplay{
+		Title:   fmt.Sprintf("Run %s | changes since checkpoint %s", shortRunID(run.Run.ID), shortRunID(m.checkpoint.SHA)),
+		Source:  "checkpoint",
+		Lines:   m.checkpoint.Lines,
+		Message: "No changes since checkpoint.",
+		Harness: run.Harness,
+	}
+	switch {
+	case m.checkpoint.Loading:
+		display.Message = "Loading checkpoint diff..."
+	case m.checkpoint.Err != "":
+		display.Message = "Checkpoint diff failed: " + m.checkpoint.Err
+	}
+	return display
+}
+
+// historyRuns returns the loaded run history, newest first.
+func (m model) historyRuns() []*models.LoopRun {
+	runs := make([]*models.LoopRun, 0, len(m.runHistory))
+	for _, view := range m.runHistory {
+		if view.Run != nil {
+			runs = append(runs, view.Run)
+		}
+	}
+	return runs
+}
+
+func splitDiffLines(diff string) []string {
+	diff = strings.TrimRight(diff, "\n")
+	if diff == "" {
+		return nil
+	}
+	return strings.Split(diff, "\n")
+}
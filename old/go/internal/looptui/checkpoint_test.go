@@ -0,0 +1,69 @@
+package looptui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestCheckpointDiffKeyShowsChangesSinceRunStart(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	checkpoint, err := loop.CreateCheckpoint(context.Background(), repo, models.LoopCheckpointCommit, "id-a", "run-1")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "edited.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.loops = []loopView{testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, repo)}
+	m.applyFilters("", 0)
+	m.tab = tabRuns
+	m.runHistory = []runView{
+		{Run: &models.LoopRun{ID: "run-1", StartedAt: time.Now().UTC(), OutputTail: "working", Metadata: map[string]any{"checkpoint": checkpoint}}},
+		{Run: &models.LoopRun{ID: "run-0", StartedAt: time.Now().Add(-time.Minute).UTC(), OutputTail: "done"}},
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = next.(model)
+	if cmd == nil || m.checkpoint.RunID != "run-1" || m.logLayer != logLayerDiff {
+		t.Fatalf("expected checkpoint diff to load for run-1, got %+v layer=%v", m.checkpoint, m.logLayer)
+	}
+	m = updateModel(t, m, cmd())
+
+	view, _ := m.selectedView()
+	pane := stripANSI(m.renderRunsPane(view, 100, 30))
+	if !strings.Contains(pane, "changes since checkpoint") || !strings.Contains(pane, "package main") {
+		t.Fatalf("expected checkpoint diff in runs pane, got:\n%s", pane)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if m.checkpoint.RunID != "" {
+		t.Fatalf("expected moving the selection to clear the diff, got %+v", m.checkpoint)
+	}
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = next.(model)
+	if cmd != nil || !strings.Contains(m.statusText, "no checkpoint") {
+		t.Fatalf("expected no-checkpoint status for run-0, got %q", m.statusText)
+	}
+}
@@ -0,0 +1,74 @@
+package looptui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openCloneWizard opens the new-loop wizard prefilled with the selected
+// loop's configuration, so a similar loop only needs the differences typed.
+func (m *model) openCloneWizard() {
+	view, ok := m.selectedView()
+	if !ok || view.Loop == nil {
+		m.setStatus(statusInfo, "No loop selected")
+		return
+	}
+	m.mode = modeWizard
+	m.wizard = newWizardState(m.defaultInterval, m.defaultPrompt, m.defaultPromptMsg)
+	m.loadWizardTemplates()
+	m.wizard.Values = wizardValuesFromLoop(view, m.loopNames())
+	m.wizard.CloneOf = view.Loop.Name
+}
+
+// wizardValuesFromLoop copies a loop's prompt, runtime limits, pool or
+// profile, and tags into wizard values. The name gets a -copy suffix that
+// does not collide with existing loops.
+func wizardValuesFromLoop(view loopView, existing map[string]struct{}) wizardValues {
+	l := view.Loop
+	values := wizardValues{
+		Name:      cloneLoopName(l.Name, existing),
+		Count:     "1",
+		Prompt:    l.BasePromptPath,
+		PromptMsg: l.BasePromptMsg,
+		Interval:  (time.Duration(l.IntervalSeconds) * time.Second).String(),
+		Tags:      strings.Join(l.Tags, ","),
+	}
+	// The wizard accepts a pool or a profile, not both. A pooled loop also
+	// records the profile it last ran with, so the pool wins.
+	switch {
+	case l.PoolID != "":
+		values.Pool = defaultString(view.PoolName, l.PoolID)
+	case l.ProfileID != "":
+		values.Profile = defaultString(view.ProfileName, l.ProfileID)
+	}
+	if l.MaxRuntimeSeconds > 0 {
+		values.MaxRuntime = (time.Duration(l.MaxRuntimeSeconds) * time.Second).String()
+	}
+	if l.MaxIterations > 0 {
+		values.MaxIterations = strconv.Itoa(l.MaxIterations)
+	}
+	return values
+}
+
+func cloneLoopName(name string, existing map[string]struct{}) string {
+	base := strings.TrimSpace(name) + "-copy"
+	candidate := base
+	for i := 2; ; i++ {
+		if _, taken := existing[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func (m model) loopNames() map[string]struct{} {
+	names := make(map[string]struct{}, len(m.loops))
+	for _, view := range m.loops {
+		if view.Loop != nil {
+			names[view.Loop.Name] = struct{}{}
+		}
+	}
+	return names
+}
@@ -0,0 +1,62 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestCloneLoopPrefillsWizard(t *testing.T) {
+	source := testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/repo/a")
+	source.Loop.BasePromptPath = "/repo/a/.forge/prompts/review.md"
+	source.Loop.BasePromptMsg = "focus on tests"
+	source.Loop.IntervalSeconds = 90
+	source.Loop.MaxRuntimeSeconds = 3600
+	source.Loop.MaxIterations = 12
+	source.Loop.PoolID = "pool-1"
+	source.Loop.ProfileID = "profile-1"
+	source.Loop.Tags = []string{"review", "nightly"}
+	source.PoolName = "default"
+	taken := testLoopView("id-b", "idb", "alpha-copy", models.LoopStateStopped, "/repo/a")
+
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m = updateModel(t, m, refreshMsg{loops: []loopView{source, taken}})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	if m.mode != modeWizard || m.wizard.CloneOf != "alpha" {
+		t.Fatalf("expected clone wizard for alpha, got mode %v clone %q", m.mode, m.wizard.CloneOf)
+	}
+
+	want := wizardValues{
+		Name:          "alpha-copy-2",
+		Count:         "1",
+		Pool:          "default",
+		Prompt:        "/repo/a/.forge/prompts/review.md",
+		PromptMsg:     "focus on tests",
+		Interval:      "1m30s",
+		MaxRuntime:    "1h0m0s",
+		MaxIterations: "12",
+		Tags:          "review,nightly",
+	}
+	if m.wizard.Values != want {
+		t.Fatalf("unexpected clone values:\n got %+v\nwant %+v", m.wizard.Values, want)
+	}
+	if err := validateWizardStep(3, m.wizard.Values, m.defaultInterval); err != nil {
+		t.Fatalf("expected cloned values to validate, got %v", err)
+	}
+	if !strings.Contains(m.renderWizard(100), "Clone loop wizard (from alpha)") {
+		t.Fatalf("expected clone title in wizard")
+	}
+}
+
+func TestCloneKeyTogglesDiffOnLogTabs(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m = updateModel(t, m, refreshMsg{loops: []loopView{testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/repo/a")}})
+	m.setTab(tabLogs)
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	if m.mode != modeMain || !m.diffCollapse {
+		t.Fatalf("expected C to collapse diff on the logs tab, got mode %v collapse %v", m.mode, m.diffCollapse)
+	}
+}
@@ -0,0 +1,306 @@
+package looptui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// listColumn names a loop list column. The names are the values accepted in
+// the config file's tui.loop_columns list.
+type listColumn string
+
+const (
+	columnStatus  listColumn = "status"
+	columnID      listColumn = "id"
+	columnName    listColumn = "name"
+	columnRuns    listColumn = "runs"
+	columnQueue   listColumn = "queue"
+	columnLastRun listColumn = "last_run"
+	columnHarness listColumn = "harness"
+	columnProfile listColumn = "profile"
+	columnPool    listColumn = "pool"
+	columnTags    listColumn = "tags"
+	columnCPU     listColumn = "cpu"
+	columnDir     listColumn = "dir"
+)
+
+type columnSpec struct {
+	header string
+	width  int
+	right  bool
+}
+
+var listColumnSpecs = map[listColumn]columnSpec{
+	columnStatus:  {header: "STATUS", width: 7},
+	columnID:      {header: "ID", width: 9},
+	columnName:    {header: "NAME", width: 16},
+	columnRuns:    {header: "RUNS", width: 4, right: true},
+	columnQueue:   {header: "QUEUE", width: 5, right: true},
+	columnLastRun: {header: "LAST", width: 4, right: true},
+	columnHarness: {header: "HARNESS", width: 9},
+	columnProfile: {header: "PROFILE", width: 12},
+	columnPool:    {header: "POOL", width: 12},
+	columnTags:    {header: "TAGS", width: 16},
+	columnCPU:     {header: "CPU", width: listSparkWidth},
+	columnDir:     {header: "DIR", width: 16},
+}
+
+// defaultListColumns is the list layout used when tui.loop_columns is unset.
+var defaultListColumns = []listColumn{columnStatus, columnID, columnRuns, columnHarness, columnCPU, columnDir}
+
+// parseListColumns resolves configured column names. An empty list yields
+// the defaults; unknown and repeated columns are rejected.
+func parseListColumns(names []string) ([]listColumn, error) {
+	if len(names) == 0 {
+		return defaultListColumns, nil
+	}
+	columns := make([]listColumn, 0, len(names))
+	seen := make(map[listColumn]struct{}, len(names))
+	for _, name := range names {
+		column := listColumn(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := listColumnSpecs[column]; !ok {
+			return nil, fmt.Errorf("tui.loop_columns: unknown column %q (valid: %s)", name, strings.Join(listColumnNames(), ", "))
+		}
+		if _, dup := seen[column]; dup {
+			return nil, fmt.Errorf("tui.loop_columns: column %q listed twice", column)
+		}
+		seen[column] = struct{}{}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func listColumnNames() []string {
+	names := make([]string, 0, len(listColumnSpecs))
+	for column := range listColumnSpecs {
+		names = append(names, string(column))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// listSortKey orders the loop list.
+type listSortKey string
+
+const (
+	sortCreated listSortKey = "created"
+	sortStatus  listSortKey = "status"
+	sortRuns    listSortKey = "runs"
+	sortLastRun listSortKey = "last_run"
+	sortQueue   listSortKey = "queue"
+)
+
+// listSortOrder is the cycle order of the sort key binding.
+var listSortOrder = []listSortKey{sortCreated, sortStatus, sortRuns, sortLastRun, sortQueue}
+
+func parseListSort(name string) (listSortKey, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return sortCreated, nil
+	}
+	for _, key := range listSortOrder {
+		if string(key) == name {
+			return key, nil
+		}
+	}
+	names := make([]string, len(listSortOrder))
+	for i, key := range listSortOrder {
+		names[i] = string(key)
+	}
+	return "", fmt.Errorf("tui.loop_sort: unknown sort %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// ValidateListLayout reports whether the loop list columns and sort are
+// usable.
+func ValidateListLayout(columns []string, sortKey string) error {
+	if _, err := parseListColumns(columns); err != nil {
+		return err
+	}
+	_, err := parseListSort(sortKey)
+	return err
+}
+
+func (k listSortKey) label() string {
+	switch k {
+	case sortStatus:
+		return "status"
+	case sortRuns:
+		return "runs (most first)"
+	case sortLastRun:
+		return "last run (newest first)"
+	case sortQueue:
+		return "queue depth (deepest first)"
+	default:
+		return "created"
+	}
+}
+
+// loopStateRank orders states for the status sort: active loops first.
+var loopStateRank = map[models.LoopState]int{
+	models.LoopStateRunning:  0,
+	models.LoopStateSleeping: 1,
+	models.LoopStateWaiting:  2,
+	models.LoopStateError:    3,
+	models.LoopStateStopped:  4,
+}
+
+// sortLoopViews orders views in place. Ties keep creation order.
+func sortLoopViews(views []loopView, key listSortKey) {
+	if key == sortCreated || key == "" {
+		return
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		left, right := views[i], views[j]
+		if left.Loop == nil || right.Loop == nil {
+			return false
+		}
+		switch key {
+		case sortStatus:
+			return stateRank(left.Loop.State) < stateRank(right.Loop.State)
+		case sortRuns:
+			return left.Runs > right.Runs
+		case sortQueue:
+			return left.QueueDepth > right.QueueDepth
+		case sortLastRun:
+			if left.Loop.LastRunAt == nil || right.Loop.LastRunAt == nil {
+				return left.Loop.LastRunAt != nil && right.Loop.LastRunAt == nil
+			}
+			return left.Loop.LastRunAt.After(*right.Loop.LastRunAt)
+		}
+		return false
+	})
+}
+
+func stateRank(state models.LoopState) int {
+	if rank, ok := loopStateRank[state]; ok {
+		return rank
+	}
+	return len(loopStateRank)
+}
+
+func (m *model) cycleListSort() {
+	idx := 0
+	for i, key := range listSortOrder {
+		if key == m.listSort {
+			idx = i
+			break
+		}
+	}
+	m.listSort = listSortOrder[(idx+1)%len(listSortOrder)]
+	m.applyFilters(m.selectedID, m.selectedIdx)
+	m.setStatus(statusInfo, "Sort: "+m.listSort.label())
+}
+
+// renderListHeader renders the column titles aligned with renderListRow.
+func (m model) renderListHeader() string {
+	cells := make([]string, len(m.listColumns))
+	for i, column := range m.listColumns {
+		spec := listColumnSpecs[column]
+		if i == len(m.listColumns)-1 {
+			cells[i] = spec.header
+			continue
+		}
+		cells[i] = alignCell(spec.header, spec)
+	}
+	return "P " + strings.Join(cells, " ")
+}
+
+func (m model) renderListRow(view loopView, width int) string {
+	if view.Loop == nil {
+		return ""
+	}
+	pin := " "
+	if m.isPinned(view.Loop.ID) {
+		pin = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Warning)).Bold(true).Render("P")
+	}
+
+	cells := make([]string, len(m.listColumns))
+	for i, column := range m.listColumns {
+		spec := listColumnSpecs[column]
+		if column == columnStatus {
+			status := truncateLine(strings.ToUpper(string(view.Loop.State)), spec.width)
+			cells[i] = statusStyleForPalette(m.palette, view.Loop.State).Render(padRight(status, spec.width))
+			continue
+		}
+		value := m.listCellValue(view, column)
+		if i == len(m.listColumns)-1 {
+			cells[i] = value
+			continue
+		}
+		cells[i] = alignCell(value, spec)
+	}
+	return truncateLine(pin+" "+strings.Join(cells, " "), width)
+}
+
+func (m model) listCellValue(view loopView, column listColumn) string {
+	loopEntry := view.Loop
+	switch column {
+	case columnID:
+		return loopDisplayID(loopEntry)
+	case columnName:
+		return loopEntry.Name
+	case columnRuns:
+		return fmt.Sprintf("%d", view.Runs)
+	case columnQueue:
+		return fmt.Sprintf("%d", view.QueueDepth)
+	case columnLastRun:
+		return formatListAge(loopEntry.LastRunAt, time.Now())
+	case columnHarness:
+		return orDash(strings.ToLower(string(view.ProfileHarness)))
+	case columnProfile:
+		return orDash(view.ProfileName)
+	case columnPool:
+		return orDash(view.PoolName)
+	case columnTags:
+		return orDash(strings.Join(loopEntry.Tags, ","))
+	case columnCPU:
+		cpu := m.resources[loopEntry.ID].cpuSparkline(listSparkWidth)
+		if strings.TrimSpace(cpu) == "" {
+			return "-"
+		}
+		return cpu
+	case columnDir:
+		return filepath.Base(loopEntry.RepoPath)
+	}
+	return ""
+}
+
+func alignCell(value string, spec columnSpec) string {
+	value = truncateLine(value, spec.width)
+	fill := strings.Repeat(" ", maxInt(0, spec.width-lipgloss.Width(value)))
+	if spec.right {
+		return fill + value
+	}
+	return value + fill
+}
+
+// formatListAge renders the time since t compactly ("45s", "12m", "3h", "2d").
+func formatListAge(t *time.Time, now time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	age := now.Sub(*t)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", maxInt(0, int(age.Seconds())))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+func orDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
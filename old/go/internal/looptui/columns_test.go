@@ -0,0 +1,101 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestListSortKeyCyclesOrder(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.width = 140
+	m.height = 40
+
+	now := time.Now().UTC()
+	older := now.Add(-time.Hour)
+	alpha := testLoopView("id-a", "ida", "alpha", models.LoopStateStopped, "/tmp/a")
+	alpha.Runs = 2
+	alpha.QueueDepth = 5
+	beta := testLoopView("id-b", "idb", "beta", models.LoopStateRunning, "/tmp/b")
+	beta.Runs = 9
+	beta.Loop.LastRunAt = &older
+	gamma := testLoopView("id-c", "idc", "gamma", models.LoopStateSleeping, "/tmp/c")
+	gamma.Loop.LastRunAt = &now
+	gamma.QueueDepth = 1
+	m = updateModel(t, m, refreshMsg{loops: []loopView{alpha, beta, gamma}})
+
+	order := func() string {
+		ids := make([]string, len(m.filtered))
+		for i, view := range m.filtered {
+			ids[i] = view.Loop.ShortID
+		}
+		return strings.Join(ids, ",")
+	}
+	if got := order(); got != "ida,idb,idc" {
+		t.Fatalf("expected creation order, got %s", got)
+	}
+
+	sortKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")}
+	want := []struct {
+		sort  listSortKey
+		order string
+	}{
+		{sortStatus, "idb,idc,ida"},
+		{sortRuns, "idb,ida,idc"},
+		{sortLastRun, "idc,idb,ida"},
+		{sortQueue, "ida,idc,idb"},
+		{sortCreated, "ida,idb,idc"},
+	}
+	for _, step := range want {
+		m = updateModel(t, m, sortKey)
+		if m.listSort != step.sort || order() != step.order {
+			t.Fatalf("sort %s: expected %s, got sort=%s order=%s", step.sort, step.order, m.listSort, order())
+		}
+	}
+}
+
+func TestListColumnsFromConfig(t *testing.T) {
+	m := newModel(nil, Config{
+		RefreshInterval: time.Second,
+		LogLines:        8,
+		Theme:           "default",
+		ListColumns:     []string{"name", "pool", "tags", "queue"},
+		ListSort:        "runs",
+	})
+	if m.listSort != sortRuns {
+		t.Fatalf("expected configured sort, got %s", m.listSort)
+	}
+
+	view := testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a")
+	view.PoolName = "fast"
+	view.QueueDepth = 3
+	view.Loop.Tags = []string{"nightly", "docs"}
+
+	header := m.renderListHeader()
+	if header != "P NAME             POOL         TAGS             QUEUE" {
+		t.Fatalf("unexpected header %q", header)
+	}
+	row := m.renderListRow(view, 120)
+	if row != "  alpha            fast         nightly,docs     3" {
+		t.Fatalf("unexpected row %q", row)
+	}
+}
+
+func TestValidateListLayoutRejectsInvalidConfig(t *testing.T) {
+	if err := ValidateListLayout(nil, ""); err != nil {
+		t.Fatalf("expected defaults to validate, got %v", err)
+	}
+	if err := ValidateListLayout([]string{"status", "bogus"}, ""); err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+	if err := ValidateListLayout([]string{"runs", "RUNS"}, ""); err == nil {
+		t.Fatalf("expected duplicate column error")
+	}
+	if err := ValidateListLayout(nil, "alphabetical"); err == nil {
+		t.Fatalf("expected unknown sort error")
+	}
+}
@@ -0,0 +1,60 @@
+package looptui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// deletePreviewFiles caps the files listed in the delete confirm dialog.
+const deletePreviewFiles = 4
+
+// previewDelete builds the cascading delete plan shown in the confirm
+// dialog. It returns nil when the plan cannot be computed.
+func (m model) previewDelete(loopEntry *models.Loop) *loop.DeletePlan {
+	if m.db == nil || loopEntry == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	plan, err := loop.PlanDelete(ctx, m.db, m.dataDir, loopEntry, true)
+	if err != nil {
+		return nil
+	}
+	return plan
+}
+
+func renderDeletePlan(plan *loop.DeletePlan) []string {
+	if plan == nil {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Removes %d run(s) and %d queue item(s) with the record.", plan.Runs, plan.QueueItems)}
+	if len(plan.Files) == 0 {
+		return append(lines, "No logs, ledger, or run files on disk.")
+	}
+	lines = append(lines, fmt.Sprintf("Press c to also delete %d file(s), reclaiming %s:", len(plan.Files), formatDiskBytes(plan.Bytes)))
+	for i, file := range plan.Files {
+		if i == deletePreviewFiles {
+			lines = append(lines, fmt.Sprintf("  +%d more", len(plan.Files)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %-9s %s (%s)", file.Kind, file.Path, formatDiskBytes(file.Bytes)))
+	}
+	return lines
+}
+
+func formatDiskBytes(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,342 @@
+package looptui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// diffSplitMinWidth is the narrowest pane that renders side-by-side;
+	// below it the diff layer falls back to unified output.
+	diffSplitMinWidth = 120
+	// diffContextKeep is how many unchanged lines stay visible on each side
+	// of a change when unchanged context is collapsed.
+	diffContextKeep = 3
+)
+
+type diffRowKind int
+
+const (
+	diffRowMeta diffRowKind = iota
+	diffRowHunk
+	diffRowContext
+	diffRowAdd
+	diffRowDel
+	diffRowCollapsed
+)
+
+type diffRow struct {
+	Kind   diffRowKind
+	Old    int
+	New    int
+	Text   string
+	Hidden int
+	// NewError marks an added line in a run comparison that looks like an
+	// error and is absent from the older run.
+	NewError bool
+}
+
+// parseDiffRows extracts diff content from log lines. Inside a hunk the @@
+// header's line counts decide what belongs to it, so context lines and
+// removed lines that look like "---" headers are kept in place. Outside
+// hunks only +/- lines survive, matching the old diff layer filter.
+func parseDiffRows(lines []string) []diffRow {
+	rows := make([]diffRow, 0, len(lines))
+	oldNo, newNo := 0, 0
+	oldLeft, newLeft := 0, 0
+	for _, raw := range lines {
+		line := sanitizeLogLine(raw)
+		if ts, ok := parseTimestampPrefix(line); ok {
+			line = strings.TrimPrefix(line[len(ts):], " ")
+		}
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				rows = append(rows, diffRow{Kind: diffRowAdd, New: newNo, Text: line[1:]})
+				newNo++
+				newLeft--
+				continue
+			case strings.HasPrefix(line, "-"):
+				rows = append(rows, diffRow{Kind: diffRowDel, Old: oldNo, Text: line[1:]})
+				oldNo++
+				oldLeft--
+				continue
+			case strings.HasPrefix(line, " ") || line == "":
+				text := line
+				if text != "" {
+					text = text[1:]
+				}
+				rows = append(rows, diffRow{Kind: diffRowContext, Old: oldNo, New: newNo, Text: text})
+				oldNo++
+				newNo++
+				oldLeft--
+				newLeft--
+				continue
+			case strings.HasPrefix(line, `\`):
+				rows = append(rows, diffRow{Kind: diffRowMeta, Text: line})
+				continue
+			}
+			// Truncated hunk: fall through and treat the line as new input.
+			oldLeft, newLeft = 0, 0
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "@@"):
+			oldStart, oldCount, newStart, newCount, ok := parseHunkHeader(trimmed)
+			if ok {
+				oldNo, newNo = oldStart, newStart
+				oldLeft, newLeft = oldCount, newCount
+			}
+			rows = append(rows, diffRow{Kind: diffRowHunk, Text: trimmed})
+		case strings.HasPrefix(trimmed, "diff --git"),
+			strings.HasPrefix(trimmed, "index "),
+			strings.HasPrefix(trimmed, "+++"),
+			strings.HasPrefix(trimmed, "---"):
+			rows = append(rows, diffRow{Kind: diffRowMeta, Text: trimmed})
+		case strings.HasPrefix(trimmed, "+"):
+			rows = append(rows, diffRow{Kind: diffRowAdd, Text: trimmed[1:]})
+		case strings.HasPrefix(trimmed, "-"):
+			rows = append(rows, diffRow{Kind: diffRowDel, Text: trimmed[1:]})
+		}
+	}
+	return rows
+}
+
+// parseHunkHeader parses "@@ -a[,b] +c[,d] @@ ...".
+func parseHunkHeader(line string) (oldStart, oldCount, newStart, newCount int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, 0, false
+	}
+	oldStart, oldCount, ok = parseHunkRange(fields[1][1:])
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	newStart, newCount, ok = parseHunkRange(fields[2][1:])
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	return oldStart, oldCount, newStart, newCount, true
+}
+
+func parseHunkRange(value string) (int, int, bool) {
+	startText, countText, hasCount := strings.Cut(value, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	count := 1
+	if hasCount {
+		count, err = strconv.Atoi(countText)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// collapseDiffContext replaces long runs of unchanged lines with a single
+// placeholder row, keeping diffContextKeep lines next to each change.
+func collapseDiffContext(rows []diffRow) []diffRow {
+	out := make([]diffRow, 0, len(rows))
+	for i := 0; i < len(rows); {
+		if rows[i].Kind != diffRowContext {
+			out = append(out, rows[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(rows) && rows[j].Kind == diffRowContext {
+			j++
+		}
+		run := rows[i:j]
+		keepHead, keepTail := diffContextKeep, diffContextKeep
+		if i == 0 || rows[i-1].Kind == diffRowHunk || rows[i-1].Kind == diffRowMeta {
+			keepHead = 0
+		}
+		if j == len(rows) || rows[j].Kind == diffRowHunk || rows[j].Kind == diffRowMeta {
+			keepTail = 0
+		}
+		if hidden := len(run) - keepHead - keepTail; hidden > 1 {
+			out = append(out, run[:keepHead]...)
+			out = append(out, diffRow{Kind: diffRowCollapsed, Hidden: hidden})
+			out = append(out, run[len(run)-keepTail:]...)
+		} else {
+			out = append(out, run...)
+		}
+		i = j
+	}
+	return out
+}
+
+func diffLineNumber(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func diffRowColor(palette tuiPalette, kind diffRowKind) string {
+	switch kind {
+	case diffRowMeta:
+		return palette.Focus
+	case diffRowHunk:
+		return palette.Warning
+	case diffRowAdd:
+		return palette.Success
+	case diffRowDel:
+		return palette.Error
+	case diffRowCollapsed:
+		return palette.TextMuted
+	default:
+		return ""
+	}
+}
+
+func diffRowMarker(row diffRow) string {
+	if row.NewError {
+		return "!"
+	}
+	switch row.Kind {
+	case diffRowAdd:
+		return "+"
+	case diffRowDel:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+func collapsedLabel(hidden int) string {
+	return fmt.Sprintf("··· %d unchanged lines ···", hidden)
+}
+
+// renderUnifiedDiff renders rows with an old/new line number gutter and a
+// colorized +/- marker column.
+func renderUnifiedDiff(palette tuiPalette, rows []diffRow, width int) []string {
+	out := make([]string, 0, len(rows))
+	for _, row := range rows {
+		color := diffRowColor(palette, row.Kind)
+		switch row.Kind {
+		case diffRowMeta, diffRowHunk:
+			out = append(out, colorText(truncateLine(row.Text, width), color, row.Kind == diffRowMeta))
+			continue
+		case diffRowCollapsed:
+			out = append(out, colorText(truncateLine(collapsedLabel(row.Hidden), width), color, false))
+			continue
+		}
+		gutter := fmt.Sprintf("%4s %4s ", diffLineNumber(row.Old), diffLineNumber(row.New))
+		body := truncateLine(diffRowMarker(row)+" "+row.Text, maxInt(1, width-len(gutter)))
+		if row.NewError {
+			body = colorText(body, palette.Error, true)
+		} else if color != "" {
+			body = colorText(body, color, false)
+		}
+		out = append(out, colorText(gutter, palette.TextMuted, false)+body)
+	}
+	return out
+}
+
+// renderSideBySideDiff renders old content on the left and new content on
+// the right. Removed and added lines in the same change block are paired so
+// edits line up.
+func renderSideBySideDiff(palette tuiPalette, rows []diffRow, width int) []string {
+	half := maxInt(1, (width-3)/2)
+	separator := colorText(" │ ", palette.Border, false)
+	cell := func(row *diffRow, number int) string {
+		if row == nil {
+			return strings.Repeat(" ", half)
+		}
+		text := fmt.Sprintf("%4s %s %s", diffLineNumber(number), diffRowMarker(*row), row.Text)
+		text = fitDiffCell(text, half)
+		if row.NewError {
+			return colorText(text, palette.Error, true)
+		}
+		if color := diffRowColor(palette, row.Kind); color != "" {
+			return colorText(text, color, false)
+		}
+		return text
+	}
+
+	out := make([]string, 0, len(rows))
+	for i := 0; i < len(rows); {
+		row := rows[i]
+		switch row.Kind {
+		case diffRowMeta, diffRowHunk:
+			out = append(out, colorText(truncateLine(row.Text, width), diffRowColor(palette, row.Kind), row.Kind == diffRowMeta))
+			i++
+		case diffRowCollapsed:
+			out = append(out, colorText(truncateLine(collapsedLabel(row.Hidden), width), palette.TextMuted, false))
+			i++
+		case diffRowContext:
+			out = append(out, cell(&rows[i], row.Old)+separator+cell(&rows[i], row.New))
+			i++
+		default:
+			var dels, adds []*diffRow
+			for i < len(rows) && rows[i].Kind == diffRowDel {
+				dels = append(dels, &rows[i])
+				i++
+			}
+			for i < len(rows) && rows[i].Kind == diffRowAdd {
+				adds = append(adds, &rows[i])
+				i++
+			}
+			for k := 0; k < maxInt(len(dels), len(adds)); k++ {
+				var left, right *diffRow
+				leftNo, rightNo := 0, 0
+				if k < len(dels) {
+					left, leftNo = dels[k], dels[k].Old
+				}
+				if k < len(adds) {
+					right, rightNo = adds[k], adds[k].New
+				}
+				out = append(out, cell(left, leftNo)+separator+cell(right, rightNo))
+			}
+		}
+	}
+	return out
+}
+
+func fitDiffCell(text string, width int) string {
+	text = truncateLine(text, width)
+	if pad := width - lipgloss.Width(text); pad > 0 {
+		text += strings.Repeat(" ", pad)
+	}
+	return text
+}
+
+// renderDiffBlock is renderLogBlock for the diff layer. Windowing happens
+// after rendering because collapsing and pairing change the row count.
+func (m model) renderDiffBlock(display logDisplay, width, available, scroll int) []string {
+	rows := parseDiffRows(displayLogLines(display.Lines))
+	if len(rows) == 0 {
+		return []string{truncateLine("No lines matched layer="+m.logLayerLabel(), width)}
+	}
+	if m.diffCollapse {
+		rows = collapseDiffContext(rows)
+	}
+	var rendered []string
+	if m.diffSplit && width >= diffSplitMinWidth {
+		rendered = renderSideBySideDiff(m.palette, rows, width)
+	} else {
+		rendered = renderUnifiedDiff(m.palette, rows, width)
+	}
+	start, end, _ := logWindowBounds(len(rendered), available, scroll)
+	return rendered[start:end]
+}
+
+func (m model) diffModeLabel() string {
+	label := "unified"
+	if m.diffSplit {
+		label = "split"
+	}
+	if m.diffCollapse {
+		label += "+collapsed"
+	}
+	return label
+}
@@ -0,0 +1,128 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var sampleDiffLog = []string{
+	"running tests",
+	"diff --git a/main.go b/main.go",
+	"index 123..456 100644",
+	"--- a/main.go",
+	"+++ b/main.go",
+	"@@ -10,9 +10,9 @@ func main() {",
+	" one",
+	" two",
+	" three",
+	" four",
+	"-old call",
+	"--- not a header",
+	"+new call",
+	" five",
+	" six",
+	" seven",
+	"done",
+}
+
+func TestParseDiffRowsTracksHunkLineNumbers(t *testing.T) {
+	rows := parseDiffRows(sampleDiffLog)
+	var kinds []diffRowKind
+	for _, row := range rows {
+		kinds = append(kinds, row.Kind)
+	}
+	want := []diffRowKind{
+		diffRowMeta, diffRowMeta, diffRowMeta, diffRowMeta, diffRowHunk,
+		diffRowContext, diffRowContext, diffRowContext, diffRowContext,
+		diffRowDel, diffRowDel, diffRowAdd,
+		diffRowContext, diffRowContext, diffRowContext,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %d rows, got %d: %+v", len(want), len(kinds), rows)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("row %d: expected kind %d, got %d (%+v)", i, want[i], kinds[i], rows[i])
+		}
+	}
+	if rows[9].Old != 14 || rows[10].Text != "-- not a header" || rows[11].New != 14 {
+		t.Fatalf("unexpected change rows: %+v %+v %+v", rows[9], rows[10], rows[11])
+	}
+	if last := rows[len(rows)-1]; last.Old != 18 || last.New != 17 {
+		t.Fatalf("unexpected trailing context numbers: %+v", last)
+	}
+}
+
+func TestParseDiffRowsKeepsLooseChangeLines(t *testing.T) {
+	rows := parseDiffRows([]string{"```diff", "+added", "plain", "  -removed", "```"})
+	if len(rows) != 2 || rows[0].Kind != diffRowAdd || rows[1].Kind != diffRowDel || rows[1].Text != "removed" {
+		t.Fatalf("unexpected loose rows: %+v", rows)
+	}
+}
+
+func TestCollapseDiffContextKeepsNeighbours(t *testing.T) {
+	lines := []string{"@@ -1,17 +1,17 @@"}
+	for i := 0; i < 8; i++ {
+		lines = append(lines, " before")
+	}
+	lines = append(lines, "-old", "+new")
+	for i := 0; i < 8; i++ {
+		lines = append(lines, " after")
+	}
+	collapsed := collapseDiffContext(parseDiffRows(lines))
+
+	// Runs touching the hunk edges only keep the side next to the change.
+	var kinds []diffRowKind
+	for _, row := range collapsed {
+		kinds = append(kinds, row.Kind)
+	}
+	want := []diffRowKind{
+		diffRowHunk, diffRowCollapsed, diffRowContext, diffRowContext, diffRowContext,
+		diffRowDel, diffRowAdd,
+		diffRowContext, diffRowContext, diffRowContext, diffRowCollapsed,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), collapsed)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("row %d: expected kind %d, got %+v", i, want[i], collapsed[i])
+		}
+	}
+	if collapsed[1].Hidden != 5 || collapsed[10].Hidden != 5 {
+		t.Fatalf("expected 5 hidden lines per side, got %d and %d", collapsed[1].Hidden, collapsed[10].Hidden)
+	}
+}
+
+func TestRenderSideBySideDiffPairsChanges(t *testing.T) {
+	palette := resolvePalette("default")
+	rows := parseDiffRows(sampleDiffLog)
+	lines := renderSideBySideDiff(palette, rows, 120)
+	var paired string
+	for _, line := range lines {
+		plain := stripANSI(line)
+		if strings.Contains(plain, "old call") {
+			paired = plain
+		}
+	}
+	if !strings.Contains(paired, "new call") || !strings.Contains(paired, "│") {
+		t.Fatalf("expected old and new call on the same row, got %q", paired)
+	}
+}
+
+func TestDiffToggleKeysSwitchToDiffLayer(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	m.tab = tabLogs
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	if m.logLayer != logLayerDiff || !m.diffSplit {
+		t.Fatalf("expected split diff layer, got layer=%v split=%v", m.logLayer, m.diffSplit)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	if !m.diffCollapse || m.diffModeLabel() != "split+collapsed" {
+		t.Fatalf("expected collapsed split diff, got %q", m.diffModeLabel())
+	}
+}
@@ -0,0 +1,62 @@
+package looptui
+
+import (
+	"os"
+	"time"
+)
+
+// toggleFollow turns follow mode on or off. While it is on, every refresh
+// selects the loop whose log was written most recently.
+func (m *model) toggleFollow() {
+	m.follow = !m.follow
+	if !m.follow {
+		m.setStatus(statusInfo, "Follow off")
+		return
+	}
+	m.followMostRecentLog()
+	m.setStatus(statusInfo, "Follow on: selecting the most recently active loop")
+}
+
+// stopFollow turns follow mode off when the user picks a loop by hand, so
+// the next refresh does not snap the selection away again.
+func (m *model) stopFollow() {
+	if !m.follow {
+		return
+	}
+	m.follow = false
+	m.setStatus(statusInfo, "Follow off (manual selection)")
+}
+
+// followMostRecentLog selects the filtered loop whose log changed last and
+// reports whether the selection moved. Loops without a log are skipped.
+func (m *model) followMostRecentLog() bool {
+	best := -1
+	for i, view := range m.filtered {
+		if view.Loop == nil || view.LogModified.IsZero() {
+			continue
+		}
+		if best < 0 || view.LogModified.After(m.filtered[best].LogModified) {
+			best = i
+		}
+	}
+	if best < 0 || m.filtered[best].Loop.ID == m.selectedID {
+		return false
+	}
+	m.selectedIdx = best
+	m.selectedID = m.filtered[best].Loop.ID
+	m.logScroll = 0
+	return true
+}
+
+// logModTime returns when the log at path was last written, or the zero
+// time when it does not exist yet.
+func logModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
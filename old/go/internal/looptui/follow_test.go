@@ -0,0 +1,62 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestFollowModeSelectsMostRecentlyActiveLoop(t *testing.T) {
+	now := time.Now().UTC()
+	withLog := func(view loopView, modified time.Time) loopView {
+		view.LogModified = modified
+		return view
+	}
+	alpha := testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/repo/a")
+	beta := testLoopView("id-b", "idb", "beta", models.LoopStateRunning, "/repo/b")
+	gamma := testLoopView("id-c", "idc", "gamma", models.LoopStateStopped, "/repo/c")
+
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m = updateModel(t, m, refreshMsg{loops: []loopView{
+		withLog(alpha, now.Add(-time.Minute)),
+		withLog(beta, now.Add(-time.Second)),
+		gamma,
+	}})
+	if m.selectedID != "id-a" {
+		t.Fatalf("expected first loop selected before follow, got %q", m.selectedID)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if !m.follow || m.selectedID != "id-b" {
+		t.Fatalf("expected follow to jump to beta, got follow=%v selected=%q", m.follow, m.selectedID)
+	}
+	if !strings.Contains(m.renderHeader(), "follow:on") {
+		t.Fatalf("expected header to show follow mode, got %q", m.renderHeader())
+	}
+
+	// A later write to gamma's log moves the selection on the next refresh.
+	m = updateModel(t, m, refreshMsg{loops: []loopView{
+		withLog(alpha, now.Add(-time.Minute)),
+		withLog(beta, now.Add(-time.Second)),
+		withLog(gamma, now),
+	}})
+	if m.selectedID != "id-c" {
+		t.Fatalf("expected follow to chase gamma, got %q", m.selectedID)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if m.follow || m.selectedID != "id-b" {
+		t.Fatalf("expected manual selection to turn follow off, got follow=%v selected=%q", m.follow, m.selectedID)
+	}
+	m = updateModel(t, m, refreshMsg{loops: []loopView{
+		withLog(alpha, now.Add(time.Second)),
+		withLog(beta, now.Add(-time.Second)),
+		withLog(gamma, now),
+	}})
+	if m.selectedID != "id-b" {
+		t.Fatalf("expected selection to stay put with follow off, got %q", m.selectedID)
+	}
+}
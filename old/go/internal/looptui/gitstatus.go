@@ -0,0 +1,55 @@
+package looptui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/workspace"
+)
+
+// overviewDirtyFiles caps how many changed files the overview lists.
+const overviewDirtyFiles = 5
+
+// loadLoopGitStatus returns the git status of the selected loop's repo. The
+// collector only runs git once its cached status is older than its interval.
+func loadLoopGitStatus(collector *workspace.GitStatusCollector, views []loopView, loopID string) *models.GitInfo {
+	if collector == nil {
+		return nil
+	}
+	for _, view := range views {
+		if view.Loop == nil || view.Loop.ID != loopID || strings.TrimSpace(view.Loop.RepoPath) == "" {
+			continue
+		}
+		info, err := collector.Status(view.Loop.RepoPath)
+		if err != nil {
+			return nil
+		}
+		return info
+	}
+	return nil
+}
+
+// renderGitLines renders the overview "Repo:" section for loopID, or
+// nothing when the loop's directory is not a git repository.
+func (m model) renderGitLines(loopID string, width int) []string {
+	info := m.repoGit
+	if m.repoGitLoopID != loopID || info == nil || !info.IsRepo {
+		return nil
+	}
+	lines := []string{"", lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Repo:")}
+	lines = append(lines, truncateLine("  "+workspace.FormatGitSummary(info), width))
+	if info.Upstream != "" {
+		lines = append(lines, truncateLine(fmt.Sprintf("  upstream=%s ahead=%d behind=%d", info.Upstream, info.Ahead, info.Behind), width))
+	}
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Warning))
+	for i, change := range info.DirtyFiles {
+		if i == overviewDirtyFiles {
+			lines = append(lines, truncateLine(fmt.Sprintf("  ... %d more", info.DirtyCount-overviewDirtyFiles), width))
+			break
+		}
+		lines = append(lines, warn.Render(truncateLine(fmt.Sprintf("  %s %s", change.Status, change.Path), width)))
+	}
+	return lines
+}
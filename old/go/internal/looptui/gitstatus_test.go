@@ -0,0 +1,31 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestRefreshRendersRepoGitStatus(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.width = 140
+	m.height = 40
+	views := []loopView{testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a")}
+
+	m = updateModel(t, m, refreshMsg{loops: views, selectedID: "id-a", git: &models.GitInfo{
+		IsRepo: true, Branch: "main", Upstream: "origin/main", Ahead: 2, IsDirty: true, DirtyCount: 1,
+		DirtyFiles: []models.GitFileChange{{Status: " M", Path: "loop.go"}},
+	}})
+
+	overview := m.renderOverviewPane(m.filtered[0], 100, 40)
+	for _, want := range []string{"Repo:", "main ↑2 dirty=1", "upstream=origin/main ahead=2 behind=0", " M loop.go"} {
+		if !strings.Contains(overview, want) {
+			t.Fatalf("expected %q in overview, got:\n%s", want, overview)
+		}
+	}
+	if lines := m.renderGitLines("id-b", 80); lines != nil {
+		t.Fatalf("expected no repo lines for another loop, got %v", lines)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/models"
 )
 
@@ -234,6 +235,9 @@ func lineMatchesLayer(harness models.Harness, line string, layer logLayer) bool
 	if layer == logLayerRaw {
 		return true
 	}
+	if rec, ok := loop.ParseLogRecord(line); ok {
+		return recordMatchesLayer(harness, rec, layer)
+	}
 	trimmed := strings.TrimSpace(sanitizeLogLine(line))
 	if trimmed == "" {
 		return false
@@ -253,6 +257,44 @@ func lineMatchesLayer(harness models.Harness, line string, layer logLayer) bool
 	}
 }
 
+// recordMatchesLayer filters JSONL log records on their stream and harness
+// event fields, falling back to text heuristics only where those are silent.
+func recordMatchesLayer(harness models.Harness, rec loop.LogRecord, layer logLayer) bool {
+	trimmed := strings.TrimSpace(sanitizeLogLine(rec.Text))
+	if trimmed == "" {
+		return false
+	}
+	event := strings.ToLower(rec.Event)
+
+	switch layer {
+	case logLayerDiff:
+		return rec.Stream != loop.LogStreamLoop && looksLikeDiffLine(trimmed)
+	case logLayerErrors:
+		if rec.Stream == loop.LogStreamStderr || strings.Contains(event, "error") || strings.Contains(event, "fail") {
+			return true
+		}
+		return rec.Stream == loop.LogStreamLoop && looksLikeErrorLine(trimmed)
+	case logLayerTools:
+		if event != "" {
+			return strings.Contains(event, "tool") || strings.Contains(event, "command")
+		}
+		return looksLikeToolLine(harness, trimmed)
+	case logLayerEvents:
+		return rec.Stream == loop.LogStreamLoop || event != ""
+	default:
+		return true
+	}
+}
+
+// displayLogLines renders JSONL log records as plain text lines.
+func displayLogLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = loop.DisplayLogLine(line)
+	}
+	return out
+}
+
 func looksLikeDiffLine(line string) bool {
 	switch {
 	case strings.HasPrefix(line, "diff --git"),
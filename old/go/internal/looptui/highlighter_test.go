@@ -85,3 +85,23 @@ func TestLineMatchesLayer(t *testing.T) {
 		t.Fatalf("expected plain line to not match errors layer")
 	}
 }
+
+func TestLineMatchesLayerUsesStructuredRecords(t *testing.T) {
+	stderr := `{"ts":"2026-02-08T10:00:00Z","stream":"stderr","iteration":2,"text":"warning: retrying"}`
+	tool := `{"ts":"2026-02-08T10:00:00Z","stream":"stdout","event":"item.started:command_execution","text":"{}"}`
+	plain := `{"ts":"2026-02-08T10:00:00Z","stream":"stdout","text":"all tests passed, no error"}`
+	runner := `{"ts":"2026-02-08T10:00:00Z","stream":"loop","text":"run r1 start"}`
+
+	if !lineMatchesLayer(models.HarnessCodex, stderr, logLayerErrors) {
+		t.Fatalf("expected stderr record in errors layer")
+	}
+	if !lineMatchesLayer(models.HarnessCodex, tool, logLayerTools) {
+		t.Fatalf("expected command event in tools layer")
+	}
+	if lineMatchesLayer(models.HarnessCodex, plain, logLayerErrors) {
+		t.Fatalf("expected stdout text mentioning error to stay out of errors layer")
+	}
+	if !lineMatchesLayer(models.HarnessCodex, runner, logLayerEvents) || lineMatchesLayer(models.HarnessCodex, runner, logLayerTools) {
+		t.Fatalf("expected runner record in events layer only")
+	}
+}
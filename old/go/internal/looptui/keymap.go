@@ -0,0 +1,211 @@
+package looptui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyAction names a remappable loop TUI action. The names are the keys
+// accepted in the config file's keybindings section.
+type keyAction string
+
+const (
+	keyQuit           keyAction = "quit"
+	keyHelp           keyAction = "help"
+	keyFilter         keyAction = "filter"
+	keyNextTab        keyAction = "next_tab"
+	keyPrevTab        keyAction = "prev_tab"
+	keyTabOverview    keyAction = "tab_overview"
+	keyTabLogs        keyAction = "tab_logs"
+	keyTabRuns        keyAction = "tab_runs"
+	keyTabMultiLogs   keyAction = "tab_multi_logs"
+	keyTabQueue       keyAction = "tab_queue"
+	keyTheme          keyAction = "theme"
+	keyZen            keyAction = "zen"
+	keyExpandedLogs   keyAction = "expanded_logs"
+	keyNew            keyAction = "new"
+	keyStop           keyAction = "stop"
+	keyKill           keyAction = "kill"
+	keyDelete         keyAction = "delete"
+	keyResume         keyAction = "resume"
+	keyMessage        keyAction = "message"
+	keySwitchProfile  keyAction = "switch_profile"
+	keyManageProfiles keyAction = "manage_profiles"
+	keyManagePools    keyAction = "manage_pools"
+	keyPin            keyAction = "pin"
+	keyClearPins      keyAction = "clear_pins"
+	keyCompareRuns    keyAction = "compare_runs"
+	keyQueueAdd       keyAction = "queue_add"
+	keyQueueRemove    keyAction = "queue_remove"
+	keyQueueMoveUp    keyAction = "queue_move_up"
+	keyQueueMoveDown  keyAction = "queue_move_down"
+	keyLogTimestamps  keyAction = "log_timestamps"
+	keyJumpToTime     keyAction = "jump_to_time"
+	keySort           keyAction = "sort"
+	keyCheckpointDiff keyAction = "checkpoint_diff"
+	keyFollow         keyAction = "follow"
+)
+
+// defaultKeyBindings lists the built-in keys per action. The first key is
+// canonical: it is what the mode handlers switch on, so a remapped key is
+// translated back to it before dispatch.
+var defaultKeyBindings = map[keyAction][]string{
+	keyQuit:           {"q"},
+	keyHelp:           {"?"},
+	keyFilter:         {"/"},
+	keyNextTab:        {"]"},
+	keyPrevTab:        {"["},
+	keyTabOverview:    {"1"},
+	keyTabLogs:        {"2"},
+	keyTabRuns:        {"3"},
+	keyTabMultiLogs:   {"4"},
+	keyTabQueue:       {"5"},
+	keyTheme:          {"t"},
+	keyZen:            {"z"},
+	keyExpandedLogs:   {"l"},
+	keyNew:            {"n"},
+	keyStop:           {"S"},
+	keyKill:           {"K"},
+	keyDelete:         {"D"},
+	keyResume:         {"r"},
+	keyMessage:        {"M"},
+	keySwitchProfile:  {"P"},
+	keyManageProfiles: {"p"},
+	keyManagePools:    {"o"},
+	keyPin:            {"space"},
+	keyClearPins:      {"c"},
+	keyCompareRuns:    {"="},
+	keyQueueAdd:       {"a"},
+	keyQueueRemove:    {"X"},
+	keyQueueMoveUp:    {"<"},
+	keyQueueMoveDown:  {">"},
+	keyLogTimestamps:  {"T"},
+	keyJumpToTime:     {"g"},
+	keySort:           {"O"},
+	keyCheckpointDiff: {"G"},
+	keyFollow:         {"F"},
+}
+
+// reservedKeys are handled directly by the main and expanded-log views and
+// cannot be bound to an action.
+var reservedKeys = map[string]struct{}{
+	"ctrl+c": {}, "esc": {}, "enter": {}, "tab": {}, "shift+tab": {},
+	"j": {}, "k": {}, "up": {}, "down": {},
+	"pgup": {}, "pgdown": {}, "ctrl+u": {}, "ctrl+d": {}, "u": {}, "d": {},
+	"home": {}, "end": {},
+	"m": {}, "v": {}, "x": {}, "|": {}, "C": {}, ",": {}, ".": {},
+}
+
+// keyMap holds the active bindings.
+type keyMap struct {
+	keys  map[keyAction][]string
+	byKey map[string]keyAction
+}
+
+func defaultKeyMap() keyMap {
+	km, _ := newKeyMap(nil)
+	return km
+}
+
+// newKeyMap applies overrides (action -> keys) on top of the defaults. An
+// override replaces all default keys for its action. Unknown actions,
+// reserved keys, and keys bound to two actions are rejected.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	km := keyMap{
+		keys:  make(map[keyAction][]string, len(defaultKeyBindings)),
+		byKey: make(map[string]keyAction, len(defaultKeyBindings)),
+	}
+	for action, keys := range defaultKeyBindings {
+		km.keys[action] = keys
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action := keyAction(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := defaultKeyBindings[action]; !ok {
+			return keyMap{}, fmt.Errorf("keybindings: unknown action %q (valid: %s)", name, strings.Join(keyActionNames(), ", "))
+		}
+		keys := make([]string, 0, len(overrides[name]))
+		for _, key := range overrides[name] {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			if _, reserved := reservedKeys[key]; reserved {
+				return keyMap{}, fmt.Errorf("keybindings: %s: key %q is reserved", action, key)
+			}
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			return keyMap{}, fmt.Errorf("keybindings: %s: at least one key is required", action)
+		}
+		km.keys[action] = keys
+	}
+
+	for _, action := range sortedKeyActions() {
+		for _, key := range km.keys[action] {
+			if other, exists := km.byKey[key]; exists {
+				return keyMap{}, fmt.Errorf("keybindings: key %q is bound to both %s and %s", key, other, action)
+			}
+			km.byKey[key] = action
+		}
+	}
+	return km, nil
+}
+
+// ValidateKeybindings reports whether the keybindings section is usable.
+func ValidateKeybindings(overrides map[string][]string) error {
+	_, err := newKeyMap(overrides)
+	return err
+}
+
+// resolve translates a pressed key to the canonical key the view handlers
+// expect. Default keys whose action was remapped elsewhere resolve to "".
+func (km keyMap) resolve(key string) string {
+	if km.byKey == nil {
+		return key
+	}
+	if action, ok := km.byKey[key]; ok {
+		return defaultKeyBindings[action][0]
+	}
+	for _, keys := range defaultKeyBindings {
+		for _, k := range keys {
+			if k == key {
+				return ""
+			}
+		}
+	}
+	return key
+}
+
+// label renders an action's active keys for help text.
+func (km keyMap) label(action keyAction) string {
+	keys := km.keys[action]
+	if len(keys) == 0 {
+		keys = defaultKeyBindings[action]
+	}
+	return strings.Join(keys, "/")
+}
+
+func sortedKeyActions() []keyAction {
+	actions := make([]keyAction, 0, len(defaultKeyBindings))
+	for action := range defaultKeyBindings {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
+
+func keyActionNames() []string {
+	actions := sortedKeyActions()
+	names := make([]string, len(actions))
+	for i, action := range actions {
+		names[i] = string(action)
+	}
+	return names
+}
@@ -0,0 +1,66 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestKeybindingsRemapActions(t *testing.T) {
+	m := newModel(nil, Config{
+		RefreshInterval: time.Second,
+		LogLines:        8,
+		Theme:           "default",
+		Keybindings: map[string][]string{
+			"stop":     {"s"},
+			"tab_logs": {"L"},
+		},
+	})
+	m.loops = []loopView{
+		testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if m.tab != tabOverview {
+		t.Fatalf("expected default tab key to be unbound, got tab %v", m.tab)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if m.tab != tabLogs {
+		t.Fatalf("expected remapped key to switch to logs tab, got %v", m.tab)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	if m.mode != modeMain {
+		t.Fatalf("expected default stop key to be unbound, got mode %v", m.mode)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.mode != modeConfirm || m.confirm == nil || m.confirm.Action != actionStop {
+		t.Fatalf("expected remapped stop key to open stop confirm, got mode %v", m.mode)
+	}
+
+	help := m.renderHelpDialog(120)
+	if !strings.Contains(help, "s stop") {
+		t.Fatalf("expected help to show active stop binding, got:\n%s", help)
+	}
+}
+
+func TestValidateKeybindingsRejectsInvalidConfig(t *testing.T) {
+	cases := map[string]map[string][]string{
+		"unknown action": {"explode": {"x"}},
+		"reserved key":   {"stop": {"j"}},
+		"duplicate key":  {"stop": {"K"}},
+		"empty keys":     {"kill": {" "}},
+	}
+	for name, bindings := range cases {
+		if err := ValidateKeybindings(bindings); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if err := ValidateKeybindings(map[string][]string{"stop": {"s"}, "kill": {"ctrl+k"}}); err != nil {
+		t.Fatalf("expected valid bindings, got %v", err)
+	}
+}
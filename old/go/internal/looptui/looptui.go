@@ -24,6 +24,7 @@ import (
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/names"
 	"github.com/tOgg1/forge/internal/procutil"
+	"github.com/tOgg1/forge/internal/workspace"
 )
 
 const (
@@ -58,6 +59,21 @@ type Config struct {
 	DefaultPrompt    string
 	DefaultPromptMsg string
 	ConfigFile       string
+
+	// TemplateDir holds saved loop templates offered by the wizard.
+	TemplateDir string
+
+	// Keybindings remaps actions to keys (action name -> keys).
+	Keybindings map[string][]string
+
+	// ListColumns picks the loop list columns in order; empty uses the
+	// defaults. ListSort is the initial list order (default "created").
+	ListColumns []string
+	ListSort    string
+
+	// GitStatusInterval is how long the overview reuses a loop repo's git
+	// status before running git again (default 30s).
+	GitStatusInterval time.Duration
 }
 
 // Run starts the loop TUI.
@@ -68,6 +84,12 @@ func Run(database *db.DB, cfg Config) error {
 	if cfg.LogLines <= 0 {
 		cfg.LogLines = defaultLogLines
 	}
+	if err := ValidateKeybindings(cfg.Keybindings); err != nil {
+		return err
+	}
+	if err := ValidateListLayout(cfg.ListColumns, cfg.ListSort); err != nil {
+		return err
+	}
 
 	model := newModel(database, cfg)
 	program := tea.NewProgram(model, tea.WithAltScreen())
@@ -84,6 +106,10 @@ const (
 	modeConfirm
 	modeWizard
 	modeHelp
+	modeMessage
+	modeSwitchProfile
+	modeManage
+	modeQueueAdd
 )
 
 type statusKind int
@@ -110,6 +136,15 @@ const (
 	actionDelete
 	actionResume
 	actionCreate
+	actionMessage
+	actionSwitchProfile
+	actionSaveProfile
+	actionDeleteProfile
+	actionSavePool
+	actionDeletePool
+	actionQueueAdd
+	actionQueueRemove
+	actionQueueMove
 )
 
 type mainTab int
@@ -119,9 +154,10 @@ const (
 	tabLogs
 	tabRuns
 	tabMultiLogs
+	tabQueue
 )
 
-var tabOrder = []mainTab{tabOverview, tabLogs, tabRuns, tabMultiLogs}
+var tabOrder = []mainTab{tabOverview, tabLogs, tabRuns, tabMultiLogs, tabQueue}
 
 type logSource int
 
@@ -149,6 +185,8 @@ type loopView struct {
 	ProfileHarness models.Harness
 	ProfileAuth    string
 	PoolName       string
+	// LogModified is when the loop's log was last written (zero if none).
+	LogModified time.Time
 }
 
 type logTailView struct {
@@ -162,12 +200,15 @@ type logDisplay struct {
 	Lines   []string
 	Message string
 	Harness models.Harness
+	// BaseTime stamps leading lines that carry no timestamp (the run start).
+	BaseTime time.Time
 }
 
 type confirmState struct {
 	Action actionType
 	LoopID string
 	Prompt string
+	Plan   *loop.DeletePlan // cascading delete preview; nil when unavailable
 }
 
 type wizardValues struct {
@@ -184,11 +225,37 @@ type wizardValues struct {
 	Tags          string
 }
 
+type messageState struct {
+	LoopID string
+	Field  int
+	Text   string
+	At     string
+	Error  string
+}
+
+type switchProfileState struct {
+	LoopID  string
+	Field   int
+	Profile string
+	Queue   string
+	Force   string
+	Error   string
+}
+
 type wizardState struct {
 	Step   int
 	Field  int
 	Values wizardValues
 	Error  string
+
+	// Templates are the saved loop templates; TemplateIdx is the applied
+	// one (-1 = defaults). SaveAs is the review-step template name.
+	Templates   []loop.Template
+	TemplateIdx int
+	SaveAs      string
+
+	// CloneOf names the loop whose configuration prefilled the wizard.
+	CloneOf string
 }
 
 type model struct {
@@ -200,27 +267,47 @@ type model struct {
 	defaultPrompt    string
 	defaultPromptMsg string
 	configFile       string
+	templateDir      string
 	palette          tuiPalette
+	keys             keyMap
+	listColumns      []listColumn
+	listSort         listSortKey
 
 	width  int
 	height int
 
-	loops       []loopView
-	filtered    []loopView
-	selectedID  string
-	selectedIdx int
-	selectedLog logTailView
-	runHistory  []runView
-	selectedRun int
-	tab         mainTab
-	logSource   logSource
-	logLayer    logLayer
-	logScroll   int
-	focusRight  bool
-	pinned      map[string]struct{}
-	layoutIdx   int
-	multiPage   int
-	multiLogs   map[string]logTailView
+	loops        []loopView
+	filtered     []loopView
+	selectedID   string
+	selectedIdx  int
+	selectedLog  logTailView
+	runHistory   []runView
+	selectedRun  int
+	compareRunID string
+	tab          mainTab
+	logSource    logSource
+	logLayer     logLayer
+	logScroll    int
+	logTimeMode  logTimeMode
+	diffSplit    bool
+	diffCollapse bool
+	focusRight   bool
+	pinned       map[string]struct{}
+	layoutIdx    int
+	multiPage    int
+	multiLogs    map[string]logTailView
+	resources    map[string]resourceHistory
+
+	watches       []loop.WatchResult
+	watchesLoopID string
+
+	gitStatus     *workspace.GitStatusCollector
+	repoGit       *models.GitInfo
+	repoGitLoopID string
+
+	queueItems    []*models.LoopQueueItem
+	selectedQueue int
+	ledger        *loop.LedgerSummary
 
 	mode        uiMode
 	helpReturn  uiMode
@@ -229,6 +316,22 @@ type model struct {
 	filterFocus filterFocus
 	confirm     *confirmState
 	wizard      wizardState
+	message     messageState
+	switchProf  switchProfileState
+	manage      manageState
+	queueAdd    queueAddState
+	jump        *jumpState
+
+	// filterOutput matches the filter text against run output through the
+	// search index instead of against loop names and paths.
+	filterOutput  bool
+	outputMatches map[string]string // loop ID -> best matching snippet
+
+	// checkpoint holds the Runs tab's diff against a run's git checkpoint.
+	checkpoint checkpointDiffState
+
+	// follow keeps the loop whose log was written last selected.
+	follow bool
 
 	err           error
 	statusText    string
@@ -236,6 +339,9 @@ type model struct {
 	statusExpires time.Time
 	actionBusy    bool
 	quitting      bool
+
+	eventCursor string
+	eventsSince time.Time
 }
 
 type refreshMsg struct {
@@ -244,7 +350,17 @@ type refreshMsg struct {
 	selected   logTailView
 	runs       []runView
 	multiLogs  map[string]logTailView
-	err        error
+	queue      []*models.LoopQueueItem
+	ledger     *loop.LedgerSummary
+	events     loopEventBatch
+	usage      map[string]resourceSample
+	sampledAt  time.Time
+	watches    []loop.WatchResult
+	git        *models.GitInfo
+	// outputQuery is the run output search outputMatches answers.
+	outputQuery   string
+	outputMatches map[string]string
+	err           error
 }
 
 type tickMsg struct{}
@@ -253,7 +369,15 @@ type actionRequest struct {
 	Kind        actionType
 	LoopID      string
 	ForceDelete bool
+	Cascade     bool
 	Wizard      wizardValues
+	Message     string
+	NotBefore   *time.Time
+	Switch      switchProfileState
+	Manage      manageForm
+	QueueItemID string
+	QueueDelta  int
+	QueueKind   string
 }
 
 type actionResultMsg struct {
@@ -277,6 +401,7 @@ func newModel(database *db.DB, cfg Config) model {
 		defaultPrompt:    cfg.DefaultPrompt,
 		defaultPromptMsg: cfg.DefaultPromptMsg,
 		configFile:       cfg.ConfigFile,
+		templateDir:      cfg.TemplateDir,
 		palette:          palette,
 		mode:             modeMain,
 		filterState:      "all",
@@ -288,8 +413,25 @@ func newModel(database *db.DB, cfg Config) model {
 		layoutIdx:        layoutIndexFor(2, 2),
 		multiPage:        0,
 		multiLogs:        make(map[string]logTailView),
+		resources:        make(map[string]resourceHistory),
+		gitStatus:        workspace.NewGitStatusCollector(cfg.GitStatusInterval),
+		eventsSince:      time.Now().UTC(),
 	}
 	m.wizard = newWizardState(cfg.DefaultInterval, cfg.DefaultPrompt, cfg.DefaultPromptMsg)
+	if keys, err := newKeyMap(cfg.Keybindings); err == nil {
+		m.keys = keys
+	} else {
+		m.keys = defaultKeyMap()
+	}
+	if columns, err := parseListColumns(cfg.ListColumns); err == nil {
+		m.listColumns = columns
+	} else {
+		m.listColumns = defaultListColumns
+	}
+	m.listSort, _ = parseListSort(cfg.ListSort)
+	if m.listSort == "" {
+		m.listSort = sortCreated
+	}
 	return m
 }
 
@@ -315,9 +457,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		if msg.err == nil {
 			m.loops = msg.loops
+			m.resources = recordResourceSamples(m.resources, msg.usage, msg.sampledAt)
+			if m.filterOutput && msg.outputQuery == strings.TrimSpace(m.filterText) {
+				m.outputMatches = msg.outputMatches
+			}
 			oldSelectedID := m.selectedID
 			oldSelectedIdx := m.selectedIdx
 			m.applyFilters(oldSelectedID, oldSelectedIdx)
+			if m.follow {
+				m.followMostRecentLog()
+			}
+			m.notifyLoopEvents(msg.events)
 			if m.selectedID == msg.selectedID {
 				m.selectedLog = msg.selected
 			} else if m.selectedID != "" {
@@ -337,8 +487,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.multiLogs = make(map[string]logTailView)
 			}
+			m.ledger = msg.ledger
+			m.watches = msg.watches
+			m.watchesLoopID = msg.selectedID
+			m.repoGit = msg.git
+			m.repoGitLoopID = msg.selectedID
+			m.queueItems = msg.queue
+			if len(m.queueItems) == 0 {
+				m.selectedQueue = 0
+			} else if m.selectedQueue >= len(m.queueItems) {
+				m.selectedQueue = len(m.queueItems) - 1
+			}
 		}
 		return m, nil
+	case manageLoadedMsg:
+		return m.applyManageLoaded(msg), nil
+	case checkpointDiffMsg:
+		m.applyCheckpointDiff(msg)
+		return m, nil
 	case actionResultMsg:
 		m.actionBusy = false
 		if msg.Err != nil {
@@ -347,9 +513,37 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.mode = modeWizard
 				m.wizard.Error = msg.Err.Error()
 			}
+			if msg.Kind == actionMessage {
+				m.mode = modeMessage
+				m.message.Error = msg.Err.Error()
+			}
+			if msg.Kind == actionSwitchProfile {
+				m.mode = modeSwitchProfile
+				m.switchProf.Error = msg.Err.Error()
+			}
+			if msg.Kind == actionQueueAdd {
+				m.mode = modeQueueAdd
+				m.queueAdd.Error = msg.Err.Error()
+			}
+			if msg.Kind == actionQueueMove || msg.Kind == actionQueueRemove {
+				return m, m.fetchCmd()
+			}
+			if isManageAction(msg.Kind) {
+				m.mode = modeManage
+				m.manage.Error = msg.Err.Error()
+			}
 			return m, nil
 		}
 
+		if isManageAction(msg.Kind) {
+			m.manage.Form = nil
+			m.manage.Error = ""
+			if msg.Message != "" {
+				m.setStatus(statusOK, msg.Message)
+			}
+			return m, tea.Batch(m.fetchCmd(), m.manageLoadCmd())
+		}
+
 		if msg.Kind == actionCreate {
 			m.mode = modeMain
 			m.wizard.Error = ""
@@ -379,6 +573,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateWizardMode(msg)
 		case modeHelp:
 			return m.updateHelpMode(msg)
+		case modeMessage:
+			return m.updateMessageMode(msg)
+		case modeSwitchProfile:
+			return m.updateSwitchProfileMode(msg)
+		case modeManage:
+			return m.updateManageMode(msg)
+		case modeQueueAdd:
+			return m.updateQueueAddMode(msg)
 		default:
 			return m.updateMainMode(msg)
 		}
@@ -397,14 +599,7 @@ func (m model) View() string {
 
 	header := m.renderHeader()
 	tabBar := m.renderTabBar(width)
-	overhead := 4
-	if m.mode == modeFilter || m.mode == modeConfirm || m.mode == modeWizard || m.mode == modeHelp {
-		overhead += 3
-	}
-	if m.statusText != "" {
-		overhead++
-	}
-	paneHeight := maxInt(10, height-overhead)
+	paneHeight := m.paneHeight(height)
 
 	var body string
 	if m.focusRight {
@@ -429,6 +624,21 @@ func (m model) View() string {
 	if m.mode == modeHelp {
 		parts = append(parts, m.renderHelpDialog(width))
 	}
+	if m.mode == modeMessage {
+		parts = append(parts, m.renderMessageDialog(width))
+	}
+	if m.mode == modeSwitchProfile {
+		parts = append(parts, m.renderSwitchProfileDialog(width))
+	}
+	if m.mode == modeManage {
+		parts = append(parts, m.renderManageDialog(width))
+	}
+	if m.mode == modeQueueAdd {
+		parts = append(parts, m.renderQueueAddDialog(width))
+	}
+	if m.mode == modeExpandedLogs && m.jump != nil {
+		parts = append(parts, m.renderJumpDialog(width))
+	}
 	if m.statusText != "" {
 		parts = append(parts, m.renderStatusLine(width))
 	}
@@ -441,8 +651,24 @@ func (m model) View() string {
 	return strings.Join(parts, "\n")
 }
 
+// paneHeight is the height left for the panes once the header, tab bar,
+// any dialog and the status line are drawn.
+func (m model) paneHeight(height int) int {
+	overhead := 4
+	if m.mode == modeFilter || m.mode == modeConfirm || m.mode == modeWizard || m.mode == modeHelp || m.mode == modeMessage || m.mode == modeSwitchProfile || m.mode == modeManage || m.mode == modeQueueAdd {
+		overhead += 3
+	}
+	if m.mode == modeExpandedLogs && m.jump != nil {
+		overhead += 3
+	}
+	if m.statusText != "" {
+		overhead++
+	}
+	return maxInt(10, height-overhead)
+}
+
 func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	switch m.keys.resolve(msg.String()) {
 	case "q":
 		m.quitting = true
 		return m, tea.Quit
@@ -462,6 +688,9 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "4":
 		m.setTab(tabMultiLogs)
 		return m, m.fetchCmd()
+	case "5":
+		m.setTab(tabQueue)
+		return m, m.fetchCmd()
 	case "]":
 		m.cycleTab(1)
 		return m, m.fetchCmd()
@@ -491,11 +720,16 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterFocus = filterFocusText
 		return m, nil
 	case "j", "down":
+		m.stopFollow()
 		m.moveSelection(1)
 		return m, m.fetchCmd()
 	case "k", "up":
+		m.stopFollow()
 		m.moveSelection(-1)
 		return m, m.fetchCmd()
+	case "F":
+		m.toggleFollow()
+		return m, m.fetchCmd()
 	case "pgup", "ctrl+u", "u":
 		if m.tab == tabLogs || m.tab == tabRuns {
 			m.scrollLogs(m.logScrollPageSize())
@@ -536,6 +770,19 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "c":
 		m.clearPinned()
 		return m, m.fetchCmd()
+	case "O":
+		m.cycleListSort()
+		return m, m.fetchCmd()
+	case "=":
+		if m.tab == tabRuns {
+			m.toggleRunCompare()
+		}
+		return m, nil
+	case "G":
+		if m.tab == tabRuns {
+			return m, m.toggleCheckpointDiff()
+		}
+		return m, nil
 	case "m":
 		if m.tab == tabMultiLogs {
 			m.cycleLayout(1)
@@ -555,6 +802,18 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, nil
+	case "T":
+		m.cycleLogTimeMode()
+		return m, nil
+	case "|", "C":
+		if m.tab == tabLogs || m.tab == tabRuns || m.tab == tabMultiLogs {
+			m.toggleDiffOption(msg.String())
+			return m, nil
+		}
+		if msg.String() == "C" {
+			m.openCloneWizard()
+		}
+		return m, nil
 	case ",":
 		if m.tab == tabLogs || m.tab == tabRuns {
 			m.moveRunSelection(-1)
@@ -564,6 +823,9 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.moveMultiPage(-1)
 			return m, m.fetchCmd()
 		}
+		if m.tab == tabQueue {
+			m.moveQueueSelection(-1)
+		}
 		return m, nil
 	case ".":
 		if m.tab == tabLogs || m.tab == tabRuns {
@@ -574,7 +836,47 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.moveMultiPage(1)
 			return m, m.fetchCmd()
 		}
+		if m.tab == tabQueue {
+			m.moveQueueSelection(1)
+		}
+		return m, nil
+	case "<":
+		if m.tab == tabQueue {
+			return m.queueMoveCmd(-1)
+		}
+		return m, nil
+	case ">":
+		if m.tab == tabQueue {
+			return m.queueMoveCmd(1)
+		}
 		return m, nil
+	case "a":
+		if m.tab != tabQueue {
+			return m, nil
+		}
+		view, ok := m.selectedView()
+		if !ok {
+			m.setStatus(statusInfo, "No loop selected")
+			return m, nil
+		}
+		m.mode = modeQueueAdd
+		m.queueAdd = queueAddState{LoopID: view.Loop.ID, Kind: queueAddPrompt}
+		return m, nil
+	case "X":
+		if m.tab != tabQueue {
+			return m, nil
+		}
+		view, ok := m.selectedView()
+		if !ok {
+			m.setStatus(statusInfo, "No loop selected")
+			return m, nil
+		}
+		item, ok := m.selectedQueueItem()
+		if !ok {
+			m.setStatus(statusInfo, "Queue is empty")
+			return m, nil
+		}
+		return m.runAction(actionRequest{Kind: actionQueueRemove, LoopID: view.Loop.ID, QueueItemID: item.ID})
 	case "l":
 		if _, ok := m.selectedView(); !ok {
 			m.setStatus(statusInfo, "No loop selected")
@@ -582,9 +884,32 @@ func (m model) updateMainMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = modeExpandedLogs
 		return m, m.fetchCmd()
+	case "M":
+		view, ok := m.selectedView()
+		if !ok {
+			m.setStatus(statusInfo, "No loop selected")
+			return m, nil
+		}
+		m.mode = modeMessage
+		m.message = messageState{LoopID: view.Loop.ID}
+		return m, nil
+	case "P":
+		view, ok := m.selectedView()
+		if !ok {
+			m.setStatus(statusInfo, "No loop selected")
+			return m, nil
+		}
+		m.mode = modeSwitchProfile
+		m.switchProf = switchProfileState{LoopID: view.Loop.ID, Queue: string(loop.SwitchQueueMigrate)}
+		return m, nil
+	case "p":
+		return m.openManage(manageProfiles)
+	case "o":
+		return m.openManage(managePools)
 	case "n":
 		m.mode = modeWizard
 		m.wizard = newWizardState(m.defaultInterval, m.defaultPrompt, m.defaultPromptMsg)
+		m.loadWizardTemplates()
 		return m, nil
 	case "r":
 		view, ok := m.selectedView()
@@ -614,6 +939,12 @@ func (m model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.helpReturn = modeFilter
 		m.mode = modeHelp
 		return m, nil
+	case "ctrl+o":
+		m.filterOutput = !m.filterOutput
+		m.outputMatches = nil
+		oldID, oldIdx := m.selectedID, m.selectedIdx
+		m.applyFilters(oldID, oldIdx)
+		return m, m.fetchCmd()
 	case "tab":
 		if m.filterFocus == filterFocusText {
 			m.filterFocus = filterFocusStatus
@@ -662,7 +993,10 @@ func (m model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateExpandedLogsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	if m.jump != nil {
+		return m.updateJumpPrompt(msg)
+	}
+	switch m.keys.resolve(msg.String()) {
 	case "q", "esc":
 		m.mode = modeMain
 		return m, m.fetchCmd()
@@ -697,6 +1031,15 @@ func (m model) updateExpandedLogsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "x":
 		m.cycleLogLayer(1)
 		return m, nil
+	case "T":
+		m.cycleLogTimeMode()
+		return m, nil
+	case "g":
+		m.jump = &jumpState{}
+		return m, nil
+	case "|", "C":
+		m.toggleDiffOption(msg.String())
+		return m, nil
 	case ",":
 		m.moveRunSelection(-1)
 		return m, m.fetchCmd()
@@ -751,6 +1094,15 @@ func (m model) updateConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.confirm = nil
 		req := actionRequest{Kind: confirm.Action, LoopID: confirm.LoopID, ForceDelete: confirm.Action == actionDelete && strings.Contains(confirm.Prompt, "Force delete")}
 		return m.runAction(req)
+	case "c", "C":
+		confirm := m.confirm
+		if confirm.Action != actionDelete || confirm.Plan == nil {
+			return m, nil
+		}
+		m.mode = modeMain
+		m.confirm = nil
+		req := actionRequest{Kind: actionDelete, LoopID: confirm.LoopID, ForceDelete: strings.Contains(confirm.Prompt, "Force delete"), Cascade: true}
+		return m.runAction(req)
 	default:
 		return m, nil
 	}
@@ -784,7 +1136,21 @@ func (m model) updateWizardMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m.runAction(actionRequest{Kind: actionCreate, Wizard: m.wizard.Values})
+	case "ctrl+t":
+		if m.wizard.Step == 1 {
+			m.cycleWizardTemplate()
+		}
+		return m, nil
+	case "ctrl+s":
+		if m.wizard.Step == 4 {
+			m.saveWizardTemplate()
+		}
+		return m, nil
 	case "b", "left":
+		if m.wizard.Step == 4 && m.wizard.SaveAs != "" && msg.String() == "b" {
+			m.wizard.SaveAs += "b"
+			return m, nil
+		}
 		if m.wizard.Step > 1 {
 			m.wizard.Step--
 			m.wizard.Field = 0
@@ -792,6 +1158,10 @@ func (m model) updateWizardMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "backspace", "ctrl+h", "delete":
+		if m.wizard.Step == 4 {
+			m.wizard.SaveAs = removeLastRune(m.wizard.SaveAs)
+			return m, nil
+		}
 		if m.wizard.Step > 3 {
 			return m, nil
 		}
@@ -813,6 +1183,10 @@ func (m model) updateWizardMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		wizardSet(&m.wizard.Values, key, wizardGet(&m.wizard.Values, key)+" ")
 		return m, nil
 	default:
+		if m.wizard.Step == 4 && len(msg.Runes) > 0 {
+			m.wizard.SaveAs += string(msg.Runes)
+			return m, nil
+		}
 		if m.wizard.Step > 3 || len(msg.Runes) == 0 {
 			return m, nil
 		}
@@ -825,8 +1199,88 @@ func (m model) updateWizardMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m model) updateHelpMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m model) updateMessageMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.message.Text
+	if m.message.Field == 1 {
+		field = &m.message.At
+	}
 	switch msg.String() {
+	case "esc":
+		m.mode = modeMain
+		m.message = messageState{}
+		return m, nil
+	case "tab", "shift+tab", "down", "up":
+		m.message.Field = 1 - m.message.Field
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(m.message.Text) == "" {
+			m.message.Error = "message text required"
+			return m, nil
+		}
+		notBefore, err := loop.ParseNotBefore(m.message.At, time.Now())
+		if err != nil {
+			m.message.Error = err.Error()
+			return m, nil
+		}
+		m.mode = modeMain
+		m.message.Error = ""
+		return m.runAction(actionRequest{Kind: actionMessage, LoopID: m.message.LoopID, Message: m.message.Text, NotBefore: notBefore})
+	case "backspace", "ctrl+h", "delete":
+		*field = removeLastRune(*field)
+		return m, nil
+	case "space":
+		*field += " "
+		return m, nil
+	default:
+		if len(msg.Runes) > 0 {
+			*field += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+func (m model) updateSwitchProfileMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := []*string{&m.switchProf.Profile, &m.switchProf.Queue, &m.switchProf.Force}
+	field := fields[m.switchProf.Field]
+	switch msg.String() {
+	case "esc":
+		m.mode = modeMain
+		m.switchProf = switchProfileState{}
+		return m, nil
+	case "tab", "down":
+		m.switchProf.Field = (m.switchProf.Field + 1) % len(fields)
+		return m, nil
+	case "shift+tab", "up":
+		m.switchProf.Field = (m.switchProf.Field + len(fields) - 1) % len(fields)
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(m.switchProf.Profile) == "" {
+			m.switchProf.Error = "profile required"
+			return m, nil
+		}
+		if _, err := loop.ParseSwitchQueueMode(m.switchProf.Queue); err != nil {
+			m.switchProf.Error = err.Error()
+			return m, nil
+		}
+		m.mode = modeMain
+		m.switchProf.Error = ""
+		return m.runAction(actionRequest{Kind: actionSwitchProfile, LoopID: m.switchProf.LoopID, Switch: m.switchProf})
+	case "backspace", "ctrl+h", "delete":
+		*field = removeLastRune(*field)
+		return m, nil
+	case "space":
+		*field += " "
+		return m, nil
+	default:
+		if len(msg.Runes) > 0 {
+			*field += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+func (m model) updateHelpMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.keys.resolve(msg.String()) {
 	case "q", "esc", "?":
 		if m.helpReturn == modeHelp {
 			m.mode = modeMain
@@ -857,6 +1311,20 @@ func (m model) runAction(req actionRequest) (tea.Model, tea.Cmd) {
 		m.setStatus(statusInfo, "Killing loop...")
 	case actionDelete:
 		m.setStatus(statusInfo, "Deleting loop record...")
+	case actionMessage:
+		m.setStatus(statusInfo, "Queueing message...")
+	case actionSwitchProfile:
+		m.setStatus(statusInfo, "Switching profile...")
+	case actionQueueAdd:
+		m.setStatus(statusInfo, "Queueing item...")
+	case actionQueueRemove:
+		m.setStatus(statusInfo, "Removing queue item...")
+	case actionQueueMove:
+		m.setStatus(statusInfo, "Reordering queue...")
+	case actionSaveProfile, actionSavePool:
+		m.setStatus(statusInfo, "Saving...")
+	case actionDeleteProfile, actionDeletePool:
+		m.setStatus(statusInfo, "Deleting...")
 	default:
 		m.setStatus(statusInfo, "Running action...")
 	}
@@ -885,7 +1353,25 @@ func (m model) actionCmd(req actionRequest) tea.Cmd {
 		case actionKill:
 			result.Message, err = killLoop(ctx, database, req.LoopID)
 		case actionDelete:
-			result.Message, err = deleteLoop(ctx, database, req.LoopID, req.ForceDelete)
+			result.Message, err = deleteLoop(ctx, database, dataDir, req.LoopID, req.ForceDelete, req.Cascade)
+		case actionMessage:
+			result.Message, err = queueMessage(ctx, database, req.LoopID, req.Message, req.NotBefore)
+		case actionSwitchProfile:
+			result.Message, err = switchLoopProfile(ctx, database, configFile, req.LoopID, req.Switch)
+		case actionQueueAdd:
+			result.Message, err = addQueueItem(ctx, database, req.LoopID, req.QueueKind, req.Message)
+		case actionQueueRemove:
+			result.Message, err = removeQueueItem(ctx, database, req.LoopID, req.QueueItemID)
+		case actionQueueMove:
+			result.Message, err = moveQueueItem(ctx, database, req.LoopID, req.QueueItemID, req.QueueDelta)
+		case actionSaveProfile:
+			result.Message, err = saveProfile(ctx, database, req.Manage)
+		case actionDeleteProfile:
+			result.Message, err = deleteProfile(ctx, database, req.Manage.ID)
+		case actionSavePool:
+			result.Message, err = savePool(ctx, database, req.Manage)
+		case actionDeletePool:
+			result.Message, err = deletePool(ctx, database, req.Manage.ID)
 		case actionCreate:
 			result.SelectedLoopID, result.Message, err = createLoops(ctx, database, dataDir, configFile, defaultInterval, defaultPrompt, defaultPromptMsg, req.Wizard)
 		default:
@@ -918,6 +1404,7 @@ func (m model) enterConfirm(action actionType) (tea.Model, tea.Cmd) {
 		} else {
 			confirm.Prompt = fmt.Sprintf("Loop is still running. Force delete record %s? [y/N]", loopID)
 		}
+		confirm.Plan = m.previewDelete(view.Loop)
 	default:
 		m.setStatus(statusErr, "Unsupported destructive action")
 		return m, nil
@@ -979,7 +1466,11 @@ func (m *model) applyFilters(previousID string, previousIdx int) {
 		if state != "" && state != "all" && loopState != state {
 			continue
 		}
-		if query != "" {
+		if query != "" && m.filterOutput {
+			if _, ok := m.outputMatches[view.Loop.ID]; !ok {
+				continue
+			}
+		} else if query != "" {
 			idCandidate := strings.ToLower(loopDisplayID(view.Loop))
 			fullID := strings.ToLower(view.Loop.ID)
 			name := strings.ToLower(view.Loop.Name)
@@ -991,6 +1482,7 @@ func (m *model) applyFilters(previousID string, previousIdx int) {
 		filtered = append(filtered, view)
 	}
 
+	sortLoopViews(filtered, m.listSort)
 	m.filtered = filtered
 	if len(filtered) == 0 {
 		m.selectedIdx = 0
@@ -1044,6 +1536,8 @@ func (m model) tabLabel(tab mainTab) string {
 		return "Runs"
 	case tabMultiLogs:
 		return "Multi Logs"
+	case tabQueue:
+		return "Queue"
 	default:
 		return "Overview"
 	}
@@ -1059,6 +1553,8 @@ func (m model) tabShortLabel(tab mainTab) string {
 		return "runs"
 	case tabMultiLogs:
 		return "multi"
+	case tabQueue:
+		return "queue"
 	default:
 		return "ov"
 	}
@@ -1281,6 +1777,7 @@ func (m *model) moveRunSelection(delta int) {
 		m.selectedRun = len(m.runHistory) - 1
 	}
 	m.logScroll = 0
+	m.checkpoint = checkpointDiffState{}
 }
 
 func (m *model) cycleLogSource(delta int) {
@@ -1335,6 +1832,23 @@ func (m model) logSourceLabel() string {
 	}
 }
 
+// toggleDiffOption flips a diff layer display option and switches to the
+// diff layer so the key always has a visible effect.
+func (m *model) toggleDiffOption(key string) {
+	m.logLayer = logLayerDiff
+	if key == "|" {
+		m.diffSplit = !m.diffSplit
+	} else {
+		m.diffCollapse = !m.diffCollapse
+	}
+	m.logScroll = 0
+	status := "Diff: " + m.diffModeLabel()
+	if m.diffSplit && m.effectiveWidth() < diffSplitMinWidth {
+		status += fmt.Sprintf(" (side-by-side needs %d columns)", diffSplitMinWidth)
+	}
+	m.setStatus(statusInfo, status)
+}
+
 func (m model) logLayerLabel() string {
 	switch m.logLayer {
 	case logLayerEvents:
@@ -1395,20 +1909,22 @@ func (m model) currentLogDisplay(view loopView) logDisplay {
 		}
 		run := m.runHistory[0]
 		return logDisplay{
-			Title:   fmt.Sprintf("Latest run %s (%s)", shortRunID(run.Run.ID), strings.ToUpper(string(run.Run.Status))),
-			Source:  source,
-			Lines:   runOutputLines(run.Run, m.desiredSelectedLogLines()),
-			Message: "Run output is empty.",
-			Harness: run.Harness,
+			Title:    fmt.Sprintf("Latest run %s (%s)", shortRunID(run.Run.ID), strings.ToUpper(string(run.Run.Status))),
+			Source:   source,
+			Lines:    runOutputLines(run.Run, m.desiredSelectedLogLines()),
+			Message:  "Run output is empty.",
+			Harness:  run.Harness,
+			BaseTime: run.Run.StartedAt,
 		}
 	case logSourceRunSelection:
 		if run, ok := m.selectedRunView(); ok && run.Run != nil {
 			return logDisplay{
-				Title:   fmt.Sprintf("Run %s (%s)", shortRunID(run.Run.ID), strings.ToUpper(string(run.Run.Status))),
-				Source:  source,
-				Lines:   runOutputLines(run.Run, m.desiredSelectedLogLines()),
-				Message: "Run output is empty.",
-				Harness: run.Harness,
+				Title:    fmt.Sprintf("Run %s (%s)", shortRunID(run.Run.ID), strings.ToUpper(string(run.Run.Status))),
+				Source:   source,
+				Lines:    runOutputLines(run.Run, m.desiredSelectedLogLines()),
+				Message:  "Run output is empty.",
+				Harness:  run.Harness,
+				BaseTime: run.Run.StartedAt,
 			}
 		}
 		return logDisplay{
@@ -1438,12 +1954,16 @@ func (m model) currentRunDisplay(view loopView) logDisplay {
 			Harness: view.ProfileHarness,
 		}
 	}
+	if m.checkpoint.RunID == run.Run.ID {
+		return m.checkpointDiffDisplay(run)
+	}
 	return logDisplay{
-		Title:   fmt.Sprintf("Run %s | profile=%s | started=%s", shortRunID(run.Run.ID), displayName(run.ProfileName, run.Run.ProfileID), run.Run.StartedAt.UTC().Format(time.RFC3339)),
-		Source:  "runs",
-		Lines:   runOutputLines(run.Run, m.desiredSelectedLogLines()),
-		Message: "Run output is empty.",
-		Harness: run.Harness,
+		Title:    fmt.Sprintf("Run %s | profile=%s | started=%s", shortRunID(run.Run.ID), displayName(run.ProfileName, run.Run.ProfileID), run.Run.StartedAt.UTC().Format(time.RFC3339)),
+		Source:   "runs",
+		Lines:    runOutputLines(run.Run, m.desiredSelectedLogLines()),
+		Message:  "Run output is empty.",
+		Harness:  run.Harness,
+		BaseTime: run.Run.StartedAt,
 	}
 }
 
@@ -1458,17 +1978,29 @@ func (m model) renderLogBlock(display logDisplay, width, available, scroll int)
 		}
 		return []string{truncateLine(message, width)}
 	}
-	lines := display.Lines
-	start, end, _ := logWindowBounds(len(lines), available, scroll)
-	lines = lines[start:end]
+	if m.logLayer == logLayerDiff {
+		return m.renderDiffBlock(display, width, available, scroll)
+	}
+	start, end, _ := logWindowBounds(len(display.Lines), available, scroll)
+	var stamps []logStamp
+	textWidth := width
+	if m.logTimeMode != logTimeOff && width > logGutterWidth {
+		stamps = stampLogLines(display.Lines, display.BaseTime)
+		textWidth = width - logGutterWidth
+	}
+	now := time.Now()
 	highlighter := newHarnessLogHighlighter(display.Harness)
-	rendered := make([]string, 0, len(lines))
-	for _, line := range lines {
+	rendered := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		line := display.Lines[i]
 		if !lineMatchesLayer(display.Harness, line, m.logLayer) {
 			continue
 		}
-		highlighted := highlighter.HighlightLine(m.palette, line)
-		rendered = append(rendered, truncateLine(highlighted, width))
+		highlighted := truncateLine(highlighter.HighlightLine(m.palette, loop.DisplayLogLine(line)), textWidth)
+		if stamps != nil {
+			highlighted = m.renderLogGutter(stamps[i], now) + highlighted
+		}
+		rendered = append(rendered, highlighted)
 	}
 	if len(rendered) == 0 {
 		return []string{truncateLine("No lines matched layer="+m.logLayerLabel(), width)}
@@ -1527,6 +2059,14 @@ func (m model) fetchCmd() tea.Cmd {
 	selectedLogLines := m.desiredSelectedLogLines()
 	multiLogLines := m.desiredMultiLogLines()
 	multiTargets := m.multiTargetIDs(m.multiPage, m.multiPageSize())
+	eventCursor := m.eventCursor
+	eventsSince := m.eventsSince
+	evalWatches := m.tab == tabOverview
+	gitStatus := m.gitStatus
+	outputQuery := ""
+	if m.filterOutput {
+		outputQuery = strings.TrimSpace(m.filterText)
+	}
 
 	if selectedID == "" && len(m.filtered) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.filtered) {
 		selectedID = m.filtered[m.selectedIdx].Loop.ID
@@ -1543,13 +2083,30 @@ func (m model) fetchCmd() tea.Cmd {
 
 		logLoopID, tail := loadSelectedLogTail(views, selectedID, dataDir, selectedLogLines)
 		runViews, _ := loadRunViews(ctx, database, logLoopID)
+		queueItems, _ := loadQueueItems(ctx, database, logLoopID)
 		multiLogs := loadLoopLogTails(views, multiTargets, dataDir, multiLogLines)
+		var watches []loop.WatchResult
+		var git *models.GitInfo
+		if evalWatches {
+			watches = loadLoopWatches(views, logLoopID)
+			git = loadLoopGitStatus(gitStatus, views, logLoopID)
+		}
 		return refreshMsg{
 			loops:      views,
 			selectedID: logLoopID,
 			selected:   tail,
 			runs:       runViews,
 			multiLogs:  multiLogs,
+			queue:      queueItems,
+			ledger:     loadLedgerSummary(views, logLoopID),
+			events:     loadLoopEvents(ctx, database, eventCursor, eventsSince),
+			usage:      sampleLoopResources(views),
+			sampledAt:  time.Now(),
+			watches:    watches,
+			git:        git,
+
+			outputQuery:   outputQuery,
+			outputMatches: searchRunOutput(ctx, database, outputQuery),
 		}
 	}
 }
@@ -1623,6 +2180,12 @@ func (m model) renderHeader() string {
 		modeName = "New Loop Wizard"
 	case modeHelp:
 		modeName = "Help"
+	case modeMessage:
+		modeName = "Message"
+	case modeSwitchProfile:
+		modeName = "Switch Profile"
+	case modeManage:
+		modeName = "Profiles & Pools"
 	}
 
 	total := len(m.loops)
@@ -1633,12 +2196,21 @@ func (m model) renderHeader() string {
 		}
 	}
 	header := fmt.Sprintf(
-		"Forge loops  mode:%s  tab:%s  loops:%d running:%d  theme:%s  keys:/ filter n new S/K/D r l ? q",
+		"Forge loops  mode:%s  tab:%s  loops:%d running:%d  theme:%s  keys:%s filter %s new %s/%s/%s %s %s %s %s",
 		modeName,
 		m.tabLabel(m.tab),
 		total,
 		running,
 		m.palette.Name,
+		m.keys.label(keyFilter),
+		m.keys.label(keyNew),
+		m.keys.label(keyStop),
+		m.keys.label(keyKill),
+		m.keys.label(keyDelete),
+		m.keys.label(keyResume),
+		m.keys.label(keyExpandedLogs),
+		m.keys.label(keyHelp),
+		m.keys.label(keyQuit),
 	)
 	if m.actionBusy {
 		header += "  action:running"
@@ -1646,6 +2218,9 @@ func (m model) renderHeader() string {
 	if m.focusRight {
 		header += "  zen:on"
 	}
+	if m.follow {
+		header += "  follow:on"
+	}
 	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.palette.Text)).
 		Background(lipgloss.Color(m.palette.Panel)).
@@ -1675,13 +2250,18 @@ func (m model) renderTabBar(width int) string {
 	hints := ""
 	switch m.tab {
 	case tabLogs:
-		hints = fmt.Sprintf("  v source  x layer(%s)  ,/. run  pgup/pgdn home/end  z zen  l expanded  ? help", m.logLayerLabel())
+		hints = fmt.Sprintf("  v source  x layer(%s)  ,/. run  pgup/pgdn home/end  %s zen  %s expanded  %s help", m.logLayerLabel(), m.keys.label(keyZen), m.keys.label(keyExpandedLogs), m.keys.label(keyHelp))
 	case tabRuns:
-		hints = fmt.Sprintf("  x layer(%s)  ,/. run  pgup/pgdn home/end  z zen  l expanded  ? help", m.logLayerLabel())
+		hints = fmt.Sprintf("  x layer(%s)  ,/. run  pgup/pgdn home/end  %s zen  %s expanded  %s help", m.logLayerLabel(), m.keys.label(keyZen), m.keys.label(keyExpandedLogs), m.keys.label(keyHelp))
+	case tabQueue:
+		hints = fmt.Sprintf("  ,/. select  %s/%s move  %s add  %s remove  %s help", m.keys.label(keyQueueMoveUp), m.keys.label(keyQueueMoveDown), m.keys.label(keyQueueAdd), m.keys.label(keyQueueRemove), m.keys.label(keyHelp))
 	case tabMultiLogs:
-		hints = fmt.Sprintf("  x layer(%s)  space pin  c clear  m layout(%s)  ,/. page  home/end  z zen  ? help", m.logLayerLabel(), m.currentLayout().Label())
+		hints = fmt.Sprintf("  x layer(%s)  %s pin  %s clear  m layout(%s)  ,/. page  home/end  %s zen  %s help", m.logLayerLabel(), m.keys.label(keyPin), m.keys.label(keyClearPins), m.currentLayout().Label(), m.keys.label(keyZen), m.keys.label(keyHelp))
 	default:
-		hints = "  ]/[ tabs  t theme  z zen  space pin  ? help"
+		hints = fmt.Sprintf("  %s/%s tabs  %s theme  %s zen  %s pin  %s help", m.keys.label(keyNextTab), m.keys.label(keyPrevTab), m.keys.label(keyTheme), m.keys.label(keyZen), m.keys.label(keyPin), m.keys.label(keyHelp))
+	}
+	if m.logLayer == logLayerDiff && m.tab != tabOverview && m.tab != tabQueue {
+		hints = fmt.Sprintf("  | split  C collapse  diff(%s)", m.diffModeLabel()) + hints
 	}
 	targetWidth := maxInt(1, width-1)
 	line := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
@@ -1736,7 +2316,7 @@ func (m model) renderLeftPane(width, height int) string {
 	rows = append(rows, lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.palette.Text)).
 		Bold(true).
-		Render(truncateLine("P STATUS   ID       RUNS HARNESS   DIR", contentWidth)))
+		Render(truncateLine(m.renderListHeader(), contentWidth)))
 
 	if len(m.filtered) == 0 {
 		empty := []string{
@@ -1789,29 +2369,6 @@ func (m model) renderLeftPane(width, height int) string {
 	return style.Render(strings.Join(rows, "\n"))
 }
 
-func (m model) renderListRow(view loopView, width int) string {
-	if view.Loop == nil {
-		return ""
-	}
-	status := strings.ToUpper(string(view.Loop.State))
-	status = truncateLine(status, 7)
-	statusStyled := statusStyleForPalette(m.palette, view.Loop.State).Render(padRight(status, 7))
-	pin := " "
-	if m.isPinned(view.Loop.ID) {
-		pin = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Warning)).Bold(true).Render("P")
-	}
-	id := truncateLine(loopDisplayID(view.Loop), 9)
-	runs := fmt.Sprintf("%d", view.Runs)
-	harness := truncateLine(strings.ToLower(string(view.ProfileHarness)), 9)
-	if strings.TrimSpace(harness) == "" {
-		harness = "-"
-	}
-	dir := filepath.Base(view.Loop.RepoPath)
-
-	base := fmt.Sprintf("%s %s %-9s %4s %-9s %s", pin, statusStyled, id, runs, harness, dir)
-	return truncateLine(base, width)
-}
-
 func (m model) renderRightPane(width, height int) string {
 	style := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -1844,6 +2401,8 @@ func (m model) renderRightPane(width, height int) string {
 		return style.Render(m.renderRunsPane(view, width-2, height-2))
 	case tabMultiLogs:
 		return style.Render(m.renderMultiLogsPane(width-2, height-2))
+	case tabQueue:
+		return style.Render(m.renderQueuePane(view, width-2, height-2))
 	default:
 		return style.Render(m.renderOverviewPane(view, width-2, height-2))
 	}
@@ -1860,6 +2419,7 @@ func (m model) renderOverviewPane(view loopView, width, height int) string {
 	lines = append(lines, fmt.Sprintf("Pool: %s", displayName(view.PoolName, loopEntry.PoolID)))
 	lines = append(lines, fmt.Sprintf("Profile: %s", displayName(view.ProfileName, loopEntry.ProfileID)))
 	lines = append(lines, fmt.Sprintf("Harness/Auth: %s / %s", displayName(string(view.ProfileHarness), "-"), displayName(view.ProfileAuth, "-")))
+	lines = append(lines, fmt.Sprintf("Prompt: %s", loopPromptLabel(loopEntry)))
 	lines = append(lines, fmt.Sprintf("Last Run: %s", formatTime(loopEntry.LastRunAt)))
 	lines = append(lines, fmt.Sprintf("Queue Depth: %d", view.QueueDepth))
 	lines = append(lines, fmt.Sprintf("Interval: %s", formatDurationSeconds(loopEntry.IntervalSeconds)))
@@ -1893,14 +2453,39 @@ func (m model) renderOverviewPane(view loopView, width, height int) string {
 	for _, line := range lines {
 		content = append(content, truncateLine(line, contentWidth))
 	}
+	if hist, ok := m.resources[loopEntry.ID]; ok {
+		sparkWidth := minInt(resourceHistoryLen, maxInt(8, contentWidth-24))
+		content = append(content, "")
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render(fmt.Sprintf("Resources (pid %d):", hist.pid)))
+		cpuLabel := "-"
+		if value, ok := lastValue(hist.cpu); ok {
+			cpuLabel = fmt.Sprintf("%.1f%%", value)
+		}
+		memLabel := "-"
+		if value, ok := lastValue(hist.rssBytes); ok {
+			memLabel = formatRSS(value)
+		}
+		content = append(content, truncateLine(fmt.Sprintf("  CPU %s %s", hist.cpuSparkline(sparkWidth), cpuLabel), contentWidth))
+		content = append(content, truncateLine(fmt.Sprintf("  Mem %s %s", hist.memSparkline(sparkWidth), memLabel), contentWidth))
+	}
+	content = append(content, m.renderGitLines(loopEntry.ID, contentWidth)...)
+	content = append(content, m.renderWatchLines(loopEntry.ID, contentWidth)...)
 	content = append(content, "")
 	content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Run snapshot:"))
 	content = append(content, truncateLine(fmt.Sprintf("  total=%d success=%d error=%d killed=%d running=%d", len(m.runHistory), successCount, errorCount, killedCount, runningCount), contentWidth))
 	if run, ok := m.selectedRunView(); ok && run.Run != nil {
 		content = append(content, truncateLine(fmt.Sprintf("  latest=%s status=%s exit=%s duration=%s", shortRunID(run.Run.ID), strings.ToUpper(string(run.Run.Status)), runExitCode(run.Run), formatRunDuration(run.Run)), contentWidth))
 	}
+	if m.ledger != nil && m.ledger.Entries > 0 {
+		content = append(content, "")
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Ledger:"))
+		content = append(content, truncateLine(fmt.Sprintf("  entries=%d success=%d error=%d killed=%d", m.ledger.Entries,
+			m.ledger.ByStatus[models.LoopRunStatusSuccess], m.ledger.ByStatus[models.LoopRunStatusError], m.ledger.ByStatus[models.LoopRunStatusKilled]), contentWidth))
+		last := m.ledger.Last
+		content = append(content, truncateLine(fmt.Sprintf("  last=#%d %s at %s", last.Iteration, strings.ToUpper(displayName(string(last.Status), "-")), formatTime(&last.Timestamp)), contentWidth))
+	}
 	content = append(content, "")
-	content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Workflow: 2=Logs (deep scroll) | 3=Runs | 4=Multi Logs"))
+	content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Workflow: 2=Logs (deep scroll) | 3=Runs | 4=Multi Logs | 5=Queue"))
 	return strings.Join(trimToHeight(content, maxInt(1, height-1)), "\n")
 }
 
@@ -1921,9 +2506,12 @@ func (m model) renderRunsPane(view loopView, width, height int) string {
 	contentWidth := maxInt(1, width-2)
 	content := []string{
 		fmt.Sprintf("Run history: %s  layer=%s", loopDisplayID(view.Loop), m.logLayerLabel()),
-		",/. select run | x layer | pgup/pgdn scroll output | l expanded",
-		"",
+		fmt.Sprintf(",/. select run | %s compare | %s checkpoint diff | x layer | pgup/pgdn scroll output | l expanded", m.keys.label(keyCompareRuns), m.keys.label(keyCheckpointDiff)),
+	}
+	if usage := runUsageTotals(m.runHistory); usage != "" {
+		content = append(content, truncateLine(usage, contentWidth))
 	}
+	content = append(content, "")
 	if len(m.runHistory) == 0 {
 		content = append(content, "No recorded runs yet.")
 		return strings.Join(content, "\n")
@@ -1938,6 +2526,8 @@ func (m model) renderRunsPane(view loopView, width, height int) string {
 		prefix := "  "
 		if i == m.selectedRun {
 			prefix = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Focus)).Bold(true).Render("> ")
+		} else if run.Run.ID == m.compareRunID {
+			prefix = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Warning)).Bold(true).Render("= ")
 		}
 		exit := "-"
 		if run.Run.ExitCode != nil {
@@ -1951,12 +2541,37 @@ func (m model) renderRunsPane(view loopView, width, height int) string {
 			formatRunDuration(run.Run),
 			displayName(run.ProfileName, run.Run.ProfileID),
 		)
+		if usage := formatRunUsage(run.Run); usage != "" {
+			label += " " + usage
+		}
+		if prompt := runPromptLabel(run.Run); prompt != "" {
+			label += " prompt=" + prompt
+		}
+		if len(run.Run.Artifacts) > 0 {
+			label += fmt.Sprintf(" art=%d", len(run.Run.Artifacts))
+		}
+		if checkpoint, ok := loop.RunCheckpoint(run.Run); ok {
+			label += " ckpt=" + shortRunID(checkpoint.SHA)
+		}
 		content = append(content, prefix+truncateLine(label, contentWidth-2))
 	}
 	if len(m.runHistory) > listLimit {
 		content = append(content, truncateLine(fmt.Sprintf("... %d more runs", len(m.runHistory)-listLimit), contentWidth))
 	}
 	content = append(content, "")
+	if selected, ok := m.selectedRunView(); ok {
+		if artifacts := runArtifactLines(selected.Run, maxInt(1, height/6)); len(artifacts) > 0 {
+			for _, line := range artifacts {
+				content = append(content, truncateLine(line, contentWidth))
+			}
+			content = append(content, "")
+		}
+	}
+
+	if base, ok := m.compareBaseRun(); ok {
+		content = append(content, m.renderRunCompare(base, contentWidth, maxInt(1, height-len(content)))...)
+		return strings.Join(content, "\n")
+	}
 
 	display := m.currentRunDisplay(view)
 	content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render(display.Title))
@@ -2130,21 +2745,22 @@ func (m model) renderMiniLogEmptyPane(width, height int) string {
 		Render(strings.Join(lines, "\n"))
 }
 
+func (m model) expandedLogDisplay(view loopView) logDisplay {
+	if m.tab == tabRuns {
+		return m.currentRunDisplay(view)
+	}
+	return m.currentLogDisplay(view)
+}
+
 func (m model) renderExpandedLogs(width, height int) string {
 	view, ok := m.selectedView()
 	if !ok || view.Loop == nil {
 		return "No loop selected."
 	}
-	var display logDisplay
-	switch m.tab {
-	case tabRuns:
-		display = m.currentRunDisplay(view)
-	default:
-		display = m.currentLogDisplay(view)
-	}
+	display := m.expandedLogDisplay(view)
 	content := []string{
 		fmt.Sprintf("Expanded logs for %s", loopDisplayID(view.Loop)),
-		fmt.Sprintf("tab=%s source=%s layer=%s  q/esc close", m.tabLabel(m.tab), display.Source, m.logLayerLabel()),
+		fmt.Sprintf("tab=%s source=%s layer=%s time=%s  %s jump  q/esc close", m.tabLabel(m.tab), display.Source, m.logLayerLabel(), m.logTimeMode.label(), m.keys.label(keyJumpToTime)),
 		"",
 	}
 	available := maxInt(1, height-len(content)-2)
@@ -2188,8 +2804,21 @@ func (m model) renderFilterBar(width int) string {
 	}
 	statusField := statusStyle.Render("status=" + strings.Join(statusParts, " "))
 
-	line := fmt.Sprintf("Filter mode | %s | %s | tab switches focus | esc exits", textField, statusField)
-	return box.Render(truncateLine(line, maxInt(1, width-6)))
+	scope := "names"
+	if m.filterOutput {
+		scope = "run output"
+	}
+	line := fmt.Sprintf("Filter mode | %s | %s | in=%s (ctrl+o) | tab switches focus | esc exits", textField, statusField, scope)
+	if view, ok := m.selectedView(); ok && m.filterOutput {
+		if snippet := m.outputMatches[view.Loop.ID]; snippet != "" {
+			line += "\n" + "match: " + snippet
+		}
+	}
+	lines := strings.Split(line, "\n")
+	for i := range lines {
+		lines[i] = truncateLine(lines[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(lines, "\n"))
 }
 
 func (m model) renderConfirmDialog(width int) string {
@@ -2206,8 +2835,9 @@ func (m model) renderConfirmDialog(width int) string {
 	text := []string{
 		title,
 		m.confirm.Prompt,
-		"Press y to confirm. Press n, Enter, q, or Esc to cancel.",
 	}
+	text = append(text, renderDeletePlan(m.confirm.Plan)...)
+	text = append(text, "Press y to confirm. Press n, Enter, q, or Esc to cancel.")
 	return box.Render(strings.Join(text, "\n"))
 }
 
@@ -2226,8 +2856,12 @@ func (m model) renderWizard(width int) string {
 		}
 	}
 
+	title := "New loop wizard"
+	if m.wizard.CloneOf != "" {
+		title = "Clone loop wizard (from " + m.wizard.CloneOf + ")"
+	}
 	content := []string{
-		"New loop wizard",
+		title,
 		strings.Join(stepLabels, "  "),
 		"",
 	}
@@ -2235,6 +2869,7 @@ func (m model) renderWizard(width int) string {
 	switch m.wizard.Step {
 	case 1:
 		content = append(content,
+			m.renderWizardTemplates(),
 			renderWizardField(m.palette, "name", m.wizard.Values.Name, m.wizard.Field == 0),
 			renderWizardField(m.palette, "name-prefix", m.wizard.Values.NamePrefix, m.wizard.Field == 1),
 			renderWizardField(m.palette, "count", m.wizard.Values.Count, m.wizard.Field == 2),
@@ -2267,11 +2902,13 @@ func (m model) renderWizard(width int) string {
 			fmt.Sprintf("  max-runtime=%q", m.wizard.Values.MaxRuntime),
 			fmt.Sprintf("  max-iterations=%q", m.wizard.Values.MaxIterations),
 			fmt.Sprintf("  tags=%q", m.wizard.Values.Tags),
+			"",
+			renderWizardField(m.palette, "save as template", m.wizard.SaveAs, true)+"  (ctrl+s save)",
 		)
 	}
 
 	content = append(content, "")
-	content = append(content, "tab/down/up navigate fields, enter next/submit, b back, esc cancel")
+	content = append(content, "tab/down/up navigate fields, enter next/submit, b back, ctrl+t template, esc cancel")
 	if m.wizard.Error != "" {
 		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.wizard.Error))
 	}
@@ -2283,6 +2920,53 @@ func (m model) renderWizard(width int) string {
 	return box.Render(strings.Join(content, "\n"))
 }
 
+func (m model) renderMessageDialog(width int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.palette.Accent)).
+		Background(lipgloss.Color(m.palette.PanelAlt)).
+		Padding(0, 1).
+		Width(maxInt(40, width))
+
+	content := []string{
+		"Queue message",
+		renderWizardField(m.palette, "message", m.message.Text, m.message.Field == 0),
+		renderWizardField(m.palette, "at (HH:MM, RFC3339, +30m; empty = next iteration)", m.message.At, m.message.Field == 1),
+		"tab switches field, enter queues, esc cancels",
+	}
+	if m.message.Error != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.message.Error))
+	}
+	for i := range content {
+		content[i] = truncateLine(content[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
+
+func (m model) renderSwitchProfileDialog(width int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.palette.Accent)).
+		Background(lipgloss.Color(m.palette.PanelAlt)).
+		Padding(0, 1).
+		Width(maxInt(40, width))
+
+	content := []string{
+		"Switch profile",
+		renderWizardField(m.palette, "profile (name or id)", m.switchProf.Profile, m.switchProf.Field == 0),
+		renderWizardField(m.palette, "pending queue (migrate|drain)", m.switchProf.Queue, m.switchProf.Field == 1),
+		renderWizardField(m.palette, "force past cooldown/missing harness (y/N)", m.switchProf.Force, m.switchProf.Field == 2),
+		"tab switches field, enter switches and restarts, esc cancels",
+	}
+	if m.switchProf.Error != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.switchProf.Error))
+	}
+	for i := range content {
+		content[i] = truncateLine(content[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
+
 func (m model) renderHelpDialog(width int) string {
 	box := lipgloss.NewStyle().
 		Border(lipgloss.DoubleBorder()).
@@ -2291,25 +2975,46 @@ func (m model) renderHelpDialog(width int) string {
 		Padding(0, 1).
 		Width(maxInt(56, width))
 
+	k := m.keys
 	lines := []string{
 		lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Text)).Bold(true).Render("Forge TUI Help"),
 		"",
 		"Global:",
-		"  q quit | ? toggle help | ]/[ tab cycle | 1..4 jump tabs | t theme | z zen",
-		"  j/k or arrows move loop | / filter | l expanded logs | n new loop wizard",
-		"  S/K/D stop/kill/delete | r resume | space pin/unpin | c clear pins",
+		fmt.Sprintf("  %s quit | %s toggle help | %s/%s tab cycle | %s/%s/%s/%s/%s jump tabs | %s theme | %s zen",
+			k.label(keyQuit), k.label(keyHelp), k.label(keyNextTab), k.label(keyPrevTab),
+			k.label(keyTabOverview), k.label(keyTabLogs), k.label(keyTabRuns), k.label(keyTabMultiLogs), k.label(keyTabQueue),
+			k.label(keyTheme), k.label(keyZen)),
+		fmt.Sprintf("  j/k or arrows move loop | %s filter (ctrl+o searches run output) | %s expanded logs | %s new loop wizard",
+			k.label(keyFilter), k.label(keyExpandedLogs), k.label(keyNew)),
+		fmt.Sprintf("  %s stop | %s kill | %s delete | %s resume | %s pin/unpin | %s clear pins",
+			k.label(keyStop), k.label(keyKill), k.label(keyDelete), k.label(keyResume), k.label(keyPin), k.label(keyClearPins)),
+		fmt.Sprintf("  %s cycle list sort (created/status/runs/last run/queue depth); columns via tui.loop_columns", k.label(keySort)),
+		fmt.Sprintf("  %s follow: keep the loop that last wrote to its log selected (j/k turns it off)", k.label(keyFollow)),
+		"  C (Overview/Queue) clone the selected loop into the wizard, prefilled for tweaking",
+		fmt.Sprintf("  %s queue message (optionally scheduled with HH:MM or +duration)", k.label(keyMessage)),
+		fmt.Sprintf("  %s switch profile (migrates or drains pending queue, restarts runner)", k.label(keySwitchProfile)),
+		fmt.Sprintf("  %s/%s manage profiles/pools (n new, e edit, D delete, tab switch list)", k.label(keyManageProfiles), k.label(keyManagePools)),
 		"",
 		"Logs + Runs:",
 		"  v source cycle (live/latest-run/selected-run)",
 		"  x semantic layer cycle (raw/events/errors/tools/diff)",
+		"  diff layer: | side-by-side (wide panes) | C collapse unchanged lines",
 		"  ,/. previous/next run",
+		fmt.Sprintf("  %s (Runs) compare selected run against the next one picked; new error lines marked !", k.label(keyCompareRuns)),
+		fmt.Sprintf("  %s (Runs) diff of repo changes since the selected run's git checkpoint (ckpt=)", k.label(keyCheckpointDiff)),
 		"  pgup/pgdn/home/end/u/d scroll log output",
+		fmt.Sprintf("  %s timestamp gutter (off/absolute/relative; ~ marks times inherited from earlier lines)", k.label(keyLogTimestamps)),
+		fmt.Sprintf("  %s (expanded logs) jump to time: HH:MM[:SS], RFC3339, or 10m ago", k.label(keyJumpToTime)),
 		"",
 		"Multi Logs:",
 		"  m cycle layouts (1x1 -> 4x4)",
 		"  ,/. previous/next page | home/end first/last page",
 		"",
-		"Press q, esc, or ? to close help.",
+		"Queue:",
+		fmt.Sprintf("  ,/. select pending item | %s/%s move earlier/later | %s remove", k.label(keyQueueMoveUp), k.label(keyQueueMoveDown), k.label(keyQueueRemove)),
+		fmt.Sprintf("  %s add a next-prompt override or appended message", k.label(keyQueueAdd)),
+		"",
+		fmt.Sprintf("Press %s, esc, or %s to close help.", k.label(keyQuit), k.label(keyHelp)),
 	}
 	for i := range lines {
 		lines[i] = truncateLine(lines[i], maxInt(1, width-8))
@@ -2356,6 +3061,25 @@ func statusStyleForPalette(palette tuiPalette, state models.LoopState) lipgloss.
 	}
 }
 
+// searchRunOutput maps each loop with a run whose output matches query to
+// its best matching snippet.
+func searchRunOutput(ctx context.Context, database *db.DB, query string) map[string]string {
+	if database == nil || query == "" {
+		return nil
+	}
+	results, err := db.NewLoopRunRepository(database).SearchOutput(ctx, db.LoopRunSearchQuery{Text: query, Limit: 500})
+	if err != nil {
+		return nil
+	}
+	matches := make(map[string]string, len(results))
+	for _, result := range results {
+		if _, ok := matches[result.Run.LoopID]; !ok {
+			matches[result.Run.LoopID] = strings.Join(strings.Fields(result.Snippet), " ")
+		}
+	}
+	return matches
+}
+
 func loadLoopViews(ctx context.Context, database *db.DB) ([]loopView, error) {
 	if database == nil {
 		return nil, errors.New("database is nil")
@@ -2416,6 +3140,7 @@ func loadLoopViews(ctx context.Context, database *db.DB) ([]loopView, error) {
 			ProfileHarness: profileHarness[loopEntry.ProfileID],
 			ProfileAuth:    profileAuth[loopEntry.ProfileID],
 			PoolName:       poolNames[loopEntry.PoolID],
+			LogModified:    logModTime(loopEntry.LogPath),
 		})
 	}
 
@@ -2431,6 +3156,23 @@ func loadLoopViews(ctx context.Context, database *db.DB) ([]loopView, error) {
 	return views, nil
 }
 
+// loadLedgerSummary summarizes the selected loop's ledger; nil when the
+// loop has not written one yet.
+func loadLedgerSummary(views []loopView, loopID string) *loop.LedgerSummary {
+	for _, view := range views {
+		if view.Loop == nil || view.Loop.ID != loopID || view.Loop.LedgerPath == "" {
+			continue
+		}
+		ledger, err := loop.ReadLedger(view.Loop.LedgerPath)
+		if err != nil {
+			return nil
+		}
+		summary := loop.SummarizeLedger(ledger.Entries)
+		return &summary
+	}
+	return nil
+}
+
 func loadSelectedLogTail(views []loopView, selectedID, dataDir string, maxLines int) (string, logTailView) {
 	if maxLines <= 0 {
 		maxLines = defaultLogLines
@@ -2586,6 +3328,29 @@ func stopLoop(ctx context.Context, database *db.DB, loopID string) (string, erro
 	return fmt.Sprintf("Stop requested for loop %s", loopDisplayID(loopEntry)), nil
 }
 
+func queueMessage(ctx context.Context, database *db.DB, loopID, text string, notBefore *time.Time) (string, error) {
+	loopRepo := db.NewLoopRepository(database)
+	queueRepo := db.NewLoopQueueRepository(database)
+	loopEntry, err := loopRepo.Get(ctx, loopID)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(models.MessageAppendPayload{Text: text})
+	if err != nil {
+		return "", err
+	}
+	item := &models.LoopQueueItem{Type: models.LoopQueueItemMessageAppend, Payload: payload, NotBefore: notBefore}
+	if err := queueRepo.Enqueue(ctx, loopEntry.ID, item); err != nil {
+		return "", err
+	}
+
+	if notBefore != nil {
+		return fmt.Sprintf("Queued message for loop %s at %s", loopDisplayID(loopEntry), notBefore.Local().Format("2006-01-02 15:04")), nil
+	}
+	return fmt.Sprintf("Queued message for loop %s", loopDisplayID(loopEntry)), nil
+}
+
 func killLoop(ctx context.Context, database *db.DB, loopID string) (string, error) {
 	loopRepo := db.NewLoopRepository(database)
 	queueRepo := db.NewLoopQueueRepository(database)
@@ -2631,7 +3396,49 @@ func resumeLoop(ctx context.Context, database *db.DB, configFile, loopID string)
 	return fmt.Sprintf("Loop %q resumed (%s)", loopEntry.Name, loopDisplayID(loopEntry)), nil
 }
 
-func deleteLoop(ctx context.Context, database *db.DB, loopID string, force bool) (string, error) {
+func switchLoopProfile(ctx context.Context, database *db.DB, configFile, loopID string, req switchProfileState) (string, error) {
+	loopRepo := db.NewLoopRepository(database)
+	loopEntry, err := loopRepo.Get(ctx, loopID)
+	if err != nil {
+		return "", err
+	}
+	profile, err := resolveProfileByRef(ctx, db.NewProfileRepository(database), strings.TrimSpace(req.Profile))
+	if err != nil {
+		return "", err
+	}
+	mode, err := loop.ParseSwitchQueueMode(req.Queue)
+	if err != nil {
+		return "", err
+	}
+	force := strings.EqualFold(strings.TrimSpace(req.Force), "y") || strings.EqualFold(strings.TrimSpace(req.Force), "yes")
+
+	plan, err := loop.PlanProfileSwitch(ctx, database, loopEntry, profile, mode, force)
+	if err != nil {
+		return "", err
+	}
+	plan.Reason = "tui"
+
+	_ = killLoopProcess(loopEntry)
+	if err := loop.ApplyProfileSwitch(ctx, database, plan); err != nil {
+		return "", err
+	}
+	if plan.Restart {
+		if err := startLoopProcessFn(loopEntry.ID, configFile); err != nil {
+			return "", fmt.Errorf("profile switched but restart failed: %w", err)
+		}
+		if err := setLoopRunnerMetadata(ctx, loopRepo, loopEntry.ID, "local", ""); err != nil {
+			return "", err
+		}
+	}
+
+	message := fmt.Sprintf("Loop %s switched to %s (%d migrated, %d dropped)", loopDisplayID(loopEntry), profile.Name, plan.Migrated(), plan.Dropped())
+	if len(plan.Warnings) > 0 {
+		message += "; warning: " + strings.Join(plan.Warnings, "; ")
+	}
+	return message, nil
+}
+
+func deleteLoop(ctx context.Context, database *db.DB, dataDir, loopID string, force, cascade bool) (string, error) {
 	loopRepo := db.NewLoopRepository(database)
 	loopEntry, err := loopRepo.Get(ctx, loopID)
 	if err != nil {
@@ -2640,9 +3447,16 @@ func deleteLoop(ctx context.Context, database *db.DB, loopID string, force bool)
 	if loopEntry.State != models.LoopStateStopped && !force {
 		return "", fmt.Errorf("loop %q is %s; force delete required", loopEntry.Name, loopEntry.State)
 	}
-	if err := loopRepo.Delete(ctx, loopEntry.ID); err != nil {
+	plan, err := loop.PlanDelete(ctx, database, dataDir, loopEntry, cascade)
+	if err != nil {
 		return "", err
 	}
+	if err := loop.ExecuteDelete(ctx, database, plan); err != nil {
+		return "", err
+	}
+	if cascade {
+		return fmt.Sprintf("Loop %s deleted with %d file(s), %s reclaimed", loopDisplayID(loopEntry), len(plan.Files), formatDiskBytes(plan.Bytes)), nil
+	}
 	return fmt.Sprintf("Loop record %s deleted", loopDisplayID(loopEntry)), nil
 }
 
@@ -2882,8 +3696,9 @@ func newWizardState(defaultInterval time.Duration, defaultPrompt, defaultPromptM
 		interval = defaultInterval.String()
 	}
 	return wizardState{
-		Step:  1,
-		Field: 0,
+		Step:        1,
+		Field:       0,
+		TemplateIdx: -1,
 		Values: wizardValues{
 			Count:      "1",
 			Prompt:     strings.TrimSpace(defaultPrompt),
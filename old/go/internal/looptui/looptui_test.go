@@ -11,6 +11,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
 	"github.com/tOgg1/forge/internal/models"
 )
 
@@ -288,6 +289,194 @@ func TestModeTransitions(t *testing.T) {
 	}
 }
 
+func TestMessageModeValidatesSchedule(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	m.loops = []loopView{
+		testLoopView("a", "a12345", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if m.mode != modeMessage || m.message.LoopID != "a" {
+		t.Fatalf("expected message mode for selected loop, got %v %+v", m.mode, m.message)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("nightly audit")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("soon")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeMessage || m.message.Error == "" {
+		t.Fatalf("expected schedule validation error, got mode %v error %q", m.mode, m.message.Error)
+	}
+	if m.message.Text != "nightly audit" || m.message.At != "soon" {
+		t.Fatalf("unexpected message fields: %+v", m.message)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeMain {
+		t.Fatalf("expected main mode after esc, got %v", m.mode)
+	}
+}
+
+func TestSwitchProfileModeValidatesQueueMode(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	m.loops = []loopView{
+		testLoopView("a", "a12345", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if m.mode != modeSwitchProfile || m.switchProf.LoopID != "a" || m.switchProf.Queue != "migrate" {
+		t.Fatalf("expected switch-profile mode for selected loop, got %v %+v", m.mode, m.switchProf)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.switchProf.Error != "profile required" {
+		t.Fatalf("expected profile required error, got %q", m.switchProf.Error)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("codex")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeSwitchProfile || !strings.Contains(m.switchProf.Error, "invalid queue mode") {
+		t.Fatalf("expected queue mode error, got mode %v error %q", m.mode, m.switchProf.Error)
+	}
+	if m.switchProf.Profile != "codex" || m.switchProf.Queue != "migratex" {
+		t.Fatalf("unexpected switch fields: %+v", m.switchProf)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeMain {
+		t.Fatalf("expected main mode after esc, got %v", m.mode)
+	}
+}
+
+func TestManageModeProfileForm(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if m.mode != modeManage || m.manage.Tab != manageProfiles {
+		t.Fatalf("expected profile management mode, got %v %+v", m.mode, m.manage)
+	}
+	m = updateModel(t, m, manageLoadedMsg{profiles: []*models.Profile{{ID: "p1", Name: "alpha", Harness: models.HarnessCodex}}})
+	if !strings.Contains(m.View(), "alpha") {
+		t.Fatalf("expected profile list in view")
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.manage.Form == nil || m.manage.Form.ID != "" {
+		t.Fatalf("expected new profile form, got %+v", m.manage.Form)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.manage.Error != "name required" {
+		t.Fatalf("expected name required error, got %q", m.manage.Error)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("beta")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRight})
+	form := m.manage.Form
+	if form.value(profileFieldName) != "beta" || form.value(profileFieldHarness) != "codex" {
+		t.Fatalf("unexpected form values: %+v", form.Fields)
+	}
+	if form.value(profileFieldPromptMode) != "stdin" {
+		t.Fatalf("expected prompt mode to follow harness default, got %q", form.value(profileFieldPromptMode))
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeManage || m.manage.Form != nil {
+		t.Fatalf("expected esc to return to list, got %v %+v", m.mode, m.manage.Form)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.manage.Tab != managePools {
+		t.Fatalf("expected pools tab")
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeMain {
+		t.Fatalf("expected main mode after esc, got %v", m.mode)
+	}
+}
+
+func TestManageSaveProfileAndPool(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory db: %v", err)
+	}
+	defer database.Close()
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"alpha", "beta"} {
+		form := newProfileForm(nil)
+		form.Fields[profileFieldName].Value = name
+		if _, err := saveProfile(ctx, database, *form); err != nil {
+			t.Fatalf("save profile %s: %v", name, err)
+		}
+	}
+	profile, err := db.NewProfileRepository(database).GetByName(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("get profile: %v", err)
+	}
+	if profile.Harness != models.HarnessClaude || profile.CommandTemplate == "" || profile.MaxConcurrency != 1 {
+		t.Fatalf("expected harness defaults, got %+v", profile)
+	}
+
+	edit := newProfileForm(profile)
+	edit.Fields[profileFieldModel].Value = "sonnet"
+	if _, err := saveProfile(ctx, database, *edit); err != nil {
+		t.Fatalf("update profile: %v", err)
+	}
+
+	pool := newPoolForm(nil)
+	pool.Fields[poolFieldName].Value = "main"
+	pool.Fields[poolFieldProfiles].Value = "beta, alpha"
+	pool.Fields[poolFieldDefault].Value = "yes"
+	if _, err := savePool(ctx, database, *pool); err != nil {
+		t.Fatalf("save pool: %v", err)
+	}
+
+	profiles, pools, err := loadManageData(ctx, database)
+	if err != nil {
+		t.Fatalf("load manage data: %v", err)
+	}
+	if len(profiles) != 2 || len(pools) != 1 {
+		t.Fatalf("expected 2 profiles and 1 pool, got %d/%d", len(profiles), len(pools))
+	}
+	if !pools[0].Pool.IsDefault || strings.Join(pools[0].Members, ",") != "beta,alpha" {
+		t.Fatalf("unexpected pool view: %+v %v", pools[0].Pool, pools[0].Members)
+	}
+
+	edited := newPoolForm(&pools[0])
+	edited.Fields[poolFieldProfiles].Value = "alpha"
+	if _, err := savePool(ctx, database, *edited); err != nil {
+		t.Fatalf("update pool: %v", err)
+	}
+	_, pools, err = loadManageData(ctx, database)
+	if err != nil {
+		t.Fatalf("reload manage data: %v", err)
+	}
+	if strings.Join(pools[0].Members, ",") != "alpha" || !pools[0].Pool.IsDefault {
+		t.Fatalf("expected synced members, got %+v %v", pools[0].Pool, pools[0].Members)
+	}
+
+	if _, err := deletePool(ctx, database, pools[0].Pool.ID); err != nil {
+		t.Fatalf("delete pool: %v", err)
+	}
+	if _, err := deleteProfile(ctx, database, profile.ID); err != nil {
+		t.Fatalf("delete profile: %v", err)
+	}
+	profiles, pools, err = loadManageData(ctx, database)
+	if err != nil {
+		t.Fatalf("load after delete: %v", err)
+	}
+	if len(profiles) != 1 || len(pools) != 0 {
+		t.Fatalf("expected deletes to apply, got %d profiles %d pools", len(profiles), len(pools))
+	}
+}
+
 func TestDeleteConfirmPromptMatchesPRD(t *testing.T) {
 	running := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
 	running.loops = []loopView{
@@ -386,6 +575,45 @@ func TestFilterModeRealtimeTextAndStatus(t *testing.T) {
 	}
 }
 
+func TestFilterModeSearchesRunOutput(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	loops := []loopView{
+		testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/repo/alpha"),
+		testLoopView("id-b", "idb", "beta", models.LoopStateStopped, "/repo/beta"),
+	}
+	m.loops = loops
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !m.filterOutput {
+		t.Fatalf("expected ctrl+o to switch to run output search")
+	}
+	for _, r := range []rune("panic") {
+		m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if len(m.filtered) != 0 {
+		t.Fatalf("expected no rows before search results arrive, got %d", len(m.filtered))
+	}
+
+	m = updateModel(t, m, refreshMsg{loops: loops, outputQuery: "pan", outputMatches: map[string]string{"id-a": "stale"}})
+	if len(m.filtered) != 0 {
+		t.Fatalf("expected results for a stale query to be ignored")
+	}
+	m = updateModel(t, m, refreshMsg{loops: loops, outputQuery: "panic", outputMatches: map[string]string{"id-b": "«panic»: nil map"}})
+	if len(m.filtered) != 1 || m.filtered[0].Loop.ID != "id-b" {
+		t.Fatalf("expected output search to isolate id-b, got %d rows", len(m.filtered))
+	}
+	if bar := m.renderFilterBar(160); !strings.Contains(bar, "«panic»: nil map") {
+		t.Fatalf("expected filter bar to show the match snippet, got %q", bar)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.filterOutput || len(m.filtered) != 0 {
+		t.Fatalf("expected name filter to find no loop named panic, got %d rows", len(m.filtered))
+	}
+}
+
 func TestWizardStepValidation(t *testing.T) {
 	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
 	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
@@ -500,3 +728,121 @@ func updateModel(t *testing.T, m model, msg tea.Msg) model {
 	}
 	return updated
 }
+
+func TestRunUsageFormatting(t *testing.T) {
+	runs := []runView{
+		{Run: &models.LoopRun{ID: "a", TotalTokens: 1500, CostUSD: 0.126}},
+		{Run: &models.LoopRun{ID: "b", TotalTokens: 2_500_000}},
+		{Run: &models.LoopRun{ID: "c"}},
+	}
+	if got := formatRunUsage(runs[0].Run); got != "tok=1.5k $0.13" {
+		t.Fatalf("formatRunUsage = %q", got)
+	}
+	if got := formatRunUsage(runs[2].Run); got != "" {
+		t.Fatalf("expected empty usage for run without report, got %q", got)
+	}
+	if got := runUsageTotals(runs); got != "usage: 2/3 runs  tokens=2.5M  cost=$0.13" {
+		t.Fatalf("runUsageTotals = %q", got)
+	}
+	if got := runUsageTotals(runs[2:]); got != "" {
+		t.Fatalf("expected no totals line, got %q", got)
+	}
+}
+
+func TestRunArtifactLines(t *testing.T) {
+	run := &models.LoopRun{
+		ArtifactDir: "/data/artifacts/loops/demo/r1",
+		Artifacts: []models.LoopRunArtifact{
+			{Path: "dist/app", Size: 2048},
+			{Path: "dist/app.map", Size: 10},
+			{Path: "report.xml", Size: 3 << 20},
+		},
+	}
+	got := strings.Join(runArtifactLines(run, 2), "\n")
+	want := strings.Join([]string{
+		"artifacts: 3 files (3.0M) in /data/artifacts/loops/demo/r1",
+		"  dist/app  2.0K",
+		"  dist/app.map  10B",
+		"  ... 1 more (forge loop artifacts)",
+	}, "\n")
+	if got != want {
+		t.Fatalf("runArtifactLines =\n%s\nwant\n%s", got, want)
+	}
+	if lines := runArtifactLines(&models.LoopRun{}, 2); lines != nil {
+		t.Fatalf("expected no lines without artifacts, got %v", lines)
+	}
+}
+
+func TestOverviewShowsLedgerSummary(t *testing.T) {
+	dir := t.TempDir()
+	ledgerPath := filepath.Join(dir, "ledger.md")
+	ledger := "---\nloop_id: id-a\n---\n\n# Loop Ledger: alpha\n\n" +
+		"## 2026-01-01T10:00:00Z\n\n- run_id: r1\n- status: success\n\n" +
+		"## 2026-01-01T11:00:00Z\n\n- run_id: r2\n- status: error\n\n"
+	if err := os.WriteFile(ledgerPath, []byte(ledger), 0o644); err != nil {
+		t.Fatalf("write ledger: %v", err)
+	}
+
+	view := testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, dir)
+	view.Loop.LedgerPath = ledgerPath
+	if summary := loadLedgerSummary([]loopView{view}, "id-b"); summary != nil {
+		t.Fatalf("expected no summary for another loop, got %+v", summary)
+	}
+
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.ledger = loadLedgerSummary([]loopView{view}, "id-a")
+	pane := stripANSI(m.renderOverviewPane(view, 100, 40))
+	if !strings.Contains(pane, "entries=2 success=1 error=1 killed=0") || !strings.Contains(pane, "last=#2 ERROR at 2026-01-01T11:00:00Z") {
+		t.Fatalf("expected ledger summary in overview, got:\n%s", pane)
+	}
+}
+
+func TestDeleteConfirmPreviewsCascade(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory db: %v", err)
+	}
+	defer database.Close()
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	loopEntry := &models.Loop{Name: "gamma", RepoPath: t.TempDir(), IntervalSeconds: 10, State: models.LoopStateStopped}
+	if err := db.NewLoopRepository(database).Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	logPath := loop.LogPath(dataDir, loopEntry.Name, loopEntry.ID)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(logPath, []byte("hello log"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	m := newModel(database, Config{RefreshInterval: time.Second, LogLines: 8, DataDir: dataDir})
+	m.loops = []loopView{{Loop: loopEntry}}
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	if m.confirm == nil || m.confirm.Plan == nil || len(m.confirm.Plan.Files) != 1 {
+		t.Fatalf("expected delete plan with the log file, got %+v", m.confirm)
+	}
+	if dialog := m.renderConfirmDialog(100); !strings.Contains(dialog, "Press c to also delete 1 file(s), reclaiming 9 B") {
+		t.Fatalf("expected cascade preview in dialog, got:\n%s", dialog)
+	}
+
+	next, cmd := m.updateConfirmMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = next.(model)
+	if m.mode != modeMain || cmd == nil {
+		t.Fatalf("expected cascade delete to start")
+	}
+	result, ok := cmd().(actionResultMsg)
+	if !ok || result.Err != nil || !strings.Contains(result.Message, "1 file(s), 9 B reclaimed") {
+		t.Fatalf("unexpected delete result: %+v", result)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("expected log to be removed, stat err=%v", err)
+	}
+}
@@ -0,0 +1,617 @@
+package looptui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/harness"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+type manageTab int
+
+const (
+	manageProfiles manageTab = iota
+	managePools
+)
+
+// Profile form field indexes.
+const (
+	profileFieldName = iota
+	profileFieldHarness
+	profileFieldAuthKind
+	profileFieldAuthHome
+	profileFieldModel
+	profileFieldPromptMode
+	profileFieldCommand
+	profileFieldMaxConcurrency
+)
+
+// Pool form field indexes.
+const (
+	poolFieldName = iota
+	poolFieldStrategy
+	poolFieldProfiles
+	poolFieldDefault
+)
+
+var (
+	harnessOptions      = []string{string(models.HarnessClaude), string(models.HarnessCodex), string(models.HarnessOpenCode), string(models.HarnessPi), string(models.HarnessDroid)}
+	promptModeOptions   = []string{string(models.PromptModeEnv), string(models.PromptModeStdin), string(models.PromptModePath)}
+	poolStrategyOptions = []string{string(models.PoolStrategyRoundRobin), string(models.PoolStrategyLRU)}
+	yesNoOptions        = []string{"no", "yes"}
+)
+
+// manageState backs the profile/pool management modal. Form is nil while the
+// list is shown.
+type manageState struct {
+	Tab           manageTab
+	Profiles      []*models.Profile
+	Pools         []managePoolView
+	Selected      int
+	Form          *manageForm
+	ConfirmDelete bool
+	Error         string
+}
+
+type managePoolView struct {
+	Pool    *models.Pool
+	Members []string
+}
+
+// manageForm edits one profile or pool. ID is empty when creating.
+type manageForm struct {
+	Tab    manageTab
+	ID     string
+	Field  int
+	Fields []manageField
+}
+
+// manageField is a text field, or a choice field when Options is set.
+type manageField struct {
+	Label   string
+	Value   string
+	Options []string
+}
+
+type manageLoadedMsg struct {
+	profiles []*models.Profile
+	pools    []managePoolView
+	err      error
+}
+
+func (f manageForm) value(idx int) string {
+	if idx < 0 || idx >= len(f.Fields) {
+		return ""
+	}
+	return strings.TrimSpace(f.Fields[idx].Value)
+}
+
+func newProfileForm(profile *models.Profile) *manageForm {
+	form := &manageForm{Tab: manageProfiles, Fields: []manageField{
+		{Label: "name"},
+		{Label: "harness", Value: string(models.HarnessClaude), Options: harnessOptions},
+		{Label: "auth kind"},
+		{Label: "auth home"},
+		{Label: "model"},
+		{Label: "prompt mode", Options: promptModeOptions},
+		{Label: "command (empty = harness default)"},
+		{Label: "max concurrency", Value: "1"},
+	}}
+	if profile == nil {
+		form.Fields[profileFieldPromptMode].Value = string(harness.DefaultPromptMode(models.HarnessClaude))
+		return form
+	}
+	form.ID = profile.ID
+	form.Fields[profileFieldName].Value = profile.Name
+	if profile.Harness != "" {
+		form.Fields[profileFieldHarness].Value = string(profile.Harness)
+	}
+	form.Fields[profileFieldAuthKind].Value = profile.AuthKind
+	form.Fields[profileFieldAuthHome].Value = profile.AuthHome
+	form.Fields[profileFieldModel].Value = profile.Model
+	form.Fields[profileFieldPromptMode].Value = string(profile.PromptMode)
+	if profile.PromptMode == "" {
+		form.Fields[profileFieldPromptMode].Value = string(harness.DefaultPromptMode(profile.Harness))
+	}
+	form.Fields[profileFieldCommand].Value = profile.CommandTemplate
+	form.Fields[profileFieldMaxConcurrency].Value = strconv.Itoa(profile.MaxConcurrency)
+	return form
+}
+
+func newPoolForm(view *managePoolView) *manageForm {
+	form := &manageForm{Tab: managePools, Fields: []manageField{
+		{Label: "name"},
+		{Label: "strategy", Value: string(models.PoolStrategyRoundRobin), Options: poolStrategyOptions},
+		{Label: "profiles (comma-separated names or ids)"},
+		{Label: "default", Value: "no", Options: yesNoOptions},
+	}}
+	if view == nil || view.Pool == nil {
+		return form
+	}
+	form.ID = view.Pool.ID
+	form.Fields[poolFieldName].Value = view.Pool.Name
+	if view.Pool.Strategy != "" {
+		form.Fields[poolFieldStrategy].Value = string(view.Pool.Strategy)
+	}
+	form.Fields[poolFieldProfiles].Value = strings.Join(view.Members, ", ")
+	if view.Pool.IsDefault {
+		form.Fields[poolFieldDefault].Value = "yes"
+	}
+	return form
+}
+
+func (m model) openManage(tab manageTab) (tea.Model, tea.Cmd) {
+	m.mode = modeManage
+	m.manage = manageState{Tab: tab}
+	return m, m.manageLoadCmd()
+}
+
+func (m model) manageLoadCmd() tea.Cmd {
+	database := m.db
+	if database == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		profiles, pools, err := loadManageData(ctx, database)
+		return manageLoadedMsg{profiles: profiles, pools: pools, err: err}
+	}
+}
+
+func (m model) applyManageLoaded(msg manageLoadedMsg) model {
+	if msg.err != nil {
+		m.manage.Error = msg.err.Error()
+		return m
+	}
+	m.manage.Profiles = msg.profiles
+	m.manage.Pools = msg.pools
+	m.manage.Selected = clampManageIndex(m.manage.Selected, m.manageCount())
+	return m
+}
+
+func (m model) manageCount() int {
+	if m.manage.Tab == managePools {
+		return len(m.manage.Pools)
+	}
+	return len(m.manage.Profiles)
+}
+
+func (m model) updateManageMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.manage.Form != nil {
+		return m.updateManageForm(msg)
+	}
+
+	if m.manage.ConfirmDelete {
+		m.manage.ConfirmDelete = false
+		if msg.String() != "y" && msg.String() != "Y" {
+			return m, nil
+		}
+		form := manageForm{Tab: m.manage.Tab}
+		kind := actionDeleteProfile
+		if m.manage.Tab == managePools {
+			kind = actionDeletePool
+			form.ID = m.manage.Pools[m.manage.Selected].Pool.ID
+		} else {
+			form.ID = m.manage.Profiles[m.manage.Selected].ID
+		}
+		m.manage.Error = ""
+		return m.runAction(actionRequest{Kind: kind, Manage: form})
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeMain
+		m.manage = manageState{}
+		return m, nil
+	case "tab", "shift+tab", "]", "[":
+		if m.manage.Tab == manageProfiles {
+			m.manage.Tab = managePools
+		} else {
+			m.manage.Tab = manageProfiles
+		}
+		m.manage.Selected = 0
+		m.manage.Error = ""
+		return m, nil
+	case "j", "down":
+		m.manage.Selected = clampManageIndex(m.manage.Selected+1, m.manageCount())
+		return m, nil
+	case "k", "up":
+		m.manage.Selected = clampManageIndex(m.manage.Selected-1, m.manageCount())
+		return m, nil
+	case "n":
+		if m.manage.Tab == managePools {
+			m.manage.Form = newPoolForm(nil)
+		} else {
+			m.manage.Form = newProfileForm(nil)
+		}
+		m.manage.Error = ""
+		return m, nil
+	case "e", "enter":
+		if m.manageCount() == 0 {
+			return m, nil
+		}
+		if m.manage.Tab == managePools {
+			m.manage.Form = newPoolForm(&m.manage.Pools[m.manage.Selected])
+		} else {
+			m.manage.Form = newProfileForm(m.manage.Profiles[m.manage.Selected])
+		}
+		m.manage.Error = ""
+		return m, nil
+	case "D":
+		if m.manageCount() == 0 {
+			return m, nil
+		}
+		m.manage.ConfirmDelete = true
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m model) updateManageForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := m.manage.Form
+	field := &form.Fields[form.Field]
+	switch msg.String() {
+	case "esc":
+		m.manage.Form = nil
+		m.manage.Error = ""
+		return m, nil
+	case "tab", "down":
+		form.Field = (form.Field + 1) % len(form.Fields)
+		return m, nil
+	case "shift+tab", "up":
+		form.Field = (form.Field + len(form.Fields) - 1) % len(form.Fields)
+		return m, nil
+	case "right", " ", "space":
+		if len(field.Options) > 0 {
+			m.cycleManageOption(1)
+		} else if msg.String() != "right" {
+			field.Value += " "
+		}
+		return m, nil
+	case "left":
+		if len(field.Options) > 0 {
+			m.cycleManageOption(-1)
+		}
+		return m, nil
+	case "enter":
+		kind, err := validateManageForm(*form)
+		if err != nil {
+			m.manage.Error = err.Error()
+			return m, nil
+		}
+		m.manage.Error = ""
+		return m.runAction(actionRequest{Kind: kind, Manage: *form})
+	case "backspace", "ctrl+h", "delete":
+		if len(field.Options) == 0 {
+			field.Value = removeLastRune(field.Value)
+		}
+		return m, nil
+	default:
+		if len(field.Options) == 0 && len(msg.Runes) > 0 {
+			field.Value += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+// cycleManageOption steps the focused choice field. Changing the harness of
+// a new profile also resets its prompt mode to the harness default.
+func (m model) cycleManageOption(step int) {
+	form := m.manage.Form
+	field := &form.Fields[form.Field]
+	idx := 0
+	for i, option := range field.Options {
+		if option == field.Value {
+			idx = i
+			break
+		}
+	}
+	idx = (idx + step + len(field.Options)) % len(field.Options)
+	field.Value = field.Options[idx]
+
+	if form.Tab == manageProfiles && form.Field == profileFieldHarness && form.ID == "" {
+		form.Fields[profileFieldPromptMode].Value = string(harness.DefaultPromptMode(models.Harness(field.Value)))
+	}
+}
+
+func validateManageForm(form manageForm) (actionType, error) {
+	if form.value(0) == "" {
+		return actionNone, errors.New("name required")
+	}
+	if form.Tab == managePools {
+		return actionSavePool, nil
+	}
+	if _, err := parseMaxConcurrency(form.value(profileFieldMaxConcurrency)); err != nil {
+		return actionNone, err
+	}
+	return actionSaveProfile, nil
+}
+
+func parseMaxConcurrency(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid max concurrency %q", value)
+	}
+	return n, nil
+}
+
+func isManageAction(kind actionType) bool {
+	switch kind {
+	case actionSaveProfile, actionDeleteProfile, actionSavePool, actionDeletePool:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m model) renderManageDialog(width int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.palette.Accent)).
+		Background(lipgloss.Color(m.palette.PanelAlt)).
+		Padding(0, 1).
+		Width(maxInt(40, width))
+
+	tabs := []string{"Profiles", "Pools"}
+	for i := range tabs {
+		if manageTab(i) == m.manage.Tab {
+			tabs[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Focus)).Bold(true).Render("[" + tabs[i] + "]")
+		}
+	}
+	content := []string{"Profiles and pools  " + strings.Join(tabs, " "), ""}
+
+	if form := m.manage.Form; form != nil {
+		title := "New profile"
+		if form.Tab == managePools {
+			title = "New pool"
+		}
+		if form.ID != "" {
+			title = "Edit " + strings.ToLower(strings.TrimPrefix(title, "New "))
+		}
+		content = append(content, title)
+		for i, field := range form.Fields {
+			label := field.Label
+			if len(field.Options) > 0 {
+				label += " (" + strings.Join(field.Options, "|") + ")"
+			}
+			content = append(content, renderWizardField(m.palette, label, field.Value, form.Field == i))
+		}
+		content = append(content, "", "tab/down/up navigate fields, left/right/space cycle choices, enter saves, esc back")
+	} else {
+		content = append(content, m.renderManageList()...)
+		content = append(content, "")
+		if m.manage.ConfirmDelete {
+			content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Warning)).Render("Delete selected "+m.manageNoun()+"? [y/N]"))
+		} else {
+			content = append(content, "tab switch list, j/k move, n new, e/enter edit, D delete, esc close")
+		}
+	}
+	if m.manage.Error != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.manage.Error))
+	}
+
+	for i := range content {
+		content[i] = truncateLine(content[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
+
+func (m model) renderManageList() []string {
+	if m.manageCount() == 0 {
+		return []string{fmt.Sprintf("No %ss. Press n to create one.", m.manageNoun())}
+	}
+	lines := make([]string, 0, m.manageCount())
+	for i := 0; i < m.manageCount(); i++ {
+		var line string
+		if m.manage.Tab == managePools {
+			view := m.manage.Pools[i]
+			line = fmt.Sprintf("%-20s %-12s default=%s members=%s", view.Pool.Name, view.Pool.Strategy, formatYesNo(view.Pool.IsDefault), strings.Join(view.Members, ","))
+		} else {
+			profile := m.manage.Profiles[i]
+			line = fmt.Sprintf("%-20s %-9s auth=%-10s model=%s", profile.Name, profile.Harness, displayOrDash(profile.AuthKind), displayOrDash(profile.Model))
+		}
+		if i == m.manage.Selected {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Focus)).Bold(true).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func clampManageIndex(idx, count int) int {
+	return maxInt(0, minInt(idx, count-1))
+}
+
+func (m model) manageNoun() string {
+	if m.manage.Tab == managePools {
+		return "pool"
+	}
+	return "profile"
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func displayOrDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
+
+func loadManageData(ctx context.Context, database *db.DB) ([]*models.Profile, []managePoolView, error) {
+	profileRepo := db.NewProfileRepository(database)
+	poolRepo := db.NewPoolRepository(database)
+
+	profiles, err := profileRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]string, len(profiles))
+	for _, profile := range profiles {
+		names[profile.ID] = profile.Name
+	}
+
+	pools, err := poolRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	views := make([]managePoolView, 0, len(pools))
+	for _, pool := range pools {
+		members, err := poolRepo.ListMembers(ctx, pool.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		view := managePoolView{Pool: pool, Members: make([]string, 0, len(members))}
+		for _, member := range members {
+			if name, ok := names[member.ProfileID]; ok {
+				view.Members = append(view.Members, name)
+			}
+		}
+		views = append(views, view)
+	}
+	return profiles, views, nil
+}
+
+func saveProfile(ctx context.Context, database *db.DB, form manageForm) (string, error) {
+	repo := db.NewProfileRepository(database)
+	profile := &models.Profile{}
+	if form.ID != "" {
+		existing, err := repo.Get(ctx, form.ID)
+		if err != nil {
+			return "", err
+		}
+		profile = existing
+	}
+
+	maxConcurrency, err := parseMaxConcurrency(form.value(profileFieldMaxConcurrency))
+	if err != nil {
+		return "", err
+	}
+	profile.Name = form.value(profileFieldName)
+	profile.Harness = models.Harness(form.value(profileFieldHarness))
+	profile.AuthKind = form.value(profileFieldAuthKind)
+	profile.AuthHome = form.value(profileFieldAuthHome)
+	profile.Model = form.value(profileFieldModel)
+	profile.PromptMode = models.PromptMode(form.value(profileFieldPromptMode))
+	profile.CommandTemplate = form.value(profileFieldCommand)
+	if profile.CommandTemplate == "" {
+		profile.CommandTemplate = harness.DefaultCommandTemplate(profile.Harness, profile.Model)
+	}
+	profile.MaxConcurrency = maxConcurrency
+
+	if form.ID == "" {
+		if err := repo.Create(ctx, profile); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Profile %q created", profile.Name), nil
+	}
+	if err := repo.Update(ctx, profile); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Profile %q updated", profile.Name), nil
+}
+
+func deleteProfile(ctx context.Context, database *db.DB, id string) (string, error) {
+	repo := db.NewProfileRepository(database)
+	profile, err := repo.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := repo.Delete(ctx, profile.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Profile %q deleted", profile.Name), nil
+}
+
+// savePool creates or updates a pool and syncs its members to the form's
+// profile list, keeping the listed order.
+func savePool(ctx context.Context, database *db.DB, form manageForm) (string, error) {
+	poolRepo := db.NewPoolRepository(database)
+	profileRepo := db.NewProfileRepository(database)
+
+	members := make([]*models.Profile, 0)
+	for _, ref := range parseTags(form.value(poolFieldProfiles)) {
+		profile, err := resolveProfileByRef(ctx, profileRepo, ref)
+		if err != nil {
+			return "", fmt.Errorf("profile %q: %w", ref, err)
+		}
+		members = append(members, profile)
+	}
+
+	pool := &models.Pool{}
+	if form.ID != "" {
+		existing, err := poolRepo.Get(ctx, form.ID)
+		if err != nil {
+			return "", err
+		}
+		pool = existing
+	}
+	makeDefault := form.value(poolFieldDefault) == "yes"
+	pool.Name = form.value(poolFieldName)
+	pool.Strategy = models.PoolStrategy(form.value(poolFieldStrategy))
+	pool.IsDefault = pool.IsDefault && makeDefault
+
+	verb := "updated"
+	if form.ID == "" {
+		verb = "created"
+		if err := poolRepo.Create(ctx, pool); err != nil {
+			return "", err
+		}
+	} else if err := poolRepo.Update(ctx, pool); err != nil {
+		return "", err
+	}
+
+	existing, err := poolRepo.ListMembers(ctx, pool.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, member := range existing {
+		if err := poolRepo.RemoveMember(ctx, pool.ID, member.ProfileID); err != nil {
+			return "", err
+		}
+	}
+	for i, profile := range members {
+		member := &models.PoolMember{PoolID: pool.ID, ProfileID: profile.ID, Position: i + 1}
+		if err := poolRepo.AddMember(ctx, member); err != nil {
+			return "", err
+		}
+	}
+
+	if makeDefault && !pool.IsDefault {
+		if err := poolRepo.SetDefault(ctx, pool.ID); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("Pool %q %s (%d profiles)", pool.Name, verb, len(members)), nil
+}
+
+func deletePool(ctx context.Context, database *db.DB, id string) (string, error) {
+	repo := db.NewPoolRepository(database)
+	pool, err := repo.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := repo.Delete(ctx, pool.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Pool %q deleted", pool.Name), nil
+}
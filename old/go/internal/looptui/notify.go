@@ -0,0 +1,145 @@
+package looptui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+const loopEventPollLimit = 50
+
+// loopEventBatch is the slice of the event log read during one refresh.
+type loopEventBatch struct {
+	// from is the cursor the batch was read after; stale batches are dropped.
+	from   string
+	cursor string
+	events []*models.Event
+}
+
+// loadLoopEvents reads loop events recorded after cursor, or at or after
+// since when no event has been seen yet.
+func loadLoopEvents(ctx context.Context, database *db.DB, cursor string, since time.Time) loopEventBatch {
+	batch := loopEventBatch{from: cursor, cursor: cursor}
+	if database == nil {
+		return batch
+	}
+	entityType := models.EntityTypeLoop
+	query := db.EventQuery{EntityType: &entityType, Cursor: cursor, Limit: loopEventPollLimit}
+	if cursor == "" && !since.IsZero() {
+		query.Since = &since
+	}
+	page, err := db.NewEventRepository(database).Query(ctx, query)
+	if err != nil || len(page.Events) == 0 {
+		return batch
+	}
+	batch.events = page.Events
+	batch.cursor = page.Events[len(page.Events)-1].ID
+	return batch
+}
+
+// notifyLoopEvents surfaces events for visible loops that are neither
+// selected nor pinned as a transient status toast. Failures win over other
+// events; the rest are summarized as a count.
+func (m *model) notifyLoopEvents(batch loopEventBatch) {
+	if batch.from != m.eventCursor {
+		return
+	}
+	m.eventCursor = batch.cursor
+
+	visible := make(map[string]string, len(m.filtered))
+	for _, view := range m.filtered {
+		if view.Loop == nil || view.Loop.ID == m.selectedID {
+			continue
+		}
+		if _, pinned := m.pinned[view.Loop.ID]; pinned {
+			continue
+		}
+		visible[view.Loop.ID] = view.Loop.Name
+	}
+
+	var (
+		toast     string
+		toastKind statusKind
+		count     int
+	)
+	for _, event := range batch.events {
+		name, ok := visible[event.EntityID]
+		if !ok {
+			continue
+		}
+		text, kind, ok := describeLoopEvent(event, name)
+		if !ok {
+			continue
+		}
+		count++
+		if toast == "" || kind == statusErr || toastKind != statusErr {
+			toast, toastKind = text, kind
+		}
+	}
+	if count == 0 {
+		return
+	}
+	if count > 1 {
+		toast = fmt.Sprintf("%s (+%d more)", toast, count-1)
+	}
+	m.setStatus(toastKind, toast)
+}
+
+// describeLoopEvent renders a loop event as a one-line toast.
+func describeLoopEvent(event *models.Event, name string) (string, statusKind, bool) {
+	var payload models.LoopEventPayload
+	if len(event.Payload) > 0 {
+		_ = json.Unmarshal(event.Payload, &payload)
+	}
+	if strings.TrimSpace(payload.LoopName) != "" {
+		name = payload.LoopName
+	}
+
+	switch event.Type {
+	case models.EventTypeLoopRunFailed:
+		text := name + ": run failed"
+		if payload.ExitCode != nil {
+			text += fmt.Sprintf(" (exit %d)", *payload.ExitCode)
+		}
+		if payload.Error != "" {
+			text += ": " + firstLine(payload.Error)
+		}
+		return text, statusErr, true
+	case models.EventTypeLoopStateChanged:
+		if payload.State == models.LoopStateError {
+			text := name + ": error"
+			if payload.Error != "" {
+				text += ": " + firstLine(payload.Error)
+			}
+			return text, statusErr, true
+		}
+		if payload.State == "" {
+			return "", statusInfo, false
+		}
+		return fmt.Sprintf("%s: %s", name, payload.State), statusInfo, true
+	case models.EventTypeLoopPaused:
+		text := name + ": paused"
+		if payload.Until != nil {
+			text += " until " + payload.Until.Local().Format("15:04:05")
+		}
+		if payload.Reason != "" {
+			text += " (" + firstLine(payload.Reason) + ")"
+		}
+		return text, statusInfo, true
+	default:
+		return "", statusInfo, false
+	}
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if idx := strings.IndexByte(text, '\n'); idx >= 0 {
+		text = strings.TrimSpace(text[:idx])
+	}
+	return text
+}
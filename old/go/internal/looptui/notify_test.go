@@ -0,0 +1,100 @@
+package looptui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func loopEvent(t *testing.T, eventType models.EventType, loopID string, payload models.LoopEventPayload) *models.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return &models.Event{Type: eventType, EntityType: models.EntityTypeLoop, EntityID: loopID, Payload: data}
+}
+
+func TestNotifyLoopEventsSkipsSelectedAndPinned(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	m.filtered = []loopView{
+		{Loop: &models.Loop{ID: "sel", Name: "selected"}},
+		{Loop: &models.Loop{ID: "pin", Name: "pinned"}},
+		{Loop: &models.Loop{ID: "bg", Name: "background"}},
+	}
+	m.selectedID = "sel"
+	m.pinned["pin"] = struct{}{}
+
+	exit := 2
+	m.notifyLoopEvents(loopEventBatch{cursor: "e3", events: []*models.Event{
+		loopEvent(t, models.EventTypeLoopRunFailed, "sel", models.LoopEventPayload{}),
+		loopEvent(t, models.EventTypeLoopRunFailed, "pin", models.LoopEventPayload{}),
+		loopEvent(t, models.EventTypeLoopRunFailed, "bg", models.LoopEventPayload{ExitCode: &exit, Error: "boom\ntrace"}),
+		loopEvent(t, models.EventTypeLoopStateChanged, "bg", models.LoopEventPayload{State: models.LoopStateStopped}),
+		loopEvent(t, models.EventTypeLoopRunFailed, "hidden", models.LoopEventPayload{}),
+	}})
+
+	if m.statusKind != statusErr || m.statusText != "background: run failed (exit 2): boom (+1 more)" {
+		t.Fatalf("unexpected toast %q (kind %v)", m.statusText, m.statusKind)
+	}
+	if m.eventCursor != "e3" {
+		t.Fatalf("expected cursor to advance, got %q", m.eventCursor)
+	}
+
+	// A batch read from an older cursor is a duplicate and is ignored.
+	m.statusText = ""
+	m.notifyLoopEvents(loopEventBatch{cursor: "e9", events: []*models.Event{
+		loopEvent(t, models.EventTypeLoopPaused, "bg", models.LoopEventPayload{Reason: "disk"}),
+	}})
+	if m.statusText != "" || m.eventCursor != "e3" {
+		t.Fatalf("expected stale batch to be dropped, got %q cursor %q", m.statusText, m.eventCursor)
+	}
+}
+
+func TestLoadLoopEventsFollowsCursor(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory db: %v", err)
+	}
+	defer database.Close()
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repo := db.NewEventRepository(database)
+	old := loopEvent(t, models.EventTypeLoopRunFailed, "l1", models.LoopEventPayload{})
+	old.Timestamp = time.Now().UTC().Add(-time.Hour)
+	if err := repo.Create(ctx, old); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	since := time.Now().UTC().Add(-time.Minute)
+	if err := repo.Create(ctx, loopEvent(t, models.EventTypeLoopPaused, "l1", models.LoopEventPayload{})); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+
+	batch := loadLoopEvents(ctx, database, "", since)
+	if len(batch.events) != 1 || batch.events[0].Type != models.EventTypeLoopPaused {
+		t.Fatalf("expected only events since startup, got %+v", batch.events)
+	}
+	if again := loadLoopEvents(ctx, database, batch.cursor, since); len(again.events) != 0 || again.cursor != batch.cursor {
+		t.Fatalf("expected no new events after cursor, got %+v", again)
+	}
+}
+
+func TestDescribeLoopEventPaused(t *testing.T) {
+	until := time.Date(2026, 1, 1, 12, 30, 0, 0, time.Local)
+	text, kind, ok := describeLoopEvent(loopEvent(t, models.EventTypeLoopPaused, "l1", models.LoopEventPayload{
+		LoopName: "alpha",
+		Reason:   "waiting on review",
+		Until:    &until,
+	}), "")
+	if !ok || kind != statusInfo || !strings.Contains(text, "alpha: paused until 12:30:00 (waiting on review)") {
+		t.Fatalf("unexpected description %q", text)
+	}
+}
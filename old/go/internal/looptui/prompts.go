@@ -0,0 +1,44 @@
+package looptui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// loopPromptLabel describes the loop's prompt for the overview: the active
+// playlist entry with its position, the base prompt file, or "inline".
+func loopPromptLabel(loopEntry *models.Loop) string {
+	if playlist, ok := loop.LoadPromptPlaylist(loopEntry); ok {
+		count := len(playlist.Prompts)
+		index, ok := loop.ActivePlaylistIndex(loopEntry)
+		if !ok || index < 0 || index >= count {
+			return fmt.Sprintf("playlist of %d (%s), not started", count, playlist.NormalizedStrategy())
+		}
+		return fmt.Sprintf("%s [%d/%d %s]", filepath.Base(playlist.Prompts[index]), index+1, count, playlist.NormalizedStrategy())
+	}
+	switch {
+	case strings.TrimSpace(loopEntry.BasePromptMsg) != "":
+		return "inline message"
+	case strings.TrimSpace(loopEntry.BasePromptPath) != "":
+		return filepath.Base(loopEntry.BasePromptPath)
+	default:
+		return "repo default"
+	}
+}
+
+// runPromptLabel names the prompt file of a run that came from a playlist
+// or an override, and is empty for base-prompt runs.
+func runPromptLabel(run *models.LoopRun) string {
+	if run == nil || strings.TrimSpace(run.PromptPath) == "" || run.PromptSource == "base" {
+		return ""
+	}
+	label := filepath.Base(run.PromptPath)
+	if index, ok := run.Metadata["prompt_playlist_index"]; ok {
+		label += fmt.Sprintf("#%v", index)
+	}
+	return label
+}
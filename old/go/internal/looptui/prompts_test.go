@@ -0,0 +1,31 @@
+package looptui
+
+import (
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestLoopPromptLabelShowsActivePlaylistEntry(t *testing.T) {
+	loopEntry := &models.Loop{Metadata: map[string]any{
+		"prompt_playlist": map[string]any{"prompts": []any{"/repo/plan.md", "/repo/build.md"}, "strategy": "random"},
+	}}
+	if got := loopPromptLabel(loopEntry); got != "playlist of 2 (random), not started" {
+		t.Fatalf("unexpected label before first iteration: %q", got)
+	}
+	loopEntry.Metadata["prompt_playlist_index"] = float64(1)
+	if got := loopPromptLabel(loopEntry); got != "build.md [2/2 random]" {
+		t.Fatalf("unexpected active label: %q", got)
+	}
+	if got := loopPromptLabel(&models.Loop{BasePromptPath: "/repo/PROMPT.md"}); got != "PROMPT.md" {
+		t.Fatalf("unexpected base label: %q", got)
+	}
+
+	run := &models.LoopRun{PromptSource: "playlist", PromptPath: "/repo/plan.md", Metadata: map[string]any{"prompt_playlist_index": float64(1)}}
+	if got := runPromptLabel(run); got != "plan.md#1" {
+		t.Fatalf("unexpected run label: %q", got)
+	}
+	if got := runPromptLabel(&models.LoopRun{PromptSource: "base", PromptPath: "/repo/PROMPT.md"}); got != "" {
+		t.Fatalf("expected no label for base prompt runs, got %q", got)
+	}
+}
@@ -0,0 +1,322 @@
+package looptui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Item kinds offered by the queue add dialog.
+const (
+	queueAddPrompt  = "prompt"
+	queueAddMessage = "message"
+)
+
+// queueAddState backs the inline "add queue item" dialog. Kind is prompt
+// (next-iteration prompt override) or message (appended to the prompt).
+type queueAddState struct {
+	LoopID string
+	Field  int
+	Text   string
+	Kind   string
+	Error  string
+}
+
+// loadQueueItems returns a loop's pending queue items in dispatch order.
+func loadQueueItems(ctx context.Context, database *db.DB, loopID string) ([]*models.LoopQueueItem, error) {
+	if database == nil || strings.TrimSpace(loopID) == "" {
+		return nil, nil
+	}
+	items, err := db.NewLoopQueueRepository(database).List(ctx, loopID)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]*models.LoopQueueItem, 0, len(items))
+	for _, item := range items {
+		if item != nil && item.Status == models.LoopQueueStatusPending {
+			pending = append(pending, item)
+		}
+	}
+	return pending, nil
+}
+
+func (m *model) moveQueueSelection(delta int) {
+	if len(m.queueItems) == 0 {
+		m.selectedQueue = 0
+		return
+	}
+	m.selectedQueue += delta
+	if m.selectedQueue < 0 {
+		m.selectedQueue = 0
+	}
+	if m.selectedQueue >= len(m.queueItems) {
+		m.selectedQueue = len(m.queueItems) - 1
+	}
+}
+
+func (m model) selectedQueueItem() (*models.LoopQueueItem, bool) {
+	if m.selectedQueue < 0 || m.selectedQueue >= len(m.queueItems) {
+		return nil, false
+	}
+	return m.queueItems[m.selectedQueue], true
+}
+
+// queueMoveCmd reorders the selected item by delta. The cursor follows the
+// item so repeated presses keep moving it.
+func (m model) queueMoveCmd(delta int) (tea.Model, tea.Cmd) {
+	view, ok := m.selectedView()
+	if !ok {
+		m.setStatus(statusInfo, "No loop selected")
+		return m, nil
+	}
+	item, ok := m.selectedQueueItem()
+	if !ok {
+		m.setStatus(statusInfo, "Queue is empty")
+		return m, nil
+	}
+	target := m.selectedQueue + delta
+	if target < 0 || target >= len(m.queueItems) {
+		return m, nil
+	}
+	if m.actionBusy {
+		m.setStatus(statusInfo, "Another action is still running")
+		return m, nil
+	}
+	m.selectedQueue = target
+	return m.runAction(actionRequest{Kind: actionQueueMove, LoopID: view.Loop.ID, QueueItemID: item.ID, QueueDelta: delta})
+}
+
+func (m model) updateQueueAddMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.queueAdd.Text
+	if m.queueAdd.Field == 1 {
+		field = &m.queueAdd.Kind
+	}
+	switch msg.String() {
+	case "esc":
+		m.mode = modeMain
+		m.queueAdd = queueAddState{}
+		return m, nil
+	case "tab", "shift+tab", "down", "up":
+		m.queueAdd.Field = 1 - m.queueAdd.Field
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(m.queueAdd.Text) == "" {
+			m.queueAdd.Error = "text required"
+			return m, nil
+		}
+		kind := strings.ToLower(strings.TrimSpace(m.queueAdd.Kind))
+		if kind != queueAddPrompt && kind != queueAddMessage {
+			m.queueAdd.Error = fmt.Sprintf("invalid kind %q (prompt|message)", m.queueAdd.Kind)
+			return m, nil
+		}
+		m.mode = modeMain
+		m.queueAdd.Error = ""
+		return m.runAction(actionRequest{Kind: actionQueueAdd, LoopID: m.queueAdd.LoopID, Message: m.queueAdd.Text, QueueKind: kind})
+	case "backspace", "ctrl+h", "delete":
+		*field = removeLastRune(*field)
+		return m, nil
+	case "space":
+		*field += " "
+		return m, nil
+	default:
+		if len(msg.Runes) > 0 {
+			*field += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+func (m model) renderQueuePane(view loopView, width, height int) string {
+	contentWidth := maxInt(1, width-2)
+	k := m.keys
+	content := []string{
+		fmt.Sprintf("Queue: %s  pending=%d", loopDisplayID(view.Loop), len(m.queueItems)),
+		truncateLine(fmt.Sprintf(",/. select | %s/%s move | %s add | %s remove", k.label(keyQueueMoveUp), k.label(keyQueueMoveDown), k.label(keyQueueAdd), k.label(keyQueueRemove)), contentWidth),
+		"",
+	}
+	if len(m.queueItems) == 0 {
+		content = append(content, "Queue is empty.")
+		content = append(content, fmt.Sprintf("Press %s to queue a prompt or message.", k.label(keyQueueAdd)))
+		return strings.Join(content, "\n")
+	}
+
+	available := maxInt(1, height-len(content)-1)
+	start := 0
+	if len(m.queueItems) > available {
+		start = m.selectedQueue - available/2
+		if start < 0 {
+			start = 0
+		}
+		if start > len(m.queueItems)-available {
+			start = len(m.queueItems) - available
+		}
+	}
+	end := minInt(len(m.queueItems), start+available)
+	for i := start; i < end; i++ {
+		item := m.queueItems[i]
+		prefix := "  "
+		if i == m.selectedQueue {
+			prefix = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Focus)).Bold(true).Render("> ")
+		}
+		label := fmt.Sprintf("%2d %-20s", i+1, item.Type)
+		if item.NotBefore != nil {
+			label += " at " + item.NotBefore.Local().Format("15:04")
+		}
+		if summary := queueItemSummary(item); summary != "" {
+			label += " " + summary
+		}
+		content = append(content, prefix+truncateLine(label, contentWidth-2))
+	}
+	if end < len(m.queueItems) {
+		content = append(content, truncateLine(fmt.Sprintf("... %d more", len(m.queueItems)-end), contentWidth))
+	}
+	return strings.Join(content, "\n")
+}
+
+func (m model) renderQueueAddDialog(width int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.palette.Accent)).
+		Background(lipgloss.Color(m.palette.PanelAlt)).
+		Padding(0, 1).
+		Width(maxInt(40, width))
+
+	content := []string{
+		"Add queue item",
+		renderWizardField(m.palette, "text", m.queueAdd.Text, m.queueAdd.Field == 0),
+		renderWizardField(m.palette, "kind (prompt = override next prompt, message = append)", m.queueAdd.Kind, m.queueAdd.Field == 1),
+		"tab switches field, enter appends to queue, esc cancels",
+	}
+	if m.queueAdd.Error != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.queueAdd.Error))
+	}
+	for i := range content {
+		content[i] = truncateLine(content[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
+
+// queueItemSummary renders the payload field an operator recognizes an
+// item by.
+func queueItemSummary(item *models.LoopQueueItem) string {
+	var text string
+	switch item.Type {
+	case models.LoopQueueItemMessageAppend:
+		var payload models.MessageAppendPayload
+		_ = json.Unmarshal(item.Payload, &payload)
+		text = payload.Text
+	case models.LoopQueueItemNextPromptOverride:
+		var payload models.NextPromptOverridePayload
+		_ = json.Unmarshal(item.Payload, &payload)
+		text = payload.Prompt
+		if payload.IsPath {
+			text = "file:" + text
+		}
+	case models.LoopQueueItemSteerMessage:
+		var payload models.SteerPayload
+		_ = json.Unmarshal(item.Payload, &payload)
+		text = payload.Message
+	case models.LoopQueueItemPause:
+		var payload models.LoopPausePayload
+		_ = json.Unmarshal(item.Payload, &payload)
+		text = "until resumed"
+		if payload.DurationSeconds > 0 {
+			text = formatDurationSeconds(payload.DurationSeconds)
+		}
+		if payload.Reason != "" {
+			text += " (" + payload.Reason + ")"
+		}
+	default:
+		var payload struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.Unmarshal(item.Payload, &payload)
+		text = payload.Reason
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func addQueueItem(ctx context.Context, database *db.DB, loopID, kind, text string) (string, error) {
+	loopEntry, err := db.NewLoopRepository(database).Get(ctx, loopID)
+	if err != nil {
+		return "", err
+	}
+
+	item := &models.LoopQueueItem{Type: models.LoopQueueItemNextPromptOverride}
+	var payload []byte
+	if kind == queueAddMessage {
+		item.Type = models.LoopQueueItemMessageAppend
+		payload, err = json.Marshal(models.MessageAppendPayload{Text: text})
+	} else {
+		payload, err = json.Marshal(models.NextPromptOverridePayload{Prompt: text})
+	}
+	if err != nil {
+		return "", err
+	}
+	item.Payload = payload
+	if err := db.NewLoopQueueRepository(database).Enqueue(ctx, loopEntry.ID, item); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Queued %s for loop %s", kind, loopDisplayID(loopEntry)), nil
+}
+
+func removeQueueItem(ctx context.Context, database *db.DB, loopID, itemID string) (string, error) {
+	if _, err := findPendingQueueItem(ctx, database, loopID, itemID); err != nil {
+		return "", err
+	}
+	if err := db.NewLoopQueueRepository(database).Remove(ctx, itemID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Removed queue item %s", shortRunID(itemID)), nil
+}
+
+// moveQueueItem swaps a pending item with its neighbour delta places away.
+func moveQueueItem(ctx context.Context, database *db.DB, loopID, itemID string, delta int) (string, error) {
+	pending, err := loadQueueItems(ctx, database, loopID)
+	if err != nil {
+		return "", err
+	}
+	index := -1
+	for i, item := range pending {
+		if item.ID == itemID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return "", fmt.Errorf("queue item %s is no longer pending", shortRunID(itemID))
+	}
+	target := index + delta
+	if target < 0 || target >= len(pending) {
+		return "", nil
+	}
+
+	pending[index], pending[target] = pending[target], pending[index]
+	orderedIDs := make([]string, 0, len(pending))
+	for _, item := range pending {
+		orderedIDs = append(orderedIDs, item.ID)
+	}
+	if err := db.NewLoopQueueRepository(database).Reorder(ctx, loopID, orderedIDs); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Moved queue item %s to position %d", shortRunID(itemID), target+1), nil
+}
+
+func findPendingQueueItem(ctx context.Context, database *db.DB, loopID, itemID string) (*models.LoopQueueItem, error) {
+	pending, err := loadQueueItems(ctx, database, loopID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range pending {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("queue item %s is no longer pending", shortRunID(itemID))
+}
@@ -0,0 +1,126 @@
+package looptui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestQueueTabKeys(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.loops = []loopView{
+		testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}})
+	if m.tab != tabQueue {
+		t.Fatalf("expected queue tab, got %v", m.tab)
+	}
+
+	prompt, _ := json.Marshal(models.NextPromptOverridePayload{Prompt: "fix the\nflaky test"})
+	message, _ := json.Marshal(models.MessageAppendPayload{Text: "also update docs"})
+	m.queueItems = []*models.LoopQueueItem{
+		{ID: "q-1", Type: models.LoopQueueItemNextPromptOverride, Payload: prompt},
+		{ID: "q-2", Type: models.LoopQueueItemMessageAppend, Payload: message},
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if m.selectedQueue != 1 {
+		t.Fatalf("expected second item selected, got %d", m.selectedQueue)
+	}
+
+	view, _ := m.selectedView()
+	pane := stripANSI(m.renderQueuePane(view, 100, 20))
+	if !strings.Contains(pane, "pending=2") || !strings.Contains(pane, "fix the flaky test") || !strings.Contains(pane, "> ") {
+		t.Fatalf("unexpected queue pane:\n%s", pane)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if m.selectedQueue != 0 || !m.actionBusy {
+		t.Fatalf("expected cursor to follow moved item and action to start, got %d busy=%v", m.selectedQueue, m.actionBusy)
+	}
+	m.actionBusy = false
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if m.mode != modeQueueAdd || m.queueAdd.LoopID != "id-a" || m.queueAdd.Kind != queueAddPrompt {
+		t.Fatalf("expected queue add mode, got %v %+v", m.mode, m.queueAdd)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.queueAdd.Error != "text required" {
+		t.Fatalf("expected text required error, got %q", m.queueAdd.Error)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ship it")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeQueueAdd || !strings.Contains(m.queueAdd.Error, "invalid kind") {
+		t.Fatalf("expected kind validation error, got mode %v error %q", m.mode, m.queueAdd.Error)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeMain {
+		t.Fatalf("expected main mode after esc, got %v", m.mode)
+	}
+}
+
+func TestQueueItemEditing(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory db: %v", err)
+	}
+	defer database.Close()
+	ctx := context.Background()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	loopEntry := &models.Loop{Name: "queue-loop", RepoPath: "/repo", IntervalSeconds: 10, State: models.LoopStateStopped}
+	if err := db.NewLoopRepository(database).Create(ctx, loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+
+	for _, text := range []string{"first", "second", "third"} {
+		if _, err := addQueueItem(ctx, database, loopEntry.ID, queueAddPrompt, text); err != nil {
+			t.Fatalf("add %s: %v", text, err)
+		}
+	}
+	if _, err := addQueueItem(ctx, database, loopEntry.ID, queueAddMessage, "note"); err != nil {
+		t.Fatalf("add message: %v", err)
+	}
+
+	items, err := loadQueueItems(ctx, database, loopEntry.ID)
+	if err != nil {
+		t.Fatalf("load queue: %v", err)
+	}
+	if len(items) != 4 || items[3].Type != models.LoopQueueItemMessageAppend {
+		t.Fatalf("expected 4 items ending in message_append, got %d", len(items))
+	}
+
+	if _, err := moveQueueItem(ctx, database, loopEntry.ID, items[2].ID, -1); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if _, err := removeQueueItem(ctx, database, loopEntry.ID, items[0].ID); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	items, err = loadQueueItems(ctx, database, loopEntry.ID)
+	if err != nil {
+		t.Fatalf("reload queue: %v", err)
+	}
+	got := make([]string, 0, len(items))
+	for _, item := range items {
+		got = append(got, queueItemSummary(item))
+	}
+	if strings.Join(got, ",") != "third,second,note" {
+		t.Fatalf("unexpected queue order %v", got)
+	}
+
+	if _, err := removeQueueItem(ctx, database, loopEntry.ID, "missing"); err == nil {
+		t.Fatalf("expected error removing unknown item")
+	}
+}
@@ -0,0 +1,141 @@
+package looptui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/procutil"
+)
+
+// resourceHistoryLen is how many refreshes of CPU/memory history each loop
+// keeps for its sparklines.
+const resourceHistoryLen = 32
+
+// listSparkWidth is the width of the CPU column in the loop list.
+const listSparkWidth = 8
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// resourceSample is one procutil reading of a loop process.
+type resourceSample struct {
+	pid   int
+	usage procutil.Usage
+}
+
+// resourceHistory is the in-memory CPU (percent of one core) and RSS history
+// of a loop process. It resets when the loop's pid changes.
+type resourceHistory struct {
+	pid      int
+	lastCPU  time.Duration
+	lastAt   time.Time
+	cpu      []float64
+	rssBytes []float64
+}
+
+// sampleLoopResources reads resource usage for every active loop with a
+// recorded pid. Stopped and errored loops are skipped so a recycled pid is
+// not mistaken for the loop.
+func sampleLoopResources(views []loopView) map[string]resourceSample {
+	samples := make(map[string]resourceSample)
+	for _, view := range views {
+		if view.Loop == nil || view.Loop.State == models.LoopStateStopped || view.Loop.State == models.LoopStateError {
+			continue
+		}
+		pid, ok := loopPID(view.Loop)
+		if !ok || pid <= 0 {
+			continue
+		}
+		usage, err := procutil.SampleUsage(pid)
+		if err != nil {
+			continue
+		}
+		samples[view.Loop.ID] = resourceSample{pid: pid, usage: usage}
+	}
+	return samples
+}
+
+// recordResourceSamples folds one refresh worth of samples into the
+// per-loop histories. Loops without a sample lose their history.
+func recordResourceSamples(prev map[string]resourceHistory, samples map[string]resourceSample, at time.Time) map[string]resourceHistory {
+	next := make(map[string]resourceHistory, len(samples))
+	for loopID, sample := range samples {
+		hist, ok := prev[loopID]
+		if !ok || hist.pid != sample.pid {
+			hist = resourceHistory{pid: sample.pid}
+		} else if elapsed := at.Sub(hist.lastAt); elapsed > 0 {
+			percent := float64(sample.usage.CPUTime-hist.lastCPU) / float64(elapsed) * 100
+			hist.cpu = appendBounded(hist.cpu, math.Max(0, percent))
+		}
+		hist.rssBytes = appendBounded(hist.rssBytes, float64(sample.usage.RSSBytes))
+		hist.lastCPU = sample.usage.CPUTime
+		hist.lastAt = at
+		next[loopID] = hist
+	}
+	return next
+}
+
+func appendBounded(values []float64, value float64) []float64 {
+	values = append(values, value)
+	if len(values) > resourceHistoryLen {
+		values = append([]float64(nil), values[len(values)-resourceHistoryLen:]...)
+	}
+	return values
+}
+
+// sparkline renders the newest width values, scaled against ceiling (or the
+// largest value when it is higher). Missing history is left-padded with
+// spaces so columns stay aligned.
+func sparkline(values []float64, width int, ceiling float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	for _, value := range values {
+		ceiling = math.Max(ceiling, value)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, value := range values {
+		idx := 0
+		if ceiling > 0 {
+			idx = int(math.Round(value / ceiling * float64(len(sparkBlocks)-1)))
+		}
+		idx = maxInt(0, minInt(len(sparkBlocks)-1, idx))
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// cpuSparkline scales CPU history against one full core.
+func (h resourceHistory) cpuSparkline(width int) string {
+	return sparkline(h.cpu, width, 100)
+}
+
+// memSparkline scales RSS history against its own peak.
+func (h resourceHistory) memSparkline(width int) string {
+	return sparkline(h.rssBytes, width, 0)
+}
+
+func lastValue(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[len(values)-1], true
+}
+
+func formatRSS(bytes float64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", bytes/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", bytes/(1<<20))
+	default:
+		return fmt.Sprintf("%.0f KiB", bytes/(1<<10))
+	}
+}
@@ -0,0 +1,112 @@
+package looptui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/procutil"
+)
+
+func TestRecordResourceSamplesComputesCPUPercent(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	hist := recordResourceSamples(nil, map[string]resourceSample{
+		"a": {pid: 10, usage: procutil.Usage{CPUTime: time.Second, RSSBytes: 1 << 20}},
+	}, start)
+	if len(hist["a"].cpu) != 0 || len(hist["a"].rssBytes) != 1 {
+		t.Fatalf("expected no cpu point on first sample, got %+v", hist["a"])
+	}
+
+	hist = recordResourceSamples(hist, map[string]resourceSample{
+		"a": {pid: 10, usage: procutil.Usage{CPUTime: 1500 * time.Millisecond, RSSBytes: 2 << 20}},
+	}, start.Add(time.Second))
+	if got, _ := lastValue(hist["a"].cpu); got != 50 {
+		t.Fatalf("expected 50%% cpu, got %v", got)
+	}
+
+	// A new pid starts a fresh history; loops without samples are dropped.
+	hist = recordResourceSamples(hist, map[string]resourceSample{
+		"a": {pid: 11, usage: procutil.Usage{CPUTime: time.Second, RSSBytes: 1 << 20}},
+	}, start.Add(2*time.Second))
+	if len(hist["a"].cpu) != 0 || len(hist["a"].rssBytes) != 1 {
+		t.Fatalf("expected history reset on pid change, got %+v", hist["a"])
+	}
+	hist = recordResourceSamples(hist, nil, start.Add(3*time.Second))
+	if len(hist) != 0 {
+		t.Fatalf("expected histories dropped, got %+v", hist)
+	}
+}
+
+func TestResourceHistoryIsBounded(t *testing.T) {
+	hist := map[string]resourceHistory{}
+	at := time.Now()
+	for i := 0; i < resourceHistoryLen+10; i++ {
+		at = at.Add(time.Second)
+		hist = recordResourceSamples(hist, map[string]resourceSample{
+			"a": {pid: 1, usage: procutil.Usage{CPUTime: time.Duration(i) * time.Second, RSSBytes: uint64(i)}},
+		}, at)
+	}
+	if len(hist["a"].cpu) != resourceHistoryLen || len(hist["a"].rssBytes) != resourceHistoryLen {
+		t.Fatalf("expected %d points, got cpu=%d rss=%d", resourceHistoryLen, len(hist["a"].cpu), len(hist["a"].rssBytes))
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 50, 100}, 5, 100); got != "  ▁▅█" {
+		t.Fatalf("unexpected sparkline %q", got)
+	}
+	if got := sparkline([]float64{1, 2, 3, 4}, 2, 0); got != "▆█" {
+		t.Fatalf("expected newest values scaled to peak, got %q", got)
+	}
+	// Values above the ceiling raise it instead of clipping.
+	if got := sparkline([]float64{100, 200}, 2, 100); got != "▅█" {
+		t.Fatalf("unexpected sparkline above ceiling %q", got)
+	}
+}
+
+func TestRefreshRendersResourceSparklines(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.width = 140
+	m.height = 40
+	views := []loopView{testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a")}
+	start := time.Now()
+
+	m = updateModel(t, m, refreshMsg{loops: views, usage: map[string]resourceSample{
+		"id-a": {pid: 4242, usage: procutil.Usage{CPUTime: 0, RSSBytes: 64 << 20}},
+	}, sampledAt: start})
+	m = updateModel(t, m, refreshMsg{loops: views, usage: map[string]resourceSample{
+		"id-a": {pid: 4242, usage: procutil.Usage{CPUTime: time.Second, RSSBytes: 80 << 20}},
+	}, sampledAt: start.Add(time.Second)})
+
+	row := m.renderListRow(m.filtered[0], 80)
+	if !strings.Contains(row, "█") {
+		t.Fatalf("expected cpu sparkline in list row, got %q", row)
+	}
+	overview := m.renderOverviewPane(m.filtered[0], 100, 40)
+	if !strings.Contains(overview, "Resources (pid 4242)") || !strings.Contains(overview, "100.0%") || !strings.Contains(overview, "80.0 MiB") {
+		t.Fatalf("expected resource section in overview, got:\n%s", overview)
+	}
+
+	m = updateModel(t, m, tea.WindowSizeMsg{Width: 140, Height: 40})
+	if _, ok := m.resources["id-a"]; !ok {
+		t.Fatalf("expected history to survive resize")
+	}
+}
+
+func TestSampleLoopResourcesSkipsStoppedLoops(t *testing.T) {
+	running := testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a")
+	running.Loop.Metadata = map[string]any{"pid": float64(os.Getpid())}
+	stopped := testLoopView("id-b", "idb", "beta", models.LoopStateStopped, "/tmp/b")
+	stopped.Loop.Metadata = map[string]any{"pid": float64(os.Getpid())}
+
+	samples := sampleLoopResources([]loopView{running, stopped})
+	if _, ok := samples["id-b"]; ok {
+		t.Fatalf("expected stopped loop to be skipped")
+	}
+	if sample, ok := samples["id-a"]; ok && sample.pid != os.Getpid() {
+		t.Fatalf("unexpected sample %+v", sample)
+	}
+}
@@ -0,0 +1,187 @@
+package looptui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runCompareMaxLines caps how many trailing output lines of each run are
+// diffed; the line diff is quadratic in the number of lines.
+const runCompareMaxLines = 1500
+
+// runComparison is the line diff between two runs' outputs. Old is the run
+// that started first.
+type runComparison struct {
+	Old       runView
+	New       runView
+	Rows      []diffRow
+	Added     int
+	Removed   int
+	NewErrors int
+}
+
+// compareRuns diffs the output of two runs, older run first, and flags
+// added lines that look like errors and did not appear in the older run.
+func compareRuns(a, b runView) runComparison {
+	older, newer := a, b
+	if b.Run.StartedAt.Before(a.Run.StartedAt) {
+		older, newer = b, a
+	}
+	oldLines := normalizeCompareLines(runOutputLines(older.Run, runCompareMaxLines))
+	newLines := normalizeCompareLines(runOutputLines(newer.Run, runCompareMaxLines))
+
+	cmp := runComparison{Old: older, New: newer, Rows: diffLines(oldLines, newLines)}
+	seenErrors := make(map[string]struct{})
+	for _, line := range oldLines {
+		if looksLikeErrorLine(line) {
+			seenErrors[strings.TrimSpace(line)] = struct{}{}
+		}
+	}
+	for i := range cmp.Rows {
+		row := &cmp.Rows[i]
+		switch row.Kind {
+		case diffRowAdd:
+			cmp.Added++
+			if !looksLikeErrorLine(row.Text) {
+				continue
+			}
+			if _, seen := seenErrors[strings.TrimSpace(row.Text)]; !seen {
+				row.NewError = true
+				cmp.NewErrors++
+			}
+		case diffRowDel:
+			cmp.Removed++
+		}
+	}
+	return cmp
+}
+
+// normalizeCompareLines renders log records as text and strips timestamp
+// prefixes so identical output from different runs lines up.
+func normalizeCompareLines(lines []string) []string {
+	out := displayLogLines(lines)
+	for i, line := range out {
+		line = sanitizeLogLine(line)
+		if ts, ok := parseTimestampPrefix(line); ok {
+			line = strings.TrimPrefix(strings.TrimLeft(line, " ")[len(ts):], " ")
+		}
+		out[i] = line
+	}
+	return out
+}
+
+// diffLines computes a line diff from the longest common subsequence of
+// oldLines and newLines. Removals come before additions within a change
+// block so the side-by-side renderer pairs them.
+func diffLines(oldLines, newLines []string) []diffRow {
+	n, m := len(oldLines), len(newLines)
+	// lcs[i][j] is the LCS length of oldLines[i:] and newLines[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	rows := make([]diffRow, 0, n+m)
+	var dels, adds []diffRow
+	flush := func() {
+		rows = append(rows, dels...)
+		rows = append(rows, adds...)
+		dels, adds = dels[:0], adds[:0]
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldLines[i] == newLines[j]:
+			flush()
+			rows = append(rows, diffRow{Kind: diffRowContext, Old: i + 1, New: j + 1, Text: oldLines[i]})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			adds = append(adds, diffRow{Kind: diffRowAdd, New: j + 1, Text: newLines[j]})
+			j++
+		default:
+			dels = append(dels, diffRow{Kind: diffRowDel, Old: i + 1, Text: oldLines[i]})
+			i++
+		}
+	}
+	flush()
+	return rows
+}
+
+// compareBaseRun returns the run marked as comparison base, if it is still
+// in the loaded history.
+func (m model) compareBaseRun() (runView, bool) {
+	if m.compareRunID == "" {
+		return runView{}, false
+	}
+	for _, view := range m.runHistory {
+		if view.Run != nil && view.Run.ID == m.compareRunID {
+			return view, true
+		}
+	}
+	return runView{}, false
+}
+
+// toggleRunCompare marks the selected run as comparison base, or leaves
+// compare mode when a base is already set.
+func (m *model) toggleRunCompare() {
+	if m.compareRunID != "" {
+		m.compareRunID = ""
+		m.logScroll = 0
+		m.setStatus(statusInfo, "Compare off")
+		return
+	}
+	run, ok := m.selectedRunView()
+	if !ok || run.Run == nil {
+		m.setStatus(statusInfo, "No run selected")
+		return
+	}
+	m.compareRunID = run.Run.ID
+	m.logScroll = 0
+	m.setStatus(statusInfo, fmt.Sprintf("Compare base %s: select another run with ,/.", shortRunID(run.Run.ID)))
+}
+
+// renderRunCompare renders the comparison of the base run against the
+// selected run. It honors the diff layer's split and collapse options.
+func (m model) renderRunCompare(base runView, width, available int) []string {
+	selected, ok := m.selectedRunView()
+	if !ok || selected.Run == nil || selected.Run.ID == base.Run.ID {
+		return []string{
+			colorText(truncateLine(fmt.Sprintf("Compare base %s", shortRunID(base.Run.ID)), width), m.palette.TextMuted, false),
+			truncateLine("Select another run with ,/. to compare.", width),
+		}
+	}
+
+	cmp := compareRuns(base, selected)
+	header := fmt.Sprintf("Compare %s -> %s | +%d -%d", shortRunID(cmp.Old.Run.ID), shortRunID(cmp.New.Run.ID), cmp.Added, cmp.Removed)
+	if cmp.NewErrors > 0 {
+		header += fmt.Sprintf(" | %d new error lines", cmp.NewErrors)
+	}
+	out := []string{colorText(truncateLine(header, width), m.palette.TextMuted, false)}
+	if cmp.Added == 0 && cmp.Removed == 0 {
+		return append(out, truncateLine("Run outputs are identical.", width))
+	}
+
+	rows := cmp.Rows
+	if m.diffCollapse {
+		rows = collapseDiffContext(rows)
+	}
+	var rendered []string
+	if m.diffSplit && width >= diffSplitMinWidth {
+		rendered = renderSideBySideDiff(m.palette, rows, width)
+	} else {
+		rendered = renderUnifiedDiff(m.palette, rows, width)
+	}
+	start, end, clamped := logWindowBounds(len(rendered), maxInt(1, available-2), m.logScroll)
+	out = append(out, truncateLine("compare "+formatLineWindow(start, end, len(rendered), clamped), width))
+	return append(out, rendered[start:end]...)
+}
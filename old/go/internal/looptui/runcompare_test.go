@@ -0,0 +1,88 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestDiffLinesGroupsChangeBlocks(t *testing.T) {
+	rows := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "y", "c"})
+	var kinds []diffRowKind
+	for _, row := range rows {
+		kinds = append(kinds, row.Kind)
+	}
+	want := []diffRowKind{diffRowContext, diffRowDel, diffRowAdd, diffRowAdd, diffRowContext}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), rows)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("row %d: expected kind %d, got %+v", i, want[i], rows[i])
+		}
+	}
+	if rows[1].Old != 2 || rows[3].New != 3 || rows[4].Old != 3 || rows[4].New != 4 {
+		t.Fatalf("unexpected line numbers: %+v", rows)
+	}
+}
+
+func TestCompareRunsFlagsOnlyNewErrors(t *testing.T) {
+	now := time.Now().UTC()
+	older := runView{Run: &models.LoopRun{
+		ID:         "run-old",
+		StartedAt:  now.Add(-time.Minute),
+		OutputTail: "[2026-01-01T00:00:00Z] build\nerror: flaky cache\nok",
+	}}
+	newer := runView{Run: &models.LoopRun{
+		ID:         "run-new",
+		StartedAt:  now,
+		OutputTail: "[2026-01-01T00:05:00Z] build\nerror: flaky cache\nerror: nil pointer in parser\nok",
+	}}
+
+	// Argument order does not matter; the older run is always the base.
+	cmp := compareRuns(newer, older)
+	if cmp.Old.Run.ID != "run-old" || cmp.New.Run.ID != "run-new" {
+		t.Fatalf("expected old->new ordering, got %s->%s", cmp.Old.Run.ID, cmp.New.Run.ID)
+	}
+	if cmp.Added != 1 || cmp.Removed != 0 || cmp.NewErrors != 1 {
+		t.Fatalf("expected +1 -0 with 1 new error (timestamps ignored), got %+v", cmp)
+	}
+	for _, row := range cmp.Rows {
+		if row.NewError != (row.Text == "error: nil pointer in parser") {
+			t.Fatalf("unexpected NewError flag on %+v", row)
+		}
+	}
+}
+
+func TestRunCompareKeyRendersComparison(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.loops = []loopView{
+		testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+	m.tab = tabRuns
+	m.runHistory = []runView{
+		{Run: &models.LoopRun{ID: "run-2", StartedAt: time.Now().UTC(), OutputTail: "step\npanic: boom"}},
+		{Run: &models.LoopRun{ID: "run-1", StartedAt: time.Now().Add(-time.Minute).UTC(), OutputTail: "step\ndone"}},
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'='}})
+	if m.compareRunID != "run-2" {
+		t.Fatalf("expected run-2 as compare base, got %q", m.compareRunID)
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+
+	view, _ := m.selectedView()
+	pane := stripANSI(m.renderRunsPane(view, 100, 30))
+	if !strings.Contains(pane, "+1 -1 | 1 new error lines") || !strings.Contains(pane, "! panic: boom") {
+		t.Fatalf("expected comparison with flagged error, got:\n%s", pane)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'='}})
+	if m.compareRunID != "" {
+		t.Fatalf("expected compare mode off, got %q", m.compareRunID)
+	}
+}
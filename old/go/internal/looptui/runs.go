@@ -2,6 +2,7 @@ package looptui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/tOgg1/forge/internal/db"
@@ -70,3 +71,87 @@ func runOutputLines(run *models.LoopRun, maxLines int) []string {
 	}
 	return lines
 }
+
+// formatRunUsage renders a run's token usage and cost, or "" when the
+// harness reported none.
+func formatRunUsage(run *models.LoopRun) string {
+	if !run.HasUsage() {
+		return ""
+	}
+	parts := make([]string, 0, 2)
+	if run.TotalTokens > 0 {
+		parts = append(parts, "tok="+formatTokenCount(run.TotalTokens))
+	}
+	if run.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", run.CostUSD))
+	}
+	return strings.Join(parts, " ")
+}
+
+// runUsageTotals summarizes usage across the loaded runs for the Runs tab
+// header, or "" when no run reported usage.
+func runUsageTotals(runs []runView) string {
+	var (
+		counted int
+		tokens  int64
+		cost    float64
+	)
+	for _, view := range runs {
+		if !view.Run.HasUsage() {
+			continue
+		}
+		counted++
+		tokens += view.Run.TotalTokens
+		cost += view.Run.CostUSD
+	}
+	if counted == 0 {
+		return ""
+	}
+	return fmt.Sprintf("usage: %d/%d runs  tokens=%s  cost=$%.2f", counted, len(runs), formatTokenCount(tokens), cost)
+}
+
+func formatTokenCount(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1_000)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
+
+// runArtifactLines lists a run's captured artifacts for the Runs tab,
+// showing at most maxFiles paths. It returns nil when nothing was captured.
+func runArtifactLines(run *models.LoopRun, maxFiles int) []string {
+	if run == nil || len(run.Artifacts) == 0 {
+		return nil
+	}
+	var total int64
+	for _, artifact := range run.Artifacts {
+		total += artifact.Size
+	}
+	lines := []string{fmt.Sprintf("artifacts: %d files (%s) in %s", len(run.Artifacts), formatArtifactSize(total), run.ArtifactDir)}
+	shown := run.Artifacts
+	if maxFiles > 0 && len(shown) > maxFiles {
+		shown = shown[:maxFiles]
+	}
+	for _, artifact := range shown {
+		lines = append(lines, fmt.Sprintf("  %s  %s", artifact.Path, formatArtifactSize(artifact.Size)))
+	}
+	if len(run.Artifacts) > len(shown) {
+		lines = append(lines, fmt.Sprintf("  ... %d more (forge loop artifacts)", len(run.Artifacts)-len(shown)))
+	}
+	return lines
+}
+
+func formatArtifactSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
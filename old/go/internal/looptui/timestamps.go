@@ -0,0 +1,300 @@
+package looptui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+// logTimeMode is the log timestamp gutter setting.
+type logTimeMode int
+
+const (
+	logTimeOff logTimeMode = iota
+	logTimeAbsolute
+	logTimeRelative
+)
+
+// logGutterWidth is the width of the timestamp gutter: a marker column for
+// synthesized times, eight columns of time and a separator.
+const logGutterWidth = 10
+
+// logStamp is the time attributed to one log line. Synthesized stamps are
+// inherited from a neighbouring line (or the run start) rather than parsed.
+type logStamp struct {
+	at          time.Time
+	synthesized bool
+}
+
+// jumpState is the "jump to time" prompt of the expanded logs view.
+type jumpState struct {
+	Input string
+	Error string
+}
+
+func (mode logTimeMode) label() string {
+	switch mode {
+	case logTimeAbsolute:
+		return "absolute"
+	case logTimeRelative:
+		return "relative"
+	default:
+		return "off"
+	}
+}
+
+func (mode logTimeMode) next() logTimeMode {
+	return (mode + 1) % 3
+}
+
+// parseLogTimestamp extracts the time of a log line: the ts field of JSONL
+// records, the "[RFC3339] " prefix of runner lines, or a leading RFC3339 or
+// "2006-01-02 15:04:05" timestamp written by the harness.
+func parseLogTimestamp(line string) (time.Time, bool) {
+	if rec, ok := loop.ParseLogRecord(line); ok {
+		return rec.Time, !rec.Time.IsZero()
+	}
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[") {
+		if end := strings.IndexByte(trimmed, ']'); end > 0 {
+			if t, err := time.Parse(time.RFC3339Nano, trimmed[1:end]); err == nil {
+				return t, true
+			}
+		}
+	}
+	if field, _, _ := strings.Cut(trimmed, " "); len(field) >= len("2006-01-02T15:04:05Z") {
+		if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+			return t, true
+		}
+	}
+	if len(trimmed) >= len(time.DateTime) {
+		if t, err := time.ParseInLocation(time.DateTime, trimmed[:len(time.DateTime)], time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// stampLogLines attributes a time to every line. Lines without a timestamp
+// inherit the previous parsed one; leading lines take the first parsed one,
+// or base when the block has none. With no parsed time and a zero base the
+// stamps stay zero.
+func stampLogLines(lines []string, base time.Time) []logStamp {
+	stamps := make([]logStamp, len(lines))
+	var last time.Time
+	firstParsed := -1
+	for i, line := range lines {
+		if t, ok := parseLogTimestamp(line); ok {
+			last = t
+			stamps[i] = logStamp{at: t}
+			if firstParsed < 0 {
+				firstParsed = i
+			}
+			continue
+		}
+		if !last.IsZero() {
+			stamps[i] = logStamp{at: last, synthesized: true}
+		}
+	}
+
+	lead := base
+	if firstParsed >= 0 {
+		lead = stamps[firstParsed].at
+	} else {
+		firstParsed = len(lines)
+	}
+	if !lead.IsZero() {
+		for i := 0; i < firstParsed; i++ {
+			stamps[i] = logStamp{at: lead, synthesized: true}
+		}
+	}
+	return stamps
+}
+
+// formatLogGutter renders a stamp as a fixed-width gutter cell.
+func formatLogGutter(stamp logStamp, mode logTimeMode, now time.Time) string {
+	if stamp.at.IsZero() {
+		return strings.Repeat(" ", logGutterWidth)
+	}
+	marker := " "
+	if stamp.synthesized {
+		marker = "~"
+	}
+	var text string
+	if mode == logTimeRelative {
+		text = "-" + compactDuration(now.Sub(stamp.at))
+	} else {
+		text = stamp.at.Local().Format("15:04:05")
+	}
+	return fmt.Sprintf("%s%8s ", marker, text)
+}
+
+// compactDuration renders d in at most seven columns (12s, 3m12s, 2h05m,
+// 3d04h).
+func compactDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d/time.Minute), int(d%time.Minute/time.Second))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	default:
+		days := int(d / (24 * time.Hour))
+		if days > 99 {
+			return fmt.Sprintf("%dd", days)
+		}
+		return fmt.Sprintf("%dd%02dh", days, int(d%(24*time.Hour)/time.Hour))
+	}
+}
+
+func (m model) renderLogGutter(stamp logStamp, now time.Time) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted))
+	if stamp.synthesized {
+		style = style.Faint(true)
+	}
+	return style.Render(formatLogGutter(stamp, m.logTimeMode, now))
+}
+
+// parseJumpTime parses a jump target: HH:MM or HH:MM:SS (today, or
+// yesterday when that is still in the future), RFC3339, or a duration ago
+// such as 10m or -10m.
+func parseJumpTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("time required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "-")); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		clock, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		local := now.Local()
+		when := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, local.Location())
+		if when.After(local) {
+			when = when.AddDate(0, 0, -1)
+		}
+		return when, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM[:SS], RFC3339, or a duration ago like 10m)", value)
+}
+
+// firstLineAtOrAfter returns the index of the first line stamped at or
+// after target, skipping unstamped lines.
+func firstLineAtOrAfter(stamps []logStamp, target time.Time) (int, bool) {
+	for i, stamp := range stamps {
+		if !stamp.at.IsZero() && !stamp.at.Before(target) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// jumpScroll is the logScroll that puts line idx at the top of a window of
+// rows lines over total lines.
+func jumpScroll(total, rows, idx int) int {
+	scroll := total - idx - maxInt(1, rows)
+	return maxInt(0, minInt(scroll, total-1))
+}
+
+func (m *model) cycleLogTimeMode() {
+	m.logTimeMode = m.logTimeMode.next()
+	m.setStatus(statusInfo, "Log timestamps: "+m.logTimeMode.label())
+}
+
+func (m model) updateJumpPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.jump = nil
+		return m, nil
+	case "enter":
+		target, err := parseJumpTime(m.jump.Input, time.Now())
+		if err != nil {
+			m.jump.Error = err.Error()
+			return m, nil
+		}
+		m.jump = nil
+		m.jumpToTime(target)
+		return m, nil
+	case "backspace", "ctrl+h", "delete":
+		m.jump.Input = removeLastRune(m.jump.Input)
+		return m, nil
+	case "space":
+		m.jump.Input += " "
+		return m, nil
+	default:
+		if len(msg.Runes) > 0 {
+			m.jump.Input += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+// jumpToTime scrolls the expanded log so the first line at or after target
+// is at the top. Only the lines already loaded are searched.
+func (m *model) jumpToTime(target time.Time) {
+	view, ok := m.selectedView()
+	if !ok || view.Loop == nil {
+		m.setStatus(statusInfo, "No loop selected")
+		return
+	}
+	display := m.expandedLogDisplay(view)
+	stamps := stampLogLines(display.Lines, display.BaseTime)
+	idx, found := firstLineAtOrAfter(stamps, target)
+	if !found {
+		m.setStatus(statusErr, "No log lines at or after "+target.Local().Format("15:04:05"))
+		return
+	}
+	if m.logTimeMode == logTimeOff {
+		m.logTimeMode = logTimeAbsolute
+	}
+	if idx == 0 && stamps[0].at.After(target) {
+		m.setStatus(statusInfo, "Earliest loaded line is at "+stamps[0].at.Local().Format("15:04:05")+"; scroll up to load more")
+	} else {
+		m.setStatus(statusInfo, "Jumped to "+stamps[idx].at.Local().Format("15:04:05"))
+	}
+	// The status line takes a row, so size the window after setting it.
+	m.logScroll = jumpScroll(len(display.Lines), m.expandedLogRows(), idx)
+}
+
+// expandedLogRows is the number of log lines renderExpandedLogs shows:
+// the pane minus its border and the five header lines.
+func (m model) expandedLogRows() int {
+	return maxInt(1, m.paneHeight(m.effectiveHeight())-8)
+}
+
+func (m model) renderJumpDialog(width int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.palette.Accent)).
+		Background(lipgloss.Color(m.palette.PanelAlt)).
+		Padding(0, 1).
+		Width(maxInt(40, width))
+
+	content := []string{
+		"Jump to time",
+		renderWizardField(m.palette, "time (HH:MM[:SS], RFC3339, 10m = ten minutes ago)", m.jump.Input, true),
+		"enter jumps, esc cancels",
+	}
+	if m.jump.Error != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error)).Render("Error: "+m.jump.Error))
+	}
+	for i := range content {
+		content[i] = truncateLine(content[i], maxInt(1, width-6))
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
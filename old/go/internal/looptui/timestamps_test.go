@@ -0,0 +1,155 @@
+package looptui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestParseLogTimestamp(t *testing.T) {
+	want := time.Date(2026, 3, 1, 12, 30, 5, 0, time.UTC)
+	cases := map[string]bool{
+		"[2026-03-01T12:30:05Z] loop started":                      true,
+		`{"ts":"2026-03-01T12:30:05Z","stream":"loop","msg":"hi"}`: true,
+		"2026-03-01T12:30:05Z tool call finished":                  true,
+		"plain harness output":                                     false,
+		"[not-a-time] bracketed":                                   false,
+	}
+	for line, ok := range cases {
+		got, parsed := parseLogTimestamp(line)
+		if parsed != ok {
+			t.Fatalf("%q: expected parsed=%v, got %v", line, ok, parsed)
+		}
+		if ok && !got.Equal(want) {
+			t.Fatalf("%q: expected %v, got %v", line, want, got)
+		}
+	}
+	if _, ok := parseLogTimestamp("2026-03-01 12:30:05 local format"); !ok {
+		t.Fatalf("expected date-time prefix to parse")
+	}
+}
+
+func TestStampLogLinesSynthesizesMissingTimes(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lines := []string{
+		"harness banner",
+		"[2026-03-01T12:00:10Z] iteration 1",
+		"tool output",
+		"[2026-03-01T12:00:20Z] iteration 2",
+	}
+	stamps := stampLogLines(lines, base)
+	if !stamps[0].synthesized || !stamps[0].at.Equal(base.Add(10*time.Second)) {
+		t.Fatalf("expected leading line back-filled from first stamp, got %+v", stamps[0])
+	}
+	if stamps[1].synthesized || !stamps[2].synthesized || !stamps[2].at.Equal(stamps[1].at) {
+		t.Fatalf("expected carried-forward stamp, got %+v", stamps)
+	}
+
+	stamps = stampLogLines([]string{"a", "b"}, base)
+	if !stamps[1].at.Equal(base) || !stamps[1].synthesized {
+		t.Fatalf("expected base time for unstamped block, got %+v", stamps)
+	}
+	if stamps = stampLogLines([]string{"a"}, time.Time{}); !stamps[0].at.IsZero() {
+		t.Fatalf("expected zero stamp without base, got %+v", stamps)
+	}
+}
+
+func TestFormatLogGutter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	stamp := logStamp{at: now.Add(-(3*time.Minute + 12*time.Second))}
+	if got := formatLogGutter(stamp, logTimeRelative, now); got != "   -3m12s " {
+		t.Fatalf("unexpected relative gutter %q", got)
+	}
+	stamp.synthesized = true
+	if got := formatLogGutter(stamp, logTimeAbsolute, now); got != "~"+stamp.at.Local().Format("15:04:05")+" " {
+		t.Fatalf("unexpected absolute gutter %q", got)
+	}
+	if got := formatLogGutter(logStamp{}, logTimeAbsolute, now); got != strings.Repeat(" ", logGutterWidth) {
+		t.Fatalf("expected blank gutter, got %q", got)
+	}
+	for d, want := range map[time.Duration]string{
+		12 * time.Second:            "12s",
+		2*time.Hour + 5*time.Minute: "2h05m",
+		(3*24 + 4) * time.Hour:      "3d04h",
+		-time.Second:                "0s",
+	} {
+		if got := compactDuration(d); got != want {
+			t.Fatalf("compactDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestParseJumpTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	got, err := parseJumpTime("10m", now)
+	if err != nil || !got.Equal(now.Add(-10*time.Minute)) {
+		t.Fatalf("expected ten minutes ago, got %v %v", got, err)
+	}
+	if got, err = parseJumpTime("-1h", now); err != nil || !got.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected an hour ago, got %v %v", got, err)
+	}
+	if got, err = parseJumpTime("08:15", now); err != nil || !got.Equal(time.Date(2026, 3, 1, 8, 15, 0, 0, time.Local)) {
+		t.Fatalf("expected today 08:15, got %v %v", got, err)
+	}
+	if got, err = parseJumpTime("23:00:30", now); err != nil || !got.Equal(time.Date(2026, 2, 28, 23, 0, 30, 0, time.Local)) {
+		t.Fatalf("expected yesterday 23:00:30, got %v %v", got, err)
+	}
+	if _, err = parseJumpTime("soon", now); err == nil {
+		t.Fatalf("expected invalid time error")
+	}
+}
+
+func TestJumpToTimeScrollsExpandedLogs(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8})
+	m.loops = []loopView{
+		testLoopView("a", "a12345", "alpha", models.LoopStateRunning, "/tmp/a"),
+	}
+	m.applyFilters("", 0)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		m.selectedLog.Lines = append(m.selectedLog.Lines, fmt.Sprintf("[%s] line %d", start.Add(time.Duration(i)*time.Second).Format(time.RFC3339), i))
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	if m.jump == nil {
+		t.Fatalf("expected jump prompt")
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2026-03-01T12:00:50Z")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.jump != nil || m.mode != modeExpandedLogs {
+		t.Fatalf("expected prompt closed in expanded mode, got jump=%+v mode=%v", m.jump, m.mode)
+	}
+	if m.logTimeMode != logTimeAbsolute {
+		t.Fatalf("expected jump to enable the timestamp gutter, got %v", m.logTimeMode)
+	}
+	first, _, _ := logWindowBounds(len(m.selectedLog.Lines), m.expandedLogRows(), m.logScroll)
+	if first != 50 {
+		t.Fatalf("expected line 50 at the top of the window, got %d (scroll %d)", first, m.logScroll)
+	}
+	if !strings.Contains(m.View(), "line 50") {
+		t.Fatalf("expected jumped-to line in view")
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2026-03-02T00:00:00Z")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.statusKind != statusErr || !strings.Contains(m.statusText, "No log lines") {
+		t.Fatalf("expected error for time past the log, got %q", m.statusText)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("bogus")})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.jump == nil || m.jump.Error == "" {
+		t.Fatalf("expected prompt error for invalid input")
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.jump != nil || m.mode != modeExpandedLogs {
+		t.Fatalf("expected esc to close only the prompt")
+	}
+}
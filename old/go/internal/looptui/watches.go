@@ -0,0 +1,51 @@
+package looptui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+// loadLoopWatches evaluates the watch expressions of the selected loop.
+// Watches run on their own timeout, not the refresh context, so one slow
+// expression shows as timed out instead of failing the whole refresh.
+func loadLoopWatches(views []loopView, loopID string) []loop.WatchResult {
+	for _, view := range views {
+		if view.Loop == nil || view.Loop.ID != loopID {
+			continue
+		}
+		return loop.EvalWatches(context.Background(), view.Loop, loop.DefaultWatchTimeout)
+	}
+	return nil
+}
+
+// renderWatchLines renders the overview "Watch:" section for loopID, or
+// nothing when the loop has no evaluated watches.
+func (m model) renderWatchLines(loopID string, width int) []string {
+	if m.watchesLoopID != loopID || len(m.watches) == 0 {
+		return nil
+	}
+	nameWidth := 0
+	for _, result := range m.watches {
+		nameWidth = maxInt(nameWidth, len(result.Name))
+	}
+	lines := []string{"", lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("Watch:")}
+	failed := lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Error))
+	for _, result := range m.watches {
+		value := displayName(result.Value, "-")
+		switch {
+		case result.Error != "":
+			value = "error: " + result.Error
+		case result.ExitCode != 0:
+			value = fmt.Sprintf("exit %d: %s", result.ExitCode, value)
+		}
+		line := truncateLine(fmt.Sprintf("  %-*s %s", nameWidth, result.Name, value), width)
+		if result.Failed() {
+			line = failed.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,35 @@
+package looptui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestRefreshRendersWatchPanel(t *testing.T) {
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default"})
+	m.width = 140
+	m.height = 40
+	views := []loopView{testLoopView("id-a", "ida", "alpha", models.LoopStateRunning, "/tmp/a")}
+
+	m = updateModel(t, m, refreshMsg{loops: views, selectedID: "id-a", watches: []loop.WatchResult{
+		{Name: "dirty", Expr: "git status --porcelain | wc -l", Value: "3"},
+		{Name: "tests", Expr: "make check", Value: "FAIL", ExitCode: 2},
+	}})
+
+	overview := m.renderOverviewPane(m.filtered[0], 100, 40)
+	if !strings.Contains(overview, "Watch:") || !strings.Contains(overview, "dirty 3") || !strings.Contains(overview, "exit 2: FAIL") {
+		t.Fatalf("expected watch section in overview, got:\n%s", overview)
+	}
+
+	m = updateModel(t, m, refreshMsg{loops: views, selectedID: "id-a"})
+	if overview := m.renderOverviewPane(m.filtered[0], 100, 40); strings.Contains(overview, "Watch:") {
+		t.Fatalf("expected watch section to clear when nothing was evaluated, got:\n%s", overview)
+	}
+	if lines := m.renderWatchLines("id-b", 80); lines != nil {
+		t.Fatalf("expected no watch lines for another loop, got %v", lines)
+	}
+}
@@ -0,0 +1,129 @@
+package looptui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+// loadWizardTemplates reads the saved loop templates for wizard step 1.
+func (m *model) loadWizardTemplates() {
+	m.wizard.Templates = nil
+	m.wizard.TemplateIdx = -1
+	if strings.TrimSpace(m.templateDir) == "" {
+		return
+	}
+	templates, err := loop.ListTemplates(m.templateDir)
+	if err != nil {
+		m.wizard.Error = "load templates: " + err.Error()
+		return
+	}
+	m.wizard.Templates = templates
+}
+
+// cycleWizardTemplate selects the next template and prefills every field
+// from it; cycling past the last template restores the defaults.
+func (m *model) cycleWizardTemplate() {
+	if len(m.wizard.Templates) == 0 {
+		m.wizard.Error = "no loop templates (save one on the review step or with `forge loop template save`)"
+		return
+	}
+	next := m.wizard.TemplateIdx + 1
+	if next >= len(m.wizard.Templates) {
+		templates := m.wizard.Templates
+		m.wizard = newWizardState(m.defaultInterval, m.defaultPrompt, m.defaultPromptMsg)
+		m.wizard.Templates = templates
+		m.wizard.TemplateIdx = -1
+		return
+	}
+	m.wizard.TemplateIdx = next
+	m.wizard.Values = wizardValuesFromTemplate(m.wizard.Templates[next])
+	m.wizard.Field = 0
+	m.wizard.Error = ""
+}
+
+// saveWizardTemplate stores the current wizard values under the save-as name.
+func (m *model) saveWizardTemplate() {
+	if strings.TrimSpace(m.templateDir) == "" {
+		m.wizard.Error = "template directory is not configured"
+		return
+	}
+	tpl := templateFromWizardValues(m.wizard.SaveAs, m.wizard.Values)
+	if err := loop.SaveTemplate(m.templateDir, tpl); err != nil {
+		m.wizard.Error = err.Error()
+		return
+	}
+	m.wizard.Error = ""
+	m.setStatus(statusOK, fmt.Sprintf("Saved loop template %q", strings.TrimSpace(m.wizard.SaveAs)))
+	selected := strings.TrimSpace(m.wizard.SaveAs)
+	m.loadWizardTemplates()
+	for i, existing := range m.wizard.Templates {
+		if existing.Name == selected {
+			m.wizard.TemplateIdx = i
+		}
+	}
+}
+
+func (m model) renderWizardTemplates() string {
+	if len(m.wizard.Templates) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.TextMuted)).Render("templates: none saved")
+	}
+	names := make([]string, 0, len(m.wizard.Templates)+1)
+	names = append(names, "(defaults)")
+	for _, tpl := range m.wizard.Templates {
+		names = append(names, tpl.Name)
+	}
+	selected := m.wizard.TemplateIdx + 1
+	for i := range names {
+		if i == selected {
+			names[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(m.palette.Focus)).Bold(true).Render("[" + names[i] + "]")
+		}
+	}
+	line := "templates: " + strings.Join(names, "  ") + "  (ctrl+t next)"
+	if m.wizard.TemplateIdx >= 0 {
+		if desc := strings.TrimSpace(m.wizard.Templates[m.wizard.TemplateIdx].Description); desc != "" {
+			line += "\n  " + desc
+		}
+	}
+	return line
+}
+
+func wizardValuesFromTemplate(tpl loop.Template) wizardValues {
+	count := tpl.Count
+	if strings.TrimSpace(count) == "" {
+		count = "1"
+	}
+	return wizardValues{
+		Name:          tpl.LoopName,
+		NamePrefix:    tpl.NamePrefix,
+		Count:         count,
+		Pool:          tpl.Pool,
+		Profile:       tpl.Profile,
+		Prompt:        tpl.Prompt,
+		PromptMsg:     tpl.PromptMsg,
+		Interval:      tpl.Interval,
+		MaxRuntime:    tpl.MaxRuntime,
+		MaxIterations: tpl.MaxIterations,
+		Tags:          tpl.Tags,
+	}
+}
+
+func templateFromWizardValues(name string, values wizardValues) loop.Template {
+	return loop.Template{
+		Name:          strings.TrimSpace(name),
+		LoopName:      strings.TrimSpace(values.Name),
+		NamePrefix:    strings.TrimSpace(values.NamePrefix),
+		Count:         strings.TrimSpace(values.Count),
+		Pool:          strings.TrimSpace(values.Pool),
+		Profile:       strings.TrimSpace(values.Profile),
+		Prompt:        strings.TrimSpace(values.Prompt),
+		PromptMsg:     strings.TrimSpace(values.PromptMsg),
+		Interval:      strings.TrimSpace(values.Interval),
+		MaxRuntime:    strings.TrimSpace(values.MaxRuntime),
+		MaxIterations: strings.TrimSpace(values.MaxIterations),
+		Tags:          strings.TrimSpace(values.Tags),
+	}
+}
@@ -0,0 +1,50 @@
+package looptui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tOgg1/forge/internal/loop"
+)
+
+func TestWizardTemplatesPrefillAndSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "loop-templates")
+	if err := loop.SaveTemplate(dir, loop.Template{Name: "review", NamePrefix: "rev", Count: "3", Pool: "default", Interval: "45s", Tags: "review"}); err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+
+	m := newModel(nil, Config{RefreshInterval: time.Second, LogLines: 8, Theme: "default", TemplateDir: dir})
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.mode != modeWizard || len(m.wizard.Templates) != 1 {
+		t.Fatalf("expected wizard with 1 template, got mode %v templates %d", m.mode, len(m.wizard.Templates))
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.wizard.TemplateIdx != 0 || m.wizard.Values.NamePrefix != "rev" || m.wizard.Values.Count != "3" || m.wizard.Values.Pool != "default" || m.wizard.Values.Interval != "45s" {
+		t.Fatalf("expected template to prefill wizard, got %+v", m.wizard.Values)
+	}
+
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.wizard.TemplateIdx != -1 || m.wizard.Values.Pool != "" {
+		t.Fatalf("expected cycling past last template to restore defaults, got %+v", m.wizard.Values)
+	}
+
+	m.wizard.Values.Profile = "claude"
+	m.wizard.Step = 4
+	for _, r := range "solo" {
+		m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = updateModel(t, m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.wizard.Error != "" {
+		t.Fatalf("unexpected save error: %s", m.wizard.Error)
+	}
+	saved, err := loop.LoadTemplate(dir, "solo")
+	if err != nil {
+		t.Fatalf("load saved template: %v", err)
+	}
+	if saved.Profile != "claude" {
+		t.Fatalf("expected saved profile, got %+v", saved)
+	}
+}
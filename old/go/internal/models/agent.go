@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -61,6 +62,9 @@ type Agent struct {
 	// WorkspaceID references the workspace this agent belongs to.
 	WorkspaceID string `json:"workspace_id"`
 
+	// Namespace is the tenant that owns the agent, normally its workspace's.
+	Namespace string `json:"namespace,omitempty"`
+
 	// Type identifies the agent CLI being used.
 	Type AgentType `json:"type"`
 
@@ -124,6 +128,9 @@ type AgentMetadata struct {
 	// StartCommand is the command used to spawn the agent.
 	StartCommand string `json:"start_command,omitempty"`
 
+	// WorkingDir is the directory the agent's pane was started in.
+	WorkingDir string `json:"working_dir,omitempty"`
+
 	// Environment contains environment variable overrides.
 	Environment map[string]string `json:"environment,omitempty"`
 
@@ -139,9 +146,33 @@ type AgentMetadata struct {
 	// ProcessStats captures process-level resource metrics.
 	ProcessStats *ProcessStats `json:"process_stats,omitempty"`
 
+	// Resources captures cgroup limits and usage when the agent runs in a
+	// cgroup.
+	Resources *ResourceUsage `json:"resources,omitempty"`
+
 	// OpenCode contains connection details for OpenCode server integration.
 	// Only populated for agents of type AgentTypeOpenCode.
 	OpenCode *OpenCodeConnection `json:"opencode,omitempty"`
+
+	// CrashLoop tracks consecutive failures and restart backoff.
+	CrashLoop *CrashLoopInfo `json:"crash_loop,omitempty"`
+
+	// Recovery tracks automatic recoveries after the agent's tmux pane
+	// disappeared.
+	Recovery *RecoveryInfo `json:"recovery,omitempty"`
+
+	// Takeover is set while an operator has interactive control of the
+	// agent's pane; dispatch is paused until it is released.
+	Takeover *TakeoverInfo `json:"takeover,omitempty"`
+
+	// Heartbeat tracks the liveness signal written by the agent's harness
+	// wrapper. It is nil until the first heartbeat is seen.
+	Heartbeat *HeartbeatInfo `json:"heartbeat,omitempty"`
+
+	// NodeConstraints are the node label requirements declared at spawn.
+	// The scheduler holds dispatches while the agent's node does not
+	// satisfy them (e.g. after the node was relabeled).
+	NodeConstraints []NodeConstraint `json:"node_constraints,omitempty"`
 }
 
 // UsageMetrics contains usage metrics captured from an agent runtime.
@@ -234,6 +265,182 @@ type ProcessStats struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ResourceLimits caps the CPU and memory available to an agent's cgroup.
+// Zero values mean no limit.
+type ResourceLimits struct {
+	// CPUCores is the CPU bandwidth limit in cores (1.5 = one and a half).
+	CPUCores float64 `json:"cpu_cores,omitempty"`
+
+	// MemoryBytes is the hard memory limit in bytes.
+	MemoryBytes int64 `json:"memory_bytes,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l.CPUCores <= 0 && l.MemoryBytes <= 0
+}
+
+// ResourceUsage is a snapshot of an agent cgroup's limits and consumption.
+type ResourceUsage struct {
+	// CgroupPath is the cgroup directory the agent's pane was placed in.
+	CgroupPath string `json:"cgroup_path"`
+
+	// Limits are the limits applied to the cgroup.
+	Limits ResourceLimits `json:"limits"`
+
+	// CPUUsageUsec is total CPU time consumed, in microseconds.
+	CPUUsageUsec int64 `json:"cpu_usage_usec,omitempty"`
+
+	// CPUThrottledUsec is time spent throttled by the CPU limit.
+	CPUThrottledUsec int64 `json:"cpu_throttled_usec,omitempty"`
+
+	// MemoryBytes is current memory usage.
+	MemoryBytes int64 `json:"memory_bytes,omitempty"`
+
+	// MemoryPeakBytes is the highest memory usage seen (if the kernel reports it).
+	MemoryPeakBytes int64 `json:"memory_peak_bytes,omitempty"`
+
+	// OOMKills counts processes killed for exceeding the memory limit.
+	OOMKills int64 `json:"oom_kills,omitempty"`
+
+	// UpdatedAt is when the usage was read.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemoryFraction returns current memory usage as a fraction of the limit,
+// or 0 when no memory limit is set.
+func (u *ResourceUsage) MemoryFraction() float64 {
+	if u == nil || u.Limits.MemoryBytes <= 0 {
+		return 0
+	}
+	return float64(u.MemoryBytes) / float64(u.Limits.MemoryBytes)
+}
+
+// IsRunaway reports whether the agent has hit its memory limit (an OOM
+// kill) or is using at least threshold of it.
+func (u *ResourceUsage) IsRunaway(threshold float64) bool {
+	if u == nil {
+		return false
+	}
+	if u.OOMKills > 0 {
+		return true
+	}
+	return threshold > 0 && u.MemoryFraction() >= threshold
+}
+
+// CrashLoopReasonPrefix starts the state reason of an agent that has been
+// marked crashlooping.
+const CrashLoopReasonPrefix = "crashlooping"
+
+// CrashLoopInfo tracks consecutive agent failures. An agent is crashlooping
+// once its failures reach the configured threshold; it stays that way until
+// it completes work again or an operator resets it.
+type CrashLoopInfo struct {
+	// ConsecutiveFailures counts failures since the last healthy run.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastFailure is the most recent failure reason.
+	LastFailure string `json:"last_failure,omitempty"`
+
+	// LastFailureAt is when the most recent failure was recorded.
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+
+	// BackoffUntil is the earliest time the agent may be restarted.
+	BackoffUntil *time.Time `json:"backoff_until,omitempty"`
+
+	// CrashLooping is set once ConsecutiveFailures reaches the threshold.
+	CrashLooping bool `json:"crash_looping,omitempty"`
+}
+
+// RecoveryInfo tracks automatic recovery of an agent whose tmux session or
+// pane disappeared.
+type RecoveryInfo struct {
+	// Attempts counts recoveries since the agent last completed work.
+	Attempts int `json:"attempts"`
+
+	// LastAttemptAt is when the most recent recovery was attempted.
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+
+	// LastError is the reason the most recent recovery failed, if it did.
+	LastError string `json:"last_error,omitempty"`
+
+	// Exhausted is set once Attempts reaches the retry cap; the agent is
+	// left in the error state until an operator restarts it.
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+// TakeoverInfo records an operator's interactive control of an agent's pane.
+type TakeoverInfo struct {
+	// Operator identifies who has control (user@host).
+	Operator string `json:"operator"`
+
+	// Since is when control was taken.
+	Since time.Time `json:"since"`
+
+	// PrevPausedUntil is the pause that was in effect when control was
+	// taken; it is restored on release if it has not lapsed.
+	PrevPausedUntil *time.Time `json:"prev_paused_until,omitempty"`
+}
+
+// HeartbeatFileEnv names the file an agent's harness wrapper writes its
+// heartbeat to. It is set in the environment of every spawned agent when
+// heartbeats are enabled.
+const HeartbeatFileEnv = "FORGE_HEARTBEAT_FILE"
+
+// AgentHeartbeat is the content of a heartbeat file. Wrappers may also just
+// touch the file; its modification time then serves as the timestamp.
+type AgentHeartbeat struct {
+	// Timestamp is when the wrapper last saw the harness make progress.
+	Timestamp time.Time `json:"ts"`
+
+	// Status is an optional short status (e.g. "working", "waiting").
+	Status string `json:"status,omitempty"`
+}
+
+// HeartbeatInfo records the last heartbeat seen for an agent and whether it
+// has gone quiet for longer than its harness timeout.
+type HeartbeatInfo struct {
+	// LastBeat is the time of the most recent heartbeat.
+	LastBeat time.Time `json:"last_beat"`
+
+	// Source is where the heartbeat came from ("file" or "fmail").
+	Source string `json:"source"`
+
+	// Status is the status reported with the heartbeat, if any.
+	Status string `json:"status,omitempty"`
+
+	// Stalled is set once no heartbeat arrived within the timeout; it is
+	// cleared by the next heartbeat.
+	Stalled bool `json:"stalled,omitempty"`
+
+	// StalledAt is when the agent was marked stalled.
+	StalledAt *time.Time `json:"stalled_at,omitempty"`
+}
+
+// IsStalled reports whether the agent has been marked stalled.
+func (h *HeartbeatInfo) IsStalled() bool {
+	return h != nil && h.Stalled
+}
+
+// IsCrashLooping reports whether the agent has been marked crashlooping.
+func (c *CrashLoopInfo) IsCrashLooping() bool {
+	return c != nil && c.CrashLooping
+}
+
+// BackoffRemaining returns how long restarts are still held back at now.
+func (c *CrashLoopInfo) BackoffRemaining(now time.Time) time.Duration {
+	if c == nil || c.BackoffUntil == nil || !now.Before(*c.BackoffUntil) {
+		return 0
+	}
+	return c.BackoffUntil.Sub(now)
+}
+
+// IsCrashLoopReason reports whether a state reason was written for a
+// crashlooping agent.
+func IsCrashLoopReason(reason string) bool {
+	return strings.HasPrefix(strings.TrimSpace(reason), CrashLoopReasonPrefix)
+}
+
 // OpenCodeConnection contains connection details for an OpenCode server instance.
 // Each OpenCode agent runs its own server on a dedicated port.
 type OpenCodeConnection struct {
@@ -300,6 +507,9 @@ func (a *Agent) IsActive() bool {
 
 // IsBlocked returns true if the agent is blocked and cannot accept work.
 func (a *Agent) IsBlocked() bool {
+	if a.Metadata.CrashLoop.IsCrashLooping() || a.Metadata.Heartbeat.IsStalled() {
+		return true
+	}
 	switch a.State {
 	case AgentStateAwaitingApproval, AgentStateRateLimited, AgentStateError, AgentStatePaused:
 		return true
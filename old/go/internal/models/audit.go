@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditSource identifies the surface a mutating action came through.
+type AuditSource string
+
+const (
+	AuditSourceCLI AuditSource = "cli"
+	AuditSourceAPI AuditSource = "api"
+)
+
+// AuditResult is the outcome of an audited action.
+type AuditResult string
+
+const (
+	AuditResultOK    AuditResult = "ok"
+	AuditResultError AuditResult = "error"
+)
+
+// AuditEntry records one mutating request: who ran it, what it was, a hash
+// of its payload, and how it ended. Payloads themselves are not stored
+// since they may carry prompts or secrets.
+type AuditEntry struct {
+	// ID is the unique identifier for the entry.
+	ID string `json:"id"`
+
+	// Timestamp is when the action started.
+	Timestamp time.Time `json:"timestamp"`
+
+	// RequestID groups everything done on behalf of one request.
+	RequestID string `json:"request_id"`
+
+	// Actor is who performed the action (FORGE_ACTOR, or user@host).
+	Actor string `json:"actor"`
+
+	// Source is the surface the action came through.
+	Source AuditSource `json:"source"`
+
+	// Action names the operation, e.g. "loop stop" or "SpawnAgent".
+	Action string `json:"action"`
+
+	// Target is the primary entity the action was aimed at, if known.
+	Target string `json:"target,omitempty"`
+
+	// PayloadHash is the SHA-256 of the canonical request payload.
+	PayloadHash string `json:"payload_hash,omitempty"`
+
+	// Result is the outcome of the action.
+	Result AuditResult `json:"result"`
+
+	// Error holds the error message when Result is error.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the action took.
+	Duration time.Duration `json:"duration"`
+}
+
+// Validate checks that the entry has the fields every audit record needs.
+func (e *AuditEntry) Validate() error {
+	validation := &ValidationErrors{}
+	if strings.TrimSpace(e.Actor) == "" {
+		validation.AddMessage("actor", "actor is required")
+	}
+	if strings.TrimSpace(e.Action) == "" {
+		validation.AddMessage("action", "action is required")
+	}
+	switch e.Source {
+	case AuditSourceCLI, AuditSourceAPI:
+	default:
+		validation.AddMessage("source", "source must be cli or api")
+	}
+	switch e.Result {
+	case AuditResultOK, AuditResultError:
+	default:
+		validation.AddMessage("result", "result must be ok or error")
+	}
+	return validation.Err()
+}
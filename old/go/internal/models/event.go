@@ -11,24 +11,37 @@ type EventType string
 
 const (
 	// Node events
-	EventTypeNodeOnline  EventType = "node.online"
-	EventTypeNodeOffline EventType = "node.offline"
-	EventTypeNodeAdded   EventType = "node.added"
-	EventTypeNodeRemoved EventType = "node.removed"
+	EventTypeNodeOnline        EventType = "node.online"
+	EventTypeNodeOffline       EventType = "node.offline"
+	EventTypeNodeAdded         EventType = "node.added"
+	EventTypeNodeRemoved       EventType = "node.removed"
+	EventTypeNodeLabelsChanged EventType = "node.labels_changed"
+	EventTypeNodeDiskCritical  EventType = "node.disk_critical"
 
 	// Workspace events
-	EventTypeWorkspaceCreated   EventType = "workspace.created"
-	EventTypeWorkspaceImported  EventType = "workspace.imported"
-	EventTypeWorkspaceDestroyed EventType = "workspace.destroyed"
-	EventTypeWorkspaceUnmanaged EventType = "workspace.unmanaged"
+	EventTypeWorkspaceCreated       EventType = "workspace.created"
+	EventTypeWorkspaceImported      EventType = "workspace.imported"
+	EventTypeWorkspaceDestroyed     EventType = "workspace.destroyed"
+	EventTypeWorkspaceUnmanaged     EventType = "workspace.unmanaged"
+	EventTypeWorkspaceSynced        EventType = "workspace.synced"
+	EventTypeWorkspaceSnapshotted   EventType = "workspace.snapshotted"
+	EventTypeWorkspaceRestored      EventType = "workspace.restored"
+	EventTypeWorkspaceQuotaExceeded EventType = "workspace.quota_exceeded"
 
 	// Agent events
-	EventTypeAgentSpawned      EventType = "agent.spawned"
-	EventTypeAgentStateChanged EventType = "agent.state_changed"
-	EventTypeAgentRestarted    EventType = "agent.restarted"
-	EventTypeAgentTerminated   EventType = "agent.terminated"
-	EventTypeAgentPaused       EventType = "agent.paused"
-	EventTypeAgentResumed      EventType = "agent.resumed"
+	EventTypeAgentSpawned         EventType = "agent.spawned"
+	EventTypeAgentStateChanged    EventType = "agent.state_changed"
+	EventTypeAgentRestarted       EventType = "agent.restarted"
+	EventTypeAgentTerminated      EventType = "agent.terminated"
+	EventTypeAgentPaused          EventType = "agent.paused"
+	EventTypeAgentResumed         EventType = "agent.resumed"
+	EventTypeAgentCrashLooping    EventType = "agent.crashlooping"
+	EventTypeAgentRecovered       EventType = "agent.recovered"
+	EventTypeAgentRecoveryFailed  EventType = "agent.recovery_failed"
+	EventTypeAgentControlTaken    EventType = "agent.control_taken"
+	EventTypeAgentControlReleased EventType = "agent.control_released"
+	EventTypeAgentStalled         EventType = "agent.stalled"
+	EventTypeAgentAlive           EventType = "agent.alive"
 
 	// Message events
 	EventTypeMessageQueued     EventType = "message.queued"
@@ -36,6 +49,10 @@ const (
 	EventTypeMessageCompleted  EventType = "message.completed"
 	EventTypeMessageFailed     EventType = "message.failed"
 
+	// Queue deadline events
+	EventTypeQueueDeadlineWarning EventType = "queue.deadline_warning"
+	EventTypeQueueDeadlineMissed  EventType = "queue.deadline_missed"
+
 	// Approval events
 	EventTypeApprovalRequested EventType = "approval.requested"
 	EventTypeApprovalApproved  EventType = "approval.approved"
@@ -47,9 +64,16 @@ const (
 	EventTypeCooldownEnded     EventType = "cooldown.ended"
 	EventTypeAccountRotated    EventType = "account.rotated"
 
+	// Loop events
+	EventTypeLoopProfileSwitched EventType = "loop.profile_switched"
+	EventTypeLoopStateChanged    EventType = "loop.state_changed"
+	EventTypeLoopRunFailed       EventType = "loop.run_failed"
+	EventTypeLoopPaused          EventType = "loop.paused"
+
 	// System events
-	EventTypeError   EventType = "error"
-	EventTypeWarning EventType = "warning"
+	EventTypeError      EventType = "error"
+	EventTypeWarning    EventType = "warning"
+	EventTypeReconciled EventType = "system.reconciled"
 )
 
 // EntityType identifies the type of entity an event relates to.
@@ -62,6 +86,7 @@ const (
 	EntityTypeQueue     EntityType = "queue"
 	EntityTypeAccount   EntityType = "account"
 	EntityTypeSystem    EntityType = "system"
+	EntityTypeLoop      EntityType = "loop"
 )
 
 // Event represents an append-only log entry.
@@ -81,6 +106,9 @@ type Event struct {
 	// EntityID is the ID of the related entity.
 	EntityID string `json:"entity_id"`
 
+	// Namespace is the tenant the event belongs to.
+	Namespace string `json:"namespace,omitempty"`
+
 	// Payload contains event-specific data.
 	Payload json.RawMessage `json:"payload,omitempty"`
 
@@ -111,6 +139,43 @@ type StateChangedPayload struct {
 	Reason     string          `json:"reason"`
 }
 
+// CrashLoopPayload is the payload for agent.crashlooping events.
+type CrashLoopPayload struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastFailure         string    `json:"last_failure,omitempty"`
+	BackoffUntil        time.Time `json:"backoff_until"`
+}
+
+// AgentRecoveryPayload is the payload for agent.recovered and
+// agent.recovery_failed events.
+type AgentRecoveryPayload struct {
+	Attempt     int    `json:"attempt"`
+	MaxAttempts int    `json:"max_attempts"`
+	OldPane     string `json:"old_pane,omitempty"`
+	NewPane     string `json:"new_pane,omitempty"`
+	Session     string `json:"session,omitempty"`
+	WorkDir     string `json:"work_dir,omitempty"`
+	Command     string `json:"command,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Exhausted   bool   `json:"exhausted,omitempty"`
+}
+
+// AgentControlPayload is the payload for agent.control_taken and
+// agent.control_released events.
+type AgentControlPayload struct {
+	Operator string     `json:"operator"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+// AgentHeartbeatPayload is the payload for agent.stalled and agent.alive
+// events.
+type AgentHeartbeatPayload struct {
+	LastBeat time.Time `json:"last_beat"`
+	Source   string    `json:"source"`
+	Status   string    `json:"status,omitempty"`
+	Timeout  string    `json:"timeout"`
+}
+
 // MessageQueuedPayload is the payload for message.queued events.
 type MessageQueuedPayload struct {
 	QueueItemID string        `json:"queue_item_id"`
@@ -143,6 +208,18 @@ type MessageFailedPayload struct {
 	Attempts    int           `json:"attempts"`
 }
 
+// QueueDeadlinePayload is the payload for queue.deadline_warning and
+// queue.deadline_missed events.
+type QueueDeadlinePayload struct {
+	QueueItemID string        `json:"queue_item_id"`
+	ItemType    QueueItemType `json:"item_type"`
+	AgentID     string        `json:"agent_id"`
+	Deadline    time.Time     `json:"deadline"`
+	// Dispatched is true when a missed item has been dispatched late, false
+	// while it is still waiting.
+	Dispatched bool `json:"dispatched,omitempty"`
+}
+
 // RateLimitPayload is the payload for rate_limit.detected events.
 type RateLimitPayload struct {
 	AccountID       string   `json:"account_id"`
@@ -159,6 +236,63 @@ type AccountRotatedPayload struct {
 	Reason       string `json:"reason"`
 }
 
+// LoopProfileSwitchedPayload is the payload for loop.profile_switched events.
+type LoopProfileSwitchedPayload struct {
+	LoopID         string  `json:"loop_id"`
+	OldProfileID   string  `json:"old_profile_id,omitempty"`
+	OldProfileName string  `json:"old_profile_name,omitempty"`
+	OldHarness     Harness `json:"old_harness,omitempty"`
+	NewProfileID   string  `json:"new_profile_id"`
+	NewProfileName string  `json:"new_profile_name"`
+	NewHarness     Harness `json:"new_harness"`
+	QueueMode      string  `json:"queue_mode"`
+	Migrated       int     `json:"migrated"`
+	Dropped        int     `json:"dropped"`
+	Restarted      bool    `json:"restarted"`
+	Reason         string  `json:"reason,omitempty"`
+}
+
+// LoopEventPayload is the payload for loop.state_changed, loop.run_failed,
+// and loop.paused events.
+type LoopEventPayload struct {
+	LoopID        string     `json:"loop_id"`
+	LoopName      string     `json:"loop_name"`
+	State         LoopState  `json:"state"`
+	PreviousState LoopState  `json:"previous_state,omitempty"`
+	RunID         string     `json:"run_id,omitempty"`
+	ExitCode      *int       `json:"exit_code,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	Until         *time.Time `json:"until,omitempty"`
+}
+
+// DiskCriticalPayload is the payload for node.disk_critical events.
+type DiskCriticalPayload struct {
+	NodeName string `json:"node_name"`
+	Details  string `json:"details"`
+}
+
+// WorkspaceQuotaPayload is the payload for workspace.quota_exceeded events.
+type WorkspaceQuotaPayload struct {
+	Resource  QuotaResource `json:"resource"`
+	Limit     int64         `json:"limit"`
+	Current   int64         `json:"current"`
+	Requested int64         `json:"requested"`
+	Message   string        `json:"message"`
+}
+
+// ReconcilePayload is the payload for system.reconciled events.
+type ReconcilePayload struct {
+	Trigger       string         `json:"trigger"`
+	Fix           bool           `json:"fix"`
+	LoopsChecked  int            `json:"loops_checked"`
+	AgentsChecked int            `json:"agents_checked"`
+	PanesChecked  int            `json:"panes_checked"`
+	Findings      int            `json:"findings"`
+	Fixed         int            `json:"fixed"`
+	Kinds         map[string]int `json:"kinds,omitempty"`
+}
+
 // ErrorPayload is the payload for error events.
 type ErrorPayload struct {
 	Error      string `json:"error"`
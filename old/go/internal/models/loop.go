@@ -21,6 +21,7 @@ type Loop struct {
 	ID                string         `json:"id"`
 	ShortID           string         `json:"short_id"`
 	Name              string         `json:"name"`
+	Namespace         string         `json:"namespace,omitempty"`
 	RepoPath          string         `json:"repo_path"`
 	BasePromptPath    string         `json:"base_prompt_path,omitempty"`
 	BasePromptMsg     string         `json:"base_prompt_msg,omitempty"`
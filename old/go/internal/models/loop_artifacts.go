@@ -0,0 +1,23 @@
+package models
+
+// LoopArtifactsConfig declares files copied out of the repo after each loop
+// iteration.
+//
+// Stored inside Loop.Metadata as JSON under the "artifacts" key.
+type LoopArtifactsConfig struct {
+	// Globs are repo-relative patterns. "**" matches any number of
+	// directories; a pattern matching a directory captures all files in it.
+	Globs []string `json:"globs,omitempty"`
+}
+
+// IsZero reports whether no artifact globs are configured.
+func (c LoopArtifactsConfig) IsZero() bool {
+	return len(c.Globs) == 0
+}
+
+// LoopRunArtifact is a file captured into a run's artifact directory.
+type LoopRunArtifact struct {
+	// Path is relative to both the repo and the run's artifact directory.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
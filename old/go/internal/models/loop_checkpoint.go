@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoopCheckpointMode selects how a loop snapshots its repo before each
+// iteration.
+//
+// Stored inside Loop.Metadata as a string under the "checkpoint" key.
+type LoopCheckpointMode string
+
+const (
+	// LoopCheckpointOff takes no checkpoints.
+	LoopCheckpointOff LoopCheckpointMode = ""
+	// LoopCheckpointCommit writes the working tree, untracked files
+	// included, to a commit under refs/forge/checkpoints/ without touching
+	// HEAD, the index, or the working tree.
+	LoopCheckpointCommit LoopCheckpointMode = "commit"
+	// LoopCheckpointStash records uncommitted changes to tracked files as a
+	// stash entry, leaving the working tree as it is.
+	LoopCheckpointStash LoopCheckpointMode = "stash"
+)
+
+// ParseLoopCheckpointMode validates a checkpoint mode; "off" and "" both
+// disable checkpoints.
+func ParseLoopCheckpointMode(value string) (LoopCheckpointMode, error) {
+	switch mode := LoopCheckpointMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case LoopCheckpointOff, "off":
+		return LoopCheckpointOff, nil
+	case LoopCheckpointCommit, LoopCheckpointStash:
+		return mode, nil
+	default:
+		return LoopCheckpointOff, fmt.Errorf("invalid checkpoint mode %q (valid: off, commit, stash)", value)
+	}
+}
+
+// LoopCheckpoint records the git checkpoint taken before a run.
+//
+// Stored inside LoopRun.Metadata under "checkpoint".
+type LoopCheckpoint struct {
+	Mode LoopCheckpointMode `json:"mode"`
+	// SHA is the checkpoint commit. For a clean working tree it is HEAD.
+	SHA string `json:"sha,omitempty"`
+	// Head is HEAD when the checkpoint was taken.
+	Head string `json:"head,omitempty"`
+	// Ref names the checkpoint: a refs/forge/checkpoints/ ref in commit mode,
+	// the stash entry's message in stash mode.
+	Ref   string `json:"ref,omitempty"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// LoopHooksConfig configures commands run around each loop iteration.
+//
+// Stored inside Loop.Metadata as JSON under the "hooks" key.
+type LoopHooksConfig struct {
+	// PreRun is executed via `bash -lc` before each iteration starts. A leading
+	// relative script path is resolved against the repo root.
+	PreRun string `json:"pre_run,omitempty"`
+
+	// PostRun is executed the same way after each iteration completes.
+	PostRun string `json:"post_run,omitempty"`
+
+	// TimeoutSeconds caps each hook's runtime (0 = no extra timeout).
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// IsZero reports whether no hooks are configured.
+func (c LoopHooksConfig) IsZero() bool {
+	return c.PreRun == "" && c.PostRun == ""
+}
+
+// LoopHookResult records a failed hook on a loop run.
+//
+// Stored inside LoopRun.Metadata under "hooks", keyed by "pre_run" or "post_run".
+type LoopHookResult struct {
+	Cmd      string `json:"cmd"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+}
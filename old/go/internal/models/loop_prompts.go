@@ -0,0 +1,46 @@
+package models
+
+import "strings"
+
+// Prompt playlist strategies.
+const (
+	PromptStrategySequential = "sequential"
+	PromptStrategyRandom     = "random"
+)
+
+// LoopPromptPlaylist is an ordered list of prompt files a loop rotates
+// through, one per iteration, in place of a single base prompt.
+//
+// Stored inside Loop.Metadata as JSON under the "prompt_playlist" key.
+type LoopPromptPlaylist struct {
+	// Prompts are prompt file paths, absolute or relative to the repo.
+	Prompts []string `json:"prompts,omitempty"`
+
+	// Strategy is "sequential" (in order, wrapping around) or "random".
+	// Empty means sequential.
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// IsZero reports whether the playlist has no prompts.
+func (p LoopPromptPlaylist) IsZero() bool {
+	return len(p.Prompts) == 0
+}
+
+// NormalizedStrategy returns the playlist strategy, defaulting to
+// sequential.
+func (p LoopPromptPlaylist) NormalizedStrategy() string {
+	if strings.EqualFold(strings.TrimSpace(p.Strategy), PromptStrategyRandom) {
+		return PromptStrategyRandom
+	}
+	return PromptStrategySequential
+}
+
+// ValidPromptStrategy reports whether value names a playlist strategy.
+func ValidPromptStrategy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", PromptStrategySequential, PromptStrategyRandom:
+		return true
+	default:
+		return false
+	}
+}
@@ -18,6 +18,7 @@ const (
 	LoopQueueItemStopGraceful       LoopQueueItemType = "stop_graceful"
 	LoopQueueItemKillNow            LoopQueueItemType = "kill_now"
 	LoopQueueItemSteerMessage       LoopQueueItemType = "steer_message"
+	LoopQueueItemResume             LoopQueueItemType = "resume"
 )
 
 // LoopQueueItemStatus represents the status of a loop queue item.
@@ -44,6 +45,15 @@ type LoopQueueItem struct {
 	DispatchedAt *time.Time          `json:"dispatched_at,omitempty"`
 	CompletedAt  *time.Time          `json:"completed_at,omitempty"`
 	Error        string              `json:"error,omitempty"`
+
+	// NotBefore is the earliest time the item may be dispatched. Nil means
+	// the item is due immediately.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+}
+
+// IsDue reports whether the item may be dispatched at now.
+func (q *LoopQueueItem) IsDue(now time.Time) bool {
+	return q.NotBefore == nil || !q.NotBefore.After(now)
 }
 
 // MessageAppendPayload appends a message to the prompt.
@@ -57,7 +67,8 @@ type NextPromptOverridePayload struct {
 	IsPath bool   `json:"is_path"`
 }
 
-// LoopPausePayload pauses the loop for a duration.
+// LoopPausePayload pauses the loop between iterations without exiting the
+// runner. A zero duration pauses until a resume item is queued.
 type LoopPausePayload struct {
 	DurationSeconds int    `json:"duration_seconds"`
 	Reason          string `json:"reason,omitempty"`
@@ -73,6 +84,11 @@ type KillPayload struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// ResumePayload ends a pause early.
+type ResumePayload struct {
+	Reason string `json:"reason,omitempty"`
+}
+
 // SteerPayload requests an interrupt + message.
 type SteerPayload struct {
 	Message string `json:"message"`
@@ -116,8 +132,8 @@ func (q *LoopQueueItem) Validate() error {
 		if err := json.Unmarshal(q.Payload, &payload); err != nil {
 			return fmt.Errorf("invalid pause payload: %w", err)
 		}
-		if payload.DurationSeconds <= 0 {
-			return errors.New("pause payload duration_seconds must be > 0")
+		if payload.DurationSeconds < 0 {
+			return errors.New("pause payload duration_seconds must be >= 0")
 		}
 	case LoopQueueItemStopGraceful:
 		var payload StopPayload
@@ -129,6 +145,11 @@ func (q *LoopQueueItem) Validate() error {
 		if err := json.Unmarshal(q.Payload, &payload); err != nil {
 			return fmt.Errorf("invalid kill_now payload: %w", err)
 		}
+	case LoopQueueItemResume:
+		var payload ResumePayload
+		if err := json.Unmarshal(q.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid resume payload: %w", err)
+		}
 	case LoopQueueItemSteerMessage:
 		var payload SteerPayload
 		if err := json.Unmarshal(q.Payload, &payload); err != nil {
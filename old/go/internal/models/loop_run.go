@@ -26,4 +26,33 @@ type LoopRun struct {
 	ExitCode       *int           `json:"exit_code,omitempty"`
 	OutputTail     string         `json:"output_tail,omitempty"`
 	Metadata       map[string]any `json:"metadata,omitempty"`
+
+	// Token usage and cost reported by the harness; zero when the harness
+	// output carried none.
+	InputTokens  int64   `json:"input_tokens,omitempty"`
+	OutputTokens int64   `json:"output_tokens,omitempty"`
+	TotalTokens  int64   `json:"total_tokens,omitempty"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+
+	// ArtifactDir holds files captured by the loop's artifact globs after
+	// the run; empty when nothing was captured.
+	ArtifactDir string            `json:"artifact_dir,omitempty"`
+	Artifacts   []LoopRunArtifact `json:"artifacts,omitempty"`
+}
+
+// HasUsage reports whether any token usage or cost was recorded for the run.
+func (r *LoopRun) HasUsage() bool {
+	return r != nil && (r.InputTokens > 0 || r.OutputTokens > 0 || r.TotalTokens > 0 || r.CostUSD > 0)
+}
+
+// LoopRunUsageSummary aggregates run usage for one loop or profile.
+type LoopRunUsageSummary struct {
+	// Key is the loop ID or profile ID the runs are grouped by.
+	Key           string  `json:"key"`
+	Runs          int     `json:"runs"`
+	RunsWithUsage int     `json:"runs_with_usage"`
+	InputTokens   int64   `json:"input_tokens"`
+	OutputTokens  int64   `json:"output_tokens"`
+	TotalTokens   int64   `json:"total_tokens"`
+	CostUSD       float64 `json:"cost_usd"`
 }
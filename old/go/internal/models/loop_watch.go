@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LoopWatch is a named shell expression the loop TUI evaluates on each
+// refresh and shows in the overview pane, e.g.
+// `git -C $REPO status --porcelain | wc -l`.
+//
+// Stored inside Loop.Metadata as a JSON list under the "watches" key.
+type LoopWatch struct {
+	// Name labels the value in the overview pane.
+	Name string `json:"name"`
+
+	// Expr is executed via `bash -lc` in the repo root with REPO, LOOP_ID,
+	// and LOOP_NAME set. The first line of stdout is displayed.
+	Expr string `json:"expr"`
+}
+
+// LoopWatchesKey is the Loop.Metadata key holding the watch list.
+const LoopWatchesKey = "watches"
+
+// LoopWatches decodes the watch list from loop metadata. Malformed entries
+// are dropped.
+func LoopWatches(loopEntry *Loop) []LoopWatch {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return nil
+	}
+	raw, ok := loopEntry.Metadata[LoopWatchesKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var watches []LoopWatch
+	if err := json.Unmarshal(data, &watches); err != nil {
+		return nil
+	}
+	out := watches[:0]
+	for _, watch := range watches {
+		if strings.TrimSpace(watch.Name) == "" || strings.TrimSpace(watch.Expr) == "" {
+			continue
+		}
+		out = append(out, watch)
+	}
+	return out
+}
+
+// SetLoopWatches stores the watch list in loop metadata, removing the key
+// when the list is empty.
+func SetLoopWatches(loopEntry *Loop, watches []LoopWatch) {
+	if len(watches) == 0 {
+		delete(loopEntry.Metadata, LoopWatchesKey)
+		return
+	}
+	if loopEntry.Metadata == nil {
+		loopEntry.Metadata = make(map[string]any)
+	}
+	loopEntry.Metadata[LoopWatchesKey] = watches
+}
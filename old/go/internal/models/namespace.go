@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultNamespace holds every workspace, agent, loop, and event created
+// without an explicit namespace, including all rows that predate namespaces.
+const DefaultNamespace = "default"
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,62}[a-z0-9])?$`)
+
+// NormalizeNamespace lowercases and validates a namespace name. Empty input
+// is the default namespace. Names are 1-64 characters of a-z, 0-9, '-' and
+// '_', starting and ending with a letter or digit.
+func NormalizeNamespace(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" {
+		return DefaultNamespace, nil
+	}
+	if !namespacePattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid namespace %q: use 1-64 characters of a-z, 0-9, '-' and '_'", name)
+	}
+	return normalized, nil
+}
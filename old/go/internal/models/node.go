@@ -96,6 +96,10 @@ type Node struct {
 	// AgentCount is the number of agents currently running on this node.
 	AgentCount int `json:"agent_count"`
 
+	// Labels are operator-assigned key/value pairs (e.g. gpu=true,
+	// region=eu) matched by placement constraints.
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Metadata contains additional node information.
 	Metadata NodeMetadata `json:"metadata,omitempty"`
 
@@ -136,5 +140,10 @@ func (n *Node) Validate() error {
 	if !n.IsLocal && n.SSHTarget == "" {
 		validation.Add("ssh_target", ErrInvalidSSHTarget)
 	}
+	for key := range n.Labels {
+		if err := ValidateNodeLabelKey(key); err != nil {
+			validation.Add("labels", err)
+		}
+	}
 	return validation.Err()
 }
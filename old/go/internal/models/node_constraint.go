@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeConstraint is a placement requirement on a node label, written as
+// "key=value", "key!=value", or "key" (label present with any value).
+type NodeConstraint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Negate bool   `json:"negate,omitempty"`
+	// Exists matches any value; Value is ignored.
+	Exists bool `json:"exists,omitempty"`
+}
+
+// String renders the constraint in the form ParseNodeConstraint accepts.
+func (c NodeConstraint) String() string {
+	switch {
+	case c.Exists:
+		return c.Key
+	case c.Negate:
+		return c.Key + "!=" + c.Value
+	default:
+		return c.Key + "=" + c.Value
+	}
+}
+
+// Matches reports whether labels satisfy the constraint. A "!=" constraint
+// is satisfied when the label is missing.
+func (c NodeConstraint) Matches(labels map[string]string) bool {
+	value, ok := labels[c.Key]
+	switch {
+	case c.Exists:
+		return ok
+	case c.Negate:
+		return !ok || value != c.Value
+	default:
+		return ok && value == c.Value
+	}
+}
+
+// ParseNodeConstraint parses a single constraint expression.
+func ParseNodeConstraint(expr string) (NodeConstraint, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return NodeConstraint{}, fmt.Errorf("empty node constraint")
+	}
+	var c NodeConstraint
+	if key, value, ok := strings.Cut(expr, "!="); ok {
+		c = NodeConstraint{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Negate: true}
+	} else if key, value, ok := strings.Cut(expr, "="); ok {
+		c = NodeConstraint{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)}
+	} else {
+		c = NodeConstraint{Key: expr, Exists: true}
+	}
+	if err := ValidateNodeLabelKey(c.Key); err != nil {
+		return NodeConstraint{}, fmt.Errorf("invalid node constraint %q: %w", expr, err)
+	}
+	return c, nil
+}
+
+// ParseNodeConstraints parses a list of constraint expressions.
+func ParseNodeConstraints(exprs []string) ([]NodeConstraint, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	constraints := make([]NodeConstraint, 0, len(exprs))
+	for _, expr := range exprs {
+		c, err := ParseNodeConstraint(expr)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// FormatNodeConstraints renders constraints as a comma-separated list.
+func FormatNodeConstraints(constraints []NodeConstraint) string {
+	parts := make([]string, len(constraints))
+	for i, c := range constraints {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// SatisfiesConstraints reports whether the node's labels match every
+// constraint. A node always satisfies an empty constraint list.
+func (n *Node) SatisfiesConstraints(constraints []NodeConstraint) bool {
+	for _, c := range constraints {
+		if !c.Matches(n.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateNodeLabelKey checks that a label key is non-empty and made of
+// letters, digits, '.', '-', '_' and '/'.
+func ValidateNodeLabelKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("label key is required")
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '-', r == '_', r == '/':
+		default:
+			return fmt.Errorf("label key %q contains invalid character %q", key, r)
+		}
+	}
+	return nil
+}
+
+// ParseNodeLabel parses a "key=value" label assignment.
+func ParseNodeLabel(expr string) (string, string, error) {
+	key, value, ok := strings.Cut(strings.TrimSpace(expr), "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid label %q (expected key=value)", expr)
+	}
+	key = strings.TrimSpace(key)
+	if err := ValidateNodeLabelKey(key); err != nil {
+		return "", "", err
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// FormatNodeLabels renders labels as sorted "key=value" pairs.
+func FormatNodeLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + labels[key]
+	}
+	return strings.Join(parts, ",")
+}
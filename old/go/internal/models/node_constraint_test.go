@@ -0,0 +1,59 @@
+package models
+
+import "testing"
+
+func TestParseNodeConstraint(t *testing.T) {
+	tests := []struct {
+		expr string
+		want NodeConstraint
+	}{
+		{"gpu=true", NodeConstraint{Key: "gpu", Value: "true"}},
+		{" region != eu ", NodeConstraint{Key: "region", Value: "eu", Negate: true}},
+		{"ssd", NodeConstraint{Key: "ssd", Exists: true}},
+		{"zone=", NodeConstraint{Key: "zone", Value: ""}},
+	}
+	for _, tt := range tests {
+		got, err := ParseNodeConstraint(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseNodeConstraint(%q): %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseNodeConstraint(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+		if reparsed, _ := ParseNodeConstraint(got.String()); reparsed != got {
+			t.Fatalf("String() of %+v does not round-trip: %q", got, got.String())
+		}
+	}
+
+	for _, bad := range []string{"", "=true", "has space=1", "!=x"} {
+		if _, err := ParseNodeConstraint(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestNodeSatisfiesConstraints(t *testing.T) {
+	node := &Node{Labels: map[string]string{"gpu": "true", "region": "eu"}}
+	tests := []struct {
+		exprs []string
+		want  bool
+	}{
+		{nil, true},
+		{[]string{"gpu=true", "region=eu"}, true},
+		{[]string{"gpu=false"}, false},
+		{[]string{"region!=us"}, true},
+		{[]string{"region!=eu"}, false},
+		{[]string{"arch!=arm64"}, true},
+		{[]string{"gpu"}, true},
+		{[]string{"ssd"}, false},
+	}
+	for _, tt := range tests {
+		constraints, err := ParseNodeConstraints(tt.exprs)
+		if err != nil {
+			t.Fatalf("ParseNodeConstraints(%v): %v", tt.exprs, err)
+		}
+		if got := node.SatisfiesConstraints(constraints); got != tt.want {
+			t.Fatalf("SatisfiesConstraints(%v) = %v, want %v", tt.exprs, got, tt.want)
+		}
+	}
+}
@@ -38,9 +38,24 @@ type Profile struct {
 	ExtraArgs       []string          `json:"extra_args,omitempty"`
 	Env             map[string]string `json:"env,omitempty"`
 	MaxConcurrency  int               `json:"max_concurrency"`
-	CooldownUntil   *time.Time        `json:"cooldown_until,omitempty"`
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
+	// MaxRunsPerHour caps how many runs may start on this profile in any
+	// rolling hour (0 = unlimited).
+	MaxRunsPerHour int `json:"max_runs_per_hour,omitempty"`
+	// MinRunGapSeconds is the minimum time between run starts (0 = none).
+	MinRunGapSeconds int        `json:"min_run_gap_seconds,omitempty"`
+	CooldownUntil    *time.Time `json:"cooldown_until,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// MinRunGap returns the minimum gap between run starts.
+func (p *Profile) MinRunGap() time.Duration {
+	return time.Duration(p.MinRunGapSeconds) * time.Second
+}
+
+// HasRateLimit reports whether the profile limits how often runs start.
+func (p *Profile) HasRateLimit() bool {
+	return p.MaxRunsPerHour > 0 || p.MinRunGapSeconds > 0
 }
 
 // Validate checks if the profile configuration is valid.
@@ -55,6 +70,12 @@ func (p *Profile) Validate() error {
 	if p.MaxConcurrency < 0 {
 		validation.AddMessage("max_concurrency", "max_concurrency must be >= 0")
 	}
+	if p.MaxRunsPerHour < 0 {
+		validation.AddMessage("max_runs_per_hour", "max_runs_per_hour must be >= 0")
+	}
+	if p.MinRunGapSeconds < 0 {
+		validation.AddMessage("min_run_gap_seconds", "min_run_gap_seconds must be >= 0")
+	}
 	if validation.Err() != nil {
 		return validation.Err()
 	}
@@ -65,6 +65,105 @@ type QueueItem struct {
 
 	// Error contains error details (if failed).
 	Error string `json:"error,omitempty"`
+
+	// Deadline is when the item should be dispatched by (optional).
+	Deadline *time.Time `json:"deadline,omitempty"`
+
+	// DeadlineMissed is set when the item was dispatched after its deadline.
+	DeadlineMissed bool `json:"deadline_missed,omitempty"`
+
+	// RetryPolicy governs redispatch after a failed attempt. It is stamped
+	// from the item type's policy at enqueue time; nil uses the scheduler's
+	// current policy for the type.
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// DeadLetteredAt is when the item exhausted its retries and moved to the
+	// dead-letter queue (status failed).
+	DeadLetteredAt *time.Time `json:"dead_lettered_at,omitempty"`
+}
+
+// RetryPolicyKind selects how a failed queue item is retried.
+type RetryPolicyKind string
+
+const (
+	// RetryPolicyImmediate redispatches on the next scheduler tick.
+	RetryPolicyImmediate RetryPolicyKind = "immediate"
+	// RetryPolicyExponential waits Backoff, doubling on each further retry.
+	RetryPolicyExponential RetryPolicyKind = "exponential"
+	// RetryPolicyNone dead-letters the item on its first failure.
+	RetryPolicyNone RetryPolicyKind = "none"
+)
+
+// ParseRetryPolicyKind parses a policy name, case-insensitively.
+func ParseRetryPolicyKind(value string) (RetryPolicyKind, error) {
+	switch kind := RetryPolicyKind(strings.ToLower(strings.TrimSpace(value))); kind {
+	case RetryPolicyImmediate, RetryPolicyExponential, RetryPolicyNone:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown retry policy %q (expected immediate, exponential, or none)", value)
+	}
+}
+
+// RetryPolicy describes how many times and how quickly a failed queue item
+// is redispatched before it is dead-lettered.
+type RetryPolicy struct {
+	Kind RetryPolicyKind `json:"kind"`
+
+	// MaxRetries is the number of redispatches after the first failure.
+	// Ignored for RetryPolicyNone.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Backoff is the delay before the first exponential retry.
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+// DefaultRetryPolicies returns the built-in policy for each queue item type.
+// Pauses have nothing to retry.
+func DefaultRetryPolicies() map[QueueItemType]RetryPolicy {
+	exponential := RetryPolicy{Kind: RetryPolicyExponential, MaxRetries: 3, Backoff: 5 * time.Second}
+	return map[QueueItemType]RetryPolicy{
+		QueueItemTypeMessage:     exponential,
+		QueueItemTypeConditional: exponential,
+		QueueItemTypePause:       {Kind: RetryPolicyNone},
+	}
+}
+
+// Validate checks if the retry policy is valid.
+func (p RetryPolicy) Validate() error {
+	validation := &ValidationErrors{}
+	if _, err := ParseRetryPolicyKind(string(p.Kind)); err != nil {
+		validation.AddMessage("kind", err.Error())
+	}
+	if p.MaxRetries < 0 {
+		validation.AddMessage("max_retries", "max_retries must be greater than or equal to 0")
+	}
+	if p.Backoff < 0 {
+		validation.AddMessage("backoff", "backoff must be greater than or equal to 0")
+	}
+	if p.Kind == RetryPolicyExponential && p.Backoff == 0 {
+		validation.AddMessage("backoff", "backoff is required for exponential retries")
+	}
+	return validation.Err()
+}
+
+// Retries returns how many redispatches the policy allows.
+func (p RetryPolicy) Retries() int {
+	if p.Kind == RetryPolicyNone || p.MaxRetries < 0 {
+		return 0
+	}
+	return p.MaxRetries
+}
+
+// Delay returns how long to wait before retry number attempt (1-based).
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if p.Kind != RetryPolicyExponential || p.Backoff <= 0 {
+		return 0
+	}
+	delay := p.Backoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+	}
+	return delay
 }
 
 // MessagePayload is the payload for message queue items.
@@ -153,6 +252,9 @@ func (q *QueueItem) Validate() error {
 	if len(q.Payload) == 0 {
 		validation.Add("payload", ErrInvalidQueueItem)
 	}
+	if q.RetryPolicy != nil {
+		validation.Add("retry_policy", q.RetryPolicy.Validate())
+	}
 
 	if q.Type != "" && len(q.Payload) > 0 {
 		switch q.Type {
@@ -22,6 +22,9 @@ type Workspace struct {
 	// Name is the human-friendly name for the workspace.
 	Name string `json:"name"`
 
+	// Namespace is the tenant that owns the workspace.
+	Namespace string `json:"namespace,omitempty"`
+
 	// NodeID references the node where this workspace lives.
 	NodeID string `json:"node_id"`
 
@@ -72,6 +75,16 @@ type GitInfo struct {
 	// IsDirty indicates if there are uncommitted changes.
 	IsDirty bool `json:"is_dirty"`
 
+	// DirtyCount is the number of changed or untracked files.
+	DirtyCount int `json:"dirty_count,omitempty"`
+
+	// DirtyFiles lists changed or untracked files, capped at a few dozen;
+	// DirtyCount has the full count.
+	DirtyFiles []GitFileChange `json:"dirty_files,omitempty"`
+
+	// Upstream is the branch's upstream, e.g. "origin/main".
+	Upstream string `json:"upstream,omitempty"`
+
 	// Ahead is the number of commits ahead of remote.
 	Ahead int `json:"ahead"`
 
@@ -83,6 +96,45 @@ type GitInfo struct {
 
 	// LastCommit is the hash of the last commit.
 	LastCommit string `json:"last_commit,omitempty"`
+
+	// CheckedAt is when the repository was last inspected.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// GitFileChange is one entry of `git status --porcelain`.
+type GitFileChange struct {
+	// Status is the two-letter porcelain code, e.g. " M" or "??".
+	Status string `json:"status"`
+
+	// Path is the file path relative to the repository root.
+	Path string `json:"path"`
+}
+
+// QuotaResource names a resource capped by a workspace quota.
+type QuotaResource string
+
+const (
+	QuotaResourceAgents QuotaResource = "agents"
+	QuotaResourceLoops  QuotaResource = "loops"
+	QuotaResourceDisk   QuotaResource = "disk"
+)
+
+// WorkspaceQuota caps the resources a workspace may use. Zero fields are
+// unlimited.
+type WorkspaceQuota struct {
+	// MaxAgents caps the number of agents in the workspace.
+	MaxAgents int `json:"max_agents,omitempty"`
+
+	// MaxLoops caps the number of loops on the workspace repo.
+	MaxLoops int `json:"max_loops,omitempty"`
+
+	// MaxDiskMB caps the size of the workspace repo directory.
+	MaxDiskMB int64 `json:"max_disk_mb,omitempty"`
+}
+
+// IsZero reports whether the quota imposes no limits.
+func (q WorkspaceQuota) IsZero() bool {
+	return q.MaxAgents <= 0 && q.MaxLoops <= 0 && q.MaxDiskMB <= 0
 }
 
 // AlertSeverity indicates the severity of an alert.
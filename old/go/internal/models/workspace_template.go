@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkspaceTemplate describes how to provision a workspace: where to clone
+// it from and which commands prepare it for agents.
+type WorkspaceTemplate struct {
+	// ID is the unique identifier for the template.
+	ID string `json:"id"`
+
+	// Name is the unique name used with `forge ws create --template`.
+	Name string `json:"name"`
+
+	// Description is an optional human-friendly summary.
+	Description string `json:"description,omitempty"`
+
+	// GitURL is cloned into the workspace path when the path does not exist.
+	GitURL string `json:"git_url,omitempty"`
+
+	// Branch is checked out when cloning; empty uses the remote default.
+	Branch string `json:"branch,omitempty"`
+
+	// Bootstrap commands run in order in the workspace's tmux session.
+	Bootstrap []string `json:"bootstrap,omitempty"`
+
+	// Env is exported for the bootstrap commands.
+	Env map[string]string `json:"env,omitempty"`
+
+	// CreatedAt is when the template was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the template was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the template for required fields.
+func (t *WorkspaceTemplate) Validate() error {
+	validation := &ValidationErrors{}
+	if strings.TrimSpace(t.Name) == "" {
+		validation.AddMessage("name", "name is required")
+	}
+	for i, command := range t.Bootstrap {
+		if strings.TrimSpace(command) == "" {
+			validation.AddMessage("bootstrap", fmt.Sprintf("bootstrap command %d is empty", i+1))
+		}
+	}
+	for key := range t.Env {
+		if !isEnvKey(key) {
+			validation.AddMessage("env", "invalid env name "+key)
+		}
+	}
+	return validation.Err()
+}
+
+func isEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// BootstrapStatus is the state of a workspace bootstrap run.
+type BootstrapStatus string
+
+const (
+	BootstrapStatusRunning   BootstrapStatus = "running"
+	BootstrapStatusSucceeded BootstrapStatus = "succeeded"
+	BootstrapStatusFailed    BootstrapStatus = "failed"
+)
+
+// WorkspaceBootstrap records the latest bootstrap run of a workspace.
+type WorkspaceBootstrap struct {
+	// WorkspaceID is the bootstrapped workspace.
+	WorkspaceID string `json:"workspace_id"`
+
+	// TemplateID is the template the commands came from.
+	TemplateID string `json:"template_id,omitempty"`
+
+	// Status is the outcome of the run.
+	Status BootstrapStatus `json:"status"`
+
+	// ExitCode is the exit code of the bootstrap script, when it finished.
+	ExitCode *int `json:"exit_code,omitempty"`
+
+	// Output is the captured tmux pane output.
+	Output string `json:"output,omitempty"`
+
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"started_at"`
+
+	// FinishedAt is when the run finished.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
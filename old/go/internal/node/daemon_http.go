@@ -0,0 +1,12 @@
+package node
+
+import "net/http"
+
+// NewDaemonHTTPHandler builds the handler forged serves on its HTTP
+// listener. logStreams (loop.LogStreamer's Handler) is mounted under
+// /loops/, and every request must pass auth before it reaches a route.
+func NewDaemonHTTPHandler(auth *ListenerAuth, logStreams http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/loops/", logStreams)
+	return auth.HTTPMiddleware(mux)
+}
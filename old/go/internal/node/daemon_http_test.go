@@ -0,0 +1,81 @@
+package node
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/loop"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestDaemonHTTPHandlerServesLogStreamsBehindAuth(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	dataDir := t.TempDir()
+	loopEntry := &models.Loop{Name: "mounted-loop", RepoPath: t.TempDir(), IntervalSeconds: 10}
+	if err := db.NewLoopRepository(database).Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	logPath := loop.LogPath(dataDir, loopEntry.Name, loopEntry.ID)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(logPath, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	auth, err := NewListenerAuth(ListenerAuthConfig{Tokens: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	server := httptest.NewServer(NewDaemonHTTPHandler(auth, loop.NewLogStreamer(database, dataDir).Handler()))
+	defer server.Close()
+
+	get := func(path, token string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	logURL := "/loops/" + loopEntry.Name + "/log"
+	if code, _ := get(logURL, ""); code != http.StatusUnauthorized {
+		t.Fatalf("without token: status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := get(logURL, "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("wrong token: status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, body := get(logURL, "secret"); code != http.StatusOK || body != "one\ntwo\n" {
+		t.Fatalf("with token: status = %d body = %q", code, body)
+	}
+	if code, _ := get("/loops/missing/log", "secret"); code != http.StatusNotFound {
+		t.Fatalf("unknown loop: status = %d, want %d", code, http.StatusNotFound)
+	}
+}
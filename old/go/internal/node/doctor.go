@@ -86,6 +86,15 @@ func (s *Service) Doctor(ctx context.Context, node *models.Node) (*DoctorReport,
 		CheckedAt: time.Now().UTC(),
 	}
 
+	for _, check := range checks {
+		if check.Name == "disk" && check.Status == CheckFail && check.Error == "" {
+			s.publishEvent(ctx, models.EventTypeNodeDiskCritical, node.ID, models.DiskCriticalPayload{
+				NodeName: node.Name,
+				Details:  check.Details,
+			})
+		}
+	}
+
 	return report, nil
 }
 
@@ -173,7 +182,7 @@ func checkTmux(ctx context.Context, executor execer) DoctorCheck {
 	if details == "" {
 		details = "tmux detected"
 	}
-	version, err := tmux.ParseVersion(details)
+	caps, err := tmux.DetectCapabilities(details)
 	if err != nil {
 		return DoctorCheck{
 			Name:    "tmux",
@@ -182,7 +191,7 @@ func checkTmux(ctx context.Context, executor execer) DoctorCheck {
 		}
 	}
 
-	if version.LessThan(tmux.MinVersion) {
+	if caps.Version.LessThan(tmux.MinVersion) {
 		return DoctorCheck{
 			Name:    "tmux",
 			Status:  CheckWarn,
@@ -190,6 +199,14 @@ func checkTmux(ctx context.Context, executor execer) DoctorCheck {
 		}
 	}
 
+	if missing := caps.Missing(); len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, f := range missing {
+			names[i] = string(f)
+		}
+		details = fmt.Sprintf("%s (no %s)", details, strings.Join(names, ", "))
+	}
+
 	return DoctorCheck{
 		Name:    "tmux",
 		Status:  CheckPass,
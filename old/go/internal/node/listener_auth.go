@@ -0,0 +1,352 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/logging"
+)
+
+const defaultTokenReloadInterval = 10 * time.Second
+
+// ErrUnauthenticated is returned when a request carries no valid token.
+var ErrUnauthenticated = errors.New("missing or invalid bearer token")
+
+// ListenerAuthConfig describes how forged authenticates callers on its HTTP
+// and gRPC listeners.
+type ListenerAuthConfig struct {
+	// CertFile and KeyFile enable TLS.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile enables mutual TLS against these CAs.
+	ClientCAFile string
+
+	// Tokens are accepted static bearer tokens.
+	Tokens []string
+
+	// TokenFile holds accepted bearer tokens, one per line; blank lines and
+	// lines starting with # are ignored.
+	TokenFile string
+
+	// TokenReloadInterval is how often TokenFile is checked for changes.
+	TokenReloadInterval time.Duration
+}
+
+// ListenerAuthConfigFromSettings maps node_defaults.daemon_auth onto a
+// ListenerAuthConfig.
+func ListenerAuthConfigFromSettings(settings config.DaemonAuthConfig) ListenerAuthConfig {
+	return ListenerAuthConfig{
+		CertFile:            strings.TrimSpace(settings.TLSCertFile),
+		KeyFile:             strings.TrimSpace(settings.TLSKeyFile),
+		ClientCAFile:        strings.TrimSpace(settings.TLSClientCAFile),
+		Tokens:              settings.Tokens,
+		TokenFile:           strings.TrimSpace(settings.TokenFile),
+		TokenReloadInterval: settings.TokenReloadInterval,
+	}
+}
+
+// TLSEnabled reports whether the listeners should serve TLS.
+func (c ListenerAuthConfig) TLSEnabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// TokensEnabled reports whether callers must present a bearer token.
+func (c ListenerAuthConfig) TokensEnabled() bool {
+	return len(normalizeTokens(c.Tokens)) > 0 || c.TokenFile != ""
+}
+
+// ListenerAuth enforces bearer tokens on forged's listeners and builds their
+// TLS configuration. Tokens loaded from TokenFile are swapped atomically on
+// reload, so rotating a token is a matter of adding the new one to the file,
+// moving clients over, and removing the old one.
+type ListenerAuth struct {
+	cfg    ListenerAuthConfig
+	logger zerolog.Logger
+
+	mu        sync.RWMutex
+	tokens    [][]byte
+	fileMod   time.Time
+	fileSize  int64
+	hasTokens bool
+}
+
+// ListenerAuthOption configures a ListenerAuth.
+type ListenerAuthOption func(*ListenerAuth)
+
+// WithListenerAuthLogger sets the logger used for token reloads.
+func WithListenerAuthLogger(logger zerolog.Logger) ListenerAuthOption {
+	return func(a *ListenerAuth) {
+		a.logger = logger
+	}
+}
+
+// NewListenerAuth loads the configured tokens. It fails when TokenFile is set
+// but cannot be read.
+func NewListenerAuth(cfg ListenerAuthConfig, opts ...ListenerAuthOption) (*ListenerAuth, error) {
+	if cfg.TokenReloadInterval <= 0 {
+		cfg.TokenReloadInterval = defaultTokenReloadInterval
+	}
+	a := &ListenerAuth{
+		cfg:    cfg,
+		logger: logging.Component("listener-auth"),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload re-reads TokenFile and replaces the accepted token set. Static
+// tokens from the config are always accepted.
+func (a *ListenerAuth) Reload() error {
+	tokens := normalizeTokens(a.cfg.Tokens)
+	var mod time.Time
+	var size int64
+	if a.cfg.TokenFile != "" {
+		info, err := os.Stat(a.cfg.TokenFile)
+		if err != nil {
+			return fmt.Errorf("read token file: %w", err)
+		}
+		data, err := os.ReadFile(a.cfg.TokenFile)
+		if err != nil {
+			return fmt.Errorf("read token file: %w", err)
+		}
+		tokens = append(tokens, parseTokenFile(data)...)
+		mod, size = info.ModTime(), info.Size()
+	}
+
+	accepted := make([][]byte, 0, len(tokens))
+	for _, token := range tokens {
+		accepted = append(accepted, []byte(token))
+	}
+
+	a.mu.Lock()
+	a.tokens = accepted
+	a.fileMod = mod
+	a.fileSize = size
+	a.hasTokens = a.cfg.TokensEnabled()
+	a.mu.Unlock()
+	return nil
+}
+
+// Watch reloads TokenFile whenever it changes until ctx is cancelled. A file
+// that fails to load keeps the previous token set in place.
+func (a *ListenerAuth) Watch(ctx context.Context) {
+	if a.cfg.TokenFile == "" {
+		return
+	}
+	ticker := time.NewTicker(a.cfg.TokenReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !a.tokenFileChanged() {
+				continue
+			}
+			if err := a.Reload(); err != nil {
+				a.logger.Warn().Err(err).Str("path", a.cfg.TokenFile).Msg("token reload failed; keeping previous tokens")
+				continue
+			}
+			a.logger.Info().Str("path", a.cfg.TokenFile).Msg("reloaded listener tokens")
+		}
+	}
+}
+
+func (a *ListenerAuth) tokenFileChanged() bool {
+	info, err := os.Stat(a.cfg.TokenFile)
+	if err != nil {
+		return true
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return !info.ModTime().Equal(a.fileMod) || info.Size() != a.fileSize
+}
+
+// Authenticate checks a bearer token. It always succeeds when no tokens
+// are configured.
+func (a *ListenerAuth) Authenticate(token string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !a.hasTokens {
+		return nil
+	}
+	candidate := []byte(strings.TrimSpace(token))
+	if len(candidate) == 0 {
+		return ErrUnauthenticated
+	}
+	ok := 0
+	for _, accepted := range a.tokens {
+		ok |= subtle.ConstantTimeCompare(candidate, accepted)
+	}
+	if ok == 0 {
+		return ErrUnauthenticated
+	}
+	return nil
+}
+
+// HTTPMiddleware rejects requests without a valid Authorization: Bearer
+// header.
+func (a *ListenerAuth) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.Authenticate(bearerToken(r.Header.Get("Authorization"))); err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="forged"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor enforces bearer tokens on unary RPCs.
+func (a *ListenerAuth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authenticateContext(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor enforces bearer tokens on streaming RPCs.
+func (a *ListenerAuth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authenticateContext(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (a *ListenerAuth) authenticateContext(ctx context.Context) error {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = bearerToken(values[0])
+		}
+	}
+	if err := a.Authenticate(token); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// TLSConfig returns the server TLS configuration, or nil when TLS is off.
+// With ClientCAFile set, clients must present a certificate it verifies.
+func (a *ListenerAuth) TLSConfig() (*tls.Config, error) {
+	if !a.cfg.TLSEnabled() {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(a.cfg.CertFile, a.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS key pair: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if a.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(a.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("client CA file %s contains no certificates", a.cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// GRPCServerOptions returns the credentials and interceptors a forged gRPC
+// server needs to enforce this configuration.
+func (a *ListenerAuth) GRPCServerOptions() ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(a.StreamServerInterceptor()),
+	}
+	tlsConfig, err := a.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	return opts, nil
+}
+
+// BearerTokenCredentials attaches a bearer token to every RPC a client makes.
+type BearerTokenCredentials struct {
+	Token string
+
+	// Insecure allows sending the token over a plaintext connection, e.g.
+	// through an SSH tunnel.
+	Insecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c BearerTokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.Token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c BearerTokenCredentials) RequireTransportSecurity() bool {
+	return !c.Insecure
+}
+
+func bearerToken(header string) string {
+	header = strings.TrimSpace(header)
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[len("Bearer "):])
+}
+
+func parseTokenFile(data []byte) []string {
+	var tokens []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	return tokens
+}
+
+func normalizeTokens(tokens []string) []string {
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			out = append(out, token)
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package node
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestListenerAuthHTTPMiddlewareRequiresToken(t *testing.T) {
+	auth, err := NewListenerAuth(ListenerAuthConfig{Tokens: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	handler := auth.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusNoContent,
+		"bearer secret": http.StatusNoContent,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/v1/status", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Authorization %q: status = %d, want %d", header, rec.Code, want)
+		}
+	}
+}
+
+func TestListenerAuthAllowsAllWithoutTokens(t *testing.T) {
+	auth, err := NewListenerAuth(ListenerAuthConfig{})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	if err := auth.Authenticate(""); err != nil {
+		t.Fatalf("expected unauthenticated access without tokens, got %v", err)
+	}
+	tlsConfig, err := auth.TLSConfig()
+	if err != nil || tlsConfig != nil {
+		t.Fatalf("expected no TLS config, got %v, %v", tlsConfig, err)
+	}
+}
+
+func TestListenerAuthUnaryInterceptor(t *testing.T) {
+	auth, err := NewListenerAuth(ListenerAuthConfig{Tokens: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	interceptor := auth.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/forged.v1.ForgedService/Ping"}
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without metadata, got %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+	resp, err := interceptor(ctx, nil, info, handler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("expected handler to run, got %v, %v", resp, err)
+	}
+}
+
+func TestListenerAuthWatchRotatesTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte("# current\nold-token\n"), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	auth, err := NewListenerAuth(ListenerAuthConfig{TokenFile: path, TokenReloadInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	if err := auth.Authenticate("old-token"); err != nil {
+		t.Fatalf("old token rejected: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go auth.Watch(ctx)
+
+	if err := os.WriteFile(path, []byte("new-token\n"), 0o600); err != nil {
+		t.Fatalf("rotate token file: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for auth.Authenticate("new-token") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("rotated token was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := auth.Authenticate("old-token"); err == nil {
+		t.Fatal("expected old token to be rejected after rotation")
+	}
+}
+
+func TestNewListenerAuthFailsOnMissingTokenFile(t *testing.T) {
+	_, err := NewListenerAuth(ListenerAuthConfig{TokenFile: filepath.Join(t.TempDir(), "missing")})
+	if err == nil {
+		t.Fatal("expected error for missing token file")
+	}
+}
+
+func TestListenerAuthGRPCRoundTrip(t *testing.T) {
+	auth, err := NewListenerAuth(ListenerAuthConfig{Tokens: []string{"secret"}})
+	if err != nil {
+		t.Fatalf("NewListenerAuth: %v", err)
+	}
+	serverOpts, err := auth.GRPCServerOptions()
+	if err != nil {
+		t.Fatalf("GRPCServerOptions: %v", err)
+	}
+	server := grpc.NewServer(serverOpts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	check := func(dialOpts ...grpc.DialOption) error {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.NewClient(listener.Addr().String(), dialOpts...)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	if err := check(); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+	if err := check(grpc.WithPerRPCCredentials(BearerTokenCredentials{Token: "wrong", Insecure: true})); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated with a wrong token, got %v", err)
+	}
+	if err := check(grpc.WithPerRPCCredentials(BearerTokenCredentials{Token: "secret", Insecure: true})); err != nil {
+		t.Fatalf("expected the client token to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// ErrNoMatchingNode is returned when no node satisfies placement constraints.
+var ErrNoMatchingNode = errors.New("no node satisfies constraints")
+
+// SelectNode picks a node for new work that satisfies every constraint.
+// Offline nodes are skipped. Among matches, online nodes beat nodes of
+// unknown status, then fewer running agents wins, then the local node,
+// then name order.
+func (s *Service) SelectNode(ctx context.Context, constraints []models.NodeConstraint) (*models.Node, error) {
+	nodes, err := s.ListNodes(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	candidates := FilterNodes(nodes, constraints)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoMatchingNode, models.FormatNodeConstraints(constraints))
+	}
+	return candidates[0], nil
+}
+
+// FilterNodes returns the non-offline nodes matching constraints in
+// SelectNode preference order.
+func FilterNodes(nodes []*models.Node, constraints []models.NodeConstraint) []*models.Node {
+	matches := make([]*models.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n == nil || n.Status == models.NodeStatusOffline {
+			continue
+		}
+		if n.SatisfiesConstraints(constraints) {
+			matches = append(matches, n)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if (a.Status == models.NodeStatusOnline) != (b.Status == models.NodeStatusOnline) {
+			return a.Status == models.NodeStatusOnline
+		}
+		if a.AgentCount != b.AgentCount {
+			return a.AgentCount < b.AgentCount
+		}
+		if a.IsLocal != b.IsLocal {
+			return a.IsLocal
+		}
+		return a.Name < b.Name
+	})
+	return matches
+}
+
+// SetLabels adds or replaces the labels in set and deletes the keys in
+// remove, then persists the node.
+func (s *Service) SetLabels(ctx context.Context, id string, set map[string]string, remove []string) (*models.Node, error) {
+	n, err := s.GetNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n.Labels == nil {
+		n.Labels = make(map[string]string, len(set))
+	}
+	for key, value := range set {
+		if err := models.ValidateNodeLabelKey(key); err != nil {
+			return nil, err
+		}
+		n.Labels[key] = value
+	}
+	for _, key := range remove {
+		delete(n.Labels, key)
+	}
+	if err := s.UpdateNode(ctx, n); err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, models.EventTypeNodeLabelsChanged, n.ID, map[string]any{"labels": n.Labels})
+	return n, nil
+}
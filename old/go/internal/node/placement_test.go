@@ -0,0 +1,41 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestFilterNodesOrdersByPreference(t *testing.T) {
+	gpu := map[string]string{"gpu": "true"}
+	nodes := []*models.Node{
+		{Name: "busy", Status: models.NodeStatusOnline, AgentCount: 4, Labels: gpu},
+		{Name: "down", Status: models.NodeStatusOffline, Labels: gpu},
+		{Name: "cpu", Status: models.NodeStatusOnline},
+		{Name: "unknown", Status: models.NodeStatusUnknown, Labels: gpu},
+		{Name: "idle", Status: models.NodeStatusOnline, AgentCount: 1, Labels: gpu},
+	}
+	constraints, err := models.ParseNodeConstraints([]string{"gpu=true"})
+	if err != nil {
+		t.Fatalf("parse constraints: %v", err)
+	}
+
+	got := FilterNodes(nodes, constraints)
+	var names []string
+	for _, n := range got {
+		names = append(names, n.Name)
+	}
+	want := []string{"idle", "busy", "unknown"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, names)
+		}
+	}
+
+	if len(FilterNodes(nodes, []models.NodeConstraint{{Key: "tpu", Exists: true}})) != 0 {
+		t.Fatal("expected no node to match tpu")
+	}
+}
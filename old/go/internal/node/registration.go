@@ -0,0 +1,319 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/logging"
+)
+
+const (
+	defaultHeartbeatInterval = 30 * time.Second
+	defaultRegisterBackoff   = time.Second
+	defaultMaxBackoff        = 2 * time.Minute
+	deregisterTimeout        = 5 * time.Second
+)
+
+// errNotRegistered is returned when the control plane no longer knows the node.
+var errNotRegistered = errors.New("node not registered with control plane")
+
+// RegistrationConfig describes how a forged instance announces itself to a
+// control plane such as swarmd.
+type RegistrationConfig struct {
+	// URL is the control-plane base URL, e.g. https://swarm.example:7400.
+	URL string
+
+	// Token is sent as a bearer token when set.
+	Token string
+
+	// NodeID identifies this node; defaults to the hostname.
+	NodeID string
+
+	// Address is where the control plane can reach forged (host:port).
+	Address string
+
+	// Version is the forged build version.
+	Version string
+
+	// Capabilities advertises what the node can run (tmux, harnesses, ...).
+	Capabilities []string
+
+	// HeartbeatInterval is how often the node reports liveness.
+	HeartbeatInterval time.Duration
+
+	// MaxBackoff caps the retry delay after failed registrations.
+	MaxBackoff time.Duration
+}
+
+// RegistrationConfigFromSettings maps node_defaults.control_plane onto a
+// RegistrationConfig. The second result is false when registration is off.
+func RegistrationConfigFromSettings(settings config.ControlPlaneConfig, version string) (RegistrationConfig, bool) {
+	if strings.TrimSpace(settings.URL) == "" {
+		return RegistrationConfig{}, false
+	}
+	return RegistrationConfig{
+		URL:               settings.URL,
+		Token:             settings.Token,
+		NodeID:            settings.NodeID,
+		Address:           settings.Address,
+		Version:           version,
+		Capabilities:      DetectCapabilities(),
+		HeartbeatInterval: settings.HeartbeatInterval,
+	}, true
+}
+
+// RegistrationPayload is the body sent on register and heartbeat.
+type RegistrationPayload struct {
+	NodeID       string    `json:"node_id"`
+	Hostname     string    `json:"hostname"`
+	Address      string    `json:"address,omitempty"`
+	Version      string    `json:"version,omitempty"`
+	OS           string    `json:"os"`
+	Arch         string    `json:"arch"`
+	Capabilities []string  `json:"capabilities,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// Registrar registers a node with a control plane, keeps it alive with
+// heartbeats, and deregisters it on shutdown.
+//
+// Endpoints (relative to RegistrationConfig.URL):
+//
+//	POST   /v1/nodes                  register
+//	POST   /v1/nodes/{id}/heartbeat   heartbeat (404 triggers re-registration)
+//	DELETE /v1/nodes/{id}             deregister
+type Registrar struct {
+	cfg     RegistrationConfig
+	payload RegistrationPayload
+	client  *http.Client
+	logger  zerolog.Logger
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// RegistrarOption configures a Registrar.
+type RegistrarOption func(*Registrar)
+
+// WithHTTPClient overrides the HTTP client used to reach the control plane.
+func WithHTTPClient(client *http.Client) RegistrarOption {
+	return func(r *Registrar) {
+		r.client = client
+	}
+}
+
+// WithRegistrarLogger sets the registrar logger.
+func WithRegistrarLogger(logger zerolog.Logger) RegistrarOption {
+	return func(r *Registrar) {
+		r.logger = logger
+	}
+}
+
+// NewRegistrar validates cfg and fills in defaults.
+func NewRegistrar(cfg RegistrationConfig, opts ...RegistrarOption) (*Registrar, error) {
+	cfg.URL = strings.TrimRight(strings.TrimSpace(cfg.URL), "/")
+	if cfg.URL == "" {
+		return nil, errors.New("control plane URL is required")
+	}
+	if parsed, err := url.Parse(cfg.URL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid control plane URL %q", cfg.URL)
+	}
+
+	hostname, _ := os.Hostname()
+	if strings.TrimSpace(cfg.NodeID) == "" {
+		cfg.NodeID = hostname
+	}
+	if cfg.NodeID == "" {
+		return nil, errors.New("node id is required")
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+
+	r := &Registrar{
+		cfg: cfg,
+		payload: RegistrationPayload{
+			NodeID:       cfg.NodeID,
+			Hostname:     hostname,
+			Address:      cfg.Address,
+			Version:      cfg.Version,
+			OS:           runtime.GOOS,
+			Arch:         runtime.GOARCH,
+			Capabilities: cfg.Capabilities,
+			StartedAt:    time.Now().UTC(),
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: logging.Component("node-registrar"),
+		sleep:  sleepContext,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Run registers the node, retrying with exponential backoff, then sends
+// heartbeats until ctx is cancelled. On cancellation it deregisters with a
+// short independent timeout.
+func (r *Registrar) Run(ctx context.Context) {
+	for {
+		if err := r.registerWithRetry(ctx); err != nil {
+			// Cancelled before registering; nothing to undo.
+			return
+		}
+		err := r.heartbeatLoop(ctx)
+		if ctx.Err() != nil {
+			r.deregisterOnShutdown()
+			return
+		}
+		r.logger.Warn().Err(err).Msg("control plane lost node registration; re-registering")
+	}
+}
+
+func (r *Registrar) registerWithRetry(ctx context.Context) error {
+	backoff := defaultRegisterBackoff
+	for attempt := 1; ; attempt++ {
+		err := r.Register(ctx)
+		if err == nil {
+			r.logger.Info().Str("node_id", r.cfg.NodeID).Str("control_plane", r.cfg.URL).Msg("registered with control plane")
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r.logger.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", backoff).Msg("control plane registration failed")
+		if err := r.sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = minDuration(backoff*2, r.cfg.MaxBackoff)
+	}
+}
+
+// heartbeatLoop returns when ctx is done or the registration is gone.
+// Transient heartbeat failures are logged and retried on the next tick.
+func (r *Registrar) heartbeatLoop(ctx context.Context) error {
+	for {
+		if err := r.sleep(ctx, r.cfg.HeartbeatInterval); err != nil {
+			return err
+		}
+		err := r.Heartbeat(ctx)
+		switch {
+		case err == nil:
+		case errors.Is(err, errNotRegistered):
+			return err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			r.logger.Warn().Err(err).Msg("control plane heartbeat failed")
+		}
+	}
+}
+
+func (r *Registrar) deregisterOnShutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), deregisterTimeout)
+	defer cancel()
+	if err := r.Deregister(ctx); err != nil {
+		r.logger.Warn().Err(err).Msg("control plane deregistration failed")
+		return
+	}
+	r.logger.Info().Str("node_id", r.cfg.NodeID).Msg("deregistered from control plane")
+}
+
+// Register announces the node once.
+func (r *Registrar) Register(ctx context.Context) error {
+	return r.do(ctx, http.MethodPost, "/v1/nodes", r.payload)
+}
+
+// Heartbeat reports liveness once.
+func (r *Registrar) Heartbeat(ctx context.Context) error {
+	return r.do(ctx, http.MethodPost, "/v1/nodes/"+url.PathEscape(r.cfg.NodeID)+"/heartbeat", r.payload)
+}
+
+// Deregister removes the node from the control plane. A node the control
+// plane has already forgotten counts as deregistered.
+func (r *Registrar) Deregister(ctx context.Context) error {
+	err := r.do(ctx, http.MethodDelete, "/v1/nodes/"+url.PathEscape(r.cfg.NodeID), nil)
+	if errors.Is(err, errNotRegistered) {
+		return nil
+	}
+	return err
+}
+
+func (r *Registrar) do(ctx context.Context, method, path string, payload any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("encode registration: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.URL+path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && path != "/v1/nodes" {
+		return errNotRegistered
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("control plane returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// DetectCapabilities reports which runtimes and harness CLIs are installed on
+// this host, for advertising in RegistrationConfig.Capabilities.
+func DetectCapabilities() []string {
+	caps := make([]string, 0)
+	for _, bin := range []string{"tmux", "git", "claude", "codex", "opencode", "pi", "droid"} {
+		if _, err := exec.LookPath(bin); err == nil {
+			caps = append(caps, bin)
+		}
+	}
+	return caps
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,138 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeControlPlane struct {
+	mu         sync.Mutex
+	calls      []string
+	failFirst  int
+	forgetOnHB bool
+	auth       string
+	payload    RegistrationPayload
+	heartbeats chan struct{}
+}
+
+func (f *fakeControlPlane) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	f.auth = r.Header.Get("Authorization")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/nodes":
+		if f.failFirst > 0 {
+			f.failFirst--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&f.payload)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/nodes/node-a/heartbeat":
+		if f.forgetOnHB {
+			f.forgetOnHB = false
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		select {
+		case f.heartbeats <- struct{}{}:
+		default:
+		}
+	case r.Method == http.MethodDelete && r.URL.Path == "/v1/nodes/node-a":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeControlPlane) snapshot() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func TestRegistrarRetriesHeartbeatsAndDeregisters(t *testing.T) {
+	plane := &fakeControlPlane{failFirst: 2, forgetOnHB: true, heartbeats: make(chan struct{}, 1)}
+	server := httptest.NewServer(plane)
+	defer server.Close()
+
+	registrar, err := NewRegistrar(RegistrationConfig{
+		URL:               server.URL + "/",
+		Token:             "secret",
+		NodeID:            "node-a",
+		Version:           "1.2.3",
+		Capabilities:      []string{"tmux"},
+		HeartbeatInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new registrar: %v", err)
+	}
+	var sleeps []time.Duration
+	registrar.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		registrar.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-plane.heartbeats:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no heartbeat received; calls=%v", plane.snapshot())
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("registrar did not stop")
+	}
+
+	calls := plane.snapshot()
+	want := []string{
+		"POST /v1/nodes",
+		"POST /v1/nodes",
+		"POST /v1/nodes",
+		"POST /v1/nodes/node-a/heartbeat",
+		"POST /v1/nodes",
+		"POST /v1/nodes/node-a/heartbeat",
+	}
+	for i, call := range want {
+		if i >= len(calls) || calls[i] != call {
+			t.Fatalf("unexpected call sequence: %v", calls)
+		}
+	}
+	if calls[len(calls)-1] != "DELETE /v1/nodes/node-a" {
+		t.Fatalf("expected deregistration last, got %v", calls)
+	}
+	if sleeps[0] != time.Second || sleeps[1] != 2*time.Second {
+		t.Fatalf("expected exponential backoff, got %v", sleeps[:2])
+	}
+	if plane.auth != "Bearer secret" {
+		t.Fatalf("expected bearer token, got %q", plane.auth)
+	}
+	if plane.payload.NodeID != "node-a" || plane.payload.Version != "1.2.3" || len(plane.payload.Capabilities) != 1 {
+		t.Fatalf("unexpected registration payload: %+v", plane.payload)
+	}
+}
+
+func TestNewRegistrarValidatesURL(t *testing.T) {
+	if _, err := NewRegistrar(RegistrationConfig{}); err == nil {
+		t.Fatalf("expected error for empty URL")
+	}
+	if _, err := NewRegistrar(RegistrationConfig{URL: "swarm:7400"}); err == nil {
+		t.Fatalf("expected error for relative URL")
+	}
+}
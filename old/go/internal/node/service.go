@@ -297,6 +297,12 @@ func (s *Service) executorForNode(node *models.Node) (ssh.Executor, error) {
 		return ssh.NewLocalExecutor(), nil
 	}
 
+	// Create executor based on backend preference
+	return s.createExecutor(node.SSHBackend, s.ConnectionOptions(node))
+}
+
+// ConnectionOptions builds SSH connection options for a node.
+func (s *Service) ConnectionOptions(node *models.Node) ssh.ConnectionOptions {
 	// Parse SSH target
 	user, host, port := ParseSSHTarget(node.SSHTarget)
 
@@ -318,8 +324,7 @@ func (s *Service) executorForNode(node *models.Node) (ssh.Executor, error) {
 		opts.Timeout = time.Duration(node.SSHTimeoutSeconds) * time.Second
 	}
 
-	// Create executor based on backend preference
-	return s.createExecutor(node.SSHBackend, opts)
+	return opts
 }
 
 // RefreshNodeStatus tests connectivity and updates the node's status.
@@ -0,0 +1,225 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket
+// activation (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// ActivatedListener is a listener inherited through systemd socket
+// activation. Name comes from FileDescriptorName= in the socket unit.
+type ActivatedListener struct {
+	Name     string
+	Listener net.Listener
+}
+
+// ActivationListeners returns the listening sockets passed by systemd via
+// LISTEN_FDS, or nil when forged was not socket-activated. The LISTEN_*
+// variables are cleared so child processes do not inherit them.
+func ActivationListeners() ([]ActivatedListener, error) {
+	defer func() {
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	count, names, err := parseListenEnv(os.Getenv, os.Getpid())
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	listeners := make([]ActivatedListener, 0, count)
+	for i := 0; i < count; i++ {
+		file := os.NewFile(uintptr(listenFDsStart+i), names[i])
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Listener.Close()
+			}
+			return nil, fmt.Errorf("socket activation fd %d (%s): %w", listenFDsStart+i, names[i], err)
+		}
+		listeners = append(listeners, ActivatedListener{Name: names[i], Listener: listener})
+	}
+	return listeners, nil
+}
+
+// ActivationListener returns the activated listener called name, if any.
+func ActivationListener(listeners []ActivatedListener, name string) (net.Listener, bool) {
+	for _, l := range listeners {
+		if l.Name == name {
+			return l.Listener, true
+		}
+	}
+	return nil, false
+}
+
+// parseListenEnv reads LISTEN_PID, LISTEN_FDS, and LISTEN_FDNAMES. A
+// LISTEN_PID for another process means the variables were inherited and
+// are ignored. Sockets without a name are called "unknown", as systemd does.
+func parseListenEnv(getenv func(string) string, pid int) (int, []string, error) {
+	pidValue := strings.TrimSpace(getenv("LISTEN_PID"))
+	if pidValue == "" {
+		return 0, nil, nil
+	}
+	listenPID, err := strconv.Atoi(pidValue)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid LISTEN_PID %q", pidValue)
+	}
+	if listenPID != pid {
+		return 0, nil, nil
+	}
+
+	fdsValue := strings.TrimSpace(getenv("LISTEN_FDS"))
+	count, err := strconv.Atoi(fdsValue)
+	if err != nil || count < 0 {
+		return 0, nil, fmt.Errorf("invalid LISTEN_FDS %q", fdsValue)
+	}
+
+	names := make([]string, count)
+	fdNames := strings.Split(getenv("LISTEN_FDNAMES"), ":")
+	for i := range names {
+		names[i] = "unknown"
+		if i < len(fdNames) && fdNames[i] != "" {
+			names[i] = fdNames[i]
+		}
+	}
+	return count, names, nil
+}
+
+// SystemdNotifier reports service state to systemd over NOTIFY_SOCKET
+// (sd_notify). All methods are no-ops when forged was not started by a
+// unit with Type=notify, so callers need not check.
+type SystemdNotifier struct {
+	addr     string
+	watchdog time.Duration
+}
+
+// NewSystemdNotifier reads NOTIFY_SOCKET and WATCHDOG_USEC from the
+// environment. The variables are left set so a restarted forged can reuse
+// them.
+func NewSystemdNotifier() *SystemdNotifier {
+	return newSystemdNotifier(os.Getenv, os.Getpid())
+}
+
+func newSystemdNotifier(getenv func(string) string, pid int) *SystemdNotifier {
+	n := &SystemdNotifier{addr: strings.TrimSpace(getenv("NOTIFY_SOCKET"))}
+
+	usec, err := strconv.ParseInt(strings.TrimSpace(getenv("WATCHDOG_USEC")), 10, 64)
+	if err != nil || usec <= 0 {
+		return n
+	}
+	if watchdogPID := strings.TrimSpace(getenv("WATCHDOG_PID")); watchdogPID != "" {
+		if value, err := strconv.Atoi(watchdogPID); err != nil || value != pid {
+			return n
+		}
+	}
+	n.watchdog = time.Duration(usec) * time.Microsecond
+	return n
+}
+
+// Enabled reports whether systemd is listening for notifications.
+func (n *SystemdNotifier) Enabled() bool {
+	return n != nil && n.addr != ""
+}
+
+// WatchdogInterval returns the unit's WatchdogSec, or zero when the
+// watchdog is off.
+func (n *SystemdNotifier) WatchdogInterval() time.Duration {
+	if !n.Enabled() {
+		return 0
+	}
+	return n.watchdog
+}
+
+// Ready reports that forged has finished starting and is serving.
+func (n *SystemdNotifier) Ready(status string) error {
+	return n.Notify("READY=1", statusLine(status))
+}
+
+// Draining reports that forged has stopped accepting work and is waiting
+// for in-flight requests. systemd has no drain state, so it shows as the
+// unit status.
+func (n *SystemdNotifier) Draining(inflight int) error {
+	return n.Notify(fmt.Sprintf("STATUS=draining (%d in flight)", inflight))
+}
+
+// Stopping reports that forged is shutting down.
+func (n *SystemdNotifier) Stopping(status string) error {
+	return n.Notify("STOPPING=1", statusLine(status))
+}
+
+// Status sets the free-form status shown by systemctl status.
+func (n *SystemdNotifier) Status(status string) error {
+	return n.Notify(statusLine(status))
+}
+
+// Watchdog pings the systemd watchdog.
+func (n *SystemdNotifier) Watchdog() error {
+	return n.Notify("WATCHDOG=1")
+}
+
+// RunWatchdog pings the watchdog at half the configured interval until ctx
+// is done. healthy may be nil; when it returns false the ping is skipped so
+// systemd restarts a wedged daemon.
+func (n *SystemdNotifier) RunWatchdog(ctx context.Context, healthy func() bool) {
+	interval := n.WatchdogInterval() / 2
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if healthy == nil || healthy() {
+				_ = n.Watchdog()
+			}
+		}
+	}
+}
+
+// Notify sends raw sd_notify assignments, one per line. Empty entries are
+// dropped.
+func (n *SystemdNotifier) Notify(state ...string) error {
+	if !n.Enabled() {
+		return nil
+	}
+	lines := make([]string, 0, len(state))
+	for _, s := range state {
+		if s != "" {
+			lines = append(lines, s)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.addr, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("sd_notify: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		return fmt.Errorf("sd_notify: %w", err)
+	}
+	return nil
+}
+
+func statusLine(status string) string {
+	status = strings.TrimSpace(strings.ReplaceAll(status, "\n", " "))
+	if status == "" {
+		return ""
+	}
+	return "STATUS=" + status
+}
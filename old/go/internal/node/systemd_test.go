@@ -0,0 +1,134 @@
+package node
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func envMap(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestParseListenEnv(t *testing.T) {
+	count, names, err := parseListenEnv(envMap(map[string]string{
+		"LISTEN_PID":     "42",
+		"LISTEN_FDS":     "3",
+		"LISTEN_FDNAMES": "grpc::http",
+	}), 42)
+	if err != nil {
+		t.Fatalf("parseListenEnv: %v", err)
+	}
+	if count != 3 || strings.Join(names, ",") != "grpc,unknown,http" {
+		t.Fatalf("unexpected result count=%d names=%v", count, names)
+	}
+
+	count, _, err = parseListenEnv(envMap(map[string]string{"LISTEN_PID": "7", "LISTEN_FDS": "2"}), 42)
+	if err != nil || count != 0 {
+		t.Fatalf("expected inherited LISTEN_PID to be ignored, got count=%d err=%v", count, err)
+	}
+
+	count, _, err = parseListenEnv(envMap(nil), 42)
+	if err != nil || count != 0 {
+		t.Fatalf("expected no activation without LISTEN_PID, got count=%d err=%v", count, err)
+	}
+
+	if _, _, err := parseListenEnv(envMap(map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "x"}), 42); err == nil {
+		t.Fatalf("expected error for invalid LISTEN_FDS")
+	}
+}
+
+func listenNotifySocket(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+	addr := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return addr, conn
+}
+
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSystemdNotifierSendsStates(t *testing.T) {
+	addr, conn := listenNotifySocket(t)
+	notifier := newSystemdNotifier(envMap(map[string]string{"NOTIFY_SOCKET": addr}), 1)
+
+	if err := notifier.Ready("serving on :50051"); err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if got := readNotify(t, conn); got != "READY=1\nSTATUS=serving on :50051" {
+		t.Fatalf("Ready sent %q", got)
+	}
+
+	if err := notifier.Draining(2); err != nil {
+		t.Fatalf("Draining: %v", err)
+	}
+	if got := readNotify(t, conn); got != "STATUS=draining (2 in flight)" {
+		t.Fatalf("Draining sent %q", got)
+	}
+
+	if err := notifier.Stopping(""); err != nil {
+		t.Fatalf("Stopping: %v", err)
+	}
+	if got := readNotify(t, conn); got != "STOPPING=1" {
+		t.Fatalf("Stopping sent %q", got)
+	}
+}
+
+func TestSystemdNotifierDisabledIsNoop(t *testing.T) {
+	notifier := newSystemdNotifier(envMap(map[string]string{"WATCHDOG_USEC": "1000000"}), 1)
+	if notifier.Enabled() || notifier.WatchdogInterval() != 0 {
+		t.Fatalf("expected disabled notifier")
+	}
+	if err := notifier.Ready("ok"); err != nil {
+		t.Fatalf("Ready on disabled notifier: %v", err)
+	}
+	var nilNotifier *SystemdNotifier
+	if err := nilNotifier.Watchdog(); err != nil {
+		t.Fatalf("Watchdog on nil notifier: %v", err)
+	}
+}
+
+func TestSystemdNotifierWatchdog(t *testing.T) {
+	addr, conn := listenNotifySocket(t)
+
+	other := newSystemdNotifier(envMap(map[string]string{
+		"NOTIFY_SOCKET": addr, "WATCHDOG_USEC": "40000", "WATCHDOG_PID": "2",
+	}), 1)
+	if other.WatchdogInterval() != 0 {
+		t.Fatalf("expected watchdog for another pid to be ignored")
+	}
+
+	notifier := newSystemdNotifier(envMap(map[string]string{
+		"NOTIFY_SOCKET": addr, "WATCHDOG_USEC": "40000", "WATCHDOG_PID": "1",
+	}), 1)
+	if got := notifier.WatchdogInterval(); got != 40*time.Millisecond {
+		t.Fatalf("WatchdogInterval = %v, want 40ms", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		notifier.RunWatchdog(ctx, nil)
+		close(done)
+	}()
+	if got := readNotify(t, conn); got != "WATCHDOG=1" {
+		t.Fatalf("watchdog sent %q", got)
+	}
+	cancel()
+	<-done
+}
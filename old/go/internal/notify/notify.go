@@ -0,0 +1,254 @@
+// Package notify delivers event notifications to external sinks (generic
+// webhooks, Slack and SMTP email) according to configured rules.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultTimeout bounds a delivery when the sink does not set a timeout.
+const DefaultTimeout = 10 * time.Second
+
+// watchPageSize is how many events one poll reads per query.
+const watchPageSize = 100
+
+// Notification is a rendered message for one sink delivery.
+type Notification struct {
+	Rule    string        `json:"rule"`
+	Trigger string        `json:"trigger"`
+	Title   string        `json:"title"`
+	Body    string        `json:"body"`
+	Event   *models.Event `json:"event,omitempty"`
+}
+
+// Sink delivers notifications to one destination.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, notification Notification) error
+}
+
+// Notifier matches events against rules and delivers them to sinks.
+type Notifier struct {
+	sinks    map[string]Sink
+	timeouts map[string]time.Duration
+	rules    []*rule
+	logger   zerolog.Logger
+}
+
+// Option configures a Notifier.
+type Option func(*options)
+
+type options struct {
+	client   *http.Client
+	sendMail SendMailFunc
+	sinks    []Sink
+}
+
+// WithHTTPClient sets the client used by webhook and Slack sinks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithSendMail replaces smtp.SendMail for email sinks.
+func WithSendMail(send SendMailFunc) Option {
+	return func(o *options) {
+		o.sendMail = send
+	}
+}
+
+// WithSink registers a sink, replacing a configured sink with the same name.
+func WithSink(sink Sink) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sink)
+	}
+}
+
+// New builds a notifier from configuration. Rule templates are parsed
+// up front so mistakes surface before any event arrives.
+func New(cfg config.NotificationsConfig, opts ...Option) (*Notifier, error) {
+	o := options{client: &http.Client{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	n := &Notifier{
+		sinks:    make(map[string]Sink, len(cfg.Sinks)),
+		timeouts: make(map[string]time.Duration, len(cfg.Sinks)),
+		logger:   logging.Component("notify"),
+	}
+	for _, sinkCfg := range cfg.Sinks {
+		sink, err := newSink(sinkCfg, o)
+		if err != nil {
+			return nil, err
+		}
+		n.sinks[sink.Name()] = sink
+		if sinkCfg.Timeout > 0 {
+			n.timeouts[sink.Name()] = sinkCfg.Timeout
+		}
+	}
+	for _, sink := range o.sinks {
+		n.sinks[sink.Name()] = sink
+	}
+
+	for i, ruleCfg := range cfg.Rules {
+		r, err := newRule(i, ruleCfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range r.sinks {
+			if _, ok := n.sinks[name]; !ok {
+				return nil, fmt.Errorf("notification rule %q references unknown sink %q", r.name, name)
+			}
+		}
+		n.rules = append(n.rules, r)
+	}
+	return n, nil
+}
+
+// Sinks returns the configured sink names in sorted order.
+func (n *Notifier) Sinks() []string {
+	names := make([]string, 0, len(n.sinks))
+	for name := range n.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Notify delivers the event to the sinks of every matching rule. Each sink
+// receives an event at most once even when several rules match; the first
+// matching rule renders the message. Delivery errors are joined.
+func (n *Notifier) Notify(ctx context.Context, event *models.Event) error {
+	if event == nil {
+		return nil
+	}
+	payload := decodePayload(event)
+
+	var errs []error
+	sent := make(map[string]bool)
+	for _, r := range n.rules {
+		trigger, ok := r.match(event, payload)
+		if !ok {
+			continue
+		}
+		notification, err := r.render(trigger, event, payload)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, name := range r.sinks {
+			if sent[name] {
+				continue
+			}
+			sent[name] = true
+			if err := n.send(ctx, name, notification); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Test sends a sample notification to one sink.
+func (n *Notifier) Test(ctx context.Context, sinkName string) error {
+	if _, ok := n.sinks[sinkName]; !ok {
+		return fmt.Errorf("unknown notification sink %q", sinkName)
+	}
+	return n.send(ctx, sinkName, Notification{
+		Rule:    "test",
+		Trigger: "test",
+		Title:   "Forge test notification",
+		Body:    fmt.Sprintf("Sink %q is configured correctly.", sinkName),
+	})
+}
+
+// Watch polls the event log and notifies on events recorded after since
+// until ctx is cancelled. Delivery failures are logged, not returned.
+func (n *Notifier) Watch(ctx context.Context, repo *db.EventRepository, interval time.Duration, since time.Time) error {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	cursor := ""
+	for {
+		next, err := n.poll(ctx, repo, cursor, since)
+		if err != nil && ctx.Err() == nil {
+			n.logger.Warn().Err(err).Msg("failed to read events")
+		}
+		cursor = next
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll notifies on every event after cursor and returns the new cursor.
+func (n *Notifier) poll(ctx context.Context, repo *db.EventRepository, cursor string, since time.Time) (string, error) {
+	for {
+		query := db.EventQuery{Cursor: cursor, Limit: watchPageSize}
+		if cursor == "" {
+			query.Since = &since
+		}
+		page, err := repo.Query(ctx, query)
+		if err != nil {
+			return cursor, err
+		}
+		for _, event := range page.Events {
+			if err := n.Notify(ctx, event); err != nil {
+				n.logger.Warn().Err(err).Str("event_id", event.ID).Str("event_type", string(event.Type)).Msg("notification delivery failed")
+			}
+			cursor = event.ID
+		}
+		if page.NextCursor == "" {
+			return cursor, nil
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, name string, notification Notification) error {
+	timeout := DefaultTimeout
+	if override, ok := n.timeouts[name]; ok {
+		timeout = override
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := n.sinks[name].Send(ctx, notification); err != nil {
+		return fmt.Errorf("sink %s: %w", name, err)
+	}
+	n.logger.Debug().Str("sink", name).Str("rule", notification.Rule).Msg("notification sent")
+	return nil
+}
+
+func newSink(cfg config.NotificationSinkConfig, o options) (Sink, error) {
+	name := strings.TrimSpace(cfg.Name)
+	switch cfg.Type {
+	case config.NotificationSinkWebhook:
+		return &WebhookSink{name: name, url: cfg.URL, headers: cfg.Headers, client: o.client}, nil
+	case config.NotificationSinkSlack:
+		return &SlackSink{name: name, url: cfg.URL, channel: cfg.Channel, client: o.client}, nil
+	case config.NotificationSinkEmail:
+		return newEmailSink(name, cfg, o.sendMail), nil
+	default:
+		return nil, fmt.Errorf("notification sink %q: unsupported type %q", name, cfg.Type)
+	}
+}
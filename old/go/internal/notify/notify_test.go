@@ -0,0 +1,271 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+type recordingSink struct {
+	name string
+	mu   sync.Mutex
+	got  []Notification
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(ctx context.Context, notification Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.got = append(s.got, notification)
+	return nil
+}
+
+func (s *recordingSink) sent() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Notification(nil), s.got...)
+}
+
+func event(t *testing.T, eventType models.EventType, entityType models.EntityType, entityID string, payload any) *models.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return &models.Event{Type: eventType, EntityType: entityType, EntityID: entityID, Payload: data}
+}
+
+func TestNotifyMatchesTriggers(t *testing.T) {
+	ops := &recordingSink{name: "ops"}
+	cfg := config.NotificationsConfig{
+		Rules: []config.NotificationRuleConfig{
+			{Name: "failures", On: []string{TriggerAgentError, TriggerLoopFailed}, Sinks: []string{"ops"}},
+			{Name: "done", On: []string{TriggerLoopFinished}, Sinks: []string{"ops"},
+				Title: `{{.Payload.loop_name}} is done`, Body: `rule={{.Rule}} trigger={{.Trigger}}`},
+		},
+	}
+	notifier, err := New(cfg, WithSink(ops))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+
+	events := []*models.Event{
+		event(t, models.EventTypeAgentStateChanged, models.EntityTypeAgent, "agent-1",
+			models.StateChangedPayload{OldState: models.AgentStateWorking, NewState: models.AgentStateError, Reason: "panic in pane"}),
+		event(t, models.EventTypeAgentStateChanged, models.EntityTypeAgent, "agent-1",
+			models.StateChangedPayload{OldState: models.AgentStateWorking, NewState: models.AgentStateIdle}),
+		event(t, models.EventTypeLoopStateChanged, models.EntityTypeLoop, "loop-1",
+			models.LoopEventPayload{LoopName: "docs", State: models.LoopStateStopped, PreviousState: models.LoopStateRunning}),
+		event(t, models.EventTypeLoopRunFailed, models.EntityTypeLoop, "loop-1",
+			models.LoopEventPayload{LoopName: "docs", State: models.LoopStateRunning, Error: "harness crashed"}),
+	}
+	for _, e := range events {
+		if err := notifier.Notify(ctx, e); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	got := ops.sent()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 notifications, got %d: %+v", len(got), got)
+	}
+	if got[0].Trigger != TriggerAgentError || got[0].Title != "Agent agent-1 hit an error" || got[0].Body != "panic in pane" {
+		t.Fatalf("unexpected agent error notification %+v", got[0])
+	}
+	if got[1].Title != "docs is done" || got[1].Body != "rule=done trigger=loop_finished" {
+		t.Fatalf("unexpected loop finished notification %+v", got[1])
+	}
+	if got[2].Title != "Loop docs failed" || got[2].Body != "harness crashed" {
+		t.Fatalf("unexpected loop failed notification %+v", got[2])
+	}
+}
+
+func TestNotifySendsOncePerSinkAndFiltersEntity(t *testing.T) {
+	ops := &recordingSink{name: "ops"}
+	cfg := config.NotificationsConfig{
+		Rules: []config.NotificationRuleConfig{
+			{Name: "disk", On: []string{TriggerDiskCritical}, Sinks: []string{"ops"}},
+			{Name: "node-a", On: []string{"node.disk_critical"}, EntityID: "node-a", Sinks: []string{"ops"}},
+		},
+	}
+	notifier, err := New(cfg, WithSink(ops))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	disk := event(t, models.EventTypeNodeDiskCritical, models.EntityTypeNode, "node-a",
+		models.DiskCriticalPayload{NodeName: "build-1", Details: "97% used"})
+	if err := notifier.Notify(context.Background(), disk); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	got := ops.sent()
+	if len(got) != 1 || got[0].Rule != "disk" || got[0].Title != "Disk critical on build-1" || got[0].Body != "97% used" {
+		t.Fatalf("expected one disk notification from the first rule, got %+v", got)
+	}
+}
+
+func TestNewRejectsBadRules(t *testing.T) {
+	cfg := config.NotificationsConfig{
+		Rules: []config.NotificationRuleConfig{{Name: "bad", On: []string{"error"}, Sinks: []string{"ops"}, Title: "{{.Nope"}},
+	}
+	if _, err := New(cfg, WithSink(&recordingSink{name: "ops"})); err == nil || !strings.Contains(err.Error(), "invalid title template") {
+		t.Fatalf("expected template error, got %v", err)
+	}
+
+	cfg.Rules[0].Title = ""
+	cfg.Rules[0].Sinks = []string{"missing"}
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "unknown sink") {
+		t.Fatalf("expected unknown sink error, got %v", err)
+	}
+}
+
+func TestWebhookAndSlackSinks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]map[string]any{}
+		headers  = map[string]string{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var decoded map[string]any
+		_ = json.Unmarshal(body, &decoded)
+		mu.Lock()
+		requests[r.URL.Path] = decoded
+		headers[r.URL.Path] = r.Header.Get("X-Token")
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("FORGE_TEST_SLACK_URL", server.URL+"/slack")
+	cfg := config.NotificationsConfig{
+		Sinks: []config.NotificationSinkConfig{
+			{Name: "hook", Type: config.NotificationSinkWebhook, URL: server.URL + "/hook", Headers: map[string]string{"X-Token": "secret"}},
+			{Name: "slack", Type: config.NotificationSinkSlack, URL: "env:FORGE_TEST_SLACK_URL", Channel: "#ops"},
+			{Name: "broken", Type: config.NotificationSinkWebhook, URL: server.URL + "/fail"},
+		},
+		Rules: []config.NotificationRuleConfig{
+			{On: []string{"account.rotated"}, Sinks: []string{"hook", "slack"}},
+			{On: []string{"account.rotated"}, Sinks: []string{"broken"}},
+		},
+	}
+	notifier, err := New(cfg, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rotated := event(t, models.EventTypeAccountRotated, models.EntityTypeAgent, "agent-1", map[string]string{"reason": "rate_limit"})
+	err = notifier.Notify(context.Background(), rotated)
+	if err == nil || !strings.Contains(err.Error(), "sink broken: returned status 502") {
+		t.Fatalf("expected broken sink error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	hook := requests["/hook"]
+	if hook["title"] != "account.rotated agent agent-1" || hook["rule"] != "rule-1" || headers["/hook"] != "secret" {
+		t.Fatalf("unexpected webhook request %v (token %q)", hook, headers["/hook"])
+	}
+	if event, ok := hook["event"].(map[string]any); !ok || event["entity_id"] != "agent-1" {
+		t.Fatalf("expected event in webhook body, got %v", hook["event"])
+	}
+	slack := requests["/slack"]
+	if slack["channel"] != "#ops" || slack["text"] != "*account.rotated agent agent-1*\n{\"reason\":\"rate_limit\"}" {
+		t.Fatalf("unexpected slack request %v", slack)
+	}
+}
+
+func TestEmailSink(t *testing.T) {
+	t.Setenv("FORGE_TEST_SMTP_PASSWORD", "hunter2")
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+		gotAuth smtp.Auth
+	)
+	send := func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotTo, gotMsg = addr, auth, to, string(msg)
+		return nil
+	}
+	cfg := config.NotificationsConfig{
+		Sinks: []config.NotificationSinkConfig{{
+			Name: "mail", Type: config.NotificationSinkEmail, SMTPHost: "smtp.example.com",
+			Username: "forge", PasswordRef: "env:FORGE_TEST_SMTP_PASSWORD",
+			From: "forge@example.com", To: []string{"ops@example.com", "oncall@example.com"},
+		}},
+	}
+	notifier, err := New(cfg, WithSendMail(send))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := notifier.Test(context.Background(), "mail"); err != nil {
+		t.Fatalf("Test: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotAuth == nil || len(gotTo) != 2 {
+		t.Fatalf("unexpected send addr=%q auth=%v to=%v", gotAddr, gotAuth, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: Forge test notification\r\n") || !strings.Contains(gotMsg, "To: ops@example.com, oncall@example.com\r\n") {
+		t.Fatalf("unexpected message:\n%s", gotMsg)
+	}
+	if err := notifier.Test(context.Background(), "nope"); err == nil {
+		t.Fatalf("expected unknown sink error")
+	}
+}
+
+func TestPollNotifiesNewEvents(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close()
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := db.NewEventRepository(database)
+
+	ops := &recordingSink{name: "ops"}
+	notifier, err := New(config.NotificationsConfig{
+		Rules: []config.NotificationRuleConfig{{On: []string{TriggerLoopFinished}, Sinks: []string{"ops"}}},
+	}, WithSink(ops))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	since := time.Now().Add(-time.Minute)
+	for _, name := range []string{"a", "b"} {
+		if err := repo.Create(ctx, event(t, models.EventTypeLoopStateChanged, models.EntityTypeLoop, "loop-"+name,
+			models.LoopEventPayload{LoopName: name, State: models.LoopStateStopped})); err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+	}
+	cursor, err := notifier.poll(ctx, repo, "", since)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if len(ops.sent()) != 2 || cursor == "" {
+		t.Fatalf("expected 2 notifications, got %d (cursor %q)", len(ops.sent()), cursor)
+	}
+
+	if cursor, err = notifier.poll(ctx, repo, cursor, since); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if len(ops.sent()) != 2 {
+		t.Fatalf("expected no repeat notifications, got %d", len(ops.sent()))
+	}
+}
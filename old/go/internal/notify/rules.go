@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Named triggers. Any other trigger is compared to the event type.
+const (
+	TriggerAgentError   = "agent_error"
+	TriggerDiskCritical = "disk_critical"
+	TriggerLoopFinished = "loop_finished"
+	TriggerLoopFailed   = "loop_failed"
+)
+
+var defaultTitles = map[string]string{
+	TriggerAgentError:   `Agent {{.EntityID}} hit an error`,
+	TriggerDiskCritical: `Disk critical on {{with .Payload.node_name}}{{.}}{{else}}{{$.EntityID}}{{end}}`,
+	TriggerLoopFinished: `Loop {{with .Payload.loop_name}}{{.}}{{else}}{{$.EntityID}}{{end}} finished`,
+	TriggerLoopFailed:   `Loop {{with .Payload.loop_name}}{{.}}{{else}}{{$.EntityID}}{{end}} failed`,
+}
+
+var defaultBodies = map[string]string{
+	TriggerAgentError:   `{{with .Payload.reason}}{{.}}{{else}}{{with .Payload.error}}{{.}}{{end}}{{end}}`,
+	TriggerDiskCritical: `{{with .Payload.details}}{{.}}{{end}}`,
+	TriggerLoopFinished: `{{with .Payload.previous_state}}{{.}} -> {{end}}{{.Payload.state}}`,
+	TriggerLoopFailed:   `{{with .Payload.exit_code}}exit {{.}}: {{end}}{{with .Payload.error}}{{.}}{{end}}`,
+}
+
+const (
+	fallbackTitle = `{{.Type}} {{.EntityType}} {{.EntityID}}`
+	fallbackBody  = `{{.PayloadJSON}}`
+)
+
+// TemplateData is the value rule templates are executed against.
+type TemplateData struct {
+	Rule        string
+	Trigger     string
+	Type        models.EventType
+	EntityType  models.EntityType
+	EntityID    string
+	Timestamp   time.Time
+	Payload     map[string]any
+	PayloadJSON string
+}
+
+type rule struct {
+	name     string
+	triggers []string
+	entityID string
+	sinks    []string
+	title    *template.Template
+	body     *template.Template
+	titles   map[string]*template.Template
+	bodies   map[string]*template.Template
+}
+
+func newRule(index int, cfg config.NotificationRuleConfig) (*rule, error) {
+	r := &rule{
+		name:     strings.TrimSpace(cfg.Name),
+		entityID: strings.TrimSpace(cfg.EntityID),
+		titles:   make(map[string]*template.Template),
+		bodies:   make(map[string]*template.Template),
+	}
+	if r.name == "" {
+		r.name = fmt.Sprintf("rule-%d", index+1)
+	}
+	for _, trigger := range cfg.On {
+		if trigger = strings.TrimSpace(trigger); trigger != "" {
+			r.triggers = append(r.triggers, trigger)
+		}
+	}
+	for _, sink := range cfg.Sinks {
+		r.sinks = append(r.sinks, strings.TrimSpace(sink))
+	}
+
+	var err error
+	if strings.TrimSpace(cfg.Title) != "" {
+		if r.title, err = parseTemplate(r.name, "title", cfg.Title); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(cfg.Body) != "" {
+		if r.body, err = parseTemplate(r.name, "body", cfg.Body); err != nil {
+			return nil, err
+		}
+	}
+	for _, trigger := range r.triggers {
+		if r.titles[trigger], err = parseTemplate(r.name, "title", defaultTemplate(defaultTitles, trigger, fallbackTitle)); err != nil {
+			return nil, err
+		}
+		if r.bodies[trigger], err = parseTemplate(r.name, "body", defaultTemplate(defaultBodies, trigger, fallbackBody)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func parseTemplate(ruleName, field, text string) (*template.Template, error) {
+	tmpl, err := template.New(ruleName + "." + field).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("notification rule %q: invalid %s template: %w", ruleName, field, err)
+	}
+	return tmpl, nil
+}
+
+func defaultTemplate(defaults map[string]string, trigger, fallback string) string {
+	if text, ok := defaults[trigger]; ok {
+		return text
+	}
+	return fallback
+}
+
+// match returns the first trigger of the rule that fires for the event.
+func (r *rule) match(event *models.Event, payload map[string]any) (string, bool) {
+	if r.entityID != "" && event.EntityID != r.entityID {
+		return "", false
+	}
+	for _, trigger := range r.triggers {
+		if triggerMatches(trigger, event, payload) {
+			return trigger, true
+		}
+	}
+	return "", false
+}
+
+func triggerMatches(trigger string, event *models.Event, payload map[string]any) bool {
+	switch trigger {
+	case TriggerAgentError:
+		if event.Type == models.EventTypeAgentStateChanged {
+			return payloadString(payload, "new_state") == string(models.AgentStateError)
+		}
+		return event.Type == models.EventTypeError && event.EntityType == models.EntityTypeAgent
+	case TriggerDiskCritical:
+		return event.Type == models.EventTypeNodeDiskCritical
+	case TriggerLoopFinished:
+		return event.Type == models.EventTypeLoopStateChanged &&
+			payloadString(payload, "state") == string(models.LoopStateStopped)
+	case TriggerLoopFailed:
+		if event.Type == models.EventTypeLoopRunFailed {
+			return true
+		}
+		return event.Type == models.EventTypeLoopStateChanged &&
+			payloadString(payload, "state") == string(models.LoopStateError)
+	default:
+		return string(event.Type) == trigger
+	}
+}
+
+func (r *rule) render(trigger string, event *models.Event, payload map[string]any) (Notification, error) {
+	data := TemplateData{
+		Rule:        r.name,
+		Trigger:     trigger,
+		Type:        event.Type,
+		EntityType:  event.EntityType,
+		EntityID:    event.EntityID,
+		Timestamp:   event.Timestamp,
+		Payload:     payload,
+		PayloadJSON: string(event.Payload),
+	}
+
+	title, body := r.title, r.body
+	if title == nil {
+		title = r.titles[trigger]
+	}
+	if body == nil {
+		body = r.bodies[trigger]
+	}
+
+	notification := Notification{Rule: r.name, Trigger: trigger, Event: event}
+	var err error
+	if notification.Title, err = execute(title, data); err != nil {
+		return notification, fmt.Errorf("notification rule %q: %w", r.name, err)
+	}
+	if notification.Body, err = execute(body, data); err != nil {
+		return notification, fmt.Errorf("notification rule %q: %w", r.name, err)
+	}
+	return notification, nil
+}
+
+func execute(tmpl *template.Template, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// decodePayload returns the event payload as a map; non-object payloads
+// yield an empty map so templates can still index it.
+func decodePayload(event *models.Event) map[string]any {
+	payload := map[string]any{}
+	if len(event.Payload) > 0 {
+		_ = json.Unmarshal(event.Payload, &payload)
+	}
+	return payload
+}
+
+func payloadString(payload map[string]any, key string) string {
+	value, _ := payload[key].(string)
+	return value
+}
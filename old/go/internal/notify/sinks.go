@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/account"
+	"github.com/tOgg1/forge/internal/config"
+)
+
+// SendMailFunc matches smtp.SendMail.
+type SendMailFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// WebhookSink POSTs the notification as JSON.
+type WebhookSink struct {
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// Name returns the sink name.
+func (s *WebhookSink) Name() string { return s.name }
+
+// Send posts the notification, including the triggering event.
+func (s *WebhookSink) Send(ctx context.Context, notification Notification) error {
+	return postJSON(ctx, s.client, s.url, s.headers, notification)
+}
+
+// SlackSink posts to a Slack incoming webhook.
+type SlackSink struct {
+	name    string
+	url     string
+	channel string
+	client  *http.Client
+}
+
+// Name returns the sink name.
+func (s *SlackSink) Name() string { return s.name }
+
+// Send posts the title in bold followed by the body.
+func (s *SlackSink) Send(ctx context.Context, notification Notification) error {
+	text := "*" + notification.Title + "*"
+	if notification.Body != "" {
+		text += "\n" + notification.Body
+	}
+	message := map[string]string{"text": text}
+	if s.channel != "" {
+		message["channel"] = s.channel
+	}
+	return postJSON(ctx, s.client, s.url, nil, message)
+}
+
+// EmailSink sends plain-text mail over SMTP.
+type EmailSink struct {
+	name        string
+	addr        string
+	host        string
+	username    string
+	passwordRef string
+	from        string
+	to          []string
+	sendMail    SendMailFunc
+}
+
+func newEmailSink(name string, cfg config.NotificationSinkConfig, send SendMailFunc) *EmailSink {
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return &EmailSink{
+		name:        name,
+		addr:        net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+		host:        cfg.SMTPHost,
+		username:    cfg.Username,
+		passwordRef: cfg.PasswordRef,
+		from:        cfg.From,
+		to:          cfg.To,
+		sendMail:    send,
+	}
+}
+
+// Name returns the sink name.
+func (s *EmailSink) Name() string { return s.name }
+
+// Send delivers the notification with the title as subject. net/smtp does
+// not take a context, so a cancelled context only stops sends not yet started.
+func (s *EmailSink) Send(ctx context.Context, notification Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.username != "" {
+		password, err := resolveSecret(s.passwordRef)
+		if err != nil {
+			return fmt.Errorf("resolve smtp password: %w", err)
+		}
+		auth = smtp.PlainAuth("", s.username, password, s.host)
+	}
+	return s.sendMail(s.addr, auth, s.from, s.to, s.message(notification))
+}
+
+func (s *EmailSink) message(notification Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(notification.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// headerValue keeps a rendered title on one header line.
+func headerValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func postJSON(ctx context.Context, client *http.Client, rawURL string, headers map[string]string, payload any) error {
+	url, err := resolveSecret(rawURL)
+	if err != nil {
+		return fmt.Errorf("resolve url: %w", err)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		if strings.TrimSpace(key) != "" {
+			request.Header.Set(key, value)
+		}
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("returned status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// resolveSecret resolves credential references (env:, $VAR, file:, vault:)
+// and passes plain values through.
+func resolveSecret(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	return account.ResolveCredential(value)
+}
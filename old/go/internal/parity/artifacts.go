@@ -40,7 +40,7 @@ func WriteDiffArtifacts(expectedDir, actualDir, outputDir string) (Report, error
 	if err := writeManifest(filepath.Join(normalizedOut, "report.json"), report); err != nil {
 		return report, err
 	}
-	if err := writeDriftReport(normalizedOut, report); err != nil {
+	if err := writeDriftReport(normalizedOut, expectedDir, report); err != nil {
 		return report, err
 	}
 
@@ -73,13 +73,23 @@ func WriteDiffArtifacts(expectedDir, actualDir, outputDir string) (Report, error
 }
 
 func writeMismatchDiff(outDir, rel, expectedPath, actualPath string) error {
-	expectedBytes, err := os.ReadFile(expectedPath)
+	text, err := mismatchDiff(rel, expectedPath, actualPath)
 	if err != nil {
 		return err
 	}
+	return os.WriteFile(filepath.Join(outDir, slug(rel)+".diff"), []byte(text), 0o644)
+}
+
+// mismatchDiff renders a unified diff of the normalized expected and actual
+// files.
+func mismatchDiff(rel, expectedPath, actualPath string) (string, error) {
+	expectedBytes, err := os.ReadFile(expectedPath)
+	if err != nil {
+		return "", err
+	}
 	actualBytes, err := os.ReadFile(actualPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	expectedLines := difflib.SplitLines(string(normalize(expectedBytes)))
@@ -91,12 +101,7 @@ func writeMismatchDiff(outDir, rel, expectedPath, actualPath string) error {
 		ToFile:   "actual/" + rel,
 		Context:  3,
 	}
-	text, err := difflib.GetUnifiedDiffString(ud)
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(filepath.Join(outDir, slug(rel)+".diff"), []byte(text), 0o644)
+	return difflib.GetUnifiedDiffString(ud)
 }
 
 func writeManifest(path string, report Report) error {
@@ -146,6 +151,8 @@ type driftReportItem struct {
 	RootCause     string `json:"root_cause"`
 	Action        string `json:"action"`
 	TrackingIssue string `json:"tracking_issue"`
+	// Golden is the expected file the path was compared against.
+	Golden string `json:"golden,omitempty"`
 }
 
 type alertRoutingReport struct {
@@ -166,7 +173,7 @@ type alertOwnerRoute struct {
 	Paths []string `json:"paths"`
 }
 
-func writeDriftReport(outDir string, report Report) error {
+func writeDriftReport(outDir, expectedDir string, report Report) error {
 	items := make([]driftReportItem, 0, len(report.MissingExpected)+len(report.Mismatched)+len(report.Unexpected))
 	appendItems := func(paths []string, priority, driftType string) {
 		for _, rel := range paths {
@@ -178,6 +185,7 @@ func writeDriftReport(outDir string, report Report) error {
 				RootCause:     "TODO",
 				Action:        "TODO",
 				TrackingIssue: "TODO",
+				Golden:        filepath.ToSlash(filepath.Join(expectedDir, filepath.FromSlash(rel))),
 			})
 		}
 	}
@@ -264,6 +272,11 @@ func renderDriftTriageMarkdown(rep driftReport) string {
 		)
 	}
 
+	fmt.Fprintf(&b, "\n## Goldens\n\n")
+	for _, item := range rep.Items {
+		fmt.Fprintf(&b, "- `%s`: `%s`\n", escapeMarkdownCell(item.Path), item.Golden)
+	}
+
 	fmt.Fprintf(&b, "\n## Fill Rules\n\n")
 	fmt.Fprintf(&b, "- Set owner + root cause + action + tracking issue before closing parity incident.\n")
 	fmt.Fprintf(&b, "- Keep one row per drift path; split follow-up fixes into separate linked tasks.\n")
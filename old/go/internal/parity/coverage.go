@@ -0,0 +1,441 @@
+package parity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxHelpDepth bounds how deep SurfaceFromHelp descends into subcommands.
+const maxHelpDepth = 4
+
+// SurfaceFromHelp builds a command surface by walking a Cobra-style binary's
+// --help output: the root help, then every subcommand's help, to
+// maxHelpDepth levels.
+func SurfaceFromHelp(ctx context.Context, binary string, timeout time.Duration) (SurfaceManifest, error) {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	rootHelp, err := runHelp(ctx, timeout, binary, nil)
+	if err != nil {
+		return SurfaceManifest{}, err
+	}
+	manifest := SurfaceManifest{
+		CLI:         strings.TrimSuffix(filepath.Base(binary), filepath.Ext(binary)),
+		GlobalFlags: ParseGlobalFlagsFromHelp(rootHelp),
+		Commands:    ParseRootHelp(rootHelp),
+	}
+	if len(manifest.GlobalFlags) == 0 {
+		// Root help lists the persistent flags under "Flags:".
+		manifest.GlobalFlags, _ = ParseCommandHelp(rootHelp)
+	}
+	if err := describeCommands(ctx, timeout, binary, nil, manifest.Commands, 1); err != nil {
+		return SurfaceManifest{}, err
+	}
+	return manifest, nil
+}
+
+func describeCommands(ctx context.Context, timeout time.Duration, binary string, parents []string, cmds []SurfaceCommand, depth int) error {
+	for i := range cmds {
+		if cmds[i].Name == "help" {
+			continue
+		}
+		path := append(append([]string(nil), parents...), cmds[i].Name)
+		help, err := runHelp(ctx, timeout, binary, path)
+		if err != nil {
+			return err
+		}
+		cmds[i].Flags, cmds[i].Subcommands = ParseCommandHelp(help)
+		cmds[i].Aliases = parseAliasSection(help, cmds[i].Name)
+		if depth >= maxHelpDepth {
+			cmds[i].Subcommands = nil
+			continue
+		}
+		if err := describeCommands(ctx, timeout, binary, path, cmds[i].Subcommands, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runHelp(ctx context.Context, timeout time.Duration, binary string, path []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, binary, append(append([]string(nil), path...), "--help")...)
+	cmd.Env = append(os.Environ(), "FORGE_NON_INTERACTIVE=1")
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil && ctx.Err() != nil {
+		return "", fmt.Errorf("%s %s --help: %w", binary, strings.Join(path, " "), ctx.Err())
+	}
+	if strings.TrimSpace(stdout.String()) == "" {
+		return stderr.String(), nil
+	}
+	return stdout.String(), nil
+}
+
+// parseAliasSection reads the "Aliases:" section of Cobra help, which lists
+// the command's own name first.
+func parseAliasSection(helpText, name string) []string {
+	lines := strings.Split(helpText, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "Aliases:" || i+1 >= len(lines) {
+			continue
+		}
+		var aliases []string
+		for _, alias := range strings.Split(lines[i+1], ",") {
+			alias = strings.TrimSpace(alias)
+			if alias != "" && alias != name {
+				aliases = append(aliases, alias)
+			}
+		}
+		return aliases
+	}
+	return nil
+}
+
+// CoverageGap is a command or flag no scenario exercises.
+type CoverageGap struct {
+	Kind string `json:"kind"` // "command", "flag", "global_flag"
+	Path string `json:"path"` // dotted path e.g. "forge.loop.up" or "forge.loop.up.--count"
+}
+
+// FlagCoverage counts the steps that passed one flag.
+type FlagCoverage struct {
+	Name string `json:"name"`
+	Hits int    `json:"hits"`
+}
+
+// CommandCoverage counts the steps that invoked one command.
+type CommandCoverage struct {
+	Path      string         `json:"path"`
+	Hits      int            `json:"hits"`
+	Covered   bool           `json:"covered"`
+	Scenarios []string       `json:"scenarios,omitempty"`
+	Flags     []FlagCoverage `json:"flags,omitempty"`
+}
+
+// CoverageReport summarizes which parts of a CLI surface the parity
+// scenarios exercise. Gaps is the machine-readable list of what is left.
+type CoverageReport struct {
+	CLI             string            `json:"cli"`
+	GeneratedAt     string            `json:"generated_at"`
+	Scenarios       []string          `json:"scenarios"`
+	Steps           int               `json:"steps"`
+	Commands        int               `json:"commands"`
+	CoveredCommands int               `json:"covered_commands"`
+	Flags           int               `json:"flags"`
+	CoveredFlags    int               `json:"covered_flags"`
+	GlobalFlags     []FlagCoverage    `json:"global_flags,omitempty"`
+	Coverage        []CommandCoverage `json:"coverage"`
+	Gaps            []CoverageGap     `json:"gaps"`
+	// Unresolved lists step args whose first word matched no command.
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// CommandPercent is the share of commands covered, 0-100.
+func (r CoverageReport) CommandPercent() float64 {
+	return percent(r.CoveredCommands, r.Commands)
+}
+
+// FlagPercent is the share of flags covered, 0-100.
+func (r CoverageReport) FlagPercent() float64 {
+	return percent(r.CoveredFlags, r.Flags)
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
+
+// CoverageSummary returns a one-line summary.
+func CoverageSummary(r CoverageReport) string {
+	return fmt.Sprintf("scenarios=%d steps=%d commands=%d/%d (%.1f%%) flags=%d/%d (%.1f%%) gaps=%d unresolved=%d",
+		len(r.Scenarios), r.Steps,
+		r.CoveredCommands, r.Commands, r.CommandPercent(),
+		r.CoveredFlags, r.Flags, r.FlagPercent(),
+		len(r.Gaps), len(r.Unresolved))
+}
+
+// WriteCoverageReport writes an indented JSON report.
+func WriteCoverageReport(path string, report CoverageReport) error {
+	return writeIndentedJSON(path, report)
+}
+
+// WriteCoverageGaps writes just the gap list as a JSON array.
+func WriteCoverageGaps(path string, report CoverageReport) error {
+	gaps := report.Gaps
+	if gaps == nil {
+		gaps = []CoverageGap{}
+	}
+	return writeIndentedJSON(path, gaps)
+}
+
+func writeIndentedJSON(path string, value any) error {
+	body, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	body = append(body, '\n')
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+type coverageFlag struct {
+	flag SurfaceFlag
+	hits int
+}
+
+type coverageNode struct {
+	path      string
+	children  map[string]*coverageNode // by name and alias
+	ordered   []*coverageNode
+	flags     []*coverageFlag
+	byLong    map[string]*coverageFlag
+	byShort   map[string]*coverageFlag
+	hits      int
+	scenarios map[string]struct{}
+}
+
+func newCoverageNode(path string, flags []SurfaceFlag) *coverageNode {
+	node := &coverageNode{
+		path:      path,
+		children:  make(map[string]*coverageNode),
+		byLong:    make(map[string]*coverageFlag),
+		byShort:   make(map[string]*coverageFlag),
+		scenarios: make(map[string]struct{}),
+	}
+	for _, flag := range flags {
+		if flag.Inherited || flag.Long == "help" {
+			continue
+		}
+		entry := &coverageFlag{flag: flag}
+		node.flags = append(node.flags, entry)
+		node.byLong[flag.Long] = entry
+		if flag.Short != "" {
+			node.byShort[flag.Short] = entry
+		}
+	}
+	return node
+}
+
+func (n *coverageNode) addChildren(cmds []SurfaceCommand) {
+	for _, cmd := range cmds {
+		if cmd.Name == "help" {
+			continue
+		}
+		child := newCoverageNode(n.path+"."+cmd.Name, cmd.Flags)
+		child.addChildren(cmd.Subcommands)
+		n.ordered = append(n.ordered, child)
+		n.children[cmd.Name] = child
+		for _, alias := range cmd.Aliases {
+			if _, taken := n.children[alias]; !taken {
+				n.children[alias] = child
+			}
+		}
+	}
+}
+
+// covered reports whether a step invoked the command or, for command
+// groups, any of their subcommands.
+func (n *coverageNode) covered() bool {
+	if n.hits > 0 {
+		return true
+	}
+	for _, child := range n.ordered {
+		if child.covered() {
+			return true
+		}
+	}
+	return false
+}
+
+// CoverageTracker accumulates which commands and flags of a CLI surface
+// scenario steps exercise.
+type CoverageTracker struct {
+	cli        string
+	root       *coverageNode
+	scenarios  []string
+	steps      int
+	unresolved []string
+}
+
+// NewCoverageTracker starts tracking coverage of manifest.
+func NewCoverageTracker(manifest SurfaceManifest) *CoverageTracker {
+	cli := manifest.CLI
+	if cli == "" {
+		cli = "forge"
+	}
+	root := newCoverageNode(cli, manifest.GlobalFlags)
+	root.addChildren(manifest.Commands)
+	return &CoverageTracker{cli: cli, root: root}
+}
+
+// RecordScenario records every step of scenario.
+func (t *CoverageTracker) RecordScenario(scenario LifecycleScenario) {
+	name := scenario.Name
+	if name == "" {
+		name = fmt.Sprintf("scenario-%d", len(t.scenarios)+1)
+	}
+	t.scenarios = append(t.scenarios, name)
+	for _, step := range scenario.Steps {
+		t.Record(name, step.Args)
+	}
+}
+
+// Record resolves one invocation's args (without the binary name) to a
+// command and the flags it passed. Command words are matched until the
+// first positional argument; flags are credited to the nearest command
+// that defines them, then to the global flags.
+func (t *CoverageTracker) Record(scenario string, args []string) {
+	t.steps++
+	node := t.root
+	chain := []*coverageNode{t.root}
+	positional := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if flag := lookupFlag(chain, name, false); flag != nil {
+				flag.hits++
+				if !hasValue && flag.flag.Type != "bool" {
+					i++
+				}
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			shorts := []rune(strings.TrimPrefix(arg, "-"))
+			for j, short := range shorts {
+				flag := lookupFlag(chain, string(short), true)
+				if flag == nil {
+					break
+				}
+				flag.hits++
+				if flag.flag.Type != "bool" {
+					if j == len(shorts)-1 {
+						i++
+					}
+					break
+				}
+			}
+		case !positional:
+			if child, ok := node.children[arg]; ok {
+				node = child
+				chain = append(chain, child)
+				continue
+			}
+			positional = true
+		}
+	}
+	if node == t.root {
+		t.unresolved = append(t.unresolved, strings.Join(args, " "))
+		return
+	}
+	node.hits++
+	node.scenarios[scenario] = struct{}{}
+}
+
+func lookupFlag(chain []*coverageNode, name string, short bool) *coverageFlag {
+	for i := len(chain) - 1; i >= 0; i-- {
+		index := chain[i].byLong
+		if short {
+			index = chain[i].byShort
+		}
+		if flag, ok := index[name]; ok {
+			return flag
+		}
+	}
+	return nil
+}
+
+// Report returns the coverage accumulated so far. Flags of uncovered
+// commands are counted but not listed as gaps; the command gap covers them.
+func (t *CoverageTracker) Report() CoverageReport {
+	report := CoverageReport{
+		CLI:         t.cli,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Scenarios:   append([]string{}, t.scenarios...),
+		Steps:       t.steps,
+		Coverage:    []CommandCoverage{},
+		Gaps:        []CoverageGap{},
+		Unresolved:  append([]string(nil), t.unresolved...),
+	}
+	for _, flag := range t.root.flags {
+		report.Flags++
+		report.GlobalFlags = append(report.GlobalFlags, FlagCoverage{Name: flag.flag.Long, Hits: flag.hits})
+		if flag.hits > 0 {
+			report.CoveredFlags++
+			continue
+		}
+		report.Gaps = append(report.Gaps, CoverageGap{Kind: "global_flag", Path: t.root.path + ".--" + flag.flag.Long})
+	}
+	var walk func(nodes []*coverageNode)
+	walk = func(nodes []*coverageNode) {
+		for _, node := range nodes {
+			covered := node.covered()
+			entry := CommandCoverage{Path: node.path, Hits: node.hits, Covered: covered}
+			for name := range node.scenarios {
+				entry.Scenarios = append(entry.Scenarios, name)
+			}
+			sort.Strings(entry.Scenarios)
+			report.Commands++
+			if covered {
+				report.CoveredCommands++
+			} else {
+				report.Gaps = append(report.Gaps, CoverageGap{Kind: "command", Path: node.path})
+			}
+			for _, flag := range node.flags {
+				report.Flags++
+				entry.Flags = append(entry.Flags, FlagCoverage{Name: flag.flag.Long, Hits: flag.hits})
+				if flag.hits > 0 {
+					report.CoveredFlags++
+				} else if covered {
+					report.Gaps = append(report.Gaps, CoverageGap{Kind: "flag", Path: node.path + ".--" + flag.flag.Long})
+				}
+			}
+			report.Coverage = append(report.Coverage, entry)
+			walk(node.ordered)
+		}
+	}
+	walk(t.root.ordered)
+	sort.SliceStable(report.Gaps, func(i, j int) bool { return report.Gaps[i].Path < report.Gaps[j].Path })
+	return report
+}
+
+// ScenarioFiles expands directories into their *.json scenario files.
+func ScenarioFiles(args []string) ([]string, error) {
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			out = append(out, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no scenario files in %s", arg)
+		}
+		sort.Strings(matches)
+		out = append(out, matches...)
+	}
+	return out, nil
+}
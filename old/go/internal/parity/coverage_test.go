@@ -0,0 +1,206 @@
+package parity
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func coverageManifest() SurfaceManifest {
+	return SurfaceManifest{
+		CLI: "forge",
+		GlobalFlags: []SurfaceFlag{
+			{Long: "json", Type: "bool"},
+			{Long: "config", Type: "string"},
+		},
+		Commands: []SurfaceCommand{
+			{
+				Name: "loop",
+				Subcommands: []SurfaceCommand{
+					{Name: "up", Flags: []SurfaceFlag{
+						{Long: "count", Short: "n", Type: "int"},
+						{Long: "name", Type: "string"},
+						{Long: "detach", Short: "d", Type: "bool"},
+					}},
+					{Name: "ps", Aliases: []string{"list"}},
+				},
+			},
+			{Name: "up", Flags: []SurfaceFlag{{Long: "count", Short: "n", Type: "int"}}},
+			{Name: "status"},
+		},
+	}
+}
+
+func TestCoverageTrackerResolvesCommandsAndFlags(t *testing.T) {
+	tracker := NewCoverageTracker(coverageManifest())
+	tracker.RecordScenario(LifecycleScenario{Name: "lifecycle", Steps: []LifecycleStep{
+		{Name: "up", Args: []string{"loop", "up", "-dn", "2", "--name=alpha"}},
+		{Name: "ps", Args: []string{"--json", "loop", "list"}},
+		{Name: "stray", Args: []string{"touch", "up"}},
+	}})
+	tracker.Record("adhoc", []string{"--config", "status", "loop", "up", "--", "--count"})
+	report := tracker.Report()
+
+	if report.Steps != 4 || report.Commands != 5 || report.CoveredCommands != 3 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	if report.Flags != 6 || report.CoveredFlags != 5 {
+		t.Fatalf("unexpected flag totals: flags=%d covered=%d", report.Flags, report.CoveredFlags)
+	}
+	want := []CoverageGap{{Kind: "command", Path: "forge.status"}, {Kind: "command", Path: "forge.up"}}
+	if !reflect.DeepEqual(report.Gaps, want) {
+		t.Fatalf("unexpected gaps %+v", report.Gaps)
+	}
+	if !reflect.DeepEqual(report.Unresolved, []string{"touch up"}) {
+		t.Fatalf("unexpected unresolved %v", report.Unresolved)
+	}
+
+	byPath := make(map[string]CommandCoverage)
+	for _, entry := range report.Coverage {
+		byPath[entry.Path] = entry
+	}
+	if got := byPath["forge.loop"]; !got.Covered || got.Hits != 0 {
+		t.Fatalf("expected loop covered through subcommands, got %+v", got)
+	}
+	if got := byPath["forge.loop.ps"]; got.Hits != 1 || !reflect.DeepEqual(got.Scenarios, []string{"lifecycle"}) {
+		t.Fatalf("expected alias to resolve to ps, got %+v", got)
+	}
+	up := byPath["forge.loop.up"]
+	if up.Hits != 2 || !reflect.DeepEqual(up.Scenarios, []string{"adhoc", "lifecycle"}) {
+		t.Fatalf("expected --config to consume its value before loop up, got %+v", up)
+	}
+	wantFlags := []FlagCoverage{{Name: "count", Hits: 1}, {Name: "name", Hits: 1}, {Name: "detach", Hits: 1}}
+	if !reflect.DeepEqual(up.Flags, wantFlags) {
+		t.Fatalf("unexpected up flags %+v", up.Flags)
+	}
+}
+
+func TestCoverageTrackerReportsFlagGapsForCoveredCommands(t *testing.T) {
+	tracker := NewCoverageTracker(coverageManifest())
+	tracker.Record("s", []string{"up"})
+	report := tracker.Report()
+
+	gaps := make(map[string]string)
+	for _, gap := range report.Gaps {
+		gaps[gap.Path] = gap.Kind
+	}
+	if gaps["forge.up.--count"] != "flag" {
+		t.Fatalf("expected flag gap for covered command, got %+v", report.Gaps)
+	}
+	if _, ok := gaps["forge.loop.up.--count"]; ok {
+		t.Fatalf("flags of uncovered commands should fold into the command gap: %+v", report.Gaps)
+	}
+	if gaps["forge.--json"] != "global_flag" || gaps["forge.loop"] != "command" {
+		t.Fatalf("expected global flag and command gaps, got %+v", report.Gaps)
+	}
+
+	dir := t.TempDir()
+	gapsPath := filepath.Join(dir, "gaps.json")
+	if err := WriteCoverageGaps(gapsPath, report); err != nil {
+		t.Fatalf("write gaps: %v", err)
+	}
+	data, err := os.ReadFile(gapsPath)
+	if err != nil {
+		t.Fatalf("read gaps: %v", err)
+	}
+	var decoded []CoverageGap
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decode gaps: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, report.Gaps) {
+		t.Fatalf("gap file mismatch: %+v", decoded)
+	}
+}
+
+func TestSurfaceFromHelpWalksSubcommands(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "forge")
+	writeScript(t, bin, `#!/usr/bin/env bash
+case "$*" in
+  "--help")
+    cat <<'HELP'
+Usage:
+  forge [command]
+
+Available Commands:
+  loop        Manage loops
+  help        Help about any command
+
+Flags:
+      --json            output JSON
+  -h, --help            help for forge
+HELP
+    ;;
+  "loop --help")
+    cat <<'HELP'
+Usage:
+  forge loop [command]
+
+Aliases:
+  loop, loops
+
+Available Commands:
+  up          Start loops
+
+Global Flags:
+      --json   output JSON
+HELP
+    ;;
+  "loop up --help")
+    cat <<'HELP'
+Usage:
+  forge loop up [flags]
+
+Flags:
+  -n, --count int         number of loops (default 1)
+      --tags strings      loop tags
+  -h, --help              help for up
+HELP
+    ;;
+esac
+`)
+
+	manifest, err := SurfaceFromHelp(context.Background(), bin, 5*time.Second)
+	if err != nil {
+		t.Fatalf("surface from help: %v", err)
+	}
+	if manifest.CLI != "forge" || !hasSurfaceFlag(manifest.GlobalFlags, "json") {
+		t.Fatalf("unexpected root surface %+v", manifest)
+	}
+	var loop SurfaceCommand
+	for _, cmd := range manifest.Commands {
+		if cmd.Name == "loop" {
+			loop = cmd
+		}
+	}
+	if !reflect.DeepEqual(loop.Aliases, []string{"loops"}) || len(loop.Subcommands) != 1 {
+		t.Fatalf("unexpected loop surface %+v", loop)
+	}
+	var tags SurfaceFlag
+	for _, flag := range loop.Subcommands[0].Flags {
+		if flag.Long == "tags" {
+			tags = flag
+		}
+	}
+	if tags.Type != "strings" {
+		t.Fatalf("expected tags to parse as strings flag, got %+v", loop.Subcommands[0].Flags)
+	}
+
+	tracker := NewCoverageTracker(manifest)
+	tracker.Record("s", []string{"loops", "up", "--tags", "a,b", "-n", "3"})
+	if report := tracker.Report(); report.CoveredCommands != 2 || len(report.Unresolved) != 0 {
+		t.Fatalf("expected loop and loop up covered, got %+v", report)
+	}
+}
+
+func hasSurfaceFlag(flags []SurfaceFlag, long string) bool {
+	for _, flag := range flags {
+		if flag.Long == long {
+			return true
+		}
+	}
+	return false
+}
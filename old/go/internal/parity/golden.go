@@ -0,0 +1,152 @@
+package parity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoldenConfig configures recording expected output for one scenario.
+type GoldenConfig struct {
+	Binary     string
+	FixtureDir string
+	Scenario   LifecycleScenario
+	ExtraEnv   map[string]string
+	Timeout    time.Duration
+}
+
+// GoldenScenarioDir returns the directory name used for a scenario's goldens.
+func GoldenScenarioDir(scenario LifecycleScenario) string {
+	name := strings.TrimSpace(scenario.Name)
+	if name == "" {
+		name = "scenario"
+	}
+	return slug(name)
+}
+
+// RecordGolden runs every scenario step against cfg.Binary and writes
+// normalized <step>.stdout.txt, <step>.stderr.txt and <step>.exit-code.txt
+// files under outDir/<scenario>. Output is normalized the same way the
+// lifecycle harness compares it, so goldens stay stable across runs.
+func RecordGolden(ctx context.Context, cfg GoldenConfig, outDir string) ([]string, error) {
+	if strings.TrimSpace(cfg.Binary) == "" {
+		return nil, errors.New("binary path is required")
+	}
+	if err := validateLifecycleScenario(cfg.Scenario); err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "forge-parity-golden-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+	if cfg.FixtureDir != "" {
+		if err := copyTree(cfg.FixtureDir, workDir); err != nil {
+			return nil, fmt.Errorf("copy fixture: %w", err)
+		}
+	}
+
+	scenarioDir := GoldenScenarioDir(cfg.Scenario)
+	if err := os.MkdirAll(filepath.Join(outDir, scenarioDir), 0o755); err != nil {
+		return nil, err
+	}
+
+	env := buildHarnessEnv(cfg.Scenario.Env, cfg.ExtraEnv)
+	written := make([]string, 0, len(cfg.Scenario.Steps)*3)
+	for _, step := range cfg.Scenario.Steps {
+		result, err := runHarnessStep(ctx, cfg.Timeout, cfg.Binary, step, workDir, env)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+		stdout, err := applyNormalizers([]byte(result.Stdout), DefaultCompareOptions(normalizeStepFormat(step.StdoutFormat)))
+		if err != nil {
+			return nil, fmt.Errorf("normalize stdout for step %q: %w", step.Name, err)
+		}
+		stderr, err := applyNormalizers([]byte(result.Stderr), DefaultCompareOptions(normalizeStepFormat(step.StderrFormat)))
+		if err != nil {
+			return nil, fmt.Errorf("normalize stderr for step %q: %w", step.Name, err)
+		}
+
+		prefix := path.Join(scenarioDir, slug(step.Name))
+		files := map[string][]byte{
+			prefix + ".stdout.txt":    stdout,
+			prefix + ".stderr.txt":    stderr,
+			prefix + ".exit-code.txt": []byte(strconv.Itoa(result.ExitCode) + "\n"),
+		}
+		for rel, body := range files {
+			if err := os.WriteFile(filepath.Join(outDir, filepath.FromSlash(rel)), body, 0o644); err != nil {
+				return nil, err
+			}
+			written = append(written, rel)
+		}
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+// AcceptGolden copies drifted files from actualDir into goldenDir and removes
+// goldens the recording no longer produces. Only paths matching one of
+// patterns are touched; a pattern matches a path glob ("cli/*.stdout.txt"), a
+// scenario directory ("cli"), a step ("cli/ps") or everything ("all").
+// It returns the updated golden paths.
+func AcceptGolden(goldenDir, actualDir string, patterns []string) ([]string, error) {
+	if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+		return nil, err
+	}
+	report, err := CompareTrees(goldenDir, actualDir)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]string, 0)
+	for _, rel := range append(append([]string(nil), report.Mismatched...), report.Unexpected...) {
+		if !matchesGoldenPattern(rel, patterns) {
+			continue
+		}
+		dst := filepath.Join(goldenDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return nil, err
+		}
+		if err := copyFile(filepath.Join(actualDir, filepath.FromSlash(rel)), dst); err != nil {
+			return nil, err
+		}
+		updated = append(updated, rel)
+	}
+	for _, rel := range report.MissingExpected {
+		if !matchesGoldenPattern(rel, patterns) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(goldenDir, filepath.FromSlash(rel))); err != nil {
+			return nil, err
+		}
+		updated = append(updated, rel)
+	}
+	sort.Strings(updated)
+	return updated, nil
+}
+
+func matchesGoldenPattern(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(strings.TrimSpace(filepath.ToSlash(pattern)), "/")
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "all":
+			return true
+		case strings.HasPrefix(rel, pattern+"/"), strings.HasPrefix(rel, pattern+"."):
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
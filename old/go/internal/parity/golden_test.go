@@ -0,0 +1,109 @@
+package parity
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndAcceptGolden(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	goBin := filepath.Join(tmp, "go-cli.sh")
+	writeScript(t, goBin, fakeGoScript(false))
+
+	scenario, err := LoadLifecycleScenario(filepath.Join("testdata", "lifecycle_harness", "scenario.json"))
+	if err != nil {
+		t.Fatalf("load scenario fixture: %v", err)
+	}
+	record := func(dir string) {
+		t.Helper()
+		if _, err := RecordGolden(context.Background(), GoldenConfig{Binary: goBin, Scenario: scenario, Timeout: 5 * time.Second}, dir); err != nil {
+			t.Fatalf("record golden: %v", err)
+		}
+	}
+
+	golden := filepath.Join(tmp, "golden")
+	actual := filepath.Join(tmp, "actual")
+	record(actual)
+	psOut, err := os.ReadFile(filepath.Join(actual, "loop-lifecycle-smoke", "ps.stdout.txt"))
+	if err != nil {
+		t.Fatalf("read recorded stdout: %v", err)
+	}
+	if strings.Contains(string(psOut), "2026-02-10T12:00:01Z") {
+		t.Fatalf("expected normalized timestamps, got %q", psOut)
+	}
+
+	updated, err := AcceptGolden(golden, actual, []string{"all"})
+	if err != nil {
+		t.Fatalf("accept all: %v", err)
+	}
+	if len(updated) != 9 {
+		t.Fatalf("expected 9 golden files, got %v", updated)
+	}
+
+	// Drift two steps, then accept only one of them.
+	mustWriteFile(t, filepath.Join(golden, "loop-lifecycle-smoke", "ps.stdout.txt"), "stale\n")
+	mustWriteFile(t, filepath.Join(golden, "loop-lifecycle-smoke", "touch.exit-code.txt"), "3\n")
+	updated, err = AcceptGolden(golden, actual, []string{"loop-lifecycle-smoke/ps"})
+	if err != nil {
+		t.Fatalf("accept step: %v", err)
+	}
+	if strings.Join(updated, ",") != "loop-lifecycle-smoke/ps.stdout.txt" {
+		t.Fatalf("expected only ps stdout accepted, got %v", updated)
+	}
+
+	out := filepath.Join(tmp, "artifacts")
+	report, err := WriteDiffArtifacts(golden, actual, out)
+	if err != nil {
+		t.Fatalf("write diff artifacts: %v", err)
+	}
+	if len(report.Mismatched) != 1 || report.Mismatched[0] != "loop-lifecycle-smoke/touch.exit-code.txt" {
+		t.Fatalf("unexpected remaining drift: %+v", report)
+	}
+
+	var drift struct {
+		Items []struct {
+			Path   string `json:"path"`
+			Golden string `json:"golden"`
+		} `json:"items"`
+	}
+	body, err := os.ReadFile(filepath.Join(out, "normalized", "drift-report.json"))
+	if err != nil {
+		t.Fatalf("read drift report: %v", err)
+	}
+	if err := json.Unmarshal(body, &drift); err != nil {
+		t.Fatalf("unmarshal drift report: %v", err)
+	}
+	want := filepath.ToSlash(filepath.Join(golden, "loop-lifecycle-smoke", "touch.exit-code.txt"))
+	if len(drift.Items) != 1 || drift.Items[0].Golden != want {
+		t.Fatalf("expected drift item linking %s, got %+v", want, drift.Items)
+	}
+}
+
+func TestMatchesGoldenPattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"all", "cli/ps.stdout.txt", true},
+		{"cli", "cli/ps.stdout.txt", true},
+		{"cli/ps", "cli/ps.stderr.txt", true},
+		{"cli/p", "cli/ps.stdout.txt", false},
+		{"cli/*.stdout.txt", "cli/ps.stdout.txt", true},
+		{"other", "cli/ps.stdout.txt", false},
+	}
+	for _, tc := range cases {
+		if got := matchesGoldenPattern(tc.rel, []string{tc.pattern}); got != tc.want {
+			t.Fatalf("matchesGoldenPattern(%q, %q) = %v, want %v", tc.rel, tc.pattern, got, tc.want)
+		}
+	}
+}
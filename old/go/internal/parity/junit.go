@@ -0,0 +1,202 @@
+package parity
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// JUnitSuites is the root of a JUnit XML report, the format CI systems
+// render natively in their test summaries.
+type JUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite groups the test cases of one parity run.
+type JUnitSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr,omitempty"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is one compared file or lifecycle step.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes the drift behind a failed test case.
+type JUnitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (s *JUnitSuite) add(tc JUnitTestCase) {
+	s.Cases = append(s.Cases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+}
+
+// TreeDriftJUnit builds a suite with one test case per file compared by
+// CompareTrees. Mismatched files carry their unified diff.
+func TreeDriftJUnit(expectedDir, actualDir string, report Report) (JUnitSuite, error) {
+	suite := JUnitSuite{Name: "parity.tree"}
+
+	expectedFiles, err := listFiles(expectedDir)
+	if err != nil {
+		return suite, err
+	}
+	actualFiles, err := listFiles(actualDir)
+	if err != nil {
+		return suite, err
+	}
+
+	drift := make(map[string]string, len(report.MissingExpected)+len(report.Mismatched)+len(report.Unexpected))
+	for _, rel := range report.MissingExpected {
+		drift[rel] = "missing_expected"
+	}
+	for _, rel := range report.Mismatched {
+		drift[rel] = "mismatched"
+	}
+	for _, rel := range report.Unexpected {
+		drift[rel] = "unexpected"
+	}
+
+	paths := make([]string, 0, len(expectedFiles)+len(report.Unexpected))
+	for rel := range expectedFiles {
+		paths = append(paths, rel)
+	}
+	paths = append(paths, report.Unexpected...)
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		tc := JUnitTestCase{Name: rel, Classname: "parity." + ownerForDriftPath(rel)}
+		switch drift[rel] {
+		case "missing_expected":
+			tc.Failure = &JUnitFailure{Type: "missing_expected", Message: "missing actual file: " + rel}
+		case "unexpected":
+			tc.Failure = &JUnitFailure{Type: "unexpected", Message: "unexpected actual file: " + rel}
+		case "mismatched":
+			text, err := mismatchDiff(rel, expectedFiles[rel], actualFiles[rel])
+			if err != nil {
+				return suite, err
+			}
+			tc.Failure = &JUnitFailure{Type: "mismatched", Message: "content drift: " + rel, Text: text}
+		}
+		suite.add(tc)
+	}
+	return suite, nil
+}
+
+// LifecycleJUnit builds a suite with one test case per lifecycle step.
+// Drift and, in benchmark mode, perf regressions fail the step. Drift
+// classified as flaky in repeat mode has failure type "flaky_drift".
+func LifecycleJUnit(report LifecycleHarnessReport) JUnitSuite {
+	name := "parity.lifecycle"
+	if report.Scenario != "" {
+		name += "." + report.Scenario
+	}
+	suite := JUnitSuite{Name: name}
+	var total float64
+	for _, step := range report.Steps {
+		seconds := step.Go.wall.Seconds()
+		total += seconds
+		tc := JUnitTestCase{
+			Name:      lifecycleCaseName(step),
+			Classname: name,
+			Time:      fmt.Sprintf("%.3f", seconds),
+		}
+		switch {
+		case step.HasDrift:
+			tc.Failure = lifecycleDriftFailure(step)
+			if step.Flake.Flaky() {
+				tc.Failure.Type = "flaky_drift"
+				tc.Failure.Message = fmt.Sprintf("flaky (%d/%d repeats drifted): %s", step.Flake.DriftRuns, step.Flake.Repeats, tc.Failure.Message)
+			}
+		case step.Perf != nil && step.Perf.Regression:
+			tc.Failure = &JUnitFailure{
+				Type:    "perf_regression",
+				Message: strings.Join(step.Perf.Reasons, "; "),
+			}
+		}
+		suite.add(tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+	return suite
+}
+
+// lifecycleCaseName keeps the cases of a step distinct across variants.
+func lifecycleCaseName(step LifecycleStepReport) string {
+	if step.Variant == "" {
+		return step.Name
+	}
+	return step.Name + " [" + step.Variant + "]"
+}
+
+func lifecycleDriftFailure(step LifecycleStepReport) *JUnitFailure {
+	var reasons []string
+	var text strings.Builder
+	fmt.Fprintf(&text, "args: %s\n", strings.Join(step.Args, " "))
+	if !step.ExitCodeMatch {
+		reasons = append(reasons, fmt.Sprintf("exit code %d (go) vs %d (rust)", step.Go.ExitCode, step.Rust.ExitCode))
+	}
+	if !step.PromptsMatch {
+		reasons = append(reasons, "prompts differ")
+	}
+	for _, stream := range []struct {
+		name string
+		cmp  StreamComparison
+	}{{"stdout", step.Stdout}, {"stderr", step.Stderr}} {
+		if stream.cmp.Equal {
+			continue
+		}
+		reasons = append(reasons, stream.name+" differs")
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(stream.cmp.GoNormalized),
+			B:        difflib.SplitLines(stream.cmp.RustNormalized),
+			FromFile: "go/" + stream.name,
+			ToFile:   "rust/" + stream.name,
+			Context:  3,
+		})
+		text.WriteString(diff)
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "drift")
+	}
+	return &JUnitFailure{Type: "drift", Message: strings.Join(reasons, "; "), Text: text.String()}
+}
+
+// WriteJUnitReport writes suites as a JUnit XML document to path.
+func WriteJUnitReport(path, name string, suites ...JUnitSuite) error {
+	doc := JUnitSuites{Name: name, Suites: suites}
+	for _, suite := range suites {
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	body := append([]byte(xml.Header), out...)
+	body = append(body, '\n')
+	return os.WriteFile(path, body, 0o644)
+}
@@ -0,0 +1,115 @@
+package parity
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTreeDriftJUnitOneCasePerFile(t *testing.T) {
+	t.Parallel()
+
+	expected := t.TempDir()
+	actual := t.TempDir()
+	mustWriteFile(t, filepath.Join(expected, "forge/root/help.txt"), "same\n")
+	mustWriteFile(t, filepath.Join(expected, "fmail/send.txt"), "expected\n")
+	mustWriteFile(t, filepath.Join(expected, "schema/missing.txt"), "gone\n")
+	mustWriteFile(t, filepath.Join(actual, "forge/root/help.txt"), "same\n")
+	mustWriteFile(t, filepath.Join(actual, "fmail/send.txt"), "actual\n")
+	mustWriteFile(t, filepath.Join(actual, "extra.txt"), "extra\n")
+
+	report, err := CompareTrees(expected, actual)
+	if err != nil {
+		t.Fatalf("compare trees: %v", err)
+	}
+	suite, err := TreeDriftJUnit(expected, actual, report)
+	if err != nil {
+		t.Fatalf("tree drift junit: %v", err)
+	}
+	if suite.Tests != 4 || suite.Failures != 3 {
+		t.Fatalf("expected 4 cases with 3 failures, got %d/%d", suite.Tests, suite.Failures)
+	}
+
+	byName := make(map[string]JUnitTestCase, len(suite.Cases))
+	for _, tc := range suite.Cases {
+		byName[tc.Name] = tc
+	}
+	if tc := byName["forge/root/help.txt"]; tc.Failure != nil || tc.Classname != "parity.forge-cli" {
+		t.Fatalf("unexpected passing case %+v", tc)
+	}
+	if tc := byName["fmail/send.txt"]; tc.Failure == nil || tc.Failure.Type != "mismatched" || !strings.Contains(tc.Failure.Text, "+actual") {
+		t.Fatalf("expected mismatch with diff, got %+v", tc)
+	}
+	if tc := byName["schema/missing.txt"]; tc.Failure == nil || tc.Failure.Type != "missing_expected" {
+		t.Fatalf("expected missing failure, got %+v", tc)
+	}
+	if tc := byName["extra.txt"]; tc.Failure == nil || tc.Failure.Type != "unexpected" {
+		t.Fatalf("expected unexpected failure, got %+v", tc)
+	}
+
+	path := filepath.Join(t.TempDir(), "out", "junit.xml")
+	if err := WriteJUnitReport(path, "parity", suite); err != nil {
+		t.Fatalf("write junit: %v", err)
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read junit: %v", err)
+	}
+	var doc JUnitSuites
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("unmarshal junit: %v", err)
+	}
+	if doc.Tests != 4 || doc.Failures != 3 || len(doc.Suites) != 1 || len(doc.Suites[0].Cases) != 4 {
+		t.Fatalf("unexpected junit document %+v", doc)
+	}
+}
+
+func TestLifecycleJUnitOneCasePerStep(t *testing.T) {
+	t.Parallel()
+
+	report := LifecycleHarnessReport{
+		Scenario: "smoke",
+		Steps: []LifecycleStepReport{
+			{Name: "up", ExitCodeMatch: true, PromptsMatch: true, Stdout: StreamComparison{Equal: true}, Stderr: StreamComparison{Equal: true}},
+			{
+				Name:          "ps",
+				Args:          []string{"ps", "--json"},
+				Go:            LifecycleCommandResult{ExitCode: 0},
+				Rust:          LifecycleCommandResult{ExitCode: 1},
+				PromptsMatch:  true,
+				Stdout:        StreamComparison{GoNormalized: "a\n", RustNormalized: "b\n"},
+				Stderr:        StreamComparison{Equal: true},
+				HasDrift:      true,
+				ExitCodeMatch: false,
+			},
+			{
+				Name:          "stop",
+				ExitCodeMatch: true,
+				PromptsMatch:  true,
+				Stdout:        StreamComparison{Equal: true},
+				Stderr:        StreamComparison{Equal: true},
+				Perf:          &LifecycleStepPerf{Regression: true, Reasons: []string{"wall 30.0ms vs 10.0ms (x3.00)"}},
+			},
+		},
+	}
+
+	suite := LifecycleJUnit(report)
+	if suite.Name != "parity.lifecycle.smoke" || suite.Tests != 3 || suite.Failures != 2 {
+		t.Fatalf("unexpected suite %+v", suite)
+	}
+	if suite.Cases[0].Failure != nil {
+		t.Fatalf("expected clean step to pass, got %+v", suite.Cases[0].Failure)
+	}
+	drift := suite.Cases[1].Failure
+	if drift == nil || drift.Type != "drift" || drift.Message != "exit code 0 (go) vs 1 (rust); stdout differs" {
+		t.Fatalf("unexpected drift failure %+v", drift)
+	}
+	if !strings.Contains(drift.Text, "args: ps --json") || !strings.Contains(drift.Text, "+b") {
+		t.Fatalf("expected args and stdout diff in failure text, got %q", drift.Text)
+	}
+	if perf := suite.Cases[2].Failure; perf == nil || perf.Type != "perf_regression" {
+		t.Fatalf("expected perf regression failure, got %+v", perf)
+	}
+}
@@ -0,0 +1,162 @@
+package parity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultPerfRegressionThreshold is the relative slowdown (0.25 = 25%)
+// tolerated before a benchmarked step is flagged as a regression.
+const DefaultPerfRegressionThreshold = 0.25
+
+// perfNoiseFloor keeps sub-millisecond jitter on fast commands from being
+// reported as a wall-clock regression.
+const perfNoiseFloor = 5 * time.Millisecond
+
+// LifecyclePerfStats summarizes repeated runs of one step for one binary.
+type LifecyclePerfStats struct {
+	Runs         int     `json:"runs"`
+	MedianMillis float64 `json:"median_ms"`
+	MinMillis    float64 `json:"min_ms"`
+	MaxMillis    float64 `json:"max_ms"`
+	// PeakRSSKB is the highest max RSS seen across runs; 0 when the
+	// platform does not report it.
+	PeakRSSKB int64 `json:"peak_rss_kb"`
+}
+
+// LifecycleStepPerf compares Go and Rust timings for one step. Ratios are
+// Rust over Go, so values above 1 mean the Rust binary is slower or larger.
+type LifecycleStepPerf struct {
+	Go         LifecyclePerfStats `json:"go"`
+	Rust       LifecyclePerfStats `json:"rust"`
+	WallRatio  float64            `json:"wall_ratio"`
+	RSSRatio   float64            `json:"rss_ratio,omitempty"`
+	Regression bool               `json:"regression"`
+	Reasons    []string           `json:"reasons,omitempty"`
+}
+
+// HasPerfRegression reports whether any benchmarked step regressed.
+func (r LifecycleHarnessReport) HasPerfRegression() bool {
+	return r.PerfRegressionCount() > 0
+}
+
+// PerfRegressionCount returns number of steps flagged as perf regressions.
+func (r LifecycleHarnessReport) PerfRegressionCount() int {
+	count := 0
+	for _, step := range r.Steps {
+		if step.Perf != nil && step.Perf.Regression {
+			count++
+		}
+	}
+	return count
+}
+
+// benchLifecycleScenario replays the whole scenario cfg.BenchRuns times,
+// each pass on fresh fixture copies so state-mutating steps see the same
+// starting point, and attaches per-step perf stats to report.
+func benchLifecycleScenario(ctx context.Context, cfg LifecycleHarnessConfig, tempRoot string, env []string, report *LifecycleHarnessReport) error {
+	steps := cfg.Scenario.Steps
+	goSamples := make([][]LifecycleCommandResult, len(steps))
+	rustSamples := make([][]LifecycleCommandResult, len(steps))
+
+	for run := 0; run < cfg.BenchRuns; run++ {
+		goDir := filepath.Join(tempRoot, fmt.Sprintf("bench-%d-go", run))
+		rustDir := filepath.Join(tempRoot, fmt.Sprintf("bench-%d-rust", run))
+		if err := prepareFixtureDir(cfg.FixtureDir, goDir); err != nil {
+			return fmt.Errorf("bench run %d: copy fixture for go: %w", run+1, err)
+		}
+		if err := prepareFixtureDir(cfg.FixtureDir, rustDir); err != nil {
+			return fmt.Errorf("bench run %d: copy fixture for rust: %w", run+1, err)
+		}
+
+		for i, step := range steps {
+			goResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.GoBinary, step, goDir, env)
+			if err != nil {
+				return fmt.Errorf("bench run %d: go step %q: %w", run+1, step.Name, err)
+			}
+			rustResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.RustBinary, step, rustDir, env)
+			if err != nil {
+				return fmt.Errorf("bench run %d: rust step %q: %w", run+1, step.Name, err)
+			}
+			goSamples[i] = append(goSamples[i], goResult)
+			rustSamples[i] = append(rustSamples[i], rustResult)
+		}
+
+		if err := os.RemoveAll(goDir); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(rustDir); err != nil {
+			return err
+		}
+	}
+
+	threshold := cfg.PerfThreshold
+	if threshold <= 0 {
+		threshold = DefaultPerfRegressionThreshold
+	}
+	report.BenchRuns = cfg.BenchRuns
+	report.PerfThreshold = threshold
+	for i := range report.Steps {
+		perf := compareStepPerf(summarizePerf(goSamples[i]), summarizePerf(rustSamples[i]), threshold)
+		report.Steps[i].Perf = &perf
+	}
+	return nil
+}
+
+func summarizePerf(samples []LifecycleCommandResult) LifecyclePerfStats {
+	stats := LifecyclePerfStats{Runs: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+	walls := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		walls = append(walls, sample.wall)
+		if sample.peakRSSKB > stats.PeakRSSKB {
+			stats.PeakRSSKB = sample.peakRSSKB
+		}
+	}
+	sort.Slice(walls, func(i, j int) bool { return walls[i] < walls[j] })
+
+	median := walls[len(walls)/2]
+	if len(walls)%2 == 0 {
+		median = (walls[len(walls)/2-1] + walls[len(walls)/2]) / 2
+	}
+	stats.MedianMillis = durationMillis(median)
+	stats.MinMillis = durationMillis(walls[0])
+	stats.MaxMillis = durationMillis(walls[len(walls)-1])
+	return stats
+}
+
+func compareStepPerf(goStats, rustStats LifecyclePerfStats, threshold float64) LifecycleStepPerf {
+	perf := LifecycleStepPerf{Go: goStats, Rust: rustStats}
+	if goStats.MedianMillis > 0 {
+		perf.WallRatio = roundRatio(rustStats.MedianMillis / goStats.MedianMillis)
+	}
+	if goStats.PeakRSSKB > 0 && rustStats.PeakRSSKB > 0 {
+		perf.RSSRatio = roundRatio(float64(rustStats.PeakRSSKB) / float64(goStats.PeakRSSKB))
+	}
+
+	limit := 1 + threshold
+	slower := rustStats.MedianMillis - goStats.MedianMillis
+	if perf.WallRatio > limit && slower >= durationMillis(perfNoiseFloor) {
+		perf.Reasons = append(perf.Reasons, fmt.Sprintf("wall %.1fms vs %.1fms (x%.2f)", rustStats.MedianMillis, goStats.MedianMillis, perf.WallRatio))
+	}
+	if perf.RSSRatio > limit {
+		perf.Reasons = append(perf.Reasons, fmt.Sprintf("rss %dKB vs %dKB (x%.2f)", rustStats.PeakRSSKB, goStats.PeakRSSKB, perf.RSSRatio))
+	}
+	perf.Regression = len(perf.Reasons) > 0
+	return perf
+}
+
+func durationMillis(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+func roundRatio(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,153 @@
+package parity
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Drift classifications assigned to drifting steps in repeat mode.
+const (
+	// DriftDeterministic marks a step whose drift reproduced identically on
+	// every repeat.
+	DriftDeterministic = "deterministic"
+	// DriftFlaky marks a step whose outcome differed between repeats: it
+	// stopped drifting, or drifted with different output.
+	DriftFlaky = "flaky"
+)
+
+// LifecycleStepFlake records how a drifting step behaved when the scenario
+// was repeated.
+type LifecycleStepFlake struct {
+	// Repeats is the number of reruns, excluding the original run.
+	Repeats int `json:"repeats"`
+	// DriftRuns counts the reruns in which the step drifted.
+	DriftRuns int `json:"drift_runs"`
+	// Variants counts the distinct outcomes seen across all runs.
+	Variants       int    `json:"variants"`
+	Classification string `json:"classification"`
+}
+
+// Flaky reports whether the step was classified as flaky.
+func (f *LifecycleStepFlake) Flaky() bool {
+	return f != nil && f.Classification == DriftFlaky
+}
+
+// FlakyCount returns number of drifting steps classified as flaky.
+func (r LifecycleHarnessReport) FlakyCount() int {
+	count := 0
+	for _, step := range r.Steps {
+		if step.HasDrift && step.Flake.Flaky() {
+			count++
+		}
+	}
+	return count
+}
+
+// HasDeterministicDrift reports whether any step drifted without being
+// classified as flaky. Without repeats every drift counts.
+func (r LifecycleHarnessReport) HasDeterministicDrift() bool {
+	return r.DriftCount() > r.FlakyCount()
+}
+
+// repeatDriftingSteps replays the scenario cfg.Repeat times on fresh fixture
+// copies, up to the last drifting step, and classifies every drifting step
+// by comparing its outcome across runs.
+func repeatDriftingSteps(ctx context.Context, cfg LifecycleHarnessConfig, tempRoot string, env []string, report *LifecycleHarnessReport) error {
+	report.Repeat = cfg.Repeat
+
+	last := -1
+	outcomes := make(map[int][]string)
+	for i, step := range report.Steps {
+		if step.HasDrift {
+			last = i
+			outcomes[i] = []string{stepOutcome(step)}
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+
+	driftRuns := make(map[int]int, len(outcomes))
+	for run := 0; run < cfg.Repeat; run++ {
+		goDir := filepath.Join(tempRoot, fmt.Sprintf("repeat-%d-go", run))
+		rustDir := filepath.Join(tempRoot, fmt.Sprintf("repeat-%d-rust", run))
+		if err := prepareFixtureDir(cfg.FixtureDir, goDir); err != nil {
+			return fmt.Errorf("repeat %d: copy fixture for go: %w", run+1, err)
+		}
+		if err := prepareFixtureDir(cfg.FixtureDir, rustDir); err != nil {
+			return fmt.Errorf("repeat %d: copy fixture for rust: %w", run+1, err)
+		}
+
+		for i, step := range cfg.Scenario.Steps[:last+1] {
+			goResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.GoBinary, step, goDir, env)
+			if err != nil {
+				return fmt.Errorf("repeat %d: go step %q: %w", run+1, step.Name, err)
+			}
+			rustResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.RustBinary, step, rustDir, env)
+			if err != nil {
+				return fmt.Errorf("repeat %d: rust step %q: %w", run+1, step.Name, err)
+			}
+			if _, drifting := outcomes[i]; !drifting {
+				continue
+			}
+			stepReport, err := compareLifecycleStep(step, goResult, rustResult)
+			if err != nil {
+				return fmt.Errorf("repeat %d: %w", run+1, err)
+			}
+			if stepReport.HasDrift {
+				driftRuns[i]++
+			}
+			outcomes[i] = append(outcomes[i], stepOutcome(stepReport))
+		}
+
+		if err := os.RemoveAll(goDir); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(rustDir); err != nil {
+			return err
+		}
+	}
+
+	for i, runs := range outcomes {
+		flake := classifyDrift(runs, driftRuns[i])
+		report.Steps[i].Flake = &flake
+	}
+	return nil
+}
+
+// classifyDrift classifies a step from its outcomes, the original run first.
+func classifyDrift(outcomes []string, driftRuns int) LifecycleStepFlake {
+	distinct := make(map[string]struct{}, len(outcomes))
+	for _, outcome := range outcomes {
+		distinct[outcome] = struct{}{}
+	}
+	flake := LifecycleStepFlake{
+		Repeats:        len(outcomes) - 1,
+		DriftRuns:      driftRuns,
+		Variants:       len(distinct),
+		Classification: DriftDeterministic,
+	}
+	if flake.Variants > 1 {
+		flake.Classification = DriftFlaky
+	}
+	return flake
+}
+
+// stepOutcome fingerprints the compared result of a step so repeats can be
+// checked for identical drift.
+func stepOutcome(step LifecycleStepReport) string {
+	return strings.Join([]string{
+		strconv.FormatBool(step.HasDrift),
+		strconv.Itoa(step.Go.ExitCode),
+		strconv.Itoa(step.Rust.ExitCode),
+		strconv.FormatBool(step.PromptsMatch),
+		step.Stdout.GoNormalized,
+		step.Stdout.RustNormalized,
+		step.Stderr.GoNormalized,
+		step.Stderr.RustNormalized,
+	}, "\x00")
+}
@@ -19,14 +19,19 @@ type LifecycleScenario struct {
 	Name  string            `json:"name,omitempty"`
 	Env   map[string]string `json:"env,omitempty"`
 	Steps []LifecycleStep   `json:"steps"`
+	// Variants, when set, replays the scenario once per environment
+	// variant on fresh fixture copies and reports drift per variant.
+	Variants []LifecycleEnvVariant `json:"variants,omitempty"`
 }
 
-// LifecycleStep describes one command invocation.
+// LifecycleStep describes one command invocation. Steps with Interactions
+// run with scripted stdin and without FORGE_NON_INTERACTIVE.
 type LifecycleStep struct {
-	Name         string   `json:"name"`
-	Args         []string `json:"args"`
-	StdoutFormat Format   `json:"stdout_format,omitempty"`
-	StderrFormat Format   `json:"stderr_format,omitempty"`
+	Name         string                 `json:"name"`
+	Args         []string               `json:"args"`
+	StdoutFormat Format                 `json:"stdout_format,omitempty"`
+	StderrFormat Format                 `json:"stderr_format,omitempty"`
+	Interactions []LifecycleInteraction `json:"interactions,omitempty"`
 }
 
 // LifecycleHarnessConfig configures side-by-side CLI execution.
@@ -37,13 +42,26 @@ type LifecycleHarnessConfig struct {
 	Scenario   LifecycleScenario
 	ExtraEnv   map[string]string
 	Timeout    time.Duration
+	// BenchRuns, when positive, replays the scenario that many more times
+	// against both binaries to record wall-clock time and peak RSS.
+	BenchRuns int
+	// PerfThreshold is the relative slowdown tolerated before a step is
+	// flagged; 0 uses DefaultPerfRegressionThreshold.
+	PerfThreshold float64
+	// Repeat, when positive, reruns the scenario that many more times to
+	// classify each drifting step as deterministic or flaky.
+	Repeat int
 }
 
 // LifecycleCommandResult captures one command execution.
 type LifecycleCommandResult struct {
-	ExitCode int    `json:"exit_code"`
-	Stdout   string `json:"stdout"`
-	Stderr   string `json:"stderr"`
+	ExitCode     int                          `json:"exit_code"`
+	Stdout       string                       `json:"stdout"`
+	Stderr       string                       `json:"stderr"`
+	Interactions []LifecycleInteractionResult `json:"interactions,omitempty"`
+
+	wall      time.Duration
+	peakRSSKB int64
 }
 
 // StreamComparison captures normalized comparison output for one stream.
@@ -55,14 +73,23 @@ type StreamComparison struct {
 
 // LifecycleStepReport captures parity for one step.
 type LifecycleStepReport struct {
-	Name          string                 `json:"name"`
+	Name string `json:"name"`
+	// Variant names the environment variant the step ran under, if any.
+	Variant       string                 `json:"variant,omitempty"`
 	Args          []string               `json:"args"`
 	Go            LifecycleCommandResult `json:"go"`
 	Rust          LifecycleCommandResult `json:"rust"`
 	ExitCodeMatch bool                   `json:"exit_code_match"`
 	Stdout        StreamComparison       `json:"stdout"`
 	Stderr        StreamComparison       `json:"stderr"`
-	HasDrift      bool                   `json:"has_drift"`
+	// PromptsMatch is false when either binary did not print a scripted
+	// prompt of an interactive step in order.
+	PromptsMatch bool `json:"prompts_match"`
+	HasDrift     bool `json:"has_drift"`
+	// Perf is set when the harness ran in benchmark mode.
+	Perf *LifecycleStepPerf `json:"perf,omitempty"`
+	// Flake is set on drifting steps when the harness ran with repeats.
+	Flake *LifecycleStepFlake `json:"flake,omitempty"`
 }
 
 // LifecycleHarnessReport is the full run output.
@@ -73,6 +100,14 @@ type LifecycleHarnessReport struct {
 	FixtureDir  string                `json:"fixture_dir,omitempty"`
 	GeneratedAt string                `json:"generated_at"`
 	Steps       []LifecycleStepReport `json:"steps"`
+	// BenchRuns and PerfThreshold echo the benchmark settings, if any.
+	BenchRuns     int     `json:"bench_runs,omitempty"`
+	PerfThreshold float64 `json:"perf_threshold,omitempty"`
+	// Repeat echoes the number of flake-detection repeats, if any.
+	Repeat int `json:"repeat,omitempty"`
+	// Variants summarizes drift per environment variant when the scenario
+	// declares variants; Steps then holds every step once per variant.
+	Variants []LifecycleVariantReport `json:"variants,omitempty"`
 }
 
 // HasDrift reports whether any step contains parity drift.
@@ -137,66 +172,125 @@ func RunLoopLifecycleHarness(ctx context.Context, cfg LifecycleHarnessConfig) (L
 	}
 	defer os.RemoveAll(tempRoot)
 
-	goDir := filepath.Join(tempRoot, "go-fixture")
-	rustDir := filepath.Join(tempRoot, "rust-fixture")
-	if err := os.MkdirAll(goDir, 0o755); err != nil {
-		return LifecycleHarnessReport{}, err
-	}
-	if err := os.MkdirAll(rustDir, 0o755); err != nil {
-		return LifecycleHarnessReport{}, err
-	}
-	if cfg.FixtureDir != "" {
-		if err := copyTree(cfg.FixtureDir, goDir); err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("copy fixture for go: %w", err)
-		}
-		if err := copyTree(cfg.FixtureDir, rustDir); err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("copy fixture for rust: %w", err)
-		}
-	}
-
-	env := buildHarnessEnv(cfg.Scenario.Env, cfg.ExtraEnv)
 	report := LifecycleHarnessReport{
 		Scenario:    cfg.Scenario.Name,
 		GoBinary:    cfg.GoBinary,
 		RustBinary:  cfg.RustBinary,
 		FixtureDir:  cfg.FixtureDir,
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Steps:       make([]LifecycleStepReport, 0, len(cfg.Scenario.Steps)),
 	}
 
+	env := buildHarnessEnv(cfg.Scenario.Env, cfg.ExtraEnv)
+	if len(cfg.Scenario.Variants) == 0 {
+		if err := runLifecycleScenario(ctx, cfg, tempRoot, env, &report); err != nil {
+			return LifecycleHarnessReport{}, err
+		}
+		return report, nil
+	}
+
+	for i, variant := range cfg.Scenario.Variants {
+		variantRoot := filepath.Join(tempRoot, fmt.Sprintf("variant-%d", i))
+		var variantReport LifecycleHarnessReport
+		if err := runLifecycleScenario(ctx, cfg, variantRoot, applyVariantEnv(env, variant), &variantReport); err != nil {
+			return LifecycleHarnessReport{}, fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+		for j := range variantReport.Steps {
+			variantReport.Steps[j].Variant = variant.Name
+		}
+		report.Steps = append(report.Steps, variantReport.Steps...)
+		report.Variants = append(report.Variants, LifecycleVariantReport{
+			Name:  variant.Name,
+			Env:   variant.overrides(),
+			Steps: len(variantReport.Steps),
+			Drift: variantReport.DriftCount(),
+		})
+		report.BenchRuns = variantReport.BenchRuns
+		report.PerfThreshold = variantReport.PerfThreshold
+		report.Repeat = variantReport.Repeat
+	}
+	return report, nil
+}
+
+// runLifecycleScenario runs every step on fresh fixture copies under root
+// with env, then the flake repeats and benchmark passes if configured, and
+// records the results in report.
+func runLifecycleScenario(ctx context.Context, cfg LifecycleHarnessConfig, root string, env []string, report *LifecycleHarnessReport) error {
+	goDir := filepath.Join(root, "go-fixture")
+	rustDir := filepath.Join(root, "rust-fixture")
+	if err := prepareFixtureDir(cfg.FixtureDir, goDir); err != nil {
+		return fmt.Errorf("copy fixture for go: %w", err)
+	}
+	if err := prepareFixtureDir(cfg.FixtureDir, rustDir); err != nil {
+		return fmt.Errorf("copy fixture for rust: %w", err)
+	}
+
+	report.Steps = make([]LifecycleStepReport, 0, len(cfg.Scenario.Steps))
 	for _, step := range cfg.Scenario.Steps {
-		goResult, err := runHarnessCommand(ctx, cfg.Timeout, cfg.GoBinary, step.Args, goDir, env)
+		goResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.GoBinary, step, goDir, env)
 		if err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("go step %q: %w", step.Name, err)
+			return fmt.Errorf("go step %q: %w", step.Name, err)
 		}
-		rustResult, err := runHarnessCommand(ctx, cfg.Timeout, cfg.RustBinary, step.Args, rustDir, env)
+		rustResult, err := runHarnessStep(ctx, cfg.Timeout, cfg.RustBinary, step, rustDir, env)
 		if err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("rust step %q: %w", step.Name, err)
+			return fmt.Errorf("rust step %q: %w", step.Name, err)
 		}
 
-		stdoutCmp, err := compareStreams(goResult.Stdout, rustResult.Stdout, normalizeStepFormat(step.StdoutFormat))
+		stepReport, err := compareLifecycleStep(step, goResult, rustResult)
 		if err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("compare stdout for step %q: %w", step.Name, err)
+			return err
 		}
-		stderrCmp, err := compareStreams(goResult.Stderr, rustResult.Stderr, normalizeStepFormat(step.StderrFormat))
-		if err != nil {
-			return LifecycleHarnessReport{}, fmt.Errorf("compare stderr for step %q: %w", step.Name, err)
+		report.Steps = append(report.Steps, stepReport)
+	}
+
+	if cfg.Repeat > 0 {
+		if err := repeatDriftingSteps(ctx, cfg, root, env, report); err != nil {
+			return err
 		}
+	}
 
-		stepReport := LifecycleStepReport{
-			Name:          step.Name,
-			Args:          append([]string(nil), step.Args...),
-			Go:            goResult,
-			Rust:          rustResult,
-			ExitCodeMatch: goResult.ExitCode == rustResult.ExitCode,
-			Stdout:        stdoutCmp,
-			Stderr:        stderrCmp,
+	if cfg.BenchRuns > 0 {
+		if err := benchLifecycleScenario(ctx, cfg, root, env, report); err != nil {
+			return err
 		}
-		stepReport.HasDrift = !stepReport.ExitCodeMatch || !stepReport.Stdout.Equal || !stepReport.Stderr.Equal
-		report.Steps = append(report.Steps, stepReport)
+	}
+	return nil
+}
+
+// compareLifecycleStep compares the Go and Rust results of one step.
+func compareLifecycleStep(step LifecycleStep, goResult, rustResult LifecycleCommandResult) (LifecycleStepReport, error) {
+	stdoutCmp, err := compareStreams(goResult.Stdout, rustResult.Stdout, normalizeStepFormat(step.StdoutFormat))
+	if err != nil {
+		return LifecycleStepReport{}, fmt.Errorf("compare stdout for step %q: %w", step.Name, err)
+	}
+	stderrCmp, err := compareStreams(goResult.Stderr, rustResult.Stderr, normalizeStepFormat(step.StderrFormat))
+	if err != nil {
+		return LifecycleStepReport{}, fmt.Errorf("compare stderr for step %q: %w", step.Name, err)
 	}
 
-	return report, nil
+	stepReport := LifecycleStepReport{
+		Name:          step.Name,
+		Args:          append([]string(nil), step.Args...),
+		Go:            goResult,
+		Rust:          rustResult,
+		ExitCodeMatch: goResult.ExitCode == rustResult.ExitCode,
+		Stdout:        stdoutCmp,
+		Stderr:        stderrCmp,
+		PromptsMatch:  interactionsMatched(goResult.Interactions) && interactionsMatched(rustResult.Interactions),
+	}
+	stepReport.HasDrift = !stepReport.ExitCodeMatch || !stepReport.Stdout.Equal || !stepReport.Stderr.Equal || !stepReport.PromptsMatch
+	return stepReport, nil
+}
+
+// prepareFixtureDir creates dir and, when fixtureDir is set, copies the
+// fixture into it.
+func prepareFixtureDir(fixtureDir, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if fixtureDir == "" {
+		return nil
+	}
+	return copyTree(fixtureDir, dir)
 }
 
 func validateHarnessConfig(cfg LifecycleHarnessConfig) error {
@@ -206,6 +300,15 @@ func validateHarnessConfig(cfg LifecycleHarnessConfig) error {
 	if strings.TrimSpace(cfg.RustBinary) == "" {
 		return errors.New("rust binary path is required")
 	}
+	if cfg.BenchRuns < 0 {
+		return errors.New("bench runs must not be negative")
+	}
+	if cfg.PerfThreshold < 0 {
+		return errors.New("perf threshold must not be negative")
+	}
+	if cfg.Repeat < 0 {
+		return errors.New("repeat must not be negative")
+	}
 	if err := validateLifecycleScenario(cfg.Scenario); err != nil {
 		return err
 	}
@@ -216,6 +319,9 @@ func validateLifecycleScenario(scenario LifecycleScenario) error {
 	if len(scenario.Steps) == 0 {
 		return errors.New("scenario must include at least one step")
 	}
+	if err := validateLifecycleVariants(scenario.Variants); err != nil {
+		return err
+	}
 	for i, step := range scenario.Steps {
 		if strings.TrimSpace(step.Name) == "" {
 			return fmt.Errorf("step %d: name is required", i)
@@ -229,6 +335,11 @@ func validateLifecycleScenario(scenario LifecycleScenario) error {
 		if !isValidFormat(normalizeStepFormat(step.StderrFormat)) {
 			return fmt.Errorf("step %d (%s): invalid stderr_format %q", i, step.Name, step.StderrFormat)
 		}
+		for j, interaction := range step.Interactions {
+			if strings.ContainsAny(interaction.Send, "\r\n") {
+				return fmt.Errorf("step %d (%s): interaction %d: send must be a single line", i, step.Name, j)
+			}
+		}
 	}
 	return nil
 }
@@ -298,7 +409,10 @@ func runHarnessCommand(ctx context.Context, timeout time.Duration, binary string
 	cmd.Stderr = &stderr
 
 	result := LifecycleCommandResult{}
+	started := time.Now()
 	err := cmd.Run()
+	result.wall = time.Since(started)
+	result.peakRSSKB = peakRSSKB(cmd.ProcessState)
 	result.Stdout = stdout.String()
 	result.Stderr = stderr.String()
 
@@ -117,6 +117,312 @@ func TestLoadLifecycleScenarioValidation(t *testing.T) {
 	}
 }
 
+func TestRunLoopLifecycleHarnessInteractiveSteps(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	goBin := filepath.Join(tmp, "go-cli.sh")
+	rustBin := filepath.Join(tmp, "rust-cli.sh")
+	driftBin := filepath.Join(tmp, "drift-cli.sh")
+	writeScript(t, goBin, fakeInteractiveScript("Remove loop review? [y/N] "))
+	writeScript(t, rustBin, fakeInteractiveScript("Remove loop review? [y/N] "))
+	writeScript(t, driftBin, fakeInteractiveScript("Delete review? "))
+
+	scenario := LifecycleScenario{
+		Name: "loop-rm-confirm",
+		Steps: []LifecycleStep{
+			{
+				Name: "rm-confirm",
+				Args: []string{"rm", "review"},
+				Interactions: []LifecycleInteraction{
+					{Expect: "Remove loop review? [y/N]", Send: "y"},
+					{Expect: "Reason:", Send: "done"},
+				},
+			},
+		},
+	}
+
+	report, err := RunLoopLifecycleHarness(context.Background(), LifecycleHarnessConfig{
+		GoBinary:   goBin,
+		RustBinary: rustBin,
+		Scenario:   scenario,
+		Timeout:    5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("run harness: %v", err)
+	}
+	if report.HasDrift() {
+		t.Fatalf("expected no drift, got %+v", report.Steps)
+	}
+	step := report.Steps[0]
+	if !step.PromptsMatch || len(step.Go.Interactions) != 2 {
+		t.Fatalf("expected both prompts matched, got %+v", step.Go.Interactions)
+	}
+	if !strings.Contains(step.Go.Stdout, "removed review (y, done) interactive=1") {
+		t.Fatalf("expected scripted answers in output, got %q", step.Go.Stdout)
+	}
+
+	report, err = RunLoopLifecycleHarness(context.Background(), LifecycleHarnessConfig{
+		GoBinary:   goBin,
+		RustBinary: driftBin,
+		Scenario:   scenario,
+		Timeout:    10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("run harness: %v", err)
+	}
+	step = report.Steps[0]
+	if step.PromptsMatch || !step.HasDrift {
+		t.Fatalf("expected prompt drift, got %+v", step)
+	}
+	if step.Rust.Interactions[0].Matched || step.Rust.Interactions[1].Matched {
+		t.Fatalf("expected unmatched rust prompts, got %+v", step.Rust.Interactions)
+	}
+}
+
+func TestLoadLifecycleScenarioRejectsMultilineSend(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	body := `{"steps":[{"name":"wizard","args":["up"],"interactions":[{"expect":"Name:","send":"a\nb"}]}]}`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+	if _, err := LoadLifecycleScenario(path); err == nil {
+		t.Fatalf("expected multi-line send to be rejected")
+	}
+}
+
+func TestRunLoopLifecycleHarnessBenchFlagsRegression(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	goBin := filepath.Join(tmp, "go-cli.sh")
+	rustBin := filepath.Join(tmp, "rust-cli.sh")
+	writeScript(t, goBin, fakeGoScript(false))
+	writeScript(t, rustBin, strings.Replace(fakeRustScript(false), "  touch)\n", "  touch)\n    sleep 0.2\n", 1))
+
+	scenario := LifecycleScenario{
+		Name: "loop-lifecycle-bench",
+		Steps: []LifecycleStep{
+			{Name: "touch", Args: []string{"touch"}},
+		},
+	}
+
+	report, err := RunLoopLifecycleHarness(context.Background(), LifecycleHarnessConfig{
+		GoBinary:      goBin,
+		RustBinary:    rustBin,
+		FixtureDir:    t.TempDir(),
+		Scenario:      scenario,
+		Timeout:       5 * time.Second,
+		BenchRuns:     3,
+		PerfThreshold: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("run harness: %v", err)
+	}
+	if report.HasDrift() {
+		t.Fatalf("expected no output drift, got %+v", report.Steps)
+	}
+	perf := report.Steps[0].Perf
+	if perf == nil {
+		t.Fatalf("expected perf stats in bench mode")
+	}
+	if perf.Go.Runs != 3 || perf.Rust.Runs != 3 {
+		t.Fatalf("expected 3 runs per binary, got go=%d rust=%d", perf.Go.Runs, perf.Rust.Runs)
+	}
+	// Fresh fixture copies per pass keep the counter at 1.
+	if report.Steps[0].Rust.Stdout != "1\n" {
+		t.Fatalf("unexpected stdout %q", report.Steps[0].Rust.Stdout)
+	}
+	if !perf.Regression || report.PerfRegressionCount() != 1 {
+		t.Fatalf("expected wall-clock regression, got %+v", perf)
+	}
+	if perf.Rust.MedianMillis < 200 {
+		t.Fatalf("expected rust median >= 200ms, got %.1f", perf.Rust.MedianMillis)
+	}
+	if report.BenchRuns != 3 || report.PerfThreshold != 0.5 {
+		t.Fatalf("expected bench settings echoed, got runs=%d threshold=%v", report.BenchRuns, report.PerfThreshold)
+	}
+}
+
+func TestRunLoopLifecycleHarnessRepeatClassifiesFlakes(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	goBin := filepath.Join(tmp, "go-cli.sh")
+	rustBin := filepath.Join(tmp, "rust-cli.sh")
+	// "flap" alternates its output across invocations via a counter kept
+	// outside the fixture, so it only drifts on odd runs.
+	flapCounter := filepath.Join(tmp, "flap-count")
+	writeScript(t, goBin, strings.Replace(fakeGoScript(false), "  *)\n", "  flap)\n    echo even\n    ;;\n  *)\n", 1))
+	writeScript(t, rustBin, strings.Replace(fakeRustScript(true), "  *)\n", strings.Join([]string{
+		"  flap)",
+		"    n=0",
+		"    [[ -f '" + flapCounter + "' ]] && n=\"$(cat '" + flapCounter + "')\"",
+		"    n=$((n+1))",
+		"    echo \"$n\" > '" + flapCounter + "'",
+		"    if (( n % 2 )); then echo odd; else echo even; fi",
+		"    ;;",
+		"  *)",
+		"",
+	}, "\n"), 1))
+
+	scenario := LifecycleScenario{
+		Name: "loop-lifecycle-flakes",
+		Steps: []LifecycleStep{
+			{Name: "ps", Args: []string{"ps"}},
+			{Name: "flap", Args: []string{"flap"}},
+			{Name: "touch", Args: []string{"touch"}},
+		},
+	}
+
+	report, err := RunLoopLifecycleHarness(context.Background(), LifecycleHarnessConfig{
+		GoBinary:   goBin,
+		RustBinary: rustBin,
+		FixtureDir: t.TempDir(),
+		Scenario:   scenario,
+		Timeout:    5 * time.Second,
+		Repeat:     2,
+	})
+	if err != nil {
+		t.Fatalf("run harness: %v", err)
+	}
+	if report.Repeat != 2 || report.DriftCount() != 2 {
+		t.Fatalf("expected 2 drifting steps with repeat echoed, got repeat=%d drift=%d", report.Repeat, report.DriftCount())
+	}
+
+	ps := report.Steps[0].Flake
+	if ps == nil || ps.Classification != DriftDeterministic || ps.DriftRuns != 2 || ps.Variants != 1 {
+		t.Fatalf("expected deterministic drift on ps, got %+v", ps)
+	}
+	flap := report.Steps[1].Flake
+	if flap == nil || flap.Classification != DriftFlaky || flap.DriftRuns != 1 || flap.Repeats != 2 {
+		t.Fatalf("expected flaky drift on flap, got %+v", flap)
+	}
+	if report.Steps[2].Flake != nil {
+		t.Fatalf("expected no flake annotation on a clean step, got %+v", report.Steps[2].Flake)
+	}
+	// The rerun stops at the last drifting step: flap ran 3 times, touch once.
+	if data, err := os.ReadFile(flapCounter); err != nil || strings.TrimSpace(string(data)) != "3" {
+		t.Fatalf("expected flap to run 3 times, got %q (%v)", data, err)
+	}
+	if report.FlakyCount() != 1 || !report.HasDeterministicDrift() {
+		t.Fatalf("expected one flaky and one deterministic step, got flaky=%d", report.FlakyCount())
+	}
+
+	suite := LifecycleJUnit(report)
+	if got := suite.Cases[1].Failure; got == nil || got.Type != "flaky_drift" || !strings.HasPrefix(got.Message, "flaky (1/2 repeats drifted)") {
+		t.Fatalf("expected flaky junit failure, got %+v", got)
+	}
+	if got := suite.Cases[0].Failure; got == nil || got.Type != "drift" {
+		t.Fatalf("expected plain drift failure for ps, got %+v", got)
+	}
+}
+
+func TestRunLoopLifecycleHarnessEnvVariants(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	goBin := filepath.Join(tmp, "go-cli.sh")
+	rustBin := filepath.Join(tmp, "rust-cli.sh")
+	envCase := "  env)\n    echo \"tz=${TZ:-} lang=${LANG:-} color=${NO_COLOR:-on} cols=${COLUMNS:-}\"\n    ;;\n  *)\n"
+	writeScript(t, goBin, strings.Replace(fakeGoScript(false), "  *)\n", envCase, 1))
+	// The Rust fake ignores TZ, so it only matches Go when TZ is UTC.
+	rustEnvCase := strings.Replace(envCase, "tz=${TZ:-}", "tz=UTC", 1)
+	writeScript(t, rustBin, strings.Replace(fakeRustScript(false), "  *)\n", rustEnvCase, 1))
+
+	noColor := false
+	scenario := LifecycleScenario{
+		Name:  "loop-lifecycle-variants",
+		Steps: []LifecycleStep{{Name: "env", Args: []string{"env"}}},
+		Variants: []LifecycleEnvVariant{
+			{Name: "utc", TZ: "UTC"},
+			{Name: "ny-de", TZ: "America/New_York", Locale: "de_DE.UTF-8", NoColor: &noColor, Columns: 60},
+		},
+	}
+
+	report, err := RunLoopLifecycleHarness(context.Background(), LifecycleHarnessConfig{
+		GoBinary:   goBin,
+		RustBinary: rustBin,
+		Scenario:   scenario,
+		Timeout:    5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("run harness: %v", err)
+	}
+	if len(report.Steps) != 2 || report.Steps[0].Variant != "utc" || report.Steps[1].Variant != "ny-de" {
+		t.Fatalf("expected the step once per variant, got %+v", report.Steps)
+	}
+	if report.Steps[0].HasDrift || !report.Steps[1].HasDrift {
+		t.Fatalf("expected drift only under ny-de, got utc=%t ny-de=%t", report.Steps[0].HasDrift, report.Steps[1].HasDrift)
+	}
+	if got := report.Steps[1].Go.Stdout; got != "tz=America/New_York lang=de_DE.UTF-8 color=on cols=60\n" {
+		t.Fatalf("unexpected variant environment: %q", got)
+	}
+	if len(report.Variants) != 2 || report.Variants[0].Drift != 0 || report.Variants[1].Drift != 1 || report.Variants[1].Env["LC_ALL"] != "de_DE.UTF-8" {
+		t.Fatalf("unexpected variant summary: %+v", report.Variants)
+	}
+
+	suite := LifecycleJUnit(report)
+	if suite.Cases[0].Name != "env [utc]" || suite.Cases[1].Name != "env [ny-de]" || suite.Cases[1].Failure == nil {
+		t.Fatalf("expected junit cases per variant, got %+v", suite.Cases)
+	}
+}
+
+func TestValidateLifecycleVariants(t *testing.T) {
+	t.Parallel()
+
+	steps := []LifecycleStep{{Name: "ps", Args: []string{"ps"}}}
+	for name, variants := range map[string][]LifecycleEnvVariant{
+		"missing name":     {{TZ: "UTC"}},
+		"duplicate name":   {{Name: "a"}, {Name: "a"}},
+		"negative columns": {{Name: "narrow", Columns: -1}},
+		"bad env name":     {{Name: "a", Env: map[string]string{"A=B": "1"}}},
+	} {
+		if err := validateLifecycleScenario(LifecycleScenario{Steps: steps, Variants: variants}); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestCompareStepPerfIgnoresNoise(t *testing.T) {
+	t.Parallel()
+
+	perf := compareStepPerf(
+		LifecyclePerfStats{Runs: 3, MedianMillis: 2, PeakRSSKB: 4000},
+		LifecyclePerfStats{Runs: 3, MedianMillis: 4, PeakRSSKB: 4200},
+		DefaultPerfRegressionThreshold,
+	)
+	if perf.Regression {
+		t.Fatalf("expected 2ms slowdown under the noise floor to pass, got %+v", perf)
+	}
+
+	perf = compareStepPerf(
+		LifecyclePerfStats{Runs: 3, MedianMillis: 20, PeakRSSKB: 4000},
+		LifecyclePerfStats{Runs: 3, MedianMillis: 21, PeakRSSKB: 9000},
+		DefaultPerfRegressionThreshold,
+	)
+	if !perf.Regression || len(perf.Reasons) != 1 || !strings.HasPrefix(perf.Reasons[0], "rss ") {
+		t.Fatalf("expected rss regression, got %+v", perf)
+	}
+}
+
+func fakeInteractiveScript(prompt string) string {
+	return strings.Join([]string{
+		"#!/usr/bin/env bash",
+		"set -uo pipefail",
+		"interactive=1",
+		"[[ -n \"${FORGE_NON_INTERACTIVE:-}\" ]] && interactive=0",
+		"printf '" + shellEscapeSingle(prompt) + "'",
+		"read -r answer || exit 3",
+		"printf 'Reason: ' >&2",
+		"read -r reason || exit 4",
+		"echo \"removed $2 ($answer, $reason) interactive=$interactive\"",
+		"",
+	}, "\n")
+}
+
 func writeScript(t *testing.T, path, body string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(body), 0o755); err != nil {
@@ -0,0 +1,224 @@
+package parity
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPromptTimeout bounds how long an interactive step waits for each
+// expected prompt.
+const defaultPromptTimeout = 5 * time.Second
+
+// LifecycleInteraction is one scripted exchange with an interactive step:
+// wait for Expect to appear on stdout or stderr, then write Send plus a
+// newline to stdin. An empty Expect sends immediately.
+type LifecycleInteraction struct {
+	Expect string `json:"expect,omitempty"`
+	Send   string `json:"send"`
+}
+
+// LifecycleInteractionResult records whether a prompt appeared in order.
+type LifecycleInteractionResult struct {
+	Expect  string `json:"expect,omitempty"`
+	Send    string `json:"send"`
+	Matched bool   `json:"matched"`
+}
+
+// Interactive reports whether the step feeds scripted stdin.
+func (s LifecycleStep) Interactive() bool {
+	return len(s.Interactions) > 0
+}
+
+// interactionsMatched reports whether every scripted prompt appeared.
+func interactionsMatched(results []LifecycleInteractionResult) bool {
+	for _, result := range results {
+		if !result.Matched {
+			return false
+		}
+	}
+	return true
+}
+
+// runHarnessStep runs one scenario step, feeding scripted stdin when the
+// step is interactive.
+func runHarnessStep(ctx context.Context, timeout time.Duration, binary string, step LifecycleStep, workdir string, env []string) (LifecycleCommandResult, error) {
+	if !step.Interactive() {
+		return runHarnessCommand(ctx, timeout, binary, step.Args, workdir, env)
+	}
+	return runInteractiveHarnessCommand(ctx, timeout, binary, step, workdir, interactiveEnv(env))
+}
+
+// interactiveEnv drops FORGE_NON_INTERACTIVE so the binary prompts.
+func interactiveEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, entry := range env {
+		if strings.HasPrefix(entry, "FORGE_NON_INTERACTIVE=") {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func runInteractiveHarnessCommand(ctx context.Context, timeout time.Duration, binary string, step LifecycleStep, workdir string, env []string) (LifecycleCommandResult, error) {
+	runCtx := ctx
+	cancel := func() {}
+	if timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, binary, step.Args...)
+	cmd.Dir = workdir
+	cmd.Env = append([]string(nil), env...)
+
+	output := newInteractiveOutput()
+	cmd.Stdout = output.writer(false)
+	cmd.Stderr = output.writer(true)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return LifecycleCommandResult{}, err
+	}
+	started := time.Now()
+	if err := cmd.Start(); err != nil {
+		return LifecycleCommandResult{}, fmt.Errorf("run command %q: %w", strings.Join(append([]string{binary}, step.Args...), " "), err)
+	}
+
+	exited := make(chan struct{})
+	var waitErr error
+	var wall time.Duration
+	go func() {
+		waitErr = cmd.Wait()
+		wall = time.Since(started)
+		close(exited)
+	}()
+
+	results := make([]LifecycleInteractionResult, 0, len(step.Interactions))
+	offset := 0
+	failed := false
+	for _, interaction := range step.Interactions {
+		result := LifecycleInteractionResult{Expect: interaction.Expect, Send: interaction.Send}
+		if !failed && interaction.Expect != "" {
+			next, ok := output.waitFor(runCtx, exited, interaction.Expect, offset)
+			if ok {
+				offset = next
+			} else {
+				failed = true
+			}
+		}
+		if !failed {
+			if _, err := io.WriteString(stdin, interaction.Send+"\n"); err != nil {
+				failed = true
+			} else {
+				result.Matched = true
+			}
+		}
+		results = append(results, result)
+	}
+	_ = stdin.Close()
+	<-exited
+
+	stdout, stderr := output.streams()
+	result := LifecycleCommandResult{Stdout: stdout, Stderr: stderr, Interactions: results, wall: wall, peakRSSKB: peakRSSKB(cmd.ProcessState)}
+	if waitErr == nil {
+		return result, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	if runCtx.Err() != nil {
+		return result, fmt.Errorf("run command %q timed out/canceled: %w", strings.Join(append([]string{binary}, step.Args...), " "), runCtx.Err())
+	}
+	return result, fmt.Errorf("run command %q: %w", strings.Join(append([]string{binary}, step.Args...), " "), waitErr)
+}
+
+// interactiveOutput collects stdout and stderr separately plus their
+// interleaving, which is what prompts are matched against.
+type interactiveOutput struct {
+	mu       sync.Mutex
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	combined bytes.Buffer
+	changed  chan struct{}
+}
+
+func newInteractiveOutput() *interactiveOutput {
+	return &interactiveOutput{changed: make(chan struct{}, 1)}
+}
+
+func (o *interactiveOutput) writer(stderr bool) io.Writer {
+	return interactiveStream{out: o, stderr: stderr}
+}
+
+func (o *interactiveOutput) streams() (string, string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stdout.String(), o.stderr.String()
+}
+
+// waitFor blocks until expect appears in the combined output at or after
+// offset and returns the offset just past it. It gives up when the prompt
+// timeout passes, ctx ends, or the process exits without printing it.
+func (o *interactiveOutput) waitFor(ctx context.Context, exited <-chan struct{}, expect string, offset int) (int, bool) {
+	timer := time.NewTimer(defaultPromptTimeout)
+	defer timer.Stop()
+	for {
+		if next, ok := o.find(expect, offset); ok {
+			return next, true
+		}
+		select {
+		case <-o.changed:
+		case <-exited:
+			return o.find(expect, offset)
+		case <-timer.C:
+			return offset, false
+		case <-ctx.Done():
+			return offset, false
+		}
+	}
+}
+
+func (o *interactiveOutput) find(expect string, offset int) (int, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	data := o.combined.Bytes()
+	if offset > len(data) {
+		return offset, false
+	}
+	idx := bytes.Index(data[offset:], []byte(expect))
+	if idx < 0 {
+		return offset, false
+	}
+	return offset + idx + len(expect), true
+}
+
+type interactiveStream struct {
+	out    *interactiveOutput
+	stderr bool
+}
+
+func (s interactiveStream) Write(p []byte) (int, error) {
+	s.out.mu.Lock()
+	if s.stderr {
+		s.out.stderr.Write(p)
+	} else {
+		s.out.stdout.Write(p)
+	}
+	s.out.combined.Write(p)
+	s.out.mu.Unlock()
+
+	select {
+	case s.out.changed <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package parity
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// peakRSSKB returns the max resident set size of an exited process in KB.
+func peakRSSKB(state *os.ProcessState) int64 {
+	if state == nil {
+		return 0
+	}
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || usage == nil {
+		return 0
+	}
+	// Darwin reports ru_maxrss in bytes, Linux and the BSDs in kilobytes.
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return int64(usage.Maxrss) / 1024
+	}
+	return int64(usage.Maxrss)
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package parity
+
+import "os"
+
+// peakRSSKB is not reported on Windows; benchmarks compare wall-clock only.
+func peakRSSKB(state *os.ProcessState) int64 {
+	return 0
+}
@@ -0,0 +1,110 @@
+package parity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LifecycleEnvVariant is one environment a scenario is replayed under.
+// Output parity often breaks only under a specific locale, time zone, color
+// setting or terminal width, so a scenario can list several variants and
+// every step is compared under each of them.
+type LifecycleEnvVariant struct {
+	Name string `json:"name"`
+	// Locale sets LANG and LC_ALL, e.g. "de_DE.UTF-8".
+	Locale string `json:"locale,omitempty"`
+	// TZ sets the time zone, e.g. "America/New_York".
+	TZ string `json:"tz,omitempty"`
+	// NoColor, when false, unsets the NO_COLOR the harness exports by
+	// default so color output is compared too.
+	NoColor *bool `json:"no_color,omitempty"`
+	// Columns sets COLUMNS, the terminal width commands wrap to.
+	Columns int `json:"columns,omitempty"`
+	// Env sets any other variables, after the fields above; an empty value
+	// unsets the variable.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// LifecycleVariantReport summarizes the steps run under one variant.
+type LifecycleVariantReport struct {
+	Name string `json:"name"`
+	// Env lists the variables the variant set; an empty value means the
+	// variable was unset.
+	Env   map[string]string `json:"env,omitempty"`
+	Steps int               `json:"steps"`
+	Drift int               `json:"drift"`
+}
+
+// overrides returns the variables the variant sets, with "" for variables
+// it unsets.
+func (v LifecycleEnvVariant) overrides() map[string]string {
+	out := make(map[string]string)
+	if locale := strings.TrimSpace(v.Locale); locale != "" {
+		out["LANG"] = locale
+		out["LC_ALL"] = locale
+	}
+	if tz := strings.TrimSpace(v.TZ); tz != "" {
+		out["TZ"] = tz
+	}
+	if v.NoColor != nil {
+		out["NO_COLOR"] = ""
+		if *v.NoColor {
+			out["NO_COLOR"] = "1"
+		}
+	}
+	if v.Columns > 0 {
+		out["COLUMNS"] = strconv.Itoa(v.Columns)
+	}
+	for key, value := range v.Env {
+		out[key] = value
+	}
+	return out
+}
+
+// applyVariantEnv applies a variant's overrides to a built harness
+// environment. Variables set to "" are removed.
+func applyVariantEnv(env []string, variant LifecycleEnvVariant) []string {
+	overrides := variant.overrides()
+	if len(overrides) == 0 {
+		return env
+	}
+	out := make([]string, 0, len(env)+len(overrides))
+	for _, entry := range env {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := overrides[key]; !ok {
+			out = append(out, entry)
+		}
+	}
+	for key, value := range overrides {
+		if value != "" {
+			out = append(out, key+"="+value)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func validateLifecycleVariants(variants []LifecycleEnvVariant) error {
+	seen := make(map[string]bool, len(variants))
+	for i, variant := range variants {
+		name := strings.TrimSpace(variant.Name)
+		if name == "" {
+			return fmt.Errorf("variant %d: name is required", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("variant %d: duplicate name %q", i, name)
+		}
+		seen[name] = true
+		if variant.Columns < 0 {
+			return fmt.Errorf("variant %d (%s): columns must not be negative", i, name)
+		}
+		for key := range variant.Env {
+			if key == "" || strings.Contains(key, "=") {
+				return fmt.Errorf("variant %d (%s): invalid env name %q", i, name, key)
+			}
+		}
+	}
+	return nil
+}
@@ -12,8 +12,10 @@ import (
 
 // SchemaFingerprint captures a deterministic schema dump plus digest.
 type SchemaFingerprint struct {
-	Dump   string
-	SHA256 string
+	// Version is the schema version the migrations produced.
+	Version int
+	Dump    string
+	SHA256  string
 }
 
 // ComputeSchemaFingerprint migrates an in-memory DB and fingerprints sqlite schema objects.
@@ -29,6 +31,11 @@ func ComputeSchemaFingerprint(ctx context.Context) (SchemaFingerprint, error) {
 	if _, err := database.MigrateUp(ctx); err != nil {
 		return fingerprint, fmt.Errorf("migrate up: %w", err)
 	}
+	version, err := database.SchemaVersion(ctx)
+	if err != nil {
+		return fingerprint, fmt.Errorf("schema version: %w", err)
+	}
+	fingerprint.Version = version
 
 	rows, err := database.QueryContext(ctx, `
 		SELECT type, name, tbl_name, COALESCE(sql, '')
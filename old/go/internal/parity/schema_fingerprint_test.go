@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
 )
 
 func TestSchemaFingerprintBaseline(t *testing.T) {
@@ -25,6 +27,14 @@ func TestSchemaFingerprintBaseline(t *testing.T) {
 	if fingerprint.SHA256 != expectedHash {
 		t.Fatalf("schema fingerprint hash drift: got %s want %s", fingerprint.SHA256, expectedHash)
 	}
+
+	latest, err := db.LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("latest schema version: %v", err)
+	}
+	if fingerprint.Version != latest {
+		t.Fatalf("fingerprint version %d, want latest migration %d", fingerprint.Version, latest)
+	}
 }
 
 func TestSchemaFingerprintDeterministic(t *testing.T) {
@@ -296,7 +296,8 @@ func parseFlagLine(line string) (SurfaceFlag, bool) {
 	if idx < len(parts) {
 		candidate := strings.ToLower(parts[idx])
 		switch candidate {
-		case "string", "int", "duration", "stringslice":
+		case "string", "int", "duration", "stringslice",
+			"strings", "stringarray", "stringtostring", "int64", "uint", "float64", "ints":
 			f.Type = candidate
 			idx++
 		default:
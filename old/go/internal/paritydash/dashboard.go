@@ -46,6 +46,7 @@ type Dashboard struct {
 	Run           RunInfo `json:"run,omitempty"`
 	Summary       Summary `json:"summary"`
 	Checks        []Check `json:"checks"`
+	Trend         *Trend  `json:"trend,omitempty"`
 }
 
 type Summary struct {
@@ -64,6 +65,9 @@ type Check struct {
 	Outcome string `json:"outcome"` // original outcome string
 	Details string `json:"details,omitempty"`
 	URL     string `json:"url,omitempty"`
+
+	// History holds this check's status per trend input, oldest first.
+	History []string `json:"history,omitempty"`
 }
 
 func Build(input Input, now time.Time) (Dashboard, error) {
@@ -136,6 +140,17 @@ func statusFromOutcome(outcome string) string {
 }
 
 func WriteFiles(outDir string, d Dashboard, writeMarkdown bool) error {
+	return WriteFilesWithOptions(outDir, d, WriteOptions{Markdown: writeMarkdown})
+}
+
+// WriteOptions selects the optional renderers used by WriteFilesWithOptions.
+// The JSON dashboard is always written.
+type WriteOptions struct {
+	Markdown bool
+	HTML     bool
+}
+
+func WriteFilesWithOptions(outDir string, d Dashboard, opts WriteOptions) error {
 	if strings.TrimSpace(outDir) == "" {
 		return errors.New("out dir is required")
 	}
@@ -154,7 +169,7 @@ func WriteFiles(outDir string, d Dashboard, writeMarkdown bool) error {
 		return fmt.Errorf("write json: %w", err)
 	}
 
-	if writeMarkdown {
+	if opts.Markdown {
 		mdPath := filepath.Join(outDir, "parity-dashboard.md")
 		md := []byte(MarkdownSummary(d) + "\n")
 		if err := os.WriteFile(mdPath, md, 0o644); err != nil {
@@ -162,5 +177,16 @@ func WriteFiles(outDir string, d Dashboard, writeMarkdown bool) error {
 		}
 	}
 
+	if opts.HTML {
+		page, err := HTMLReport(d)
+		if err != nil {
+			return fmt.Errorf("render html: %w", err)
+		}
+		htmlPath := filepath.Join(outDir, "parity-dashboard.html")
+		if err := os.WriteFile(htmlPath, []byte(page), 0o644); err != nil {
+			return fmt.Errorf("write html: %w", err)
+		}
+	}
+
 	return nil
 }
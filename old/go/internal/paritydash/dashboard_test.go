@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -93,3 +94,90 @@ func TestWriteFilesWritesJSONAndMarkdown(t *testing.T) {
 		t.Fatalf("stat md: %v", err)
 	}
 }
+
+func TestBuildWithHistoryComputesTrend(t *testing.T) {
+	now := time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC)
+	inputs := []Input{
+		{Run: RunInfo{SHA: "aaaaaaaaaa"}, Checks: []InputCheck{{ID: "oracle", Outcome: "failure"}, {ID: "schema", Outcome: "failure"}}},
+		{Run: RunInfo{SHA: "bbbbbbbbbb"}, Checks: []InputCheck{{ID: "oracle", Outcome: "success"}, {ID: "schema", Outcome: "failure"}}},
+		{Run: RunInfo{SHA: "cccccccccc"}, Checks: []InputCheck{{ID: "oracle", Outcome: "success"}, {ID: "schema", Outcome: "success"}, {ID: "diff", Outcome: "skipped"}}},
+	}
+
+	d, err := BuildWithHistory(inputs, now, 0)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if d.Run.SHA != "cccccccccc" || d.Summary.Status != "pass" {
+		t.Fatalf("expected current run to drive summary: %+v %+v", d.Run, d.Summary)
+	}
+	if d.Trend == nil || len(d.Trend.Points) != 3 {
+		t.Fatalf("trend: %+v", d.Trend)
+	}
+	if got := []int{d.Trend.Points[0].Drift, d.Trend.Points[1].Drift, d.Trend.Points[2].Drift}; got[0] != 2 || got[1] != 1 || got[2] != 0 {
+		t.Fatalf("drift: %v", got)
+	}
+	if !d.Trend.Points[2].Current || d.Trend.Direction != "improving" {
+		t.Fatalf("unexpected trend summary: %+v", d.Trend)
+	}
+	for _, c := range d.Checks {
+		if c.ID == "diff" && strings.Join(c.History, ",") != "missing,missing,skipped" {
+			t.Fatalf("diff history: %v", c.History)
+		}
+		if c.ID == "oracle" && strings.Join(c.History, ",") != "fail,pass,pass" {
+			t.Fatalf("oracle history: %v", c.History)
+		}
+	}
+
+	limited, err := BuildWithHistory(inputs, now, 2)
+	if err != nil {
+		t.Fatalf("build limited: %v", err)
+	}
+	if len(limited.Trend.Points) != 2 || limited.Trend.Points[0].SHA != "bbbbbbbbbb" {
+		t.Fatalf("limit not applied: %+v", limited.Trend.Points)
+	}
+}
+
+func TestBuildWithHistorySingleInputHasNoTrend(t *testing.T) {
+	d, err := BuildWithHistory([]Input{{Checks: []InputCheck{{ID: "oracle", Outcome: "success"}}}}, time.Now(), 5)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if d.Trend != nil || d.Checks[0].History != nil {
+		t.Fatalf("expected no trend for a single input: %+v", d)
+	}
+}
+
+func TestWriteFilesWithOptionsWritesHTML(t *testing.T) {
+	now := time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC)
+	d, err := BuildWithHistory([]Input{
+		{Checks: []InputCheck{{ID: "oracle", Outcome: "failure"}}},
+		{Run: RunInfo{SHA: "deadbeefcafe"}, Checks: []InputCheck{{ID: "oracle", Name: "<Oracle>", Outcome: "success"}}},
+	}, now, 0)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "dash")
+	if err := WriteFilesWithOptions(out, d, WriteOptions{Markdown: true, HTML: true}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	hb, err := os.ReadFile(filepath.Join(out, "parity-dashboard.html"))
+	if err != nil {
+		t.Fatalf("read html: %v", err)
+	}
+	page := string(hb)
+	for _, want := range []string{"<polyline", "improving over the last 2 runs", "cell-fail", "cell-pass", "&lt;Oracle&gt;", "deadbee"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("html missing %q", want)
+		}
+	}
+
+	mb, err := os.ReadFile(filepath.Join(out, "parity-dashboard.md"))
+	if err != nil {
+		t.Fatalf("read md: %v", err)
+	}
+	if !strings.Contains(string(mb), "- Trend: improving (drift 1 -> 0 over 2 runs)") {
+		t.Fatalf("markdown missing trend line:\n%s", mb)
+	}
+}
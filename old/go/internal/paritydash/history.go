@@ -0,0 +1,116 @@
+package paritydash
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultHistoryLimit is how many CI inputs (including the current one) are
+// kept in a dashboard trend.
+const DefaultHistoryLimit = 20
+
+// Trend describes parity drift across consecutive CI inputs, oldest first.
+type Trend struct {
+	Points    []TrendPoint `json:"points"`
+	Direction string       `json:"direction"` // improving|regressing|steady
+}
+
+// TrendPoint is the summary of one CI input. Drift counts checks that did
+// not pass cleanly (failed or unknown).
+type TrendPoint struct {
+	RunID   string `json:"run_id,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+	Ref     string `json:"ref,omitempty"`
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Drift   int    `json:"drift"`
+	Status  string `json:"status"`
+	Current bool   `json:"current,omitempty"`
+}
+
+// BuildWithHistory builds the dashboard for the last input and attaches a
+// drift trend computed from up to limit inputs. Inputs are ordered oldest
+// first; each check also gains its per-run status history.
+func BuildWithHistory(inputs []Input, now time.Time, limit int) (Dashboard, error) {
+	if len(inputs) == 0 {
+		return Dashboard{}, errors.New("no inputs provided")
+	}
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if len(inputs) > limit {
+		inputs = inputs[len(inputs)-limit:]
+	}
+
+	d, err := Build(inputs[len(inputs)-1], now)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	if len(inputs) == 1 {
+		return d, nil
+	}
+
+	trend := &Trend{}
+	statuses := make([]map[string]string, 0, len(inputs))
+	for i, in := range inputs {
+		past := d
+		if i < len(inputs)-1 {
+			past, err = Build(in, now)
+			if err != nil {
+				return Dashboard{}, err
+			}
+		}
+		trend.Points = append(trend.Points, TrendPoint{
+			RunID:   in.Run.RunID,
+			SHA:     in.Run.SHA,
+			Ref:     in.Run.Ref,
+			Total:   past.Summary.Total,
+			Passed:  past.Summary.Passed,
+			Drift:   past.Summary.Failed + past.Summary.Unknown,
+			Status:  past.Summary.Status,
+			Current: i == len(inputs)-1,
+		})
+		byID := make(map[string]string, len(past.Checks))
+		for _, c := range past.Checks {
+			byID[c.ID] = c.Status
+		}
+		statuses = append(statuses, byID)
+	}
+	trend.Direction = trendDirection(trend.Points)
+	d.Trend = trend
+
+	for i := range d.Checks {
+		history := make([]string, 0, len(statuses))
+		for _, byID := range statuses {
+			status, ok := byID[d.Checks[i].ID]
+			if !ok {
+				status = "missing"
+			}
+			history = append(history, status)
+		}
+		d.Checks[i].History = history
+	}
+
+	return d, nil
+}
+
+// trendDirection compares the current drift with the average of earlier runs.
+func trendDirection(points []TrendPoint) string {
+	if len(points) < 2 {
+		return "steady"
+	}
+	last := points[len(points)-1].Drift
+	sum := 0
+	for _, p := range points[:len(points)-1] {
+		sum += p.Drift
+	}
+	prev := float64(sum) / float64(len(points)-1)
+	switch {
+	case float64(last) < prev:
+		return "improving"
+	case float64(last) > prev:
+		return "regressing"
+	default:
+		return "steady"
+	}
+}
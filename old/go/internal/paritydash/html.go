@@ -0,0 +1,181 @@
+package paritydash
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+const (
+	sparkWidth  = 240
+	sparkHeight = 40
+	sparkPad    = 3
+	cellSize    = 10
+)
+
+var htmlTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Parity Dashboard</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #59636e; font-size: 0.9rem; }
+.status { display: inline-block; padding: 0.1rem 0.5rem; border-radius: 4px; font-weight: 600; color: #fff; }
+.status-pass { background: #1a7f37; }
+.status-fail { background: #cf222e; }
+.status-skipped { background: #9a6700; }
+.status-unknown, .status-missing { background: #6e7781; }
+table { border-collapse: collapse; margin-top: 1rem; }
+th, td { border-bottom: 1px solid #d1d9e0; padding: 0.35rem 0.75rem; text-align: left; vertical-align: middle; }
+.trend { margin-top: 1rem; }
+.trend-improving { color: #1a7f37; }
+.trend-regressing { color: #cf222e; }
+.trend-steady { color: #59636e; }
+.cell-pass { fill: #1a7f37; }
+.cell-fail { fill: #cf222e; }
+.cell-skipped { fill: #d4a72c; }
+.cell-unknown, .cell-missing { fill: #afb8c1; }
+</style>
+</head>
+<body>
+<h1>Parity Dashboard</h1>
+<p class="meta">Generated {{.GeneratedAt}}{{with .Run.Repository}} &middot; {{.}}{{end}}{{with .Run.Ref}} &middot; {{.}}{{end}}{{with .Run.SHA}} &middot; {{.}}{{end}}{{with .Run.RunURL}} &middot; <a href="{{.}}">run</a>{{end}}</p>
+<p><span class="status status-{{.Summary.Status}}">{{.StatusLabel}}</span>
+{{.Summary.Total}} checks: {{.Summary.Passed}} pass, {{.Summary.Failed}} fail, {{.Summary.Skipped}} skipped, {{.Summary.Unknown}} unknown</p>
+{{if .Trend}}
+<div class="trend">
+<h2>Drift trend</h2>
+<p class="trend-{{.Trend.Direction}}">{{.Trend.Direction}} over the last {{len .Trend.Points}} runs (drift = failed + unknown checks)</p>
+<svg width="{{.SparkWidth}}" height="{{.SparkHeight}}" viewBox="0 0 {{.SparkWidth}} {{.SparkHeight}}" role="img" aria-label="drift sparkline">
+<polyline fill="none" stroke="#cf222e" stroke-width="2" points="{{.SparkPoints}}"/>
+{{range .SparkDots}}<circle cx="{{.X}}" cy="{{.Y}}" r="{{if .Current}}3{{else}}1.5{{end}}" fill="#cf222e"><title>{{.Label}}</title></circle>{{end}}
+</svg>
+</div>
+{{end}}
+<h2>Checks</h2>
+<table>
+<thead><tr><th>Status</th><th>ID</th><th>Name</th>{{if .Trend}}<th>History</th>{{end}}</tr></thead>
+<tbody>
+{{range .Checks}}<tr>
+<td><span class="status status-{{.Status}}">{{.Label}}</span></td>
+<td><code>{{.ID}}</code></td>
+<td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{with .Details}}<br><small>{{.}}</small>{{end}}</td>
+{{if $.Trend}}<td><svg width="{{.StripWidth}}" height="{{$.CellSize}}">{{range .Cells}}<rect x="{{.X}}" y="0" width="{{$.CellInner}}" height="{{$.CellSize}}" class="cell-{{.Status}}"><title>{{.Status}}</title></rect>{{end}}</svg></td>{{end}}
+</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+type htmlPage struct {
+	Dashboard
+	StatusLabel string
+	SparkWidth  int
+	SparkHeight int
+	SparkPoints string
+	SparkDots   []htmlDot
+	CellSize    int
+	CellInner   int
+	Checks      []htmlCheck
+}
+
+type htmlDot struct {
+	X, Y    float64
+	Current bool
+	Label   string
+}
+
+type htmlCheck struct {
+	Check
+	Label      string
+	StripWidth int
+	Cells      []htmlCell
+}
+
+type htmlCell struct {
+	X      int
+	Status string
+}
+
+// HTMLReport renders a self-contained HTML page for the dashboard. When the
+// dashboard carries a trend, it includes a drift sparkline and a per-check
+// status strip.
+func HTMLReport(d Dashboard) (string, error) {
+	page := htmlPage{
+		Dashboard:   d,
+		StatusLabel: strings.ToUpper(d.Summary.Status),
+		SparkWidth:  sparkWidth,
+		SparkHeight: sparkHeight,
+		CellSize:    cellSize,
+		CellInner:   cellSize - 2,
+	}
+	if d.Trend != nil {
+		page.SparkPoints, page.SparkDots = sparkline(d.Trend.Points)
+	}
+
+	checks := append([]Check(nil), d.Checks...)
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].Status == checks[j].Status {
+			return checks[i].ID < checks[j].ID
+		}
+		return statusRank(checks[i].Status) < statusRank(checks[j].Status)
+	})
+	for _, c := range checks {
+		hc := htmlCheck{Check: c, Label: strings.ToUpper(c.Status), StripWidth: len(c.History) * cellSize}
+		for i, status := range c.History {
+			hc.Cells = append(hc.Cells, htmlCell{X: i * cellSize, Status: status})
+		}
+		page.Checks = append(page.Checks, hc)
+	}
+
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, page); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// sparkline maps drift values onto the SVG canvas; higher drift is drawn
+// higher so regressions stand out.
+func sparkline(points []TrendPoint) (string, []htmlDot) {
+	if len(points) == 0 {
+		return "", nil
+	}
+	maxDrift := 1
+	for _, p := range points {
+		if p.Drift > maxDrift {
+			maxDrift = p.Drift
+		}
+	}
+	step := 0.0
+	if len(points) > 1 {
+		step = float64(sparkWidth-2*sparkPad) / float64(len(points)-1)
+	}
+	usable := float64(sparkHeight - 2*sparkPad)
+
+	coords := make([]string, 0, len(points))
+	dots := make([]htmlDot, 0, len(points))
+	for i, p := range points {
+		x := float64(sparkPad) + step*float64(i)
+		y := float64(sparkPad) + usable*(1-float64(p.Drift)/float64(maxDrift))
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+
+		label := fmt.Sprintf("drift %d/%d", p.Drift, p.Total)
+		if p.SHA != "" {
+			label = fmt.Sprintf("%s (%s)", label, shortSHA(p.SHA))
+		}
+		dots = append(dots, htmlDot{X: x, Y: y, Current: p.Current, Label: label})
+	}
+	return strings.Join(coords, " "), dots
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
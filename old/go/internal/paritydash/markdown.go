@@ -11,8 +11,14 @@ func MarkdownSummary(d Dashboard) string {
 
 	fmt.Fprintf(&b, "## Parity Dashboard\n\n")
 	fmt.Fprintf(&b, "- Status: %s\n", strings.ToUpper(d.Summary.Status))
-	fmt.Fprintf(&b, "- Checks: %d total (%d pass, %d fail, %d skipped, %d unknown)\n\n",
+	fmt.Fprintf(&b, "- Checks: %d total (%d pass, %d fail, %d skipped, %d unknown)\n",
 		d.Summary.Total, d.Summary.Passed, d.Summary.Failed, d.Summary.Skipped, d.Summary.Unknown)
+	if d.Trend != nil && len(d.Trend.Points) > 1 {
+		first, last := d.Trend.Points[0], d.Trend.Points[len(d.Trend.Points)-1]
+		fmt.Fprintf(&b, "- Trend: %s (drift %d -> %d over %d runs)\n",
+			d.Trend.Direction, first.Drift, last.Drift, len(d.Trend.Points))
+	}
+	fmt.Fprintf(&b, "\n")
 
 	if d.Run.Workflow != "" {
 		fmt.Fprintf(&b, "### Run\n\n")
@@ -75,4 +81,3 @@ func statusRank(status string) int {
 func escapePipes(s string) string {
 	return strings.ReplaceAll(s, "|", "\\|")
 }
-
@@ -1,6 +1,26 @@
 package procutil
 
-import "os/exec"
+import (
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// ErrUsageUnavailable is returned when resource usage cannot be sampled on
+// this platform.
+var ErrUsageUnavailable = errors.New("process resource usage unavailable")
+
+// ErrCommandLineUnavailable is returned when a process command line cannot
+// be read on this platform.
+var ErrCommandLineUnavailable = errors.New("process command line unavailable")
+
+// Usage is a point-in-time resource sample of a process.
+type Usage struct {
+	// CPUTime is the cumulative user+system CPU time.
+	CPUTime time.Duration
+	// RSSBytes is the resident set size.
+	RSSBytes uint64
+}
 
 // ConfigureDetached configures a command to run detached from the current session/process group.
 func ConfigureDetached(cmd *exec.Cmd) {
@@ -11,3 +31,14 @@ func ConfigureDetached(cmd *exec.Cmd) {
 func IsProcessAlive(pid int) bool {
 	return isProcessAlive(pid)
 }
+
+// SampleUsage reads the cumulative CPU time and resident memory of a process.
+// CPU utilization is derived by comparing two samples.
+func SampleUsage(pid int) (Usage, error) {
+	return sampleUsage(pid)
+}
+
+// CommandLine returns the space-joined command line of a process.
+func CommandLine(pid int) (string, error) {
+	return commandLine(pid)
+}
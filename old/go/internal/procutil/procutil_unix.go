@@ -4,9 +4,13 @@ package procutil
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 )
 
 func configureDetached(cmd *exec.Cmd) {
@@ -35,3 +39,128 @@ func isProcessAlive(pid int) bool {
 	// EPERM means process exists but we lack permission to signal it.
 	return !errors.Is(err, syscall.ESRCH)
 }
+
+// clockTicksPerSecond is USER_HZ, which Linux fixes at 100 for /proc.
+const clockTicksPerSecond = 100
+
+func sampleUsage(pid int) (Usage, error) {
+	if pid <= 0 {
+		return Usage{}, fmt.Errorf("invalid pid %d", pid)
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err == nil {
+		return parseProcStat(string(data), os.Getpagesize())
+	}
+	if !errors.Is(err, os.ErrNotExist) || procMounted() {
+		return Usage{}, err
+	}
+	// No procfs (macOS, BSD): ask ps.
+	out, err := exec.Command("ps", "-o", "time=,rss=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return Usage{}, err
+	}
+	return parsePSUsage(string(out))
+}
+
+func commandLine(pid int) (string, error) {
+	if pid <= 0 {
+		return "", fmt.Errorf("invalid pid %d", pid)
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err == nil {
+		return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " ")), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || procMounted() {
+		return "", err
+	}
+	out, err := exec.Command("ps", "-o", "args=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func procMounted() bool {
+	_, err := os.Stat("/proc/self/stat")
+	return err == nil
+}
+
+// parseProcStat extracts utime, stime and rss from a /proc/<pid>/stat line.
+// The command name may contain spaces and parentheses, so fields are counted
+// from the last ')'.
+func parseProcStat(line string, pageSize int) (Usage, error) {
+	end := strings.LastIndexByte(line, ')')
+	if end < 0 {
+		return Usage{}, fmt.Errorf("malformed stat line")
+	}
+	// Fields after the command start at field 3 (state).
+	fields := strings.Fields(line[end+1:])
+	const (
+		utimeIdx = 14 - 3
+		stimeIdx = 15 - 3
+		rssIdx   = 24 - 3
+	)
+	if len(fields) <= rssIdx {
+		return Usage{}, fmt.Errorf("malformed stat line")
+	}
+	utime, err := strconv.ParseUint(fields[utimeIdx], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("parse utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[stimeIdx], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("parse stime: %w", err)
+	}
+	rss, err := strconv.ParseInt(fields[rssIdx], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("parse rss: %w", err)
+	}
+	if rss < 0 {
+		rss = 0
+	}
+	return Usage{
+		CPUTime:  time.Duration(utime+stime) * time.Second / clockTicksPerSecond,
+		RSSBytes: uint64(rss) * uint64(pageSize),
+	}, nil
+}
+
+// parsePSUsage parses `ps -o time=,rss=` output: cumulative CPU time as
+// [[dd-]hh:]mm:ss[.cc] and RSS in KiB.
+func parsePSUsage(out string) (Usage, error) {
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return Usage{}, fmt.Errorf("unexpected ps output %q", strings.TrimSpace(out))
+	}
+	cpu, err := parsePSTime(fields[0])
+	if err != nil {
+		return Usage{}, err
+	}
+	rss, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("parse rss: %w", err)
+	}
+	return Usage{CPUTime: cpu, RSSBytes: rss * 1024}, nil
+}
+
+func parsePSTime(value string) (time.Duration, error) {
+	var days int64
+	if day, rest, ok := strings.Cut(value, "-"); ok {
+		parsed, err := strconv.ParseInt(day, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse cpu time %q: %w", value, err)
+		}
+		days = parsed
+		value = rest
+	}
+	parts := strings.Split(value, ":")
+	var total float64
+	for _, part := range parts {
+		parsed, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse cpu time %q: %w", value, err)
+		}
+		total = total*60 + parsed
+	}
+	total += float64(days) * 24 * 3600
+	return time.Duration(total * float64(time.Second)), nil
+}
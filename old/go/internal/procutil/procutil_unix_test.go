@@ -3,8 +3,11 @@
 package procutil
 
 import (
+	"os"
 	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfigureDetachedSetsid(t *testing.T) {
@@ -17,3 +20,48 @@ func TestConfigureDetachedSetsid(t *testing.T) {
 		t.Fatalf("expected Setsid=true")
 	}
 }
+
+func TestParseProcStat(t *testing.T) {
+	line := "4242 (forge (loop) x) S 1 4242 4242 0 -1 4194560 1200 0 0 0 250 50 0 0 20 0 8 0 1000 123456789 300 18446744073709551615"
+	usage, err := parseProcStat(line, 4096)
+	if err != nil {
+		t.Fatalf("parseProcStat: %v", err)
+	}
+	if usage.CPUTime != 3*time.Second {
+		t.Fatalf("expected 3s cpu time, got %s", usage.CPUTime)
+	}
+	if usage.RSSBytes != 300*4096 {
+		t.Fatalf("expected rss %d, got %d", 300*4096, usage.RSSBytes)
+	}
+}
+
+func TestParsePSUsage(t *testing.T) {
+	usage, err := parsePSUsage("  1-02:03:04.50  2048\n")
+	if err != nil {
+		t.Fatalf("parsePSUsage: %v", err)
+	}
+	want := 26*time.Hour + 3*time.Minute + 4500*time.Millisecond
+	if usage.CPUTime != want || usage.RSSBytes != 2048*1024 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+}
+
+func TestSampleUsageSelf(t *testing.T) {
+	usage, err := SampleUsage(os.Getpid())
+	if err != nil {
+		t.Fatalf("SampleUsage: %v", err)
+	}
+	if usage.RSSBytes == 0 {
+		t.Fatalf("expected non-zero rss, got %+v", usage)
+	}
+}
+
+func TestCommandLineSelf(t *testing.T) {
+	cmdline, err := CommandLine(os.Getpid())
+	if err != nil {
+		t.Fatalf("CommandLine: %v", err)
+	}
+	if !strings.Contains(cmdline, "procutil") {
+		t.Fatalf("expected test binary in command line, got %q", cmdline)
+	}
+}
@@ -33,3 +33,11 @@ func isProcessAlive(pid int) bool {
 	defer syscall.CloseHandle(handle)
 	return true
 }
+
+func sampleUsage(pid int) (Usage, error) {
+	return Usage{}, ErrUsageUnavailable
+}
+
+func commandLine(pid int) (string, error) {
+	return "", ErrCommandLineUnavailable
+}
@@ -30,24 +30,57 @@ type QueueService interface {
 	Remove(ctx context.Context, itemID string) error
 	UpdateStatus(ctx context.Context, itemID string, status models.QueueItemStatus, errorMsg string) error
 	UpdateAttempts(ctx context.Context, itemID string, attempts int) error
+	DeadLetter(ctx context.Context, itemID string, errorMsg string) error
 }
 
 // Service implements QueueService using a QueueRepository.
 type Service struct {
-	repo   *db.QueueRepository
-	logger zerolog.Logger
+	repo          *db.QueueRepository
+	retryPolicies map[models.QueueItemType]models.RetryPolicy
+	logger        zerolog.Logger
 }
 
-// NewService creates a new QueueService.
-func NewService(repo *db.QueueRepository) *Service {
-	return &Service{
-		repo:   repo,
-		logger: logging.Component("queue"),
+// ServiceOption configures a queue Service.
+type ServiceOption func(*Service)
+
+// WithRetryPolicies sets the per-type retry policies stamped on new items
+// that do not carry their own.
+func WithRetryPolicies(policies map[models.QueueItemType]models.RetryPolicy) ServiceOption {
+	return func(s *Service) {
+		s.retryPolicies = policies
+	}
+}
+
+// NewService creates a new QueueService. Without WithRetryPolicies, new
+// items get models.DefaultRetryPolicies.
+func NewService(repo *db.QueueRepository, opts ...ServiceOption) *Service {
+	s := &Service{
+		repo:          repo,
+		retryPolicies: models.DefaultRetryPolicies(),
+		logger:        logging.Component("queue"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// stampRetryPolicy records the item type's retry policy on the item so
+// later configuration changes do not affect work already queued.
+func (s *Service) stampRetryPolicy(item *models.QueueItem) {
+	if item == nil || item.RetryPolicy != nil {
+		return
+	}
+	if policy, ok := s.retryPolicies[item.Type]; ok {
+		item.RetryPolicy = &policy
 	}
 }
 
 // Enqueue adds items to the agent queue.
 func (s *Service) Enqueue(ctx context.Context, agentID string, items ...*models.QueueItem) error {
+	for _, item := range items {
+		s.stampRetryPolicy(item)
+	}
 	if err := s.repo.Enqueue(ctx, agentID, items...); err != nil {
 		return fmt.Errorf("failed to enqueue items: %w", err)
 	}
@@ -106,6 +139,7 @@ func (s *Service) Clear(ctx context.Context, agentID string) (int, error) {
 
 // InsertAt inserts an item at a specific position.
 func (s *Service) InsertAt(ctx context.Context, agentID string, position int, item *models.QueueItem) error {
+	s.stampRetryPolicy(item)
 	if err := s.repo.InsertAt(ctx, agentID, position, item); err != nil {
 		return fmt.Errorf("failed to insert queue item: %w", err)
 	}
@@ -145,4 +179,38 @@ func (s *Service) UpdateAttempts(ctx context.Context, itemID string, attempts in
 	return nil
 }
 
+// DeadLetter moves an item that exhausted its retries to the dead-letter
+// queue.
+func (s *Service) DeadLetter(ctx context.Context, itemID string, errorMsg string) error {
+	if err := s.repo.DeadLetter(ctx, itemID, errorMsg); err != nil {
+		if errors.Is(err, db.ErrQueueItemNotFound) {
+			return ErrQueueItemNotFound
+		}
+		return fmt.Errorf("failed to dead-letter queue item: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns dead-lettered items for an agent, or for every
+// agent when agentID is empty.
+func (s *Service) ListDeadLetters(ctx context.Context, agentID string) ([]*models.QueueItem, error) {
+	items, err := s.repo.ListDeadLetters(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return items, nil
+}
+
+// Requeue moves a dead-lettered item back to the end of its agent's queue.
+func (s *Service) Requeue(ctx context.Context, itemID string) (*models.QueueItem, error) {
+	item, err := s.repo.Requeue(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, db.ErrQueueItemNotFound) {
+			return nil, ErrQueueItemNotFound
+		}
+		return nil, fmt.Errorf("failed to requeue item: %w", err)
+	}
+	return item, nil
+}
+
 var _ QueueService = (*Service)(nil)
@@ -297,3 +297,58 @@ func TestService_Dequeue_Empty(t *testing.T) {
 		t.Errorf("expected ErrQueueEmpty, got %v", err)
 	}
 }
+
+func TestService_RetryPolicyAndDeadLetters(t *testing.T) {
+	testDB, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	defer testDB.Close()
+	if err := testDB.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	service := NewService(db.NewQueueRepository(testDB), WithRetryPolicies(map[models.QueueItemType]models.RetryPolicy{
+		models.QueueItemTypeMessage: {Kind: models.RetryPolicyImmediate, MaxRetries: 2},
+	}))
+	ws := createTestWorkspace(t, testDB)
+	agent := createTestAgent(t, testDB, ws)
+	ctx := context.Background()
+
+	item := newMessageItem(t, "retry test")
+	if err := service.Enqueue(ctx, agent.ID, item); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	items, err := service.List(ctx, agent.ID)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if policy := items[0].RetryPolicy; policy == nil || policy.Kind != models.RetryPolicyImmediate || policy.MaxRetries != 2 {
+		t.Fatalf("expected immediate policy stamped on item, got %+v", policy)
+	}
+
+	if err := service.DeadLetter(ctx, item.ID, "boom"); err != nil {
+		t.Fatalf("DeadLetter failed: %v", err)
+	}
+	dead, err := service.ListDeadLetters(ctx, "")
+	if err != nil {
+		t.Fatalf("ListDeadLetters failed: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Status != models.QueueItemStatusFailed || dead[0].Error != "boom" {
+		t.Fatalf("expected one dead-lettered item, got %+v", dead)
+	}
+
+	requeued, err := service.Requeue(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	if requeued.Status != models.QueueItemStatusPending || requeued.Attempts != 0 {
+		t.Fatalf("expected requeued item pending with no attempts, got %+v", requeued)
+	}
+	if dead, _ := service.ListDeadLetters(ctx, agent.ID); len(dead) != 0 {
+		t.Fatalf("expected dead-letter queue to be empty, got %d", len(dead))
+	}
+	if _, err := service.Requeue(ctx, item.ID); !errors.Is(err, ErrQueueItemNotFound) {
+		t.Fatalf("expected requeue of a live item to fail, got %v", err)
+	}
+}
@@ -0,0 +1,476 @@
+// Package reconcile compares the loops and agents the database records as
+// running with the processes and tmux panes that actually exist, and
+// repairs the records that drifted.
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/logging"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/procutil"
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+// StaleRunnerReason is recorded as LastError on loops stopped because their
+// runner died.
+const StaleRunnerReason = "stale_runner"
+
+const (
+	loopMetadataRunnerOwnerKey    = "runner_owner"
+	loopMetadataRunnerLivenessKey = "runner_liveness"
+	loopRunnerOwnerDaemon         = "daemon"
+)
+
+// Kind classifies a reconciliation finding.
+type Kind string
+
+const (
+	// KindLoopRunnerDead is a loop recorded as active whose runner is gone.
+	// Fixed by marking the loop stopped.
+	KindLoopRunnerDead Kind = "loop_runner_dead"
+	// KindLoopRunnerOrphaned is a stopped loop whose runner process is still
+	// alive. Reported only: the process must be stopped by hand.
+	KindLoopRunnerOrphaned Kind = "loop_runner_orphaned"
+	// KindAgentPaneMoved is an agent whose recorded pane is gone but whose
+	// tagged pane still exists. Fixed by relinking the agent.
+	KindAgentPaneMoved Kind = "agent_pane_moved"
+	// KindAgentPaneMissing is an active agent with no live pane. Fixed by
+	// marking the agent errored, which leaves it eligible for recovery.
+	KindAgentPaneMissing Kind = "agent_pane_missing"
+	// KindPaneOrphaned is a tagged pane whose agent is unknown or stopped.
+	// Reported only.
+	KindPaneOrphaned Kind = "pane_orphaned"
+)
+
+// Finding is one drift between recorded and live state.
+type Finding struct {
+	Kind       Kind              `json:"kind"`
+	EntityType models.EntityType `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Name       string            `json:"name,omitempty"`
+	Details    string            `json:"details"`
+	Fixed      bool              `json:"fixed"`
+}
+
+// Report summarizes a reconciliation pass.
+type Report struct {
+	Trigger       string    `json:"trigger"`
+	Fix           bool      `json:"fix"`
+	LoopsChecked  int       `json:"loops_checked"`
+	AgentsChecked int       `json:"agents_checked"`
+	PanesChecked  int       `json:"panes_checked"`
+	Findings      []Finding `json:"findings"`
+	Fixed         int       `json:"fixed"`
+	// TmuxError is set when tmux could not be queried; agent checks are
+	// skipped in that case.
+	TmuxError string    `json:"tmux_error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Counts returns the number of findings of each kind.
+func (r *Report) Counts() map[Kind]int {
+	counts := make(map[Kind]int)
+	for _, finding := range r.Findings {
+		counts[finding.Kind]++
+	}
+	return counts
+}
+
+// RunnerLiveness reports whether a daemon-owned loop runner is alive. known
+// is false when the daemon cannot be asked.
+type RunnerLiveness func(ctx context.Context, loop *models.Loop) (alive, known bool)
+
+// Reconciler checks recorded loop and agent state against live processes and
+// tmux panes.
+type Reconciler struct {
+	db           *db.DB
+	tmux         *tmux.Client
+	publisher    events.Publisher
+	processAlive func(pid int) bool
+	commandLine  func(pid int) (string, error)
+	runnerAlive  RunnerLiveness
+	now          func() time.Time
+	logger       zerolog.Logger
+}
+
+// Option configures a Reconciler.
+type Option func(*Reconciler)
+
+// WithTmuxClient enables agent and pane checks against a tmux server.
+func WithTmuxClient(client *tmux.Client) Option {
+	return func(r *Reconciler) {
+		r.tmux = client
+	}
+}
+
+// WithPublisher publishes a system.reconciled summary event after each pass.
+func WithPublisher(publisher events.Publisher) Option {
+	return func(r *Reconciler) {
+		r.publisher = publisher
+	}
+}
+
+// WithRunnerLiveness sets how daemon-owned loop runners are checked.
+// Without it, daemon-owned loops are only reconciled by PID.
+func WithRunnerLiveness(fn RunnerLiveness) Option {
+	return func(r *Reconciler) {
+		r.runnerAlive = fn
+	}
+}
+
+// WithProcessInspector overrides the PID liveness and command line probes.
+func WithProcessInspector(alive func(pid int) bool, commandLine func(pid int) (string, error)) Option {
+	return func(r *Reconciler) {
+		if alive != nil {
+			r.processAlive = alive
+		}
+		if commandLine != nil {
+			r.commandLine = commandLine
+		}
+	}
+}
+
+// New creates a Reconciler.
+func New(database *db.DB, opts ...Option) *Reconciler {
+	r := &Reconciler{
+		db:           database,
+		processAlive: procutil.IsProcessAlive,
+		commandLine:  procutil.CommandLine,
+		now:          time.Now,
+		logger:       logging.Component("reconcile"),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run performs one reconciliation pass. trigger names the caller ("boot",
+// "doctor") in the summary event. With fix unset, nothing is changed and
+// every finding is reported as unfixed.
+func (r *Reconciler) Run(ctx context.Context, trigger string, fix bool) (*Report, error) {
+	report := &Report{
+		Trigger:   trigger,
+		Fix:       fix,
+		Findings:  []Finding{},
+		CheckedAt: r.now().UTC(),
+	}
+	if err := r.reconcileLoops(ctx, report); err != nil {
+		return nil, err
+	}
+	if r.tmux != nil {
+		if err := r.reconcileAgents(ctx, report); err != nil {
+			return nil, err
+		}
+	}
+	for _, finding := range report.Findings {
+		if finding.Fixed {
+			report.Fixed++
+		}
+	}
+	r.publishSummary(ctx, report)
+	return report, nil
+}
+
+func (r *Reconciler) reconcileLoops(ctx context.Context, report *Report) error {
+	loopRepo := db.NewLoopRepository(r.db)
+	loops, err := loopRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list loops: %w", err)
+	}
+
+	for _, loopEntry := range loops {
+		report.LoopsChecked++
+		pid, hasPID := loopPID(loopEntry)
+		pidAlive := hasPID && r.processAlive(pid)
+
+		if loopActive(loopEntry.State) {
+			if pidAlive {
+				continue
+			}
+			daemonAlive, known := false, false
+			if r.runnerAlive != nil {
+				daemonAlive, known = r.runnerAlive(ctx, loopEntry)
+			}
+			if daemonAlive {
+				continue
+			}
+			// Without a daemon to ask, a daemon-owned runner may live on
+			// without a local PID.
+			if !known && loopRunnerOwner(loopEntry) == loopRunnerOwnerDaemon {
+				continue
+			}
+
+			details := "no runner pid recorded"
+			if hasPID {
+				details = fmt.Sprintf("runner pid %d is not running", pid)
+			}
+			finding := Finding{
+				Kind:       KindLoopRunnerDead,
+				EntityType: models.EntityTypeLoop,
+				EntityID:   loopEntry.ID,
+				Name:       loopEntry.Name,
+				Details:    fmt.Sprintf("recorded %s; %s", loopEntry.State, details),
+			}
+			if report.Fix {
+				if err := MarkLoopStale(ctx, loopRepo, loopEntry, pidAlive, daemonAlive); err != nil {
+					return fmt.Errorf("mark loop %s stale: %w", loopEntry.ID, err)
+				}
+				finding.Fixed = true
+			}
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+
+		// The runner PID stays in metadata after a loop stops, so a live PID
+		// only counts when the process still looks like a forge runner.
+		if pidAlive && r.looksLikeRunner(pid) {
+			report.Findings = append(report.Findings, Finding{
+				Kind:       KindLoopRunnerOrphaned,
+				EntityType: models.EntityTypeLoop,
+				EntityID:   loopEntry.ID,
+				Name:       loopEntry.Name,
+				Details:    fmt.Sprintf("recorded %s but runner pid %d is still running", loopEntry.State, pid),
+			})
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) looksLikeRunner(pid int) bool {
+	cmdline, err := r.commandLine(pid)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(cmdline, "forge")
+}
+
+func (r *Reconciler) reconcileAgents(ctx context.Context, report *Report) error {
+	panes, err := r.tmux.ListAllPanes(ctx)
+	if err != nil {
+		report.TmuxError = err.Error()
+		r.logger.Warn().Err(err).Msg("skipping agent reconciliation")
+		return nil
+	}
+	report.PanesChecked = len(panes)
+
+	live := make(map[string]struct{}, len(panes)*2)
+	tagged := make(map[string]tmux.TaggedPane)
+	for _, pane := range panes {
+		live[pane.ID] = struct{}{}
+		live[fmt.Sprintf("%s:%d.%d", pane.Session, pane.WindowIndex, pane.Index)] = struct{}{}
+		if pane.AgentID != "" {
+			tagged[pane.AgentID] = pane
+		}
+	}
+
+	local, err := r.localWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	agentRepo := db.NewAgentRepository(r.db)
+	agents, err := agentRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list agents: %w", err)
+	}
+
+	known := make(map[string]*models.Agent, len(agents))
+	for _, agent := range agents {
+		known[agent.ID] = agent
+		if _, ok := local[agent.WorkspaceID]; !ok {
+			continue
+		}
+		report.AgentsChecked++
+		if agent.State == models.AgentStateStopped || strings.TrimSpace(agent.TmuxPane) == "" {
+			continue
+		}
+		if _, ok := live[agent.TmuxPane]; ok {
+			continue
+		}
+
+		if pane, ok := tagged[agent.ID]; ok {
+			finding := Finding{
+				Kind:       KindAgentPaneMoved,
+				EntityType: models.EntityTypeAgent,
+				EntityID:   agent.ID,
+				Details:    fmt.Sprintf("pane %s is gone; tagged pane %s in session %s", agent.TmuxPane, pane.ID, pane.Session),
+			}
+			if report.Fix {
+				agent.TmuxPane = pane.ID
+				if err := agentRepo.Update(ctx, agent); err != nil {
+					return fmt.Errorf("relink agent %s: %w", agent.ID, err)
+				}
+				finding.Fixed = true
+			}
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+
+		finding := Finding{
+			Kind:       KindAgentPaneMissing,
+			EntityType: models.EntityTypeAgent,
+			EntityID:   agent.ID,
+			Details:    fmt.Sprintf("recorded %s but pane %s is gone", agent.State, agent.TmuxPane),
+		}
+		if report.Fix {
+			if err := r.markAgentPaneMissing(ctx, agentRepo, agent); err != nil {
+				return fmt.Errorf("mark agent %s: %w", agent.ID, err)
+			}
+			finding.Fixed = true
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	for agentID, pane := range tagged {
+		agent, ok := known[agentID]
+		if ok && agent.State != models.AgentStateStopped {
+			continue
+		}
+		details := "tagged with an unknown agent"
+		if ok {
+			details = "tagged with a stopped agent"
+		}
+		report.Findings = append(report.Findings, Finding{
+			Kind:       KindPaneOrphaned,
+			EntityType: models.EntityTypeAgent,
+			EntityID:   agentID,
+			Details:    fmt.Sprintf("pane %s in session %s is %s", pane.ID, pane.Session, details),
+		})
+	}
+	return nil
+}
+
+// localWorkspaces returns the IDs of workspaces on local nodes; agents on
+// remote nodes are not visible to the local tmux server.
+func (r *Reconciler) localWorkspaces(ctx context.Context) (map[string]struct{}, error) {
+	nodes, err := db.NewNodeRepository(r.db).List(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	localNodes := make(map[string]struct{})
+	for _, node := range nodes {
+		if node.IsLocal {
+			localNodes[node.ID] = struct{}{}
+		}
+	}
+	workspaces, err := db.NewWorkspaceRepository(r.db).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	local := make(map[string]struct{})
+	for _, ws := range workspaces {
+		if _, ok := localNodes[ws.NodeID]; ok {
+			local[ws.ID] = struct{}{}
+		}
+	}
+	return local, nil
+}
+
+func (r *Reconciler) markAgentPaneMissing(ctx context.Context, repo *db.AgentRepository, agent *models.Agent) error {
+	now := r.now().UTC()
+	agent.State = models.AgentStateError
+	agent.StateInfo = models.StateInfo{
+		State:      models.AgentStateError,
+		Confidence: models.StateConfidenceHigh,
+		Reason:     fmt.Sprintf("tmux pane %s not found during reconciliation", agent.TmuxPane),
+		DetectedAt: now,
+	}
+	return repo.Update(ctx, agent)
+}
+
+// MarkLoopStale stops a loop whose runner is gone, recording what was
+// observed under the runner_liveness metadata key.
+func MarkLoopStale(ctx context.Context, repo *db.LoopRepository, loopEntry *models.Loop, pidAlive, daemonAlive bool) error {
+	if loopEntry == nil {
+		return nil
+	}
+
+	loopEntry.State = models.LoopStateStopped
+	loopEntry.LastError = StaleRunnerReason
+	if loopEntry.Metadata == nil {
+		loopEntry.Metadata = make(map[string]any)
+	}
+	loopEntry.Metadata[loopMetadataRunnerLivenessKey] = map[string]any{
+		"pid_alive":           pidAlive,
+		"daemon_runner_alive": daemonAlive,
+		"reconciled_at":       time.Now().UTC().Format(time.RFC3339),
+		"reason":              StaleRunnerReason,
+	}
+	return repo.Update(ctx, loopEntry)
+}
+
+// loopPID returns the runner PID recorded in a loop's metadata.
+func loopPID(loopEntry *models.Loop) (int, bool) {
+	if loopEntry == nil || loopEntry.Metadata == nil {
+		return 0, false
+	}
+	switch v := loopEntry.Metadata["pid"].(type) {
+	case int:
+		return v, v > 0
+	case int64:
+		return int(v), v > 0
+	case float64:
+		return int(v), v > 0
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		return parsed, err == nil && parsed > 0
+	}
+	return 0, false
+}
+
+func loopRunnerOwner(loopEntry *models.Loop) string {
+	if loopEntry.Metadata == nil {
+		return ""
+	}
+	owner, _ := loopEntry.Metadata[loopMetadataRunnerOwnerKey].(string)
+	return strings.TrimSpace(owner)
+}
+
+func loopActive(state models.LoopState) bool {
+	switch state {
+	case models.LoopStateRunning, models.LoopStateSleeping, models.LoopStateWaiting:
+		return true
+	}
+	return false
+}
+
+func (r *Reconciler) publishSummary(ctx context.Context, report *Report) {
+	if r.publisher == nil {
+		return
+	}
+
+	kinds := make(map[string]int)
+	for kind, count := range report.Counts() {
+		kinds[string(kind)] = count
+	}
+	payload, err := json.Marshal(models.ReconcilePayload{
+		Trigger:       report.Trigger,
+		Fix:           report.Fix,
+		LoopsChecked:  report.LoopsChecked,
+		AgentsChecked: report.AgentsChecked,
+		PanesChecked:  report.PanesChecked,
+		Findings:      len(report.Findings),
+		Fixed:         report.Fixed,
+		Kinds:         kinds,
+	})
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("failed to encode reconcile payload")
+		return
+	}
+	r.publisher.Publish(ctx, &models.Event{
+		Type:       models.EventTypeReconciled,
+		EntityType: models.EntityTypeSystem,
+		EntityID:   "reconcile",
+		Payload:    payload,
+	})
+}
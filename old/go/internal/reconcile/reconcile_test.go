@@ -0,0 +1,198 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/testutil"
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+type paneExecutor struct {
+	output string
+}
+
+func (e *paneExecutor) Exec(ctx context.Context, cmd string) ([]byte, []byte, error) {
+	if strings.HasPrefix(cmd, "tmux list-panes -a") {
+		return []byte(e.output), nil, nil
+	}
+	return nil, nil, nil
+}
+
+func createLoop(t *testing.T, database *db.DB, name string, state models.LoopState, metadata map[string]any) *models.Loop {
+	t.Helper()
+	loopEntry := &models.Loop{
+		Name:            name,
+		RepoPath:        t.TempDir(),
+		IntervalSeconds: 10,
+		State:           state,
+		Metadata:        metadata,
+	}
+	if err := db.NewLoopRepository(database).Create(context.Background(), loopEntry); err != nil {
+		t.Fatalf("create loop: %v", err)
+	}
+	return loopEntry
+}
+
+func TestRunMarksDeadLoopRunnerStopped(t *testing.T) {
+	ctx := context.Background()
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	dead := createLoop(t, database, "dead", models.LoopStateRunning, map[string]any{"pid": 4242})
+	createLoop(t, database, "alive", models.LoopStateRunning, map[string]any{"pid": 4343})
+	createLoop(t, database, "daemon", models.LoopStateRunning, map[string]any{"runner_owner": "daemon"})
+
+	publisher := events.NewInMemoryPublisher()
+	var published []*models.Event
+	if err := publisher.Subscribe("test", events.Filter{}, func(event *models.Event) {
+		published = append(published, event)
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	reconciler := New(database,
+		WithPublisher(publisher),
+		WithProcessInspector(func(pid int) bool { return pid == 4343 }, nil),
+	)
+
+	report, err := reconciler.Run(ctx, "doctor", false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Kind != KindLoopRunnerDead || report.Findings[0].EntityID != dead.ID || report.Findings[0].Fixed {
+		t.Fatalf("expected one unfixed dead runner finding, got %+v", report.Findings)
+	}
+	loopRepo := db.NewLoopRepository(database)
+	got, err := loopRepo.Get(ctx, dead.ID)
+	if err != nil {
+		t.Fatalf("get loop: %v", err)
+	}
+	if got.State != models.LoopStateRunning {
+		t.Fatalf("expected report-only pass to leave state, got %s", got.State)
+	}
+
+	report, err = reconciler.Run(ctx, "boot", true)
+	if err != nil {
+		t.Fatalf("Run fix: %v", err)
+	}
+	if report.Fixed != 1 {
+		t.Fatalf("expected one fix, got %+v", report)
+	}
+	got, err = loopRepo.Get(ctx, dead.ID)
+	if err != nil {
+		t.Fatalf("get loop: %v", err)
+	}
+	if got.State != models.LoopStateStopped || got.LastError != StaleRunnerReason {
+		t.Fatalf("expected stale loop stopped, got state=%s last_error=%q", got.State, got.LastError)
+	}
+
+	if len(published) != 2 || published[1].Type != models.EventTypeReconciled {
+		t.Fatalf("expected a summary event per pass, got %+v", published)
+	}
+	var payload models.ReconcilePayload
+	if err := json.Unmarshal(published[1].Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Trigger != "boot" || payload.Fixed != 1 || payload.LoopsChecked != 3 || payload.Kinds[string(KindLoopRunnerDead)] != 1 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestRunReportsOrphanedLoopRunner(t *testing.T) {
+	ctx := context.Background()
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	orphan := createLoop(t, database, "orphan", models.LoopStateStopped, map[string]any{"pid": 100})
+	createLoop(t, database, "reused", models.LoopStateStopped, map[string]any{"pid": 200})
+
+	reconciler := New(database, WithProcessInspector(
+		func(pid int) bool { return true },
+		func(pid int) (string, error) {
+			if pid == 100 {
+				return "/usr/local/bin/forge loop run " + orphan.ID, nil
+			}
+			return "/bin/bash", nil
+		},
+	))
+	report, err := reconciler.Run(ctx, "doctor", true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Kind != KindLoopRunnerOrphaned || report.Findings[0].EntityID != orphan.ID {
+		t.Fatalf("expected one orphaned runner finding, got %+v", report.Findings)
+	}
+	if report.Findings[0].Fixed {
+		t.Fatalf("orphaned runners are report-only")
+	}
+}
+
+func TestRunReconcilesAgentPanes(t *testing.T) {
+	ctx := context.Background()
+	database, cleanup := testutil.NewTestDB(t)
+	defer cleanup()
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{Name: "local", SSHBackend: models.SSHBackendAuto, Status: models.NodeStatusUnknown, IsLocal: true}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	ws := &models.Workspace{Name: "demo", NodeID: localNode.ID, RepoPath: "/repo/demo", TmuxSession: "forge-demo", Status: models.WorkspaceStatusActive}
+	if err := db.NewWorkspaceRepository(database).Create(ctx, ws); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	agentRepo := db.NewAgentRepository(database)
+	newAgent := func(pane string) *models.Agent {
+		agent := &models.Agent{
+			WorkspaceID: ws.ID,
+			Type:        models.AgentTypeClaudeCode,
+			TmuxPane:    pane,
+			State:       models.AgentStateIdle,
+			StateInfo:   models.StateInfo{State: models.AgentStateIdle, Confidence: models.StateConfidenceHigh},
+		}
+		if err := agentRepo.Create(ctx, agent); err != nil {
+			t.Fatalf("create agent: %v", err)
+		}
+		return agent
+	}
+	healthy := newAgent("%1")
+	moved := newAgent("%3")
+	missing := newAgent("forge-demo:0.4")
+
+	exec := &paneExecutor{output: healthy.ID + "|forge-demo|%1|0|0|/repo/demo|1|claude\n" +
+		moved.ID + "|forge-demo|%8|1|1|/repo/demo|0|claude\n" +
+		"ghost|forge-demo|%9|1|2|/repo/demo|0|claude\n"}
+	reconciler := New(database, WithTmuxClient(tmux.NewClient(exec)))
+
+	report, err := reconciler.Run(ctx, "boot", true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	counts := report.Counts()
+	if counts[KindAgentPaneMoved] != 1 || counts[KindAgentPaneMissing] != 1 || counts[KindPaneOrphaned] != 1 || len(report.Findings) != 3 {
+		t.Fatalf("unexpected findings %+v", report.Findings)
+	}
+	if report.AgentsChecked != 3 || report.PanesChecked != 3 || report.Fixed != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	got, err := agentRepo.Get(ctx, moved.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if got.TmuxPane != "%8" {
+		t.Fatalf("expected moved agent relinked to %%8, got %q", got.TmuxPane)
+	}
+	got, err = agentRepo.Get(ctx, missing.ID)
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if got.State != models.AgentStateError {
+		t.Fatalf("expected missing agent errored, got %s", got.State)
+	}
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// tickPressure summarizes the work observed during a tick.
+type tickPressure struct {
+	// PendingAgents is the number of dispatchable agents with queued items.
+	PendingAgents int
+
+	// NextDeadline is the time until the earliest retry backoff or
+	// auto-resume expires. Zero means no deadline is pending.
+	NextDeadline time.Duration
+}
+
+// idle reports whether the tick found nothing to do soon.
+func (p tickPressure) idle() bool {
+	return p.PendingAgents == 0 && p.NextDeadline <= 0
+}
+
+// nextTickInterval computes the effective tick interval after a tick.
+//
+// Pending work shortens the interval towards MinTickInterval, an approaching
+// deadline wakes the scheduler just in time, and idle ticks back off
+// exponentially until MaxTickInterval.
+func nextTickInterval(cfg Config, current time.Duration, pressure tickPressure) time.Duration {
+	base := cfg.TickInterval
+	minInterval := cfg.MinTickInterval
+	maxInterval := cfg.MaxTickInterval
+	if minInterval <= 0 || minInterval > base {
+		minInterval = base
+	}
+	if maxInterval < base {
+		maxInterval = base
+	}
+	if current <= 0 {
+		current = base
+	}
+
+	if pressure.idle() {
+		next := current * 2
+		if next < base {
+			next = base
+		}
+		if next > maxInterval {
+			next = maxInterval
+		}
+		return next
+	}
+
+	next := base
+	if pressure.PendingAgents > 0 {
+		next = base / time.Duration(pressure.PendingAgents+1)
+	}
+	if pressure.NextDeadline > 0 && pressure.NextDeadline < next {
+		next = pressure.NextDeadline
+	}
+	if next < minInterval {
+		next = minInterval
+	}
+	return next
+}
+
+// measurePressure derives tick pressure from the agent list and the
+// scheduler's retry/pause bookkeeping.
+func (s *Scheduler) measurePressure(agents []*models.Agent, now time.Time) tickPressure {
+	pressure := tickPressure{}
+	var deadline time.Time
+	consider := func(at time.Time) {
+		if !at.After(now) {
+			return
+		}
+		if deadline.IsZero() || at.Before(deadline) {
+			deadline = at
+		}
+	}
+
+	for _, a := range agents {
+		if a == nil {
+			continue
+		}
+		if a.QueueLength > 0 && a.State != models.AgentStatePaused && !s.IsAgentPaused(a.ID) {
+			pressure.PendingAgents++
+		}
+		if a.PausedUntil != nil {
+			consider(*a.PausedUntil)
+		}
+	}
+
+	s.mu.RLock()
+	for _, until := range s.retryAfter {
+		consider(until)
+	}
+	s.mu.RUnlock()
+
+	if !deadline.IsZero() {
+		pressure.NextDeadline = deadline.Sub(now)
+	}
+	return pressure
+}
+
+// setEffectiveTickInterval records the interval used for the next tick.
+func (s *Scheduler) setEffectiveTickInterval(interval time.Duration) {
+	s.statsMu.Lock()
+	s.stats.TickInterval = interval
+	s.statsMu.Unlock()
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestNextTickIntervalIdleBacksOff(t *testing.T) {
+	cfg := Config{TickInterval: time.Second, MinTickInterval: 250 * time.Millisecond, MaxTickInterval: 5 * time.Second}
+
+	interval := cfg.TickInterval
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		interval = nextTickInterval(cfg, interval, tickPressure{})
+		if interval != expected {
+			t.Fatalf("step %d: expected %v, got %v", i, expected, interval)
+		}
+	}
+}
+
+func TestNextTickIntervalPendingShortens(t *testing.T) {
+	cfg := Config{TickInterval: time.Second, MinTickInterval: 250 * time.Millisecond, MaxTickInterval: 5 * time.Second}
+
+	if got := nextTickInterval(cfg, 4*time.Second, tickPressure{PendingAgents: 1}); got != 500*time.Millisecond {
+		t.Fatalf("expected 500ms with one pending agent, got %v", got)
+	}
+	if got := nextTickInterval(cfg, time.Second, tickPressure{PendingAgents: 10}); got != 250*time.Millisecond {
+		t.Fatalf("expected clamp to min interval, got %v", got)
+	}
+}
+
+func TestNextTickIntervalDeadline(t *testing.T) {
+	cfg := Config{TickInterval: time.Second, MinTickInterval: 100 * time.Millisecond, MaxTickInterval: 5 * time.Second}
+
+	if got := nextTickInterval(cfg, 5*time.Second, tickPressure{NextDeadline: 300 * time.Millisecond}); got != 300*time.Millisecond {
+		t.Fatalf("expected wake at deadline, got %v", got)
+	}
+	if got := nextTickInterval(cfg, 5*time.Second, tickPressure{NextDeadline: 30 * time.Second}); got != time.Second {
+		t.Fatalf("expected base interval for distant deadline, got %v", got)
+	}
+}
+
+func TestNextTickIntervalFixedWhenUnconfigured(t *testing.T) {
+	cfg := Config{TickInterval: 100 * time.Millisecond}
+
+	if got := nextTickInterval(cfg, cfg.TickInterval, tickPressure{}); got != cfg.TickInterval {
+		t.Fatalf("expected fixed interval when idle, got %v", got)
+	}
+	if got := nextTickInterval(cfg, cfg.TickInterval, tickPressure{PendingAgents: 3}); got != cfg.TickInterval {
+		t.Fatalf("expected fixed interval under pressure, got %v", got)
+	}
+}
+
+func TestMeasurePressure(t *testing.T) {
+	sched := New(DefaultConfig(), nil, nil, nil, nil)
+	now := time.Now().UTC()
+	resumeAt := now.Add(2 * time.Second)
+	sched.setRetryAfter("agent-retry", now.Add(500*time.Millisecond))
+	_ = sched.PauseAgent("agent-held")
+
+	pressure := sched.measurePressure([]*models.Agent{
+		{ID: "agent-busy", State: models.AgentStateWorking, QueueLength: 2},
+		{ID: "agent-idle", State: models.AgentStateIdle},
+		{ID: "agent-held", State: models.AgentStateIdle, QueueLength: 1},
+		{ID: "agent-paused", State: models.AgentStatePaused, QueueLength: 1, PausedUntil: &resumeAt},
+	}, now)
+
+	if pressure.PendingAgents != 1 {
+		t.Fatalf("expected 1 pending agent, got %d", pressure.PendingAgents)
+	}
+	if pressure.NextDeadline != 500*time.Millisecond {
+		t.Fatalf("expected retry deadline of 500ms, got %v", pressure.NextDeadline)
+	}
+}
+
+func TestConfigFromSettingsAdaptiveBounds(t *testing.T) {
+	cfg := ConfigFromSettings(config.SchedulerConfig{
+		DispatchInterval:    2 * time.Second,
+		MinDispatchInterval: 500 * time.Millisecond,
+		MaxDispatchInterval: 30 * time.Second,
+		MaxRetries:          5,
+	})
+
+	if cfg.TickInterval != 2*time.Second || cfg.MinTickInterval != 500*time.Millisecond || cfg.MaxTickInterval != 30*time.Second {
+		t.Fatalf("unexpected tick bounds: %v/%v/%v", cfg.TickInterval, cfg.MinTickInterval, cfg.MaxTickInterval)
+	}
+	if message := cfg.RetryPolicies[models.QueueItemTypeMessage]; message.MaxRetries != 5 || message.Backoff != 5*time.Second {
+		t.Fatalf("expected MaxRetries 5 with default backoff, got %+v", message)
+	}
+	if pause := cfg.RetryPolicies[models.QueueItemTypePause]; pause.Kind != models.RetryPolicyNone {
+		t.Fatalf("expected default pause policy, got %+v", pause)
+	}
+}
+
+func TestConfigFromSettingsRetryPolicies(t *testing.T) {
+	cfg := ConfigFromSettings(config.SchedulerConfig{
+		MaxRetries: 2,
+		RetryPolicies: map[string]config.RetryPolicyConfig{
+			"message": {Policy: "exponential", MaxRetries: 5},
+		},
+	})
+	if message := cfg.RetryPolicies[models.QueueItemTypeMessage]; message.MaxRetries != 5 || message.Backoff != 5*time.Second {
+		t.Fatalf("expected message policy with 5 retries and default backoff, got %+v", message)
+	}
+	if conditional := cfg.RetryPolicies[models.QueueItemTypeConditional]; conditional.MaxRetries != 2 {
+		t.Fatalf("expected legacy MaxRetries for unlisted conditional, got %+v", conditional)
+	}
+}
+
+func TestConfigFromSettingsClampsMinimumToDispatchInterval(t *testing.T) {
+	cfg := ConfigFromSettings(config.SchedulerConfig{
+		DispatchInterval:    200 * time.Millisecond,
+		MinDispatchInterval: 250 * time.Millisecond,
+	})
+	if cfg.MinTickInterval != 200*time.Millisecond {
+		t.Fatalf("expected min tick interval clamped to 200ms, got %v", cfg.MinTickInterval)
+	}
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// deadlineNotice records which deadline events were emitted for a queue item.
+type deadlineNotice struct {
+	agentID string
+	warned  bool
+	missed  bool
+}
+
+// deadlineScan is the result of inspecting queue heads for deadlines.
+type deadlineScan struct {
+	// urgent maps agent IDs to the deadline of a queue head that is inside
+	// the warning window or already past.
+	urgent map[string]time.Time
+
+	// wake is the next time a head enters the window or misses its
+	// deadline. Zero means nothing is pending.
+	wake time.Time
+}
+
+// scanDeadlines peeks the queue head of every backlogged agent, emits
+// queue.deadline_warning once per item as it enters the warning window and
+// queue.deadline_missed once when the deadline passes while the item is
+// still waiting. Only queue heads are judged, since only they can be
+// dispatched next.
+func (s *Scheduler) scanDeadlines(ctx context.Context, backlogged []*models.Agent, now time.Time) deadlineScan {
+	scan := deadlineScan{}
+	window := s.config.DeadlineWarning
+	if window <= 0 || s.queueService == nil {
+		return scan
+	}
+
+	type pendingEvent struct {
+		eventType models.EventType
+		payload   models.QueueDeadlinePayload
+	}
+	var pending []pendingEvent
+	wakeAt := func(at time.Time) {
+		if at.After(now) && (scan.wake.IsZero() || at.Before(scan.wake)) {
+			scan.wake = at
+		}
+	}
+
+	type urgentHead struct {
+		agentID string
+		item    *models.QueueItem
+	}
+	active := make(map[string]struct{}, len(backlogged))
+	var heads []urgentHead
+	for _, a := range backlogged {
+		active[a.ID] = struct{}{}
+		item := s.peekQueueHead(ctx, a.ID)
+		if item == nil || item.Deadline == nil {
+			continue
+		}
+		deadline := *item.Deadline
+		wakeAt(deadline.Add(-window))
+		wakeAt(deadline)
+		if now.Before(deadline.Add(-window)) {
+			continue
+		}
+		if scan.urgent == nil {
+			scan.urgent = make(map[string]time.Time)
+		}
+		scan.urgent[a.ID] = deadline
+		heads = append(heads, urgentHead{a.ID, item})
+	}
+
+	s.mu.Lock()
+	for _, head := range heads {
+		item, deadline := head.item, *head.item.Deadline
+		notice := s.deadlineNotices[item.ID]
+		if notice == nil {
+			notice = &deadlineNotice{agentID: head.agentID}
+			s.deadlineNotices[item.ID] = notice
+		}
+		payload := models.QueueDeadlinePayload{
+			QueueItemID: item.ID,
+			ItemType:    item.Type,
+			AgentID:     head.agentID,
+			Deadline:    deadline,
+		}
+		if now.Before(deadline) && !notice.warned {
+			notice.warned = true
+			pending = append(pending, pendingEvent{models.EventTypeQueueDeadlineWarning, payload})
+		}
+		if !now.Before(deadline) && !notice.missed {
+			notice.missed = true
+			pending = append(pending, pendingEvent{models.EventTypeQueueDeadlineMissed, payload})
+		}
+	}
+	// Forget items whose agent has drained its queue.
+	for itemID, notice := range s.deadlineNotices {
+		if _, ok := active[notice.agentID]; !ok {
+			delete(s.deadlineNotices, itemID)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, event := range pending {
+		if event.eventType == models.EventTypeQueueDeadlineMissed {
+			s.logger.Warn().
+				Str("agent_id", event.payload.AgentID).
+				Str("item_id", event.payload.QueueItemID).
+				Time("deadline", event.payload.Deadline).
+				Msg("queue item missed its deadline")
+		}
+		s.publishEvent(ctx, event.eventType, models.EntityTypeQueue, event.payload.QueueItemID, event.payload)
+	}
+	return scan
+}
+
+// prioritize moves agents with urgent queue heads ahead of the strategy
+// order, earliest deadline first. The relative order of the remaining
+// agents is kept.
+func (scan deadlineScan) prioritize(ordered []*models.Agent) []*models.Agent {
+	if len(scan.urgent) == 0 {
+		return ordered
+	}
+	result := make([]*models.Agent, 0, len(ordered))
+	var rest []*models.Agent
+	for _, a := range ordered {
+		if _, ok := scan.urgent[a.ID]; ok {
+			result = append(result, a)
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return scan.urgent[result[i].ID].Before(scan.urgent[result[j].ID])
+	})
+	return append(result, rest...)
+}
+
+// applyTo shortens the pressure deadline so the adaptive tick wakes when
+// the next queue head enters its warning window or misses its deadline.
+func (scan deadlineScan) applyTo(pressure tickPressure, now time.Time) tickPressure {
+	if scan.wake.IsZero() {
+		return pressure
+	}
+	until := scan.wake.Sub(now)
+	if until > 0 && (pressure.NextDeadline <= 0 || until < pressure.NextDeadline) {
+		pressure.NextDeadline = until
+	}
+	return pressure
+}
+
+// noteLateDispatch reports an item that was dequeued after its deadline.
+// The queue repository has already persisted deadline_missed on the item.
+func (s *Scheduler) noteLateDispatch(ctx context.Context, agentID string, item *models.QueueItem) {
+	s.mu.Lock()
+	notice := s.deadlineNotices[item.ID]
+	delete(s.deadlineNotices, item.ID)
+	s.mu.Unlock()
+
+	if !item.DeadlineMissed || item.Deadline == nil {
+		return
+	}
+	s.logger.Warn().
+		Str("agent_id", agentID).
+		Str("item_id", item.ID).
+		Time("deadline", *item.Deadline).
+		Msg("dispatching queue item past its deadline")
+	if notice != nil && notice.missed {
+		return
+	}
+	s.publishEvent(ctx, models.EventTypeQueueDeadlineMissed, models.EntityTypeQueue, item.ID, models.QueueDeadlinePayload{
+		QueueItemID: item.ID,
+		ItemType:    item.Type,
+		AgentID:     agentID,
+		Deadline:    *item.Deadline,
+		Dispatched:  true,
+	})
+}
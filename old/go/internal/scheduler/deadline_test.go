@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func recordDeadlineEvents(t *testing.T, publisher *events.InMemoryPublisher) *[]models.EventType {
+	t.Helper()
+	var got []models.EventType
+	filter := events.Filter{EventTypes: []models.EventType{
+		models.EventTypeQueueDeadlineWarning,
+		models.EventTypeQueueDeadlineMissed,
+	}}
+	if err := publisher.Subscribe("deadline-test", filter, func(event *models.Event) {
+		got = append(got, event.Type)
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	return &got
+}
+
+func TestScanDeadlinesPrioritizesAndWarnsOnce(t *testing.T) {
+	now := time.Now().UTC()
+	soon := now.Add(2 * time.Minute)
+	later := now.Add(time.Hour)
+	past := now.Add(-time.Minute)
+
+	queueSvc := newMockQueueService()
+	_ = queueSvc.Enqueue(context.Background(), "a1", &models.QueueItem{ID: "plain", Type: models.QueueItemTypeMessage})
+	_ = queueSvc.Enqueue(context.Background(), "b1", &models.QueueItem{ID: "later", Type: models.QueueItemTypeMessage, Deadline: &later})
+	_ = queueSvc.Enqueue(context.Background(), "c1", &models.QueueItem{ID: "soon", Type: models.QueueItemTypeMessage, Deadline: &soon})
+	_ = queueSvc.Enqueue(context.Background(), "d1", &models.QueueItem{ID: "past", Type: models.QueueItemTypeMessage, Deadline: &past})
+
+	publisher := events.NewInMemoryPublisher()
+	got := recordDeadlineEvents(t, publisher)
+	sched := New(DefaultConfig(), nil, queueSvc, nil, nil, WithPublisher(publisher))
+
+	agents := agentsFor("ws/a1", "ws/b1", "ws/c1", "ws/d1")
+	scan := sched.scanDeadlines(context.Background(), agents, now)
+
+	if order := agentIDs(scan.prioritize(agents)); order != "d1,c1,a1,b1" {
+		t.Fatalf("expected urgent agents first by deadline, got %s", order)
+	}
+	if !scan.wake.Equal(soon) {
+		t.Fatalf("expected wake at the soonest deadline %v, got %v", soon, scan.wake)
+	}
+	if len(*got) != 2 || (*got)[0] == (*got)[1] {
+		t.Fatalf("expected one warning and one missed event, got %v", *got)
+	}
+
+	sched.scanDeadlines(context.Background(), agents, now)
+	if len(*got) != 2 {
+		t.Fatalf("expected events to be emitted once, got %v", *got)
+	}
+
+	// A missed item that was already reported is not reported again at dispatch.
+	item, _ := queueSvc.Dequeue(context.Background(), "d1")
+	item.DeadlineMissed = true
+	sched.noteLateDispatch(context.Background(), "d1", item)
+	if len(*got) != 2 {
+		t.Fatalf("expected no duplicate missed event, got %v", *got)
+	}
+	if _, ok := sched.deadlineNotices["past"]; ok {
+		t.Fatalf("expected dispatched item to be forgotten")
+	}
+}
+
+func TestNoteLateDispatchPublishesMissed(t *testing.T) {
+	deadline := time.Now().UTC().Add(-time.Minute)
+	publisher := events.NewInMemoryPublisher()
+	got := recordDeadlineEvents(t, publisher)
+	sched := New(DefaultConfig(), nil, newMockQueueService(), nil, nil, WithPublisher(publisher))
+
+	sched.noteLateDispatch(context.Background(), "a1", &models.QueueItem{ID: "on-time", Deadline: &deadline})
+	sched.noteLateDispatch(context.Background(), "a1", &models.QueueItem{ID: "late", Deadline: &deadline, DeadlineMissed: true})
+
+	if len(*got) != 1 || (*got)[0] != models.EventTypeQueueDeadlineMissed {
+		t.Fatalf("expected one missed event, got %v", *got)
+	}
+}
+
+func TestScanDeadlinesDisabled(t *testing.T) {
+	now := time.Now().UTC()
+	soon := now.Add(time.Minute)
+	queueSvc := newMockQueueService()
+	_ = queueSvc.Enqueue(context.Background(), "a1", &models.QueueItem{ID: "soon", Deadline: &soon})
+
+	cfg := DefaultConfig()
+	cfg.DeadlineWarning = 0
+	sched := New(cfg, nil, queueSvc, nil, nil)
+
+	scan := sched.scanDeadlines(context.Background(), agentsFor("ws/a1"), now)
+	if len(scan.urgent) != 0 || !scan.wake.IsZero() {
+		t.Fatalf("expected no deadline handling when disabled, got %+v", scan)
+	}
+}
+
+func TestDeadlineScanWakesTick(t *testing.T) {
+	now := time.Now().UTC()
+	scan := deadlineScan{wake: now.Add(30 * time.Second)}
+
+	pressure := scan.applyTo(tickPressure{NextDeadline: time.Minute}, now)
+	if pressure.NextDeadline != 30*time.Second {
+		t.Fatalf("expected deadline wake to shorten pressure, got %v", pressure.NextDeadline)
+	}
+	pressure = scan.applyTo(tickPressure{NextDeadline: 10 * time.Second}, now)
+	if pressure.NextDeadline != 10*time.Second {
+		t.Fatalf("expected earlier pressure deadline to win, got %v", pressure.NextDeadline)
+	}
+}
+
+func TestQueueItemDeadlinePrefersExplicitDeadline(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	deadline := created.Add(time.Hour)
+	if got := queueItemDeadline(&models.QueueItem{CreatedAt: created}); !got.Equal(created) {
+		t.Fatalf("expected enqueue time without deadline, got %v", got)
+	}
+	if got := queueItemDeadline(&models.QueueItem{CreatedAt: created, Deadline: &deadline}); !got.Equal(deadline) {
+		t.Fatalf("expected explicit deadline, got %v", got)
+	}
+}
@@ -144,6 +144,10 @@ func (m *trackingQueueService) UpdateAttempts(ctx context.Context, itemID string
 	return nil
 }
 
+func (m *trackingQueueService) DeadLetter(ctx context.Context, itemID string, errorMsg string) error {
+	return m.UpdateStatus(ctx, itemID, models.QueueItemStatusFailed, errorMsg)
+}
+
 func (m *trackingQueueService) queueLength(agentID string) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -298,7 +302,7 @@ func TestScheduler_DispatchToAgent_MessageItemSends(t *testing.T) {
 	sched := New(DefaultConfig(), agentSvc, queueSvc, nil, nil)
 	sched.ctx = context.Background()
 
-	sched.dispatchToAgent(agentID)
+	sched.dispatchToAgent(sched.ctx, agentID)
 
 	if got := queueSvc.queueLength(agentID); got != 0 {
 		t.Fatalf("expected queue to be empty, got %d", got)
@@ -329,7 +333,7 @@ func TestScheduler_DispatchToAgent_PauseItemPausesAgent(t *testing.T) {
 	sched := New(DefaultConfig(), agentSvc, queueSvc, nil, nil)
 	sched.ctx = context.Background()
 
-	sched.dispatchToAgent(agentID)
+	sched.dispatchToAgent(sched.ctx, agentID)
 
 	agentModel, err := agentSvc.GetAgent(context.Background(), agentID)
 	if err != nil {
@@ -363,7 +367,7 @@ func TestScheduler_DispatchConditional_RequeuesWhenNotMet(t *testing.T) {
 	sched := New(DefaultConfig(), agentSvc, queueSvc, nil, nil)
 	sched.ctx = context.Background()
 
-	sched.dispatchToAgent(agentID)
+	sched.dispatchToAgent(sched.ctx, agentID)
 
 	if got := queueSvc.queueLength(agentID); got != 1 {
 		t.Fatalf("expected conditional item to be re-queued, got %d", got)
@@ -400,7 +404,7 @@ func TestScheduler_DispatchConditional_SkipsAfterMaxEvaluations(t *testing.T) {
 	sched := New(DefaultConfig(), agentSvc, queueSvc, nil, nil)
 	sched.ctx = context.Background()
 
-	sched.dispatchToAgent(agentID)
+	sched.dispatchToAgent(sched.ctx, agentID)
 
 	if got := queueSvc.queueLength(agentID); got != 0 {
 		t.Fatalf("expected queue to be empty, got %d", got)
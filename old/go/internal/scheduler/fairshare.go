@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DispatchPolicy controls the order in which eligible agents from different
+// workspaces are dispatched within a tick. Order matters once
+// MaxConcurrentDispatches caps how many dispatches a tick can start.
+type DispatchPolicy string
+
+const (
+	// DispatchPolicyFairShare interleaves workspaces by weighted virtual
+	// time, so a workspace with weight 2 gets twice the dispatches of a
+	// workspace with weight 1 while both have pending work.
+	DispatchPolicyFairShare DispatchPolicy = "fair_share"
+
+	// DispatchPolicyRoundRobin interleaves workspaces one agent at a time,
+	// rotating which workspace goes first each tick. Weights are ignored.
+	DispatchPolicyRoundRobin DispatchPolicy = "round_robin"
+
+	// DispatchPolicyFIFO dispatches in agent list order.
+	DispatchPolicyFIFO DispatchPolicy = "fifo"
+
+	// DispatchPolicyPriority dispatches agents from higher-priority
+	// workspaces first.
+	DispatchPolicyPriority DispatchPolicy = "priority"
+
+	// DispatchPolicyDeadlineFirst dispatches the agent whose next queue
+	// item is due soonest.
+	DispatchPolicyDeadlineFirst DispatchPolicy = "deadline_first"
+)
+
+// ParseDispatchPolicy normalizes a configured policy name. Unknown or empty
+// values fall back to DispatchPolicyFairShare.
+func ParseDispatchPolicy(value string) DispatchPolicy {
+	switch DispatchPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case DispatchPolicyRoundRobin:
+		return DispatchPolicyRoundRobin
+	case DispatchPolicyFIFO:
+		return DispatchPolicyFIFO
+	case DispatchPolicyPriority:
+		return DispatchPolicyPriority
+	case DispatchPolicyDeadlineFirst:
+		return DispatchPolicyDeadlineFirst
+	default:
+		return DispatchPolicyFairShare
+	}
+}
+
+// workspaceShares tracks dispatch history across ticks.
+type workspaceShares struct {
+	mu sync.Mutex
+	// cursor rotates the first workspace for round-robin ordering.
+	cursor int
+	// vtime is each backlogged workspace's weighted dispatch count.
+	vtime map[string]float64
+}
+
+func newWorkspaceShares() *workspaceShares {
+	return &workspaceShares{vtime: make(map[string]float64)}
+}
+
+// order returns eligible agents in dispatch order for policy. backlogged are
+// all agents with queued work, eligible or not; workspaces without backlog
+// drop their history so they cannot bank credit while idle.
+func (w *workspaceShares) order(policy DispatchPolicy, weights map[string]float64, eligible, backlogged []*models.Agent) []*models.Agent {
+	if policy == DispatchPolicyFIFO {
+		return eligible
+	}
+
+	groups := make(map[string][]*models.Agent)
+	workspaces := make([]string, 0)
+	for _, a := range eligible {
+		if _, ok := groups[a.WorkspaceID]; !ok {
+			workspaces = append(workspaces, a.WorkspaceID)
+		}
+		groups[a.WorkspaceID] = append(groups[a.WorkspaceID], a)
+	}
+	sort.Strings(workspaces)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if policy == DispatchPolicyRoundRobin {
+		if len(workspaces) < 2 {
+			return eligible
+		}
+		start := w.cursor % len(workspaces)
+		w.cursor++
+		rotated := append(append([]string(nil), workspaces[start:]...), workspaces[:start]...)
+		return interleave(rotated, groups, len(eligible))
+	}
+
+	w.prune(backlogged)
+	floor, _ := w.minVirtualTime()
+	vtime := make(map[string]float64, len(workspaces))
+	for _, ws := range workspaces {
+		if _, ok := w.vtime[ws]; !ok {
+			// Newly backlogged workspaces join at the current minimum.
+			w.vtime[ws] = floor
+		}
+		vtime[ws] = w.vtime[ws]
+	}
+
+	ordered := make([]*models.Agent, 0, len(eligible))
+	for len(ordered) < len(eligible) {
+		next := ""
+		for _, ws := range workspaces {
+			if len(groups[ws]) == 0 {
+				continue
+			}
+			if next == "" || vtime[ws] < vtime[next] {
+				next = ws
+			}
+		}
+		ordered = append(ordered, groups[next][0])
+		groups[next] = groups[next][1:]
+		vtime[next] += 1 / workspaceWeight(weights, next)
+	}
+	return ordered
+}
+
+// charge records a dispatch started for workspace.
+func (w *workspaceShares) charge(weights map[string]float64, workspace string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	v, ok := w.vtime[workspace]
+	if !ok {
+		v, _ = w.minVirtualTime()
+	}
+	w.vtime[workspace] = v + 1/workspaceWeight(weights, workspace)
+}
+
+func (w *workspaceShares) prune(backlogged []*models.Agent) {
+	active := make(map[string]struct{}, len(backlogged))
+	for _, a := range backlogged {
+		active[a.WorkspaceID] = struct{}{}
+	}
+	for ws := range w.vtime {
+		if _, ok := active[ws]; !ok {
+			delete(w.vtime, ws)
+		}
+	}
+}
+
+func (w *workspaceShares) minVirtualTime() (float64, bool) {
+	first := true
+	var floor float64
+	for _, v := range w.vtime {
+		if first || v < floor {
+			floor = v
+			first = false
+		}
+	}
+	return floor, !first
+}
+
+func interleave(workspaces []string, groups map[string][]*models.Agent, total int) []*models.Agent {
+	ordered := make([]*models.Agent, 0, total)
+	for round := 0; len(ordered) < total; round++ {
+		for _, ws := range workspaces {
+			if round < len(groups[ws]) {
+				ordered = append(ordered, groups[ws][round])
+			}
+		}
+	}
+	return ordered
+}
+
+func workspaceWeight(weights map[string]float64, workspace string) float64 {
+	if weight, ok := weights[workspace]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func agentsFor(specs ...string) []*models.Agent {
+	agents := make([]*models.Agent, 0, len(specs))
+	for _, spec := range specs {
+		ws, id, _ := strings.Cut(spec, "/")
+		agents = append(agents, &models.Agent{ID: id, WorkspaceID: ws, QueueLength: 1})
+	}
+	return agents
+}
+
+func agentIDs(agents []*models.Agent) string {
+	ids := make([]string, 0, len(agents))
+	for _, a := range agents {
+		ids = append(ids, a.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestWorkspaceSharesRoundRobinRotates(t *testing.T) {
+	shares := newWorkspaceShares()
+	agents := agentsFor("ws-a/a1", "ws-a/a2", "ws-a/a3", "ws-b/b1", "ws-c/c1")
+
+	first := shares.order(DispatchPolicyRoundRobin, nil, agents, agents)
+	if got := agentIDs(first); got != "a1,b1,c1,a2,a3" {
+		t.Fatalf("unexpected first round order %s", got)
+	}
+	second := shares.order(DispatchPolicyRoundRobin, nil, agents, agents)
+	if got := agentIDs(second); got != "b1,c1,a1,a2,a3" {
+		t.Fatalf("expected rotation to start at ws-b, got %s", got)
+	}
+}
+
+func TestWorkspaceSharesFairShareHonorsWeights(t *testing.T) {
+	shares := newWorkspaceShares()
+	weights := map[string]float64{"ws-a": 2}
+	eligible := agentsFor("ws-a/a1", "ws-b/b1")
+
+	// Simulate one dispatch slot per tick: the heavier workspace should win
+	// two of every three slots while both stay backlogged.
+	counts := map[string]int{}
+	for i := 0; i < 30; i++ {
+		next := shares.order(DispatchPolicyFairShare, weights, eligible, eligible)[0]
+		shares.charge(weights, next.WorkspaceID)
+		counts[next.WorkspaceID]++
+	}
+	if counts["ws-a"] != 20 || counts["ws-b"] != 10 {
+		t.Fatalf("expected 20/10 split, got %v", counts)
+	}
+}
+
+func TestWorkspaceSharesFairShareNewcomerJoinsAtFloor(t *testing.T) {
+	shares := newWorkspaceShares()
+	busy := agentsFor("ws-a/a1")
+	for i := 0; i < 10; i++ {
+		shares.charge(nil, "ws-a")
+	}
+
+	// ws-b joins late; it must not be owed ten dispatches of catch-up.
+	both := agentsFor("ws-a/a1", "ws-b/b1")
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		next := shares.order(DispatchPolicyFairShare, nil, both, both)[0]
+		shares.charge(nil, next.WorkspaceID)
+		counts[next.WorkspaceID]++
+	}
+	if counts["ws-a"] != 2 || counts["ws-b"] != 2 {
+		t.Fatalf("expected alternating dispatch, got %v", counts)
+	}
+
+	// Workspaces without backlog drop their history.
+	shares.order(DispatchPolicyFairShare, nil, busy, busy)
+	if _, ok := shares.vtime["ws-b"]; ok {
+		t.Fatalf("expected idle workspace pruned")
+	}
+}
+
+func TestWorkspaceSharesFIFOKeepsOrder(t *testing.T) {
+	shares := newWorkspaceShares()
+	agents := agentsFor("ws-b/b1", "ws-a/a1", "ws-b/b2")
+	if got := agentIDs(shares.order(DispatchPolicyFIFO, nil, agents, agents)); got != "b1,a1,b2" {
+		t.Fatalf("unexpected fifo order %s", got)
+	}
+}
+
+func TestConfigFromSettingsDispatchPolicy(t *testing.T) {
+	settings := config.DefaultConfig().Scheduler
+	settings.DispatchPolicy = "Round_Robin"
+	settings.WorkspaceWeights = map[string]float64{"ws-a": 3}
+
+	cfg := ConfigFromSettings(settings)
+	if cfg.DispatchPolicy != DispatchPolicyRoundRobin {
+		t.Fatalf("expected round_robin, got %q", cfg.DispatchPolicy)
+	}
+	if cfg.WorkspaceWeights["ws-a"] != 3 {
+		t.Fatalf("expected weights copied, got %v", cfg.WorkspaceWeights)
+	}
+	if ParseDispatchPolicy("bogus") != DispatchPolicyFairShare {
+		t.Fatalf("expected unknown policy to fall back to fair_share")
+	}
+}
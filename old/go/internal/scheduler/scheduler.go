@@ -14,11 +14,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/tOgg1/forge/internal/account"
 	"github.com/tOgg1/forge/internal/agent"
+	"github.com/tOgg1/forge/internal/config"
 	"github.com/tOgg1/forge/internal/events"
 	"github.com/tOgg1/forge/internal/logging"
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/queue"
 	"github.com/tOgg1/forge/internal/state"
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 // Scheduler errors.
@@ -39,6 +41,15 @@ type Config struct {
 	// Default: 1 second.
 	TickInterval time.Duration
 
+	// MinTickInterval is the shortest interval used while queues have
+	// pending items or a deadline is about to expire.
+	// Default: 250 milliseconds. Values above TickInterval are clamped.
+	MinTickInterval time.Duration
+
+	// MaxTickInterval is the ceiling for the idle exponential backoff.
+	// Default: 10 seconds. Values below TickInterval disable backoff.
+	MaxTickInterval time.Duration
+
 	// DispatchTimeout is the maximum time allowed for a single dispatch.
 	// Default: 30 seconds.
 	DispatchTimeout time.Duration
@@ -55,33 +66,122 @@ type Config struct {
 	// Default: true.
 	AutoResumeEnabled bool
 
-	// MaxRetries is the maximum number of dispatch retries.
-	// Default: 3.
-	MaxRetries int
-
-	// RetryBackoff is the base backoff duration for retries.
-	// Default: 5 seconds.
-	RetryBackoff time.Duration
+	// RetryPolicies sets how failed items are retried, by item type. It
+	// applies to items queued without a policy of their own; types not
+	// listed use models.DefaultRetryPolicies.
+	RetryPolicies map[models.QueueItemType]models.RetryPolicy
 
 	// DefaultCooldownDuration is the default pause duration after rate limiting.
 	// Default: 5 minutes.
 	DefaultCooldownDuration time.Duration
+
+	// AutoRotateOnRateLimit restarts a rate-limited agent on another
+	// available account instead of pausing it for the cooldown.
+	// Default: true.
+	AutoRotateOnRateLimit bool
+
+	// AutoRecoverAgents recreates the tmux pane (and session, if needed) of
+	// agents whose pane has disappeared and replays their launch command.
+	// Default: true.
+	AutoRecoverAgents bool
+
+	// RecoveryCheckInterval is how often agents are checked for dead panes
+	// when AutoRecoverAgents is set.
+	// Default: 30 seconds.
+	RecoveryCheckInterval time.Duration
+
+	// HeartbeatCheckInterval is how often harness heartbeats are read to
+	// mark agents stalled or alive. Stalled agents are not dispatched to.
+	// Default: 15 seconds.
+	HeartbeatCheckInterval time.Duration
+
+	// RunawayMemoryThreshold holds back dispatches to agents whose cgroup
+	// memory use is at or above this fraction of the limit, or that have
+	// been OOM-killed. Zero disables the check.
+	// Default: 0.9.
+	RunawayMemoryThreshold float64
+
+	// DispatchPolicy orders dispatches across workspaces within a tick.
+	// Default: DispatchPolicyFairShare.
+	DispatchPolicy DispatchPolicy
+
+	// WorkspaceWeights sets fair-share weights by workspace ID.
+	// Workspaces not listed get weight 1.
+	WorkspaceWeights map[string]float64
+
+	// WorkspacePriorities sets priorities by workspace ID for
+	// DispatchPolicyPriority. Workspaces not listed get priority 0.
+	WorkspacePriorities map[string]int
+
+	// DeadlineWarning is how close a queue head's deadline must be before
+	// its agent is dispatched ahead of the policy order and a
+	// queue.deadline_warning event is emitted. Zero disables both; late
+	// dispatches are still recorded.
+	// Default: 5 minutes.
+	DeadlineWarning time.Duration
 }
 
 // DefaultConfig returns sensible default configuration.
 func DefaultConfig() Config {
 	return Config{
 		TickInterval:            1 * time.Second,
+		MinTickInterval:         250 * time.Millisecond,
+		MaxTickInterval:         10 * time.Second,
 		DispatchTimeout:         30 * time.Second,
 		MaxConcurrentDispatches: 10,
 		IdleStateRequired:       true,
 		AutoResumeEnabled:       true,
-		MaxRetries:              3,
-		RetryBackoff:            5 * time.Second,
+		RetryPolicies:           models.DefaultRetryPolicies(),
 		DefaultCooldownDuration: 5 * time.Minute,
+		AutoRotateOnRateLimit:   true,
+		AutoRecoverAgents:       true,
+		RecoveryCheckInterval:   30 * time.Second,
+		HeartbeatCheckInterval:  15 * time.Second,
+		RunawayMemoryThreshold:  0.9,
+		DispatchPolicy:          DispatchPolicyFairShare,
+		DeadlineWarning:         5 * time.Minute,
 	}
 }
 
+// ConfigFromSettings builds a scheduler Config from user configuration,
+// falling back to DefaultConfig for fields the settings do not cover.
+func ConfigFromSettings(settings config.SchedulerConfig) Config {
+	cfg := DefaultConfig()
+	if settings.DispatchInterval > 0 {
+		cfg.TickInterval = settings.DispatchInterval
+	}
+	if settings.MinDispatchInterval > 0 {
+		cfg.MinTickInterval = settings.MinDispatchInterval
+	}
+	if cfg.MinTickInterval > cfg.TickInterval {
+		cfg.MinTickInterval = cfg.TickInterval
+	}
+	if settings.MaxDispatchInterval > 0 {
+		cfg.MaxTickInterval = settings.MaxDispatchInterval
+	}
+	cfg.RetryPolicies = settings.QueueRetryPolicies()
+	if settings.DefaultCooldownDuration > 0 {
+		cfg.DefaultCooldownDuration = settings.DefaultCooldownDuration
+	}
+	cfg.AutoRotateOnRateLimit = settings.AutoRotateOnRateLimit
+	cfg.AutoRecoverAgents = settings.AutoRecoverAgents
+	cfg.DispatchPolicy = ParseDispatchPolicy(settings.DispatchPolicy)
+	cfg.DeadlineWarning = settings.DeadlineWarning
+	if len(settings.WorkspaceWeights) > 0 {
+		cfg.WorkspaceWeights = make(map[string]float64, len(settings.WorkspaceWeights))
+		for workspace, weight := range settings.WorkspaceWeights {
+			cfg.WorkspaceWeights[workspace] = weight
+		}
+	}
+	if len(settings.WorkspacePriorities) > 0 {
+		cfg.WorkspacePriorities = make(map[string]int, len(settings.WorkspacePriorities))
+		for workspace, priority := range settings.WorkspacePriorities {
+			cfg.WorkspacePriorities[workspace] = priority
+		}
+	}
+	return cfg
+}
+
 // DispatchEvent represents a dispatch action taken by the scheduler.
 type DispatchEvent struct {
 	// AgentID is the agent that received the dispatch.
@@ -104,6 +204,9 @@ type DispatchEvent struct {
 
 	// Duration is how long the dispatch took.
 	Duration time.Duration
+
+	// DeadlineMissed indicates the item was dispatched after its deadline.
+	DeadlineMissed bool
 }
 
 // SchedulerStats contains scheduler statistics.
@@ -131,6 +234,9 @@ type SchedulerStats struct {
 
 	// PausedAgents is the count of currently paused agents.
 	PausedAgents int
+
+	// TickInterval is the current effective (adaptive) tick interval.
+	TickInterval time.Duration
 }
 
 // Scheduler manages message dispatch to agents.
@@ -154,6 +260,15 @@ type Scheduler struct {
 	scheduleNow  chan string // channel to trigger immediate dispatch for an agent
 	pausedAgents map[string]struct{}
 	retryAfter   map[string]time.Time
+	lastRecovery time.Time
+
+	// lastHeartbeatCheck is when heartbeats were last read.
+	lastHeartbeatCheck time.Time
+
+	// deadlineNotices tracks deadline events already emitted, by item ID.
+	deadlineNotices map[string]*deadlineNotice
+	strategy        Strategy
+	nodeLookup      NodeLookupFunc
 
 	// Per-agent dispatch locks to prevent concurrent dispatch to the same agent.
 	// Key: agentID, Value: mutex for that agent's dispatch operations.
@@ -175,48 +290,100 @@ func WithPublisher(publisher events.Publisher) Option {
 	}
 }
 
+// WithStrategy overrides the dispatch strategy selected by
+// Config.DispatchPolicy.
+func WithStrategy(strategy Strategy) Option {
+	return func(s *Scheduler) {
+		s.strategy = strategy
+	}
+}
+
+// NodeLookupFunc returns the node hosting a workspace.
+type NodeLookupFunc func(ctx context.Context, workspaceID string) (*models.Node, error)
+
+// WithNodeLookup enables placement checks: agents that declared node
+// constraints are held back while their workspace's node does not
+// satisfy them.
+func WithNodeLookup(lookup NodeLookupFunc) Option {
+	return func(s *Scheduler) {
+		s.nodeLookup = lookup
+	}
+}
+
 // New creates a new Scheduler.
 func New(config Config, agentService *agent.Service, queueService queue.QueueService, stateEngine *state.Engine, accountService *account.Service, opts ...Option) *Scheduler {
 	if config.TickInterval <= 0 {
 		config.TickInterval = DefaultConfig().TickInterval
 	}
+	if config.MinTickInterval <= 0 || config.MinTickInterval > config.TickInterval {
+		config.MinTickInterval = config.TickInterval
+	}
+	if config.MaxTickInterval < config.TickInterval {
+		config.MaxTickInterval = config.TickInterval
+	}
 	if config.DispatchTimeout <= 0 {
 		config.DispatchTimeout = DefaultConfig().DispatchTimeout
 	}
 	if config.MaxConcurrentDispatches <= 0 {
 		config.MaxConcurrentDispatches = DefaultConfig().MaxConcurrentDispatches
 	}
-	if config.MaxRetries < 0 {
-		config.MaxRetries = 0
-	}
-	if config.RetryBackoff <= 0 {
-		config.RetryBackoff = DefaultConfig().RetryBackoff
+	policies := models.DefaultRetryPolicies()
+	for itemType, policy := range config.RetryPolicies {
+		policies[itemType] = policy
 	}
+	config.RetryPolicies = policies
 	if config.DefaultCooldownDuration <= 0 {
 		config.DefaultCooldownDuration = DefaultConfig().DefaultCooldownDuration
 	}
+	if config.RecoveryCheckInterval <= 0 {
+		config.RecoveryCheckInterval = DefaultConfig().RecoveryCheckInterval
+	}
+	if config.HeartbeatCheckInterval <= 0 {
+		config.HeartbeatCheckInterval = DefaultConfig().HeartbeatCheckInterval
+	}
+	config.DispatchPolicy = ParseDispatchPolicy(string(config.DispatchPolicy))
+	if config.DeadlineWarning < 0 {
+		config.DeadlineWarning = 0
+	}
 
 	s := &Scheduler{
-		config:         config,
-		agentService:   agentService,
-		queueService:   queueService,
-		stateEngine:    stateEngine,
-		accountService: accountService,
-		logger:         logging.Component("scheduler"),
-		dispatchSem:    make(chan struct{}, config.MaxConcurrentDispatches),
-		scheduleNow:    make(chan string, 100),
-		pausedAgents:   make(map[string]struct{}),
-		retryAfter:     make(map[string]time.Time),
-		dispatchCh:     make(chan DispatchEvent, 100),
+		config:          config,
+		agentService:    agentService,
+		queueService:    queueService,
+		stateEngine:     stateEngine,
+		accountService:  accountService,
+		logger:          logging.Component("scheduler"),
+		dispatchSem:     make(chan struct{}, config.MaxConcurrentDispatches),
+		scheduleNow:     make(chan string, 100),
+		pausedAgents:    make(map[string]struct{}),
+		retryAfter:      make(map[string]time.Time),
+		deadlineNotices: make(map[string]*deadlineNotice),
+		dispatchCh:      make(chan DispatchEvent, 100),
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.strategy == nil {
+		s.strategy = NewStrategy(config, s.peekQueueHead)
+	}
 
 	return s
 }
 
+// peekQueueHead returns an agent's next queue item for strategies that
+// order by queued work.
+func (s *Scheduler) peekQueueHead(ctx context.Context, agentID string) *models.QueueItem {
+	if s.queueService == nil {
+		return nil
+	}
+	item, err := s.queueService.Peek(ctx, agentID)
+	if err != nil {
+		return nil
+	}
+	return item
+}
+
 // Start begins the scheduler's background processing loop.
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -235,10 +402,13 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	s.stats.Running = true
 	s.stats.Paused = false
 	s.stats.StartedAt = &now
+	s.stats.TickInterval = s.config.TickInterval
 	s.statsMu.Unlock()
 
 	s.logger.Info().
 		Dur("tick_interval", s.config.TickInterval).
+		Dur("min_tick_interval", s.config.MinTickInterval).
+		Dur("max_tick_interval", s.config.MaxTickInterval).
 		Int("max_concurrent", s.config.MaxConcurrentDispatches).
 		Msg("scheduler starting")
 
@@ -458,8 +628,9 @@ func (s *Scheduler) DispatchEvents() <-chan DispatchEvent {
 func (s *Scheduler) runLoop() {
 	defer s.wg.Done()
 
-	ticker := time.NewTicker(s.config.TickInterval)
-	defer ticker.Stop()
+	interval := s.config.TickInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
 
 	for {
 		select {
@@ -473,46 +644,78 @@ func (s *Scheduler) runLoop() {
 			s.mu.RUnlock()
 
 			if !paused {
-				s.tryDispatch(agentID)
+				s.tryDispatch(s.ctx, agentID)
+			}
+
+			// New work arrived; cut any idle backoff short.
+			if interval > s.config.TickInterval {
+				interval = s.config.TickInterval
+				s.setEffectiveTickInterval(interval)
+				timer.Reset(interval)
 			}
 
-		case <-ticker.C:
+		case <-timer.C:
 			// Regular tick
 			s.mu.RLock()
 			paused := s.paused
 			s.mu.RUnlock()
 
-			if !paused {
-				s.tick()
+			if paused {
+				interval = nextTickInterval(s.config, interval, tickPressure{})
+			} else {
+				interval = nextTickInterval(s.config, interval, s.tick())
 			}
+			s.setEffectiveTickInterval(interval)
+			timer.Reset(interval)
 		}
 	}
 }
 
-// tick performs one scheduling cycle.
-func (s *Scheduler) tick() {
-	ctx := s.ctx
+// tick performs one scheduling cycle and reports the observed queue pressure.
+func (s *Scheduler) tick() tickPressure {
+	ctx, span := tracing.Start(s.ctx, "scheduler.tick")
+	defer span.End()
 
 	// Get all agents
 	agents, err := s.agentService.ListAgents(ctx, agent.ListAgentsOptions{
 		IncludeQueueLength: true,
 	})
 	if err != nil {
+		span.RecordError(err)
 		s.logger.Error().Err(err).Msg("failed to list agents")
-		return
+		return tickPressure{}
 	}
+	span.SetAttributes(tracing.Int("agents", len(agents)))
 
 	// Check for auto-resume of paused agents
 	if s.config.AutoResumeEnabled {
 		s.checkAutoResume(ctx, agents)
 	}
+	if s.config.AutoRecoverAgents {
+		s.checkDeadAgents(ctx)
+	}
+	s.checkHeartbeats(ctx)
 
-	// Find eligible agents and dispatch
+	// Find eligible agents and dispatch them in policy order.
+	var eligible, backlogged []*models.Agent
 	for _, a := range agents {
-		if s.isEligibleForDispatch(a) {
-			s.tryDispatch(a.ID)
+		if a.QueueLength > 0 {
+			backlogged = append(backlogged, a)
+		}
+		if s.isEligibleForDispatch(a) && s.placementAllows(ctx, a) {
+			eligible = append(eligible, a)
+		}
+	}
+	// Agents whose queue head is near its deadline jump the policy order.
+	deadlines := s.scanDeadlines(ctx, backlogged, time.Now().UTC())
+	for _, a := range deadlines.prioritize(s.strategy.Order(ctx, eligible, backlogged)) {
+		if s.tryDispatch(ctx, a.ID) {
+			s.strategy.Dispatched(a)
 		}
 	}
+
+	now := time.Now().UTC()
+	return deadlines.applyTo(s.measurePressure(agents, now), now)
 }
 
 // checkAutoResume checks for agents that should auto-resume.
@@ -540,6 +743,40 @@ func (s *Scheduler) checkAutoResume(ctx context.Context, agents []*models.Agent)
 	}
 }
 
+// checkDeadAgents recovers agents whose tmux pane has disappeared, at most
+// once per RecoveryCheckInterval. Recovered agents restart in the starting
+// state, so they are not dispatched to until the harness is ready.
+func (s *Scheduler) checkDeadAgents(ctx context.Context) {
+	now := time.Now()
+	if !s.lastRecovery.IsZero() && now.Sub(s.lastRecovery) < s.config.RecoveryCheckInterval {
+		return
+	}
+	s.lastRecovery = now
+
+	recovered, err := s.agentService.RecoverDeadAgents(ctx)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("failed to check for dead agent panes")
+		return
+	}
+	for _, a := range recovered {
+		s.logger.Info().Str("agent_id", a.ID).Str("pane", a.TmuxPane).Msg("recovered agent with dead tmux pane")
+	}
+}
+
+// checkHeartbeats marks agents stalled or alive from their harness
+// heartbeats, at most once per HeartbeatCheckInterval.
+func (s *Scheduler) checkHeartbeats(ctx context.Context) {
+	now := time.Now()
+	if !s.lastHeartbeatCheck.IsZero() && now.Sub(s.lastHeartbeatCheck) < s.config.HeartbeatCheckInterval {
+		return
+	}
+	s.lastHeartbeatCheck = now
+
+	if _, err := s.agentService.CheckHeartbeats(ctx); err != nil {
+		s.logger.Warn().Err(err).Msg("failed to check agent heartbeats")
+	}
+}
+
 // isEligibleForDispatch checks if an agent is eligible for dispatch.
 func (s *Scheduler) isEligibleForDispatch(a *models.Agent) bool {
 	// Check if agent is paused in scheduler
@@ -557,6 +794,17 @@ func (s *Scheduler) isEligibleForDispatch(a *models.Agent) bool {
 	if a.State == models.AgentStateStopped {
 		return false
 	}
+	if a.Metadata.CrashLoop.IsCrashLooping() {
+		return false
+	}
+	if a.Metadata.Heartbeat.IsStalled() {
+		return false
+	}
+
+	// Hold work back from agents about to exhaust their memory limit.
+	if s.config.RunawayMemoryThreshold > 0 && a.Metadata.Resources.IsRunaway(s.config.RunawayMemoryThreshold) {
+		return false
+	}
 
 	// If idle state is required, check for idle
 	if s.config.IdleStateRequired && a.State != models.AgentStateIdle {
@@ -571,8 +819,34 @@ func (s *Scheduler) isEligibleForDispatch(a *models.Agent) bool {
 	return true
 }
 
-// tryDispatch attempts to dispatch the next item to an agent.
-func (s *Scheduler) tryDispatch(agentID string) {
+// placementAllows reports whether the node hosting the agent's workspace
+// still satisfies the agent's node constraints. Agents without constraints,
+// or schedulers without a node lookup, always pass; a failed lookup holds
+// the agent back.
+func (s *Scheduler) placementAllows(ctx context.Context, a *models.Agent) bool {
+	if s.nodeLookup == nil || len(a.Metadata.NodeConstraints) == 0 {
+		return true
+	}
+	n, err := s.nodeLookup(ctx, a.WorkspaceID)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", a.ID).Msg("failed to resolve agent node for placement check")
+		return false
+	}
+	if !n.SatisfiesConstraints(a.Metadata.NodeConstraints) {
+		s.logger.Debug().
+			Str("agent_id", a.ID).
+			Str("node", n.Name).
+			Str("constraints", models.FormatNodeConstraints(a.Metadata.NodeConstraints)).
+			Msg("agent node no longer satisfies constraints")
+		return false
+	}
+	return true
+}
+
+// tryDispatch attempts to dispatch the next item to an agent and reports
+// whether a dispatch was started. The parent context only carries trace
+// state; cancellation follows the scheduler.
+func (s *Scheduler) tryDispatch(parent context.Context, agentID string) bool {
 	// Try to acquire the per-agent dispatch lock first.
 	// This ensures only one dispatch happens per agent at a time.
 	if !s.tryLockAgentDispatch(agentID) {
@@ -581,7 +855,7 @@ func (s *Scheduler) tryDispatch(agentID string) {
 		s.logger.Debug().
 			Str("agent_id", agentID).
 			Msg("dispatch skipped: another dispatch already in progress for this agent")
-		return
+		return false
 	}
 
 	// Acquire global dispatch semaphore to limit total concurrent dispatches
@@ -593,7 +867,7 @@ func (s *Scheduler) tryDispatch(agentID string) {
 		s.logger.Debug().
 			Str("agent_id", agentID).
 			Msg("dispatch skipped: max concurrent dispatches reached")
-		return
+		return false
 	}
 
 	s.wg.Add(1)
@@ -602,15 +876,19 @@ func (s *Scheduler) tryDispatch(agentID string) {
 		defer func() { <-s.dispatchSem }()
 		defer s.unlockAgentDispatch(agentID)
 
-		s.dispatchToAgent(agentID)
+		s.dispatchToAgent(parent, agentID)
 	}()
+	return true
 }
 
 // dispatchToAgent dispatches the next queue item to an agent.
-func (s *Scheduler) dispatchToAgent(agentID string) {
+func (s *Scheduler) dispatchToAgent(parent context.Context, agentID string) {
 	ctx, cancel := context.WithTimeout(s.ctx, s.config.DispatchTimeout)
 	defer cancel()
 
+	ctx, span := tracing.StartChild(tracing.ContextWithSpan(ctx, tracing.SpanFromContext(parent)), "scheduler.dispatch", tracing.String("agent_id", agentID))
+	defer span.End()
+
 	// Guard against nil queue service
 	if s.queueService == nil {
 		return
@@ -623,6 +901,14 @@ func (s *Scheduler) dispatchToAgent(agentID string) {
 		if event != nil {
 			event.Duration = time.Since(startTime)
 			s.recordDispatch(*event)
+			span.SetAttributes(
+				tracing.String("item_id", event.ItemID),
+				tracing.String("item_type", string(event.ItemType)),
+				tracing.Bool("success", event.Success),
+			)
+			if event.Error != "" {
+				span.RecordError(errors.New(event.Error))
+			}
 		}
 	}()
 
@@ -650,7 +936,7 @@ func (s *Scheduler) dispatchToAgent(agentID string) {
 		return
 	}
 
-	// Check account cooldown before dequeuing
+	// Check account cooldown and quota before dequeuing
 	if s.accountService != nil && agentInfo != nil && agentInfo.AccountID != "" {
 		onCooldown, remaining, err := s.accountService.IsOnCooldown(ctx, agentInfo.AccountID)
 		if err != nil && !errors.Is(err, account.ErrAccountNotFound) {
@@ -661,43 +947,25 @@ func (s *Scheduler) dispatchToAgent(agentID string) {
 			return
 		}
 
+		reason := ""
 		if onCooldown {
-			// Try to rotate to another account
-			rotated, rotateErr := s.accountService.RotateAccountForAgent(ctx, agentInfo.AccountID, agentID, "cooldown")
-			if rotateErr != nil {
+			reason = "cooldown"
+		} else if overQuota, err := s.accountService.IsOverQuota(ctx, agentInfo.AccountID); err == nil && overQuota {
+			reason = "quota"
+		}
+
+		if reason != "" {
+			rotated, ok := s.rotateAgentAccount(ctx, agentID, agentInfo.AccountID, reason)
+			if !ok {
 				s.logger.Debug().
 					Str("agent_id", agentID).
 					Str("account_id", agentInfo.AccountID).
+					Str("reason", reason).
 					Dur("cooldown_remaining", remaining).
-					Msg("account on cooldown, no rotation available, skipping dispatch")
-				return
-			}
-
-			if s.agentService == nil {
-				s.logger.Warn().
-					Str("agent_id", agentID).
-					Str("account_id", agentInfo.AccountID).
-					Msg("agent service unavailable; cannot restart with rotated account")
+					Msg("account unavailable, no rotation available, skipping dispatch")
 				return
 			}
-
-			fromAccount := agentInfo.AccountID
-			if _, err := s.agentService.RestartAgentWithAccount(ctx, agentID, rotated.ID); err != nil {
-				s.logger.Warn().
-					Err(err).
-					Str("agent_id", agentID).
-					Str("from_account", fromAccount).
-					Str("to_account", rotated.ID).
-					Msg("failed to restart agent with rotated account")
-				return
-			}
-
-			agentInfo.AccountID = rotated.ID
-			s.logger.Info().
-				Str("agent_id", agentID).
-				Str("from_account", fromAccount).
-				Str("to_account", rotated.ID).
-				Msg("rotated to available account and restarted agent")
+			agentInfo.AccountID = rotated
 		}
 	}
 
@@ -720,11 +988,13 @@ func (s *Scheduler) dispatchToAgent(agentID string) {
 
 	// Initialize event now that we have an item
 	event = &DispatchEvent{
-		AgentID:   agentID,
-		Timestamp: startTime,
-		ItemID:    item.ID,
-		ItemType:  item.Type,
+		AgentID:        agentID,
+		Timestamp:      startTime,
+		ItemID:         item.ID,
+		ItemType:       item.Type,
+		DeadlineMissed: item.DeadlineMissed,
 	}
+	s.noteLateDispatch(ctx, agentID, item)
 
 	// Publish message.dispatched event
 	s.publishEvent(ctx, models.EventTypeMessageDispatched, models.EntityTypeQueue, item.ID, models.MessageDispatchedPayload{
@@ -930,24 +1200,28 @@ func (s *Scheduler) handleDispatchFailure(ctx context.Context, agentID string, i
 	}
 
 	attempts := item.Attempts + 1
-	maxRetries := s.config.MaxRetries
-	if maxRetries < 0 {
-		maxRetries = 0
+	policy := s.retryPolicy(item)
+	maxRetries := policy.Retries()
+
+	if err := s.queueService.UpdateAttempts(ctx, item.ID, attempts); err != nil {
+		return err
 	}
 
 	if attempts <= maxRetries {
-		if err := s.queueService.UpdateAttempts(ctx, item.ID, attempts); err != nil {
-			return err
-		}
 		if err := s.queueService.UpdateStatus(ctx, item.ID, models.QueueItemStatusPending, dispatchErr.Error()); err != nil {
 			return err
 		}
 
-		backoff := s.retryBackoff(attempts)
-		s.setRetryAfter(agentID, time.Now().UTC().Add(backoff))
+		backoff := policy.Delay(attempts)
+		if backoff > 0 {
+			s.setRetryAfter(agentID, time.Now().UTC().Add(backoff))
+		} else {
+			s.clearRetryAfter(agentID)
+		}
 		s.logger.Warn().
 			Str("agent_id", agentID).
 			Str("item_id", item.ID).
+			Str("retry_policy", string(policy.Kind)).
 			Int("attempt", attempts).
 			Int("max_retries", maxRetries).
 			Dur("backoff", backoff).
@@ -955,10 +1229,7 @@ func (s *Scheduler) handleDispatchFailure(ctx context.Context, agentID string, i
 		return nil
 	}
 
-	if err := s.queueService.UpdateAttempts(ctx, item.ID, attempts); err != nil {
-		return err
-	}
-	if err := s.queueService.UpdateStatus(ctx, item.ID, models.QueueItemStatusFailed, dispatchErr.Error()); err != nil {
+	if err := s.queueService.DeadLetter(ctx, item.ID, dispatchErr.Error()); err != nil {
 		return err
 	}
 
@@ -966,26 +1237,23 @@ func (s *Scheduler) handleDispatchFailure(ctx context.Context, agentID string, i
 	s.logger.Warn().
 		Str("agent_id", agentID).
 		Str("item_id", item.ID).
+		Str("retry_policy", string(policy.Kind)).
 		Int("attempt", attempts).
 		Int("max_retries", maxRetries).
-		Msg("dispatch failed; max retries exceeded")
+		Msg("dispatch failed; retries exhausted, item dead-lettered")
 	return nil
 }
 
-func (s *Scheduler) retryBackoff(attempt int) time.Duration {
-	base := s.config.RetryBackoff
-	if base <= 0 {
-		base = DefaultConfig().RetryBackoff
+// retryPolicy returns the policy stored on the item, falling back to the
+// configured policy for its type.
+func (s *Scheduler) retryPolicy(item *models.QueueItem) models.RetryPolicy {
+	if item.RetryPolicy != nil {
+		return *item.RetryPolicy
 	}
-	if attempt <= 1 {
-		return base
+	if policy, ok := s.config.RetryPolicies[item.Type]; ok {
+		return policy
 	}
-
-	backoff := base
-	for i := 1; i < attempt; i++ {
-		backoff *= 2
-	}
-	return backoff
+	return models.RetryPolicy{Kind: models.RetryPolicyNone}
 }
 
 // recordDispatch records a dispatch event in stats.
@@ -1054,6 +1322,12 @@ func (s *Scheduler) handleRateLimit(change state.StateChange) {
 		ctx = context.Background()
 	}
 
+	// With auto-rotation the agent moves to a fresh account right away and
+	// its queue keeps flowing; otherwise it pauses until the cooldown ends.
+	if s.coolDownAndRotate(ctx, change) {
+		return
+	}
+
 	if s.queueService != nil {
 		duration := s.rateLimitPauseDuration(change.StateInfo)
 		if duration > 0 {
@@ -1065,19 +1339,24 @@ func (s *Scheduler) handleRateLimit(change state.StateChange) {
 			}
 		}
 	}
+}
 
+// coolDownAndRotate puts the rate-limited agent's account on cooldown and,
+// when AutoRotateOnRateLimit is set, restarts the agent on another account.
+// It reports whether the agent was rotated.
+func (s *Scheduler) coolDownAndRotate(ctx context.Context, change state.StateChange) bool {
 	if s.accountService == nil || s.agentService == nil {
-		return
+		return false
 	}
 
 	agentInfo, err := s.agentService.GetAgent(ctx, change.AgentID)
 	if err != nil {
 		s.logger.Warn().Err(err).Str("agent_id", change.AgentID).Msg("failed to load agent for rate limit handling")
-		return
+		return false
 	}
 	if agentInfo.AccountID == "" {
 		s.logger.Debug().Str("agent_id", change.AgentID).Msg("agent has no account; skipping cooldown")
-		return
+		return false
 	}
 
 	if err := s.accountService.SetCooldownForRateLimit(ctx, agentInfo.AccountID, change.StateInfo.Reason); err != nil {
@@ -1086,13 +1365,49 @@ func (s *Scheduler) handleRateLimit(change state.StateChange) {
 			Str("agent_id", change.AgentID).
 			Str("account_id", agentInfo.AccountID).
 			Msg("failed to set account cooldown after rate limit")
-		return
+		return false
 	}
 
 	s.logger.Info().
 		Str("agent_id", change.AgentID).
 		Str("account_id", agentInfo.AccountID).
 		Msg("account placed on cooldown due to rate limit")
+
+	if !s.config.AutoRotateOnRateLimit {
+		return false
+	}
+	_, ok := s.rotateAgentAccount(ctx, change.AgentID, agentInfo.AccountID, "rate_limit")
+	return ok
+}
+
+// rotateAgentAccount restarts an agent on the least recently used available
+// account of the same provider. It returns the new account ID.
+func (s *Scheduler) rotateAgentAccount(ctx context.Context, agentID, fromAccount, reason string) (string, bool) {
+	if s.agentService == nil {
+		return "", false
+	}
+	rotated, err := s.accountService.RotateAccountForAgent(ctx, fromAccount, agentID, reason)
+	if err != nil {
+		return "", false
+	}
+
+	if _, err := s.agentService.RestartAgentWithAccount(ctx, agentID, rotated.ID); err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("agent_id", agentID).
+			Str("from_account", fromAccount).
+			Str("to_account", rotated.ID).
+			Msg("failed to restart agent with rotated account")
+		return "", false
+	}
+
+	s.logger.Info().
+		Str("agent_id", agentID).
+		Str("from_account", fromAccount).
+		Str("to_account", rotated.ID).
+		Str("reason", reason).
+		Msg("rotated to available account and restarted agent")
+	return rotated.ID, true
 }
 
 func (s *Scheduler) rateLimitPauseDuration(info models.StateInfo) time.Duration {
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -155,6 +156,23 @@ func (m *mockQueueService) UpdateAttempts(ctx context.Context, itemID string, at
 	return nil
 }
 
+func (m *mockQueueService) DeadLetter(ctx context.Context, itemID string, errorMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	for _, items := range m.queues {
+		for _, item := range items {
+			if item != nil && item.ID == itemID {
+				item.Status = models.QueueItemStatusFailed
+				item.Error = errorMsg
+				item.DeadLetteredAt = &now
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
 func (m *mockQueueService) queueLength(agentID string) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -573,6 +591,33 @@ func TestScheduler_IsEligibleForDispatch(t *testing.T) {
 			paused:   true,
 			eligible: false,
 		},
+		{
+			name: "idle agent near memory limit",
+			agent: &models.Agent{
+				ID:          "agent-7",
+				State:       models.AgentStateIdle,
+				QueueLength: 5,
+				Metadata: models.AgentMetadata{
+					Resources: &models.ResourceUsage{
+						Limits:      models.ResourceLimits{MemoryBytes: 100},
+						MemoryBytes: 95,
+					},
+				},
+			},
+			eligible: false,
+		},
+		{
+			name: "idle agent crashlooping",
+			agent: &models.Agent{
+				ID:          "agent-8",
+				State:       models.AgentStateIdle,
+				QueueLength: 5,
+				Metadata: models.AgentMetadata{
+					CrashLoop: &models.CrashLoopInfo{ConsecutiveFailures: 3, CrashLooping: true},
+				},
+			},
+			eligible: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -613,6 +658,38 @@ func TestScheduler_IsEligibleForDispatch_IdleNotRequired(t *testing.T) {
 	}
 }
 
+func TestScheduler_PlacementAllows(t *testing.T) {
+	labels := map[string]string{"gpu": "true"}
+	sched := New(DefaultConfig(), nil, nil, nil, nil, WithNodeLookup(func(_ context.Context, workspaceID string) (*models.Node, error) {
+		if workspaceID != "ws-1" {
+			return nil, errors.New("workspace not found")
+		}
+		return &models.Node{Name: "node-1", Labels: labels}, nil
+	}))
+	constrained := &models.Agent{
+		ID:          "agent-1",
+		WorkspaceID: "ws-1",
+		Metadata: models.AgentMetadata{
+			NodeConstraints: []models.NodeConstraint{{Key: "gpu", Value: "true"}},
+		},
+	}
+
+	if !sched.placementAllows(context.Background(), constrained) {
+		t.Fatal("expected agent on gpu node to pass placement")
+	}
+	labels["gpu"] = "false"
+	if sched.placementAllows(context.Background(), constrained) {
+		t.Fatal("expected agent to be held after node lost the gpu label")
+	}
+	if !sched.placementAllows(context.Background(), &models.Agent{ID: "agent-2", WorkspaceID: "missing"}) {
+		t.Fatal("expected unconstrained agent to skip the node lookup")
+	}
+	constrained.WorkspaceID = "missing"
+	if sched.placementAllows(context.Background(), constrained) {
+		t.Fatal("expected failed node lookup to hold the agent")
+	}
+}
+
 func TestScheduler_Stats(t *testing.T) {
 	sched := New(DefaultConfig(), nil, nil, nil, nil)
 
@@ -723,7 +800,7 @@ func TestScheduler_DispatchToAgent_SkipsWhenNotIdle(t *testing.T) {
 	sched := New(cfg, agentSvc, queueSvc, nil, nil)
 	sched.ctx = context.Background()
 
-	sched.dispatchToAgent(agentID)
+	sched.dispatchToAgent(sched.ctx, agentID)
 
 	if got := queueSvc.dequeueCallCount(); got != 0 {
 		t.Errorf("expected no dequeue attempts, got %d", got)
@@ -777,8 +854,9 @@ func TestScheduler_HandleDispatchFailure_Retry(t *testing.T) {
 	}
 
 	cfg := DefaultConfig()
-	cfg.MaxRetries = 2
-	cfg.RetryBackoff = time.Second
+	cfg.RetryPolicies = map[models.QueueItemType]models.RetryPolicy{
+		models.QueueItemTypeMessage: {Kind: models.RetryPolicyExponential, MaxRetries: 2, Backoff: time.Second},
+	}
 	sched := New(cfg, nil, queueSvc, nil, nil)
 
 	if err := sched.handleDispatchFailure(context.Background(), agentID, item, fmt.Errorf("dispatch error")); err != nil {
@@ -805,8 +883,9 @@ func TestScheduler_HandleDispatchFailure_ExceedsMax(t *testing.T) {
 	}
 
 	cfg := DefaultConfig()
-	cfg.MaxRetries = 2
-	cfg.RetryBackoff = time.Second
+	cfg.RetryPolicies = map[models.QueueItemType]models.RetryPolicy{
+		models.QueueItemTypeMessage: {Kind: models.RetryPolicyExponential, MaxRetries: 2, Backoff: time.Second},
+	}
 	sched := New(cfg, nil, queueSvc, nil, nil)
 
 	if err := sched.handleDispatchFailure(context.Background(), agentID, item, fmt.Errorf("dispatch error")); err != nil {
@@ -818,11 +897,47 @@ func TestScheduler_HandleDispatchFailure_ExceedsMax(t *testing.T) {
 	if item.Status != models.QueueItemStatusFailed {
 		t.Fatalf("expected failed status, got %q", item.Status)
 	}
+	if item.DeadLetteredAt == nil {
+		t.Fatalf("expected exhausted item to be dead-lettered")
+	}
 	if sched.isRetryBackoffActive(agentID) {
 		t.Fatalf("expected retry backoff to be cleared")
 	}
 }
 
+func TestScheduler_HandleDispatchFailure_ItemPolicy(t *testing.T) {
+	queueSvc := newMockQueueService()
+	agentID := "agent-1"
+
+	immediate := createMessageItem("item-immediate", "hello")
+	immediate.RetryPolicy = &models.RetryPolicy{Kind: models.RetryPolicyImmediate, MaxRetries: 1}
+	noRetry := createMessageItem("item-none", "hello")
+	noRetry.RetryPolicy = &models.RetryPolicy{Kind: models.RetryPolicyNone, MaxRetries: 5}
+	if err := queueSvc.Enqueue(context.Background(), agentID, immediate, noRetry); err != nil {
+		t.Fatalf("failed to enqueue items: %v", err)
+	}
+
+	// The policy stored on the item wins over the configured one.
+	sched := New(DefaultConfig(), nil, queueSvc, nil, nil)
+
+	if err := sched.handleDispatchFailure(context.Background(), agentID, immediate, fmt.Errorf("dispatch error")); err != nil {
+		t.Fatalf("handleDispatchFailure failed: %v", err)
+	}
+	if immediate.Status != models.QueueItemStatusPending || immediate.DeadLetteredAt != nil {
+		t.Fatalf("expected immediate retry, got status %q", immediate.Status)
+	}
+	if sched.isRetryBackoffActive(agentID) {
+		t.Fatalf("expected no backoff for immediate retry")
+	}
+
+	if err := sched.handleDispatchFailure(context.Background(), agentID, noRetry, fmt.Errorf("dispatch error")); err != nil {
+		t.Fatalf("handleDispatchFailure failed: %v", err)
+	}
+	if noRetry.Status != models.QueueItemStatusFailed || noRetry.DeadLetteredAt == nil {
+		t.Fatalf("expected no-retry item to be dead-lettered on first failure, got status %q", noRetry.Status)
+	}
+}
+
 func TestSchedulerStats_Fields(t *testing.T) {
 	now := time.Now()
 	stats := SchedulerStats{
@@ -1054,7 +1169,7 @@ func TestScheduler_ConcurrentDispatchPrevention(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			sched.tryDispatch(agentID)
+			sched.tryDispatch(sched.ctx, agentID)
 		}()
 	}
 
@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Strategy decides the order in which eligible agents are dispatched within
+// a tick. Order matters once MaxConcurrentDispatches caps how many
+// dispatches a tick can start.
+type Strategy interface {
+	// Name identifies the strategy in logs and configuration.
+	Name() DispatchPolicy
+
+	// Order returns eligible agents in dispatch order. backlogged holds
+	// every agent with queued work, eligible or not.
+	Order(ctx context.Context, eligible, backlogged []*models.Agent) []*models.Agent
+
+	// Dispatched records that a dispatch to agent started.
+	Dispatched(agent *models.Agent)
+}
+
+// QueueHeadFunc returns an agent's next pending queue item, or nil when the
+// queue is empty or cannot be read.
+type QueueHeadFunc func(ctx context.Context, agentID string) *models.QueueItem
+
+// NewStrategy builds the strategy selected by config.DispatchPolicy. head is
+// only consulted by strategies that look at queued items.
+func NewStrategy(config Config, head QueueHeadFunc) Strategy {
+	switch ParseDispatchPolicy(string(config.DispatchPolicy)) {
+	case DispatchPolicyFIFO:
+		return FIFOStrategy{}
+	case DispatchPolicyRoundRobin:
+		return NewRoundRobinStrategy()
+	case DispatchPolicyPriority:
+		return NewPriorityStrategy(config.WorkspacePriorities)
+	case DispatchPolicyDeadlineFirst:
+		return NewDeadlineFirstStrategy(head)
+	default:
+		return NewFairShareStrategy(config.WorkspaceWeights)
+	}
+}
+
+// FIFOStrategy dispatches in agent list order.
+type FIFOStrategy struct{}
+
+// Name implements Strategy.
+func (FIFOStrategy) Name() DispatchPolicy { return DispatchPolicyFIFO }
+
+// Order implements Strategy.
+func (FIFOStrategy) Order(_ context.Context, eligible, _ []*models.Agent) []*models.Agent {
+	return eligible
+}
+
+// Dispatched implements Strategy.
+func (FIFOStrategy) Dispatched(*models.Agent) {}
+
+// RoundRobinStrategy takes one agent per workspace in turn, rotating which
+// workspace goes first each tick.
+type RoundRobinStrategy struct {
+	shares *workspaceShares
+}
+
+// NewRoundRobinStrategy creates a round-robin strategy.
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{shares: newWorkspaceShares()}
+}
+
+// Name implements Strategy.
+func (s *RoundRobinStrategy) Name() DispatchPolicy { return DispatchPolicyRoundRobin }
+
+// Order implements Strategy.
+func (s *RoundRobinStrategy) Order(_ context.Context, eligible, backlogged []*models.Agent) []*models.Agent {
+	return s.shares.order(DispatchPolicyRoundRobin, nil, eligible, backlogged)
+}
+
+// Dispatched implements Strategy.
+func (s *RoundRobinStrategy) Dispatched(*models.Agent) {}
+
+// FairShareStrategy interleaves workspaces by weighted virtual time. It is
+// the default strategy.
+type FairShareStrategy struct {
+	shares  *workspaceShares
+	weights map[string]float64
+}
+
+// NewFairShareStrategy creates a fair-share strategy. Workspaces missing
+// from weights get weight 1.
+func NewFairShareStrategy(weights map[string]float64) *FairShareStrategy {
+	return &FairShareStrategy{shares: newWorkspaceShares(), weights: weights}
+}
+
+// Name implements Strategy.
+func (s *FairShareStrategy) Name() DispatchPolicy { return DispatchPolicyFairShare }
+
+// Order implements Strategy.
+func (s *FairShareStrategy) Order(_ context.Context, eligible, backlogged []*models.Agent) []*models.Agent {
+	return s.shares.order(DispatchPolicyFairShare, s.weights, eligible, backlogged)
+}
+
+// Dispatched implements Strategy.
+func (s *FairShareStrategy) Dispatched(agent *models.Agent) {
+	s.shares.charge(s.weights, agent.WorkspaceID)
+}
+
+// PriorityStrategy dispatches agents from higher-priority workspaces first,
+// keeping agent list order within a priority. Workspaces missing from the
+// priority map have priority 0; a busy high-priority workspace can starve
+// lower ones.
+type PriorityStrategy struct {
+	priorities map[string]int
+}
+
+// NewPriorityStrategy creates a priority strategy.
+func NewPriorityStrategy(priorities map[string]int) *PriorityStrategy {
+	return &PriorityStrategy{priorities: priorities}
+}
+
+// Name implements Strategy.
+func (s *PriorityStrategy) Name() DispatchPolicy { return DispatchPolicyPriority }
+
+// Order implements Strategy.
+func (s *PriorityStrategy) Order(_ context.Context, eligible, _ []*models.Agent) []*models.Agent {
+	ordered := append([]*models.Agent(nil), eligible...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return s.priorities[ordered[i].WorkspaceID] > s.priorities[ordered[j].WorkspaceID]
+	})
+	return ordered
+}
+
+// Dispatched implements Strategy.
+func (s *PriorityStrategy) Dispatched(*models.Agent) {}
+
+// DeadlineFirstStrategy dispatches the agent whose next queue item has the
+// earliest deadline. Items without an explicit deadline use their enqueue
+// time, so the oldest waiting item goes first among them. Agents whose next
+// item cannot be read go last, in list order.
+type DeadlineFirstStrategy struct {
+	head QueueHeadFunc
+}
+
+// NewDeadlineFirstStrategy creates a deadline-first strategy that reads
+// queue heads through head.
+func NewDeadlineFirstStrategy(head QueueHeadFunc) *DeadlineFirstStrategy {
+	return &DeadlineFirstStrategy{head: head}
+}
+
+// Name implements Strategy.
+func (s *DeadlineFirstStrategy) Name() DispatchPolicy { return DispatchPolicyDeadlineFirst }
+
+// Order implements Strategy.
+func (s *DeadlineFirstStrategy) Order(ctx context.Context, eligible, _ []*models.Agent) []*models.Agent {
+	if s.head == nil || len(eligible) < 2 {
+		return eligible
+	}
+
+	deadlines := make(map[string]time.Time, len(eligible))
+	for _, a := range eligible {
+		if item := s.head(ctx, a.ID); item != nil {
+			deadlines[a.ID] = queueItemDeadline(item)
+		}
+	}
+
+	ordered := append([]*models.Agent(nil), eligible...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		di, iok := deadlines[ordered[i].ID]
+		dj, jok := deadlines[ordered[j].ID]
+		if iok != jok {
+			return iok
+		}
+		return iok && di.Before(dj)
+	})
+	return ordered
+}
+
+// Dispatched implements Strategy.
+func (s *DeadlineFirstStrategy) Dispatched(*models.Agent) {}
+
+// queueItemDeadline returns the time by which item should be dispatched.
+func queueItemDeadline(item *models.QueueItem) time.Time {
+	if item.Deadline != nil {
+		return *item.Deadline
+	}
+	return item.CreatedAt
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestNewStrategySelectsByPolicy(t *testing.T) {
+	cases := map[DispatchPolicy]DispatchPolicy{
+		"":                          DispatchPolicyFairShare,
+		"bogus":                     DispatchPolicyFairShare,
+		DispatchPolicyFairShare:     DispatchPolicyFairShare,
+		DispatchPolicyRoundRobin:    DispatchPolicyRoundRobin,
+		DispatchPolicyFIFO:          DispatchPolicyFIFO,
+		"Priority":                  DispatchPolicyPriority,
+		DispatchPolicyDeadlineFirst: DispatchPolicyDeadlineFirst,
+	}
+	for policy, want := range cases {
+		cfg := DefaultConfig()
+		cfg.DispatchPolicy = policy
+		if got := NewStrategy(cfg, nil).Name(); got != want {
+			t.Errorf("NewStrategy(%q).Name() = %q, want %q", policy, got, want)
+		}
+	}
+}
+
+func TestNewDefaultsToFairShareStrategy(t *testing.T) {
+	s := New(DefaultConfig(), nil, nil, nil, nil)
+	if got := s.strategy.Name(); got != DispatchPolicyFairShare {
+		t.Fatalf("default strategy = %q, want %q", got, DispatchPolicyFairShare)
+	}
+
+	custom := FIFOStrategy{}
+	s = New(DefaultConfig(), nil, nil, nil, nil, WithStrategy(custom))
+	if s.strategy != Strategy(custom) {
+		t.Fatalf("WithStrategy did not override the configured strategy")
+	}
+}
+
+func TestFIFOStrategyKeepsOrder(t *testing.T) {
+	agents := agentsFor("ws-b/b1", "ws-a/a1", "ws-b/b2")
+	if got := agentIDs(FIFOStrategy{}.Order(context.Background(), agents, agents)); got != "b1,a1,b2" {
+		t.Fatalf("unexpected fifo order %s", got)
+	}
+}
+
+func TestRoundRobinStrategyRotates(t *testing.T) {
+	strategy := NewRoundRobinStrategy()
+	agents := agentsFor("ws-a/a1", "ws-a/a2", "ws-b/b1")
+
+	if got := agentIDs(strategy.Order(context.Background(), agents, agents)); got != "a1,b1,a2" {
+		t.Fatalf("unexpected first round order %s", got)
+	}
+	if got := agentIDs(strategy.Order(context.Background(), agents, agents)); got != "b1,a1,a2" {
+		t.Fatalf("unexpected second round order %s", got)
+	}
+}
+
+func TestFairShareStrategyChargesDispatches(t *testing.T) {
+	strategy := NewFairShareStrategy(map[string]float64{"ws-a": 2})
+	eligible := agentsFor("ws-a/a1", "ws-b/b1")
+
+	counts := map[string]int{}
+	for i := 0; i < 30; i++ {
+		first := strategy.Order(context.Background(), eligible, eligible)[0]
+		strategy.Dispatched(first)
+		counts[first.WorkspaceID]++
+	}
+	if counts["ws-a"] != 20 || counts["ws-b"] != 10 {
+		t.Fatalf("expected 20/10 split, got %v", counts)
+	}
+}
+
+func TestPriorityStrategyOrdersByWorkspacePriority(t *testing.T) {
+	strategy := NewPriorityStrategy(map[string]int{"ws-a": 5, "ws-c": -1})
+	agents := agentsFor("ws-c/c1", "ws-b/b1", "ws-a/a1", "ws-b/b2", "ws-a/a2")
+
+	if got := agentIDs(strategy.Order(context.Background(), agents, agents)); got != "a1,a2,b1,b2,c1" {
+		t.Fatalf("unexpected priority order %s", got)
+	}
+	if got := agentIDs(agents); got != "c1,b1,a1,b2,a2" {
+		t.Fatalf("Order mutated its input: %s", got)
+	}
+}
+
+func TestDeadlineFirstStrategyOrdersByQueueHead(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	heads := map[string]*models.QueueItem{
+		"a1": {ID: "qa", CreatedAt: base.Add(2 * time.Minute)},
+		"b1": {ID: "qb", CreatedAt: base},
+		"c1": {ID: "qc", CreatedAt: base.Add(time.Minute)},
+	}
+	var peeked []string
+	strategy := NewDeadlineFirstStrategy(func(_ context.Context, agentID string) *models.QueueItem {
+		peeked = append(peeked, agentID)
+		return heads[agentID]
+	})
+
+	agents := agentsFor("ws-a/a1", "ws-x/x1", "ws-b/b1", "ws-c/c1")
+	if got := agentIDs(strategy.Order(context.Background(), agents, agents)); got != "b1,c1,a1,x1" {
+		t.Fatalf("unexpected deadline order %s", got)
+	}
+	if len(peeked) != len(agents) {
+		t.Fatalf("expected one peek per eligible agent, got %v", peeked)
+	}
+}
+
+func TestDeadlineFirstStrategyWithoutQueueKeepsOrder(t *testing.T) {
+	agents := agentsFor("ws-b/b1", "ws-a/a1")
+	if got := agentIDs(NewDeadlineFirstStrategy(nil).Order(context.Background(), agents, agents)); got != "b1,a1" {
+		t.Fatalf("unexpected order %s", got)
+	}
+}
+
+func TestConfigFromSettingsWorkspacePriorities(t *testing.T) {
+	settings := config.DefaultConfig().Scheduler
+	settings.DispatchPolicy = "priority"
+	settings.WorkspacePriorities = map[string]int{"ws-b": 1}
+	cfg := ConfigFromSettings(settings)
+
+	agents := agentsFor("ws-a/a1", "ws-b/b1")
+	if got := agentIDs(NewStrategy(cfg, nil).Order(context.Background(), agents, agents)); got != "b1,a1" {
+		t.Fatalf("unexpected order %s", got)
+	}
+}
@@ -49,6 +49,8 @@ const (
 	BlockReasonQueueEmpty       BlockReason = "queue_empty"
 	BlockReasonConditionNotMet  BlockReason = "condition_not_met"
 	BlockReasonAwaitingApproval BlockReason = "awaiting_approval"
+	BlockReasonCrashLooping     BlockReason = "agent_crashlooping"
+	BlockReasonStalled          BlockReason = "agent_stalled"
 )
 
 // AgentSnapshot represents the state of an agent at a point in time.
@@ -61,6 +63,8 @@ type AgentSnapshot struct {
 	PausedUntil     *time.Time        `json:"paused_until,omitempty"`
 	SchedulerPaused bool              `json:"scheduler_paused"`
 	RetryAfter      *time.Time        `json:"retry_after,omitempty"`
+	CrashLooping    bool              `json:"crash_looping,omitempty"`
+	Stalled         bool              `json:"stalled,omitempty"`
 }
 
 // QueueItemSnapshot represents a queue item at a point in time.
@@ -280,6 +284,12 @@ func checkEligibilityWithItem(
 	if agent.State == models.AgentStateStopped {
 		return true, BlockReasonStopped
 	}
+	if agent.CrashLooping {
+		return true, BlockReasonCrashLooping
+	}
+	if agent.Stalled {
+		return true, BlockReasonStalled
+	}
 
 	// Special handling for AwaitingApproval state:
 	// Only allow dispatch if the message is a permission response
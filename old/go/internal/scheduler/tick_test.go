@@ -691,3 +691,63 @@ func mustMarshal(v any) json.RawMessage {
 	}
 	return data
 }
+
+func TestTick_CrashLoopingAgentBlocked(t *testing.T) {
+	input := TickInput{
+		Agents: []AgentSnapshot{{
+			ID:           "agent-1",
+			State:        models.AgentStateIdle,
+			QueueLength:  1,
+			CrashLooping: true,
+		}},
+		QueueItems: []QueueItemSnapshot{{
+			ID:      "item-1",
+			AgentID: "agent-1",
+			Type:    models.QueueItemTypeMessage,
+			Status:  models.QueueItemStatusPending,
+			Payload: mustMarshal(models.MessagePayload{Text: "hello"}),
+		}},
+		Now:    time.Now().UTC(),
+		Config: DefaultTickConfig(),
+	}
+
+	result := Tick(input)
+
+	for _, action := range result.Actions {
+		if action.Type == ActionTypeDispatch {
+			t.Fatalf("expected no dispatch to crashlooping agent")
+		}
+	}
+	if blocked := result.Blocked["agent-1"]; blocked != BlockReasonCrashLooping {
+		t.Errorf("blocked = %v, want %v", blocked, BlockReasonCrashLooping)
+	}
+}
+
+func TestTick_StalledAgentBlocked(t *testing.T) {
+	input := TickInput{
+		Agents: []AgentSnapshot{{
+			ID:          "agent-1",
+			State:       models.AgentStateIdle,
+			QueueLength: 1,
+			Stalled:     true,
+		}},
+		QueueItems: []QueueItemSnapshot{{
+			ID:      "item-1",
+			AgentID: "agent-1",
+			Type:    models.QueueItemTypeMessage,
+			Status:  models.QueueItemStatusPending,
+			Payload: mustMarshal(models.MessagePayload{Text: "hello"}),
+		}},
+		Now:    time.Now().UTC(),
+		Config: DefaultTickConfig(),
+	}
+
+	result := Tick(input)
+
+	if len(result.Actions) != 0 {
+		t.Fatalf("expected no actions for a stalled agent, got %+v", result.Actions)
+	}
+	if blocked := result.Blocked["agent-1"]; blocked != BlockReasonStalled {
+		t.Errorf("blocked = %v, want %v", blocked, BlockReasonStalled)
+	}
+}
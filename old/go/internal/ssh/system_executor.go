@@ -114,6 +114,13 @@ func (s *SystemSession) Close() error {
 	return nil
 }
 
+// BuildSSHArgs returns the ssh flags and user@host target for the given options.
+// Tools that tunnel through the system ssh binary (e.g. rsync -e) use it to
+// honour the same connection settings as the executor.
+func BuildSSHArgs(options ConnectionOptions) ([]string, string) {
+	return buildSSHArgs(options)
+}
+
 func buildSSHArgs(options ConnectionOptions) ([]string, string) {
 	args := []string{}
 	if options.Port > 0 {
@@ -92,6 +92,11 @@ type Engine struct {
 	statsCollector *ProcessStatsCollector
 	mu             sync.RWMutex
 	logger         zerolog.Logger
+
+	// outbox, when set, receives state change events instead of eventRepo;
+	// notifyOutbox wakes its dispatcher after the write commits.
+	outbox       *db.OutboxRepository
+	notifyOutbox func()
 }
 
 // NewEngine creates a new StateEngine.
@@ -107,6 +112,14 @@ func NewEngine(repo *db.AgentRepository, eventRepo *db.EventRepository, tmuxClie
 	}
 }
 
+// SetOutbox routes state change events through the transactional outbox so
+// they are both logged and published to subscribers by the outbox
+// dispatcher. notify may be nil.
+func (e *Engine) SetOutbox(outbox *db.OutboxRepository, notify func()) {
+	e.outbox = outbox
+	e.notifyOutbox = notify
+}
+
 // GetState retrieves the current state for an agent.
 func (e *Engine) GetState(ctx context.Context, agentID string) (*models.StateInfo, error) {
 	agent, err := e.repo.Get(ctx, agentID)
@@ -171,7 +184,18 @@ func (e *Engine) UpdateStateWithStats(ctx context.Context, agentID string, state
 		agent.Metadata.ProcessStats = stats
 	}
 
-	if previousState != state && e.eventRepo != nil {
+	if previousState != state && e.outbox != nil {
+		event, err := buildStateChangeEvent(agentID, previousState, state, info, now)
+		if err != nil {
+			return err
+		}
+		if err := e.repo.UpdateWithOutbox(ctx, agent, event, e.outbox); err != nil {
+			return err
+		}
+		if e.notifyOutbox != nil {
+			e.notifyOutbox()
+		}
+	} else if previousState != state && e.eventRepo != nil {
 		event, err := buildStateChangeEvent(agentID, previousState, state, info, now)
 		if err != nil {
 			return err
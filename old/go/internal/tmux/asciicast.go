@@ -0,0 +1,286 @@
+package tmux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CastExt is the file extension of asciicast recordings.
+const CastExt = ".cast"
+
+// recordMaxCaptureFailures is how many consecutive failed captures end a
+// recording; a killed pane fails every capture.
+const recordMaxCaptureFailures = 3
+
+// CastOptions controls how a CastWriter rotates and prunes its files.
+type CastOptions struct {
+	// MaxBytes starts a new file once the current one reaches this size.
+	// Zero disables size-based rotation.
+	MaxBytes int64
+
+	// MaxDuration starts a new file once the current one spans this long.
+	// Zero disables time-based rotation.
+	MaxDuration time.Duration
+
+	// MaxFiles is how many recordings are kept in the directory; the oldest
+	// are removed on rotation. Zero keeps every file.
+	MaxFiles int
+
+	// Title is written to each file header.
+	Title string
+}
+
+// CastFile describes a recording on disk.
+type CastFile struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// CastWriter writes terminal output as asciicast v2 files named
+// <prefix>-<seq>.cast in dir, rotating to a new file with a fresh header
+// when the size or duration limit is reached.
+type CastWriter struct {
+	dir    string
+	prefix string
+	opts   CastOptions
+	now    func() time.Time
+
+	file    *os.File
+	seq     int
+	size    int64
+	started time.Time
+	width   int
+	height  int
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// NewCastWriter creates a writer for dir. No file is created until the
+// first Write, which supplies the terminal size for the header.
+func NewCastWriter(dir, prefix string, opts CastOptions) (*CastWriter, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("recording directory is required")
+	}
+	if strings.TrimSpace(prefix) == "" {
+		return nil, fmt.Errorf("recording prefix is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	return &CastWriter{dir: dir, prefix: prefix, opts: opts, now: time.Now}, nil
+}
+
+// Path returns the file currently being written, or "" before the first Write.
+func (w *CastWriter) Path() string {
+	if w.file == nil {
+		return ""
+	}
+	return w.file.Name()
+}
+
+// Write records data as output of a width x height terminal. A size change
+// is recorded as a resize event.
+func (w *CastWriter) Write(width, height int, data string) error {
+	now := w.now()
+	if w.file != nil && w.rotationDue(now) {
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		if err := w.open(width, height, now); err != nil {
+			return err
+		}
+	} else if width != w.width || height != w.height {
+		w.width, w.height = width, height
+		if err := w.event(now, "r", fmt.Sprintf("%dx%d", width, height)); err != nil {
+			return err
+		}
+	}
+	return w.event(now, "o", data)
+}
+
+// Close closes the current file. A later Write starts a new one.
+func (w *CastWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *CastWriter) rotationDue(now time.Time) bool {
+	if w.opts.MaxBytes > 0 && w.size >= w.opts.MaxBytes {
+		return true
+	}
+	return w.opts.MaxDuration > 0 && now.Sub(w.started) >= w.opts.MaxDuration
+}
+
+func (w *CastWriter) open(width, height int, now time.Time) error {
+	w.seq++
+	path := filepath.Join(w.dir, fmt.Sprintf("%s-%03d%s", w.prefix, w.seq, CastExt))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: now.Unix(),
+		Title:     w.opts.Title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	n, err := file.Write(append(header, '\n'))
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write recording header: %w", err)
+	}
+
+	w.file = file
+	w.size = int64(n)
+	w.started = now
+	w.width, w.height = width, height
+	return w.prune()
+}
+
+func (w *CastWriter) event(now time.Time, kind, data string) error {
+	line, err := json.Marshal([]any{now.Sub(w.started).Seconds(), kind, data})
+	if err != nil {
+		return err
+	}
+	n, err := w.file.Write(append(line, '\n'))
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// prune removes the oldest recordings in dir beyond MaxFiles.
+func (w *CastWriter) prune() error {
+	if w.opts.MaxFiles <= 0 {
+		return nil
+	}
+	files, err := ListCasts(w.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > w.opts.MaxFiles {
+		if files[0].Path != w.Path() {
+			if err := os.Remove(files[0].Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove old recording: %w", err)
+			}
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// ListCasts returns the recordings in dir, oldest first. A missing
+// directory has no recordings.
+func ListCasts(dir string) ([]CastFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	files := make([]CastFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != CastExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, CastFile{
+			Name:    entry.Name(),
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].ModTime.Equal(files[j].ModTime) {
+			return files[i].ModTime.Before(files[j].ModTime)
+		}
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+// RecordPane captures target every interval and writes each changed screen
+// to w as a full redraw, so any rotated file replays on its own. It returns
+// nil when ctx is done and an error once captures keep failing, which is
+// how a killed pane ends its recording.
+func (c *Client) RecordPane(ctx context.Context, target string, w *CastWriter, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastHash string
+	failures := 0
+	for {
+		screen, err := c.CaptureScreen(ctx, target)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			failures++
+			if failures >= recordMaxCaptureFailures {
+				return err
+			}
+		default:
+			failures = 0
+			if screen.Hash != lastHash {
+				if err := w.Write(screen.Width, screen.Height, renderCastFrame(screen)); err != nil {
+					return err
+				}
+				lastHash = screen.Hash
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderCastFrame clears the terminal, draws every line, and restores the
+// cursor position.
+func renderCastFrame(screen *ScreenState) string {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	b.WriteString(strings.Join(screen.Lines, "\r\n"))
+	fmt.Fprintf(&b, "\x1b[%d;%dH", screen.CursorY+1, screen.CursorX+1)
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package tmux
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readCastLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestCastWriter_WritesHeaderEventsAndResize(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewCastWriter(dir, "session", CastOptions{Title: "agent"})
+	if err != nil {
+		t.Fatalf("NewCastWriter failed: %v", err)
+	}
+	start := time.Unix(1700000000, 0)
+	now := start
+	writer.now = func() time.Time { return now }
+
+	if err := writer.Write(80, 24, "hello"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	now = start.Add(1500 * time.Millisecond)
+	if err := writer.Write(100, 30, "world"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	path := writer.Path()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if filepath.Base(path) != "session-001.cast" {
+		t.Fatalf("unexpected file name %q", path)
+	}
+
+	lines := readCastLines(t, path)
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 events, got %q", lines)
+	}
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Timestamp != start.Unix() || header.Title != "agent" {
+		t.Fatalf("unexpected header %+v", header)
+	}
+	want := []string{`[0,"o","hello"]`, `[1.5,"r","100x30"]`, `[1.5,"o","world"]`}
+	for i, line := range want {
+		if lines[i+1] != line {
+			t.Fatalf("event %d = %s, want %s", i, lines[i+1], line)
+		}
+	}
+}
+
+func TestCastWriter_RotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewCastWriter(dir, "session", CastOptions{MaxDuration: time.Minute, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("NewCastWriter failed: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	writer.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		if err := writer.Write(80, 24, "frame"); err != nil {
+			t.Fatalf("Write %d failed: %v", i, err)
+		}
+		now = now.Add(time.Minute)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	files, err := ListCasts(dir)
+	if err != nil {
+		t.Fatalf("ListCasts failed: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if strings.Join(names, ",") != "session-003.cast,session-004.cast" {
+		t.Fatalf("unexpected recordings %v", names)
+	}
+	if lines := readCastLines(t, files[1].Path); len(lines) != 2 || !strings.HasPrefix(lines[0], `{"version":2`) {
+		t.Fatalf("rotated file should start with its own header, got %q", lines)
+	}
+}
+
+func TestListCasts_MissingDir(t *testing.T) {
+	files, err := ListCasts(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(files) != 0 {
+		t.Fatalf("expected no recordings, got %v, %v", files, err)
+	}
+}
+
+func TestRecordPane_WritesChangedScreensUntilPaneGone(t *testing.T) {
+	exec := &fakeExecutor{
+		stdoutQueue: [][]byte{
+			[]byte("0,0,80,24\n"), []byte("$ make\n"),
+			[]byte("0,0,80,24\n"), []byte("$ make\n"),
+			[]byte("2,1,80,24\n"), []byte("$ make\nok\n"),
+		},
+		errQueue: []error{nil, nil, nil, nil, nil, nil},
+		stderr:   []byte("can't find pane: %1"),
+		err:      errors.New("exit status 1"),
+	}
+	client := NewClient(exec)
+	writer, err := NewCastWriter(t.TempDir(), "session", CastOptions{})
+	if err != nil {
+		t.Fatalf("NewCastWriter failed: %v", err)
+	}
+
+	err = client.RecordPane(context.Background(), "%1", writer, time.Millisecond)
+	if err == nil {
+		t.Fatalf("expected recording to end with the capture error")
+	}
+	path := writer.Path()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := readCastLines(t, path)
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two frames, got %q", lines)
+	}
+	var event []any
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event[1] != "o" || event[2] != "\x1b[H\x1b[2J$ make\r\nok\x1b[2;3H" {
+		t.Fatalf("unexpected frame %q", event)
+	}
+}
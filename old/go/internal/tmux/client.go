@@ -8,6 +8,9 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/tOgg1/forge/internal/tracing"
 )
 
 // Executor runs tmux commands.
@@ -36,6 +39,9 @@ func (e *LocalExecutor) Exec(ctx context.Context, cmd string) (stdout, stderr []
 // Client wraps tmux command helpers.
 type Client struct {
 	exec Executor
+
+	capsMu sync.Mutex
+	caps   *Capabilities // set by a successful Probe
 }
 
 // AgentWindowName is the default window name used for agent panes.
@@ -51,6 +57,25 @@ func NewLocalClient() *Client {
 	return &Client{exec: &LocalExecutor{}}
 }
 
+// run executes a tmux command, recording a span when the caller is traced.
+// Only the tmux subcommand is recorded; arguments may carry message text.
+func (c *Client) run(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	ctx, span := tracing.StartChild(ctx, "tmux.exec", tracing.String("tmux.command", tmuxSubcommand(cmd)))
+	defer func() { span.RecordError(err); span.End() }()
+	return c.exec.Exec(ctx, cmd)
+}
+
+func tmuxSubcommand(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) >= 2 && fields[0] == "tmux" {
+		return fields[1]
+	}
+	if len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
 // Session describes a tmux session.
 type Session struct {
 	Name        string
@@ -59,7 +84,7 @@ type Session struct {
 
 // ListSessions returns all known tmux sessions.
 func (c *Client) ListSessions(ctx context.Context) ([]Session, error) {
-	stdout, stderr, err := c.exec.Exec(ctx, "tmux list-sessions -F '#{session_name}|#{session_windows}'")
+	stdout, stderr, err := c.run(ctx, "tmux list-sessions -F '#{session_name}|#{session_windows}'")
 	if err != nil {
 		if isNoServerRunning(stderr) {
 			return []Session{}, nil
@@ -102,7 +127,7 @@ func (c *Client) ListPanePaths(ctx context.Context, session string) ([]string, e
 	}
 
 	cmd := fmt.Sprintf("tmux list-panes -t %s -F '#{pane_id}|#{pane_current_path}'", session)
-	stdout, stderr, err := c.exec.Exec(ctx, cmd)
+	stdout, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isNoServerRunning(stderr) {
 			return []string{}, nil
@@ -145,7 +170,7 @@ func (c *Client) HasSession(ctx context.Context, session string) (bool, error) {
 	}
 
 	cmd := fmt.Sprintf("tmux has-session -t %s", escapeSessionName(session))
-	_, stderr, err := c.exec.Exec(ctx, cmd)
+	_, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		// "no server running" or "session not found" both mean session doesn't exist
 		if isNoServerRunning(stderr) || isSessionNotFound(stderr) {
@@ -169,7 +194,7 @@ func (c *Client) NewSession(ctx context.Context, session, workDir string) error
 		cmd = fmt.Sprintf("%s -c %s", cmd, escapeArg(workDir))
 	}
 
-	_, stderr, err := c.exec.Exec(ctx, cmd)
+	_, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isDuplicateSession(stderr) {
 			return ErrSessionExists
@@ -224,7 +249,7 @@ func (c *Client) NewWindow(ctx context.Context, session, name, workDir string) e
 		cmd = fmt.Sprintf("%s -c %s", cmd, escapeArg(workDir))
 	}
 
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux new-window failed: %w", err)
 	}
 
@@ -241,7 +266,7 @@ func (c *Client) HasWindow(ctx context.Context, session, name string) (bool, err
 	}
 
 	cmd := fmt.Sprintf("tmux list-windows -t %s -F '#{window_name}'", escapeSessionName(session))
-	stdout, stderr, err := c.exec.Exec(ctx, cmd)
+	stdout, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isNoServerRunning(stderr) || isSessionNotFound(stderr) {
 			return false, nil
@@ -297,7 +322,7 @@ func (c *Client) SelectWindow(ctx context.Context, target string) error {
 	}
 
 	cmd := fmt.Sprintf("tmux select-window -t %s", escapeArg(target))
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux select-window failed: %w", err)
 	}
 
@@ -314,7 +339,7 @@ func (c *Client) SelectLayout(ctx context.Context, target string, layout string)
 	}
 
 	cmd := fmt.Sprintf("tmux select-layout -t %s %s", escapeArg(target), escapeArg(layout))
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux select-layout failed: %w", err)
 	}
 
@@ -329,7 +354,7 @@ func (c *Client) KillSession(ctx context.Context, session string) error {
 	}
 
 	cmd := fmt.Sprintf("tmux kill-session -t %s", escapeSessionName(session))
-	_, stderr, err := c.exec.Exec(ctx, cmd)
+	_, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isNoServerRunning(stderr) || isSessionNotFound(stderr) {
 			return ErrSessionNotFound
@@ -372,7 +397,7 @@ func (c *Client) ListPanes(ctx context.Context, session string) ([]Pane, error)
 	}
 
 	cmd := fmt.Sprintf("tmux list-panes -t %s -F '#{pane_id}|#{window_index}|#{pane_index}|#{pane_current_path}|#{pane_active}|#{pane_current_command}'", escapeSessionName(session))
-	stdout, stderr, err := c.exec.Exec(ctx, cmd)
+	stdout, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isNoServerRunning(stderr) {
 			return []Pane{}, nil
@@ -426,7 +451,7 @@ func (c *Client) SplitWindow(ctx context.Context, target string, horizontal bool
 		cmd = fmt.Sprintf("%s -c %s", cmd, escapeArg(workDir))
 	}
 
-	stdout, _, err := c.exec.Exec(ctx, cmd)
+	stdout, _, err := c.run(ctx, cmd)
 	if err != nil {
 		return "", fmt.Errorf("tmux split-window failed: %w", err)
 	}
@@ -451,13 +476,13 @@ func (c *Client) SendKeys(ctx context.Context, target, keys string, literal, ent
 	escapedKeys := escapeArg(keys)
 	cmd := fmt.Sprintf("tmux send-keys -t %s %s %s", escapeArg(target), literalFlag, escapedKeys)
 
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux send-keys failed: %w", err)
 	}
 
 	if enter {
 		enterCmd := fmt.Sprintf("tmux send-keys -t %s Enter", escapeArg(target))
-		if _, _, err := c.exec.Exec(ctx, enterCmd); err != nil {
+		if _, _, err := c.run(ctx, enterCmd); err != nil {
 			return fmt.Errorf("tmux send-keys Enter failed: %w", err)
 		}
 	}
@@ -472,7 +497,7 @@ func (c *Client) SendInterrupt(ctx context.Context, target string) error {
 	}
 
 	cmd := fmt.Sprintf("tmux send-keys -t %s C-c", escapeArg(target))
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux send-keys C-c failed: %w", err)
 	}
 
@@ -539,7 +564,7 @@ func (c *Client) HistorySize(ctx context.Context, target string) (int, error) {
 	}
 
 	cmd := fmt.Sprintf("tmux display-message -p -t %s '#{history_size}'", escapeArg(target))
-	stdout, _, err := c.exec.Exec(ctx, cmd)
+	stdout, _, err := c.run(ctx, cmd)
 	if err != nil {
 		return 0, fmt.Errorf("tmux display-message failed: %w", err)
 	}
@@ -622,7 +647,7 @@ func (c *Client) capturePaneRange(ctx context.Context, target, start, end string
 		cmd = fmt.Sprintf("%s -E %s", cmd, end)
 	}
 
-	stdout, _, err := c.exec.Exec(ctx, cmd)
+	stdout, _, err := c.run(ctx, cmd)
 	if err != nil {
 		return "", fmt.Errorf("tmux capture-pane failed: %w", err)
 	}
@@ -637,7 +662,7 @@ func (c *Client) GetPanePID(ctx context.Context, target string) (int, error) {
 	}
 
 	cmd := fmt.Sprintf("tmux display-message -p -t %s '#{pane_pid}'", escapeArg(target))
-	stdout, _, err := c.exec.Exec(ctx, cmd)
+	stdout, _, err := c.run(ctx, cmd)
 	if err != nil {
 		return 0, fmt.Errorf("tmux display-message failed: %w", err)
 	}
@@ -661,7 +686,7 @@ func (c *Client) KillPane(ctx context.Context, target string) error {
 	}
 
 	cmd := fmt.Sprintf("tmux kill-pane -t %s", escapeArg(target))
-	_, stderr, err := c.exec.Exec(ctx, cmd)
+	_, stderr, err := c.run(ctx, cmd)
 	if err != nil {
 		if isPaneNotFound(stderr) {
 			return ErrPaneNotFound
@@ -694,7 +719,7 @@ func (c *Client) SelectPane(ctx context.Context, target string) error {
 	}
 
 	cmd := fmt.Sprintf("tmux select-pane -t %s", escapeArg(target))
-	if _, _, err := c.exec.Exec(ctx, cmd); err != nil {
+	if _, _, err := c.run(ctx, cmd); err != nil {
 		return fmt.Errorf("tmux select-pane failed: %w", err)
 	}
 
@@ -0,0 +1,154 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AgentIDOption is the user pane option holding the logical agent ID. Tags
+// survive pane renumbering and window moves, so panes can be matched back to
+// agents without relying on pane indexes or working directories.
+const AgentIDOption = "@forge_agent_id"
+
+// TaggedPane is a pane carrying a Forge agent tag.
+type TaggedPane struct {
+	Pane
+	Session string
+	AgentID string
+}
+
+// SetPaneOption sets a pane-scoped user option (name must start with "@").
+// Pane options need tmux 3.0; when the client has already probed an older
+// tmux it returns an UnsupportedError without running the command.
+func (c *Client) SetPaneOption(ctx context.Context, target, name, value string) error {
+	if strings.TrimSpace(target) == "" {
+		return fmt.Errorf("target is required")
+	}
+	if !strings.HasPrefix(name, "@") {
+		return fmt.Errorf("pane option %q must start with @", name)
+	}
+	if err := c.requireIfProbed(FeaturePaneOptions); err != nil {
+		return err
+	}
+
+	cmd := fmt.Sprintf("tmux set-option -p -t %s %s %s", escapeArg(target), name, escapeArg(value))
+	_, stderr, err := c.run(ctx, cmd)
+	if err != nil {
+		if isPaneNotFound(stderr) {
+			return ErrPaneNotFound
+		}
+		return fmt.Errorf("tmux set-option failed: %w", err)
+	}
+	return nil
+}
+
+// PaneOption returns a pane-scoped user option, or "" when it is unset.
+func (c *Client) PaneOption(ctx context.Context, target, name string) (string, error) {
+	if strings.TrimSpace(target) == "" {
+		return "", fmt.Errorf("target is required")
+	}
+	if !strings.HasPrefix(name, "@") {
+		return "", fmt.Errorf("pane option %q must start with @", name)
+	}
+
+	cmd := fmt.Sprintf("tmux display-message -p -t %s '#{%s}'", escapeArg(target), name)
+	stdout, stderr, err := c.run(ctx, cmd)
+	if err != nil {
+		if isPaneNotFound(stderr) {
+			return "", ErrPaneNotFound
+		}
+		return "", fmt.Errorf("tmux display-message failed: %w", err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// TagPaneAgent tags a pane with the agent ID it hosts.
+func (c *Client) TagPaneAgent(ctx context.Context, target, agentID string) error {
+	if strings.TrimSpace(agentID) == "" {
+		return fmt.Errorf("agent id is required")
+	}
+	return c.SetPaneOption(ctx, target, AgentIDOption, agentID)
+}
+
+// PaneAgentID returns the agent ID a pane is tagged with, or "".
+func (c *Client) PaneAgentID(ctx context.Context, target string) (string, error) {
+	return c.PaneOption(ctx, target, AgentIDOption)
+}
+
+// ListAllPanes returns every pane on the server with its session and agent
+// tag; AgentID is empty for untagged panes.
+func (c *Client) ListAllPanes(ctx context.Context) ([]TaggedPane, error) {
+	cmd := fmt.Sprintf("tmux list-panes -a -F '#{%s}|#{session_name}|#{pane_id}|#{window_index}|#{pane_index}|#{pane_current_path}|#{pane_active}|#{pane_current_command}'", AgentIDOption)
+	stdout, stderr, err := c.run(ctx, cmd)
+	if err != nil {
+		if isNoServerRunning(stderr) {
+			return []TaggedPane{}, nil
+		}
+		return nil, fmt.Errorf("tmux list-panes failed: %w", err)
+	}
+
+	output := strings.TrimSpace(string(stdout))
+	if output == "" {
+		return []TaggedPane{}, nil
+	}
+
+	panes := []TaggedPane{}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "|", 8)
+		if len(parts) != 8 {
+			return nil, fmt.Errorf("unexpected tmux output line: %q", line)
+		}
+
+		windowIndex, _ := strconv.Atoi(strings.TrimSpace(parts[3]))
+		index, _ := strconv.Atoi(strings.TrimSpace(parts[4]))
+		panes = append(panes, TaggedPane{
+			AgentID: strings.TrimSpace(parts[0]),
+			Session: strings.TrimSpace(parts[1]),
+			Pane: Pane{
+				ID:          strings.TrimSpace(parts[2]),
+				WindowIndex: windowIndex,
+				Index:       index,
+				CurrentDir:  strings.TrimSpace(parts[5]),
+				Active:      strings.TrimSpace(parts[6]) == "1",
+				Command:     strings.TrimSpace(parts[7]),
+			},
+		})
+	}
+	return panes, nil
+}
+
+// ListTaggedPanes returns every pane on the server tagged with an agent ID.
+func (c *Client) ListTaggedPanes(ctx context.Context) ([]TaggedPane, error) {
+	panes, err := c.ListAllPanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tagged := []TaggedPane{}
+	for _, pane := range panes {
+		if pane.AgentID != "" {
+			tagged = append(tagged, pane)
+		}
+	}
+	return tagged, nil
+}
+
+// FindPaneByAgentID returns the pane tagged with agentID.
+// Returns ErrPaneNotFound if no pane carries the tag.
+func (c *Client) FindPaneByAgentID(ctx context.Context, agentID string) (TaggedPane, error) {
+	agentID = strings.TrimSpace(agentID)
+	if agentID == "" {
+		return TaggedPane{}, fmt.Errorf("agent id is required")
+	}
+	panes, err := c.ListTaggedPanes(ctx)
+	if err != nil {
+		return TaggedPane{}, err
+	}
+	for _, pane := range panes {
+		if pane.AgentID == agentID {
+			return pane, nil
+		}
+	}
+	return TaggedPane{}, ErrPaneNotFound
+}
@@ -0,0 +1,96 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTagPaneAgent(t *testing.T) {
+	exec := &fakeExecutor{}
+	client := NewClient(exec)
+
+	if err := client.TagPaneAgent(context.Background(), "%3", "agent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "tmux set-option -p -t '%3' @forge_agent_id 'agent-1'"
+	if exec.lastCmd != want {
+		t.Fatalf("expected %q, got %q", want, exec.lastCmd)
+	}
+
+	if err := client.SetPaneOption(context.Background(), "%3", "forge_agent_id", "x"); err == nil {
+		t.Fatal("expected error for option without @ prefix")
+	}
+}
+
+func TestTagPaneAgent_PaneNotFound(t *testing.T) {
+	exec := &fakeExecutor{err: errors.New("exit status 1"), stderr: []byte("can't find pane: %9")}
+	client := NewClient(exec)
+
+	err := client.TagPaneAgent(context.Background(), "%9", "agent-1")
+	if !errors.Is(err, ErrPaneNotFound) {
+		t.Fatalf("expected ErrPaneNotFound, got %v", err)
+	}
+}
+
+func TestPaneAgentID(t *testing.T) {
+	exec := &fakeExecutor{stdout: []byte("agent-1\n")}
+	client := NewClient(exec)
+
+	id, err := client.PaneAgentID(context.Background(), "%3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "agent-1" {
+		t.Fatalf("expected agent-1, got %q", id)
+	}
+	if !strings.Contains(exec.lastCmd, "#{@forge_agent_id}") {
+		t.Fatalf("unexpected command: %q", exec.lastCmd)
+	}
+}
+
+func TestFindPaneByAgentID(t *testing.T) {
+	exec := &fakeExecutor{stdout: []byte(
+		"|other|%1|0|0|/home/user|1|bash\n" +
+			"agent-1|forge-demo|%4|1|2|/repo/demo|0|claude\n" +
+			"agent-2|forge-demo|%5|1|3|/repo/demo|1|codex\n",
+	)}
+	client := NewClient(exec)
+
+	panes, err := client.ListTaggedPanes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(panes) != 2 {
+		t.Fatalf("expected 2 tagged panes, got %d", len(panes))
+	}
+	if !strings.Contains(exec.lastCmd, "list-panes -a") {
+		t.Fatalf("expected server-wide listing, got %q", exec.lastCmd)
+	}
+
+	pane, err := client.FindPaneByAgentID(context.Background(), "agent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pane.ID != "%4" || pane.Session != "forge-demo" || pane.WindowIndex != 1 || pane.Index != 2 || pane.Command != "claude" {
+		t.Fatalf("unexpected pane: %+v", pane)
+	}
+
+	if _, err := client.FindPaneByAgentID(context.Background(), "agent-9"); !errors.Is(err, ErrPaneNotFound) {
+		t.Fatalf("expected ErrPaneNotFound, got %v", err)
+	}
+}
+
+func TestListTaggedPanes_NoServer(t *testing.T) {
+	exec := &fakeExecutor{err: errors.New("exit status 1"), stderr: []byte("no server running on /tmp/tmux-1000/default")}
+	client := NewClient(exec)
+
+	panes, err := client.ListTaggedPanes(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(panes) != 0 {
+		t.Fatalf("expected no panes, got %d", len(panes))
+	}
+}
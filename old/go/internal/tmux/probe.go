@@ -0,0 +1,178 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Feature is a tmux capability that is only available from some version on.
+type Feature string
+
+const (
+	// FeaturePipePane is pipe-pane with the -I/-O direction flags (tmux 2.7).
+	FeaturePipePane Feature = "pipe-pane"
+	// FeaturePaneOptions is pane-scoped options via set-option -p (tmux 3.0).
+	FeaturePaneOptions Feature = "pane-options"
+	// FeaturePopup is display-popup (tmux 3.2).
+	FeaturePopup Feature = "popup"
+)
+
+// featureVersions maps each feature to the first tmux version that has it.
+var featureVersions = map[Feature]Version{
+	FeaturePipePane:    {Major: 2, Minor: 7},
+	FeaturePaneOptions: {Major: 3, Minor: 0},
+	FeaturePopup:       {Major: 3, Minor: 2},
+}
+
+// Features lists the known features in the order they were introduced.
+var Features = []Feature{FeaturePipePane, FeaturePaneOptions, FeaturePopup}
+
+// RequiredVersion returns the first tmux version that supports f.
+func RequiredVersion(f Feature) (Version, bool) {
+	v, ok := featureVersions[f]
+	return v, ok
+}
+
+var (
+	// ErrNotInstalled is returned by Probe when tmux cannot be run.
+	ErrNotInstalled = errors.New("tmux is not installed")
+	// ErrUnsupported matches any UnsupportedError.
+	ErrUnsupported = errors.New("tmux feature not supported")
+)
+
+// UnsupportedError reports a feature the installed tmux is too old for.
+type UnsupportedError struct {
+	Feature Feature
+	Have    Version
+	Need    Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("tmux %s does not support %s (requires tmux %s or newer)", e.Have, e.Feature, e.Need)
+}
+
+// Is makes errors.Is(err, ErrUnsupported) match.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// Capabilities describes the tmux a client talks to.
+type Capabilities struct {
+	Raw         string
+	Version     Version
+	PipePane    bool
+	PaneOptions bool
+	Popup       bool
+}
+
+// DetectCapabilities derives capabilities from `tmux -V` output.
+func DetectCapabilities(output string) (Capabilities, error) {
+	raw := strings.TrimSpace(output)
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	caps := Capabilities{Raw: raw, Version: version}
+	caps.PipePane = caps.Supports(FeaturePipePane)
+	caps.PaneOptions = caps.Supports(FeaturePaneOptions)
+	caps.Popup = caps.Supports(FeaturePopup)
+	return caps, nil
+}
+
+// Supports reports whether the probed version has feature f. Unknown
+// features are reported as unsupported.
+func (c Capabilities) Supports(f Feature) bool {
+	need, ok := featureVersions[f]
+	if !ok {
+		return false
+	}
+	return !c.Version.LessThan(need)
+}
+
+// Missing returns the known features the probed version lacks.
+func (c Capabilities) Missing() []Feature {
+	var missing []Feature
+	for _, f := range Features {
+		if !c.Supports(f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// Require returns an UnsupportedError when f is not available.
+func (c Capabilities) Require(f Feature) error {
+	if c.Supports(f) {
+		return nil
+	}
+	need, ok := featureVersions[f]
+	if !ok {
+		return fmt.Errorf("unknown tmux feature %q", f)
+	}
+	return &UnsupportedError{Feature: f, Have: c.Version, Need: need}
+}
+
+// Probe runs `tmux -V` and reports the version and feature support. A
+// successful result is cached for the lifetime of the client; failures are
+// not, so a later call picks up a freshly installed tmux.
+func (c *Client) Probe(ctx context.Context) (Capabilities, error) {
+	if caps, ok := c.cachedCapabilities(); ok {
+		return caps, nil
+	}
+
+	stdout, stderr, err := c.run(ctx, "tmux -V")
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return Capabilities{}, fmt.Errorf("%w: %s", ErrNotInstalled, msg)
+		}
+		return Capabilities{}, fmt.Errorf("%w: %v", ErrNotInstalled, err)
+	}
+	caps, err := DetectCapabilities(string(stdout))
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("failed to parse tmux version: %w", err)
+	}
+
+	c.capsMu.Lock()
+	c.caps = &caps
+	c.capsMu.Unlock()
+	return caps, nil
+}
+
+// Require probes tmux and returns an error when feature f is unavailable.
+// Callers that can work without f should check errors.Is(err, ErrUnsupported)
+// and fall back.
+func (c *Client) Require(ctx context.Context, f Feature) error {
+	caps, err := c.Probe(ctx)
+	if err != nil {
+		return err
+	}
+	return caps.Require(f)
+}
+
+// ResetProbe drops the cached capabilities, e.g. after tmux was upgraded.
+func (c *Client) ResetProbe() {
+	c.capsMu.Lock()
+	c.caps = nil
+	c.capsMu.Unlock()
+}
+
+func (c *Client) cachedCapabilities() (Capabilities, bool) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+	if c.caps == nil {
+		return Capabilities{}, false
+	}
+	return *c.caps, true
+}
+
+// requireIfProbed checks f against cached capabilities only. Commands use it
+// to fail fast on a known-old tmux without adding a probe round trip.
+func (c *Client) requireIfProbed(f Feature) error {
+	caps, ok := c.cachedCapabilities()
+	if !ok {
+		return nil
+	}
+	return caps.Require(f)
+}
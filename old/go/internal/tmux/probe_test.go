@@ -0,0 +1,112 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		output      string
+		pipePane    bool
+		paneOptions bool
+		popup       bool
+	}{
+		{output: "tmux 2.4", pipePane: false, paneOptions: false, popup: false},
+		{output: "tmux 2.9a", pipePane: true, paneOptions: false, popup: false},
+		{output: "tmux 3.1c", pipePane: true, paneOptions: true, popup: false},
+		{output: "tmux 3.3a\n", pipePane: true, paneOptions: true, popup: true},
+	}
+	for _, tt := range tests {
+		caps, err := DetectCapabilities(tt.output)
+		if err != nil {
+			t.Fatalf("DetectCapabilities(%q): %v", tt.output, err)
+		}
+		if caps.PipePane != tt.pipePane || caps.PaneOptions != tt.paneOptions || caps.Popup != tt.popup {
+			t.Fatalf("DetectCapabilities(%q) = %+v", tt.output, caps)
+		}
+	}
+
+	if _, err := DetectCapabilities("invalid"); err == nil {
+		t.Fatal("expected error for unparseable output")
+	}
+}
+
+func TestProbeCachesSuccess(t *testing.T) {
+	exec := &fakeExecutor{stdout: []byte("tmux 3.3a\n")}
+	client := NewClient(exec)
+
+	for i := 0; i < 2; i++ {
+		caps, err := client.Probe(context.Background())
+		if err != nil {
+			t.Fatalf("Probe: %v", err)
+		}
+		if caps.Version != (Version{Major: 3, Minor: 3}) || !caps.Popup {
+			t.Fatalf("unexpected capabilities: %+v", caps)
+		}
+	}
+	if len(exec.commands) != 1 || exec.commands[0] != "tmux -V" {
+		t.Fatalf("expected a single tmux -V, got %v", exec.commands)
+	}
+
+	client.ResetProbe()
+	if _, err := client.Probe(context.Background()); err != nil {
+		t.Fatalf("Probe after reset: %v", err)
+	}
+	if len(exec.commands) != 2 {
+		t.Fatalf("expected reset to force a new probe, got %v", exec.commands)
+	}
+}
+
+func TestProbeNotInstalled(t *testing.T) {
+	exec := &fakeExecutor{
+		stderr:      []byte("sh: 1: tmux: not found"),
+		errQueue:    []error{errors.New("exit status 127")},
+		stdoutQueue: [][]byte{nil, []byte("tmux 3.0")},
+	}
+	client := NewClient(exec)
+
+	_, err := client.Probe(context.Background())
+	if !errors.Is(err, ErrNotInstalled) || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected ErrNotInstalled, got %v", err)
+	}
+
+	// Failures are not cached.
+	exec.stderr = nil
+	if _, err := client.Probe(context.Background()); err != nil {
+		t.Fatalf("expected second probe to succeed, got %v", err)
+	}
+}
+
+func TestRequireUnsupported(t *testing.T) {
+	client := NewClient(&fakeExecutor{stdout: []byte("tmux 3.1")})
+
+	if err := client.Require(context.Background(), FeaturePaneOptions); err != nil {
+		t.Fatalf("expected pane options on 3.1, got %v", err)
+	}
+	err := client.Require(context.Background(), FeaturePopup)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	if err.Error() != "tmux 3.1 does not support popup (requires tmux 3.2 or newer)" {
+		t.Fatalf("unexpected message: %q", err.Error())
+	}
+}
+
+func TestSetPaneOptionSkipsOnProbedOldTmux(t *testing.T) {
+	exec := &fakeExecutor{stdout: []byte("tmux 2.9")}
+	client := NewClient(exec)
+	if _, err := client.Probe(context.Background()); err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+
+	err := client.SetPaneOption(context.Background(), "%3", AgentIDOption, "agent-1")
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	if len(exec.commands) != 1 {
+		t.Fatalf("expected no set-option after probe, got %v", exec.commands)
+	}
+}
@@ -0,0 +1,184 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScreenState is the visible content of a pane plus cursor metadata.
+type ScreenState struct {
+	Lines   []string `json:"lines"`
+	CursorX int      `json:"cursor_x"`
+	CursorY int      `json:"cursor_y"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Hash    string   `json:"hash"`
+}
+
+// LineChange is a single changed screen line.
+type LineChange struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// ScreenDiff describes how a pane's screen changed between two captures.
+// Only changed lines are carried; a receiver applies them to its copy of
+// the previous screen, truncated or padded to LineCount.
+type ScreenDiff struct {
+	// Full is set when there was no previous screen or the pane was
+	// resized; Changed then holds every line.
+	Full      bool         `json:"full,omitempty"`
+	Changed   []LineChange `json:"changed,omitempty"`
+	LineCount int          `json:"line_count"`
+	CursorX   int          `json:"cursor_x"`
+	CursorY   int          `json:"cursor_y"`
+	Width     int          `json:"width"`
+	Height    int          `json:"height"`
+	PrevHash  string       `json:"prev_hash,omitempty"`
+	Hash      string       `json:"hash"`
+}
+
+// Empty reports whether nothing changed, including the cursor.
+func (d ScreenDiff) Empty() bool {
+	return !d.Full && len(d.Changed) == 0 && d.PrevHash == d.Hash
+}
+
+// CaptureScreen captures the visible content of a pane together with its
+// cursor position and size.
+func (c *Client) CaptureScreen(ctx context.Context, target string) (*ScreenState, error) {
+	if strings.TrimSpace(target) == "" {
+		return nil, fmt.Errorf("target is required")
+	}
+
+	cmd := fmt.Sprintf("tmux display-message -p -t %s '#{cursor_x},#{cursor_y},#{pane_width},#{pane_height}'", escapeArg(target))
+	stdout, _, err := c.run(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("tmux display-message failed: %w", err)
+	}
+	cursor, err := parseScreenMetadata(strings.TrimSpace(string(stdout)))
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := c.capturePaneRange(ctx, target, "", "")
+	if err != nil {
+		return nil, err
+	}
+	cursor.Lines = splitScreenLines(content)
+	cursor.Hash = screenHash(cursor)
+	return cursor, nil
+}
+
+// CapturePaneDiff captures a pane twice, interval apart, and returns only
+// the lines that changed between the two captures along with the second
+// screen. Streaming callers that already hold the previous screen should
+// use CaptureScreen and DiffScreens instead of capturing twice.
+func (c *Client) CapturePaneDiff(ctx context.Context, target string, interval time.Duration) (ScreenDiff, *ScreenState, error) {
+	first, err := c.CaptureScreen(ctx, target)
+	if err != nil {
+		return ScreenDiff{}, nil, err
+	}
+
+	if interval > 0 {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ScreenDiff{}, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	second, err := c.CaptureScreen(ctx, target)
+	if err != nil {
+		return ScreenDiff{}, nil, err
+	}
+	return DiffScreens(first, second), second, nil
+}
+
+// DiffScreens returns the changes needed to turn prev into next. A nil prev
+// or a resized pane yields a full diff.
+func DiffScreens(prev, next *ScreenState) ScreenDiff {
+	if next == nil {
+		return ScreenDiff{}
+	}
+	diff := ScreenDiff{
+		LineCount: len(next.Lines),
+		CursorX:   next.CursorX,
+		CursorY:   next.CursorY,
+		Width:     next.Width,
+		Height:    next.Height,
+		Hash:      next.Hash,
+	}
+
+	if prev == nil || prev.Width != next.Width || prev.Height != next.Height {
+		diff.Full = true
+		diff.Changed = make([]LineChange, 0, len(next.Lines))
+		for i, line := range next.Lines {
+			diff.Changed = append(diff.Changed, LineChange{Line: i, Text: line})
+		}
+		return diff
+	}
+
+	diff.PrevHash = prev.Hash
+	for i, line := range next.Lines {
+		if i < len(prev.Lines) && prev.Lines[i] == line {
+			continue
+		}
+		diff.Changed = append(diff.Changed, LineChange{Line: i, Text: line})
+	}
+	return diff
+}
+
+// ApplyScreenDiff applies diff to a copy of lines and returns the result.
+func ApplyScreenDiff(lines []string, diff ScreenDiff) []string {
+	out := make([]string, diff.LineCount)
+	if !diff.Full {
+		copy(out, lines)
+	}
+	for _, change := range diff.Changed {
+		if change.Line >= 0 && change.Line < len(out) {
+			out[change.Line] = change.Text
+		}
+	}
+	return out
+}
+
+func parseScreenMetadata(raw string) (*ScreenState, error) {
+	parts := strings.Split(strings.Trim(raw, "'"), ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("unexpected pane metadata %q", raw)
+	}
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pane metadata %q: %w", raw, err)
+		}
+		values[i] = value
+	}
+	return &ScreenState{
+		CursorX: values[0],
+		CursorY: values[1],
+		Width:   values[2],
+		Height:  values[3],
+	}, nil
+}
+
+// splitScreenLines splits captured content into lines, dropping the
+// trailing newline capture-pane always emits.
+func splitScreenLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+// screenHash covers content and cursor so a cursor-only move is visible.
+func screenHash(state *ScreenState) string {
+	return HashSnapshot(fmt.Sprintf("%d,%d\n%s", state.CursorX, state.CursorY, strings.Join(state.Lines, "\n")))
+}
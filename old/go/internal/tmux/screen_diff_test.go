@@ -0,0 +1,64 @@
+package tmux
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCapturePaneDiff_ReturnsChangedLines(t *testing.T) {
+	exec := &fakeExecutor{stdoutQueue: [][]byte{
+		[]byte("0,1,80,24\n"),
+		[]byte("$ make\nbuilding\n\n"),
+		[]byte("4,2,80,24\n"),
+		[]byte("$ make\nok\ndone\n"),
+	}}
+	client := NewClient(exec)
+
+	diff, screen, err := client.CapturePaneDiff(context.Background(), "sess:0.1", 0)
+	if err != nil {
+		t.Fatalf("CapturePaneDiff failed: %v", err)
+	}
+	if len(exec.commands) != 4 {
+		t.Fatalf("expected two metadata+capture pairs, got %d commands", len(exec.commands))
+	}
+	if !strings.Contains(exec.commands[0], "#{cursor_x}") || !strings.Contains(exec.commands[1], "capture-pane") {
+		t.Fatalf("unexpected commands: %q", exec.commands)
+	}
+	if diff.Full {
+		t.Fatalf("expected incremental diff")
+	}
+	want := []LineChange{{Line: 1, Text: "ok"}, {Line: 2, Text: "done"}}
+	if !reflect.DeepEqual(diff.Changed, want) {
+		t.Fatalf("changed = %+v, want %+v", diff.Changed, want)
+	}
+	if diff.CursorX != 4 || diff.CursorY != 2 || diff.LineCount != 3 {
+		t.Fatalf("unexpected cursor metadata: %+v", diff)
+	}
+	if diff.Hash != screen.Hash || diff.PrevHash == diff.Hash {
+		t.Fatalf("unexpected hashes: %+v", diff)
+	}
+
+	applied := ApplyScreenDiff([]string{"$ make", "building", ""}, diff)
+	if !reflect.DeepEqual(applied, screen.Lines) {
+		t.Fatalf("applied = %q, want %q", applied, screen.Lines)
+	}
+}
+
+func TestDiffScreens_FullOnResizeAndEmptyWhenUnchanged(t *testing.T) {
+	prev := &ScreenState{Lines: []string{"a", "b"}, Width: 80, Height: 24}
+	prev.Hash = screenHash(prev)
+
+	same := *prev
+	if diff := DiffScreens(prev, &same); !diff.Empty() {
+		t.Fatalf("expected empty diff, got %+v", diff)
+	}
+
+	resized := &ScreenState{Lines: []string{"a", "b"}, Width: 100, Height: 24}
+	resized.Hash = screenHash(resized)
+	diff := DiffScreens(prev, resized)
+	if !diff.Full || len(diff.Changed) != 2 {
+		t.Fatalf("expected full diff on resize, got %+v", diff)
+	}
+}
@@ -0,0 +1,249 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/ssh"
+)
+
+const (
+	defaultSSHAttempts = 3
+	defaultSSHBackoff  = 200 * time.Millisecond
+)
+
+// SSHDialFunc opens an SSH executor for a connection target.
+type SSHDialFunc func(backend models.SSHBackend, opts ssh.ConnectionOptions) (ssh.Executor, error)
+
+// SSHPool shares SSH connections between executors that target the same
+// node, so many tmux clients for one host reuse a single connection.
+type SSHPool struct {
+	mu    sync.Mutex
+	dial  SSHDialFunc
+	conns map[string]ssh.Executor
+}
+
+// NewSSHPool creates a connection pool. A nil dial uses the native SSH
+// client, falling back to the system ssh binary for the auto backend.
+func NewSSHPool(dial SSHDialFunc) *SSHPool {
+	if dial == nil {
+		dial = dialSSH
+	}
+	return &SSHPool{dial: dial, conns: make(map[string]ssh.Executor)}
+}
+
+var defaultSSHPool = NewSSHPool(nil)
+
+func (p *SSHPool) acquire(key string, backend models.SSHBackend, opts ssh.ConnectionOptions) (ssh.Executor, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if executor, ok := p.conns[key]; ok {
+		return executor, nil
+	}
+	executor, err := p.dial(backend, opts)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[key] = executor
+	return executor, nil
+}
+
+// discard drops a connection that failed so the next acquire redials.
+func (p *SSHPool) discard(key string, executor ssh.Executor) {
+	p.mu.Lock()
+	if current, ok := p.conns[key]; ok && current == executor {
+		delete(p.conns, key)
+	}
+	p.mu.Unlock()
+	_ = executor.Close()
+}
+
+// Close closes every pooled connection.
+func (p *SSHPool) Close() error {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[string]ssh.Executor)
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, executor := range conns {
+		if err := executor.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func dialSSH(backend models.SSHBackend, opts ssh.ConnectionOptions) (ssh.Executor, error) {
+	switch backend {
+	case models.SSHBackendSystem:
+		return ssh.NewSystemExecutor(opts), nil
+	case models.SSHBackendNative:
+		return ssh.NewNativeExecutor(opts)
+	default:
+		executor, err := ssh.NewNativeExecutor(opts)
+		if err != nil {
+			return ssh.NewSystemExecutor(opts), nil
+		}
+		return executor, nil
+	}
+}
+
+// SSHExecutor runs tmux commands on a remote node over SSH. Connections
+// come from a shared pool, and commands that fail on a transient
+// connection error are retried on a fresh connection.
+type SSHExecutor struct {
+	target   ssh.ConnectionOptions
+	backend  models.SSHBackend
+	pool     *SSHPool
+	attempts int
+	backoff  time.Duration
+}
+
+// SSHExecutorOption configures an SSHExecutor.
+type SSHExecutorOption func(*SSHExecutor)
+
+// WithSSHPool sets the connection pool (default: a process-wide pool).
+func WithSSHPool(pool *SSHPool) SSHExecutorOption {
+	return func(e *SSHExecutor) {
+		if pool != nil {
+			e.pool = pool
+		}
+	}
+}
+
+// WithSSHBackend selects the SSH implementation (default: auto).
+func WithSSHBackend(backend models.SSHBackend) SSHExecutorOption {
+	return func(e *SSHExecutor) {
+		if backend != "" {
+			e.backend = backend
+		}
+	}
+}
+
+// WithSSHRetry sets how many times a command is attempted and the initial
+// backoff between attempts, which doubles after each failure.
+func WithSSHRetry(attempts int, backoff time.Duration) SSHExecutorOption {
+	return func(e *SSHExecutor) {
+		if attempts > 0 {
+			e.attempts = attempts
+		}
+		if backoff >= 0 {
+			e.backoff = backoff
+		}
+	}
+}
+
+// NewSSHExecutor creates an executor for the given SSH target.
+func NewSSHExecutor(target ssh.ConnectionOptions, opts ...SSHExecutorOption) *SSHExecutor {
+	e := &SSHExecutor{
+		target:   target,
+		backend:  models.SSHBackendAuto,
+		pool:     defaultSSHPool,
+		attempts: defaultSSHAttempts,
+		backoff:  defaultSSHBackoff,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// NewSSHExecutorForNode creates an executor from a node's SSH target, key
+// path, and backend. Host aliases are resolved through ~/.ssh/config.
+func NewSSHExecutorForNode(node *models.Node, opts ...SSHExecutorOption) (*SSHExecutor, error) {
+	if node == nil {
+		return nil, fmt.Errorf("node is required")
+	}
+	if node.IsLocal {
+		return nil, fmt.Errorf("node %s is local", node.Name)
+	}
+	target, err := ssh.ParseSSHTarget(node.SSHTarget)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", node.Name, err)
+	}
+	target.KeyPath = node.SSHKeyPath
+	if resolved, err := ssh.ApplySSHConfig(*target); err == nil {
+		target = &resolved
+	}
+	opts = append([]SSHExecutorOption{WithSSHBackend(node.SSHBackend)}, opts...)
+	return NewSSHExecutor(*target, opts...), nil
+}
+
+// NewClientForNode returns a tmux client for the node: local execution for
+// the local node, SSH otherwise.
+func NewClientForNode(node *models.Node, opts ...SSHExecutorOption) (*Client, error) {
+	if node != nil && node.IsLocal {
+		return NewLocalClient(), nil
+	}
+	executor, err := NewSSHExecutorForNode(node, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(executor), nil
+}
+
+// Exec runs a tmux command on the remote node.
+func (e *SSHExecutor) Exec(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	key := e.poolKey()
+	backoff := e.backoff
+	for attempt := 1; ; attempt++ {
+		var executor ssh.Executor
+		executor, err = e.pool.acquire(key, e.backend, e.target)
+		if err == nil {
+			stdout, stderr, err = executor.Exec(ctx, cmd)
+			if err != nil && isTransientSSHError(err) {
+				e.pool.discard(key, executor)
+			}
+		}
+		if err == nil || attempt >= e.attempts || !isTransientSSHError(err) {
+			return stdout, stderr, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return stdout, stderr, err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (e *SSHExecutor) poolKey() string {
+	return strings.Join([]string{
+		string(e.backend),
+		e.target.User,
+		e.target.Host,
+		fmt.Sprint(e.target.Port),
+		e.target.KeyPath,
+		e.target.ProxyJump,
+	}, "|")
+}
+
+// isTransientSSHError reports whether err looks like a connection problem
+// worth retrying rather than a failure of the command itself.
+func isTransientSSHError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var execErr *ssh.ExecError
+	if errors.As(err, &execErr) {
+		// The system ssh binary exits 255 when the connection fails;
+		// any other exit status came from the remote command.
+		return execErr.ExitCode == 255
+	}
+	if errors.Is(err, ssh.ErrMissingHost) ||
+		errors.Is(err, ssh.ErrHostKeyRejected) ||
+		errors.Is(err, ssh.ErrHostKeyPromptUnavailable) ||
+		errors.Is(err, ssh.ErrPassphraseRequired) {
+		return false
+	}
+	return !strings.Contains(err.Error(), "unable to authenticate")
+}
@@ -0,0 +1,122 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/ssh"
+)
+
+type fakeSSHExecutor struct {
+	fakeExecutor
+	closed bool
+}
+
+func (f *fakeSSHExecutor) ExecInteractive(ctx context.Context, cmd string, stdin io.Reader) error {
+	return nil
+}
+
+func (f *fakeSSHExecutor) StartSession() (ssh.Session, error) {
+	return nil, errors.New("not supported")
+}
+
+func (f *fakeSSHExecutor) Close() error {
+	f.closed = true
+	return nil
+}
+
+type fakeDialer struct {
+	dials     []ssh.ConnectionOptions
+	executors []*fakeSSHExecutor
+}
+
+func (d *fakeDialer) dial(backend models.SSHBackend, opts ssh.ConnectionOptions) (ssh.Executor, error) {
+	d.dials = append(d.dials, opts)
+	executor := d.executors[0]
+	if len(d.executors) > 1 {
+		d.executors = d.executors[1:]
+	}
+	return executor, nil
+}
+
+func TestSSHExecutorRetriesTransientFailure(t *testing.T) {
+	broken := &fakeSSHExecutor{fakeExecutor: fakeExecutor{err: io.EOF}}
+	healthy := &fakeSSHExecutor{fakeExecutor: fakeExecutor{stdout: []byte("alpha|1\n")}}
+	dialer := &fakeDialer{executors: []*fakeSSHExecutor{broken, healthy}}
+	pool := NewSSHPool(dialer.dial)
+
+	executor := NewSSHExecutor(ssh.ConnectionOptions{Host: "node-a", User: "forge"}, WithSSHPool(pool), WithSSHRetry(3, 0))
+	sessions, err := NewClient(executor).ListSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Name != "alpha" {
+		t.Fatalf("unexpected sessions %+v", sessions)
+	}
+	if len(dialer.dials) != 2 {
+		t.Fatalf("expected a redial after the transient failure, got %d dials", len(dialer.dials))
+	}
+	if !broken.closed {
+		t.Fatalf("expected failed connection to be closed")
+	}
+
+	// A second executor for the same target reuses the pooled connection.
+	other := NewSSHExecutor(ssh.ConnectionOptions{Host: "node-a", User: "forge"}, WithSSHPool(pool))
+	if _, _, err := other.Exec(context.Background(), "tmux -V"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if len(dialer.dials) != 2 {
+		t.Fatalf("expected pooled connection reuse, got %d dials", len(dialer.dials))
+	}
+}
+
+func TestSSHExecutorDoesNotRetryCommandFailure(t *testing.T) {
+	remote := &fakeSSHExecutor{fakeExecutor: fakeExecutor{err: &ssh.ExecError{Command: "tmux has-session", ExitCode: 1}}}
+	dialer := &fakeDialer{executors: []*fakeSSHExecutor{remote}}
+
+	executor := NewSSHExecutor(ssh.ConnectionOptions{Host: "node-a"}, WithSSHPool(NewSSHPool(dialer.dial)), WithSSHRetry(3, 0))
+	if _, _, err := executor.Exec(context.Background(), "tmux has-session -t x"); err == nil {
+		t.Fatalf("expected command failure")
+	}
+	if len(remote.commands) != 1 || remote.closed {
+		t.Fatalf("expected a single attempt on a kept connection, got %d attempts", len(remote.commands))
+	}
+}
+
+func TestSSHExecutorGivesUpAfterAttempts(t *testing.T) {
+	broken := &fakeSSHExecutor{fakeExecutor: fakeExecutor{err: &ssh.ExecError{ExitCode: 255}}}
+	dialer := &fakeDialer{executors: []*fakeSSHExecutor{broken}}
+
+	executor := NewSSHExecutor(ssh.ConnectionOptions{Host: "node-a"}, WithSSHPool(NewSSHPool(dialer.dial)), WithSSHRetry(2, 0))
+	if _, _, err := executor.Exec(context.Background(), "tmux -V"); err == nil {
+		t.Fatalf("expected failure after retries")
+	}
+	if len(dialer.dials) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(dialer.dials))
+	}
+}
+
+func TestNewSSHExecutorForNode(t *testing.T) {
+	node := &models.Node{Name: "gpu", SSHTarget: "forge@10.0.0.5:2222", SSHKeyPath: "/keys/gpu", SSHBackend: models.SSHBackendSystem}
+	executor, err := NewSSHExecutorForNode(node)
+	if err != nil {
+		t.Fatalf("NewSSHExecutorForNode failed: %v", err)
+	}
+	if executor.target.User != "forge" || executor.target.Host != "10.0.0.5" || executor.target.Port != 2222 || executor.target.KeyPath != "/keys/gpu" {
+		t.Fatalf("unexpected target %+v", executor.target)
+	}
+	if executor.backend != models.SSHBackendSystem {
+		t.Fatalf("expected system backend, got %q", executor.backend)
+	}
+
+	if _, err := NewSSHExecutorForNode(&models.Node{Name: "local", IsLocal: true}); err == nil {
+		t.Fatalf("expected error for local node")
+	}
+	client, err := NewClientForNode(&models.Node{Name: "local", IsLocal: true})
+	if err != nil || client == nil {
+		t.Fatalf("expected local client, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/config"
+)
+
+func TestOTLPExporterPostsJSON(t *testing.T) {
+	var (
+		gotPath   string
+		gotHeader string
+		gotBody   otlpRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL+"/", "forge-test", map[string]string{"Authorization": "Bearer x"}, time.Second)
+	start := time.Unix(1700000000, 0)
+	span := SpanData{
+		Name:        "tmux.exec",
+		TraceID:     TraceID{1},
+		SpanID:      SpanID{2},
+		StartTime:   start,
+		EndTime:     start.Add(time.Millisecond),
+		Attributes:  []Attribute{String("tmux.command", "send-keys"), Int("attempt", 2), Bool("ok", false)},
+		StatusError: true,
+	}
+	if err := exporter.ExportSpans(context.Background(), []SpanData{span}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if gotPath != "/v1/traces" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if gotHeader != "Bearer x" {
+		t.Fatalf("authorization header = %q", gotHeader)
+	}
+	if len(gotBody.ResourceSpans) != 1 || len(gotBody.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected payload shape: %+v", gotBody)
+	}
+	resource := gotBody.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || *resource[0].Value.StringValue != "forge-test" {
+		t.Fatalf("unexpected resource attributes: %+v", resource)
+	}
+	spans := gotBody.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	got := spans[0]
+	if got.TraceID != "01000000000000000000000000000000" || got.SpanID != "0200000000000000" {
+		t.Fatalf("unexpected ids: %s/%s", got.TraceID, got.SpanID)
+	}
+	if got.ParentSpanID != "" {
+		t.Fatalf("expected no parent span id, got %q", got.ParentSpanID)
+	}
+	if got.StartTimeUnixNano != "1700000000000000000" || got.EndTimeUnixNano != "1700000000001000000" {
+		t.Fatalf("unexpected timestamps: %s-%s", got.StartTimeUnixNano, got.EndTimeUnixNano)
+	}
+	if got.Status == nil || got.Status.Code != otlpStatusError {
+		t.Fatalf("expected error status, got %+v", got.Status)
+	}
+	if len(got.Attributes) != 3 || *got.Attributes[1].Value.IntValue != "2" || *got.Attributes[2].Value.BoolValue {
+		t.Fatalf("unexpected attributes: %+v", got.Attributes)
+	}
+}
+
+func TestOTLPExporterReportsCollectorErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL+"/v1/traces", "", nil, time.Second)
+	err := exporter.ExportSpans(context.Background(), []SpanData{{Name: "x", TraceID: TraceID{1}, SpanID: SpanID{1}}})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("expected collector status error, got %v", err)
+	}
+}
+
+func TestSetupDisabledIsNoop(t *testing.T) {
+	SetProvider(nil)
+	shutdown := Setup(config.TracingConfig{Enabled: false})
+	if CurrentProvider() != nil {
+		t.Fatalf("expected no provider when disabled")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/tOgg1/forge/internal/logging"
+)
+
+const (
+	defaultBatchSize     = 256
+	defaultQueueSize     = 2048
+	defaultFlushInterval = 5 * time.Second
+)
+
+// Exporter ships finished spans to a backend.
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// Provider creates spans and batches finished ones to an Exporter.
+type Provider struct {
+	exporter      Exporter
+	serviceName   string
+	sampleRatio   float64
+	batchSize     int
+	flushInterval time.Duration
+	logger        zerolog.Logger
+
+	queue   chan SpanData
+	flushCh chan chan struct{}
+	done    chan struct{}
+	stopped sync.Once
+	dropped atomic.Int64
+}
+
+// ProviderOption configures a Provider.
+type ProviderOption func(*Provider)
+
+// WithServiceName sets the service.name resource attribute.
+func WithServiceName(name string) ProviderOption {
+	return func(p *Provider) {
+		if name != "" {
+			p.serviceName = name
+		}
+	}
+}
+
+// WithSampleRatio sets the fraction of new traces that are recorded (0..1).
+func WithSampleRatio(ratio float64) ProviderOption {
+	return func(p *Provider) {
+		switch {
+		case ratio < 0:
+			p.sampleRatio = 0
+		case ratio > 1:
+			p.sampleRatio = 1
+		default:
+			p.sampleRatio = ratio
+		}
+	}
+}
+
+// WithBatchSize sets how many spans are exported per request.
+func WithBatchSize(size int) ProviderOption {
+	return func(p *Provider) {
+		if size > 0 {
+			p.batchSize = size
+		}
+	}
+}
+
+// WithFlushInterval sets how often partial batches are exported.
+func WithFlushInterval(interval time.Duration) ProviderOption {
+	return func(p *Provider) {
+		if interval > 0 {
+			p.flushInterval = interval
+		}
+	}
+}
+
+// NewProvider creates a provider and starts its export loop.
+func NewProvider(exporter Exporter, opts ...ProviderOption) *Provider {
+	p := &Provider{
+		exporter:      exporter,
+		serviceName:   "forge",
+		sampleRatio:   1,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		logger:        logging.Component("tracing"),
+		queue:         make(chan SpanData, defaultQueueSize),
+		flushCh:       make(chan chan struct{}),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.run()
+	return p
+}
+
+// ServiceName returns the configured service name.
+func (p *Provider) ServiceName() string { return p.serviceName }
+
+// Dropped returns how many spans were discarded because the queue was full.
+func (p *Provider) Dropped() int64 { return p.dropped.Load() }
+
+// ForceFlush exports all queued spans.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flushCh <- ack:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown flushes queued spans and stops the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.ForceFlush(ctx)
+	p.stopped.Do(func() { close(p.done) })
+	if shutdownErr := p.exporter.Shutdown(ctx); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+func (p *Provider) newSpan(parent SpanContext, name string, attrs []Attribute) *Span {
+	sc := SpanContext{SpanID: newSpanID()}
+	var parentID SpanID
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+		parentID = parent.SpanID
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = p.shouldSample(sc.TraceID)
+	}
+	span := &Span{provider: p, sc: sc}
+	if sc.Sampled {
+		span.data = SpanData{
+			Name:         name,
+			TraceID:      sc.TraceID,
+			SpanID:       sc.SpanID,
+			ParentSpanID: parentID,
+			StartTime:    time.Now(),
+			Attributes:   append([]Attribute(nil), attrs...),
+		}
+	}
+	return span
+}
+
+// shouldSample makes a deterministic decision from the trace ID so that every
+// process sharing a trace agrees.
+func (p *Provider) shouldSample(id TraceID) bool {
+	if p.sampleRatio >= 1 {
+		return true
+	}
+	if p.sampleRatio <= 0 {
+		return false
+	}
+	bound := uint64(p.sampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(id[8:])>>1 < bound
+}
+
+func (p *Provider) enqueue(span SpanData) {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	select {
+	case p.queue <- span:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+func (p *Provider) run() {
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, p.batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+			p.logger.Warn().Err(err).Int("spans", len(batch)).Msg("failed to export spans")
+		}
+		cancel()
+		batch = make([]SpanData, 0, p.batchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case span := <-p.queue:
+				batch = append(batch, span)
+				if len(batch) >= p.batchSize {
+					export()
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case ack := <-p.flushCh:
+			drain()
+			export()
+			close(ack)
+		}
+	}
+}
+
+var globalProvider atomic.Pointer[Provider]
+
+// SetProvider installs the process-wide provider. Passing nil disables tracing.
+func SetProvider(p *Provider) {
+	globalProvider.Store(p)
+}
+
+// CurrentProvider returns the process-wide provider, or nil when disabled.
+func CurrentProvider() *Provider {
+	return currentProvider()
+}
+
+func currentProvider() *Provider {
+	return globalProvider.Load()
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/tOgg1/forge/internal/config"
+)
+
+// Setup installs a process-wide provider from configuration. The returned
+// function flushes and uninstalls it; it is safe to call when tracing is off.
+func Setup(cfg config.TracingConfig) func(context.Context) error {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }
+	}
+
+	exporter := NewOTLPExporter(cfg.Endpoint, cfg.ServiceName, cfg.Headers, cfg.ExportTimeout)
+	provider := NewProvider(exporter,
+		WithServiceName(cfg.ServiceName),
+		WithSampleRatio(cfg.SampleRatio),
+	)
+	SetProvider(provider)
+
+	return func(ctx context.Context) error {
+		SetProvider(nil)
+		return provider.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,262 @@
+// Package tracing provides lightweight distributed tracing for Forge.
+//
+// Spans follow the OpenTelemetry data model (128-bit trace IDs, 64-bit span
+// IDs, W3C traceparent propagation) and are exported over OTLP/HTTP, so any
+// OpenTelemetry collector can receive them. Tracing is disabled until a
+// Provider is installed with SetProvider; every call is a cheap no-op before
+// that.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the trace ID.
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid reports whether the trace ID is non-zero.
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// String returns the lowercase hex encoding of the span ID.
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid reports whether the span ID is non-zero.
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext is the propagated identity of a span.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Int64 returns an integer attribute.
+func Int64(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// SpanData is the immutable record of a finished span handed to exporters.
+type SpanData struct {
+	Name          string
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	StatusError   bool
+	StatusMessage string
+}
+
+// Span is an in-flight operation. A nil *Span is valid and does nothing.
+type Span struct {
+	provider *Provider
+	sc       SpanContext
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the span's propagated identity.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// IsRecording reports whether the span will be exported when ended.
+func (s *Span) IsRecording() bool {
+	return s != nil && s.provider != nil && s.sc.Sampled
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Attributes = append(s.data.Attributes, attrs...)
+	}
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil || !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.StatusError = true
+		s.data.StatusMessage = err.Error()
+	}
+}
+
+// End finishes the span and queues it for export. Calling End twice is a no-op.
+func (s *Span) End() {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.provider.enqueue(data)
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns a context carrying span as the current span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the current span, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// TraceIDFromContext returns the hex trace ID of the current sampled span, or
+// "" when the context is not being traced.
+func TraceIDFromContext(ctx context.Context) string {
+	sc := SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() || !sc.Sampled {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+// Start begins a span as a child of the span in ctx, or a new trace root.
+// When tracing is disabled it returns ctx unchanged and a nil span.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	provider := currentProvider()
+	if provider == nil {
+		return ctx, nil
+	}
+	span := provider.newSpan(SpanFromContext(ctx).SpanContext(), name, attrs)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartChild begins a span only when ctx is already being traced. Use it for
+// high-volume operations (DB queries, tmux commands) that should not start
+// traces of their own.
+func StartChild(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx).SpanContext()
+	if !parent.IsValid() || !parent.Sampled {
+		return ctx, nil
+	}
+	return Start(ctx, name, attrs...)
+}
+
+// Traceparent returns the W3C traceparent header for the current span, or "".
+func Traceparent(ctx context.Context) string {
+	sc := SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() {
+		return ""
+	}
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ContextWithTraceparent continues a trace received from another process.
+// Malformed headers are ignored.
+func ContextWithTraceparent(ctx context.Context, header string) context.Context {
+	sc, err := ParseTraceparent(header)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithSpan(ctx, &Span{sc: sc})
+}
+
+// ParseTraceparent parses a W3C traceparent header.
+func ParseTraceparent(header string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", header)
+	}
+	if parts[0] == "ff" {
+		return SpanContext{}, fmt.Errorf("invalid traceparent version %q", parts[0])
+	}
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace id: %w", err)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid span id: %w", err)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace flags: %w", err)
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q: zero id", header)
+	}
+	return sc, nil
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error { return nil }
+
+func (e *recordingExporter) snapshot() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+func installProvider(t *testing.T, opts ...ProviderOption) (*Provider, *recordingExporter) {
+	t.Helper()
+	exporter := &recordingExporter{}
+	provider := NewProvider(exporter, opts...)
+	SetProvider(provider)
+	t.Cleanup(func() {
+		SetProvider(nil)
+		_ = provider.Shutdown(context.Background())
+	})
+	return provider, exporter
+}
+
+func TestStartIsNoopWithoutProvider(t *testing.T) {
+	SetProvider(nil)
+	ctx := context.Background()
+	got, span := Start(ctx, "noop")
+	if span != nil {
+		t.Fatalf("expected nil span when tracing is disabled")
+	}
+	if got != ctx {
+		t.Fatalf("expected context to be returned unchanged")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("boom"))
+	span.End()
+	if TraceIDFromContext(got) != "" {
+		t.Fatalf("expected empty trace id")
+	}
+}
+
+func TestStartChildRequiresParent(t *testing.T) {
+	installProvider(t)
+	if _, span := StartChild(context.Background(), "db.query"); span != nil {
+		t.Fatalf("expected no span without a traced parent")
+	}
+
+	ctx, root := Start(context.Background(), "root")
+	_, child := StartChild(ctx, "db.query")
+	if child == nil {
+		t.Fatalf("expected child span under traced parent")
+	}
+	if child.SpanContext().TraceID != root.SpanContext().TraceID {
+		t.Fatalf("child trace id mismatch")
+	}
+}
+
+func TestSpansExportWithParentLinks(t *testing.T) {
+	provider, exporter := installProvider(t, WithServiceName("forge-test"))
+
+	ctx, root := Start(context.Background(), "scheduler.tick", Int("agents", 2))
+	_, child := Start(ctx, "scheduler.dispatch", String("agent_id", "a1"))
+	child.RecordError(errors.New("dispatch failed"))
+	child.End()
+	root.End()
+	root.End() // second End is ignored
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	spans := exporter.snapshot()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	dispatch, tick := spans[0], spans[1]
+	if dispatch.Name != "scheduler.dispatch" || tick.Name != "scheduler.tick" {
+		t.Fatalf("unexpected span order: %s, %s", dispatch.Name, tick.Name)
+	}
+	if dispatch.ParentSpanID != tick.SpanID {
+		t.Fatalf("expected dispatch parent to be tick span")
+	}
+	if tick.ParentSpanID.IsValid() {
+		t.Fatalf("expected root span to have no parent")
+	}
+	if !dispatch.StatusError || dispatch.StatusMessage != "dispatch failed" {
+		t.Fatalf("expected error status, got %+v", dispatch)
+	}
+	if TraceIDFromContext(ctx) != tick.TraceID.String() {
+		t.Fatalf("trace id from context mismatch")
+	}
+}
+
+func TestSampleRatioZeroRecordsNothing(t *testing.T) {
+	provider, exporter := installProvider(t, WithSampleRatio(0))
+
+	ctx, span := Start(context.Background(), "root")
+	if span.IsRecording() {
+		t.Fatalf("expected unsampled span")
+	}
+	if _, child := StartChild(ctx, "child"); child != nil {
+		t.Fatalf("expected no child under unsampled parent")
+	}
+	span.End()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := len(exporter.snapshot()); got != 0 {
+		t.Fatalf("expected no exported spans, got %d", got)
+	}
+	if TraceIDFromContext(ctx) != "" {
+		t.Fatalf("expected no trace id for unsampled trace")
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	installProvider(t)
+	ctx, span := Start(context.Background(), "root")
+	header := Traceparent(ctx)
+	if len(header) != 55 || header[len(header)-2:] != "01" {
+		t.Fatalf("unexpected traceparent %q", header)
+	}
+
+	remote := ContextWithTraceparent(context.Background(), header)
+	if got := TraceIDFromContext(remote); got != span.SpanContext().TraceID.String() {
+		t.Fatalf("trace id = %q, want %q", got, span.SpanContext().TraceID)
+	}
+	_, child := Start(remote, "remote.child")
+	if child.SpanContext().TraceID != span.SpanContext().TraceID {
+		t.Fatalf("remote child should continue the trace")
+	}
+}
+
+func TestParseTraceparentRejectsMalformed(t *testing.T) {
+	cases := []string{
+		"",
+		"00-abc-def-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+	}
+	for _, header := range cases {
+		if _, err := ParseTraceparent(header); err == nil {
+			t.Fatalf("expected error for %q", header)
+		}
+	}
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if sc.Sampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("unexpected span context %+v", sc)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/tOgg1/forge/internal/events"
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/sequences"
 	"github.com/tOgg1/forge/internal/state"
@@ -33,6 +34,8 @@ type Config struct {
 	Theme string
 	// AgentMail configures optional mailbox integration.
 	AgentMail AgentMailConfig
+	// Publisher delivers scheduler events such as queue deadline warnings.
+	Publisher events.Publisher
 }
 
 // Run launches the Forge TUI program.
@@ -81,12 +84,24 @@ func RunWithConfig(cfg Config) error {
 		}()
 	}
 
+	if cfg.Publisher != nil {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			if cmd := SubscribeToDeadlineEvents(cfg.Publisher, tuiSubscriberID)(program); cmd != nil {
+				program.Send(cmd())
+			}
+		}()
+	}
+
 	_, err := program.Run()
 
 	// Clean up subscription on exit
 	if cfg.StateEngine != nil {
 		_ = cfg.StateEngine.Unsubscribe(tuiSubscriberID)
 	}
+	if cfg.Publisher != nil {
+		_ = cfg.Publisher.Unsubscribe(tuiSubscriberID)
+	}
 
 	return err
 }
@@ -226,6 +241,8 @@ type queueItem struct {
 	ConditionType   models.ConditionType
 	ConditionExpr   string
 	DurationSeconds int
+	RetryPolicy     models.RetryPolicyKind
+	DeadLettered    bool
 }
 
 type queueEditMode int
@@ -850,6 +867,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusExpiresAt = time.Time{}
 		}
 		return m, toastTickCmd()
+	case DeadlineEventMsg:
+		m.setStatus(deadlineStatus(msg, time.Now()))
 	case SubscriptionErrorMsg:
 		// Log subscription errors (for now just ignore)
 		// In production, might show a status indicator
@@ -2251,12 +2270,22 @@ func (m model) queueEditorLines() []string {
 
 	state := m.queueEditorStateForAgent(agent)
 	count := 0
+	deadLettered := 0
 	if state != nil {
 		count = len(state.Items)
+		for _, item := range state.Items {
+			if item.DeadLettered {
+				deadLettered++
+			}
+		}
 	}
 
+	title := fmt.Sprintf("Queue for agent %s (%d items)", agent, count)
+	if deadLettered > 0 {
+		title = fmt.Sprintf("Queue for agent %s (%d items, %d dead-lettered)", agent, count, deadLettered)
+	}
 	lines := []string{
-		m.styles.Text.Render(fmt.Sprintf("Queue for agent %s (%d items)", agent, count)),
+		m.styles.Text.Render(title),
 		m.styles.Muted.Render("j/k move | J/K reorder | i insert | p pause | g gate | t template"),
 		m.styles.Muted.Render("Enter edit | e expand | d delete | r retry | c copy | esc close"),
 	}
@@ -2299,7 +2328,7 @@ func (m model) queueEditorLines() []string {
 			lines = append(lines, m.styles.Text.Render(line))
 		}
 
-		showDetail := i == state.Selected || blockReason != "" || strings.TrimSpace(item.Error) != ""
+		showDetail := i == state.Selected || blockReason != "" || strings.TrimSpace(item.Error) != "" || item.DeadLettered
 		if state.Expanded != nil && state.Expanded[item.ID] {
 			showDetail = true
 		}
@@ -2411,6 +2440,9 @@ func queueItemTypeTimelineLabel(kind models.QueueItemType) string {
 }
 
 func queueItemStatusLabel(item queueItem, blockedReason string) string {
+	if item.DeadLettered {
+		return "dead"
+	}
 	status := item.Status
 	if status == "" {
 		status = models.QueueItemStatusPending
@@ -2535,6 +2567,13 @@ func queueItemDetailLine(item queueItem, blockedReason string, cooldown string)
 		}
 		parts = append(parts, fmt.Sprintf("blocked: %s", label))
 	}
+	if item.DeadLettered {
+		label := "dead-lettered: retries exhausted, r to requeue"
+		if item.RetryPolicy != "" {
+			label = fmt.Sprintf("dead-lettered: %s retries exhausted, r to requeue", item.RetryPolicy)
+		}
+		parts = append(parts, label)
+	}
 	if item.Attempts > 0 {
 		parts = append(parts, fmt.Sprintf("attempts: %d", item.Attempts))
 	}
@@ -4230,6 +4269,10 @@ func messagePaletteQueueItemsFromModels(state *queueEditorState, items []models.
 			status = models.QueueItemStatusPending
 		}
 		summary, conditionType, conditionExpr, durationSeconds := messagePaletteQueueItemDetails(item)
+		var retryPolicy models.RetryPolicyKind
+		if item.RetryPolicy != nil {
+			retryPolicy = item.RetryPolicy.Kind
+		}
 		out = append(out, queueItem{
 			ID:              fmt.Sprintf("q-%02d", nextIndex),
 			Kind:            item.Type,
@@ -4240,6 +4283,8 @@ func messagePaletteQueueItemsFromModels(state *queueEditorState, items []models.
 			ConditionType:   conditionType,
 			ConditionExpr:   conditionExpr,
 			DurationSeconds: durationSeconds,
+			RetryPolicy:     retryPolicy,
+			DeadLettered:    item.DeadLetteredAt != nil,
 		})
 		nextIndex++
 	}
@@ -4819,6 +4864,22 @@ func (m *model) setStatus(message string, severity statusSeverity) {
 	m.statusExpiresAt = time.Now().Add(statusToastDuration)
 }
 
+// deadlineStatus renders a queue deadline event for the status line.
+func deadlineStatus(msg DeadlineEventMsg, now time.Time) (string, statusSeverity) {
+	item, agentID := shortID(msg.Payload.QueueItemID), shortID(msg.Payload.AgentID)
+	if msg.Type == models.EventTypeQueueDeadlineMissed {
+		if msg.Payload.Dispatched {
+			return fmt.Sprintf("Deadline missed: queue item %s dispatched late to %s", item, agentID), statusError
+		}
+		return fmt.Sprintf("Deadline missed: queue item %s still waiting for %s", item, agentID), statusError
+	}
+	remaining := msg.Payload.Deadline.Sub(now).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("Deadline in %s: queue item %s for %s", remaining, item, agentID), statusWarn
+}
+
 func shortID(value string) string {
 	if len(value) <= 8 {
 		return value
@@ -4873,6 +4934,7 @@ func (m model) allAgentCards() []components.AgentCard {
 			LastActivity:  lastPtr,
 			CooldownUntil: cooldownPtr,
 			RecentEvents:  m.agentRecentEvents[id],
+			CrashLooping:  models.IsCrashLoopReason(info.Reason),
 		}
 		cards = append(cards, card)
 	}
@@ -6004,9 +6066,15 @@ func (m *model) retryQueueItem(state *queueEditorState) {
 	if item.Status != models.QueueItemStatusFailed {
 		return
 	}
+	wasDeadLettered := item.DeadLettered
 	item.Status = models.QueueItemStatusPending
 	item.Attempts = 0
 	item.Error = ""
+	item.DeadLettered = false
+	if wasDeadLettered {
+		m.setStatus("Dead-lettered item requeued.", statusInfo)
+		return
+	}
 	m.setStatus("Queue item reset to pending.", statusInfo)
 }
 
@@ -6021,6 +6089,7 @@ func (m *model) copyQueueItem(state *queueEditorState) {
 	clone.Status = models.QueueItemStatusPending
 	clone.Attempts = 0
 	clone.Error = ""
+	clone.DeadLettered = false
 	insertIndex := clampInt(index+1, 0, len(state.Items))
 	state.Items = append(state.Items, queueItem{})
 	copy(state.Items[insertIndex+1:], state.Items[insertIndex:])
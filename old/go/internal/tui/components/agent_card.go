@@ -32,6 +32,8 @@ type AgentCard struct {
 	RecentEvents  []time.Time              // Timestamps of recent state changes for activity pulse
 	UsageMetrics  *models.UsageMetrics     // Usage metrics from adapter
 	ClaimSummary  *models.FileClaimSummary // File claim status from Agent Mail
+	Resources     *models.ResourceUsage    // Cgroup limits and usage
+	CrashLooping  bool                     // Agent is held back by crash-loop backoff
 }
 
 // RenderAgentCard renders a compact agent summary card.
@@ -68,6 +70,9 @@ func RenderAgentCard(styleSet styles.Styles, card AgentCard, focused bool, selec
 		stateLabel = styleSet.Muted.Render("State:")
 	}
 	stateLine := fmt.Sprintf("%s %s", stateLabel, stateBadge)
+	if card.CrashLooping {
+		stateLine = fmt.Sprintf("%s %s", stateLine, styleSet.Error.Render("CRASHLOOP"))
+	}
 	reasonLine := mutedStyle.Render(fmt.Sprintf("Why: %s", reason))
 	cooldownLine := renderCooldownLine(styleSet, card.CooldownUntil)
 	confidenceLine := renderConfidenceLine(styleSet, card.Confidence)
@@ -105,6 +110,12 @@ func RenderAgentCard(styleSet styles.Styles, card AgentCard, focused bool, selec
 		lines = append(lines, usageLine)
 	}
 
+	// Resource line (if the agent runs in a cgroup)
+	resourceLine := RenderResourceLine(styleSet, card.Resources)
+	if resourceLine != "" {
+		lines = append(lines, resourceLine)
+	}
+
 	// File claims line (if available)
 	claimsLine := RenderFileClaimsLine(styleSet, card.ClaimSummary)
 	if claimsLine != "" {
@@ -198,6 +209,50 @@ func formatDuration(value time.Duration) string {
 	return value.Round(time.Minute).String()
 }
 
+// runawayThreshold is the memory fraction at which the resource line turns red.
+const runawayThreshold = 0.9
+
+// RenderResourceLine renders cgroup memory and CPU usage against limits.
+func RenderResourceLine(styleSet styles.Styles, usage *models.ResourceUsage) string {
+	if usage == nil {
+		return ""
+	}
+	memory := formatResourceBytes(usage.MemoryBytes)
+	if usage.Limits.MemoryBytes > 0 {
+		memory = fmt.Sprintf("%s/%s", memory, formatResourceBytes(usage.Limits.MemoryBytes))
+	}
+	cpu := "unlimited"
+	if usage.Limits.CPUCores > 0 {
+		cpu = fmt.Sprintf("%.2g cores", usage.Limits.CPUCores)
+	}
+	text := fmt.Sprintf("Mem %s  CPU %s", memory, cpu)
+	if usage.OOMKills > 0 {
+		text += fmt.Sprintf("  [%d OOM]", usage.OOMKills)
+	}
+
+	style := styleSet.Text
+	switch {
+	case usage.IsRunaway(runawayThreshold):
+		style = styleSet.Error
+	case usage.MemoryFraction() >= 0.75:
+		style = styleSet.Warning
+	}
+	return fmt.Sprintf("%s %s", styleSet.Muted.Render("Limits:"), style.Render(text))
+}
+
+func formatResourceBytes(value int64) string {
+	switch {
+	case value >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(value)/(1<<30))
+	case value >= 1<<20:
+		return fmt.Sprintf("%dM", value>>20)
+	case value >= 1<<10:
+		return fmt.Sprintf("%dK", value>>10)
+	default:
+		return fmt.Sprintf("%dB", value)
+	}
+}
+
 // RenderFileClaimsLine renders a summary line showing file claim status.
 // Returns empty string if no claims are present.
 func RenderFileClaimsLine(styleSet styles.Styles, summary *models.FileClaimSummary) string {
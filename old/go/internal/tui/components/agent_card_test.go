@@ -168,3 +168,22 @@ func TestRenderAgentCard_WithoutClaimSummary(t *testing.T) {
 		t.Error("Agent card should not contain 'Claims:' when claim summary is nil")
 	}
 }
+
+func TestRenderResourceLine(t *testing.T) {
+	styleSet := styles.DefaultStyles()
+	if result := RenderResourceLine(styleSet, nil); result != "" {
+		t.Errorf("RenderResourceLine(nil) = %q, want empty string", result)
+	}
+
+	usage := &models.ResourceUsage{
+		Limits:      models.ResourceLimits{CPUCores: 1.5, MemoryBytes: 2 << 30},
+		MemoryBytes: 512 << 20,
+		OOMKills:    1,
+	}
+	result := RenderResourceLine(styleSet, usage)
+	for _, want := range []string{"Limits:", "512M/2.0G", "1.5 cores", "[1 OOM]"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Result should contain %q, got: %s", want, result)
+		}
+	}
+}
@@ -3,10 +3,12 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/tOgg1/forge/internal/events"
 	"github.com/tOgg1/forge/internal/models"
 	"github.com/tOgg1/forge/internal/state"
 )
@@ -79,6 +81,39 @@ func UnsubscribeFromStateChanges(engine *state.Engine, subscriberID string) tea.
 	}
 }
 
+// DeadlineEventMsg reports a scheduler queue deadline warning or miss.
+type DeadlineEventMsg struct {
+	Type    models.EventType
+	Payload models.QueueDeadlinePayload
+}
+
+// SubscribeToDeadlineEvents subscribes to queue deadline events on the
+// publisher and forwards them to the program as DeadlineEventMsg.
+func SubscribeToDeadlineEvents(publisher events.Publisher, subscriberID string) func(*tea.Program) tea.Cmd {
+	return func(program *tea.Program) tea.Cmd {
+		return func() tea.Msg {
+			if publisher == nil {
+				return SubscriptionErrorMsg{Err: nil}
+			}
+			filter := events.Filter{EventTypes: []models.EventType{
+				models.EventTypeQueueDeadlineWarning,
+				models.EventTypeQueueDeadlineMissed,
+			}}
+			err := publisher.Subscribe(subscriberID, filter, func(event *models.Event) {
+				var payload models.QueueDeadlinePayload
+				if err := json.Unmarshal(event.Payload, &payload); err != nil {
+					return
+				}
+				program.Send(DeadlineEventMsg{Type: event.Type, Payload: payload})
+			})
+			if err != nil {
+				return SubscriptionErrorMsg{Err: err}
+			}
+			return nil
+		}
+	}
+}
+
 // ConnectionStatusMsg indicates connection/reconnection status.
 type ConnectionStatusMsg struct {
 	Connected bool
@@ -132,6 +132,16 @@ func sampleQueueItems() []queueItem {
 			Attempts: 2,
 			Error:    "Rate limited by provider",
 		},
+		{
+			ID:           "q-06",
+			Kind:         models.QueueItemTypeMessage,
+			Summary:      "Post release notes to the team channel.",
+			Status:       models.QueueItemStatusFailed,
+			Attempts:     4,
+			Error:        "tmux pane not found",
+			RetryPolicy:  models.RetryPolicyExponential,
+			DeadLettered: true,
+		},
 	}
 }
 
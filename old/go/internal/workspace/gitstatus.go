@@ -0,0 +1,99 @@
+package workspace
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// DefaultGitStatusInterval is how long a collected git status is reused
+// before the repository is inspected again.
+const DefaultGitStatusInterval = 30 * time.Second
+
+// GitStatusCollector caches git status (branch, dirty files, ahead/behind)
+// per repository path and refreshes an entry once it is older than the
+// interval, so views that poll often do not run git on every refresh.
+type GitStatusCollector struct {
+	interval time.Duration
+	detect   func(repoPath string) (*models.GitInfo, error)
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]gitStatusEntry
+}
+
+type gitStatusEntry struct {
+	info *models.GitInfo
+	at   time.Time
+}
+
+// NewGitStatusCollector creates a collector that refreshes each repository
+// at most once per interval. Zero or less uses DefaultGitStatusInterval.
+func NewGitStatusCollector(interval time.Duration) *GitStatusCollector {
+	if interval <= 0 {
+		interval = DefaultGitStatusInterval
+	}
+	return &GitStatusCollector{
+		interval: interval,
+		detect:   DetectGitInfo,
+		now:      time.Now,
+		cache:    make(map[string]gitStatusEntry),
+	}
+}
+
+// Interval returns how long a collected status is reused.
+func (c *GitStatusCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// Status returns the git status of repoPath, inspecting the repository
+// again only when the cached status is older than the interval.
+func (c *GitStatusCollector) Status(repoPath string) (*models.GitInfo, error) {
+	key := normalizePath(repoPath)
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.at) < c.interval {
+		return entry.info, nil
+	}
+	return c.Refresh(repoPath)
+}
+
+// Refresh inspects repoPath now and caches the result.
+func (c *GitStatusCollector) Refresh(repoPath string) (*models.GitInfo, error) {
+	info, err := c.detect(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.cache[normalizePath(repoPath)] = gitStatusEntry{info: info, at: c.now()}
+	c.mu.Unlock()
+	return info, nil
+}
+
+// FormatGitSummary renders a one-line git summary such as
+// "main ↑2 ↓1 dirty=3", or "" when info is not a repository.
+func FormatGitSummary(info *models.GitInfo) string {
+	if info == nil || !info.IsRepo {
+		return ""
+	}
+	parts := []string{info.Branch}
+	if info.Branch == "" {
+		parts[0] = "(unknown)"
+	}
+	if info.Ahead > 0 {
+		parts = append(parts, "↑"+strconv.Itoa(info.Ahead))
+	}
+	if info.Behind > 0 {
+		parts = append(parts, "↓"+strconv.Itoa(info.Behind))
+	}
+	if info.IsDirty {
+		parts = append(parts, "dirty="+strconv.Itoa(info.DirtyCount))
+	} else {
+		parts = append(parts, "clean")
+	}
+	return strings.Join(parts, " ")
+}
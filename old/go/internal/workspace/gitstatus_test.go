@@ -0,0 +1,108 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+func TestDetectGitInfoDirtyFilesAndUpstream(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	git := func(dir string, args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=forge", "-c", "user.email=forge@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	origin := t.TempDir()
+	git(origin, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(origin, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	git(origin, "add", "a.txt")
+	git(origin, "commit", "-q", "-m", "init")
+
+	repo := filepath.Join(t.TempDir(), "clone")
+	git(filepath.Dir(repo), "clone", "-q", origin, repo)
+	git(repo, "commit", "-q", "--allow-empty", "-m", "local")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("modify a.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "b.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("write b.txt: %v", err)
+	}
+
+	info, err := DetectGitInfo(repo)
+	if err != nil {
+		t.Fatalf("DetectGitInfo: %v", err)
+	}
+	if info.Branch != "main" || info.Upstream != "origin/main" || info.Ahead != 1 || info.Behind != 0 {
+		t.Fatalf("unexpected branch state: %+v", info)
+	}
+	want := []models.GitFileChange{{Status: " M", Path: "a.txt"}, {Status: "??", Path: "b.txt"}}
+	if !info.IsDirty || info.DirtyCount != 2 || len(info.DirtyFiles) != 2 || info.DirtyFiles[0] != want[0] || info.DirtyFiles[1] != want[1] {
+		t.Fatalf("unexpected dirty files: count=%d files=%+v", info.DirtyCount, info.DirtyFiles)
+	}
+	if info.CheckedAt == nil {
+		t.Fatalf("expected CheckedAt to be set")
+	}
+}
+
+func TestParseGitPorcelainRename(t *testing.T) {
+	changes := parseGitPorcelain("R  old.go -> new.go\nA  added.go\n")
+	if len(changes) != 2 || changes[0] != (models.GitFileChange{Status: "R ", Path: "new.go"}) || changes[1].Path != "added.go" {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestGitStatusCollectorCachesWithinInterval(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	collector := NewGitStatusCollector(30 * time.Second)
+	collector.now = func() time.Time { return now }
+	collector.detect = func(string) (*models.GitInfo, error) {
+		calls++
+		return &models.GitInfo{IsRepo: true, Branch: "main", DirtyCount: calls}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := collector.Status("/repo"); err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+		now = now.Add(10 * time.Second)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one git inspection within the interval, got %d", calls)
+	}
+
+	info, _ := collector.Status("/repo")
+	if calls != 2 || info.DirtyCount != 2 {
+		t.Fatalf("expected a refresh once the interval passed, got calls=%d", calls)
+	}
+	if _, err := collector.Refresh("/repo"); err != nil || calls != 3 {
+		t.Fatalf("expected Refresh to inspect the repo, got calls=%d err=%v", calls, err)
+	}
+}
+
+func TestFormatGitSummary(t *testing.T) {
+	cases := map[string]*models.GitInfo{
+		"":                   {IsRepo: false},
+		"main clean":         {IsRepo: true, Branch: "main"},
+		"feat ↑2 ↓1 dirty=3": {IsRepo: true, Branch: "feat", Ahead: 2, Behind: 1, IsDirty: true, DirtyCount: 3},
+	}
+	for want, info := range cases {
+		if got := FormatGitSummary(info); got != want {
+			t.Errorf("FormatGitSummary(%+v) = %q, want %q", info, got, want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// ErrQuotaExceeded is wrapped by every QuotaError.
+var ErrQuotaExceeded = errors.New("workspace quota exceeded")
+
+const bytesPerMB = 1 << 20
+
+// QuotaError reports a create request blocked by a workspace quota.
+type QuotaError struct {
+	WorkspaceID   string
+	WorkspaceName string
+	Resource      models.QuotaResource
+	Limit         int64
+	Current       int64
+	Requested     int64
+}
+
+func (e *QuotaError) Error() string {
+	name := e.WorkspaceName
+	if name == "" {
+		name = e.WorkspaceID
+	}
+	switch e.Resource {
+	case models.QuotaResourceDisk:
+		return fmt.Sprintf("workspace %q uses %d MB of disk, over its %d MB quota; free space or raise workspace_defaults.quota.max_disk_mb",
+			name, e.Current, e.Limit)
+	case models.QuotaResourceLoops:
+		return fmt.Sprintf("workspace %q loop quota exceeded: %d of %d loops used, %d requested; remove loops with 'forge rm' or raise workspace_defaults.quota.max_loops",
+			name, e.Current, e.Limit, e.Requested)
+	default:
+		return fmt.Sprintf("workspace %q agent quota exceeded: %d of %d agents used, %d requested; terminate agents or raise workspace_defaults.quota.max_agents",
+			name, e.Current, e.Limit, e.Requested)
+	}
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// WithQuotas sets the resolver for per-workspace quotas. Without one no
+// quotas are enforced.
+func WithQuotas(resolver func(*models.Workspace) models.WorkspaceQuota) ServiceOption {
+	return func(s *Service) {
+		s.quotaFor = resolver
+	}
+}
+
+// WithLoopRepository sets the repository loops are counted from for the
+// max_loops quota.
+func WithLoopRepository(loopRepo *db.LoopRepository) ServiceOption {
+	return func(s *Service) {
+		s.loopRepo = loopRepo
+	}
+}
+
+// Quota returns the effective quota for a workspace.
+func (s *Service) Quota(ws *models.Workspace) models.WorkspaceQuota {
+	if s.quotaFor == nil || ws == nil {
+		return models.WorkspaceQuota{}
+	}
+	return s.quotaFor(ws)
+}
+
+// CheckAgentQuota returns a *QuotaError when adding one agent to ws would
+// exceed its agent or disk quota.
+func (s *Service) CheckAgentQuota(ctx context.Context, ws *models.Workspace) error {
+	quota := s.Quota(ws)
+	if quota.IsZero() {
+		return nil
+	}
+	if quota.MaxAgents > 0 {
+		count, err := s.repo.GetAgentCount(ctx, ws.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count workspace agents: %w", err)
+		}
+		if count+1 > quota.MaxAgents {
+			return s.quotaExceeded(ctx, ws, models.QuotaResourceAgents, int64(quota.MaxAgents), int64(count), 1)
+		}
+	}
+	return s.checkDiskQuota(ctx, ws, quota)
+}
+
+// CheckLoopQuota returns a *QuotaError when adding count loops to the
+// workspaces on repoPath would exceed a loop or disk quota. Repos without a
+// workspace have no quota.
+func (s *Service) CheckLoopQuota(ctx context.Context, repoPath string, count int) error {
+	if s.quotaFor == nil || strings.TrimSpace(repoPath) == "" {
+		return nil
+	}
+	workspaces, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	target := filepath.Clean(repoPath)
+	for _, ws := range workspaces {
+		if filepath.Clean(ws.RepoPath) != target {
+			continue
+		}
+		quota := s.Quota(ws)
+		if quota.IsZero() {
+			continue
+		}
+		if quota.MaxLoops > 0 {
+			existing, err := s.countLoops(ctx, target)
+			if err != nil {
+				return err
+			}
+			if existing+count > quota.MaxLoops {
+				return s.quotaExceeded(ctx, ws, models.QuotaResourceLoops, int64(quota.MaxLoops), int64(existing), int64(count))
+			}
+		}
+		if err := s.checkDiskQuota(ctx, ws, quota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) countLoops(ctx context.Context, repoPath string) (int, error) {
+	if s.loopRepo == nil {
+		return 0, nil
+	}
+	loops, err := s.loopRepo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list loops: %w", err)
+	}
+	count := 0
+	for _, loop := range loops {
+		if filepath.Clean(loop.RepoPath) == repoPath {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Service) checkDiskQuota(ctx context.Context, ws *models.Workspace, quota models.WorkspaceQuota) error {
+	if quota.MaxDiskMB <= 0 {
+		return nil
+	}
+	used, err := s.diskUsageBytes(ctx, ws)
+	if err != nil {
+		// An unmeasurable repo should not block work; the quota is best effort.
+		s.logger.Warn().Err(err).Str("workspace_id", ws.ID).Msg("failed to measure workspace disk usage")
+		return nil
+	}
+	usedMB := used / bytesPerMB
+	if used > quota.MaxDiskMB*bytesPerMB {
+		return s.quotaExceeded(ctx, ws, models.QuotaResourceDisk, quota.MaxDiskMB, usedMB, 0)
+	}
+	return nil
+}
+
+// diskUsageBytes measures the workspace repo directory: by walking it on the
+// local node, or with du over the node's executor on remote nodes.
+func (s *Service) diskUsageBytes(ctx context.Context, ws *models.Workspace) (int64, error) {
+	local := true
+	var nodeObj *models.Node
+	if s.nodeService != nil && ws.NodeID != "" {
+		n, err := s.nodeService.GetNode(ctx, ws.NodeID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get node: %w", err)
+		}
+		nodeObj = n
+		local = n.IsLocal
+	}
+	if local {
+		return localDirSize(ctx, ws.RepoPath)
+	}
+
+	result, err := s.nodeService.ExecCommand(ctx, nodeObj, "du -sk "+shellQuote(ws.RepoPath))
+	if err != nil {
+		return 0, err
+	}
+	if result.ExitCode != 0 {
+		detail := strings.TrimSpace(result.Stderr)
+		if detail == "" {
+			detail = result.Error
+		}
+		return 0, fmt.Errorf("du failed: %s", detail)
+	}
+	fields := strings.Fields(result.Stdout)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected du output %q", result.Stdout)
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected du output %q", result.Stdout)
+	}
+	return kb * 1024, nil
+}
+
+func localDirSize(ctx context.Context, root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+func (s *Service) quotaExceeded(ctx context.Context, ws *models.Workspace, resource models.QuotaResource, limit, current, requested int64) error {
+	quotaErr := &QuotaError{
+		WorkspaceID:   ws.ID,
+		WorkspaceName: ws.Name,
+		Resource:      resource,
+		Limit:         limit,
+		Current:       current,
+		Requested:     requested,
+	}
+	s.logger.Warn().
+		Str("workspace_id", ws.ID).
+		Str("resource", string(resource)).
+		Int64("limit", limit).
+		Int64("current", current).
+		Int64("requested", requested).
+		Msg("workspace quota blocked create request")
+	s.publishEvent(ctx, models.EventTypeWorkspaceQuotaExceeded, ws.ID, models.WorkspaceQuotaPayload{
+		Resource:  resource,
+		Limit:     limit,
+		Current:   current,
+		Requested: requested,
+		Message:   quotaErr.Error(),
+	})
+	return quotaErr
+}
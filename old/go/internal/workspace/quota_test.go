@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/events"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+)
+
+func setupQuotaService(t *testing.T, quota models.WorkspaceQuota, opts ...ServiceOption) (*Service, *models.Workspace, *db.DB) {
+	t.Helper()
+	ctx := context.Background()
+	database := setupWorkspaceTestDB(t)
+	t.Cleanup(func() { database.Close() })
+	if _, err := database.ExecContext(ctx, `CREATE TABLE agents (id TEXT PRIMARY KEY, workspace_id TEXT NOT NULL)`); err != nil {
+		t.Fatalf("failed to create agents table: %v", err)
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{
+		Name:       "local",
+		IsLocal:    true,
+		Status:     models.NodeStatusUnknown,
+		SSHBackend: models.SSHBackendAuto,
+	}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("failed to create local node: %v", err)
+	}
+
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	writeSnapshotTestFile(t, filepath.Join(repoPath, "main.go"), "package main\n")
+
+	wsRepo := db.NewWorkspaceRepository(database)
+	ws := &models.Workspace{
+		Name:        "repo",
+		NodeID:      localNode.ID,
+		RepoPath:    repoPath,
+		TmuxSession: "forge-repo",
+		Status:      models.WorkspaceStatusActive,
+	}
+	if err := wsRepo.Create(ctx, ws); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	opts = append(opts, WithQuotas(func(*models.Workspace) models.WorkspaceQuota { return quota }))
+	return NewService(wsRepo, node.NewService(nodeRepo), nil, opts...), ws, database
+}
+
+func TestCheckAgentQuota(t *testing.T) {
+	ctx := context.Background()
+	quota := models.WorkspaceQuota{MaxAgents: 2}
+
+	var published []*models.Event
+	publisher := events.NewInMemoryPublisher()
+	if err := publisher.Subscribe("test", events.Filter{}, func(event *models.Event) {
+		published = append(published, event)
+	}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	service, ws, database := setupQuotaService(t, quota, WithPublisher(publisher))
+
+	if err := service.CheckAgentQuota(ctx, ws); err != nil {
+		t.Fatalf("expected empty workspace to pass, got %v", err)
+	}
+
+	for _, id := range []string{"a1", "a2"} {
+		if _, err := database.ExecContext(ctx, `INSERT INTO agents (id, workspace_id) VALUES (?, ?)`, id, ws.ID); err != nil {
+			t.Fatalf("failed to insert agent: %v", err)
+		}
+	}
+
+	err := service.CheckAgentQuota(ctx, ws)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Resource != models.QuotaResourceAgents || quotaErr.Current != 2 || quotaErr.Limit != 2 {
+		t.Fatalf("unexpected quota error: %+v", quotaErr)
+	}
+	if !strings.Contains(err.Error(), "max_agents") {
+		t.Fatalf("expected error to name the config key, got %q", err.Error())
+	}
+
+	if len(published) != 1 || published[0].Type != models.EventTypeWorkspaceQuotaExceeded {
+		t.Fatalf("expected one quota event, got %+v", published)
+	}
+	var payload models.WorkspaceQuotaPayload
+	if err := json.Unmarshal(published[0].Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Resource != models.QuotaResourceAgents || payload.Limit != 2 || payload.Requested != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestCheckLoopQuotaDisk(t *testing.T) {
+	ctx := context.Background()
+	quota := models.WorkspaceQuota{MaxDiskMB: 1}
+	service, ws, _ := setupQuotaService(t, quota)
+
+	if err := service.CheckLoopQuota(ctx, ws.RepoPath+string(filepath.Separator), 1); err != nil {
+		t.Fatalf("expected small repo to pass, got %v", err)
+	}
+	if err := service.CheckLoopQuota(ctx, filepath.Join(t.TempDir(), "other"), 1); err != nil {
+		t.Fatalf("expected repo without workspace to pass, got %v", err)
+	}
+
+	big := make([]byte, 2<<20)
+	if err := os.WriteFile(filepath.Join(ws.RepoPath, "big.bin"), big, 0o644); err != nil {
+		t.Fatalf("failed to write big file: %v", err)
+	}
+
+	var quotaErr *QuotaError
+	err := service.CheckLoopQuota(ctx, ws.RepoPath, 1)
+	if !errors.As(err, &quotaErr) || quotaErr.Resource != models.QuotaResourceDisk {
+		t.Fatalf("expected disk quota error, got %v", err)
+	}
+	if quotaErr.Current != 2 || quotaErr.Limit != 1 {
+		t.Fatalf("unexpected disk usage: %+v", quotaErr)
+	}
+}
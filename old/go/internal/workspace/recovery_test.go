@@ -56,12 +56,14 @@ func setupWorkspaceTestDB(t *testing.T) *db.DB {
 			is_local INTEGER NOT NULL DEFAULT 0,
 			last_seen_at TEXT,
 			metadata_json TEXT,
+			labels_json TEXT,
 			created_at TEXT NOT NULL DEFAULT (datetime('now')),
 			updated_at TEXT NOT NULL DEFAULT (datetime('now'))
 		);`,
 		`CREATE TABLE workspaces (
 			id TEXT PRIMARY KEY,
 			name TEXT NOT NULL,
+			namespace TEXT NOT NULL DEFAULT 'default',
 			node_id TEXT NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
 			repo_path TEXT NOT NULL,
 			tmux_session TEXT NOT NULL,
@@ -65,8 +65,18 @@ func DetectGitInfo(repoPath string) (*models.GitInfo, error) {
 		info.RemoteURL = remote
 	}
 
-	if status, err := runGitTrim(repoPath, "status", "--porcelain"); err == nil {
-		info.IsDirty = status != ""
+	if status, _, err := runGit(repoPath, "status", "--porcelain"); err == nil {
+		changes := parseGitPorcelain(status)
+		info.IsDirty = len(changes) > 0
+		info.DirtyCount = len(changes)
+		if len(changes) > maxDirtyFiles {
+			changes = changes[:maxDirtyFiles]
+		}
+		info.DirtyFiles = changes
+	}
+
+	if upstream, err := runGitTrim(repoPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		info.Upstream = upstream
 	}
 
 	if counts, err := runGitTrim(repoPath, "rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
@@ -81,9 +91,32 @@ func DetectGitInfo(repoPath string) (*models.GitInfo, error) {
 		}
 	}
 
+	checkedAt := time.Now().UTC()
+	info.CheckedAt = &checkedAt
+
 	return info, nil
 }
 
+// maxDirtyFiles caps how many changed files DetectGitInfo records.
+const maxDirtyFiles = 50
+
+// parseGitPorcelain parses `git status --porcelain` output. Renames are
+// reported under their new path.
+func parseGitPorcelain(out string) []models.GitFileChange {
+	var changes []models.GitFileChange
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		if _, to, ok := strings.Cut(path, " -> "); ok {
+			path = to
+		}
+		changes = append(changes, models.GitFileChange{Status: line[:2], Path: path})
+	}
+	return changes
+}
+
 func listCommitTimesSince(repoPath string, since time.Time, limit int) ([]time.Time, error) {
 	if err := ValidateRepoPath(repoPath); err != nil {
 		return nil, err
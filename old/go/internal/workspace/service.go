@@ -3,6 +3,7 @@ package workspace
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -26,6 +27,7 @@ var (
 	ErrNodeNotFound           = errors.New("node not found")
 	ErrTmuxSessionFailed      = errors.New("failed to create tmux session")
 	ErrRepoValidationFailed   = errors.New("repository validation failed")
+	ErrNodeConstraints        = errors.New("node does not satisfy constraints")
 )
 
 const (
@@ -44,13 +46,22 @@ const (
 
 // Service manages workspace operations.
 type Service struct {
-	repo        *db.WorkspaceRepository
-	nodeService *node.Service
-	agentRepo   *db.AgentRepository
-	eventRepo   *db.EventRepository
-	publisher   events.Publisher
-	tmuxFactory func() *tmux.Client
-	logger      zerolog.Logger
+	repo         *db.WorkspaceRepository
+	nodeService  *node.Service
+	agentRepo    *db.AgentRepository
+	eventRepo    *db.EventRepository
+	templateRepo *db.WorkspaceTemplateRepository
+	publisher    events.Publisher
+	tmuxFactory  func() *tmux.Client
+	logger       zerolog.Logger
+
+	snapshotDir       string
+	snapshotRetention int
+
+	loopRepo *db.LoopRepository
+	quotaFor func(*models.Workspace) models.WorkspaceQuota
+
+	gitStatus *GitStatusCollector
 }
 
 // ServiceOption configures a WorkspaceService.
@@ -70,6 +81,13 @@ func WithEventRepository(eventRepo *db.EventRepository) ServiceOption {
 	}
 }
 
+// WithTemplateRepository sets the repository bootstrap runs are recorded in.
+func WithTemplateRepository(templateRepo *db.WorkspaceTemplateRepository) ServiceOption {
+	return func(s *Service) {
+		s.templateRepo = templateRepo
+	}
+}
+
 // WithTmuxClientFactory overrides the tmux client factory.
 func WithTmuxClientFactory(factory func() *tmux.Client) ServiceOption {
 	return func(s *Service) {
@@ -77,6 +95,29 @@ func WithTmuxClientFactory(factory func() *tmux.Client) ServiceOption {
 	}
 }
 
+// WithSnapshotDir sets the directory workspace snapshots are written to.
+func WithSnapshotDir(dir string) ServiceOption {
+	return func(s *Service) {
+		s.snapshotDir = dir
+	}
+}
+
+// WithSnapshotRetention sets how many snapshots are kept per workspace.
+// Zero or less keeps every snapshot.
+func WithSnapshotRetention(keep int) ServiceOption {
+	return func(s *Service) {
+		s.snapshotRetention = keep
+	}
+}
+
+// WithGitStatusInterval sets how long collected git status is reused before
+// a workspace repository is inspected again.
+func WithGitStatusInterval(interval time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.gitStatus = NewGitStatusCollector(interval)
+	}
+}
+
 // NewService creates a new WorkspaceService.
 func NewService(repo *db.WorkspaceRepository, nodeService *node.Service, agentRepo *db.AgentRepository, opts ...ServiceOption) *Service {
 	s := &Service{
@@ -85,6 +126,7 @@ func NewService(repo *db.WorkspaceRepository, nodeService *node.Service, agentRe
 		agentRepo:   agentRepo,
 		tmuxFactory: tmux.NewLocalClient,
 		logger:      logging.Component("workspace"),
+		gitStatus:   NewGitStatusCollector(DefaultGitStatusInterval),
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -95,9 +137,13 @@ func NewService(repo *db.WorkspaceRepository, nodeService *node.Service, agentRe
 // CreateWorkspaceInput contains the parameters for creating a workspace.
 type CreateWorkspaceInput struct {
 	// NodeID is the node where the workspace will be created.
-	// If empty, uses the local node.
+	// If empty, a node satisfying NodeConstraints is selected, or the
+	// local node when there are no constraints.
 	NodeID string
 
+	// NodeConstraints are label requirements the node must satisfy.
+	NodeConstraints []models.NodeConstraint
+
 	// RepoPath is the absolute path to the repository.
 	RepoPath string
 
@@ -111,6 +157,10 @@ type CreateWorkspaceInput struct {
 
 	// CreateTmuxSession indicates whether to create a new tmux session.
 	CreateTmuxSession bool
+
+	// Template, when set, is cloned into RepoPath if the path does not
+	// exist yet. Its bootstrap commands are run separately by RunBootstrap.
+	Template *models.WorkspaceTemplate
 }
 
 // CreateWorkspace creates a new workspace for a repository.
@@ -120,6 +170,10 @@ func (s *Service) CreateWorkspace(ctx context.Context, input CreateWorkspaceInpu
 		Str("repo_path", input.RepoPath).
 		Msg("creating workspace")
 
+	if err := prepareTemplateRepo(input.RepoPath, input.Template); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTemplateCloneFailed, err)
+	}
+
 	// Validate repo path exists
 	if err := ValidateRepoPath(input.RepoPath); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRepoValidationFailed, err)
@@ -127,6 +181,13 @@ func (s *Service) CreateWorkspace(ctx context.Context, input CreateWorkspaceInpu
 
 	// Get or default node
 	nodeID := input.NodeID
+	if nodeID == "" && len(input.NodeConstraints) > 0 {
+		selected, err := s.nodeService.SelectNode(ctx, input.NodeConstraints)
+		if err != nil {
+			return nil, err
+		}
+		nodeID = selected.ID
+	}
 	if nodeID == "" {
 		// Use local node - it must exist
 		nodes, err := s.nodeService.ListNodes(ctx, nil)
@@ -145,13 +206,17 @@ func (s *Service) CreateWorkspace(ctx context.Context, input CreateWorkspaceInpu
 	}
 
 	// Verify node exists
-	_, err := s.nodeService.GetNode(ctx, nodeID)
+	nodeObj, err := s.nodeService.GetNode(ctx, nodeID)
 	if err != nil {
 		if errors.Is(err, node.ErrNodeNotFound) {
 			return nil, ErrNodeNotFound
 		}
 		return nil, fmt.Errorf("failed to get node: %w", err)
 	}
+	if !nodeObj.SatisfiesConstraints(input.NodeConstraints) {
+		return nil, fmt.Errorf("%w: node %s (labels %q) does not match %s",
+			ErrNodeConstraints, nodeObj.Name, models.FormatNodeLabels(nodeObj.Labels), models.FormatNodeConstraints(input.NodeConstraints))
+	}
 
 	// Generate tmux session name if not provided
 	tmuxSession := input.TmuxSession
@@ -366,6 +431,25 @@ func (s *Service) GetWorkspace(ctx context.Context, id string) (*models.Workspac
 	return workspace, nil
 }
 
+// GetWorkspaceNode returns the node hosting a workspace.
+func (s *Service) GetWorkspaceNode(ctx context.Context, id string) (*models.Node, error) {
+	workspace, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrWorkspaceNotFound) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	nodeObj, err := s.nodeService.GetNode(ctx, workspace.NodeID)
+	if err != nil {
+		if errors.Is(err, node.ErrNodeNotFound) {
+			return nil, ErrNodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return nodeObj, nil
+}
+
 // WorkspaceStatusResult contains comprehensive status information.
 type WorkspaceStatusResult struct {
 	// Workspace is the base workspace info.
@@ -436,7 +520,7 @@ func (s *Service) GetWorkspaceStatus(ctx context.Context, id string) (*Workspace
 
 	// Refresh git info
 	if workspace.RepoPath != "" {
-		if gitInfo, err := DetectGitInfo(workspace.RepoPath); err == nil {
+		if gitInfo, err := s.gitStatus.Status(workspace.RepoPath); err == nil {
 			result.GitInfo = gitInfo
 			// Update stored git info
 			if err := s.repo.UpdateGitInfo(ctx, workspace.ID, gitInfo); err != nil {
@@ -530,7 +614,7 @@ func (s *Service) RefreshGitInfo(ctx context.Context, id string) (*models.GitInf
 		return nil, err
 	}
 
-	gitInfo, err := DetectGitInfo(workspace.RepoPath)
+	gitInfo, err := s.gitStatus.Refresh(workspace.RepoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect git info: %w", err)
 	}
@@ -953,15 +1037,34 @@ func (s *Service) discoverAgentsInSession(ctx context.Context, workspace *models
 		return err
 	}
 	existingPanes := make(map[string]struct{}, len(existing))
+	existingByID := make(map[string]*models.Agent, len(existing))
 	for _, agent := range existing {
 		existingPanes[agent.TmuxPane] = struct{}{}
+		existingByID[agent.ID] = agent
+	}
+
+	// Panes tagged with an agent ID are matched by tag, not by position.
+	tagged := make(map[string]string)
+	if taggedPanes, err := client.ListTaggedPanes(ctx); err == nil {
+		for _, pane := range taggedPanes {
+			tagged[pane.ID] = pane.AgentID
+		}
 	}
 
 	for _, pane := range panes {
 		paneTarget := fmt.Sprintf("%s:%s", workspace.TmuxSession, pane.ID)
+		if agent, ok := existingByID[tagged[pane.ID]]; ok {
+			if agent.TmuxPane != pane.ID && agent.TmuxPane != paneTarget {
+				s.relinkDiscoveredAgent(ctx, agent, pane.ID)
+			}
+			continue
+		}
 		if _, ok := existingPanes[paneTarget]; ok {
 			continue
 		}
+		if _, ok := existingPanes[pane.ID]; ok {
+			continue
+		}
 
 		agentType, reason, evidence := detectAgentType(pane.Command, "")
 		if agentType == "" {
@@ -996,6 +1099,10 @@ func (s *Service) discoverAgentsInSession(ctx context.Context, workspace *models
 			continue
 		}
 
+		if err := client.TagPaneAgent(ctx, paneTarget, agent.ID); err != nil {
+			s.logger.Debug().Err(err).Str("tmux_pane", paneTarget).Msg("failed to tag discovered agent pane")
+		}
+
 		s.logger.Info().
 			Str("workspace_id", workspace.ID).
 			Str("agent_id", agent.ID).
@@ -1007,6 +1114,22 @@ func (s *Service) discoverAgentsInSession(ctx context.Context, workspace *models
 	return nil
 }
 
+// relinkDiscoveredAgent points an existing agent at the pane carrying its
+// tag after the pane was renumbered or moved.
+func (s *Service) relinkDiscoveredAgent(ctx context.Context, agent *models.Agent, paneID string) {
+	oldPane := agent.TmuxPane
+	agent.TmuxPane = paneID
+	if err := s.agentRepo.Update(ctx, agent); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", agent.ID).Msg("failed to relink agent pane")
+		return
+	}
+	s.logger.Info().
+		Str("agent_id", agent.ID).
+		Str("old_pane", oldPane).
+		Str("new_pane", paneID).
+		Msg("relinked agent to tagged pane")
+}
+
 func detectAgentType(command, screen string) (models.AgentType, string, []string) {
 	lowerCmd := strings.ToLower(strings.TrimSpace(command))
 	if agentType, ok := agentTypeFromHint(lowerCmd); ok {
@@ -1080,6 +1203,14 @@ func (s *Service) publishEvent(ctx context.Context, eventType models.EventType,
 		EntityType: models.EntityTypeWorkspace,
 		EntityID:   workspaceID,
 	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("event_type", string(eventType)).Msg("failed to encode event payload")
+		} else {
+			event.Payload = data
+		}
+	}
 
 	s.publisher.Publish(ctx, event)
 }
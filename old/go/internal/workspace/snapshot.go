@@ -0,0 +1,726 @@
+package workspace
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// snapshotManifestName is the first entry of every snapshot archive.
+const snapshotManifestName = ".forge-snapshot.json"
+
+// snapshotExt is the file extension of snapshot archives.
+const snapshotExt = ".tar.gz"
+
+// Snapshot errors.
+var (
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrSnapshotDirUnset = errors.New("snapshot directory not configured")
+)
+
+// SnapshotOptions controls a workspace snapshot.
+type SnapshotOptions struct {
+	// Label is an optional note stored with the snapshot.
+	Label string
+}
+
+// RestoreOptions controls a snapshot restore.
+type RestoreOptions struct {
+	// SkipSafetySnapshot skips snapshotting the current state before restoring.
+	SkipSafetySnapshot bool
+}
+
+// Snapshot describes a stored workspace snapshot.
+type Snapshot struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Label       string    `json:"label,omitempty"`
+	RepoPath    string    `json:"repo_path"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Files is the number of regular files archived; Bytes is their total size.
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+
+	// Path and Size locate the archive on disk. They are filled in when a
+	// snapshot is read back and are not part of the stored manifest.
+	Path string `json:"path,omitempty"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// snapshotManifest is stored as the first archive entry so a snapshot
+// carries the workspace and agent records it was taken with.
+type snapshotManifest struct {
+	Snapshot
+	Workspace *models.Workspace `json:"workspace"`
+	Agents    []*models.Agent   `json:"agents,omitempty"`
+}
+
+// RestoreResult summarizes a snapshot restore.
+type RestoreResult struct {
+	WorkspaceID string    `json:"workspace_id"`
+	Snapshot    *Snapshot `json:"snapshot"`
+	Files       int       `json:"files"`
+
+	// SafetySnapshot holds the state the restore replaced, if one was taken.
+	SafetySnapshot *Snapshot `json:"safety_snapshot,omitempty"`
+}
+
+// snapshotEntry is a path collected for archiving, relative to the workspace.
+type snapshotEntry struct {
+	rel  string
+	info fs.FileInfo
+}
+
+// Snapshot archives the workspace directory together with its workspace and
+// agent records. The archive is written to a temporary file and renamed into
+// place, so a snapshot is either complete or absent. Snapshots beyond the
+// retention limit are pruned, oldest first.
+func (s *Service) Snapshot(ctx context.Context, id string, opts SnapshotOptions) (*Snapshot, error) {
+	ws, err := s.snapshotWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := s.writeSnapshot(ctx, ws, opts.Label)
+	if err != nil {
+		return nil, err
+	}
+	s.pruneSnapshots(ctx, ws.ID, snap.ID)
+
+	s.logger.Info().
+		Str("workspace_id", ws.ID).
+		Str("snapshot_id", snap.ID).
+		Int("files", snap.Files).
+		Msg("workspace snapshot created")
+
+	s.publishEvent(ctx, models.EventTypeWorkspaceSnapshotted, ws.ID, snap)
+	return snap, nil
+}
+
+// ListSnapshots returns a workspace's snapshots, newest first.
+func (s *Service) ListSnapshots(ctx context.Context, workspaceID string) ([]*Snapshot, error) {
+	if strings.TrimSpace(s.snapshotDir) == "" {
+		return nil, ErrSnapshotDirUnset
+	}
+
+	dir := s.workspaceSnapshotDir(workspaceID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	snapshots := make([]*Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, snapshotExt) {
+			continue
+		}
+		manifest, err := readSnapshotManifest(filepath.Join(dir, name))
+		if err != nil {
+			s.logger.Warn().Err(err).Str("path", filepath.Join(dir, name)).Msg("skipping unreadable snapshot")
+			continue
+		}
+		snapshots = append(snapshots, &manifest.Snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// DeleteSnapshot removes a stored snapshot.
+func (s *Service) DeleteSnapshot(ctx context.Context, workspaceID, snapshotID string) error {
+	archivePath, err := s.snapshotArchivePath(workspaceID, snapshotID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(archivePath); err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	s.logger.Info().Str("workspace_id", workspaceID).Str("snapshot_id", snapshotID).Msg("workspace snapshot deleted")
+	return nil
+}
+
+// Restore replaces the workspace directory contents with a snapshot. Unless
+// opts.SkipSafetySnapshot is set, the current state is snapshotted first so
+// the restore itself can be undone. The archive is extracted next to the
+// workspace and swapped in only once extraction succeeds; the workspace
+// directory itself is kept so tmux panes rooted there stay valid.
+func (s *Service) Restore(ctx context.Context, id, snapshotID string, opts RestoreOptions) (*RestoreResult, error) {
+	ws, err := s.snapshotWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	archivePath, err := s.snapshotArchivePath(ws.ID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := readSnapshotManifest(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.WorkspaceID != ws.ID {
+		return nil, fmt.Errorf("snapshot %s belongs to workspace %s", snapshotID, manifest.WorkspaceID)
+	}
+
+	result := &RestoreResult{WorkspaceID: ws.ID, Snapshot: &manifest.Snapshot}
+	keep := []string{snapshotID}
+	if !opts.SkipSafetySnapshot {
+		safety, err := s.writeSnapshot(ctx, ws, "pre-restore "+snapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot current state: %w", err)
+		}
+		result.SafetySnapshot = safety
+		keep = append(keep, safety.ID)
+	}
+
+	parent, base := filepath.Dir(ws.RepoPath), filepath.Base(ws.RepoPath)
+	staging, err := os.MkdirTemp(parent, "."+base+".restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	files, err := extractSnapshot(ctx, archivePath, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := os.MkdirTemp(parent, "."+base+".pre-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore backup directory: %w", err)
+	}
+	if err := swapDirContents(ws.RepoPath, staging, backup, s.snapshotDirEntry(ws.RepoPath)); err != nil {
+		_ = os.Remove(backup) // only succeeds once the originals are back in place
+		return nil, err
+	}
+	if err := os.RemoveAll(backup); err != nil {
+		s.logger.Warn().Err(err).Str("path", backup).Msg("failed to remove replaced workspace files")
+	}
+	result.Files = files
+
+	if gitInfo, err := DetectGitInfo(ws.RepoPath); err != nil {
+		s.logger.Warn().Err(err).Str("workspace_id", ws.ID).Msg("failed to detect git info after restore")
+	} else if err := s.repo.UpdateGitInfo(ctx, ws.ID, gitInfo); err != nil {
+		s.logger.Warn().Err(err).Str("workspace_id", ws.ID).Msg("failed to update git info after restore")
+	}
+	s.pruneSnapshots(ctx, ws.ID, keep...)
+
+	s.logger.Info().
+		Str("workspace_id", ws.ID).
+		Str("snapshot_id", snapshotID).
+		Int("files", files).
+		Msg("workspace restored from snapshot")
+
+	s.publishEvent(ctx, models.EventTypeWorkspaceRestored, ws.ID, result)
+	return result, nil
+}
+
+// snapshotWorkspace loads a workspace that can be snapshotted: the snapshot
+// directory must be configured and the workspace must live on the local node.
+func (s *Service) snapshotWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	if strings.TrimSpace(s.snapshotDir) == "" {
+		return nil, ErrSnapshotDirUnset
+	}
+
+	ws, err := s.GetWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	nodeObj, err := s.nodeService.GetNode(ctx, ws.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, err)
+	}
+	if !nodeObj.IsLocal {
+		return nil, fmt.Errorf("remote workspace snapshots not yet implemented (use forge ws sync --pull)")
+	}
+	if err := ValidateRepoPath(ws.RepoPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepoValidationFailed, err)
+	}
+	return ws, nil
+}
+
+// snapshotDirEntry returns the top-level entry of repoPath that holds the
+// snapshot directory, or "" when snapshots are stored outside the workspace.
+func (s *Service) snapshotDirEntry(repoPath string) string {
+	snapshotDir, err := filepath.Abs(s.snapshotDir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(repoPath, snapshotDir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return strings.Split(rel, string(filepath.Separator))[0]
+}
+
+func (s *Service) workspaceSnapshotDir(workspaceID string) string {
+	return filepath.Join(s.snapshotDir, workspaceID)
+}
+
+// snapshotArchivePath resolves a snapshot ID to its archive, rejecting IDs
+// that would escape the workspace's snapshot directory.
+func (s *Service) snapshotArchivePath(workspaceID, snapshotID string) (string, error) {
+	if strings.TrimSpace(s.snapshotDir) == "" {
+		return "", ErrSnapshotDirUnset
+	}
+	if snapshotID == "" || snapshotID != filepath.Base(snapshotID) || strings.HasPrefix(snapshotID, ".") {
+		return "", fmt.Errorf("%w: %q", ErrSnapshotNotFound, snapshotID)
+	}
+
+	archivePath := filepath.Join(s.workspaceSnapshotDir(workspaceID), snapshotID+snapshotExt)
+	if _, err := os.Stat(archivePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", ErrSnapshotNotFound, snapshotID)
+		}
+		return "", fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+	return archivePath, nil
+}
+
+// writeSnapshot archives ws into its snapshot directory without pruning.
+func (s *Service) writeSnapshot(ctx context.Context, ws *models.Workspace, label string) (*Snapshot, error) {
+	entries, err := s.collectSnapshotEntries(ctx, ws.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.workspaceSnapshotDir(ws.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	snapshotID := newSnapshotID(now)
+	finalPath := filepath.Join(dir, snapshotID+snapshotExt)
+	for {
+		if _, err := os.Stat(finalPath); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		now = now.Add(time.Microsecond)
+		snapshotID = newSnapshotID(now)
+		finalPath = filepath.Join(dir, snapshotID+snapshotExt)
+	}
+
+	manifest := snapshotManifest{
+		Snapshot: Snapshot{
+			ID:          snapshotID,
+			WorkspaceID: ws.ID,
+			Label:       strings.TrimSpace(label),
+			RepoPath:    ws.RepoPath,
+			CreatedAt:   now,
+		},
+		Workspace: ws,
+	}
+	for _, entry := range entries {
+		if entry.info.Mode().IsRegular() {
+			manifest.Files++
+			manifest.Bytes += entry.info.Size()
+		}
+	}
+	if s.agentRepo != nil {
+		agents, err := s.agentRepo.ListByWorkspace(ctx, ws.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list agents: %w", err)
+		}
+		manifest.Agents = agents
+	}
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err := writeSnapshotArchive(ctx, tmp, ws.RepoPath, manifest, entries); err != nil {
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		return nil, fmt.Errorf("failed to finalize snapshot: %w", err)
+	}
+	committed = true
+
+	snap := manifest.Snapshot
+	snap.Path = finalPath
+	if info, err := os.Stat(finalPath); err == nil {
+		snap.Size = info.Size()
+	}
+	return &snap, nil
+}
+
+// pruneSnapshots removes the oldest snapshots beyond the retention limit,
+// never touching the IDs in keep.
+func (s *Service) pruneSnapshots(ctx context.Context, workspaceID string, keep ...string) {
+	if s.snapshotRetention <= 0 {
+		return
+	}
+	snapshots, err := s.ListSnapshots(ctx, workspaceID)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("workspace_id", workspaceID).Msg("failed to list snapshots for pruning")
+		return
+	}
+
+	kept := 0
+	for _, snap := range snapshots {
+		protected := false
+		for _, id := range keep {
+			if snap.ID == id {
+				protected = true
+				break
+			}
+		}
+		if protected || kept < s.snapshotRetention {
+			kept++
+			continue
+		}
+		if err := os.Remove(snap.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.logger.Warn().Err(err).Str("snapshot_id", snap.ID).Msg("failed to prune snapshot")
+			continue
+		}
+		s.logger.Debug().Str("workspace_id", workspaceID).Str("snapshot_id", snap.ID).Msg("pruned workspace snapshot")
+	}
+}
+
+// collectSnapshotEntries walks root and returns the directories, regular
+// files, and symlinks to archive. Other file types are skipped, as is the
+// snapshot directory when it lives inside the workspace.
+func (s *Service) collectSnapshotEntries(ctx context.Context, root string) ([]snapshotEntry, error) {
+	snapshotDir, _ := filepath.Abs(s.snapshotDir)
+
+	var entries []snapshotEntry
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		if d.IsDir() && p == snapshotDir {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode()
+		if !mode.IsDir() && !mode.IsRegular() && mode&fs.ModeSymlink == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, snapshotEntry{rel: rel, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workspace: %w", err)
+	}
+	return entries, nil
+}
+
+func newSnapshotID(t time.Time) string {
+	return t.UTC().Format("20060102-150405.000000")
+}
+
+// writeSnapshotArchive writes the manifest followed by every entry as a
+// gzip-compressed tar stream.
+func writeSnapshotArchive(ctx context.Context, w io.Writer, root string, manifest snapshotManifest, entries []snapshotEntry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     snapshotManifestName,
+		Typeflag: tar.TypeReg,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  manifest.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeSnapshotEntry(tw, root, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish snapshot archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish snapshot archive: %w", err)
+	}
+	return nil
+}
+
+func writeSnapshotEntry(tw *tar.Writer, root string, entry snapshotEntry) error {
+	fullPath := filepath.Join(root, entry.rel)
+
+	var link string
+	if entry.info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(fullPath)
+		if err != nil {
+			return fmt.Errorf("failed to read symlink %s: %w", entry.rel, err)
+		}
+		link = target
+	}
+
+	hdr, err := tar.FileInfoHeader(entry.info, link)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", entry.rel, err)
+	}
+	hdr.Name = filepath.ToSlash(entry.rel)
+	if entry.info.IsDir() {
+		hdr.Name += "/"
+	}
+	// Owner names depend on the local user database; restores keep the
+	// restoring user as owner anyway.
+	hdr.Uname, hdr.Gname = "", ""
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", entry.rel, err)
+	}
+	if !entry.info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", entry.rel, err)
+	}
+	defer file.Close()
+	if _, err := io.CopyN(tw, file, hdr.Size); err != nil {
+		return fmt.Errorf("failed to archive %s (changed during snapshot?): %w", entry.rel, err)
+	}
+	return nil
+}
+
+// readSnapshotManifest reads the manifest at the start of a snapshot archive.
+func readSnapshotManifest(archivePath string) (*snapshotManifest, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", archivePath, err)
+	}
+	if hdr.Name != snapshotManifestName {
+		return nil, fmt.Errorf("snapshot %s has no manifest", archivePath)
+	}
+
+	var manifest snapshotManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot manifest %s: %w", archivePath, err)
+	}
+	manifest.Path = archivePath
+	if info, err := file.Stat(); err == nil {
+		manifest.Size = info.Size()
+	}
+	return &manifest, nil
+}
+
+// extractSnapshot unpacks an archive into dest and returns the number of
+// regular files written. Symlinks are created after everything else so no
+// entry can be written through one, and directory modes and times are
+// applied last so extraction is not blocked by read-only directories.
+func extractSnapshot(ctx context.Context, archivePath, dest string) (int, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	type pendingLink struct {
+		path   string
+		target string
+	}
+	var (
+		links []pendingLink
+		dirs  []*tar.Header
+		files int
+	)
+
+	tr := tar.NewReader(gz)
+	for first := true; ; first = false {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if first && hdr.Name == snapshotManifestName {
+			continue
+		}
+
+		target, err := snapshotEntryPath(dest, hdr.Name)
+		if err != nil {
+			return 0, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return 0, fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+			}
+			dirs = append(dirs, hdr)
+		case tar.TypeReg:
+			if err := extractSnapshotFile(tr, hdr, target); err != nil {
+				return 0, err
+			}
+			files++
+		case tar.TypeSymlink:
+			links = append(links, pendingLink{path: target, target: hdr.Linkname})
+		}
+	}
+
+	for _, link := range links {
+		if err := os.MkdirAll(filepath.Dir(link.path), 0o755); err != nil {
+			return 0, fmt.Errorf("failed to restore symlink %s: %w", link.path, err)
+		}
+		if err := os.Symlink(link.target, link.path); err != nil {
+			return 0, fmt.Errorf("failed to restore symlink %s: %w", link.path, err)
+		}
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		target, _ := snapshotEntryPath(dest, dirs[i].Name)
+		_ = os.Chmod(target, dirs[i].FileInfo().Mode().Perm())
+		_ = os.Chtimes(target, dirs[i].ModTime, dirs[i].ModTime)
+	}
+	return files, nil
+}
+
+func extractSnapshotFile(r io.Reader, hdr *tar.Header, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+	}
+	_ = os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	return nil
+}
+
+// snapshotEntryPath maps an archive entry name into dest, rejecting absolute
+// names and names that climb out of it.
+func snapshotEntryPath(dest, name string) (string, error) {
+	clean := path.Clean(strings.TrimSuffix(name, "/"))
+	if clean == "." || clean == ".." || path.IsAbs(clean) || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid snapshot entry %q", name)
+	}
+	return filepath.Join(dest, filepath.FromSlash(clean)), nil
+}
+
+// swapDirContents moves dir's entries into backup, then moves staging's
+// entries into dir. If moving the new entries in fails, the original entries
+// are put back. The caller removes backup once the swap has succeeded.
+// The entry named keep (the one holding the snapshot directory when it lives
+// inside the workspace) is left in place on both sides so stored snapshots
+// survive the restore.
+func swapDirContents(dir, staging, backup, keep string) error {
+	if err := moveDirEntries(dir, backup, keep); err != nil {
+		if rollbackErr := moveDirEntries(backup, dir, keep); rollbackErr != nil {
+			return fmt.Errorf("failed to restore snapshot: %v (original files kept in %s)", err, backup)
+		}
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	if err := moveDirEntries(staging, dir, keep); err != nil {
+		_ = moveDirEntries(dir, staging, keep)
+		if rollbackErr := moveDirEntries(backup, dir, keep); rollbackErr != nil {
+			return fmt.Errorf("failed to restore snapshot: %v (original files kept in %s)", err, backup)
+		}
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	return nil
+}
+
+func moveDirEntries(src, dst, keep string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if keep != "" && entry.Name() == keep {
+			continue
+		}
+		if err := os.Rename(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+)
+
+func setupSnapshotService(t *testing.T, opts ...ServiceOption) (*Service, *models.Workspace) {
+	t.Helper()
+	ctx := context.Background()
+	database := setupWorkspaceTestDB(t)
+	t.Cleanup(func() { database.Close() })
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{
+		Name:       "local",
+		IsLocal:    true,
+		Status:     models.NodeStatusUnknown,
+		SSHBackend: models.SSHBackendAuto,
+	}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("failed to create local node: %v", err)
+	}
+
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	writeSnapshotTestFile(t, filepath.Join(repoPath, "main.go"), "package main\n")
+	writeSnapshotTestFile(t, filepath.Join(repoPath, "docs", "notes.md"), "notes\n")
+	if err := os.Symlink("docs/notes.md", filepath.Join(repoPath, "NOTES")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	wsRepo := db.NewWorkspaceRepository(database)
+	ws := &models.Workspace{
+		Name:        "repo",
+		NodeID:      localNode.ID,
+		RepoPath:    repoPath,
+		TmuxSession: "forge-repo",
+		Status:      models.WorkspaceStatusActive,
+	}
+	if err := wsRepo.Create(ctx, ws); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	opts = append([]ServiceOption{WithSnapshotDir(filepath.Join(t.TempDir(), "snapshots"))}, opts...)
+	return NewService(wsRepo, node.NewService(nodeRepo), nil, opts...), ws
+}
+
+func writeSnapshotTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func readSnapshotTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	ctx := context.Background()
+	service, ws := setupSnapshotService(t)
+
+	snap, err := service.Snapshot(ctx, ws.ID, SnapshotOptions{Label: "before agent"})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if snap.Files != 2 || snap.Label != "before agent" || snap.Size == 0 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+
+	// Simulate a misbehaving agent.
+	writeSnapshotTestFile(t, filepath.Join(ws.RepoPath, "main.go"), "package broken\n")
+	writeSnapshotTestFile(t, filepath.Join(ws.RepoPath, "junk", "out.txt"), "junk\n")
+	if err := os.Remove(filepath.Join(ws.RepoPath, "docs", "notes.md")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	result, err := service.Restore(ctx, ws.ID, snap.ID, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.Files != 2 || result.SafetySnapshot == nil {
+		t.Fatalf("unexpected restore result: %+v", result)
+	}
+
+	if got := readSnapshotTestFile(t, filepath.Join(ws.RepoPath, "main.go")); got != "package main\n" {
+		t.Fatalf("main.go = %q, want original content", got)
+	}
+	if got := readSnapshotTestFile(t, filepath.Join(ws.RepoPath, "NOTES")); got != "notes\n" {
+		t.Fatalf("NOTES = %q, want symlinked notes", got)
+	}
+	if _, err := os.Stat(filepath.Join(ws.RepoPath, "junk")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected junk/ to be removed, got %v", err)
+	}
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(ws.RepoPath), ".repo.*"))
+	if err != nil || len(leftovers) != 0 {
+		t.Fatalf("expected no staging leftovers, got %v (%v)", leftovers, err)
+	}
+
+	snapshots, err := service.ListSnapshots(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != result.SafetySnapshot.ID || snapshots[1].ID != snap.ID {
+		t.Fatalf("expected safety snapshot then original, got %+v", snapshots)
+	}
+
+	// The safety snapshot undoes the restore.
+	if _, err := service.Restore(ctx, ws.ID, result.SafetySnapshot.ID, RestoreOptions{SkipSafetySnapshot: true}); err != nil {
+		t.Fatalf("Restore of safety snapshot failed: %v", err)
+	}
+	if got := readSnapshotTestFile(t, filepath.Join(ws.RepoPath, "junk", "out.txt")); got != "junk\n" {
+		t.Fatalf("junk/out.txt = %q, want agent state back", got)
+	}
+}
+
+func TestSnapshotRetentionPrunesOldest(t *testing.T) {
+	ctx := context.Background()
+	service, ws := setupSnapshotService(t, WithSnapshotRetention(2))
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		snap, err := service.Snapshot(ctx, ws.ID, SnapshotOptions{})
+		if err != nil {
+			t.Fatalf("Snapshot %d failed: %v", i, err)
+		}
+		ids = append(ids, snap.ID)
+	}
+
+	snapshots, err := service.ListSnapshots(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != ids[2] || snapshots[1].ID != ids[1] {
+		t.Fatalf("expected the two newest snapshots, got %+v", snapshots)
+	}
+
+	if _, err := service.Restore(ctx, ws.ID, ids[0], RestoreOptions{}); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Fatalf("expected ErrSnapshotNotFound for pruned snapshot, got %v", err)
+	}
+}
+
+func TestRestoreKeepsSnapshotDirInsideWorkspace(t *testing.T) {
+	ctx := context.Background()
+	service, ws := setupSnapshotService(t)
+	service.snapshotDir = filepath.Join(ws.RepoPath, ".snapshots")
+
+	snap, err := service.Snapshot(ctx, ws.ID, SnapshotOptions{})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if snap.Files != 2 {
+		t.Fatalf("expected the snapshot directory to be left out of the archive, got %d files", snap.Files)
+	}
+	writeSnapshotTestFile(t, filepath.Join(ws.RepoPath, "main.go"), "package broken\n")
+
+	result, err := service.Restore(ctx, ws.ID, snap.ID, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got := readSnapshotTestFile(t, filepath.Join(ws.RepoPath, "main.go")); got != "package main\n" {
+		t.Fatalf("main.go = %q, want original content", got)
+	}
+
+	snapshots, err := service.ListSnapshots(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != result.SafetySnapshot.ID || snapshots[1].ID != snap.ID {
+		t.Fatalf("expected snapshots to survive the restore, got %+v", snapshots)
+	}
+}
+
+func TestSnapshotEntryPathRejectsEscapes(t *testing.T) {
+	dest := t.TempDir()
+	for _, name := range []string{"../evil", "/etc/passwd", "a/../../evil", ".", ""} {
+		if _, err := snapshotEntryPath(dest, name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+	got, err := snapshotEntryPath(dest, "src/app/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(dest, "src", "app"); got != want {
+		t.Fatalf("snapshotEntryPath = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/ssh"
+)
+
+// SyncIgnoreFile is the per-directory ignore file honoured by workspace sync.
+// It uses rsync exclude syntax, one pattern per line.
+const SyncIgnoreFile = ".forgesyncignore"
+
+// DefaultSyncExcludes are always excluded from workspace sync.
+var DefaultSyncExcludes = []string{".git/", ".forge/"}
+
+// ErrSyncConflict is returned when both sides changed the same files and the
+// sync was not forced.
+var ErrSyncConflict = errors.New("sync conflict")
+
+// runSyncCommand executes the transfer tool; overridden in tests.
+var runSyncCommand = func(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// SyncDirection selects which side of a workspace sync is the source.
+type SyncDirection string
+
+const (
+	// SyncPull copies the node workspace to the operator machine.
+	SyncPull SyncDirection = "pull"
+	// SyncPush copies the operator machine copy to the node workspace.
+	SyncPush SyncDirection = "push"
+)
+
+// SyncOptions controls a workspace sync.
+type SyncOptions struct {
+	// Direction is pull (remote → local) or push (local → remote).
+	Direction SyncDirection
+
+	// LocalPath is the directory on the operator machine.
+	LocalPath string
+
+	// Excludes are additional rsync exclude patterns.
+	Excludes []string
+
+	// DryRun reports what would change without transferring anything.
+	DryRun bool
+
+	// Delete removes destination files that do not exist at the source.
+	Delete bool
+
+	// Force overwrites destination files that are newer than the source.
+	Force bool
+}
+
+// SyncChangeKind classifies a single file change.
+type SyncChangeKind string
+
+const (
+	SyncChangeCreate SyncChangeKind = "create"
+	SyncChangeUpdate SyncChangeKind = "update"
+	SyncChangeDelete SyncChangeKind = "delete"
+)
+
+// SyncChange describes one path that a sync transfers or removes.
+type SyncChange struct {
+	Path  string         `json:"path"`
+	Kind  SyncChangeKind `json:"kind"`
+	IsDir bool           `json:"is_dir,omitempty"`
+}
+
+// SyncResult summarizes a workspace sync.
+type SyncResult struct {
+	WorkspaceID string        `json:"workspace_id"`
+	Direction   SyncDirection `json:"direction"`
+	Source      string        `json:"source"`
+	Destination string        `json:"destination"`
+	DryRun      bool          `json:"dry_run"`
+	Applied     bool          `json:"applied"`
+	Changes     []SyncChange  `json:"changes"`
+	// Conflicts lists destination files that are newer than the source.
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// SyncWorkspace transfers files between a workspace on its node and a local
+// directory using rsync over the node's SSH settings. Conflicts (destination
+// files newer than their source) abort the transfer unless Force is set.
+func (s *Service) SyncWorkspace(ctx context.Context, id string, opts SyncOptions) (*SyncResult, error) {
+	if opts.Direction != SyncPull && opts.Direction != SyncPush {
+		return nil, fmt.Errorf("invalid sync direction %q (expected pull or push)", opts.Direction)
+	}
+	localPath := strings.TrimSpace(opts.LocalPath)
+	if localPath == "" {
+		return nil, errors.New("local path is required")
+	}
+	localPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local path: %w", err)
+	}
+	if opts.Direction == SyncPush {
+		if err := ValidateRepoPath(localPath); err != nil {
+			return nil, err
+		}
+	} else if err := os.MkdirAll(localPath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local path: %w", err)
+	}
+
+	ws, err := s.GetWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	nodeObj, err := s.nodeService.GetNode(ctx, ws.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, err)
+	}
+
+	var sshArgs []string
+	remote := ws.RepoPath
+	if !nodeObj.IsLocal {
+		var target string
+		sshArgs, target = ssh.BuildSSHArgs(s.nodeService.ConnectionOptions(nodeObj))
+		remote = target + ":" + ws.RepoPath
+	}
+
+	source, dest := withTrailingSlash(remote), withTrailingSlash(localPath)
+	if opts.Direction == SyncPush {
+		source, dest = withTrailingSlash(localPath), withTrailingSlash(remote)
+	}
+
+	base := buildRsyncArgs(sshArgs, localPath, opts)
+	result := &SyncResult{
+		WorkspaceID: ws.ID,
+		Direction:   opts.Direction,
+		Source:      source,
+		Destination: dest,
+		DryRun:      opts.DryRun,
+	}
+
+	planned, err := execRsync(ctx, append(append([]string{}, base...), "--dry-run"), source, dest)
+	if err != nil {
+		return nil, err
+	}
+	guarded, err := execRsync(ctx, append(append([]string{}, base...), "--dry-run", "--update"), source, dest)
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = planned
+	result.Conflicts = detectSyncConflicts(planned, guarded)
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if len(result.Conflicts) > 0 && !opts.Force {
+		return result, fmt.Errorf("%w: %d file(s) are newer at %s (use --force to overwrite)", ErrSyncConflict, len(result.Conflicts), dest)
+	}
+
+	if _, err := execRsync(ctx, base, source, dest); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	s.publishEvent(ctx, models.EventTypeWorkspaceSynced, ws.ID, result)
+	return result, nil
+}
+
+// buildRsyncArgs returns the rsync flags shared by the planning and transfer runs.
+func buildRsyncArgs(sshArgs []string, localPath string, opts SyncOptions) []string {
+	args := []string{"--archive", "--compress", "--itemize-changes"}
+	if len(sshArgs) > 0 {
+		// --protect-args sends the remote path over the rsync protocol instead
+		// of through the remote shell, which would split it on spaces and
+		// expand shell metacharacters.
+		args = append(args, "-e", remoteShellCommand(sshArgs), "--protect-args")
+	}
+	if opts.Delete {
+		args = append(args, "--delete")
+	}
+	for _, pattern := range DefaultSyncExcludes {
+		args = append(args, "--exclude", pattern)
+	}
+	for _, pattern := range opts.Excludes {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			args = append(args, "--exclude", pattern)
+		}
+	}
+	ignorePath := filepath.Join(localPath, SyncIgnoreFile)
+	if info, err := os.Stat(ignorePath); err == nil && !info.IsDir() {
+		args = append(args, "--exclude-from", ignorePath)
+	}
+	return args
+}
+
+func execRsync(ctx context.Context, args []string, source, dest string) ([]SyncChange, error) {
+	args = append(append([]string{}, args...), source, dest)
+	stdout, stderr, err := runSyncCommand(ctx, "rsync", args...)
+	if err != nil {
+		msg := strings.TrimSpace(string(stderr))
+		if msg == "" {
+			return nil, fmt.Errorf("rsync failed: %w", err)
+		}
+		return nil, fmt.Errorf("rsync failed: %w: %s", err, msg)
+	}
+	return parseItemizedChanges(stdout), nil
+}
+
+// parseItemizedChanges parses rsync --itemize-changes output.
+func parseItemizedChanges(output []byte) []SyncChange {
+	var changes []SyncChange
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "*deleting") {
+			path := strings.TrimSpace(strings.TrimPrefix(line, "*deleting"))
+			if path == "" {
+				continue
+			}
+			changes = append(changes, SyncChange{
+				Path:  strings.TrimSuffix(path, "/"),
+				Kind:  SyncChangeDelete,
+				IsDir: strings.HasSuffix(path, "/"),
+			})
+			continue
+		}
+
+		// YXcstpoguax <path>
+		if len(line) < 13 || line[11] != ' ' {
+			continue
+		}
+		update, fileType, attrs := line[0], line[1], line[2:11]
+		path := line[12:]
+		if update == '.' || path == "./" {
+			// Attribute-only or no-op entries.
+			continue
+		}
+		if !strings.ContainsRune("<>ch", rune(update)) {
+			continue
+		}
+		kind := SyncChangeUpdate
+		if strings.HasPrefix(attrs, "+++") {
+			kind = SyncChangeCreate
+		}
+		isDir := fileType == 'd'
+		changes = append(changes, SyncChange{
+			Path:  strings.TrimSuffix(path, "/"),
+			Kind:  kind,
+			IsDir: isDir,
+		})
+	}
+	return changes
+}
+
+// detectSyncConflicts returns updated paths that a transfer would write but an
+// --update transfer skips, i.e. files that are newer at the destination.
+func detectSyncConflicts(planned, guarded []SyncChange) []string {
+	skipped := make(map[string]struct{}, len(guarded))
+	for _, change := range guarded {
+		skipped[change.Path] = struct{}{}
+	}
+	var conflicts []string
+	for _, change := range planned {
+		if change.Kind != SyncChangeUpdate || change.IsDir {
+			continue
+		}
+		if _, ok := skipped[change.Path]; !ok {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func remoteShellCommand(sshArgs []string) string {
+	parts := make([]string, 0, len(sshArgs)+1)
+	parts = append(parts, "ssh")
+	for _, arg := range sshArgs {
+		if strings.ContainsAny(arg, " \t'\"") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+func withTrailingSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return path
+	}
+	return path + "/"
+}
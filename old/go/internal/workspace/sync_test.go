@@ -0,0 +1,83 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseItemizedChanges(t *testing.T) {
+	output := []byte(`cd+++++++++ src/
+>f+++++++++ src/new.go
+>f.st...... main.go
+.d..t...... ./
+.f....og... perms.txt
+*deleting   old.txt
+*deleting   stale/
+`)
+
+	got := parseItemizedChanges(output)
+	want := []SyncChange{
+		{Path: "src", Kind: SyncChangeCreate, IsDir: true},
+		{Path: "src/new.go", Kind: SyncChangeCreate},
+		{Path: "main.go", Kind: SyncChangeUpdate},
+		{Path: "old.txt", Kind: SyncChangeDelete},
+		{Path: "stale", Kind: SyncChangeDelete, IsDir: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseItemizedChanges mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestDetectSyncConflicts(t *testing.T) {
+	planned := []SyncChange{
+		{Path: "a.go", Kind: SyncChangeUpdate},
+		{Path: "b.go", Kind: SyncChangeUpdate},
+		{Path: "c.go", Kind: SyncChangeCreate},
+		{Path: "dir", Kind: SyncChangeUpdate, IsDir: true},
+	}
+	guarded := []SyncChange{
+		{Path: "b.go", Kind: SyncChangeUpdate},
+		{Path: "c.go", Kind: SyncChangeCreate},
+	}
+
+	got := detectSyncConflicts(planned, guarded)
+	if want := []string{"a.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected conflicts %v, got %v", want, got)
+	}
+}
+
+func TestBuildRsyncArgs(t *testing.T) {
+	local := t.TempDir()
+	ignorePath := filepath.Join(local, SyncIgnoreFile)
+	if err := os.WriteFile(ignorePath, []byte("node_modules/\n"), 0o644); err != nil {
+		t.Fatalf("write ignore file: %v", err)
+	}
+
+	args := buildRsyncArgs([]string{"-p", "2222", "-i", "/keys/my key"}, local, SyncOptions{
+		Delete:   true,
+		Excludes: []string{"*.log", " "},
+	})
+	want := []string{
+		"--archive", "--compress", "--itemize-changes",
+		"-e", "ssh -p 2222 -i '/keys/my key'", "--protect-args",
+		"--delete",
+		"--exclude", ".git/",
+		"--exclude", ".forge/",
+		"--exclude", "*.log",
+		"--exclude-from", ignorePath,
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("buildRsyncArgs mismatch:\n got: %q\nwant: %q", args, want)
+	}
+}
+
+func TestBuildRsyncArgsLocalNode(t *testing.T) {
+	args := buildRsyncArgs(nil, t.TempDir(), SyncOptions{})
+	for _, arg := range args {
+		if arg == "-e" || arg == "--protect-args" || arg == "--delete" || arg == "--exclude-from" {
+			t.Fatalf("unexpected arg %q in %q", arg, args)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tOgg1/forge/internal/models"
+)
+
+// Template errors.
+var (
+	ErrTemplateCloneFailed = errors.New("failed to clone template repository")
+	ErrBootstrapFailed     = errors.New("workspace bootstrap failed")
+)
+
+const (
+	// BootstrapWindowName is the tmux window bootstrap commands run in.
+	BootstrapWindowName = "bootstrap"
+
+	// DefaultBootstrapTimeout bounds how long RunBootstrap waits.
+	DefaultBootstrapTimeout = 10 * time.Minute
+
+	bootstrapPollInterval = 500 * time.Millisecond
+	bootstrapExitMarker   = "__FORGE_BOOTSTRAP_EXIT__"
+)
+
+// prepareTemplateRepo clones the template's repository into repoPath when
+// the path does not exist yet or is an empty directory. Existing checkouts
+// are used as-is. Like tmux session creation, this only supports the local
+// node.
+func prepareTemplateRepo(repoPath string, tmpl *models.WorkspaceTemplate) error {
+	if tmpl == nil || strings.TrimSpace(tmpl.GitURL) == "" || repoPath == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(repoPath)
+	switch {
+	case err == nil && len(entries) > 0:
+		return nil
+	case err != nil && !os.IsNotExist(err):
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0o755); err != nil {
+		return err
+	}
+	args := []string{"clone"}
+	if branch := strings.TrimSpace(tmpl.Branch); branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	args = append(args, "--", tmpl.GitURL, repoPath)
+	if _, stderr, err := runGit(filepath.Dir(repoPath), args...); err != nil {
+		return fmt.Errorf("git clone %s: %w: %s", tmpl.GitURL, err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// RunBootstrap runs the template's bootstrap commands in a dedicated window
+// of the workspace's tmux session, waits for them to finish, and records the
+// captured output. A non-zero exit returns ErrBootstrapFailed along with the
+// recorded run.
+func (s *Service) RunBootstrap(ctx context.Context, workspace *models.Workspace, tmpl *models.WorkspaceTemplate, timeout time.Duration) (*models.WorkspaceBootstrap, error) {
+	if workspace == nil || tmpl == nil {
+		return nil, fmt.Errorf("workspace and template are required")
+	}
+	if len(tmpl.Bootstrap) == 0 {
+		return nil, nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultBootstrapTimeout
+	}
+
+	run := &models.WorkspaceBootstrap{
+		WorkspaceID: workspace.ID,
+		TemplateID:  tmpl.ID,
+		Status:      models.BootstrapStatusRunning,
+		StartedAt:   time.Now().UTC(),
+	}
+	s.saveBootstrap(ctx, run)
+
+	// The nonce tells this run's output apart from earlier runs still in
+	// the window's scrollback.
+	nonce := strconv.FormatInt(run.StartedAt.UnixNano(), 36)
+	exitPattern := regexp.MustCompile(bootstrapExitMarker + nonce + `:(\d+)`)
+
+	client := s.tmuxClient()
+	target := workspace.TmuxSession + ":" + BootstrapWindowName
+	err := client.EnsureWindow(ctx, workspace.TmuxSession, BootstrapWindowName, workspace.RepoPath)
+	if err == nil {
+		err = client.SendKeys(ctx, target, bootstrapScript(tmpl, nonce), true, true)
+	}
+	if err != nil {
+		return s.finishBootstrap(ctx, run, nil, "", fmt.Errorf("%w: %v", ErrBootstrapFailed, err))
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(bootstrapPollInterval)
+	defer ticker.Stop()
+
+	var output string
+	for {
+		output, err = client.CapturePane(ctx, target, true)
+		if err == nil {
+			if code, ok := parseBootstrapExit(output, exitPattern); ok {
+				output = bootstrapOutput(output, nonce, exitPattern)
+				if code != 0 {
+					return s.finishBootstrap(ctx, run, &code, output, fmt.Errorf("%w: exit code %d", ErrBootstrapFailed, code))
+				}
+				return s.finishBootstrap(ctx, run, &code, output, nil)
+			}
+		}
+		select {
+		case <-waitCtx.Done():
+			return s.finishBootstrap(ctx, run, nil, output, fmt.Errorf("%w: timed out after %s", ErrBootstrapFailed, timeout))
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) finishBootstrap(ctx context.Context, run *models.WorkspaceBootstrap, code *int, output string, runErr error) (*models.WorkspaceBootstrap, error) {
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.ExitCode = code
+	run.Output = strings.TrimRight(output, "\n")
+	run.Status = models.BootstrapStatusSucceeded
+	if runErr != nil {
+		run.Status = models.BootstrapStatusFailed
+	}
+	s.saveBootstrap(ctx, run)
+
+	s.logger.Info().
+		Str("workspace_id", run.WorkspaceID).
+		Str("status", string(run.Status)).
+		Msg("workspace bootstrap finished")
+	return run, runErr
+}
+
+func (s *Service) saveBootstrap(ctx context.Context, run *models.WorkspaceBootstrap) {
+	if s.templateRepo == nil {
+		return
+	}
+	if err := s.templateRepo.SaveBootstrap(ctx, run); err != nil {
+		s.logger.Warn().Err(err).Str("workspace_id", run.WorkspaceID).Msg("failed to record workspace bootstrap")
+	}
+}
+
+// bootstrapScript joins the bootstrap commands into one shell line that
+// stops at the first failure and prints an exit marker.
+func bootstrapScript(tmpl *models.WorkspaceTemplate, nonce string) string {
+	keys := make([]string, 0, len(tmpl.Env))
+	for key := range tmpl.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := []string{"set -e"}
+	for _, key := range keys {
+		parts = append(parts, "export "+key+"="+shellQuote(tmpl.Env[key]))
+	}
+	parts = append(parts, tmpl.Bootstrap...)
+	return ": forge-bootstrap-" + nonce + "; (" + strings.Join(parts, "; ") + "); echo " + bootstrapExitMarker + nonce + ":$?"
+}
+
+func parseBootstrapExit(output string, exitPattern *regexp.Regexp) (int, bool) {
+	matches := exitPattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	code, err := strconv.Atoi(matches[len(matches)-1][1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// bootstrapOutput keeps the pane lines from this run's command onwards,
+// without the exit marker.
+func bootstrapOutput(output, nonce string, exitPattern *regexp.Regexp) string {
+	lines := strings.Split(output, "\n")
+	start := 0
+	for i, line := range lines {
+		if strings.Contains(line, "forge-bootstrap-"+nonce) {
+			start = i
+		}
+	}
+	kept := make([]string, 0, len(lines)-start)
+	for _, line := range lines[start:] {
+		if exitPattern.MatchString(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,204 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tOgg1/forge/internal/db"
+	"github.com/tOgg1/forge/internal/models"
+	"github.com/tOgg1/forge/internal/node"
+	"github.com/tOgg1/forge/internal/tmux"
+)
+
+var bootstrapNoncePattern = regexp.MustCompile(`forge-bootstrap-([0-9a-z]+)`)
+
+// bootstrapExecutor fakes a tmux pane that finishes the bootstrap script
+// with the configured exit code as soon as it is sent.
+type bootstrapExecutor struct {
+	exitCode string
+	sent     string
+	nonce    string
+}
+
+func (e *bootstrapExecutor) Exec(ctx context.Context, cmd string) ([]byte, []byte, error) {
+	switch {
+	case strings.HasPrefix(cmd, "tmux list-windows"):
+		return []byte("main\nbootstrap\n"), nil, nil
+	case strings.HasPrefix(cmd, "tmux send-keys") && !strings.HasSuffix(cmd, " Enter"):
+		e.sent = cmd
+		if m := bootstrapNoncePattern.FindStringSubmatch(cmd); m != nil {
+			e.nonce = m[1]
+		}
+	case strings.HasPrefix(cmd, "tmux display-message"):
+		return []byte("3\n"), nil, nil
+	case strings.HasPrefix(cmd, "tmux capture-pane"):
+		if e.nonce == "" {
+			return nil, nil, nil
+		}
+		pane := strings.Join([]string{
+			"$ : forge-bootstrap-old; (make old); echo " + bootstrapExitMarker + "old:0",
+			"stale output",
+			"$ : forge-bootstrap-" + e.nonce + "; (set -e; make deps); echo " + bootstrapExitMarker + e.nonce + ":$?",
+			"installing deps",
+			bootstrapExitMarker + e.nonce + ":" + e.exitCode,
+			"$ ",
+		}, "\n")
+		return []byte(pane), nil, nil
+	}
+	return nil, nil, nil
+}
+
+func setupBootstrapService(t *testing.T, exec *bootstrapExecutor) (*Service, *db.WorkspaceTemplateRepository, *models.Workspace) {
+	t.Helper()
+	ctx := context.Background()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	nodeRepo := db.NewNodeRepository(database)
+	localNode := &models.Node{Name: "local", IsLocal: true, Status: models.NodeStatusOnline, SSHBackend: models.SSHBackendAuto}
+	if err := nodeRepo.Create(ctx, localNode); err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	wsRepo := db.NewWorkspaceRepository(database)
+	ws := &models.Workspace{
+		Name:        "api",
+		NodeID:      localNode.ID,
+		RepoPath:    t.TempDir(),
+		TmuxSession: "forge-api",
+		Status:      models.WorkspaceStatusActive,
+	}
+	if err := wsRepo.Create(ctx, ws); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	templateRepo := db.NewWorkspaceTemplateRepository(database)
+	service := NewService(wsRepo, node.NewService(nodeRepo), nil,
+		WithTemplateRepository(templateRepo),
+		WithTmuxClientFactory(func() *tmux.Client { return tmux.NewClient(exec) }),
+	)
+	return service, templateRepo, ws
+}
+
+func TestRunBootstrapRecordsOutput(t *testing.T) {
+	ctx := context.Background()
+	exec := &bootstrapExecutor{exitCode: "0"}
+	service, templateRepo, ws := setupBootstrapService(t, exec)
+
+	tmpl := &models.WorkspaceTemplate{
+		Name:      "api-service",
+		Bootstrap: []string{"make deps"},
+		Env:       map[string]string{"GOFLAGS": "-mod=mod"},
+	}
+	if err := templateRepo.Create(ctx, tmpl); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	run, err := service.RunBootstrap(ctx, ws, tmpl, time.Second)
+	if err != nil {
+		t.Fatalf("RunBootstrap failed: %v", err)
+	}
+	if run.Status != models.BootstrapStatusSucceeded || run.ExitCode == nil || *run.ExitCode != 0 {
+		t.Fatalf("unexpected run %+v", run)
+	}
+	if !strings.Contains(exec.sent, "forge-api:bootstrap") || !strings.Contains(exec.sent, "GOFLAGS=") {
+		t.Fatalf("unexpected send-keys command %q", exec.sent)
+	}
+	if strings.Contains(run.Output, "stale output") || strings.Contains(run.Output, bootstrapExitMarker+exec.nonce+":0") {
+		t.Fatalf("expected only this run's output, got %q", run.Output)
+	}
+	if !strings.Contains(run.Output, "installing deps") {
+		t.Fatalf("expected bootstrap output, got %q", run.Output)
+	}
+
+	stored, err := templateRepo.GetBootstrap(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("get bootstrap: %v", err)
+	}
+	if stored.Status != models.BootstrapStatusSucceeded || stored.TemplateID != tmpl.ID || stored.Output != run.Output {
+		t.Fatalf("unexpected stored run %+v", stored)
+	}
+}
+
+func TestRunBootstrapFailure(t *testing.T) {
+	ctx := context.Background()
+	exec := &bootstrapExecutor{exitCode: "2"}
+	service, templateRepo, ws := setupBootstrapService(t, exec)
+
+	tmpl := &models.WorkspaceTemplate{Name: "api-service", Bootstrap: []string{"make deps"}}
+	run, err := service.RunBootstrap(ctx, ws, tmpl, time.Second)
+	if !errors.Is(err, ErrBootstrapFailed) {
+		t.Fatalf("expected ErrBootstrapFailed, got %v", err)
+	}
+	if run == nil || run.Status != models.BootstrapStatusFailed || run.ExitCode == nil || *run.ExitCode != 2 {
+		t.Fatalf("unexpected run %+v", run)
+	}
+	stored, err := templateRepo.GetBootstrap(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("get bootstrap: %v", err)
+	}
+	if stored.Status != models.BootstrapStatusFailed || stored.FinishedAt == nil {
+		t.Fatalf("unexpected stored run %+v", stored)
+	}
+}
+
+func TestBootstrapScript(t *testing.T) {
+	tmpl := &models.WorkspaceTemplate{
+		Bootstrap: []string{"make deps", "make test"},
+		Env:       map[string]string{"B": "it's", "A": "1"},
+	}
+	got := bootstrapScript(tmpl, "abc")
+	want := `: forge-bootstrap-abc; (set -e; export A='1'; export B='it'\''s'; make deps; make test); echo __FORGE_BOOTSTRAP_EXIT__abc:$?`
+	if got != want {
+		t.Fatalf("bootstrapScript:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestPrepareTemplateRepoClones(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	origin := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=forge", "-c", "user.email=forge@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "release"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = origin
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	repoPath := filepath.Join(t.TempDir(), "checkouts", "api")
+	tmpl := &models.WorkspaceTemplate{Name: "api", GitURL: origin, Branch: "release"}
+	if err := prepareTemplateRepo(repoPath, tmpl); err != nil {
+		t.Fatalf("prepareTemplateRepo: %v", err)
+	}
+	head, err := os.ReadFile(filepath.Join(repoPath, ".git", "HEAD"))
+	if err != nil {
+		t.Fatalf("read HEAD: %v", err)
+	}
+	if strings.TrimSpace(string(head)) != "ref: refs/heads/release" {
+		t.Fatalf("expected release branch checkout, got %q", head)
+	}
+
+	// An existing checkout is left alone.
+	tmpl.GitURL = filepath.Join(origin, "missing")
+	if err := prepareTemplateRepo(repoPath, tmpl); err != nil {
+		t.Fatalf("expected existing checkout to be reused, got %v", err)
+	}
+}
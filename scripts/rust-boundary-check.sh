@@ -5,6 +5,7 @@ repo_root="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
 cd "$repo_root"
 
 policy_file="${1:-docs/rust-crate-boundaries.json}"
+shift || true
 policy_file_abs="$policy_file"
 if [[ "$policy_file_abs" != /* ]]; then
   policy_file_abs="$repo_root/$policy_file_abs"
@@ -12,5 +13,5 @@ fi
 
 (
   cd "$repo_root/old/go"
-  env -u GOROOT -u GOTOOLDIR go run ./cmd/rust-boundary-check --policy "$policy_file_abs"
+  env -u GOROOT -u GOTOOLDIR go run ./cmd/rust-boundary-check --root "$repo_root" --policy "$policy_file_abs" "$@"
 )